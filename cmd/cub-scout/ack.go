@@ -0,0 +1,348 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ackNamespace string
+	ackReason    string
+	ackExpires   string
+)
+
+var ackCmd = &cobra.Command{
+	Use:   "ack",
+	Short: "Acknowledge intentional orphans",
+	Long: `Acknowledge Native resources that are unmanaged on purpose: vendor
+appliances, break-glass tooling, resources owned by another team.
+
+Acknowledged resources are left out of 'map orphans', 'map bypass' and the
+GitOps coverage of 'map sprawl' (and its --enforce budgets) until their
+acknowledgement expires. Each one records a reason, so the list doubles as
+the record of accepted exceptions.
+
+Acknowledgements live in ~/.cub-scout/acks.yaml ($CUB_SCOUT_ACKS). Kinds
+accept the usual short names; names and namespaces may be glob patterns.`,
+}
+
+var ackAddCmd = &cobra.Command{
+	Use:   "add <kind>/<name>",
+	Short: "Acknowledge a Native resource",
+	Long: `Acknowledge a Native resource, or every resource matching a pattern.
+
+--expires takes a duration (90d, 12w, 720h) or a date (2027-01-31); without
+it the acknowledgement never expires. Adding the same resource again
+replaces its reason and expiry.
+
+Examples:
+  cub-scout ack add deploy/foo -n bar --reason "vendor appliance" --expires 90d
+  cub-scout ack add 'deploy/debug-*' -n '*' --reason "on-call tooling" --expires 2w
+  cub-scout ack add cm/feature-flags -n web --reason "edited live by the flags service"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAckAdd,
+}
+
+var ackListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List acknowledgements and whether they have expired",
+	Args:  cobra.NoArgs,
+	RunE:  runAckList,
+}
+
+var ackRemoveCmd = &cobra.Command{
+	Use:     "remove <kind>/<name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove an acknowledgement",
+	Long: `Remove the acknowledgement added for <kind>/<name> in the namespace.
+
+Examples:
+  cub-scout ack remove deploy/foo -n bar`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAckRemove,
+}
+
+func init() {
+	ackAddCmd.Flags().StringVarP(&ackNamespace, "namespace", "n", "default", "Namespace (glob pattern)")
+	_ = ackAddCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	ackAddCmd.Flags().StringVar(&ackReason, "reason", "", "Why the resource is unmanaged (required)")
+	ackAddCmd.Flags().StringVar(&ackExpires, "expires", "", "When the acknowledgement lapses: a duration (90d, 12w, 720h) or a date (2027-01-31)")
+	_ = ackAddCmd.MarkFlagRequired("reason")
+	ackRemoveCmd.Flags().StringVarP(&ackNamespace, "namespace", "n", "default", "Namespace (glob pattern)")
+	_ = ackRemoveCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+
+	ackCmd.AddCommand(ackAddCmd, ackListCmd, ackRemoveCmd)
+	rootCmd.AddCommand(ackCmd)
+}
+
+// AckFile is the acknowledgement file.
+type AckFile struct {
+	Acks []Ack `yaml:"acks"`
+}
+
+// Ack acknowledges the resources matching Kind, Namespace and Name as
+// intentionally unmanaged. Namespace and Name are glob patterns; Kind is a
+// kind or "*".
+type Ack struct {
+	Kind      string     `yaml:"kind"`
+	Namespace string     `yaml:"namespace"`
+	Name      string     `yaml:"name"`
+	Reason    string     `yaml:"reason"`
+	Expires   *time.Time `yaml:"expires,omitempty"`
+	AddedBy   string     `yaml:"addedBy,omitempty"`
+	AddedAt   time.Time  `yaml:"addedAt"`
+}
+
+// Expired reports whether the acknowledgement has lapsed at now.
+func (a Ack) Expired(now time.Time) bool {
+	return a.Expires != nil && !now.Before(*a.Expires)
+}
+
+// Matches reports whether the acknowledgement covers the resource.
+func (a Ack) Matches(kind, namespace, name string) bool {
+	if a.Kind != "*" && !strings.EqualFold(a.Kind, kind) {
+		return false
+	}
+	if ok, _ := path.Match(a.Namespace, namespace); !ok {
+		return false
+	}
+	ok, _ := path.Match(a.Name, name)
+	return ok
+}
+
+// Acknowledged returns the unexpired acknowledgement covering the resource.
+func (f AckFile) Acknowledged(kind, namespace, name string, now time.Time) (Ack, bool) {
+	for _, a := range f.Acks {
+		if !a.Expired(now) && a.Matches(kind, namespace, name) {
+			return a, true
+		}
+	}
+	return Ack{}, false
+}
+
+// AckFilePath returns the acknowledgement file: $CUB_SCOUT_ACKS, then
+// ~/.cub-scout/acks.yaml.
+func AckFilePath() string {
+	if file := os.Getenv("CUB_SCOUT_ACKS"); file != "" {
+		return file
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cub-scout", "acks.yaml")
+}
+
+// loadAckFile reads file; a missing file has no acknowledgements.
+func loadAckFile(file string) (AckFile, error) {
+	var f AckFile
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return f, err
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return AckFile{}, fmt.Errorf("parse %s: %w", file, err)
+	}
+	for i, a := range f.Acks {
+		for _, p := range []string{a.Namespace, a.Name} {
+			if _, err := path.Match(p, ""); err != nil {
+				return AckFile{}, fmt.Errorf("parse %s: ack %d: bad pattern %q", file, i+1, p)
+			}
+		}
+	}
+	return f, nil
+}
+
+func saveAckFile(file string, f AckFile) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o644)
+}
+
+var (
+	ackFileOnce sync.Once
+	ackFile     AckFile
+)
+
+// activeAcks loads the acknowledgement file once per process.
+func activeAcks() AckFile {
+	ackFileOnce.Do(func() {
+		var err error
+		ackFile, err = loadAckFile(AckFilePath())
+		if err != nil {
+			logger.Warn("ignoring acknowledgements", "err", err)
+		}
+	})
+	return ackFile
+}
+
+// isAcknowledged reports whether an unexpired acknowledgement covers the
+// resource.
+func isAcknowledged(kind, namespace, name string) bool {
+	_, ok := activeAcks().Acknowledged(kind, namespace, name, time.Now())
+	return ok
+}
+
+// parseAckExpiry reads --expires: days or weeks ("90d", "2w"), a Go
+// duration ("720h"), or a date ("2027-01-31", the start of that day UTC).
+func parseAckExpiry(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if d, err := parseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --expires %q: want a duration (90d, 12w, 720h) or a date (2027-01-31)", s)
+}
+
+// parseAckResource splits "<kind>/<name>", normalizing the kind.
+func parseAckResource(arg string) (kind, name string, err error) {
+	kind, name, ok := strings.Cut(arg, "/")
+	if !ok || kind == "" || name == "" {
+		return "", "", fmt.Errorf("invalid resource %q: want <kind>/<name>, e.g. deploy/foo", arg)
+	}
+	if _, err := path.Match(name, ""); err != nil {
+		return "", "", fmt.Errorf("invalid name pattern %q", name)
+	}
+	if kind == "*" {
+		return kind, name, nil
+	}
+	return normalizeKind(kind), name, nil
+}
+
+func runAckAdd(cmd *cobra.Command, args []string) error {
+	kind, name, err := parseAckResource(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := path.Match(ackNamespace, ""); err != nil {
+		return fmt.Errorf("invalid namespace pattern %q", ackNamespace)
+	}
+	if strings.TrimSpace(ackReason) == "" {
+		return fmt.Errorf("--reason is required")
+	}
+	now := time.Now().UTC()
+	ack := Ack{Kind: kind, Namespace: ackNamespace, Name: name, Reason: ackReason, AddedBy: os.Getenv("USER"), AddedAt: now.Truncate(time.Second)}
+	if ackExpires != "" {
+		expires, err := parseAckExpiry(ackExpires, now)
+		if err != nil {
+			return err
+		}
+		expires = expires.Truncate(time.Second)
+		ack.Expires = &expires
+	}
+
+	file := AckFilePath()
+	f, err := loadAckFile(file)
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i, a := range f.Acks {
+		if a.Kind == ack.Kind && a.Namespace == ack.Namespace && a.Name == ack.Name {
+			f.Acks[i] = ack
+			replaced = true
+		}
+	}
+	if !replaced {
+		f.Acks = append(f.Acks, ack)
+	}
+	if err := saveAckFile(file, f); err != nil {
+		return fmt.Errorf("save %s: %w", file, err)
+	}
+
+	until := "no expiry"
+	if ack.Expires != nil {
+		until = "until " + ack.Expires.Format("2006-01-02")
+	}
+	verb := "Acknowledged"
+	if replaced {
+		verb = "Updated"
+	}
+	fmt.Printf("✓ %s %s/%s in %s (%s): %s\n", verb, kind, name, ackNamespace, until, ackReason)
+	return nil
+}
+
+func runAckList(cmd *cobra.Command, args []string) error {
+	file := AckFilePath()
+	f, err := loadAckFile(file)
+	if err != nil {
+		return err
+	}
+	if len(f.Acks) == 0 {
+		fmt.Printf("No acknowledgements in %s\n", file)
+		return nil
+	}
+
+	now := time.Now()
+	acks := append([]Ack{}, f.Acks...)
+	sortResources(acks, func(a Ack) (string, string, string) { return a.Namespace, a.Kind, a.Name })
+	expired := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tNAMESPACE\tKIND\tNAME\tEXPIRES\tREASON")
+	fmt.Fprintln(w, "──────\t─────────\t────\t────\t───────\t──────")
+	for _, a := range acks {
+		status, expires := "✓", "never"
+		if a.Expires != nil {
+			expires = a.Expires.Format("2006-01-02")
+		}
+		if a.Expired(now) {
+			status = "✗ expired"
+			expired++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", status, a.Namespace, a.Kind, a.Name, expires, a.Reason)
+	}
+	w.Flush()
+	if expired > 0 {
+		fmt.Printf("\n%d expired: their resources count as orphans again. Renew with 'cub-scout ack add' or remove them.\n", expired)
+	}
+	return nil
+}
+
+func runAckRemove(cmd *cobra.Command, args []string) error {
+	kind, name, err := parseAckResource(args[0])
+	if err != nil {
+		return err
+	}
+	file := AckFilePath()
+	f, err := loadAckFile(file)
+	if err != nil {
+		return err
+	}
+	kept := f.Acks[:0]
+	for _, a := range f.Acks {
+		if !(a.Kind == kind && a.Namespace == ackNamespace && a.Name == name) {
+			kept = append(kept, a)
+		}
+	}
+	if len(kept) == len(f.Acks) {
+		return fmt.Errorf("no acknowledgement for %s/%s in %s", kind, name, ackNamespace)
+	}
+	f.Acks = kept
+	if err := saveAckFile(file, f); err != nil {
+		return fmt.Errorf("save %s: %w", file, err)
+	}
+	fmt.Printf("✓ Removed acknowledgement for %s/%s in %s\n", kind, name, ackNamespace)
+	return nil
+}
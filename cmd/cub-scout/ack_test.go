@@ -0,0 +1,101 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAckFileAcknowledged(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	f := AckFile{Acks: []Ack{
+		{Kind: "Deployment", Namespace: "bar", Name: "foo", Reason: "vendor appliance", Expires: &future},
+		{Kind: "Deployment", Namespace: "*", Name: "debug-*", Reason: "on-call tooling"},
+		{Kind: "*", Namespace: "legacy", Name: "*", Reason: "retiring", Expires: &past},
+	}}
+
+	tests := []struct {
+		kind, ns, name string
+		want           bool
+	}{
+		{"Deployment", "bar", "foo", true},
+		{"Deployment", "other", "foo", false},
+		{"ConfigMap", "bar", "foo", false},
+		{"Deployment", "prod", "debug-shell", true},
+		{"Deployment", "legacy", "app", false}, // expired
+	}
+	for _, tt := range tests {
+		if _, got := f.Acknowledged(tt.kind, tt.ns, tt.name, now); got != tt.want {
+			t.Errorf("Acknowledged(%s, %s, %s) = %v, want %v", tt.kind, tt.ns, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseAckExpiry(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"90d", now.Add(90 * 24 * time.Hour)},
+		{"2w", now.Add(14 * 24 * time.Hour)},
+		{"36h", now.Add(36 * time.Hour)},
+		{"2027-01-31", time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseAckExpiry(tt.in, now)
+		if err != nil {
+			t.Errorf("parseAckExpiry(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseAckExpiry(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "soon", "0d", "-3d"} {
+		if _, err := parseAckExpiry(bad, now); err == nil {
+			t.Errorf("parseAckExpiry(%q) = nil error, want error", bad)
+		}
+	}
+}
+
+func TestAckAddReplacesAndRemoves(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "acks.yaml")
+	t.Setenv("CUB_SCOUT_ACKS", file)
+	defer func() { ackNamespace, ackReason, ackExpires = "default", "", "" }()
+
+	ackNamespace, ackReason, ackExpires = "bar", "vendor appliance", "90d"
+	if err := runAckAdd(ackAddCmd, []string{"deploy/foo"}); err != nil {
+		t.Fatal(err)
+	}
+	ackReason, ackExpires = "vendor appliance, renewed", ""
+	if err := runAckAdd(ackAddCmd, []string{"deployment/foo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := loadAckFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Acks) != 1 {
+		t.Fatalf("got %d acks, want 1 (re-adding replaces): %+v", len(f.Acks), f.Acks)
+	}
+	a := f.Acks[0]
+	if a.Kind != "Deployment" || a.Namespace != "bar" || a.Name != "foo" || a.Reason != "vendor appliance, renewed" || a.Expires != nil {
+		t.Errorf("ack = %+v", a)
+	}
+
+	if err := runAckRemove(ackRemoveCmd, []string{"deploy/foo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runAckRemove(ackRemoveCmd, []string{"deploy/foo"}); err == nil {
+		t.Error("removing a missing ack: want error")
+	}
+	if f, _ := loadAckFile(file); len(f.Acks) != 0 {
+		t.Errorf("got %d acks after remove, want 0", len(f.Acks))
+	}
+}
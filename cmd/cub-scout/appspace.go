@@ -96,11 +96,12 @@ func runAppSpaceList(cmd *cobra.Command, args []string) error {
 		cubArgs = append(cubArgs, "--json")
 	}
 
-	cubCmd := exec.Command("cub", cubArgs...)
-	cubCmd.Stdout = os.Stdout
-	cubCmd.Stderr = os.Stderr
-
-	return cubCmd.Run()
+	out, err := runCubCommand(cubArgs...)
+	if err != nil {
+		return fmt.Errorf("list app spaces: %s", cubStderr(err))
+	}
+	_, err = os.Stdout.Write(out)
+	return err
 }
 
 // AppSpaceResult represents the result of creating an App Space
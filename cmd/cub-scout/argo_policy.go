@@ -0,0 +1,56 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+// printArgoPolicies prints each Argo CD Application's sync policy and
+// notification subscriptions, and the risky policies and silent
+// Applications found.
+func printArgoPolicies(w io.Writer, a agent.ArgoPolicyAudit) {
+	if len(a.Apps) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nARGO CD SYNC POLICY AND NOTIFICATIONS\n")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tAPPLICATION\tNAMESPACE\tSYNC\tNOTIFICATIONS\tRISKS")
+	fmt.Fprintln(tw, "──────\t───────────\t─────────\t────\t─────────────\t─────")
+	for _, app := range a.Apps {
+		status := "✓"
+		if len(app.Risks) > 0 || !app.Notified {
+			status = "⚠"
+		}
+		notify := "none"
+		if app.Notified {
+			notify = truncate(strings.Join(app.Subscriptions, ", "), 40)
+		}
+		risks := make([]string, 0, len(app.Risks))
+		for _, r := range app.Risks {
+			risks = append(risks, r.Reason)
+		}
+		riskList := "-"
+		if len(risks) > 0 {
+			riskList = strings.Join(risks, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", status, app.Name, app.Namespace, app.Sync, notify, riskList)
+	}
+	tw.Flush()
+
+	if !a.NotificationsInstalled {
+		fmt.Fprintf(w, "⚠ Argo CD notifications not configured (no %s); failures of all %d Applications are silent\n",
+			agent.ArgoNotificationsConfigMap, len(a.Apps))
+	} else if n := len(a.Unnotified()); n > 0 {
+		fmt.Fprintf(w, "⚠ %d of %d Applications covered by no notification subscription (failures are silent)\n", n, len(a.Apps))
+	}
+	if n := len(a.Risky()); n > 0 {
+		fmt.Fprintf(w, "⚠ %d Applications with a risky sync policy - run: cub-scout scan --state\n", n)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+func TestPrintArgoPolicies(t *testing.T) {
+	audit := agent.ArgoPolicyAudit{
+		NotificationsInstalled: true,
+		Apps: []agent.ArgoAppPolicy{
+			{Name: "api", Namespace: "argocd", Sync: agent.ArgoSyncPolicy{Automated: true, Prune: true, SelfHeal: true},
+				Subscriptions: []string{"on-sync-failed.slack"}, Notified: true},
+			{Name: "billing", Namespace: "argocd", Production: true,
+				Risks: []agent.ArgoPolicyRisk{{Reason: "ManualSyncProduction"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	printArgoPolicies(&buf, audit)
+	out := buf.String()
+	for _, want := range []string{
+		"ARGO CD SYNC POLICY AND NOTIFICATIONS",
+		"auto+prune+selfHeal",
+		"on-sync-failed.slack",
+		"manual",
+		"ManualSyncProduction",
+		"⚠ 1 of 2 Applications covered by no notification subscription",
+		"⚠ 1 Applications with a risky sync policy",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	audit.NotificationsInstalled = false
+	printArgoPolicies(&buf, audit)
+	if !strings.Contains(buf.String(), "notifications not configured (no argocd-notifications-cm); failures of all 2") {
+		t.Errorf("without notifications:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+// loadArgoTracking reads Argo CD's resource tracking method from the
+// argocd-cm ConfigMap, in whichever namespace Argo CD runs, and sets it for
+// ownership detection. Without Argo CD (or RBAC to read the ConfigMap) both
+// the tracking-id annotation and the instance label are accepted.
+func loadArgoTracking(ctx context.Context, dynClient dynamic.Interface) agent.ArgoTracking {
+	var tracking agent.ArgoTracking
+	l, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).
+		List(ctx, v1.ListOptions{FieldSelector: "metadata.name=argocd-cm"})
+	if err == nil && len(l.Items) > 0 {
+		data, _, _ := unstructured.NestedStringMap(l.Items[0].Object, "data")
+		tracking = agent.ArgoTrackingFromConfigMap(data)
+	}
+	agent.SetArgoTracking(tracking)
+	return tracking
+}
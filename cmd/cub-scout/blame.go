@@ -0,0 +1,467 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	blameNamespace string
+	blameJSON      bool
+	blameGitDir    string
+	blameLimit     int
+)
+
+var blameCmd = &cobra.Command{
+	Use:   "blame <kind/name>",
+	Short: "Show who last changed a resource and which change produced it",
+	Long: `Answer "what change produced the current spec of this object".
+
+Blame combines:
+  - managedFields: which field manager (controller, GitOps tool, kubectl)
+    last wrote the spec, and when
+  - Deployer history: the Flux/Argo CD/Helm revision last applied
+  - ConfigHub revisions: the unit revision deployed (if ConfigHub-managed)
+  - Git commit metadata: author and subject of the applied commit
+
+If the most recent spec writer is kubectl rather than the deployer, the
+resource has been changed out-of-band and blame says so.
+
+Examples:
+  cub-scout blame deploy/api -n prod
+  cub-scout blame deployment/api -n prod --json
+  cub-scout blame deploy/api -n prod --git-dir ~/src/platform-config`,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE:              runBlame,
+	ValidArgsFunction: completeResourceArg,
+}
+
+func init() {
+	rootCmd.AddCommand(blameCmd)
+	blameCmd.Flags().StringVarP(&blameNamespace, "namespace", "n", "default", "Namespace of the resource")
+	blameCmd.Flags().BoolVar(&blameJSON, "json", false, "Output as JSON")
+	blameCmd.Flags().StringVar(&blameGitDir, "git-dir", "", "Local clone of the source repo to read commit details from")
+	blameCmd.Flags().IntVar(&blameLimit, "limit", 5, "Number of deployer and ConfigHub revisions to show")
+	_ = blameCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+}
+
+// BlameResult is the combined change attribution for one resource.
+type BlameResult struct {
+	Object     agent.ResourceRef    `json:"object"`
+	Owner      string               `json:"owner"`
+	LastWriter *agent.FieldManager  `json:"lastSpecWriter,omitempty"`
+	Managers   []agent.FieldManager `json:"managers,omitempty"`
+	Deployer   *DeployerRevision    `json:"deployer,omitempty"`
+	ConfigHub  *BlameConfigHub      `json:"confighub,omitempty"`
+	Verdict    string               `json:"verdict"`
+}
+
+// DeployerRevision is the GitOps deployer state relevant to a resource.
+type DeployerRevision struct {
+	Kind      string               `json:"kind"`
+	Name      string               `json:"name"`
+	Namespace string               `json:"namespace,omitempty"`
+	RepoURL   string               `json:"repoUrl,omitempty"`
+	Revision  string               `json:"revision,omitempty"`
+	Commit    *agent.GitCommit     `json:"commit,omitempty"`
+	History   []agent.HistoryEntry `json:"history,omitempty"`
+}
+
+// BlameConfigHub holds the ConfigHub unit and revisions behind a resource.
+type BlameConfigHub struct {
+	Space       string              `json:"space,omitempty"`
+	Unit        string              `json:"unit"`
+	RevisionNum string              `json:"revisionNum,omitempty"`
+	Revisions   []ConfigHubRevision `json:"revisions,omitempty"`
+}
+
+// ConfigHubRevision is one entry from "cub revision list".
+type ConfigHubRevision struct {
+	Num         int       `json:"num"`
+	Description string    `json:"description,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Source      string    `json:"source,omitempty"` // what made it, e.g. a trigger
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func runBlame(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	kind, name, err := parseResourceArgs(args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	gvr := kindToGVR(kind)
+	if gvr.Resource == "" {
+		return fmt.Errorf("unknown resource kind: %s", kind)
+	}
+	obj, err := dynClient.Resource(gvr).Namespace(blameNamespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get %s/%s: %w", kind, name, err)
+	}
+
+	ownership := agent.DetectOwnership(obj)
+	result := &BlameResult{
+		Object:   agent.ResourceRef{Kind: kind, Name: name, Namespace: blameNamespace},
+		Owner:    displayOwner(ownership.Type),
+		Managers: agent.ParseManagedFields(obj),
+	}
+	result.LastWriter = agent.LastSpecWriter(result.Managers)
+
+	commits := agent.NewCommitEnricher(blameGitDir)
+	result.Deployer = fetchDeployerRevision(ctx, dynClient, ownership, blameNamespace, blameLimit)
+	if result.Deployer != nil {
+		result.Deployer.Commit = commits.Resolve(ctx, result.Deployer.RepoURL, result.Deployer.Revision)
+	}
+	result.ConfigHub = configHubBlame(obj, blameLimit)
+	result.Verdict = blameVerdict(result)
+
+	if blameJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	printBlame(result)
+	return nil
+}
+
+// parseResourceArgs accepts "kind/name" or "kind name" and normalizes the kind.
+func parseResourceArgs(args []string) (string, string, error) {
+	var kind, name string
+	if len(args) == 1 {
+		parts := strings.SplitN(args[0], "/", 2)
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid resource format: use kind/name (e.g., deployment/nginx)")
+		}
+		kind, name = parts[0], parts[1]
+	} else {
+		kind, name = args[0], args[1]
+	}
+	return normalizeKind(kind), name, nil
+}
+
+// fetchDeployerRevision reads the applied revision and history from the
+// Flux Kustomization/HelmRelease, Argo CD Application, or Helm release that
+// owns a resource. Returns nil when there is no deployer or it can't be read.
+func fetchDeployerRevision(ctx context.Context, dynClient dynamic.Interface, ownership agent.Ownership, resourceNamespace string, limit int) *DeployerRevision {
+	switch ownership.Type {
+	case agent.OwnerFlux:
+		gvr := schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+		kind := "Kustomization"
+		if ownership.SubType == "helmrelease" {
+			gvr = schema.GroupVersionResource{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}
+			kind = "HelmRelease"
+		}
+		obj, err := dynClient.Resource(gvr).Namespace(ownership.Namespace).Get(ctx, ownership.Name, v1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		d := &DeployerRevision{Kind: kind, Name: ownership.Name, Namespace: ownership.Namespace}
+		d.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAppliedRevision")
+		d.RepoURL = fluxSourceURL(ctx, dynClient, obj)
+		if data, err := json.Marshal(obj.Object); err == nil {
+			if history, err := agent.ParseFluxResourceHistory(data); err == nil {
+				d.History = limitHistory(history, limit)
+			}
+		}
+		return d
+
+	case agent.OwnerArgo:
+		gvr := schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+		namespace := ownership.Namespace
+		if namespace == "" {
+			namespace = "argocd"
+		}
+		obj, err := dynClient.Resource(gvr).Namespace(namespace).Get(ctx, ownership.Name, v1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		d := &DeployerRevision{Kind: "Application", Name: ownership.Name, Namespace: namespace}
+		d.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "sync", "revision")
+		d.RepoURL, _, _ = unstructured.NestedString(obj.Object, "spec", "source", "repoURL")
+		d.History = limitHistory(argoHistory(obj), limit)
+		return d
+
+	case agent.OwnerHelm:
+		restCfg, err := buildConfig()
+		if err != nil {
+			return nil
+		}
+		clientset, err := kubernetes.NewForConfig(restCfg)
+		if err != nil {
+			return nil
+		}
+		namespace := ownership.Namespace
+		if namespace == "" {
+			namespace = resourceNamespace
+		}
+		history, err := agent.NewHelmTracer(clientset).GetReleaseHistory(ctx, ownership.Name, namespace)
+		if err != nil {
+			return nil
+		}
+		d := &DeployerRevision{Kind: "Release", Name: ownership.Name, Namespace: namespace, History: limitHistory(history, limit)}
+		if len(history) > 0 {
+			d.Revision = history[0].Revision
+		}
+		return d
+	}
+	return nil
+}
+
+// fluxSourceURL returns spec.url of the GitRepository referenced by a Flux
+// deployer, in whichever namespace it lives.
+func fluxSourceURL(ctx context.Context, dynClient dynamic.Interface, deployer *unstructured.Unstructured) string {
+	src, err := agent.NewFluxResolver(agent.ClientFluxLookup(dynClient)).Source(ctx, deployer)
+	if err != nil || src.Kind != "GitRepository" {
+		return ""
+	}
+	return src.URL
+}
+
+// argoHistory converts an Application's status.history to history entries, newest first.
+func argoHistory(app *unstructured.Unstructured) []agent.HistoryEntry {
+	items, _, _ := unstructured.NestedSlice(app.Object, "status", "history")
+	var history []agent.HistoryEntry
+	for _, item := range items {
+		h, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		entry := agent.HistoryEntry{Status: "deployed"}
+		entry.Revision, _ = h["revision"].(string)
+		if deployedAt, ok := h["deployedAt"].(string); ok {
+			entry.Timestamp, _ = time.Parse(time.RFC3339, deployedAt)
+		}
+		if initiatedBy, ok := h["initiatedBy"].(map[string]interface{}); ok {
+			if user, _ := initiatedBy["username"].(string); user != "" {
+				entry.Source = "manual sync by " + user
+			} else if auto, _ := initiatedBy["automated"].(bool); auto {
+				entry.Source = "auto-sync"
+			}
+		}
+		history = append(history, entry)
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Timestamp.After(history[j].Timestamp) })
+	return history
+}
+
+func limitHistory(history []agent.HistoryEntry, limit int) []agent.HistoryEntry {
+	if limit > 0 && len(history) > limit {
+		return history[:limit]
+	}
+	return history
+}
+
+// configHubBlame reads ConfigHub unit annotations and, when cub is available,
+// the unit's recent revisions.
+func configHubBlame(obj *unstructured.Unstructured, limit int) *BlameConfigHub {
+	annotations := obj.GetAnnotations()
+	labels := obj.GetLabels()
+	unit := labels["confighub.com/UnitSlug"]
+	if unit == "" {
+		unit = annotations["confighub.com/UnitSlug"]
+	}
+	if unit == "" {
+		return nil
+	}
+
+	ch := &BlameConfigHub{
+		Unit:        unit,
+		Space:       annotations["confighub.com/SpaceName"],
+		RevisionNum: annotations["confighub.com/RevisionNum"],
+	}
+	if ch.Space != "" {
+		if revisions, err := fetchConfigHubRevisions(ch.Space, unit); err == nil {
+			if limit > 0 && len(revisions) > limit {
+				revisions = revisions[:limit]
+			}
+			ch.Revisions = revisions
+		}
+	}
+	return ch
+}
+
+// fetchConfigHubRevisions lists a unit's revisions via "cub revision list", newest first.
+func fetchConfigHubRevisions(space, unit string) ([]ConfigHubRevision, error) {
+	out, err := runCubCommand("revision", "list", unit, "--space", space, "--json")
+	if err != nil {
+		return nil, err
+	}
+	return parseConfigHubRevisions(out)
+}
+
+// parseConfigHubRevisions parses "cub revision list --json" output, which
+// may be a flat list or nested as [{Revision: {...}, User: {...}}].
+func parseConfigHubRevisions(data []byte) ([]ConfigHubRevision, error) {
+	var response []map[string]interface{}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("parse revisions: %w", err)
+	}
+
+	var revisions []ConfigHubRevision
+	for _, item := range response {
+		rev := item
+		if nested, ok := item["Revision"].(map[string]interface{}); ok {
+			rev = nested
+		}
+		r := ConfigHubRevision{}
+		if n, ok := rev["RevisionNum"].(float64); ok {
+			r.Num = int(n)
+		}
+		r.Description, _ = rev["Description"].(string)
+		r.Source, _ = rev["Source"].(string)
+		if created, ok := rev["CreatedAt"].(string); ok {
+			r.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		}
+		if user, ok := item["User"].(map[string]interface{}); ok {
+			r.Author, _ = user["Username"].(string)
+		}
+		revisions = append(revisions, r)
+	}
+	sort.SliceStable(revisions, func(i, j int) bool { return revisions[i].Num > revisions[j].Num })
+	return revisions, nil
+}
+
+// blameVerdict summarizes what change produced the current spec.
+func blameVerdict(r *BlameResult) string {
+	w := r.LastWriter
+	if w == nil {
+		return "No managedFields recorded; cannot attribute the current spec"
+	}
+
+	when := ""
+	if w.Time != nil {
+		when = " " + formatElapsed(time.Since(*w.Time)) + " ago"
+	}
+
+	if w.ManualChange() {
+		if r.Owner != "Native" {
+			return fmt.Sprintf("Changed out-of-band by %s%s — overrides %s; the next reconcile may revert it", w.Manager, when, r.Owner)
+		}
+		return fmt.Sprintf("Last changed manually by %s%s", w.Manager, when)
+	}
+
+	verdict := fmt.Sprintf("Last applied by %s (%s)%s", w.Tool, w.Manager, when)
+	if d := r.Deployer; d != nil && d.Revision != "" {
+		verdict += fmt.Sprintf(" from %s/%s @ %s", d.Kind, d.Name, d.Revision)
+		if d.Commit != nil && d.Commit.Subject != "" {
+			verdict += fmt.Sprintf(" — %q by %s", d.Commit.Subject, d.Commit.Author)
+		}
+	}
+	if ch := r.ConfigHub; ch != nil && ch.RevisionNum != "" {
+		verdict += fmt.Sprintf(" (ConfigHub %s rev %s)", ch.Unit, ch.RevisionNum)
+	}
+	return verdict
+}
+
+func printBlame(r *BlameResult) {
+	fmt.Printf("\n%s%sBLAME:%s %s%s%s\n\n", colorBold, colorCyan, colorReset, colorBold, r.Object.String(), colorReset)
+	fmt.Printf("  Owner: %s\n", r.Owner)
+	verdictColor := colorGreen
+	if r.LastWriter != nil && r.LastWriter.ManualChange() {
+		verdictColor = colorYellow
+	}
+	fmt.Printf("  %s%s%s\n", verdictColor, r.Verdict, colorReset)
+
+	if len(r.Managers) > 0 {
+		fmt.Printf("\n%sFIELD MANAGERS%s (newest first)\n", colorBold, colorReset)
+		for _, m := range r.Managers {
+			when := "-"
+			if m.Time != nil {
+				when = formatElapsed(time.Since(*m.Time)) + " ago"
+			}
+			sub := ""
+			if m.Subresource != "" {
+				sub = " [" + m.Subresource + "]"
+			}
+			fmt.Printf("  %-32s %-12s %-7s %s%s\n", m.Manager, m.Tool, m.Operation, when, sub)
+			if len(m.Fields) > 0 && m.Subresource == "" {
+				fmt.Printf("  %s%s%s\n", colorDim, "  "+strings.Join(m.Fields, ", "), colorReset)
+			}
+		}
+	}
+
+	if d := r.Deployer; d != nil {
+		fmt.Printf("\n%sDEPLOYER%s %s/%s", colorBold, colorReset, d.Kind, d.Name)
+		if d.Namespace != "" {
+			fmt.Printf(" in %s", d.Namespace)
+		}
+		fmt.Println()
+		if d.Revision != "" {
+			fmt.Printf("  Revision: %s%s%s\n", colorPurple, d.Revision, colorReset)
+		}
+		if c := d.Commit; c != nil {
+			if c.Subject != "" {
+				fmt.Printf("  Commit:   %s — %s\n", c.Subject, c.Author)
+			}
+			if c.URL != "" {
+				fmt.Printf("  URL:      %s%s%s\n", colorBlue, c.URL, colorReset)
+			}
+		}
+		for _, h := range d.History {
+			ts := "-"
+			if !h.Timestamp.IsZero() {
+				ts = h.Timestamp.Local().Format("2006-01-02 15:04")
+			}
+			line := fmt.Sprintf("  %s  %s  %s", ts, h.Revision, h.Status)
+			if h.Source != "" {
+				line += "  (" + h.Source + ")"
+			}
+			fmt.Println(line)
+		}
+	}
+
+	if ch := r.ConfigHub; ch != nil {
+		fmt.Printf("\n%sCONFIGHUB%s unit %s", colorBold, colorReset, ch.Unit)
+		if ch.Space != "" {
+			fmt.Printf(" in space %s", ch.Space)
+		}
+		if ch.RevisionNum != "" {
+			fmt.Printf(" (deployed rev %s)", ch.RevisionNum)
+		}
+		fmt.Println()
+		for _, rev := range ch.Revisions {
+			marker := " "
+			if strconv.Itoa(rev.Num) == ch.RevisionNum {
+				marker = "▶"
+			}
+			line := fmt.Sprintf("  %s rev %d  %s", marker, rev.Num, rev.CreatedAt.Local().Format("2006-01-02 15:04"))
+			if rev.Author != "" {
+				line += "  " + rev.Author
+			}
+			if rev.Description != "" {
+				line += "  " + rev.Description
+			}
+			fmt.Println(line)
+		}
+	}
+	fmt.Println()
+}
@@ -0,0 +1,94 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+func TestBlameVerdictManualOverride(t *testing.T) {
+	when := time.Now().Add(-30 * time.Minute)
+	r := &BlameResult{
+		Owner:      "Flux",
+		LastWriter: &agent.FieldManager{Manager: "kubectl-edit", Tool: "kubectl", Time: &when},
+	}
+	got := blameVerdict(r)
+	if !strings.Contains(got, "out-of-band by kubectl-edit") || !strings.Contains(got, "overrides Flux") {
+		t.Errorf("unexpected verdict: %s", got)
+	}
+}
+
+func TestBlameVerdictDeployer(t *testing.T) {
+	r := &BlameResult{
+		Owner:      "Flux",
+		LastWriter: &agent.FieldManager{Manager: "kustomize-controller", Tool: "Flux"},
+		Deployer: &DeployerRevision{
+			Kind:     "Kustomization",
+			Name:     "apps",
+			Revision: "main@sha1:abc1234",
+			Commit:   &agent.GitCommit{SHA: "abc1234", Subject: "Scale api", Author: "Alice"},
+		},
+		ConfigHub: &BlameConfigHub{Unit: "api", RevisionNum: "7"},
+	}
+	want := `Last applied by Flux (kustomize-controller) from Kustomization/apps @ main@sha1:abc1234 — "Scale api" by Alice (ConfigHub api rev 7)`
+	if got := blameVerdict(r); got != want {
+		t.Errorf("verdict =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+func TestBlameVerdictNoManagedFields(t *testing.T) {
+	if got := blameVerdict(&BlameResult{Owner: "Native"}); !strings.Contains(got, "No managedFields") {
+		t.Errorf("unexpected verdict: %s", got)
+	}
+}
+
+func TestParseConfigHubRevisions(t *testing.T) {
+	data := []byte(`[
+		{"Revision": {"RevisionNum": 6, "Description": "scale to 3", "CreatedAt": "2026-01-30T09:00:00Z"}, "User": {"Username": "alice"}},
+		{"Revision": {"RevisionNum": 7, "Description": "bump image", "CreatedAt": "2026-01-30T10:00:00Z"}, "User": {"Username": "bob"}}
+	]`)
+	revisions, err := parseConfigHubRevisions(data)
+	if err != nil {
+		t.Fatalf("parseConfigHubRevisions: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Num != 7 || revisions[0].Author != "bob" || revisions[0].Description != "bump image" {
+		t.Errorf("unexpected revisions: %+v", revisions)
+	}
+	if revisions[1].CreatedAt.IsZero() {
+		t.Error("expected CreatedAt to be parsed")
+	}
+}
+
+func TestArgoHistory(t *testing.T) {
+	app := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"history": []interface{}{
+				map[string]interface{}{"revision": "aaa", "deployedAt": "2026-01-30T09:00:00Z",
+					"initiatedBy": map[string]interface{}{"automated": true}},
+				map[string]interface{}{"revision": "bbb", "deployedAt": "2026-01-30T10:00:00Z",
+					"initiatedBy": map[string]interface{}{"username": "alice"}},
+			},
+		},
+	}}
+	history := argoHistory(app)
+	if len(history) != 2 || history[0].Revision != "bbb" || history[0].Source != "manual sync by alice" || history[1].Source != "auto-sync" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+func TestParseResourceArgs(t *testing.T) {
+	kind, name, err := parseResourceArgs([]string{"deploy/api"})
+	if err != nil || kind != "Deployment" || name != "api" {
+		t.Errorf("got %s/%s, %v", kind, name, err)
+	}
+	if _, _, err := parseResourceArgs([]string{"api"}); err == nil {
+		t.Error("expected error for missing kind")
+	}
+}
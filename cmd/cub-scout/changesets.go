@@ -0,0 +1,408 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	changeSetSpaces []string
+	changeSetJSON   bool
+)
+
+var changeSetsCmd = &cobra.Command{
+	Use:   "changesets",
+	Short: "Show ConfigHub changesets and how far they have applied",
+	Long: `Show ConfigHub changesets: changes to several units that are applied
+together. Open the same view in the hub TUI with C.`,
+}
+
+var changeSetsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List changesets with their apply progress",
+	Long: `List changesets with how many of their units have applied.
+
+A changeset's state follows its units:
+
+  open      no unit has applied yet
+  applying  an apply is running for at least one unit
+  applied   every unit is live at its head revision
+  partial   some units applied, others are pending or failed
+  failed    units failed and none applied
+
+Examples:
+  cub-scout changesets list
+  cub-scout changesets list --space prod
+  cub-scout changesets list --json`,
+	Args: cobra.NoArgs,
+	RunE: runChangeSetsList,
+}
+
+var changeSetsStatusCmd = &cobra.Command{
+	Use:   "status [space/]changeset",
+	Short: "Show a changeset's units, progress per target, and failures",
+	Long: `Show a changeset's member units, apply progress per target, and why
+each failed unit failed.
+
+The changeset may be named as space/slug, or by slug alone when only one
+space has a changeset of that name.
+
+Examples:
+  cub-scout changesets status prod/rotate-certs
+  cub-scout changesets status rotate-certs --space prod
+  cub-scout changesets status rotate-certs --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runChangeSetsStatus,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{changeSetsListCmd, changeSetsStatusCmd} {
+		c.Flags().StringSliceVar(&changeSetSpaces, "space", nil, "Spaces to look in (default: all spaces)")
+		_ = c.RegisterFlagCompletionFunc("space", completeSpaces)
+		c.Flags().BoolVar(&changeSetJSON, "json", false, "Output as JSON")
+		changeSetsCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(changeSetsCmd)
+}
+
+// Changeset and member unit states.
+const (
+	changeSetEmpty    = "empty"
+	changeSetOpen     = "open"
+	changeSetApplying = "applying"
+	changeSetApplied  = "applied"
+	changeSetPartial  = "partial"
+	changeSetFailed   = "failed"
+
+	changeSetUnitPending = "pending"
+)
+
+// ChangeSet is a changeset and the state of its member units.
+type ChangeSet struct {
+	Space       string     `json:"space"`
+	Slug        string     `json:"slug"`
+	DisplayName string     `json:"displayName,omitempty"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	// State is empty, open, applying, applied, partial or failed
+	State   string            `json:"state"`
+	Units   []ChangeSetUnit   `json:"units"`
+	Targets []ChangeSetTarget `json:"targets"`
+}
+
+// ChangeSetUnit is a member unit of a changeset.
+type ChangeSetUnit struct {
+	Unit         string `json:"unit"`
+	Target       string `json:"target,omitempty"`
+	HeadRevision int    `json:"headRevision"`
+	LiveRevision int    `json:"liveRevision"`
+	// State is pending, applying, applied or failed
+	State string `json:"state"`
+	// Detail says why a failed unit failed, or what gate a pending one
+	// waits on
+	Detail string `json:"detail,omitempty"`
+}
+
+// ChangeSetTarget is a changeset's apply progress on one target.
+type ChangeSetTarget struct {
+	Target   string `json:"target"`
+	Units    int    `json:"units"`
+	Applied  int    `json:"applied"`
+	Applying int    `json:"applying"`
+	Failed   int    `json:"failed"`
+}
+
+// Count returns how many member units are in state.
+func (c ChangeSet) Count(state string) int {
+	n := 0
+	for _, u := range c.Units {
+		if u.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+// changeSetUnitState is how far a member unit's apply has got.
+func changeSetUnitState(u CubUnitData) (state, detail string) {
+	s := u.UnitStatus
+	switch {
+	case s.Status == "Error" || strings.Contains(s.ActionResult, "Fail"):
+		detail = s.ActionResult
+		if detail == "" {
+			detail = "status " + s.Status
+		}
+		if s.Action != "" {
+			detail = s.Action + ": " + detail
+		}
+		return changeSetFailed, detail
+	case s.Action != "" && s.ActionResult == "" && s.ActionTerminatedAt == "":
+		return changeSetApplying, ""
+	case u.Unit.LiveRevisionNum > 0 && u.Unit.LiveRevisionNum >= u.Unit.HeadRevisionNum:
+		return changeSetApplied, ""
+	}
+	// A gated unit says what it waits on
+	return changeSetUnitPending, applyGateSummary(u)
+}
+
+// buildChangeSets joins a space's changesets with its units. A unit is a
+// member when its pending change belongs to the changeset.
+func buildChangeSets(space string, sets []CubChangeSetData, units []CubUnitData) []ChangeSet {
+	var out []ChangeSet
+	for _, d := range sets {
+		cs := ChangeSet{
+			Space:       space,
+			Slug:        d.ChangeSet.Slug,
+			DisplayName: d.ChangeSet.DisplayName,
+			Description: d.ChangeSet.Description,
+			Units:       []ChangeSetUnit{},
+			Targets:     []ChangeSetTarget{},
+		}
+		if t, err := time.Parse(time.RFC3339, d.ChangeSet.CreatedAt); err == nil {
+			cs.CreatedAt = &t
+		}
+		byTarget := map[string]*ChangeSetTarget{}
+		for _, u := range units {
+			if u.Unit.ChangeSetID == "" || u.Unit.ChangeSetID != d.ChangeSet.ChangeSetID {
+				continue
+			}
+			state, detail := changeSetUnitState(u)
+			cs.Units = append(cs.Units, ChangeSetUnit{
+				Unit:         u.Unit.Slug,
+				Target:       u.Target.Slug,
+				HeadRevision: u.Unit.HeadRevisionNum,
+				LiveRevision: u.Unit.LiveRevisionNum,
+				State:        state,
+				Detail:       detail,
+			})
+			t := byTarget[u.Target.Slug]
+			if t == nil {
+				t = &ChangeSetTarget{Target: u.Target.Slug}
+				byTarget[u.Target.Slug] = t
+			}
+			t.Units++
+			switch state {
+			case changeSetApplied:
+				t.Applied++
+			case changeSetApplying:
+				t.Applying++
+			case changeSetFailed:
+				t.Failed++
+			}
+		}
+		sort.Slice(cs.Units, func(i, j int) bool { return cs.Units[i].Unit < cs.Units[j].Unit })
+		for _, t := range byTarget {
+			cs.Targets = append(cs.Targets, *t)
+		}
+		sort.Slice(cs.Targets, func(i, j int) bool { return cs.Targets[i].Target < cs.Targets[j].Target })
+		cs.State = changeSetState(cs)
+		out = append(out, cs)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Slug < out[j].Slug })
+	return out
+}
+
+func changeSetState(cs ChangeSet) string {
+	applied, failed := cs.Count(changeSetApplied), cs.Count(changeSetFailed)
+	switch {
+	case len(cs.Units) == 0:
+		return changeSetEmpty
+	case cs.Count(changeSetApplying) > 0:
+		return changeSetApplying
+	case applied == len(cs.Units):
+		return changeSetApplied
+	case applied > 0:
+		return changeSetPartial
+	case failed > 0:
+		return changeSetFailed
+	}
+	return changeSetOpen
+}
+
+// loadChangeSets lists the changesets in spaces. Units are only listed for
+// spaces that have changesets.
+func loadChangeSets(spaces []string) ([]ChangeSet, error) {
+	var all []ChangeSet
+	for _, space := range spaces {
+		sets, err := loadChangeSetsForSpace(space)
+		if err != nil {
+			return nil, fmt.Errorf("list changesets in space %s: %w", space, err)
+		}
+		if len(sets) == 0 {
+			continue
+		}
+		units, err := loadUnitsForSpace(space)
+		if err != nil {
+			return nil, fmt.Errorf("list units in space %s: %w", space, err)
+		}
+		all = append(all, buildChangeSets(space, sets, units)...)
+	}
+	return all, nil
+}
+
+func loadChangeSetsForSpace(spaceSlug string) ([]CubChangeSetData, error) {
+	out, err := runCubCommand("changeset", "list", "--space", spaceSlug, "--json")
+	if err != nil {
+		return nil, err
+	}
+	var sets []CubChangeSetData
+	if err := json.Unmarshal(out, &sets); err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// changeSetScope returns --space, or every space.
+func changeSetScope() ([]string, error) {
+	if len(changeSetSpaces) > 0 {
+		return changeSetSpaces, nil
+	}
+	spaces, err := listSpaceSlugs()
+	if err != nil {
+		return nil, fmt.Errorf("list spaces: %w", err)
+	}
+	return spaces, nil
+}
+
+func runChangeSetsList(cmd *cobra.Command, args []string) error {
+	if err := checkCubAuth(cmd.CommandPath()); err != nil {
+		return err
+	}
+	spaces, err := changeSetScope()
+	if err != nil {
+		return err
+	}
+	sets, err := loadChangeSets(spaces)
+	if err != nil {
+		return err
+	}
+	if changeSetJSON {
+		if sets == nil {
+			sets = []ChangeSet{}
+		}
+		return writeJSON(os.Stdout, "ChangeSets", sets)
+	}
+	printChangeSets(os.Stdout, sets, time.Now())
+	return nil
+}
+
+func runChangeSetsStatus(cmd *cobra.Command, args []string) error {
+	if err := checkCubAuth(cmd.CommandPath()); err != nil {
+		return err
+	}
+	name := args[0]
+	if space, slug, ok := strings.Cut(name, "/"); ok {
+		changeSetSpaces, name = []string{space}, slug
+	}
+	spaces, err := changeSetScope()
+	if err != nil {
+		return err
+	}
+	sets, err := loadChangeSets(spaces)
+	if err != nil {
+		return err
+	}
+	cs, err := findChangeSet(sets, name)
+	if err != nil {
+		return err
+	}
+	if changeSetJSON {
+		return writeJSON(os.Stdout, "ChangeSet", cs)
+	}
+	printChangeSetStatus(os.Stdout, cs)
+	return nil
+}
+
+// findChangeSet returns the changeset named slug, which must be in only
+// one of the spaces listed.
+func findChangeSet(sets []ChangeSet, slug string) (ChangeSet, error) {
+	var found []ChangeSet
+	for _, cs := range sets {
+		if cs.Slug == slug {
+			found = append(found, cs)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return ChangeSet{}, fmt.Errorf("changeset %s not found", slug)
+	case 1:
+		return found[0], nil
+	}
+	var spaces []string
+	for _, cs := range found {
+		spaces = append(spaces, cs.Space)
+	}
+	return ChangeSet{}, fmt.Errorf("changeset %s is in several spaces (%s); name one with --space or space/changeset", slug, strings.Join(spaces, ", "))
+}
+
+func printChangeSets(w io.Writer, sets []ChangeSet, now time.Time) {
+	if len(sets) == 0 {
+		fmt.Fprintln(w, "No changesets found.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SPACE\tCHANGESET\tSTATE\tAPPLIED\tFAILED\tAGE")
+	for _, cs := range sets {
+		age := "-"
+		if cs.CreatedAt != nil {
+			age = formatElapsed(now.Sub(*cs.CreatedAt))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d\t%d\t%s\n",
+			cs.Space, cs.Slug, cs.State, cs.Count(changeSetApplied), len(cs.Units), cs.Count(changeSetFailed), age)
+	}
+	tw.Flush()
+}
+
+func printChangeSetStatus(w io.Writer, cs ChangeSet) {
+	fmt.Fprintf(w, "%sChangeset:%s %s/%s (%s)\n", colorBold, colorReset, cs.Space, cs.Slug, cs.State)
+	if cs.Description != "" {
+		fmt.Fprintf(w, "  %s\n", cs.Description)
+	}
+	if len(cs.Units) == 0 {
+		fmt.Fprintln(w, "\nNo units have changes in this changeset.")
+		return
+	}
+	fmt.Fprintf(w, "  %s %d/%d unit(s) applied\n", makeBar(cs.Count(changeSetApplied), len(cs.Units), 20), cs.Count(changeSetApplied), len(cs.Units))
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tAPPLIED\tAPPLYING\tFAILED")
+	for _, t := range cs.Targets {
+		fmt.Fprintf(tw, "%s\t%d/%d\t%d\t%d\n", orDash(t.Target), t.Applied, t.Units, t.Applying, t.Failed)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "UNIT\tTARGET\tREVISION\tSTATE")
+	for _, u := range cs.Units {
+		fmt.Fprintf(tw, "%s\t%s\t%d→%d\t%s\n", u.Unit, orDash(u.Target), u.LiveRevision, u.HeadRevision, u.State)
+	}
+	tw.Flush()
+
+	var failed []ChangeSetUnit
+	for _, u := range cs.Units {
+		if u.State == changeSetFailed {
+			failed = append(failed, u)
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s✗ %d unit(s) failed:%s\n", colorRed, len(failed), colorReset)
+	for _, u := range failed {
+		fmt.Fprintf(w, "  %s: %s\n", u.Unit, u.Detail)
+	}
+	fmt.Fprintf(w, "%sOpen in the hub TUI: %s%s\n", colorDim, hubFocusCommand(cs.Space, "unit", failed[0].Unit), colorReset)
+}
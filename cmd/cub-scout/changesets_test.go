@@ -0,0 +1,118 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// changeSetCub is a prod space with a rotate-certs changeset part-way
+// through: one unit applied, one applying, one failed, and one that is not
+// a member. staging has no changesets.
+var changeSetCub = fakeCub{
+	"changeset list --space prod --json": `[
+		{"ChangeSet": {"ChangeSetID": "cs-1", "Slug": "rotate-certs", "Description": "Rotate TLS certs", "CreatedAt": "2026-03-01T10:00:00Z"}},
+		{"ChangeSet": {"ChangeSetID": "cs-2", "Slug": "empty"}}]`,
+	"changeset list --space staging --json": `[]`,
+	"unit list --space prod --json": `[
+		{"Unit": {"Slug": "api", "HeadRevisionNum": 4, "LiveRevisionNum": 4, "ChangeSetID": "cs-1"}, "Target": {"Slug": "east"}},
+		{"Unit": {"Slug": "web", "HeadRevisionNum": 3, "LiveRevisionNum": 2, "ChangeSetID": "cs-1"}, "Target": {"Slug": "east"},
+			"UnitStatus": {"Action": "Apply"}},
+		{"Unit": {"Slug": "worker", "HeadRevisionNum": 5, "LiveRevisionNum": 4, "ChangeSetID": "cs-1"}, "Target": {"Slug": "west"},
+			"UnitStatus": {"Status": "Error", "Action": "Apply", "ActionResult": "ApplyFailed", "ActionTerminatedAt": "2026-03-01T10:05:00Z"}},
+		{"Unit": {"Slug": "other", "HeadRevisionNum": 1, "LiveRevisionNum": 1}}]`,
+}
+
+func TestLoadChangeSets(t *testing.T) {
+	changeSetCub.install(t)
+	sets, err := loadChangeSets([]string{"prod", "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 2 || sets[0].Slug != "empty" || sets[0].State != changeSetEmpty {
+		t.Fatalf("sets = %+v, want empty first, sorted by slug", sets)
+	}
+	cs := sets[1]
+	if cs.State != changeSetApplying || len(cs.Units) != 3 || cs.CreatedAt == nil {
+		t.Fatalf("rotate-certs = %+v", cs)
+	}
+	var states []string
+	for _, u := range cs.Units {
+		states = append(states, u.Unit+":"+u.State)
+	}
+	if got := strings.Join(states, " "); got != "api:applied web:applying worker:failed" {
+		t.Errorf("unit states = %s", got)
+	}
+	if cs.Units[2].Detail != "Apply: ApplyFailed" {
+		t.Errorf("failure detail = %q", cs.Units[2].Detail)
+	}
+	if len(cs.Targets) != 2 || cs.Targets[0] != (ChangeSetTarget{Target: "east", Units: 2, Applied: 1, Applying: 1}) ||
+		cs.Targets[1].Failed != 1 {
+		t.Errorf("targets = %+v", cs.Targets)
+	}
+
+	// Once the apply finishes, the changeset is partly applied
+	cs.Units[1].State = changeSetUnitPending
+	if got := changeSetState(cs); got != changeSetPartial {
+		t.Errorf("state = %s, want partial", got)
+	}
+
+	if _, err := findChangeSet(append(sets, ChangeSet{Space: "qa", Slug: "empty"}), "empty"); err == nil || !strings.Contains(err.Error(), "prod, qa") {
+		t.Errorf("ambiguous changeset should name both spaces, got %v", err)
+	}
+}
+
+func TestPrintChangeSetStatus(t *testing.T) {
+	changeSetCub.install(t)
+	sets, _ := loadChangeSets([]string{"prod"})
+	var buf bytes.Buffer
+	printChangeSetStatus(&buf, sets[1])
+	out := buf.String()
+	for _, want := range []string{"prod/rotate-certs (applying)", "1/3 unit(s) applied", "east    1/2", "worker  west", "1 unit(s) failed", "worker: Apply: ApplyFailed", "--focus space/prod/unit/worker"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("status should contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestChangeSetPanel(t *testing.T) {
+	changeSetCub.install(t)
+	m := jumpTestModel()
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	m = asModel(next)
+	if !m.changeSetPanel || !m.changeSetsLoading || cmd == nil {
+		t.Fatal("C should open the changesets panel and list changesets")
+	}
+	next, _ = m.Update(cmd())
+	m = asModel(next)
+	if m.changeSetsLoading || len(m.changeSets) != 2 {
+		t.Fatalf("changesets = %+v (err %v)", m.changeSets, m.changeSetsErr)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	view := m.View()
+	for _, want := range []string{"CHANGESETS", "prod/rotate-certs", "applying", "1/3", "1 failed", "target east", "ApplyFailed"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel should show %q:\n%s", want, view)
+		}
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.changeSetPanel {
+		t.Error("Esc should close the changesets panel")
+	}
+}
+
+// asModel unwraps Update's result, which may be the model or a pointer.
+func asModel(next tea.Model) Model {
+	if p, ok := next.(*Model); ok {
+		return *p
+	}
+	return next.(Model)
+}
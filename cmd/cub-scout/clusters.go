@@ -0,0 +1,165 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ClusterConfig is the cluster registry, e.g.:
+//
+//	clusters:
+//	  - context: arn:aws:eks:us-east-1:123456789012:cluster/prod-east
+//	    name: prod-east
+//	    environment: prod
+//	    labels: {region: us-east-1}
+//	    targets: [prod-east-k8s]
+//	  - context: kind-*
+//	    name: local
+//	    environment: dev
+//
+// Context is a kubeconfig context name or glob pattern; the first entry that
+// matches wins. Name is the canonical cluster name every command reports.
+// Targets are the ConfigHub targets that deploy to the cluster; when set,
+// they replace name-based target matching.
+type ClusterConfig struct {
+	Clusters []ClusterEntry `yaml:"clusters"`
+}
+
+// ClusterEntry is one cluster in the registry.
+type ClusterEntry struct {
+	Context     string            `yaml:"context"`
+	Name        string            `yaml:"name"`
+	Environment string            `yaml:"environment,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Targets     []string          `yaml:"targets,omitempty"`
+}
+
+// ClusterConfigFile returns the cluster registry path:
+// $CUB_SCOUT_CLUSTERS, then ~/.cub-scout/clusters.yaml.
+func ClusterConfigFile() string {
+	if file := os.Getenv("CUB_SCOUT_CLUSTERS"); file != "" {
+		return file
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cub-scout", "clusters.yaml")
+}
+
+// loadClusterConfig reads file. A missing file is an empty registry.
+func loadClusterConfig(file string) (ClusterConfig, error) {
+	var cfg ClusterConfig
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ClusterConfig{}, nil
+		}
+		return ClusterConfig{}, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return ClusterConfig{}, fmt.Errorf("parse %s: %w", file, err)
+	}
+	for i, c := range cfg.Clusters {
+		if c.Context == "" || c.Name == "" {
+			return ClusterConfig{}, fmt.Errorf("parse %s: cluster %d needs both context and name", file, i+1)
+		}
+		if _, err := path.Match(c.Context, ""); err != nil {
+			return ClusterConfig{}, fmt.Errorf("parse %s: bad context pattern %q", file, c.Context)
+		}
+	}
+	return cfg, nil
+}
+
+// Lookup returns the first entry whose context matches contextName.
+func (c ClusterConfig) Lookup(contextName string) (ClusterEntry, bool) {
+	for _, e := range c.Clusters {
+		if ok, _ := path.Match(e.Context, contextName); ok {
+			return e, true
+		}
+	}
+	return ClusterEntry{}, false
+}
+
+// Resolve returns the registry entry for contextName, or one named from
+// the context by the built-in heuristics (EKS ARN, GKE, kind prefixes).
+func (c ClusterConfig) Resolve(contextName string) ClusterEntry {
+	if e, ok := c.Lookup(contextName); ok {
+		return e
+	}
+	name := extractClusterName(contextName)
+	if name == "" || name == "unknown" {
+		name = "default"
+	}
+	return ClusterEntry{Context: contextName, Name: name}
+}
+
+// Canonical maps a cluster name or context, as found in snapshots taken
+// before the registry existed, to its canonical name.
+func (c ClusterConfig) Canonical(name string) ClusterEntry {
+	for _, e := range c.Clusters {
+		if e.Name == name {
+			return e
+		}
+	}
+	if e, ok := c.Lookup(name); ok {
+		return e
+	}
+	return ClusterEntry{Name: name}
+}
+
+// MatchesTarget reports whether ConfigHub target slug deploys to cluster.
+// A cluster with registered targets matches only those; otherwise the
+// target slug is compared with the cluster name.
+func (e ClusterEntry) MatchesTarget(slug string) bool {
+	if len(e.Targets) > 0 {
+		for _, t := range e.Targets {
+			if t == slug {
+				return true
+			}
+		}
+		return false
+	}
+	return matchesCluster(slug, e.Name)
+}
+
+var (
+	clusterConfigOnce sync.Once
+	clusterConfig     ClusterConfig
+)
+
+// activeClusterConfig loads the cluster registry once per process.
+func activeClusterConfig() ClusterConfig {
+	clusterConfigOnce.Do(func() {
+		var err error
+		clusterConfig, err = loadClusterConfig(ClusterConfigFile())
+		if err != nil {
+			logger.Warn("ignoring cluster registry", "err", err)
+		}
+	})
+	return clusterConfig
+}
+
+// currentCluster identifies the cluster the kubeconfig points at: the
+// cluster CLUSTER_NAME names when set, otherwise the registry entry for the
+// current context.
+func currentCluster() ClusterEntry {
+	contextName := getCurrentContext()
+	if name := os.Getenv("CLUSTER_NAME"); name != "" {
+		c := activeClusterConfig().Canonical(name)
+		c.Context = contextName
+		return c
+	}
+	return activeClusterConfig().Resolve(contextName)
+}
+
+// currentClusterName is the canonical name of the current cluster, used in
+// entry IDs, snapshots and exports.
+func currentClusterName() string {
+	return currentCluster().Name
+}
@@ -0,0 +1,132 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+const testClusterRegistry = `clusters:
+  - context: arn:aws:eks:us-east-1:123456789012:cluster/prod-east
+    name: prod-east
+    environment: prod
+    labels: {region: us-east-1}
+    targets: [eks-east]
+  - context: kind-*
+    name: local
+    environment: dev
+`
+
+func TestLoadClusterConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := loadClusterConfig(filepath.Join(dir, "missing.yaml"))
+	if err != nil || len(cfg.Clusters) != 0 {
+		t.Fatalf("missing file should be an empty registry: %+v, %v", cfg, err)
+	}
+
+	file := filepath.Join(dir, "clusters.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(testClusterRegistry)
+	if cfg, err = loadClusterConfig(file); err != nil || len(cfg.Clusters) != 2 {
+		t.Fatalf("load: %+v, %v", cfg, err)
+	}
+
+	write("clusters:\n  - context: kind-dev\n")
+	if _, err := loadClusterConfig(file); err == nil {
+		t.Error("expected an error for an entry without a name")
+	}
+	write("clusters:\n  - context: \"[\"\n    name: x\n")
+	if _, err := loadClusterConfig(file); err == nil {
+		t.Error("expected an error for a bad context pattern")
+	}
+}
+
+func TestClusterConfigResolve(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "clusters.yaml")
+	if err := os.WriteFile(file, []byte(testClusterRegistry), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadClusterConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		context, name, env string
+	}{
+		{"arn:aws:eks:us-east-1:123456789012:cluster/prod-east", "prod-east", "prod"},
+		{"kind-payments", "local", "dev"},                           // glob
+		{"gke_acme_us-central1_staging", "staging", ""},             // heuristic
+		{"arn:aws:eks:eu-west-1:123456789012:cluster/eu", "eu", ""}, // unregistered ARN
+		{"unknown", "default", ""},                                  // no kubeconfig
+	}
+	for _, tt := range tests {
+		c := cfg.Resolve(tt.context)
+		if c.Name != tt.name || c.Environment != tt.env {
+			t.Errorf("Resolve(%q) = %s/%s, want %s/%s", tt.context, c.Name, c.Environment, tt.name, tt.env)
+		}
+	}
+
+	// Snapshots pushed under a context name join their canonical cluster
+	if c := cfg.Canonical("kind-payments"); c.Name != "local" {
+		t.Errorf("Canonical(context) = %s, want local", c.Name)
+	}
+	if c := cfg.Canonical("prod-east"); c.Environment != "prod" || c.Labels["region"] != "us-east-1" {
+		t.Errorf("Canonical(name) = %+v", c)
+	}
+	if c := cfg.Canonical("west"); c.Name != "west" || c.Environment != "" {
+		t.Errorf("Canonical(unknown) = %+v", c)
+	}
+}
+
+func TestClusterEntryMatchesTarget(t *testing.T) {
+	registered := ClusterEntry{Name: "prod-east", Targets: []string{"eks-east"}}
+	if !registered.MatchesTarget("eks-east") || registered.MatchesTarget("prod-east-k8s") {
+		t.Error("registered targets should match exactly and replace name matching")
+	}
+	byName := ClusterEntry{Name: "prod-east"}
+	if !byName.MatchesTarget("prod-east-k8s") || byName.MatchesTarget("staging") {
+		t.Error("without registered targets, targets match by cluster name")
+	}
+}
+
+func TestCurrentClusterName(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: kind-payments
+contexts:
+- name: kind-payments
+  context: {cluster: kind-payments}
+clusters:
+- name: kind-payments
+  cluster: {server: "https://127.0.0.1:6443"}
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+	t.Setenv("CLUSTER_NAME", "")
+	t.Setenv("CUB_SCOUT_CLUSTERS", filepath.Join(dir, "missing.yaml"))
+	clusterConfigOnce = sync.Once{}
+	t.Cleanup(func() { clusterConfigOnce = sync.Once{} })
+
+	if got := currentClusterName(); got != "payments" {
+		t.Errorf("currentClusterName() = %q, want payments from the kind- context", got)
+	}
+	t.Setenv("CLUSTER_NAME", "prod-east")
+	if c := currentCluster(); c.Name != "prod-east" || c.Context != "kind-payments" {
+		t.Errorf("CLUSTER_NAME should name the cluster: %+v", c)
+	}
+}
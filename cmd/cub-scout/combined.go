@@ -78,6 +78,7 @@ func init() {
 	combinedCmd.Flags().StringVar(&combinedGitURL, "git-url", "", "Git repository URL to parse")
 	combinedCmd.Flags().StringVar(&combinedGitPath, "git-path", "", "Local path to Git repository")
 	combinedCmd.Flags().StringVarP(&combinedNamespace, "namespace", "n", "", "Namespace to scan in cluster")
+	_ = combinedCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
 	combinedCmd.Flags().BoolVar(&combinedJSON, "json", false, "Output as JSON")
 	combinedCmd.Flags().BoolVar(&combinedSuggest, "suggest", false, "Generate Hub/App Space model proposal")
 	combinedCmd.Flags().BoolVar(&combinedApply, "apply", false, "Create App Space and Units in ConfigHub")
@@ -333,23 +334,41 @@ func convertToSuggestionJSON(s *HubAppSpaceSuggestion) *SuggestionJSON {
 	if s == nil {
 		return nil
 	}
-	units := make([]UnitJSON, 0, len(s.Units))
-	for _, u := range s.Units {
-		workloads := make([]string, 0, len(u.Workloads))
-		for _, w := range u.Workloads {
-			workloads = append(workloads, fmt.Sprintf("%s/%s", w.Namespace, w.Name))
-		}
+	return &SuggestionJSON{
+		AppSpace: s.AppSpace,
+		Units:    unitsToJSON(s.Units),
+		Bases:    unitsToJSON(s.Bases),
+	}
+}
+
+func unitsToJSON(in []HubAppSpaceUnit) []UnitJSON {
+	units := make([]UnitJSON, 0, len(in))
+	for _, u := range in {
 		units = append(units, UnitJSON{
-			Slug:      u.Slug,
-			App:       u.App,
-			Variant:   u.Variant,
-			Workloads: workloads,
+			Slug:       u.Slug,
+			App:        u.App,
+			Variant:    u.Variant,
+			Workloads:  workloadRefs(u.Workloads),
+			Upstream:   u.Upstream,
+			Confidence: u.Confidence,
+			Rationale:  u.Rationale,
 		})
 	}
-	return &SuggestionJSON{
-		AppSpace: s.AppSpace,
-		Units:    units,
+	return units
+}
+
+// workloadRefs returns "namespace/name" for each workload, prefixed with
+// "cluster:" when it came from a named kube context.
+func workloadRefs(workloads []WorkloadInfo) []string {
+	refs := make([]string, 0, len(workloads))
+	for _, w := range workloads {
+		ref := fmt.Sprintf("%s/%s", w.Namespace, w.Name)
+		if w.Cluster != "" {
+			ref = w.Cluster + ":" + ref
+		}
+		refs = append(refs, ref)
 	}
+	return refs
 }
 
 // applyProposal creates the App Space and Units in ConfigHub
@@ -0,0 +1,456 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+
+	"github.com/confighub/cub-scout/pkg/diff"
+	"github.com/confighub/cub-scout/pkg/query"
+	"github.com/confighub/cub-scout/pkg/redact"
+)
+
+var (
+	compareNamespace   string
+	compareNoKustomize bool
+	compareRelease     string
+	compareValues      []string
+	compareJSON        bool
+	compareDiff        diffFlags
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare desired configuration against the live cluster",
+}
+
+var compareLocalCmd = &cobra.Command{
+	Use:   "local <dir-or-file>",
+	Short: "Diff local manifests against live cluster objects",
+	Long: `Build manifests from a local checkout and diff each object against its live
+counterpart, for teams that aren't managing the config in ConfigHub yet.
+
+Sources are found as in 'scan path':
+  - a directory with a kustomization.yaml is rendered with 'kubectl kustomize'
+  - a directory with a Chart.yaml is rendered with 'helm template'
+  - other *.yaml and *.yml files are read as they are
+
+Only fields set in the local manifest are compared, so defaults and status
+the cluster adds are not differences. Quantities are compared by value
+(500m equals 0.5), and Secret values are never printed.
+
+Objects without a namespace are compared in --namespace (default: default).
+Live objects with no local manifest are not reported.
+
+With --diff-format, each changed or missing object is shown as a diff from
+live to local: unified, side-by-side (fitted to the terminal width) or an
+RFC 6902 JSON patch.
+
+Examples:
+  cub-scout compare local ./manifests -n prod
+  cub-scout compare local ./clusters/prod
+  cub-scout compare local ./charts/api -n prod --release api -f values-prod.yaml
+  cub-scout compare local ./manifests -n prod --diff-format side-by-side
+  cub-scout compare local ./manifests --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCompareLocal,
+}
+
+func init() {
+	compareLocalCmd.Flags().StringVarP(&compareNamespace, "namespace", "n", "default", "Namespace for objects that don't set one")
+	_ = compareLocalCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	compareLocalCmd.Flags().BoolVar(&compareNoKustomize, "no-kustomize", false, "Read files as they are instead of rendering kustomizations")
+	compareLocalCmd.Flags().StringVar(&compareRelease, "release", "", "Helm release name for charts (default: chart directory name)")
+	compareLocalCmd.Flags().StringSliceVarP(&compareValues, "values", "f", nil, "Helm values files for charts")
+	compareLocalCmd.Flags().BoolVar(&compareJSON, "json", false, "Output as JSON")
+	addDiffFlags(compareLocalCmd, &compareDiff)
+
+	compareCmd.AddCommand(compareLocalCmd)
+	rootCmd.AddCommand(compareCmd)
+}
+
+// LocalDiff is one local manifest object compared with the cluster.
+type LocalDiff struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Source is the file, kustomization or chart the object came from
+	Source  string              `json:"source"`
+	Status  string              `json:"status"` // in-sync, changed, missing, error
+	Changes []query.DriftChange `json:"changes,omitempty"`
+	Error   string              `json:"error,omitempty"`
+
+	// The compared fields of each side, for --diff-format
+	local, live interface{}
+}
+
+// localObject is a rendered manifest and where it came from.
+type localObject struct {
+	source string
+	doc    map[string]interface{}
+}
+
+// liveGetter fetches the live object for a manifest; found is false when it
+// doesn't exist.
+type liveGetter func(apiVersion, kind, namespace, name string) (live map[string]interface{}, namespaced, found bool, err error)
+
+func runCompareLocal(cmd *cobra.Command, args []string) error {
+	root := args[0]
+	render, err := compareDiff.options()
+	if err != nil {
+		return err
+	}
+	sources, err := findManifestSources(root, !compareNoKustomize, true)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", root, err)
+	}
+
+	var objects []localObject
+	for _, src := range sources {
+		var docs []map[string]interface{}
+		if src.Helm {
+			docs, err = helmTemplate(src.Path, compareRelease, compareNamespace, compareValues)
+		} else {
+			docs, err = loadManifestSource(src)
+		}
+		if err != nil {
+			if src.Kustomized || src.Helm {
+				return fmt.Errorf("%s: %w", src.Path, err)
+			}
+			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", src.Path, err)
+			continue
+		}
+		for _, doc := range docs {
+			if kind, _ := doc["kind"].(string); kind != "" {
+				objects = append(objects, localObject{source: src.Path, doc: doc})
+			}
+		}
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("no Kubernetes manifests found in %s", root)
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create discovery client: %w", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	ctx := context.Background()
+	get := func(apiVersion, kind, namespace, name string) (map[string]interface{}, bool, bool, error) {
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil, false, false, err
+		}
+		mapping, err := mapper.RESTMapping(gv.WithKind(kind).GroupKind(), gv.Version)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				return nil, false, false, fmt.Errorf("%s %s is not served by the cluster", apiVersion, kind)
+			}
+			return nil, false, false, err
+		}
+		namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+		res := dynClient.Resource(mapping.Resource)
+		var ri dynamic.ResourceInterface = res
+		if namespaced {
+			ri = res.Namespace(namespace)
+		}
+		live, err := ri.Get(ctx, name, v1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, namespaced, false, nil
+		}
+		if err != nil {
+			return nil, namespaced, false, err
+		}
+		return live.Object, namespaced, true, nil
+	}
+
+	diffs := compareLocalObjects(objects, compareNamespace, get)
+	if compareJSON {
+		return writeJSON(os.Stdout, "LocalDiffs", diffs)
+	}
+	return printLocalDiffs(os.Stdout, root, diffs, render)
+}
+
+// helmTemplate renders a chart with 'helm template'.
+func helmTemplate(chart, release, namespace string, values []string) ([]map[string]interface{}, error) {
+	if release == "" {
+		release = filepath.Base(filepath.Clean(chart))
+	}
+	args := []string{"template", release, chart, "--namespace", namespace}
+	for _, v := range values {
+		args = append(args, "--values", v)
+	}
+	cmd := exec.Command("helm", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("helm template: %s", msg)
+		}
+		return nil, fmt.Errorf("helm template: %w", err)
+	}
+	return decodeYAMLDocs(out)
+}
+
+// compareLocalObjects diffs each local object against the live one.
+func compareLocalObjects(objects []localObject, defaultNamespace string, get liveGetter) []LocalDiff {
+	diffs := make([]LocalDiff, 0, len(objects))
+	for _, obj := range objects {
+		apiVersion, _ := obj.doc["apiVersion"].(string)
+		kind, _ := obj.doc["kind"].(string)
+		metadata, _ := obj.doc["metadata"].(map[string]interface{})
+		name, _ := metadata["name"].(string)
+		namespace, _ := metadata["namespace"].(string)
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+
+		d := LocalDiff{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name, Source: obj.source}
+		live, namespaced, found, err := get(apiVersion, kind, namespace, name)
+		if !namespaced {
+			d.Namespace = ""
+		}
+		switch {
+		case err != nil:
+			d.Status = "error"
+			d.Error = err.Error()
+		case !found:
+			d.Status = "missing"
+			d.local, _ = diffSides(obj.doc, nil)
+		default:
+			d.Changes = diffLocalObject(obj.doc, live)
+			d.local, d.live = diffSides(obj.doc, live)
+			d.Status = "in-sync"
+			if len(d.Changes) > 0 {
+				d.Status = "changed"
+			}
+		}
+		diffs = append(diffs, d)
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		a, b := diffs[i], diffs[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return diffs
+}
+
+// diffLocalObject compares the fields set in a local manifest with the same
+// fields of the live object. metadata.namespace is left to the lookup, since
+// manifests often omit it.
+func diffLocalObject(local, live map[string]interface{}) []query.DriftChange {
+	local = stripNamespace(local)
+	if local["kind"] == "Secret" {
+		local = secretStringDataToData(local)
+	}
+
+	var changes []query.DriftChange
+	for _, c := range query.Compare(local, projectOnto(live, local)) {
+		if quantitiesEqual(c.Declared, c.Live) {
+			continue
+		}
+		if local["kind"] == "Secret" && (strings.HasPrefix(c.Path, "data") || strings.HasPrefix(c.Path, "stringData")) {
+			c.Declared, c.Live = redacted(c.Declared), redacted(c.Live)
+		} else {
+			c.Declared, c.Live = activeRedactor().Value(c.Declared), activeRedactor().Value(c.Live)
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// diffSides returns the local manifest and the same fields of the live
+// object, as diffLocalObject compares them, for --diff-format. live is nil
+// for an object that isn't in the cluster. Secret values are redacted.
+func diffSides(local, live map[string]interface{}) (interface{}, interface{}) {
+	local = stripNamespace(local)
+	if local["kind"] == "Secret" {
+		local = secretStringDataToData(local)
+	}
+	var projected map[string]interface{}
+	if live != nil {
+		projected, _ = projectOnto(live, local).(map[string]interface{})
+	}
+	if local["kind"] == "Secret" {
+		local, projected = redact.SecretData(local, projected)
+	}
+	if projected == nil {
+		return local, nil
+	}
+	return local, projected
+}
+
+func stripNamespace(doc map[string]interface{}) map[string]interface{} {
+	metadata, ok := doc["metadata"].(map[string]interface{})
+	if !ok {
+		return doc
+	}
+	if _, ok := metadata["namespace"]; !ok {
+		return doc
+	}
+	out := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		out[k] = v
+	}
+	m := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		if k != "namespace" {
+			m[k] = v
+		}
+	}
+	out["metadata"] = m
+	return out
+}
+
+// secretStringDataToData folds stringData into base64 data, as the API server
+// does on write, so the two compare.
+func secretStringDataToData(doc map[string]interface{}) map[string]interface{} {
+	stringData, ok := doc["stringData"].(map[string]interface{})
+	if !ok {
+		return doc
+	}
+	out := make(map[string]interface{}, len(doc))
+	for k, v := range doc {
+		if k != "stringData" {
+			out[k] = v
+		}
+	}
+	data := map[string]interface{}{}
+	if existing, ok := doc["data"].(map[string]interface{}); ok {
+		for k, v := range existing {
+			data[k] = v
+		}
+	}
+	for k, v := range stringData {
+		data[k] = base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(v)))
+	}
+	out["data"] = data
+	return out
+}
+
+func redacted(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return redact.Mask
+}
+
+// quantitiesEqual reports whether two scalar values are the same resource
+// quantity written differently, such as "500m" and "0.5" or 1 and "1".
+func quantitiesEqual(a, b interface{}) bool {
+	parse := func(v interface{}) (resource.Quantity, bool) {
+		switch v.(type) {
+		case string, int, int64, float64:
+			q, err := resource.ParseQuantity(fmt.Sprint(v))
+			return q, err == nil
+		}
+		return resource.Quantity{}, false
+	}
+	qa, ok := parse(a)
+	if !ok {
+		return false
+	}
+	qb, ok := parse(b)
+	return ok && qa.Cmp(qb) == 0
+}
+
+// printLocalDiffs prints one line per object. With render, changed and
+// missing objects are followed by their diff from live to local.
+func printLocalDiffs(w io.Writer, root string, diffs []LocalDiff, render *diff.Options) error {
+	fmt.Fprintf(w, "%sCOMPARE LOCAL%s %s → live cluster\n\n", colorBold, colorReset, root)
+
+	counts := map[string]int{}
+	for _, d := range diffs {
+		counts[d.Status]++
+		ref := d.Kind + "/" + d.Name
+		if d.Namespace != "" {
+			ref = d.Kind + "/" + d.Namespace + "/" + d.Name
+		}
+		switch d.Status {
+		case "in-sync":
+			fmt.Fprintf(w, "%s✓%s %s\n", colorGreen, colorReset, ref)
+		case "missing":
+			fmt.Fprintf(w, "%s+%s %s %snot in cluster (%s)%s\n", colorCyan, colorReset, ref, colorDim, d.Source, colorReset)
+		case "error":
+			fmt.Fprintf(w, "%s?%s %s %s%s%s\n", colorRed, colorReset, ref, colorDim, d.Error, colorReset)
+		case "changed":
+			fmt.Fprintf(w, "%s✗%s %s %s%d difference(s) (%s)%s\n", colorYellow, colorReset, ref, colorDim, len(d.Changes), d.Source, colorReset)
+			if render == nil {
+				for _, c := range d.Changes {
+					fmt.Fprintf(w, "    %s: local %s, live %s\n", c.Path, formatDiffValue(c.Declared), formatDiffValue(c.Live))
+				}
+			}
+		}
+		if render != nil && (d.Status == "changed" || d.Status == "missing") {
+			doc := diff.Document{Name: ref, FromLabel: "live", ToLabel: "local", From: d.live, To: d.local}
+			if err := diff.Render(w, doc, *render); err != nil {
+				return err
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d object(s): %d in sync, %d changed, %d not in cluster", len(diffs), counts["in-sync"], counts["changed"], counts["missing"])
+	if counts["error"] > 0 {
+		fmt.Fprintf(w, ", %d could not be compared", counts["error"])
+	}
+	fmt.Fprintln(w)
+	return nil
+}
+
+func formatDiffValue(v interface{}) string {
+	if v == nil {
+		return "<not set>"
+	}
+	var s string
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		s = string(data)
+	default:
+		s = fmt.Sprint(v)
+	}
+	if len(s) > 60 {
+		s = s[:57] + "..."
+	}
+	return s
+}
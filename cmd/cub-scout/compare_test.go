@@ -0,0 +1,199 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/diff"
+)
+
+func TestDiffLocalObject(t *testing.T) {
+	local := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "api", "namespace": "prod"},
+		"spec": map[string]interface{}{
+			"replicas": 3,
+			"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{
+					"name":      "api",
+					"image":     "api:1.2",
+					"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "0.5", "memory": "1Gi"}},
+				},
+			}}},
+		},
+	}
+	live := map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "api", "namespace": "prod", "uid": "x", "generation": int64(4)},
+		"spec": map[string]interface{}{
+			"replicas":             int64(5),
+			"revisionHistoryLimit": int64(10),
+			"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{
+					"name":                     "api",
+					"image":                    "api:1.2",
+					"imagePullPolicy":          "IfNotPresent",
+					"terminationMessagePath":   "/dev/termination-log",
+					"terminationMessagePolicy": "File",
+					"resources":                map[string]interface{}{"requests": map[string]interface{}{"cpu": "500m", "memory": "1Gi"}},
+				},
+			}}},
+		},
+		"status": map[string]interface{}{"readyReplicas": int64(5)},
+	}
+
+	changes := diffLocalObject(local, live)
+	if len(changes) != 1 || changes[0].Path != "spec.replicas" {
+		t.Fatalf("changes = %+v, want only spec.replicas (defaults, status and equal quantities ignored)", changes)
+	}
+
+	delete(local["metadata"].(map[string]interface{}), "namespace")
+	if got := diffLocalObject(local, live); len(got) != 1 {
+		t.Errorf("manifest without namespace: changes = %+v", got)
+	}
+}
+
+func TestDiffLocalSecret(t *testing.T) {
+	local := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "db"},
+		"stringData": map[string]interface{}{"user": "admin", "password": "hunter2"},
+	}
+	live := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "db", "namespace": "prod"},
+		"data":       map[string]interface{}{"user": "YWRtaW4=", "password": "c2VjcmV0"},
+	}
+
+	changes := diffLocalObject(local, live)
+	if len(changes) != 1 || changes[0].Path != "data.password" {
+		t.Fatalf("changes = %+v, want data.password only", changes)
+	}
+	if changes[0].Declared != "(redacted)" || changes[0].Live != "(redacted)" {
+		t.Errorf("secret values leaked: %+v", changes[0])
+	}
+}
+
+func TestCompareLocalObjects(t *testing.T) {
+	doc := func(apiVersion, kind, namespace, name string) map[string]interface{} {
+		meta := map[string]interface{}{"name": name}
+		if namespace != "" {
+			meta["namespace"] = namespace
+		}
+		return map[string]interface{}{"apiVersion": apiVersion, "kind": kind, "metadata": meta, "data": map[string]interface{}{"k": "v"}}
+	}
+	objects := []localObject{
+		{source: "app/cm.yaml", doc: doc("v1", "ConfigMap", "", "settings")},
+		{source: "app/cm.yaml", doc: doc("v1", "ConfigMap", "other", "changed")},
+		{source: "app/new.yaml", doc: doc("v1", "ConfigMap", "", "new")},
+		{source: "crds", doc: doc("example.com/v1", "Widget", "", "w")},
+		{source: "rbac", doc: doc("rbac.authorization.k8s.io/v1", "ClusterRole", "", "reader")},
+	}
+
+	var gotNamespaces []string
+	get := func(apiVersion, kind, namespace, name string) (map[string]interface{}, bool, bool, error) {
+		gotNamespaces = append(gotNamespaces, namespace)
+		switch name {
+		case "settings", "reader":
+			return doc(apiVersion, kind, namespace, name), kind != "ClusterRole", true, nil
+		case "changed":
+			live := doc(apiVersion, kind, namespace, name)
+			live["data"] = map[string]interface{}{"k": "v2"}
+			return live, true, true, nil
+		case "new":
+			return nil, true, false, nil
+		}
+		return nil, false, false, errors.New("example.com/v1 Widget is not served by the cluster")
+	}
+
+	diffs := compareLocalObjects(objects, "prod", get)
+	if gotNamespaces[0] != "prod" || gotNamespaces[1] != "other" {
+		t.Errorf("lookup namespaces = %v, want default prod and explicit other", gotNamespaces)
+	}
+	status := map[string]string{}
+	for _, d := range diffs {
+		status[d.Name] = d.Status
+	}
+	want := map[string]string{"settings": "in-sync", "changed": "changed", "new": "missing", "w": "error", "reader": "in-sync"}
+	for name, s := range want {
+		if status[name] != s {
+			t.Errorf("%s status = %q, want %q", name, status[name], s)
+		}
+	}
+	if diffs[0].Name != "reader" || diffs[0].Namespace != "" {
+		t.Errorf("cluster-scoped object should sort first without a namespace: %+v", diffs[0])
+	}
+
+	var buf bytes.Buffer
+	_ = printLocalDiffs(&buf, "./app", diffs, nil)
+	out := buf.String()
+	for _, s := range []string{"data.k: local v, live v2", "not in cluster (app/new.yaml)", "5 object(s): 2 in sync, 1 changed, 1 not in cluster, 1 could not be compared"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output missing %q:\n%s", s, out)
+		}
+	}
+
+	buf.Reset()
+	if err := printLocalDiffs(&buf, "./app", diffs, &diff.Options{Format: diff.Unified}); err != nil {
+		t.Fatal(err)
+	}
+	out = buf.String()
+	for _, s := range []string{"--- live/ConfigMap/other/changed", "-  k: v2", "+  k: v", "+++ local/ConfigMap/prod/new", "+kind: ConfigMap"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("unified output missing %q:\n%s", s, out)
+		}
+	}
+	if strings.Contains(out, "data.k: local") {
+		t.Errorf("--diff-format should replace the per-field lines:\n%s", out)
+	}
+}
+
+func TestDiffSidesRedactsSecrets(t *testing.T) {
+	secret := func(value string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "v1", "kind": "Secret",
+			"metadata": map[string]interface{}{"name": "db", "namespace": "prod"},
+			"data":     map[string]interface{}{"password": value},
+		}
+	}
+	local, live := diffSides(secret("c2VjcmV0"), secret("b3RoZXI="))
+	var buf bytes.Buffer
+	if err := diff.Render(&buf, diff.Document{Name: "Secret/prod/db", From: live, To: local}, diff.Options{Format: diff.Unified}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "c2VjcmV0") || strings.Contains(out, "b3RoZXI=") {
+		t.Fatalf("secret values leaked into the diff:\n%s", out)
+	}
+	if !strings.Contains(out, "-  password: (redacted, differs)") || !strings.Contains(out, "+  password: (redacted)") {
+		t.Errorf("expected the changed password to show as redacted:\n%s", out)
+	}
+}
+
+func TestQuantitiesEqual(t *testing.T) {
+	tests := []struct {
+		a, b interface{}
+		want bool
+	}{
+		{"500m", "0.5", true},
+		{1, "1", true},
+		{"1Gi", "1024Mi", true},
+		{"1Gi", "1G", false},
+		{"abc", "abc", false},
+		{map[string]interface{}{}, "1", false},
+	}
+	for _, tt := range tests {
+		if got := quantitiesEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("quantitiesEqual(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,12 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,7 +18,12 @@ import (
 	"github.com/spf13/cobra"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/confighub/cub-scout/pkg/queries"
 )
 
 // Namespace completion cache (avoid repeated API calls during tab-complete)
@@ -66,40 +77,93 @@ func completeNamespaces(cmd *cobra.Command, args []string, toComplete string) ([
 	return filterPrefix(namespaces, toComplete), cobra.ShellCompDirectiveNoFileComp
 }
 
-// completeKinds returns common Kubernetes resource kinds
+// completeKinds returns common Kubernetes resource kinds, followed by any
+// other listable kinds the cluster serves (CRDs included)
 func completeKinds(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-	// Common workload and config kinds that cub-scout typically queries
-	kinds := []string{
-		// Workloads
-		"Deployment",
-		"StatefulSet",
-		"DaemonSet",
-		"ReplicaSet",
-		"Pod",
-		"Job",
-		"CronJob",
-		// Config
-		"ConfigMap",
-		"Secret",
-		// Networking
-		"Service",
-		"Ingress",
-		"NetworkPolicy",
-		// Storage
-		"PersistentVolumeClaim",
-		// Flux
-		"GitRepository",
-		"Kustomization",
-		"HelmRelease",
-		"HelmRepository",
-		// Argo CD
-		"Application",
-		"ApplicationSet",
-		"AppProject",
+	kinds := append([]string{}, commonKinds...)
+	seen := map[string]bool{}
+	for _, k := range kinds {
+		seen[k] = true
+	}
+	if cfg, err := buildConfig(); err == nil {
+		key := fmt.Sprintf("kinds-%x", sha256.Sum256([]byte(cfg.Host)))[:18]
+		for _, k := range cachedCompletion(key, func() ([]string, error) { return discoverKinds(cfg) }) {
+			if !seen[k] {
+				seen[k] = true
+				kinds = append(kinds, k)
+			}
+		}
 	}
 	return filterPrefix(kinds, toComplete), cobra.ShellCompDirectiveNoFileComp
 }
 
+// discoverKinds returns the sorted kinds of listable resources on the cluster.
+func discoverKinds(cfg *rest.Config) ([]string, error) {
+	cfg = rest.CopyConfig(cfg)
+	cfg.Timeout = 2 * time.Second // don't block the shell
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := dc.ServerPreferredResources()
+	if len(lists) == 0 && err != nil {
+		return nil, err // partial discovery failures still return usable lists
+	}
+	seen := map[string]bool{}
+	var kinds []string
+	for _, l := range lists {
+		for _, r := range l.APIResources {
+			if strings.Contains(r.Name, "/") || seen[r.Kind] || !hasVerb(r.Verbs, "list") {
+				continue
+			}
+			seen[r.Kind] = true
+			kinds = append(kinds, r.Kind)
+		}
+	}
+	sort.Strings(kinds)
+	return kinds, nil
+}
+
+func hasVerb(verbs []string, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+// commonKinds are the workload and config kinds cub-scout typically queries,
+// completed first and offline.
+var commonKinds = []string{
+	// Workloads
+	"Deployment",
+	"StatefulSet",
+	"DaemonSet",
+	"ReplicaSet",
+	"Pod",
+	"Job",
+	"CronJob",
+	// Config
+	"ConfigMap",
+	"Secret",
+	// Networking
+	"Service",
+	"Ingress",
+	"NetworkPolicy",
+	// Storage
+	"PersistentVolumeClaim",
+	// Flux
+	"GitRepository",
+	"Kustomization",
+	"HelmRelease",
+	"HelmRepository",
+	// Argo CD
+	"Application",
+	"ApplicationSet",
+	"AppProject",
+}
+
 // completeOwners returns valid owner types for --owner flag
 func completeOwners(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	owners := []string{
@@ -114,6 +178,231 @@ func completeOwners(cmd *cobra.Command, args []string, toComplete string) ([]str
 	return filterPrefix(owners, toComplete), cobra.ShellCompDirectiveNoFileComp
 }
 
+// completeKubeContexts completes kubeconfig context names for a
+// comma-separated list flag such as --contexts.
+func completeKubeContexts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	rawConfig, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	done := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		done, toComplete = toComplete[:i+1], toComplete[i+1:]
+	}
+	var names []string
+	for name := range rawConfig.Contexts {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, done+name)
+		}
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSavedQueries completes saved query names for -q. Names may be
+// written bare or as @name, and are completed after AND/OR as well.
+func completeSavedQueries(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := queries.NewQueryStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Complete the last word of the expression, keeping what precedes it
+	head, word := "", toComplete
+	if i := strings.LastIndex(toComplete, " "); i >= 0 {
+		head, word = toComplete[:i+1], toComplete[i+1:]
+	}
+	at := ""
+	if strings.HasPrefix(word, "@") {
+		at, word = "@", word[1:]
+	}
+
+	var completions []string
+	for _, q := range store.List() {
+		if strings.HasPrefix(strings.ToLower(q.Name), strings.ToLower(word)) {
+			completions = append(completions, head+at+q.Name+"\t"+q.Description)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSpaces returns ConfigHub space slugs from cached cub output
+func completeSpaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	spaces := cachedCompletion("spaces", func() ([]string, error) {
+		out, err := runCubCommand("space", "list", "--json", "--quiet")
+		if err != nil {
+			return nil, err
+		}
+		var list []struct {
+			Space struct {
+				Slug string `json:"Slug"`
+			} `json:"Space"`
+		}
+		if err := json.Unmarshal(out, &list); err != nil {
+			return nil, err
+		}
+		var slugs []string
+		for _, s := range list {
+			slugs = append(slugs, s.Space.Slug)
+		}
+		sort.Strings(slugs)
+		return slugs, nil
+	})
+	return filterPrefix(spaces, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeUnits returns ConfigHub unit slugs in the --space flag's space (or
+// the cub context's default space) from cached cub output
+func completeUnits(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	space, _ := cmd.Flags().GetString("space")
+	if space == "" {
+		if ctx, _, err := getStatusCubContext(); err == nil {
+			space = ctx.Settings.DefaultSpace
+		}
+	}
+	if space == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	units := cachedCompletion("units-"+sanitizeSlug(space), func() ([]string, error) {
+		out, err := runCubCommand("unit", "list", "--space", space, "--json", "--quiet")
+		if err != nil {
+			return nil, err
+		}
+		var list []struct {
+			Unit struct {
+				Slug string `json:"Slug"`
+			} `json:"Unit"`
+		}
+		if err := json.Unmarshal(out, &list); err != nil {
+			return nil, err
+		}
+		var slugs []string
+		for _, u := range list {
+			slugs = append(slugs, u.Unit.Slug)
+		}
+		sort.Strings(slugs)
+		return slugs, nil
+	})
+	return filterPrefix(units, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeResourceArg completes the resource argument of trace, debug,
+// blame and timeline like kubectl: kind/name, or the name after a separate
+// kind argument, from live resources in the current namespace.
+func completeResourceArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 1 && !strings.Contains(args[0], "/"):
+		// <kind> <name>
+		return filterPrefix(completeResourceNames(cmd, normalizeKind(args[0])), toComplete), cobra.ShellCompDirectiveNoFileComp
+	case len(args) > 0:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	kind, prefix, found := strings.Cut(toComplete, "/")
+	if !found {
+		var kinds []string
+		for _, k := range commonKinds {
+			if kindToGVR(k).Resource != "" {
+				kinds = append(kinds, strings.ToLower(k)+"/")
+			}
+		}
+		return filterPrefix(kinds, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+
+	var names []string
+	for _, name := range completeResourceNames(cmd, normalizeKind(kind)) {
+		names = append(names, kind+"/"+name)
+	}
+	return filterPrefix(names, kind+"/"+prefix), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeResourceNames returns the sorted names of live resources of kind
+// in completionNamespace, or nil if the kind is unknown or the cluster can't
+// be reached in time.
+func completeResourceNames(cmd *cobra.Command, kind string) []string {
+	gvr := kindToGVR(kind)
+	if gvr.Resource == "" {
+		return nil
+	}
+	dynClient, err := kubeClient()
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), 2*time.Second)
+	defer cancel()
+	names, _ := listResourceNames(ctx, dynClient, gvr, completionNamespace(cmd))
+	return names
+}
+
+// listResourceNames returns the sorted, unique names of gvr in namespace.
+func listResourceNames(ctx context.Context, dynClient dynamic.Interface, gvr schema.GroupVersionResource, namespace string) ([]string, error) {
+	list, err := dynClient.Resource(gvr).Namespace(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range list.Items {
+		names = appendUnique(names, item.GetName())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// completionNamespace is the namespace positional arguments are completed
+// in: --namespace when given, else the kubeconfig context's namespace, as
+// kubectl does.
+func completionNamespace(cmd *cobra.Command) string {
+	if f := cmd.Flags().Lookup("namespace"); f != nil && f.Changed {
+		return f.Value.String()
+	}
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	if ns, _, err := kubeConfig.Namespace(); err == nil && ns != "" {
+		return ns
+	}
+	return "default"
+}
+
+// completionCacheTTL bounds how stale cached cub and discovery output can be.
+// Every tab press is a new process, so this cache lives on disk.
+const completionCacheTTL = 2 * time.Minute
+
+// completionCacheDir returns ~/.cub-scout/cache/completion
+func completionCacheDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cub-scout", "cache", "completion")
+}
+
+// cachedCompletion returns the values cached under key, calling load when the
+// cache is missing or older than completionCacheTTL. If load fails, stale
+// values are better than none.
+func cachedCompletion(key string, load func() ([]string, error)) []string {
+	file := filepath.Join(completionCacheDir(), key+".json")
+
+	var cached []string
+	fi, statErr := os.Stat(file)
+	if statErr == nil {
+		if data, err := os.ReadFile(file); err == nil {
+			_ = json.Unmarshal(data, &cached)
+		}
+		if time.Since(fi.ModTime()) < completionCacheTTL {
+			return cached
+		}
+	}
+
+	values, err := load()
+	if err != nil {
+		return cached
+	}
+	if data, err := json.Marshal(values); err == nil {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err == nil {
+			_ = os.WriteFile(file, data, 0o644)
+		}
+	}
+	return values
+}
+
 // filterPrefix filters strings by prefix (case-insensitive)
 func filterPrefix(items []string, prefix string) []string {
 	if prefix == "" {
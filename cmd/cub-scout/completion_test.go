@@ -4,9 +4,19 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 )
 
 func TestCompleteOwnersIncludesCrossplaneAndTerraform(t *testing.T) {
@@ -30,3 +40,120 @@ func TestCompleteOwnersIncludesCrossplaneAndTerraform(t *testing.T) {
 		t.Fatalf("expected Crossplane in owner completions, got: %v", owners)
 	}
 }
+
+func TestCompleteSavedQueries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cmd := &cobra.Command{}
+
+	got, _ := completeSavedQueries(cmd, nil, "namespace=prod* AND @unm")
+	if len(got) != 1 || !strings.HasPrefix(got[0], "namespace=prod* AND @unmanaged\t") {
+		t.Fatalf("expected @unmanaged completion after AND, got %v", got)
+	}
+
+	got, _ = completeSavedQueries(cmd, nil, "hel")
+	if len(got) != 1 || !strings.HasPrefix(got[0], "helm-only\t") {
+		t.Fatalf("expected helm-only, got %v", got)
+	}
+
+	if resolved := resolveSavedQueries("@unmanaged AND namespace=prod"); resolved != "owner=Native AND namespace=prod" {
+		t.Errorf("resolveSavedQueries(@unmanaged) = %q", resolved)
+	}
+}
+
+func TestCompleteResourceArgKinds(t *testing.T) {
+	got, directive := completeResourceArg(&cobra.Command{}, nil, "dep")
+	if len(got) != 1 || got[0] != "deployment/" {
+		t.Fatalf("expected deployment/, got %v", got)
+	}
+	if directive&cobra.ShellCompDirectiveNoSpace == 0 {
+		t.Error("kind completion should not add a space after the slash")
+	}
+}
+
+func TestListResourceNames(t *testing.T) {
+	deployment := func(ns, name string) runtime.Object {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("apps/v1")
+		u.SetKind("Deployment")
+		u.SetNamespace(ns)
+		u.SetName(name)
+		return u
+	}
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	fake := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "DeploymentList"},
+		deployment("prod", "web"), deployment("prod", "api"), deployment("staging", "worker"))
+
+	got, err := listResourceNames(context.Background(), fake, gvr, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "api,web" {
+		t.Errorf("names in prod = %v, want [api web]", got)
+	}
+}
+
+func TestCompletionNamespace(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: dev
+contexts:
+- name: dev
+  context: {cluster: dev, namespace: payments}
+clusters:
+- name: dev
+  cluster: {server: https://127.0.0.1:6443}
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("namespace", "n", "default", "")
+	if got := completionNamespace(cmd); got != "payments" {
+		t.Errorf("without -n: %q, want the context namespace payments", got)
+	}
+	_ = cmd.Flags().Set("namespace", "prod")
+	if got := completionNamespace(cmd); got != "prod" {
+		t.Errorf("with -n prod: %q", got)
+	}
+}
+
+func TestCompleteResourceArgAfterKind(t *testing.T) {
+	// A kind/name first argument is complete; nothing follows it
+	got, _ := completeResourceArg(&cobra.Command{}, []string{"deployment/api"}, "")
+	if len(got) != 0 {
+		t.Errorf("expected no completions after kind/name, got %v", got)
+	}
+	// An unknown separate kind has no names to offer
+	got, _ = completeResourceArg(&cobra.Command{}, []string{"nosuchkind"}, "")
+	if len(got) != 0 {
+		t.Errorf("expected no names for an unknown kind, got %v", got)
+	}
+}
+
+func TestCachedCompletion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	calls := 0
+	load := func() ([]string, error) {
+		calls++
+		return []string{"payments", "platform"}, nil
+	}
+	cachedCompletion("spaces", load)
+	if got := cachedCompletion("spaces", load); len(got) != 2 || calls != 1 {
+		t.Fatalf("expected cached values without reloading, got %v after %d loads", got, calls)
+	}
+
+	// Expired cache falls back to stale values when cub fails
+	file := filepath.Join(completionCacheDir(), "spaces.json")
+	old := time.Now().Add(-2 * completionCacheTTL)
+	if err := os.Chtimes(file, old, old); err != nil {
+		t.Fatal(err)
+	}
+	got := cachedCompletion("spaces", func() ([]string, error) { return nil, errors.New("cub: not logged in") })
+	if len(got) != 2 {
+		t.Errorf("expected stale values on load failure, got %v", got)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// plannedChange is one mutation a command is about to make, shown to the
+// user before anything is written.
+type plannedChange struct {
+	Action  string // e.g. "disable auto-sync", "delete", "remove labels"
+	Target  string // e.g. "Application argocd/guestbook"
+	Detail  string // exactly what changes
+	Cluster bool   // writes to the cluster, so blocked in read-only mode
+}
+
+// confirmChanges prints the changes and asks once whether to make them.
+// yes (--yes) skips the prompt for automation. Cluster changes are refused
+// in read-only mode before anything is printed.
+func confirmChanges(changes []plannedChange, yes bool) (bool, error) {
+	return promptChanges(os.Stdout, os.Stdin, changes, yes)
+}
+
+func promptChanges(w io.Writer, r io.Reader, changes []plannedChange, yes bool) (bool, error) {
+	if err := checkChanges(changes); err != nil {
+		return false, err
+	}
+	printPlannedChanges(w, changes)
+	if yes {
+		return true, nil
+	}
+	fmt.Fprintf(w, "\nMake these %d change(s)? [y/N] ", len(changes))
+	if !readConfirm(r) {
+		fmt.Fprintln(w, "Cancelled.")
+		return false, nil
+	}
+	return true, nil
+}
+
+// checkChanges refuses the first cluster change in read-only mode. The TUI
+// calls it directly, since it confirms from its own views rather than a
+// prompt.
+func checkChanges(changes []plannedChange) error {
+	for _, c := range changes {
+		if !c.Cluster {
+			continue
+		}
+		if err := checkWrite(c.Action + " " + c.Target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printPlannedChanges lists changes one per line. Dry runs print the same
+// list so it matches what a real run would do.
+func printPlannedChanges(w io.Writer, changes []plannedChange) {
+	fmt.Fprintf(w, "\n%sChanges to be made:%s\n", colorBold, colorReset)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range changes {
+		where := "ConfigHub"
+		if c.Cluster {
+			where = "cluster"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s%s%s\n", where, c.Action, c.Target, colorDim, c.Detail, colorReset)
+	}
+	tw.Flush()
+}
+
+// readConfirm reads one line from r and reports whether it is y or yes.
+func readConfirm(r io.Reader) bool {
+	response, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && response == "" {
+		return false
+	}
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
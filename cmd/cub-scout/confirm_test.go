@@ -0,0 +1,64 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPromptChanges(t *testing.T) {
+	t.Cleanup(func() { readOnlyMode = false })
+
+	changes := []plannedChange{
+		{Action: "create unit", Target: "apps/guestbook", Detail: "3 resources"},
+		{Action: "disable auto-sync", Target: "Application argocd/guestbook", Detail: "spec.syncPolicy.automated removed", Cluster: true},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		yes   bool
+		want  bool
+	}{
+		{"yes flag skips prompt", "", true, true},
+		{"answer y", "y\n", false, true},
+		{"answer yes without newline", "YES", false, true},
+		{"answer n", "n\n", false, false},
+		{"no answer", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			ok, err := promptChanges(&out, strings.NewReader(tt.input), changes, tt.yes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.want {
+				t.Errorf("ok = %v, want %v", ok, tt.want)
+			}
+			for _, s := range []string{"ConfigHub", "apps/guestbook", "cluster", "spec.syncPolicy.automated removed"} {
+				if !strings.Contains(out.String(), s) {
+					t.Errorf("summary missing %q:\n%s", s, out.String())
+				}
+			}
+			if prompted := strings.Contains(out.String(), "[y/N]"); prompted == tt.yes {
+				t.Errorf("prompted = %v with yes = %v", prompted, tt.yes)
+			}
+		})
+	}
+
+	readOnlyMode = true
+	var out bytes.Buffer
+	if ok, err := promptChanges(&out, strings.NewReader("y\n"), changes, true); ok || err == nil {
+		t.Errorf("read-only: ok = %v, err = %v; want refusal", ok, err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("read-only: printed %q before refusing", out.String())
+	}
+	if ok, err := promptChanges(&out, strings.NewReader(""), changes[:1], true); !ok || err != nil {
+		t.Errorf("read-only, ConfigHub-only changes: ok = %v, err = %v", ok, err)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// nodeEventWindow is how far back node events are considered relevant to crash triage.
+const nodeEventWindow = time.Hour
+
+// nodeEventReasons are Node event reasons that indicate scheduling or health trouble.
+var nodeEventReasons = map[string]bool{
+	"NodeNotReady":           true,
+	"NodeNotSchedulable":     true,
+	"NodeHasDiskPressure":    true,
+	"NodeHasMemoryPressure":  true,
+	"NodeHasInsufficientPID": true,
+	"EvictionThresholdMet":   true,
+	"Rebooted":               true,
+	"NodeShutdown":           true,
+	"TaintManagerEviction":   true,
+}
+
+// NodeHealth summarizes scheduling-relevant state of a node.
+type NodeHealth struct {
+	Name     string   `json:"name"`
+	Ready    bool     `json:"ready"`
+	Cordoned bool     `json:"cordoned,omitempty"`
+	Pressure []string `json:"pressure,omitempty"` // MemoryPressure, DiskPressure, PIDPressure
+	Taints   []string `json:"taints,omitempty"`   // key=value:effect for NoSchedule/NoExecute taints
+	Events   []string `json:"recentEvents,omitempty"`
+	NotFound bool     `json:"notFound,omitempty"`
+}
+
+// Problems returns short descriptions of everything wrong with the node.
+func (n NodeHealth) Problems() []string {
+	var problems []string
+	if n.NotFound {
+		return []string{"node deleted"}
+	}
+	if !n.Ready {
+		problems = append(problems, "NotReady")
+	}
+	if n.Cordoned {
+		problems = append(problems, "cordoned")
+	}
+	problems = append(problems, n.Pressure...)
+	for _, t := range n.Taints {
+		problems = append(problems, "taint "+t)
+	}
+	return problems
+}
+
+// Healthy reports whether the node shows no problems and no recent warning events.
+func (n NodeHealth) Healthy() bool {
+	return len(n.Problems()) == 0 && len(n.Events) == 0
+}
+
+// summarizeNodeHealth extracts readiness, pressure conditions, cordon state and taints.
+// Taints that Kubernetes adds for NotReady/unreachable nodes are skipped since
+// they duplicate the Ready condition.
+func summarizeNodeHealth(node *unstructured.Unstructured) NodeHealth {
+	h := NodeHealth{Name: node.GetName()}
+
+	conditions, _, _ := unstructured.NestedSlice(node.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := cond["type"].(string)
+		status, _ := cond["status"].(string)
+		switch condType {
+		case "Ready":
+			h.Ready = status == "True"
+		case "MemoryPressure", "DiskPressure", "PIDPressure":
+			if status == "True" {
+				h.Pressure = append(h.Pressure, condType)
+			}
+		}
+	}
+
+	h.Cordoned, _, _ = unstructured.NestedBool(node.Object, "spec", "unschedulable")
+
+	taints, _, _ := unstructured.NestedSlice(node.Object, "spec", "taints")
+	for _, t := range taints {
+		taint, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _ := taint["key"].(string)
+		value, _ := taint["value"].(string)
+		effect, _ := taint["effect"].(string)
+		if effect == "PreferNoSchedule" {
+			continue
+		}
+		switch key {
+		case "node.kubernetes.io/not-ready", "node.kubernetes.io/unreachable", "node.kubernetes.io/unschedulable":
+			continue
+		}
+		s := key
+		if value != "" {
+			s += "=" + value
+		}
+		h.Taints = append(h.Taints, s+":"+effect)
+	}
+
+	return h
+}
+
+// recentNodeEvents groups recent troubling Node events by node name.
+func recentNodeEvents(events []unstructured.Unstructured, now time.Time) map[string][]string {
+	byNode := map[string][]string{}
+	for _, ev := range events {
+		kind, _, _ := unstructured.NestedString(ev.Object, "involvedObject", "kind")
+		if kind != "Node" {
+			continue
+		}
+		reason, _, _ := unstructured.NestedString(ev.Object, "reason")
+		if !nodeEventReasons[reason] {
+			continue
+		}
+		ts := eventTimestamp(&ev)
+		if ts.IsZero() || now.Sub(ts) > nodeEventWindow {
+			continue
+		}
+		node, _, _ := unstructured.NestedString(ev.Object, "involvedObject", "name")
+		byNode[node] = append(byNode[node], fmt.Sprintf("%s (%s ago)", reason, formatDuration(now.Sub(ts))))
+	}
+	for node := range byNode {
+		sort.Strings(byNode[node])
+	}
+	return byNode
+}
+
+// eventTimestamp returns the most recent timestamp recorded on an Event.
+func eventTimestamp(ev *unstructured.Unstructured) time.Time {
+	for _, field := range []string{"lastTimestamp", "eventTime", "firstTimestamp"} {
+		s, _, _ := unstructured.NestedString(ev.Object, field)
+		if s == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t
+		}
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t
+		}
+	}
+	return ev.GetCreationTimestamp().Time
+}
+
+// loadNodeHealth fetches nodes and recent node events. Failures (for example,
+// missing RBAC for nodes) return an empty map so crash triage still works.
+func loadNodeHealth(ctx context.Context, dynClient dynamic.Interface, now time.Time) map[string]NodeHealth {
+	result := map[string]NodeHealth{}
+
+	nodeList, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "nodes"}).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return result
+	}
+	for i := range nodeList.Items {
+		h := summarizeNodeHealth(&nodeList.Items[i])
+		result[h.Name] = h
+	}
+
+	eventList, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "events"}).List(ctx, v1.ListOptions{
+		FieldSelector: "involvedObject.kind=Node",
+	})
+	if err == nil {
+		for node, events := range recentNodeEvents(eventList.Items, now) {
+			h, ok := result[node]
+			if !ok {
+				h = NodeHealth{Name: node, NotFound: true}
+			}
+			h.Events = events
+			result[node] = h
+		}
+	}
+
+	return result
+}
+
+// nodeStatusLabel renders a compact node status for the crashes table.
+func nodeStatusLabel(h NodeHealth) string {
+	problems := h.Problems()
+	if len(problems) == 0 {
+		return "ok"
+	}
+	return strings.Join(problems, ",")
+}
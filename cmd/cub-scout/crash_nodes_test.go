@@ -0,0 +1,95 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSummarizeNodeHealth(t *testing.T) {
+	node := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Node",
+		"metadata": map[string]interface{}{
+			"name": "worker-1",
+		},
+		"spec": map[string]interface{}{
+			"unschedulable": true,
+			"taints": []interface{}{
+				map[string]interface{}{"key": "node.kubernetes.io/unschedulable", "effect": "NoSchedule"},
+				map[string]interface{}{"key": "dedicated", "value": "gpu", "effect": "NoSchedule"},
+				map[string]interface{}{"key": "soft", "effect": "PreferNoSchedule"},
+			},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False"},
+				map[string]interface{}{"type": "MemoryPressure", "status": "True"},
+				map[string]interface{}{"type": "DiskPressure", "status": "False"},
+			},
+		},
+	}}
+
+	h := summarizeNodeHealth(node)
+	want := []string{"NotReady", "cordoned", "MemoryPressure", "taint dedicated=gpu:NoSchedule"}
+	if got := h.Problems(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Problems() = %v, want %v", got, want)
+	}
+	if h.Healthy() {
+		t.Error("expected unhealthy node")
+	}
+}
+
+func TestSummarizeNodeHealthReady(t *testing.T) {
+	node := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "worker-2"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}}
+	h := summarizeNodeHealth(node)
+	if !h.Healthy() || nodeStatusLabel(h) != "ok" {
+		t.Errorf("expected healthy node, got %+v", h)
+	}
+}
+
+func TestRecentNodeEvents(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	event := func(node, reason string, at time.Time) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":           "Event",
+			"reason":         reason,
+			"lastTimestamp":  at.Format(time.RFC3339),
+			"involvedObject": map[string]interface{}{"kind": "Node", "name": node},
+		}}
+	}
+
+	events := []unstructured.Unstructured{
+		event("worker-1", "NodeNotReady", now.Add(-10*time.Minute)),
+		event("worker-1", "NodeNotSchedulable", now.Add(-5*time.Minute)),
+		event("worker-1", "Starting", now.Add(-5*time.Minute)),   // not a trouble reason
+		event("worker-2", "NodeNotReady", now.Add(-3*time.Hour)), // too old
+	}
+
+	byNode := recentNodeEvents(events, now)
+	if len(byNode) != 1 {
+		t.Fatalf("expected events for 1 node, got %v", byNode)
+	}
+	want := []string{"NodeNotReady (10m ago)", "NodeNotSchedulable (5m ago)"}
+	if !reflect.DeepEqual(byNode["worker-1"], want) {
+		t.Errorf("worker-1 events = %v, want %v", byNode["worker-1"], want)
+	}
+}
+
+func TestNodeHealthNotFound(t *testing.T) {
+	h := NodeHealth{Name: "gone", NotFound: true}
+	if got := nodeStatusLabel(h); got != "node deleted" {
+		t.Errorf("nodeStatusLabel = %q, want %q", got, "node deleted")
+	}
+}
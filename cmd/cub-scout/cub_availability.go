@@ -0,0 +1,50 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+)
+
+// cub-scout works without cub: commands that only read the cluster never
+// need it. Commands and views that read ConfigHub check for cub once per
+// process and, when it is missing, say what is unavailable and keep
+// showing cluster data where they have any.
+
+// cubInstallURL is where the cub CLI is installed from.
+const cubInstallURL = "https://docs.confighub.com/cli"
+
+// errCubMissing is the error of every cub call when cub is not installed.
+var errCubMissing = errors.New("cub CLI not found")
+
+// cubLookPath finds the cub CLI. Tests replace it.
+var cubLookPath = func() error {
+	_, err := exec.LookPath("cub")
+	return err
+}
+
+var (
+	cubLookupOnce sync.Once
+	cubFound      bool
+)
+
+// cubInstalled reports whether cub is on the PATH, looked up once.
+func cubInstalled() bool {
+	cubLookupOnce.Do(func() { cubFound = cubLookPath() == nil })
+	return cubFound
+}
+
+// cubMissingError explains that what, a command or view, needs cub and that
+// cub is not installed. It wraps errCubMissing.
+func cubMissingError(what string) error {
+	return fmt.Errorf("%w: %s needs ConfigHub, read through the cub CLI. Install it from %s; cluster-only commands (map, scan, trace, snapshot) work without it", errCubMissing, what, cubInstallURL)
+}
+
+// cubMissingNote is cubMissingError for a status line.
+func cubMissingNote(what string) string {
+	return fmt.Sprintf("cub CLI not found: %s needs ConfigHub (install from %s)", what, cubInstallURL)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// withoutCub makes cub look uninstalled until the test ends.
+func withoutCub(t *testing.T) {
+	t.Helper()
+	prev := cubLookPath
+	cubLookPath = func() error { return errors.New("exec: \"cub\": executable file not found in $PATH") }
+	cubLookupOnce = sync.Once{}
+	t.Cleanup(func() {
+		cubLookPath = prev
+		cubLookupOnce = sync.Once{}
+	})
+}
+
+// withCub makes cub look installed until the test ends, whether or not it
+// is on the PATH. Commands fail as unknown to an empty fakeCub, so nothing
+// reaches a real ConfigHub.
+func withCub(t *testing.T) {
+	t.Helper()
+	prev := cubLookPath
+	cubLookPath = func() error { return nil }
+	cubLookupOnce = sync.Once{}
+	fakeCub{}.install(t)
+	t.Cleanup(func() {
+		cubLookPath = prev
+		cubLookupOnce = sync.Once{}
+	})
+}
+
+func TestCubMissing(t *testing.T) {
+	withoutCub(t)
+	cubCache.Purge()
+
+	if _, err := runCubCommand("space", "list", "--json"); !errors.Is(err, errCubMissing) {
+		t.Errorf("cub call err = %v, want errCubMissing", err)
+	}
+	err := checkCubAuth("cub-scout functions list")
+	if !errors.Is(err, errCubMissing) || !strings.Contains(err.Error(), "cub-scout functions list needs ConfigHub") ||
+		strings.Contains(err.Error(), "cub auth login") {
+		t.Errorf("checkCubAuth = %v, want it to name the command and not ask for a login", err)
+	}
+	if _, err := runHierarchyLoopWithContext("", nil); !errors.Is(err, errCubMissing) {
+		t.Errorf("hub TUI err = %v, want errCubMissing", err)
+	}
+}
+
+func TestLocalTUIWithoutCub(t *testing.T) {
+	withoutCub(t)
+	m := LocalClusterModel{
+		entries:     []MapEntry{{Name: "nginx", Namespace: "default", Kind: "Deployment", Owner: "Flux", Status: "Ready"}},
+		width:       80,
+		height:      24,
+		ready:       true,
+		view:        viewDashboard,
+		keymap:      defaultLocalKeyMap(),
+		clusterName: "test-cluster",
+	}
+
+	// H explains there is no hub instead of offering a login
+	next, _ := m.Update(checkCubAuthForSwitch())
+	m = next.(LocalClusterModel)
+	if m.authNeeded || m.switchToHub || !strings.Contains(m.statusMsg, "ConfigHub mode needs ConfigHub") {
+		t.Errorf("switch without cub: authNeeded=%v switchToHub=%v status=%q", m.authNeeded, m.switchToHub, m.statusMsg)
+	}
+
+	// I stays in the cluster view
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("I")})
+	m = next.(LocalClusterModel)
+	if m.switchToImport || !strings.Contains(m.statusMsg, "import needs ConfigHub") {
+		t.Errorf("import without cub: switchToImport=%v status=%q", m.switchToImport, m.statusMsg)
+	}
+	if !strings.Contains(m.View(), "import needs ConfigHub") {
+		t.Error("the dashboard should still render, with the note")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/confighub/cub-scout/internal/hierarchysvc"
+)
+
+// ConfigHub reads (cub list, get and tree commands) are cached for cubCacheTTL
+// (CUB_SCOUT_CUB_CACHE_TTL overrides it; 0 disables the cache), and at most
+// cubReadRate uncached reads start per second. Any other cub command may
+// change what the reads return, so it empties the cache.
+const (
+	cubCacheTTL  = 15 * time.Second
+	cubReadRate  = 20
+	cubReadBurst = 20
+)
+
+var cubCache = hierarchysvc.NewCache(cubCacheTTLFromEnv(), cubReadRate, cubReadBurst)
+
+// cubCacheTTLFromEnv returns the cache TTL from CUB_SCOUT_CUB_CACHE_TTL, a
+// duration such as 30s, or cubCacheTTL.
+func cubCacheTTLFromEnv() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("CUB_SCOUT_CUB_CACHE_TTL")); err == nil && ttl >= 0 {
+		return ttl
+	}
+	return cubCacheTTL
+}
+
+// isCubRead reports whether args only read ConfigHub: a list, get, tree or
+// livedata, e.g. "unit list", optionally for another context
+// ("--context x space list"), or cub --version.
+func isCubRead(args []string) bool {
+	if len(args) == 1 && args[0] == "--version" {
+		return true
+	}
+	if len(args) >= 2 && args[0] == "--context" {
+		args = args[2:]
+	}
+	words := strings.Fields(cubSubcommand(args))
+	if len(words) != 2 {
+		return false
+	}
+	switch words[1] {
+	case "list", "get", "tree", "livedata":
+		return true
+	}
+	return false
+}
+
+// cubCacheKey keys a command line; args are joined with a separator cub
+// arguments can't contain.
+func cubCacheKey(args []string) string {
+	return strings.Join(args, "\x00")
+}
+
+// invalidateCubCache empties the cache when msg reports a ConfigHub change
+// made outside runCubCommand, so the reload that follows refetches.
+func invalidateCubCache(msg tea.Msg) {
+	switch msg.(type) {
+	case createResourceMsg, deleteResourceMsg, unitAppliedMsg, cmdCompleteMsg,
+		importCompleteMsg, spaceCreatedMsg, workerCreatedMsg, testUpdateCompleteMsg,
+		orgLoginDoneMsg:
+		cubCache.Purge()
+	}
+}
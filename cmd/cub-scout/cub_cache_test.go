@@ -0,0 +1,78 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunCubCommandCachesReads(t *testing.T) {
+	var calls []string
+	prev := cubExec
+	cubExec = func(args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		return []byte(`[]`), nil
+	}
+	cubCache.Purge()
+	t.Cleanup(func() {
+		cubExec = prev
+		cubCache.Purge()
+	})
+
+	for i := 0; i < 3; i++ {
+		_, _ = runCubCommand("unit", "list", "--space", "prod", "--json")
+	}
+	_, _ = runCubCommand("unit", "list", "--space", "staging", "--json")
+	if len(calls) != 2 {
+		t.Fatalf("calls = %v, want each read run once", calls)
+	}
+
+	// A write runs every time and drops cached reads
+	_, _ = runCubCommand("unit", "delete", "api", "--space", "prod")
+	_, _ = runCubCommand("unit", "list", "--space", "prod", "--json")
+	if len(calls) != 4 || calls[3] != "unit list --space prod --json" {
+		t.Errorf("calls = %v, want the read rerun after a write", calls)
+	}
+}
+
+func TestRefreshCubCommandRefetches(t *testing.T) {
+	var calls int
+	prev := cubExec
+	cubExec = func(args ...string) ([]byte, error) {
+		calls++
+		return []byte(`{}`), nil
+	}
+	cubCache.Purge()
+	t.Cleanup(func() {
+		cubExec = prev
+		cubCache.Purge()
+	})
+
+	_, _ = runCubCommand("unit", "get", "api", "--space", "prod", "--json")
+	_, _ = refreshCubCommand("unit", "get", "api", "--space", "prod", "--json")
+	_, _ = runCubCommand("unit", "get", "api", "--space", "prod", "--json")
+	if calls != 2 {
+		t.Errorf("calls = %d, want the refresh to fetch and its output cached", calls)
+	}
+}
+
+func TestIsCubRead(t *testing.T) {
+	for args, want := range map[string]bool{
+		"unit list --space prod --json":          true,
+		"unit get --space prod --json api":       true,
+		"unit livedata api --space prod":         true,
+		"unit tree --space prod --edge clone":    true,
+		"--context other space list --json":      true,
+		"context use other":                      false,
+		"unit delete api --space prod":           false,
+		"--context other unit apply api --space": false,
+		"--version":                              true,
+		"version":                                false,
+	} {
+		if got := isCubRead(strings.Fields(args)); got != want {
+			t.Errorf("isCubRead(%s) = %v, want %v", args, got, want)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	debugNamespace string
+	debugJSON      bool
+	debugNoPause   bool
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug <kind/name>",
+	Short: "Walk a failing resource through a guided GitOps decision tree",
+	Long: `Debug a resource one GitOps layer at a time, the way an experienced
+operator would, and learn what each layer does along the way:
+
+  1. Resource   Does the object exist?
+  2. Owner      Which deployer manages it?
+  3. Source     Did the controller fetch the Git repo / OCI artifact?
+  4. Artifact   Did the manifests build (kustomize build, helm template)?
+  5. Apply      Did the controller apply them to the cluster?
+  6. Health     Is the running workload healthy?
+
+Each step runs the relevant checks and stops at the first broken layer,
+explaining what that layer is responsible for and what to run next.
+
+In a terminal the walk pauses between steps; use --no-pause to print
+everything at once.
+
+Examples:
+  cub-scout debug deploy/api -n prod
+  cub-scout debug deploy/api -n prod --no-pause
+  cub-scout debug deploy/api -n prod --json`,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE:              runDebug,
+	ValidArgsFunction: completeResourceArg,
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+	debugCmd.Flags().StringVarP(&debugNamespace, "namespace", "n", "default", "Namespace of the resource")
+	debugCmd.Flags().BoolVar(&debugJSON, "json", false, "Output as JSON")
+	debugCmd.Flags().BoolVar(&debugNoPause, "no-pause", false, "Do not wait for Enter between steps")
+	_ = debugCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+}
+
+// Debug step statuses
+const (
+	debugPass = "pass"
+	debugWarn = "warn"
+	debugFail = "fail"
+	debugSkip = "skip"
+)
+
+// DebugStep is one layer of the debug decision tree.
+type DebugStep struct {
+	Layer    string `json:"layer"`
+	Question string `json:"question"`
+	Status   string `json:"status"` // pass, warn, fail, skip
+	Finding  string `json:"finding"`
+	Lesson   string `json:"lesson"`
+	Next     string `json:"next,omitempty"`
+}
+
+// debugInputs is everything the decision tree inspects, fetched up front.
+type debugInputs struct {
+	Ref       agent.ResourceRef
+	Resource  *unstructured.Unstructured
+	Ownership agent.Ownership
+	Deployer  *unstructured.Unstructured // Kustomization, HelmRelease or Application
+	Source    *unstructured.Unstructured // GitRepository, OCIRepository, HelmRepository, ...
+	SourceRef string                     // Kind/name the deployer points at
+	Warnings  []TimelineEntry            // warning events and restarts, newest first
+}
+
+func runDebug(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	kind, name, err := parseResourceArgs(args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	in := collectDebugInputs(ctx, dynClient, kind, name, debugNamespace)
+	steps := debugDecisionTree(in)
+
+	if debugJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(steps)
+	}
+
+	pause := !debugNoPause && stdinIsTerminal()
+	printDebugSteps(in.Ref, steps, pause)
+	return nil
+}
+
+// collectDebugInputs fetches the resource, its deployer, the deployer's source
+// and recent warnings. Missing objects are left nil for the tree to report.
+func collectDebugInputs(ctx context.Context, dynClient dynamic.Interface, kind, name, namespace string) debugInputs {
+	in := debugInputs{Ref: agent.ResourceRef{Kind: kind, Name: name, Namespace: namespace}}
+
+	gvr := kindToGVR(kind)
+	if gvr.Resource == "" {
+		return in
+	}
+	obj, err := dynClient.Resource(gvr).Namespace(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return in
+	}
+	in.Resource = obj
+	in.Ownership = agent.DetectOwnership(obj)
+
+	get := func(gvr schema.GroupVersionResource, ns, name string) *unstructured.Unstructured {
+		o, err := dynClient.Resource(gvr).Namespace(ns).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		return o
+	}
+
+	ownerNs := in.Ownership.Namespace
+	switch in.Ownership.Type {
+	case agent.OwnerFlux:
+		if ownerNs == "" {
+			ownerNs = "flux-system"
+		}
+		flux := agent.NewFluxResolver(agent.ClientFluxLookup(dynClient))
+		deployer := agent.FluxObjectRef{Kind: "Kustomization", Name: in.Ownership.Name, Namespace: ownerNs}
+		if in.Ownership.SubType == "helmrelease" {
+			deployer.Kind = "HelmRelease"
+		}
+		in.Deployer, _ = flux.Get(ctx, deployer)
+		if in.Deployer != nil {
+			if ref, err := agent.FluxSourceRef(in.Deployer); err == nil {
+				in.SourceRef = ref.Kind + "/" + ref.Name
+				in.Source, _ = flux.Get(ctx, ref)
+			}
+		}
+	case agent.OwnerArgo:
+		if ownerNs == "" {
+			ownerNs = "argocd"
+		}
+		in.Deployer = get(schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}, ownerNs, in.Ownership.Name)
+	}
+
+	related := map[string]bool{kind + "/" + name: true}
+	pods := relatedPods(ctx, dynClient, obj, related)
+	in.Warnings = append(in.Warnings, timelineFromPods(pods)...)
+	if eventList, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "events"}).Namespace(namespace).List(ctx, v1.ListOptions{}); err == nil {
+		for _, e := range timelineFromEvents(eventList.Items, related) {
+			if e.Warning {
+				in.Warnings = append(in.Warnings, e)
+			}
+		}
+	}
+	sort.SliceStable(in.Warnings, func(i, j int) bool { return in.Warnings[i].Time.After(in.Warnings[j].Time) })
+	return in
+}
+
+// Lessons explain what each layer is responsible for.
+const (
+	lessonResource = "Everything starts with the object in the API server. If it is missing, either it was never applied or it was pruned."
+	lessonOwner    = "GitOps controllers label what they apply. The owner tells you which controller to ask when the object is wrong."
+	lessonSource   = "The source controller fetches Git or OCI on an interval. If the fetch fails, nothing downstream can change: check the URL, credentials Secret and network."
+	lessonArtifact = "Manifests are rendered from the fetched source (kustomize build, helm template). Failures here are mistakes in the repository: bad YAML, missing files or values."
+	lessonApply    = "The controller applies the rendered manifests to the cluster. Failures here come from the API server: admission webhooks, RBAC, immutable fields, missing CRDs."
+	lessonHealth   = "The configuration reached the cluster; what remains is runtime: image pulls, crashes, probes, resource limits and scheduling."
+)
+
+// debugDecisionTree walks the layers in order and stops at the first failure.
+func debugDecisionTree(in debugInputs) []DebugStep {
+	ref := in.Ref.Kind + "/" + in.Ref.Name
+	var steps []DebugStep
+	add := func(s DebugStep) bool {
+		steps = append(steps, s)
+		return s.Status != debugFail
+	}
+
+	// 1. Resource
+	if in.Resource == nil {
+		add(DebugStep{Layer: "Resource", Question: "Does the object exist?", Status: debugFail,
+			Finding: fmt.Sprintf("%s not found in namespace %s", ref, in.Ref.Namespace),
+			Lesson:  lessonResource,
+			Next:    "cub-scout map list -n " + in.Ref.Namespace})
+		return steps
+	}
+	add(DebugStep{Layer: "Resource", Question: "Does the object exist?", Status: debugPass,
+		Finding: fmt.Sprintf("%s exists in %s", ref, in.Ref.Namespace), Lesson: lessonResource})
+
+	// 2. Owner
+	owner := displayOwner(in.Ownership.Type)
+	gitops := in.Ownership.Type == agent.OwnerFlux || in.Ownership.Type == agent.OwnerArgo
+	switch {
+	case gitops && in.Deployer == nil:
+		add(DebugStep{Layer: "Owner", Question: "Which deployer manages it?", Status: debugFail,
+			Finding: fmt.Sprintf("labels point at %s %s, but that object does not exist", owner, in.Ownership.Name),
+			Lesson:  lessonOwner + " A missing deployer leaves the object orphaned: nothing will reconcile it.",
+			Next:    "cub-scout map deployers"})
+		return steps
+	case gitops:
+		add(DebugStep{Layer: "Owner", Question: "Which deployer manages it?", Status: debugPass,
+			Finding: fmt.Sprintf("%s %s/%s", owner, in.Deployer.GetKind(), in.Deployer.GetName()), Lesson: lessonOwner})
+	default:
+		finding := "no GitOps owner (Native): nothing reconciles this object"
+		if in.Ownership.Type == agent.OwnerHelm || in.Ownership.Type == agent.OwnerConfigHub {
+			finding = fmt.Sprintf("%s %s (no in-cluster source or artifact to check)", owner, in.Ownership.Name)
+		}
+		add(DebugStep{Layer: "Owner", Question: "Which deployer manages it?", Status: debugWarn,
+			Finding: finding, Lesson: lessonOwner})
+		for _, layer := range []string{"Source", "Artifact", "Apply"} {
+			add(DebugStep{Layer: layer, Status: debugSkip, Finding: "not applicable for " + owner})
+		}
+		add(debugHealthStep(in, ref))
+		return steps
+	}
+
+	// 3-5. Source, Artifact, Apply
+	var more bool
+	if in.Ownership.Type == agent.OwnerArgo {
+		more = debugArgoSteps(in, add)
+	} else {
+		more = debugFluxSteps(in, add)
+	}
+	if !more {
+		return steps
+	}
+
+	// 6. Health
+	add(debugHealthStep(in, ref))
+	return steps
+}
+
+func debugFluxSteps(in debugInputs, add func(DebugStep) bool) bool {
+	deployer := in.Deployer.GetKind() + "/" + in.Deployer.GetName()
+	fluxCmd := "flux get sources all -A"
+
+	// Source
+	src := in.Source
+	switch {
+	case src == nil:
+		return add(DebugStep{Layer: "Source", Question: "Did the controller fetch the source?", Status: debugFail,
+			Finding: fmt.Sprintf("%s references %s, which does not exist", deployer, in.SourceRef),
+			Lesson:  lessonSource, Next: fluxCmd})
+	case !isResourceReady(src):
+		return add(DebugStep{Layer: "Source", Question: "Did the controller fetch the source?", Status: debugFail,
+			Finding: fmt.Sprintf("%s is not ready: %s", in.SourceRef, getConditionMessage(src)),
+			Lesson:  lessonSource, Next: fluxCmd})
+	}
+	url, _, _ := unstructured.NestedString(src.Object, "spec", "url")
+	finding := fmt.Sprintf("%s fetched %s", in.SourceRef, url)
+	lesson := lessonSource
+	if oci := agent.ParseOCISource(url); oci.IsConfigHub {
+		finding = fmt.Sprintf("%s fetched ConfigHub OCI %s/%s", in.SourceRef, oci.Space, oci.Target)
+		lesson += " This source is published by ConfigHub: fix configuration in ConfigHub, not Git."
+	}
+	if !add(DebugStep{Layer: "Source", Question: "Did the controller fetch the source?", Status: debugPass,
+		Finding: finding, Lesson: lesson}) {
+		return false
+	}
+
+	// Artifact
+	artifactRev, _, _ := unstructured.NestedString(src.Object, "status", "artifact", "revision")
+	reason := readyReason(in.Deployer)
+	switch {
+	case artifactRev == "":
+		return add(DebugStep{Layer: "Artifact", Question: "Did the manifests build?", Status: debugFail,
+			Finding: in.SourceRef + " has no artifact yet", Lesson: lessonArtifact, Next: fluxCmd})
+	case reason == "BuildFailed" || reason == "ArtifactFailed" || reason == "ChartPullFailed":
+		return add(DebugStep{Layer: "Artifact", Question: "Did the manifests build?", Status: debugFail,
+			Finding: fmt.Sprintf("%s: %s", deployer, getConditionMessage(in.Deployer)),
+			Lesson:  lessonArtifact, Next: "flux build kustomization " + in.Deployer.GetName()})
+	}
+	if !add(DebugStep{Layer: "Artifact", Question: "Did the manifests build?", Status: debugPass,
+		Finding: "artifact " + shortRevision(artifactRev), Lesson: lessonArtifact}) {
+		return false
+	}
+
+	// Apply
+	applied, _, _ := unstructured.NestedString(in.Deployer.Object, "status", "lastAppliedRevision")
+	suspended, _, _ := unstructured.NestedBool(in.Deployer.Object, "spec", "suspend")
+	switch {
+	case suspended:
+		return add(DebugStep{Layer: "Apply", Question: "Did the controller apply it?", Status: debugFail,
+			Finding: deployer + " is suspended: changes are not being applied",
+			Lesson:  lessonApply, Next: "flux resume " + strings.ToLower(in.Deployer.GetKind()) + " " + in.Deployer.GetName() + " -n " + in.Deployer.GetNamespace()})
+	case !isResourceReady(in.Deployer) && reason != "HealthCheckFailed":
+		return add(DebugStep{Layer: "Apply", Question: "Did the controller apply it?", Status: debugFail,
+			Finding: fmt.Sprintf("%s: %s", deployer, getConditionMessage(in.Deployer)),
+			Lesson:  lessonApply, Next: "cub-scout trace " + in.Ref.Kind + "/" + in.Ref.Name + " -n " + in.Ref.Namespace})
+	case applied != "" && applied != artifactRev:
+		return add(DebugStep{Layer: "Apply", Question: "Did the controller apply it?", Status: debugWarn,
+			Finding: fmt.Sprintf("%s applied %s, source is at %s", deployer, shortRevision(applied), shortRevision(artifactRev)),
+			Lesson:  lessonApply})
+	}
+	return add(DebugStep{Layer: "Apply", Question: "Did the controller apply it?", Status: debugPass,
+		Finding: deployer + " applied " + shortRevision(applied), Lesson: lessonApply})
+}
+
+func debugArgoSteps(in debugInputs, add func(DebugStep) bool) bool {
+	app := in.Deployer
+	appRef := "Application/" + app.GetName()
+	repoURL, _, _ := unstructured.NestedString(app.Object, "spec", "source", "repoURL")
+
+	// Argo reports fetch and render problems as ComparisonError conditions
+	var comparisonErr string
+	conditions, _, _ := unstructured.NestedSlice(app.Object, "status", "conditions")
+	for _, c := range conditions {
+		if cond, ok := c.(map[string]interface{}); ok && cond["type"] == "ComparisonError" {
+			comparisonErr, _ = cond["message"].(string)
+		}
+	}
+	fetchFailed := comparisonErr != "" && (strings.Contains(comparisonErr, "repository") ||
+		strings.Contains(comparisonErr, "authentication") || strings.Contains(comparisonErr, "not found"))
+
+	// Source
+	if fetchFailed {
+		return add(DebugStep{Layer: "Source", Question: "Did Argo fetch the source?", Status: debugFail,
+			Finding: comparisonErr, Lesson: lessonSource, Next: "argocd repo list"})
+	}
+	finding := appRef + " fetched " + repoURL
+	lesson := lessonSource
+	if oci := agent.ParseOCISource(repoURL); oci.IsConfigHub {
+		finding = fmt.Sprintf("%s fetched ConfigHub OCI %s/%s", appRef, oci.Space, oci.Target)
+		lesson += " This source is published by ConfigHub: fix configuration in ConfigHub, not Git."
+	}
+	add(DebugStep{Layer: "Source", Question: "Did Argo fetch the source?", Status: debugPass, Finding: finding, Lesson: lesson})
+
+	// Artifact
+	syncStatus, _, _ := unstructured.NestedString(app.Object, "status", "sync", "status")
+	if comparisonErr != "" || syncStatus == "Unknown" {
+		msg := comparisonErr
+		if msg == "" {
+			msg = "sync status Unknown: Argo could not render manifests"
+		}
+		return add(DebugStep{Layer: "Artifact", Question: "Did the manifests build?", Status: debugFail,
+			Finding: msg, Lesson: lessonArtifact, Next: "argocd app manifests " + app.GetName()})
+	}
+	add(DebugStep{Layer: "Artifact", Question: "Did the manifests build?", Status: debugPass,
+		Finding: "manifests rendered (sync status " + syncStatus + ")", Lesson: lessonArtifact})
+
+	// Apply
+	phase, _, _ := unstructured.NestedString(app.Object, "status", "operationState", "phase")
+	opMessage, _, _ := unstructured.NestedString(app.Object, "status", "operationState", "message")
+	switch {
+	case phase == "Failed" || phase == "Error":
+		return add(DebugStep{Layer: "Apply", Question: "Did Argo apply it?", Status: debugFail,
+			Finding: "last sync " + phase + ": " + opMessage, Lesson: lessonApply, Next: "argocd app get " + app.GetName()})
+	case syncStatus == "OutOfSync":
+		return add(DebugStep{Layer: "Apply", Question: "Did Argo apply it?", Status: debugWarn,
+			Finding: appRef + " is OutOfSync: the cluster differs from the source (auto-sync off, or a sync is pending)",
+			Lesson:  lessonApply, Next: "argocd app diff " + app.GetName()})
+	}
+	return add(DebugStep{Layer: "Apply", Question: "Did Argo apply it?", Status: debugPass,
+		Finding: appRef + " is Synced", Lesson: lessonApply})
+}
+
+func debugHealthStep(in debugInputs, ref string) DebugStep {
+	step := DebugStep{Layer: "Health", Question: "Is it healthy?", Lesson: lessonHealth}
+	status := detectStatus(in.Resource)
+	if status == "Ready" || status == "Running" || status == "Active" {
+		step.Status = debugPass
+		step.Finding = ref + " is " + status
+		if len(in.Warnings) > 0 {
+			step.Status = debugWarn
+			step.Finding += "; recent warning: " + in.Warnings[0].Message
+		}
+		return step
+	}
+
+	step.Status = debugFail
+	step.Finding = ref + " is " + status
+	var reasons []string
+	for i, w := range in.Warnings {
+		if i == 3 {
+			break
+		}
+		reasons = append(reasons, w.Object+": "+w.Message)
+	}
+	if len(reasons) > 0 {
+		step.Finding += " — " + strings.Join(reasons, "; ")
+	}
+	step.Next = "cub-scout timeline " + ref + " -n " + in.Ref.Namespace
+	return step
+}
+
+// readyReason returns the reason of the Ready condition.
+func readyReason(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		if cond, ok := c.(map[string]interface{}); ok && cond["type"] == "Ready" {
+			reason, _ := cond["reason"].(string)
+			return reason
+		}
+	}
+	return ""
+}
+
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func printDebugSteps(ref agent.ResourceRef, steps []DebugStep, pause bool) {
+	fmt.Printf("%s%sDEBUG:%s %s%s%s\n\n", colorBold, colorCyan, colorReset, colorBold, ref.String(), colorReset)
+
+	reader := bufio.NewReader(os.Stdin)
+	broken := ""
+	for i, s := range steps {
+		icon, color := "✓", colorGreen
+		switch s.Status {
+		case debugWarn:
+			icon, color = "⚠", colorYellow
+		case debugFail:
+			icon, color = "✗", colorRed
+			broken = s.Layer
+		case debugSkip:
+			icon, color = "-", colorDim
+		}
+
+		if s.Status == debugSkip {
+			fmt.Printf("%s%d. %s: skipped (%s)%s\n\n", colorDim, i+1, s.Layer, s.Finding, colorReset)
+			continue
+		}
+		fmt.Printf("%s%d. %s%s — %s\n", colorBold, i+1, s.Layer, colorReset, s.Question)
+		fmt.Printf("   %s%s%s %s\n", color, icon, colorReset, s.Finding)
+		fmt.Printf("   %s%s%s\n", colorDim, s.Lesson, colorReset)
+		if s.Next != "" {
+			fmt.Printf("   → %s\n", s.Next)
+		}
+		fmt.Println()
+
+		if pause && s.Status != debugFail && i < len(steps)-1 {
+			fmt.Printf("%sPress Enter to check the next layer...%s", colorDim, colorReset)
+			_, _ = reader.ReadString('\n')
+			fmt.Println()
+		}
+	}
+
+	if broken != "" {
+		fmt.Printf("%s%sBroken layer: %s%s\n", colorBold, colorRed, broken, colorReset)
+	} else {
+		fmt.Printf("%s%sNo broken layer found.%s Warnings above may still explain the symptom.\n", colorBold, colorGreen, colorReset)
+	}
+}
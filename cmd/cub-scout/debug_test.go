@@ -0,0 +1,123 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+func debugTestInputs(source, kustomization map[string]interface{}) debugInputs {
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "Deployment",
+		"metadata": map[string]interface{}{"name": "api", "namespace": "prod"},
+		"spec":     map[string]interface{}{"replicas": int64(2)},
+		"status":   map[string]interface{}{"replicas": int64(2), "readyReplicas": int64(2)},
+	}}
+	in := debugInputs{
+		Ref:       agent.ResourceRef{Kind: "Deployment", Name: "api", Namespace: "prod"},
+		Resource:  deploy,
+		Ownership: agent.Ownership{Type: agent.OwnerFlux, SubType: "kustomization", Name: "apps"},
+		SourceRef: "OCIRepository/hub",
+	}
+	if kustomization != nil {
+		ks := delegatedTestObject("Kustomization", "apps", map[string]interface{}{}, kustomization)
+		in.Deployer = &ks
+	}
+	if source != nil {
+		src := delegatedTestObject("OCIRepository", "hub",
+			map[string]interface{}{"url": "oci://oci.api.confighub.com/target/prod/us-west"}, source)
+		in.Source = &src
+	}
+	return in
+}
+
+func lastStep(steps []DebugStep) DebugStep {
+	return steps[len(steps)-1]
+}
+
+func TestDebugDecisionTreeHealthy(t *testing.T) {
+	in := debugTestInputs(
+		readyStatus(map[string]interface{}{"artifact": map[string]interface{}{"revision": "sha256:abc"}}),
+		readyStatus(map[string]interface{}{"lastAppliedRevision": "sha256:abc"}),
+	)
+	steps := debugDecisionTree(in)
+	if len(steps) != 6 {
+		t.Fatalf("expected 6 steps, got %d: %+v", len(steps), steps)
+	}
+	for _, s := range steps {
+		if s.Status != debugPass {
+			t.Errorf("%s: status %s (%s)", s.Layer, s.Status, s.Finding)
+		}
+	}
+	if !strings.Contains(steps[2].Finding, "ConfigHub OCI prod/us-west") {
+		t.Errorf("source finding should name ConfigHub target: %q", steps[2].Finding)
+	}
+}
+
+func TestDebugDecisionTreeStopsAtBrokenLayer(t *testing.T) {
+	notReady := map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "False", "reason": "BuildFailed", "message": "kustomization.yaml not found"},
+		},
+	}
+	fetchFailed := map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "False", "message": "failed to pull artifact: unauthorized"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		in     debugInputs
+		layer  string
+		status string
+	}{
+		{"missing resource", debugInputs{Ref: agent.ResourceRef{Kind: "Deployment", Name: "api"}}, "Resource", debugFail},
+		{"missing deployer", debugTestInputs(nil, nil), "Owner", debugFail},
+		{"source fetch failed", debugTestInputs(fetchFailed, readyStatus(nil)), "Source", debugFail},
+		{"build failed", debugTestInputs(
+			readyStatus(map[string]interface{}{"artifact": map[string]interface{}{"revision": "sha256:abc"}}),
+			notReady), "Artifact", debugFail},
+		{"suspended", func() debugInputs {
+			in := debugTestInputs(
+				readyStatus(map[string]interface{}{"artifact": map[string]interface{}{"revision": "sha256:abc"}}),
+				readyStatus(nil))
+			in.Deployer.Object["spec"] = map[string]interface{}{"suspend": true}
+			return in
+		}(), "Apply", debugFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last := lastStep(debugDecisionTree(tt.in))
+			if last.Layer != tt.layer || last.Status != tt.status {
+				t.Errorf("stopped at %s/%s, want %s/%s (%s)", last.Layer, last.Status, tt.layer, tt.status, last.Finding)
+			}
+			if last.Lesson == "" {
+				t.Error("expected a lesson for the broken layer")
+			}
+		})
+	}
+}
+
+func TestDebugDecisionTreeNativeSkipsGitOpsLayers(t *testing.T) {
+	in := debugTestInputs(nil, nil)
+	in.Ownership = agent.Ownership{Type: agent.OwnerUnknown}
+	in.Warnings = []TimelineEntry{{Object: "Pod/api-1", Message: "Back-off restarting failed container", Warning: true}}
+
+	steps := debugDecisionTree(in)
+	if len(steps) != 6 {
+		t.Fatalf("expected 6 steps, got %d", len(steps))
+	}
+	if steps[1].Status != debugWarn || steps[2].Status != debugSkip {
+		t.Errorf("unexpected owner/source statuses: %s, %s", steps[1].Status, steps[2].Status)
+	}
+	if last := lastStep(steps); last.Status != debugWarn || !strings.Contains(last.Finding, "Back-off") {
+		t.Errorf("expected health warning with recent event, got %+v", last)
+	}
+}
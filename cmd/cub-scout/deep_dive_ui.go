@@ -0,0 +1,366 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+var (
+	// deepDiveOpenUI port-forwards to the Flux or Argo CD UI and opens it (--open-ui)
+	deepDiveOpenUI string
+	// deepDiveObject is the deployer to open in the UI (--object)
+	deepDiveObject string
+)
+
+func init() {
+	mapClusterDataCmd.Flags().StringVar(&deepDiveOpenUI, "open-ui", "", "Port-forward to the controller UI (flux or argo) and open it in the browser")
+	mapClusterDataCmd.Flags().StringVar(&deepDiveObject, "object", "", "With --open-ui, the deployer to open: [kind/][namespace/]name")
+	_ = mapClusterDataCmd.RegisterFlagCompletionFunc("open-ui", cobra.FixedCompletions([]string{"flux", "argo"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// controllerUI is a web UI for a GitOps controller, found by the labels of
+// its Service.
+type controllerUI struct {
+	Tool     string // flux or argo
+	Name     string
+	Selector string
+	Scheme   string
+	// ObjectPath is the UI path showing a deployer; nil opens the start page
+	ObjectPath func(ref deployerRef) string
+}
+
+// controllerUIs are the UIs --open-ui looks for, in order of preference.
+var controllerUIs = []controllerUI{
+	{
+		Tool: "argo", Name: "Argo CD", Selector: "app.kubernetes.io/name=argocd-server", Scheme: "https",
+		ObjectPath: func(ref deployerRef) string {
+			return "/applications/" + url.PathEscape(ref.Namespace) + "/" + url.PathEscape(ref.Name)
+		},
+	},
+	{
+		Tool: "flux", Name: "Weave GitOps", Selector: "app.kubernetes.io/name=weave-gitops", Scheme: "http",
+		ObjectPath: func(ref deployerRef) string {
+			page := "kustomization"
+			if ref.Kind == "HelmRelease" {
+				page = "helm_release"
+			}
+			q := url.Values{"clusterName": {"Default"}, "name": {ref.Name}, "namespace": {ref.Namespace}}
+			return "/" + page + "/details?" + q.Encode()
+		},
+	},
+	{Tool: "flux", Name: "Flux Operator", Selector: "app.kubernetes.io/name=flux-operator", Scheme: "http"},
+	{Tool: "flux", Name: "Capacitor", Selector: "app.kubernetes.io/name=capacitor", Scheme: "http"},
+}
+
+// deployerRef names a deployer to open in a UI.
+type deployerRef struct {
+	Kind, Namespace, Name string
+}
+
+// parseDeployerRef parses --object, [kind/][namespace/]name, defaulting the
+// kind to the tool's main deployer.
+func parseDeployerRef(tool, s string) (deployerRef, error) {
+	ref := deployerRef{Kind: "Application"}
+	if tool == "flux" {
+		ref.Kind = "Kustomization"
+	}
+	parts := strings.Split(s, "/")
+	switch len(parts) {
+	case 1:
+		ref.Name = parts[0]
+	case 2:
+		ref.Namespace, ref.Name = parts[0], parts[1]
+	case 3:
+		ref.Kind, ref.Namespace, ref.Name = parts[0], parts[1], parts[2]
+	default:
+		return ref, fmt.Errorf("invalid --object %q: want [kind/][namespace/]name", s)
+	}
+	for _, k := range []string{"Application", "Kustomization", "HelmRelease"} {
+		if strings.EqualFold(ref.Kind, k) {
+			ref.Kind = k
+		}
+	}
+	if ref.Name == "" {
+		return ref, fmt.Errorf("invalid --object %q: no name", s)
+	}
+	return ref, nil
+}
+
+// deployerGVRs are the deployer kinds --object can open.
+var deployerGVRs = map[string]schema.GroupVersionResource{
+	"Application":   {Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+	"Kustomization": {Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"},
+	"HelmRelease":   {Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"},
+}
+
+// resolveDeployerRef checks the deployer exists, filling in its namespace
+// when --object left it out.
+func resolveDeployerRef(ctx context.Context, dynClient dynamic.Interface, ref deployerRef) (deployerRef, error) {
+	gvr, ok := deployerGVRs[ref.Kind]
+	if !ok {
+		return ref, fmt.Errorf("cannot open a %s in a controller UI (use Application, Kustomization or HelmRelease)", ref.Kind)
+	}
+	if ref.Namespace != "" {
+		if _, err := dynClient.Resource(gvr).Namespace(ref.Namespace).Get(ctx, ref.Name, v1.GetOptions{}); err != nil {
+			return ref, fmt.Errorf("%s %s/%s: %w", ref.Kind, ref.Namespace, ref.Name, err)
+		}
+		return ref, nil
+	}
+
+	list, err := dynClient.Resource(gvr).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return ref, fmt.Errorf("list %s: %w", gvr.Resource, err)
+	}
+	var namespaces []string
+	for _, item := range list.Items {
+		if item.GetName() == ref.Name {
+			namespaces = append(namespaces, item.GetNamespace())
+		}
+	}
+	switch len(namespaces) {
+	case 0:
+		return ref, fmt.Errorf("no %s named %s", ref.Kind, ref.Name)
+	case 1:
+		ref.Namespace = namespaces[0]
+		return ref, nil
+	}
+	return ref, fmt.Errorf("%s %s is in several namespaces (%s); use --object namespace/%s",
+		ref.Kind, ref.Name, strings.Join(namespaces, ", "), ref.Name)
+}
+
+// uiEndpoint is a running pod serving a controller UI.
+type uiEndpoint struct {
+	UI        controllerUI
+	Service   string
+	Namespace string
+	Pod       string
+	Port      int
+}
+
+// findControllerUI finds the first UI for tool with a ready pod behind its
+// Service, and the pod port the Service forwards to.
+func findControllerUI(ctx context.Context, clientset kubernetes.Interface, tool string) (uiEndpoint, error) {
+	var names []string
+	for _, ui := range controllerUIs {
+		if ui.Tool != tool {
+			continue
+		}
+		names = append(names, ui.Name)
+		svcs, err := clientset.CoreV1().Services("").List(ctx, v1.ListOptions{LabelSelector: ui.Selector})
+		if err != nil {
+			return uiEndpoint{}, fmt.Errorf("list services: %w", err)
+		}
+		for _, svc := range svcs.Items {
+			if len(svc.Spec.Selector) == 0 || len(svc.Spec.Ports) == 0 {
+				continue
+			}
+			pods, err := clientset.CoreV1().Pods(svc.Namespace).List(ctx, v1.ListOptions{
+				LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+			})
+			if err != nil {
+				return uiEndpoint{}, fmt.Errorf("list pods: %w", err)
+			}
+			for i := range pods.Items {
+				pod := &pods.Items[i]
+				if !podReady(pod) {
+					continue
+				}
+				if port := podTargetPort(pod, uiServicePort(svc, ui.Scheme)); port > 0 {
+					return uiEndpoint{UI: ui, Service: svc.Name, Namespace: svc.Namespace, Pod: pod.Name, Port: port}, nil
+				}
+			}
+		}
+	}
+	return uiEndpoint{}, fmt.Errorf("no %s UI found in the cluster (looked for %s)", tool, strings.Join(names, ", "))
+}
+
+// uiServicePort picks the Service port named like the UI's scheme, or its
+// first port.
+func uiServicePort(svc corev1.Service, scheme string) corev1.ServicePort {
+	for _, p := range svc.Spec.Ports {
+		if p.Name == scheme {
+			return p
+		}
+	}
+	return svc.Spec.Ports[0]
+}
+
+// podTargetPort resolves a Service port's target port on pod, looking up
+// named ports in the pod's containers. It returns 0 when pod lacks it.
+func podTargetPort(pod *corev1.Pod, sp corev1.ServicePort) int {
+	switch {
+	case sp.TargetPort.Type == intstr.String:
+		for _, c := range pod.Spec.Containers {
+			for _, p := range c.Ports {
+				if p.Name == sp.TargetPort.StrVal {
+					return int(p.ContainerPort)
+				}
+			}
+		}
+		return 0
+	case sp.TargetPort.IntVal != 0:
+		return int(sp.TargetPort.IntVal)
+	}
+	return int(sp.Port)
+}
+
+func podReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// uiURL is the browser URL for ep forwarded to localPort, at the object
+// when one is given and the UI can show it.
+func uiURL(ep uiEndpoint, localPort int, ref *deployerRef) string {
+	u := fmt.Sprintf("%s://localhost:%d", ep.UI.Scheme, localPort)
+	if ref != nil && ep.UI.ObjectPath != nil {
+		return u + ep.UI.ObjectPath(*ref)
+	}
+	return u + "/"
+}
+
+// startPortForward forwards a free local port to port on pod until stop is
+// closed. It returns the local port once the forward is ready, and a
+// channel receiving the forward's result when it ends.
+func startPortForward(cfg *rest.Config, clientset kubernetes.Interface, namespace, pod string, port int, stop <-chan struct{}, errOut io.Writer) (int, <-chan error, error) {
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(namespace).Name(pod).SubResource("portforward")
+	transport, upgrader, err := spdy.RoundTripperFor(cfg)
+	if err != nil {
+		return 0, nil, fmt.Errorf("port-forward: %w", err)
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	ready := make(chan struct{})
+	fw, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, stop, ready, io.Discard, errOut)
+	if err != nil {
+		return 0, nil, fmt.Errorf("port-forward: %w", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- fw.ForwardPorts() }()
+
+	select {
+	case <-ready:
+	case err := <-done:
+		return 0, nil, fmt.Errorf("port-forward to %s/%s: %w", namespace, pod, err)
+	}
+	ports, err := fw.GetPorts()
+	if err != nil || len(ports) == 0 {
+		return 0, nil, fmt.Errorf("port-forward to %s/%s: no local port", namespace, pod)
+	}
+	return int(ports[0].Local), done, nil
+}
+
+// openBrowser opens url in the default browser, best-effort.
+func openBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	}
+	return exec.Command("xdg-open", url).Start()
+}
+
+// runOpenControllerUI port-forwards to the Flux or Argo CD UI, opens the
+// browser at --object (or the UI's start page), and keeps the forward open
+// until interrupted.
+func runOpenControllerUI(tool string) error {
+	if tool != "flux" && tool != "argo" {
+		return fmt.Errorf("invalid --open-ui %q (valid: flux, argo)", tool)
+	}
+	ctx := context.Background()
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create kubernetes client: %w", err)
+	}
+
+	var ref *deployerRef
+	if deepDiveObject != "" {
+		r, err := parseDeployerRef(tool, deepDiveObject)
+		if err != nil {
+			return err
+		}
+		dynClient, err := dynamic.NewForConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("create dynamic client: %w", err)
+		}
+		if r, err = resolveDeployerRef(ctx, dynClient, r); err != nil {
+			return err
+		}
+		ref = &r
+	}
+
+	ep, err := findControllerUI(ctx, clientset, tool)
+	if err != nil {
+		return err
+	}
+	if ref != nil && ep.UI.ObjectPath == nil {
+		fmt.Fprintf(os.Stderr, "Note: %s has no page per object; opening its start page\n", ep.UI.Name)
+	}
+
+	stop := make(chan struct{})
+	localPort, done, err := startPortForward(cfg, clientset, ep.Namespace, ep.Pod, ep.Port, stop, os.Stderr)
+	if err != nil {
+		return err
+	}
+	link := uiURL(ep, localPort, ref)
+
+	fmt.Printf("%s: localhost:%d → %s/%s (pod %s, port %d)\n", ep.UI.Name, localPort, ep.Namespace, ep.Service, ep.Pod, ep.Port)
+	fmt.Printf("  %s\n", link)
+	if err := openBrowser(link); err != nil {
+		fmt.Printf("  %sCould not open a browser (%v); open the URL above%s\n", colorDim, err, colorReset)
+	}
+	fmt.Printf("%sForwarding until Ctrl+C%s\n", colorDim, colorReset)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	select {
+	case <-interrupt:
+		close(stop)
+		<-done
+		fmt.Println("Port-forward closed")
+		return nil
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("port-forward closed: %w", err)
+		}
+		return fmt.Errorf("port-forward closed: pod %s/%s went away", ep.Namespace, ep.Pod)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func uiPod(name, namespace string, ready bool, ports ...corev1.ContainerPort) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": name[:strings.LastIndex(name, "-")]}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "server", Ports: ports}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func uiService(name, namespace, appLabel, app string, ports ...corev1.ServicePort) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app.kubernetes.io/name": appLabel}},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": app}, Ports: ports},
+	}
+}
+
+func TestFindControllerUI(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		uiService("argocd-server", "argocd", "argocd-server", "argocd-server",
+			corev1.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+			corev1.ServicePort{Name: "https", Port: 443, TargetPort: intstr.FromString("server")}),
+		uiPod("argocd-server-0", "argocd", false, corev1.ContainerPort{Name: "server", ContainerPort: 8080}),
+		uiPod("argocd-server-1", "argocd", true, corev1.ContainerPort{Name: "server", ContainerPort: 8443}),
+		uiService("ww-gitops-weave-gitops", "flux-system", "weave-gitops", "weave-gitops",
+			corev1.ServicePort{Name: "http", Port: 9001}),
+		uiPod("weave-gitops-0", "flux-system", true),
+	)
+
+	ep, err := findControllerUI(context.Background(), client, "argo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The ready pod, through the https port's named target port
+	if ep.Pod != "argocd-server-1" || ep.Port != 8443 || ep.UI.Name != "Argo CD" {
+		t.Errorf("argo = %+v, want argocd-server-1:8443", ep)
+	}
+
+	ep, err = findControllerUI(context.Background(), client, "flux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.Service != "ww-gitops-weave-gitops" || ep.Port != 9001 {
+		t.Errorf("flux = %+v, want weave-gitops on 9001", ep)
+	}
+	ref := deployerRef{Kind: "HelmRelease", Namespace: "podinfo", Name: "podinfo"}
+	if got := uiURL(ep, 40123, &ref); got != "http://localhost:40123/helm_release/details?clusterName=Default&name=podinfo&namespace=podinfo" {
+		t.Errorf("uiURL = %s", got)
+	}
+
+	_, err = findControllerUI(context.Background(), fake.NewSimpleClientset(), "flux")
+	if err == nil || !strings.Contains(err.Error(), "Weave GitOps, Flux Operator, Capacitor") {
+		t.Errorf("err = %v, want the UIs looked for", err)
+	}
+}
+
+func TestParseDeployerRef(t *testing.T) {
+	for _, tc := range []struct {
+		tool, in string
+		want     deployerRef
+	}{
+		{"argo", "payments", deployerRef{Kind: "Application", Name: "payments"}},
+		{"flux", "flux-system/apps", deployerRef{Kind: "Kustomization", Namespace: "flux-system", Name: "apps"}},
+		{"flux", "helmrelease/podinfo/podinfo", deployerRef{Kind: "HelmRelease", Namespace: "podinfo", Name: "podinfo"}},
+	} {
+		got, err := parseDeployerRef(tc.tool, tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("parseDeployerRef(%s, %s) = %+v, %v; want %+v", tc.tool, tc.in, got, err, tc.want)
+		}
+	}
+	for _, bad := range []string{"a/b/c/d", "argocd/"} {
+		if _, err := parseDeployerRef("argo", bad); err == nil {
+			t.Errorf("parseDeployerRef(%s): want an error", bad)
+		}
+	}
+}
+
+func TestResolveDeployerRef(t *testing.T) {
+	app := func(namespace, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1", "kind": "Application",
+			"metadata": map[string]interface{}{"name": name, "namespace": namespace},
+		}}
+	}
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{deployerGVRs["Application"]: "ApplicationList"},
+		app("argocd", "payments"), app("argocd", "web"), app("team-a", "web"))
+
+	ref, err := resolveDeployerRef(context.Background(), dyn, deployerRef{Kind: "Application", Name: "payments"})
+	if err != nil || ref.Namespace != "argocd" {
+		t.Errorf("payments = %+v, %v; want it found in argocd", ref, err)
+	}
+	if _, err := resolveDeployerRef(context.Background(), dyn, deployerRef{Kind: "Application", Name: "web"}); err == nil ||
+		!strings.Contains(err.Error(), "several namespaces (argocd, team-a)") {
+		t.Errorf("web: err = %v, want it ambiguous", err)
+	}
+	if _, err := resolveDeployerRef(context.Background(), dyn, deployerRef{Kind: "Application", Name: "billing"}); err == nil {
+		t.Error("billing: want not found")
+	}
+}
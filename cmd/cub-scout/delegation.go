@@ -0,0 +1,116 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+// ownerDelegations indexes GitOps deployers that apply ConfigHub OCI artifacts.
+// Map views load it once per command via loadDelegations; nil means none.
+var ownerDelegations *agent.DelegationIndex
+
+// loadDelegations builds the delegation index from the cluster. Missing CRDs
+// are not an error: a cluster without Flux or Argo simply has no delegations.
+func loadDelegations(ctx context.Context, dynClient dynamic.Interface) *agent.DelegationIndex {
+	idx := agent.NewDelegationIndex()
+
+	// The same scans attribute Argo CD resources by its tracking method
+	loadArgoTracking(ctx, dynClient)
+
+	kustomizations := listAll(ctx, dynClient, schema.GroupVersionResource{
+		Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations",
+	})
+	if len(kustomizations) > 0 {
+		// OCIRepository graduated to v1 in Flux 2.6; fall back to v1beta2
+		repos := listAll(ctx, dynClient, schema.GroupVersionResource{
+			Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "ocirepositories",
+		})
+		if repos == nil {
+			repos = listAll(ctx, dynClient, schema.GroupVersionResource{
+				Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "ocirepositories",
+			})
+		}
+		idx.AddFluxKustomizations(kustomizations, repos)
+	}
+
+	idx.AddArgoApplications(listAll(ctx, dynClient, schema.GroupVersionResource{
+		Group: "argoproj.io", Version: "v1alpha1", Resource: "applications",
+	}))
+
+	ownerDelegations = idx
+	return idx
+}
+
+// listAll lists gvr across all namespaces, returning nil on error.
+func listAll(ctx context.Context, dynClient dynamic.Interface, gvr schema.GroupVersionResource) []unstructured.Unstructured {
+	l, err := dynClient.Resource(gvr).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	return l.Items
+}
+
+// applyDelegation rewrites a map entry owned by a delegating deployer to
+// ConfigHub ownership, keeping the deployer and unit linkage in OwnerDetails.
+func applyDelegation(entry *MapEntry, obj *unstructured.Unstructured, d *agent.Delegation) {
+	entry.Owner = "ConfigHub"
+	if entry.OwnerDetails == nil {
+		entry.OwnerDetails = map[string]string{}
+	}
+	entry.OwnerDetails["delegatedVia"] = d.Via
+	entry.OwnerDetails["deployer"] = d.DeployerKind + "/" + d.DeployerName
+	entry.OwnerDetails["oci"] = d.URL
+	if d.Space != "" {
+		entry.OwnerDetails["space"] = d.Space
+	}
+	if d.Target != "" {
+		entry.OwnerDetails["target"] = d.Target
+	}
+	if unit := d.UnitFor(obj); unit != "" {
+		entry.OwnerDetails["unit"] = unit
+	}
+}
+
+// ownerLabel returns the owner column text for a map entry, spelling out
+// delegated applies, e.g. "ConfigHub (delegated via Flux)", and contested
+// ownership, e.g. "ArgoCD ⚠ contested: ArgoCD+ConfigHub".
+func ownerLabel(e MapEntry) string {
+	if via := e.OwnerDetails["delegatedVia"]; via != "" {
+		return e.Owner + " (delegated via " + via + ")"
+	}
+	if len(e.Contested) > 0 {
+		return e.Owner + " ⚠ contested: " + strings.Join(e.Contested, "+")
+	}
+	return e.Owner
+}
+
+// countContested returns the number of entries claimed by more than one manager.
+func countContested(entries []MapEntry) int {
+	n := 0
+	for _, e := range entries {
+		if len(e.Contested) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// delegatedManagedBy returns the managed-by column for a delegated resource:
+// the unit when known, otherwise the ConfigHub target.
+func delegatedManagedBy(obj *unstructured.Unstructured, d *agent.Delegation) string {
+	linkage := d.UnitFor(obj)
+	if linkage == "" {
+		linkage = "target " + d.Space + "/" + d.Target
+	}
+	return linkage + " (delegated via " + d.Via + ")"
+}
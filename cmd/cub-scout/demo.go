@@ -230,6 +230,9 @@ func getRepoRoot() string {
 
 // Apply a YAML file with kubectl
 func kubectlApply(yamlPath string) error {
+	if err := checkWrite("kubectl apply -f " + yamlPath); err != nil {
+		return err
+	}
 	cmd := exec.Command("kubectl", "apply", "-f", yamlPath)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -238,12 +241,26 @@ func kubectlApply(yamlPath string) error {
 
 // Delete a YAML file with kubectl
 func kubectlDelete(yamlPath string) error {
+	if err := checkWrite("kubectl delete -f " + yamlPath); err != nil {
+		return err
+	}
 	cmd := exec.Command("kubectl", "delete", "-f", yamlPath, "--ignore-not-found")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// Delete a namespace with kubectl
+func kubectlDeleteNamespace(namespace string) error {
+	if err := checkWrite("delete namespace " + namespace); err != nil {
+		return err
+	}
+	cmd := exec.Command("kubectl", "delete", "namespace", namespace, "--ignore-not-found")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // Run a cub-scout subcommand
 func runCubAgent(args ...string) error {
 	// Find the cub-scout binary
@@ -299,8 +316,12 @@ func cleanupDemoQuick() error {
 	fixturesDir := filepath.Join(repoRoot, "test", "atk", "fixtures")
 
 	fmt.Println(demoInfoStyle.Render("Cleaning up quick demo resources..."))
-	_ = kubectlDelete(filepath.Join(fixturesDir, "flux-basic.yaml"))
-	_ = kubectlDelete(filepath.Join(fixturesDir, "argo-basic.yaml"))
+	if err := kubectlDelete(filepath.Join(fixturesDir, "flux-basic.yaml")); err != nil {
+		return err
+	}
+	if err := kubectlDelete(filepath.Join(fixturesDir, "argo-basic.yaml")); err != nil {
+		return err
+	}
 	fmt.Println(demoPassStyle.Render("Cleanup complete."))
 
 	return nil
@@ -348,7 +369,9 @@ func cleanupDemoCCVE() error {
 	badConfigPath := filepath.Join(repoRoot, "examples", "impressive-demo", "bad-configs", "monitoring-bad.yaml")
 
 	fmt.Println(demoInfoStyle.Render("Cleaning up CCVE demo resources..."))
-	_ = kubectlDelete(badConfigPath)
+	if err := kubectlDelete(badConfigPath); err != nil {
+		return err
+	}
 	fmt.Println(demoPassStyle.Render("Cleanup complete."))
 
 	return nil
@@ -418,7 +441,9 @@ func cleanupDemoQuery() error {
 	multiClusterPath := filepath.Join(repoRoot, "examples", "demos", "multi-cluster.yaml")
 
 	fmt.Println(demoInfoStyle.Render("Cleaning up query demo resources..."))
-	_ = kubectlDelete(multiClusterPath)
+	if err := kubectlDelete(multiClusterPath); err != nil {
+		return err
+	}
 	fmt.Println(demoPassStyle.Render("Cleanup complete."))
 
 	return nil
@@ -537,11 +562,14 @@ func cleanupScenarioBreakGlass() error {
 	fixturePath := filepath.Join(repoRoot, "examples", "demos", "break-glass.yaml")
 
 	fmt.Println(demoInfoStyle.Render("Cleaning up break-glass scenario resources..."))
-	_ = kubectlDelete(fixturePath)
+	if err := kubectlDelete(fixturePath); err != nil {
+		return err
+	}
 
 	// Also delete namespace
-	cmd := exec.Command("kubectl", "delete", "namespace", "break-glass-demo", "--ignore-not-found")
-	_ = cmd.Run()
+	if err := kubectlDeleteNamespace("break-glass-demo"); err != nil {
+		return err
+	}
 
 	fmt.Println(demoPassStyle.Render("Cleanup complete."))
 
@@ -0,0 +1,74 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/confighub/cub-scout/pkg/diff"
+)
+
+// diffFlags are the --diff-format and --color flags of a diff-producing
+// command.
+type diffFlags struct {
+	format string
+	color  string
+}
+
+// addDiffFlags registers --diff-format and --color on cmd.
+func addDiffFlags(cmd *cobra.Command, f *diffFlags) {
+	cmd.Flags().StringVar(&f.format, "diff-format", "", "Show each difference as a diff: unified, side-by-side or jsonpatch")
+	cmd.Flags().StringVar(&f.color, "color", "auto", "Color diffs: auto (when writing to a terminal), always or never")
+	_ = cmd.RegisterFlagCompletionFunc("diff-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return filterPrefix(diff.Formats, toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("color", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return filterPrefix([]string{"auto", "always", "never"}, toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// options returns the rendering options, or nil when --diff-format wasn't
+// given and the command keeps its own summary output.
+func (f diffFlags) options() (*diff.Options, error) {
+	if f.format == "" {
+		return nil, nil
+	}
+	format, err := diff.ParseFormat(f.format)
+	if err != nil {
+		return nil, err
+	}
+	opts := &diff.Options{Format: format, Width: stdoutWidth(), Redactor: activeRedactor()}
+	switch f.color {
+	case "always":
+		opts.Color = true
+	case "never":
+	case "auto", "":
+		opts.Color = stdoutIsTerminal() && os.Getenv("NO_COLOR") == ""
+	default:
+		return nil, fmt.Errorf("unknown --color %q (use auto, always or never)", f.color)
+	}
+	return opts, nil
+}
+
+func stdoutIsTerminal() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// stdoutWidth is the terminal's width, $COLUMNS when stdout isn't a
+// terminal, or diff.DefaultWidth.
+func stdoutWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return diff.DefaultWidth
+}
@@ -0,0 +1,327 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	doctorNamespace string
+	doctorJSON      bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check cluster access, RBAC and tooling before you start",
+	Long: `Run preflight checks and print an actionable fix for each problem:
+
+  - Kubeconfig loads and the API server is reachable
+  - list/get permissions for every resource type cub-scout reads
+  - Flux and Argo CD CRDs are installed
+  - cub CLI is on PATH and authenticated (needed for connected mode only)
+
+When a command "shows nothing", run doctor first: missing RBAC makes
+resource types look empty rather than failing.
+
+Exit status is non-zero when any check fails.
+
+Examples:
+  cub-scout doctor
+  cub-scout doctor -n prod
+  cub-scout doctor --json`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().StringVarP(&doctorNamespace, "namespace", "n", "", "Check permissions in this namespace instead of cluster-wide")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output as JSON")
+	_ = doctorCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+}
+
+// Doctor check statuses
+const (
+	doctorOK   = "ok"
+	doctorInfo = "info"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// DoctorCheck is the result of one preflight check.
+type DoctorCheck struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Status   string `json:"status"` // ok, info, warn, fail
+	Detail   string `json:"detail,omitempty"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// doctorResource is a resource type cub-scout reads. Group resources are
+// only checked when their API group is installed.
+type doctorResource struct {
+	GVR      schema.GroupVersionResource
+	Required bool   // core to map/trace; missing access fails the check
+	Used     string // what breaks without access
+}
+
+var doctorResources = []doctorResource{
+	{schema.GroupVersionResource{Group: "", Version: "v1", Resource: "namespaces"}, true, "namespace discovery"},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, true, "map, trace"},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, true, "map"},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, true, "map"},
+	{schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, false, "trace, timeline"},
+	{schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}, false, "trace, timeline, debug"},
+	{schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"}, true, "map"},
+	{schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}, true, "map"},
+	{schema.GroupVersionResource{Group: "", Version: "v1", Resource: "secrets"}, false, "Helm release detection, map list"},
+	{schema.GroupVersionResource{Group: "", Version: "v1", Resource: "events"}, false, "timeline, debug"},
+	{schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, false, "map"},
+	{schema.GroupVersionResource{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories"}, true, "Flux trace"},
+	{schema.GroupVersionResource{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "ocirepositories"}, false, "ConfigHub delegated apply"},
+	{schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}, true, "Flux ownership and trace"},
+	{schema.GroupVersionResource{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}, true, "Flux Helm trace"},
+	{schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}, true, "Argo CD ownership and trace"},
+}
+
+// doctorTools are the GitOps tools whose CRDs are checked.
+var doctorTools = []struct {
+	Name   string
+	Groups []string
+}{
+	{"Flux", []string{"source.toolkit.fluxcd.io", "kustomize.toolkit.fluxcd.io", "helm.toolkit.fluxcd.io"}},
+	{"Argo CD", []string{"argoproj.io"}},
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	var checks []DoctorCheck
+
+	cfg, err := buildConfig()
+	if err != nil {
+		checks = append(checks, DoctorCheck{Category: "Cluster", Name: "kubeconfig", Status: doctorFail,
+			Detail: err.Error(),
+			Fix:    "Set KUBECONFIG or run 'kubectl config use-context <name>'"})
+	} else {
+		checks = append(checks, DoctorCheck{Category: "Cluster", Name: "kubeconfig", Status: doctorOK,
+			Detail: fmt.Sprintf("context %s → %s", getCurrentContext(), cfg.Host)})
+		clientset, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("create kubernetes client: %w", err)
+		}
+		checks = append(checks, checkClusterAccess(ctx, clientset, doctorNamespace)...)
+	}
+	checks = append(checks, checkCubCLI()...)
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == doctorFail {
+			failed++
+		}
+	}
+
+	if doctorJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(checks); err != nil {
+			return err
+		}
+	} else {
+		printDoctorChecks(checks)
+	}
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", failed)
+	}
+	return nil
+}
+
+// checkClusterAccess checks API server reachability, CRDs and RBAC.
+func checkClusterAccess(ctx context.Context, client kubernetes.Interface, namespace string) []DoctorCheck {
+	var checks []DoctorCheck
+
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return append(checks, DoctorCheck{Category: "Cluster", Name: "API server", Status: doctorFail,
+			Detail: err.Error(),
+			Fix:    "Check network access to the cluster and that your credentials have not expired (kubectl get ns)"})
+	}
+	checks = append(checks, DoctorCheck{Category: "Cluster", Name: "API server", Status: doctorOK,
+		Detail: "Kubernetes " + version.GitVersion})
+
+	installed := map[string]bool{"": true}
+	if groups, err := client.Discovery().ServerGroups(); err == nil {
+		for _, g := range groups.Groups {
+			installed[g.Name] = true
+		}
+	}
+	for _, tool := range doctorTools {
+		var found, missing []string
+		for _, g := range tool.Groups {
+			if installed[g] {
+				found = append(found, g)
+			} else {
+				missing = append(missing, g)
+			}
+		}
+		switch {
+		case len(missing) == 0:
+			checks = append(checks, DoctorCheck{Category: "CRDs", Name: tool.Name, Status: doctorOK, Detail: strings.Join(found, ", ")})
+		case len(found) == 0:
+			checks = append(checks, DoctorCheck{Category: "CRDs", Name: tool.Name, Status: doctorInfo,
+				Detail: "not installed; " + tool.Name + " ownership is still detected from labels, but trace cannot follow its sources"})
+		default:
+			checks = append(checks, DoctorCheck{Category: "CRDs", Name: tool.Name, Status: doctorWarn,
+				Detail: "partially installed, missing " + strings.Join(missing, ", "),
+				Fix:    "Check the " + tool.Name + " installation (some controllers are not installed)"})
+		}
+	}
+
+	scope := "cluster-wide"
+	if namespace != "" {
+		scope = "in " + namespace
+	}
+	for _, r := range doctorResources {
+		if !installed[r.GVR.Group] {
+			continue
+		}
+		if r.GVR.Resource == "namespaces" && namespace != "" {
+			continue // cluster-scoped; not needed for a single namespace
+		}
+		name := r.GVR.Resource
+		if r.GVR.Group != "" {
+			name += "." + r.GVR.Group
+		}
+		var denied []string
+		var reviewErr error
+		for _, verb := range []string{"list", "get"} {
+			allowed, err := canI(ctx, client, verb, r.GVR, namespace)
+			if err != nil {
+				reviewErr = err
+				break
+			}
+			if !allowed {
+				denied = append(denied, verb)
+			}
+		}
+		if reviewErr != nil {
+			checks = append(checks, DoctorCheck{Category: "RBAC", Name: name, Status: doctorWarn,
+				Detail: "could not check access: " + reviewErr.Error()})
+			continue
+		}
+		if len(denied) == 0 {
+			checks = append(checks, DoctorCheck{Category: "RBAC", Name: name, Status: doctorOK, Detail: "list, get " + scope})
+			continue
+		}
+		status := doctorWarn
+		if r.Required {
+			status = doctorFail
+		}
+		checks = append(checks, DoctorCheck{Category: "RBAC", Name: name, Status: status,
+			Detail: fmt.Sprintf("cannot %s %s; affects %s", strings.Join(denied, "/"), scope, r.Used),
+			Fix:    rbacFix(r.GVR, namespace)})
+	}
+	return checks
+}
+
+// canI asks the API server whether the current user may perform verb on gvr.
+func canI(ctx context.Context, client kubernetes.Interface, verb string, gvr schema.GroupVersionResource, namespace string) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     gvr.Group,
+				Resource:  gvr.Resource,
+			},
+		},
+	}
+	result, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, v1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
+
+// rbacFix suggests a kubectl command granting read access to gvr.
+func rbacFix(gvr schema.GroupVersionResource, namespace string) string {
+	resource := gvr.Resource
+	if gvr.Group != "" {
+		resource += "." + gvr.Group
+	}
+	if namespace != "" {
+		return fmt.Sprintf("kubectl create role cub-scout-read --verb=get,list,watch --resource=%s -n %s && kubectl create rolebinding cub-scout-read --role=cub-scout-read --user=<you> -n %s", resource, namespace, namespace)
+	}
+	return fmt.Sprintf("kubectl create clusterrole cub-scout-read --verb=get,list,watch --resource=%s && kubectl create clusterrolebinding cub-scout-read --clusterrole=cub-scout-read --user=<you>", resource)
+}
+
+// checkCubCLI checks the cub CLI, which only connected mode needs.
+func checkCubCLI() []DoctorCheck {
+	if _, err := exec.LookPath("cub"); err != nil {
+		return []DoctorCheck{{Category: "ConfigHub", Name: "cub CLI", Status: doctorInfo,
+			Detail: "not installed; standalone commands work, connected mode is unavailable",
+			Fix:    "Install cub: https://docs.confighub.com/get-started/setup/#install-the-cli"}}
+	}
+	checks := []DoctorCheck{{Category: "ConfigHub", Name: "cub CLI", Status: doctorOK, Detail: "on PATH"}}
+
+	cubCtx, email, err := getStatusCubContext()
+	if err != nil || cubCtx == nil {
+		return append(checks, DoctorCheck{Category: "ConfigHub", Name: "cub auth", Status: doctorWarn,
+			Detail: "not authenticated; connected mode and ConfigHub lookups are disabled",
+			Fix:    "cub auth login"})
+	}
+	detail := cubCtx.Coordinate.ServerURL
+	if email != "" {
+		detail = email + " @ " + detail
+	}
+	if cubCtx.Settings.DefaultSpace != "" {
+		detail += " (space " + cubCtx.Settings.DefaultSpace + ")"
+	}
+	return append(checks, DoctorCheck{Category: "ConfigHub", Name: "cub auth", Status: doctorOK, Detail: detail})
+}
+
+func printDoctorChecks(checks []DoctorCheck) {
+	fmt.Printf("%s%sCUB-SCOUT DOCTOR%s\n", colorBold, colorCyan, colorReset)
+
+	category := ""
+	problems := 0
+	for _, c := range checks {
+		if c.Category != category {
+			category = c.Category
+			fmt.Printf("\n%s%s%s\n", colorBold, category, colorReset)
+		}
+		icon, color := "✓", colorGreen
+		switch c.Status {
+		case doctorInfo:
+			icon, color = "○", colorDim
+		case doctorWarn:
+			icon, color = "⚠", colorYellow
+			problems++
+		case doctorFail:
+			icon, color = "✗", colorRed
+			problems++
+		}
+		fmt.Printf("  %s%s%s %-40s %s\n", color, icon, colorReset, c.Name, c.Detail)
+		if c.Fix != "" {
+			fmt.Printf("    %s→ %s%s\n", colorDim, c.Fix, colorReset)
+		}
+	}
+
+	fmt.Println()
+	if problems == 0 {
+		fmt.Printf("%s✓ All checks passed%s\n", colorGreen, colorReset)
+	} else {
+		fmt.Printf("%d problem(s) found. Apply the fixes above, then run 'cub-scout doctor' again.\n", problems)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckClusterAccess(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Resources = []*v1.APIResourceList{
+		{GroupVersion: "v1"},
+		{GroupVersion: "apps/v1"},
+		{GroupVersion: "kustomize.toolkit.fluxcd.io/v1"},
+		{GroupVersion: "source.toolkit.fluxcd.io/v1"},
+	}
+	// Allow everything except secrets and kustomizations
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		res := review.Spec.ResourceAttributes.Resource
+		review.Status.Allowed = res != "secrets" && res != "kustomizations"
+		return true, review, nil
+	})
+
+	checks := checkClusterAccess(context.Background(), client, "")
+	byName := map[string]DoctorCheck{}
+	for _, c := range checks {
+		byName[c.Category+"/"+c.Name] = c
+	}
+
+	if c := byName["Cluster/API server"]; c.Status != doctorOK {
+		t.Errorf("API server: %+v", c)
+	}
+	if c := byName["CRDs/Flux"]; c.Status != doctorWarn {
+		t.Errorf("Flux without helm controller should warn: %+v", c)
+	}
+	if c := byName["CRDs/Argo CD"]; c.Status != doctorInfo {
+		t.Errorf("Argo CD not installed should be info: %+v", c)
+	}
+	if c := byName["RBAC/deployments.apps"]; c.Status != doctorOK {
+		t.Errorf("deployments: %+v", c)
+	}
+	if c := byName["RBAC/secrets"]; c.Status != doctorWarn || c.Fix == "" {
+		t.Errorf("secrets should warn with a fix: %+v", c)
+	}
+	if c := byName["RBAC/kustomizations.kustomize.toolkit.fluxcd.io"]; c.Status != doctorFail {
+		t.Errorf("kustomizations should fail: %+v", c)
+	}
+	if _, ok := byName["RBAC/applications.argoproj.io"]; ok {
+		t.Error("resources of uninstalled groups should not be checked")
+	}
+}
+
+func TestRBACFix(t *testing.T) {
+	got := rbacFix(doctorResources[0].GVR, "prod")
+	want := "kubectl create role cub-scout-read --verb=get,list,watch --resource=namespaces -n prod && kubectl create rolebinding cub-scout-read --role=cub-scout-read --user=<you> -n prod"
+	if got != want {
+		t.Errorf("rbacFix() = %q", got)
+	}
+}
@@ -0,0 +1,474 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/confighub/cub-scout/pkg/diff"
+)
+
+var (
+	driftSpaces  []string
+	driftJSON    bool
+	driftContent bool
+	driftDiff    diffFlags
+)
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Find drift between ConfigHub and live state",
+	Long: `Find drift between ConfigHub and what is running.
+
+For GitOps deployers that are out of sync, use 'cub-scout map drift'.`,
+}
+
+var driftUnitsCmd = &cobra.Command{
+	Use:   "units",
+	Short: "List ConfigHub units whose live state differs from ConfigHub",
+	Long: `List ConfigHub units whose live state has drifted from ConfigHub.
+
+A unit is listed when:
+  revision  its live revision is behind its head revision (changes not applied)
+  status    ConfigHub reports drift for it (UnitStatus.Drift)
+  content   with --content, the unit is applied at head but its live data
+            no longer hashes to its desired data (fields set outside ConfigHub)
+
+With --content, --diff-format shows each differing resource as a unified
+diff, side-by-side columns or a JSON patch from its desired data to what is
+live. Secret data is redacted.
+
+Each unit shows how long it has drifted and the target and worker
+responsible for applying it. Revision drift is dated from the oldest
+unapplied revision; other drift has no start time and shows "-".
+
+Units being applied report drift until their workloads finish rolling out.
+With --wait, units are checked once rollouts in the current cluster have
+settled; --until-stable re-checks until two checks agree.
+
+Examples:
+  cub-scout drift units --space prod             # One space
+  cub-scout drift units                          # Every space
+  cub-scout drift units --space prod --content   # Also compare live data
+  cub-scout drift units --space prod --content --wait  # After rollouts settle
+  cub-scout drift units --space prod --content --diff-format side-by-side
+  cub-scout drift units --space prod --json`,
+	Args: cobra.NoArgs,
+	RunE: runDriftUnits,
+}
+
+func init() {
+	driftUnitsCmd.Flags().StringSliceVar(&driftSpaces, "space", nil, "Spaces to check (default: all spaces)")
+	_ = driftUnitsCmd.RegisterFlagCompletionFunc("space", completeSpaces)
+	driftUnitsCmd.Flags().BoolVar(&driftJSON, "json", false, "Output as JSON")
+	addRolloutWaitFlags(driftUnitsCmd)
+	driftUnitsCmd.Flags().BoolVar(&driftContent, "content", false, "Also compare each unit's live data with its desired data (two cub calls per unit)")
+	addDiffFlags(driftUnitsCmd, &driftDiff)
+
+	driftCmd.AddCommand(driftUnitsCmd)
+	rootCmd.AddCommand(driftCmd)
+}
+
+// Kinds of unit drift.
+const (
+	unitDriftRevision = "revision"
+	unitDriftStatus   = "status"
+	unitDriftContent  = "content"
+)
+
+// UnitDrift is a ConfigHub unit whose live state differs from ConfigHub.
+type UnitDrift struct {
+	Space        string   `json:"space"`
+	Unit         string   `json:"unit"`
+	Kinds        []string `json:"kinds"` // revision, status, content
+	HeadRevision int      `json:"headRevision"`
+	LiveRevision int      `json:"liveRevision"`
+	// Status is UnitStatus.Drift as reported by ConfigHub
+	Status string `json:"status,omitempty"`
+	// Since is when the oldest unapplied revision was created (revision drift only)
+	Since      *time.Time `json:"since,omitempty"`
+	AgeSeconds int64      `json:"ageSeconds,omitempty"`
+	Target     string     `json:"target,omitempty"`
+	Worker     string     `json:"worker,omitempty"`
+	Detail     string     `json:"detail,omitempty"`
+
+	// diffs are the differing resources found by --content
+	diffs []diff.Document
+}
+
+func runDriftUnits(cmd *cobra.Command, args []string) error {
+	if !cubInstalled() {
+		return cubMissingError(cmd.CommandPath())
+	}
+	render, err := driftDiff.options()
+	if err != nil {
+		return err
+	}
+	if render != nil && !driftContent {
+		return fmt.Errorf("--diff-format needs --content: only content drift has data to diff")
+	}
+
+	spaces := driftSpaces
+	if len(spaces) == 0 {
+		var err error
+		if spaces, err = listSpaceSlugs(); err != nil {
+			return fmt.Errorf("list spaces: %w\n\n  Check that you're authenticated: cub auth login", err)
+		}
+	}
+
+	var drifts []UnitDrift
+	err = localRolloutGate(context.Background()).run(func() (string, error) {
+		var err error
+		drifts, err = collectUnitDrifts(spaces)
+		return unitDriftSummary(drifts), err
+	})
+	if err != nil {
+		return err
+	}
+
+	if driftJSON {
+		if drifts == nil {
+			drifts = []UnitDrift{}
+		}
+		return writeJSON(os.Stdout, "UnitDrifts", drifts)
+	}
+	printUnitDrifts(os.Stdout, drifts, len(spaces))
+	if render != nil {
+		return printUnitDriftDiffs(os.Stdout, drifts, *render)
+	}
+	return nil
+}
+
+// collectUnitDrifts checks every unit in spaces, oldest drift first.
+func collectUnitDrifts(spaces []string) ([]UnitDrift, error) {
+	var drifts []UnitDrift
+	for _, space := range spaces {
+		units, err := loadUnitsForSpace(space)
+		if err != nil {
+			return nil, fmt.Errorf("list units in space %s: %w", space, err)
+		}
+		for _, u := range units {
+			d := detectUnitDrift(space, u)
+			// Data at head is only expected live once head is applied
+			if driftContent && u.Unit.LiveRevisionNum > 0 && u.Unit.LiveRevisionNum == u.Unit.HeadRevisionNum {
+				if detail, diffs, err := compareUnitContent(space, u.Unit.Slug); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not compare content of %s/%s: %v\n", space, u.Unit.Slug, err)
+				} else if detail != "" {
+					if d == nil {
+						d = newUnitDrift(space, u)
+					}
+					d.Kinds = append(d.Kinds, unitDriftContent)
+					d.Detail = detail
+					d.diffs = diffs
+				}
+			}
+			if d == nil {
+				continue
+			}
+			if contains(d.Kinds, unitDriftRevision) {
+				if revisions, err := fetchConfigHubRevisions(space, d.Unit); err == nil {
+					setDriftSince(d, revisions, time.Now())
+				}
+			}
+			drifts = append(drifts, *d)
+		}
+	}
+	sortUnitDrifts(drifts)
+	return drifts, nil
+}
+
+// unitDriftSummary identifies a drift result for --until-stable comparisons.
+func unitDriftSummary(drifts []UnitDrift) string {
+	var b strings.Builder
+	for _, d := range drifts {
+		fmt.Fprintf(&b, "%s/%s %v %d/%d %s\n", d.Space, d.Unit, d.Kinds, d.LiveRevision, d.HeadRevision, d.Detail)
+	}
+	return b.String()
+}
+
+// listSpaceSlugs returns the slugs of every space in the current org.
+func listSpaceSlugs() ([]string, error) {
+	out, err := runCubCommand("space", "list", "--json", "--quiet")
+	if err != nil {
+		return nil, err
+	}
+	var spaces []CubSpaceData
+	if err := json.Unmarshal(out, &spaces); err != nil {
+		return nil, err
+	}
+	var slugs []string
+	for _, s := range spaces {
+		slugs = append(slugs, s.Space.Slug)
+	}
+	sort.Strings(slugs)
+	return slugs, nil
+}
+
+func newUnitDrift(space string, u CubUnitData) *UnitDrift {
+	return &UnitDrift{
+		Space:        space,
+		Unit:         u.Unit.Slug,
+		HeadRevision: u.Unit.HeadRevisionNum,
+		LiveRevision: u.Unit.LiveRevisionNum,
+		Target:       u.Target.Slug,
+		Worker:       u.BridgeWorker.Slug,
+	}
+}
+
+// detectUnitDrift returns the unit's revision and status drift, or nil.
+// Units never applied (live revision 0) have nothing live to drift.
+func detectUnitDrift(space string, u CubUnitData) *UnitDrift {
+	var kinds []string
+	if u.Unit.LiveRevisionNum > 0 && u.Unit.LiveRevisionNum < u.Unit.HeadRevisionNum {
+		kinds = append(kinds, unitDriftRevision)
+	}
+	status := u.UnitStatus.Drift
+	if status != "" && status != "NotDrifted" && status != "N/A" {
+		kinds = append(kinds, unitDriftStatus)
+	}
+	if len(kinds) == 0 {
+		return nil
+	}
+	d := newUnitDrift(space, u)
+	d.Kinds = kinds
+	if contains(kinds, unitDriftStatus) {
+		d.Status = status
+	}
+	return d
+}
+
+// setDriftSince dates revision drift from the oldest revision after the live
+// one: the first change that was not applied.
+func setDriftSince(d *UnitDrift, revisions []ConfigHubRevision, now time.Time) {
+	since, ok := oldestRevisionAfter(revisions, d.LiveRevision)
+	if !ok {
+		return
+	}
+	d.Since = &since
+	d.AgeSeconds = int64(now.Sub(since).Seconds())
+}
+
+// oldestRevisionAfter returns when the first dated revision after num was
+// created.
+func oldestRevisionAfter(revisions []ConfigHubRevision, num int) (time.Time, bool) {
+	var since time.Time
+	oldest := 0
+	for _, rev := range revisions {
+		if rev.Num <= num || rev.CreatedAt.IsZero() {
+			continue
+		}
+		if oldest == 0 || rev.Num < oldest {
+			oldest = rev.Num
+			since = rev.CreatedAt
+		}
+	}
+	return since, oldest != 0
+}
+
+// compareUnitContent compares the unit's desired data with its live data and
+// describes the mismatch, or returns "" when they agree.
+func compareUnitContent(space, unit string) (string, []diff.Document, error) {
+	desired, err := runCubCommand("unit", "get", unit, "--space", space, "--data-only")
+	if err != nil {
+		return "", nil, fmt.Errorf("get data: %w", err)
+	}
+	live, err := runCubCommand("unit", "livedata", unit, "--space", space)
+	if err != nil {
+		return "", nil, fmt.Errorf("get live data: %w", err)
+	}
+	return contentDrift(desired, live)
+}
+
+// contentDrift hashes each desired resource and the same fields of its live
+// counterpart, so fields the cluster adds (status, defaults) do not count as
+// drift. It describes the resources that differ or are missing, and returns
+// a diff document for each.
+func contentDrift(desired, live []byte) (string, []diff.Document, error) {
+	desiredDocs, err := decodeYAMLDocs(desired)
+	if err != nil {
+		return "", nil, fmt.Errorf("parse data: %w", err)
+	}
+	liveDocs, err := decodeYAMLDocs(live)
+	if err != nil {
+		return "", nil, fmt.Errorf("parse live data: %w", err)
+	}
+	liveByKey := make(map[string]map[string]any, len(liveDocs))
+	for _, doc := range liveDocs {
+		liveByKey[resourceKey(doc)] = doc
+	}
+
+	var changed, missing []string
+	var diffs []diff.Document
+	for _, doc := range desiredDocs {
+		key := resourceKey(doc)
+		liveDoc, ok := liveByKey[key]
+		if !ok {
+			missing = append(missing, key)
+		} else if contentHash(doc) != contentHash(projectOnto(liveDoc, doc)) {
+			changed = append(changed, key)
+		} else {
+			continue
+		}
+		from, to := diffSides(doc, liveDoc)
+		diffs = append(diffs, diff.Document{Name: key, FromLabel: "confighub", ToLabel: "live", From: from, To: to})
+	}
+
+	var parts []string
+	if len(changed) > 0 {
+		parts = append(parts, "changed: "+strings.Join(changed, ", "))
+	}
+	if len(missing) > 0 {
+		parts = append(parts, "missing: "+strings.Join(missing, ", "))
+	}
+	return strings.Join(parts, "; "), diffs, nil
+}
+
+// decodeYAMLDocs decodes a multi-document YAML stream, skipping empty documents.
+func decodeYAMLDocs(data []byte) ([]map[string]any, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []map[string]any
+	for {
+		var doc map[string]any
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+			return nil, err
+		}
+		if len(doc) > 0 {
+			docs = append(docs, doc)
+		}
+	}
+}
+
+// resourceKey identifies a resource as Kind/namespace/name.
+func resourceKey(doc map[string]any) string {
+	kind, _ := doc["kind"].(string)
+	meta, _ := doc["metadata"].(map[string]any)
+	name, _ := meta["name"].(string)
+	if ns, _ := meta["namespace"].(string); ns != "" {
+		return kind + "/" + ns + "/" + name
+	}
+	return kind + "/" + name
+}
+
+// projectOnto returns the parts of live at the paths set in desired.
+func projectOnto(live, desired any) any {
+	switch d := desired.(type) {
+	case map[string]any:
+		l, ok := live.(map[string]any)
+		if !ok {
+			return live
+		}
+		out := make(map[string]any, len(d))
+		for k, v := range d {
+			if lv, ok := l[k]; ok {
+				out[k] = projectOnto(lv, v)
+			}
+		}
+		return out
+	case []any:
+		l, ok := live.([]any)
+		if !ok || len(l) != len(d) {
+			return live
+		}
+		out := make([]any, len(d))
+		for i := range d {
+			out[i] = projectOnto(l[i], d[i])
+		}
+		return out
+	}
+	return live
+}
+
+// contentHash hashes a decoded document. JSON encoding sorts map keys, so
+// equal content hashes equal regardless of field order.
+func contentHash(v any) string {
+	data, _ := json.Marshal(v)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// sortUnitDrifts puts the longest-drifted units first, then undated drift by
+// space and unit.
+func sortUnitDrifts(drifts []UnitDrift) {
+	sort.SliceStable(drifts, func(i, j int) bool {
+		a, b := drifts[i], drifts[j]
+		if (a.Since == nil) != (b.Since == nil) {
+			return a.Since != nil
+		}
+		if a.Since != nil && !a.Since.Equal(*b.Since) {
+			return a.Since.Before(*b.Since)
+		}
+		if a.Space != b.Space {
+			return a.Space < b.Space
+		}
+		return a.Unit < b.Unit
+	})
+}
+
+func printUnitDrifts(w io.Writer, drifts []UnitDrift, spaces int) {
+	if len(drifts) == 0 {
+		fmt.Fprintf(w, "✓ No unit drift in %d space(s)\n", spaces)
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SPACE\tUNIT\tDRIFT\tREVISION\tAGE\tTARGET\tWORKER")
+	for _, d := range drifts {
+		age := "-"
+		if d.Since != nil {
+			age = formatElapsed(time.Duration(d.AgeSeconds) * time.Second)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d→%d\t%s\t%s\t%s\n",
+			d.Space, d.Unit, strings.Join(d.Kinds, ","), d.LiveRevision, d.HeadRevision, age,
+			orDash(d.Target), orDash(d.Worker))
+	}
+	tw.Flush()
+
+	for _, d := range drifts {
+		if d.Detail != "" {
+			fmt.Fprintf(w, "  %s/%s %s\n", d.Space, d.Unit, d.Detail)
+		}
+	}
+	fmt.Fprintf(w, "\n⚠ %d unit(s) drifted\n", len(drifts))
+	fmt.Fprintf(w, "%sOpen in the hub TUI: %s%s\n", colorDim, hubFocusCommand(drifts[0].Space, "unit", drifts[0].Unit), colorReset)
+}
+
+// printUnitDriftDiffs renders the content diffs of each drifted unit.
+func printUnitDriftDiffs(w io.Writer, drifts []UnitDrift, opts diff.Options) error {
+	for _, d := range drifts {
+		if len(d.diffs) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s%s/%s%s\n", colorBold, d.Space, d.Unit, colorReset)
+		for _, doc := range d.diffs {
+			if err := diff.Render(w, doc, opts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// orDash returns s, or "-" when s is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,315 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	sloFile   string
+	sloSpace  string
+	sloLabels []string
+	sloMaxLag time.Duration
+	sloJSON   bool
+)
+
+var driftSLOCmd = &cobra.Command{
+	Use:   "slo",
+	Short: "Check revision lag SLOs: how long live may trail head",
+	Long: `Check revision lag SLOs for ConfigHub units.
+
+A revision lag SLO sets how long a unit's live revision may trail its head
+revision, e.g. "prod must catch up within 15m". Lag is measured from the
+oldest unapplied revision. Units whose lag exceeds the SLO are violations,
+and the command exits 1 when there are any.
+
+SLOs come from a YAML file, scoped by space and/or unit labels:
+
+  slos:
+    - name: prod
+      space: prod
+      maxLag: 15m
+    - name: critical
+      labels:
+        tier: critical
+      maxLag: 5m
+
+or from flags for a single SLO (--max-lag with --space and/or --label).
+
+To evaluate SLOs continuously, pass the same file to
+'map export --slo' in agent mode; each run pushes an "slo" report to the
+configured sinks.
+
+Examples:
+  cub-scout drift slo --space prod --max-lag 15m
+  cub-scout drift slo --label tier=critical --max-lag 5m
+  cub-scout drift slo --file slo.yaml --json`,
+	Args: cobra.NoArgs,
+	RunE: runDriftSLO,
+}
+
+func init() {
+	driftSLOCmd.Flags().StringVarP(&sloFile, "file", "f", "", "YAML file of revision lag SLOs")
+	driftSLOCmd.Flags().StringVar(&sloSpace, "space", "", "Space for a single SLO (default: all spaces)")
+	_ = driftSLOCmd.RegisterFlagCompletionFunc("space", completeSpaces)
+	driftSLOCmd.Flags().StringArrayVar(&sloLabels, "label", nil, "Unit label for a single SLO, key=value (can be repeated)")
+	driftSLOCmd.Flags().DurationVar(&sloMaxLag, "max-lag", 0, "Maximum lag for a single SLO (e.g. 15m)")
+	driftSLOCmd.Flags().BoolVar(&sloJSON, "json", false, "Output as JSON")
+
+	driftCmd.AddCommand(driftSLOCmd)
+}
+
+// LagSLOConfig is a revision lag SLO file.
+type LagSLOConfig struct {
+	SLOs []LagSLO `json:"slos"`
+}
+
+// LagSLO bounds how long units in scope may run behind their head revision.
+// An empty space means every space; labels must all match.
+type LagSLO struct {
+	Name   string            `json:"name"`
+	Space  string            `json:"space,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	MaxLag string            `json:"maxLag"`
+
+	maxLag time.Duration
+}
+
+// LagSLOResult is one SLO's evaluation.
+type LagSLOResult struct {
+	SLO         string    `json:"slo"`
+	MaxLag      string    `json:"maxLag"`
+	EvaluatedAt time.Time `json:"evaluatedAt,omitzero"`
+	Units       int       `json:"units"`  // units in scope
+	Behind      int       `json:"behind"` // units with live behind head
+	// Violations are the units behind for longer than MaxLag, oldest first
+	Violations []UnitDrift `json:"violations"`
+}
+
+func runDriftSLO(cmd *cobra.Command, args []string) error {
+	var slos []LagSLO
+	switch {
+	case sloFile != "" && sloMaxLag > 0:
+		return fmt.Errorf("use either --file or --max-lag, not both")
+	case sloFile != "":
+		var err error
+		if slos, err = loadLagSLOs(sloFile); err != nil {
+			return err
+		}
+	case sloMaxLag > 0:
+		slo := LagSLO{Name: "cli", Space: sloSpace, MaxLag: sloMaxLag.String(), maxLag: sloMaxLag}
+		for _, l := range sloLabels {
+			k, v, ok := strings.Cut(l, "=")
+			if !ok || k == "" {
+				return fmt.Errorf("invalid --label %q (want key=value)", l)
+			}
+			if slo.Labels == nil {
+				slo.Labels = map[string]string{}
+			}
+			slo.Labels[k] = v
+		}
+		slos = []LagSLO{slo}
+	default:
+		return fmt.Errorf("no SLO: use --file, or --max-lag with --space and/or --label")
+	}
+
+	results, err := evaluateLagSLOs(slos, cubLagSource(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	if sloJSON {
+		if err := writeJSON(os.Stdout, "LagSLOResults", results); err != nil {
+			return err
+		}
+	} else {
+		printLagSLOResults(os.Stdout, results)
+	}
+	if n := countLagViolations(results); n > 0 {
+		return fmt.Errorf("%d revision lag SLO violation(s)", n)
+	}
+	return nil
+}
+
+// loadLagSLOs reads and validates an SLO file.
+func loadLagSLOs(file string) ([]LagSLO, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read SLO file: %w", err)
+	}
+	var cfg LagSLOConfig
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse SLO file %s: %w", file, err)
+	}
+	if len(cfg.SLOs) == 0 {
+		return nil, fmt.Errorf("SLO file %s defines no slos", file)
+	}
+	for i := range cfg.SLOs {
+		slo := &cfg.SLOs[i]
+		if slo.Name == "" {
+			slo.Name = fmt.Sprintf("slo-%d", i+1)
+		}
+		d, err := time.ParseDuration(slo.MaxLag)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("SLO %s: maxLag must be a positive duration like 15m, got %q", slo.Name, slo.MaxLag)
+		}
+		slo.maxLag = d
+	}
+	return cfg.SLOs, nil
+}
+
+// lagSource fetches what SLO evaluation needs from ConfigHub.
+type lagSource struct {
+	spaces    func() ([]string, error)
+	units     func(space string) ([]CubUnitData, error)
+	labels    func(space, unit string) (map[string]string, error)
+	revisions func(space, unit string) ([]ConfigHubRevision, error)
+}
+
+func cubLagSource() lagSource {
+	return lagSource{
+		spaces:    listSpaceSlugs,
+		units:     loadUnitsForSpace,
+		labels:    fetchUnitLabels,
+		revisions: fetchConfigHubRevisions,
+	}
+}
+
+// evaluateLagSLOs checks every SLO, fetching each space's units, and each
+// unit's labels and revisions, at most once. Labels are only fetched for SLOs
+// with a label selector, and revisions only for units behind head.
+func evaluateLagSLOs(slos []LagSLO, src lagSource, now time.Time) ([]LagSLOResult, error) {
+	var allSpaces []string
+	unitsBySpace := map[string][]CubUnitData{}
+	labelCache := map[string]map[string]string{}
+	driftCache := map[string]*UnitDrift{}
+
+	results := make([]LagSLOResult, 0, len(slos))
+	for _, slo := range slos {
+		spaces := []string{slo.Space}
+		if slo.Space == "" {
+			if allSpaces == nil {
+				var err error
+				if allSpaces, err = src.spaces(); err != nil {
+					return nil, fmt.Errorf("list spaces: %w", err)
+				}
+			}
+			spaces = allSpaces
+		}
+
+		result := LagSLOResult{SLO: slo.Name, MaxLag: slo.MaxLag, EvaluatedAt: now, Violations: []UnitDrift{}}
+		for _, space := range spaces {
+			units, ok := unitsBySpace[space]
+			if !ok {
+				var err error
+				if units, err = src.units(space); err != nil {
+					return nil, fmt.Errorf("list units in space %s: %w", space, err)
+				}
+				unitsBySpace[space] = units
+			}
+
+			for _, u := range units {
+				key := space + "/" + u.Unit.Slug
+				if len(slo.Labels) > 0 {
+					labels, ok := labelCache[key]
+					if !ok {
+						labels, _ = src.labels(space, u.Unit.Slug) // unreadable labels match nothing
+						labelCache[key] = labels
+					}
+					if !labelsMatch(labels, slo.Labels) {
+						continue
+					}
+				}
+				result.Units++
+
+				d, ok := driftCache[key]
+				if !ok {
+					if u.Unit.LiveRevisionNum > 0 && u.Unit.LiveRevisionNum < u.Unit.HeadRevisionNum {
+						d = newUnitDrift(space, u)
+						d.Kinds = []string{unitDriftRevision}
+						if revisions, err := src.revisions(space, u.Unit.Slug); err == nil {
+							setDriftSince(d, revisions, now)
+						}
+					}
+					driftCache[key] = d
+				}
+				if d == nil {
+					continue
+				}
+				result.Behind++
+				if d.Since != nil && now.Sub(*d.Since) > slo.maxLag {
+					result.Violations = append(result.Violations, *d)
+				}
+			}
+		}
+		sortUnitDrifts(result.Violations)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// labelsMatch reports whether labels has every key/value in selector.
+func labelsMatch(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func countLagViolations(results []LagSLOResult) int {
+	n := 0
+	for _, r := range results {
+		n += len(r.Violations)
+	}
+	return n
+}
+
+func printLagSLOResults(w io.Writer, results []LagSLOResult) {
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		icon := "✓"
+		if len(r.Violations) > 0 {
+			icon = "✗"
+		}
+		fmt.Fprintf(w, "%s SLO %s: live within %s of head — %d unit(s), %d behind, %d violating\n",
+			icon, r.SLO, r.MaxLag, r.Units, r.Behind, len(r.Violations))
+		if len(r.Violations) == 0 {
+			continue
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  SPACE\tUNIT\tREVISION\tLAG\tTARGET\tWORKER")
+		for _, d := range r.Violations {
+			fmt.Fprintf(tw, "  %s\t%s\t%d→%d\t%s\t%s\t%s\n",
+				d.Space, d.Unit, d.LiveRevision, d.HeadRevision,
+				formatElapsed(time.Duration(d.AgeSeconds)*time.Second), orDash(d.Target), orDash(d.Worker))
+		}
+		tw.Flush()
+	}
+}
+
+// sloViolationSummary lists violating units for agent-mode logs.
+func sloViolationSummary(results []LagSLOResult) []string {
+	var out []string
+	for _, r := range results {
+		for _, d := range r.Violations {
+			out = append(out, fmt.Sprintf("%s:%s/%s", r.SLO, d.Space, d.Unit))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
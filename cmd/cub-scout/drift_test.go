@@ -0,0 +1,266 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/confighub/cub-scout/pkg/diff"
+)
+
+func driftTestUnit(slug string, head, live int, drift string) CubUnitData {
+	var u CubUnitData
+	u.Unit.Slug = slug
+	u.Unit.HeadRevisionNum = head
+	u.Unit.LiveRevisionNum = live
+	u.UnitStatus.Drift = drift
+	u.Target.Slug = "prod-cluster"
+	u.BridgeWorker.Slug = "prod-worker"
+	return u
+}
+
+func TestDetectUnitDrift(t *testing.T) {
+	tests := []struct {
+		name      string
+		unit      CubUnitData
+		wantKinds []string
+	}{
+		{"in sync", driftTestUnit("a", 3, 3, "NotDrifted"), nil},
+		{"never applied", driftTestUnit("b", 3, 0, ""), nil},
+		{"not applicable", driftTestUnit("c", 1, 1, "N/A"), nil},
+		{"behind head", driftTestUnit("d", 5, 3, ""), []string{unitDriftRevision}},
+		{"status drift", driftTestUnit("e", 2, 2, "Drifted"), []string{unitDriftStatus}},
+		{"both", driftTestUnit("f", 4, 2, "Drifted"), []string{unitDriftRevision, unitDriftStatus}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := detectUnitDrift("prod", tt.unit)
+			if tt.wantKinds == nil {
+				if d != nil {
+					t.Fatalf("want no drift, got %+v", d)
+				}
+				return
+			}
+			if d == nil {
+				t.Fatal("want drift, got nil")
+			}
+			if strings.Join(d.Kinds, ",") != strings.Join(tt.wantKinds, ",") {
+				t.Errorf("kinds = %v, want %v", d.Kinds, tt.wantKinds)
+			}
+			if d.Target != "prod-cluster" || d.Worker != "prod-worker" {
+				t.Errorf("target/worker = %s/%s", d.Target, d.Worker)
+			}
+		})
+	}
+}
+
+func TestSetDriftSince(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	d := detectUnitDrift("prod", driftTestUnit("api", 6, 4, ""))
+	setDriftSince(d, []ConfigHubRevision{
+		{Num: 6, CreatedAt: now.Add(-2 * time.Hour)},
+		{Num: 5, CreatedAt: now.Add(-26 * time.Hour)},
+		{Num: 4, CreatedAt: now.Add(-72 * time.Hour)}, // live: not drift
+	}, now)
+
+	if d.Since == nil || !d.Since.Equal(now.Add(-26*time.Hour)) {
+		t.Fatalf("since = %v, want revision 5's creation", d.Since)
+	}
+	if d.AgeSeconds != 26*3600 {
+		t.Errorf("age = %ds, want %ds", d.AgeSeconds, 26*3600)
+	}
+}
+
+func TestContentDrift(t *testing.T) {
+	desired := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: prod
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: api
+        image: api:1.2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: prod
+`)
+	live := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: prod
+  uid: 1234
+spec:
+  replicas: 2
+  progressDeadlineSeconds: 600
+  template:
+    spec:
+      containers:
+      - image: api:1.2
+        name: api
+        imagePullPolicy: IfNotPresent
+status:
+  readyReplicas: 2
+`)
+
+	detail, diffs, err := contentDrift(desired, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail != "missing: Service/prod/api" {
+		t.Errorf("added fields should not count as drift, got %q", detail)
+	}
+	if len(diffs) != 1 || diffs[0].Name != "Service/prod/api" || diffs[0].To != nil {
+		t.Errorf("diffs = %+v, want only the missing service with no live side", diffs)
+	}
+
+	changed := bytes.Replace(live, []byte("replicas: 2\n  progress"), []byte("replicas: 5\n  progress"), 1)
+	detail, diffs, err = contentDrift(desired, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(detail, "changed: Deployment/prod/api") {
+		t.Errorf("detail = %q, want the scaled deployment reported", detail)
+	}
+	var buf bytes.Buffer
+	if err := printUnitDriftDiffs(&buf, []UnitDrift{{Space: "prod", Unit: "api", diffs: diffs}}, diff.Options{Format: diff.Unified}); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "-  replicas: 2") || !strings.Contains(out, "+  replicas: 5") || strings.Contains(out, "readyReplicas") {
+		t.Errorf("diff should show the replica change and nothing the cluster added:\n%s", out)
+	}
+
+	if detail, _, _ := contentDrift(desired[:bytes.Index(desired, []byte("---"))], live); detail != "" {
+		t.Errorf("matching content reported as drift: %q", detail)
+	}
+}
+
+func TestPrintUnitDriftsOldestFirst(t *testing.T) {
+	now := time.Now()
+	old, recent := now.Add(-48*time.Hour), now.Add(-time.Hour)
+	drifts := []UnitDrift{
+		{Space: "prod", Unit: "checkout", Kinds: []string{unitDriftStatus}, HeadRevision: 7, LiveRevision: 7},
+		{Space: "prod", Unit: "web", Kinds: []string{unitDriftRevision}, HeadRevision: 3, LiveRevision: 2, Since: &recent, AgeSeconds: 3600},
+		{Space: "prod", Unit: "api", Kinds: []string{unitDriftRevision}, HeadRevision: 6, LiveRevision: 4, Since: &old, AgeSeconds: 48 * 3600, Target: "prod-cluster"},
+	}
+	sortUnitDrifts(drifts)
+	if drifts[0].Unit != "api" || drifts[1].Unit != "web" || drifts[2].Unit != "checkout" {
+		t.Fatalf("order = %s, %s, %s", drifts[0].Unit, drifts[1].Unit, drifts[2].Unit)
+	}
+
+	var buf bytes.Buffer
+	printUnitDrifts(&buf, drifts, 1)
+	out := buf.String()
+	for _, want := range []string{"4→6", "2d 0h", "prod-cluster", "3 unit(s) drifted"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printUnitDrifts(&buf, nil, 2)
+	if !strings.Contains(buf.String(), "No unit drift in 2 space(s)") {
+		t.Errorf("empty output = %q", buf.String())
+	}
+}
+
+func TestLoadLagSLOs(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "slo.yaml")
+	if err := os.WriteFile(file, []byte("slos:\n  - name: prod\n    space: prod\n    maxLag: 15m\n  - labels:\n      tier: critical\n    maxLag: 5m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	slos, err := loadLagSLOs(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slos) != 2 || slos[0].maxLag != 15*time.Minute || slos[1].Name != "slo-2" || slos[1].Labels["tier"] != "critical" {
+		t.Errorf("slos = %+v", slos)
+	}
+
+	for _, bad := range []string{"slos: []\n", "slos:\n  - name: x\n    maxLag: soon\n", "slos:\n  - name: x\n    maxLag: 5m\n    typo: 1\n"} {
+		if err := os.WriteFile(file, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadLagSLOs(file); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestEvaluateLagSLOs(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	units := map[string][]CubUnitData{
+		"prod": {
+			driftTestUnit("api", 6, 4, ""), // behind for 2h
+			driftTestUnit("web", 3, 2, ""), // behind for 5m
+			driftTestUnit("db", 2, 2, ""),  // in sync
+			driftTestUnit("new", 1, 0, ""), // never applied
+		},
+		"dev": {driftTestUnit("api", 9, 1, "")}, // behind for 2h
+	}
+	created := map[string]time.Time{
+		"prod/api": now.Add(-2 * time.Hour),
+		"prod/web": now.Add(-5 * time.Minute),
+		"dev/api":  now.Add(-2 * time.Hour),
+	}
+	var labelCalls, revisionCalls int
+	src := lagSource{
+		spaces: func() ([]string, error) { return []string{"dev", "prod"}, nil },
+		units:  func(space string) ([]CubUnitData, error) { return units[space], nil },
+		labels: func(space, unit string) (map[string]string, error) {
+			labelCalls++
+			if unit == "api" {
+				return map[string]string{"tier": "critical"}, nil
+			}
+			return nil, nil
+		},
+		revisions: func(space, unit string) ([]ConfigHubRevision, error) {
+			revisionCalls++
+			return []ConfigHubRevision{{Num: 99, CreatedAt: created[space+"/"+unit]}}, nil
+		},
+	}
+
+	results, err := evaluateLagSLOs([]LagSLO{
+		{Name: "prod", Space: "prod", MaxLag: "15m", maxLag: 15 * time.Minute},
+		{Name: "critical", Labels: map[string]string{"tier": "critical"}, MaxLag: "1h", maxLag: time.Hour},
+	}, src, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prod := results[0]
+	if prod.Units != 4 || prod.Behind != 2 || len(prod.Violations) != 1 || prod.Violations[0].Unit != "api" {
+		t.Errorf("prod = %+v", prod)
+	}
+	if prod.Violations[0].AgeSeconds != 7200 || prod.Violations[0].Target != "prod-cluster" {
+		t.Errorf("violation = %+v", prod.Violations[0])
+	}
+
+	critical := results[1]
+	if critical.Units != 2 || len(critical.Violations) != 2 {
+		t.Errorf("critical = %+v", critical)
+	}
+	if labelCalls != 5 {
+		t.Errorf("labels fetched %d times, want once per unit (5)", labelCalls)
+	}
+	if revisionCalls != 3 {
+		t.Errorf("revisions fetched %d times, want once per unit behind (3)", revisionCalls)
+	}
+	if got := sloViolationSummary(results); strings.Join(got, " ") != "critical:dev/api critical:prod/api prod:prod/api" {
+		t.Errorf("summary = %v", got)
+	}
+}
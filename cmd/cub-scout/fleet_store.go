@@ -0,0 +1,340 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// FleetStore is a directory holding the latest GSF snapshot for each cluster.
+// Snapshots are stored as <cluster>.json so pushing again from the same
+// cluster replaces its previous state.
+type FleetStore struct {
+	Dir string
+}
+
+// FleetInventoryEntry is one workload in the cross-cluster fleet inventory.
+type FleetInventoryEntry struct {
+	App         string    `json:"app"`
+	Variant     string    `json:"variant,omitempty"`
+	Space       string    `json:"space,omitempty"`
+	Cluster     string    `json:"cluster"`
+	Environment string    `json:"environment,omitempty"`
+	Namespace   string    `json:"namespace"`
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	Owner       string    `json:"owner"`
+	SnapshotAt  time.Time `json:"snapshotAt"`
+}
+
+var (
+	fleetFromStore bool
+	fleetStorePath string
+	snapshotPush   bool
+)
+
+var mapFleetPushCmd = &cobra.Command{
+	Use:   "push [snapshot.json...]",
+	Short: "Add cluster snapshots to the local fleet store",
+	Long: `Add GSF snapshots from one or more clusters to the fleet store.
+
+Each snapshot replaces the previous one for the same cluster. Use "-" to read
+a snapshot from stdin. Snapshots are produced by "cub-scout snapshot", or
+pushed directly with "cub-scout snapshot --push". Each cluster is named by
+the cluster registry (~/.cub-scout/clusters.yaml), by CLUSTER_NAME, or from
+its kubeconfig context; snapshots pushed under a registered context name are
+listed under the cluster's canonical name, with its environment.
+
+Examples:
+  # Collect snapshots from several clusters
+  for ctx in prod-east prod-west; do
+    kubectl config use-context $ctx
+    CLUSTER_NAME=$ctx cub-scout snapshot -o $ctx.json
+  done
+  cub-scout map fleet push prod-east.json prod-west.json
+
+  # Query the aggregated inventory
+  cub-scout map fleet --from-store --app payment-api`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runMapFleetPush,
+}
+
+func init() {
+	mapFleetCmd.AddCommand(mapFleetPushCmd)
+	mapFleetCmd.PersistentFlags().StringVar(&fleetStorePath, "store-dir", "", "Fleet store directory (default: $CUB_SCOUT_FLEET_STORE or ~/.cub-scout/fleet)")
+	mapFleetCmd.Flags().BoolVar(&fleetFromStore, "from-store", false, "Show inventory aggregated from cluster snapshots in the fleet store")
+
+	snapshotCmd.Flags().BoolVar(&snapshotPush, "push", false, "Also save the snapshot to the local fleet store")
+	snapshotCmd.Flags().StringVar(&fleetStorePath, "store-dir", "", "Fleet store directory used with --push")
+}
+
+// openFleetStore returns the fleet store at dir, falling back to
+// $CUB_SCOUT_FLEET_STORE and then ~/.cub-scout/fleet.
+func openFleetStore(dir string) FleetStore {
+	if dir == "" {
+		dir = os.Getenv("CUB_SCOUT_FLEET_STORE")
+	}
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".cub-scout", "fleet")
+	}
+	return FleetStore{Dir: dir}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Save writes snap to the store, replacing any earlier snapshot of the same
+// cluster. Snapshots named "default", the name used when the context could
+// not be identified, are refused: every such cluster would share one file.
+func (s FleetStore) Save(snap *GSFSnapshot) (string, error) {
+	switch snap.Cluster {
+	case "":
+		return "", fmt.Errorf("snapshot has no cluster name (set CLUSTER_NAME when running cub-scout snapshot)")
+	case "default":
+		return "", fmt.Errorf("snapshot cluster name is \"default\", which would overwrite other unnamed clusters (set CLUSTER_NAME or register the context in %s)", ClusterConfigFile())
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("create fleet store: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	path := filepath.Join(s.Dir, unsafeFileChars.ReplaceAllString(snap.Cluster, "_")+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("write snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads every cluster snapshot in the store, sorted by cluster name.
+// A missing store directory is treated as empty.
+func (s FleetStore) Load() ([]GSFSnapshot, error) {
+	files, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var snaps []GSFSnapshot
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", f, err)
+		}
+		var snap GSFSnapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", f, err)
+		}
+		snaps = append(snaps, snap)
+	}
+
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Cluster < snaps[j].Cluster })
+	return snaps, nil
+}
+
+func runMapFleetPush(cmd *cobra.Command, args []string) error {
+	store := openFleetStore(fleetStorePath)
+
+	for _, arg := range args {
+		var data []byte
+		var err error
+		if arg == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(arg)
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", arg, err)
+		}
+
+		var snap GSFSnapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return fmt.Errorf("parse %s: %w", arg, err)
+		}
+		if snap.Version == "" || !strings.HasPrefix(snap.Version, "gsf/") {
+			return fmt.Errorf("%s is not a GSF snapshot (run: cub-scout snapshot -o file.json)", arg)
+		}
+
+		path, err := store.Save(&snap)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s: %d entries → %s\n", snap.Cluster, len(snap.Entries), path)
+	}
+	return nil
+}
+
+// fleetWorkloadKinds are the GSF entry kinds included in the fleet inventory.
+var fleetWorkloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+// buildFleetInventory flattens cluster snapshots into workload entries with
+// inferred app, variant and space, filtered by app and space when set.
+// Cluster names are mapped to their canonical names in registry, so
+// snapshots pushed under a context name join the same cluster, and a
+// workload without a variant takes its cluster's environment.
+func buildFleetInventory(snaps []GSFSnapshot, registry ClusterConfig, appFilter, spaceFilter string) []FleetInventoryEntry {
+	var inventory []FleetInventoryEntry
+	for _, snap := range snaps {
+		for _, e := range snap.Entries {
+			if !fleetWorkloadKinds[e.Kind] || isSystemNamespace(e.Namespace) {
+				continue
+			}
+
+			cluster := e.Cluster
+			if cluster == "" {
+				cluster = snap.Cluster
+			}
+			registered := registry.Canonical(cluster)
+			env := snap.Environment
+			if env == "" {
+				env = registered.Environment
+			}
+
+			ownerType := ""
+			space := e.Labels["confighub.com/SpaceName"]
+			if e.Owner != nil {
+				ownerType = e.Owner.Type
+				if e.Owner.Type == "confighub" && e.Owner.Namespace != "" {
+					space = e.Owner.Namespace
+				}
+			}
+
+			app, variant := inferAppAndVariant(WorkloadInfo{
+				Kind:      e.Kind,
+				Namespace: e.Namespace,
+				Name:      e.Name,
+				Labels:    e.Labels,
+			})
+			if variant == "" {
+				variant = env
+			}
+
+			if appFilter != "" && app != appFilter {
+				continue
+			}
+			if spaceFilter != "" && space != spaceFilter {
+				continue
+			}
+
+			inventory = append(inventory, FleetInventoryEntry{
+				App:         app,
+				Variant:     variant,
+				Space:       space,
+				Cluster:     registered.Name,
+				Environment: env,
+				Namespace:   e.Namespace,
+				Kind:        e.Kind,
+				Name:        e.Name,
+				Owner:       displayOwner(ownerType),
+				SnapshotAt:  snap.GeneratedAt,
+			})
+		}
+	}
+
+	sort.Slice(inventory, func(i, j int) bool {
+		a, b := inventory[i], inventory[j]
+		if a.App != b.App {
+			return a.App < b.App
+		}
+		if a.Variant != b.Variant {
+			return a.Variant < b.Variant
+		}
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return inventory
+}
+
+func runMapFleetStore() error {
+	store := openFleetStore(fleetStorePath)
+	snaps, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("load fleet store: %w", err)
+	}
+
+	if len(snaps) == 0 {
+		fmt.Printf("No cluster snapshots in fleet store %s\n", store.Dir)
+		fmt.Println("\nTo populate the fleet store, run in each cluster:")
+		fmt.Println("  CLUSTER_NAME=<cluster> cub-scout snapshot --push")
+		fmt.Println("or push existing snapshots:")
+		fmt.Println("  cub-scout map fleet push cluster-a.json cluster-b.json")
+		return nil
+	}
+
+	registry := activeClusterConfig()
+	inventory := buildFleetInventory(snaps, registry, fleetApp, fleetSpace)
+
+	if mapJSON {
+		return writeJSON(os.Stdout, "FleetInventory", inventory)
+	}
+
+	fmt.Println("Fleet Inventory (from cluster snapshots)")
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tENVIRONMENT\tSNAPSHOT AGE\tENTRIES")
+	now := time.Now()
+	for _, snap := range snaps {
+		c := registry.Canonical(snap.Cluster)
+		env := snap.Environment
+		if env == "" {
+			env = c.Environment
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", c.Name, orDash(env), formatDuration(now.Sub(snap.GeneratedAt)), len(snap.Entries))
+	}
+	w.Flush()
+	fmt.Println()
+
+	if len(inventory) == 0 {
+		fmt.Println("No workloads match the given filters.")
+		return nil
+	}
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APP\tVARIANT\tCLUSTER\tNAMESPACE\tWORKLOAD\tOWNER\tSPACE")
+	clusters := map[string]bool{}
+	apps := map[string]bool{}
+	for _, e := range inventory {
+		clusters[e.Cluster] = true
+		apps[e.App] = true
+		variant := e.Variant
+		if variant == "" {
+			variant = "-"
+		}
+		space := e.Space
+		if space == "" {
+			space = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s/%s\t%s\t%s\n", e.App, variant, e.Cluster, e.Namespace, e.Kind, e.Name, e.Owner, space)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d workloads, %d apps across %d clusters\n", len(inventory), len(apps), len(clusters))
+	return nil
+}
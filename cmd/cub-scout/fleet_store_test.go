@@ -0,0 +1,108 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFleetStoreSaveLoad(t *testing.T) {
+	store := FleetStore{Dir: t.TempDir()}
+
+	first := &GSFSnapshot{Version: "gsf/v1", Cluster: "prod/west", Entries: []GSFEntry{{Name: "old"}}}
+	if _, err := store.Save(first); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	// Pushing the same cluster again replaces the earlier snapshot.
+	second := &GSFSnapshot{Version: "gsf/v1", Cluster: "prod/west", Entries: []GSFEntry{{Name: "new"}}}
+	if _, err := store.Save(second); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := store.Save(&GSFSnapshot{Version: "gsf/v1", Cluster: "dev"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	snaps, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(snaps))
+	}
+	if snaps[0].Cluster != "dev" || snaps[1].Cluster != "prod/west" {
+		t.Errorf("unexpected cluster order: %s, %s", snaps[0].Cluster, snaps[1].Cluster)
+	}
+	if snaps[1].Entries[0].Name != "new" {
+		t.Errorf("expected latest snapshot to win, got %q", snaps[1].Entries[0].Name)
+	}
+
+	if _, err := store.Save(&GSFSnapshot{Version: "gsf/v1"}); err == nil {
+		t.Error("expected error for snapshot without cluster name")
+	}
+	if _, err := store.Save(&GSFSnapshot{Version: "gsf/v1", Cluster: "default"}); err == nil {
+		t.Error("expected error for snapshot under the fallback cluster name")
+	}
+}
+
+func TestFleetStoreLoadMissingDir(t *testing.T) {
+	snaps, err := FleetStore{Dir: t.TempDir() + "/missing"}.Load()
+	if err != nil || len(snaps) != 0 {
+		t.Errorf("expected empty store, got %v, %v", snaps, err)
+	}
+}
+
+func TestBuildFleetInventory(t *testing.T) {
+	at := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	snaps := []GSFSnapshot{
+		{Cluster: "east", GeneratedAt: at, Entries: []GSFEntry{
+			{Cluster: "east", Namespace: "payments", Kind: "Deployment", Name: "api",
+				Labels: map[string]string{"app.kubernetes.io/name": "payment-api", "environment": "prod"},
+				Owner:  &GSFOwner{Type: "confighub", Name: "payment-api", Namespace: "payments-team"}},
+			{Cluster: "east", Namespace: "payments", Kind: "Service", Name: "api"},
+			{Cluster: "east", Namespace: "kube-system", Kind: "DaemonSet", Name: "kube-proxy"},
+		}},
+		{Cluster: "west", GeneratedAt: at, Entries: []GSFEntry{
+			{Namespace: "payments", Kind: "Deployment", Name: "api",
+				Labels: map[string]string{"app.kubernetes.io/name": "payment-api", "environment": "prod"},
+				Owner:  &GSFOwner{Type: "flux", Name: "apps"}},
+			{Namespace: "web", Kind: "StatefulSet", Name: "cache", Labels: map[string]string{"app": "cache"}},
+		}},
+	}
+
+	all := buildFleetInventory(snaps, ClusterConfig{}, "", "")
+	if len(all) != 3 {
+		t.Fatalf("expected 3 workloads, got %d: %+v", len(all), all)
+	}
+
+	byApp := buildFleetInventory(snaps, ClusterConfig{}, "payment-api", "")
+	if len(byApp) != 2 || byApp[0].Cluster != "east" || byApp[1].Cluster != "west" {
+		t.Fatalf("unexpected app filter result: %+v", byApp)
+	}
+	if byApp[0].Space != "payments-team" || byApp[0].Owner != "ConfigHub" || byApp[0].Variant != "prod" {
+		t.Errorf("unexpected east entry: %+v", byApp[0])
+	}
+	if byApp[1].Owner != "Flux" {
+		t.Errorf("west owner = %s, want Flux", byApp[1].Owner)
+	}
+
+	bySpace := buildFleetInventory(snaps, ClusterConfig{}, "", "payments-team")
+	if len(bySpace) != 1 || bySpace[0].Cluster != "east" {
+		t.Errorf("unexpected space filter result: %+v", bySpace)
+	}
+	// A registry renames context-named snapshots and fills in environments
+	registry := ClusterConfig{Clusters: []ClusterEntry{
+		{Context: "west", Name: "prod-west", Environment: "prod"},
+	}}
+	renamed := buildFleetInventory(snaps, registry, "", "")
+	var cache FleetInventoryEntry
+	for _, e := range renamed {
+		if e.Name == "cache" {
+			cache = e
+		}
+	}
+	if cache.Cluster != "prod-west" || cache.Environment != "prod" || cache.Variant != "prod" {
+		t.Errorf("registered cluster entry = %+v, want prod-west with environment and variant prod", cache)
+	}
+}
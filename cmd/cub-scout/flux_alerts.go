@@ -0,0 +1,86 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+// printFluxAlerting prints the Flux Alerts and Receivers and the
+// Kustomizations and HelmReleases whose failures no Alert forwards.
+func printFluxAlerting(w io.Writer, a agent.FluxAlerting) {
+	if !a.Installed {
+		if len(a.Coverage) > 0 {
+			fmt.Fprintf(w, "\nALERTING: notification-controller not installed; failures of all %d Kustomizations/HelmReleases are silent\n", len(a.Coverage))
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "\nALERTING (%d Alerts, %d Providers)\n", len(a.Alerts), a.Providers)
+	if len(a.Alerts) > 0 {
+		sortResources(a.Alerts, func(al agent.FluxAlert) (string, string, string) { return al.Namespace, al.Kind, al.Name })
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "STATUS\tALERT\tNAMESPACE\tPROVIDER\tSEVERITY\tSOURCES")
+		fmt.Fprintln(tw, "──────\t─────\t─────────\t────────\t────────\t───────")
+		for _, al := range a.Alerts {
+			status := "✓"
+			switch {
+			case al.Suspended:
+				status = "⏸"
+			case !al.Delivers():
+				status = "✗"
+			}
+			provider := al.Provider
+			if al.ProviderType != "" {
+				provider += " (" + al.ProviderType + ")"
+			}
+			if !al.Delivers() && !al.Suspended {
+				provider = al.Problem
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", status, al.Name, al.Namespace, provider, al.Severity, truncate(al.SourcesSummary(), 60))
+		}
+		tw.Flush()
+	}
+
+	if len(a.Receivers) > 0 {
+		sortResources(a.Receivers, func(r agent.FluxReceiver) (string, string, string) { return r.Namespace, r.Kind, r.Name })
+		fmt.Fprintf(w, "\nRECEIVERS (webhooks triggering reconciliation)\n")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "STATUS\tRECEIVER\tNAMESPACE\tTYPE\tRESOURCES")
+		fmt.Fprintln(tw, "──────\t────────\t─────────\t────\t─────────")
+		for _, r := range a.Receivers {
+			status := "✓"
+			if !r.Ready {
+				status = "✗"
+			}
+			resources := make([]string, 0, len(r.Resources))
+			for _, res := range r.Resources {
+				resources = append(resources, res.String())
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", status, r.Name, r.Namespace, r.Type, truncate(strings.Join(resources, ", "), 60))
+		}
+		tw.Flush()
+	}
+
+	silent := a.Silent()
+	if len(silent) == 0 {
+		if len(a.Coverage) > 0 {
+			fmt.Fprintf(w, "\n✓ Failures of all %d Kustomizations/HelmReleases are alerted\n", len(a.Coverage))
+		}
+		return
+	}
+	fmt.Fprintf(w, "\n⚠ %d of %d Kustomizations/HelmReleases not covered by any Alert (failures are silent):\n", len(silent), len(a.Coverage))
+	for _, c := range silent {
+		status := "ready"
+		if !c.Ready {
+			status = "✗ failing now"
+		}
+		fmt.Fprintf(w, "  %s/%s/%s (%s)\n", c.Namespace, c.Kind, c.Name, status)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+func TestPrintFluxAlerting(t *testing.T) {
+	ref := func(kind, ns, name string) agent.FluxObjectRef {
+		return agent.FluxObjectRef{Kind: kind, Namespace: ns, Name: name}
+	}
+	alerting := agent.FluxAlerting{
+		Installed: true,
+		Providers: 1,
+		Alerts: []agent.FluxAlert{
+			{FluxObjectRef: ref("Alert", "flux-system", "infra"), Provider: "slack", ProviderType: "slack", Severity: "error",
+				Sources: []agent.FluxEventSource{{Kind: "Kustomization", Name: "*", Namespace: "flux-system"}}},
+			{FluxObjectRef: ref("Alert", "flux-system", "apps"), Provider: "slak", Severity: "info", Problem: "provider slak not found",
+				Sources: []agent.FluxEventSource{{Kind: "HelmRelease", Name: "*", Namespace: "apps"}}},
+		},
+		Receivers: []agent.FluxReceiver{
+			{FluxObjectRef: ref("Receiver", "flux-system", "github"), Type: "github", Ready: true,
+				Resources: []agent.FluxEventSource{{Kind: "GitRepository", Name: "flux-system", Namespace: "flux-system"}}},
+		},
+		Coverage: []agent.FluxAlertCoverage{
+			{FluxObjectRef: ref("HelmRelease", "apps", "redis")},
+			{FluxObjectRef: ref("Kustomization", "flux-system", "infra"), Ready: true, Alerts: []string{"flux-system/infra"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	printFluxAlerting(&buf, alerting)
+	out := buf.String()
+	for _, want := range []string{
+		"ALERTING (2 Alerts, 1 Providers)",
+		"slack (slack)",
+		"provider slak not found",
+		"apps/*",
+		"RECEIVERS",
+		"GitRepository/flux-system/flux-system",
+		"⚠ 1 of 2 Kustomizations/HelmReleases not covered by any Alert",
+		"apps/HelmRelease/redis (✗ failing now)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printFluxAlerting(&buf, agent.FluxAlerting{Coverage: alerting.Coverage})
+	if out := buf.String(); !strings.Contains(out, "notification-controller not installed; failures of all 2") {
+		t.Errorf("not installed:\n%s", out)
+	}
+}
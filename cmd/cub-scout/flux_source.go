@@ -0,0 +1,134 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+// fluxSourceSummary renders a deployer's source for map views, e.g.
+// "flux-system/GitRepository/platform (cross-namespace, not ready: auth failed)".
+func fluxSourceSummary(ctx context.Context, flux *agent.FluxResolver, deployer *unstructured.Unstructured) string {
+	ref, err := agent.FluxSourceRef(deployer)
+	if errors.Is(err, agent.ErrNoFluxSource) {
+		return "-"
+	}
+	if err != nil {
+		return "invalid: " + err.Error()
+	}
+
+	var notes []string
+	if ref.CrossNamespace {
+		notes = append(notes, "cross-namespace")
+	}
+	src, err := flux.Source(ctx, deployer)
+	switch {
+	case err != nil:
+		notes = append(notes, "unreadable: "+err.Error())
+	case src.Missing:
+		notes = append(notes, "not found")
+	case src.Suspended:
+		notes = append(notes, "suspended")
+	case !src.Ready:
+		notes = append(notes, "not ready: "+src.Message)
+	}
+	s := ref.Namespace + "/" + ref.Kind + "/" + ref.Name
+	if len(notes) > 0 {
+		s += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return s
+}
+
+// printFluxDependsOn prints a deployer's dependsOn chain, one indented line
+// per dependency, after a "DependsOn:" label aligned with the other fields.
+func printFluxDependsOn(ctx context.Context, w io.Writer, flux *agent.FluxResolver, deployer *unstructured.Unstructured) {
+	deps, err := flux.Dependencies(ctx, deployer)
+	if len(deps) == 0 && err == nil {
+		return
+	}
+	fmt.Fprintf(w, "  DependsOn:\n")
+	for _, d := range deps {
+		status := "✓"
+		switch {
+		case d.Cycle:
+			status = "✗ cycle"
+		case d.Missing:
+			status = "✗ not found"
+		case d.Suspended:
+			status = "⏸ suspended"
+		case !d.Ready:
+			status = "✗ " + d.Message
+		}
+		fmt.Fprintf(w, "    %s%s/%s %s\n", strings.Repeat("  ", d.Depth-1), d.Namespace, d.Name, status)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "    (%v)\n", err)
+	}
+}
+
+// fluxSourceHealthIndex keys source health by reference, so deployers can
+// look up the Bucket or HelmRepository they consume.
+func fluxSourceHealthIndex(sources []agent.FluxSourceHealth) map[agent.FluxObjectRef]agent.FluxSourceHealth {
+	index := map[agent.FluxObjectRef]agent.FluxSourceHealth{}
+	for _, s := range sources {
+		index[s.FluxObjectRef] = s
+	}
+	return index
+}
+
+// unhealthyFluxSource returns the Bucket or HelmRepository a deployer
+// consumes when it isn't healthy.
+func unhealthyFluxSource(index map[agent.FluxObjectRef]agent.FluxSourceHealth, deployer *unstructured.Unstructured) (agent.FluxSourceHealth, bool) {
+	ref, err := agent.FluxSourceRef(deployer)
+	if err != nil {
+		return agent.FluxSourceHealth{}, false
+	}
+	ref.CrossNamespace = false
+	s, ok := index[ref]
+	if !ok || s.Healthy() {
+		return agent.FluxSourceHealth{}, false
+	}
+	return s, true
+}
+
+// printFluxSourceHealth prints the Buckets and HelmRepositories with their
+// last-fetched age and fetch error.
+func printFluxSourceHealth(w io.Writer, sources []agent.FluxSourceHealth, now time.Time) {
+	sortResources(sources, func(s agent.FluxSourceHealth) (string, string, string) { return s.Namespace, s.Kind, s.Name })
+	fmt.Fprintf(w, "\nSOURCES (Buckets and HelmRepositories)\n")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tKIND\tNAME\tNAMESPACE\tPROVIDER\tFETCHED\tERROR")
+	fmt.Fprintln(tw, "──────\t────\t────\t─────────\t────────\t───────\t─────")
+	for _, s := range sources {
+		status, errMsg := "✓", "-"
+		switch {
+		case s.Suspended:
+			status, errMsg = "⏸", "suspended"
+		case !s.Ready:
+			status, errMsg = "✗", truncate(s.Message, 60)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", status, s.Kind, s.Name, s.Namespace, s.Provider, s.ShortAge(now), errMsg)
+	}
+	tw.Flush()
+}
+
+func countUnhealthySources(sources []agent.FluxSourceHealth) int {
+	n := 0
+	for _, s := range sources {
+		if !s.Healthy() {
+			n++
+		}
+	}
+	return n
+}
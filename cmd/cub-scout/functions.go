@@ -0,0 +1,364 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	functionsSpaces []string
+	functionsJSON   bool
+)
+
+var functionsCmd = &cobra.Command{
+	Use:   "functions",
+	Short: "Show the functions ConfigHub triggers run on units",
+	Long: `Show ConfigHub triggers: functions that run on every unit of a space
+when it changes. A mutating function rewrites the unit's data as it is
+saved; a validating function checks it, and a failing check gates the
+unit's apply.`,
+}
+
+var functionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List triggers, the functions they run, and when they last ran",
+	Long: `List the triggers in each space, the function each runs with its
+arguments, and its last execution:
+
+  validating  passing, or failing with the units whose apply it gates
+  mutating    the newest unit revision the function made
+
+Use it to find out why a unit's data changed after it was saved. A
+mutating run is recognized by a revision whose source or description
+names the trigger or its function.
+
+Examples:
+  cub-scout functions list
+  cub-scout functions list --space prod
+  cub-scout functions list --json`,
+	Args: cobra.NoArgs,
+	RunE: runFunctionsList,
+}
+
+func init() {
+	functionsListCmd.Flags().StringSliceVar(&functionsSpaces, "space", nil, "Spaces to list (default: all spaces)")
+	_ = functionsListCmd.RegisterFlagCompletionFunc("space", completeSpaces)
+	functionsListCmd.Flags().BoolVar(&functionsJSON, "json", false, "Output as JSON")
+	functionsCmd.AddCommand(functionsListCmd)
+	rootCmd.AddCommand(functionsCmd)
+}
+
+// TriggerInfo is a trigger and its last execution.
+type TriggerInfo struct {
+	Space      string   `json:"space"`
+	Slug       string   `json:"slug"`
+	Event      string   `json:"event"`
+	Function   string   `json:"function"`
+	Arguments  []string `json:"arguments,omitempty"`
+	Toolchain  string   `json:"toolchain,omitempty"`
+	Validating bool     `json:"validating"`
+	Disabled   bool     `json:"disabled,omitempty"`
+	// Blocking lists the units whose apply a failing validating trigger
+	// gates
+	Blocking []string `json:"blocking,omitempty"`
+	// LastRun is the newest unit revision a mutating trigger made
+	LastRun *TriggerRun `json:"lastRun,omitempty"`
+}
+
+// TriggerRun is a unit revision made by a trigger.
+type TriggerRun struct {
+	Unit        string    `json:"unit"`
+	Revision    int       `json:"revision"`
+	At          time.Time `json:"at"`
+	Description string    `json:"description,omitempty"`
+}
+
+// Status is the trigger's last execution, in words.
+func (t TriggerInfo) Status(now time.Time) string {
+	switch {
+	case t.Disabled:
+		return "disabled"
+	case t.Validating && len(t.Blocking) > 0:
+		return fmt.Sprintf("failing on %d unit(s)", len(t.Blocking))
+	case t.Validating:
+		return "passing"
+	case t.LastRun != nil:
+		return fmt.Sprintf("changed %s rev %d %s ago", t.LastRun.Unit, t.LastRun.Revision, formatElapsed(now.Sub(t.LastRun.At)))
+	}
+	return "no changes found"
+}
+
+// validatingFunctions are ConfigHub's built-in checks. A trigger is
+// validating when cub says so, or when it runs one of these.
+var validatingFunctions = []string{"vet-", "is-approved", "no-placeholders", "cel-validate"}
+
+func triggerValidating(d CubTriggerData) bool {
+	if d.Trigger.Validating {
+		return true
+	}
+	for _, f := range validatingFunctions {
+		if strings.HasPrefix(d.Trigger.FunctionName, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesTo reports whether the trigger runs on u: triggers run on every
+// unit of their space with the same toolchain.
+func (t TriggerInfo) appliesTo(u CubUnitData) bool {
+	return t.Toolchain == "" || u.Unit.ToolchainType == "" || t.Toolchain == u.Unit.ToolchainType
+}
+
+// madeBy reports whether rev was made by the trigger.
+func (t TriggerInfo) madeBy(rev ConfigHubRevision) bool {
+	text := strings.ToLower(rev.Source + " " + rev.Description)
+	return strings.Contains(text, strings.ToLower(t.Slug)) || (t.Function != "" && strings.Contains(text, strings.ToLower(t.Function)))
+}
+
+// gates reports whether the trigger gates u's apply. Gates are named after
+// the trigger that set them.
+func (t TriggerInfo) gates(u CubUnitData) bool {
+	for _, gate := range u.ApplyGateNames() {
+		if gateDisplayName(gate) == t.Slug {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTriggerInfos joins a space's triggers with its units and, for
+// mutating triggers, the units' revisions by unit slug.
+func buildTriggerInfos(space string, triggers []CubTriggerData, units []CubUnitData, revisions map[string][]ConfigHubRevision) []TriggerInfo {
+	var out []TriggerInfo
+	for _, d := range triggers {
+		t := TriggerInfo{
+			Space:      space,
+			Slug:       d.Trigger.Slug,
+			Event:      d.Trigger.Event,
+			Function:   d.Trigger.FunctionName,
+			Toolchain:  d.Trigger.ToolchainType,
+			Validating: triggerValidating(d),
+			Disabled:   d.Trigger.Disabled,
+		}
+		for _, a := range d.Trigger.Arguments {
+			t.Arguments = append(t.Arguments, fmt.Sprint(a.Value))
+		}
+		for _, u := range units {
+			if !t.appliesTo(u) {
+				continue
+			}
+			if t.Validating {
+				if t.gates(u) {
+					t.Blocking = append(t.Blocking, u.Unit.Slug)
+				}
+				continue
+			}
+			for _, rev := range revisions[u.Unit.Slug] {
+				if !t.madeBy(rev) || (t.LastRun != nil && !rev.CreatedAt.After(t.LastRun.At)) {
+					continue
+				}
+				t.LastRun = &TriggerRun{Unit: u.Unit.Slug, Revision: rev.Num, At: rev.CreatedAt, Description: rev.Description}
+			}
+		}
+		sort.Strings(t.Blocking)
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Slug < out[j].Slug })
+	return out
+}
+
+func loadTriggersForSpace(spaceSlug string) ([]CubTriggerData, error) {
+	out, err := runCubCommand("trigger", "list", "--space", spaceSlug, "--json")
+	if err != nil {
+		return nil, err
+	}
+	var triggers []CubTriggerData
+	if err := json.Unmarshal(out, &triggers); err != nil {
+		return nil, err
+	}
+	return triggers, nil
+}
+
+// collectTriggers lists the triggers in spaces. Units are listed only for
+// spaces with triggers, and revisions only for spaces with a mutating one,
+// fetched through spaceLoadPool.
+func collectTriggers(ctx context.Context, spaces []string) ([]TriggerInfo, error) {
+	var all []TriggerInfo
+	for _, space := range spaces {
+		triggers, err := loadTriggersForSpace(space)
+		if err != nil {
+			return nil, fmt.Errorf("list triggers in space %s: %w", space, err)
+		}
+		if len(triggers) == 0 {
+			continue
+		}
+		units, err := loadUnitsForSpace(space)
+		if err != nil {
+			return nil, fmt.Errorf("list units in space %s: %w", space, err)
+		}
+		var revisions map[string][]ConfigHubRevision
+		for _, d := range triggers {
+			if !triggerValidating(d) && !d.Trigger.Disabled {
+				revisions = loadUnitRevisions(ctx, space, units)
+				break
+			}
+		}
+		all = append(all, buildTriggerInfos(space, triggers, units, revisions)...)
+	}
+	return all, nil
+}
+
+// loadUnitRevisions lists the revisions of each unit, by slug. Units whose
+// revisions can't be listed are left out.
+func loadUnitRevisions(ctx context.Context, space string, units []CubUnitData) map[string][]ConfigHubRevision {
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		out = make(map[string][]ConfigHubRevision, len(units))
+	)
+	for _, u := range units {
+		slug := u.Unit.Slug
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = spaceLoadPool.Do(ctx, func() {
+				revs, err := fetchConfigHubRevisions(space, slug)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				out[slug] = revs
+			})
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+func runFunctionsList(cmd *cobra.Command, args []string) error {
+	if err := checkCubAuth(cmd.CommandPath()); err != nil {
+		return err
+	}
+	spaces := functionsSpaces
+	if len(spaces) == 0 {
+		var err error
+		if spaces, err = listSpaceSlugs(); err != nil {
+			return fmt.Errorf("list spaces: %w", err)
+		}
+	}
+	triggers, err := collectTriggers(cmd.Context(), spaces)
+	if err != nil {
+		return err
+	}
+	if functionsJSON {
+		if triggers == nil {
+			triggers = []TriggerInfo{}
+		}
+		return writeJSON(os.Stdout, "Triggers", triggers)
+	}
+	printTriggers(os.Stdout, triggers, time.Now())
+	return nil
+}
+
+func printTriggers(w io.Writer, triggers []TriggerInfo, now time.Time) {
+	if len(triggers) == 0 {
+		fmt.Fprintln(w, "No triggers found.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SPACE\tTRIGGER\tEVENT\tFUNCTION\tKIND\tLAST EXECUTION")
+	for _, t := range triggers {
+		kind := "mutating"
+		if t.Validating {
+			kind = "validating"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", t.Space, t.Slug, orDash(t.Event), t.invocation(), kind, t.Status(now))
+	}
+	tw.Flush()
+
+	for _, t := range triggers {
+		if len(t.Blocking) > 0 {
+			fmt.Fprintf(w, "  %s/%s gates: %s\n", t.Space, t.Slug, strings.Join(t.Blocking, ", "))
+		}
+	}
+}
+
+// invocation is the function call, e.g. "set-replicas 3".
+func (t TriggerInfo) invocation() string {
+	return strings.TrimSpace(t.Function + " " + strings.Join(t.Arguments, " "))
+}
+
+// formatUnitFunctions lists the triggers that run on u for the details
+// pane, with how each last went for this unit, or returns "" when none do.
+func formatUnitFunctions(triggers []TriggerInfo, u CubUnitData, revisions []ConfigHubRevision, now time.Time) string {
+	var b strings.Builder
+	for _, t := range triggers {
+		if !t.appliesTo(u) {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString("FUNCTIONS\n")
+			b.WriteString("─────────────────────────────────────\n")
+		}
+		status := "no changes to this unit found"
+		switch {
+		case t.Disabled:
+			status = "disabled"
+		case t.Validating && t.gates(u):
+			status = "failing — blocks apply"
+		case t.Validating:
+			status = "passing"
+		default:
+			for _, rev := range revisions {
+				if t.madeBy(rev) {
+					status = fmt.Sprintf("changed rev %d, %s ago", rev.Num, formatElapsed(now.Sub(rev.CreatedAt)))
+					break
+				}
+			}
+		}
+		b.WriteString(fmt.Sprintf("%-12s %s (%s)\n", t.Slug+":", t.invocation(), status))
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatSpaceFunctions lists a space's triggers for the details pane.
+// Validating triggers are checked against the loaded units; a mutating
+// trigger's last run takes the units' revisions, so is left to
+// `functions list`.
+func formatSpaceFunctions(triggers []TriggerInfo) string {
+	var b strings.Builder
+	b.WriteString("FUNCTIONS\n")
+	b.WriteString("─────────────────────────────────────\n")
+	for _, t := range triggers {
+		status := "mutating"
+		switch {
+		case t.Disabled:
+			status = "disabled"
+		case t.Validating && len(t.Blocking) > 0:
+			status = "failing — gates " + strings.Join(t.Blocking, ", ")
+		case t.Validating:
+			status = "passing"
+		}
+		b.WriteString(fmt.Sprintf("%-12s %s (%s)\n", t.Slug+":", t.invocation(), status))
+	}
+	b.WriteString(fmt.Sprintf("Last runs:   cub-scout functions list --space %s\n\n", triggers[0].Space))
+	return b.String()
+}
@@ -0,0 +1,101 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// functionsCub is a prod space with a mutating trigger that sets replicas
+// and a validating one that gates web; staging has no triggers.
+var functionsCub = fakeCub{
+	"trigger list --space prod --json": `[
+		{"Trigger": {"Slug": "set-replicas", "Event": "Mutation", "ToolchainType": "Kubernetes/YAML", "FunctionName": "set-replicas",
+			"Arguments": [{"ParameterName": "replicas", "Value": 3}]}},
+		{"Trigger": {"Slug": "no-latest", "Event": "Mutation", "FunctionName": "vet-no-latest"}},
+		{"Trigger": {"Slug": "old", "Event": "Mutation", "FunctionName": "set-label", "Disabled": true}}]`,
+	"trigger list --space staging --json": `[]`,
+	"unit list --space prod --json": `[
+		{"Unit": {"Slug": "api", "ToolchainType": "Kubernetes/YAML"}},
+		{"Unit": {"Slug": "web", "ToolchainType": "Kubernetes/YAML", "ApplyGates": {"prod/no-latest": true}}}]`,
+	"revision list api --space prod --json": `[
+		{"Revision": {"RevisionNum": 2, "Source": "trigger set-replicas", "CreatedAt": "2026-03-01T10:00:00Z"}},
+		{"Revision": {"RevisionNum": 3, "Description": "bump image", "CreatedAt": "2026-03-01T11:00:00Z"}}]`,
+	"revision list web --space prod --json": `[
+		{"Revision": {"RevisionNum": 5, "Description": "Applied set-replicas", "CreatedAt": "2026-03-01T12:00:00Z"}}]`,
+}
+
+func TestCollectTriggers(t *testing.T) {
+	functionsCub.install(t)
+	triggers, err := collectTriggers(context.Background(), []string{"prod", "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers) != 3 || triggers[0].Slug != "no-latest" || triggers[2].Slug != "set-replicas" {
+		t.Fatalf("triggers = %+v, want prod's three, sorted by slug", triggers)
+	}
+
+	gate := triggers[0]
+	if !gate.Validating || len(gate.Blocking) != 1 || gate.Blocking[0] != "web" {
+		t.Errorf("no-latest = %+v, want validating and gating web", gate)
+	}
+	if triggers[1].Status(time.Now()) != "disabled" {
+		t.Errorf("old status = %q", triggers[1].Status(time.Now()))
+	}
+
+	set := triggers[2]
+	if set.Validating || set.invocation() != "set-replicas 3" {
+		t.Errorf("set-replicas = %+v", set)
+	}
+	if set.LastRun == nil || set.LastRun.Unit != "web" || set.LastRun.Revision != 5 {
+		t.Errorf("last run = %+v, want web rev 5, the newest revision it made", set.LastRun)
+	}
+	now := set.LastRun.At.Add(90 * time.Minute)
+	if got := set.Status(now); got != "changed web rev 5 1h 30m ago" {
+		t.Errorf("status = %q", got)
+	}
+
+	var buf bytes.Buffer
+	printTriggers(&buf, triggers, now)
+	for _, want := range []string{"LAST EXECUTION", "vet-no-latest", "failing on 1 unit(s)", "validating", "mutating", "prod/no-latest gates: web"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("list should contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestFormatUnitFunctions(t *testing.T) {
+	functionsCub.install(t)
+	triggers, _ := loadTriggersForSpace("prod")
+	infos := buildTriggerInfos("prod", triggers, nil, nil)
+	units, _ := loadUnitsForSpace("prod")
+	revisions, _ := fetchConfigHubRevisions("prod", "api")
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	api := formatUnitFunctions(infos, units[0], revisions, now)
+	for _, want := range []string{"FUNCTIONS", "no-latest:   vet-no-latest (passing)", "set-replicas 3 (changed rev 2, 2h 0m ago)", "old:", "(disabled)"} {
+		if !strings.Contains(api, want) {
+			t.Errorf("api functions should contain %q:\n%s", want, api)
+		}
+	}
+	if web := formatUnitFunctions(infos, units[1], nil, now); !strings.Contains(web, "failing — blocks apply") ||
+		!strings.Contains(web, "set-replicas 3 (no changes to this unit found)") {
+		t.Errorf("web functions:\n%s", web)
+	}
+
+	// A trigger for another toolchain doesn't run on the unit
+	units[0].Unit.ToolchainType = "AppConfig/Properties"
+	if got := formatUnitFunctions(infos[2:], units[0], revisions, now); got != "" {
+		t.Errorf("functions of another toolchain = %q, want none", got)
+	}
+
+	space := formatSpaceFunctions(buildTriggerInfos("prod", triggers, units, nil))
+	if !strings.Contains(space, "failing — gates web") || !strings.Contains(space, "functions list --space prod") {
+		t.Errorf("space functions:\n%s", space)
+	}
+}
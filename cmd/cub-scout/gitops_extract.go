@@ -6,18 +6,20 @@ package main
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
-	"os/exec"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 )
 
 // ExtractGitOpsConfig extracts configuration from the GitOps resource that manages a workload
-func ExtractGitOpsConfig(w *WorkloadInfo) error {
+func ExtractGitOpsConfig(ctx context.Context, dynClient dynamic.Interface, w *WorkloadInfo) error {
 	if w.GitOpsRef == nil {
 		return fmt.Errorf("no GitOps reference available")
 	}
@@ -27,13 +29,13 @@ func ExtractGitOpsConfig(w *WorkloadInfo) error {
 
 	switch w.GitOpsRef.Kind {
 	case "Application":
-		rawConfig, err = extractArgoConfig(w.GitOpsRef)
+		rawConfig, err = extractArgoConfig(ctx, dynClient, w.GitOpsRef)
 	case "HelmRelease":
-		rawConfig, err = extractFluxHelmReleaseConfig(w.GitOpsRef)
+		rawConfig, err = extractFluxHelmReleaseConfig(ctx, dynClient, w.GitOpsRef)
 	case "Kustomization":
-		rawConfig, err = extractFluxKustomizationConfig(w.GitOpsRef)
+		rawConfig, err = extractFluxKustomizationConfig(ctx, dynClient, w.GitOpsRef)
 	case "HelmSecret":
-		rawConfig, err = extractNativeHelmConfig(w.GitOpsRef.Namespace, w.GitOpsRef.Name)
+		rawConfig, err = extractNativeHelmConfig(ctx, dynClient, w.GitOpsRef.Namespace, w.GitOpsRef.Name)
 	default:
 		return fmt.Errorf("unknown GitOps resource kind: %s", w.GitOpsRef.Kind)
 	}
@@ -78,15 +80,26 @@ func wrapInConfigMap(name, namespace string, ref *GitOpsReference, rawConfig str
 	return sb.String()
 }
 
+// getGitOpsObject reads the GitOps resource ref points at as JSON.
+func getGitOpsObject(ctx context.Context, dynClient dynamic.Interface, ref *GitOpsReference) ([]byte, error) {
+	obj, err := dynClient.Resource(kindToGVR(ref.Kind)).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return obj.MarshalJSON()
+}
+
 // extractArgoConfig extracts configuration from an Argo CD Application
-func extractArgoConfig(ref *GitOpsReference) (string, error) {
-	cmd := exec.Command("kubectl", "get", "application.argoproj.io",
-		"-n", ref.Namespace, ref.Name, "-o", "json")
-	output, err := cmd.Output()
+func extractArgoConfig(ctx context.Context, dynClient dynamic.Interface, ref *GitOpsReference) (string, error) {
+	output, err := getGitOpsObject(ctx, dynClient, ref)
 	if err != nil {
 		return "", fmt.Errorf("failed to get Argo Application: %w", err)
 	}
+	return renderArgoConfig(ref, output)
+}
 
+// renderArgoConfig renders the config of an Argo CD Application from its JSON.
+func renderArgoConfig(ref *GitOpsReference, output []byte) (string, error) {
 	var app struct {
 		Spec struct {
 			Project string `json:"project"`
@@ -105,7 +118,8 @@ func extractArgoConfig(ref *GitOpsReference) (string, error) {
 					ReleaseName string `json:"releaseName"`
 				} `json:"helm"`
 				Kustomize *struct {
-					Images []string `json:"images"`
+					Images  []string `json:"images"`
+					Patches []any    `json:"patches"`
 				} `json:"kustomize"`
 			} `json:"source"`
 			Destination struct {
@@ -139,6 +153,13 @@ func extractArgoConfig(ref *GitOpsReference) (string, error) {
 	if app.Spec.Destination.Namespace != "" {
 		sb.WriteString(fmt.Sprintf("# Target namespace: %s\n", app.Spec.Destination.Namespace))
 	}
+	var argoPatches kustomizePatchSet
+	if app.Spec.Source.Kustomize != nil {
+		argoPatches.Patches = app.Spec.Source.Kustomize.Patches
+	}
+	if !argoPatches.empty() {
+		sb.WriteString(fmt.Sprintf("# Kustomize patches: %s\n", argoPatches.summary()))
+	}
 
 	// Extract Helm values if present
 	if app.Spec.Source.Helm != nil {
@@ -186,6 +207,9 @@ func extractArgoConfig(ref *GitOpsReference) (string, error) {
 		sb.WriteString(fmt.Sprintf("  repoURL: %s\n", app.Spec.Source.RepoURL))
 		sb.WriteString(fmt.Sprintf("  path: %s\n", app.Spec.Source.Path))
 		sb.WriteString(fmt.Sprintf("  targetRevision: %s\n", app.Spec.Source.TargetRevision))
+		if err := writePatchDocument(&sb, "Kustomize patches applied by Argo CD after kustomize build", argoPatches); err != nil {
+			return "", err
+		}
 	} else {
 		// Plain manifest source
 		sb.WriteString("---\n")
@@ -199,14 +223,18 @@ func extractArgoConfig(ref *GitOpsReference) (string, error) {
 }
 
 // extractFluxHelmReleaseConfig extracts configuration from a Flux HelmRelease
-func extractFluxHelmReleaseConfig(ref *GitOpsReference) (string, error) {
-	cmd := exec.Command("kubectl", "get", "helmrelease.helm.toolkit.fluxcd.io",
-		"-n", ref.Namespace, ref.Name, "-o", "json")
-	output, err := cmd.Output()
+func extractFluxHelmReleaseConfig(ctx context.Context, dynClient dynamic.Interface, ref *GitOpsReference) (string, error) {
+	output, err := getGitOpsObject(ctx, dynClient, ref)
 	if err != nil {
 		return "", fmt.Errorf("failed to get Flux HelmRelease: %w", err)
 	}
+	return renderFluxHelmReleaseConfig(ref, output)
+}
 
+// renderFluxHelmReleaseConfig renders the config of a Flux HelmRelease from
+// its JSON, including post-renderers so the unit matches what is deployed
+// rather than the raw chart output.
+func renderFluxHelmReleaseConfig(ref *GitOpsReference, output []byte) (string, error) {
 	var hr struct {
 		Spec struct {
 			Chart struct {
@@ -228,8 +256,11 @@ func extractFluxHelmReleaseConfig(ref *GitOpsReference) (string, error) {
 				TargetPath string `json:"targetPath"`
 				Optional   bool   `json:"optional"`
 			} `json:"valuesFrom"`
-			Interval    string `json:"interval"`
-			ReleaseName string `json:"releaseName"`
+			Interval      string `json:"interval"`
+			ReleaseName   string `json:"releaseName"`
+			PostRenderers []struct {
+				Kustomize *kustomizePatchSet `json:"kustomize"`
+			} `json:"postRenderers"`
 		} `json:"spec"`
 	}
 
@@ -272,6 +303,17 @@ func extractFluxHelmReleaseConfig(ref *GitOpsReference) (string, error) {
 		sb.WriteString("# These references were not resolved during migration.\n")
 	}
 
+	// Merge kustomize post-renderers; Flux applies them in order
+	var postRender kustomizePatchSet
+	for _, pr := range hr.Spec.PostRenderers {
+		if pr.Kustomize != nil {
+			postRender.append(*pr.Kustomize)
+		}
+	}
+	if !postRender.empty() {
+		sb.WriteString(fmt.Sprintf("# Post-renderers: kustomize (%s)\n", postRender.summary()))
+	}
+
 	sb.WriteString("---\n")
 
 	// Include inline values
@@ -285,18 +327,25 @@ func extractFluxHelmReleaseConfig(ref *GitOpsReference) (string, error) {
 		sb.WriteString("# No inline values defined\n")
 	}
 
+	if err := writePatchDocument(&sb, "Post-renderers applied by Flux to the rendered chart", postRender); err != nil {
+		return "", err
+	}
+
 	return sb.String(), nil
 }
 
 // extractFluxKustomizationConfig extracts configuration from a Flux Kustomization
-func extractFluxKustomizationConfig(ref *GitOpsReference) (string, error) {
-	cmd := exec.Command("kubectl", "get", "kustomization.kustomize.toolkit.fluxcd.io",
-		"-n", ref.Namespace, ref.Name, "-o", "json")
-	output, err := cmd.Output()
+func extractFluxKustomizationConfig(ctx context.Context, dynClient dynamic.Interface, ref *GitOpsReference) (string, error) {
+	output, err := getGitOpsObject(ctx, dynClient, ref)
 	if err != nil {
 		return "", fmt.Errorf("failed to get Flux Kustomization: %w", err)
 	}
+	return renderFluxKustomizationConfig(ref, output)
+}
 
+// renderFluxKustomizationConfig renders the config of a Flux Kustomization
+// from its JSON, including all patch forms it applies.
+func renderFluxKustomizationConfig(ref *GitOpsReference, output []byte) (string, error) {
 	var ks struct {
 		Spec struct {
 			Path      string `json:"path"`
@@ -315,17 +364,10 @@ func extractFluxKustomizationConfig(ref *GitOpsReference) (string, error) {
 					Name string `json:"name"`
 				} `json:"substituteFrom"`
 			} `json:"postBuild"`
-			Patches []struct {
-				Patch  string `json:"patch"`
-				Target struct {
-					Group     string `json:"group"`
-					Version   string `json:"version"`
-					Kind      string `json:"kind"`
-					Name      string `json:"name"`
-					Namespace string `json:"namespace"`
-				} `json:"target"`
-			} `json:"patches"`
-			Images []struct {
+			Patches               []any `json:"patches"`
+			PatchesStrategicMerge []any `json:"patchesStrategicMerge"`
+			PatchesJSON6902       []any `json:"patchesJson6902"`
+			Images                []struct {
 				Name    string `json:"name"`
 				NewName string `json:"newName"`
 				NewTag  string `json:"newTag"`
@@ -356,6 +398,14 @@ func extractFluxKustomizationConfig(ref *GitOpsReference) (string, error) {
 	if ks.Spec.TargetNamespace != "" {
 		sb.WriteString(fmt.Sprintf("# Target namespace: %s\n", ks.Spec.TargetNamespace))
 	}
+	patches := kustomizePatchSet{
+		Patches:               ks.Spec.Patches,
+		PatchesStrategicMerge: ks.Spec.PatchesStrategicMerge,
+		PatchesJSON6902:       ks.Spec.PatchesJSON6902,
+	}
+	if !patches.empty() {
+		sb.WriteString(fmt.Sprintf("# Patches: %s\n", patches.summary()))
+	}
 
 	sb.WriteString("---\n")
 
@@ -394,28 +444,12 @@ func extractFluxKustomizationConfig(ref *GitOpsReference) (string, error) {
 	}
 
 	// Include patches
-	if len(ks.Spec.Patches) > 0 {
-		sb.WriteString("\n# Patches:\n")
-		sb.WriteString("patches:\n")
-		for _, p := range ks.Spec.Patches {
-			sb.WriteString("  - target:\n")
-			if p.Target.Kind != "" {
-				sb.WriteString(fmt.Sprintf("      kind: %s\n", p.Target.Kind))
-			}
-			if p.Target.Name != "" {
-				sb.WriteString(fmt.Sprintf("      name: %s\n", p.Target.Name))
-			}
-			if p.Patch != "" {
-				sb.WriteString("    patch: |\n")
-				for _, line := range strings.Split(p.Patch, "\n") {
-					sb.WriteString(fmt.Sprintf("      %s\n", line))
-				}
-			}
-		}
+	if err := writePatchDocument(&sb, "Patches applied by Flux after kustomize build", patches); err != nil {
+		return "", err
 	}
 
 	// If nothing was extracted, note the source reference
-	if ks.Spec.PostBuild == nil && len(ks.Spec.Images) == 0 && len(ks.Spec.Patches) == 0 {
+	if ks.Spec.PostBuild == nil && len(ks.Spec.Images) == 0 && patches.empty() {
 		sb.WriteString("# Kustomization has no inline configuration.\n")
 		sb.WriteString("# Configuration is defined in the source repository.\n")
 		sb.WriteString("source:\n")
@@ -428,16 +462,18 @@ func extractFluxKustomizationConfig(ref *GitOpsReference) (string, error) {
 }
 
 // extractNativeHelmConfig extracts configuration from a native Helm release Secret
-func extractNativeHelmConfig(namespace, releaseName string) (string, error) {
+func extractNativeHelmConfig(ctx context.Context, dynClient dynamic.Interface, namespace, releaseName string) (string, error) {
 	// Find the latest release secret
-	cmd := exec.Command("kubectl", "get", "secret",
-		"-n", namespace,
-		"-l", fmt.Sprintf("name=%s,owner=helm", releaseName),
-		"-o", "json")
-	output, err := cmd.Output()
+	secrets, err := dynClient.Resource(kindToGVR("Secret")).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("name=%s,owner=helm", releaseName),
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get Helm release secrets: %w", err)
 	}
+	output, err := secrets.MarshalJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode Helm secrets: %w", err)
+	}
 
 	var secretList struct {
 		Items []struct {
@@ -0,0 +1,166 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+// patchDocument returns the last YAML document of extracted config.
+func patchDocument(t *testing.T, config string) map[string]any {
+	t.Helper()
+	dec := yaml.NewDecoder(strings.NewReader(config))
+	var last map[string]any
+	for {
+		var doc map[string]any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("extracted config is not valid YAML: %v\n%s", err, config)
+		}
+		if doc != nil {
+			last = doc
+		}
+	}
+	return last
+}
+
+func TestRenderFluxHelmReleasePostRenderers(t *testing.T) {
+	ref := &GitOpsReference{Kind: "HelmRelease", Name: "podinfo", Namespace: "apps"}
+	hr := `{"spec": {
+		"chart": {"spec": {"chart": "podinfo", "version": "6.5.0"}},
+		"values": {"replicaCount": 2},
+		"postRenderers": [
+			{"kustomize": {"patches": [{"target": {"kind": "Deployment", "labelSelector": "app=podinfo"}, "patch": "- op: add\n  path: /metadata/labels/team\n  value: web\n"}]}},
+			{"kustomize": {"patchesStrategicMerge": [{"kind": "Deployment", "metadata": {"name": "podinfo"}}], "images": [{"name": "podinfo", "newTag": "6.5.1"}]}}
+		]
+	}}`
+	config, err := renderFluxHelmReleaseConfig(ref, []byte(hr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(config, "# Post-renderers: kustomize (1 patch, 1 strategic merge patch, 1 image override)") {
+		t.Errorf("missing post-renderer summary:\n%s", config)
+	}
+	doc := patchDocument(t, config)
+	patches, _ := doc["patches"].([]any)
+	if len(patches) != 1 || len(doc["patchesStrategicMerge"].([]any)) != 1 || len(doc["images"].([]any)) != 1 {
+		t.Fatalf("post-renderers not carried into config: %v", doc)
+	}
+	target := patches[0].(map[string]any)["target"].(map[string]any)
+	if target["labelSelector"] != "app=podinfo" {
+		t.Errorf("patch target lost fields: %v", target)
+	}
+}
+
+func TestRenderFluxKustomizationPatches(t *testing.T) {
+	ref := &GitOpsReference{Kind: "Kustomization", Name: "apps", Namespace: "flux-system"}
+	ks := `{"spec": {
+		"path": "./apps/prod",
+		"sourceRef": {"kind": "GitRepository", "name": "platform"},
+		"patches": [{"target": {"group": "apps", "version": "v1", "kind": "Deployment", "namespace": "prod"}, "patch": "- op: replace\n  path: /spec/replicas\n  value: 3\n"}],
+		"patchesStrategicMerge": [{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "web"}}],
+		"patchesJson6902": [{"target": {"kind": "Ingress", "name": "web"}, "patch": [{"op": "remove", "path": "/spec/tls"}]}]
+	}}`
+	config, err := renderFluxKustomizationConfig(ref, []byte(ks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(config, "no inline configuration") {
+		t.Errorf("patches should count as inline configuration:\n%s", config)
+	}
+	doc := patchDocument(t, config)
+	for _, key := range []string{"patches", "patchesStrategicMerge", "patchesJson6902"} {
+		if list, _ := doc[key].([]any); len(list) != 1 {
+			t.Errorf("%s missing from extracted config: %v", key, doc)
+		}
+	}
+	target := doc["patches"].([]any)[0].(map[string]any)["target"].(map[string]any)
+	if target["group"] != "apps" || target["namespace"] != "prod" {
+		t.Errorf("patch target lost fields: %v", target)
+	}
+}
+
+func TestRenderArgoKustomizePatches(t *testing.T) {
+	ref := &GitOpsReference{Kind: "Application", Name: "web", Namespace: "argocd"}
+	app := `{"spec": {"source": {
+		"repoURL": "https://github.com/org/apps", "path": "web/overlays/prod", "targetRevision": "main",
+		"kustomize": {"patches": [{"target": {"kind": "Deployment"}, "patch": "- op: add\n  path: /spec/paused\n  value: false\n"}]}
+	}}}`
+	config, err := renderArgoConfig(ref, []byte(app))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(config, "# Kustomize patches: 1 patch") {
+		t.Errorf("missing patch summary:\n%s", config)
+	}
+	if list, _ := patchDocument(t, config)["patches"].([]any); len(list) != 1 {
+		t.Errorf("Argo kustomize patches missing from extracted config:\n%s", config)
+	}
+}
+
+func TestRenderWithoutPatches(t *testing.T) {
+	ref := &GitOpsReference{Kind: "HelmRelease", Name: "podinfo", Namespace: "apps"}
+	config, err := renderFluxHelmReleaseConfig(ref, []byte(`{"spec": {"values": {"a": 1}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(config, "Post-renderers") || strings.Count(config, "---") != 1 {
+		t.Errorf("unexpected post-renderer output without post-renderers:\n%s", config)
+	}
+}
+
+func TestExtractGitOpsConfigFromCluster(t *testing.T) {
+	var release bytes.Buffer
+	gz := gzip.NewWriter(&release)
+	gz.Write([]byte(`{"name": "redis", "version": 3, "config": {"replicas": 2}, "chart": {"metadata": {"name": "redis", "version": "18.0.0"}}}`))
+	gz.Close()
+	helmSecret := func(version, data string) *unstructured.Unstructured {
+		u := agenttest.Object("v1", "Secret", "cache", "sh.helm.release.v1.redis.v"+version,
+			agenttest.WithLabels(map[string]string{"name": "redis", "owner": "helm", "version": version}))
+		u.Object["type"] = "helm.sh/release.v1"
+		u.Object["data"] = map[string]interface{}{"release": data}
+		return u
+	}
+	client := agenttest.FakeClient(
+		agenttest.FluxKustomization("flux-system", "apps"),
+		helmSecret("2", "not a release"),
+		helmSecret("3", base64.StdEncoding.EncodeToString(release.Bytes())),
+	)
+
+	ks := &WorkloadInfo{Name: "api", Namespace: "prod", GitOpsRef: &GitOpsReference{Kind: "Kustomization", Name: "apps", Namespace: "flux-system"}}
+	if err := ExtractGitOpsConfig(context.Background(), client, ks); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ks.ExtractedConfig, "# Path: ./apps") || !strings.Contains(ks.ExtractedConfig, "name: api-gitops-config") {
+		t.Errorf("Kustomization config:\n%s", ks.ExtractedConfig)
+	}
+
+	helm := &WorkloadInfo{Name: "redis", Namespace: "cache", GitOpsRef: &GitOpsReference{Kind: "HelmSecret", Name: "redis", Namespace: "cache"}}
+	if err := ExtractGitOpsConfig(context.Background(), client, helm); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(helm.ExtractedConfig, "(version 3)") || !strings.Contains(helm.ExtractedConfig, "replicas: 2") {
+		t.Errorf("Helm config does not come from the latest release:\n%s", helm.ExtractedConfig)
+	}
+
+	missing := &WorkloadInfo{Name: "web", Namespace: "prod", GitOpsRef: &GitOpsReference{Kind: "Application", Name: "web", Namespace: "argocd"}}
+	if err := ExtractGitOpsConfig(context.Background(), client, missing); err == nil || missing.ConfigError == nil {
+		t.Errorf("missing Application: err = %v, ConfigError = %v", err, missing.ConfigError)
+	}
+}
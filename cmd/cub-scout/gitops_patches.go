@@ -0,0 +1,80 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kustomizePatchSet is the kustomize patch configuration a GitOps tool applies
+// on top of its source: Flux Kustomization patches, HelmRelease kustomize
+// post-renderers and Argo CD source.kustomize.patches. Entries are kept as
+// decoded JSON so every field (targets, selectors, JSON6902 ops) survives
+// extraction unchanged.
+type kustomizePatchSet struct {
+	Patches               []any `json:"patches,omitempty" yaml:"patches,omitempty"`
+	PatchesStrategicMerge []any `json:"patchesStrategicMerge,omitempty" yaml:"patchesStrategicMerge,omitempty"`
+	PatchesJSON6902       []any `json:"patchesJson6902,omitempty" yaml:"patchesJson6902,omitempty"`
+	Images                []any `json:"images,omitempty" yaml:"images,omitempty"`
+}
+
+func (p kustomizePatchSet) empty() bool {
+	return len(p.Patches) == 0 && len(p.PatchesStrategicMerge) == 0 &&
+		len(p.PatchesJSON6902) == 0 && len(p.Images) == 0
+}
+
+// append adds other's entries after p's, preserving apply order.
+func (p *kustomizePatchSet) append(other kustomizePatchSet) {
+	p.Patches = append(p.Patches, other.Patches...)
+	p.PatchesStrategicMerge = append(p.PatchesStrategicMerge, other.PatchesStrategicMerge...)
+	p.PatchesJSON6902 = append(p.PatchesJSON6902, other.PatchesJSON6902...)
+	p.Images = append(p.Images, other.Images...)
+}
+
+// summary describes the patch set for a header comment, e.g.
+// "2 patches, 1 strategic merge patch".
+func (p kustomizePatchSet) summary() string {
+	var parts []string
+	for _, c := range []struct {
+		n    int
+		noun string
+	}{
+		{len(p.Patches), "patch"},
+		{len(p.PatchesStrategicMerge), "strategic merge patch"},
+		{len(p.PatchesJSON6902), "JSON 6902 patch"},
+		{len(p.Images), "image override"},
+	} {
+		switch {
+		case c.n == 1:
+			parts = append(parts, "1 "+c.noun)
+		case c.n > 1:
+			plural := c.noun + "s"
+			if strings.HasSuffix(c.noun, "h") {
+				plural = c.noun + "es"
+			}
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, plural))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writePatchDocument appends the patch set as its own YAML document, so
+// imported config carries the changes the GitOps tool makes after rendering.
+// Writes nothing for an empty set.
+func writePatchDocument(sb *strings.Builder, comment string, p kustomizePatchSet) error {
+	if p.empty() {
+		return nil
+	}
+	yamlBytes, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal patches: %w", err)
+	}
+	sb.WriteString("---\n")
+	sb.WriteString("# " + comment + "\n")
+	sb.Write(yamlBytes)
+	return nil
+}
@@ -7,8 +7,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -21,9 +21,12 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/confighub/cub-scout/internal/hierarchysvc"
+	"github.com/confighub/cub-scout/internal/mapsvc"
 	"github.com/confighub/cub-scout/pkg/agent"
+	"github.com/confighub/cub-scout/pkg/orgpattern"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -51,17 +54,30 @@ func loadDataCmd() tea.Msg {
 	return dataLoadedMsg{nodes: nodes, currentOrg: currentOrg, currentOrgInt: currentOrgInt, currentSpace: currentSpace, spacesToLoad: spacesToLoad}
 }
 
-// loadSpaceDataCmd loads units, targets, and workers for a space in the background
+// loadSpaceDataCmd loads units, targets, and workers for a space in the background.
+// Each `cub` call goes through spaceLoadPool, so loading many spaces at once
+// runs a bounded number of subprocesses.
 func loadSpaceDataCmd(spaceSlug string) tea.Cmd {
 	return func() tea.Msg {
-		units, _ := loadUnitsForSpace(spaceSlug)
-		targets, _ := loadTargetsForSpace(spaceSlug)
-		workers, _ := loadWorkersForSpace(spaceSlug)
+		ctx := context.Background()
+		var (
+			units    []CubUnitData
+			targets  []CubTargetData
+			workers  []CubWorkerData
+			unitsErr error
+		)
+		_ = spaceLoadPool.Do(ctx, func() { units, unitsErr = loadUnitsForSpace(spaceSlug) })
+		if unitsErr != nil {
+			return spaceDataLoadedMsg{spaceSlug: spaceSlug, err: unitsErr}
+		}
+		_ = spaceLoadPool.Do(ctx, func() { targets, _ = loadTargetsForSpace(spaceSlug) })
+		_ = spaceLoadPool.Do(ctx, func() { workers, _ = loadWorkersForSpace(spaceSlug) })
 		return spaceDataLoadedMsg{
 			spaceSlug: spaceSlug,
 			units:     units,
 			targets:   targets,
 			workers:   workers,
+			health:    targetHealthBySlug(probeTargets(units, targets, workers, probeKubeContext, time.Now())),
 		}
 	}
 }
@@ -82,10 +98,7 @@ func loadPanelDataCmd(unitSlugs []string) tea.Cmd {
 			return panelDataLoadedMsg{err: fmt.Errorf("create dynamic client: %w", err)}
 		}
 
-		clusterName := os.Getenv("CLUSTER_NAME")
-		if clusterName == "" {
-			clusterName = "default"
-		}
+		clusterName := currentClusterName()
 
 		var workloads []MapEntry
 		correlation := make(map[string][]MapEntry)
@@ -128,7 +141,7 @@ func loadPanelDataCmd(unitSlugs []string) tea.Cmd {
 					Labels:      labels,
 					Status:      detectStatus(item),
 					CreatedAt:   item.GetCreationTimestamp().Time,
-					UpdatedAt:   item.GetCreationTimestamp().Time,
+					UpdatedAt:   mapsvc.LastChanged(item),
 				}
 
 				// Extract ConfigHub details
@@ -372,7 +385,8 @@ func loadConfigHubData() ([]*TreeNode, string, string, string, []string, error)
 					OrgID:    org.ExternalID,
 				}
 
-				// Queue this space for background loading (don't block initial render)
+				// Queue this space for background loading (don't block initial render);
+				// large orgs load only the default space until others are expanded
 				spacesToLoad = append(spacesToLoad, space.Space.Slug)
 
 				spaceNode.Children = []*TreeNode{unitsGroup, targetsGroup, workersGroup}
@@ -383,7 +397,7 @@ func loadConfigHubData() ([]*TreeNode, string, string, string, []string, error)
 		nodes = append(nodes, orgNode)
 	}
 
-	return nodes, currentOrg, currentOrgInt, currentSpace, spacesToLoad, nil
+	return nodes, currentOrg, currentOrgInt, currentSpace, spacesToLoadAtStartup(spacesToLoad, currentSpace), nil
 }
 
 func loadUnitsForSpace(spaceSlug string) ([]CubUnitData, error) {
@@ -422,11 +436,40 @@ func loadWorkersForSpace(spaceSlug string) ([]CubWorkerData, error) {
 	return workers, nil
 }
 
+// cubExec runs the cub CLI and returns its stdout. Tests replace it with a
+// fake runner so TUI flows render without cub or a ConfigHub login.
+var cubExec = func(args ...string) ([]byte, error) {
+	if !cubInstalled() {
+		return nil, errCubMissing
+	}
+	return exec.Command("cub", args...).Output()
+}
+
+// runCubCommand runs cub, answering reads from cubCache.
 func runCubCommand(args ...string) ([]byte, error) {
-	cmd := exec.Command("cub", args...)
-	output, err := cmd.Output()
+	if !isCubRead(args) {
+		defer cubCache.Purge()
+		return execCubCommand(args...)
+	}
+	return cubCache.Get(cubCacheKey(args), func() ([]byte, error) {
+		return execCubCommand(args...)
+	})
+}
+
+// refreshCubCommand is runCubCommand for a read that must see ConfigHub as it
+// is now: polling for a change, or reading back a write made with stdin. It
+// always fetches, and caches the fresh output.
+func refreshCubCommand(args ...string) ([]byte, error) {
+	cubCache.Forget(cubCacheKey(args))
+	return runCubCommand(args...)
+}
+
+func execCubCommand(args ...string) ([]byte, error) {
+	done := traceCub(args)
+	output, err := cubExec(args...)
+	done(err)
 	if err != nil {
-		return nil, err
+		return nil, &cubCommandError{args: args, err: err}
 	}
 	return output, nil
 }
@@ -434,39 +477,56 @@ func runCubCommand(args ...string) ([]byte, error) {
 // Import wizard commands
 func loadNamespacesCmd() tea.Cmd {
 	return func() tea.Msg {
-		// Get namespace names
-		out, err := exec.Command("kubectl", "get", "namespaces", "-o", "jsonpath={.items[*].metadata.name}").Output()
+		dynClient, err := kubeClient()
 		if err != nil {
 			return namespacesLoadedMsg{err: err}
 		}
-		nsNames := strings.Fields(string(out))
-
-		// Get workload counts and owner info for each namespace
-		var namespaces []namespaceInfo
-		for _, ns := range nsNames {
-			info := namespaceInfo{Name: ns}
+		namespaces, warnings, err := loadNamespaces(context.Background(), dynClient)
+		return namespacesLoadedMsg{namespaces: namespaces, warnings: warnings, err: err}
+	}
+}
 
-			// Get all workloads with their labels in one call per type
-			// Deployments
-			if out, err := exec.Command("kubectl", "get", "deployments", "-n", ns, "-o", "json").Output(); err == nil {
-				countWorkloadsWithOwners(out, &info, &info.Deployments)
-			}
+// loadNamespaces lists namespaces with their workload counts and owners.
+// A workload kind that cannot be listed is left out of the counts and
+// reported in warnings.
+func loadNamespaces(ctx context.Context, dynClient dynamic.Interface) ([]namespaceInfo, []string, error) {
+	nsList, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("list namespaces: %w", err)
+	}
 
-			// StatefulSets
-			if out, err := exec.Command("kubectl", "get", "statefulsets", "-n", ns, "-o", "json").Output(); err == nil {
-				countWorkloadsWithOwners(out, &info, &info.StatefulSet)
-			}
+	// One list per workload kind across all namespaces, grouped below
+	var warnings []string
+	workloads := map[string][]unstructured.Unstructured{}
+	for _, kind := range []string{"Deployment", "StatefulSet", "DaemonSet"} {
+		list, err := dynClient.Resource(kindToGVR(kind)).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("list %ss: %v; not counted", strings.ToLower(kind), err))
+			continue
+		}
+		workloads[kind] = list.Items
+	}
 
-			// DaemonSets
-			if out, err := exec.Command("kubectl", "get", "daemonsets", "-n", ns, "-o", "json").Output(); err == nil {
-				countWorkloadsWithOwners(out, &info, &info.DaemonSets)
-			}
+	namespaces := make([]namespaceInfo, 0, len(nsList.Items))
+	for _, ns := range nsList.Items {
+		info := namespaceInfo{Name: ns.GetName()}
+		countWorkloadsWithOwners(inNamespace(workloads["Deployment"], info.Name), &info, &info.Deployments)
+		countWorkloadsWithOwners(inNamespace(workloads["StatefulSet"], info.Name), &info, &info.StatefulSet)
+		countWorkloadsWithOwners(inNamespace(workloads["DaemonSet"], info.Name), &info, &info.DaemonSets)
+		namespaces = append(namespaces, info)
+	}
+	return namespaces, warnings, nil
+}
 
-			namespaces = append(namespaces, info)
+// inNamespace returns the objects in namespace.
+func inNamespace(objs []unstructured.Unstructured, namespace string) []unstructured.Unstructured {
+	var out []unstructured.Unstructured
+	for _, obj := range objs {
+		if obj.GetNamespace() == namespace {
+			out = append(out, obj)
 		}
-
-		return namespacesLoadedMsg{namespaces: namespaces}
 	}
+	return out
 }
 
 func loadArgoAppsCmd() tea.Cmd {
@@ -546,7 +606,7 @@ func loadArgoResourcesCmd(app argoAppInfo) tea.Cmd {
 		ctx := context.Background()
 
 		// Get managed resources for this ArgoCD Application
-		resources, err := getManagedResources(ctx, clientset, dynamicClient, app.Name, app.DestNS)
+		resources, err := getManagedResources(ctx, clientset, dynamicClient, app.Namespace, app.Name, app.DestNS)
 		if err != nil {
 			return argoResourcesLoadedMsg{err: fmt.Errorf("failed to get managed resources: %w", err)}
 		}
@@ -623,23 +683,27 @@ func deleteArgoAppCmd(namespace, name string) tea.Cmd {
 
 // applyUnitCmd applies a unit to its target. This is called AFTER ArgoCD cleanup
 // to ensure ArgoCD's selfHeal doesn't revert the changes.
-// It first cleans stale ConfigHub inventory annotations from the target resources
+// It first removes stale ConfigHub inventory markers from the target resources
 // to prevent ownership conflicts from previous imports.
 func applyUnitCmd(space, unitSlug string, workloads []WorkloadInfo) tea.Cmd {
 	return func() tea.Msg {
-		// Clean stale ConfigHub inventory annotations from live resources
-		// This prevents conflicts when re-importing resources that were previously managed
-		// Errors are intentionally ignored: cleanup is best-effort, missing annotations are fine
-		for _, w := range workloads {
-			// Remove stale ownership annotation (best-effort)
-			_ = exec.Command("kubectl", "annotate", strings.ToLower(w.Kind), w.Name,
-				"-n", w.Namespace, "config.k8s.io/owning-inventory-", "--overwrite").Run() //nolint:errcheck // best-effort cleanup
-			// Remove stale ConfigHub label (best-effort)
-			_ = exec.Command("kubectl", "label", strings.ToLower(w.Kind), w.Name,
-				"-n", w.Namespace, "confighub.com/UnitSlug-", "--overwrite").Run() //nolint:errcheck // best-effort cleanup
-			// Remove stale inventory ID label (best-effort)
-			_ = exec.Command("kubectl", "label", strings.ToLower(w.Kind), w.Name,
-				"-n", w.Namespace, "cli-utils.sigs.k8s.io/inventory-id-", "--overwrite").Run() //nolint:errcheck // best-effort cleanup
+		if err := checkChanges(applyUnitChanges(space, unitSlug, workloads)); err != nil {
+			return unitAppliedMsg{unitSlug: unitSlug, err: err}
+		}
+		// Clean stale ConfigHub inventory markers from live resources. This
+		// prevents ownership conflicts when re-importing resources that were
+		// previously managed; a failure stops the apply rather than leaving
+		// it to conflict later.
+		cfg, err := buildConfig()
+		if err != nil {
+			return unitAppliedMsg{unitSlug: unitSlug, err: fmt.Errorf("build kubernetes config: %w", err)}
+		}
+		dynClient, err := dynamic.NewForConfig(cfg)
+		if err != nil {
+			return unitAppliedMsg{unitSlug: unitSlug, err: fmt.Errorf("create dynamic client: %w", err)}
+		}
+		if errs := removeImportMarkers(context.Background(), dynClient, workloads); len(errs) > 0 {
+			return unitAppliedMsg{unitSlug: unitSlug, err: fmt.Errorf("remove stale import markers: %w", errors.Join(errs...))}
 		}
 
 		cmd := exec.Command("cub", "unit", "apply", unitSlug, "--space", space)
@@ -662,26 +726,13 @@ func testPipelineCmd(space, unitSlug string) tea.Cmd {
 	}
 }
 
-// countWorkloadsWithOwners parses kubectl JSON output and counts workloads by owner
-func countWorkloadsWithOwners(jsonData []byte, info *namespaceInfo, kindCount *int) {
-	var result struct {
-		Items []struct {
-			Metadata struct {
-				Labels      map[string]string `json:"labels"`
-				Annotations map[string]string `json:"annotations"`
-			} `json:"metadata"`
-		} `json:"items"`
-	}
-
-	if err := json.Unmarshal(jsonData, &result); err != nil {
-		return
-	}
+// countWorkloadsWithOwners counts workloads of one kind by owner
+func countWorkloadsWithOwners(items []unstructured.Unstructured, info *namespaceInfo, kindCount *int) {
+	*kindCount = len(items)
 
-	*kindCount = len(result.Items)
-
-	for _, item := range result.Items {
-		labels := item.Metadata.Labels
-		annotations := item.Metadata.Annotations
+	for _, item := range items {
+		labels := item.GetLabels()
+		annotations := item.GetAnnotations()
 
 		// Check for ConfigHub first
 		if labels["confighub.com/UnitSlug"] != "" || annotations["confighub.com/UnitSlug"] != "" {
@@ -698,7 +749,7 @@ func countWorkloadsWithOwners(jsonData []byte, info *namespaceInfo, kindCount *i
 		}
 
 		// Check for Argo CD
-		if labels["argocd.argoproj.io/instance"] != "" ||
+		if _, argoApp, _ := agent.ArgoApplication(labels, annotations); argoApp != "" ||
 			labels["app.kubernetes.io/instance"] != "" && labels["app.kubernetes.io/managed-by"] == "Helm" && strings.Contains(labels["app.kubernetes.io/instance"], "argocd") {
 			info.ArgoCount++
 			continue
@@ -747,7 +798,7 @@ func importWorkloadsCmd(space string, workloads []WorkloadInfo) tea.Cmd {
 // importArgoWorkloadsCmd imports ArgoCD workloads as a combined unit.
 // Creates a single {appName}-workload unit with all resources, matching the CLI behavior.
 // Also links to target and applies so livedata becomes available.
-func importArgoWorkloadsCmd(space, appName, target string, workloads []WorkloadInfo) tea.Cmd {
+func importArgoWorkloadsCmd(space, appName, target, labels string, workloads []WorkloadInfo) tea.Cmd {
 	return func() tea.Msg {
 		if len(workloads) == 0 {
 			return importCompleteMsg{success: 0, failed: 0}
@@ -767,7 +818,7 @@ func importArgoWorkloadsCmd(space, appName, target string, workloads []WorkloadI
 
 		// Create single unit with {appName}-workload slug
 		unitSlug := fmt.Sprintf("%s-workload", appName)
-		if err := createUnitWithConfig(space, unitSlug, combinedYAML.String()); err != nil {
+		if err := createUnitWithConfigAndLabels(space, unitSlug, combinedYAML.String(), labels); err != nil {
 			return importCompleteMsg{success: 0, failed: len(workloads)}
 		}
 
@@ -788,13 +839,20 @@ func importArgoWorkloadsCmd(space, appName, target string, workloads []WorkloadI
 			applyErr = fmt.Errorf("no target specified - unit created but not applied")
 		}
 
-		// Label all workloads with the combined unit slug
+		// Label all workloads with the combined unit slug. The unit exists
+		// either way, so a labeling failure is counted rather than fatal.
+		var labelErr error
+		failed := 0
 		for _, w := range workloads {
-			// Best-effort labeling: don't fail the whole import for labeling issues
-			_ = labelWorkload(w.Kind, w.Namespace, w.Name, unitSlug) //nolint:errcheck // best-effort labeling
+			if err := labelWorkload(w.Kind, w.Namespace, w.Name, unitSlug); err != nil {
+				failed++
+				if labelErr == nil {
+					labelErr = err
+				}
+			}
 		}
 
-		return importCompleteMsg{success: len(workloads), failed: 0, applyError: applyErr}
+		return importCompleteMsg{success: len(workloads) - failed, failed: failed, applyError: applyErr, labelError: labelErr}
 	}
 }
 
@@ -807,52 +865,30 @@ func importWorkloadsWithSuggestionCmd(space string, suggestion *ImportSuggestion
 			return importWorkloadsCmd(space, selected)()
 		}
 
-		// Build a set of selected workload names for quick lookup
-		selectedSet := make(map[string]WorkloadInfo)
-		for _, w := range selected {
-			selectedSet[w.Namespace+"/"+w.Name] = w
-		}
-
 		success, failed := 0, 0
-
-		// Iterate through the suggestion structure
-		for _, app := range suggestion.Apps {
-			for _, variant := range app.Variants {
-				// Collect selected workloads in this variant
-				var variantWorkloads []WorkloadInfo
-				for _, w := range variant.Workloads {
-					if sw, ok := selectedSet[w.Namespace+"/"+w.Name]; ok {
-						variantWorkloads = append(variantWorkloads, sw)
-					}
-				}
-
-				if len(variantWorkloads) == 0 {
-					continue // No selected workloads in this variant
+		for _, unit := range suggestedUnits(suggestion, selected) {
+			// Create unit with the suggested slug
+			// Use the first workload's config for the unit if available
+			var config string
+			for _, w := range unit.workloads {
+				if w.ExtractedConfig != "" {
+					config = w.ExtractedConfig
+					break
 				}
+			}
 
-				// Create unit with the suggested slug
-				// Use the first workload's config for the unit if available
-				var config string
-				for _, w := range variantWorkloads {
-					if w.ExtractedConfig != "" {
-						config = w.ExtractedConfig
-						break
-					}
-				}
+			if err := createUnitWithConfig(space, unit.slug, config); err != nil {
+				failed += len(unit.workloads)
+				continue
+			}
 
-				if err := createUnitWithConfig(space, variant.UnitSlug, config); err != nil {
-					failed += len(variantWorkloads)
+			// Label all workloads in this variant with the same unit slug
+			for _, w := range unit.workloads {
+				if err := labelWorkload(w.Kind, w.Namespace, w.Name, unit.slug); err != nil {
+					failed++
 					continue
 				}
-
-				// Label all workloads in this variant with the same unit slug
-				for _, w := range variantWorkloads {
-					if err := labelWorkload(w.Kind, w.Namespace, w.Name, variant.UnitSlug); err != nil {
-						failed++
-						continue
-					}
-					success++
-				}
+				success++
 			}
 		}
 
@@ -860,6 +896,40 @@ func importWorkloadsWithSuggestionCmd(space string, suggestion *ImportSuggestion
 	}
 }
 
+// suggestedUnit is one variant of the import suggestion with the selected
+// workloads it holds.
+type suggestedUnit struct {
+	slug      string
+	workloads []WorkloadInfo
+}
+
+// suggestedUnits returns the variants of suggestion holding at least one
+// selected workload, in suggestion order.
+func suggestedUnits(suggestion *ImportSuggestion, selected []WorkloadInfo) []suggestedUnit {
+	// Build a set of selected workload names for quick lookup
+	selectedSet := make(map[string]WorkloadInfo)
+	for _, w := range selected {
+		selectedSet[w.Namespace+"/"+w.Name] = w
+	}
+
+	var units []suggestedUnit
+	for _, app := range suggestion.Apps {
+		for _, variant := range app.Variants {
+			// Collect selected workloads in this variant
+			unit := suggestedUnit{slug: variant.UnitSlug}
+			for _, w := range variant.Workloads {
+				if sw, ok := selectedSet[w.Namespace+"/"+w.Name]; ok {
+					unit.workloads = append(unit.workloads, sw)
+				}
+			}
+			if len(unit.workloads) > 0 {
+				units = append(units, unit)
+			}
+		}
+	}
+	return units
+}
+
 // extractConfigCmd extracts GitOps configuration for selected workloads
 func extractConfigCmd(workloads []WorkloadInfo) tea.Cmd {
 	return func() tea.Msg {
@@ -867,13 +937,18 @@ func extractConfigCmd(workloads []WorkloadInfo) tea.Cmd {
 		updated := make([]WorkloadInfo, len(workloads))
 		copy(updated, workloads)
 
+		dynClient, clientErr := kubeClient()
 		successCount := 0
 		for i := range updated {
 			w := &updated[i]
 			if w.GitOpsRef == nil {
 				continue // No GitOps source to extract from
 			}
-			if err := ExtractGitOpsConfig(w); err == nil {
+			if clientErr != nil {
+				w.ConfigError = clientErr
+				continue
+			}
+			if err := ExtractGitOpsConfig(context.Background(), dynClient, w); err == nil {
 				successCount++
 			}
 			// ConfigError is set by ExtractGitOpsConfig on failure
@@ -1055,7 +1130,7 @@ func runCommandCmd(command string) tea.Cmd {
 		// Parse command into parts
 		parts := strings.Fields(command)
 		if len(parts) == 0 {
-			return cmdCompleteMsg{err: fmt.Errorf("empty command")}
+			return cmdCompleteMsg{command: command, err: fmt.Errorf("empty command")}
 		}
 
 		// Special handling for common commands
@@ -1070,24 +1145,15 @@ func runCommandCmd(command string) tea.Cmd {
 		output, err := cmd.CombinedOutput()
 		if err != nil {
 			return cmdCompleteMsg{
-				output: string(output),
-				err:    fmt.Errorf("%s: %w", cmdName, err),
+				command: command,
+				output:  string(output),
+				err:     fmt.Errorf("%s: %w", cmdName, err),
 			}
 		}
-		return cmdCompleteMsg{output: string(output)}
+		return cmdCompleteMsg{command: command, output: string(output)}
 	}
 }
 
-// getCurrentKubeContext returns the current kubectl context name
-func getCurrentKubeContext() string {
-	cmd := exec.Command("kubectl", "config", "current-context")
-	out, err := cmd.Output()
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(out))
-}
-
 // Create wizard commands
 
 // loadCreateUnitsCmd loads units in a space for cloning (includes toolchain type)
@@ -1284,8 +1350,9 @@ func doDeleteTargetCmd(space, name string) tea.Cmd {
 	}
 }
 
-// buildUnitInfo creates a detailed info string for a unit
-func buildUnitInfo(unit CubUnitData) string {
+// buildUnitInfo creates a detailed info string for a unit. promotion is its
+// lag behind its upstream, from promotionLagInfo.
+func buildUnitInfo(unit CubUnitData, promotion string) string {
 	var parts []string
 
 	// Target connection
@@ -1293,14 +1360,21 @@ func buildUnitInfo(unit CubUnitData) string {
 		parts = append(parts, fmt.Sprintf("→ %s", unit.Target.Slug))
 	}
 
-	// Revision info (show if head != live, indicating pending changes)
+	// Revision info (show if head != live, indicating pending changes).
+	// A gated unit's changes wait on the gate, not on an apply.
+	gate := applyGateSummary(unit)
 	if unit.Unit.HeadRevisionNum > 0 {
-		if unit.Unit.LiveRevisionNum > 0 && unit.Unit.HeadRevisionNum != unit.Unit.LiveRevisionNum {
+		if gate != "" {
+			parts = append(parts, fmt.Sprintf("rev:%d", unit.Unit.LiveRevisionNum), gate)
+		} else if unit.Unit.LiveRevisionNum > 0 && unit.Unit.HeadRevisionNum != unit.Unit.LiveRevisionNum {
 			parts = append(parts, fmt.Sprintf("rev:%d→%d", unit.Unit.LiveRevisionNum, unit.Unit.HeadRevisionNum))
 		} else {
 			parts = append(parts, fmt.Sprintf("rev:%d", unit.Unit.HeadRevisionNum))
 		}
 	}
+	if promotion != "" {
+		parts = append(parts, promotion)
+	}
 
 	// Error status (accessibility: always show text with error icon)
 	if unit.UnitStatus.Status == "Error" {
@@ -1378,8 +1452,23 @@ func buildUnitDetailChildren(unit CubUnitData, parent *TreeNode) []*TreeNode {
 		Parent: parent,
 	}
 	statusNode.Status = unit.DeriveStatus()
+	if statusNode.Status == statusGated {
+		statusNode.Status = "warn"
+	}
 	children = append(children, statusNode)
 
+	// Apply gates (if any block the head revision)
+	if gate := applyGateSummary(unit); gate != "" {
+		children = append(children, &TreeNode{
+			ID:     parent.ID + "/gates",
+			Name:   "Apply Gate",
+			Type:   "detail",
+			Status: "warn",
+			Info:   gate,
+			Parent: parent,
+		})
+	}
+
 	// Last action info (if available)
 	if unit.UnitStatus.Action != "" {
 		actionInfo := unit.UnitStatus.Action
@@ -1445,8 +1534,7 @@ func (m *Model) updateSearchMatches() {
 	query := strings.ToLower(m.searchQuery)
 	for i, node := range m.flatList {
 		// Match against name and info
-		if strings.Contains(strings.ToLower(node.Name), query) ||
-			strings.Contains(strings.ToLower(node.Info), query) {
+		if strings.Contains(m.nodeSearchText(node), query) {
 			m.searchMatches = append(m.searchMatches, i)
 		}
 	}
@@ -1487,24 +1575,12 @@ func (m *Model) prevSearchMatch() {
 	m.cursor = m.searchMatches[m.searchIndex]
 }
 
-// isSearchMatch returns true if the given flatList index is a search match
-func (m *Model) isSearchMatch(idx int) bool {
-	for _, matchIdx := range m.searchMatches {
-		if matchIdx == idx {
-			return true
-		}
-	}
-	return false
-}
-
 // nodeMatchesQuery returns true if this node directly matches the search query
 func (m *Model) nodeMatchesQuery(node *TreeNode) bool {
 	if m.searchQuery == "" {
 		return true
 	}
-	query := strings.ToLower(m.searchQuery)
-	return strings.Contains(strings.ToLower(node.Name), query) ||
-		strings.Contains(strings.ToLower(node.Info), query)
+	return strings.Contains(m.nodeSearchText(node), strings.ToLower(m.searchQuery))
 }
 
 // nodeOrDescendantMatches returns true if this node or any descendant matches
@@ -1547,17 +1623,11 @@ func (m *Model) nodeOrDescendantMatches(node *TreeNode) bool {
 	return false
 }
 
-// clearMatchCache clears the match cache (call when tree structure changes)
-func (m *Model) clearMatchCache() {
-	m.matchCache = make(map[*TreeNode]bool)
-}
-
-// openSpaceInBrowserCmd opens the space in the web browser
-func openSpaceInBrowserCmd(spaceID string) tea.Cmd {
+// openInBrowserCmd opens a ConfigHub GUI path (see guiPath) in the web browser
+func openInBrowserCmd(path string) tea.Cmd {
 	return func() tea.Msg {
 		// Get current context to find server URL
-		listCmd := exec.Command("cub", "context", "list", "--json")
-		listOutput, err := listCmd.Output()
+		listOutput, err := runCubCommand("context", "list", "--json")
 		if err != nil {
 			return statusUpdateMsg{msg: "Failed to get context info"}
 		}
@@ -1584,8 +1654,7 @@ func openSpaceInBrowserCmd(spaceID string) tea.Cmd {
 			return statusUpdateMsg{msg: "No active context found"}
 		}
 
-		// Build URL: {serverURL}/spaces/{spaceID}
-		url := fmt.Sprintf("%s/spaces/%s", serverURL, spaceID)
+		url := strings.TrimSuffix(serverURL, "/") + path
 
 		// Open in browser (macOS) - best-effort, user sees URL in status anyway
 		_ = exec.Command("open", url).Start() //nolint:errcheck // best-effort browser open
@@ -1599,25 +1668,19 @@ type statusUpdateMsg struct {
 	msg string
 }
 
-func switchOrgCmd(orgID string) tea.Cmd {
+// switchOrgCmd switches to a cub context for the org. When no context
+// exists for it, the result says so (noContext), so the TUI can offer a
+// login instead of failing. afterLogin marks the retry after that login.
+func switchOrgCmd(orgID string, afterLogin bool) tea.Cmd {
 	return func() tea.Msg {
-		// First, list all contexts to find one with the target org
-		listCmd := exec.Command("cub", "context", "list", "--json")
-		listOutput, err := listCmd.Output()
-		if err != nil {
-			return authCompleteMsg{success: false, orgID: orgID}
+		fail := func(err error) tea.Msg {
+			return authCompleteMsg{orgID: orgID, err: err, afterLogin: afterLogin}
 		}
 
-		// Parse contexts to find one matching the target org
-		var contexts []struct {
-			Name       string `json:"name"`
-			Coordinate struct {
-				ServerURL      string `json:"serverURL"`
-				OrganizationID string `json:"organizationID"`
-			} `json:"coordinate"`
-		}
-		if err := json.Unmarshal(listOutput, &contexts); err != nil {
-			return authCompleteMsg{success: false, orgID: orgID}
+		// First, list all contexts to find one with the target org
+		contexts, err := listCubContexts()
+		if err != nil {
+			return fail(err)
 		}
 
 		// Find a context that has the target org (by ExternalID)
@@ -1631,18 +1694,26 @@ func switchOrgCmd(orgID string) tea.Cmd {
 
 		if targetContext == "" {
 			// No existing context for this org
-			return authCompleteMsg{success: false, orgID: orgID}
+			return authCompleteMsg{orgID: orgID, noContext: true, afterLogin: afterLogin}
 		}
 
 		// Switch to the found context
-		useCmd := exec.Command("cub", "context", "use", targetContext)
-		if err := useCmd.Run(); err != nil {
-			return authCompleteMsg{success: false, orgID: orgID}
+		if _, err := runCubCommand("context", "use", targetContext); err != nil {
+			return fail(fmt.Errorf("cub context use %s: %w", targetContext, err))
 		}
-		return authCompleteMsg{success: true, orgID: orgID}
+		return authCompleteMsg{success: true, orgID: orgID, afterLogin: afterLogin}
 	}
 }
 
+// orgLoginCmd hands the terminal to `cub auth login`, which signs in and
+// creates a context for the organization chosen in the browser. The switch
+// is retried when it exits.
+func orgLoginCmd(orgID string) tea.Cmd {
+	return tea.ExecProcess(exec.Command("cub", "auth", "login"), func(err error) tea.Msg {
+		return orgLoginDoneMsg{orgID: orgID, err: err}
+	})
+}
+
 // Initialize model
 func initialModel() Model {
 	return initialModelWithContext("")
@@ -1659,17 +1730,17 @@ func initialModelWithContext(appContext string) Model {
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
 
-	// Get current kubectl context for cluster filtering
-	contextName := getCurrentContext()
-	clusterName := extractClusterName(contextName)
+	// Get current cluster for filtering units by target
+	cluster := currentCluster()
 
 	m := Model{
 		keymap:         defaultKeyMap(),
 		loading:        true,
 		detailsPane:    vp,
 		spinner:        s,
-		contextName:    contextName,
-		currentCluster: clusterName,
+		contextName:    cluster.Context,
+		currentCluster: cluster.Name,
+		clusterTargets: cluster.Targets,
 		showAllUnits:   false, // Default to showing only current cluster's units
 	}
 
@@ -1682,7 +1753,10 @@ func initialModelWithContext(appContext string) Model {
 		if snap := loadHubSnapshot(); snap != nil {
 			m.cursor = snap.Cursor
 			m.mapsMode = snap.MapsMode
-			m.pendingSnapshot = snap // Save for expanded paths restoration after data loads
+			m.searchQuery = snap.SearchQuery
+			m.filterActive = snap.FilterActive && snap.SearchQuery != ""
+			m.treeOffset = snap.TreeOffset
+			m.pendingSnapshot = snap // Save for path-based restoration after data loads
 		}
 	}
 
@@ -1694,6 +1768,25 @@ func (m Model) Init() tea.Cmd {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer saveHubSnapshotOnPanic(&m)
+	if _, ok := msg.(tea.KeyMsg); ok && !m.loading {
+		m.pendingSnapshot = nil // the user has moved on; don't jump the cursor later
+		m.pendingFocus = nil
+	}
+	m.recordBackgroundError(msg)
+	invalidateCubCache(msg)
+	next, cmd := m.update(msg)
+	switch nm := next.(type) {
+	case Model:
+		nm.scrollTreeToCursor()
+		return nm, cmd
+	case *Model:
+		nm.scrollTreeToCursor()
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		// Handle auth prompt
@@ -1703,7 +1796,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.authPrompt = false
 				m.loading = true
 				m.statusMsg = fmt.Sprintf("Switching to %s...", m.authOrgName)
-				return m, switchOrgCmd(m.authOrgID)
+				return m, switchOrgCmd(m.authOrgID, false)
 			case "n", "N", "esc", "q":
 				m.authPrompt = false
 				m.authOrgName = ""
@@ -1713,6 +1806,49 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle the no-context prompt: log in to get a context for the org
+		if m.orgLoginPrompt {
+			switch msg.String() {
+			case "l", "L", "y", "Y", "enter":
+				m.orgLoginPrompt = false
+				m.loading = true
+				m.statusMsg = fmt.Sprintf("Logging in to %s...", m.authOrgName)
+				return m, orgLoginCmd(m.authOrgID)
+			case "n", "N", "esc", "q":
+				m.orgLoginPrompt = false
+				m.statusMsg = fmt.Sprintf("Not switched. To add %s later: cub auth login (choose %s), then press r", m.authOrgName, m.authOrgName)
+				m.authOrgName = ""
+				m.authOrgID = ""
+				return m, nil
+			}
+			return m, nil
+		}
+
+		// Error drawer, reachable from the tree and the wizards
+		if m.errorDrawer {
+			return m.updateErrorDrawer(msg)
+		}
+		if key.Matches(msg, m.keymap.Errors) && !m.typingText() {
+			m.openErrorDrawer()
+			return m, nil
+		}
+
+		// Workers panel, reachable from the tree
+		if m.workerPanel {
+			return m.updateWorkerPanel(msg)
+		}
+		if key.Matches(msg, m.keymap.Workers) && !m.typingText() && !m.importMode && !m.createMode && !m.deleteMode {
+			return m, m.openWorkerPanel()
+		}
+
+		// Changesets panel, reachable from the tree
+		if m.changeSetPanel {
+			return m.updateChangeSetPanel(msg)
+		}
+		if key.Matches(msg, m.keymap.ChangeSets) && !m.typingText() && !m.importMode && !m.createMode && !m.deleteMode {
+			return m, m.openChangeSetPanel()
+		}
+
 		// Handle import wizard mode
 		if m.importMode {
 			return m.updateImportWizard(msg)
@@ -1764,6 +1900,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle fuzzy jump overlay
+		if m.jumpMode {
+			return m.updateJump(msg)
+		}
+
 		// Handle help overlay mode - dismiss on any key
 		if m.helpMode {
 			m.helpMode = false
@@ -1824,7 +1965,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "esc":
 				m.detailsFocused = false
 				return m, nil
-			case "O", ":", "L", "?":
+			case "O", ":", "L", "?", "ctrl+p":
 				// Global keys - fall through to main handler
 				m.detailsFocused = false
 			default:
@@ -1968,8 +2109,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.cursor < len(m.flatList) {
 				node := m.flatList[m.cursor]
 
-				// Check if this is an org that's not the current one
-				if node.Type == "org" {
+				// Check if this is an org that's not the current one;
+				// orgs read in guest mode expand to their spaces
+				if node.Type == "org" && !m.isGuestOrg(node) {
 					orgData, ok := node.Data.(CubOrganization)
 					if ok {
 						isCurrentOrg := m.isCurrentOrg(orgData)
@@ -1985,22 +2127,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// Expand node
 				if !node.Expanded {
-					// Load data on-demand for groups
-					if node.Type == "group" && len(node.Children) == 0 {
-						// Find the space slug from parent
-						if node.Parent != nil && node.Parent.Type == "space" {
-							spaceSlug := node.Parent.ID
-							m.loadGroupData(node, spaceSlug)
-						}
-					}
 					// Load detail children for units
 					if node.Type == "unit" && len(node.Children) == 0 {
 						if unitData, ok := node.Data.(CubUnitData); ok {
 							node.Children = buildUnitDetailChildren(unitData, node)
+							m.treeChanged()
 						}
 					}
 					node.Expanded = true
 					m.rebuildFlatList()
+					// Load the space's contents in the background on first expand
+					if spaceSlug := spaceSlugOf(node); spaceSlug != "" {
+						return m, m.requestSpaceLoad(spaceSlug)
+					}
 				}
 			}
 
@@ -2041,6 +2180,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.searchQuery = ""
 			m.searchMatches = nil
 
+		case key.Matches(msg, m.keymap.Jump):
+			m.openJump()
+			return m, nil
+
 		case key.Matches(msg, m.keymap.NextMatch):
 			m.nextSearchMatch()
 
@@ -2081,6 +2224,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, m.keymap.Refresh):
+			cubCache.Purge()
 			m.loading = true
 			m.statusMsg = "Refreshing..."
 			return m, loadDataCmd
@@ -2112,22 +2256,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.panelLoading = true
 			// Collect unit slugs for correlation
 			var unitSlugs []string
-			for _, node := range m.nodes {
-				if node.Type == "organization" {
-					for _, spaceNode := range node.Children {
-						if spaceNode.Type == "space" {
-							for _, groupNode := range spaceNode.Children {
-								if groupNode.Type == "units" {
-									for _, unitNode := range groupNode.Children {
-										if unitData, ok := unitNode.Data.(CubUnitData); ok {
-											unitSlugs = append(unitSlugs, unitData.Unit.Slug)
-										}
-									}
-								}
-							}
-						}
-					}
-				}
+			for _, unit := range m.loadedUnits() {
+				unitSlugs = append(unitSlugs, unit.Unit.Slug)
 			}
 			return m, loadPanelDataCmd(unitSlugs)
 
@@ -2218,14 +2348,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case key.Matches(msg, m.keymap.OpenWeb):
-			// Open the current space in the web browser
+			// Open the node's page in the ConfigHub GUI
 			if m.cursor < len(m.flatList) {
-				node := m.flatList[m.cursor]
-				spaceID := m.getSpaceIDFromNode(node)
-				if spaceID != "" {
-					return m, openSpaceInBrowserCmd(spaceID)
-				}
-				m.statusMsg = "No space selected"
+				return m, openInBrowserCmd(m.guiPath(m.flatList[m.cursor]))
 			}
 		}
 
@@ -2255,6 +2380,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case dataLoadedMsg:
 		m.nodes = msg.nodes
+		m.treeChanged()
+		m.spaceLoads = make(map[string]spaceLoadState)
 		m.currentOrg = msg.currentOrg
 		m.currentOrgInt = msg.currentOrgInt
 		m.loading = false
@@ -2277,7 +2404,6 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			restoreExpanded(m.nodes, "")
-			m.pendingSnapshot = nil // Clear after restoration
 		}
 
 		m.rebuildFlatList()
@@ -2315,20 +2441,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.detailsPane.SetContent(m.detailsContent)
 		}
 
-		// Trigger background loading for all spaces
-		if len(msg.spacesToLoad) > 0 {
-			var cmds []tea.Cmd
-			for _, spaceSlug := range msg.spacesToLoad {
-				cmds = append(cmds, loadSpaceDataCmd(spaceSlug))
-			}
-			return m, tea.Batch(cmds...)
+		// Trigger background loading for the startup spaces and any
+		// space expanded by the restored snapshot
+		cmds := []tea.Cmd{m.restoreSnapshotPositions(), m.applyFocus()}
+		for _, spaceSlug := range msg.spacesToLoad {
+			cmds = append(cmds, m.requestSpaceLoad(spaceSlug))
 		}
+		cmds = append(cmds, m.loadExpandedSpaces())
+		// Other orgs are read in guest mode, without switching context
+		if orgs := m.nonCurrentOrgs(); len(orgs) > 0 {
+			cmds = append(cmds, loadGuestOrgsCmd(orgs))
+		}
+		return m, tea.Batch(cmds...)
+
+	case workerPanelTickMsg:
+		if m.workerPanel {
+			return m, workerPanelTick()
+		}
+		return m, nil
+
+	case guestOrgsLoadedMsg:
+		m.applyGuestOrgs(msg)
+		return m, nil
+
+	case changeSetsLoadedMsg:
+		m.changeSetsLoading = false
+		m.changeSets, m.changeSetsErr = msg.sets, msg.err
+		if m.changeSetCursor >= len(m.changeSets) {
+			m.changeSetCursor = 0
+			m.changeSetDrill = false
+		}
+		return m, nil
 
 	case spaceDataLoadedMsg:
 		// Update the tree in place without resetting cursor or expanded state
-		if msg.err == nil {
-			m.updateSpaceData(msg.spaceSlug, msg.units, msg.targets, msg.workers)
+		if msg.err != nil {
+			delete(m.spaceLoads, msg.spaceSlug) // retry on next expand
+			m.statusMsg = fmt.Sprintf("Failed to load space %s: %v", msg.spaceSlug, msg.err)
+			if m.pendingFocus != nil && m.pendingFocus.Space == msg.spaceSlug {
+				m.pendingFocus = nil
+			}
+		} else {
+			m.spaceLoads[msg.spaceSlug] = spaceLoaded
+			m.updateSpaceData(msg.spaceSlug, msg.units, msg.targets, msg.workers, msg.health)
 			m.rebuildFlatList()
+			// A restored cursor or --focus may point into this space
+			return m, tea.Batch(m.restoreSnapshotPositions(), m.applyFocus())
 		}
 
 	case panelDataLoadedMsg:
@@ -2363,16 +2521,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.detailsPane.SetContent(m.detailsContent)
 		m.detailsPane.GotoTop()
+		if m.detailsRestore > 0 {
+			m.detailsPane.SetYOffset(m.detailsRestore)
+			m.detailsRestore = 0
+		}
 
 	case authCompleteMsg:
-		if msg.success {
+		switch {
+		case msg.success:
 			m.statusMsg = "Switched org, reloading..."
 			return m, loadDataCmd
-		} else {
+		case msg.noContext && !msg.afterLogin:
+			m.loading = false
+			m.orgLoginPrompt = true
+		case msg.noContext:
+			// Logged in, but to a different org; the current context changed
+			// with the login, so reload to show where we are now
+			m.statusMsg = fmt.Sprintf("Logged in, but no context for %s yet. Run cub auth login and choose %s in the browser", m.authOrgName, m.authOrgName)
+			return m, loadDataCmd
+		default:
 			m.loading = false
-			m.statusMsg = "Failed to switch organization"
+			m.statusMsg = fmt.Sprintf("Failed to switch to %s: %v", m.authOrgName, msg.err)
 		}
 
+	case orgLoginDoneMsg:
+		if msg.err != nil {
+			m.loading = false
+			m.statusMsg = fmt.Sprintf("cub auth login failed: %v", msg.err)
+			return m, nil
+		}
+		m.statusMsg = fmt.Sprintf("Logged in, switching to %s...", m.authOrgName)
+		return m, switchOrgCmd(msg.orgID, true)
+
 	case statusUpdateMsg:
 		m.statusMsg = msg.msg
 
@@ -2388,6 +2568,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.importNamespaces = msg.namespaces
+		m.importNamespaceWarnings = msg.warnings
 
 	case argoAppsLoadedMsg:
 		m.importLoading = false
@@ -2396,6 +2577,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.importArgoApps = msg.apps
+		m.importArgoAllApps = msg.apps
+		m.importArgoPath = nil
 
 	case argoResourcesLoadedMsg:
 		m.importLoading = false
@@ -2464,6 +2647,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.importProgress = msg.success
 		m.importTotal = msg.success + msg.failed
 		m.importApplyError = msg.applyError // May be nil if apply succeeded
+		m.importLabelError = msg.labelError
 		// For ArgoCD imports, transition to cleanup step to offer disable/delete
 		if m.importSource == importSourceArgoCD && m.importSelectedArgo != nil {
 			m.importStep = importStepArgoCleanup
@@ -2581,7 +2765,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// We have a running worker - wait for target to be auto-created
 				m.importStep = importStepWaitTarget
 				m.statusMsg = "Waiting for target to be created..."
-				kubeContext := getCurrentKubeContext()
+				kubeContext := getCurrentContext()
 				return m, waitForTargetCmd(m.importSpace, w.Slug, kubeContext)
 			}
 		}
@@ -2629,7 +2813,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.importSelectedWorker = msg.worker // Mark that we have a running worker
 		m.importStep = importStepWaitTarget
 		m.statusMsg = "Waiting for target to be created..."
-		kubeContext := getCurrentKubeContext()
+		kubeContext := getCurrentContext()
 		return m, waitForTargetCmd(m.importSpace, m.importNewWorkerName, kubeContext)
 
 	case targetFoundMsg:
@@ -2738,64 +2922,6 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) loadGroupData(groupNode *TreeNode, spaceSlug string) {
-	switch {
-	case strings.HasSuffix(groupNode.ID, "/units"):
-		units, err := loadUnitsForSpace(spaceSlug)
-		if err == nil {
-			for _, unit := range units {
-				unitNode := &TreeNode{
-					ID:     unit.Unit.Slug,
-					Name:   unit.Unit.Slug,
-					Type:   "unit",
-					Parent: groupNode,
-					Data:   unit,
-					OrgID:  groupNode.OrgID,
-				}
-				unitNode.Status = unit.DeriveStatus()
-				unitNode.Info = buildUnitInfo(unit)
-				groupNode.Children = append(groupNode.Children, unitNode)
-			}
-		}
-
-	case strings.HasSuffix(groupNode.ID, "/targets"):
-		targets, err := loadTargetsForSpace(spaceSlug)
-		if err == nil {
-			for _, target := range targets {
-				targetNode := &TreeNode{
-					ID:     target.Target.Slug,
-					Name:   target.Target.Slug,
-					Type:   "target",
-					Status: "ok",
-					Info:   target.Target.ProviderType,
-					Parent: groupNode,
-					Data:   target,
-					OrgID:  groupNode.OrgID,
-				}
-				groupNode.Children = append(groupNode.Children, targetNode)
-			}
-		}
-
-	case strings.HasSuffix(groupNode.ID, "/workers"):
-		workers, err := loadWorkersForSpace(spaceSlug)
-		if err == nil {
-			for _, worker := range workers {
-				workerNode := &TreeNode{
-					ID:     worker.BridgeWorker.Slug,
-					Name:   worker.BridgeWorker.Slug,
-					Type:   "worker",
-					Status: worker.DeriveStatus(),
-					Info:   worker.BridgeWorker.Condition,
-					Parent: groupNode,
-					Data:   worker,
-					OrgID:  groupNode.OrgID,
-				}
-				groupNode.Children = append(groupNode.Children, workerNode)
-			}
-		}
-	}
-}
-
 // Import wizard methods
 func (m *Model) updateImportWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -2816,6 +2942,18 @@ func (m *Model) updateImportWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.importViewingConfig = false
 			return m, nil
 		}
+		if m.importStep == importStepArgoApps && len(m.importArgoPath) > 0 {
+			// Close the innermost App of Apps
+			m.importArgoPath = m.importArgoPath[:len(m.importArgoPath)-1]
+			if n := len(m.importArgoPath); n > 0 {
+				m.importArgoApps = argoChildInfos(m.importArgoAllApps, m.importArgoPath[n-1])
+			} else {
+				m.importArgoApps = m.importArgoAllApps
+			}
+			m.importCursor = 0
+			m.importError = nil
+			return m, nil
+		}
 		if m.importStep == importStepExtractConfig {
 			// Go back to selection step
 			m.importStep = importStepSelection
@@ -3062,10 +3200,18 @@ func (m *Model) advanceImportStep() (tea.Model, tea.Cmd) {
 	case importStepArgoApps:
 		if m.importCursor < len(m.importArgoApps) {
 			selectedApp := m.importArgoApps[m.importCursor]
-			// Warn about App of Apps
+			// Open App of Apps to pick one of its children
 			if selectedApp.IsAppOfApps {
-				m.importError = fmt.Errorf("'%s' is an App of Apps that manages %d child Applications.\n\nApp of Apps don't contain workloads directly - import the child apps instead:\n  %s",
-					selectedApp.Name, len(selectedApp.ChildApps), strings.Join(selectedApp.ChildApps, ", "))
+				children := argoChildInfos(m.importArgoAllApps, selectedApp)
+				if len(children) == 0 {
+					m.importError = fmt.Errorf("'%s' is an App of Apps, but none of its %d child Applications are listed here.\n\nImport them all from the CLI instead:\n  cub-scout import-argocd %s --recursive",
+						selectedApp.Name, len(selectedApp.ChildApps), selectedApp.Name)
+					return m, nil
+				}
+				m.importArgoPath = append(m.importArgoPath, selectedApp)
+				m.importArgoApps = children
+				m.importCursor = 0
+				m.importError = nil
 				return m, nil
 			}
 			m.importLoading = true
@@ -3152,15 +3298,20 @@ func (m *Model) advanceImportStep() (tea.Model, tea.Cmd) {
 		}
 
 	case importStepExtractConfig:
-		// User confirmed extracted configs, proceed to import
+		// User confirmed extracted configs and the changes listed with
+		// them, proceed to import
 		selected := m.getSelectedWorkloads()
 		if len(selected) > 0 {
+			if err := checkChanges(m.importChanges()); err != nil {
+				m.importError = err
+				return m, nil
+			}
 			m.importStep = importStepImporting
 			m.importTotal = len(selected)
 			// ArgoCD imports: use chosen unit structure
 			if m.importSource == importSourceArgoCD && m.importSelectedArgo != nil {
 				if m.importUnitStructure == unitStructureCombined {
-					return m, importArgoWorkloadsCmd(m.importSpace, m.importSelectedArgo.Name, m.importNewTargetName, selected)
+					return m, importArgoWorkloadsCmd(m.importSpace, m.importSelectedArgo.Name, m.importNewTargetName, argoTreeLabels(*m.importSelectedArgo), selected)
 				}
 				// Individual units - use standard import
 				return m, importWorkloadsCmd(m.importSpace, selected)
@@ -3176,6 +3327,12 @@ func (m *Model) advanceImportStep() (tea.Model, tea.Cmd) {
 		// Handle ArgoCD cleanup choice
 		m.importArgoCleanup = m.importCursor
 		if m.importSelectedArgo != nil {
+			if err := checkChanges(m.argoApplyChanges()); err != nil {
+				m.importError = err
+				m.importStep = importStepTest
+				m.importCursor = testOptionSkip
+				return m, nil
+			}
 			switch m.importCursor {
 			case argoCleanupDisableSync:
 				m.importLoading = true
@@ -3267,8 +3424,7 @@ func (m *Model) getSpaceIDFromNode(node *TreeNode) string {
 // getSpaceURL returns the URL to open the current import space in browser
 func (m *Model) getSpaceURL() string {
 	// Get server URL from current context
-	ctxCmd := exec.Command("cub", "context", "get", "--json")
-	ctxOutput, err := ctxCmd.Output()
+	ctxOutput, err := runCubCommand("context", "get", "--json")
 	if err != nil {
 		return ""
 	}
@@ -3288,8 +3444,7 @@ func (m *Model) getSpaceURL() string {
 	}
 
 	// Get space ID from space slug
-	spaceCmd := exec.Command("cub", "space", "list", "--json")
-	spaceOutput, err := spaceCmd.Output()
+	spaceOutput, err := runCubCommand("space", "list", "--json")
 	if err != nil {
 		return fmt.Sprintf("%s/spaces/%s", serverURL, m.importSpace)
 	}
@@ -3319,6 +3474,7 @@ func (m *Model) resetImportState() {
 	m.importLoading = false
 	m.importError = nil
 	m.importApplyError = nil
+	m.importLabelError = nil
 	m.importProgress = 0
 	m.importTotal = 0
 	// Reset config extraction state
@@ -3340,6 +3496,8 @@ func (m *Model) resetImportState() {
 	// Reset ArgoCD import state
 	m.importSource = importSourceKubernetes
 	m.importArgoApps = nil
+	m.importArgoAllApps = nil
+	m.importArgoPath = nil
 	m.importSelectedArgo = nil
 	m.importArgoResources = nil
 	m.importArgoCleanup = argoCleanupKeepAsIs
@@ -3360,6 +3518,33 @@ func (m *Model) getSelectedWorkloads() []WorkloadInfo {
 	return selected
 }
 
+// importChanges lists what importing the selected workloads changes,
+// following the same choice of import command as the extract config step.
+func (m *Model) importChanges() []plannedChange {
+	selected := m.getSelectedWorkloads()
+	var changes []plannedChange
+	if m.importSource == importSourceArgoCD && m.importSelectedArgo != nil {
+		if m.importUnitStructure == unitStructureCombined {
+			return importUnitChanges(m.importSpace, m.importSelectedArgo.Name+"-workload", selected)
+		}
+	} else if m.importGroupedView && m.importSuggestion != nil {
+		for _, unit := range suggestedUnits(m.importSuggestion, selected) {
+			changes = append(changes, importUnitChanges(m.importSpace, unit.slug, unit.workloads)...)
+		}
+		return changes
+	}
+	for _, w := range selected {
+		changes = append(changes, importUnitChanges(m.importSpace, w.Name, []WorkloadInfo{w})...)
+	}
+	return changes
+}
+
+// argoApplyChanges lists applying the imported Argo CD unit, which every
+// choice of the Argo CD cleanup step does.
+func (m *Model) argoApplyChanges() []plannedChange {
+	return applyUnitChanges(m.importSpace, m.importSelectedArgo.Name+"-workload", m.getSelectedWorkloads())
+}
+
 // Create wizard methods
 func (m *Model) updateCreateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -3826,6 +4011,14 @@ func (m Model) renderHelpOverlay() string {
 	b.WriteString("  " + keyStyle.Render("Enter") + "      " + descStyle.Render("Load details in right pane"))
 	b.WriteString("\n")
 	b.WriteString("  " + keyStyle.Render("Tab") + "        " + descStyle.Render("Switch focus to details pane"))
+	b.WriteString("\n")
+	b.WriteString("  " + keyStyle.Render("ctrl+p") + "     " + descStyle.Render("Jump to any loaded org, space or unit"))
+	b.WriteString("\n")
+	b.WriteString("  " + keyStyle.Render("e") + "          " + descStyle.Render("Recent errors with command, stderr and retry"))
+	b.WriteString("\n")
+	b.WriteString("  " + keyStyle.Render("W") + "          " + descStyle.Render("Workers: last seen, run command, relaunch supervised"))
+	b.WriteString("\n")
+	b.WriteString("  " + keyStyle.Render("C") + "          " + descStyle.Render("Changesets: apply progress per target, failed units"))
 	b.WriteString("\n\n")
 
 	b.WriteString(sectionStyle.Render("SEARCH & FILTER"))
@@ -3853,7 +4046,7 @@ func (m Model) renderHelpOverlay() string {
 	b.WriteString("\n")
 	b.WriteString("  " + keyStyle.Render("i") + "          " + descStyle.Render("Import workloads from Kubernetes"))
 	b.WriteString("\n")
-	b.WriteString("  " + keyStyle.Render("o") + "          " + descStyle.Render("Open in browser"))
+	b.WriteString("  " + keyStyle.Render("o") + "          " + descStyle.Render("Open in ConfigHub GUI"))
 	b.WriteString("\n")
 	b.WriteString("  " + keyStyle.Render("O") + "          " + descStyle.Render("Switch organization"))
 	b.WriteString("\n")
@@ -4037,19 +4230,20 @@ func (m Model) renderActivityView() string {
 		if node.Type == "target" {
 			targetsFound = true
 			targetName := node.ID
-			status := dimStyle.Render("Unknown")
-			if targetData, ok := node.Data.(CubTargetData); ok {
-				if targetData.Target.Slug != "" {
-					targetName = targetData.Target.Slug
-				}
-				// Targets don't have a condition field, show provider instead
-				provider := targetData.Target.ProviderType
-				if provider == "" {
-					provider = "Kubernetes"
-				}
-				status = dimStyle.Render(provider)
+			if targetData, ok := node.Data.(CubTargetData); ok && targetData.Target.Slug != "" {
+				targetName = targetData.Target.Slug
+			}
+			// Status comes from the target probe (worker liveness, KubeContext reachability)
+			var status string
+			switch node.Status {
+			case "ok":
+				status = syncedStyle.Render("● Healthy")
+			case "error":
+				status = errorStyle.Render("○ Error")
+			default:
+				status = notSyncedStyle.Render("◐ Warning")
 			}
-			b.WriteString(fmt.Sprintf("  ●  %-20s  %s", nameStyle.Render(targetName), status))
+			b.WriteString(fmt.Sprintf("  %s  %-20s  %s", status, nameStyle.Render(targetName), dimStyle.Render(node.Info)))
 			b.WriteString("\n")
 		}
 	}
@@ -4265,25 +4459,7 @@ func (m Model) renderPanelView() string {
 		paneWidth = (m.width - 10) / 2
 	}
 
-	// Collect units from the tree
-	var units []CubUnitData
-	for _, node := range m.nodes {
-		if node.Type == "organization" {
-			for _, spaceNode := range node.Children {
-				if spaceNode.Type == "space" {
-					for _, groupNode := range spaceNode.Children {
-						if groupNode.Type == "units" {
-							for _, unitNode := range groupNode.Children {
-								if unitData, ok := unitNode.Data.(CubUnitData); ok {
-									units = append(units, unitData)
-								}
-							}
-						}
-					}
-				}
-			}
-		}
-	}
+	units := m.loadedUnits()
 
 	// WET column header
 	wetHeader := sectionStyle.Render("WET (ConfigHub)")
@@ -4375,6 +4551,26 @@ func (m Model) renderPanelView() string {
 	return b.String()
 }
 
+// loadedUnits returns the units loaded so far in every space of the tree.
+func (m Model) loadedUnits() []CubUnitData {
+	var units []CubUnitData
+	for _, org := range m.nodes {
+		for _, space := range org.Children {
+			if space.Type != "space" {
+				continue
+			}
+			for _, group := range space.Children {
+				for _, node := range group.Children {
+					if unit, ok := node.Data.(CubUnitData); ok && node.Type == "unit" {
+						units = append(units, unit)
+					}
+				}
+			}
+		}
+	}
+	return units
+}
+
 // renderSuggestView shows suggested ConfigHub units from cluster workloads
 func (m Model) renderSuggestView() string {
 	var b strings.Builder
@@ -4797,7 +4993,15 @@ func (m Model) renderImportWizard() string {
 		b.WriteString(dimStyle.Render("↑↓ navigate  Enter select  Esc cancel"))
 
 	case importStepArgoApps:
-		b.WriteString("Select an ArgoCD Application to import:\n\n")
+		if len(m.importArgoPath) > 0 {
+			var names []string
+			for _, p := range m.importArgoPath {
+				names = append(names, p.Name)
+			}
+			b.WriteString(fmt.Sprintf("Select a child of App of Apps %s to import:\n\n", activeStyle.Render(strings.Join(names, " › "))))
+		} else {
+			b.WriteString("Select an ArgoCD Application to import:\n\n")
+		}
 		if m.importLoading {
 			b.WriteString(dimStyle.Render("Loading ArgoCD Applications...\n\n"))
 		} else if m.importError != nil && len(m.importArgoApps) == 0 {
@@ -4867,11 +5071,21 @@ func (m Model) renderImportWizard() string {
 				))
 			}
 			b.WriteString("\n")
-			b.WriteString(dimStyle.Render("↑↓ navigate  Enter select  Esc cancel"))
+			if len(m.importArgoPath) > 0 {
+				b.WriteString(dimStyle.Render("↑↓ navigate  Enter select  Esc back to parent"))
+			} else {
+				b.WriteString(dimStyle.Render("↑↓ navigate  Enter select ([AoA] opens children)  Esc cancel"))
+			}
 		}
 
 	case importStepNamespace:
 		b.WriteString("Select a Kubernetes namespace to import:\n\n")
+		for _, w := range m.importNamespaceWarnings {
+			b.WriteString(statusWarn.Render("⚠ "+w) + "\n")
+		}
+		if len(m.importNamespaceWarnings) > 0 {
+			b.WriteString("\n")
+		}
 		if len(m.importNamespaces) == 0 {
 			// No namespaces available - show helpful message
 			if m.importError != nil {
@@ -5013,7 +5227,7 @@ func (m Model) renderImportWizard() string {
 		b.WriteString(fmt.Sprintf("Namespace: %s\n", activeStyle.Render(m.importNamespace)))
 		b.WriteString(fmt.Sprintf("Space: %s  Worker: %s\n\n", activeStyle.Render(m.importSpace), activeStyle.Render(m.importNewWorkerName)))
 		b.WriteString("Waiting for target to be auto-created...\n\n")
-		kubeCtx := getCurrentKubeContext()
+		kubeCtx := getCurrentContext()
 		expectedSlug := fmt.Sprintf("%s-kubernetes-yaml-%s", m.importNewWorkerName, strings.ReplaceAll(kubeCtx, "/", "-"))
 		b.WriteString(dimStyle.Render("Expected target: ") + expectedSlug + "\n")
 		b.WriteString(dimStyle.Render("Kubernetes context: ") + kubeCtx + "\n\n")
@@ -5101,10 +5315,20 @@ func (m Model) renderImportWizard() string {
 					}
 
 					unitInfo := fmt.Sprintf("unit: %s", variant.UnitSlug)
+					if variant.Upstream != "" {
+						unitInfo += fmt.Sprintf(" (variant=%s, clones %s)", variant.Name, variant.Upstream)
+					}
+					unitInfo += fmt.Sprintf("  %.0f%% confidence", variant.Confidence*100)
 					b.WriteString(fmt.Sprintf("%s%s %s  %s\n",
 						cursor, unitCheck,
 						groupStyle.Render(app.Name),
 						dimStyle.Render(unitInfo)))
+					// Why this grouping: shown for the unit under the cursor
+					if lineNum == m.importCursor {
+						for _, r := range variant.Rationale {
+							b.WriteString(dimStyle.Render("      • "+r) + "\n")
+						}
+					}
 					lineNum++
 
 					// Workloads under this unit
@@ -5310,6 +5534,7 @@ func (m Model) renderImportWizard() string {
 				b.WriteString(fmt.Sprintf("%s%s %s %s\n", padRight(cursor, 2), padRight(statusIcon, 1), padRight(w.Name, 20), status))
 			}
 
+			printPlannedChanges(&b, m.importChanges())
 			b.WriteString("\n")
 			b.WriteString(dimStyle.Render("↑↓ navigate  v view config  Enter proceed  Esc back"))
 		}
@@ -5347,6 +5572,7 @@ func (m Model) renderImportWizard() string {
 				b.WriteString(fmt.Sprintf("%s%s\n", cursor, groupStyle.Render(opt.name)))
 				b.WriteString(fmt.Sprintf("   %s\n", style.Render(opt.desc)))
 			}
+			printPlannedChanges(&b, m.argoApplyChanges())
 			b.WriteString("\n")
 			b.WriteString(dimStyle.Render("↑↓ navigate  Enter select"))
 		}
@@ -5369,6 +5595,10 @@ func (m Model) renderImportWizard() string {
 			b.WriteString(dimStyle.Render(m.importApplyError.Error()) + "\n\n")
 			b.WriteString(dimStyle.Render("Pipeline test may not work without successful apply.") + "\n\n")
 		}
+		if m.importLabelError != nil {
+			b.WriteString(statusWarn.Render("⚠ Labeling workloads failed") + "\n")
+			b.WriteString(dimStyle.Render(m.importLabelError.Error()) + "\n\n")
+		}
 
 		if m.importLoading {
 			b.WriteString(groupStyle.Render("Testing ConfigHub Pipeline") + "\n\n")
@@ -5475,7 +5705,11 @@ func (m Model) renderImportWizard() string {
 		b.WriteString("\n\n")
 		b.WriteString(statusErr.Render(fmt.Sprintf("Error: %v", m.importError)))
 		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("Press Esc to cancel"))
+		if m.typingText() {
+			b.WriteString(dimStyle.Render("Press Esc to cancel"))
+		} else {
+			b.WriteString(dimStyle.Render("Press e for details, Esc to cancel"))
+		}
 	}
 
 	return b.String()
@@ -5483,7 +5717,7 @@ func (m Model) renderImportWizard() string {
 
 func (m *Model) rebuildFlatList() {
 	m.flatList = nil
-	m.clearMatchCache() // Clear cache when rebuilding
+	m.syncMatchCache() // Reuse results while the query only grows
 
 	for _, node := range m.nodes {
 		// When filter is active and we have a search query, skip nodes that don't match
@@ -5561,24 +5795,24 @@ func (m *Model) unitMatchesCurrentCluster(node *TreeNode) bool {
 		return true // No target, show it (might be unconfigured)
 	}
 
-	// Use the matchesCluster helper for flexible matching
-	return matchesCluster(targetSlug, m.currentCluster)
+	// Registered targets match exactly; otherwise match by cluster name
+	return ClusterEntry{Name: m.currentCluster, Targets: m.clusterTargets}.MatchesTarget(targetSlug)
 }
 
 // addHubAppSpaceView adds spaces grouped into Hub (platform) and AppSpaces (apps)
 func (m *Model) addHubAppSpaceView(orgNode *TreeNode) {
-	// Categorize spaces into Hub (platform) vs AppSpaces
+	// Categorize spaces into Hub (platform) vs AppSpaces by Role label,
+	// the targets they serve, and name
+	hub := make(map[string]bool)
+	for _, name := range orgpattern.Detect(orgPatternSpaces(orgNode.Children)).Hub {
+		hub[name] = true
+	}
 	var hubSpaces, appSpaces []*TreeNode
 	for _, child := range orgNode.Children {
 		if child.Type != "space" {
 			continue
 		}
-		spaceName := child.Name
-		// Hub spaces: platform-*, infra-*, hub-*, shared-*
-		if strings.HasPrefix(spaceName, "platform-") ||
-			strings.HasPrefix(spaceName, "infra-") ||
-			strings.HasPrefix(spaceName, "hub-") ||
-			strings.HasPrefix(spaceName, "shared-") {
+		if hub[child.Name] {
 			hubSpaces = append(hubSpaces, child)
 		} else {
 			appSpaces = append(appSpaces, child)
@@ -5593,6 +5827,7 @@ func (m *Model) addHubAppSpaceView(orgNode *TreeNode) {
 			Type:     "hub_group",
 			Info:     fmt.Sprintf("(%d spaces)", len(hubSpaces)),
 			Parent:   orgNode,
+			Children: hubSpaces,
 			Expanded: true,
 			OrgID:    orgNode.OrgID,
 		}
@@ -5619,6 +5854,7 @@ func (m *Model) addHubAppSpaceView(orgNode *TreeNode) {
 			Type:     "app_group",
 			Info:     fmt.Sprintf("(%d spaces)", len(appSpaces)),
 			Parent:   orgNode,
+			Children: appSpaces,
 			Expanded: true,
 			OrgID:    orgNode.OrgID,
 		}
@@ -5766,6 +6002,7 @@ func (m *Model) injectPendingCreates() {
 
 // removeNodeFromTree removes a node from the tree structure (for delete completion)
 func (m *Model) removeNodeFromTree(nodeType, name, parentID string) {
+	m.treeChanged()
 	// Find and remove from parent's children
 	for _, node := range m.nodes {
 		if m.removeNodeRecursive(node, nodeType, name, parentID) {
@@ -5797,6 +6034,7 @@ func (m *Model) removeNodeRecursive(node *TreeNode, nodeType, name, parentID str
 
 // insertCreatedNode adds a newly created node to the tree (for create completion)
 func (m *Model) insertCreatedNode(nodeType, name, parentID string) {
+	m.treeChanged()
 	newNode := &TreeNode{
 		ID:       name,
 		Name:     name,
@@ -5848,8 +6086,10 @@ func (m *Model) insertNodeRecursive(node *TreeNode, newNode *TreeNode, nodeType,
 }
 
 // updateSpaceData updates a space's children with loaded data (units, targets, workers)
-// This preserves the tree structure and expanded state
-func (m *Model) updateSpaceData(spaceSlug string, units []CubUnitData, targets []CubTargetData, workers []CubWorkerData) {
+// This preserves the tree structure and expanded state. Targets take their
+// status from health; targets without a probe result show as ok.
+func (m *Model) updateSpaceData(spaceSlug string, units []CubUnitData, targets []CubTargetData, workers []CubWorkerData, health map[string]hierarchysvc.TargetHealth) {
+	m.treeChanged()
 	// Find the space node in the tree
 	for _, orgNode := range m.nodes {
 		for _, spaceNode := range orgNode.Children {
@@ -5870,7 +6110,7 @@ func (m *Model) updateSpaceData(spaceSlug string, units []CubUnitData, targets [
 								OrgID:  spaceNode.OrgID,
 							}
 							unitNode.Status = unit.DeriveStatus()
-							unitNode.Info = buildUnitInfo(unit)
+							unitNode.Info = buildUnitInfo(unit, "")
 							groupNode.Children = append(groupNode.Children, unitNode)
 						}
 
@@ -5887,6 +6127,12 @@ func (m *Model) updateSpaceData(spaceSlug string, units []CubUnitData, targets [
 								Data:   target,
 								OrgID:  spaceNode.OrgID,
 							}
+							if h, ok := health[target.Target.Slug]; ok {
+								targetNode.Status = h.Status
+								if h.Status != hierarchysvc.TargetOK {
+									targetNode.Info += " · " + h.Summary()
+								}
+							}
 							groupNode.Children = append(groupNode.Children, targetNode)
 						}
 
@@ -5907,6 +6153,7 @@ func (m *Model) updateSpaceData(spaceSlug string, units []CubUnitData, targets [
 						}
 					}
 				}
+				m.annotatePromotionLag(orgNode)
 				return
 			}
 		}
@@ -5915,6 +6162,13 @@ func (m *Model) updateSpaceData(spaceSlug string, units []CubUnitData, targets [
 
 // loadEntityDetailsCmd fetches full details for an entity and formats for display
 func loadEntityDetailsCmd(node *TreeNode) tea.Cmd {
+	// Units loaded in the tree give a unit's clones and what a space's
+	// triggers gate; collected here, before the command runs, as the tree
+	// belongs to the update loop
+	var orgUnits map[string][]CubUnitData
+	if node != nil && (node.Type == "unit" || node.Type == "space") {
+		orgUnits = loadedUnits(node)
+	}
 	return func() tea.Msg {
 		if node == nil {
 			return detailsLoadedMsg{
@@ -5943,8 +6197,22 @@ func loadEntityDetailsCmd(node *TreeNode) tea.Cmd {
 				// Fetch full unit details
 				output, err := runCubCommand("unit", "get", "--space", spaceSlug, "--json", unitSlug)
 				if err == nil {
+					// Links are listed from the unit's space, so links into it
+					// from other spaces are not shown
+					links, _ := loadLinksForSpace(spaceSlug)
+					g := buildUnitGraph(orgUnits, links)
+					sections := formatUnitRelations(g, UnitRef{Space: spaceSlug, Unit: unitSlug})
+
+					// Triggers that rewrite the unit are matched to the
+					// revisions they made
+					if triggers, _ := loadTriggersForSpace(spaceSlug); len(triggers) > 0 {
+						revisions, _ := fetchConfigHubRevisions(spaceSlug, unitSlug)
+						infos := buildTriggerInfos(spaceSlug, triggers, nil, nil)
+						sections += formatUnitFunctions(infos, unitData, revisions, time.Now())
+					}
+
 					// Parse and format the detailed output
-					content := formatUnitDetails(output, unitData)
+					content := formatUnitDetails(output, unitData, sections)
 					return detailsLoadedMsg{
 						node:    node,
 						content: content,
@@ -5955,6 +6223,14 @@ func loadEntityDetailsCmd(node *TreeNode) tea.Cmd {
 			}
 		}
 
+		// For spaces, list the triggers ahead of the cached data
+		functions := ""
+		if node.Type == "space" {
+			if triggers, _ := loadTriggersForSpace(node.ID); len(triggers) > 0 {
+				functions = formatSpaceFunctions(buildTriggerInfos(node.ID, triggers, orgUnits[node.ID], nil))
+			}
+		}
+
 		// Default: show cached data as formatted JSON
 		if node.Data == nil {
 			return detailsLoadedMsg{
@@ -5973,14 +6249,93 @@ func loadEntityDetailsCmd(node *TreeNode) tea.Cmd {
 
 		return detailsLoadedMsg{
 			node:    node,
-			content: string(jsonBytes),
+			content: functions + string(jsonBytes),
 			err:     nil,
 		}
 	}
 }
 
+// loadedUnits collects the units loaded into node's org, keyed by space.
+func loadedUnits(node *TreeNode) map[string][]CubUnitData {
+	for node.Parent != nil {
+		node = node.Parent
+	}
+	units := map[string][]CubUnitData{}
+	seen := map[string]bool{}
+	var walk func(n *TreeNode, space string)
+	walk = func(n *TreeNode, space string) {
+		if n.Type == "space" {
+			space = n.ID
+		}
+		if u, ok := n.Data.(CubUnitData); ok && n.Type == "unit" && !seen[space+"/"+u.Unit.Slug] {
+			seen[space+"/"+u.Unit.Slug] = true
+			units[space] = append(units[space], u)
+		}
+		for _, c := range n.Children {
+			walk(c, space)
+		}
+	}
+	walk(node, "")
+	return units
+}
+
+// annotatePromotionLag notes on each unit under org how far it trails its
+// upstream. A clone is noted once its upstream's space has loaded too.
+func (m *Model) annotatePromotionLag(org *TreeNode) {
+	g := buildUnitGraph(loadedUnits(org), nil)
+	var walk func(n *TreeNode, space string)
+	walk = func(n *TreeNode, space string) {
+		if n.Type == "space" {
+			space = n.ID
+		}
+		if u, ok := n.Data.(CubUnitData); ok && n.Type == "unit" {
+			n.Info = buildUnitInfo(u, promotionLagInfo(g, UnitRef{Space: space, Unit: u.Unit.Slug}))
+		}
+		for _, c := range n.Children {
+			walk(c, space)
+		}
+	}
+	walk(org, "")
+}
+
+// formatUnitRelations describes a unit's upstream, links and fan-out for
+// the details pane, or returns "" when it has none.
+func formatUnitRelations(g *UnitGraph, ref UnitRef) string {
+	n := g.Get(ref)
+	if n == nil || (n.Upstream == nil && len(n.Downstream) == 0 && len(n.DependsOn) == 0 && len(n.DependedBy) == 0) {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("DEPENDENCIES\n")
+	b.WriteString("─────────────────────────────────────\n")
+	if n.Upstream != nil {
+		line := n.Upstream.String()
+		if up := g.Get(*n.Upstream); up != nil {
+			line += fmt.Sprintf(" (rev %d of %d)", n.UpstreamRevision, up.HeadRevision)
+			if n.UpstreamRevision < up.HeadRevision {
+				line += " ⚠️ behind"
+			}
+		}
+		b.WriteString("Upstream:    " + line + "\n")
+	}
+	for _, l := range n.DependsOn {
+		b.WriteString("Links to:    " + l.To.String() + linkSlug(l) + "\n")
+	}
+	for _, d := range n.Downstream {
+		b.WriteString("Clone:       " + d.String() + "\n")
+	}
+	for _, l := range n.DependedBy {
+		b.WriteString("Linked by:   " + l.From.String() + linkSlug(l) + "\n")
+	}
+	if fan := g.FanOut(ref); len(fan) > 0 {
+		b.WriteString(fmt.Sprintf("Fan-out:     a change reaches %d unit(s)\n", len(fan)))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
 // formatUnitDetails creates a rich formatted view of unit details
-func formatUnitDetails(jsonData []byte, basicData CubUnitData) string {
+func formatUnitDetails(jsonData []byte, basicData CubUnitData, sections string) string {
 	var b strings.Builder
 
 	// Parse the full response to extract any additional fields
@@ -6008,7 +6363,10 @@ func formatUnitDetails(jsonData []byte, basicData CubUnitData) string {
 	b.WriteString("─────────────────────────────────────\n")
 	b.WriteString(fmt.Sprintf("Head:        %d\n", basicData.Unit.HeadRevisionNum))
 	b.WriteString(fmt.Sprintf("Live:        %d\n", basicData.Unit.LiveRevisionNum))
-	if basicData.Unit.HeadRevisionNum != basicData.Unit.LiveRevisionNum {
+	if gate := applyGateSummary(basicData); gate != "" {
+		b.WriteString("Gates:       " + strings.Join(basicData.ApplyGateNames(), ", ") + "\n")
+		b.WriteString(iconGated + "  Apply blocked: " + gate + "\n")
+	} else if basicData.Unit.HeadRevisionNum != basicData.Unit.LiveRevisionNum {
 		b.WriteString("⚠️  Drift detected (head ≠ live)\n")
 	}
 	b.WriteString("\n")
@@ -6059,6 +6417,9 @@ func formatUnitDetails(jsonData []byte, basicData CubUnitData) string {
 		}
 	}
 
+	// Clones, links and functions
+	b.WriteString(sections)
+
 	// Show raw JSON for any extra fields not captured above
 	b.WriteString("RAW DATA\n")
 	b.WriteString("─────────────────────────────────────\n")
@@ -6092,8 +6453,8 @@ func formatGroupPatternContext(node *TreeNode) string {
 		b.WriteString("DETECTED PATTERN\n")
 		b.WriteString("─────────────────────────────────────\n")
 
-		// Detect pattern from space names
-		pattern := detectPatternFromSpaces(node.Children)
+		// Detect pattern across the whole org
+		pattern := detectPatternFromSpaces(node.Parent.Children)
 		b.WriteString(pattern)
 		b.WriteString("\n")
 
@@ -6129,8 +6490,8 @@ func formatGroupPatternContext(node *TreeNode) string {
 		b.WriteString("DETECTED PATTERN\n")
 		b.WriteString("─────────────────────────────────────\n")
 
-		// Detect pattern from space names
-		pattern := detectPatternFromSpaces(node.Children)
+		// Detect pattern across the whole org
+		pattern := detectPatternFromSpaces(node.Parent.Children)
 		b.WriteString(pattern)
 		b.WriteString("\n")
 
@@ -6155,101 +6516,39 @@ func formatGroupPatternContext(node *TreeNode) string {
 	return b.String()
 }
 
-// detectPatternFromSpaces analyzes space names to detect common patterns
+// detectPatternFromSpaces describes the org layout of spaces.
 func detectPatternFromSpaces(spaces []*TreeNode) string {
-	if len(spaces) == 0 {
-		return "No pattern detected (no spaces)\n"
-	}
-
-	var names []string
-	for _, s := range spaces {
-		if s.Type == "space" {
-			names = append(names, s.Name)
-		}
-	}
-
-	// Check for known patterns
-	hasPlatform := false
-	hasBase := false
-	hasInfra := false
-	hasDevStagingProd := false
-	hasRegions := false
-	hasClusters := false
-
-	devCount, stagingCount, prodCount := 0, 0, 0
-	regionCount := 0
+	return orgpattern.Detect(orgPatternSpaces(spaces)).Summary()
+}
 
-	for _, name := range names {
-		lower := strings.ToLower(name)
-		if strings.Contains(lower, "platform") {
-			hasPlatform = true
-		}
-		if strings.Contains(lower, "base") {
-			hasBase = true
-		}
-		if strings.Contains(lower, "infra") {
-			hasInfra = true
-		}
-		if strings.Contains(lower, "-dev") || strings.HasSuffix(lower, "-dev") {
-			devCount++
-		}
-		if strings.Contains(lower, "staging") {
-			stagingCount++
-		}
-		if strings.Contains(lower, "-prod") || strings.HasSuffix(lower, "-prod") {
-			prodCount++
-		}
-		if strings.Contains(lower, "-asia") || strings.Contains(lower, "-eu") || strings.Contains(lower, "-us") {
-			regionCount++
+// orgPatternSpaces converts space nodes for pattern detection, with
+// whatever units, targets and workers have been loaded under them.
+func orgPatternSpaces(nodes []*TreeNode) []orgpattern.Space {
+	var data []orgSpaceData
+	for _, n := range nodes {
+		if n.Type != "space" {
+			continue
 		}
-		if strings.Contains(lower, "cluster-") || strings.Contains(lower, ".example.") {
-			hasClusters = true
+		d := orgSpaceData{}
+		if space, ok := n.Data.(CubSpaceData); ok {
+			d.Space = space
+		}
+		d.Space.Space.Slug = n.Name
+		for _, group := range n.Children {
+			for _, child := range group.Children {
+				switch v := child.Data.(type) {
+				case CubUnitData:
+					d.Units = append(d.Units, v)
+				case CubTargetData:
+					d.Targets = append(d.Targets, v)
+				case CubWorkerData:
+					d.Workers = append(d.Workers, v)
+				}
+			}
 		}
+		data = append(data, d)
 	}
-
-	if devCount > 0 && prodCount > 0 {
-		hasDevStagingProd = true
-	}
-	if regionCount >= 2 {
-		hasRegions = true
-	}
-
-	var b strings.Builder
-
-	// Determine pattern
-	if hasClusters && (hasBase || hasInfra) {
-		b.WriteString("Pattern: Banko (Flux)\n")
-		b.WriteString("• Cluster-per-directory structure\n")
-		b.WriteString("• Versioned platform components\n")
-		b.WriteString("• platform/ → Hub, clusters/* → AppSpaces\n")
-	} else if hasBase && hasDevStagingProd {
-		b.WriteString("Pattern: Arnie (ArgoCD)\n")
-		b.WriteString("• Folders-per-environment\n")
-		b.WriteString("• Promotion = file copy\n")
-		b.WriteString("• base/ → Hub, envs/* → AppSpaces\n")
-	} else if hasRegions && (hasBase || hasInfra) {
-		b.WriteString("Pattern: TraderX (Multi-region)\n")
-		b.WriteString("• Base/Infra Hub + regional AppSpaces\n")
-		b.WriteString("• Labels: variant, region\n")
-	} else if hasPlatform && hasDevStagingProd {
-		b.WriteString("Pattern: KubeCon Demo\n")
-		b.WriteString("• Platform team + App teams\n")
-		b.WriteString("• platform-* → Hub, app*-dev/prod → AppSpaces\n")
-	} else if hasBase && hasInfra && hasDevStagingProd {
-		b.WriteString("Pattern: curious-cub (Standard)\n")
-		b.WriteString("• Base/Infra Hub\n")
-		b.WriteString("• dev/staging/prod AppSpaces\n")
-	} else if hasDevStagingProd {
-		b.WriteString("Pattern: Environment-based\n")
-		b.WriteString("• Spaces per environment (dev/staging/prod)\n")
-		b.WriteString("• Consider adding base/infra Hub spaces\n")
-	} else {
-		b.WriteString("Pattern: Custom\n")
-		b.WriteString("• No standard pattern detected\n")
-		b.WriteString("• See docs for reference architectures\n")
-	}
-
-	return b.String()
+	return toOrgPatternSpaces(data)
 }
 
 // buildOrgSummary creates summary content for the current organization
@@ -6488,7 +6787,7 @@ func (m *Model) renderWorkerWarning() string {
 			b.WriteString(fmt.Sprintf("  • %s\n", w))
 		}
 	}
-	b.WriteString("\nRun: cub worker run <worker-name>  |  : cub worker run <name>")
+	b.WriteString("\nPress W for last seen times, the exact cub worker run command, and relaunch")
 
 	return warningStyle.Render(b.String()) + "\n\n"
 }
@@ -6546,6 +6845,8 @@ func (m *Model) getDetailsHeader(node *TreeNode) string {
 		return fmt.Sprintf("Organization: %s", node.Name)
 	case "space":
 		return fmt.Sprintf("Space: %s", node.Name)
+	case "guest_space":
+		return fmt.Sprintf("Space: %s (read-only)", node.Name)
 	case "unit":
 		return fmt.Sprintf("Unit: %s", node.Name)
 	case "target":
@@ -6621,6 +6922,7 @@ func (m *Model) colorJSONValue(value string) string {
 }
 
 func (m Model) View() string {
+	defer saveHubSnapshotOnPanic(&m)
 	if !m.ready {
 		return m.spinner.View() + " Initializing..."
 	}
@@ -6633,6 +6935,21 @@ func (m Model) View() string {
 		return m.spinner.View() + " " + msg
 	}
 
+	// Error drawer
+	if m.errorDrawer {
+		return m.renderErrorDrawer()
+	}
+
+	// Workers panel
+	if m.workerPanel {
+		return m.renderWorkerPanel()
+	}
+
+	// Changesets panel
+	if m.changeSetPanel {
+		return m.renderChangeSetPanel()
+	}
+
 	if m.err != nil {
 		errMsg := fmt.Sprintf("Error: %v\n\n", m.err)
 		// Add login hint for auth-related errors
@@ -6641,7 +6958,7 @@ func (m Model) View() string {
 			strings.Contains(errStr, "auth") || strings.Contains(errStr, "401") {
 			errMsg += "Hint: Try running 'cub auth login' to authenticate.\n\n"
 		}
-		errMsg += "Press q to quit, r to retry"
+		errMsg += "Press q to quit, r to retry, e for details"
 		return errMsg
 	}
 
@@ -6665,6 +6982,11 @@ func (m Model) View() string {
 		return m.renderOrgSelector()
 	}
 
+	// Fuzzy jump overlay
+	if m.jumpMode {
+		return m.renderJumpOverlay()
+	}
+
 	// Help overlay
 	if m.helpMode {
 		return m.renderHelpOverlay()
@@ -6749,6 +7071,17 @@ func (m Model) View() string {
 		return b.String()
 	}
 
+	// No cub context for the org: offer a login hand-off
+	if m.orgLoginPrompt {
+		b.WriteString(promptStyle.Render(fmt.Sprintf("No cub context for organization '%s'", m.authOrgName)))
+		b.WriteString("\n\n")
+		b.WriteString("Switching orgs uses a cub context, and none is set up for this one yet.\n")
+		b.WriteString(fmt.Sprintf("Logging in creates one: run cub auth login and choose '%s' in the browser.", m.authOrgName))
+		b.WriteString("\n\n")
+		b.WriteString("[l] Log in now  [n] Cancel")
+		return b.String()
+	}
+
 	// Breadcrumb
 	if m.cursor < len(m.flatList) {
 		node := m.flatList[m.cursor]
@@ -6873,9 +7206,12 @@ func (m Model) View() string {
 			item("⇥", "pane") + dot + item("f", "filter") + dot + item("n/N", "match") + dot + item("q", "quit")
 	} else {
 		helpBar = item("↑↓", "move") + dot + item("←→", "expand") + dot + item("⏎", "details") + dot +
-			item("⇥", "pane") + dot + item("/", "filter") + dot + item(":", "cmd") + dot +
+			item("⇥", "pane") + dot + item("/", "filter") + dot + item("^p", "jump") + dot + item(":", "cmd") + dot +
 			item("L", "local") + dot + item("?", "help") + dot + item("q", "quit")
 	}
+	if m.errorsUnseen > 0 {
+		helpBar += dot + wizardErrorStyle.Render(fmt.Sprintf("e %d new error(s)", m.errorsUnseen))
+	}
 
 	b.WriteString("\n")
 	b.WriteString(helpBar)
@@ -6896,7 +7232,10 @@ func (m Model) buildBreadcrumb(node *TreeNode) string {
 func (m Model) renderTree() string {
 	var b strings.Builder
 
-	for i, node := range m.flatList {
+	// Only the rows in view are rendered, so large orgs stay responsive
+	start, end := treeWindow(m.treeOffset, m.cursor, len(m.flatList), m.treeHeight())
+	for i := start; i < end; i++ {
+		node := m.flatList[i]
 		// Calculate depth
 		depth := 0
 		parent := node.Parent
@@ -6951,6 +7290,10 @@ func (m Model) renderTree() string {
 				if isCurrentOrg {
 					b.WriteString(activeStyle.Render(iconActive) + " ")
 					b.WriteString(activeStyle.Render(node.Name))
+				} else if m.isGuestOrg(node) {
+					b.WriteString(dimStyle.Render(iconInactive) + " ")
+					b.WriteString(node.Name)
+					b.WriteString(dimStyle.Render(" (read-only)"))
 				} else {
 					b.WriteString(dimStyle.Render(iconInactive) + " ")
 					b.WriteString(node.Name)
@@ -6958,6 +7301,11 @@ func (m Model) renderTree() string {
 				}
 			}
 
+		case "guest_space":
+			// Another org's space, read in guest mode and never loaded
+			b.WriteString(dimStyle.Render(iconInactive) + " ")
+			b.WriteString(node.Name)
+
 		case "space":
 			// Status icon
 			if icon := renderStatusIcon(node.Status); icon != "" {
@@ -6966,6 +7314,9 @@ func (m Model) renderTree() string {
 				b.WriteString(dimStyle.Render(iconInactive) + " ")
 			}
 			b.WriteString(node.Name)
+			if m.spaceLoads[node.ID] == spaceLoading {
+				b.WriteString(dimStyle.Render("  loading…"))
+			}
 
 		case "group":
 			b.WriteString(groupStyle.Render(node.Name))
@@ -7038,7 +7389,8 @@ Navigate through Organizations, Spaces, Units, Targets, and Workers in a tree vi
 Navigation:
   ↑/k, ↓/j     Move up/down
   ←/h          Collapse node or go to parent
-  →/l, Enter   Expand node (prompts to switch org if needed)
+  →/l, Enter   Expand node (prompts to switch org if needed; → opens
+               another org's spaces read-only when cub can read it)
   /            Filter - type to filter, hides non-matching nodes while preserving hierarchy
   f            Toggle filter on/off (when search query is active)
   n/N          Jump to next/previous match
@@ -7059,17 +7411,26 @@ func init() {
 }
 
 func runHierarchy(cmd *cobra.Command, args []string) error {
-	if _, err := exec.LookPath("cub"); err != nil {
-		return fmt.Errorf("cub CLI not found. Install from: https://docs.confighub.com/cli")
+	if !cubInstalled() {
+		return cubMissingError("the ConfigHub hierarchy")
 	}
 
 	if _, err := runCubCommand("context", "get"); err != nil {
 		return fmt.Errorf("not authenticated to ConfigHub. Run: cub auth login")
 	}
 
+	focus, err := parseHubFocusFlag()
+	if err != nil {
+		return err
+	}
+
 	for {
-		p := tea.NewProgram(initialModel(), tea.WithAltScreen())
+		m := initialModel()
+		m.setFocus(focus)
+		focus = nil // only the first run opens at --focus
+		p := tea.NewProgram(m, tea.WithAltScreen())
 		finalModel, err := p.Run()
+		saveHubSnapshotAfterRun(finalModel, err)
 		if err != nil {
 			return fmt.Errorf("error running TUI: %w", err)
 		}
@@ -0,0 +1,177 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// changeSetsLoadedMsg carries the changesets of the loaded spaces.
+type changeSetsLoadedMsg struct {
+	sets []ChangeSet
+	err  error
+}
+
+func loadChangeSetsCmd(spaces []string) tea.Cmd {
+	return func() tea.Msg {
+		sets, err := loadChangeSets(spaces)
+		return changeSetsLoadedMsg{sets: sets, err: err}
+	}
+}
+
+// loadedSpaces returns the slugs of the spaces whose data has loaded.
+func (m *Model) loadedSpaces() []string {
+	var spaces []string
+	for space, state := range m.spaceLoads {
+		if state == spaceLoaded {
+			spaces = append(spaces, space)
+		}
+	}
+	sort.Strings(spaces)
+	return spaces
+}
+
+// openChangeSetPanel opens the changesets panel and lists the changesets
+// in every loaded space.
+func (m *Model) openChangeSetPanel() tea.Cmd {
+	m.changeSetPanel = true
+	m.changeSetCursor = 0
+	m.changeSetDrill = false
+	return m.reloadChangeSets()
+}
+
+func (m *Model) reloadChangeSets() tea.Cmd {
+	spaces := m.loadedSpaces()
+	if len(spaces) == 0 {
+		m.changeSets, m.changeSetsErr = nil, nil
+		return nil
+	}
+	m.changeSetsLoading = true
+	return loadChangeSetsCmd(spaces)
+}
+
+// updateChangeSetPanel handles keys while the changesets panel is open:
+// ↑/↓ pick a changeset, Enter shows its targets and units, r reloads,
+// Esc or C closes.
+func (m *Model) updateChangeSetPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "C", "q":
+		m.changeSetPanel = false
+	case "up", "k":
+		if m.changeSetCursor > 0 {
+			m.changeSetCursor--
+			m.changeSetDrill = false
+		}
+	case "down", "j":
+		if m.changeSetCursor < len(m.changeSets)-1 {
+			m.changeSetCursor++
+			m.changeSetDrill = false
+		}
+	case "enter":
+		m.changeSetDrill = !m.changeSetDrill && m.changeSetCursor < len(m.changeSets)
+	case "r":
+		cubCache.Purge()
+		return m, m.reloadChangeSets()
+	}
+	return m, nil
+}
+
+func (m Model) renderChangeSetPanel() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(" CHANGESETS "))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.changeSetsLoading:
+		b.WriteString(m.spinner.View() + " Loading changesets...")
+		b.WriteString("\n\n")
+	case m.changeSetsErr != nil:
+		b.WriteString(statusErr.Render("  Error loading changesets: " + m.changeSetsErr.Error()))
+		b.WriteString("\n\n")
+	case len(m.loadedSpaces()) == 0:
+		b.WriteString(dimStyle.Render("No spaces loaded. Expand a space to load its changesets."))
+		b.WriteString("\n\n")
+	case len(m.changeSets) == 0:
+		b.WriteString(dimStyle.Render(fmt.Sprintf("No changesets in the %d loaded space(s).", len(m.loadedSpaces()))))
+		b.WriteString("\n\n")
+	}
+
+	if !m.changeSetsLoading {
+		for i, cs := range m.changeSets {
+			cursor := "  "
+			name := cs.Space + "/" + cs.Slug
+			if i == m.changeSetCursor {
+				cursor = activeStyle.Render("> ")
+				name = activeStyle.Render(name)
+			}
+			applied := cs.Count(changeSetApplied)
+			failed := ""
+			if n := cs.Count(changeSetFailed); n > 0 {
+				failed = statusErr.Render(fmt.Sprintf("%d failed", n))
+			}
+			b.WriteString(fmt.Sprintf("%s%s %-32s %-9s %s %d/%d  %s", cursor, changeSetIcon(cs.State), name, cs.State,
+				makeBar(applied, len(cs.Units), 10), applied, len(cs.Units), failed))
+			b.WriteString("\n")
+			if i == m.changeSetCursor && m.changeSetDrill {
+				b.WriteString(renderChangeSetDrill(cs))
+			}
+		}
+		if len(m.changeSets) > 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	if m.statusMsg != "" {
+		b.WriteString(dimStyle.Render(m.statusMsg))
+		b.WriteString("\n")
+	}
+	b.WriteString(helpStyle.Render("↑↓ select  Enter targets and units  r refresh  Esc close"))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("Changesets in spaces not loaded yet are not shown."))
+	return b.String()
+}
+
+// renderChangeSetDrill shows a changeset's progress per target and its
+// units, failed units with why.
+func renderChangeSetDrill(cs ChangeSet) string {
+	var b strings.Builder
+	if cs.Description != "" {
+		b.WriteString("    " + dimStyle.Render(cs.Description) + "\n")
+	}
+	for _, t := range cs.Targets {
+		line := fmt.Sprintf("    %s %-24s %s %d/%d", dimStyle.Render("target"), orDash(t.Target), makeBar(t.Applied, t.Units, 10), t.Applied, t.Units)
+		if t.Applying > 0 {
+			line += fmt.Sprintf("  %d applying", t.Applying)
+		}
+		if t.Failed > 0 {
+			line += "  " + statusErr.Render(fmt.Sprintf("%d failed", t.Failed))
+		}
+		b.WriteString(line + "\n")
+	}
+	for _, u := range cs.Units {
+		line := fmt.Sprintf("      %s %-24s rev %d→%d  %s", changeSetIcon(u.State), u.Unit, u.LiveRevision, u.HeadRevision, u.State)
+		if u.Detail != "" {
+			line += "  " + dimStyle.Render(u.Detail)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func changeSetIcon(state string) string {
+	switch state {
+	case changeSetApplied:
+		return statusOK.Render(iconCheckOK)
+	case changeSetFailed:
+		return statusErr.Render(iconCheckErr)
+	case changeSetPartial, changeSetApplying:
+		return statusWarn.Render(iconCheckWarn)
+	}
+	return dimStyle.Render(iconInactive)
+}
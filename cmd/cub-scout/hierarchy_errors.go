@@ -0,0 +1,408 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errorLogLimit is how many recent errors the error drawer keeps.
+const errorLogLimit = 50
+
+// errorStderrLines is how many trailing stderr lines the drawer shows for
+// the selected error.
+const errorStderrLines = 12
+
+// tuiError is a failed background command, kept for the error drawer (e).
+type tuiError struct {
+	At      time.Time
+	Action  string // What the TUI was doing, e.g. "load space prod"
+	Command string // The command line that failed, if known
+	Err     error
+	Stderr  string
+	retry   func(m *Model) tea.Cmd // nil if the action can't be retried from here
+}
+
+// cubCommandError records the arguments of a failed cub invocation. Its
+// message is the underlying error's, so status lines read as before.
+type cubCommandError struct {
+	args []string
+	err  error
+}
+
+func (e *cubCommandError) Error() string { return e.err.Error() }
+func (e *cubCommandError) Unwrap() error { return e.err }
+
+// cubStderr returns what a failed cub command printed on stderr, or the
+// error itself when it printed nothing.
+func cubStderr(err error) string {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return err.Error()
+}
+
+// newTUIError builds a drawer entry for err, pulling the failed command and
+// its stderr out of the error chain when they are there.
+func newTUIError(action string, err error, retry func(m *Model) tea.Cmd) tuiError {
+	e := tuiError{At: time.Now(), Action: action, Err: err, retry: retry}
+	var cubErr *cubCommandError
+	if errors.As(err, &cubErr) {
+		e.Command = "cub " + strings.Join(cubErr.args, " ")
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		e.Stderr = strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return e
+}
+
+// recordError adds an entry to the error log, newest first.
+func (m *Model) recordError(e tuiError) {
+	m.errorLog = append([]tuiError{e}, m.errorLog...)
+	if len(m.errorLog) > errorLogLimit {
+		m.errorLog = m.errorLog[:errorLogLimit]
+	}
+	m.errorsUnseen++
+}
+
+// recordBackgroundError logs the error carried by a background command's
+// result message, if any. The message is still handled as before; this
+// only keeps the details that the status line flattens away.
+func (m *Model) recordBackgroundError(msg tea.Msg) {
+	var e tuiError
+	switch msg := msg.(type) {
+	case errMsg:
+		e = newTUIError("load ConfigHub data", msg.err, func(m *Model) tea.Cmd {
+			m.err = nil
+			m.loading = true
+			return loadDataCmd
+		})
+	case spaceDataLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		slug := msg.spaceSlug
+		e = newTUIError("load space "+slug, msg.err, func(m *Model) tea.Cmd {
+			return m.requestSpaceLoad(slug)
+		})
+	case detailsLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		var retry func(m *Model) tea.Cmd
+		action := "load details"
+		if node := msg.node; node != nil {
+			action = "load details for " + node.Name
+			retry = func(m *Model) tea.Cmd {
+				m.detailsLoading = true
+				m.detailsError = nil
+				return loadEntityDetailsCmd(node)
+			}
+		}
+		e = newTUIError(action, msg.err, retry)
+	case changeSetsLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("list changesets", msg.err, func(m *Model) tea.Cmd {
+			return m.reloadChangeSets()
+		})
+	case guestOrgsLoadedMsg:
+		err := msg.err
+		for _, g := range msg.orgs {
+			if err == nil && g.err != nil {
+				err = fmt.Errorf("%s (%s): %w", g.org.DisplayName, g.access, g.err)
+			}
+		}
+		if err == nil {
+			return
+		}
+		e = newTUIError("read other orgs", err, func(m *Model) tea.Cmd {
+			return loadGuestOrgsCmd(m.nonCurrentOrgs())
+		})
+	case panelDataLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("load panel view", msg.err, nil)
+	case suggestDataLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("suggest units", msg.err, func(m *Model) tea.Cmd {
+			m.suggestLoading = true
+			return loadSuggestDataCmd()
+		})
+	case authCompleteMsg:
+		if msg.success || msg.noContext || msg.err == nil {
+			return
+		}
+		e = newTUIError("switch to "+m.authOrgName, msg.err, nil)
+	case orgLoginDoneMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("cub auth login", msg.err, nil)
+		e.Command = "cub auth login"
+	case cmdCompleteMsg:
+		if msg.err == nil {
+			return
+		}
+		command := msg.command
+		e = newTUIError("run command", msg.err, func(m *Model) tea.Cmd {
+			m.cmdRunning = true
+			m.cmdShowOutput = true
+			return runCommandCmd(command)
+		})
+		e.Command = command
+		if e.Stderr == "" {
+			e.Stderr = strings.TrimSpace(msg.output)
+		}
+
+	// Import wizard
+	case namespacesLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: list namespaces", msg.err, retryImport(loadNamespacesCmd))
+	case argoAppsLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: list Argo CD applications", msg.err, retryImport(loadArgoAppsCmd))
+	case argoResourcesLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		action := "import: load Argo CD application resources"
+		var retry func(m *Model) tea.Cmd
+		if app := msg.app; app != nil {
+			action = "import: load resources of " + app.Name
+			retry = retryImport(func() tea.Cmd { return loadArgoResourcesCmd(*app) })
+		}
+		e = newTUIError(action, msg.err, retry)
+	case workloadsDiscoveredMsg:
+		if msg.err == nil {
+			return
+		}
+		ns := m.importNamespace
+		e = newTUIError("import: discover workloads in "+ns, msg.err,
+			retryImport(func() tea.Cmd { return discoverWorkloadsCmd(ns) }))
+	case spacesLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: list spaces", msg.err, retryImport(loadExistingSpacesCmd))
+	case workersLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		space := m.importSpace
+		e = newTUIError("import: list workers in "+space, msg.err,
+			retryImport(func() tea.Cmd { return loadWorkersForSpaceCmd(space) }))
+	case spaceCreatedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: create space", msg.err, nil)
+	case workerCreatedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: create worker", msg.err, nil)
+	case workerStartedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: start worker", msg.err, nil)
+	case targetFoundMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: wait for target", msg.err, nil)
+	case argoSyncDisabledMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: disable Argo CD auto-sync", msg.err, nil)
+	case argoAppDeletedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: delete Argo CD application", msg.err, nil)
+	case unitAppliedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: apply unit", msg.err, nil)
+	case testUpdateCompleteMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("import: test pipeline", msg.err, nil)
+
+	// Create and delete wizards
+	case createUnitsLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("create: list units", msg.err, nil)
+	case createTargetsLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("create: list targets", msg.err, nil)
+	case createWorkersLoadedMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError("create: list workers", msg.err, nil)
+	case createResourceMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError(fmt.Sprintf("create %s %s", msg.resourceType, msg.name), msg.err, nil)
+	case deleteResourceMsg:
+		if msg.err == nil {
+			return
+		}
+		e = newTUIError(fmt.Sprintf("delete %s %s", msg.resourceType, msg.name), msg.err, nil)
+	default:
+		return
+	}
+	m.recordError(e)
+}
+
+// retryImport retries an import wizard load, provided the wizard is still
+// open.
+func retryImport(load func() tea.Cmd) func(m *Model) tea.Cmd {
+	return func(m *Model) tea.Cmd {
+		if !m.importMode {
+			m.statusMsg = "Reopen the import wizard (i) to retry"
+			return nil
+		}
+		m.importError = nil
+		m.importLoading = true
+		return load()
+	}
+}
+
+// typingText reports whether keys are going into a text field, where e
+// must be typed rather than open the error drawer.
+func (m *Model) typingText() bool {
+	switch {
+	case m.searchMode, m.cmdMode, m.jumpMode:
+		return true
+	case m.importMode:
+		return m.importStep == importStepCreateSpace || m.importStep == importStepCreateWorker
+	case m.createMode:
+		return m.createStep == createStepEnterName
+	}
+	return false
+}
+
+// openErrorDrawer opens the error drawer on the newest error.
+func (m *Model) openErrorDrawer() {
+	m.errorDrawer = true
+	m.errorCursor = 0
+	m.errorsUnseen = 0
+}
+
+// updateErrorDrawer handles keys while the error drawer is open: ↑/↓ pick
+// an error, r retries it, c clears the log, Esc or e closes.
+func (m *Model) updateErrorDrawer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "e", "q":
+		m.errorDrawer = false
+	case "up", "k":
+		if m.errorCursor > 0 {
+			m.errorCursor--
+		}
+	case "down", "j":
+		if m.errorCursor < len(m.errorLog)-1 {
+			m.errorCursor++
+		}
+	case "r":
+		if m.errorCursor >= len(m.errorLog) {
+			return m, nil
+		}
+		e := m.errorLog[m.errorCursor]
+		if e.retry == nil {
+			m.statusMsg = "Can't retry " + e.Action + " from here"
+			return m, nil
+		}
+		m.errorDrawer = false
+		m.statusMsg = "Retrying " + e.Action + "..."
+		return m, e.retry(m)
+	case "c":
+		m.errorLog = nil
+		m.errorCursor = 0
+	}
+	return m, nil
+}
+
+func (m Model) renderErrorDrawer() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(" ERRORS "))
+	b.WriteString("\n\n")
+
+	if len(m.errorLog) == 0 {
+		b.WriteString(dimStyle.Render("No errors this session"))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("Esc close"))
+		return b.String()
+	}
+
+	for i, e := range m.errorLog {
+		cursor := "  "
+		action := e.Action
+		if i == m.errorCursor {
+			cursor = activeStyle.Render("> ")
+			action = activeStyle.Render(action)
+		}
+		line := cursor + dimStyle.Render(e.At.Format("15:04:05")) + "  " + action
+		if e.retry != nil {
+			line += dimStyle.Render("  (r retry)")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+		if i != m.errorCursor {
+			continue
+		}
+
+		b.WriteString("    " + wizardErrorStyle.Render(e.Err.Error()))
+		b.WriteString("\n")
+		if e.Command != "" {
+			b.WriteString("    " + dimStyle.Render("command: ") + e.Command)
+			b.WriteString("\n")
+		}
+		if e.Stderr != "" {
+			lines := strings.Split(e.Stderr, "\n")
+			if len(lines) > errorStderrLines {
+				b.WriteString("    " + dimStyle.Render(fmt.Sprintf("stderr (last %d of %d lines):", errorStderrLines, len(lines))))
+				lines = lines[len(lines)-errorStderrLines:]
+			} else {
+				b.WriteString("    " + dimStyle.Render("stderr:"))
+			}
+			b.WriteString("\n")
+			for _, l := range lines {
+				b.WriteString("    " + dimStyle.Render("│ ") + l)
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑↓ select  r retry  c clear  Esc close"))
+	return b.String()
+}
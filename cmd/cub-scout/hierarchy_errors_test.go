@@ -0,0 +1,73 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewTUIErrorUnwrapsCubCommand(t *testing.T) {
+	exitErr := &exec.ExitError{Stderr: []byte("Error: space not found\n")}
+	err := &cubCommandError{args: []string{"unit", "list", "--space", "prod"}, err: exitErr}
+
+	e := newTUIError("load space prod", err, nil)
+	if e.Command != "cub unit list --space prod" {
+		t.Errorf("Command = %q", e.Command)
+	}
+	if e.Stderr != "Error: space not found" {
+		t.Errorf("Stderr = %q", e.Stderr)
+	}
+	if err.Error() != exitErr.Error() {
+		t.Errorf("cubCommandError message %q should match the underlying %q", err.Error(), exitErr.Error())
+	}
+}
+
+func TestErrorDrawer(t *testing.T) {
+	m := jumpTestModel()
+	delete(m.spaceLoads, "staging")
+	loadErr := &cubCommandError{args: []string{"unit", "list"}, err: errors.New("exit status 1")}
+
+	next, _ := m.Update(spaceDataLoadedMsg{spaceSlug: "staging", err: loadErr})
+	m = next.(Model)
+	if len(m.errorLog) != 1 || m.errorsUnseen != 1 {
+		t.Fatalf("errorLog = %d entries, %d unseen; want 1, 1", len(m.errorLog), m.errorsUnseen)
+	}
+	if !strings.Contains(m.View(), "1 new error") {
+		t.Error("help bar should announce the unseen error")
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if !m.errorDrawer || m.errorsUnseen != 0 {
+		t.Fatalf("e should open the drawer and mark errors seen")
+	}
+	view := m.View()
+	for _, want := range []string{"load space staging", "cub unit list", "exit status 1", "r retry"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("drawer should show %q:\n%s", want, view)
+		}
+	}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = *next.(*Model)
+	if m.errorDrawer || cmd == nil {
+		t.Error("r should close the drawer and retry the load")
+	}
+	if m.spaceLoads["staging"] != spaceLoading {
+		t.Errorf("staging load state = %v, want loading", m.spaceLoads["staging"])
+	}
+}
+
+func TestErrorDrawerKeyTypedInTextFields(t *testing.T) {
+	m := jumpTestModel()
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if m.errorDrawer || m.searchQuery != "e" {
+		t.Errorf("e in search should be typed, got drawer=%v query=%q", m.errorDrawer, m.searchQuery)
+	}
+}
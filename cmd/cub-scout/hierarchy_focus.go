@@ -0,0 +1,291 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hubFocus is a location in the hub TUI, written as a breadcrumb path:
+//
+//	[org/<org>/]space/<space>[/unit|target|worker/<slug>][/revision/<n>]
+//
+// so CLI output, docs and runbooks can deep-link with
+// cub-scout map --hub --focus space/prod-payments/unit/checkout. A ConfigHub
+// GUI URL, as opened by the TUI's 'o' action, is accepted too; its IDs match
+// the same nodes as slugs.
+type hubFocus struct {
+	Org      string
+	Space    string
+	Kind     string // unit, target or worker; empty focuses the space
+	Name     string
+	Revision int // unit revision; 0 focuses the unit itself
+}
+
+const hubFocusGrammar = "[org/<org>/]space/<space>[/unit|target|worker/<slug>][/revision/<n>] or a ConfigHub GUI URL"
+
+// parseHubFocus parses a --focus path or GUI URL.
+func parseHubFocus(s string) (*hubFocus, error) {
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		return parseGUIURL(s)
+	}
+
+	parts := strings.Split(strings.Trim(s, "/"), "/")
+	if len(parts)%2 != 0 {
+		return nil, fmt.Errorf("invalid focus %q: want %s", s, hubFocusGrammar)
+	}
+
+	f := &hubFocus{}
+	for i := 0; i < len(parts); i += 2 {
+		kind, name := parts[i], parts[i+1]
+		if name == "" {
+			return nil, fmt.Errorf("invalid focus %q: %s has no name", s, kind)
+		}
+		switch {
+		case kind == "org" && i == 0:
+			f.Org = name
+		case kind == "space" && f.Space == "" && f.Kind == "":
+			f.Space = name
+		case (kind == "unit" || kind == "target" || kind == "worker") && f.Space != "" && f.Kind == "":
+			f.Kind, f.Name = kind, name
+		case kind == "revision" && f.Kind == "unit" && f.Revision == 0:
+			n, err := strconv.Atoi(name)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid focus %q: revision %q is not a positive number", s, name)
+			}
+			f.Revision = n
+		default:
+			return nil, fmt.Errorf("invalid focus %q: unexpected %q; want %s", s, kind, hubFocusGrammar)
+		}
+	}
+	if f.Space == "" {
+		return nil, fmt.Errorf("invalid focus %q: missing space/<space>", s)
+	}
+	return f, nil
+}
+
+func (f hubFocus) String() string {
+	s := "space/" + f.Space
+	if f.Org != "" {
+		s = "org/" + f.Org + "/" + s
+	}
+	if f.Kind != "" {
+		s += "/" + f.Kind + "/" + f.Name
+	}
+	if f.Revision > 0 {
+		s += "/revision/" + strconv.Itoa(f.Revision)
+	}
+	return s
+}
+
+// parseGUIURL parses a ConfigHub GUI URL of the form guiPath builds:
+// /spaces/<id>[/units|targets|workers/<id>[/revisions/<n>]].
+func parseGUIURL(s string) (*hubFocus, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid focus URL %q: %w", s, err)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "spaces" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid focus URL %q: want a ConfigHub space, unit, target or worker page", s)
+	}
+
+	f := &hubFocus{Space: parts[1]}
+	if len(parts) >= 4 {
+		switch parts[2] {
+		case "units", "targets", "workers":
+			f.Kind, f.Name = strings.TrimSuffix(parts[2], "s"), parts[3]
+		default:
+			return nil, fmt.Errorf("invalid focus URL %q: unknown page %q", s, parts[2])
+		}
+	}
+	if len(parts) >= 6 && f.Kind == "unit" && parts[4] == "revisions" {
+		if n, err := strconv.Atoi(parts[5]); err == nil && n > 0 {
+			f.Revision = n
+		}
+	}
+	return f, nil
+}
+
+// guiPath returns the ConfigHub GUI path for a node, extending the
+// /spaces/<id> URL convention: units, targets and workers open at
+// /spaces/<id>/<kind>s/<id>, and a unit's Revision row at its head revision.
+// Other nodes open their nearest ancestor with a page; orgs open "/".
+func (m *Model) guiPath(node *TreeNode) string {
+	spaceID := m.getSpaceIDFromNode(node)
+	if spaceID == "" {
+		return "/"
+	}
+	space := "/spaces/" + spaceID
+
+	switch node.Type {
+	case "unit", "target", "worker":
+		if id := guiID(node); id != "" {
+			return space + "/" + node.Type + "s/" + id
+		}
+	case "detail":
+		if node.Parent == nil {
+			break
+		}
+		unit, ok := node.Parent.Data.(CubUnitData)
+		if !ok {
+			return m.guiPath(node.Parent)
+		}
+		switch strings.TrimPrefix(node.ID, node.Parent.ID+"/") {
+		case "revision":
+			if unit.Unit.UnitID != "" && unit.Unit.HeadRevisionNum > 0 {
+				return fmt.Sprintf("%s/units/%s/revisions/%d", space, unit.Unit.UnitID, unit.Unit.HeadRevisionNum)
+			}
+		case "target":
+			if unit.Target.TargetID != "" {
+				return space + "/targets/" + unit.Target.TargetID
+			}
+		case "worker":
+			if unit.BridgeWorker.BridgeWorkerID != "" {
+				return space + "/workers/" + unit.BridgeWorker.BridgeWorkerID
+			}
+		}
+		return m.guiPath(node.Parent)
+	}
+	return space
+}
+
+// guiID returns the ConfigHub ID of a space, unit, target or worker node.
+func guiID(node *TreeNode) string {
+	switch d := node.Data.(type) {
+	case CubSpaceData:
+		return d.Space.SpaceID
+	case CubUnitData:
+		return d.Unit.UnitID
+	case CubTargetData:
+		return d.Target.TargetID
+	case CubWorkerData:
+		return d.BridgeWorker.BridgeWorkerID
+	}
+	return ""
+}
+
+// hubFocusCommand returns the command that opens the hub TUI on a unit,
+// target or worker, for CLI output to print next to it.
+func hubFocusCommand(space, kind, name string) string {
+	return "cub-scout map --hub --focus " + hubFocus{Space: space, Kind: kind, Name: name}.String()
+}
+
+// applyFocus moves the cursor to the pending --focus location. The space is
+// loaded first if needed; the focus stays pending until its data arrives.
+func (m *Model) applyFocus() tea.Cmd {
+	f := m.pendingFocus
+	if f == nil || m.loading {
+		return nil
+	}
+
+	space := m.findFocusSpace(f)
+	if space == nil {
+		m.pendingFocus = nil
+		m.statusMsg = "Focus: space " + f.Space + " not found"
+		return nil
+	}
+	if f.Kind == "" {
+		m.pendingFocus = nil
+		return m.jumpTo(space)
+	}
+
+	if m.spaceLoads[space.ID] != spaceLoaded {
+		if space.Parent != nil {
+			space.Parent.Expanded = true
+		}
+		space.Expanded = true
+		m.focusNode(space)
+		return m.requestSpaceLoad(space.ID)
+	}
+
+	m.pendingFocus = nil
+	for _, group := range space.Children {
+		if group.Type != "group" || !strings.HasSuffix(group.ID, "/"+f.Kind+"s") {
+			continue
+		}
+		for _, n := range group.Children {
+			if n.Type == f.Kind && (n.ID == f.Name || guiID(n) == f.Name) {
+				cmd := m.jumpTo(n)
+				if f.Revision > 0 {
+					m.focusRevision(n, f.Revision)
+				}
+				return cmd
+			}
+		}
+	}
+	cmd := m.jumpTo(space)
+	m.statusMsg = fmt.Sprintf("Focus: %s %s not found in space %s", f.Kind, f.Name, f.Space)
+	return cmd
+}
+
+// focusRevision expands a unit's detail rows and moves the cursor to its
+// Revision row, noting which revision the link pointed at.
+func (m *Model) focusRevision(unit *TreeNode, revision int) {
+	data, ok := unit.Data.(CubUnitData)
+	if !ok {
+		return
+	}
+	if len(unit.Children) == 0 {
+		unit.Children = buildUnitDetailChildren(data, unit)
+		m.treeChanged()
+	}
+	unit.Expanded = true
+	for _, child := range unit.Children {
+		if child.ID == unit.ID+"/revision" {
+			m.focusNode(child)
+		}
+	}
+	m.statusMsg = fmt.Sprintf("Revision %d of %s (head %d, live %d)", revision, unit.Name, data.Unit.HeadRevisionNum, data.Unit.LiveRevisionNum)
+}
+
+// findFocusSpace finds the focused space, in the named org or else
+// preferring the current org.
+func (m *Model) findFocusSpace(f *hubFocus) *TreeNode {
+	var orgs []*TreeNode
+	for _, org := range m.nodes {
+		data, _ := org.Data.(CubOrganization)
+		switch {
+		case f.Org != "":
+			if data.Slug == f.Org || data.ExternalID == f.Org || org.Name == f.Org {
+				orgs = append(orgs, org)
+			}
+		case m.isCurrentOrg(data):
+			orgs = append([]*TreeNode{org}, orgs...)
+		default:
+			orgs = append(orgs, org)
+		}
+	}
+	for _, org := range orgs {
+		for _, space := range org.Children {
+			if space.Type == "space" && (space.ID == f.Space || guiID(space) == f.Space) {
+				return space
+			}
+		}
+	}
+	return nil
+}
+
+// parseHubFocusFlag parses --focus, returning nil when it is not set.
+func parseHubFocusFlag() (*hubFocus, error) {
+	if mapHubFocus == "" {
+		return nil, nil
+	}
+	return parseHubFocus(mapHubFocus)
+}
+
+// setFocus makes the model open at f once data loads. An explicit focus
+// wins over the saved snapshot's cursor.
+func (m *Model) setFocus(f *hubFocus) {
+	if f == nil {
+		return
+	}
+	m.pendingFocus = f
+	m.pendingSnapshot = nil
+}
@@ -0,0 +1,151 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHubFocus(t *testing.T) {
+	for _, s := range []string{
+		"space/prod-payments",
+		"space/prod-payments/unit/checkout",
+		"space/prod/target/eu-west",
+		"org/acme/space/prod/worker/prod-worker",
+		"space/prod/unit/api/revision/3",
+	} {
+		f, err := parseHubFocus(s)
+		if err != nil {
+			t.Errorf("parseHubFocus(%q): %v", s, err)
+			continue
+		}
+		if got := f.String(); got != s {
+			t.Errorf("parseHubFocus(%q).String() = %q", s, got)
+		}
+	}
+
+	f, err := parseHubFocus("/space/prod/unit/api/")
+	if err != nil || f.Space != "prod" || f.Kind != "unit" || f.Name != "api" {
+		t.Errorf("slashes: %+v, %v", f, err)
+	}
+
+	for _, s := range []string{"", "space", "unit/api", "space/prod/unit", "space/prod/cluster/x", "space/prod/unit/a/unit/b", "space/a/org/b", "space//unit/a", "space/a/target/t/revision/2", "space/a/unit/u/revision/x"} {
+		if _, err := parseHubFocus(s); err == nil {
+			t.Errorf("parseHubFocus(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestApplyFocus(t *testing.T) {
+	m := jumpTestModel()
+	m.spaceLoads = map[string]spaceLoadState{}
+	m.setFocus(&hubFocus{Space: "prod", Kind: "unit", Name: "api"})
+
+	// The space loads first; the focus waits for its data
+	if cmd := m.applyFocus(); cmd == nil {
+		t.Fatal("expected a space load")
+	}
+	if m.pendingFocus == nil || m.flatList[m.cursor].ID != "prod" {
+		t.Fatalf("before load: pending = %v, cursor on %s", m.pendingFocus, m.flatList[m.cursor].ID)
+	}
+
+	m.spaceLoads["prod"] = spaceLoaded
+	m.applyFocus()
+	if m.pendingFocus != nil || m.flatList[m.cursor].ID != "api" {
+		t.Errorf("after load: pending = %v, cursor on %s", m.pendingFocus, m.flatList[m.cursor].ID)
+	}
+
+	// A unit hidden by the cluster filter is shown
+	m.setFocus(&hubFocus{Space: "prod", Kind: "unit", Name: "checkout-prod"})
+	m.applyFocus()
+	if !m.showAllUnits || m.flatList[m.cursor].ID != "checkout-prod" {
+		t.Errorf("hidden unit: showAllUnits = %v, cursor on %s", m.showAllUnits, m.flatList[m.cursor].ID)
+	}
+
+	// A missing unit falls back to its space
+	m.setFocus(&hubFocus{Space: "prod", Kind: "unit", Name: "nope"})
+	m.applyFocus()
+	if m.flatList[m.cursor].ID != "prod" || !strings.Contains(m.statusMsg, "unit nope not found") {
+		t.Errorf("missing unit: cursor on %s, status %q", m.flatList[m.cursor].ID, m.statusMsg)
+	}
+
+	m.setFocus(&hubFocus{Space: "nope"})
+	m.applyFocus()
+	if m.pendingFocus != nil || !strings.Contains(m.statusMsg, "space nope not found") {
+		t.Errorf("missing space: pending = %v, status %q", m.pendingFocus, m.statusMsg)
+	}
+}
+
+func TestHubFocusCommand(t *testing.T) {
+	if got, want := hubFocusCommand("prod-payments", "unit", "checkout"), "cub-scout map --hub --focus space/prod-payments/unit/checkout"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGUIPathRoundTrip(t *testing.T) {
+	m := jumpTestModel()
+	org := m.nodes[0]
+	prod := org.Children[0]
+	var space CubSpaceData
+	space.Space.SpaceID = "sp-1"
+	space.Space.Slug = "prod"
+	prod.Data = space
+	api := prod.Children[0].Children[1]
+	var unit CubUnitData
+	unit.Unit.UnitID = "u-9"
+	unit.Unit.Slug = "api"
+	unit.Unit.HeadRevisionNum = 4
+	unit.Unit.LiveRevisionNum = 3
+	unit.Target.TargetID = "t-2"
+	unit.Target.Slug = "eu-west"
+	api.Data = unit
+	api.Children = buildUnitDetailChildren(unit, api)
+
+	detail := func(name string) *TreeNode {
+		for _, c := range api.Children {
+			if c.Name == name {
+				return c
+			}
+		}
+		t.Fatalf("no %s row", name)
+		return nil
+	}
+	for _, tc := range []struct {
+		node *TreeNode
+		want string
+	}{
+		{org, "/"},
+		{prod, "/spaces/sp-1"},
+		{prod.Children[0], "/spaces/sp-1"},
+		{api, "/spaces/sp-1/units/u-9"},
+		{detail("Revision"), "/spaces/sp-1/units/u-9/revisions/4"},
+		{detail("Target"), "/spaces/sp-1/targets/t-2"},
+		{detail("Status"), "/spaces/sp-1/units/u-9"},
+	} {
+		if got := m.guiPath(tc.node); got != tc.want {
+			t.Errorf("guiPath(%s) = %q, want %q", tc.node.ID, got, tc.want)
+		}
+	}
+
+	// The URL 'o' opens lands back on the same node
+	f, err := parseHubFocus("https://hub.confighub.com" + m.guiPath(detail("Revision")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *f != (hubFocus{Space: "sp-1", Kind: "unit", Name: "u-9", Revision: 4}) {
+		t.Fatalf("parsed %+v", f)
+	}
+	m.setFocus(f)
+	m.applyFocus()
+	if got := m.flatList[m.cursor]; got.ID != "api/revision" || !strings.Contains(m.statusMsg, "Revision 4 of api") {
+		t.Errorf("cursor on %s, status %q", got.ID, m.statusMsg)
+	}
+
+	for _, s := range []string{"https://hub.confighub.com/", "https://hub.confighub.com/spaces/sp-1/settings/x"} {
+		if _, err := parseHubFocus(s); err == nil {
+			t.Errorf("parseHubFocus(%q) succeeded, want error", s)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Apply gates. A ConfigHub trigger running a validating function on a unit
+// records a gate on it while the function fails, and ConfigHub refuses to
+// apply the unit until every gate clears. The is-approved function is the
+// approval gate: it clears once enough users approve the head revision.
+// Gated units show their gate instead of the plain head→live arrow, since
+// applying them would fail anyway.
+
+// statusGated is the tree status of a unit whose apply a gate blocks.
+const statusGated = "gated"
+
+// ApplyGateNames returns the gates blocking the unit's apply, sorted.
+// ConfigHub names each after the trigger that set it.
+func (u CubUnitData) ApplyGateNames() []string {
+	var gates []string
+	for gate, blocking := range u.Unit.ApplyGates {
+		if blocking {
+			gates = append(gates, gate)
+		}
+	}
+	sort.Strings(gates)
+	return gates
+}
+
+// AwaitingApproval reports whether an approval gate blocks the unit.
+func (u CubUnitData) AwaitingApproval() bool {
+	for _, gate := range u.ApplyGateNames() {
+		if isApprovalGate(gate) {
+			return true
+		}
+	}
+	return false
+}
+
+func isApprovalGate(gate string) bool {
+	return strings.Contains(strings.ToLower(gate), "approv")
+}
+
+// applyGateSummary describes what blocks the unit's apply, e.g.
+// "awaiting approval (require-approval, 1 approval so far)" or
+// "gated by no-latest-tags", or "" when nothing does.
+func applyGateSummary(u CubUnitData) string {
+	gates := u.ApplyGateNames()
+	if len(gates) == 0 {
+		return ""
+	}
+	names := make([]string, len(gates))
+	for i, g := range gates {
+		names[i] = gateDisplayName(g)
+	}
+	if !u.AwaitingApproval() {
+		return "gated by " + strings.Join(names, ", ")
+	}
+	detail := strings.Join(names, ", ")
+	if n := len(u.Unit.ApprovedBy); n > 0 {
+		detail += fmt.Sprintf(", %d approval(s) so far", n)
+	}
+	return "awaiting approval (" + detail + ")"
+}
+
+// gateDisplayName shortens a gate named by its trigger's path, e.g.
+// "platform/require-approval", to the trigger slug.
+func gateDisplayName(gate string) string {
+	if i := strings.LastIndex(gate, "/"); i >= 0 && i < len(gate)-1 {
+		return gate[i+1:]
+	}
+	return gate
+}
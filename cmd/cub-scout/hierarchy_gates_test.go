@@ -0,0 +1,54 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestUnitApplyGates(t *testing.T) {
+	var u CubUnitData
+	if err := json.Unmarshal([]byte(`{
+		"Unit": {"Slug": "api", "HeadRevisionNum": 6, "LiveRevisionNum": 4,
+			"ApplyGates": {"platform/require-approval": true, "platform/no-latest": false},
+			"ApprovedBy": ["u-1"]},
+		"UnitStatus": {"SyncStatus": "OutOfSync"}
+	}`), &u); err != nil {
+		t.Fatal(err)
+	}
+	if gates := u.ApplyGateNames(); len(gates) != 1 || gates[0] != "platform/require-approval" {
+		t.Errorf("gates = %v, want only the blocking one", gates)
+	}
+	if !u.AwaitingApproval() || u.DeriveStatus() != statusGated {
+		t.Errorf("approval-gated unit: awaiting=%v status=%s", u.AwaitingApproval(), u.DeriveStatus())
+	}
+
+	info := buildUnitInfo(u, "")
+	if !strings.Contains(info, "rev:4  awaiting approval (require-approval, 1 approval(s) so far)") || strings.Contains(info, "→") {
+		t.Errorf("info = %q, want the gate instead of the pending arrow", info)
+	}
+	details := buildUnitDetailChildren(u, &TreeNode{ID: "api"})
+	var gate *TreeNode
+	for _, c := range details {
+		if c.Name == "Apply Gate" {
+			gate = c
+		}
+	}
+	if gate == nil || gate.Status != "warn" {
+		t.Errorf("details should have an Apply Gate row: %+v", gate)
+	}
+
+	u.Unit.ApplyGates = map[string]bool{"platform/no-latest": true}
+	u.Unit.ApprovedBy = nil
+	if u.AwaitingApproval() || applyGateSummary(u) != "gated by no-latest" {
+		t.Errorf("policy gate summary = %q", applyGateSummary(u))
+	}
+
+	u.Unit.ApplyGates = nil
+	if u.DeriveStatus() != "warn" || applyGateSummary(u) != "" || !strings.Contains(buildUnitInfo(u, ""), "rev:4→6") {
+		t.Errorf("ungated unit: status=%s info=%q", u.DeriveStatus(), buildUnitInfo(u, ""))
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// Orgs other than the current one are read in guest mode: their spaces and
+// unit counts are listed without `cub context use`, so the user's cub
+// context is never changed. Guest mode needs read access to the org, from
+// an org-scoped token or a saved cub context for it.
+
+// guestAccess is how cub reads another org.
+type guestAccess struct {
+	Context string // saved cub context, named per call with --context
+	Token   string // org-scoped token, passed as CONFIGHUB_TOKEN
+}
+
+func (a guestAccess) String() string {
+	if a.Token != "" {
+		return "org token"
+	}
+	return "context " + a.Context
+}
+
+// cubContext is an entry of `cub context list --json`.
+type cubContext struct {
+	Name       string `json:"name"`
+	Coordinate struct {
+		ServerURL      string `json:"serverURL"`
+		OrganizationID string `json:"organizationID"`
+	} `json:"coordinate"`
+}
+
+func listCubContexts() ([]cubContext, error) {
+	out, err := runCubCommand("context", "list", "--json")
+	if err != nil {
+		return nil, fmt.Errorf("cub context list: %w", err)
+	}
+	var contexts []cubContext
+	if err := json.Unmarshal(out, &contexts); err != nil {
+		return nil, fmt.Errorf("parse cub contexts: %w", err)
+	}
+	return contexts, nil
+}
+
+// OrgTokensFile returns the org token file path: $CUB_SCOUT_ORG_TOKENS,
+// then ~/.cub-scout/org-tokens.yaml.
+func OrgTokensFile() string {
+	if file := os.Getenv("CUB_SCOUT_ORG_TOKENS"); file != "" {
+		return file
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cub-scout", "org-tokens.yaml")
+}
+
+// loadOrgTokens reads the org token file, which maps an org's slug or
+// external ID to a token scoped to it, e.g.:
+//
+//	acme-staging: chk_...
+//
+// A missing file has no tokens.
+func loadOrgTokens(file string) (map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var tokens map[string]string
+	if err := yaml.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	return tokens, nil
+}
+
+// guestAccessFor picks how to read org: its token, being scoped to the
+// org, then a saved context for it.
+func guestAccessFor(org CubOrganization, tokens map[string]string, contexts []cubContext) (guestAccess, bool) {
+	for _, id := range []string{org.Slug, org.ExternalID} {
+		if token := tokens[id]; id != "" && token != "" {
+			return guestAccess{Token: token}, true
+		}
+	}
+	for _, c := range contexts {
+		if c.Coordinate.OrganizationID == org.ExternalID {
+			return guestAccess{Context: c.Name}, true
+		}
+	}
+	return guestAccess{}, false
+}
+
+// cubTokenExec runs cub with a token for this call only. Tests replace it.
+var cubTokenExec = func(token string, args ...string) ([]byte, error) {
+	cmd := exec.Command("cub", args...)
+	cmd.Env = append(os.Environ(), "CONFIGHUB_TOKEN="+token)
+	return cmd.Output()
+}
+
+// runGuestCubCommand runs a read-only cub command against another org.
+func runGuestCubCommand(a guestAccess, args ...string) ([]byte, error) {
+	if a.Context != "" {
+		return runCubCommand(append([]string{"--context", a.Context}, args...)...)
+	}
+	done := traceCub(args)
+	output, err := cubTokenExec(a.Token, args...)
+	done(err)
+	if err != nil {
+		return nil, &cubCommandError{args: args, err: err}
+	}
+	return output, nil
+}
+
+// guestOrg is another org as read in guest mode.
+type guestOrg struct {
+	org    CubOrganization
+	access guestAccess
+	spaces []CubSpaceData
+	err    error
+}
+
+// guestOrgsLoadedMsg carries the orgs read in guest mode. Orgs without
+// read access are left out.
+type guestOrgsLoadedMsg struct {
+	orgs []guestOrg
+	err  error // reading the org token file failed
+}
+
+// loadGuestOrgsCmd lists the spaces of each org it has read access to,
+// through spaceLoadPool.
+func loadGuestOrgsCmd(orgs []CubOrganization) tea.Cmd {
+	return func() tea.Msg {
+		tokens, err := loadOrgTokens(OrgTokensFile())
+		if err != nil {
+			return guestOrgsLoadedMsg{err: err}
+		}
+		// Without saved contexts, only orgs with a token can be read
+		contexts, _ := listCubContexts()
+
+		var (
+			wg     sync.WaitGroup
+			loaded = make([]guestOrg, len(orgs))
+		)
+		for i, org := range orgs {
+			access, ok := guestAccessFor(org, tokens, contexts)
+			if !ok {
+				continue
+			}
+			loaded[i] = guestOrg{org: org, access: access}
+			wg.Add(1)
+			go func(g *guestOrg) {
+				defer wg.Done()
+				if err := spaceLoadPool.Do(context.Background(), func() {
+					g.spaces, g.err = loadGuestSpaces(g.org, g.access)
+				}); err != nil {
+					g.err = err
+				}
+			}(&loaded[i])
+		}
+		wg.Wait()
+
+		msg := guestOrgsLoadedMsg{}
+		for _, g := range loaded {
+			if g.access != (guestAccess{}) {
+				msg.orgs = append(msg.orgs, g)
+			}
+		}
+		return msg
+	}
+}
+
+// loadGuestSpaces lists org's spaces. Spaces of other orgs the access can
+// also see are dropped.
+func loadGuestSpaces(org CubOrganization, access guestAccess) ([]CubSpaceData, error) {
+	out, err := runGuestCubCommand(access, "space", "list", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var spaces []CubSpaceData
+	if err := json.Unmarshal(out, &spaces); err != nil {
+		return nil, fmt.Errorf("parse spaces: %w", err)
+	}
+	var own []CubSpaceData
+	for _, s := range spaces {
+		if s.Space.OrganizationID == "" || s.Space.OrganizationID == org.OrganizationID {
+			own = append(own, s)
+		}
+	}
+	return own, nil
+}
+
+// nonCurrentOrgs returns the orgs in the tree other than the current one.
+func (m *Model) nonCurrentOrgs() []CubOrganization {
+	var orgs []CubOrganization
+	for _, node := range m.nodes {
+		if org, ok := node.Data.(CubOrganization); ok && node.Type == "org" && !m.isCurrentOrg(org) {
+			orgs = append(orgs, org)
+		}
+	}
+	return orgs
+}
+
+// applyGuestOrgs adds the spaces of orgs read in guest mode to the tree.
+// Guest spaces show their counts but are not loaded: units, targets and
+// workers need a switch to the org.
+func (m *Model) applyGuestOrgs(msg guestOrgsLoadedMsg) {
+	m.guestOrgs = make(map[string]guestAccess)
+	for _, g := range msg.orgs {
+		var orgNode *TreeNode
+		for _, node := range m.nodes {
+			if node.Type == "org" && node.ID == g.org.OrganizationID {
+				orgNode = node
+			}
+		}
+		if orgNode == nil {
+			continue
+		}
+		if g.err != nil {
+			orgNode.Info = "read-only view failed"
+			continue
+		}
+		m.guestOrgs[orgNode.ID] = g.access
+
+		unitCount := 0
+		orgNode.Children = nil
+		for _, space := range g.spaces {
+			unitCount += space.TotalUnitCount
+			targetCount := 0
+			for _, count := range space.TargetCountByType {
+				targetCount += count
+			}
+			orgNode.Children = append(orgNode.Children, &TreeNode{
+				ID:     space.Space.Slug,
+				Name:   space.Space.Slug,
+				Type:   "guest_space",
+				Info:   fmt.Sprintf("units:%d targets:%d workers:%d", space.TotalUnitCount, targetCount, space.TotalBridgeWorkerCount),
+				Parent: orgNode,
+				Data:   space,
+				OrgID:  g.org.ExternalID,
+			})
+		}
+		orgNode.Info = fmt.Sprintf("%d spaces, %d units", len(g.spaces), unitCount)
+	}
+	m.treeChanged()
+	m.rebuildFlatList()
+}
+
+// isGuestOrg reports whether node is another org read in guest mode.
+func (m *Model) isGuestOrg(node *TreeNode) bool {
+	_, ok := m.guestOrgs[node.ID]
+	return ok && node.Type == "org"
+}
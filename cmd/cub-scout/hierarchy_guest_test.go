@@ -0,0 +1,114 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGuestAccessFor(t *testing.T) {
+	org := CubOrganization{OrganizationID: "org-2", ExternalID: "org_other", Slug: "other"}
+	var contexts []cubContext
+	var c cubContext
+	c.Name = "other-ctx"
+	c.Coordinate.OrganizationID = "org_other"
+	contexts = append(contexts, c)
+
+	if a, ok := guestAccessFor(org, map[string]string{"other": "tok"}, contexts); !ok || a.Token != "tok" {
+		t.Errorf("access = %+v, want the org token first", a)
+	}
+	if a, ok := guestAccessFor(org, map[string]string{"acme": "tok"}, contexts); !ok || a.Context != "other-ctx" {
+		t.Errorf("access = %+v, want the saved context", a)
+	}
+	if _, ok := guestAccessFor(org, nil, nil); ok {
+		t.Error("an org with no token or context can't be read")
+	}
+}
+
+func TestGuestOrgs(t *testing.T) {
+	// other is read through its saved context, tokened through a token
+	// from the token file, and locked has neither
+	fakeCub{
+		"context list --json": `[{"name": "default", "coordinate": {"organizationID": "org_acme"}},
+			{"name": "other-ctx", "coordinate": {"organizationID": "org_other"}}]`,
+		"--context other-ctx space list --json": `[
+			{"Space": {"Slug": "web", "OrganizationID": "org-2"}, "TotalUnitCount": 3, "TotalBridgeWorkerCount": 1, "TargetCountByToolchainType": {"Kubernetes/YAML": 2}},
+			{"Space": {"Slug": "elsewhere", "OrganizationID": "org-9"}, "TotalUnitCount": 7}]`,
+	}.install(t)
+	tokens := filepath.Join(t.TempDir(), "org-tokens.yaml")
+	if err := os.WriteFile(tokens, []byte("tokened: chk_123\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CUB_SCOUT_ORG_TOKENS", tokens)
+	prev := cubTokenExec
+	cubTokenExec = func(token string, args ...string) ([]byte, error) {
+		if token != "chk_123" || strings.Join(args, " ") != "space list --json" {
+			t.Errorf("token call: %s cub %v", token, args)
+		}
+		return []byte(`[{"Space": {"Slug": "db"}, "TotalUnitCount": 2}]`), nil
+	}
+	t.Cleanup(func() { cubTokenExec = prev })
+
+	org := func(id, ext, slug string) *TreeNode {
+		return &TreeNode{ID: id, Name: slug, Type: "org", Data: CubOrganization{OrganizationID: id, ExternalID: ext, Slug: slug, DisplayName: slug}}
+	}
+	m := Model{
+		nodes:       []*TreeNode{org("org-1", "org_acme", "acme"), org("org-2", "org_other", "other"), org("org-3", "org_tokened", "tokened"), org("org-4", "org_locked", "locked")},
+		keymap:      defaultKeyMap(),
+		ready:       true,
+		width:       80,
+		height:      20,
+		currentOrg:  "org_acme",
+		detailsPane: viewport.New(40, 10),
+	}
+	m.rebuildFlatList()
+
+	next, _ := m.Update(loadGuestOrgsCmd(m.nonCurrentOrgs())())
+	m = asModel(next)
+	other, tokened, locked := m.nodes[1], m.nodes[2], m.nodes[3]
+	if len(other.Children) != 1 || other.Children[0].Type != "guest_space" || other.Info != "1 spaces, 3 units" {
+		t.Errorf("other = %q %+v, want only its own space", other.Info, other.Children)
+	}
+	if other.Children[0].Info != "units:3 targets:2 workers:1" {
+		t.Errorf("guest space info = %q", other.Children[0].Info)
+	}
+	if len(tokened.Children) != 1 || tokened.Info != "1 spaces, 2 units" {
+		t.Errorf("tokened = %q %+v", tokened.Info, tokened.Children)
+	}
+	if len(locked.Children) != 0 || m.isGuestOrg(locked) {
+		t.Errorf("locked should not be readable: %+v", locked)
+	}
+
+	// → opens a guest org instead of prompting to switch
+	m.cursor = 1
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyRight})
+	if m.authPrompt || !other.Expanded {
+		t.Fatal("→ on a guest org should expand it read-only")
+	}
+	view := m.View()
+	for _, want := range []string{"other (read-only)", "web", "locked (switch org)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q:\n%s", want, view)
+		}
+	}
+
+	// Its spaces are never loaded, and the org is still a switch away
+	m.cursor = 2
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = asModel(next)
+	if cmd != nil || len(m.spaceLoads) != 0 {
+		t.Error("guest spaces should not load units")
+	}
+	m.cursor = 1
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.authPrompt || m.authOrgID != "org_other" {
+		t.Error("Enter on a guest org should still offer to switch to it")
+	}
+}
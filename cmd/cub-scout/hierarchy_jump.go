@@ -0,0 +1,251 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// jumpResultLimit is the number of candidates the jump overlay lists.
+const jumpResultLimit = 10
+
+// openJump opens the fuzzy jump overlay (ctrl+p) with an empty query.
+func (m *Model) openJump() {
+	m.jumpMode = true
+	m.jumpQuery = ""
+	m.jumpResults = nil
+	m.jumpCursor = 0
+}
+
+// updateJump handles keys while the jump overlay is open: typing narrows
+// the candidates, ↑/↓ pick one, Enter jumps to it.
+func (m *Model) updateJump(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+p":
+		m.jumpMode = false
+		return m, nil
+	case "up", "ctrl+k":
+		if m.jumpCursor > 0 {
+			m.jumpCursor--
+		}
+		return m, nil
+	case "down", "ctrl+j":
+		if m.jumpCursor < len(m.jumpResults)-1 {
+			m.jumpCursor++
+		}
+		return m, nil
+	case "enter":
+		m.jumpMode = false
+		if m.jumpCursor < len(m.jumpResults) {
+			return m, m.jumpTo(m.jumpResults[m.jumpCursor])
+		}
+		return m, nil
+	case "backspace":
+		if len(m.jumpQuery) > 0 {
+			m.jumpQuery = m.jumpQuery[:len(m.jumpQuery)-1]
+		}
+	default:
+		if len(msg.String()) != 1 {
+			return m, nil
+		}
+		m.jumpQuery += msg.String()
+	}
+	m.jumpResults = fuzzyJumpResults(m.nodes, m.jumpQuery, jumpResultLimit)
+	m.jumpCursor = 0
+	return m, nil
+}
+
+// jumpTo expands the node's ancestors, moves the cursor to it and loads its
+// details. A search filter or cluster filter hiding the node is cleared.
+func (m *Model) jumpTo(node *TreeNode) tea.Cmd {
+	var cmds []tea.Cmd
+	for p := node.Parent; p != nil; p = p.Parent {
+		p.Expanded = true
+		if spaceSlug := spaceSlugOf(p); spaceSlug != "" {
+			cmds = append(cmds, m.requestSpaceLoad(spaceSlug))
+		}
+	}
+
+	if !m.focusNode(node) {
+		m.searchQuery = ""
+		m.searchMatches = nil
+		m.filterActive = false
+		if node.Type == "unit" && !m.unitMatchesCurrentCluster(node) {
+			m.showAllUnits = true
+			m.statusMsg = "Showing all units"
+		}
+		if !m.focusNode(node) {
+			m.statusMsg = "Could not show " + node.Name
+			return tea.Batch(cmds...)
+		}
+	}
+
+	if node.Type == "org" {
+		if org, ok := node.Data.(CubOrganization); ok && !m.isCurrentOrg(org) {
+			m.authPrompt = true
+			m.authOrgName = org.DisplayName
+			m.authOrgID = org.ExternalID
+			return tea.Batch(cmds...)
+		}
+	}
+
+	m.detailsLoading = true
+	m.detailsError = nil
+	m.detailsNode = node
+	cmds = append(cmds, loadEntityDetailsCmd(node))
+	return tea.Batch(cmds...)
+}
+
+// focusNode rebuilds the visible list and puts the cursor on node, returning
+// false if the node is not visible.
+func (m *Model) focusNode(node *TreeNode) bool {
+	m.rebuildFlatList()
+	for i, n := range m.flatList {
+		if n == node {
+			m.cursor = i
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyJumpResults returns up to limit loaded nodes matching query, best
+// match first. Detail rows (a unit's fields) are not candidates.
+func fuzzyJumpResults(roots []*TreeNode, query string, limit int) []*TreeNode {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	type candidate struct {
+		node  *TreeNode
+		score int
+	}
+	var candidates []candidate
+	var walk func(nodes []*TreeNode)
+	walk = func(nodes []*TreeNode) {
+		for _, n := range nodes {
+			if n.Type != "detail" {
+				if score, ok := jumpScore(n, terms); ok {
+					candidates = append(candidates, candidate{n, score})
+				}
+			}
+			walk(n.Children)
+		}
+	}
+	walk(roots)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return len(candidates[i].node.Name) < len(candidates[j].node.Name)
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	results := make([]*TreeNode, len(candidates))
+	for i, c := range candidates {
+		results[i] = c.node
+	}
+	return results
+}
+
+// jumpScore matches every term fuzzily against the node's name, or failing
+// that literally against the names of its ancestors, so "chk prod" finds
+// checkout-prod and "prod api" finds the api unit in a prod space.
+func jumpScore(node *TreeNode, terms []string) (int, bool) {
+	name := strings.ToLower(node.Name)
+	var path string
+	for p := node.Parent; p != nil; p = p.Parent {
+		path = strings.ToLower(p.Name) + "/" + path
+	}
+
+	total := 0
+	for _, term := range terms {
+		if score, ok := fuzzyMatch(name, term); ok {
+			total += 2 * score
+		} else if strings.Contains(path, term) {
+			total += len(term)
+		} else {
+			return 0, false
+		}
+	}
+	return total, true
+}
+
+// fuzzyMatch reports whether the characters of term appear in s in order,
+// scoring consecutive characters and characters at word starts higher.
+func fuzzyMatch(s, term string) (int, bool) {
+	if i := strings.Index(s, term); i >= 0 {
+		score := 4 * len(term)
+		if i == 0 || isJumpSeparator(s[i-1]) {
+			score += 4
+		}
+		return score, true
+	}
+
+	score, pos, last := 0, 0, -2
+	for k := 0; k < len(term); k++ {
+		i := strings.IndexByte(s[pos:], term[k])
+		if i < 0 {
+			return 0, false
+		}
+		i += pos
+		score++
+		if i == last+1 {
+			score += 2
+		}
+		if i == 0 || isJumpSeparator(s[i-1]) {
+			score += 2
+		}
+		last, pos = i, i+1
+	}
+	return score, true
+}
+
+func isJumpSeparator(c byte) bool {
+	return c == '-' || c == '_' || c == '.' || c == '/' || c == ' '
+}
+
+func (m Model) renderJumpOverlay() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(" JUMP TO "))
+	b.WriteString("\n\n")
+	b.WriteString(activeStyle.Render("> ") + m.jumpQuery + "▌")
+	b.WriteString("\n\n")
+
+	switch {
+	case m.jumpQuery == "":
+		b.WriteString(dimStyle.Render("Type part of a name, e.g. \"chk prod\" for checkout-prod"))
+		b.WriteString("\n")
+	case len(m.jumpResults) == 0:
+		b.WriteString(dimStyle.Render("No loaded orgs, spaces, units, targets or workers match"))
+		b.WriteString("\n")
+	}
+
+	for i, node := range m.jumpResults {
+		cursor := "  "
+		name := node.Name
+		if i == m.jumpCursor {
+			cursor = activeStyle.Render("> ")
+			name = activeStyle.Render(name)
+		}
+		where := strings.TrimPrefix(nodePath(node.Parent), "/")
+		info := dimStyle.Render(" " + node.Type)
+		if where != "" {
+			info += dimStyle.Render("  " + where)
+		}
+		b.WriteString(cursor + name + info)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("type to filter  ↑↓ navigate  Enter jump  Esc cancel"))
+	return b.String()
+}
@@ -0,0 +1,127 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// jumpTestModel returns a model with two collapsed spaces, one unit hidden
+// by the cluster filter.
+func jumpTestModel() Model {
+	org := &TreeNode{ID: "org-1", Name: "acme", Type: "org", Expanded: true}
+	prod := &TreeNode{ID: "prod", Name: "prod", Type: "space", Parent: org}
+	units := &TreeNode{ID: "prod/units", Name: "Units", Type: "group", Parent: prod}
+	var onOtherCluster CubUnitData
+	onOtherCluster.Target.Slug = "other-cluster"
+	checkout := &TreeNode{ID: "checkout-prod", Name: "checkout-prod", Type: "unit", Parent: units, Data: onOtherCluster}
+	api := &TreeNode{ID: "api", Name: "api", Type: "unit", Parent: units}
+	units.Children = []*TreeNode{checkout, api,
+		{ID: "api/status", Name: "Status", Type: "detail", Parent: api}}
+	prod.Children = []*TreeNode{units}
+	staging := &TreeNode{ID: "staging", Name: "staging", Type: "space", Parent: org}
+	staging.Children = []*TreeNode{{ID: "checkout-staging", Name: "checkout-staging", Type: "unit", Parent: staging}}
+	org.Children = []*TreeNode{prod, staging}
+
+	m := Model{
+		nodes:          []*TreeNode{org},
+		keymap:         defaultKeyMap(),
+		ready:          true,
+		width:          80,
+		height:         20,
+		currentCluster: "this-cluster",
+		spaceLoads:     map[string]spaceLoadState{"prod": spaceLoaded, "staging": spaceLoaded},
+		detailsPane:    viewport.New(40, 10),
+	}
+	m.rebuildFlatList()
+	return m
+}
+
+// pressKeys sends keys to the model, which returns either a Model or, from
+// overlay handlers, a *Model.
+func pressKeys(m Model, msgs ...tea.KeyMsg) Model {
+	for _, msg := range msgs {
+		next, _ := m.Update(msg)
+		if p, ok := next.(*Model); ok {
+			m = *p
+		} else {
+			m = next.(Model)
+		}
+	}
+	return m
+}
+
+func TestFuzzyJumpResults(t *testing.T) {
+	m := jumpTestModel()
+	names := func(query string) []string {
+		var out []string
+		for _, n := range fuzzyJumpResults(m.nodes, query, jumpResultLimit) {
+			out = append(out, n.Name)
+		}
+		return out
+	}
+
+	if got := names("chk prod"); len(got) == 0 || got[0] != "checkout-prod" {
+		t.Errorf(`"chk prod" = %v, want checkout-prod first`, got)
+	}
+	if got := names("prod api"); len(got) != 1 || got[0] != "api" {
+		t.Errorf(`"prod api" = %v, want the api unit via its space`, got)
+	}
+	if got := names("checkout"); len(got) != 2 {
+		t.Errorf(`"checkout" = %v, want both checkout units`, got)
+	}
+	if got := names("status"); len(got) != 0 {
+		t.Errorf("detail rows should not be candidates, got %v", got)
+	}
+	if got := names("zzz"); len(got) != 0 {
+		t.Errorf(`"zzz" = %v`, got)
+	}
+}
+
+func TestJumpExpandsAndSelects(t *testing.T) {
+	m := jumpTestModel()
+	press := func(msgs ...tea.KeyMsg) { m = pressKeys(m, msgs...) }
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if !m.jumpMode {
+		t.Fatal("ctrl+p should open the jump overlay")
+	}
+	for _, r := range "chk prod" {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if !strings.Contains(m.View(), "checkout-prod") {
+		t.Fatalf("overlay should list checkout-prod:\n%s", m.View())
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.jumpMode {
+		t.Error("enter should close the overlay")
+	}
+	node := m.flatList[m.cursor]
+	if node.Name != "checkout-prod" {
+		t.Fatalf("cursor on %s, want checkout-prod", node.Name)
+	}
+	if !node.Parent.Expanded || !node.Parent.Parent.Expanded {
+		t.Error("ancestors should be expanded")
+	}
+	if !m.showAllUnits {
+		t.Error("a unit hidden by the cluster filter should turn the filter off")
+	}
+	if m.detailsNode != node {
+		t.Error("details should load for the jumped-to node")
+	}
+}
+
+func TestJumpEscCloses(t *testing.T) {
+	m := jumpTestModel()
+	cursor := m.cursor
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyCtrlP}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.jumpMode || m.cursor != cursor {
+		t.Errorf("esc should close without moving: jumpMode=%v cursor=%d", m.jumpMode, m.cursor)
+	}
+}
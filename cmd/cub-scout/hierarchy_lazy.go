@@ -0,0 +1,185 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/confighub/cub-scout/internal/hierarchysvc"
+)
+
+// lazySpaceThreshold is the number of spaces above which the hierarchy loads
+// space contents on demand instead of all at startup. Smaller orgs keep the
+// eager load so summaries and cross-space views are complete from the start.
+const lazySpaceThreshold = 50
+
+// Background space loads run at most spaceLoadWorkers `cub` subprocesses at
+// a time (CUB_SCOUT_HUB_CONCURRENCY overrides it) and start at most
+// spaceLoadRate per second. Without this, a 100-space org forks 300
+// processes at startup.
+const (
+	spaceLoadWorkers = 4
+	spaceLoadRate    = 10
+)
+
+var spaceLoadPool = hierarchysvc.NewLoadPool(spaceLoadConcurrency(), spaceLoadRate)
+
+// spaceLoadConcurrency returns the number of concurrent space-load
+// subprocesses from CUB_SCOUT_HUB_CONCURRENCY, or spaceLoadWorkers.
+func spaceLoadConcurrency() int {
+	if n, err := strconv.Atoi(os.Getenv("CUB_SCOUT_HUB_CONCURRENCY")); err == nil && n > 0 {
+		return n
+	}
+	return spaceLoadWorkers
+}
+
+// spaceLoadState tracks the background load of one space's units, targets
+// and workers.
+type spaceLoadState int
+
+const (
+	spaceNotLoaded spaceLoadState = iota
+	spaceLoading
+	spaceLoaded
+)
+
+// spacesToLoadAtStartup picks the spaces to load in the background when the
+// tree first appears: all of them for small orgs, otherwise only the
+// default space. The rest load when expanded.
+func spacesToLoadAtStartup(spaces []string, defaultSpace string) []string {
+	if len(spaces) <= lazySpaceThreshold {
+		return spaces
+	}
+	for _, s := range spaces {
+		if s == defaultSpace {
+			return []string{s}
+		}
+	}
+	return nil
+}
+
+// requestSpaceLoad returns a command loading the space's contents, or nil
+// if it is loaded or already loading.
+func (m *Model) requestSpaceLoad(spaceSlug string) tea.Cmd {
+	if m.spaceLoads == nil {
+		m.spaceLoads = make(map[string]spaceLoadState)
+	}
+	if m.spaceLoads[spaceSlug] != spaceNotLoaded {
+		return nil
+	}
+	m.spaceLoads[spaceSlug] = spaceLoading
+	return loadSpaceDataCmd(spaceSlug)
+}
+
+// loadExpandedSpaces requests a load for every expanded space that has not
+// been loaded, e.g. after expanded paths are restored from a snapshot.
+func (m *Model) loadExpandedSpaces() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, org := range m.nodes {
+		for _, space := range org.Children {
+			if space.Type == "space" && space.Expanded {
+				if cmd := m.requestSpaceLoad(space.ID); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+			}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// spaceSlugOf returns the slug of the space a space or group node belongs
+// to, or "".
+func spaceSlugOf(node *TreeNode) string {
+	switch {
+	case node.Type == "space":
+		return node.ID
+	case node.Type == "group" && node.Parent != nil && node.Parent.Type == "space":
+		return node.Parent.ID
+	}
+	return ""
+}
+
+// treeChanged drops the search index and match cache. Call it whenever
+// nodes are added to or removed from the tree.
+func (m *Model) treeChanged() {
+	m.searchText = nil
+	m.matchCache = nil
+	m.matchQuery = ""
+}
+
+// nodeSearchText returns the lowercased text search matches a node
+// against, computing it once per node.
+func (m *Model) nodeSearchText(node *TreeNode) string {
+	if text, ok := m.searchText[node]; ok {
+		return text
+	}
+	if m.searchText == nil {
+		m.searchText = make(map[*TreeNode]string)
+	}
+	text := strings.ToLower(node.Name) + "\n" + strings.ToLower(node.Info)
+	m.searchText[node] = text
+	return text
+}
+
+// syncMatchCache prepares the match cache for the current query. When the
+// query only grew (a character was typed), a node that matched nothing
+// before still matches nothing, so those results are kept and only the
+// previous matches are checked again.
+func (m *Model) syncMatchCache() {
+	query := strings.ToLower(m.searchQuery)
+	switch {
+	case m.matchCache == nil || m.matchQuery == "" || !strings.Contains(query, m.matchQuery):
+		m.matchCache = make(map[*TreeNode]bool)
+	case query != m.matchQuery:
+		for node, matched := range m.matchCache {
+			if matched {
+				delete(m.matchCache, node)
+			}
+		}
+	}
+	m.matchQuery = query
+}
+
+// treeHeight is the number of tree rows that fit in the left pane, or 0 when
+// the window size is not known yet.
+func (m Model) treeHeight() int {
+	if m.height <= 8 {
+		return 0
+	}
+	return m.height - 8
+}
+
+// treeWindow returns the range of flatList rows to render: height rows
+// starting at offset, moved just enough to keep the cursor visible.
+func treeWindow(offset, cursor, total, height int) (start, end int) {
+	if height <= 0 || total <= height {
+		return 0, total
+	}
+	start = offset
+	if cursor < start {
+		start = cursor
+	}
+	if cursor >= start+height {
+		start = cursor - height + 1
+	}
+	start = max(0, min(start, total-height))
+	return start, start + height
+}
+
+// scrollTreeToCursor moves the stored tree offset so the cursor stays on
+// screen, scrolling only when it reaches an edge.
+func (m *Model) scrollTreeToCursor() {
+	m.treeOffset, _ = treeWindow(m.treeOffset, m.cursor, len(m.flatList), m.treeHeight())
+}
+
+// isSearchMatch returns true if the given flatList index is a search match
+func (m *Model) isSearchMatch(idx int) bool {
+	i := sort.SearchInts(m.searchMatches, idx)
+	return i < len(m.searchMatches) && m.searchMatches[i] == idx
+}
@@ -0,0 +1,168 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// largeOrgModel returns a model with one expanded space holding n units.
+func largeOrgModel(n int) Model {
+	org := &TreeNode{ID: "org-1", Name: "big-org", Type: "org", Expanded: true}
+	space := &TreeNode{ID: "space-1", Name: "space-1", Type: "space", Parent: org, Expanded: true}
+	for i := 0; i < n; i++ {
+		space.Children = append(space.Children, &TreeNode{
+			ID: fmt.Sprintf("unit-%04d", i), Name: fmt.Sprintf("unit-%04d", i), Type: "unit", Parent: space,
+		})
+	}
+	org.Children = []*TreeNode{space}
+
+	m := Model{
+		nodes:       []*TreeNode{org},
+		keymap:      defaultKeyMap(),
+		ready:       true,
+		width:       80,
+		height:      20,
+		detailsPane: viewport.New(40, 10),
+	}
+	m.rebuildFlatList()
+	return m
+}
+
+func TestSpacesToLoadAtStartup(t *testing.T) {
+	small := []string{"a", "b", "c"}
+	if got := spacesToLoadAtStartup(small, "b"); len(got) != 3 {
+		t.Errorf("small org should load every space, got %v", got)
+	}
+
+	var large []string
+	for i := 0; i <= lazySpaceThreshold; i++ {
+		large = append(large, fmt.Sprintf("space-%d", i))
+	}
+	if got := spacesToLoadAtStartup(large, "space-7"); len(got) != 1 || got[0] != "space-7" {
+		t.Errorf("large org should load only the default space, got %v", got)
+	}
+	if got := spacesToLoadAtStartup(large, ""); len(got) != 0 {
+		t.Errorf("large org without a default space should load nothing, got %v", got)
+	}
+}
+
+func TestRequestSpaceLoadOnce(t *testing.T) {
+	m := largeOrgModel(1)
+	if m.requestSpaceLoad("space-1") == nil {
+		t.Fatal("first request should return a load command")
+	}
+	if m.requestSpaceLoad("space-1") != nil {
+		t.Error("a space already loading should not be requested again")
+	}
+
+	next, _ := m.Update(spaceDataLoadedMsg{spaceSlug: "space-1"})
+	m = next.(Model)
+	if m.spaceLoads["space-1"] != spaceLoaded {
+		t.Errorf("state = %v, want loaded", m.spaceLoads["space-1"])
+	}
+	if m.requestSpaceLoad("space-1") != nil {
+		t.Error("a loaded space should not be requested again")
+	}
+}
+
+func TestTreeWindow(t *testing.T) {
+	tests := []struct {
+		name                          string
+		offset, cursor, total, height int
+		wantStart, wantEnd            int
+	}{
+		{"fits", 0, 3, 5, 10, 0, 5},
+		{"unknown height", 0, 50, 100, 0, 0, 100},
+		{"cursor in view", 10, 15, 100, 10, 10, 20},
+		{"cursor below", 10, 25, 100, 10, 16, 26},
+		{"cursor above", 10, 4, 100, 10, 4, 14},
+		{"clamped at end", 95, 99, 100, 10, 90, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := treeWindow(tt.offset, tt.cursor, tt.total, tt.height)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("treeWindow = [%d, %d), want [%d, %d)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestRenderTreeOnlyVisibleRows(t *testing.T) {
+	m := largeOrgModel(2000)
+	for i := 0; i < 1500; i++ {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = next.(Model)
+	}
+
+	out := m.renderTree()
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != m.treeHeight() {
+		t.Fatalf("rendered %d rows, want %d", len(lines), m.treeHeight())
+	}
+	cursorNode := m.flatList[m.cursor].Name
+	if !strings.Contains(lines[len(lines)-1], cursorNode) {
+		t.Errorf("cursor row %s should be the last visible row:\n%s", cursorNode, out)
+	}
+
+	// Moving up inside the window does not scroll
+	offset := m.treeOffset
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if m = next.(Model); m.treeOffset != offset {
+		t.Errorf("offset moved from %d to %d while cursor stayed in view", offset, m.treeOffset)
+	}
+}
+
+func TestSyncMatchCacheKeepsMissesWhileQueryGrows(t *testing.T) {
+	m := largeOrgModel(3)
+	miss := m.flatList[3] // unit-0001
+
+	m.searchQuery = "unit-000"
+	m.filterActive = true
+	m.rebuildFlatList()
+	if !m.matchCache[miss] {
+		t.Fatalf("%s should match %q", miss.Name, m.searchQuery)
+	}
+
+	m.searchQuery = "unit-0000"
+	m.syncMatchCache()
+	if _, ok := m.matchCache[miss]; ok {
+		t.Error("previous matches must be checked again when the query grows")
+	}
+	m.rebuildFlatList()
+	if m.matchCache[miss] {
+		t.Errorf("%s should not match %q", miss.Name, m.searchQuery)
+	}
+
+	// Narrowing keeps the misses; a query that is not an extension resets
+	m.searchQuery = "unit-00001"
+	m.syncMatchCache()
+	if matched, ok := m.matchCache[miss]; !ok || matched {
+		t.Error("a miss should stay cached while the query grows")
+	}
+	m.searchQuery = "unit-0001"
+	m.syncMatchCache()
+	if len(m.matchCache) != 0 {
+		t.Error("cache should reset when the query is not an extension")
+	}
+}
+
+func TestTreeChangedDropsSearchIndex(t *testing.T) {
+	m := largeOrgModel(1)
+	unit := m.flatList[2]
+	if got := m.nodeSearchText(unit); got != "unit-0000\n" {
+		t.Fatalf("search text = %q", got)
+	}
+	unit.Info = "Drifted"
+	m.treeChanged()
+	if got := m.nodeSearchText(unit); got != "unit-0000\ndrifted" {
+		t.Errorf("search text after change = %q", got)
+	}
+}
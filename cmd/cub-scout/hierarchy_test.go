@@ -6,10 +6,12 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +19,9 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
 )
 
 // skipIfNoCub skips the test if the 'cub' CLI is not available.
@@ -561,6 +566,50 @@ func TestHierarchyOrgSwitch(t *testing.T) {
 	}
 }
 
+// TestHierarchyOrgSwitchNoContext tests that switching to an org without a
+// cub context offers a login instead of failing.
+func TestHierarchyOrgSwitchNoContext(t *testing.T) {
+	m := testModelMultipleOrgs()
+	m.authOrgID = "org-2"
+	m.authOrgName = "other-org"
+	m.loading = true
+
+	next, _ := m.Update(authCompleteMsg{orgID: "org-2", noContext: true})
+	m = next.(Model)
+	if !m.orgLoginPrompt || m.loading {
+		t.Fatalf("orgLoginPrompt = %v, loading = %v; want prompt shown", m.orgLoginPrompt, m.loading)
+	}
+	if view := m.View(); !strings.Contains(view, "No cub context for organization 'other-org'") || !strings.Contains(view, "[l] Log in now") {
+		t.Errorf("prompt not rendered:\n%s", view)
+	}
+
+	// A failed login reports why and leaves the TUI usable
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	m = next.(Model)
+	if m.orgLoginPrompt || !m.loading {
+		t.Fatal("'l' should start the login")
+	}
+	next, _ = m.Update(orgLoginDoneMsg{orgID: "org-2", err: errors.New("exit status 1")})
+	m = next.(Model)
+	if m.loading || !strings.Contains(m.statusMsg, "cub auth login failed") {
+		t.Errorf("loading = %v, status = %q", m.loading, m.statusMsg)
+	}
+
+	// Still no context after a login: explain, don't prompt again
+	next, _ = m.Update(authCompleteMsg{orgID: "org-2", noContext: true, afterLogin: true})
+	m = next.(Model)
+	if m.orgLoginPrompt || !strings.Contains(m.statusMsg, "choose other-org") {
+		t.Errorf("orgLoginPrompt = %v, status = %q", m.orgLoginPrompt, m.statusMsg)
+	}
+
+	// Other failures say what went wrong
+	next, _ = m.Update(authCompleteMsg{orgID: "org-2", err: errors.New("cub context list: not logged in")})
+	m = next.(Model)
+	if !strings.Contains(m.statusMsg, "Failed to switch to other-org: cub context list: not logged in") {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
+
 // TestHierarchyCommandPalette tests that ':' key enters command mode.
 func TestHierarchyCommandPalette(t *testing.T) {
 	skipIfNoCub(t)
@@ -873,6 +922,74 @@ func TestHubSnapshotSaveLoad(t *testing.T) {
 	}
 }
 
+// TestHubSnapshotNavigationContext tests that search, filter, details and
+// scroll state survive a save/restore and the cursor is restored by path.
+func TestHubSnapshotNavigationContext(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := testModel()
+	m.nodes[0].Children[0].Expanded = true
+	m.rebuildFlatList()
+	m.cursor = 2 // test-unit
+	m.searchQuery = "test"
+	m.filterActive = true
+	m.detailsNode = m.flatList[2]
+	m.detailsPane.SetContent(strings.Repeat("line\n", 50))
+	m.detailsPane.SetYOffset(7)
+	saveHubSnapshot(&m)
+
+	snap := loadHubSnapshot()
+	if snap == nil {
+		t.Fatal("snapshot was not loaded")
+	}
+	if snap.CursorPath != "/test-org/test-space/test-unit" || snap.DetailsPath != snap.CursorPath {
+		t.Errorf("paths = %q, %q", snap.CursorPath, snap.DetailsPath)
+	}
+	if snap.SearchQuery != "test" || !snap.FilterActive || snap.DetailsOffset != 7 {
+		t.Errorf("snapshot = %+v", snap)
+	}
+
+	// Restore into a fresh tree where the unit sits at a different index
+	r := testModel()
+	r.nodes[0].Children[0].Children = append([]*TreeNode{{Name: "new-unit", Type: "unit", Parent: r.nodes[0].Children[0]}}, r.nodes[0].Children[0].Children...)
+	r.nodes[0].Children[0].Expanded = true
+	r.rebuildFlatList()
+	r.pendingSnapshot = snap
+	if cmd := r.restoreSnapshotPositions(); cmd == nil {
+		t.Error("expected a details reload command")
+	}
+	if got := r.flatList[r.cursor].Name; got != "test-unit" {
+		t.Errorf("cursor on %q, want test-unit", got)
+	}
+	if r.pendingSnapshot != nil || r.detailsRestore != 7 {
+		t.Errorf("pending = %v, detailsRestore = %d", r.pendingSnapshot, r.detailsRestore)
+	}
+}
+
+// TestHubSnapshotSavedOnPanic tests that a panic in Update writes the
+// snapshot before the panic reaches bubbletea.
+func TestHubSnapshotSavedOnPanic(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	m := testModel()
+	m.searchQuery = "before-crash"
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic should be re-raised")
+			}
+		}()
+		defer saveHubSnapshotOnPanic(&m)
+		panic("boom")
+	}()
+
+	snap := loadHubSnapshot()
+	if snap == nil || snap.SearchQuery != "before-crash" {
+		t.Fatalf("snapshot after panic = %+v", snap)
+	}
+}
+
 // TestHubSnapshotExpiry tests that old snapshots are not loaded.
 func TestHubSnapshotExpiry(t *testing.T) {
 	// Use a temp directory for testing
@@ -1129,3 +1246,23 @@ func TestHubViewModeHeader(t *testing.T) {
 		t.Error("mode header should contain 'All Units' when not filtered")
 	}
 }
+
+func TestCountWorkloadsWithOwners(t *testing.T) {
+	items := []unstructured.Unstructured{
+		*agenttest.Deployment("prod", "hub", agenttest.WithLabels(map[string]string{"confighub.com/UnitSlug": "hub"})),
+		*agenttest.Deployment("prod", "flux", agenttest.WithLabels(map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps"})),
+		*agenttest.Deployment("prod", "helm", agenttest.WithLabels(map[string]string{"app.kubernetes.io/managed-by": "Helm"})),
+		*agenttest.Deployment("prod", "plain"),
+		*agenttest.Deployment("staging", "other"),
+	}
+
+	info := namespaceInfo{Name: "prod"}
+	countWorkloadsWithOwners(inNamespace(items, "prod"), &info, &info.Deployments)
+	if info.Deployments != 4 {
+		t.Errorf("Deployments = %d, want 4", info.Deployments)
+	}
+	if info.ConfigHubCount != 1 || info.FluxCount != 1 || info.HelmCount != 1 || info.NativeCount != 1 {
+		t.Errorf("owner counts = hub %d, flux %d, helm %d, native %d; want 1 each",
+			info.ConfigHubCount, info.FluxCount, info.HelmCount, info.NativeCount)
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/confighub/cub-scout/internal/hierarchysvc"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -137,6 +139,8 @@ func renderStatusIcon(status string) string {
 		return statusWarn.Render(iconCheckWarn) + " "
 	case "error":
 		return statusErr.Render(iconCheckErr) + " "
+	case statusGated:
+		return statusWarn.Render(iconGated) + " "
 	default:
 		return ""
 	}
@@ -155,6 +159,7 @@ const (
 	iconCheckOK   = "✓"
 	iconCheckWarn = "⚠"
 	iconCheckErr  = "✗"
+	iconGated     = "◷"
 	iconFolder    = "📁"
 	iconUnit      = "📦"
 	iconTarget    = "🎯"
@@ -182,9 +187,10 @@ type CubOrganization struct {
 
 type CubSpaceData struct {
 	Space struct {
-		Slug           string `json:"Slug"`
-		SpaceID        string `json:"SpaceID"`
-		OrganizationID string `json:"OrganizationID"`
+		Slug           string            `json:"Slug"`
+		SpaceID        string            `json:"SpaceID"`
+		OrganizationID string            `json:"OrganizationID"`
+		Labels         map[string]string `json:"Labels"`
 	} `json:"Space"`
 	TotalUnitCount         int            `json:"TotalUnitCount"`
 	TotalBridgeWorkerCount int            `json:"TotalBridgeWorkerCount"`
@@ -193,12 +199,27 @@ type CubSpaceData struct {
 
 type CubUnitData struct {
 	Unit struct {
-		Slug            string `json:"Slug"`
-		HeadRevisionNum int    `json:"HeadRevisionNum"`
-		LiveRevisionNum int    `json:"LiveRevisionNum"`
-		ToolchainType   string `json:"ToolchainType"`
+		UnitID          string            `json:"UnitID"`
+		SpaceID         string            `json:"SpaceID"`
+		Slug            string            `json:"Slug"`
+		HeadRevisionNum int               `json:"HeadRevisionNum"`
+		LiveRevisionNum int               `json:"LiveRevisionNum"`
+		ToolchainType   string            `json:"ToolchainType"`
+		Labels          map[string]string `json:"Labels"`
+		// UpstreamUnitID is the unit this one was cloned from, and
+		// UpstreamRevisionNum the upstream revision it was last upgraded to
+		UpstreamUnitID      string `json:"UpstreamUnitID"`
+		UpstreamRevisionNum int    `json:"UpstreamRevisionNum"`
+		// ApplyGates are the triggers blocking apply, and ApprovedBy the
+		// users who approved the head revision
+		ApplyGates map[string]bool `json:"ApplyGates"`
+		ApprovedBy []string        `json:"ApprovedBy"`
+		// ChangeSetID is the changeset the unit's pending change belongs to
+		ChangeSetID string `json:"ChangeSetID"`
 	} `json:"Unit"`
 	Target struct {
+		TargetID      string `json:"TargetID"`
+		SpaceID       string `json:"SpaceID"` // space owning the target, often a Hub
 		Slug          string `json:"Slug"`
 		ProviderType  string `json:"ProviderType"`
 		ToolchainType string `json:"ToolchainType"`
@@ -209,29 +230,98 @@ type CubUnitData struct {
 		Drift        string `json:"Drift"`
 		Action       string `json:"Action"`
 		ActionResult string `json:"ActionResult"`
+		// ActionTerminatedAt is when Action last finished (RFC 3339)
+		ActionTerminatedAt string `json:"ActionTerminatedAt"`
 	} `json:"UnitStatus"`
 	BridgeWorker struct {
-		Slug       string `json:"Slug"`
-		Condition  string `json:"Condition"`
-		IPAddress  string `json:"IPAddress"`
-		LastSeenAt string `json:"LastSeenAt"`
+		BridgeWorkerID string `json:"BridgeWorkerID"`
+		Slug           string `json:"Slug"`
+		Condition      string `json:"Condition"`
+		IPAddress      string `json:"IPAddress"`
+		LastSeenAt     string `json:"LastSeenAt"`
 	} `json:"BridgeWorker"`
 	Space struct {
 		Slug string `json:"Slug"`
 	} `json:"Space"`
 }
 
+// CubLinkData is an entry of `cub link list --json`: a link from a unit to
+// the unit whose values it needs.
+type CubLinkData struct {
+	Link struct {
+		LinkID     string `json:"LinkID"`
+		Slug       string `json:"Slug"`
+		FromUnitID string `json:"FromUnitID"`
+		ToUnitID   string `json:"ToUnitID"`
+	} `json:"Link"`
+	FromUnit struct {
+		Slug string `json:"Slug"`
+	} `json:"FromUnit"`
+	ToUnit struct {
+		Slug string `json:"Slug"`
+	} `json:"ToUnit"`
+}
+
+// CubChangeSetData is an entry of `cub changeset list --json`: a change
+// to several units that is applied together.
+type CubChangeSetData struct {
+	ChangeSet struct {
+		ChangeSetID string `json:"ChangeSetID"`
+		Slug        string `json:"Slug"`
+		DisplayName string `json:"DisplayName"`
+		Description string `json:"Description"`
+		CreatedAt   string `json:"CreatedAt"`
+	} `json:"ChangeSet"`
+}
+
+// CubTriggerData is an entry of `cub trigger list --json`: a function
+// that runs on the units of a space on every change.
+type CubTriggerData struct {
+	Trigger struct {
+		TriggerID     string `json:"TriggerID"`
+		Slug          string `json:"Slug"`
+		Event         string `json:"Event"`
+		ToolchainType string `json:"ToolchainType"`
+		FunctionName  string `json:"FunctionName"`
+		Arguments     []struct {
+			ParameterName string      `json:"ParameterName"`
+			Value         interface{} `json:"Value"`
+		} `json:"Arguments"`
+		Disabled   bool `json:"Disabled"`
+		Enforced   bool `json:"Enforced"`
+		Validating bool `json:"Validating"`
+	} `json:"Trigger"`
+}
+
 type CubTargetData struct {
 	Target struct {
-		Slug         string `json:"Slug"`
-		ProviderType string `json:"ProviderType"`
+		TargetID       string `json:"TargetID"`
+		Slug           string `json:"Slug"`
+		ProviderType   string `json:"ProviderType"`
+		BridgeWorkerID string `json:"BridgeWorkerID"`
+		Parameters     string `json:"Parameters"` // JSON object, e.g. {"KubeContext":"kind-prod"}
 	} `json:"Target"`
 }
 
+// KubeContext returns the kubeconfig context the target deploys to, or ""
+// when the target parameters do not name one.
+func (t CubTargetData) KubeContext() string {
+	var params struct {
+		KubeContext string `json:"KubeContext"`
+	}
+	if t.Target.Parameters == "" || json.Unmarshal([]byte(t.Target.Parameters), &params) != nil {
+		return ""
+	}
+	return params.KubeContext
+}
+
 type CubWorkerData struct {
 	BridgeWorker struct {
-		Slug      string `json:"Slug"`
-		Condition string `json:"Condition"`
+		BridgeWorkerID string `json:"BridgeWorkerID"`
+		Slug           string `json:"Slug"`
+		Condition      string `json:"Condition"`
+		LastSeenAt     string `json:"LastSeenAt"`
+		Version        string `json:"Version"`
 	} `json:"BridgeWorker"`
 }
 
@@ -240,6 +330,9 @@ func (u CubUnitData) DeriveStatus() string {
 	if u.UnitStatus.Status == "Error" {
 		return "error"
 	}
+	if len(u.ApplyGateNames()) > 0 {
+		return statusGated
+	}
 	if u.UnitStatus.SyncStatus == "OutOfSync" || u.UnitStatus.Drift == "Drifted" {
 		return "warn"
 	}
@@ -259,7 +352,7 @@ type TreeNode struct {
 	ID       string
 	Name     string
 	Type     string // "org", "space", "group", "unit", "target", "worker", "detail"
-	Status   string // "ok", "warn", "error", "pending", ""
+	Status   string // "ok", "warn", "error", "gated", "pending", ""
 	Info     string // Description text
 	Children []*TreeNode
 	Parent   *TreeNode
@@ -344,43 +437,50 @@ const (
 
 // Model represents the TUI state
 type Model struct {
-	nodes         []*TreeNode // Root nodes (organizations)
-	flatList      []*TreeNode // Flattened visible list for navigation
-	cursor        int         // Current selection
-	width         int
-	height        int
-	ready         bool
-	loading       bool
-	err           error
-	currentOrg    string // Current org ID (external ID)
-	currentOrgInt string // Current org internal ID
-	searchMode    bool
-	searchQuery   string
-	searchMatches []int              // Indices of matching nodes in flatList (direct matches only)
-	searchIndex   int                // Current position in searchMatches
-	filterActive  bool               // Whether filter mode is active (hides non-matching nodes)
-	matchCache    map[*TreeNode]bool // Cache of nodes that match or have matching descendants
-	keymap        keyMap
-	authPrompt    bool   // Show auth prompt
-	authOrgName   string // Org name to switch to
-	authOrgID     string // Org ID to switch to
-	statusMsg     string // Status message to display
+	nodes          []*TreeNode // Root nodes (organizations)
+	flatList       []*TreeNode // Flattened visible list for navigation
+	cursor         int         // Current selection
+	width          int
+	height         int
+	ready          bool
+	loading        bool
+	err            error
+	currentOrg     string // Current org ID (external ID)
+	currentOrgInt  string // Current org internal ID
+	searchMode     bool
+	searchQuery    string
+	searchMatches  []int                     // Indices of matching nodes in flatList (direct matches only)
+	searchIndex    int                       // Current position in searchMatches
+	filterActive   bool                      // Whether filter mode is active (hides non-matching nodes)
+	matchCache     map[*TreeNode]bool        // Cache of nodes that match or have matching descendants
+	matchQuery     string                    // Lowercased query matchCache was computed for
+	searchText     map[*TreeNode]string      // Lowercased name and info per node (search index)
+	spaceLoads     map[string]spaceLoadState // Background load state per space slug
+	treeOffset     int                       // First flatList row shown in the tree pane
+	keymap         keyMap
+	authPrompt     bool   // Show auth prompt
+	orgLoginPrompt bool   // No context for authOrgID: offer `cub auth login`
+	authOrgName    string // Org name to switch to
+	authOrgID      string // Org ID to switch to
+	statusMsg      string // Status message to display
 
 	// Import wizard state
-	importMode       bool
-	importStep       int
-	importNamespaces []namespaceInfo
-	importShowAllNS  bool // Show all namespaces including system/empty
-	importNamespace  string
-	importSpace      string
-	importWorkloads  []WorkloadInfo
-	importSelected   []bool
-	importCursor     int
-	importLoading    bool
-	importError      error
-	importApplyError error // Non-nil if apply failed during import (unit created but no livedata)
-	importProgress   int
-	importTotal      int
+	importMode              bool
+	importStep              int
+	importNamespaces        []namespaceInfo
+	importNamespaceWarnings []string // Workload kinds left out of the counts
+	importShowAllNS         bool     // Show all namespaces including system/empty
+	importNamespace         string
+	importSpace             string
+	importWorkloads         []WorkloadInfo
+	importSelected          []bool
+	importCursor            int
+	importLoading           bool
+	importError             error
+	importApplyError        error // Non-nil if apply failed during import (unit created but no livedata)
+	importLabelError        error // Non-nil if a workload couldn't be labeled with its unit
+	importProgress          int
+	importTotal             int
 
 	// Config extraction state
 	importExtractDone    bool // Whether extraction has completed
@@ -407,6 +507,8 @@ type Model struct {
 	// ArgoCD import state
 	importSource        int               // importSourceKubernetes or importSourceArgoCD
 	importArgoApps      []argoAppInfo     // list of ArgoCD Applications
+	importArgoAllApps   []argoAppInfo     // every loaded Application, for opening App of Apps
+	importArgoPath      []argoAppInfo     // App of Apps opened so far, outermost first
 	importSelectedArgo  *argoAppInfo      // selected ArgoCD Application
 	importArgoResources []ManagedResource // resources managed by selected ArgoCD app
 	importArgoCleanup   int               // cleanup choice: argoCleanupDisableSync, argoCleanupDeleteApp, argoCleanupKeepAsIs
@@ -465,6 +567,34 @@ type Model struct {
 	// Help overlay mode (? to open)
 	helpMode bool // Help overlay active
 
+	// Fuzzy jump overlay (ctrl+p to open)
+	jumpMode    bool        // Jump overlay active
+	jumpQuery   string      // Text typed so far
+	jumpResults []*TreeNode // Best matches for jumpQuery
+	jumpCursor  int         // Selected result
+
+	// Error drawer (e to open)
+	errorDrawer  bool       // Error drawer active
+	errorLog     []tuiError // Recent background errors, newest first
+	errorCursor  int        // Selected error
+	errorsUnseen int        // Errors recorded since the drawer was last opened
+
+	// Workers panel (W to open)
+	workerPanel  bool               // Workers panel active
+	workerCursor int                // Selected worker
+	supervisors  *workerSupervisors // Workers relaunched from the panel
+
+	// Changesets panel (C to open)
+	changeSetPanel    bool        // Changesets panel active
+	changeSetCursor   int         // Selected changeset
+	changeSetDrill    bool        // Selected changeset shows its targets and units
+	changeSets        []ChangeSet // Changesets in the loaded spaces
+	changeSetsLoading bool        // Loading changesets
+	changeSetsErr     error       // Error listing changesets
+
+	// Other orgs read in guest mode, by org ID, with how they are read
+	guestOrgs map[string]guestAccess
+
 	// Activity view mode (a to open)
 	activityMode bool // Activity view active
 
@@ -493,13 +623,17 @@ type Model struct {
 	hubViewMode bool // Group spaces into Hub (platform) vs AppSpaces (apps)
 
 	// Cluster filter mode (a to toggle)
-	showAllUnits   bool   // false = filter to current cluster, true = show all units
-	currentCluster string // Cluster name extracted from context
-	contextName    string // Raw kubectl context name
+	showAllUnits   bool     // false = filter to current cluster, true = show all units
+	currentCluster string   // Canonical cluster name (registry, or extracted from context)
+	clusterTargets []string // ConfigHub targets registered for the cluster, if any
+	contextName    string   // Raw kubectl context name
 
 	// Pending snapshot for restoring expanded paths after data loads
 	pendingSnapshot *HubSnapshot
 
+	// Pending --focus location, applied once its space has loaded
+	pendingFocus *hubFocus
+
 	// Optimistic UI state - tracks pending CRUD operations for immediate feedback
 	pendingActions []PendingAction
 
@@ -507,6 +641,7 @@ type Model struct {
 	detailsPane    viewport.Model // Scrollable viewport for right pane
 	detailsContent string         // Current formatted content
 	detailsNode    *TreeNode      // Node being displayed (nil = org summary)
+	detailsRestore int            // Scroll offset to apply when restored details arrive
 	detailsLoading bool           // Async loading state
 	detailsError   error          // Error from last fetch attempt
 	detailsFocused bool           // Keyboard focus on details pane
@@ -551,6 +686,10 @@ type keyMap struct {
 	Panel        key.Binding
 	Suggest      key.Binding
 	HubView      key.Binding
+	Jump         key.Binding
+	Errors       key.Binding
+	Workers      key.Binding
+	ChangeSets   key.Binding
 }
 
 func defaultKeyMap() keyMap {
@@ -655,6 +794,22 @@ func defaultKeyMap() keyMap {
 			key.WithKeys("B"),
 			key.WithHelp("B", "hub/appspace view"),
 		),
+		Jump: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("ctrl+p", "jump to"),
+		),
+		Errors: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "errors"),
+		),
+		Workers: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "workers"),
+		),
+		ChangeSets: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "changesets"),
+		),
 	}
 }
 
@@ -672,8 +827,17 @@ type errMsg struct {
 }
 
 type authCompleteMsg struct {
-	success bool
-	orgID   string
+	success    bool
+	orgID      string
+	noContext  bool  // no cub context exists for the org
+	afterLogin bool  // result of the retry after `cub auth login`
+	err        error // why the switch failed, if not noContext
+}
+
+// orgLoginDoneMsg is sent when the `cub auth login` hand-off exits.
+type orgLoginDoneMsg struct {
+	orgID string
+	err   error
 }
 
 type spaceDataLoadedMsg struct {
@@ -681,6 +845,7 @@ type spaceDataLoadedMsg struct {
 	units     []CubUnitData
 	targets   []CubTargetData
 	workers   []CubWorkerData
+	health    map[string]hierarchysvc.TargetHealth // by target slug
 	err       error
 }
 
@@ -764,6 +929,8 @@ type argoAppInfo struct {
 	HealthStatus string
 	IsAppOfApps  bool
 	ChildApps    []string
+	Parent       string // App of Apps this app was opened from, if any
+	Root         string // top-level App of Apps of Parent
 }
 
 // workerStatus represents a ConfigHub worker's connection status
@@ -776,8 +943,9 @@ type workerStatus struct {
 
 // cmdCompleteMsg is sent when a command palette command finishes
 type cmdCompleteMsg struct {
-	output string
-	err    error
+	command string
+	output  string
+	err     error
 }
 
 // workersStatusMsg is sent when worker status is fetched
@@ -788,6 +956,7 @@ type workersStatusMsg struct {
 
 type namespacesLoadedMsg struct {
 	namespaces []namespaceInfo
+	warnings   []string // workload kinds that could not be listed
 	err        error
 }
 
@@ -832,6 +1001,7 @@ type importCompleteMsg struct {
 	success    int
 	failed     int
 	applyError error
+	labelError error // first workload that couldn't be labeled with its unit
 }
 
 type configExtractedMsg struct {
@@ -937,9 +1107,26 @@ type HubSnapshot struct {
 	MapsMode      bool      `json:"maps_mode"`
 	PanelMode     bool      `json:"panel_mode"`
 	ExpandedPaths []string  `json:"expanded_paths,omitempty"` // Paths of expanded nodes
+
+	// Added in 1.1: navigation context beyond the expanded tree
+	CursorPath    string `json:"cursor_path,omitempty"`    // Path of the node under the cursor (preferred over Cursor)
+	SearchQuery   string `json:"search_query,omitempty"`   // Last search/filter query
+	FilterActive  bool   `json:"filter_active,omitempty"`  // Whether non-matching nodes were hidden
+	DetailsPath   string `json:"details_path,omitempty"`   // Path of the node shown in the details pane
+	DetailsOffset int    `json:"details_offset,omitempty"` // Details pane scroll position
+	TreeOffset    int    `json:"tree_offset,omitempty"`    // First tree row in view
 }
 
-const hubSnapshotVersion = "1.0"
+const hubSnapshotVersion = "1.1"
+
+// nodePath returns a node's path from the root, e.g. "/org/space/Units/api",
+// the form ExpandedPaths, CursorPath and DetailsPath use.
+func nodePath(n *TreeNode) string {
+	if n == nil {
+		return ""
+	}
+	return nodePath(n.Parent) + "/" + n.Name
+}
 
 func getHubSnapshotPath() string {
 	home, _ := os.UserHomeDir()
@@ -986,6 +1173,16 @@ func saveHubSnapshot(m *Model) {
 		MapsMode:      m.mapsMode,
 		PanelMode:     m.panelMode,
 		ExpandedPaths: expandedPaths,
+		SearchQuery:   m.searchQuery,
+		FilterActive:  m.filterActive,
+		TreeOffset:    m.treeOffset,
+	}
+	if m.cursor >= 0 && m.cursor < len(m.flatList) {
+		snap.CursorPath = nodePath(m.flatList[m.cursor])
+	}
+	if m.detailsNode != nil {
+		snap.DetailsPath = nodePath(m.detailsNode)
+		snap.DetailsOffset = m.detailsPane.YOffset
 	}
 
 	path := getHubSnapshotPath()
@@ -1000,3 +1197,67 @@ func saveHubSnapshot(m *Model) {
 	}
 	_ = os.WriteFile(path, data, 0644)
 }
+
+// restoreSnapshotPositions moves the cursor to the snapshot's CursorPath and
+// reloads the details it showed. Nodes inside a space exist only once the
+// space has loaded, so the snapshot stays pending until the cursor node is
+// found; it is called again after each space load.
+func (m *Model) restoreSnapshotPositions() tea.Cmd {
+	snap := m.pendingSnapshot
+	if snap == nil {
+		return nil
+	}
+	if snap.CursorPath == "" {
+		m.pendingSnapshot = nil
+		return nil
+	}
+
+	var cmd tea.Cmd
+	found := false
+	for i, n := range m.flatList {
+		path := nodePath(n)
+		if path == snap.CursorPath {
+			m.cursor = i
+			m.treeOffset = snap.TreeOffset
+			found = true
+		}
+		if snap.DetailsPath != "" && path == snap.DetailsPath {
+			m.detailsLoading = true
+			m.detailsNode = n
+			m.detailsRestore = snap.DetailsOffset
+			cmd = loadEntityDetailsCmd(n)
+			snap.DetailsPath = "" // load once
+		}
+	}
+	if found {
+		m.pendingSnapshot = nil
+	}
+	return cmd
+}
+
+// saveHubSnapshotOnPanic saves the snapshot if the TUI is panicking, then
+// re-panics so bubbletea still restores the terminal and reports the crash.
+// Deferred in Update and View with the last good model.
+func saveHubSnapshotOnPanic(m *Model) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	func() {
+		defer func() { _ = recover() }() // a broken model must not mask the original panic
+		saveHubSnapshot(m)
+	}()
+	panic(r)
+}
+
+// saveHubSnapshotAfterRun saves the final model's snapshot when the program
+// ended abnormally (killed, interrupted or panicked), where no quit key ran
+// the usual save.
+func saveHubSnapshotAfterRun(final tea.Model, err error) {
+	if err == nil {
+		return
+	}
+	if m, ok := final.(Model); ok {
+		saveHubSnapshot(&m)
+	}
+}
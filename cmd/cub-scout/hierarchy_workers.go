@@ -0,0 +1,405 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Supervision of workers relaunched from the workers panel (W). A worker
+// that exits is restarted with exponential backoff; one that keeps failing
+// is given up on after workerRestartLimit attempts. A run that lasted
+// longer than the maximum backoff counts as healthy and resets the count.
+const (
+	workerRestartLimit = 5
+	workerBackoffMax   = 30 * time.Second
+)
+
+// workerStderrTail is how much of a worker's stderr is kept to explain its
+// exit; a worker that runs for days would otherwise buffer all its logging.
+const workerStderrTail = 4 << 10
+
+// workerRestartBackoff is the delay before the first restart; tests shorten it.
+var workerRestartBackoff = time.Second
+
+// Supervised worker states.
+const (
+	workerRunning    = "running"
+	workerRestarting = "restarting"
+	workerStopped    = "stopped"
+	workerGaveUp     = "gave up"
+)
+
+// supervisedWorker runs `cub worker run` and restarts it when it exits.
+type supervisedWorker struct {
+	mu       sync.Mutex
+	state    string
+	restarts int
+	lastErr  string
+	since    time.Time
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// superviseWorker starts newCmd under supervision. newCmd is called for
+// every (re)start and must return a fresh, unstarted command.
+func superviseWorker(newCmd func() *exec.Cmd) *supervisedWorker {
+	w := &supervisedWorker{state: workerRunning, since: time.Now(), stop: make(chan struct{})}
+	go w.run(newCmd)
+	return w
+}
+
+func (w *supervisedWorker) run(newCmd func() *exec.Cmd) {
+	attempt := 0
+	for {
+		stderr := &tailBuffer{max: workerStderrTail}
+		cmd := newCmd()
+		cmd.Stdout = nil
+		cmd.Stderr = stderr
+		started := time.Now()
+		w.set(func() { w.state, w.since = workerRunning, started })
+
+		err := cmd.Start()
+		if err == nil {
+			done := make(chan error, 1)
+			go func() { done <- cmd.Wait() }()
+			select {
+			case err = <-done:
+			case <-w.stop:
+				_ = cmd.Process.Kill()
+				<-done
+				w.set(func() { w.state, w.since = workerStopped, time.Now() })
+				return
+			}
+		}
+
+		reason := lastLine(stderr.String())
+		if reason == "" && err != nil {
+			reason = err.Error()
+		}
+		if reason == "" {
+			reason = "exited"
+		}
+		if time.Since(started) > workerBackoffMax {
+			attempt = 0
+		}
+		if attempt >= workerRestartLimit {
+			w.set(func() { w.state, w.lastErr, w.since = workerGaveUp, reason, time.Now() })
+			return
+		}
+		delay := workerRestartBackoff << attempt
+		if delay > workerBackoffMax {
+			delay = workerBackoffMax
+		}
+		attempt++
+		w.set(func() { w.state, w.lastErr, w.since = workerRestarting, reason, time.Now(); w.restarts++ })
+
+		select {
+		case <-time.After(delay):
+		case <-w.stop:
+			w.set(func() { w.state, w.since = workerStopped, time.Now() })
+			return
+		}
+	}
+}
+
+// tailBuffer is a writer that keeps only the last max bytes written to it.
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) > t.max {
+		p = p[len(p)-t.max:]
+	}
+	if over := len(t.buf) + len(p) - t.max; over > 0 {
+		t.buf = t.buf[:copy(t.buf, t.buf[over:])]
+	}
+	t.buf = append(t.buf, p...)
+	return n, nil
+}
+
+func (t *tailBuffer) String() string { return string(t.buf) }
+
+func (w *supervisedWorker) set(fn func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fn()
+}
+
+// Stop kills the worker process and ends supervision.
+func (w *supervisedWorker) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+// status returns a one-line summary for the panel.
+func (w *supervisedWorker) status() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s := fmt.Sprintf("supervised: %s %s", w.state, formatDuration(time.Since(w.since)))
+	if w.restarts > 0 {
+		s += fmt.Sprintf(", %d restart(s)", w.restarts)
+	}
+	if w.lastErr != "" {
+		s += ", last exit: " + w.lastErr
+	}
+	return s
+}
+
+func (w *supervisedWorker) active() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state == workerRunning || w.state == workerRestarting
+}
+
+// workerSupervisors holds the workers relaunched this session, by
+// space/slug. Model is copied on every update, so it keeps a pointer.
+type workerSupervisors struct {
+	mu     sync.Mutex
+	byName map[string]*supervisedWorker
+}
+
+func (s *workerSupervisors) get(name string) *supervisedWorker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.byName[name]
+}
+
+func (s *workerSupervisors) put(name string, w *supervisedWorker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.byName == nil {
+		s.byName = make(map[string]*supervisedWorker)
+	}
+	s.byName[name] = w
+}
+
+// workerRow is one worker in the panel.
+type workerRow struct {
+	Space     string
+	Slug      string
+	Condition string
+	Version   string
+	LastSeen  time.Time // zero if the worker never reported
+}
+
+func (r workerRow) key() string { return r.Space + "/" + r.Slug }
+
+// runCommand is the command that starts this worker by hand.
+func (r workerRow) runCommand() string {
+	return fmt.Sprintf("cub worker run %s --space %s", r.Slug, r.Space)
+}
+
+// workerRows lists every loaded worker, not-Ready ones first.
+func (m *Model) workerRows() []workerRow {
+	var rows []workerRow
+	for _, org := range m.nodes {
+		for _, space := range org.Children {
+			if space.Type != "space" {
+				continue
+			}
+			for _, group := range space.Children {
+				for _, node := range group.Children {
+					if node.Type != "worker" {
+						continue
+					}
+					row := workerRow{Space: space.Name, Slug: node.Name, Condition: node.Info}
+					if data, ok := node.Data.(CubWorkerData); ok {
+						row.Version = data.BridgeWorker.Version
+						if t, err := time.Parse(time.RFC3339, data.BridgeWorker.LastSeenAt); err == nil {
+							row.LastSeen = t
+						}
+					}
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if ri, rj := rows[i].Condition == "Ready", rows[j].Condition == "Ready"; ri != rj {
+			return rj
+		}
+		return rows[i].key() < rows[j].key()
+	})
+	return rows
+}
+
+// workerPanelTickMsg refreshes last-seen ages and supervisor state while
+// the panel is open.
+type workerPanelTickMsg struct{}
+
+func workerPanelTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return workerPanelTickMsg{} })
+}
+
+// openWorkerPanel opens the workers panel on the first worker.
+func (m *Model) openWorkerPanel() tea.Cmd {
+	m.workerPanel = true
+	m.workerCursor = 0
+	if m.supervisors == nil {
+		m.supervisors = &workerSupervisors{}
+	}
+	return workerPanelTick()
+}
+
+// updateWorkerPanel handles keys while the workers panel is open: ↑/↓ pick
+// a worker, y copies its run command, R relaunches it supervised, s stops
+// supervision, r reloads worker status, Esc or W closes.
+func (m *Model) updateWorkerPanel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.workerRows()
+	var row *workerRow
+	if m.workerCursor < len(rows) {
+		row = &rows[m.workerCursor]
+	}
+	switch msg.String() {
+	case "esc", "W", "q":
+		m.workerPanel = false
+	case "up", "k":
+		if m.workerCursor > 0 {
+			m.workerCursor--
+		}
+	case "down", "j":
+		if m.workerCursor < len(rows)-1 {
+			m.workerCursor++
+		}
+	case "y":
+		if row == nil {
+			return m, nil
+		}
+		if err := copyToClipboard(row.runCommand()); err != nil {
+			m.statusMsg = "Copy failed: " + err.Error()
+		} else {
+			m.statusMsg = "Copied: " + row.runCommand()
+		}
+	case "R":
+		if row == nil {
+			return m, nil
+		}
+		if w := m.supervisors.get(row.key()); w != nil && w.active() {
+			m.statusMsg = row.Slug + " is already supervised"
+			return m, nil
+		}
+		space, slug := row.Space, row.Slug
+		m.supervisors.put(row.key(), superviseWorker(func() *exec.Cmd {
+			return exec.Command("cub", "worker", "run", slug, "--space", space)
+		}))
+		m.statusMsg = "Relaunched " + slug + " under supervision"
+	case "s":
+		if row == nil {
+			return m, nil
+		}
+		if w := m.supervisors.get(row.key()); w != nil && w.active() {
+			w.Stop()
+			m.statusMsg = "Stopped " + row.Slug
+		}
+	case "r":
+		var cmds []tea.Cmd
+		seen := make(map[string]bool)
+		for _, r := range rows {
+			if seen[r.Space] {
+				continue
+			}
+			seen[r.Space] = true
+			delete(m.spaceLoads, r.Space)
+			cmds = append(cmds, m.requestSpaceLoad(r.Space))
+		}
+		m.statusMsg = "Refreshing workers..."
+		return m, tea.Batch(cmds...)
+	}
+	return m, nil
+}
+
+func (m Model) renderWorkerPanel() string {
+	var b strings.Builder
+
+	b.WriteString(headerStyle.Render(" WORKERS "))
+	b.WriteString("\n\n")
+
+	rows := m.workerRows()
+	if len(rows) == 0 {
+		b.WriteString(dimStyle.Render("No workers loaded. Expand a space to load its workers."))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("Esc close"))
+		return b.String()
+	}
+
+	now := time.Now()
+	for i, r := range rows {
+		cursor := "  "
+		name := r.key()
+		if i == m.workerCursor {
+			cursor = activeStyle.Render("> ")
+			name = activeStyle.Render(name)
+		}
+		icon := statusOK.Render("●")
+		if r.Condition != "Ready" {
+			icon = statusErr.Render("○")
+		}
+		seen := "never seen"
+		if !r.LastSeen.IsZero() {
+			seen = "last seen " + formatDuration(now.Sub(r.LastSeen)) + " ago"
+		}
+		version := orDash(r.Version)
+		b.WriteString(fmt.Sprintf("%s%s %-32s %-14s %-20s %s", cursor, icon, name, r.Condition, dimStyle.Render(seen), dimStyle.Render(version)))
+		b.WriteString("\n")
+		if i != m.workerCursor {
+			continue
+		}
+		b.WriteString("    " + dimStyle.Render("run: ") + r.runCommand())
+		b.WriteString("\n")
+		if m.supervisors != nil {
+			if w := m.supervisors.get(r.key()); w != nil {
+				b.WriteString("    " + dimStyle.Render(w.status()))
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	if m.statusMsg != "" {
+		b.WriteString(dimStyle.Render(m.statusMsg))
+		b.WriteString("\n")
+	}
+	b.WriteString(helpStyle.Render("↑↓ select  y copy run command  R relaunch supervised  s stop  r refresh  Esc close"))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("Supervision ends when you quit; running workers keep running."))
+	return b.String()
+}
+
+// copyToClipboard copies text with the platform clipboard tool, falling
+// back to an OSC 52 escape that asks the terminal to do it (works over SSH).
+func copyToClipboard(text string) error {
+	for _, tool := range [][]string{
+		{"pbcopy"},
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	} {
+		if _, err := exec.LookPath(tool[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(tool[0], tool[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	_, err := fmt.Fprintf(os.Stdout, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,116 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func workerTestModel() Model {
+	m := jumpTestModel()
+	prod := m.nodes[0].Children[0]
+	group := &TreeNode{ID: "prod/workers", Name: "Workers", Type: "group", Parent: prod}
+	var ready, down CubWorkerData
+	ready.BridgeWorker.Slug, ready.BridgeWorker.Condition, ready.BridgeWorker.Version = "alpha", "Ready", "v0.9.0"
+	ready.BridgeWorker.LastSeenAt = time.Now().Add(-time.Minute).Format(time.RFC3339)
+	down.BridgeWorker.Slug, down.BridgeWorker.Condition = "beta", "Disconnected"
+	group.Children = []*TreeNode{
+		{ID: "alpha", Name: "alpha", Type: "worker", Info: "Ready", Parent: group, Data: ready},
+		{ID: "beta", Name: "beta", Type: "worker", Info: "Disconnected", Parent: group, Data: down},
+	}
+	prod.Children = append(prod.Children, group)
+	m.rebuildFlatList()
+	return m
+}
+
+func TestWorkerPanel(t *testing.T) {
+	m := workerTestModel()
+
+	rows := m.workerRows()
+	if len(rows) != 2 || rows[0].Slug != "beta" || rows[1].Version != "v0.9.0" || rows[1].LastSeen.IsZero() {
+		t.Fatalf("workerRows = %+v, want disconnected beta first, then alpha with version and last seen", rows)
+	}
+	if got := rows[0].runCommand(); got != "cub worker run beta --space prod" {
+		t.Errorf("runCommand = %q", got)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("W")})
+	if !m.workerPanel {
+		t.Fatal("W should open the workers panel")
+	}
+	view := m.View()
+	for _, want := range []string{"prod/beta", "never seen", "cub worker run beta --space prod", "last seen 1m ago", "v0.9.0"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel should show %q:\n%s", want, view)
+		}
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.workerPanel {
+		t.Error("Esc should close the workers panel")
+	}
+}
+
+func TestSupervisedWorkerRestartsThenGivesUp(t *testing.T) {
+	defer func(d time.Duration) { workerRestartBackoff = d }(workerRestartBackoff)
+	workerRestartBackoff = time.Millisecond
+
+	starts := 0
+	w := superviseWorker(func() *exec.Cmd {
+		starts++
+		return exec.Command("sh", "-c", "echo 'worker: connection refused' >&2; exit 1")
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for w.active() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	status := w.status()
+	if !strings.Contains(status, workerGaveUp) || !strings.Contains(status, "connection refused") {
+		t.Errorf("status = %q, want gave up with the last stderr line", status)
+	}
+	if starts != workerRestartLimit+1 {
+		t.Errorf("started %d times, want %d", starts, workerRestartLimit+1)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	tail := &tailBuffer{max: 8}
+	for _, chunk := range []string{"abc", "defgh", "ij", strings.Repeat("x", 20) + "last"} {
+		if n, err := tail.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+		}
+		if len(tail.buf) > tail.max {
+			t.Fatalf("kept %d bytes, want at most %d", len(tail.buf), tail.max)
+		}
+	}
+	if got := tail.String(); got != "xxxxlast" {
+		t.Errorf("tail = %q, want the last 8 bytes", got)
+	}
+
+	tail = &tailBuffer{max: 8}
+	tail.Write([]byte("abc"))
+	tail.Write([]byte("defghij"))
+	if got := tail.String(); got != "cdefghij" {
+		t.Errorf("tail = %q, want cdefghij", got)
+	}
+}
+
+func TestSupervisedWorkerStop(t *testing.T) {
+	w := superviseWorker(func() *exec.Cmd { return exec.Command("sleep", "30") })
+	time.Sleep(50 * time.Millisecond)
+	w.Stop()
+	deadline := time.Now().Add(5 * time.Second)
+	for w.active() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !strings.Contains(w.status(), workerStopped) {
+		t.Errorf("status = %q, want stopped", w.status())
+	}
+}
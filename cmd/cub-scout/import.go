@@ -16,10 +16,16 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/confighub/cub-scout/pkg/agent"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -50,6 +56,7 @@ type WorkloadInfo struct {
 	Replicas    int32
 	Labels      map[string]string
 	Annotations map[string]string
+	Cluster     string // kube context, set when scanning several clusters
 
 	// GitOps migration fields
 	GitOpsRef         *GitOpsReference
@@ -88,16 +95,20 @@ type WorkloadJSON struct {
 
 // SuggestionJSON is the JSON representation of the import suggestion
 type SuggestionJSON struct {
-	AppSpace string     `json:"appSpace"`
-	Units    []UnitJSON `json:"units"`
+	AppSpace string     `json:"appSpace" yaml:"appSpace"`
+	Units    []UnitJSON `json:"units" yaml:"units"`
+	Bases    []UnitJSON `json:"bases,omitempty" yaml:"bases,omitempty"` // upstream units multi-variant apps clone from
 }
 
 // UnitJSON is the JSON representation of a suggested unit
 type UnitJSON struct {
-	Slug      string   `json:"slug"`
-	App       string   `json:"app"`
-	Variant   string   `json:"variant"`
-	Workloads []string `json:"workloads"`
+	Slug       string   `json:"slug" yaml:"slug"`
+	App        string   `json:"app" yaml:"app"`
+	Variant    string   `json:"variant" yaml:"variant"`
+	Workloads  []string `json:"workloads" yaml:"workloads"`
+	Upstream   string   `json:"upstream,omitempty" yaml:"upstream,omitempty"`
+	Confidence float64  `json:"confidence" yaml:"confidence"`
+	Rationale  []string `json:"rationale,omitempty" yaml:"rationale,omitempty"`
 }
 
 var importCmd = &cobra.Command{
@@ -136,6 +147,7 @@ Examples:
 
 func init() {
 	importCmd.Flags().StringVarP(&importNamespace, "namespace", "n", "", "Namespace to import (discovers all if not specified)")
+	_ = importCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
 	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Preview without making changes")
 	importCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "Skip confirmation")
 	importCmd.Flags().BoolVar(&importJSON, "json", false, "Output as JSON (for GUI/scripting)")
@@ -271,17 +283,22 @@ func runImport(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// Without cub the proposal can be shown but not imported
+	if !cubInstalled() {
+		return cubMissingError("importing into ConfigHub")
+	}
+
 	// Step 4: Confirm
-	if !importYes {
-		fmt.Printf("\nImport %d units into App Space '%s'? [y/N] ", len(proposal.Units), proposal.AppSpace)
-		if !confirm() {
-			if logger != nil {
-				logger.Log("User aborted import")
-				logger.LogResult(0, 0, nil)
-			}
-			fmt.Println("Aborted.")
-			return nil
+	changes := []plannedChange{{Action: "create space", Target: proposal.AppSpace, Detail: "if missing"}}
+	for _, unit := range proposal.Units {
+		changes = append(changes, plannedChange{Action: "create unit", Target: proposal.AppSpace + "/" + unit.Slug, Detail: strings.Join(unit.Workloads, ", ")})
+	}
+	if ok, err := confirmChanges(changes, importYes); err != nil || !ok {
+		if err == nil && logger != nil {
+			logger.Log("User aborted import")
+			logger.LogResult(0, 0, nil)
 		}
+		return err
 	}
 
 	// Step 5: Apply
@@ -352,19 +369,6 @@ func discoverNamespacesWithWorkloads() ([]string, error) {
 	return namespaces, nil
 }
 
-// isSystemNamespace returns true for namespaces we should skip by default
-func isSystemNamespace(ns string) bool {
-	systemNamespaces := map[string]bool{
-		"kube-system":        true,
-		"kube-public":        true,
-		"kube-node-lease":    true,
-		"local-path-storage": true,
-		"flux-system":        true, // Flux controllers
-		"argocd":             true, // ArgoCD controllers
-	}
-	return systemNamespaces[ns]
-}
-
 func discoverWorkloads(namespace string) ([]WorkloadInfo, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	configOverrides := &clientcmd.ConfigOverrides{}
@@ -380,12 +384,18 @@ func discoverWorkloads(namespace string) ([]WorkloadInfo, error) {
 		return nil, fmt.Errorf("create client: %w", err)
 	}
 
-	dynClient, err := dynamic.NewForConfig(config)
-	if err != nil {
-		dynClient = nil // Non-fatal
+	var dynClient dynamic.Interface // Non-fatal if it can't be created
+	if dc, err := dynamic.NewForConfig(config); err == nil {
+		dynClient = dc
 	}
 
-	ctx := context.Background()
+	return listNamespaceWorkloads(context.Background(), clientset, dynClient, namespace)
+}
+
+// listNamespaceWorkloads lists the Deployments, StatefulSets and DaemonSets
+// in namespace. dynClient may be nil, in which case GitOps source paths are
+// not resolved.
+func listNamespaceWorkloads(ctx context.Context, clientset kubernetes.Interface, dynClient dynamic.Interface, namespace string) ([]WorkloadInfo, error) {
 	var workloads []WorkloadInfo
 
 	// Deployments
@@ -525,48 +535,14 @@ func detectOwnerAndRef(labels, annotations map[string]string) (string, *GitOpsRe
 		return "Flux", &GitOpsReference{Kind: "HelmRelease", Name: name, Namespace: ns}
 	}
 
-	// Argo CD
-	if instance, ok := labels["argocd.argoproj.io/instance"]; ok {
-		return "ArgoCD", &GitOpsReference{Kind: "Application", Name: instance, Namespace: "argocd"}
-	}
-	if trackingID, ok := annotations["argocd.argoproj.io/tracking-id"]; ok {
-		// ArgoCD tracking-id formats:
-		// 1. <app-name>:<group>/<kind>:<resource-ns>/<resource-name>
-		//    Example: example.guestbook:apps/Deployment:guestbook/guestbook-ui
-		// 2. <app-ns>.<app-name>:<group>/<kind>:<resource-ns>/<resource-name>
-		//    Example: argocd.my-app:apps/Deployment:default/nginx
-		//
-		// The first segment before ":" is the app identifier (possibly with namespace prefix)
-		// The second segment contains "/" (group/kind), which distinguishes it from app name
-		if parts := strings.SplitN(trackingID, ":", 4); len(parts) >= 2 {
-			appIdentifier := parts[0]
-			// If parts[1] contains "/", it's the group/kind, meaning parts[0] is the full app identifier
-			if strings.Contains(parts[1], "/") {
-				// parts[0] is the app identifier (possibly namespace.name or just name)
-				// Try to extract namespace and name from "namespace.name" format
-				appNs := "argocd" // default namespace
-				appName := appIdentifier
-				if dotIdx := strings.Index(appIdentifier, "."); dotIdx > 0 {
-					// Could be namespace.name format, but could also just be app name with dots
-					// ArgoCD app names can contain dots, so we take the whole thing as name
-					// and default to argocd namespace unless we can find the Application CR
-					appName = appIdentifier
-				}
-				if appName != "" {
-					return "ArgoCD", &GitOpsReference{Kind: "Application", Name: appName, Namespace: appNs}
-				}
-			} else {
-				// Old format: parts[1] is the app name
-				appNs := parts[0]
-				appName := parts[1]
-				if appNs == "" {
-					appNs = "argocd"
-				}
-				if appName != "" {
-					return "ArgoCD", &GitOpsReference{Kind: "Application", Name: appName, Namespace: appNs}
-				}
-			}
+	// Argo CD - tracking-id annotation or instance label, per the tracking method
+	if appNs, appName, _ := agent.ArgoApplication(labels, annotations); appName != "" {
+		if appNs == "" {
+			appNs = "argocd"
 		}
+		return "ArgoCD", &GitOpsReference{Kind: "Application", Name: appName, Namespace: appNs}
+	}
+	if _, ok := annotations[agent.ArgoTrackingIDAnnotation]; ok {
 		return "ArgoCD", nil
 	}
 
@@ -797,6 +773,8 @@ func applyImportWithLogger(proposal *FullProposal, workloads []WorkloadInfo, log
 	fmt.Println()
 	fmt.Println("View your units:")
 	fmt.Printf("  cub unit list --space %s\n", proposal.AppSpace)
+	fmt.Println("Once applied, verify the import:")
+	fmt.Printf("  cub-scout verify import %s\n", proposal.AppSpace)
 
 	// Ask to start worker and set targets (skip if -y was used)
 	if !importYes {
@@ -811,11 +789,14 @@ func applyImportWithLogger(proposal *FullProposal, workloads []WorkloadInfo, log
 }
 
 func fetchManifest(kind, namespace, name string) ([]byte, error) {
-	cmd := exec.Command("kubectl", "get", strings.ToLower(kind), name, "-n", namespace, "-o", "yaml")
-	output, err := cmd.Output()
+	obj, err := getWorkload(context.Background(), kind, namespace, name)
 	if err != nil {
 		return nil, err
 	}
+	output, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s/%s: %w", kind, name, err)
+	}
 
 	// Strip server-side fields that interfere with kubectl apply's three-way merge
 	// These fields are set by Kubernetes and shouldn't be in source YAML
@@ -908,13 +889,7 @@ func createUnitWithManifestSimple(space, slug string, labels []string, manifest
 }
 
 func confirm() bool {
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
-	if err != nil {
-		return false
-	}
-	response = strings.ToLower(strings.TrimSpace(response))
-	return response == "y" || response == "yes"
+	return readConfirm(os.Stdin)
 }
 
 // confirmDefault returns the default if user just presses enter
@@ -938,13 +913,8 @@ func startWorkerAndSetTargets(proposal *FullProposal, logger *ImportLogger) erro
 		logger.Log("Space: %s", proposal.AppSpace)
 	}
 
-	// Get current kubectl context for target matching
-	ctxCmd := exec.Command("kubectl", "config", "current-context")
-	ctxOut, err := ctxCmd.Output()
-	if err != nil {
-		return fmt.Errorf("get kubectl context: %w", err)
-	}
-	kubeContext := strings.TrimSpace(string(ctxOut))
+	// Get current kubeconfig context for target matching
+	kubeContext := getCurrentContext()
 
 	fmt.Printf("Starting worker for space '%s'...\n", proposal.AppSpace)
 
@@ -973,8 +943,7 @@ func startWorkerAndSetTargets(proposal *FullProposal, logger *ImportLogger) erro
 		fmt.Print(".")
 
 		// Check if target exists
-		checkCmd := exec.Command("cub", "target", "list", "--space", proposal.AppSpace, "-o", "json")
-		out, err := checkCmd.Output()
+		out, err := refreshCubCommand("target", "list", "--space", proposal.AppSpace, "-o", "json")
 		if err != nil {
 			continue
 		}
@@ -1128,20 +1097,62 @@ func createUnit(space, unitSlug string) error {
 
 // labelWorkload applies a ConfigHub label to a workload
 func labelWorkload(kind, namespace, name, unitSlug string) error {
-	resource := strings.ToLower(kind)
-	label := fmt.Sprintf("confighub.com/UnitSlug=%s", unitSlug)
-
-	cmd := exec.Command("kubectl", "label", resource, name,
-		"-n", namespace, label, "--overwrite")
-	output, err := cmd.CombinedOutput()
+	if err := checkWrite("label " + kind + "/" + name); err != nil {
+		return err
+	}
+	gvr := kindToGVR(kind)
+	if gvr.Resource == "" {
+		return fmt.Errorf("unsupported workload kind %s", kind)
+	}
+	dynClient, err := kubeClient()
 	if err != nil {
-		return fmt.Errorf("%s", string(output))
+		return err
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]string{"confighub.com/UnitSlug": unitSlug}},
+	})
+	if _, err := dynClient.Resource(gvr).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{FieldManager: "cub-scout"}); err != nil {
+		return fmt.Errorf("label %s/%s/%s: %w", kind, namespace, name, err)
 	}
 	return nil
 }
 
-// checkCubAuth verifies the cub CLI is authenticated
-func checkCubAuth() error {
+// kubeClient returns a dynamic client for the current kubeconfig context.
+func kubeClient() (dynamic.Interface, error) {
+	cfg, err := buildConfig()
+	if err != nil {
+		return nil, fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create dynamic client: %w", err)
+	}
+	return dynClient, nil
+}
+
+// getWorkload reads one workload from the cluster.
+func getWorkload(ctx context.Context, kind, namespace, name string) (*unstructured.Unstructured, error) {
+	gvr := kindToGVR(kind)
+	if gvr.Resource == "" {
+		return nil, fmt.Errorf("unsupported workload kind %s", kind)
+	}
+	dynClient, err := kubeClient()
+	if err != nil {
+		return nil, err
+	}
+	obj, err := dynClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get %s/%s/%s: %w", kind, namespace, name, err)
+	}
+	return obj, nil
+}
+
+// checkCubAuth verifies the cub CLI is installed and authenticated for
+// what, the command that needs it.
+func checkCubAuth(what string) error {
+	if !cubInstalled() {
+		return cubMissingError(what)
+	}
 	cmd := exec.Command("cub", "auth", "status", "--quiet")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -1152,10 +1163,9 @@ func checkCubAuth() error {
 
 // getCurrentSpace returns the currently selected ConfigHub space
 func getCurrentSpace() (string, error) {
-	cmd := exec.Command("cub", "context", "get", "--json", "--quiet")
-	output, err := cmd.CombinedOutput()
+	output, err := runCubCommand("context", "get", "--json", "--quiet")
 	if err != nil {
-		return "", fmt.Errorf("failed to get current context: %s", string(output))
+		return "", fmt.Errorf("failed to get current context: %s", cubStderr(err))
 	}
 
 	// Parse JSON output to get space
@@ -1194,3 +1204,61 @@ func ensureSpace(space string) error {
 	}
 	return nil
 }
+
+// importMarkerPatch removes the ownership markers a previous import or
+// apply left on a workload, so re-importing it doesn't hit ownership
+// conflicts.
+func importMarkerPatch() []byte {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{"config.k8s.io/owning-inventory": nil},
+			"labels": map[string]interface{}{
+				"confighub.com/UnitSlug":             nil,
+				"cli-utils.sigs.k8s.io/inventory-id": nil,
+			},
+		},
+	})
+	return patch
+}
+
+// removeImportMarkers strips import markers from each workload and returns
+// the failures. Workloads that no longer exist are skipped.
+func removeImportMarkers(ctx context.Context, dynClient dynamic.Interface, workloads []WorkloadInfo) []error {
+	var errs []error
+	for _, w := range workloads {
+		var err error
+		if gvr := kindToGVR(w.Kind); gvr.Resource == "" {
+			err = fmt.Errorf("unsupported workload kind %s", w.Kind)
+		} else {
+			_, err = dynClient.Resource(gvr).Namespace(w.Namespace).Patch(ctx, w.Name, types.MergePatchType, importMarkerPatch(), metav1.PatchOptions{FieldManager: "cub-scout"})
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("%s/%s/%s: %w", w.Kind, w.Namespace, w.Name, err))
+		}
+	}
+	return errs
+}
+
+// importUnitChanges lists importing workloads as unit in space: the unit
+// itself, then the confighub.com/UnitSlug label put on each workload.
+func importUnitChanges(space, unit string, workloads []WorkloadInfo) []plannedChange {
+	names := make([]string, 0, len(workloads))
+	for _, w := range workloads {
+		names = append(names, w.Namespace+"/"+w.Name)
+	}
+	changes := []plannedChange{{Action: "create unit", Target: space + "/" + unit, Detail: strings.Join(names, ", ")}}
+	for _, w := range workloads {
+		changes = append(changes, plannedChange{Action: "label", Target: w.Kind + " " + w.Namespace + "/" + w.Name, Detail: "confighub.com/UnitSlug=" + unit, Cluster: true})
+	}
+	return changes
+}
+
+// applyUnitChanges lists applying unit in space after an import: stripping
+// the markers of earlier imports from each workload, then the apply itself.
+func applyUnitChanges(space, unit string, workloads []WorkloadInfo) []plannedChange {
+	changes := make([]plannedChange, 0, len(workloads)+1)
+	for _, w := range workloads {
+		changes = append(changes, plannedChange{Action: "remove import markers", Target: w.Kind + " " + w.Namespace + "/" + w.Name, Detail: "confighub.com/UnitSlug and inventory labels", Cluster: true})
+	}
+	return append(changes, plannedChange{Action: "apply unit", Target: space + "/" + unit, Detail: "the target's worker applies it to the cluster", Cluster: true})
+}
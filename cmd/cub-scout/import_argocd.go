@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -20,6 +21,8 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/yaml"
+
+	"github.com/confighub/cub-scout/pkg/agent"
 )
 
 var (
@@ -35,6 +38,10 @@ var (
 	argoImportDeleteApp   bool // Delete Application after import
 	argoImportTestUpdate  bool // Test ConfigHub pipeline with annotation update
 	argoImportTestRollout bool // Test ConfigHub pipeline with rollout restart
+
+	argoImportRecursive     bool   // Import the children of an App of Apps
+	argoImportChildren      string // Comma-separated children to import (with --recursive)
+	argoImportSpacePerChild bool   // One space per child Application (with --recursive)
 )
 
 // ArgoApplication represents an ArgoCD Application CR
@@ -91,6 +98,7 @@ type ArgoStatusResource struct {
 	Name      string
 	Status    string // Sync status
 	Health    string
+	Hook      bool // Sync hook (Job, ...), not part of the desired state
 }
 
 var importArgoCmd = &cobra.Command{
@@ -101,14 +109,20 @@ var importArgoCmd = &cobra.Command{
 This command:
   1. Reads the ArgoCD Application to find its destination namespace
   2. Discovers all resources managed by the Application (Deployments, Services, etc.)
+     from its status.resources, fetching each live object from the cluster
   3. Creates a ConfigHub Unit containing the workload manifests
   4. Extracts labels from the Git path (e.g., overlays/prod → variant=prod)
 
 The ArgoCD Application CR itself is NOT imported as a Unit - it's Argo's
 orchestration mechanism. ConfigHub uses its own orchestration (Hub → Space → Unit).
 
-For App-of-Apps patterns, import the child Applications individually to get
-the actual workload resources.
+For App-of-Apps patterns, use --recursive to import the child Applications
+(including nested App of Apps) as one unit each. Each unit is labeled with
+argocd-parent and argocd-root so the App of Apps tree stays queryable.
+
+Neither the argocd CLI nor a port-forward is needed. Applications deploying
+to another cluster have no live objects here; --use-argocd-api reads them
+from the Argo CD API server instead (set ARGOCD_SERVER and ARGOCD_AUTH_TOKEN).
 
 Examples:
   # List available ArgoCD Applications
@@ -128,6 +142,15 @@ Examples:
 
   # Import and delete the ArgoCD Application (hand off to ConfigHub)
   cub-scout import-argocd guestbook --delete-app
+
+  # Import every child of an App of Apps, one unit each
+  cub-scout import-argocd platform-apps --recursive
+
+  # Import two children, each into its own space
+  cub-scout import-argocd platform-apps --recursive --children cart,checkout --space-per-child
+
+  # Import an Application deploying to another cluster
+  ARGOCD_SERVER=argocd.example.com ARGOCD_AUTH_TOKEN=... cub-scout import-argocd edge-api --use-argocd-api
 `,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runImportArgoCD,
@@ -136,6 +159,7 @@ Examples:
 func init() {
 	importArgoCmd.Flags().StringVar(&argoImportNamespace, "argocd-namespace", "argocd", "Namespace where ArgoCD is installed")
 	importArgoCmd.Flags().StringVar(&argoImportSpace, "space", "", "ConfigHub space to import into (auto-inferred if not specified)")
+	_ = importArgoCmd.RegisterFlagCompletionFunc("space", completeSpaces)
 	importArgoCmd.Flags().BoolVar(&argoImportDryRun, "dry-run", false, "Preview what would be imported without making changes")
 	importArgoCmd.Flags().BoolVar(&argoImportShowYAML, "show-yaml", false, "Show YAML content that would be imported (implies --dry-run)")
 	importArgoCmd.Flags().BoolVar(&argoImportRaw, "raw", false, "Keep raw YAML with all runtime fields (default: clean)")
@@ -145,6 +169,10 @@ func init() {
 	importArgoCmd.Flags().BoolVar(&argoImportDeleteApp, "delete-app", false, "Delete the ArgoCD Application after import (keeps resources)")
 	importArgoCmd.Flags().BoolVar(&argoImportTestUpdate, "test-update", false, "Test ConfigHub pipeline by adding an annotation to verify it can update resources")
 	importArgoCmd.Flags().BoolVar(&argoImportTestRollout, "test-rollout", false, "Test ConfigHub pipeline by triggering a rollout restart")
+	importArgoCmd.Flags().BoolVar(&argoUseAPI, "use-argocd-api", false, "Read resources the cluster can't serve (remote destinations) from the Argo CD API (ARGOCD_SERVER, ARGOCD_AUTH_TOKEN)")
+	importArgoCmd.Flags().BoolVarP(&argoImportRecursive, "recursive", "r", false, "Import the child Applications of an App of Apps, one unit each")
+	importArgoCmd.Flags().StringVar(&argoImportChildren, "children", "", "Children to import with --recursive: names or list numbers, comma-separated (default: prompt, or all with --yes)")
+	importArgoCmd.Flags().BoolVar(&argoImportSpacePerChild, "space-per-child", false, "With --recursive, import each child into a space named after it")
 
 	rootCmd.AddCommand(importArgoCmd)
 }
@@ -188,13 +216,29 @@ func runImportArgoCD(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--disable-sync and --delete-app are mutually exclusive")
 	}
 
+	if !argoImportRecursive && (argoImportChildren != "" || argoImportSpacePerChild) {
+		return fmt.Errorf("--children and --space-per-child require --recursive")
+	}
+	if argoImportRecursive {
+		if argoImportDisableSync || argoImportDeleteApp || argoImportTestUpdate || argoImportTestRollout {
+			return fmt.Errorf("--disable-sync, --delete-app, --test-update and --test-rollout work on a single Application; run them per child after a --recursive import")
+		}
+		if argoImportSpacePerChild && argoImportSpace != "" {
+			return fmt.Errorf("--space and --space-per-child are mutually exclusive")
+		}
+	}
+
 	// Check cub CLI is available and authenticated (only for actual import)
 	if !argoImportDryRun && !argoImportShowYAML {
-		if err := checkCubAuth(); err != nil {
+		if err := checkCubAuth(cmd.CommandPath()); err != nil {
 			return err
 		}
 	}
 
+	if argoImportRecursive {
+		return runImportArgoRecursive(ctx, clientset, dynamicClient, appName)
+	}
+
 	fmt.Printf("ConfigHub ArgoCD Import\n")
 	fmt.Printf("=======================\n\n")
 
@@ -240,7 +284,7 @@ func runImportArgoCD(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Println()
 		fmt.Printf("  Note: App of Apps manages Application CRs, not workload resources.\n")
-		fmt.Printf("  To import the actual workloads, import the child Applications individually.\n")
+		fmt.Printf("  To import the actual workloads, use --recursive or import the children individually.\n")
 	} else {
 		fmt.Printf("Step 3: Finding resources managed by '%s' in namespace '%s'...\n", appName, destNamespace)
 	}
@@ -248,10 +292,13 @@ func runImportArgoCD(cmd *cobra.Command, args []string) error {
 	// For non-App-of-Apps, get the actual managed workload resources
 	var managedResources []ManagedResource
 	if !appOfApps {
-		managedResources, err = getManagedResources(ctx, clientset, dynamicClient, appName, destNamespace)
+		managedResources, err = getManagedResources(ctx, clientset, dynamicClient, argoImportNamespace, appName, destNamespace)
 		if err != nil {
 			return fmt.Errorf("failed to get managed resources: %w", err)
 		}
+		if listed := len(importableStatusResources(statusResources)); len(managedResources) < listed && !argoUseAPI {
+			fmt.Printf("  ⚠ %d of %d resources Argo CD tracks could not be read from the cluster; --use-argocd-api reads them from Argo CD\n", listed-len(managedResources), listed)
+		}
 
 		if len(managedResources) == 0 {
 			fmt.Printf("  ⚠ No managed resources found in namespace %s\n", destNamespace)
@@ -305,7 +352,10 @@ func runImportArgoCD(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Type: App of Apps (manages %d child Applications)\n", len(childApps))
 		fmt.Println()
 		fmt.Printf("⚠ App of Apps detected - this Application manages other Applications,\n")
-		fmt.Printf("  not workload resources. Import the child Applications instead:\n")
+		fmt.Printf("  not workload resources. Import all children as one unit each:\n")
+		fmt.Printf("  → cub-scout import-argocd %s --recursive\n", appName)
+		fmt.Println()
+		fmt.Printf("  Or import the child Applications individually:\n")
 		for _, child := range childApps {
 			fmt.Printf("  → cub-scout import-argocd %s\n", child)
 		}
@@ -351,6 +401,8 @@ func runImportArgoCD(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
+	changes := argoImportChanges(space, argoImportNamespace, appName, len(managedResources))
+
 	// Show YAML content if requested (implies dry-run)
 	if argoImportShowYAML {
 		if argoImportRaw {
@@ -375,38 +427,22 @@ func runImportArgoCD(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Println()
 
-		// Show what cleanup action would happen
-		if argoImportDisableSync {
-			fmt.Printf("After import: Would disable auto-sync on ArgoCD Application '%s'\n", appName)
-		} else if argoImportDeleteApp {
-			fmt.Printf("After import: Would delete ArgoCD Application '%s' (resources preserved)\n", appName)
-		}
-
-		fmt.Println("Dry-run mode: no changes will be made.")
+		printPlannedChanges(os.Stdout, changes)
+		fmt.Println("\nDry-run mode: no changes will be made.")
 		fmt.Println("\nTo import, run without --dry-run and --show-yaml")
 		return nil
 	}
 
 	if argoImportDryRun {
-		// Show what cleanup action would happen
-		if argoImportDisableSync {
-			fmt.Printf("\nAfter import: Would disable auto-sync on ArgoCD Application '%s'\n", appName)
-		} else if argoImportDeleteApp {
-			fmt.Printf("\nAfter import: Would delete ArgoCD Application '%s' (resources preserved)\n", appName)
-		}
-
+		printPlannedChanges(os.Stdout, changes)
 		fmt.Println("\nDry-run mode: no changes will be made.")
 		fmt.Println("To import, run without --dry-run")
 		return nil
 	}
 
 	// Confirm
-	if !argoImportYes {
-		fmt.Printf("Proceed with import? [y/N] ")
-		if !confirm() {
-			fmt.Println("Aborted.")
-			return nil
-		}
+	if ok, err := confirmChanges(changes, argoImportYes); err != nil || !ok {
+		return err
 	}
 
 	// Create space if needed
@@ -420,32 +456,10 @@ func runImportArgoCD(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Creating unit: %s\n", appName)
 
 	// Combine all managed resources into a single YAML
-	var workloadYAML strings.Builder
-	for i, r := range managedResources {
-		if i > 0 {
-			workloadYAML.WriteString("---\n")
-		}
-		resourceYAML := r.YAML
-		if !argoImportRaw {
-			if cleaned, err := cleanResourceYAML(r.YAML); err == nil {
-				resourceYAML = cleaned
-			}
-		}
-		workloadYAML.WriteString(resourceYAML)
-		workloadYAML.WriteString("\n")
-	}
-
-	// Build labels string for unit creation
-	var labelParts []string
-	for k, v := range extractedLabels {
-		if k == "is_base" {
-			continue // Skip internal flag
-		}
-		labelParts = append(labelParts, fmt.Sprintf("%s=%s", k, v))
-	}
-	labelsArg := strings.Join(labelParts, ",")
+	workloadYAML := buildArgoUnitYAML(managedResources, argoImportRaw)
+	labelsArg := formatUnitLabels(extractedLabels)
 
-	if err := createUnitWithConfigAndLabels(space, appName, workloadYAML.String(), labelsArg); err != nil {
+	if err := createUnitWithConfigAndLabels(space, appName, workloadYAML, labelsArg); err != nil {
 		fmt.Printf("  ✗ Failed: %v\n", err)
 		return fmt.Errorf("failed to create unit: %w", err)
 	}
@@ -514,6 +528,28 @@ func runImportArgoCD(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// argoImportChanges lists what importing one Application will change,
+// following the cleanup and test flags.
+func argoImportChanges(space, namespace, appName string, resources int) []plannedChange {
+	app := "Application " + namespace + "/" + appName
+	unit := space + "/" + appName
+	changes := []plannedChange{
+		{Action: "create unit", Target: unit, Detail: fmt.Sprintf("%d resources; space created if missing", resources)},
+	}
+	if argoImportDisableSync {
+		changes = append(changes, plannedChange{Action: "disable auto-sync", Target: app, Detail: "spec.syncPolicy.automated removed", Cluster: true})
+	} else if argoImportDeleteApp {
+		changes = append(changes, plannedChange{Action: "delete", Target: app, Detail: "orphan propagation; managed resources are kept", Cluster: true})
+	}
+	if argoImportTestUpdate {
+		changes = append(changes, plannedChange{Action: "update and apply unit", Target: unit, Detail: "adds annotation confighub.com/test-update", Cluster: true})
+	}
+	if argoImportTestRollout {
+		changes = append(changes, plannedChange{Action: "update and apply unit", Target: unit, Detail: "sets kubectl.kubernetes.io/restartedAt on the pod template; pods restart", Cluster: true})
+	}
+	return changes
+}
+
 // getArgoApplication reads an ArgoCD Application CR and returns parsed info + raw YAML
 func getArgoApplication(ctx context.Context, client dynamic.Interface, namespace, name string) (*ArgoApplication, string, error) {
 	gvr := schema.GroupVersionResource{
@@ -624,6 +660,7 @@ func getStatusResources(ctx context.Context, client dynamic.Interface, namespace
 		if health, found, _ := unstructured.NestedMap(rMap, "health"); found {
 			res.Health, _, _ = unstructured.NestedString(health, "status")
 		}
+		res.Hook, _, _ = unstructured.NestedBool(rMap, "hook")
 
 		resources = append(resources, res)
 	}
@@ -705,22 +742,13 @@ func extractLabelsFromPath(path string) map[string]string {
 // isArgoManagedResource checks if a resource is managed by the given ArgoCD Application
 // ArgoCD uses either a label (argocd.argoproj.io/instance) or an annotation (argocd.argoproj.io/tracking-id)
 func isArgoManagedResource(labels, annotations map[string]string, appName string) bool {
-	// Check label first (some ArgoCD configs use this)
-	if instance, ok := labels["argocd.argoproj.io/instance"]; ok {
-		return instance == appName
-	}
-
-	// Check tracking-id annotation (format: "appName:group/Kind:namespace/name")
-	if trackingID, ok := annotations["argocd.argoproj.io/tracking-id"]; ok {
-		// tracking-id starts with "appName:"
-		return strings.HasPrefix(trackingID, appName+":")
-	}
-
-	return false
+	_, app, _ := agent.ArgoApplication(labels, annotations)
+	return app != "" && app == appName
 }
 
-// getManagedResources finds all resources in the destination namespace that are managed by the Application
-func getManagedResources(ctx context.Context, clientset *kubernetes.Clientset, dynamicClient dynamic.Interface, appName, namespace string) ([]ManagedResource, error) {
+// scanManagedResources finds the resources in the destination namespace that
+// carry the Application's tracking label or annotation
+func scanManagedResources(ctx context.Context, clientset *kubernetes.Clientset, appName, namespace string) ([]ManagedResource, error) {
 	var resources []ManagedResource
 
 	// Get Deployments - list all and filter by ArgoCD ownership
@@ -1422,6 +1450,9 @@ func testAnnotationUpdate(space, unitSlug string) (*TestUpdateResult, error) {
 	result := &TestUpdateResult{
 		UnitSlug: unitSlug,
 	}
+	if err := checkWrite("apply unit " + unitSlug); err != nil {
+		return result, err
+	}
 
 	timestamp := time.Now().UTC().Format(time.RFC3339)
 	annotationKey := "confighub.com/test-update"
@@ -1430,24 +1461,17 @@ func testAnnotationUpdate(space, unitSlug string) (*TestUpdateResult, error) {
 	result.Annotation = fmt.Sprintf("%s=%s", annotationKey, annotationValue)
 
 	// Step 1: Get current unit config
-	getCmd := exec.Command("cub", "unit", "get", unitSlug, "--space", space, "--json", "--quiet")
-	var getOut bytes.Buffer
-	getCmd.Stdout = &getOut
-	getCmd.Stderr = &getOut
-	if err := getCmd.Run(); err != nil {
-		return result, fmt.Errorf("failed to get unit config: %s", getOut.String())
+	if _, err := refreshCubCommand("unit", "get", unitSlug, "--space", space, "--json", "--quiet"); err != nil {
+		return result, fmt.Errorf("failed to get unit config: %s", cubStderr(err))
 	}
 
 	// Step 2: Get the unit's config data
-	configCmd := exec.Command("cub", "unit", "livedata", unitSlug, "--space", space)
-	var configOut bytes.Buffer
-	configCmd.Stdout = &configOut
-	configCmd.Stderr = &configOut
-	if err := configCmd.Run(); err != nil {
-		return result, fmt.Errorf("failed to get unit livedata: %s", configOut.String())
+	configOut, err := refreshCubCommand("unit", "livedata", unitSlug, "--space", space)
+	if err != nil {
+		return result, fmt.Errorf("failed to get unit livedata: %s", cubStderr(err))
 	}
 
-	configYAML := configOut.String()
+	configYAML := string(configOut)
 	if configYAML == "" {
 		return result, fmt.Errorf("unit has no config data")
 	}
@@ -1489,6 +1513,9 @@ func testRolloutRestart(space, unitSlug string) (*TestUpdateResult, error) {
 	result := &TestUpdateResult{
 		UnitSlug: unitSlug,
 	}
+	if err := checkWrite("apply unit " + unitSlug); err != nil {
+		return result, err
+	}
 
 	timestamp := time.Now().UTC().Format(time.RFC3339)
 	annotationKey := "kubectl.kubernetes.io/restartedAt"
@@ -1501,12 +1528,8 @@ func testRolloutRestart(space, unitSlug string) (*TestUpdateResult, error) {
 	var configYAML string
 	startTime := time.Now()
 	for {
-		configCmd := exec.Command("cub", "unit", "livedata", unitSlug, "--space", space)
-		var configOut bytes.Buffer
-		configCmd.Stdout = &configOut
-		configCmd.Stderr = &configOut
-		if err := configCmd.Run(); err == nil && configOut.Len() > 0 {
-			configYAML = configOut.String()
+		if configOut, err := refreshCubCommand("unit", "livedata", unitSlug, "--space", space); err == nil && len(configOut) > 0 {
+			configYAML = string(configOut)
 			break
 		}
 		// After 2 minutes, fail with helpful message (worker may not be running)
@@ -0,0 +1,371 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels recording where a child Application sat in an App of Apps tree.
+const (
+	argoParentLabel = "argocd-parent" // immediate App of Apps parent
+	argoRootLabel   = "argocd-root"   // top-level App of Apps the import started from
+)
+
+// argoChildApp is a workload Application found under an App of Apps.
+type argoChildApp struct {
+	Name   string
+	Parent string // immediate App of Apps parent
+	Root   string // App of Apps the walk started from
+	Depth  int    // 1 for direct children
+}
+
+// walkAppOfApps returns the workload Applications under root, descending
+// through nested App of Apps. Nested parents are not returned themselves;
+// they only show up as the Parent of their children. Children that are not
+// in apps (e.g. not yet created) are returned as leaves. Cycles are skipped.
+func walkAppOfApps(root string, apps []ArgoAppDetails) []argoChildApp {
+	byName := make(map[string]ArgoAppDetails, len(apps))
+	for _, a := range apps {
+		byName[a.Name] = a
+	}
+
+	var children []argoChildApp
+	seen := map[string]bool{root: true}
+	var walk func(parent string, depth int)
+	walk = func(parent string, depth int) {
+		for _, name := range byName[parent].ChildApps {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if child, ok := byName[name]; ok && child.IsAppOfApps {
+				walk(name, depth+1)
+				continue
+			}
+			children = append(children, argoChildApp{Name: name, Parent: parent, Root: root, Depth: depth})
+		}
+	}
+	walk(root, 1)
+	return children
+}
+
+// selectArgoChildren picks children by a comma-separated list of names or
+// 1-based indexes. An empty spec or "all" selects every child.
+func selectArgoChildren(children []argoChildApp, spec string) ([]argoChildApp, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || strings.EqualFold(spec, "all") {
+		return children, nil
+	}
+
+	picked := make(map[int]bool)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		idx := -1
+		if n, err := strconv.Atoi(item); err == nil {
+			if n < 1 || n > len(children) {
+				return nil, fmt.Errorf("child %d out of range (1-%d)", n, len(children))
+			}
+			idx = n - 1
+		} else {
+			for i, c := range children {
+				if c.Name == item {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				return nil, fmt.Errorf("unknown child Application %q", item)
+			}
+		}
+		picked[idx] = true
+	}
+
+	var selected []argoChildApp
+	for i, c := range children {
+		if picked[i] {
+			selected = append(selected, c)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no child Applications selected")
+	}
+	return selected, nil
+}
+
+// argoChildLabels returns the unit labels for a child: labels from its Git
+// path, its app name, and its place in the App of Apps tree.
+func argoChildLabels(c argoChildApp, path string) map[string]string {
+	labels := extractLabelsFromPath(path)
+	if _, ok := labels["app"]; !ok {
+		labels["app"] = c.Name
+	}
+	labels[argoParentLabel] = c.Parent
+	labels[argoRootLabel] = c.Root
+	return labels
+}
+
+// formatUnitLabels renders labels for `cub unit create --labels`, sorted by
+// key and without internal flags.
+func formatUnitLabels(labels map[string]string) string {
+	var parts []string
+	for k, v := range labels {
+		if k == "is_base" || v == "" {
+			continue
+		}
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// buildArgoUnitYAML combines managed resources into one multi-document
+// config, cleaning runtime fields unless raw is set.
+func buildArgoUnitYAML(resources []ManagedResource, raw bool) string {
+	var b strings.Builder
+	for i, r := range resources {
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		resourceYAML := r.YAML
+		if !raw {
+			if cleaned, err := cleanResourceYAML(r.YAML); err == nil {
+				resourceYAML = cleaned
+			}
+		}
+		b.WriteString(resourceYAML)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// argoChildPlan is the unit one selected child Application becomes.
+type argoChildPlan struct {
+	Child     argoChildApp
+	Space     string
+	Resources []ManagedResource
+	Labels    map[string]string
+	Err       error // set when the child could not be read
+}
+
+// runImportArgoRecursive imports the workload Applications under an App of
+// Apps as one unit each.
+func runImportArgoRecursive(ctx context.Context, clientset *kubernetes.Clientset, dynamicClient dynamic.Interface, parent string) error {
+	fmt.Printf("ConfigHub ArgoCD Import (App of Apps)\n")
+	fmt.Printf("=====================================\n\n")
+
+	fmt.Printf("Step 1: Reading App of Apps '%s' from namespace '%s'...\n", parent, argoImportNamespace)
+	apps, err := listArgoApplicationsDetailed(ctx, dynamicClient, argoImportNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to list ArgoCD Applications: %w", err)
+	}
+	var root *ArgoAppDetails
+	for i := range apps {
+		if apps[i].Name == parent {
+			root = &apps[i]
+			break
+		}
+	}
+	if root == nil {
+		return fmt.Errorf("ArgoCD Application %q not found in namespace %s", parent, argoImportNamespace)
+	}
+	if !root.IsAppOfApps {
+		return fmt.Errorf("'%s' is not an App of Apps; import it without --recursive", parent)
+	}
+
+	children := walkAppOfApps(parent, apps)
+	if len(children) == 0 {
+		fmt.Println("  ⚠ No child Applications found - nothing to import.")
+		return nil
+	}
+	fmt.Printf("  ✓ Found %d child Applications:\n", len(children))
+	for i, c := range children {
+		indent := strings.Repeat("  ", c.Depth-1)
+		via := ""
+		if c.Parent != parent {
+			via = fmt.Sprintf(" %s(via %s)%s", colorDim, c.Parent, colorReset)
+		}
+		fmt.Printf("    %2d. %s%s%s\n", i+1, indent, c.Name, via)
+	}
+	fmt.Println()
+
+	// Step 2: Choose which children to import
+	spec := argoImportChildren
+	if spec == "" && !argoImportYes && !argoImportDryRun && !argoImportShowYAML {
+		fmt.Printf("Import which children? [all, or numbers/names separated by commas] ")
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		spec = line
+	}
+	selected, err := selectArgoChildren(children, spec)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Step 2: Selected %d of %d child Applications\n\n", len(selected), len(children))
+
+	// Step 3: Read each child's managed resources
+	space := argoImportSpace
+	if space == "" && !argoImportSpacePerChild {
+		if space, err = getCurrentSpace(); err != nil {
+			space = parent
+		}
+	}
+
+	fmt.Printf("Step 3: Finding resources managed by each child...\n")
+	plans := make([]argoChildPlan, 0, len(selected))
+	for _, c := range selected {
+		plan := argoChildPlan{Child: c, Space: space}
+		if argoImportSpacePerChild {
+			plan.Space = c.Name
+		}
+		app, _, err := getArgoApplication(ctx, dynamicClient, argoImportNamespace, c.Name)
+		if err == nil && app.Destination.Namespace == "" {
+			err = fmt.Errorf("no destination namespace specified")
+		}
+		if err == nil {
+			plan.Resources, err = getManagedResources(ctx, clientset, dynamicClient, argoImportNamespace, c.Name, app.Destination.Namespace)
+			plan.Labels = argoChildLabels(c, app.Source.Path)
+		}
+		plan.Err = err
+		switch {
+		case err != nil:
+			fmt.Printf("  ✗ %s: %v\n", c.Name, err)
+		case len(plan.Resources) == 0:
+			fmt.Printf("  ⚠ %s: no managed resources (not synced yet?)\n", c.Name)
+		default:
+			fmt.Printf("  ✓ %s: %d resources\n", c.Name, len(plan.Resources))
+		}
+		plans = append(plans, plan)
+	}
+	fmt.Println()
+
+	// Summary
+	fmt.Printf("Import Summary\n")
+	fmt.Printf("--------------\n")
+	toCreate := 0
+	for _, p := range plans {
+		if p.Err != nil || len(p.Resources) == 0 {
+			continue
+		}
+		toCreate++
+		fmt.Printf("Unit %s/%s (%d resources)\n", p.Space, p.Child.Name, len(p.Resources))
+		fmt.Printf("  Labels: %s\n", formatUnitLabels(p.Labels))
+	}
+	fmt.Println()
+	if toCreate == 0 {
+		fmt.Printf("⚠ No child Application has managed resources - nothing to import.\n")
+		return nil
+	}
+
+	if argoImportShowYAML {
+		for _, p := range plans {
+			if p.Err != nil || len(p.Resources) == 0 {
+				continue
+			}
+			fmt.Printf("--- Unit: %s ---\n", p.Child.Name)
+			fmt.Println(buildArgoUnitYAML(p.Resources, argoImportRaw))
+		}
+	}
+	if argoImportDryRun || argoImportShowYAML {
+		fmt.Println("Dry-run mode: no changes will be made.")
+		fmt.Println("To import, run without --dry-run and --show-yaml")
+		return nil
+	}
+
+	var changes []plannedChange
+	for _, p := range plans {
+		if p.Err != nil || len(p.Resources) == 0 {
+			continue
+		}
+		changes = append(changes, plannedChange{Action: "create unit", Target: p.Space + "/" + p.Child.Name, Detail: fmt.Sprintf("%d resources; space created if missing", len(p.Resources))})
+	}
+	if ok, err := confirmChanges(changes, argoImportYes); err != nil || !ok {
+		return err
+	}
+	fmt.Println()
+
+	created, failed := 0, 0
+	spaces := make(map[string]bool)
+	for _, p := range plans {
+		if p.Err != nil || len(p.Resources) == 0 {
+			continue
+		}
+		if !spaces[p.Space] {
+			if err := ensureSpace(p.Space); err != nil {
+				fmt.Printf("  ✗ %s: failed to ensure space %s: %v\n", p.Child.Name, p.Space, err)
+				failed++
+				continue
+			}
+			spaces[p.Space] = true
+		}
+		if err := createUnitWithConfigAndLabels(p.Space, p.Child.Name, buildArgoUnitYAML(p.Resources, argoImportRaw), formatUnitLabels(p.Labels)); err != nil {
+			fmt.Printf("  ✗ %s: %v\n", p.Child.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  ✓ Created unit: %s/%s (%d resources)\n", p.Space, p.Child.Name, len(p.Resources))
+		created++
+	}
+
+	fmt.Println()
+	fmt.Printf("Import complete: %d units created", created)
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	fmt.Println()
+	fmt.Println("View the imported units:")
+	listSpace := space
+	if argoImportSpacePerChild {
+		listSpace = "'*'"
+	}
+	fmt.Printf("  cub unit list --space %s --where \"Labels.%s = '%s'\"\n", listSpace, argoRootLabel, parent)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d units failed to import", failed, toCreate)
+	}
+	return nil
+}
+
+// argoChildInfos returns the listed Applications that parent manages, tagged
+// with their place in the App of Apps tree.
+func argoChildInfos(all []argoAppInfo, parent argoAppInfo) []argoAppInfo {
+	root := parent.Root
+	if root == "" {
+		root = parent.Name
+	}
+	want := make(map[string]bool, len(parent.ChildApps))
+	for _, name := range parent.ChildApps {
+		want[name] = true
+	}
+	var children []argoAppInfo
+	for _, a := range all {
+		if want[a.Name] && a.Name != parent.Name {
+			a.Parent = parent.Name
+			a.Root = root
+			children = append(children, a)
+		}
+	}
+	return children
+}
+
+// argoTreeLabels returns the App of Apps labels for an app opened from a
+// parent in the import wizard, or "" for a top-level app.
+func argoTreeLabels(app argoAppInfo) string {
+	if app.Parent == "" {
+		return ""
+	}
+	return formatUnitLabels(map[string]string{argoParentLabel: app.Parent, argoRootLabel: app.Root})
+}
@@ -0,0 +1,99 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+)
+
+func TestWalkAppOfApps(t *testing.T) {
+	apps := []ArgoAppDetails{
+		{Name: "root", IsAppOfApps: true, ChildApps: []string{"platform", "cart"}},
+		{Name: "platform", IsAppOfApps: true, ChildApps: []string{"ingress", "root"}},
+		{Name: "ingress"},
+		{Name: "cart"},
+	}
+	got := walkAppOfApps("root", apps)
+	want := []argoChildApp{
+		{Name: "ingress", Parent: "platform", Root: "root", Depth: 2},
+		{Name: "cart", Parent: "root", Root: "root", Depth: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("walkAppOfApps = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("child %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSelectArgoChildren(t *testing.T) {
+	children := []argoChildApp{{Name: "cart"}, {Name: "checkout"}, {Name: "ingress"}}
+
+	tests := []struct {
+		spec    string
+		want    []string
+		wantErr bool
+	}{
+		{"", []string{"cart", "checkout", "ingress"}, false},
+		{"all\n", []string{"cart", "checkout", "ingress"}, false},
+		{"3, cart", []string{"cart", "ingress"}, false},
+		{"4", nil, true},
+		{"payments", nil, true},
+		{" , ", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := selectArgoChildren(children, tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("selectArgoChildren(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		var names []string
+		for _, c := range got {
+			names = append(names, c.Name)
+		}
+		if len(names) != len(tt.want) {
+			t.Errorf("selectArgoChildren(%q) = %v, want %v", tt.spec, names, tt.want)
+			continue
+		}
+		for i := range names {
+			if names[i] != tt.want[i] {
+				t.Errorf("selectArgoChildren(%q) = %v, want %v", tt.spec, names, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestArgoChildLabels(t *testing.T) {
+	c := argoChildApp{Name: "cart", Parent: "platform", Root: "root"}
+	got := formatUnitLabels(argoChildLabels(c, "apps/cart/overlays/prod"))
+	want := "app=cart,argocd-parent=platform,argocd-root=root,variant=prod"
+	if got != want {
+		t.Errorf("labels = %q, want %q", got, want)
+	}
+}
+
+func TestArgoChildInfos(t *testing.T) {
+	all := []argoAppInfo{
+		{Name: "root", IsAppOfApps: true, ChildApps: []string{"platform"}},
+		{Name: "platform", IsAppOfApps: true, ChildApps: []string{"ingress"}},
+		{Name: "ingress"},
+	}
+	platform := argoChildInfos(all, all[0])
+	if len(platform) != 1 || platform[0].Parent != "root" || platform[0].Root != "root" {
+		t.Fatalf("children of root = %+v", platform)
+	}
+	ingress := argoChildInfos(all, platform[0])
+	if len(ingress) != 1 || ingress[0].Name != "ingress" || ingress[0].Parent != "platform" || ingress[0].Root != "root" {
+		t.Fatalf("children of platform = %+v", ingress)
+	}
+	if got := argoTreeLabels(ingress[0]); got != "argocd-parent=platform,argocd-root=root" {
+		t.Errorf("argoTreeLabels = %q", got)
+	}
+	if got := argoTreeLabels(all[0]); got != "" {
+		t.Errorf("top-level app should have no tree labels, got %q", got)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+// argoUseAPI reads resources the cluster can't serve (an Application
+// deploying to another cluster) from the Argo CD API server (--use-argocd-api).
+var argoUseAPI bool
+
+// inClusterServer is the destination server of Applications deploying to
+// the cluster Argo CD runs in.
+const inClusterServer = "https://kubernetes.default.svc"
+
+// getManagedResources returns the resources an Application manages. It reads
+// the Application's status.resources, which lists everything Argo CD tracks
+// in any namespace, and fetches each live object from the API server, so
+// neither the argocd CLI nor a port-forward is needed. Resources the cluster
+// can't serve are read from the Argo CD API with --use-argocd-api.
+// Applications that haven't synced yet have no status.resources; their
+// destination namespace is scanned for Argo CD's tracking labels instead.
+func getManagedResources(ctx context.Context, clientset *kubernetes.Clientset, dynamicClient dynamic.Interface, appNamespace, appName, namespace string) ([]ManagedResource, error) {
+	app, _, err := getArgoApplication(ctx, dynamicClient, appNamespace, appName)
+	if err != nil {
+		return nil, err
+	}
+	status, err := getStatusResources(ctx, dynamicClient, appNamespace, appName)
+	if err != nil {
+		return nil, err
+	}
+	if len(status) == 0 {
+		return scanManagedResources(ctx, clientset, appName, namespace)
+	}
+
+	var resources []ManagedResource
+	missing := importableStatusResources(status)
+	if isInClusterDestination(app.Destination) {
+		resources, missing = managedResourcesFromStatus(ctx, status, newLiveObjectGetter(clientset, dynamicClient))
+	}
+	if len(missing) == 0 {
+		return resources, nil
+	}
+
+	if !argoUseAPI {
+		if len(resources) == 0 && !isInClusterDestination(app.Destination) {
+			return nil, fmt.Errorf("Application %s deploys to %s, not this cluster; pass --use-argocd-api (with ARGOCD_SERVER and ARGOCD_AUTH_TOKEN) to read its resources from Argo CD", appName, destinationLabel(app.Destination))
+		}
+		return resources, nil
+	}
+	items, err := argoAPIManagedResources(ctx, appNamespace, appName)
+	if err != nil {
+		return nil, fmt.Errorf("read managed resources from the Argo CD API: %w", err)
+	}
+	return append(resources, managedResourcesFromAPI(missing, items)...), nil
+}
+
+// importableStatusResources drops hooks and child Applications (imported on
+// their own with --recursive) from status.resources.
+func importableStatusResources(status []ArgoStatusResource) []ArgoStatusResource {
+	var out []ArgoStatusResource
+	for _, r := range status {
+		if r.Hook || (r.Group == "argoproj.io" && r.Kind == "Application") {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// liveObjectGetter returns the live object for a status.resources entry,
+// or nil when the cluster doesn't have it.
+type liveObjectGetter func(ctx context.Context, r ArgoStatusResource) (*unstructured.Unstructured, error)
+
+// newLiveObjectGetter resolves kinds through discovery, so any resource
+// type Argo CD tracks can be read, including cluster-scoped ones.
+func newLiveObjectGetter(clientset *kubernetes.Clientset, dynamicClient dynamic.Interface) liveObjectGetter {
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery()))
+	return func(ctx context.Context, r ArgoStatusResource) (*unstructured.Unstructured, error) {
+		mapping, err := mapper.RESTMapping(schema.GroupKind{Group: r.Group, Kind: r.Kind}, r.Version)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		var ri dynamic.ResourceInterface = dynamicClient.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			ri = dynamicClient.Resource(mapping.Resource).Namespace(r.Namespace)
+		}
+		obj, err := ri.Get(ctx, r.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return obj, err
+	}
+}
+
+// managedResourcesFromStatus fetches the live object of each importable
+// status.resources entry, returning the entries it could not read.
+func managedResourcesFromStatus(ctx context.Context, status []ArgoStatusResource, get liveObjectGetter) ([]ManagedResource, []ArgoStatusResource) {
+	var resources []ManagedResource
+	var missing []ArgoStatusResource
+	for _, r := range importableStatusResources(status) {
+		obj, err := get(ctx, r)
+		if err != nil || obj == nil {
+			missing = append(missing, r)
+			continue
+		}
+		yamlBytes, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			missing = append(missing, r)
+			continue
+		}
+		resources = append(resources, ManagedResource{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       r.Kind,
+			Namespace:  r.Namespace,
+			Name:       r.Name,
+			Status:     r.Status,
+			Health:     r.Health,
+			YAML:       string(yamlBytes),
+		})
+	}
+	return resources, missing
+}
+
+// argoAPIResource is one item of the Argo CD API's managed-resources
+// response. LiveState is the live object as a JSON string ("null" when
+// missing).
+type argoAPIResource struct {
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	LiveState string `json:"liveState"`
+}
+
+// argoAPIManagedResources reads an Application's managed resources from the
+// Argo CD API server, configured with ARGOCD_SERVER and ARGOCD_AUTH_TOKEN as
+// for the argocd CLI.
+func argoAPIManagedResources(ctx context.Context, appNamespace, appName string) ([]argoAPIResource, error) {
+	server, token := os.Getenv("ARGOCD_SERVER"), os.Getenv("ARGOCD_AUTH_TOKEN")
+	if server == "" || token == "" {
+		return nil, fmt.Errorf("--use-argocd-api needs ARGOCD_SERVER and ARGOCD_AUTH_TOKEN")
+	}
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	u := strings.TrimSuffix(server, "/") + "/api/v1/applications/" + url.PathEscape(appName) +
+		"/managed-resources?appNamespace=" + url.QueryEscape(appNamespace)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		Items []argoAPIResource `json:"items"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("decode managed resources: %w", err)
+	}
+	return out.Items, nil
+}
+
+// managedResourcesFromAPI builds the missing resources from the live state
+// the Argo CD API returned, keeping sync and health from status.resources.
+func managedResourcesFromAPI(missing []ArgoStatusResource, items []argoAPIResource) []ManagedResource {
+	byKey := map[string]argoAPIResource{}
+	for _, item := range items {
+		byKey[item.Group+"/"+item.Kind+"/"+item.Namespace+"/"+item.Name] = item
+	}
+
+	var resources []ManagedResource
+	for _, r := range missing {
+		item, ok := byKey[r.Group+"/"+r.Kind+"/"+r.Namespace+"/"+r.Name]
+		if !ok || item.LiveState == "" || item.LiveState == "null" {
+			continue
+		}
+		yamlBytes, err := yaml.JSONToYAML([]byte(item.LiveState))
+		if err != nil {
+			continue
+		}
+		apiVersion := r.Version
+		if r.Group != "" {
+			apiVersion = r.Group + "/" + r.Version
+		}
+		resources = append(resources, ManagedResource{
+			APIVersion: apiVersion,
+			Kind:       r.Kind,
+			Namespace:  r.Namespace,
+			Name:       r.Name,
+			Status:     r.Status,
+			Health:     r.Health,
+			YAML:       string(yamlBytes),
+		})
+	}
+	return resources
+}
+
+// isInClusterDestination reports whether an Application deploys to the
+// cluster Argo CD (and this kubeconfig) points at.
+func isInClusterDestination(d ArgoDestination) bool {
+	if d.Server != "" {
+		return d.Server == inClusterServer || strings.HasPrefix(d.Server, inClusterServer+":")
+	}
+	return d.Name == "" || d.Name == "in-cluster"
+}
+
+func destinationLabel(d ArgoDestination) string {
+	if d.Name != "" {
+		return d.Name
+	}
+	return d.Server
+}
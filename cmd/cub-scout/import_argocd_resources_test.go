@@ -0,0 +1,118 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestManagedResourcesFromStatus(t *testing.T) {
+	status := []ArgoStatusResource{
+		{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "shop", Name: "cart", Status: "Synced", Health: "Healthy"},
+		{Version: "v1", Kind: "Service", Namespace: "shop", Name: "cart", Status: "OutOfSync"},
+		{Group: "batch", Version: "v1", Kind: "Job", Namespace: "shop", Name: "migrate", Hook: true},
+		{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application", Namespace: "argocd", Name: "child"},
+	}
+	live := map[string]*unstructured.Unstructured{
+		"Deployment/cart": agenttest.Deployment("shop", "cart"),
+	}
+	var fetched []string
+	get := func(_ context.Context, r ArgoStatusResource) (*unstructured.Unstructured, error) {
+		fetched = append(fetched, r.Kind+"/"+r.Name)
+		return live[r.Kind+"/"+r.Name], nil
+	}
+
+	resources, missing := managedResourcesFromStatus(context.Background(), status, get)
+	if len(fetched) != 2 {
+		t.Errorf("fetched %v, want the Deployment and Service only", fetched)
+	}
+	if len(resources) != 1 || resources[0].Kind != "Deployment" || resources[0].APIVersion != "apps/v1" {
+		t.Fatalf("resources = %+v, want the Deployment", resources)
+	}
+	if resources[0].Status != "Synced" || resources[0].Health != "Healthy" {
+		t.Errorf("status/health = %s/%s, want Synced/Healthy", resources[0].Status, resources[0].Health)
+	}
+	if !strings.Contains(resources[0].YAML, "name: cart") {
+		t.Errorf("YAML missing the object:\n%s", resources[0].YAML)
+	}
+	if len(missing) != 1 || missing[0].Kind != "Service" {
+		t.Errorf("missing = %+v, want the Service", missing)
+	}
+}
+
+func TestArgoAPIManagedResources(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v1/applications/edge-api/managed-resources" || r.URL.Query().Get("appNamespace") != "argocd" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"items":[
+			{"group":"apps","version":"v1","kind":"Deployment","namespace":"edge","name":"api",
+			 "liveState":"{\"apiVersion\":\"apps/v1\",\"kind\":\"Deployment\",\"metadata\":{\"name\":\"api\",\"namespace\":\"edge\"}}"},
+			{"version":"v1","kind":"ConfigMap","namespace":"edge","name":"gone","liveState":"null"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("ARGOCD_SERVER", srv.URL)
+	t.Setenv("ARGOCD_AUTH_TOKEN", "s3cret")
+	items, err := argoAPIManagedResources(context.Background(), "argocd", "edge-api")
+	if err != nil {
+		t.Fatalf("argoAPIManagedResources: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	missing := []ArgoStatusResource{
+		{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "edge", Name: "api", Status: "Synced"},
+		{Version: "v1", Kind: "ConfigMap", Namespace: "edge", Name: "gone"},
+	}
+	resources := managedResourcesFromAPI(missing, items)
+	if len(resources) != 1 || resources[0].APIVersion != "apps/v1" || resources[0].Status != "Synced" {
+		t.Fatalf("resources = %+v, want the Deployment", resources)
+	}
+	if !strings.Contains(resources[0].YAML, "namespace: edge") {
+		t.Errorf("YAML not converted:\n%s", resources[0].YAML)
+	}
+
+	t.Setenv("ARGOCD_AUTH_TOKEN", "wrong")
+	if _, err := argoAPIManagedResources(context.Background(), "argocd", "edge-api"); err == nil {
+		t.Error("expected an error for a rejected token")
+	}
+	t.Setenv("ARGOCD_SERVER", "")
+	if _, err := argoAPIManagedResources(context.Background(), "argocd", "edge-api"); err == nil {
+		t.Error("expected an error without ARGOCD_SERVER")
+	}
+}
+
+func TestIsInClusterDestination(t *testing.T) {
+	tests := []struct {
+		dest ArgoDestination
+		want bool
+	}{
+		{ArgoDestination{Server: "https://kubernetes.default.svc"}, true},
+		{ArgoDestination{Server: "https://kubernetes.default.svc:443"}, true},
+		{ArgoDestination{Name: "in-cluster"}, true},
+		{ArgoDestination{}, true},
+		{ArgoDestination{Server: "https://edge.example.com:6443"}, false},
+		{ArgoDestination{Name: "edge"}, false},
+	}
+	for _, tt := range tests {
+		if got := isInClusterDestination(tt.dest); got != tt.want {
+			t.Errorf("isInClusterDestination(%+v) = %v, want %v", tt.dest, got, tt.want)
+		}
+	}
+}
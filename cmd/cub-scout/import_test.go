@@ -0,0 +1,114 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func TestRemoveImportMarkers(t *testing.T) {
+	dep := agenttest.Deployment("prod", "api",
+		agenttest.WithLabels(map[string]string{"app": "api", "confighub.com/UnitSlug": "old", "cli-utils.sigs.k8s.io/inventory-id": "inv"}),
+		agenttest.WithAnnotations(map[string]string{"config.k8s.io/owning-inventory": "inv", "keep": "me"}))
+	client := agenttest.FakeClient(dep)
+
+	errs := removeImportMarkers(context.Background(), client, []WorkloadInfo{
+		{Kind: "Deployment", Namespace: "prod", Name: "api"},
+		{Kind: "Deployment", Namespace: "prod", Name: "gone"}, // deleted since discovery
+	})
+	if len(errs) != 0 {
+		t.Fatalf("errs = %v", errs)
+	}
+
+	got, err := client.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).
+		Namespace("prod").Get(context.Background(), "api", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if labels := got.GetLabels(); len(labels) != 1 || labels["app"] != "api" {
+		t.Errorf("labels = %v, want only app=api", labels)
+	}
+	if annotations := got.GetAnnotations(); len(annotations) != 1 || annotations["keep"] != "me" {
+		t.Errorf("annotations = %v, want only keep=me", annotations)
+	}
+}
+
+func TestLoadNamespacesSkipsUnlistableKinds(t *testing.T) {
+	client := agenttest.FakeClient(
+		agenttest.Object("v1", "Namespace", "", "prod"),
+		agenttest.Deployment("prod", "api"),
+		agenttest.Object("apps/v1", "StatefulSet", "prod", "db"),
+	)
+	client.PrependReactor("list", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "statefulsets"}, "", nil)
+	})
+
+	namespaces, warnings, err := loadNamespaces(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 1 || namespaces[0].Deployments != 1 || namespaces[0].StatefulSet != 0 {
+		t.Errorf("namespaces = %+v, want prod with 1 deployment", namespaces)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "statefulsets") {
+		t.Errorf("warnings = %v, want one for statefulsets", warnings)
+	}
+}
+
+func TestImportChangesListClusterWrites(t *testing.T) {
+	api := WorkloadInfo{Kind: "Deployment", Namespace: "prod", Name: "api"}
+	worker := WorkloadInfo{Kind: "Deployment", Namespace: "prod", Name: "worker"}
+	db := WorkloadInfo{Kind: "StatefulSet", Namespace: "prod", Name: "db"}
+	m := &Model{
+		importSpace:       "payments",
+		importWorkloads:   []WorkloadInfo{api, worker, db},
+		importSelected:    []bool{true, true, false},
+		importGroupedView: true,
+		importSuggestion: &ImportSuggestion{Apps: []AppSuggestion{
+			{Name: "payments", Variants: []VariantSuggestion{{UnitSlug: "payments-prod", Workloads: []WorkloadInfo{api, worker}}}},
+			{Name: "db", Variants: []VariantSuggestion{{UnitSlug: "db-prod", Workloads: []WorkloadInfo{db}}}},
+		}},
+	}
+
+	var labels []string
+	var units []string
+	for _, c := range m.importChanges() {
+		switch c.Action {
+		case "create unit":
+			units = append(units, c.Target)
+		case "label":
+			if !c.Cluster || c.Detail != "confighub.com/UnitSlug=payments-prod" {
+				t.Errorf("label change = %+v", c)
+			}
+			labels = append(labels, c.Target)
+		}
+	}
+	if strings.Join(units, ",") != "payments/payments-prod" {
+		t.Errorf("units = %v, want only the variant with selected workloads", units)
+	}
+	if strings.Join(labels, ",") != "Deployment prod/api,Deployment prod/worker" {
+		t.Errorf("labels = %v", labels)
+	}
+
+	changes := applyUnitChanges("payments", "guestbook-workload", []WorkloadInfo{api})
+	if len(changes) != 2 || changes[0].Action != "remove import markers" || !changes[0].Cluster || changes[1].Action != "apply unit" {
+		t.Errorf("apply changes = %+v", changes)
+	}
+
+	readOnlyMode = true
+	t.Cleanup(func() { readOnlyMode = false })
+	if err := checkChanges(m.importChanges()); err == nil || !strings.Contains(err.Error(), "label Deployment prod/api") {
+		t.Errorf("read-only: err = %v, want label refusal", err)
+	}
+}
@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -89,6 +90,7 @@ type NamespaceItem struct {
 	WorkloadCount int
 	Selected      bool
 	Workloads     []WorkloadInfo // Populated when previewing
+	Err           error          // Set when the namespace couldn't be scanned
 }
 
 // WorkloadItem represents a workload for selection
@@ -168,6 +170,11 @@ type ImportWizardModel struct {
 	loadingMessage string
 	err            error
 
+	// Namespace scan progress
+	scanTotal  int
+	scanDone   int
+	scanActive []string // namespaces being scanned, in start order
+
 	// Result
 	quit bool
 
@@ -547,8 +554,27 @@ func (m ImportWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Now discover namespaces
 		cmds = append(cmds, m.discoverNamespaces)
 
+	case wizardScanStartedMsg:
+		m.scanTotal, m.scanDone, m.scanActive = len(msg.namespaces), 0, nil
+		cmds = append(cmds, waitForScan(msg.next))
+
+	case wizardScanProgressMsg:
+		if msg.done {
+			m.scanDone++
+			for i, ns := range m.scanActive {
+				if ns == msg.namespace {
+					m.scanActive = append(m.scanActive[:i:i], m.scanActive[i+1:]...)
+					break
+				}
+			}
+		} else {
+			m.scanActive = append(m.scanActive, msg.namespace)
+		}
+		cmds = append(cmds, waitForScan(msg.next))
+
 	case wizardNamespacesMsg:
 		m.namespaces = msg.namespaces
+		m.scanTotal, m.scanDone, m.scanActive = 0, 0, nil
 		m.loading = false
 
 	case wizardWorkloadsMsg:
@@ -707,6 +733,10 @@ func (m ImportWizardModel) View() string {
 	}
 
 	// Loading state (but not for test step - it has its own progress display)
+	if m.loading && m.scanTotal > 0 {
+		b.WriteString(m.renderNamespaceScan())
+		return b.String()
+	}
 	if m.loading && m.step != StepTest {
 		b.WriteString(m.spinner.View())
 		b.WriteString(" ")
@@ -1460,27 +1490,6 @@ func (m ImportWizardModel) prevStep() (tea.Model, tea.Cmd) {
 
 // Data loading commands
 
-func (m ImportWizardModel) discoverNamespaces() tea.Msg {
-	namespaces, err := discoverNamespacesWithWorkloads()
-	if err != nil {
-		return wizardErrMsg{err: err}
-	}
-
-	items := make([]NamespaceItem, 0, len(namespaces))
-	for _, ns := range namespaces {
-		// Count workloads in namespace
-		workloads, _ := discoverWorkloads(ns)
-		items = append(items, NamespaceItem{
-			Name:          ns,
-			WorkloadCount: len(workloads),
-			Selected:      false, // User must explicitly select namespaces
-			Workloads:     workloads,
-		})
-	}
-
-	return wizardNamespacesMsg{namespaces: items}
-}
-
 func (m ImportWizardModel) loadWorkloads() tea.Msg {
 	var allWorkloads []WorkloadItem
 
@@ -1826,8 +1835,10 @@ func (m ImportWizardModel) runTestAddAnnotation() tea.Msg {
 
 	// Step 1: Get unit JSON
 	appendTestDebug("Step 1: Getting unit JSON...")
-	getCmd := exec.Command("cub", "unit", "get", "--space", m.proposal.AppSpace, m.testUnitSlug, "--json")
-	unitJSON, err := getCmd.CombinedOutput()
+	unitJSON, err := refreshCubCommand("unit", "get", "--space", m.proposal.AppSpace, m.testUnitSlug, "--json")
+	if err != nil {
+		unitJSON = []byte(cubStderr(err))
+	}
 	writeTestDebug("01-unit-get.json", unitJSON)
 	appendTestDebug(fmt.Sprintf("Unit get result: %d bytes, err=%v", len(unitJSON), err))
 	if err != nil {
@@ -1956,11 +1967,13 @@ func (m ImportWizardModel) runTestApply() tea.Msg {
 
 	// First, check if the unit has a target. If not, find one and set it.
 	appendTestDebug("Checking if unit has target...")
-	checkCmd := exec.Command("cub", "unit", "get",
+	checkOutput, err := refreshCubCommand("unit", "get",
 		"--space", m.proposal.AppSpace,
 		"--json",
 		m.testUnitSlug)
-	checkOutput, _ := checkCmd.CombinedOutput()
+	if err != nil {
+		checkOutput = []byte(cubStderr(err))
+	}
 	writeTestDebug("06-unit-before-apply.json", checkOutput)
 	appendTestDebug(fmt.Sprintf("Unit JSON: %d bytes", len(checkOutput)))
 
@@ -1976,10 +1989,12 @@ func (m ImportWizardModel) runTestApply() tea.Msg {
 	if !hasTarget {
 		appendTestDebug("Unit has no target, finding one...")
 		// Find a Kubernetes target
-		targetCmd := exec.Command("cub", "target", "list",
+		targetOutput, err := runCubCommand("target", "list",
 			"--space", m.proposal.AppSpace,
 			"--json")
-		targetOutput, err := targetCmd.CombinedOutput()
+		if err != nil {
+			targetOutput = []byte(cubStderr(err))
+		}
 		writeTestDebug("07-target-list.json", targetOutput)
 		appendTestDebug(fmt.Sprintf("Target list: %d bytes, err=%v", len(targetOutput), err))
 		if err != nil {
@@ -2031,6 +2046,9 @@ func (m ImportWizardModel) runTestApply() tea.Msg {
 	}
 
 	// Apply the unit
+	if err := checkWrite("apply unit " + m.testUnitSlug); err != nil {
+		return wizardTestPhaseMsg{phase: testPhaseApply, success: false, err: err}
+	}
 	appendTestDebug("Applying unit...")
 	cmd := exec.Command("cub", "unit", "apply",
 		"--space", m.proposal.AppSpace,
@@ -2074,12 +2092,13 @@ func (m ImportWizardModel) runTestWaitSync() tea.Msg {
 	// Check if LiveRevisionNum matches HeadRevisionNum
 	// This indicates the worker has synced
 	appendTestDebug("Getting unit status...")
-	cmd := exec.Command("cub", "unit", "get",
+	output, err := refreshCubCommand("unit", "get",
 		"--space", m.proposal.AppSpace,
 		"--json",
 		m.testUnitSlug)
-
-	output, err := cmd.CombinedOutput()
+	if err != nil {
+		output = []byte(cubStderr(err))
+	}
 	writeTestDebug("10-unit-sync-status.json", output)
 	appendTestDebug(fmt.Sprintf("Unit status: %d bytes, err=%v", len(output), err))
 	if err != nil {
@@ -2183,48 +2202,25 @@ func (m ImportWizardModel) runTestVerify() tea.Msg {
 
 	annotationKey := "confighub.com/import-test"
 
-	// First, get ALL annotations for debug
-	appendTestDebug(fmt.Sprintf("Getting all annotations from %s/%s/%s...", workload.Kind, workload.Namespace, workload.Name))
-	allAnnotationsCmd := exec.Command("kubectl", "get", strings.ToLower(workload.Kind),
-		workload.Name,
-		"-n", workload.Namespace,
-		"-o", "jsonpath={.metadata.annotations}")
-	allAnnotations, _ := allAnnotationsCmd.CombinedOutput()
-	writeTestDebug("11-cluster-annotations.json", allAnnotations)
-	appendTestDebug(fmt.Sprintf("All annotations: %s", string(allAnnotations)))
-
-	// Use kubectl to check the annotation on the resource
-	appendTestDebug(fmt.Sprintf("Checking for annotation %s=%s", annotationKey, m.testAnnotation))
-	cmd := exec.Command("kubectl", "get", strings.ToLower(workload.Kind),
-		workload.Name,
-		"-n", workload.Namespace,
-		"-o", fmt.Sprintf("jsonpath={.metadata.annotations.%s}", strings.ReplaceAll(annotationKey, "/", "\\/")))
-
-	output, err := cmd.CombinedOutput()
-	writeTestDebug("12-annotation-check.txt", output)
-	appendTestDebug(fmt.Sprintf("Annotation check result: '%s', err=%v", string(output), err))
-
+	// Read the live annotations once: the full set is kept for debug and for
+	// the GitOps check below
+	appendTestDebug(fmt.Sprintf("Getting annotations from %s/%s/%s...", workload.Kind, workload.Namespace, workload.Name))
+	obj, err := getWorkload(context.Background(), workload.Kind, workload.Namespace, workload.Name)
 	if err != nil {
-		// Try alternate jsonpath format
-		appendTestDebug("First jsonpath failed, trying alternate format...")
-		cmd = exec.Command("kubectl", "get", strings.ToLower(workload.Kind),
-			workload.Name,
-			"-n", workload.Namespace,
-			"-o", "jsonpath={.metadata.annotations['confighub\\.com/import-test']}")
-		output, err = cmd.CombinedOutput()
-		appendTestDebug(fmt.Sprintf("Alternate jsonpath result: '%s', err=%v", string(output), err))
-		if err != nil {
-			appendTestDebug(fmt.Sprintf("ERROR: failed to get annotation from cluster: %s", string(output)))
-			return wizardTestPhaseMsg{
-				phase:   testPhaseVerify,
-				success: false,
-				details: fmt.Sprintf("Failed to get annotation. See %s/", testDebugDir),
-				err:     fmt.Errorf("failed to get annotation from cluster: %w", err),
-			}
+		appendTestDebug(fmt.Sprintf("ERROR: failed to get workload from cluster: %v", err))
+		return wizardTestPhaseMsg{
+			phase:   testPhaseVerify,
+			success: false,
+			details: fmt.Sprintf("Failed to get annotation. See %s/", testDebugDir),
+			err:     fmt.Errorf("failed to get annotation from cluster: %w", err),
 		}
 	}
+	allAnnotations, _ := json.Marshal(obj.GetAnnotations())
+	writeTestDebug("11-cluster-annotations.json", allAnnotations)
+	appendTestDebug(fmt.Sprintf("All annotations: %s", string(allAnnotations)))
 
-	foundValue := strings.TrimSpace(string(output))
+	foundValue := obj.GetAnnotations()[annotationKey]
+	writeTestDebug("12-annotation-check.txt", []byte(foundValue))
 	appendTestDebug(fmt.Sprintf("Found value: '%s', expected: '%s'", foundValue, m.testAnnotation))
 
 	if foundValue == m.testAnnotation {
@@ -2251,11 +2247,13 @@ func (m ImportWizardModel) runTestVerify() tea.Msg {
 			// GitOps reconciliation removed our annotation - this is expected
 			// Verify the annotation is still in the Unit (ConfigHub side worked)
 			appendTestDebug("Checking if annotation is in Unit data...")
-			unitCmd := exec.Command("cub", "unit", "get",
+			unitOutput, err := refreshCubCommand("unit", "get",
 				"--space", m.proposal.AppSpace,
 				"--json",
 				m.testUnitSlug)
-			unitOutput, err := unitCmd.CombinedOutput()
+			if err != nil {
+				unitOutput = []byte(cubStderr(err))
+			}
 			writeTestDebug("13-unit-final-state.json", unitOutput)
 			appendTestDebug(fmt.Sprintf("Unit final state: %d bytes, err=%v", len(unitOutput), err))
 
@@ -2312,12 +2310,10 @@ func (m ImportWizardModel) runTestVerify() tea.Msg {
 func (m ImportWizardModel) checkSyncStatusCmd() tea.Cmd {
 	return func() tea.Msg {
 		// Check unit status
-		cmd := exec.Command("cub", "unit", "get",
+		_, err := refreshCubCommand("unit", "get",
 			"--space", m.proposal.AppSpace,
 			"--json",
 			m.testUnitSlug)
-
-		_, err := cmd.CombinedOutput()
 		if err != nil {
 			// Keep polling
 			time.Sleep(500 * time.Millisecond)
@@ -2375,6 +2371,9 @@ func (m ImportWizardModel) renderNamespaceList() string {
 		}
 
 		count := dimStyle.Render(fmt.Sprintf("(%d workloads)", ns.WorkloadCount))
+		if ns.Err != nil {
+			count = wizardErrorStyle.Render(fmt.Sprintf("(scan failed: %v)", ns.Err))
+		}
 
 		b.WriteString(fmt.Sprintf("%s%s %s %s\n", cursor, checkbox, name, count))
 	}
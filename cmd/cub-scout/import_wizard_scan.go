@@ -0,0 +1,101 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// namespaceScanWorkers bounds how many namespaces are scanned at once, so
+// large clusters finish quickly without flooding the API server.
+const namespaceScanWorkers = 8
+
+// namespaceScanShown is how many in-flight namespaces the loading view lists.
+const namespaceScanShown = 8
+
+// wizardScanStartedMsg starts the namespace scan; later messages arrive on next.
+type wizardScanStartedMsg struct {
+	namespaces []string
+	next       <-chan tea.Msg
+}
+
+// wizardScanProgressMsg reports one namespace scan starting or finishing.
+type wizardScanProgressMsg struct {
+	namespace string
+	done      bool
+	next      <-chan tea.Msg
+}
+
+// discoverNamespaces finds the namespaces with workloads and scans each one
+// concurrently. Progress arrives as wizardScanProgressMsg, followed by a
+// wizardNamespacesMsg with the results.
+func (m ImportWizardModel) discoverNamespaces() tea.Msg {
+	if m.clientset == nil {
+		return wizardErrMsg{err: fmt.Errorf("kubernetes client not initialized")}
+	}
+	namespaces, err := discoverNamespacesWithWorkloads()
+	if err != nil {
+		return wizardErrMsg{err: err}
+	}
+	// Buffered for every message, so workers never block on the UI
+	ch := make(chan tea.Msg, 2*len(namespaces)+1)
+	go scanNamespaces(m.ctx, m.clientset, m.dynClient, namespaces, ch)
+	return wizardScanStartedMsg{namespaces: namespaces, next: ch}
+}
+
+// scanNamespaces lists workloads in each namespace with a bounded pool of
+// workers and sends progress, then the items in the original order, to ch.
+func scanNamespaces(ctx context.Context, clientset kubernetes.Interface, dynClient dynamic.Interface, namespaces []string, ch chan tea.Msg) {
+	items := make([]NamespaceItem, len(namespaces))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(namespaceScanWorkers, len(namespaces)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				ns := namespaces[i]
+				ch <- wizardScanProgressMsg{namespace: ns, next: ch}
+				workloads, err := listNamespaceWorkloads(ctx, clientset, dynClient, ns)
+				items[i] = NamespaceItem{Name: ns, WorkloadCount: len(workloads), Workloads: workloads, Err: err}
+				ch <- wizardScanProgressMsg{namespace: ns, done: true, next: ch}
+			}
+		}()
+	}
+	for i := range namespaces {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	ch <- wizardNamespacesMsg{namespaces: items}
+}
+
+// waitForScan delivers the next namespace scan message.
+func waitForScan(next <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-next
+	}
+}
+
+// renderNamespaceScan shows scan progress with a spinner per namespace
+// still being scanned.
+func (m ImportWizardModel) renderNamespaceScan() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s Scanning namespaces (%d/%d)\n\n", m.spinner.View(), m.scanDone, m.scanTotal))
+	for i, ns := range m.scanActive {
+		if i == namespaceScanShown {
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  … and %d more", len(m.scanActive)-i)) + "\n")
+			break
+		}
+		b.WriteString(fmt.Sprintf("  %s %s\n", m.spinner.View(), ns))
+	}
+	return b.String()
+}
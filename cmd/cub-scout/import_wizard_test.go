@@ -4,6 +4,8 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"testing"
 	"time"
@@ -13,6 +15,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/exp/teatest"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -558,3 +564,46 @@ func indexOf(s, substr string) int {
 	}
 	return -1
 }
+
+func TestScanNamespaces(t *testing.T) {
+	replicas := int32(1)
+	deployment := func(ns, name string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}
+	}
+	namespaces := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	var objs []runtime.Object
+	for i, ns := range namespaces {
+		for n := 0; n <= i%3; n++ {
+			objs = append(objs, deployment(ns, fmt.Sprintf("app-%d", n)))
+		}
+	}
+
+	ch := make(chan tea.Msg, 2*len(namespaces)+1)
+	go scanNamespaces(context.Background(), fake.NewSimpleClientset(objs...), nil, namespaces, ch)
+
+	started, finished := 0, 0
+	for msg := range ch {
+		switch msg := msg.(type) {
+		case wizardScanProgressMsg:
+			if msg.done {
+				finished++
+			} else {
+				started++
+			}
+		case wizardNamespacesMsg:
+			if started != len(namespaces) || finished != len(namespaces) {
+				t.Errorf("progress: %d started, %d finished, want %d each", started, finished, len(namespaces))
+			}
+			for i, item := range msg.namespaces {
+				if item.Name != namespaces[i] || item.WorkloadCount != i%3+1 || item.Err != nil {
+					t.Errorf("namespaces[%d] = %s with %d workloads (err %v), want %s with %d",
+						i, item.Name, item.WorkloadCount, item.Err, namespaces[i], i%3+1)
+				}
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/internal/mapsvc"
+	"github.com/confighub/cub-scout/pkg/agent"
+	"github.com/confighub/cub-scout/pkg/query"
+)
+
+var (
+	incidentSince         string
+	incidentQuery         string
+	incidentIncludeSystem bool
+	incidentJSON          bool
+)
+
+var incidentCmd = &cobra.Command{
+	Use:   "incident",
+	Short: "Show everything that changed across the cluster, newest first",
+	Long: `Merge recent changes across namespaces into one reverse-chronological
+stream. This is the first command to run when paged:
+
+  - Field manager writes (managedFields), including manual kubectl changes
+  - Status condition transitions (Ready, Available, Stalled, ...)
+  - Deployer syncs (Flux Kustomization/HelmRelease and Argo CD history)
+  - Kubernetes Events
+
+Namespaces are selected with --query, matched against the namespace name
+and its labels. Without a query every namespace is included except those
+excluded by the namespace config (see --include-system). A deployer is
+included when its own namespace or the namespace it deploys into matches.
+
+Then drill into one resource with 'cub-scout timeline <kind/name>'.
+
+Examples:
+  cub-scout incident
+  cub-scout incident --since 2h
+  cub-scout incident --since 30m -q "namespace=payments*"
+  cub-scout incident -q "labels[team]=checkout" --json`,
+	Args: cobra.NoArgs,
+	RunE: runIncident,
+}
+
+func init() {
+	rootCmd.AddCommand(incidentCmd)
+	incidentCmd.Flags().StringVar(&incidentSince, "since", "2h", "How far back to look (e.g., 30m, 2h, 1d)")
+	incidentCmd.Flags().StringVarP(&incidentQuery, "query", "q", "", `Namespaces to include, e.g. "namespace=prod*" or "labels[team]=payments"`)
+	incidentCmd.Flags().BoolVar(&incidentIncludeSystem, "include-system", false, "Include namespaces excluded by the namespace config")
+	incidentCmd.Flags().BoolVar(&incidentJSON, "json", false, "Output as JSON")
+	_ = incidentCmd.RegisterFlagCompletionFunc("since", completeSince)
+}
+
+// incidentDeployerGVRs are the deployers whose sync history joins the stream.
+var incidentDeployerGVRs = []schema.GroupVersionResource{
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"},
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"},
+	{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+}
+
+// incidentScope decides which namespaces the stream covers.
+type incidentScope struct {
+	query         *query.Query
+	labels        map[string]map[string]string // namespace -> labels
+	includeSystem bool
+}
+
+func (s incidentScope) contains(namespace string) bool {
+	if namespace == "" {
+		return false
+	}
+	if s.query == nil {
+		return s.includeSystem || !isSystemNamespace(namespace)
+	}
+	return s.query.Matches(namespaceMatch{name: namespace, labels: s.labels[namespace]})
+}
+
+// namespaceMatch makes a namespace queryable by name and labels.
+type namespaceMatch struct {
+	name   string
+	labels map[string]string
+}
+
+func (n namespaceMatch) GetField(field string) (string, bool) {
+	if strings.HasPrefix(field, "labels[") && strings.HasSuffix(field, "]") {
+		v, ok := n.labels[field[len("labels["):len(field)-1]]
+		return v, ok
+	}
+	switch field {
+	case "namespace", "name":
+		return n.name, true
+	}
+	return "", false
+}
+
+func runIncident(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	window, err := parseDuration(incidentSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	since := time.Now().Add(-window)
+
+	scope := incidentScope{includeSystem: incidentIncludeSystem, labels: map[string]map[string]string{}}
+	if incidentQuery != "" {
+		if scope.query, err = query.Parse(resolveSavedQueries(incidentQuery)); err != nil {
+			return fmt.Errorf("invalid query: %w", err)
+		}
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	// Without list access to namespaces, label queries match nothing
+	for _, ns := range listAll(ctx, dynClient, schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}) {
+		scope.labels[ns.GetName()] = ns.GetLabels()
+	}
+
+	var objs, deployers []unstructured.Unstructured
+	for _, gvr := range mapListResources {
+		objs = append(objs, listAll(ctx, dynClient, gvr)...)
+	}
+	for _, gvr := range incidentDeployerGVRs {
+		deployers = append(deployers, listAll(ctx, dynClient, gvr)...)
+	}
+	events := listAll(ctx, dynClient, schema.GroupVersionResource{Version: "v1", Resource: "events"})
+
+	entries := buildIncidentStream(objs, deployers, events, scope, since)
+
+	if incidentJSON {
+		return writeJSON(os.Stdout, "IncidentStream", entries)
+	}
+	printIncidentStream(os.Stdout, incidentSince, incidentQuery, entries)
+	return nil
+}
+
+// buildIncidentStream merges changes to objs, deployer syncs and events in
+// scope since the given time, newest first.
+func buildIncidentStream(objs, deployers, events []unstructured.Unstructured, scope incidentScope, since time.Time) []TimelineEntry {
+	var entries []TimelineEntry
+	add := func(namespace, object string, es []TimelineEntry) {
+		for _, e := range es {
+			e.Namespace = namespace
+			if e.Object == "" {
+				e.Object = object
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	for i := range objs {
+		obj := &objs[i]
+		if !scope.contains(obj.GetNamespace()) || mapsvc.LastChanged(obj).Before(since) {
+			continue
+		}
+		object := obj.GetKind() + "/" + obj.GetName()
+		add(obj.GetNamespace(), object, timelineFromManagers(agent.ParseManagedFields(obj)))
+		add(obj.GetNamespace(), object, timelineFromConditions(obj))
+	}
+
+	for i := range deployers {
+		d := &deployers[i]
+		if !scope.contains(d.GetNamespace()) && !scope.contains(deployerTargetNamespace(d)) {
+			continue
+		}
+		rev := &DeployerRevision{Kind: d.GetKind(), Name: d.GetName(), Namespace: d.GetNamespace()}
+		owner := displayOwner(agent.OwnerFlux)
+		if d.GetKind() == "Application" {
+			owner = displayOwner(agent.OwnerArgo)
+			rev.History = argoHistory(d)
+		} else if data, err := json.Marshal(d.Object); err == nil {
+			rev.History, _ = agent.ParseFluxResourceHistory(data)
+		}
+		add(d.GetNamespace(), "", timelineFromDeployer(rev, owner))
+	}
+
+	for _, e := range timelineFromEvents(events, nil) {
+		if scope.contains(e.Namespace) {
+			entries = append(entries, e)
+		}
+	}
+
+	entries = sortTimeline(entries, since)
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries
+}
+
+// deployerTargetNamespace is the namespace a Flux or Argo CD deployer
+// applies into, or "" when it isn't set.
+func deployerTargetNamespace(d *unstructured.Unstructured) string {
+	if d.GetKind() == "Application" {
+		ns, _, _ := unstructured.NestedString(d.Object, "spec", "destination", "namespace")
+		return ns
+	}
+	ns, _, _ := unstructured.NestedString(d.Object, "spec", "targetNamespace")
+	return ns
+}
+
+// timelineFromConditions records each status condition's last transition.
+// Transitions into an unhealthy state are flagged as warnings.
+func timelineFromConditions(obj *unstructured.Unstructured) []TimelineEntry {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var entries []TimelineEntry
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ts, _ := cond["lastTransitionTime"].(string)
+		at, err := time.Parse(time.RFC3339, ts)
+		if err != nil {
+			continue
+		}
+		condType, _ := cond["type"].(string)
+		status, _ := cond["status"].(string)
+		msg := condType + "=" + status
+		if reason, _ := cond["reason"].(string); reason != "" {
+			msg += " (" + reason + ")"
+		}
+		if message, _ := cond["message"].(string); message != "" {
+			msg += ": " + truncate(message, 120)
+		}
+		entries = append(entries, TimelineEntry{
+			Time:    at,
+			Source:  "Status",
+			Message: msg,
+			Warning: conditionUnhealthy(condType, status),
+		})
+	}
+	return entries
+}
+
+// conditionUnhealthy reports whether a condition in this state signals a
+// problem: a positive condition turning False, or a negative one True.
+func conditionUnhealthy(condType, status string) bool {
+	switch condType {
+	case "Stalled", "Degraded", "ReplicaFailure":
+		return status == "True"
+	case "Ready", "Available", "Healthy", "Synced":
+		return status == "False"
+	}
+	return false
+}
+
+func printIncidentStream(w io.Writer, window, scope string, entries []TimelineEntry) {
+	if scope == "" {
+		scope = "all namespaces"
+	}
+	fmt.Fprintf(w, "\n%s%sINCIDENT:%s %s %s(last %s, newest first)%s\n\n", colorBold, colorCyan, colorReset, scope, colorDim, window, colorReset)
+
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "  Nothing changed in this window. Try a longer --since.")
+		fmt.Fprintln(w)
+		return
+	}
+
+	namespaces := map[string]bool{}
+	warnings := 0
+	var firstWarning *TimelineEntry
+	for i, e := range entries {
+		namespaces[e.Namespace] = true
+		sourceColor := colorCyan
+		switch e.Source {
+		case "Kubernetes", "Status":
+			sourceColor = colorDim
+		case "kubectl":
+			sourceColor = colorYellow
+		}
+		msgColor := ""
+		if e.Warning {
+			msgColor = colorYellow
+			warnings++
+			if firstWarning == nil {
+				firstWarning = &entries[i]
+			}
+		}
+		fmt.Fprintf(w, "  %s  %s%-11s%s %s/%s %s%s%s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05"),
+			sourceColor, e.Source, colorReset,
+			e.Namespace, e.Object, msgColor, e.Message, colorReset)
+	}
+
+	fmt.Fprintf(w, "\n%d events, %d warnings in %d namespaces\n", len(entries), warnings, len(namespaces))
+	if firstWarning != nil {
+		fmt.Fprintf(w, "%s→ Latest warning: cub-scout timeline %s -n %s%s\n", colorDim, firstWarning.Object, firstWarning.Namespace, colorReset)
+	}
+	fmt.Fprintln(w)
+}
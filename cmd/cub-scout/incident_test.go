@@ -0,0 +1,105 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+	"github.com/confighub/cub-scout/pkg/query"
+)
+
+func TestBuildIncidentStream(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(min int) time.Time { return now.Add(-time.Duration(min) * time.Minute) }
+	set := func(value interface{}, fields ...string) agenttest.Option {
+		return func(u *unstructured.Unstructured) { _ = unstructured.SetNestedField(u.Object, value, fields...) }
+	}
+	created := func(at time.Time) agenttest.Option {
+		return func(u *unstructured.Unstructured) { u.SetCreationTimestamp(metav1.NewTime(at)) }
+	}
+	editedAt := func(at time.Time) agenttest.Option {
+		return func(u *unstructured.Unstructured) {
+			u.SetManagedFields([]metav1.ManagedFieldsEntry{
+				{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: at}},
+			})
+		}
+	}
+
+	objs := []unstructured.Unstructured{
+		*agenttest.Deployment("payments", "api", created(ago(600)), editedAt(ago(30)),
+			set([]interface{}{
+				map[string]interface{}{"type": "Available", "status": "False", "reason": "MinimumReplicasUnavailable", "lastTransitionTime": ago(25).Format(time.RFC3339)},
+			}, "status", "conditions")),
+		*agenttest.Deployment("payments", "quiet", created(ago(600))), // nothing in the window
+		*agenttest.Deployment("web", "frontend", created(ago(600)), editedAt(ago(10))),
+	}
+	deployers := []unstructured.Unstructured{
+		*agenttest.Object("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "payments",
+			set("payments", "spec", "targetNamespace"),
+			set([]interface{}{
+				map[string]interface{}{"lastReconciled": ago(35).Format(time.RFC3339), "lastReconciledStatus": "ReconciliationSucceeded", "metadata": map[string]interface{}{"revision": "main@sha1:abc"}},
+			}, "status", "history")),
+	}
+	events := []unstructured.Unstructured{
+		*agenttest.Object("v1", "Event", "payments", "api.1",
+			set(map[string]interface{}{"kind": "Pod", "name": "api-7d9"}, "involvedObject"),
+			set("BackOff", "reason"), set("Warning", "type"), set("restarting", "message"),
+			set(ago(20).Format(time.RFC3339), "lastTimestamp")),
+		*agenttest.Object("v1", "Event", "web", "frontend.1",
+			set(map[string]interface{}{"kind": "Pod", "name": "frontend-1"}, "involvedObject"),
+			set("Pulled", "reason"), set("Normal", "type"),
+			set(ago(5).Format(time.RFC3339), "lastTimestamp")),
+	}
+
+	q, err := query.Parse("namespace=pay*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := incidentScope{query: q}
+	entries := buildIncidentStream(objs, deployers, events, scope, ago(120))
+
+	want := []struct{ source, object string }{
+		{"Kubernetes", "Pod/api-7d9"},
+		{"Status", "Deployment/api"},
+		{"kubectl", "Deployment/api"},
+		{"Flux", "Kustomization/payments"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		if entries[i].Source != w.source || entries[i].Object != w.object {
+			t.Errorf("entries[%d] = %s %s, want %s %s", i, entries[i].Source, entries[i].Object, w.source, w.object)
+		}
+	}
+	if !entries[1].Warning || entries[3].Warning {
+		t.Errorf("warning flags: %+v", entries)
+	}
+	if entries[3].Namespace != "flux-system" {
+		t.Errorf("deployer namespace = %q, want flux-system", entries[3].Namespace)
+	}
+}
+
+func TestIncidentScopeLabels(t *testing.T) {
+	q, err := query.Parse("labels[team]=checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := incidentScope{query: q, labels: map[string]map[string]string{
+		"cart":     {"team": "checkout"},
+		"payments": {"team": "billing"},
+	}}
+	if !scope.contains("cart") || scope.contains("payments") || scope.contains("") {
+		t.Error("label query: want only cart in scope")
+	}
+
+	if all := (incidentScope{}); !all.contains("payments") || all.contains("kube-system") {
+		t.Error("no query: want user namespaces in scope and system namespaces out")
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	initSpaceTemplate string
+	initSpaceName     string
+	initSpaceEnvs     []string
+	initSpaceDryRun   bool
+	initSpaceYes      bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Bootstrap ConfigHub layouts",
+	Long:  `Bootstrap ConfigHub layouts from templates inferred from the current cluster.`,
+}
+
+var initSpaceCmd = &cobra.Command{
+	Use:   "space",
+	Short: "Bootstrap a Hub/AppSpace layout: spaces, labels, worker and target",
+	Long: `Create a best-practice ConfigHub layout for the current cluster from a
+template. The layout follows the Hub/AppSpace patterns the hierarchy TUI
+detects (press B in map --hub):
+
+  hub-appspace      <name>-base and <name>-infra hub spaces, one
+                    <name>-<env> AppSpace per environment. The worker and
+                    target for this cluster live in <name>-infra.
+  platform-per-env  One platform-<env> hub space per environment holding
+                    workers and targets, and one <name>-<env> AppSpace each.
+
+Inferred from the current cluster (override with flags):
+
+  --name  the cluster name (from the kubectl context)
+  --envs  environments found in namespace names (dev, staging, prod);
+          dev and prod when none are found
+  Deployer label: Flux or ArgoCD, when their objects are present
+
+Every space gets Role=hub or Role=appspace labels, and AppSpaces get
+Environment and Deployer labels. Commands use --allow-exists, so re-running
+is safe. The plan is shown and confirmed before anything is created.
+
+Examples:
+  cub-scout init space --dry-run
+  cub-scout init space --name payments
+  cub-scout init space --template platform-per-env --envs dev,prod --yes`,
+	Args: cobra.NoArgs,
+	RunE: runInitSpace,
+}
+
+func init() {
+	initSpaceCmd.Flags().StringVar(&initSpaceTemplate, "template", spaceTemplateHubAppSpace, "Layout template: hub-appspace, platform-per-env")
+	initSpaceCmd.Flags().StringVar(&initSpaceName, "name", "", "Name prefix for the spaces (default: cluster name)")
+	initSpaceCmd.Flags().StringSliceVar(&initSpaceEnvs, "envs", nil, "Environments to create AppSpaces for (default: inferred from namespaces)")
+	initSpaceCmd.Flags().BoolVar(&initSpaceDryRun, "dry-run", false, "Print the plan and cub commands without running them")
+	initSpaceCmd.Flags().BoolVarP(&initSpaceYes, "yes", "y", false, "Skip confirmation")
+	_ = initSpaceCmd.RegisterFlagCompletionFunc("template", cobra.FixedCompletions(
+		[]string{spaceTemplateHubAppSpace, spaceTemplatePlatformPerEnv}, cobra.ShellCompDirectiveNoFileComp))
+	initCmd.AddCommand(initSpaceCmd)
+	rootCmd.AddCommand(initCmd)
+}
+
+// Space layout templates.
+const (
+	spaceTemplateHubAppSpace    = "hub-appspace"
+	spaceTemplatePlatformPerEnv = "platform-per-env"
+)
+
+// spaceInputs is what a template needs to know about the cluster.
+type spaceInputs struct {
+	Name        string   // space name prefix
+	Cluster     string   // cluster name, used for the worker and target
+	KubeContext string   // kubectl context the target deploys to
+	Envs        []string // environments, in promotion order
+	Deployer    string   // Flux, ArgoCD, or "" when neither was found
+}
+
+// spaceStep is one cub command in a bootstrap plan.
+type spaceStep struct {
+	Change plannedChange
+	Args   []string
+}
+
+// spacePlan is the ordered set of commands a template expands to.
+type spacePlan struct {
+	Steps     []spaceStep
+	HubSpace  string // where this cluster's worker and target live
+	Worker    string
+	AppSpaces []string
+}
+
+// buildSpacePlan expands a template into cub commands.
+func buildSpacePlan(template string, in spaceInputs) (spacePlan, error) {
+	if in.Name == "" {
+		return spacePlan{}, fmt.Errorf("no space name: pass --name")
+	}
+	if len(in.Envs) == 0 {
+		return spacePlan{}, fmt.Errorf("no environments: pass --envs")
+	}
+	var plan spacePlan
+	createSpace := func(name string, labels ...string) {
+		args := []string{"space", "create", "--allow-exists", name}
+		for _, l := range labels {
+			args = append(args, "--label", l)
+		}
+		plan.Steps = append(plan.Steps, spaceStep{
+			Change: plannedChange{Action: "create space", Target: name, Detail: strings.Join(labels, ", ")},
+			Args:   args,
+		})
+	}
+	appSpaceLabels := func(env string) []string {
+		labels := []string{"Role=appspace", "Environment=" + env}
+		if in.Deployer != "" {
+			labels = append(labels, "Deployer="+in.Deployer)
+		}
+		return labels
+	}
+
+	switch template {
+	case spaceTemplateHubAppSpace:
+		createSpace(in.Name+"-base", "Role=hub")
+		createSpace(in.Name+"-infra", "Role=hub")
+		plan.HubSpace = in.Name + "-infra"
+	case spaceTemplatePlatformPerEnv:
+		clusterEnv := in.Envs[len(in.Envs)-1]
+		for _, env := range in.Envs {
+			if inferEnv(in.Cluster) == env {
+				clusterEnv = env
+			}
+		}
+		for _, env := range in.Envs {
+			createSpace("platform-"+env, "Role=hub", "Environment="+env)
+		}
+		plan.HubSpace = "platform-" + clusterEnv
+	default:
+		return spacePlan{}, fmt.Errorf("unknown template %q: want %s or %s", template, spaceTemplateHubAppSpace, spaceTemplatePlatformPerEnv)
+	}
+	for _, env := range in.Envs {
+		name := in.Name + "-" + env
+		createSpace(name, appSpaceLabels(env)...)
+		plan.AppSpaces = append(plan.AppSpaces, name)
+	}
+
+	if in.Cluster != "" {
+		plan.Worker = in.Cluster + "-worker"
+		plan.Steps = append(plan.Steps, spaceStep{
+			Change: plannedChange{Action: "create worker", Target: plan.HubSpace + "/" + plan.Worker},
+			Args:   []string{"worker", "create", plan.Worker, "--space", plan.HubSpace, "--allow-exists"},
+		})
+		params, _ := json.Marshal(map[string]string{"KubeContext": in.KubeContext})
+		plan.Steps = append(plan.Steps, spaceStep{
+			Change: plannedChange{Action: "create target", Target: plan.HubSpace + "/" + in.Cluster, Detail: "KubeContext=" + in.KubeContext + ", worker " + plan.Worker},
+			Args:   []string{"target", "create", in.Cluster, string(params), plan.Worker, "--space", plan.HubSpace, "--provider", "Kubernetes", "--allow-exists"},
+		})
+	}
+	return plan, nil
+}
+
+// envsFromGroups turns namespace env groups (see detectEnvGroups) into
+// environments in promotion order.
+func envsFromGroups(groups map[string][]string) []string {
+	var envs []string
+	for group, env := range map[string]string{"dev": "dev", "staging": "staging", "production": "prod"} {
+		if len(groups[group]) > 0 {
+			envs = append(envs, env)
+		}
+	}
+	sort.Slice(envs, func(i, j int) bool { return envPriority(envs[i]) < envPriority(envs[j]) })
+	return envs
+}
+
+// inferSpaceInputs fills in what the flags leave out from the current
+// cluster. An unreachable cluster still yields a plan from the flags and
+// defaults.
+func inferSpaceInputs(cmd *cobra.Command) spaceInputs {
+	in := spaceInputs{
+		Name:        initSpaceName,
+		KubeContext: getCurrentContext(),
+		Envs:        initSpaceEnvs,
+	}
+	if in.KubeContext == "unknown" {
+		in.KubeContext = "" // no kubeconfig: plan spaces only, no worker or target
+	}
+	if in.KubeContext != "" {
+		in.Cluster = activeClusterConfig().Resolve(in.KubeContext).Name
+	}
+	if in.Name == "" {
+		in.Name = in.Cluster
+	}
+
+	if cfg, err := buildConfig(); err == nil {
+		if client, err := dynamic.NewForConfig(cfg); err == nil {
+			ctx := cmd.Context()
+			if len(in.Envs) == 0 {
+				in.Envs = envsFromGroups(detectEnvGroups(ctx, client))
+			}
+			switch {
+			case len(collectFluxKustomizations(ctx, client)) > 0:
+				in.Deployer = "Flux"
+			case len(collectArgoApps(ctx, client)) > 0:
+				in.Deployer = "ArgoCD"
+			}
+		}
+	}
+	if len(in.Envs) == 0 {
+		in.Envs = []string{"dev", "prod"}
+	}
+	return in
+}
+
+func runInitSpace(cmd *cobra.Command, args []string) error {
+	in := inferSpaceInputs(cmd)
+	plan, err := buildSpacePlan(initSpaceTemplate, in)
+	if err != nil {
+		return err
+	}
+
+	deployer := in.Deployer
+	if deployer == "" {
+		deployer = "none found"
+	}
+	fmt.Printf("Template %s for cluster %s (envs: %s, deployer: %s)\n",
+		initSpaceTemplate, orDash(in.Cluster), strings.Join(in.Envs, ", "), deployer)
+
+	changes := make([]plannedChange, 0, len(plan.Steps))
+	for _, s := range plan.Steps {
+		changes = append(changes, s.Change)
+	}
+	if initSpaceDryRun {
+		printPlannedChanges(os.Stdout, changes)
+		fmt.Println("\nCommands:")
+		for _, s := range plan.Steps {
+			fmt.Println("  cub " + shellJoin(s.Args))
+		}
+		return nil
+	}
+
+	if err := checkCubAuth(cmd.CommandPath()); err != nil {
+		return err
+	}
+	ok, err := confirmChanges(changes, initSpaceYes)
+	if err != nil || !ok {
+		return err
+	}
+	for i, s := range plan.Steps {
+		if _, err := runCubCommand(s.Args...); err != nil {
+			return fmt.Errorf("%s %s (step %d of %d): %w", s.Change.Action, s.Change.Target, i+1, len(plan.Steps), err)
+		}
+		fmt.Printf("%s✓%s %s %s\n", colorGreen, colorReset, s.Change.Action, s.Change.Target)
+	}
+
+	fmt.Println()
+	if plan.Worker != "" {
+		fmt.Printf("Next: start the worker so the target comes online:\n  cub worker run %s --space %s\n", plan.Worker, plan.HubSpace)
+		fmt.Printf("  or install it in-cluster:\n  cub worker install %s --space %s --export --include-secret | kubectl apply -f -\n", plan.Worker, plan.HubSpace)
+	}
+	fmt.Printf("Then import workloads into an AppSpace:\n  cub context set --space %s && cub-scout import\n", plan.AppSpaces[0])
+	return nil
+}
+
+// shellJoin quotes args that need it for copy-pasting into a shell.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if strings.ContainsAny(a, " \"'{}$") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
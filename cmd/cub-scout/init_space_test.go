@@ -0,0 +1,83 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildSpacePlanHubAppSpace(t *testing.T) {
+	plan, err := buildSpacePlan(spaceTemplateHubAppSpace, spaceInputs{
+		Name: "payments", Cluster: "prod-east", KubeContext: "kind-prod-east",
+		Envs: []string{"dev", "prod"}, Deployer: "Flux",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, s := range plan.Steps {
+		got = append(got, shellJoin(s.Args))
+	}
+	want := []string{
+		"space create --allow-exists payments-base --label Role=hub",
+		"space create --allow-exists payments-infra --label Role=hub",
+		"space create --allow-exists payments-dev --label Role=appspace --label Environment=dev --label Deployer=Flux",
+		"space create --allow-exists payments-prod --label Role=appspace --label Environment=prod --label Deployer=Flux",
+		"worker create prod-east-worker --space payments-infra --allow-exists",
+		`target create prod-east '{"KubeContext":"kind-prod-east"}' prod-east-worker --space payments-infra --provider Kubernetes --allow-exists`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("steps =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if plan.HubSpace != "payments-infra" || !reflect.DeepEqual(plan.AppSpaces, []string{"payments-dev", "payments-prod"}) {
+		t.Errorf("hub = %q, appspaces = %v", plan.HubSpace, plan.AppSpaces)
+	}
+	for _, s := range plan.Steps {
+		if s.Change.Cluster {
+			t.Errorf("%s %s should be a ConfigHub change, not a cluster write", s.Change.Action, s.Change.Target)
+		}
+	}
+}
+
+func TestBuildSpacePlanPlatformPerEnv(t *testing.T) {
+	plan, err := buildSpacePlan(spaceTemplatePlatformPerEnv, spaceInputs{
+		Name: "shop", Cluster: "staging-1", KubeContext: "staging-1",
+		Envs: []string{"dev", "staging", "prod"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.HubSpace != "platform-staging" {
+		t.Errorf("worker should go to the cluster's env hub, got %q", plan.HubSpace)
+	}
+	if len(plan.Steps) != 3+3+2 {
+		t.Errorf("got %d steps, want 3 hub spaces, 3 appspaces, worker and target", len(plan.Steps))
+	}
+	if got := shellJoin(plan.Steps[3].Args); strings.Contains(got, "Deployer=") {
+		t.Errorf("no Deployer label without a detected deployer: %s", got)
+	}
+}
+
+func TestBuildSpacePlanErrors(t *testing.T) {
+	if _, err := buildSpacePlan("fleet", spaceInputs{Name: "x", Envs: []string{"dev"}}); err == nil || !strings.Contains(err.Error(), "unknown template") {
+		t.Errorf("unknown template err = %v", err)
+	}
+	if _, err := buildSpacePlan(spaceTemplateHubAppSpace, spaceInputs{Envs: []string{"dev"}}); err == nil {
+		t.Error("missing name should be an error")
+	}
+	plan, err := buildSpacePlan(spaceTemplateHubAppSpace, spaceInputs{Name: "x", Envs: []string{"dev"}})
+	if err != nil || plan.Worker != "" || len(plan.Steps) != 3 {
+		t.Errorf("no cluster should skip worker and target: %+v, %v", plan, err)
+	}
+}
+
+func TestEnvsFromGroups(t *testing.T) {
+	got := envsFromGroups(map[string][]string{"production": {"shop-prod"}, "dev": {"shop-dev"}, "team": {"team-a"}})
+	if !reflect.DeepEqual(got, []string{"dev", "prod"}) {
+		t.Errorf("envsFromGroups = %v, want [dev prod]", got)
+	}
+}
@@ -110,7 +110,8 @@ var savedQueries = []SavedQuery{
 type LocalClusterModel struct {
 	entries     []MapEntry
 	gitops      []GitOpsResource
-	gitSources  []GitSourceInfo // Git sources (GitRepository, OCIRepository, HelmRepository)
+	gitSources  []GitSourceInfo     // Git sources (GitRepository, OCIRepository, HelmRepository)
+	delegated   []DelegatedPipeline // Delegated apply pipelines (ConfigHub OCI → Flux/Argo)
 	width       int
 	height      int
 	ready       bool
@@ -257,6 +258,7 @@ const (
 	viewGitSources   // GitOps Sources view (Git repos → deployers → resources)
 	viewClusterData  // Cluster Data view (all data sources TUI reads)
 	viewAppHierarchy // App Hierarchy view (inferred ConfigHub model)
+	viewDelegated    // Delegated apply pipelines (ConfigHub → OCI → Flux/Argo)
 )
 
 type localKeyMap struct {
@@ -299,6 +301,8 @@ type localKeyMap struct {
 	ClusterData key.Binding
 	// App Hierarchy view (new)
 	AppHierarchy key.Binding
+	// Delegated apply pipelines view
+	Delegated key.Binding
 	// Namespace navigation
 	NextNamespace key.Binding
 	PrevNamespace key.Binding
@@ -337,6 +341,7 @@ func defaultLocalKeyMap() localKeyMap {
 		GitSources:    key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "git sources")),
 		ClusterData:   key.NewBinding(key.WithKeys("4"), key.WithHelp("4", "cluster data")),
 		AppHierarchy:  key.NewBinding(key.WithKeys("5", "A"), key.WithHelp("5/A", "app hierarchy")),
+		Delegated:     key.NewBinding(key.WithKeys("6"), key.WithHelp("6", "delegated")),
 		NextNamespace: key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next ns")),
 		PrevNamespace: key.NewBinding(key.WithKeys("["), key.WithHelp("[", "prev ns")),
 		Command:       key.NewBinding(key.WithKeys(":"), key.WithHelp(":", "command")),
@@ -349,11 +354,13 @@ type localDataLoadedMsg struct {
 	gitops       []GitOpsResource
 	gitSources   []GitSourceInfo // Git sources (GitRepository, etc.)
 	detectedApps []string        // App names detected from namespaces
+	delegated    []DelegatedPipeline
 	err          error
 }
 
 type localAuthCheckMsg struct {
 	authenticated bool
+	cubMissing    bool // cub is not installed, so there is no hub to switch to
 }
 
 // connectionStatusMsg carries connection status from async check
@@ -410,10 +417,7 @@ func initialLocalModel() LocalClusterModel {
 	vp := viewport.New(40, 20)
 	vp.MouseWheelEnabled = true
 
-	clusterName := os.Getenv("CLUSTER_NAME")
-	if clusterName == "" {
-		clusterName = "default"
-	}
+	clusterName := currentClusterName()
 
 	contextName := getCurrentContext()
 
@@ -467,11 +471,9 @@ func loadLocalClusterData() tea.Msg {
 	if err != nil {
 		return localDataLoadedMsg{err: fmt.Errorf("create dynamic client: %w", err)}
 	}
+	loadDelegations(ctx, dynClient)
 
-	clusterName := os.Getenv("CLUSTER_NAME")
-	if clusterName == "" {
-		clusterName = "default"
-	}
+	clusterName := currentClusterName()
 
 	var entries []MapEntry
 	var gitops []GitOpsResource
@@ -585,8 +587,7 @@ func loadLocalClusterData() tea.Msg {
 	for _, e := range entries {
 		ns := e.Namespace
 		// Skip system namespaces
-		if ns == "" || ns == "kube-system" || ns == "kube-public" || ns == "default" ||
-			ns == "flux-system" || ns == "argocd" || ns == "cert-manager" {
+		if ns == "" || ns == "default" || isSystemNamespace(ns) {
 			continue
 		}
 		// Extract base app name (remove -prod, -dev, -staging suffixes)
@@ -605,7 +606,9 @@ func loadLocalClusterData() tea.Msg {
 	}
 	sort.Strings(detectedApps)
 
-	return localDataLoadedMsg{entries: entries, gitops: gitops, gitSources: gitSources, detectedApps: detectedApps}
+	delegated := collectDelegatedPipelines(ctx, dynClient, entries, clusterName, false)
+
+	return localDataLoadedMsg{entries: entries, gitops: gitops, gitSources: gitSources, detectedApps: detectedApps, delegated: delegated}
 }
 
 func parseFluxKustomization(item *unstructured.Unstructured) GitOpsResource {
@@ -872,6 +875,9 @@ func parseFluxHelmRepository(item *unstructured.Unstructured) GitSourceInfo {
 }
 
 func checkCubAuthForSwitch() tea.Msg {
+	if !cubInstalled() {
+		return localAuthCheckMsg{cubMissing: true}
+	}
 	_, err := runCubCommand("context", "get")
 	return localAuthCheckMsg{authenticated: err == nil}
 }
@@ -881,14 +887,18 @@ func checkConnectionStatus(clusterName string) tea.Cmd {
 	return func() tea.Msg {
 		msg := connectionStatusMsg{mode: "offline"}
 
+		if !cubInstalled() {
+			return msg
+		}
+
 		// Check cub context
-		out, err := exec.Command("cub", "context", "get", "--json").Output()
+		out, err := runCubCommand("context", "get", "--json")
 		if err != nil {
 			// Try without --json for older cub versions
-			out, err = exec.Command("cub", "context", "get").Output()
+			out, err = runCubCommand("context", "get")
 			if err != nil {
 				// Not connected, check if online
-				if _, verr := exec.Command("cub", "--version").Output(); verr == nil {
+				if _, verr := runCubCommand("--version"); verr == nil {
 					msg.mode = "online"
 				}
 				return msg
@@ -919,7 +929,7 @@ func checkConnectionStatus(clusterName string) tea.Cmd {
 
 			// Try to get worker status for this cluster
 			if ctx.Space != "" {
-				if wout, werr := exec.Command("cub", "worker", "list", "--space", ctx.Space, "--json").Output(); werr == nil {
+				if wout, werr := runCubCommand("worker", "list", "--space", ctx.Space, "--json"); werr == nil {
 					var workers []struct {
 						Name      string `json:"name"`
 						Cluster   string `json:"cluster"`
@@ -971,6 +981,7 @@ func (m LocalClusterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.gitops = msg.gitops
 			m.gitSources = msg.gitSources
 			m.detectedApps = msg.detectedApps
+			m.delegated = msg.delegated
 			// Set default hub context to first detected app
 			if len(msg.detectedApps) > 0 {
 				m.hubContext = msg.detectedApps[0]
@@ -991,6 +1002,10 @@ func (m LocalClusterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case localAuthCheckMsg:
+		if msg.cubMissing {
+			m.statusMsg = cubMissingNote("ConfigHub mode")
+			return m, nil
+		}
 		if msg.authenticated {
 			m.switchToHub = true
 			saveSnapshot(&m)
@@ -1376,6 +1391,9 @@ func (m LocalClusterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				case key.Matches(msg, m.keymap.AppHierarchy):
 					m.panelView = viewAppHierarchy
 					m.updatePanelContent()
+				case key.Matches(msg, m.keymap.Delegated):
+					m.panelView = viewDelegated
+					m.updatePanelContent()
 				case key.Matches(msg, m.keymap.Maps):
 					m.panelView = viewMaps
 					m.updatePanelContent()
@@ -1514,6 +1532,12 @@ func (m LocalClusterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.updatePanelContent()
 			return m, nil
 
+		case key.Matches(msg, m.keymap.Delegated):
+			m.panelMode = true
+			m.panelView = viewDelegated
+			m.updatePanelContent()
+			return m, nil
+
 		case key.Matches(msg, m.keymap.Maps):
 			m.panelMode = true
 			m.panelView = viewMaps
@@ -1557,6 +1581,10 @@ func (m LocalClusterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.runScan()
 
 		case key.Matches(msg, m.keymap.Import):
+			if !cubInstalled() {
+				m.statusMsg = cubMissingNote("import")
+				return m, nil
+			}
 			m.switchToImport = true
 			saveSnapshot(&m)
 			return m, tea.Quit
@@ -1752,6 +1780,8 @@ func (m *LocalClusterModel) updatePanelContent() {
 		content = m.getPanelClusterData()
 	case viewAppHierarchy:
 		content = m.getPanelAppHierarchy()
+	case viewDelegated:
+		content = m.getPanelDelegated()
 	case viewMaps:
 		content = m.getPanelMaps()
 	}
@@ -1790,6 +1820,8 @@ func (m LocalClusterModel) getPanelTitle() string {
 		return "CLUSTER DATA"
 	case viewAppHierarchy:
 		return "APP HIERARCHY"
+	case viewDelegated:
+		return "DELEGATED APPLY"
 	case viewMaps:
 		return "MAPS"
 	default:
@@ -2117,6 +2149,7 @@ func (m LocalClusterModel) renderHelp() string {
 	b.WriteString("  " + lcNameStyle.Render("G") + "  Git sources (forward trace)\n")
 	b.WriteString("  " + lcNameStyle.Render("4") + "  Cluster Data (all data sources TUI reads)\n")
 	b.WriteString("  " + lcNameStyle.Render("5/A") + "  App Hierarchy (inferred ConfigHub model)\n")
+	b.WriteString("  " + lcNameStyle.Render("6") + "  Delegated apply (ConfigHub → OCI → Flux/Argo)\n")
 	b.WriteString("  " + lcNameStyle.Render("M") + "  Three Maps view\n")
 	b.WriteString("  " + lcNameStyle.Render("Tab") + "  Cycle views\n")
 	b.WriteString("\n")
@@ -2970,8 +3003,7 @@ func extractAppFromNamespace(namespace string) string {
 	ns := strings.ToLower(namespace)
 
 	// Skip system namespaces
-	if ns == "default" || ns == "kube-system" || ns == "kube-public" ||
-		ns == "flux-system" || ns == "argocd" || ns == "cert-manager" {
+	if ns == "default" || isSystemNamespace(ns) {
 		return ""
 	}
 
@@ -3672,6 +3704,13 @@ func (m LocalClusterModel) getPanelClusterData() string {
 					}
 					b.WriteString(fmt.Sprintf("%s Unit: %s\n", childPrefix, lcDimStyle.Render(unitSlug)))
 				}
+				if via := e.OwnerDetails["delegatedVia"]; via != "" {
+					childPrefix := "│   └──"
+					if count == configHubResources {
+						childPrefix = "    └──"
+					}
+					b.WriteString(fmt.Sprintf("%s Delegated via %s: %s\n", childPrefix, via, lcDimStyle.Render(e.OwnerDetails["deployer"])))
+				}
 			}
 		}
 		b.WriteString("\n")
@@ -3774,6 +3813,38 @@ func formatAge(d time.Duration) string {
 	return fmt.Sprintf("%d weeks", weeks)
 }
 
+// getPanelDelegated returns the delegated apply pipelines view content
+func (m LocalClusterModel) getPanelDelegated() string {
+	var b strings.Builder
+
+	b.WriteString(lcSectionStyle.Render("DELEGATED APPLY") + lcDimStyle.Render(" (ConfigHub → OCI → Flux/Argo → cluster)") + "\n\n")
+	if len(m.delegated) == 0 {
+		b.WriteString(lcDimStyle.Render("  No delegated pipelines found") + "\n")
+		b.WriteString(lcDimStyle.Render("  Flux OCIRepositories or Argo Applications reading a ConfigHub OCI URL appear here") + "\n")
+		return b.String()
+	}
+
+	for _, p := range m.delegated {
+		statusIcon := lcOkStyle.Render("✓")
+		if p.Health != pipelineHealthy {
+			statusIcon = lcWarnStyle.Render("⚠")
+		}
+		b.WriteString(fmt.Sprintf("%s %s → %s %s\n", statusIcon, lcNameStyle.Render(p.Space+"/"+p.Target), p.Deployer, lcDimStyle.Render("("+p.Health+")")))
+		if len(p.Units) > 0 {
+			b.WriteString(fmt.Sprintf("│  Units: %s\n", lcDimStyle.Render(strings.Join(p.Units, ", "))))
+		}
+		b.WriteString(fmt.Sprintf("│  OCI: %s\n", lcDimStyle.Render(p.OCIURL)))
+		b.WriteString(fmt.Sprintf("│  Registry: %s  Applied: %s\n", lcDimStyle.Render(shortRevision(p.RegistryRevision)), lcDimStyle.Render(shortRevision(p.AppliedRevision))))
+		if p.Message != "" {
+			b.WriteString(fmt.Sprintf("└─ %s\n", lcWarnStyle.Render(p.Message)))
+		} else {
+			b.WriteString(fmt.Sprintf("└─ %d resources\n", p.Resources))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 // getPanelAppHierarchy returns the App Hierarchy view content
 // Shows TUI's best-effort interpretation of cluster in ConfigHub model with full LiveTree
 func (m LocalClusterModel) getPanelAppHierarchy() string {
@@ -3972,7 +4043,7 @@ func (m LocalClusterModel) getPanelAppHierarchy() string {
 			env = "staging"
 		} else if strings.Contains(ns, "dev") || strings.Contains(ns, "development") {
 			env = "development"
-		} else if ns != "kube-system" && ns != "flux-system" && ns != "argocd" && ns != "default" {
+		} else if ns != "default" && !isSystemNamespace(ns) {
 			env = "other"
 		} else {
 			continue
@@ -582,6 +582,8 @@ func TestLocalClusterQuery(t *testing.T) {
 
 // TestLocalClusterImport tests 'I' key for import wizard.
 func TestLocalClusterImport(t *testing.T) {
+	// Without cub, I shows a note instead of quitting
+	withCub(t)
 	m := testLocalModel()
 
 	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(80, 24))
@@ -907,3 +909,22 @@ func TestSnapshotExpiry(t *testing.T) {
 		t.Error("expected nil for expired snapshot, but got a snapshot")
 	}
 }
+
+// TestLocalClusterDelegated tests '6' key for delegated apply view.
+func TestLocalClusterDelegated(t *testing.T) {
+	m := testLocalModel()
+
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(80, 24))
+	time.Sleep(50 * time.Millisecond)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'6'}})
+	time.Sleep(50 * time.Millisecond)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	finalModel := tm.FinalModel(t, teatest.WithFinalTimeout(2*time.Second))
+
+	fm := finalModel.(LocalClusterModel)
+	if !fm.panelMode || fm.panelView != viewDelegated {
+		t.Errorf("expected delegated view, got panelMode=%v view=%v", fm.panelMode, fm.panelView)
+	}
+}
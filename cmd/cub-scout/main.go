@@ -6,7 +6,10 @@ package main
 
 import (
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -37,8 +40,13 @@ It provides commands for:
 Works standalone or connected to ConfigHub for additional features.
 
 Environment Variables:
-  CLUSTER_NAME            Name for this cluster (default: default)
-  KUBECONFIG              Path to kubeconfig file (default: ~/.kube/config)
+  CLUSTER_NAME                 Name for this cluster (default: from ~/.cub-scout/clusters.yaml or the context)
+  CUB_SCOUT_CLUSTERS           Cluster registry file (default: ~/.cub-scout/clusters.yaml)
+  KUBECONFIG                   Path to kubeconfig file (default: ~/.kube/config)
+  OTEL_EXPORTER_OTLP_ENDPOINT  Export OpenTelemetry traces over OTLP/HTTP
+  CUB_SCOUT_READ_ONLY          true blocks every cluster write (see --read-only)
+  CUB_SCOUT_GITHUB_HOSTS       GitHub Enterprise hosts trusted with GITHUB_TOKEN (comma-separated)
+  CUB_SCOUT_GITLAB_HOSTS       Self-managed GitLab hosts trusted with GITLAB_TOKEN (comma-separated)
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("cub-scout - explore and map GitOps in your clusters")
@@ -53,7 +61,9 @@ Environment Variables:
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	finishTelemetry(err)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -113,22 +123,29 @@ PowerShell:
 	})
 }
 
-// buildConfig builds a Kubernetes client config
+// buildConfig builds a Kubernetes client config. API requests are traced
+// and logged at debug level, and writes are refused in read-only mode.
 func buildConfig() (*rest.Config, error) {
 	// Try in-cluster config first
 	cfg, err := rest.InClusterConfig()
-	if err == nil {
-		return cfg, nil
-	}
-
-	// Fall back to kubeconfig
-	kubeconfig := os.Getenv("KUBECONFIG")
-	if kubeconfig == "" {
-		home, _ := os.UserHomeDir()
-		kubeconfig = home + "/.kube/config"
+	if err != nil {
+		// Fall back to kubeconfig
+		kubeconfig := os.Getenv("KUBECONFIG")
+		if kubeconfig == "" {
+			home, _ := os.UserHomeDir()
+			kubeconfig = home + "/.kube/config"
+		}
+
+		cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return clientcmd.BuildConfigFromFlags("", kubeconfig)
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &tracingRoundTripper{next: &readOnlyRoundTripper{next: rt}}
+	})
+	return cfg, nil
 }
 
 // getCurrentContext returns the current kubectl context name
@@ -159,3 +176,27 @@ func formatDuration(d time.Duration) string {
 	}
 	return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
 }
+
+// parseDuration parses a positive duration given as whole days or weeks
+// ("7d", "2w") or as a Go duration ("30m", "36h"). Flags taking a window or
+// an expiry, such as --since and --expires, all accept this form.
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count <= 0 {
+				return 0, fmt.Errorf("invalid duration %q: want a positive number of days or weeks, e.g. 7d or 2w", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q: must be positive", s)
+	}
+	return d, nil
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -23,11 +24,14 @@ import (
 	"github.com/spf13/cobra"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 
 	"github.com/confighub/cub-scout/internal/mapsvc"
 	"github.com/confighub/cub-scout/pkg/agent"
+	"github.com/confighub/cub-scout/pkg/explain"
 	"github.com/confighub/cub-scout/pkg/queries"
 	"github.com/confighub/cub-scout/pkg/query"
 )
@@ -40,13 +44,27 @@ var (
 	mapJSON           bool
 	mapVerbose        bool
 	mapHub            bool   // --hub flag for ConfigHub hierarchy
+	mapHubFocus       string // --focus location to open the hub TUI at
 	mapSince          string // --since flag for time filtering
 	mapCount          bool   // --count flag for count-only output
 	mapNamesOnly      bool   // --names-only flag for names-only output
-	mapExplain        bool   // --explain flag for learning mode
+	mapListFormat     string // --format for map list: table, json or ndjson
+	mapSelector       string // --selector (-l), sent to the API server
+	mapFieldSelector  string // --field-selector, sent to the API server
+	mapExplain        string // --explain[=verbose] flag for learning mode
 	deepDiveConnected bool   // --connected flag for ConfigHub integration in deep-dive
 )
 
+// mapTimeout bounds listing in map list and deep-dive (--timeout)
+var mapTimeout time.Duration
+
+var (
+	// mapShowAcknowledged keeps acknowledged orphans in map orphans (--show-acknowledged)
+	mapShowAcknowledged bool
+	// mapHideAcknowledged drops acknowledged Native entries from runMapList
+	mapHideAcknowledged bool
+)
+
 // MapEntry is an alias for mapsvc.Entry representing a resource in the fleet map.
 // This alias maintains backward compatibility with existing code.
 type MapEntry = mapsvc.Entry
@@ -82,7 +100,8 @@ Hub mode requires ConfigHub authentication (cub auth login).
 // runMapTUI launches the interactive TUI dashboard
 func runMapTUI(cmd *cobra.Command, args []string) error {
 	// If --hub flag is set, start with ConfigHub hierarchy TUI
-	if mapHub {
+	// (--focus only makes sense there, so it implies --hub)
+	if mapHub || mapHubFocus != "" {
 		return runHierarchyWithSwitch(cmd, args)
 	}
 
@@ -113,7 +132,7 @@ func runLocalClusterWithSwitch() error {
 		}
 
 		// User wants to switch to ConfigHub mode - pass context
-		switchToLocal, err := runHierarchyLoopWithContext(hubContext)
+		switchToLocal, err := runHierarchyLoopWithContext(hubContext, nil)
 		if err != nil {
 			return err
 		}
@@ -129,12 +148,24 @@ func runLocalClusterWithSwitch() error {
 
 // runHierarchyWithSwitch runs hierarchy TUI and handles mode switching
 func runHierarchyWithSwitch(cmd *cobra.Command, args []string) error {
+	focus, err := parseHubFocusFlag()
+	if err != nil {
+		return err
+	}
+
+	// Without cub there is no hub to show; the local cluster still is
+	if !cubInstalled() {
+		fmt.Fprintf(os.Stderr, "%s\nShowing the local cluster instead.\n", cubMissingError("map --hub"))
+		return runLocalClusterWithSwitch()
+	}
+
 	for {
 		// Start without context (user explicitly chose --hub)
-		switchToLocal, err := runHierarchyLoopWithContext("")
+		switchToLocal, err := runHierarchyLoopWithContext("", focus)
 		if err != nil {
 			return err
 		}
+		focus = nil // only the first run opens at --focus
 
 		if !switchToLocal {
 			// User quit without switching modes
@@ -168,10 +199,11 @@ func runHierarchyWithSwitch(cmd *cobra.Command, args []string) error {
 }
 
 // runHierarchyLoopWithContext runs the hierarchy TUI with optional app context
-// If appContext is provided, starts in Maps view filtered to that app
-func runHierarchyLoopWithContext(appContext string) (bool, error) {
-	if _, err := exec.LookPath("cub"); err != nil {
-		return false, fmt.Errorf("cub CLI not found. Install from: https://docs.confighub.com/cli")
+// If appContext is provided, starts in Maps view filtered to that app; if
+// focus is provided, starts with the cursor on that location
+func runHierarchyLoopWithContext(appContext string, focus *hubFocus) (bool, error) {
+	if !cubInstalled() {
+		return false, cubMissingError("map --hub")
 	}
 
 	if _, err := runCubCommand("context", "get"); err != nil {
@@ -180,9 +212,11 @@ func runHierarchyLoopWithContext(appContext string) (bool, error) {
 
 	// Create model with context
 	m := initialModelWithContext(appContext)
+	m.setFocus(focus)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	finalModel, err := p.Run()
+	saveHubSnapshotAfterRun(finalModel, err)
 	if err != nil {
 		return false, err
 	}
@@ -230,6 +264,10 @@ Time Filtering:
   --since=24h           Resources changed in last day
   --since=7d            Resources changed in last week
 
+  A resource has changed when it was created, written by any field manager
+  (spec edits, label changes, status updates), or a status condition
+  transitioned. The latest of these is updatedAt in --json output.
+
 Examples:
   # List all resources from current cluster
   cub-scout map list
@@ -260,12 +298,20 @@ Examples:
   # Query: By label
   cub-scout map list -q "labels[app]=nginx"
 
+  # kubectl-style selectors, filtered by the API server; combine with -q
+  cub-scout map list -l app=nginx -q "owner!=Native"
+  cub-scout map list --field-selector metadata.name=api
+
   # Recent changes (incident investigation)
   cub-scout map list --since=1h      # last hour
   cub-scout map list --since=24h     # last day
 
   # JSON output
   cub-scout map list --json
+
+  # Stream one JSON object per line as each resource type is listed, for
+  # large clusters (entries arrive in scan order, not sorted)
+  cub-scout map list --format ndjson | jq -c 'select(.owner == "Native")'
 `,
 	RunE: runMapList,
 }
@@ -277,7 +323,17 @@ var mapStatusCmd = &cobra.Command{
 
 Example output:
   ✓ healthy: 3/3 deployers, 12/12 workloads
-  ✗ problems: 1/3 deployers, 10/12 workloads`,
+  ✗ 3 problem(s): 1/3 deployers, 10/12 workloads
+
+--wide adds a breakdown per deployer type, per workload owner and per
+namespace. --json prints the full breakdown with a stable schema (kind
+StatusSummary, see 'cub-scout schema StatusSummary') for CI smoke tests and
+MOTD banners.
+
+Examples:
+  cub-scout map status
+  cub-scout map status --wide
+  cub-scout map status --json | jq -e .healthy`,
 	RunE: runMapStatus,
 }
 
@@ -290,6 +346,8 @@ var mapProblemsCmd = &cobra.Command{
 Shows:
   - Deployments with unavailable replicas
   - Flux Kustomizations/HelmReleases not ready
+  - Flux Buckets (S3, GCS, ...) and HelmRepository indexes failing to fetch,
+    with the error and how long ago they last fetched
   - Argo CD Applications not synced/healthy`,
 	RunE: runMapProblems,
 }
@@ -302,7 +360,28 @@ var mapDeployersCmd = &cobra.Command{
 Shows:
   - Flux Kustomizations and HelmReleases
   - ArgoCD Applications
-  - Sync status and health`,
+  - Sync status and health
+  - Flux Buckets and HelmRepositories, with provider, last-fetched age and
+    fetch error; a ready deployer whose Bucket or HelmRepository fails to
+    fetch is marked ⚠, as it keeps applying the last artifact
+  - With --verbose, Flux Alerts (provider, severity, event sources),
+    Receivers, and the Kustomizations and HelmReleases no Alert covers,
+    whose reconciliation failures are silent
+  - With --verbose, each Argo CD Application's sync policy and notification
+    subscriptions, flagging auto-prune without selfHeal, prune with
+    allowEmpty, manual sync in production and unsubscribed Applications
+
+With --graph, shows the source-to-deployer topology instead: which
+GitRepository, OCIRepository, HelmRepository or Bucket (or Argo CD repo URL)
+each deployer consumes. Sources feeding several deployers are marked shared;
+sources nobody consumes are marked orphaned. --graph-format dot renders it
+for Graphviz.
+
+Examples:
+  cub-scout map deployers
+  cub-scout map deployers --verbose
+  cub-scout map deployers --graph
+  cub-scout map deployers --graph --graph-format dot | dot -Tsvg > sources.svg`,
 	RunE: runMapDeployers,
 }
 
@@ -322,7 +401,16 @@ var mapDriftCmd = &cobra.Command{
 
 This includes:
 - GitOps resources out of sync (Flux Kustomizations, ArgoCD Applications)
-- ConfigHub units with revision drift`,
+- ConfigHub units with revision drift
+
+Deployers report not ready while their workloads roll out. Use --wait to
+check once rollouts have settled (observedGeneration caught up, replicas
+updated and ready), and --until-stable to re-check until two checks agree.
+
+Examples:
+  cub-scout map drift
+  cub-scout map drift --wait           # Wait up to 5m for rollouts
+  cub-scout map drift --wait=10m --until-stable`,
 	RunE: runMapDrift,
 }
 
@@ -334,7 +422,34 @@ var mapSprawlCmd = &cobra.Command{
 Shows:
 - GitOps coverage percentage
 - Breakdown by owner (Flux, ArgoCD, Helm, ConfigHub, Native)
-- Native workloads that should be added to GitOps`,
+- Native workloads that should be added to GitOps
+
+With --enforce, each namespace is checked against the coverage budget it
+matches in ~/.cub-scout/coverage.yaml ($CUB_SCOUT_COVERAGE, or --budgets):
+a minimum GitOps-managed percentage and a maximum number of orphans
+(Native workloads). Namespaces breaking their budget are listed and the
+command exits 1, so it can gate CI or a scheduled check:
+
+  budgets:
+    - namespaces: ["prod-*", payments]
+      minCoverage: 95
+      maxOrphans: 0
+    - namespaces: ["*"]
+      minCoverage: 50
+
+Orphans acknowledged with 'cub-scout ack add' are accepted exceptions: they
+are left out of the coverage percentage and the orphan count.
+
+Each run records the cluster's coverage and ConfigHub adoption for the day
+in ~/.cub-scout/history ($CUB_SCOUT_HISTORY, or --history-dir). With
+--history, sparklines chart both over the recorded days, to show migration
+progress without exporting data.
+
+Examples:
+  cub-scout map sprawl
+  cub-scout map sprawl --history
+  cub-scout map sprawl --enforce
+  cub-scout map sprawl --enforce --budgets ./coverage.yaml`,
 	RunE: runMapSprawl,
 }
 
@@ -350,8 +465,16 @@ Shows:
 - Breakdown by owner with visual bars
 - Shadow IT warnings for native workloads
 
-Example:
-  cub-scout map dashboard`,
+With --group-by env, every section is shown per environment, production
+first, so prod health isn't diluted by dev noise in a shared cluster. A
+resource's environment is its "environment" or "env" label, then its
+namespace's, then the one its namespace name suggests (prod, staging, dev,
+test). Deployers are grouped by the namespace they deploy to.
+--group-by namespace shows a section per namespace.
+
+Examples:
+  cub-scout map dashboard
+  cub-scout map dashboard --group-by env`,
 	RunE: runMapDashboard,
 }
 
@@ -362,7 +485,9 @@ var mapBypassCmd = &cobra.Command{
 
 This includes:
 - Native workloads (kubectl apply'd directly)
-- Resources without GitOps ownership labels`,
+- Resources without GitOps ownership labels
+
+Workloads acknowledged with 'cub-scout ack add' are not listed.`,
 	RunE: runMapBypass,
 }
 
@@ -398,10 +523,13 @@ Orphaned resources are those without detected GitOps or platform ownership:
 
 Note: Resources managed by Crossplane or Terraform controllers are not considered orphans.
 
-This is equivalent to: cub-scout map list -q "owner=Native"
+This is equivalent to: cub-scout map list -q "owner=Native", less the
+resources acknowledged as intentional with 'cub-scout ack add' (until their
+acknowledgement expires).
 
 Examples:
   cub-scout map orphans             # List all orphaned resources
+  cub-scout map orphans --show-acknowledged  # Include acknowledged ones
   cub-scout map orphans --json      # JSON output
   cub-scout map orphans --namespace prod  # Filter by namespace`,
 	RunE: runMapOrphans,
@@ -432,8 +560,22 @@ Shows for each resource type:
 - Flux: GitRepositories, Kustomizations, HelmReleases with conditions, inventory, LiveTree
 - ArgoCD: AppProjects, Applications with sync results, history, LiveTree
 - Helm: Releases with chart details, values, NOTES.txt, hooks, history, LiveTree
-- Workloads: By owner with pod labels, annotations, Prometheus config
-- LiveTree: Deployment -> ReplicaSet -> Pod with IPs, nodes, restarts`,
+- Workloads: By owner with pod labels, annotations, Prometheus config, and a
+  LiveTree for ConfigHub and Native workloads
+- LiveTree: Deployment -> ReplicaSet -> Pod with IPs, nodes, restarts
+  (one workload: cub-scout map tree <workload>)
+
+With --open-ui flux|argo, the controller's web UI is found instead (Argo CD
+server; Weave GitOps, Flux Operator or Capacitor for Flux), a port-forward
+to it is opened on a free local port, and the browser opens at the deployer
+given by --object ([kind/][namespace/]name), or at the UI's start page. The
+forward lasts until Ctrl+C. Port-forwards change nothing in the cluster, so
+they are allowed in read-only mode.
+
+Examples:
+  cub-scout map deep-dive
+  cub-scout map deep-dive --open-ui argo --object payments-prod
+  cub-scout map deep-dive --open-ui flux --object HelmRelease/podinfo/podinfo`,
 	RunE: runMapClusterData,
 }
 
@@ -474,6 +616,8 @@ func init() {
 
 	// Hub flag (same as 'map hub' subcommand)
 	mapCmd.Flags().BoolVar(&mapHub, "hub", false, "Launch ConfigHub hierarchy TUI (requires cub auth)")
+	mapCmd.Flags().StringVar(&mapHubFocus, "focus", "", "Open the hub TUI at a location (e.g. space/prod-payments/unit/checkout) or ConfigHub GUI URL")
+	mapHubCmd.Flags().StringVar(&mapHubFocus, "focus", "", "Open at a location (e.g. space/prod-payments/unit/checkout) or ConfigHub GUI URL")
 
 	// Fleet-specific flags
 	mapFleetCmd.Flags().StringVar(&fleetApp, "app", "", "Filter by app label")
@@ -483,31 +627,98 @@ func init() {
 	mapCmd.PersistentFlags().BoolVar(&mapJSON, "json", false, "Output in JSON format")
 	mapCmd.PersistentFlags().BoolVar(&mapVerbose, "verbose", false, "Show additional details")
 
+	// Drift-specific flags
+	addRolloutWaitFlags(mapDriftCmd)
+
 	// List-specific flags
 	mapListCmd.Flags().StringVar(&mapNamespace, "namespace", "", "Filter by namespace")
 	mapListCmd.Flags().StringVar(&mapKind, "kind", "", "Filter by resource kind")
 	mapListCmd.Flags().StringVar(&mapOwner, "owner", "", "Filter by owner (Flux, ArgoCD, Helm, Terraform, Crossplane, ConfigHub, Native)")
 	mapListCmd.Flags().StringVarP(&mapQuery, "query", "q", "", "Query expression (e.g., 'kind=Deployment AND owner!=Native')")
+	mapListCmd.Flags().StringVarP(&mapSelector, "selector", "l", "", "Label selector sent to the API server, as in kubectl (e.g. app=foo,tier!=cache)")
+	mapListCmd.Flags().StringVar(&mapFieldSelector, "field-selector", "", "Field selector sent to the API server, as in kubectl (e.g. metadata.name=api)")
 	mapListCmd.Flags().StringVar(&mapSince, "since", "", "Show resources changed since duration (e.g., 1h, 24h, 7d)")
 	mapListCmd.Flags().BoolVar(&mapCount, "count", false, "Output count only (no list)")
 	mapListCmd.Flags().BoolVar(&mapNamesOnly, "names-only", false, "Output names only (for scripting)")
-	mapListCmd.Flags().BoolVar(&mapExplain, "explain", false, "Show explanatory content to help learn GitOps concepts")
+	mapListCmd.Flags().StringVarP(&mapListFormat, "format", "o", "table", "Output format: "+strings.Join(mapListFormats, ", "))
+	_ = mapListCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(mapListFormats, cobra.ShellCompDirectiveNoFileComp))
+	mapListCmd.Flags().StringVar(&mapExplain, "explain", "", "Show explanatory content to help learn GitOps concepts (--explain=verbose for more)")
+	mapListCmd.Flags().Lookup("explain").NoOptDefVal = "brief"
+	mapListCmd.Flags().DurationVar(&mapTimeout, "timeout", 0, "Stop listing after this long and show partial results (e.g. 30s); 0 waits")
+	addPageFlags(mapListCmd)
 
 	// Orphans-specific flags (same as list)
 	mapOrphansCmd.Flags().StringVar(&mapNamespace, "namespace", "", "Filter by namespace")
+	mapOrphansCmd.Flags().BoolVar(&mapShowAcknowledged, "show-acknowledged", false, "Include orphans acknowledged with 'cub-scout ack add'")
+	_ = mapOrphansCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	addPageFlags(mapOrphansCmd)
+	addPageFlags(mapWorkloadsCmd)
+	addPageFlags(mapCrashesCmd)
 
 	// Deep-dive flags
 	mapClusterDataCmd.Flags().BoolVar(&deepDiveConnected, "connected", false, "Show ConfigHub context for managed resources (requires cub auth)")
+	mapClusterDataCmd.Flags().DurationVar(&mapTimeout, "timeout", 0, "Stop listing after this long and show partial results (e.g. 30s); 0 waits")
 
 	// Register shell completion functions for flags
 	_ = mapListCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
 	_ = mapListCmd.RegisterFlagCompletionFunc("kind", completeKinds)
 	_ = mapListCmd.RegisterFlagCompletionFunc("owner", completeOwners)
 	_ = mapListCmd.RegisterFlagCompletionFunc("since", completeSince)
+	_ = mapListCmd.RegisterFlagCompletionFunc("query", completeSavedQueries)
+	_ = mapFleetCmd.RegisterFlagCompletionFunc("space", completeSpaces)
+}
+
+// mapListFormats are the --format values of 'map list'.
+var mapListFormats = []string{"table", "json", "ndjson"}
+
+// mapListResources are the resource types map list scans.
+var mapListResources = []schema.GroupVersionResource{
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Group: "apps", Version: "v1", Resource: "daemonsets"},
+	{Group: "", Version: "v1", Resource: "services"},
+	{Group: "", Version: "v1", Resource: "configmaps"},
+	{Group: "", Version: "v1", Resource: "secrets"},
+	{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+	// Flux resources
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories"},
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"},
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"},
+	// Argo resources
+	{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+	// Scheduled work; Jobs inherit their CronJob's owner
+	{Group: "batch", Version: "v1", Resource: "cronjobs"},
+	{Group: "batch", Version: "v1", Resource: "jobs"},
 }
 
 func runMapList(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	format := mapListFormat
+	if mapJSON {
+		format = "json"
+	}
+	if !contains(mapListFormats, format) {
+		return fmt.Errorf("unknown --format %q (want %s)", format, strings.Join(mapListFormats, ", "))
+	}
+	if format == "ndjson" && (mapCount || mapNamesOnly || mapPageSize > 0) {
+		return fmt.Errorf("--format ndjson streams every entry; it cannot be combined with --count, --names-only or --page-size")
+	}
+	scope := mapListScope{Namespace: mapNamespace, LabelSelector: mapSelector, FieldSelector: mapFieldSelector}
+	if err := scope.validate(); err != nil {
+		return err
+	}
+	explainLevel, err := explain.ParseLevel(mapExplain)
+	if err != nil {
+		return err
+	}
+	var explainLib *explain.Library
+	if explainLevel > explain.Off {
+		if explainLib, err = explain.Load(); err != nil {
+			return fmt.Errorf("load explain content: %w", err)
+		}
+	}
+
+	ctx, cancel := mapListContext()
+	defer cancel()
 
 	// Build Kubernetes config
 	cfg, err := buildConfig()
@@ -515,61 +726,18 @@ func runMapList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("build kubernetes config: %w", err)
 	}
 
-	// Create dynamic client
-	dynClient, err := dynamic.NewForConfig(cfg)
+	// Create dynamic client; list failures are reported as partial results
+	client, err := dynamic.NewForConfig(cfg)
 	if err != nil {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
+	rec := &listRecorder{}
+	dynClient := newRecordingClient(client, rec)
+	defer func() { printPartialResults(os.Stderr, rec.Failures()) }()
+	loadDelegations(ctx, dynClient)
 
 	// Get cluster name
-	clusterName := os.Getenv("CLUSTER_NAME")
-	if clusterName == "" {
-		clusterName = "default"
-	}
-
-	// Collect resources
-	entries := []MapEntry{}
-	byOwner := map[string]int{}
-
-	// Resource types to scan
-	resources := []schema.GroupVersionResource{
-		{Group: "apps", Version: "v1", Resource: "deployments"},
-		{Group: "apps", Version: "v1", Resource: "statefulsets"},
-		{Group: "apps", Version: "v1", Resource: "daemonsets"},
-		{Group: "", Version: "v1", Resource: "services"},
-		{Group: "", Version: "v1", Resource: "configmaps"},
-		{Group: "", Version: "v1", Resource: "secrets"},
-		{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
-		// Flux resources
-		{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories"},
-		{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"},
-		{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"},
-		// Argo resources
-		{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
-	}
-
-	for _, gvr := range resources {
-		if mapNamespace != "" {
-			l, err := dynClient.Resource(gvr).Namespace(mapNamespace).List(ctx, v1.ListOptions{})
-			if err != nil {
-				continue // Skip resources that don't exist
-			}
-			for _, item := range l.Items {
-				entries = processResource(&item, gvr, clusterName, entries, byOwner)
-			}
-		} else {
-			l, err := dynClient.Resource(gvr).List(ctx, v1.ListOptions{})
-			if err != nil {
-				continue
-			}
-			for _, item := range l.Items {
-				entries = processResource(&item, gvr, clusterName, entries, byOwner)
-			}
-		}
-	}
-
-	// Apply filters
-	filtered := []MapEntry{}
+	clusterName := currentClusterName()
 
 	// Parse query if provided (resolve saved query names first)
 	var q *query.Query
@@ -582,36 +750,80 @@ func runMapList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	for _, e := range entries {
+	var changedAfter time.Time
+	if mapSince != "" {
+		d, err := parseDuration(mapSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: %w", mapSince, err)
+		}
+		changedAfter = time.Now().Add(-d)
+	}
+
+	hiddenSystem, hiddenAcked := 0, 0
+	keep := func(e MapEntry) bool {
+		// Namespace exclusions apply unless a namespace was asked for
+		if mapNamespace == "" && e.Namespace != "" && isSystemNamespace(e.Namespace) {
+			hiddenSystem++
+			return false
+		}
 		// Legacy flag filters
 		if mapKind != "" && e.Kind != mapKind {
-			continue
+			return false
 		}
 		if mapOwner != "" && !strings.EqualFold(e.Owner, mapOwner) {
-			continue
+			return false
+		}
+		if !changedAfter.IsZero() && e.UpdatedAt.Before(changedAfter) {
+			return false
+		}
+		if mapHideAcknowledged && e.Owner == "Native" && isAcknowledged(e.Kind, e.Namespace, e.Name) {
+			hiddenAcked++
+			return false
 		}
 		// Query filter
-		if q != nil && !q.Matches(e) {
-			continue
+		return q == nil || q.Matches(e)
+	}
+
+	// NDJSON streams each entry as its resource type is listed
+	if format == "ndjson" {
+		return streamMapList(ctx, dynClient, scope, clusterName, func(e MapEntry) error {
+			if !keep(e) {
+				return nil
+			}
+			return writeNDJSON(os.Stdout, "MapEntry", e)
+		})
+	}
+
+	// Collect resources
+	progress := newProgressBar(len(mapListResources))
+	entries, byOwner, err := scanMapList(ctx, dynClient, scope, clusterName, progress)
+	progress.Done()
+	if err != nil {
+		return err
+	}
+
+	// Apply filters
+	filtered := []MapEntry{}
+	for _, e := range entries {
+		if keep(e) {
+			filtered = append(filtered, e)
 		}
-		filtered = append(filtered, e)
 	}
 	entries = filtered
 
-	// Sort by namespace, then name
-	sort.Slice(entries, func(i, j int) bool {
-		if entries[i].Namespace != entries[j].Namespace {
-			return entries[i].Namespace < entries[j].Namespace
-		}
-		return entries[i].Name < entries[j].Name
-	})
+	sortResources(entries, func(e MapEntry) (string, string, string) { return e.Namespace, e.Kind, e.Name })
 
-	// Handle --count flag (output count only)
+	// Handle --count flag (output count only, ignoring paging)
 	if mapCount {
 		fmt.Println(len(entries))
 		return nil
 	}
 
+	entries, page, err := paginate(entries, mapPage, mapPageSize)
+	if err != nil {
+		return err
+	}
+
 	// Handle --names-only flag (output names only, for scripting)
 	if mapNamesOnly {
 		for _, e := range entries {
@@ -624,43 +836,175 @@ func runMapList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if mapJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(entries)
+	if format == "json" {
+		return writeJSON(os.Stdout, "MapList", entries)
 	}
 
 	// Explain mode: show header explaining ownership detection
-	if mapExplain {
-		fmt.Println()
-		fmt.Println("GITOPS OWNERSHIP EXPLAINED")
-		fmt.Println("════════════════════════════════════════════════════════════════════")
-		fmt.Println("cub-scout detects who manages each resource by reading labels.")
-		fmt.Println()
-		fmt.Println("FLUX resources have labels like:")
-		fmt.Println("  kustomize.toolkit.fluxcd.io/name: my-app")
-		fmt.Println("  kustomize.toolkit.fluxcd.io/namespace: flux-system")
-		fmt.Println()
-		fmt.Println("ARGOCD resources have labels like:")
-		fmt.Println("  app.kubernetes.io/instance: my-app")
-		fmt.Println("  argocd.argoproj.io/instance: my-app")
-		fmt.Println()
-		fmt.Println("HELM resources have:")
-		fmt.Println("  app.kubernetes.io/managed-by: Helm")
-		fmt.Println()
-		fmt.Println("NATIVE means no GitOps tool claims ownership (kubectl-applied).")
-		fmt.Println("════════════════════════════════════════════════════════════════════")
-		fmt.Println()
+	if explainLib != nil {
+		printExplainIntro(os.Stdout, explainLib, "ownership")
 	}
 
 	// Table output
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	if mapVerbose {
+	printMapListTable(os.Stdout, entries, mapVerbose)
+
+	// Summary
+	fmt.Printf("\nTotal: %d resources\n", page.Total)
+	printPageFooter(os.Stdout, page)
+	if contested := countContested(entries); contested > 0 && mapQuery == "" {
+		fmt.Printf("%s⚠ %d resource(s) claimed by more than one manager; list them with: cub-scout map list -q contested=true%s\n", colorYellow, contested, colorReset)
+	}
+	if hiddenSystem > 0 {
+		fmt.Printf("%s(%d in system namespaces hidden; use --include-system to show)%s\n", colorDim, hiddenSystem, colorReset)
+	}
+	if hiddenAcked > 0 {
+		fmt.Printf("%s(%d acknowledged hidden; use --show-acknowledged to show, cub-scout ack list for reasons)%s\n", colorDim, hiddenAcked, colorReset)
+	}
+	fmt.Print("By Owner: ")
+	owners := make([]string, 0, len(byOwner))
+	for owner := range byOwner {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	ownerParts := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		ownerParts = append(ownerParts, fmt.Sprintf("%s(%d)", owner, byOwner[owner]))
+	}
+	fmt.Println(strings.Join(ownerParts, " "))
+
+	// Explain mode: show what this means and next steps
+	if explainLib != nil {
+		printMapListExplain(os.Stdout, explainLib, explainLevel, entries, byOwner)
+	}
+
+	return nil
+}
+
+// mapListScope is what map list asks the API server for: a namespace (""
+// for all) and kubectl-style label and field selectors.
+type mapListScope struct {
+	Namespace     string
+	LabelSelector string
+	FieldSelector string
+}
+
+// validate parses the selectors, so a typo fails before anything is listed.
+func (s mapListScope) validate() error {
+	if _, err := labels.Parse(s.LabelSelector); err != nil {
+		return fmt.Errorf("invalid --selector %q: %w", s.LabelSelector, err)
+	}
+	if _, err := fields.ParseSelector(s.FieldSelector); err != nil {
+		return fmt.Errorf("invalid --field-selector %q: %w", s.FieldSelector, err)
+	}
+	return nil
+}
+
+func (s mapListScope) listOptions() v1.ListOptions {
+	return v1.ListOptions{LabelSelector: s.LabelSelector, FieldSelector: s.FieldSelector}
+}
+
+// mapListLister lists mapListResources in a scope. Resource types that do
+// not support the field selector are skipped like ones that are not
+// installed: none of their objects could match. It is an error when every
+// type that answered rejected the field selector.
+type mapListLister struct {
+	client   dynamic.Interface
+	scope    mapListScope
+	accepted int
+	rejected int
+}
+
+func (l *mapListLister) list(ctx context.Context, gvr schema.GroupVersionResource) ([]unstructured.Unstructured, bool) {
+	list, err := l.client.Resource(gvr).Namespace(l.scope.Namespace).List(ctx, l.scope.listOptions())
+	switch {
+	case err == nil:
+		l.accepted++
+		return list.Items, true
+	case isUnsupportedFieldSelector(err, l.scope.FieldSelector):
+		l.rejected++
+	}
+	return nil, false // Not installed, or recorded as a partial result
+}
+
+func (l *mapListLister) err() error {
+	if l.rejected > 0 && l.accepted == 0 {
+		return fmt.Errorf("--field-selector %q is not supported by any resource type map list scans (metadata.name and metadata.namespace work for all)", l.scope.FieldSelector)
+	}
+	return nil
+}
+
+// scanMapList lists mapListResources in scope and classifies every object,
+// returning the entries and a count per owner. Resources that fail to list
+// are skipped; a recording client reports them.
+func scanMapList(ctx context.Context, client dynamic.Interface, scope mapListScope, clusterName string, progress *progressBar) ([]MapEntry, map[string]int, error) {
+	lister := &mapListLister{client: client, scope: scope}
+	var lists []listedObjects
+	for _, gvr := range mapListResources {
+		progress.Step(gvr.Resource)
+		if items, ok := lister.list(ctx, gvr); ok {
+			lists = append(lists, listedObjects{gvr, items})
+		}
+	}
+	if err := lister.err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Classify once everything is listed, so ownerReferences resolve
+	indexOwners(lists)
+	entries := []MapEntry{}
+	byOwner := map[string]int{}
+	for _, l := range lists {
+		for i := range l.items {
+			entries = processResource(&l.items[i], l.gvr, clusterName, entries, byOwner)
+		}
+	}
+	return entries, byOwner, nil
+}
+
+// streamMapList lists mapListResources in scope one type at a time and
+// calls emit for each object as soon as its type is listed. Owners are
+// indexed as they arrive; mapListResources lists controllers before what
+// they create (CronJobs before Jobs), so ownerReferences still resolve.
+func streamMapList(ctx context.Context, client dynamic.Interface, scope mapListScope, clusterName string, emit func(MapEntry) error) error {
+	lister := &mapListLister{client: client, scope: scope}
+	ownerGraph = agent.NewOwnerIndex()
+	byOwner := map[string]int{}
+	var buf []MapEntry
+	for _, gvr := range mapListResources {
+		items, ok := lister.list(ctx, gvr)
+		if !ok {
+			continue
+		}
+		for i := range items {
+			ownerGraph.Add(&items[i])
+		}
+		for i := range items {
+			buf = processResource(&items[i], gvr, clusterName, buf[:0], byOwner)
+			for _, e := range buf {
+				if err := emit(e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return lister.err()
+}
+
+// printMapListTable writes the map list table; verbose adds OWNER_DETAIL.
+func printMapListTable(out io.Writer, entries []MapEntry, verbose bool) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if verbose {
 		fmt.Fprintln(w, "NAMESPACE\tKIND\tNAME\tOWNER\tOWNER_DETAIL")
 		for _, e := range entries {
 			detail := ""
 			if e.OwnerDetails != nil {
-				if space := e.OwnerDetails["space"]; space != "" {
+				if deployer := e.OwnerDetails["deployer"]; deployer != "" {
+					unit := e.OwnerDetails["unit"]
+					if unit == "" {
+						unit = "target:" + e.OwnerDetails["target"]
+					}
+					detail = fmt.Sprintf("%s/%s via %s", e.OwnerDetails["space"], unit, deployer)
+				} else if space := e.OwnerDetails["space"]; space != "" {
 					detail = fmt.Sprintf("%s/%s", space, e.OwnerDetails["unit"])
 				} else if name := e.OwnerDetails["name"]; name != "" {
 					detail = name
@@ -670,7 +1014,7 @@ func runMapList(cmd *cobra.Command, args []string) error {
 				e.Namespace,
 				e.Kind,
 				e.Name,
-				e.Owner,
+				ownerLabel(e),
 				detail,
 			)
 		}
@@ -681,60 +1025,11 @@ func runMapList(cmd *cobra.Command, args []string) error {
 				e.Namespace,
 				e.Kind,
 				e.Name,
-				e.Owner,
+				ownerLabel(e),
 			)
 		}
 	}
 	w.Flush()
-
-	// Summary
-	fmt.Printf("\nTotal: %d resources\n", len(entries))
-	fmt.Print("By Owner: ")
-	owners := make([]string, 0, len(byOwner))
-	for owner := range byOwner {
-		owners = append(owners, owner)
-	}
-	sort.Strings(owners)
-	ownerParts := make([]string, 0, len(owners))
-	for _, owner := range owners {
-		ownerParts = append(ownerParts, fmt.Sprintf("%s(%d)", owner, byOwner[owner]))
-	}
-	fmt.Println(strings.Join(ownerParts, " "))
-
-	// Explain mode: show what this means and next steps
-	if mapExplain {
-		fmt.Println()
-		fmt.Println("WHAT THIS MEANS:")
-		// Show owner-specific explanations based on what was found
-		if byOwner["Flux"] > 0 {
-			fmt.Printf("• %d resources are managed by Flux → Changes flow from Git automatically\n", byOwner["Flux"])
-		}
-		if byOwner["ArgoCD"] > 0 {
-			fmt.Printf("• %d resources are managed by ArgoCD → Synced from Git via ArgoCD\n", byOwner["ArgoCD"])
-		}
-		if byOwner["Helm"] > 0 {
-			fmt.Printf("• %d resources are managed by Helm → Installed via helm install/upgrade\n", byOwner["Helm"])
-		}
-		if byOwner["Terraform"] > 0 {
-			fmt.Printf("• %d resources are managed by Terraform → Provisioned by Terraform controllers\n", byOwner["Terraform"])
-		}
-		if byOwner["Crossplane"] > 0 {
-			fmt.Printf("• %d resources are managed by Crossplane → Created/controlled by Crossplane compositions\n", byOwner["Crossplane"])
-		}
-		if byOwner["ConfigHub"] > 0 {
-			fmt.Printf("• %d resources are managed by ConfigHub → Deployed via ConfigHub\n", byOwner["ConfigHub"])
-		}
-		if byOwner["Native"] > 0 {
-			fmt.Printf("• %d resources are Native → No detected GitOps or platform controller ownership\n", byOwner["Native"])
-		}
-		fmt.Println()
-		fmt.Println("NEXT STEPS:")
-		fmt.Println("→ See the Git→Deployment chain: cub-scout trace <kind>/<name> -n <namespace>")
-		fmt.Println("→ See the full GitOps pipeline:  cub-scout map deployers")
-		fmt.Println("→ Visual guide:                  docs/diagrams/ownership-detection.svg")
-	}
-
-	return nil
 }
 
 func processResource(item interface{}, gvr schema.GroupVersionResource, clusterName string, entries []MapEntry, byOwner map[string]int) []MapEntry {
@@ -747,8 +1042,9 @@ func processResource(item interface{}, gvr schema.GroupVersionResource, clusterN
 	labels := unstr.GetLabels()
 	annotations := unstr.GetAnnotations()
 
-	// Detect ownership using the canonical agent function
-	ownership := agent.DetectOwnership(unstr)
+	// Detect ownership using the canonical agent function, following
+	// ownerReferences through the listed objects
+	ownership := graphOwnership(unstr)
 
 	entry := MapEntry{
 		ID:          fmt.Sprintf("%s/%s/%s/%s/%s", clusterName, unstr.GetNamespace(), gvr.Group, unstr.GetKind(), unstr.GetName()),
@@ -761,7 +1057,7 @@ func processResource(item interface{}, gvr schema.GroupVersionResource, clusterN
 		Labels:      labels,
 		Status:      detectStatus(unstr),
 		CreatedAt:   unstr.GetCreationTimestamp().Time,
-		UpdatedAt:   unstr.GetCreationTimestamp().Time,
+		UpdatedAt:   mapsvc.LastChanged(unstr),
 	}
 
 	if ownership.Type != "" && ownership.Type != agent.OwnerUnknown {
@@ -775,6 +1071,9 @@ func processResource(item interface{}, gvr schema.GroupVersionResource, clusterN
 		if ownership.SubType != "" {
 			entry.OwnerDetails["subType"] = ownership.SubType
 		}
+		if ownership.Source != "" {
+			entry.OwnerDetails["source"] = ownership.Source
+		}
 		// Add ConfigHub specific details
 		if ownership.Type == agent.OwnerConfigHub {
 			if space := annotations["confighub.com/SpaceName"]; space != "" {
@@ -789,6 +1088,16 @@ func processResource(item interface{}, gvr schema.GroupVersionResource, clusterN
 		}
 	}
 
+	// Flux/Argo applying a ConfigHub OCI artifact: ConfigHub is the owner
+	if d := ownerDelegations.Lookup(ownership); d != nil {
+		applyDelegation(&entry, unstr, d)
+	} else {
+		// Two managers applying the same object fight over it
+		for _, claim := range agent.DetectContestedOwnership(unstr) {
+			entry.Contested = append(entry.Contested, displayOwner(claim.Type))
+		}
+	}
+
 	// "Native" means no GitOps owner detected (not managed by Flux, Argo, Helm, or ConfigHub).
 	// These are resources deployed directly via kubectl, or system components like the GitOps
 	// controllers themselves. This is expected and correct - the insight is knowing WHAT is
@@ -825,6 +1134,9 @@ Example:
 
   # Filter to specific space (App Space)
   cub-scout map fleet --space payments-team
+
+  # Inventory across clusters from pushed snapshots (no ConfigHub needed)
+  cub-scout map fleet --from-store --app payment-api
 `,
 	RunE: runMapFleet,
 }
@@ -847,6 +1159,10 @@ type FleetUnit struct {
 }
 
 func runMapFleet(cmd *cobra.Command, args []string) error {
+	if fleetFromStore {
+		return runMapFleetStore()
+	}
+
 	// Get units from ConfigHub
 	units, err := fetchFleetUnits(fleetSpace, fleetApp)
 	if err != nil {
@@ -861,9 +1177,7 @@ func runMapFleet(cmd *cobra.Command, args []string) error {
 	}
 
 	if mapJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(units)
+		return writeJSON(os.Stdout, "FleetUnits", units)
 	}
 
 	// Group by app, then variant
@@ -959,11 +1273,11 @@ func fetchFleetUnits(space, appFilter string) ([]FleetUnit, error) {
 		args = append(args, "--space", space)
 	}
 
-	cmd := exec.Command("cub", args...)
-	output, err := cmd.Output()
+	output, err := runCubCommand(args...)
 	if err != nil {
 		// Check if it's an auth error
-		if exitErr, ok := err.(*exec.ExitError); ok {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
 			stderr := string(exitErr.Stderr)
 			if strings.Contains(stderr, "authentication") ||
 				strings.Contains(stderr, "token") ||
@@ -1064,8 +1378,7 @@ func fetchFleetUnits(space, appFilter string) ([]FleetUnit, error) {
 
 // fetchUnitLabels gets labels for a specific unit
 func fetchUnitLabels(space, slug string) (map[string]string, error) {
-	cmd := exec.Command("cub", "unit", "get", slug, "--space", space, "--json")
-	output, err := cmd.Output()
+	output, err := runCubCommand("unit", "get", slug, "--space", space, "--json")
 	if err != nil {
 		return nil, err
 	}
@@ -1114,9 +1427,9 @@ func resolveSavedQueries(queryExpr string) string {
 			continue
 		}
 
-		// Check if it's a saved query name (no = or != or ~=)
+		// Check if it's a saved query name (no = or != or ~=), optionally @name
 		if !strings.Contains(token, "=") {
-			if saved, found := store.Get(token); found {
+			if saved, found := store.Get(strings.TrimPrefix(token, "@")); found {
 				// Wrap in parens if it contains OR to preserve precedence
 				if strings.Contains(strings.ToUpper(saved.Query), " OR ") {
 					result = append(result, "("+saved.Query+")")
@@ -1148,18 +1461,14 @@ func runMapStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
 
-	// Count deployers
-	deployersReady, deployersTotal := 0, 0
+	var deployers, workloads []statusEntry
 
 	// Check Flux Kustomizations
 	if ksList, err := dynClient.Resource(schema.GroupVersionResource{
 		Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations",
 	}).List(ctx, v1.ListOptions{}); err == nil {
 		for _, ks := range ksList.Items {
-			deployersTotal++
-			if isResourceReady(&ks) {
-				deployersReady++
-			}
+			deployers = append(deployers, statusEntry{"Kustomization", "Flux", ks.GetNamespace(), isResourceReady(&ks)})
 		}
 	}
 
@@ -1168,10 +1477,7 @@ func runMapStatus(cmd *cobra.Command, args []string) error {
 		Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases",
 	}).List(ctx, v1.ListOptions{}); err == nil {
 		for _, hr := range hrList.Items {
-			deployersTotal++
-			if isResourceReady(&hr) {
-				deployersReady++
-			}
+			deployers = append(deployers, statusEntry{"HelmRelease", "Flux", hr.GetNamespace(), isResourceReady(&hr)})
 		}
 	}
 
@@ -1180,16 +1486,10 @@ func runMapStatus(cmd *cobra.Command, args []string) error {
 		Group: "argoproj.io", Version: "v1alpha1", Resource: "applications",
 	}).List(ctx, v1.ListOptions{}); err == nil {
 		for _, app := range appList.Items {
-			deployersTotal++
-			if isArgoAppHealthy(&app) {
-				deployersReady++
-			}
+			deployers = append(deployers, statusEntry{"Application", "ArgoCD", app.GetNamespace(), isArgoAppHealthy(&app)})
 		}
 	}
 
-	// Count workloads
-	workloadsReady, workloadsTotal := 0, 0
-
 	// Check Deployments
 	if depList, err := dynClient.Resource(schema.GroupVersionResource{
 		Group: "apps", Version: "v1", Resource: "deployments",
@@ -1199,23 +1499,21 @@ func runMapStatus(cmd *cobra.Command, args []string) error {
 			if strings.HasPrefix(ns, "kube-") || ns == "local-path-storage" {
 				continue
 			}
-			workloadsTotal++
-			if isDeploymentReady(&dep) {
-				workloadsReady++
-			}
+			owner := displayOwner(agent.DetectOwnership(&dep).Type)
+			workloads = append(workloads, statusEntry{"Deployment", owner, ns, isDeploymentReady(&dep)})
 		}
 	}
 
 	// Output
-	problems := (deployersTotal - deployersReady) + (workloadsTotal - workloadsReady)
-	if problems == 0 {
-		fmt.Printf("✓ healthy: %d/%d deployers, %d/%d workloads\n",
-			deployersReady, deployersTotal, workloadsReady, workloadsTotal)
-	} else {
-		fmt.Printf("✗ %d problem(s): %d/%d deployers, %d/%d workloads\n",
-			problems, deployersReady, deployersTotal, workloadsReady, workloadsTotal)
+	summary := buildStatusSummary(deployers, workloads)
+	switch {
+	case mapJSON:
+		return writeJSON(os.Stdout, "StatusSummary", summary)
+	case mapStatusWide:
+		printStatusWide(os.Stdout, summary)
+	default:
+		printStatusLine(os.Stdout, summary)
 	}
-
 	return nil
 }
 
@@ -1234,8 +1532,11 @@ func runMapProblems(cmd *cobra.Command, args []string) error {
 	}
 
 	// Track deployer vs workload issues separately
-	deployerIssues := []string{}
-	workloadIssues := []string{}
+	type problem struct {
+		namespace, kind, name, line string
+	}
+	deployerIssues := []problem{}
+	workloadIssues := []problem{}
 
 	// Check Flux Kustomizations
 	if ksList, err := dynClient.Resource(schema.GroupVersionResource{
@@ -1244,8 +1545,8 @@ func runMapProblems(cmd *cobra.Command, args []string) error {
 		for _, ks := range ksList.Items {
 			if !isResourceReady(&ks) {
 				reason := getConditionReason(&ks)
-				deployerIssues = append(deployerIssues, fmt.Sprintf("✗ Kustomization/%s in %s: %s",
-					ks.GetName(), ks.GetNamespace(), reason))
+				deployerIssues = append(deployerIssues, problem{ks.GetNamespace(), "Kustomization", ks.GetName(), fmt.Sprintf("✗ Kustomization/%s in %s: %s",
+					ks.GetName(), ks.GetNamespace(), reason)})
 			}
 		}
 	}
@@ -1257,12 +1558,21 @@ func runMapProblems(cmd *cobra.Command, args []string) error {
 		for _, hr := range hrList.Items {
 			if !isResourceReady(&hr) {
 				reason := getConditionReason(&hr)
-				deployerIssues = append(deployerIssues, fmt.Sprintf("✗ HelmRelease/%s in %s: %s",
-					hr.GetName(), hr.GetNamespace(), reason))
+				deployerIssues = append(deployerIssues, problem{hr.GetNamespace(), "HelmRelease", hr.GetName(), fmt.Sprintf("✗ HelmRelease/%s in %s: %s",
+					hr.GetName(), hr.GetNamespace(), reason)})
 			}
 		}
 	}
 
+	// Check Flux Buckets and HelmRepositories
+	now := time.Now()
+	for _, src := range agent.ListFluxSourceHealth(ctx, dynClient, "") {
+		if problemText := src.Problem(now); problemText != "" {
+			deployerIssues = append(deployerIssues, problem{src.Namespace, src.Kind, src.Name, fmt.Sprintf("✗ %s/%s in %s: %s",
+				src.Kind, src.Name, src.Namespace, problemText)})
+		}
+	}
+
 	// Check ArgoCD Applications
 	if appList, err := dynClient.Resource(schema.GroupVersionResource{
 		Group: "argoproj.io", Version: "v1alpha1", Resource: "applications",
@@ -1270,8 +1580,8 @@ func runMapProblems(cmd *cobra.Command, args []string) error {
 		for _, app := range appList.Items {
 			if !isArgoAppHealthy(&app) {
 				status := getArgoStatus(&app)
-				deployerIssues = append(deployerIssues, fmt.Sprintf("✗ Application/%s in %s: %s",
-					app.GetName(), app.GetNamespace(), status))
+				deployerIssues = append(deployerIssues, problem{app.GetNamespace(), "Application", app.GetName(), fmt.Sprintf("✗ Application/%s in %s: %s",
+					app.GetName(), app.GetNamespace(), status)})
 			}
 		}
 	}
@@ -1287,8 +1597,8 @@ func runMapProblems(cmd *cobra.Command, args []string) error {
 			}
 			if !isDeploymentReady(&dep) {
 				desired, available := getDeploymentReplicas(&dep)
-				workloadIssues = append(workloadIssues, fmt.Sprintf("✗ Deployment/%s in %s: %d/%d ready",
-					dep.GetName(), ns, available, desired))
+				workloadIssues = append(workloadIssues, problem{ns, "Deployment", dep.GetName(), fmt.Sprintf("✗ Deployment/%s in %s: %d/%d ready",
+					dep.GetName(), ns, available, desired)})
 			}
 		}
 	}
@@ -1310,8 +1620,9 @@ func runMapProblems(cmd *cobra.Command, args []string) error {
 	// Print deployer issues
 	if len(deployerIssues) > 0 {
 		fmt.Printf("DEPLOYERS (%d issues)\n", len(deployerIssues))
+		sortResources(deployerIssues, func(p problem) (string, string, string) { return p.namespace, p.kind, p.name })
 		for _, p := range deployerIssues {
-			fmt.Println(p)
+			fmt.Println(p.line)
 		}
 		fmt.Println()
 	}
@@ -1319,8 +1630,9 @@ func runMapProblems(cmd *cobra.Command, args []string) error {
 	// Print workload issues
 	if len(workloadIssues) > 0 {
 		fmt.Printf("WORKLOADS (%d issues)\n", len(workloadIssues))
+		sortResources(workloadIssues, func(p problem) (string, string, string) { return p.namespace, p.kind, p.name })
 		for _, p := range workloadIssues {
-			fmt.Println(p)
+			fmt.Println(p.line)
 		}
 		fmt.Println()
 	}
@@ -1352,13 +1664,20 @@ func runMapDeployers(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
+	if deployersGraph {
+		return runMapDeployersGraph(ctx, dynClient)
+	}
+	delegations := loadDelegations(ctx, dynClient)
+	sources := agent.ListFluxSourceHealth(ctx, dynClient, "")
+	sourceHealth := fluxSourceHealthIndex(sources)
 
 	// Count by type
 	var ksCount, hrCount, appCount int
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "STATUS\tKIND\tNAME\tNAMESPACE\tREVISION\tRESOURCES")
-	fmt.Fprintln(w, "──────\t────\t────\t─────────\t────────\t─────────")
+	type deployerRow struct {
+		status, kind, name, namespace, revision, resources string
+	}
+	var rows []deployerRow
 
 	// Flux Kustomizations
 	if ksList, err := dynClient.Resource(schema.GroupVersionResource{
@@ -1369,11 +1688,12 @@ func runMapDeployers(cmd *cobra.Command, args []string) error {
 			status := "✓"
 			if !isResourceReady(&ks) {
 				status = "✗"
+			} else if _, bad := unhealthyFluxSource(sourceHealth, &ks); bad {
+				status = "⚠"
 			}
 			rev := getLastAppliedRevision(&ks)
 			resources := getInventoryCount(&ks)
-			fmt.Fprintf(w, "%s\tKustomization\t%s\t%s\t%s\t%d\n",
-				status, ks.GetName(), ks.GetNamespace(), rev, resources)
+			rows = append(rows, deployerRow{status, "Kustomization", ks.GetName(), ks.GetNamespace(), rev, strconv.Itoa(resources)})
 		}
 	}
 
@@ -1386,10 +1706,11 @@ func runMapDeployers(cmd *cobra.Command, args []string) error {
 			status := "✓"
 			if !isResourceReady(&hr) {
 				status = "✗"
+			} else if _, bad := unhealthyFluxSource(sourceHealth, &hr); bad {
+				status = "⚠"
 			}
 			rev := getLastAppliedRevision(&hr)
-			fmt.Fprintf(w, "%s\tHelmRelease\t%s\t%s\t%s\t-\n",
-				status, hr.GetName(), hr.GetNamespace(), rev)
+			rows = append(rows, deployerRow{status, "HelmRelease", hr.GetName(), hr.GetNamespace(), rev, "-"})
 		}
 	}
 
@@ -1405,17 +1726,52 @@ func runMapDeployers(cmd *cobra.Command, args []string) error {
 			}
 			rev := getArgoRevision(&app)
 			resources := getArgoResourceCount(&app)
-			fmt.Fprintf(w, "%s\tApplication\t%s\t%s\t%s\t%d\n",
-				status, app.GetName(), app.GetNamespace(), rev, resources)
+			rows = append(rows, deployerRow{status, "Application", app.GetName(), app.GetNamespace(), rev, strconv.Itoa(resources)})
 		}
 	}
 
+	sortResources(rows, func(r deployerRow) (string, string, string) { return r.namespace, r.kind, r.name })
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tKIND\tNAME\tNAMESPACE\tREVISION\tRESOURCES")
+	fmt.Fprintln(w, "──────\t────\t────\t─────────\t────────\t─────────")
+	for _, r := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.status, r.kind, r.name, r.namespace, r.revision, r.resources)
+	}
 	w.Flush()
+	if len(sources) > 0 {
+		printFluxSourceHealth(os.Stdout, sources, time.Now())
+	}
+	if mapVerbose && ksCount+hrCount > 0 {
+		alerting, err := agent.ListFluxAlerting(ctx, dynClient, "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		} else {
+			printFluxAlerting(os.Stdout, alerting)
+		}
+	}
+	if mapVerbose && appCount > 0 {
+		audit, err := agent.AuditArgoPolicies(ctx, dynClient, "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		} else {
+			printArgoPolicies(os.Stdout, audit)
+		}
+	}
 
 	// Summary
 	total := ksCount + hrCount + appCount
 	fmt.Printf("\n%d deployers: %d Kustomizations, %d HelmReleases, %d Applications\n",
 		total, ksCount, hrCount, appCount)
+	if unhealthy := countUnhealthySources(sources); unhealthy > 0 {
+		fmt.Printf("⚠ %d of %d Buckets/HelmRepositories failing to fetch; deployers marked ⚠ are serving a stale artifact\n", unhealthy, len(sources))
+	}
+	if delegations.Len() > 0 {
+		fmt.Println("\nConfigHub delegated apply (ConfigHub owns, deployer applies):")
+		for _, d := range delegations.Delegations() {
+			fmt.Printf("  %s/%s/%s → ConfigHub %s/%s (%s)\n",
+				d.DeployerKind, d.DeployerNamespace, d.DeployerName, d.Space, d.Target, d.URL)
+		}
+	}
 	fmt.Println("→ Visual guide: docs/diagrams/flux-architecture.svg")
 
 	return nil
@@ -1434,14 +1790,16 @@ func runMapWorkloads(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
+	loadDelegations(ctx, dynClient)
 
 	// Count by owner
 	ownerCounts := map[string]int{}
 	var total int
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "STATUS\tNAMESPACE\tNAME\tOWNER\tMANAGED-BY\tIMAGE")
-	fmt.Fprintln(w, "──────\t─────────\t────\t─────\t──────────\t─────")
+	type workloadRow struct {
+		status, namespace, name, owner, managedBy, image string
+	}
+	var rows []workloadRow
 
 	// Get Deployments
 	if depList, err := dynClient.Resource(schema.GroupVersionResource{
@@ -1463,11 +1821,23 @@ func runMapWorkloads(cmd *cobra.Command, args []string) error {
 			ownerCounts[owner]++
 			image := getContainerImage(&dep)
 
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-				status, ns, dep.GetName(), owner, managedBy, image)
+			rows = append(rows, workloadRow{status, ns, dep.GetName(), owner, managedBy, image})
 		}
 	}
 
+	sortResources(rows, func(r workloadRow) (string, string, string) { return r.namespace, "Deployment", r.name })
+	rows, page, err := paginate(rows, mapPage, mapPageSize)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tNAMESPACE\tNAME\tOWNER\tMANAGED-BY\tIMAGE")
+	fmt.Fprintln(w, "──────\t─────────\t────\t─────\t──────────\t─────")
+	for _, r := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.status, r.namespace, r.name, r.owner, r.managedBy, r.image)
+	}
 	w.Flush()
 
 	// Summary
@@ -1481,11 +1851,21 @@ func runMapWorkloads(cmd *cobra.Command, args []string) error {
 			}
 		}
 		fmt.Printf("\n%d workloads: %s\n", total, strings.Join(parts, ", "))
+		printPageFooter(os.Stdout, page)
 	}
 
 	return nil
 }
 
+// mapListContext returns the context for a listing command, bounded by
+// --timeout when set.
+func mapListContext() (context.Context, context.CancelFunc) {
+	if mapTimeout > 0 {
+		return context.WithTimeout(context.Background(), mapTimeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
 func runMapDrift(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -1499,10 +1879,56 @@ func runMapDrift(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
 
+	var drifted []DriftItem
+	err = newRolloutGate(ctx, dynClient, "").run(func() (string, error) {
+		drifted = collectDrift(ctx, dynClient)
+		return fmt.Sprint(drifted), nil
+	})
+	if err != nil {
+		return err
+	}
+
 	fmt.Println("🔄 DRIFT DETECTION")
 	fmt.Println()
 
-	var driftedCount int
+	for _, d := range drifted {
+		fmt.Printf("⚠ %s/%s in %s: %s\n", d.Kind, d.Name, d.Namespace, d.Reason)
+		if d.BlockedBy != "" {
+			fmt.Printf("    ↳ %s\n", d.BlockedBy)
+		}
+	}
+
+	if len(drifted) == 0 {
+		fmt.Println("✓ No drift detected - all resources are in sync")
+	} else {
+		fmt.Printf("\n⚠ %d resource(s) have drifted from desired state\n", len(drifted))
+	}
+
+	return nil
+}
+
+// DriftItem is a deployer whose cluster state diverged from its desired state.
+type DriftItem struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Reason    string `json:"reason"`
+
+	// BlockedBy is the source or dependsOn entry holding a Flux deployer
+	// back, when the cause is upstream of it
+	BlockedBy string `json:"blockedBy,omitempty"`
+}
+
+// collectDrift lists Kustomizations and HelmReleases that are not ready and
+// Applications that are not synced.
+func collectDrift(ctx context.Context, dynClient dynamic.Interface) []DriftItem {
+	var drifted []DriftItem
+	flux := agent.NewFluxResolver(agent.ClientFluxLookup(dynClient))
+	blockedBy := func(deployer *unstructured.Unstructured) string {
+		src, _ := flux.Source(ctx, deployer)
+		deps, _ := flux.Dependencies(ctx, deployer)
+		return agent.FluxBlocker(src, deps)
+	}
 
 	// Check Flux Kustomizations
 	if kslist, err := dynClient.Resource(schema.GroupVersionResource{
@@ -1510,10 +1936,7 @@ func runMapDrift(cmd *cobra.Command, args []string) error {
 	}).List(ctx, v1.ListOptions{}); err == nil {
 		for _, ks := range kslist.Items {
 			if !isResourceReady(&ks) {
-				driftedCount++
-				reason := getConditionReason(&ks)
-				fmt.Printf("⚠ Kustomization/%s in %s: %s\n",
-					ks.GetName(), ks.GetNamespace(), reason)
+				drifted = append(drifted, DriftItem{Kind: "Kustomization", Name: ks.GetName(), Namespace: ks.GetNamespace(), Reason: getConditionReason(&ks), BlockedBy: blockedBy(&ks)})
 			}
 		}
 	}
@@ -1525,10 +1948,8 @@ func runMapDrift(cmd *cobra.Command, args []string) error {
 		for _, app := range appList.Items {
 			syncStatus, _, _ := unstructured.NestedString(app.Object, "status", "sync", "status")
 			if syncStatus != "" && syncStatus != "Synced" {
-				driftedCount++
 				healthStatus, _, _ := unstructured.NestedString(app.Object, "status", "health", "status")
-				fmt.Printf("⚠ Application/%s in %s: %s/%s\n",
-					app.GetName(), app.GetNamespace(), syncStatus, healthStatus)
+				drifted = append(drifted, DriftItem{Kind: "Application", Name: app.GetName(), Namespace: app.GetNamespace(), Reason: syncStatus + "/" + healthStatus})
 			}
 		}
 	}
@@ -1539,21 +1960,13 @@ func runMapDrift(cmd *cobra.Command, args []string) error {
 	}).List(ctx, v1.ListOptions{}); err == nil {
 		for _, hr := range hrList.Items {
 			if !isResourceReady(&hr) {
-				driftedCount++
-				reason := getConditionReason(&hr)
-				fmt.Printf("⚠ HelmRelease/%s in %s: %s\n",
-					hr.GetName(), hr.GetNamespace(), reason)
+				drifted = append(drifted, DriftItem{Kind: "HelmRelease", Name: hr.GetName(), Namespace: hr.GetNamespace(), Reason: getConditionReason(&hr), BlockedBy: blockedBy(&hr)})
 			}
 		}
 	}
 
-	if driftedCount == 0 {
-		fmt.Println("✓ No drift detected - all resources are in sync")
-	} else {
-		fmt.Printf("\n⚠ %d resource(s) have drifted from desired state\n", driftedCount)
-	}
-
-	return nil
+	sortResources(drifted, func(d DriftItem) (string, string, string) { return d.Namespace, d.Kind, d.Name })
+	return drifted
 }
 
 func runMapSprawl(cmd *cobra.Command, args []string) error {
@@ -1568,34 +1981,59 @@ func runMapSprawl(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
+	loadDelegations(ctx, dynClient)
+
+	return mapSprawl(ctx, dynClient, openHistoryStore(sprawlHistoryDir), currentClusterName())
+}
+
+// mapSprawl prints the cluster's ownership breakdown, records it in store
+// and, with --enforce, checks it against the coverage budgets. A failed
+// Deployment list is an error: an empty breakdown would pass every budget.
+func mapSprawl(ctx context.Context, dynClient dynamic.Interface, store HistoryStore, cluster string) error {
+	depList, err := dynClient.Resource(schema.GroupVersionResource{
+		Group: "apps", Version: "v1", Resource: "deployments",
+	}).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list deployments: %w", err)
+	}
 
 	fmt.Println("📊 CONFIGURATION SPRAWL ANALYSIS")
 	fmt.Println()
 
-	var fluxCount, argoCount, helmCount, configHubCount, nativeCount int
+	var fluxCount, argoCount, helmCount, configHubCount, nativeCount, ackedCount int
+	coverage := map[string]namespaceCoverage{}
 
-	// Get all Deployments and count by owner
-	if depList, err := dynClient.Resource(schema.GroupVersionResource{
-		Group: "apps", Version: "v1", Resource: "deployments",
-	}).List(ctx, v1.ListOptions{}); err == nil {
-		for _, dep := range depList.Items {
-			ns := dep.GetNamespace()
-			if isSystemNamespace(ns) {
-				continue
-			}
-			owner, _ := detectOwnership(&dep)
-			switch owner {
-			case "Flux":
-				fluxCount++
-			case "ArgoCD":
-				argoCount++
-			case "Helm":
-				helmCount++
-			case "ConfigHub":
-				configHubCount++
-			case "Native":
-				nativeCount++
-			}
+	// Count Deployments by owner
+	for _, dep := range depList.Items {
+		ns := dep.GetNamespace()
+		if isSystemNamespace(ns) {
+			continue
+		}
+		owner, _ := detectOwnership(&dep)
+		// Acknowledged orphans are accepted exceptions: they count
+		// neither for nor against coverage
+		if owner == "Native" && isAcknowledged("Deployment", ns, dep.GetName()) {
+			ackedCount++
+			continue
+		}
+		c := coverage[ns]
+		if owner == "Native" {
+			c.Native++
+		} else {
+			c.Managed++
+		}
+		coverage[ns] = c
+		switch owner {
+		case "Flux":
+			fluxCount++
+		case "ArgoCD":
+			argoCount++
+		case "Helm":
+			helmCount++
+		case "ConfigHub":
+			configHubCount++
+		case "Native":
+			nativeCount++
 		}
 	}
 
@@ -1603,13 +2041,13 @@ func runMapSprawl(cmd *cobra.Command, args []string) error {
 	managed := total - nativeCount
 
 	// Calculate coverage
-	coverage := 0
+	coveragePct := 0
 	if total > 0 {
-		coverage = (managed * 100) / total
+		coveragePct = (managed * 100) / total
 	}
 
 	// Print results
-	fmt.Printf("COVERAGE: %d%% GitOps managed\n\n", coverage)
+	fmt.Printf("COVERAGE: %d%% GitOps managed\n\n", coveragePct)
 
 	fmt.Println("BY OWNER:")
 	if fluxCount > 0 {
@@ -1627,12 +2065,30 @@ func runMapSprawl(cmd *cobra.Command, args []string) error {
 	if nativeCount > 0 {
 		fmt.Printf("  Native    %3d %s  ← add to GitOps\n", nativeCount, makeBar(nativeCount, total, 20))
 	}
+	if ackedCount > 0 {
+		fmt.Printf("  %s(%d acknowledged native workload(s) excluded; see cub-scout ack list)%s\n", colorDim, ackedCount, colorReset)
+	}
 
 	if nativeCount > 0 {
 		fmt.Printf("\n⚠ %d native workload(s) should be added to GitOps\n", nativeCount)
 		fmt.Println("  Run: cub-scout map bypass  # to see details")
 	}
 
+	// Every successful run records the day's coverage in the local history
+	// store; a failed list returned above, so no empty sample lands here
+	sample := SprawlSample{At: time.Now().UTC(), Workloads: total, Managed: managed, ConfigHub: configHubCount}
+	samples, err := store.RecordSprawl(cluster, sample)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: record sprawl history: %v\n", err)
+		samples = []SprawlSample{sample}
+	}
+	if sprawlShowHistory {
+		writeSprawlHistory(os.Stdout, store, cluster, samples)
+	}
+
+	if sprawlEnforce {
+		return enforceCoverageBudgets(os.Stdout, coverage)
+	}
 	return nil
 }
 
@@ -1640,6 +2096,12 @@ func runMapSprawl(cmd *cobra.Command, args []string) error {
 func runMapDashboard(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	switch dashboardGroupBy {
+	case "", "env", "namespace":
+	default:
+		return fmt.Errorf("invalid --group-by %q (valid: env, namespace)", dashboardGroupBy)
+	}
+
 	cfg, err := buildConfig()
 	if err != nil {
 		return fmt.Errorf("build kubernetes config: %w", err)
@@ -1649,22 +2111,26 @@ func runMapDashboard(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
+	loadDelegations(ctx, dynClient)
+
+	groupOf := newDashboardGrouper(ctx, dynClient, dashboardGroupBy)
+	groups := dashboardGroups{}
+	if dashboardGroupBy == "" {
+		groups.get("")
+	}
 
 	fmt.Println("📊 CLUSTER DASHBOARD")
 	fmt.Println()
 
 	// === HEALTH SECTION (from runMapStatus logic) ===
-	deployersReady, deployersTotal := 0, 0
+	// Deployers are grouped by the namespace they deploy to
 
 	// Check Flux Kustomizations
 	if ksList, err := dynClient.Resource(schema.GroupVersionResource{
 		Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations",
 	}).List(ctx, v1.ListOptions{}); err == nil {
 		for _, ks := range ksList.Items {
-			deployersTotal++
-			if isResourceReady(&ks) {
-				deployersReady++
-			}
+			groups.get(groupOf(deployerGroupNamespace(&ks), ks.GetLabels())).addDeployer(isResourceReady(&ks))
 		}
 	}
 
@@ -1673,10 +2139,7 @@ func runMapDashboard(cmd *cobra.Command, args []string) error {
 		Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases",
 	}).List(ctx, v1.ListOptions{}); err == nil {
 		for _, hr := range hrList.Items {
-			deployersTotal++
-			if isResourceReady(&hr) {
-				deployersReady++
-			}
+			groups.get(groupOf(deployerGroupNamespace(&hr), hr.GetLabels())).addDeployer(isResourceReady(&hr))
 		}
 	}
 
@@ -1685,18 +2148,11 @@ func runMapDashboard(cmd *cobra.Command, args []string) error {
 		Group: "argoproj.io", Version: "v1alpha1", Resource: "applications",
 	}).List(ctx, v1.ListOptions{}); err == nil {
 		for _, app := range appList.Items {
-			deployersTotal++
-			if isArgoAppHealthy(&app) {
-				deployersReady++
-			}
+			groups.get(groupOf(deployerGroupNamespace(&app), app.GetLabels())).addDeployer(isArgoAppHealthy(&app))
 		}
 	}
 
-	// Count workloads
-	workloadsReady, workloadsTotal := 0, 0
-	var fluxCount, argoCount, helmCount, configHubCount, nativeCount int
-
-	// Check Deployments
+	// Check Deployments, counting them by owner
 	if depList, err := dynClient.Resource(schema.GroupVersionResource{
 		Group: "apps", Version: "v1", Resource: "deployments",
 	}).List(ctx, v1.ListOptions{}); err == nil {
@@ -1705,70 +2161,12 @@ func runMapDashboard(cmd *cobra.Command, args []string) error {
 			if isSystemNamespace(ns) {
 				continue
 			}
-			workloadsTotal++
-			if isDeploymentReady(&dep) {
-				workloadsReady++
-			}
-			// Count by owner
 			owner, _ := detectOwnership(&dep)
-			switch owner {
-			case "Flux":
-				fluxCount++
-			case "ArgoCD":
-				argoCount++
-			case "Helm":
-				helmCount++
-			case "ConfigHub":
-				configHubCount++
-			case "Native":
-				nativeCount++
-			}
+			groups.get(groupOf(ns, dep.GetLabels())).addWorkload(isDeploymentReady(&dep), owner)
 		}
 	}
 
-	// Health status
-	problems := (deployersTotal - deployersReady) + (workloadsTotal - workloadsReady)
-	if problems == 0 {
-		fmt.Printf("HEALTH: ✓ healthy  %d/%d deployers, %d/%d workloads\n",
-			deployersReady, deployersTotal, workloadsReady, workloadsTotal)
-	} else {
-		fmt.Printf("HEALTH: ✗ %d problem(s)  %d/%d deployers, %d/%d workloads\n",
-			problems, deployersReady, deployersTotal, workloadsReady, workloadsTotal)
-	}
-
-	// === OWNERSHIP SECTION (from runMapSprawl logic) ===
-	total := fluxCount + argoCount + helmCount + configHubCount + nativeCount
-	managed := total - nativeCount
-
-	coverage := 0
-	if total > 0 {
-		coverage = (managed * 100) / total
-	}
-
-	fmt.Printf("COVERAGE: %d%% GitOps managed\n", coverage)
-	fmt.Println()
-
-	fmt.Println("BY OWNER:")
-	if fluxCount > 0 {
-		fmt.Printf("  Flux      %3d %s\n", fluxCount, makeBar(fluxCount, total, 20))
-	}
-	if argoCount > 0 {
-		fmt.Printf("  ArgoCD    %3d %s\n", argoCount, makeBar(argoCount, total, 20))
-	}
-	if helmCount > 0 {
-		fmt.Printf("  Helm      %3d %s\n", helmCount, makeBar(helmCount, total, 20))
-	}
-	if configHubCount > 0 {
-		fmt.Printf("  ConfigHub %3d %s\n", configHubCount, makeBar(configHubCount, total, 20))
-	}
-	if nativeCount > 0 {
-		fmt.Printf("  Native    %3d %s  ⚠ SHADOW IT\n", nativeCount, makeBar(nativeCount, total, 20))
-	}
-
-	if nativeCount > 0 {
-		fmt.Printf("\n⚠ %d native workload(s) - run: cub-scout map bypass\n", nativeCount)
-	}
-
+	writeDashboard(os.Stdout, dashboardGroupBy, groups)
 	return nil
 }
 
@@ -1784,6 +2182,7 @@ func runMapBypass(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
+	loadDelegations(ctx, dynClient)
 
 	fmt.Println("🚧 FACTORY BYPASS DETECTION")
 	fmt.Println()
@@ -1794,7 +2193,7 @@ func runMapBypass(cmd *cobra.Command, args []string) error {
 	fmt.Fprintln(w, "NAMESPACE\tNAME\tIMAGE")
 	fmt.Fprintln(w, "─────────\t────\t─────")
 
-	var nativeCount int
+	var nativeCount, ackedCount int
 
 	// Get all Deployments
 	if depList, err := dynClient.Resource(schema.GroupVersionResource{
@@ -1808,6 +2207,10 @@ func runMapBypass(cmd *cobra.Command, args []string) error {
 
 			owner, _ := detectOwnership(&dep)
 			if owner == "Native" {
+				if isAcknowledged("Deployment", ns, dep.GetName()) {
+					ackedCount++
+					continue
+				}
 				nativeCount++
 				image := getContainerImage(&dep)
 				fmt.Fprintf(w, "%s\t%s\t%s\n", ns, dep.GetName(), image)
@@ -1824,6 +2227,10 @@ func runMapBypass(cmd *cobra.Command, args []string) error {
 		fmt.Println("\nRecommendations:")
 		fmt.Println("  1. Add GitOps manifests for these workloads")
 		fmt.Println("  2. Or import them: cub-scout map (press 'i' to import)")
+		fmt.Println("  3. Or, if unmanaged on purpose: cub-scout ack add deploy/<name> -n <namespace> --reason ...")
+	}
+	if ackedCount > 0 {
+		fmt.Printf("%s(%d acknowledged native workload(s) not shown; see cub-scout ack list)%s\n", colorDim, ackedCount, colorReset)
 	}
 
 	return nil
@@ -1905,7 +2312,11 @@ func getConditionReason(obj *unstructured.Unstructured) string {
 
 // detectStatus determines the status string for a resource
 // Returns: "Ready", "NotReady", "Failed", "Pending", "Unknown"
+// Rules from the status rules file win over the built-in checks.
 func detectStatus(obj *unstructured.Unstructured) string {
+	if status, ok := activeStatusRules().Match(obj); ok {
+		return status
+	}
 	kind := obj.GetKind()
 
 	switch kind {
@@ -2071,6 +2482,11 @@ func detectOwnership(obj *unstructured.Unstructured) (string, string) {
 		annotations = map[string]string{}
 	}
 
+	// Flux/Argo applying a ConfigHub OCI artifact
+	if d := ownerDelegations.DetectDelegation(obj); d != nil {
+		return "ConfigHub", delegatedManagedBy(obj, d)
+	}
+
 	// ConfigHub
 	if slug, ok := labels["confighub.com/UnitSlug"]; ok {
 		return "ConfigHub", slug
@@ -2083,16 +2499,9 @@ func detectOwnership(obj *unstructured.Unstructured) (string, string) {
 	if name, ok := labels["helm.toolkit.fluxcd.io/name"]; ok {
 		return "Flux", name
 	}
-	// ArgoCD - check both label and tracking-id annotation
-	if instance, ok := labels["argocd.argoproj.io/instance"]; ok {
-		return "ArgoCD", instance
-	}
-	// ArgoCD tracking-id annotation (format: <app-name>:<group>/<kind>:<namespace>/<name>)
-	if tracking, ok := annotations["argocd.argoproj.io/tracking-id"]; ok {
-		parts := strings.SplitN(tracking, ":", 2)
-		if len(parts) > 0 && parts[0] != "" {
-			return "ArgoCD", parts[0]
-		}
+	// ArgoCD - tracking-id annotation or instance label, per the tracking method
+	if _, app, _ := agent.ArgoApplication(labels, annotations); app != "" {
+		return "ArgoCD", app
 	}
 	// Helm
 	if labels["app.kubernetes.io/managed-by"] == "Helm" {
@@ -2125,6 +2534,17 @@ func getContainerImage(obj *unstructured.Unstructured) string {
 }
 
 // runMapCrashes shows crashing pods (focused on pod-level health issues)
+// CrashInfo is a crashing pod reported by map crashes.
+type CrashInfo struct {
+	Namespace string      `json:"namespace"`
+	PodName   string      `json:"pod"`
+	Status    string      `json:"status"`
+	Restarts  int64       `json:"restarts"`
+	Age       string      `json:"age"`
+	NodeName  string      `json:"node,omitempty"`
+	Node      *NodeHealth `json:"nodeHealth,omitempty"`
+}
+
 func runMapCrashes(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -2138,15 +2558,7 @@ func runMapCrashes(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
 
-	type crashInfo struct {
-		namespace string
-		podName   string
-		status    string
-		restarts  int64
-		age       string
-	}
-
-	crashes := []crashInfo{}
+	crashes := []CrashInfo{}
 
 	// List all pods
 	podGVR := schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
@@ -2156,6 +2568,7 @@ func runMapCrashes(cmd *cobra.Command, args []string) error {
 	}
 
 	now := time.Now()
+	nodes := loadNodeHealth(ctx, dynClient, now)
 
 	for _, pod := range podList.Items {
 		ns := pod.GetNamespace()
@@ -2252,17 +2665,39 @@ func runMapCrashes(cmd *cobra.Command, args []string) error {
 				ageStr = fmt.Sprintf("%dm", int(age.Minutes()))
 			}
 
-			crashes = append(crashes, crashInfo{
-				namespace: ns,
-				podName:   pod.GetName(),
-				status:    crashStatus,
-				restarts:  totalRestarts,
-				age:       ageStr,
-			})
+			info := CrashInfo{
+				Namespace: ns,
+				PodName:   pod.GetName(),
+				Status:    crashStatus,
+				Restarts:  totalRestarts,
+				Age:       ageStr,
+			}
+
+			// Attach node context so "the node died" is visible next to the crash
+			if nodeName, _, _ := unstructured.NestedString(pod.Object, "spec", "nodeName"); nodeName != "" {
+				info.NodeName = nodeName
+				if h, ok := nodes[nodeName]; ok {
+					info.Node = &h
+				} else if len(nodes) > 0 {
+					info.Node = &NodeHealth{Name: nodeName, NotFound: true}
+				}
+			}
+
+			crashes = append(crashes, info)
 		}
 	}
 
-	if len(crashes) == 0 {
+	sortResources(crashes, func(c CrashInfo) (string, string, string) { return c.Namespace, "Pod", c.PodName })
+	crashes, page, err := paginate(crashes, mapPage, mapPageSize)
+	if err != nil {
+		return err
+	}
+
+	if mapJSON {
+		return writeJSON(os.Stdout, "CrashList", crashes)
+	}
+
+	if page.Total == 0 {
 		fmt.Println("✓ No crashing pods found")
 		return nil
 	}
@@ -2276,15 +2711,52 @@ func runMapCrashes(cmd *cobra.Command, args []string) error {
 
 	// Print table
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "NAMESPACE\tPOD\tSTATUS\tRESTARTS\tAGE")
-	fmt.Fprintln(w, "─────────\t───\t──────\t────────\t───")
+	fmt.Fprintln(w, "NAMESPACE\tPOD\tSTATUS\tRESTARTS\tAGE\tNODE\tNODE STATUS")
+	fmt.Fprintln(w, "─────────\t───\t──────\t────────\t───\t────\t───────────")
+	unhealthyNodes := map[string]NodeHealth{}
 	for _, c := range crashes {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", c.namespace, c.podName, c.status, c.restarts, c.age)
+		node := c.NodeName
+		if node == "" {
+			node = "-"
+		}
+		nodeStatus := "-"
+		if c.Node != nil {
+			nodeStatus = nodeStatusLabel(*c.Node)
+			if !c.Node.Healthy() {
+				unhealthyNodes[c.Node.Name] = *c.Node
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", c.Namespace, c.PodName, c.Status, c.Restarts, c.Age, node, nodeStatus)
 	}
 	w.Flush()
 
 	// Summary
-	fmt.Printf("\n%d crashing pods\n", len(crashes))
+	fmt.Printf("\n%d crashing pods\n", page.Total)
+	printPageFooter(os.Stdout, page)
+
+	// Node context: catch "it's not GitOps, the node died" early
+	if len(unhealthyNodes) > 0 {
+		names := make([]string, 0, len(unhealthyNodes))
+		for name := range unhealthyNodes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Println()
+		fmt.Println("NODE PROBLEMS")
+		fmt.Println("These crashes may be caused by the node, not the workload or its deployer.")
+		for _, name := range names {
+			h := unhealthyNodes[name]
+			problems := h.Problems()
+			if len(problems) == 0 {
+				problems = []string{"recent events"}
+			}
+			fmt.Printf("⚠ %s: %s\n", name, strings.Join(problems, ", "))
+			for _, ev := range h.Events {
+				fmt.Printf("    %s\n", ev)
+			}
+		}
+	}
 
 	// Next steps
 	fmt.Println()
@@ -2292,6 +2764,9 @@ func runMapCrashes(cmd *cobra.Command, args []string) error {
 	fmt.Println("→ View logs:     kubectl logs -n <namespace> <pod> --previous")
 	fmt.Println("→ Describe pod:  kubectl describe pod -n <namespace> <pod>")
 	fmt.Println("→ Trace owner:   cub-scout trace pod/<name> -n <namespace>")
+	if len(unhealthyNodes) > 0 {
+		fmt.Println("→ Inspect node:  kubectl describe node <node>")
+	}
 
 	return nil
 }
@@ -2309,8 +2784,10 @@ func runMapOrphans(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
-	// Set the owner filter to Native and run list
+	// Set the owner filter to Native and run list, leaving out acknowledged
+	// orphans unless asked for
 	mapOwner = "Native"
+	mapHideAcknowledged = !mapShowAcknowledged
 	err := runMapList(cmd, args)
 
 	// Print next steps if in table mode and no error
@@ -2364,8 +2841,7 @@ type cubUnitCache struct {
 // fetchConfigHubUnits fetches all units from ConfigHub
 func fetchConfigHubUnits() (*cubUnitCache, error) {
 	// Get current context (this will fail if not authenticated)
-	ctxCmd := exec.Command("cub", "context", "get", "--json")
-	ctxOut, err := ctxCmd.Output()
+	ctxOut, err := runCubCommand("context", "get", "--json")
 	if err != nil {
 		return nil, fmt.Errorf("ConfigHub authentication required.\n\n  To authenticate: cub auth login\n  To use standalone: cub-scout map (without --hub)")
 	}
@@ -2383,8 +2859,7 @@ func fetchConfigHubUnits() (*cubUnitCache, error) {
 	}
 
 	// Fetch units
-	listCmd := exec.Command("cub", "unit", "list", "--json", "--quiet")
-	listOut, err := listCmd.Output()
+	listOut, err := runCubCommand("unit", "list", "--json", "--quiet")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list units: %w", err)
 	}
@@ -2420,8 +2895,7 @@ func fetchConfigHubUnits() (*cubUnitCache, error) {
 	}
 
 	// Fetch links for dependency info
-	linksCmd := exec.Command("cub", "link", "list", "--json", "--quiet")
-	linksOut, err := linksCmd.Output()
+	linksOut, err := runCubCommand("link", "list", "--json", "--quiet")
 	if err == nil {
 		var linkList []struct {
 			FromUnit struct {
@@ -2444,8 +2918,7 @@ func fetchConfigHubUnits() (*cubUnitCache, error) {
 	}
 
 	// Fetch all spaces for cross-space correlation
-	spacesCmd := exec.Command("cub", "space", "list", "--json", "--quiet")
-	spacesOut, err := spacesCmd.Output()
+	spacesOut, err := runCubCommand("space", "list", "--json", "--quiet")
 	if err == nil {
 		var spaceList []struct {
 			Space struct {
@@ -2482,8 +2955,7 @@ func fetchConfigHubUnits() (*cubUnitCache, error) {
 		}
 
 		// Query units in this related space
-		otherUnitsCmd := exec.Command("cub", "unit", "list", "--json", "--quiet", "--space", otherSpace)
-		otherUnitsOut, err := otherUnitsCmd.Output()
+		otherUnitsOut, err := runCubCommand("unit", "list", "--json", "--quiet", "--space", otherSpace)
 		if err != nil {
 			continue
 		}
@@ -2567,17 +3039,30 @@ func printConfigHubContext(unit *cubUnitInfo) {
 // runMapClusterData shows all data sources read from cluster with MAXIMUM detail
 // CLI equivalent of TUI's '4' key (Cluster Data view)
 func runMapClusterData(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	if deepDiveOpenUI != "" {
+		return runOpenControllerUI(deepDiveOpenUI)
+	}
+
+	// Values, notes and URLs are printed as found; mask secrets among them
+	defer redactStdout()()
+
+	ctx, cancel := mapListContext()
+	defer cancel()
 
 	cfg, err := buildConfig()
 	if err != nil {
 		return fmt.Errorf("build kubernetes config: %w", err)
 	}
 
-	dynClient, err := dynamic.NewForConfig(cfg)
+	// Sections skip resource types they cannot list; report those at the end
+	client, err := dynamic.NewForConfig(cfg)
 	if err != nil {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
+	rec := &listRecorder{}
+	dynClient := newRecordingClient(client, rec)
+	defer func() { printPartialResults(os.Stdout, rec.Failures()) }()
+	loadDelegations(ctx, dynClient)
 
 	// Connected mode: fetch ConfigHub data
 	var unitCache *cubUnitCache
@@ -2618,6 +3103,7 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 	fmt.Println("───────────────────────────────────────────────────────────────────────────────")
 
 	fluxInstalled := false
+	flux := agent.NewFluxResolver(agent.ClientFluxLookup(dynClient))
 	if gitList, err := dynClient.Resource(schema.GroupVersionResource{
 		Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories",
 	}).List(ctx, v1.ListOptions{}); err == nil {
@@ -3047,12 +3533,6 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 			name := ks.GetName()
 			ns := ks.GetNamespace()
 			path, _, _ := unstructured.NestedString(ks.Object, "spec", "path")
-			sourceKind, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "kind")
-			sourceName, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "name")
-			sourceNs, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "namespace")
-			if sourceNs == "" {
-				sourceNs = ns
-			}
 			targetNs, _, _ := unstructured.NestedString(ks.Object, "spec", "targetNamespace")
 			interval, _, _ := unstructured.NestedString(ks.Object, "spec", "interval")
 			timeout, _, _ := unstructured.NestedString(ks.Object, "spec", "timeout")
@@ -3090,7 +3570,10 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 			}
 
 			fmt.Printf("\n%s %s/%s\n", icon, ns, name)
-			fmt.Printf("  Source:     %s/%s/%s\n", sourceNs, sourceKind, sourceName)
+			fmt.Printf("  Source:     %s\n", fluxSourceSummary(ctx, flux, &ks))
+			if d := ownerDelegations.Deployer("Kustomization", ns, name); d != nil {
+				fmt.Printf("  ConfigHub:  %s/%s (delegated apply via %s)\n", d.Space, d.Target, d.Via)
+			}
 			fmt.Printf("  Path:       %s\n", path)
 			if targetNs != "" {
 				fmt.Printf("  TargetNS:   %s\n", targetNs)
@@ -3139,6 +3622,7 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 			if observedGen > 0 {
 				fmt.Printf("  Generation: %d\n", observedGen)
 			}
+			printFluxDependsOn(ctx, os.Stdout, flux, &ks)
 			fmt.Printf("  Inventory:  %d resources\n", len(inventory))
 			// Show inventory with API versions
 			for i, item := range inventory {
@@ -3327,8 +3811,6 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 			ns := hr.GetNamespace()
 			chartName, _, _ := unstructured.NestedString(hr.Object, "spec", "chart", "spec", "chart")
 			chartVersion, _, _ := unstructured.NestedString(hr.Object, "spec", "chart", "spec", "version")
-			sourceKind, _, _ := unstructured.NestedString(hr.Object, "spec", "chart", "spec", "sourceRef", "kind")
-			sourceName, _, _ := unstructured.NestedString(hr.Object, "spec", "chart", "spec", "sourceRef", "name")
 			targetNs, _, _ := unstructured.NestedString(hr.Object, "spec", "targetNamespace")
 			interval, _, _ := unstructured.NestedString(hr.Object, "spec", "interval")
 
@@ -3349,7 +3831,7 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 
 			fmt.Printf("\n%s %s/%s\n", icon, ns, name)
 			fmt.Printf("  Chart:      %s (version: %s)\n", chartName, chartVersion)
-			fmt.Printf("  Source:     %s/%s\n", sourceKind, sourceName)
+			fmt.Printf("  Source:     %s\n", fluxSourceSummary(ctx, flux, &hr))
 			if targetNs != "" {
 				fmt.Printf("  TargetNS:   %s\n", targetNs)
 			}
@@ -3358,6 +3840,7 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 			if lastApplied != "" {
 				fmt.Printf("  Revision:   %s (release #%d)\n", lastApplied, lastReleaseRev)
 			}
+			printFluxDependsOn(ctx, os.Stdout, flux, &hr)
 			fmt.Printf("  Values:     %d inline keys, %d external sources\n", len(values), len(valuesFrom))
 		}
 	} else {
@@ -3498,6 +3981,9 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 				fmt.Printf("  Project:    %s\n", project)
 			}
 			fmt.Printf("  Repo:       %s\n", repoURL)
+			if d := ownerDelegations.Deployer("Application", ns, name); d != nil {
+				fmt.Printf("  ConfigHub:  %s/%s (delegated apply via %s)\n", d.Space, d.Target, d.Via)
+			}
 			if path != "" {
 				fmt.Printf("  Path:       %s\n", path)
 			}
@@ -3869,6 +4355,7 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 		annotations    map[string]string
 		podLabels      map[string]string
 		podAnnotations map[string]string
+		obj            unstructured.Unstructured
 	}
 	var workloads []workloadInfo
 
@@ -3918,6 +4405,7 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 				annotations:    dep.GetAnnotations(),
 				podLabels:      podLabelsRaw,
 				podAnnotations: podAnnotationsRaw,
+				obj:            dep,
 			})
 		}
 	}
@@ -3966,6 +4454,7 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 				annotations:    sts.GetAnnotations(),
 				podLabels:      podLabelsRaw,
 				podAnnotations: podAnnotationsRaw,
+				obj:            sts,
 			})
 		}
 	}
@@ -3976,6 +4465,15 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 		byOwner[w.owner] = append(byOwner[w.owner], w)
 	}
 
+	// LiveTree for workloads no Flux/Argo/Helm section above covers
+	var liveReplicaSets, livePods []unstructured.Unstructured
+	if l, err := dynClient.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}).List(ctx, v1.ListOptions{}); err == nil {
+		liveReplicaSets = l.Items
+	}
+	if l, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).List(ctx, v1.ListOptions{}); err == nil {
+		livePods = l.Items
+	}
+
 	for _, owner := range []string{"Flux", "ArgoCD", "Helm", "ConfigHub", "Native"} {
 		wls := byOwner[owner]
 		if len(wls) == 0 {
@@ -4025,9 +4523,9 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 				hrNs := w.labels["helm.toolkit.fluxcd.io/namespace"]
 				fmt.Printf("    FluxHR:     %s/%s\n", hrNs, hrName)
 			}
-			// Show ArgoCD labels
-			if argoInstance, ok := w.labels["argocd.argoproj.io/instance"]; ok {
-				fmt.Printf("    ArgoApp:    %s\n", argoInstance)
+			// Show ArgoCD tracking
+			if _, argoApp, source := agent.ArgoApplication(w.labels, w.annotations); argoApp != "" {
+				fmt.Printf("    ArgoApp:    %s (%s)\n", argoApp, source)
 			}
 			// Show Helm labels
 			if helmChart, ok := w.labels["helm.sh/chart"]; ok {
@@ -4068,6 +4566,10 @@ func runMapClusterData(cmd *cobra.Command, args []string) error {
 					fmt.Println("                (use --connected for full Unit context)")
 				}
 			}
+			if owner == "ConfigHub" || owner == "Native" {
+				fmt.Println("    LiveTree:")
+				printLiveTree(os.Stdout, buildLiveTree(&w.obj, liveReplicaSets, livePods), "      ", false)
+			}
 		}
 	}
 	fmt.Println()
@@ -4732,6 +5234,7 @@ func runMapAppHierarchy(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("create dynamic client: %w", err)
 	}
+	loadDelegations(ctx, dynClient)
 
 	fmt.Println("═══════════════════════════════════════════════════════════════════════════════")
 	fmt.Println("                    RICH APPLICATION HIERARCHY (STANDALONE)")
@@ -5671,7 +6174,7 @@ func runMapAppHierarchy(cmd *cobra.Command, args []string) error {
 				workloadToDeployer[key] = fmt.Sprintf("Kustomization/%s", ksName)
 			} else if hrName, ok := labels["helm.toolkit.fluxcd.io/name"]; ok {
 				workloadToDeployer[key] = fmt.Sprintf("HelmRelease/%s", hrName)
-			} else if argoApp, ok := labels["argocd.argoproj.io/instance"]; ok {
+			} else if _, argoApp, _ := agent.ArgoApplication(labels, dep.GetAnnotations()); argoApp != "" {
 				workloadToDeployer[key] = fmt.Sprintf("Application/%s", argoApp)
 			}
 		}
@@ -0,0 +1,351 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	configRefsUnreferenced bool
+	configRefsShared       bool
+)
+
+var mapConfigMapsCmd = &cobra.Command{
+	Use:     "configmaps",
+	Aliases: []string{"cm"},
+	Short:   "Show which workloads use each ConfigMap, and unused or shared ones",
+	Long: `Show ConfigMaps, the workloads that mount or read them, and who owns both.
+
+A ConfigMap is referenced by a workload through a volume (including projected
+volumes), env valueFrom or envFrom in any container of its pod template.
+
+Flagged:
+  unreferenced   no workload uses it (a cleanup candidate)
+  shared         workloads with different owners use it; a change made for
+                 one owner silently changes the others
+
+kube-root-ca.crt, present in every namespace, is skipped.
+
+Examples:
+  cub-scout map configmaps
+  cub-scout map configmaps --unreferenced
+  cub-scout map configmaps --shared --namespace prod --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { return runMapConfigRefs("ConfigMap") },
+}
+
+var mapSecretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Show which workloads use each Secret, and unused or shared ones",
+	Long: `Show Secrets, the workloads and Ingresses that use them, and who owns both.
+
+A Secret is referenced through a volume (including projected volumes), env
+valueFrom, envFrom or imagePullSecrets of a pod template, or as an Ingress
+TLS secret. Secret values are never read.
+
+Flagged:
+  unreferenced   nothing uses it (a cleanup candidate)
+  shared         workloads with different owners use it; rotating it for one
+                 owner affects the others
+
+Service account tokens and Helm release secrets are skipped.
+
+Examples:
+  cub-scout map secrets
+  cub-scout map secrets --unreferenced
+  cub-scout map secrets --shared --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { return runMapConfigRefs("Secret") },
+}
+
+func init() {
+	for _, c := range []*cobra.Command{mapConfigMapsCmd, mapSecretsCmd} {
+		c.Flags().StringVar(&mapNamespace, "namespace", "", "Filter by namespace")
+		_ = c.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+		c.Flags().BoolVar(&configRefsUnreferenced, "unreferenced", false, "Only show unreferenced objects")
+		c.Flags().BoolVar(&configRefsShared, "shared", false, "Only show objects shared across owners")
+		mapCmd.AddCommand(c)
+	}
+}
+
+// ConfigReference is a ConfigMap or Secret and the workloads that use it.
+type ConfigReference struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"` // ConfigMap, Secret
+	Name      string `json:"name"`
+	Owner     string `json:"owner"`
+	// ReferencedBy is Kind/name of each workload or Ingress using it
+	ReferencedBy   []string `json:"referencedBy,omitempty"`
+	ReferrerOwners []string `json:"referrerOwners,omitempty"`
+	Unreferenced   bool     `json:"unreferenced,omitempty"`
+	// SharedAcrossOwners is set when referrers have more than one owner
+	SharedAcrossOwners bool `json:"sharedAcrossOwners,omitempty"`
+}
+
+// configSkipped reports objects every cluster has that nothing references by
+// name: the root CA bundle, service account tokens and Helm release records.
+func configSkipped(obj *unstructured.Unstructured) bool {
+	if obj.GetKind() == "ConfigMap" {
+		return obj.GetName() == "kube-root-ca.crt"
+	}
+	t, _, _ := unstructured.NestedString(obj.Object, "type")
+	return t == "kubernetes.io/service-account-token" || strings.HasPrefix(t, "helm.sh/release")
+}
+
+func runMapConfigRefs(kind string) error {
+	ctx := context.Background()
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	list := func(gvr schema.GroupVersionResource) []unstructured.Unstructured {
+		l, err := dynClient.Resource(gvr).Namespace(mapNamespace).List(ctx, v1.ListOptions{})
+		if err != nil {
+			return nil // Not installed or no access
+		}
+		return l.Items
+	}
+
+	resource := strings.ToLower(kind) + "s"
+	objList, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: resource}).Namespace(mapNamespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list %s: %w", resource, err)
+	}
+
+	var referrers []unstructured.Unstructured
+	for _, res := range []string{"deployments", "statefulsets", "daemonsets"} {
+		referrers = append(referrers, list(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: res})...)
+	}
+	referrers = append(referrers, list(schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"})...)
+	// Bare pods; controller-owned pods are covered by their workloads
+	for _, pod := range list(schema.GroupVersionResource{Version: "v1", Resource: "pods"}) {
+		if len(pod.GetOwnerReferences()) == 0 {
+			referrers = append(referrers, pod)
+		}
+	}
+	if kind == "Secret" {
+		referrers = append(referrers, list(schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"})...)
+	}
+
+	refs := buildConfigReferences(objList.Items, referrers)
+	filtered := refs[:0]
+	for _, r := range refs {
+		if (configRefsUnreferenced && !r.Unreferenced) || (configRefsShared && !r.SharedAcrossOwners) {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	refs = filtered
+
+	if mapJSON {
+		return writeJSON(os.Stdout, "ConfigReferences", refs)
+	}
+	printConfigReferences(os.Stdout, kind, refs)
+	return nil
+}
+
+// buildConfigReferences correlates ConfigMaps or Secrets with the workloads
+// and Ingresses that reference them by name in the same namespace.
+func buildConfigReferences(objs, referrers []unstructured.Unstructured) []ConfigReference {
+	// "Kind/namespace/name" -> referrer Kind/name, and referrer owners
+	used := map[string][]string{}
+	usedOwners := map[string][]string{}
+	for i := range referrers {
+		r := &referrers[i]
+		owner := displayOwner(agent.DetectOwnership(r).Type)
+		label := r.GetKind() + "/" + r.GetName()
+		for _, key := range configRefsOf(r) {
+			used[key] = appendUnique(used[key], label)
+			usedOwners[key] = appendUnique(usedOwners[key], owner)
+		}
+	}
+
+	result := []ConfigReference{}
+	for i := range objs {
+		obj := &objs[i]
+		if isSystemNamespace(obj.GetNamespace()) || configSkipped(obj) {
+			continue
+		}
+		key := obj.GetKind() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+		ref := ConfigReference{
+			Namespace:      obj.GetNamespace(),
+			Kind:           obj.GetKind(),
+			Name:           obj.GetName(),
+			Owner:          displayOwner(agent.DetectOwnership(obj).Type),
+			ReferencedBy:   used[key],
+			ReferrerOwners: usedOwners[key],
+		}
+		sort.Strings(ref.ReferencedBy)
+		sort.Strings(ref.ReferrerOwners)
+		ref.Unreferenced = len(ref.ReferencedBy) == 0
+		ref.SharedAcrossOwners = len(ref.ReferrerOwners) > 1
+		result = append(result, ref)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// configRefsOf returns "Kind/namespace/name" keys of the ConfigMaps and
+// Secrets a workload, CronJob, bare Pod or Ingress references.
+func configRefsOf(obj *unstructured.Unstructured) []string {
+	ns := obj.GetNamespace()
+	var keys []string
+	add := func(kind, name string) {
+		if name != "" {
+			keys = appendUnique(keys, kind+"/"+ns+"/"+name)
+		}
+	}
+
+	if obj.GetKind() == "Ingress" {
+		tls, _, _ := unstructured.NestedSlice(obj.Object, "spec", "tls")
+		for _, t := range tls {
+			if m, ok := t.(map[string]interface{}); ok {
+				name, _ := m["secretName"].(string)
+				add("Secret", name)
+			}
+		}
+		return keys
+	}
+
+	var podSpec map[string]interface{}
+	switch obj.GetKind() {
+	case "Pod":
+		podSpec, _, _ = unstructured.NestedMap(obj.Object, "spec")
+	case "CronJob":
+		podSpec, _, _ = unstructured.NestedMap(obj.Object, "spec", "jobTemplate", "spec", "template", "spec")
+	default:
+		podSpec, _, _ = unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+	}
+	if podSpec == nil {
+		return nil
+	}
+
+	volumes, _, _ := unstructured.NestedSlice(podSpec, "volumes")
+	for _, v := range volumes {
+		vol, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(vol, "configMap", "name")
+		add("ConfigMap", name)
+		name, _, _ = unstructured.NestedString(vol, "secret", "secretName")
+		add("Secret", name)
+		sources, _, _ := unstructured.NestedSlice(vol, "projected", "sources")
+		for _, s := range sources {
+			src, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(src, "configMap", "name")
+			add("ConfigMap", name)
+			name, _, _ = unstructured.NestedString(src, "secret", "name")
+			add("Secret", name)
+		}
+	}
+
+	pullSecrets, _, _ := unstructured.NestedSlice(podSpec, "imagePullSecrets")
+	for _, p := range pullSecrets {
+		if m, ok := p.(map[string]interface{}); ok {
+			name, _ := m["name"].(string)
+			add("Secret", name)
+		}
+	}
+
+	for _, field := range []string{"initContainers", "containers", "ephemeralContainers"} {
+		containers, _, _ := unstructured.NestedSlice(podSpec, field)
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			env, _, _ := unstructured.NestedSlice(container, "env")
+			for _, e := range env {
+				ev, ok := e.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _, _ := unstructured.NestedString(ev, "valueFrom", "configMapKeyRef", "name")
+				add("ConfigMap", name)
+				name, _, _ = unstructured.NestedString(ev, "valueFrom", "secretKeyRef", "name")
+				add("Secret", name)
+			}
+			envFrom, _, _ := unstructured.NestedSlice(container, "envFrom")
+			for _, e := range envFrom {
+				ef, ok := e.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _, _ := unstructured.NestedString(ef, "configMapRef", "name")
+				add("ConfigMap", name)
+				name, _, _ = unstructured.NestedString(ef, "secretRef", "name")
+				add("Secret", name)
+			}
+		}
+	}
+	return keys
+}
+
+func printConfigReferences(w io.Writer, kind string, refs []ConfigReference) {
+	plural := kind + "s"
+	if len(refs) == 0 {
+		fmt.Fprintf(w, "No %s found\n", plural)
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "NAMESPACE\t%s\tOWNER\tUSED BY\tNOTE\n", strings.ToUpper(kind))
+	var unreferenced, shared int
+	for _, r := range refs {
+		usedBy := "-"
+		if len(r.ReferencedBy) > 0 {
+			usedBy = strings.Join(r.ReferencedBy, ", ") + " [" + strings.Join(r.ReferrerOwners, ",") + "]"
+		}
+		note := ""
+		switch {
+		case r.Unreferenced:
+			unreferenced++
+			note = "unreferenced"
+		case r.SharedAcrossOwners:
+			shared++
+			note = "⚠ shared across owners"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Namespace, r.Name, r.Owner, usedBy, note)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n%d %s: %d unreferenced, %d shared across owners\n", len(refs), plural, unreferenced, shared)
+	if unreferenced > 0 {
+		fmt.Fprintf(w, "%sUnreferenced %s are cleanup candidates; check for use outside pod specs (operators, CRDs) before deleting.%s\n", colorDim, plural, colorReset)
+	}
+	if shared > 0 {
+		fmt.Fprintf(w, "%s⚠ Shared %s couple deployers: a change by one owner reaches workloads of the others.%s\n", colorYellow, plural, colorReset)
+	}
+}
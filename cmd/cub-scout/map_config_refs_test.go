@@ -0,0 +1,140 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestConfigObject(kind, namespace, name string, labels map[string]string) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": kind}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func newTestConfigWorkload(kind, namespace, name string, labels map[string]string, podSpec map[string]interface{}) unstructured.Unstructured {
+	spec := map[string]interface{}{"template": map[string]interface{}{"spec": podSpec}}
+	switch kind {
+	case "Pod":
+		spec = podSpec
+	case "CronJob":
+		spec = map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": spec}}
+	}
+	u := unstructured.Unstructured{Object: map[string]interface{}{"kind": kind, "spec": spec}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func TestConfigRefsOf(t *testing.T) {
+	wl := newTestConfigWorkload("Deployment", "prod", "api", nil, map[string]interface{}{
+		"volumes": []interface{}{
+			map[string]interface{}{"name": "cfg", "configMap": map[string]interface{}{"name": "api-config"}},
+			map[string]interface{}{"name": "tls", "secret": map[string]interface{}{"secretName": "api-tls"}},
+			map[string]interface{}{"name": "all", "projected": map[string]interface{}{"sources": []interface{}{
+				map[string]interface{}{"configMap": map[string]interface{}{"name": "shared-ca"}},
+				map[string]interface{}{"secret": map[string]interface{}{"name": "api-token"}},
+			}}},
+		},
+		"imagePullSecrets": []interface{}{map[string]interface{}{"name": "registry"}},
+		"initContainers": []interface{}{map[string]interface{}{
+			"envFrom": []interface{}{map[string]interface{}{"configMapRef": map[string]interface{}{"name": "init-env"}}},
+		}},
+		"containers": []interface{}{map[string]interface{}{
+			"env": []interface{}{
+				map[string]interface{}{"name": "A", "value": "plain"},
+				map[string]interface{}{"name": "DB", "valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "db", "key": "url"}}},
+				map[string]interface{}{"name": "MODE", "valueFrom": map[string]interface{}{"configMapKeyRef": map[string]interface{}{"name": "api-config", "key": "mode"}}},
+			},
+			"envFrom": []interface{}{map[string]interface{}{"secretRef": map[string]interface{}{"name": "api-env"}}},
+		}},
+	})
+
+	want := []string{
+		"ConfigMap/prod/api-config", "Secret/prod/api-tls", "ConfigMap/prod/shared-ca", "Secret/prod/api-token",
+		"Secret/prod/registry", "ConfigMap/prod/init-env", "Secret/prod/db", "Secret/prod/api-env",
+	}
+	if got := configRefsOf(&wl); !reflect.DeepEqual(got, want) {
+		t.Errorf("configRefsOf = %v\nwant %v", got, want)
+	}
+
+	ing := newTestConfigWorkload("Ingress", "prod", "web", nil, nil)
+	ing.Object["spec"] = map[string]interface{}{"tls": []interface{}{map[string]interface{}{"secretName": "web-tls"}}}
+	if got := configRefsOf(&ing); !reflect.DeepEqual(got, []string{"Secret/prod/web-tls"}) {
+		t.Errorf("ingress refs = %v", got)
+	}
+}
+
+func TestBuildConfigReferences(t *testing.T) {
+	flux := map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps"}
+	argo := map[string]string{"argocd.argoproj.io/instance": "web"}
+	envFrom := func(cm string) map[string]interface{} {
+		return map[string]interface{}{"containers": []interface{}{map[string]interface{}{
+			"envFrom": []interface{}{map[string]interface{}{"configMapRef": map[string]interface{}{"name": cm}}},
+		}}}
+	}
+
+	objs := []unstructured.Unstructured{
+		newTestConfigObject("ConfigMap", "prod", "api-config", flux),
+		newTestConfigObject("ConfigMap", "prod", "shared", nil),
+		newTestConfigObject("ConfigMap", "prod", "leftover", nil),
+		newTestConfigObject("ConfigMap", "prod", "kube-root-ca.crt", nil),
+		newTestConfigObject("ConfigMap", "kube-system", "coredns", nil),
+	}
+	referrers := []unstructured.Unstructured{
+		newTestConfigWorkload("Deployment", "prod", "api", flux, envFrom("api-config")),
+		newTestConfigWorkload("Deployment", "prod", "api2", flux, envFrom("shared")),
+		newTestConfigWorkload("CronJob", "prod", "report", argo, envFrom("shared")),
+		newTestConfigWorkload("Deployment", "dev", "api", flux, envFrom("leftover")), // other namespace
+	}
+
+	refs := buildConfigReferences(objs, referrers)
+	if len(refs) != 3 {
+		t.Fatalf("got %d refs, want 3 (kube-root-ca.crt and system namespaces skipped): %+v", len(refs), refs)
+	}
+	byName := map[string]ConfigReference{}
+	for _, r := range refs {
+		byName[r.Name] = r
+	}
+
+	if r := byName["api-config"]; r.Owner != "Flux" || r.Unreferenced || r.SharedAcrossOwners || !reflect.DeepEqual(r.ReferencedBy, []string{"Deployment/api"}) {
+		t.Errorf("api-config = %+v", r)
+	}
+	if r := byName["shared"]; !r.SharedAcrossOwners || !reflect.DeepEqual(r.ReferrerOwners, []string{"ArgoCD", "Flux"}) {
+		t.Errorf("shared = %+v", r)
+	}
+	if r := byName["leftover"]; !r.Unreferenced {
+		t.Errorf("leftover = %+v", r)
+	}
+
+	secret := newTestConfigObject("Secret", "prod", "sa-token", nil)
+	secret.Object["type"] = "kubernetes.io/service-account-token"
+	helm := newTestConfigObject("Secret", "prod", "sh.helm.release.v1.api.v1", nil)
+	helm.Object["type"] = "helm.sh/release.v1"
+	if refs := buildConfigReferences([]unstructured.Unstructured{secret, helm}, nil); len(refs) != 0 {
+		t.Errorf("token and helm release secrets should be skipped: %+v", refs)
+	}
+}
+
+func TestPrintConfigReferences(t *testing.T) {
+	var buf bytes.Buffer
+	printConfigReferences(&buf, "ConfigMap", []ConfigReference{
+		{Namespace: "prod", Kind: "ConfigMap", Name: "shared", Owner: "Native", ReferencedBy: []string{"CronJob/report", "Deployment/api2"}, ReferrerOwners: []string{"ArgoCD", "Flux"}, SharedAcrossOwners: true},
+		{Namespace: "prod", Kind: "ConfigMap", Name: "leftover", Owner: "Native", Unreferenced: true},
+	})
+	out := buf.String()
+	for _, want := range []string{"CronJob/report, Deployment/api2 [ArgoCD,Flux]", "⚠ shared across owners", "unreferenced", "2 ConfigMaps: 1 unreferenced, 1 shared across owners"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	costGroupBy  string
+	costOpenCost string
+	costWindow   string
+)
+
+var mapCostCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Show CPU/memory requests grouped by owner",
+	Long: `Show resource request totals attributed to the owner of each workload.
+
+Requests are summed across Deployments, StatefulSets and DaemonSets
+(per-pod requests × replicas). Answers "what fraction of the cluster is
+still shadow IT" without needing a cost tool.
+
+Group by:
+  owner       Flux, ArgoCD, Helm, ConfigHub, Native (default)
+  namespace   Kubernetes namespace
+  space       ConfigHub space (from the confighub.com/SpaceName annotation or label)
+
+Optionally join OpenCost allocation data (cost per namespace) with --opencost.
+
+Examples:
+  cub-scout map cost
+  cub-scout map cost --group-by namespace
+  cub-scout map cost --group-by space --json
+  cub-scout map cost --group-by namespace --opencost http://localhost:9003`,
+	RunE: runMapCost,
+}
+
+// WorkloadRequests holds the aggregate requests for a single workload.
+type WorkloadRequests struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Owner     string `json:"owner"`
+	Space     string `json:"space,omitempty"`
+	Replicas  int64  `json:"replicas"`
+	CPUMilli  int64  `json:"cpuMillicores"`
+	MemBytes  int64  `json:"memoryBytes"`
+}
+
+// CostGroup is one row of the cost report.
+type CostGroup struct {
+	Key        string  `json:"key"`
+	Workloads  int     `json:"workloads"`
+	CPUMilli   int64   `json:"cpuMillicores"`
+	MemBytes   int64   `json:"memoryBytes"`
+	CPUPercent float64 `json:"cpuPercent"`
+	MemPercent float64 `json:"memoryPercent"`
+	Cost       float64 `json:"cost,omitempty"` // OpenCost total cost for the window (namespace grouping only)
+}
+
+// CostReport is the JSON output of 'map cost'.
+type CostReport struct {
+	GroupBy       string      `json:"groupBy"`
+	Groups        []CostGroup `json:"groups"`
+	TotalCPUMilli int64       `json:"totalCpuMillicores"`
+	TotalMemBytes int64       `json:"totalMemoryBytes"`
+	NativeCPUPct  float64     `json:"nativeCpuPercent"`
+	NativeMemPct  float64     `json:"nativeMemoryPercent"`
+	Window        string      `json:"window,omitempty"`
+
+	// Warnings are workload lists that failed, so the totals are incomplete
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func init() {
+	mapCmd.AddCommand(mapCostCmd)
+	mapCostCmd.Flags().StringVar(&mapNamespace, "namespace", "", "Filter by namespace")
+	mapCostCmd.Flags().StringVar(&costGroupBy, "group-by", "owner", "Group by: owner, namespace, space")
+	mapCostCmd.Flags().StringVar(&costOpenCost, "opencost", "", "OpenCost API base URL to join cost data (e.g., http://localhost:9003)")
+	mapCostCmd.Flags().StringVar(&costWindow, "window", "24h", "OpenCost allocation window")
+	_ = mapCostCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	_ = mapCostCmd.RegisterFlagCompletionFunc("group-by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"owner", "namespace", "space"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func runMapCost(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	switch costGroupBy {
+	case "owner", "namespace", "space":
+	default:
+		return fmt.Errorf("invalid --group-by %q (valid: owner, namespace, space)", costGroupBy)
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	workloadGVRs := []schema.GroupVersionResource{
+		{Group: "apps", Version: "v1", Resource: "deployments"},
+		{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		{Group: "apps", Version: "v1", Resource: "daemonsets"},
+	}
+
+	var workloads []WorkloadRequests
+	var warnings []string
+	for _, gvr := range workloadGVRs {
+		l, err := dynClient.Resource(gvr).Namespace(mapNamespace).List(ctx, v1.ListOptions{})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("list %s: %v; their requests are not counted", gvr.Resource, err))
+			continue
+		}
+		for i := range l.Items {
+			if isSystemNamespace(l.Items[i].GetNamespace()) {
+				continue
+			}
+			workloads = append(workloads, workloadRequestsFor(&l.Items[i]))
+		}
+	}
+
+	report := buildCostReport(workloads, costGroupBy)
+	report.Warnings = warnings
+
+	if costOpenCost != "" {
+		if costGroupBy != "namespace" {
+			fmt.Fprintln(os.Stderr, "Note: --opencost data is per namespace; use --group-by namespace to see cost")
+		} else {
+			costs, err := fetchOpenCostByNamespace(costOpenCost, costWindow)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: OpenCost unavailable: %v\n", err)
+			} else {
+				for i := range report.Groups {
+					report.Groups[i].Cost = costs[report.Groups[i].Key]
+				}
+				report.Window = costWindow
+			}
+		}
+	}
+
+	if mapJSON {
+		return writeJSON(os.Stdout, "CostReport", report)
+	}
+
+	printCostReport(report)
+	return nil
+}
+
+// workloadRequestsFor sums container requests in the pod template and multiplies
+// by the desired replica count.
+func workloadRequestsFor(obj *unstructured.Unstructured) WorkloadRequests {
+	ownership := agent.DetectOwnership(obj)
+	w := WorkloadRequests{
+		Namespace: obj.GetNamespace(),
+		Kind:      obj.GetKind(),
+		Name:      obj.GetName(),
+		Owner:     displayOwner(ownership.Type),
+		Space:     agent.ConfigHubSpace(obj),
+	}
+
+	switch obj.GetKind() {
+	case "DaemonSet":
+		w.Replicas, _, _ = unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+	default:
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		w.Replicas = replicas
+	}
+
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	var cpu, mem int64
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Quantities may be decoded as strings or bare numbers (cpu: 1)
+		requests, _, _ := unstructured.NestedMap(container, "resources", "requests")
+		if v, ok := requests["cpu"]; ok {
+			if q, err := resource.ParseQuantity(fmt.Sprint(v)); err == nil {
+				cpu += q.MilliValue()
+			}
+		}
+		if v, ok := requests["memory"]; ok {
+			if q, err := resource.ParseQuantity(fmt.Sprint(v)); err == nil {
+				mem += q.Value()
+			}
+		}
+	}
+	w.CPUMilli = cpu * w.Replicas
+	w.MemBytes = mem * w.Replicas
+	return w
+}
+
+// buildCostReport aggregates workload requests by the given grouping key.
+func buildCostReport(workloads []WorkloadRequests, groupBy string) CostReport {
+	report := CostReport{GroupBy: groupBy}
+	groups := map[string]*CostGroup{}
+	var nativeCPU, nativeMem int64
+
+	for _, w := range workloads {
+		var key string
+		switch groupBy {
+		case "namespace":
+			key = w.Namespace
+		case "space":
+			key = w.Space
+			if key == "" {
+				key = "(no space)"
+			}
+		default:
+			key = w.Owner
+		}
+
+		g := groups[key]
+		if g == nil {
+			g = &CostGroup{Key: key}
+			groups[key] = g
+		}
+		g.Workloads++
+		g.CPUMilli += w.CPUMilli
+		g.MemBytes += w.MemBytes
+
+		report.TotalCPUMilli += w.CPUMilli
+		report.TotalMemBytes += w.MemBytes
+		if w.Owner == "Native" {
+			nativeCPU += w.CPUMilli
+			nativeMem += w.MemBytes
+		}
+	}
+
+	for _, g := range groups {
+		g.CPUPercent = percentOf(g.CPUMilli, report.TotalCPUMilli)
+		g.MemPercent = percentOf(g.MemBytes, report.TotalMemBytes)
+		report.Groups = append(report.Groups, *g)
+	}
+	report.NativeCPUPct = percentOf(nativeCPU, report.TotalCPUMilli)
+	report.NativeMemPct = percentOf(nativeMem, report.TotalMemBytes)
+
+	// Largest consumers first, ties broken by key for stable output
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].CPUMilli != report.Groups[j].CPUMilli {
+			return report.Groups[i].CPUMilli > report.Groups[j].CPUMilli
+		}
+		return report.Groups[i].Key < report.Groups[j].Key
+	})
+
+	return report
+}
+
+func percentOf(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+// fetchOpenCostByNamespace queries the OpenCost allocation API and returns
+// total cost per namespace for the given window.
+func fetchOpenCostByNamespace(baseURL, window string) (map[string]float64, error) {
+	u := strings.TrimSuffix(baseURL, "/") + "/allocation/compute?" + url.Values{
+		"window":     {window},
+		"aggregate":  {"namespace"},
+		"accumulate": {"true"},
+	}.Encode()
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("opencost returned %s", resp.Status)
+	}
+
+	var body struct {
+		Data []map[string]struct {
+			Name      string  `json:"name"`
+			TotalCost float64 `json:"totalCost"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("parse opencost response: %w", err)
+	}
+
+	costs := map[string]float64{}
+	for _, set := range body.Data {
+		for ns, alloc := range set {
+			costs[ns] += alloc.TotalCost
+		}
+	}
+	return costs, nil
+}
+
+func formatCPU(milli int64) string {
+	return fmt.Sprintf("%.2f", float64(milli)/1000)
+}
+
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%.2fGi", float64(bytes)/(1<<30))
+}
+
+func printCostReport(report CostReport) {
+	fmt.Println()
+	fmt.Printf("RESOURCE REQUESTS BY %s\n", strings.ToUpper(report.GroupBy))
+	fmt.Println("════════════════════════════════════════════════════════════════════")
+	fmt.Println("CPU/memory requests of Deployments, StatefulSets and DaemonSets.")
+	fmt.Println()
+
+	for _, warning := range report.Warnings {
+		fmt.Printf("⚠ %s\n", warning)
+	}
+	if len(report.Warnings) > 0 {
+		fmt.Println()
+	}
+
+	if len(report.Groups) == 0 {
+		fmt.Println("No workloads found")
+		return
+	}
+
+	showCost := report.Window != ""
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := fmt.Sprintf("%s\tWORKLOADS\tCPU\tMEMORY\tSHARE", strings.ToUpper(report.GroupBy))
+	if showCost {
+		header += "\tCOST (" + report.Window + ")"
+	}
+	fmt.Fprintln(w, header)
+	for _, g := range report.Groups {
+		line := fmt.Sprintf("%s\t%d\t%s\t%s\t%s %3.0f%%",
+			g.Key, g.Workloads, formatCPU(g.CPUMilli), formatMemory(g.MemBytes),
+			makeBar(int(g.CPUMilli), int(report.TotalCPUMilli), 20), g.CPUPercent)
+		if showCost {
+			line += fmt.Sprintf("\t$%.2f", g.Cost)
+		}
+		fmt.Fprintln(w, line)
+	}
+	w.Flush()
+
+	fmt.Printf("\nTotal requests: %s CPU, %s memory\n", formatCPU(report.TotalCPUMilli), formatMemory(report.TotalMemBytes))
+	if report.NativeCPUPct > 0 || report.NativeMemPct > 0 {
+		fmt.Printf("⚠ Shadow IT: %.0f%% of CPU and %.0f%% of memory requests are Native (not GitOps managed)\n",
+			report.NativeCPUPct, report.NativeMemPct)
+	}
+}
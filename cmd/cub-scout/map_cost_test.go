@@ -0,0 +1,105 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestWorkload(kind, namespace, name string, replicas int64, labels map[string]string, requests ...map[string]interface{}) *unstructured.Unstructured {
+	containers := make([]interface{}, 0, len(requests))
+	for _, r := range requests {
+		containers = append(containers, map[string]interface{}{
+			"name":      "c",
+			"resources": map[string]interface{}{"requests": r},
+		})
+	}
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       kind,
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": containers},
+			},
+		},
+	}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func TestWorkloadRequestsFor(t *testing.T) {
+	dep := newTestWorkload("Deployment", "prod", "api", 3,
+		map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps", "confighub.com/SpaceName": "payments"},
+		map[string]interface{}{"cpu": "250m", "memory": "128Mi"},
+		map[string]interface{}{"cpu": int64(1)},
+	)
+
+	w := workloadRequestsFor(dep)
+	if w.Owner != "Flux" {
+		t.Errorf("Owner = %s, want Flux", w.Owner)
+	}
+	if w.Space != "payments" {
+		t.Errorf("Space = %q, want the label space", w.Space)
+	}
+	if w.CPUMilli != 3750 {
+		t.Errorf("CPUMilli = %d, want 3750", w.CPUMilli)
+	}
+	if w.MemBytes != 3*128*1024*1024 {
+		t.Errorf("MemBytes = %d, want %d", w.MemBytes, 3*128*1024*1024)
+	}
+}
+
+func TestWorkloadRequestsForDaemonSet(t *testing.T) {
+	ds := newTestWorkload("DaemonSet", "logging", "agent", 0, nil,
+		map[string]interface{}{"cpu": "100m"})
+	_ = unstructured.SetNestedField(ds.Object, int64(4), "status", "desiredNumberScheduled")
+
+	w := workloadRequestsFor(ds)
+	if w.Replicas != 4 || w.CPUMilli != 400 {
+		t.Errorf("got replicas=%d cpu=%d, want replicas=4 cpu=400", w.Replicas, w.CPUMilli)
+	}
+}
+
+func TestBuildCostReport(t *testing.T) {
+	workloads := []WorkloadRequests{
+		{Namespace: "prod", Owner: "Flux", Space: "payments", CPUMilli: 3000, MemBytes: 3 << 30},
+		{Namespace: "prod", Owner: "Native", CPUMilli: 1000, MemBytes: 1 << 30},
+		{Namespace: "dev", Owner: "Native", CPUMilli: 0, MemBytes: 0},
+	}
+
+	report := buildCostReport(workloads, "owner")
+	if len(report.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(report.Groups))
+	}
+	if report.Groups[0].Key != "Flux" || report.Groups[0].CPUPercent != 75 {
+		t.Errorf("unexpected first group: %+v", report.Groups[0])
+	}
+	if report.Groups[1].Workloads != 2 {
+		t.Errorf("Native workloads = %d, want 2", report.Groups[1].Workloads)
+	}
+	if report.NativeCPUPct != 25 || report.NativeMemPct != 25 {
+		t.Errorf("native share = %.0f%%/%.0f%%, want 25%%/25%%", report.NativeCPUPct, report.NativeMemPct)
+	}
+
+	bySpace := buildCostReport(workloads, "space")
+	keys := map[string]bool{}
+	for _, g := range bySpace.Groups {
+		keys[g.Key] = true
+	}
+	if !keys["payments"] || !keys["(no space)"] {
+		t.Errorf("unexpected space groups: %v", keys)
+	}
+}
+
+func TestBuildCostReportEmpty(t *testing.T) {
+	report := buildCostReport(nil, "namespace")
+	if len(report.Groups) != 0 || report.NativeCPUPct != 0 {
+		t.Errorf("expected empty report, got %+v", report)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// sprawlEnforce checks namespaces against their coverage budgets (--enforce)
+	sprawlEnforce bool
+	// sprawlBudgets overrides the coverage budget file (--budgets)
+	sprawlBudgets string
+)
+
+func init() {
+	mapSprawlCmd.Flags().BoolVar(&sprawlEnforce, "enforce", false, "Exit 1 when a namespace breaks its coverage budget")
+	mapSprawlCmd.Flags().StringVar(&sprawlBudgets, "budgets", "", "Coverage budget file (default: $CUB_SCOUT_COVERAGE or ~/.cub-scout/coverage.yaml)")
+}
+
+// CoverageConfig is the coverage budget file, e.g.:
+//
+//	budgets:
+//	  - namespaces: ["prod-*", payments]
+//	    minCoverage: 95
+//	    maxOrphans: 0
+//	  - namespaces: ["*"]
+//	    minCoverage: 50
+//
+// Namespaces are glob patterns. Each namespace is held to the first budget
+// that matches it; namespaces no budget matches are not checked.
+type CoverageConfig struct {
+	Budgets []CoverageBudget `yaml:"budgets"`
+}
+
+// CoverageBudget is the GitOps coverage a set of namespaces must keep.
+// Unset limits are not checked.
+type CoverageBudget struct {
+	Namespaces []string `yaml:"namespaces"`
+
+	// MinCoverage is the lowest share of GitOps-managed workloads, in percent
+	MinCoverage *float64 `yaml:"minCoverage,omitempty"`
+
+	// MaxOrphans is the most Native (unmanaged) workloads allowed
+	MaxOrphans *int `yaml:"maxOrphans,omitempty"`
+}
+
+// CoverageConfigFile returns the budget path: --budgets,
+// $CUB_SCOUT_COVERAGE, then ~/.cub-scout/coverage.yaml.
+func CoverageConfigFile() string {
+	if sprawlBudgets != "" {
+		return sprawlBudgets
+	}
+	if file := os.Getenv("CUB_SCOUT_COVERAGE"); file != "" {
+		return file
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cub-scout", "coverage.yaml")
+}
+
+// loadCoverageConfig reads and validates a budget file.
+func loadCoverageConfig(file string) (CoverageConfig, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return CoverageConfig{}, fmt.Errorf("no coverage budgets: %s does not exist", file)
+		}
+		return CoverageConfig{}, err
+	}
+	var cfg CoverageConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return CoverageConfig{}, fmt.Errorf("parse %s: %w", file, err)
+	}
+	if len(cfg.Budgets) == 0 {
+		return CoverageConfig{}, fmt.Errorf("parse %s: no budgets", file)
+	}
+	for i, b := range cfg.Budgets {
+		if len(b.Namespaces) == 0 {
+			return CoverageConfig{}, fmt.Errorf("parse %s: budget %d has no namespaces", file, i+1)
+		}
+		for _, p := range b.Namespaces {
+			if _, err := path.Match(p, ""); err != nil {
+				return CoverageConfig{}, fmt.Errorf("parse %s: bad pattern %q", file, p)
+			}
+		}
+		if b.MinCoverage != nil && (*b.MinCoverage < 0 || *b.MinCoverage > 100) {
+			return CoverageConfig{}, fmt.Errorf("parse %s: budget %d: minCoverage must be 0-100", file, i+1)
+		}
+		if b.MaxOrphans != nil && *b.MaxOrphans < 0 {
+			return CoverageConfig{}, fmt.Errorf("parse %s: budget %d: maxOrphans must not be negative", file, i+1)
+		}
+	}
+	return cfg, nil
+}
+
+// budgetFor returns the first budget matching ns.
+func (c CoverageConfig) budgetFor(ns string) (CoverageBudget, bool) {
+	for _, b := range c.Budgets {
+		if matchNamespace(b.Namespaces, ns) {
+			return b, true
+		}
+	}
+	return CoverageBudget{}, false
+}
+
+// namespaceCoverage counts a namespace's workloads by whether GitOps
+// manages them.
+type namespaceCoverage struct {
+	Managed int
+	Native  int
+}
+
+// Percent is the GitOps-managed share of the namespace's workloads.
+func (c namespaceCoverage) Percent() float64 {
+	total := c.Managed + c.Native
+	if total == 0 {
+		return 100
+	}
+	return float64(c.Managed) * 100 / float64(total)
+}
+
+// budgetViolation is a namespace breaking its budget.
+type budgetViolation struct {
+	Namespace string
+	Coverage  namespaceCoverage
+	Reasons   []string
+}
+
+// checkCoverageBudgets returns the namespaces breaking their budget, by
+// namespace.
+func checkCoverageBudgets(cfg CoverageConfig, coverage map[string]namespaceCoverage) []budgetViolation {
+	var violations []budgetViolation
+	for ns, c := range coverage {
+		b, ok := cfg.budgetFor(ns)
+		if !ok {
+			continue
+		}
+		var reasons []string
+		if b.MinCoverage != nil && c.Percent() < *b.MinCoverage {
+			reasons = append(reasons, fmt.Sprintf("coverage %.1f%% < %g%%", c.Percent(), *b.MinCoverage))
+		}
+		if b.MaxOrphans != nil && c.Native > *b.MaxOrphans {
+			reasons = append(reasons, fmt.Sprintf("%d orphan(s) > %d", c.Native, *b.MaxOrphans))
+		}
+		if len(reasons) > 0 {
+			violations = append(violations, budgetViolation{Namespace: ns, Coverage: c, Reasons: reasons})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Namespace < violations[j].Namespace })
+	return violations
+}
+
+// enforceCoverageBudgets prints the namespaces breaking their budget and
+// returns an error when there are any, so map sprawl --enforce exits 1.
+func enforceCoverageBudgets(w io.Writer, coverage map[string]namespaceCoverage) error {
+	file := CoverageConfigFile()
+	cfg, err := loadCoverageConfig(file)
+	if err != nil {
+		return err
+	}
+	violations := checkCoverageBudgets(cfg, coverage)
+
+	fmt.Fprintf(w, "\nBUDGETS (%s):\n", file)
+	if len(violations) == 0 {
+		fmt.Fprintln(w, "  ✓ All namespaces within their coverage budget")
+		return nil
+	}
+	for _, v := range violations {
+		fmt.Fprintf(w, "  ✗ %-24s %3d managed, %3d native  %s\n",
+			v.Namespace, v.Coverage.Managed, v.Coverage.Native, strings.Join(v.Reasons, ", "))
+	}
+	fmt.Fprintln(w, "  Run: cub-scout map orphans --namespace <namespace>  # to see the unmanaged workloads")
+	return fmt.Errorf("%d namespace(s) over their coverage budget", len(violations))
+}
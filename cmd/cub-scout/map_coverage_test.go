@@ -0,0 +1,119 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func writeCoverageConfig(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "coverage.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestCheckCoverageBudgets(t *testing.T) {
+	cfg, err := loadCoverageConfig(writeCoverageConfig(t, `
+budgets:
+  - namespaces: ["prod-*"]
+    minCoverage: 95
+    maxOrphans: 0
+  - namespaces: [staging]
+    maxOrphans: 2
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coverage := map[string]namespaceCoverage{
+		"prod-payments": {Managed: 18, Native: 1}, // 94.7%, 1 orphan
+		"prod-web":      {Managed: 20},
+		"staging":       {Managed: 1, Native: 2}, // low coverage, but no minimum
+		"dev":           {Native: 9},             // no budget
+	}
+	violations := checkCoverageBudgets(cfg, coverage)
+	if len(violations) != 1 {
+		t.Fatalf("got %d violations, want 1: %+v", len(violations), violations)
+	}
+	v := violations[0]
+	if v.Namespace != "prod-payments" {
+		t.Errorf("Namespace = %s, want prod-payments", v.Namespace)
+	}
+	if got := strings.Join(v.Reasons, ", "); got != "coverage 94.7% < 95%, 1 orphan(s) > 0" {
+		t.Errorf("Reasons = %q", got)
+	}
+}
+
+func TestEnforceCoverageBudgets(t *testing.T) {
+	sprawlBudgets = writeCoverageConfig(t, "budgets: [{namespaces: [prod], maxOrphans: 0}]")
+	defer func() { sprawlBudgets = "" }()
+
+	var buf bytes.Buffer
+	err := enforceCoverageBudgets(&buf, map[string]namespaceCoverage{"prod": {Managed: 3, Native: 1}})
+	if err == nil || !strings.Contains(err.Error(), "1 namespace(s) over their coverage budget") {
+		t.Errorf("err = %v", err)
+	}
+	if !strings.Contains(buf.String(), "✗ prod") {
+		t.Errorf("output does not list prod:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := enforceCoverageBudgets(&buf, map[string]namespaceCoverage{"prod": {Managed: 3}}); err != nil {
+		t.Errorf("within budget: err = %v", err)
+	}
+}
+
+func TestMapSprawlEnforceListError(t *testing.T) {
+	sprawlBudgets = writeCoverageConfig(t, "budgets: [{namespaces: [prod], maxOrphans: 0}]")
+	sprawlEnforce = true
+	defer func() { sprawlBudgets, sprawlEnforce = "", false }()
+
+	client := agenttest.FakeClient()
+	client.PrependReactor("list", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "", nil)
+	})
+
+	store := HistoryStore{Dir: t.TempDir()}
+	err := mapSprawl(context.Background(), client, store, "test")
+	if err == nil || !strings.Contains(err.Error(), "list deployments") {
+		t.Errorf("err = %v, want list deployments error", err)
+	}
+}
+
+func TestLoadCoverageConfigErrors(t *testing.T) {
+	tests := []struct {
+		name, content, wantErr string
+	}{
+		{"empty", "budgets: []", "no budgets"},
+		{"no namespaces", "budgets: [{minCoverage: 90}]", "has no namespaces"},
+		{"bad pattern", "budgets: [{namespaces: ['[']}]", "bad pattern"},
+		{"coverage out of range", "budgets: [{namespaces: [prod], minCoverage: 120}]", "minCoverage must be 0-100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadCoverageConfig(writeCoverageConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := loadCoverageConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("missing file: err = %v", err)
+	}
+}
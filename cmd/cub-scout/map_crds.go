@@ -0,0 +1,284 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var mapCRDsWarnings bool
+
+var mapCRDsCmd = &cobra.Command{
+	Use:     "crds",
+	Aliases: []string{"operators"},
+	Short:   "Inventory installed CRDs by operator, with version skew warnings",
+	Long: `List installed CustomResourceDefinitions grouped by the operator that
+installed them, and the deployer (Helm, Flux, Argo CD, ...) managing each CRD.
+
+The operator is read from app.kubernetes.io/part-of, the Helm release,
+app.kubernetes.io/instance or name, or an OLM subscription label; CRDs with
+none of these are grouped by API group.
+
+Version skew warnings:
+  - a stored version that is no longer the storage version (objects still
+    need a storage migration before that version can be removed)
+  - a stored version that is no longer served
+  - deprecated versions still written by live objects, from the apiVersion
+    recorded in their managedFields
+
+Examples:
+  cub-scout map crds
+  cub-scout map crds --warnings
+  cub-scout map crds --json`,
+	Args: cobra.NoArgs,
+	RunE: runMapCRDs,
+}
+
+func init() {
+	mapCRDsCmd.Flags().BoolVar(&mapCRDsWarnings, "warnings", false, "Only show CRDs with version skew warnings")
+	mapCmd.AddCommand(mapCRDsCmd)
+}
+
+// CRDInfo is an installed CRD, the operator it belongs to and its versions.
+type CRDInfo struct {
+	Name     string `json:"name"`
+	Group    string `json:"group"`
+	Kind     string `json:"kind"`
+	Scope    string `json:"scope"`
+	Operator string `json:"operator"`
+	// Owner is the deployer managing the CRD object itself
+	Owner          string   `json:"owner"`
+	ServedVersions []string `json:"servedVersions"`
+	StorageVersion string   `json:"storageVersion"`
+	StoredVersions []string `json:"storedVersions,omitempty"`
+	Deprecated     []string `json:"deprecated,omitempty"`
+	// DeprecatedInUse counts live objects last written through each
+	// deprecated version
+	DeprecatedInUse map[string]int `json:"deprecatedInUse,omitempty"`
+	Warnings        []string       `json:"warnings,omitempty"`
+}
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+func runMapCRDs(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	crds, err := dynClient.Resource(crdGVR).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list CRDs: %w", err)
+	}
+
+	infos := buildCRDInventory(crds.Items, func(gvr schema.GroupVersionResource) []unstructured.Unstructured {
+		return listAll(ctx, dynClient, gvr)
+	})
+	if mapCRDsWarnings {
+		filtered := infos[:0]
+		for _, c := range infos {
+			if len(c.Warnings) > 0 {
+				filtered = append(filtered, c)
+			}
+		}
+		infos = filtered
+	}
+
+	if mapJSON {
+		return writeJSON(os.Stdout, "CRDInventory", infos)
+	}
+	printCRDInventory(os.Stdout, infos)
+	return nil
+}
+
+// buildCRDInventory describes each CRD. list is called only for CRDs with
+// deprecated versions, to find objects still written through them.
+func buildCRDInventory(crds []unstructured.Unstructured, list func(schema.GroupVersionResource) []unstructured.Unstructured) []CRDInfo {
+	infos := make([]CRDInfo, 0, len(crds))
+	for i := range crds {
+		crd := &crds[i]
+		info := CRDInfo{
+			Name:     crd.GetName(),
+			Operator: crdOperator(crd),
+			Owner:    displayOwner(agent.DetectOwnership(crd).Type),
+		}
+		info.Group, _, _ = unstructured.NestedString(crd.Object, "spec", "group")
+		info.Kind, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		info.Scope, _, _ = unstructured.NestedString(crd.Object, "spec", "scope")
+		plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+		info.StoredVersions, _, _ = unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		for _, v := range versions {
+			ver, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := ver["name"].(string)
+			served, _ := ver["served"].(bool)
+			if served {
+				info.ServedVersions = append(info.ServedVersions, name)
+			}
+			if storage, _ := ver["storage"].(bool); storage {
+				info.StorageVersion = name
+			}
+			if deprecated, _ := ver["deprecated"].(bool); deprecated && served {
+				info.Deprecated = append(info.Deprecated, name)
+			}
+		}
+
+		for _, sv := range info.StoredVersions {
+			switch {
+			case !contains(info.ServedVersions, sv):
+				info.Warnings = append(info.Warnings, fmt.Sprintf("stored version %s is no longer served; objects stored as %s cannot be read", sv, sv))
+			case sv != info.StorageVersion:
+				info.Warnings = append(info.Warnings, fmt.Sprintf("objects may still be stored as %s (storage is %s); migrate them before removing %s", sv, info.StorageVersion, sv))
+			}
+		}
+
+		if len(info.Deprecated) > 0 && plural != "" && info.StorageVersion != "" {
+			objs := list(schema.GroupVersionResource{Group: info.Group, Version: info.StorageVersion, Resource: plural})
+			for _, dv := range info.Deprecated {
+				n := countWrittenVia(objs, info.Group+"/"+dv)
+				if n == 0 {
+					continue
+				}
+				if info.DeprecatedInUse == nil {
+					info.DeprecatedInUse = map[string]int{}
+				}
+				info.DeprecatedInUse[dv] = n
+				info.Warnings = append(info.Warnings, fmt.Sprintf("%d object(s) written via deprecated %s/%s", n, info.Group, dv))
+			}
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Operator != infos[j].Operator {
+			return infos[i].Operator < infos[j].Operator
+		}
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// crdOperator names the operator a CRD belongs to, falling back to its API
+// group when no label or annotation says.
+func crdOperator(crd *unstructured.Unstructured) string {
+	labels := crd.GetLabels()
+	if v := labels["app.kubernetes.io/part-of"]; v != "" {
+		return v
+	}
+	if v := crd.GetAnnotations()["meta.helm.sh/release-name"]; v != "" {
+		return v
+	}
+	for _, key := range []string{"app.kubernetes.io/instance", "app.kubernetes.io/name", "app"} {
+		if v := labels[key]; v != "" {
+			return v
+		}
+	}
+	// OLM: operators.coreos.com/<package>.<namespace>
+	for key := range labels {
+		if pkg, ok := strings.CutPrefix(key, "operators.coreos.com/"); ok {
+			name, _, _ := strings.Cut(pkg, ".")
+			return name
+		}
+	}
+	if ownership := agent.DetectOwnership(crd); ownership.Name != "" {
+		return ownership.Name
+	}
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	return group
+}
+
+// countWrittenVia counts objects with a managedFields entry for apiVersion.
+func countWrittenVia(objs []unstructured.Unstructured, apiVersion string) int {
+	n := 0
+	for i := range objs {
+		for _, mf := range objs[i].GetManagedFields() {
+			if mf.APIVersion == apiVersion {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+func printCRDInventory(w io.Writer, infos []CRDInfo) {
+	if len(infos) == 0 {
+		fmt.Fprintln(w, "No CRDs found")
+		return
+	}
+
+	byOperator := map[string][]CRDInfo{}
+	var operators []string
+	for _, c := range infos {
+		if _, ok := byOperator[c.Operator]; !ok {
+			operators = append(operators, c.Operator)
+		}
+		byOperator[c.Operator] = append(byOperator[c.Operator], c)
+	}
+
+	warned := 0
+	for _, op := range operators {
+		crds := byOperator[op]
+		var owners []string
+		for _, c := range crds {
+			owners = appendUnique(owners, c.Owner)
+		}
+		sort.Strings(owners)
+		fmt.Fprintf(w, "%s%s%s (%s) — %d CRD(s)\n", colorBold, op, colorReset, strings.Join(owners, ", "), len(crds))
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, c := range crds {
+			versions := make([]string, 0, len(c.ServedVersions))
+			for _, v := range c.ServedVersions {
+				switch {
+				case v == c.StorageVersion:
+					v += "*"
+				case contains(c.Deprecated, v):
+					v += " (deprecated)"
+				}
+				versions = append(versions, v)
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", c.Name, strings.Join(versions, ", "), c.Scope)
+		}
+		tw.Flush()
+
+		for _, c := range crds {
+			if len(c.Warnings) > 0 {
+				warned++
+			}
+			for _, warning := range c.Warnings {
+				fmt.Fprintf(w, "  %s⚠ %s: %s%s\n", colorYellow, c.Name, warning, colorReset)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%d CRD(s) from %d operator(s); %d with version skew warnings\n", len(infos), len(operators), warned)
+	fmt.Fprintf(w, "%s* storage version%s\n", colorDim, colorReset)
+}
@@ -0,0 +1,106 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newTestCRD(name, group, kind string, labels map[string]string, versions []interface{}, stored ...interface{}) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"spec": map[string]interface{}{
+			"group":    group,
+			"scope":    "Namespaced",
+			"names":    map[string]interface{}{"kind": kind, "plural": strings.Split(name, ".")[0]},
+			"versions": versions,
+		},
+		"status": map[string]interface{}{"storedVersions": stored},
+	}}
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func crdVersion(name string, served, storage, deprecated bool) map[string]interface{} {
+	return map[string]interface{}{"name": name, "served": served, "storage": storage, "deprecated": deprecated}
+}
+
+func TestBuildCRDInventory(t *testing.T) {
+	helm := map[string]string{"app.kubernetes.io/managed-by": "Helm", "app.kubernetes.io/instance": "cert-manager"}
+	crds := []unstructured.Unstructured{
+		newTestCRD("certificates.cert-manager.io", "cert-manager.io", "Certificate", helm,
+			[]interface{}{crdVersion("v1", true, true, false)}, "v1"),
+		newTestCRD("kafkas.kafka.strimzi.io", "kafka.strimzi.io", "Kafka", map[string]string{"operators.coreos.com/strimzi-kafka-operator.openshift-operators": ""},
+			[]interface{}{crdVersion("v1beta1", true, false, true), crdVersion("v1beta2", true, true, false)}, "v1beta1", "v1beta2"),
+		newTestCRD("widgets.example.com", "example.com", "Widget", nil,
+			[]interface{}{crdVersion("v1alpha1", false, false, false), crdVersion("v1", true, true, false)}, "v1alpha1", "v1"),
+	}
+
+	var listed []schema.GroupVersionResource
+	list := func(gvr schema.GroupVersionResource) []unstructured.Unstructured {
+		listed = append(listed, gvr)
+		old := unstructured.Unstructured{}
+		old.SetManagedFields([]v1.ManagedFieldsEntry{{Manager: "kubectl", APIVersion: "kafka.strimzi.io/v1beta1"}})
+		current := unstructured.Unstructured{}
+		current.SetManagedFields([]v1.ManagedFieldsEntry{{Manager: "strimzi", APIVersion: "kafka.strimzi.io/v1beta2"}})
+		return []unstructured.Unstructured{old, current}
+	}
+
+	infos := buildCRDInventory(crds, list)
+	if len(infos) != 3 {
+		t.Fatalf("got %d CRDs", len(infos))
+	}
+	if want := []schema.GroupVersionResource{{Group: "kafka.strimzi.io", Version: "v1beta2", Resource: "kafkas"}}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("listed %v, want only the CRD with a deprecated version", listed)
+	}
+
+	byName := map[string]CRDInfo{}
+	for _, c := range infos {
+		byName[c.Name] = c
+	}
+
+	cm := byName["certificates.cert-manager.io"]
+	if cm.Operator != "cert-manager" || cm.Owner != "Helm" || len(cm.Warnings) != 0 {
+		t.Errorf("cert-manager = %+v", cm)
+	}
+
+	kafka := byName["kafkas.kafka.strimzi.io"]
+	if kafka.Operator != "strimzi-kafka-operator" || kafka.StorageVersion != "v1beta2" || !reflect.DeepEqual(kafka.Deprecated, []string{"v1beta1"}) {
+		t.Errorf("kafka = %+v", kafka)
+	}
+	if kafka.DeprecatedInUse["v1beta1"] != 1 || len(kafka.Warnings) != 2 {
+		t.Errorf("kafka warnings = %v, in use = %v", kafka.Warnings, kafka.DeprecatedInUse)
+	}
+
+	widget := byName["widgets.example.com"]
+	if widget.Operator != "example.com" {
+		t.Errorf("widget operator = %q, want API group fallback", widget.Operator)
+	}
+	if len(widget.Warnings) != 1 || !strings.Contains(widget.Warnings[0], "v1alpha1 is no longer served") {
+		t.Errorf("widget warnings = %v", widget.Warnings)
+	}
+}
+
+func TestPrintCRDInventory(t *testing.T) {
+	var buf bytes.Buffer
+	printCRDInventory(&buf, []CRDInfo{
+		{Name: "kafkas.kafka.strimzi.io", Operator: "strimzi", Owner: "Native", Scope: "Namespaced", ServedVersions: []string{"v1beta1", "v1beta2"}, StorageVersion: "v1beta2", Deprecated: []string{"v1beta1"}, Warnings: []string{"1 object(s) written via deprecated kafka.strimzi.io/v1beta1"}},
+		{Name: "kafkatopics.kafka.strimzi.io", Operator: "strimzi", Owner: "Native", Scope: "Namespaced", ServedVersions: []string{"v1beta2"}, StorageVersion: "v1beta2"},
+	})
+	out := buf.String()
+	for _, want := range []string{"strimzi", "(Native) — 2 CRD(s)", "v1beta1 (deprecated), v1beta2*", "⚠ kafkas.kafka.strimzi.io: 1 object(s) written via deprecated", "2 CRD(s) from 1 operator(s); 1 with version skew warnings"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// dashboardGroupBy splits map dashboard into sections per environment or
+// namespace (--group-by)
+var dashboardGroupBy string
+
+func init() {
+	mapDashboardCmd.Flags().StringVar(&dashboardGroupBy, "group-by", "", "Group every section by: env, namespace")
+	_ = mapDashboardCmd.RegisterFlagCompletionFunc("group-by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"env", "namespace"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// environmentLabels are the labels naming a resource's or namespace's
+// environment, in order of precedence.
+var environmentLabels = []string{"environment", "env"}
+
+// dashboardOwners are the owners shown in BY OWNER, in display order.
+var dashboardOwners = []string{"Flux", "ArgoCD", "Helm", "ConfigHub", "Native"}
+
+// dashboardCounts is the health and ownership of one dashboard section.
+type dashboardCounts struct {
+	DeployersReady, DeployersTotal int
+	WorkloadsReady, WorkloadsTotal int
+	ByOwner                        map[string]int
+}
+
+func (c *dashboardCounts) addDeployer(ready bool) {
+	c.DeployersTotal++
+	if ready {
+		c.DeployersReady++
+	}
+}
+
+func (c *dashboardCounts) addWorkload(ready bool, owner string) {
+	c.WorkloadsTotal++
+	if ready {
+		c.WorkloadsReady++
+	}
+	if c.ByOwner == nil {
+		c.ByOwner = map[string]int{}
+	}
+	c.ByOwner[owner]++
+}
+
+// dashboardGroups holds a section per group; without --group-by everything
+// is in the group "".
+type dashboardGroups map[string]*dashboardCounts
+
+func (g dashboardGroups) get(key string) *dashboardCounts {
+	c, ok := g[key]
+	if !ok {
+		c = &dashboardCounts{}
+		g[key] = c
+	}
+	return c
+}
+
+// dashboardGrouper names the group of a resource from its namespace (the
+// namespace a deployer deploys to) and labels.
+type dashboardGrouper func(namespace string, labels map[string]string) string
+
+// newDashboardGrouper returns the grouper for groupBy, which puts
+// everything in one group when empty. Grouping by env reads namespace
+// labels, so it lists namespaces once.
+func newDashboardGrouper(ctx context.Context, dynClient dynamic.Interface, groupBy string) dashboardGrouper {
+	switch groupBy {
+	case "namespace":
+		return func(ns string, _ map[string]string) string { return ns }
+	case "env":
+		nsLabels := map[string]map[string]string{}
+		if nsList, err := dynClient.Resource(schema.GroupVersionResource{
+			Version: "v1", Resource: "namespaces",
+		}).List(ctx, v1.ListOptions{}); err == nil {
+			for _, ns := range nsList.Items {
+				nsLabels[ns.GetName()] = ns.GetLabels()
+			}
+		}
+		return func(ns string, labels map[string]string) string {
+			return dashboardEnvironment(ns, labels, nsLabels[ns])
+		}
+	}
+	return func(string, map[string]string) string { return "" }
+}
+
+// dashboardEnvironment is a resource's environment: its environment label,
+// then its namespace's, then the environment the namespace name suggests.
+func dashboardEnvironment(namespace string, labels, nsLabels map[string]string) string {
+	for _, l := range []map[string]string{labels, nsLabels} {
+		for _, key := range environmentLabels {
+			if env := l[key]; env != "" {
+				return env
+			}
+		}
+	}
+	return inferEnvironment(namespace, "")
+}
+
+// deployerGroupNamespace is the namespace a deployer applies into, or its
+// own namespace when it does not set one.
+func deployerGroupNamespace(d *unstructured.Unstructured) string {
+	if ns := deployerTargetNamespace(d); ns != "" {
+		return ns
+	}
+	return d.GetNamespace()
+}
+
+// sortedDashboardGroups orders groups production first, then staging,
+// development and testing, then the rest by name, with unknown last.
+func sortedDashboardGroups(groups dashboardGroups) []string {
+	rank := map[string]int{"production": 0, "staging": 1, "development": 2, "testing": 3}
+	groupRank := func(key string) int {
+		if key == "unknown" {
+			return 5
+		}
+		if r, ok := rank[inferEnvironment(key, "")]; ok {
+			return r
+		}
+		return 4
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ri, rj := groupRank(keys[i]), groupRank(keys[j]); ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// writeDashboardCounts prints a section's HEALTH, COVERAGE and BY OWNER.
+func writeDashboardCounts(w io.Writer, c *dashboardCounts) {
+	problems := (c.DeployersTotal - c.DeployersReady) + (c.WorkloadsTotal - c.WorkloadsReady)
+	if problems == 0 {
+		fmt.Fprintf(w, "HEALTH: ✓ healthy  %d/%d deployers, %d/%d workloads\n",
+			c.DeployersReady, c.DeployersTotal, c.WorkloadsReady, c.WorkloadsTotal)
+	} else {
+		fmt.Fprintf(w, "HEALTH: ✗ %d problem(s)  %d/%d deployers, %d/%d workloads\n",
+			problems, c.DeployersReady, c.DeployersTotal, c.WorkloadsReady, c.WorkloadsTotal)
+	}
+
+	total := c.WorkloadsTotal
+	coverage := 0
+	if total > 0 {
+		coverage = ((total - c.ByOwner["Native"]) * 100) / total
+	}
+	fmt.Fprintf(w, "COVERAGE: %d%% GitOps managed\n", coverage)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "BY OWNER:")
+	for _, owner := range dashboardOwners {
+		n := c.ByOwner[owner]
+		if n == 0 {
+			continue
+		}
+		if owner == "Native" {
+			fmt.Fprintf(w, "  %-9s %3d %s  ⚠ SHADOW IT\n", owner, n, makeBar(n, total, 20))
+		} else {
+			fmt.Fprintf(w, "  %-9s %3d %s\n", owner, n, makeBar(n, total, 20))
+		}
+	}
+}
+
+// writeDashboard prints every section, headed by its group name when
+// grouped, and the cluster's native workload count.
+func writeDashboard(w io.Writer, groupBy string, groups dashboardGroups) {
+	if groupBy == "" {
+		writeDashboardCounts(w, groups.get(""))
+	} else {
+		for i, key := range sortedDashboardGroups(groups) {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "── %s %s%s%s: %d deployer(s), %d workload(s) ──\n",
+				groupBy, colorBold, key, colorReset, groups[key].DeployersTotal, groups[key].WorkloadsTotal)
+			writeDashboardCounts(w, groups[key])
+		}
+	}
+
+	native := 0
+	for _, c := range groups {
+		native += c.ByOwner["Native"]
+	}
+	if native > 0 {
+		fmt.Fprintf(w, "\n⚠ %d native workload(s) - run: cub-scout map bypass\n", native)
+	}
+}
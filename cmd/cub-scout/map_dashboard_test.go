@@ -0,0 +1,129 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestDashboardEnvironmentGrouping(t *testing.T) {
+	ns := func(name string, labels map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace",
+			"metadata": map[string]interface{}{"name": name, "labels": labels},
+		}}
+	}
+	fake := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{{Version: "v1", Resource: "namespaces"}: "NamespaceList"},
+		ns("payments", map[string]interface{}{"environment": "production"}),
+		ns("web-dev", nil),
+	)
+	groupOf := newDashboardGrouper(context.Background(), fake, "env")
+
+	for _, tc := range []struct {
+		namespace string
+		labels    map[string]string
+		want      string
+	}{
+		{"payments", nil, "production"},                            // namespace label
+		{"payments", map[string]string{"env": "canary"}, "canary"}, // resource label wins
+		{"web-dev", nil, "development"},                            // namespace name
+		{"shared", nil, "unknown"},                                 // no signal
+		{"web-dev", map[string]string{"environment": "qa"}, "qa"},  // resource label
+		{"web-dev", map[string]string{"env": "", "app": "web"}, "development"},
+	} {
+		if got := groupOf(tc.namespace, tc.labels); got != tc.want {
+			t.Errorf("group of %s %v = %q, want %q", tc.namespace, tc.labels, got, tc.want)
+		}
+	}
+
+	if got := newDashboardGrouper(context.Background(), fake, "")("payments", nil); got != "" {
+		t.Errorf("ungrouped = %q, want one group", got)
+	}
+}
+
+func TestDeployerGroupNamespace(t *testing.T) {
+	app := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "Application",
+		"metadata": map[string]interface{}{"name": "payments", "namespace": "argocd"},
+		"spec":     map[string]interface{}{"destination": map[string]interface{}{"namespace": "payments-prod"}},
+	}}
+	if got := deployerGroupNamespace(app); got != "payments-prod" {
+		t.Errorf("Application = %q, want its destination namespace", got)
+	}
+	ks := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "Kustomization",
+		"metadata": map[string]interface{}{"name": "infra", "namespace": "flux-system"},
+	}}
+	if got := deployerGroupNamespace(ks); got != "flux-system" {
+		t.Errorf("Kustomization without targetNamespace = %q, want its own namespace", got)
+	}
+}
+
+func TestWriteDashboardGrouped(t *testing.T) {
+	groups := dashboardGroups{}
+	groups.get("development").addWorkload(false, "Native")
+	groups.get("development").addWorkload(true, "Native")
+	groups.get("production").addDeployer(true)
+	groups.get("production").addWorkload(true, "Flux")
+	groups.get("production").addWorkload(true, "ConfigHub")
+	groups.get("unknown").addWorkload(true, "Helm")
+	groups.get("canary").addWorkload(true, "ArgoCD")
+
+	var buf bytes.Buffer
+	writeDashboard(&buf, "env", groups)
+	out := buf.String()
+
+	// Production first, unknown last; dev problems stay in dev
+	order := []string{"production" + colorReset + ": 1 deployer(s), 2 workload(s)", "development", "canary", "unknown"}
+	last := -1
+	for _, want := range order {
+		i := strings.Index(out, want)
+		if i <= last {
+			t.Fatalf("%q out of order:\n%s", want, out)
+		}
+		last = i
+	}
+	prod := out[:strings.Index(out, "development")]
+	if !strings.Contains(prod, "HEALTH: ✓ healthy  1/1 deployers, 2/2 workloads") || !strings.Contains(prod, "COVERAGE: 100% GitOps managed") {
+		t.Errorf("production section:\n%s", prod)
+	}
+	if !strings.Contains(out, "HEALTH: ✗ 1 problem(s)  0/0 deployers, 1/2 workloads") {
+		t.Errorf("development section should report its problem:\n%s", out)
+	}
+	if !strings.Contains(out, "⚠ 2 native workload(s)") {
+		t.Errorf("missing the cluster native count:\n%s", out)
+	}
+}
+
+func TestWriteDashboardUngrouped(t *testing.T) {
+	groups := dashboardGroups{}
+	groups.get("").addWorkload(true, "Flux")
+	groups.get("").addWorkload(true, "Native")
+
+	var buf bytes.Buffer
+	writeDashboard(&buf, "", groups)
+	out := buf.String()
+	if strings.Contains(out, "──") {
+		t.Errorf("ungrouped output has group headings:\n%s", out)
+	}
+	for _, want := range []string{
+		"HEALTH: ✓ healthy  0/0 deployers, 2/2 workloads",
+		"COVERAGE: 50% GitOps managed",
+		"  Flux        1 ██████████░░░░░░░░░░",
+		"  Native      1 ██████████░░░░░░░░░░  ⚠ SHADOW IT",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
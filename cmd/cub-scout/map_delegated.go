@@ -0,0 +1,456 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var mapDelegatedCmd = &cobra.Command{
+	Use:     "delegated",
+	Aliases: []string{"delegation"},
+	Short:   "Summarize ConfigHub delegated-apply pipelines",
+	Long: `Summarize every delegated-apply pipeline in the cluster:
+
+  ConfigHub unit → OCI artifact → Flux Kustomization / Argo Application → resources
+
+For each pipeline this shows which units publish to which OCI repository,
+which Flux or Argo object consumes it, the revision fetched from the registry
+versus the revision last applied, and whether the chain is healthy:
+
+  Healthy   registry and cluster are at the same revision
+  Stalled   the registry moved but the deployer has not applied it yet
+  Failing   the source fetch or the apply is failing
+
+A pipeline is also stalled when a unit's head revision was published in
+ConfigHub more than --stall-after before the deployer's last apply caught up.
+The lag is how long the cluster has been behind. ConfigHub revisions are read
+with the cub CLI when it is installed and authenticated.
+
+With --verify-signatures, the OCI config artifact each pipeline last fetched
+and the images of the workloads it applies are checked with cosign for a
+signature and a SLSA provenance attestation. Unverified artifacts are listed
+under their pipeline. Pass --cosign-key, or for keyless verification
+--certificate-identity and --certificate-oidc-issuer matching your CI.
+
+With --annotate, each Kustomization or Application is annotated with the
+ConfigHub linkage it applies, so 'kubectl describe' shows it without
+cub-scout:
+
+  confighub.com/SourceSpace     space of the OCI target
+  confighub.com/SourceTarget    target
+  confighub.com/SourceUnits     units seen on the applied resources
+  confighub.com/SourceRevision  ConfigHub head revision, once applied
+
+Flux and Argo ignore these annotations; they trigger no reconcile. Map
+commands are read-only by default, so --annotate needs --read-only=false.
+
+Examples:
+  cub-scout map delegated
+  cub-scout map delegated --stall-after 30m
+  cub-scout map delegated --verify-signatures --cosign-key cosign.pub
+  cub-scout map delegated --verify-signatures \
+    --certificate-identity 'https://github.com/acme/.*' \
+    --certificate-oidc-issuer https://token.actions.githubusercontent.com
+  cub-scout map delegated --annotate --read-only=false --yes
+  cub-scout map delegated --json`,
+	RunE: runMapDelegated,
+}
+
+// DelegatedPipeline is one ConfigHub → OCI → deployer → cluster chain
+type DelegatedPipeline struct {
+	Via       string   `json:"via"`
+	Deployer  string   `json:"deployer"` // Kind/namespace/name
+	Space     string   `json:"space"`
+	Target    string   `json:"target"`
+	OCIURL    string   `json:"ociUrl"`
+	Units     []string `json:"units,omitempty"`
+	Resources int      `json:"resources"`
+
+	// RegistryRevision is the latest artifact revision fetched from the registry
+	RegistryRevision string `json:"registryRevision,omitempty"`
+
+	// AppliedRevision is the revision the deployer last applied to the cluster
+	AppliedRevision string `json:"appliedRevision,omitempty"`
+
+	// RegistryUpdatedAt is when the deployer last saw a new artifact (Flux only)
+	RegistryUpdatedAt *time.Time `json:"registryUpdatedAt,omitempty"`
+
+	// AppliedAt is when the deployer last finished applying
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+
+	// HeadRevision and HeadPublishedAt are the newest ConfigHub revision across
+	// the pipeline's units (set when ConfigHub is reachable)
+	HeadRevision    int        `json:"headRevision,omitempty"`
+	HeadUnit        string     `json:"headUnit,omitempty"`
+	HeadPublishedAt *time.Time `json:"headPublishedAt,omitempty"`
+
+	// LagSeconds is how long the cluster has been behind, for stalled pipelines
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+
+	Health  string `json:"health"` // Healthy, Stalled, Failing
+	Message string `json:"message,omitempty"`
+
+	// Artifacts are signature checks of the config artifact and images
+	// (set with --verify-signatures)
+	Artifacts  []ArtifactVerification `json:"artifacts,omitempty"`
+	Unverified int                    `json:"unverified,omitempty"`
+}
+
+const (
+	pipelineHealthy = "Healthy"
+	pipelineStalled = "Stalled"
+	pipelineFailing = "Failing"
+)
+
+var mapDelegatedStallAfter = 10 * time.Minute
+
+func init() {
+	mapCmd.AddCommand(mapDelegatedCmd)
+	mapDelegatedCmd.Flags().DurationVar(&mapDelegatedStallAfter, "stall-after", 10*time.Minute, "Grace period before a pipeline behind the registry or ConfigHub is reported as stalled")
+}
+
+func runMapDelegated(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if mapDelegatedVerify {
+		if err := checkVerifyFlags(mapDelegatedCosignKey, mapDelegatedCertIdentity, mapDelegatedCertOIDCIssuer); err != nil {
+			return err
+		}
+	}
+	if mapDelegatedAnnotate {
+		if err := checkWrite("annotate deployers (--annotate)"); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	clusterName := currentClusterName()
+
+	pipelines := collectDelegatedPipelines(ctx, dynClient, nil, clusterName, true)
+	if mapDelegatedVerify && len(pipelines) > 0 {
+		verify, err := cosignVerifier()
+		if err != nil {
+			return err
+		}
+		verifyPipelineArtifacts(pipelines, pipelineImages(ctx, dynClient, ownerDelegations), verify)
+	}
+
+	if mapJSON && !mapDelegatedAnnotate {
+		return writeJSON(os.Stdout, "DelegatedPipelines", pipelines)
+	}
+
+	printDelegatedPipelines(pipelines)
+	if !mapDelegatedAnnotate || len(pipelines) == 0 {
+		return nil
+	}
+
+	if ok, err := confirmChanges(delegatedLinkChanges(pipelines), mapDelegatedYes); err != nil || !ok {
+		return err
+	}
+	if failed := annotateDelegatedPipelines(ctx, dynClient, pipelines); failed > 0 {
+		return fmt.Errorf("%d of %d deployer(s) could not be annotated", failed, len(pipelines))
+	}
+	return nil
+}
+
+// collectDelegatedPipelines loads the delegation index and deployer status from
+// the cluster. entries may be nil, in which case they are collected here.
+// withConfigHub also reads unit head revisions through the cub CLI.
+func collectDelegatedPipelines(ctx context.Context, dynClient dynamic.Interface, entries []MapEntry, clusterName string, withConfigHub bool) []DelegatedPipeline {
+	idx := loadDelegations(ctx, dynClient)
+	if idx.Len() == 0 {
+		return nil
+	}
+	if entries == nil {
+		entries = collectInventoryEntries(ctx, dynClient, clusterName)
+	}
+
+	kustomizations := listAll(ctx, dynClient, schema.GroupVersionResource{
+		Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations",
+	})
+	repos := listAll(ctx, dynClient, schema.GroupVersionResource{
+		Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "ocirepositories",
+	})
+	if repos == nil {
+		repos = listAll(ctx, dynClient, schema.GroupVersionResource{
+			Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "ocirepositories",
+		})
+	}
+	apps := listAll(ctx, dynClient, schema.GroupVersionResource{
+		Group: "argoproj.io", Version: "v1alpha1", Resource: "applications",
+	})
+
+	pipelines := buildDelegatedPipelines(idx, kustomizations, repos, apps, entries)
+	if withConfigHub {
+		if cubInstalled() {
+			enrichPipelinesFromConfigHub(pipelines, fetchConfigHubRevisions)
+		}
+	}
+	markStalledPipelines(pipelines, time.Now(), mapDelegatedStallAfter)
+	return pipelines
+}
+
+// buildDelegatedPipelines joins each delegation with its deployer and source
+// status and with the map entries it applied.
+func buildDelegatedPipelines(idx *agent.DelegationIndex, kustomizations, repos, apps []unstructured.Unstructured, entries []MapEntry) []DelegatedPipeline {
+	find := func(items []unstructured.Unstructured, namespace, name string) *unstructured.Unstructured {
+		for i := range items {
+			if items[i].GetName() == name && items[i].GetNamespace() == namespace {
+				return &items[i]
+			}
+		}
+		return nil
+	}
+
+	var pipelines []DelegatedPipeline
+	for _, d := range idx.Delegations() {
+		p := DelegatedPipeline{
+			Via:      d.Via,
+			Deployer: d.DeployerKind + "/" + d.DeployerNamespace + "/" + d.DeployerName,
+			Space:    d.Space,
+			Target:   d.Target,
+			OCIURL:   d.URL,
+			Health:   pipelineHealthy,
+		}
+
+		switch d.DeployerKind {
+		case "Kustomization":
+			ks := find(kustomizations, d.DeployerNamespace, d.DeployerName)
+			repo := find(repos, d.SourceNamespace, d.SourceName)
+			if repo != nil {
+				p.RegistryRevision, _, _ = unstructured.NestedString(repo.Object, "status", "artifact", "revision")
+			}
+			if repo != nil {
+				p.RegistryUpdatedAt = parseTimePtr(repo, "status", "artifact", "lastUpdateTime")
+			}
+			if ks != nil {
+				p.AppliedRevision, _, _ = unstructured.NestedString(ks.Object, "status", "lastAppliedRevision")
+				p.AppliedAt = readyTransitionTime(ks)
+			}
+			switch {
+			case repo == nil || !isResourceReady(repo):
+				p.Health = pipelineFailing
+				p.Message = "OCIRepository/" + d.SourceName + ": "
+				if repo != nil {
+					p.Message += getConditionMessage(repo)
+				} else {
+					p.Message += "not found"
+				}
+			case ks == nil || !isResourceReady(ks):
+				p.Health = pipelineFailing
+				p.Message = "Kustomization/" + d.DeployerName + ": "
+				if ks != nil {
+					p.Message += getConditionMessage(ks)
+				} else {
+					p.Message += "not found"
+				}
+			}
+
+		case "Application":
+			app := find(apps, d.DeployerNamespace, d.DeployerName)
+			if app != nil {
+				p.RegistryRevision, _, _ = unstructured.NestedString(app.Object, "status", "sync", "revision")
+				p.AppliedRevision, _, _ = unstructured.NestedString(app.Object, "status", "operationState", "syncResult", "revision")
+				p.AppliedAt = parseTimePtr(app, "status", "operationState", "finishedAt")
+				if health, _, _ := unstructured.NestedString(app.Object, "status", "health", "status"); health == "Degraded" || health == "Missing" {
+					p.Health = pipelineFailing
+					p.Message, _, _ = unstructured.NestedString(app.Object, "status", "health", "message")
+					if p.Message == "" {
+						p.Message = "Application health is " + health
+					}
+				}
+			} else {
+				p.Health = pipelineFailing
+				p.Message = "Application/" + d.DeployerName + ": not found"
+			}
+		}
+
+		deployer := d.DeployerKind + "/" + d.DeployerName
+		for _, e := range entries {
+			if e.OwnerDetails["deployer"] != deployer {
+				continue
+			}
+			p.Resources++
+			if unit := e.OwnerDetails["unit"]; unit != "" {
+				p.Units = appendUnique(p.Units, unit)
+			}
+		}
+		sort.Strings(p.Units)
+
+		pipelines = append(pipelines, p)
+	}
+	return pipelines
+}
+
+// enrichPipelinesFromConfigHub records the newest head revision across each
+// pipeline's units. Lookup failures leave the pipeline unchanged.
+func enrichPipelinesFromConfigHub(pipelines []DelegatedPipeline, fetch func(space, unit string) ([]ConfigHubRevision, error)) {
+	for i := range pipelines {
+		p := &pipelines[i]
+		for _, unit := range p.Units {
+			revisions, err := fetch(p.Space, unit)
+			if err != nil {
+				continue
+			}
+			for _, rev := range revisions {
+				if rev.CreatedAt.IsZero() {
+					continue
+				}
+				if p.HeadPublishedAt == nil || rev.CreatedAt.After(*p.HeadPublishedAt) {
+					at := rev.CreatedAt
+					p.HeadRevision = rev.Num
+					p.HeadUnit = unit
+					p.HeadPublishedAt = &at
+				}
+			}
+		}
+	}
+}
+
+// markStalledPipelines flags healthy pipelines whose cluster is behind the
+// registry, or behind a ConfigHub head revision published more than grace
+// before the last apply, and records the lag.
+func markStalledPipelines(pipelines []DelegatedPipeline, now time.Time, grace time.Duration) {
+	for i := range pipelines {
+		p := &pipelines[i]
+		if p.Health != pipelineHealthy {
+			continue
+		}
+
+		// The registry moved but the deployer has not applied the new artifact
+		if p.RegistryRevision != "" && p.RegistryRevision != p.AppliedRevision {
+			if p.RegistryUpdatedAt != nil && now.Sub(*p.RegistryUpdatedAt) < grace {
+				continue // still within the normal reconcile window
+			}
+			p.Health = pipelineStalled
+			p.Message = fmt.Sprintf("registry at %s but cluster applied %s", shortRevision(p.RegistryRevision), shortRevision(p.AppliedRevision))
+			if p.RegistryUpdatedAt != nil {
+				p.LagSeconds = int64(now.Sub(*p.RegistryUpdatedAt).Seconds())
+				p.Message += fmt.Sprintf(" (%s behind)", formatElapsed(now.Sub(*p.RegistryUpdatedAt)))
+			}
+			continue
+		}
+
+		// ConfigHub published a newer head revision than the cluster has applied
+		if p.HeadPublishedAt != nil && p.AppliedAt != nil &&
+			p.HeadPublishedAt.After(p.AppliedAt.Add(grace)) && now.Sub(*p.HeadPublishedAt) >= grace {
+			lag := now.Sub(*p.HeadPublishedAt)
+			p.Health = pipelineStalled
+			p.LagSeconds = int64(lag.Seconds())
+			p.Message = fmt.Sprintf("%s rev %d published %s ago; last apply was %s ago",
+				p.HeadUnit, p.HeadRevision, formatElapsed(lag), formatElapsed(now.Sub(*p.AppliedAt)))
+		}
+	}
+}
+
+// parseTimePtr reads an RFC3339 timestamp field, or nil if absent.
+func parseTimePtr(obj *unstructured.Unstructured, fields ...string) *time.Time {
+	v, _, _ := unstructured.NestedString(obj.Object, fields...)
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// readyTransitionTime returns the Ready condition's lastTransitionTime. Flux
+// rewrites the condition on every applied revision, so this approximates the
+// last apply.
+func readyTransitionTime(obj *unstructured.Unstructured) *time.Time {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		if v, ok := cond["lastTransitionTime"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return &t
+			}
+		}
+	}
+	return nil
+}
+
+// shortRevision trims digests for display: "latest@sha256:abcdef…" -> "latest@sha256:abcdef123456".
+func shortRevision(rev string) string {
+	if rev == "" {
+		return "-"
+	}
+	if idx := strings.LastIndex(rev, ":"); idx != -1 && len(rev)-idx-1 > 12 {
+		return rev[:idx+13]
+	}
+	return rev
+}
+
+func printDelegatedPipelines(pipelines []DelegatedPipeline) {
+	fmt.Println("DELEGATED APPLY PIPELINES (ConfigHub → OCI → deployer → cluster)")
+	fmt.Println()
+	if len(pipelines) == 0 {
+		fmt.Println("No delegated pipelines found.")
+		fmt.Println("Delegated apply means a Flux OCIRepository or Argo Application reads a")
+		fmt.Println("ConfigHub OCI URL (oci://oci.<instance>/target/<space>/<target>).")
+		return
+	}
+
+	counts := map[string]int{}
+	unverified := 0
+	for _, p := range pipelines {
+		counts[p.Health]++
+		unverified += p.Unverified
+		icon := "✓"
+		switch p.Health {
+		case pipelineStalled:
+			icon = "⏸"
+		case pipelineFailing:
+			icon = "✗"
+		}
+
+		fmt.Printf("%s %s/%s → %s (%s)  %s\n", icon, p.Space, p.Target, p.Deployer, p.Via, p.Health)
+		fmt.Printf("    OCI:       %s\n", p.OCIURL)
+		units := "(not recorded on resources)"
+		if len(p.Units) > 0 {
+			units = strings.Join(p.Units, ", ")
+		}
+		fmt.Printf("    Units:     %s (%d resources)\n", units, p.Resources)
+		fmt.Printf("    Registry:  %s\n", shortRevision(p.RegistryRevision))
+		fmt.Printf("    Applied:   %s\n", shortRevision(p.AppliedRevision))
+		if p.HeadPublishedAt != nil {
+			fmt.Printf("    ConfigHub: %s rev %d, published %s ago\n", p.HeadUnit, p.HeadRevision, formatElapsed(time.Since(*p.HeadPublishedAt)))
+		}
+		if p.Message != "" {
+			fmt.Printf("    Message:   %s\n", p.Message)
+		}
+		printArtifactVerifications(p)
+		fmt.Println()
+	}
+
+	fmt.Printf("%d pipelines: %d healthy, %d stalled, %d failing\n",
+		len(pipelines), counts[pipelineHealthy], counts[pipelineStalled], counts[pipelineFailing])
+	if unverified > 0 {
+		fmt.Printf("%s⚠ %d unverified artifact(s): missing or invalid signature or provenance%s\n", colorYellow, unverified, colorReset)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Link annotations written on delegating Flux and Argo objects. They are
+// distinct from confighub.com/UnitSlug so the deployer itself is not
+// reported as ConfigHub-owned.
+const (
+	sourceSpaceKey    = "confighub.com/SourceSpace"
+	sourceTargetKey   = "confighub.com/SourceTarget"
+	sourceUnitsKey    = "confighub.com/SourceUnits"
+	sourceRevisionKey = "confighub.com/SourceRevision"
+)
+
+var (
+	mapDelegatedAnnotate bool
+	mapDelegatedYes      bool
+)
+
+func init() {
+	mapDelegatedCmd.Flags().BoolVar(&mapDelegatedAnnotate, "annotate", false, "Annotate each Kustomization or Application with the ConfigHub space, target, units and revision it applies")
+	mapDelegatedCmd.Flags().BoolVarP(&mapDelegatedYes, "yes", "y", false, "Skip the --annotate confirmation")
+}
+
+// delegatedDeployerGVRs are the deployer kinds a pipeline can name.
+var delegatedDeployerGVRs = map[string]schema.GroupVersionResource{
+	"Kustomization": {Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"},
+	"Application":   {Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+}
+
+// delegatedLinkPatch is a merge patch setting a pipeline's link annotations
+// on its deployer. The revision is only recorded once the cluster has
+// applied the ConfigHub head; otherwise a previously written one is removed.
+func delegatedLinkPatch(p DelegatedPipeline) []byte {
+	patch, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": delegatedLinkAnnotations(p)}})
+	return patch
+}
+
+// delegatedLinkAnnotations maps each link annotation to its value, or to
+// nil when it is removed.
+func delegatedLinkAnnotations(p DelegatedPipeline) map[string]interface{} {
+	annotations := map[string]interface{}{
+		sourceSpaceKey:    p.Space,
+		sourceTargetKey:   p.Target,
+		sourceUnitsKey:    nil,
+		sourceRevisionKey: nil,
+	}
+	if len(p.Units) > 0 {
+		annotations[sourceUnitsKey] = strings.Join(p.Units, ",")
+	}
+	if p.HeadRevision > 0 && p.Health == pipelineHealthy {
+		annotations[sourceRevisionKey] = strconv.Itoa(p.HeadRevision)
+	}
+	return annotations
+}
+
+// delegatedLinkChanges lists the annotations --annotate sets or removes on
+// each pipeline's deployer.
+func delegatedLinkChanges(pipelines []DelegatedPipeline) []plannedChange {
+	changes := make([]plannedChange, 0, len(pipelines))
+	for _, p := range pipelines {
+		annotations := delegatedLinkAnnotations(p)
+		keys := make([]string, 0, len(annotations))
+		for k := range annotations {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var parts []string
+		for _, k := range keys {
+			if v, ok := annotations[k].(string); ok {
+				parts = append(parts, k+"="+v)
+			} else {
+				parts = append(parts, k+"-")
+			}
+		}
+		changes = append(changes, plannedChange{Action: "annotate", Target: p.Deployer, Detail: strings.Join(parts, " "), Cluster: true})
+	}
+	return changes
+}
+
+// annotateDelegatedPipelines writes the link annotations on every pipeline's
+// deployer and returns the number that failed.
+func annotateDelegatedPipelines(ctx context.Context, dynClient dynamic.Interface, pipelines []DelegatedPipeline) int {
+	failed := 0
+	for _, p := range pipelines {
+		kind, rest, _ := strings.Cut(p.Deployer, "/")
+		namespace, name, _ := strings.Cut(rest, "/")
+		gvr, ok := delegatedDeployerGVRs[kind]
+		var err error
+		if !ok {
+			err = fmt.Errorf("unsupported deployer kind %s", kind)
+		} else {
+			_, err = dynClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, delegatedLinkPatch(p), v1.PatchOptions{FieldManager: "cub-scout"})
+		}
+		if err != nil {
+			fmt.Printf("  %s✗%s %s: %v\n", colorRed, colorReset, p.Deployer, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  %s✓%s %s → %s/%s\n", colorGreen, colorReset, p.Deployer, p.Space, p.Target)
+	}
+	return failed
+}
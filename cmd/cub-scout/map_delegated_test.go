@@ -0,0 +1,196 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func delegatedTestObject(kind, name string, spec, status map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     kind,
+		"metadata": map[string]interface{}{"name": name, "namespace": "flux-system"},
+		"spec":     spec,
+		"status":   status,
+	}}
+}
+
+func readyStatus(extra map[string]interface{}) map[string]interface{} {
+	status := map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True"},
+		},
+	}
+	for k, v := range extra {
+		status[k] = v
+	}
+	return status
+}
+
+func TestBuildDelegatedPipelines(t *testing.T) {
+	kustomizations := []unstructured.Unstructured{
+		delegatedTestObject("Kustomization", "prod-apps",
+			map[string]interface{}{"sourceRef": map[string]interface{}{"kind": "OCIRepository", "name": "hub-prod"}},
+			readyStatus(map[string]interface{}{"lastAppliedRevision": "latest@sha256:aaa"})),
+		delegatedTestObject("Kustomization", "qa-apps",
+			map[string]interface{}{"sourceRef": map[string]interface{}{"kind": "OCIRepository", "name": "hub-qa"}},
+			readyStatus(map[string]interface{}{"lastAppliedRevision": "latest@sha256:old"})),
+	}
+	repos := []unstructured.Unstructured{
+		delegatedTestObject("OCIRepository", "hub-prod",
+			map[string]interface{}{"url": "oci://oci.api.confighub.com/target/prod/us-west"},
+			readyStatus(map[string]interface{}{"artifact": map[string]interface{}{"revision": "latest@sha256:aaa"}})),
+		delegatedTestObject("OCIRepository", "hub-qa",
+			map[string]interface{}{"url": "oci://oci.api.confighub.com/target/qa/qa-cluster"},
+			readyStatus(map[string]interface{}{"artifact": map[string]interface{}{"revision": "latest@sha256:new"}})),
+	}
+	idx := agent.NewDelegationIndex()
+	idx.AddFluxKustomizations(kustomizations, repos)
+
+	entries := []MapEntry{
+		{Name: "api", OwnerDetails: map[string]string{"deployer": "Kustomization/prod-apps", "unit": "api"}},
+		{Name: "api-svc", OwnerDetails: map[string]string{"deployer": "Kustomization/prod-apps", "unit": "api"}},
+		{Name: "web", OwnerDetails: map[string]string{"deployer": "Kustomization/prod-apps", "unit": "web"}},
+	}
+
+	pipelines := buildDelegatedPipelines(idx, kustomizations, repos, nil, entries)
+	markStalledPipelines(pipelines, time.Now(), 10*time.Minute)
+	if len(pipelines) != 2 {
+		t.Fatalf("expected 2 pipelines, got %d", len(pipelines))
+	}
+
+	prod := pipelines[0]
+	if prod.Deployer != "Kustomization/flux-system/prod-apps" || prod.Health != pipelineHealthy {
+		t.Errorf("unexpected prod pipeline: %+v", prod)
+	}
+	if prod.Resources != 3 || len(prod.Units) != 2 || prod.Units[0] != "api" || prod.Units[1] != "web" {
+		t.Errorf("unexpected prod units: %+v", prod)
+	}
+
+	qa := pipelines[1]
+	if qa.Health != pipelineStalled || qa.RegistryRevision != "latest@sha256:new" || qa.AppliedRevision != "latest@sha256:old" {
+		t.Errorf("expected stalled qa pipeline, got %+v", qa)
+	}
+}
+
+func TestBuildDelegatedPipelinesFailing(t *testing.T) {
+	kustomizations := []unstructured.Unstructured{
+		delegatedTestObject("Kustomization", "apps",
+			map[string]interface{}{"sourceRef": map[string]interface{}{"kind": "OCIRepository", "name": "hub"}}, nil),
+	}
+	repos := []unstructured.Unstructured{
+		delegatedTestObject("OCIRepository", "hub",
+			map[string]interface{}{"url": "oci://oci.api.confighub.com/target/prod/us-west"},
+			map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "message": "unauthorized"},
+			}}),
+	}
+	idx := agent.NewDelegationIndex()
+	idx.AddFluxKustomizations(kustomizations, repos)
+
+	pipelines := buildDelegatedPipelines(idx, kustomizations, repos, nil, nil)
+	if len(pipelines) != 1 || pipelines[0].Health != pipelineFailing {
+		t.Fatalf("expected failing pipeline, got %+v", pipelines)
+	}
+}
+
+func TestMarkStalledPipelinesRegistryLag(t *testing.T) {
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	moved := now.Add(-5 * time.Minute)
+	pipelines := []DelegatedPipeline{{
+		Health:            pipelineHealthy,
+		RegistryRevision:  "latest@sha256:new",
+		AppliedRevision:   "latest@sha256:old",
+		RegistryUpdatedAt: &moved,
+	}}
+
+	// Within the grace period the deployer is still expected to catch up
+	markStalledPipelines(pipelines, now, 10*time.Minute)
+	if pipelines[0].Health != pipelineHealthy {
+		t.Fatalf("expected healthy within grace, got %+v", pipelines[0])
+	}
+
+	markStalledPipelines(pipelines, now, 2*time.Minute)
+	if pipelines[0].Health != pipelineStalled || pipelines[0].LagSeconds != 300 {
+		t.Errorf("expected stalled with 5m lag, got %+v", pipelines[0])
+	}
+}
+
+func TestMarkStalledPipelinesConfigHubHead(t *testing.T) {
+	now := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	applied := now.Add(-2 * time.Hour)
+	pipelines := []DelegatedPipeline{{
+		Health:           pipelineHealthy,
+		Units:            []string{"api", "web"},
+		RegistryRevision: "latest@sha256:aaa",
+		AppliedRevision:  "latest@sha256:aaa",
+		AppliedAt:        &applied,
+	}}
+
+	fetch := func(space, unit string) ([]ConfigHubRevision, error) {
+		if unit == "web" {
+			return []ConfigHubRevision{{Num: 9, CreatedAt: now.Add(-30 * time.Minute)}}, nil
+		}
+		return []ConfigHubRevision{{Num: 4, CreatedAt: now.Add(-3 * time.Hour)}}, nil
+	}
+	enrichPipelinesFromConfigHub(pipelines, fetch)
+	if pipelines[0].HeadUnit != "web" || pipelines[0].HeadRevision != 9 {
+		t.Fatalf("expected web rev 9 as head, got %+v", pipelines[0])
+	}
+
+	markStalledPipelines(pipelines, now, 10*time.Minute)
+	p := pipelines[0]
+	if p.Health != pipelineStalled || p.LagSeconds != 1800 || !strings.Contains(p.Message, "web rev 9 published 30m 0s ago") {
+		t.Errorf("expected stalled behind ConfigHub head, got %+v", p)
+	}
+}
+
+func TestShortRevision(t *testing.T) {
+	if got := shortRevision("latest@sha256:0123456789abcdef0123"); got != "latest@sha256:0123456789ab" {
+		t.Errorf("shortRevision = %q", got)
+	}
+	if got := shortRevision(""); got != "-" {
+		t.Errorf("shortRevision(\"\") = %q", got)
+	}
+}
+
+func TestAnnotateDelegatedPipelines(t *testing.T) {
+	ks := agenttest.Object("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "prod-apps",
+		agenttest.WithAnnotations(map[string]string{sourceRevisionKey: "7"}))
+	client := agenttest.FakeClient(ks)
+
+	pipelines := []DelegatedPipeline{
+		{Deployer: "Kustomization/flux-system/prod-apps", Space: "prod", Target: "us-west", Units: []string{"api", "web"}, HeadRevision: 9, Health: pipelineStalled},
+		{Deployer: "Application/argocd/missing", Space: "qa", Target: "qa-cluster", Health: pipelineHealthy},
+	}
+	if failed := annotateDelegatedPipelines(context.Background(), client, pipelines); failed != 1 {
+		t.Errorf("failed = %d, want 1 (missing Application)", failed)
+	}
+
+	got, err := client.Resource(delegatedDeployerGVRs["Kustomization"]).Namespace("flux-system").Get(context.Background(), "prod-apps", v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations := got.GetAnnotations()
+	if annotations[sourceSpaceKey] != "prod" || annotations[sourceTargetKey] != "us-west" || annotations[sourceUnitsKey] != "api,web" {
+		t.Errorf("annotations = %v", annotations)
+	}
+	if _, ok := annotations[sourceRevisionKey]; ok {
+		t.Errorf("stalled pipeline kept %s = %s", sourceRevisionKey, annotations[sourceRevisionKey])
+	}
+
+	pipelines[0].Health = pipelineHealthy
+	if !strings.Contains(string(delegatedLinkPatch(pipelines[0])), `"confighub.com/SourceRevision":"9"`) {
+		t.Errorf("healthy pipeline patch = %s, want SourceRevision 9", delegatedLinkPatch(pipelines[0]))
+	}
+}
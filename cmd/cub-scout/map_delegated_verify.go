@@ -0,0 +1,238 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	mapDelegatedVerify         bool
+	mapDelegatedCosignKey      string
+	mapDelegatedCertIdentity   string
+	mapDelegatedCertOIDCIssuer string
+)
+
+func init() {
+	mapDelegatedCmd.Flags().BoolVar(&mapDelegatedVerify, "verify-signatures", false, "Verify cosign signatures and SLSA provenance of OCI config artifacts and images (requires cosign)")
+	mapDelegatedCmd.Flags().StringVar(&mapDelegatedCosignKey, "cosign-key", "", "Public key or KMS URI to verify with (otherwise keyless)")
+	mapDelegatedCmd.Flags().StringVar(&mapDelegatedCertIdentity, "certificate-identity", "", "Keyless: regexp the signing certificate identity must match (required without --cosign-key)")
+	mapDelegatedCmd.Flags().StringVar(&mapDelegatedCertOIDCIssuer, "certificate-oidc-issuer", "", "Keyless: regexp the signing certificate OIDC issuer must match (required without --cosign-key)")
+}
+
+// checkVerifyFlags requires a key or both keyless certificate regexps, so
+// that a signature from any identity is never accepted as verified.
+func checkVerifyFlags(key, identity, issuer string) error {
+	if key != "" || (identity != "" && issuer != "") {
+		return nil
+	}
+	return errors.New("--verify-signatures needs --cosign-key, or --certificate-identity and --certificate-oidc-issuer for keyless verification")
+}
+
+// ArtifactVerification is the signature and provenance check of one artifact
+// a delegated pipeline delivers.
+type ArtifactVerification struct {
+	Ref      string `json:"ref"`
+	Type     string `json:"type"` // config, image
+	Signed   bool   `json:"signed"`
+	Attested bool   `json:"attested"`
+	// Finding explains why the artifact is unverified
+	Finding string `json:"finding,omitempty"`
+}
+
+// Verified reports whether the artifact is both signed and attested.
+func (a ArtifactVerification) Verified() bool {
+	return a.Signed && a.Attested
+}
+
+// artifactVerifier checks the signature of ref, or with attestation set its
+// SLSA provenance attestation. A nil error means verified.
+type artifactVerifier func(ref string, attestation bool) error
+
+// cosignVerifier verifies with the cosign CLI using the --cosign-key or
+// keyless certificate flags.
+func cosignVerifier() (artifactVerifier, error) {
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return nil, errors.New("--verify-signatures needs cosign in PATH (https://docs.sigstore.dev/cosign/system_config/installation/)")
+	}
+	return func(ref string, attestation bool) error {
+		args := []string{"verify"}
+		if attestation {
+			args = []string{"verify-attestation", "--type", "slsaprovenance"}
+		}
+		if mapDelegatedCosignKey != "" {
+			args = append(args, "--key", mapDelegatedCosignKey)
+		} else {
+			args = append(args,
+				"--certificate-identity-regexp", mapDelegatedCertIdentity,
+				"--certificate-oidc-issuer-regexp", mapDelegatedCertOIDCIssuer)
+		}
+		args = append(args, "--output", "json", ref)
+
+		cmd := exec.Command("cosign", args...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return errors.New(cosignError(stderr.String(), err))
+		}
+		return nil
+	}, nil
+}
+
+// cosignError returns the last "Error:" line cosign printed, which names the
+// reason (no signatures found, identity mismatch, ...).
+func cosignError(stderr string, err error) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if msg, ok := strings.CutPrefix(strings.TrimSpace(lines[i]), "Error: "); ok {
+			return msg
+		}
+	}
+	return err.Error()
+}
+
+// configArtifactRef returns the registry reference of the OCI artifact a
+// pipeline last fetched, pinned by digest when the revision has one:
+// "oci://host/target/s/t" at "latest@sha256:abc" -> "host/target/s/t@sha256:abc".
+func configArtifactRef(p DelegatedPipeline) string {
+	repo := strings.TrimPrefix(p.OCIURL, "oci://")
+	if repo == "" {
+		return ""
+	}
+	rev := p.RegistryRevision
+	if i := strings.Index(rev, "@"); i != -1 {
+		return repo + rev[i:]
+	}
+	if strings.HasPrefix(rev, "sha256:") {
+		return repo + "@" + rev
+	}
+	if rev != "" {
+		return repo + ":" + rev
+	}
+	return repo
+}
+
+// pipelineImages returns the container images of the workloads each
+// delegated deployer applies, keyed by the pipeline's Deployer.
+func pipelineImages(ctx context.Context, dynClient dynamic.Interface, idx *agent.DelegationIndex) map[string][]string {
+	images := map[string][]string{}
+	if idx.Len() == 0 {
+		return images
+	}
+	for _, gvr := range []schema.GroupVersionResource{
+		{Group: "apps", Version: "v1", Resource: "deployments"},
+		{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		{Group: "apps", Version: "v1", Resource: "daemonsets"},
+		{Group: "batch", Version: "v1", Resource: "cronjobs"},
+	} {
+		for _, obj := range listAll(ctx, dynClient, gvr) {
+			d := idx.DetectDelegation(&obj)
+			if d == nil {
+				continue
+			}
+			key := d.DeployerKind + "/" + d.DeployerNamespace + "/" + d.DeployerName
+			for _, img := range workloadImages(&obj) {
+				images[key] = appendUnique(images[key], img)
+			}
+		}
+	}
+	for k := range images {
+		sort.Strings(images[k])
+	}
+	return images
+}
+
+// workloadImages returns the init and app container images of a workload or
+// CronJob pod template.
+func workloadImages(obj *unstructured.Unstructured) []string {
+	path := []string{"spec", "template", "spec"}
+	if obj.GetKind() == "CronJob" {
+		path = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	}
+	var images []string
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(obj.Object, append(path, field)...)
+		for _, c := range containers {
+			if m, ok := c.(map[string]interface{}); ok {
+				if img, _ := m["image"].(string); img != "" {
+					images = appendUnique(images, img)
+				}
+			}
+		}
+	}
+	return images
+}
+
+// verifyPipelineArtifacts checks the config artifact and images of every
+// pipeline. Artifacts shared between pipelines are verified once.
+func verifyPipelineArtifacts(pipelines []DelegatedPipeline, images map[string][]string, verify artifactVerifier) {
+	cache := map[string]ArtifactVerification{}
+	check := func(ref, typ string) ArtifactVerification {
+		if v, ok := cache[ref]; ok {
+			v.Type = typ
+			return v
+		}
+		v := ArtifactVerification{Ref: ref, Type: typ}
+		if err := verify(ref, false); err != nil {
+			v.Finding = "signature: " + err.Error()
+		} else {
+			v.Signed = true
+			if err := verify(ref, true); err != nil {
+				v.Finding = "provenance: " + err.Error()
+			} else {
+				v.Attested = true
+			}
+		}
+		cache[ref] = v
+		return v
+	}
+
+	for i := range pipelines {
+		p := &pipelines[i]
+		p.Artifacts = nil
+		p.Unverified = 0
+		if ref := configArtifactRef(*p); ref != "" {
+			p.Artifacts = append(p.Artifacts, check(ref, "config"))
+		}
+		for _, img := range images[p.Deployer] {
+			p.Artifacts = append(p.Artifacts, check(img, "image"))
+		}
+		for _, a := range p.Artifacts {
+			if !a.Verified() {
+				p.Unverified++
+			}
+		}
+	}
+}
+
+// printArtifactVerifications prints a pipeline's verification results,
+// listing only the unverified artifacts.
+func printArtifactVerifications(p DelegatedPipeline) {
+	if p.Artifacts == nil {
+		return
+	}
+	verified := len(p.Artifacts) - p.Unverified
+	if p.Unverified == 0 {
+		fmt.Printf("    Signed:    %s✓ %d/%d artifacts signed with provenance%s\n", colorGreen, verified, len(p.Artifacts), colorReset)
+		return
+	}
+	fmt.Printf("    Signed:    %s⚠ %d/%d artifacts signed with provenance%s\n", colorYellow, verified, len(p.Artifacts), colorReset)
+	for _, a := range p.Artifacts {
+		if !a.Verified() {
+			fmt.Printf("      %s %s %s(%s)%s\n", a.Type, a.Ref, colorDim, a.Finding, colorReset)
+		}
+	}
+}
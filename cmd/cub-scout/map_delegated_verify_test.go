@@ -0,0 +1,125 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestConfigArtifactRef(t *testing.T) {
+	url := "oci://oci.api.confighub.com/target/prod/us-west"
+	tests := []struct {
+		rev, want string
+	}{
+		{"latest@sha256:aaa", "oci.api.confighub.com/target/prod/us-west@sha256:aaa"},
+		{"sha256:bbb", "oci.api.confighub.com/target/prod/us-west@sha256:bbb"},
+		{"v1", "oci.api.confighub.com/target/prod/us-west:v1"},
+		{"", "oci.api.confighub.com/target/prod/us-west"},
+	}
+	for _, tt := range tests {
+		if got := configArtifactRef(DelegatedPipeline{OCIURL: url, RegistryRevision: tt.rev}); got != tt.want {
+			t.Errorf("configArtifactRef(%q) = %q, want %q", tt.rev, got, tt.want)
+		}
+	}
+}
+
+func TestWorkloadImages(t *testing.T) {
+	dep := unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Deployment",
+		"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+			"initContainers": []interface{}{map[string]interface{}{"image": "busybox:1.36"}},
+			"containers":     []interface{}{map[string]interface{}{"image": "api:1.2"}, map[string]interface{}{"image": "busybox:1.36"}},
+		}}},
+	}}
+	if got := workloadImages(&dep); !reflect.DeepEqual(got, []string{"busybox:1.36", "api:1.2"}) {
+		t.Errorf("deployment images = %v", got)
+	}
+
+	cron := unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "CronJob",
+		"spec": map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"image": "report:2"}},
+		}}}}},
+	}}
+	if got := workloadImages(&cron); !reflect.DeepEqual(got, []string{"report:2"}) {
+		t.Errorf("cronjob images = %v", got)
+	}
+}
+
+func TestVerifyPipelineArtifacts(t *testing.T) {
+	pipelines := []DelegatedPipeline{
+		{Deployer: "Kustomization/flux-system/prod-apps", OCIURL: "oci://oci.example.com/target/prod/a", RegistryRevision: "latest@sha256:aaa"},
+		{Deployer: "Kustomization/flux-system/qa-apps", OCIURL: "oci://oci.example.com/target/qa/a", RegistryRevision: "latest@sha256:bbb"},
+	}
+	images := map[string][]string{
+		"Kustomization/flux-system/prod-apps": {"api:1.2", "redis:7"},
+		"Kustomization/flux-system/qa-apps":   {"api:1.2"},
+	}
+
+	calls := map[string]int{}
+	verify := func(ref string, attestation bool) error {
+		calls[ref]++
+		switch {
+		case ref == "redis:7":
+			return errors.New("no signatures found")
+		case ref == "oci.example.com/target/qa/a@sha256:bbb" && attestation:
+			return errors.New("none of the attestations matched the predicate type")
+		}
+		return nil
+	}
+
+	verifyPipelineArtifacts(pipelines, images, verify)
+
+	prod := pipelines[0]
+	if len(prod.Artifacts) != 3 || prod.Unverified != 1 {
+		t.Fatalf("prod artifacts = %+v, unverified = %d", prod.Artifacts, prod.Unverified)
+	}
+	if a := prod.Artifacts[0]; a.Type != "config" || !a.Verified() {
+		t.Errorf("prod config = %+v", a)
+	}
+	if a := prod.Artifacts[2]; a.Ref != "redis:7" || a.Signed || a.Finding != "signature: no signatures found" {
+		t.Errorf("redis = %+v", a)
+	}
+
+	qa := pipelines[1]
+	if qa.Unverified != 1 || !qa.Artifacts[0].Signed || qa.Artifacts[0].Attested {
+		t.Errorf("qa = %+v", qa)
+	}
+	if calls["api:1.2"] != 2 {
+		t.Errorf("shared image verified %d times, want once (signature + attestation)", calls["api:1.2"])
+	}
+}
+
+func TestCosignError(t *testing.T) {
+	stderr := "Verifying...\nError: no matching signatures: none found\nmain.go:74: error during command execution\n"
+	if got := cosignError(stderr, errors.New("exit status 1")); got != "no matching signatures: none found" {
+		t.Errorf("cosignError = %q", got)
+	}
+	if got := cosignError("", errors.New("exit status 1")); got != "exit status 1" {
+		t.Errorf("cosignError without message = %q", got)
+	}
+}
+
+func TestCheckVerifyFlags(t *testing.T) {
+	tests := []struct {
+		key, identity, issuer string
+		ok                    bool
+	}{
+		{key: "cosign.pub", ok: true},
+		{identity: "https://github.com/acme/.*", issuer: "https://token.actions.githubusercontent.com", ok: true},
+		{identity: "https://github.com/acme/.*"},
+		{issuer: "https://token.actions.githubusercontent.com"},
+		{},
+	}
+	for _, tt := range tests {
+		err := checkVerifyFlags(tt.key, tt.identity, tt.issuer)
+		if (err == nil) != tt.ok {
+			t.Errorf("checkVerifyFlags(%q, %q, %q) = %v, want ok=%v", tt.key, tt.identity, tt.issuer, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/confighub/cub-scout/pkg/explain"
+)
+
+// mapListFindings maps entry conditions to finding topic IDs, in print order.
+var mapListFindings = []struct {
+	ID    string
+	Label string
+	Match func(MapEntry) bool
+}{
+	{"failed", "Failed", func(e MapEntry) bool { return e.Status == "Failed" }},
+	{"not-ready", "NotReady", func(e MapEntry) bool { return e.Status == "NotReady" }},
+	{"pending", "Pending", func(e MapEntry) bool { return e.Status == "Pending" }},
+	{"orphan", "Native (orphans)", func(e MapEntry) bool { return e.Owner == "Native" && e.OwnerDetails["source"] == "" }},
+	{"delegated", "delegated ConfigHub applies", func(e MapEntry) bool { return e.OwnerDetails["delegatedVia"] != "" }},
+}
+
+// printExplainIntro prints a concept topic as a section header.
+func printExplainIntro(w io.Writer, lib *explain.Library, concept string) {
+	t, ok := lib.Concepts[concept]
+	if !ok {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "GITOPS OWNERSHIP EXPLAINED")
+	fmt.Fprintln(w, "════════════════════════════════════════════════════════════════════")
+	fmt.Fprintln(w, t.Summary+".")
+	if t.Detail != "" {
+		fmt.Fprintln(w)
+		fmt.Fprint(w, t.Detail)
+	}
+	fmt.Fprintln(w, "════════════════════════════════════════════════════════════════════")
+	fmt.Fprintln(w)
+}
+
+// printMapListExplain explains the owners and findings in a map list result,
+// citing a shown resource as the example for each.
+func printMapListExplain(w io.Writer, lib *explain.Library, level explain.Level, entries []MapEntry, byOwner map[string]int) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "WHAT THIS MEANS:")
+	for _, owner := range lib.OwnerIDs() {
+		count := byOwner[owner]
+		if count == 0 {
+			continue
+		}
+		t := lib.Owners[owner]
+		verb := "are managed by " + owner
+		if owner == "Native" {
+			verb = "are Native"
+		}
+		fmt.Fprintf(w, "• %d resources %s → %s\n", count, verb, t.Summary)
+
+		example, found := firstEntry(entries, func(e MapEntry) bool { return e.Owner == owner })
+		if found {
+			fmt.Fprintf(w, "  %se.g. %s is %s%s\n", colorDim, entryRef(example), ownerReason(example), colorReset)
+		}
+		if level >= explain.Verbose {
+			printTopicDetail(w, t, example, found)
+		}
+	}
+
+	var findings []string
+	for _, f := range mapListFindings {
+		count := 0
+		var example MapEntry
+		for _, e := range entries {
+			if f.Match(e) {
+				if count == 0 {
+					example = e
+				}
+				count++
+			}
+		}
+		t, ok := lib.Findings[f.ID]
+		if count == 0 || !ok {
+			continue
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "• %d %s → %s\n", count, f.Label, t.Summary)
+		fmt.Fprintf(&b, "  %se.g. %s%s\n", colorDim, entryRef(example), colorReset)
+		if level >= explain.Verbose {
+			printTopicDetail(&b, t, example, true)
+		}
+		findings = append(findings, b.String())
+	}
+	if len(findings) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "FINDINGS:")
+		for _, f := range findings {
+			fmt.Fprint(w, f)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "NEXT STEPS:")
+	fmt.Fprintln(w, "→ See the Git→Deployment chain: cub-scout trace <kind>/<name> -n <namespace>")
+	fmt.Fprintln(w, "→ See the full GitOps pipeline:  cub-scout map deployers")
+	fmt.Fprintln(w, "→ Visual guide:                  docs/diagrams/ownership-detection.svg")
+	if level < explain.Verbose {
+		fmt.Fprintln(w, "→ More detail:                   --explain=verbose")
+	}
+}
+
+// printTopicDetail prints a topic's detail paragraph, the evidence for the
+// example resource, and next steps filled in from it.
+func printTopicDetail(w io.Writer, t explain.Topic, example MapEntry, haveExample bool) {
+	for _, line := range strings.Split(strings.TrimSpace(t.Detail), "\n") {
+		fmt.Fprintf(w, "    %s\n", line)
+	}
+	p := explain.Placeholders{}
+	if haveExample {
+		if why := t.Why(example.OwnerDetails["source"]); why != "" {
+			fmt.Fprintf(w, "    Why: %s.\n", why)
+		}
+		p = explain.Placeholders{Kind: example.Kind, Name: example.Name, Namespace: example.Namespace, OwnerName: example.OwnerDetails["name"]}
+	}
+	for _, step := range t.NextSteps(p) {
+		fmt.Fprintf(w, "    → %s\n", step)
+	}
+	if t.Diagram != "" {
+		fmt.Fprintf(w, "    → Visual guide: %s\n", t.Diagram)
+	}
+}
+
+// ownerReason says why an entry has its owner, e.g. "Flux-managed because
+// of label kustomize.toolkit.fluxcd.io/name=apps".
+func ownerReason(e MapEntry) string {
+	if via := e.OwnerDetails["delegatedVia"]; via != "" {
+		return fmt.Sprintf("ConfigHub-managed: %s applies it via delegated apply", e.OwnerDetails["deployer"])
+	}
+	return explain.Because(e.Owner, e.OwnerDetails["source"], e.Labels)
+}
+
+func entryRef(e MapEntry) string {
+	if e.Namespace == "" {
+		return e.Kind + " " + e.Name
+	}
+	return e.Kind + " " + e.Namespace + "/" + e.Name
+}
+
+func firstEntry(entries []MapEntry, match func(MapEntry) bool) (MapEntry, bool) {
+	for _, e := range entries {
+		if match(e) {
+			return e, true
+		}
+	}
+	return MapEntry{}, false
+}
@@ -0,0 +1,57 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/explain"
+)
+
+func TestPrintMapListExplain(t *testing.T) {
+	lib, err := explain.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := []MapEntry{
+		{Namespace: "prod", Kind: "Deployment", Name: "api", Owner: "Flux", Status: "NotReady",
+			Labels:       map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps"},
+			OwnerDetails: map[string]string{"name": "apps", "source": "label:kustomize.toolkit.fluxcd.io/name"}},
+		{Namespace: "default", Kind: "ConfigMap", Name: "debug", Owner: "Native", Status: "Ready"},
+	}
+	byOwner := map[string]int{"Flux": 1, "Native": 1}
+
+	var brief bytes.Buffer
+	printMapListExplain(&brief, lib, explain.Brief, entries, byOwner)
+	out := brief.String()
+	for _, want := range []string{
+		"1 resources are managed by Flux",
+		"Deployment prod/api is Flux-managed because of label kustomize.toolkit.fluxcd.io/name=apps",
+		"1 NotReady",
+		"1 Native (orphans)",
+		"--explain=verbose",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("brief output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Why:") {
+		t.Errorf("brief output should not include evidence detail:\n%s", out)
+	}
+
+	var verbose bytes.Buffer
+	printMapListExplain(&verbose, lib, explain.Verbose, entries, byOwner)
+	out = verbose.String()
+	for _, want := range []string{
+		"Why: Flux's kustomize-controller labels everything it applies",
+		"→ cub-scout trace deployment/api -n prod",
+		"→ cub-scout debug deployment/api -n prod",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("verbose output missing %q:\n%s", want, out)
+		}
+	}
+}
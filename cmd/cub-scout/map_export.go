@@ -0,0 +1,400 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	exportToConfigHub bool
+	exportSpace       string
+	exportUnit        string
+	exportInterval    time.Duration
+	exportDryRun      bool
+	exportSinks       string
+	exportSLOFile     string
+)
+
+var mapExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export ownership inventory to ConfigHub",
+	Long: `Export cub-scout's ownership classification of this cluster to ConfigHub.
+
+With --to-confighub, the inventory is written as a "cluster inventory" unit
+(a ConfigMap named cub-scout-inventory) in the given space. The unit is
+created on first export and updated afterwards, so ConfigHub revision history
+shows how ownership changed over time. This lets the ConfigHub GUI show
+ownership for clusters that do not run a ConfigHub worker.
+
+The unit carries the labels inventory=cluster and cluster=<name> so
+inventories can be found with:
+  cub unit list --space <space> --where "Labels.inventory = 'cluster'"
+
+With --interval, export repeats until interrupted (agent mode).
+
+With --sinks, each run also pushes inventory, drift and stuck-reconciliation
+scan reports to the sinks configured in a YAML file: stdout (JSON lines),
+webhook (HTTP POST), s3/gcs (timestamped snapshot archives, uploaded with the
+aws/gcloud CLI) and confighub (one unit per report kind):
+
+  sinks:
+    - type: stdout
+    - type: webhook
+      url: https://hooks.example.com/cub-scout
+      headers:
+        Authorization: Bearer ${WEBHOOK_TOKEN}
+    - type: s3
+      bucket: cluster-snapshots
+      prefix: cub-scout
+    - type: confighub
+      space: platform-inventory
+      reports: [drift, scan]
+
+With --slo, each run also evaluates revision lag SLOs (see 'drift slo'),
+logs violations and pushes an "slo" report to the sinks.
+
+Examples:
+  cub-scout map export --to-confighub --space platform-inventory
+  cub-scout map export --to-confighub --space platform-inventory --interval 15m
+  cub-scout map export --to-confighub --space platform-inventory --dry-run
+  cub-scout map export --sinks /etc/cub-scout/sinks.yaml --interval 5m
+  cub-scout map export --sinks sinks.yaml --slo slo.yaml --interval 1m`,
+	RunE: runMapExport,
+}
+
+func init() {
+	mapCmd.AddCommand(mapExportCmd)
+	mapExportCmd.Flags().BoolVar(&exportToConfigHub, "to-confighub", false, "Write the inventory to a ConfigHub unit (requires cub auth)")
+	mapExportCmd.Flags().StringVar(&exportSpace, "space", "", "ConfigHub space for the inventory unit")
+	mapExportCmd.Flags().StringVar(&exportUnit, "unit", "", "Unit slug (default: cluster-inventory-<cluster>)")
+	_ = mapExportCmd.RegisterFlagCompletionFunc("space", completeSpaces)
+	_ = mapExportCmd.RegisterFlagCompletionFunc("unit", completeUnits)
+	mapExportCmd.Flags().DurationVar(&exportInterval, "interval", 0, "Repeat export at this interval (e.g. 15m); 0 exports once")
+	mapExportCmd.Flags().BoolVar(&exportDryRun, "dry-run", false, "Print the inventory unit instead of writing it")
+	mapExportCmd.Flags().StringVar(&exportSinks, "sinks", "", "YAML file of sinks to push inventory, drift and scan reports to")
+	mapExportCmd.Flags().StringVar(&exportSLOFile, "slo", "", "YAML file of revision lag SLOs to evaluate on every run")
+	mapExportCmd.Flags().StringVar(&mapNamespace, "namespace", "", "Limit the inventory to one namespace")
+	_ = mapExportCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+}
+
+// InventoryItem is one resource in an exported cluster inventory.
+type InventoryItem struct {
+	Namespace string `json:"namespace,omitempty"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Owner     string `json:"owner"`
+	Status    string `json:"status,omitempty"`
+}
+
+// InventorySummary holds the aggregate counts stored alongside the inventory.
+// GeneratedAt is left out of the ConfigHub unit, see buildInventoryManifest.
+type InventorySummary struct {
+	Cluster     string         `json:"cluster"`
+	GeneratedAt time.Time      `json:"generatedAt,omitzero"`
+	Total       int            `json:"total"`
+	ByOwner     map[string]int `json:"byOwner"`
+	NativePct   float64        `json:"nativePercent"`
+}
+
+func runMapExport(cmd *cobra.Command, args []string) error {
+	if !exportToConfigHub && !exportDryRun && exportSinks == "" {
+		return fmt.Errorf("no export destination: use --to-confighub or --sinks (or --dry-run to preview)")
+	}
+	if exportToConfigHub && exportSpace == "" && !exportDryRun {
+		return fmt.Errorf("--space is required with --to-confighub")
+	}
+
+	clusterName := currentClusterName()
+	unit := exportUnit
+	if unit == "" {
+		unit = sanitizeSlug("cluster-inventory-" + clusterName)
+	}
+
+	var sinks []reportSink
+	if exportSinks != "" {
+		var err error
+		if sinks, err = loadSinks(exportSinks); err != nil {
+			return err
+		}
+	}
+
+	var slos []LagSLO
+	if exportSLOFile != "" {
+		var err error
+		if slos, err = loadLagSLOs(exportSLOFile); err != nil {
+			return err
+		}
+	}
+
+	if exportInterval <= 0 {
+		return exportInventoryOnce(clusterName, unit, sinks, slos)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+	for {
+		if err := exportInventoryOnce(clusterName, unit, sinks, slos); err != nil {
+			// Keep running in agent mode; the next tick may succeed.
+			logger.Error("export failed", "cluster", clusterName, "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func exportInventoryOnce(clusterName, unit string, sinks []reportSink, slos []LagSLO) error {
+	ctx := context.Background()
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+	loadDelegations(ctx, dynClient)
+
+	entries := collectInventoryEntries(ctx, dynClient, clusterName)
+	now := time.Now().UTC()
+
+	var sloResults []LagSLOResult
+	if len(slos) > 0 {
+		if sloResults, err = evaluateLagSLOs(slos, cubLagSource(), now); err != nil {
+			logger.Error("SLO evaluation failed", "cluster", clusterName, "err", err)
+		} else if violations := sloViolationSummary(sloResults); len(violations) > 0 {
+			logger.Warn("revision lag SLO violations", "cluster", clusterName, "count", len(violations), "units", strings.Join(violations, ","))
+		}
+	}
+
+	if len(sinks) > 0 && !exportDryRun {
+		reports := collectAgentReports(ctx, cfg, dynClient, clusterName, entries, now)
+		if sloResults != nil {
+			reports = append(reports, AgentReport{Kind: reportSLO, Cluster: clusterName, GeneratedAt: now, Payload: sloResults})
+		}
+		if err := publishReports(ctx, sinks, reports); err != nil {
+			// One unreachable sink should not stop the others or the ConfigHub export
+			logger.Error("sink delivery failed", "cluster", clusterName, "err", err)
+		} else {
+			logger.Info("reports published", "cluster", clusterName, "sinks", len(sinks), "resources", len(entries))
+		}
+		if !exportToConfigHub {
+			return nil
+		}
+	}
+
+	manifest, err := buildInventoryManifest(clusterName, entries)
+	if err != nil {
+		return err
+	}
+
+	if exportDryRun {
+		fmt.Print(manifest)
+		return nil
+	}
+
+	action, err := upsertInventoryUnit(exportSpace, unit, clusterName, manifest)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ %s %s/%s (%d resources) at %s\n", action, exportSpace, unit, len(entries), time.Now().Format(time.RFC3339))
+	logger.Info("inventory exported", "space", exportSpace, "unit", unit, "action", action, "resources", len(entries))
+	return nil
+}
+
+// collectAgentReports builds the inventory, drift and scan reports pushed to
+// sinks. A failed state scan is reported inside the scan payload.
+func collectAgentReports(ctx context.Context, cfg *rest.Config, dynClient dynamic.Interface, clusterName string, entries []MapEntry, at time.Time) []AgentReport {
+	reports := []AgentReport{
+		inventoryReport(clusterName, entries, at),
+		{Kind: reportDrift, Cluster: clusterName, GeneratedAt: at, Payload: collectDrift(ctx, dynClient)},
+	}
+
+	scan := &agent.StateScanResult{ScannedAt: at}
+	if scanner, err := agent.NewStateScanner(cfg); err != nil {
+		scan.Warnings = append(scan.Warnings, err.Error())
+	} else if result, err := scanner.ScanWithThreshold(ctx, 5*time.Minute); err != nil {
+		scan.Warnings = append(scan.Warnings, err.Error())
+	} else {
+		scan = result
+	}
+	return append(reports, AgentReport{Kind: reportScan, Cluster: clusterName, GeneratedAt: at, Payload: scan})
+}
+
+// inventoryReport is the inventory pushed to sinks.
+func inventoryReport(clusterName string, entries []MapEntry, at time.Time) AgentReport {
+	items, summary := buildInventory(clusterName, entries, at)
+	return AgentReport{Kind: reportInventory, Cluster: clusterName, GeneratedAt: at, Payload: map[string]interface{}{"summary": summary, "items": items}}
+}
+
+// collectInventoryEntries lists the resources shown by "map list" for the inventory.
+func collectInventoryEntries(ctx context.Context, dynClient dynamic.Interface, clusterName string) []MapEntry {
+	resources := []schema.GroupVersionResource{
+		{Group: "apps", Version: "v1", Resource: "deployments"},
+		{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		{Group: "apps", Version: "v1", Resource: "daemonsets"},
+		{Group: "", Version: "v1", Resource: "services"},
+		{Group: "", Version: "v1", Resource: "configmaps"},
+		{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+		{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories"},
+		{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"},
+		{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"},
+		{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+		{Group: "batch", Version: "v1", Resource: "cronjobs"},
+		{Group: "batch", Version: "v1", Resource: "jobs"},
+	}
+
+	var lists []listedObjects
+	for _, gvr := range resources {
+		l, err := dynClient.Resource(gvr).Namespace(mapNamespace).List(ctx, v1.ListOptions{})
+		if err != nil {
+			continue // CRD not installed or no access
+		}
+		lists = append(lists, listedObjects{gvr, l.Items})
+	}
+
+	entries := []MapEntry{}
+	byOwner := map[string]int{}
+	indexOwners(lists)
+	for _, l := range lists {
+		for i := range l.items {
+			entries = processResource(&l.items[i], l.gvr, clusterName, entries, byOwner)
+		}
+	}
+	return entries
+}
+
+// buildInventory converts map entries to sorted inventory items and their
+// summary. Secrets are never included.
+func buildInventory(clusterName string, entries []MapEntry, at time.Time) ([]InventoryItem, InventorySummary) {
+	items := make([]InventoryItem, 0, len(entries))
+	summary := InventorySummary{
+		Cluster:     clusterName,
+		GeneratedAt: at,
+		ByOwner:     map[string]int{},
+	}
+	for _, e := range entries {
+		if e.Kind == "Secret" {
+			continue
+		}
+		items = append(items, InventoryItem{
+			Namespace: e.Namespace,
+			Kind:      e.Kind,
+			Name:      e.Name,
+			Owner:     e.Owner,
+			Status:    e.Status,
+		})
+		summary.ByOwner[e.Owner]++
+	}
+	summary.Total = len(items)
+	if summary.Total > 0 {
+		summary.NativePct = float64(summary.ByOwner["Native"]) * 100 / float64(summary.Total)
+	}
+
+	sortResources(items, func(e InventoryItem) (string, string, string) { return e.Namespace, e.Kind, e.Name })
+	return items, summary
+}
+
+// buildInventoryManifest renders the inventory as a ConfigMap so it can be
+// stored as a regular ConfigHub unit. It carries no timestamp, so exporting
+// an unchanged cluster again renders the same unit and every ConfigHub
+// revision is an ownership change; the revision records when it was made.
+func buildInventoryManifest(clusterName string, entries []MapEntry) (string, error) {
+	items, summary := buildInventory(clusterName, entries, time.Time{})
+
+	summaryJSON, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode summary: %w", err)
+	}
+	itemsJSON, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode inventory: %w", err)
+	}
+
+	cm := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "cub-scout-inventory",
+			"namespace": "cub-scout",
+			"labels": map[string]string{
+				"app.kubernetes.io/managed-by": "cub-scout",
+				"cub-scout/cluster":            clusterName,
+			},
+		},
+		"data": map[string]string{
+			"summary.json":   string(summaryJSON),
+			"inventory.json": string(itemsJSON),
+		},
+	}
+	out, err := yaml.Marshal(cm)
+	if err != nil {
+		return "", fmt.Errorf("encode inventory manifest: %w", err)
+	}
+	return string(out), nil
+}
+
+// upsertInventoryUnit creates the inventory unit or, if it already exists,
+// updates it with the new manifest. It returns "Created" or "Updated".
+func upsertInventoryUnit(space, unit, clusterName, manifest string) (string, error) {
+	labels := []string{"inventory=cluster", "cluster=" + sanitizeSlug(clusterName)}
+	return upsertReportUnit(space, unit, labels, manifest, "cub-scout inventory export")
+}
+
+// upsertReportUnit creates unit with the given labels or, if it already
+// exists, updates it with manifest. It returns "Created" or "Updated".
+func upsertReportUnit(space, unit string, labels []string, manifest, changeDesc string) (string, error) {
+	args := []string{"unit", "create", "--space", space}
+	for _, label := range labels {
+		args = append(args, "--label", label)
+	}
+	args = append(args, unit, "-")
+
+	done := traceCub(args)
+	create := exec.Command("cub", args...)
+	create.Stdin = strings.NewReader(manifest)
+	output, err := create.CombinedOutput()
+	done(err)
+	if err == nil {
+		return "Created", nil
+	}
+	if !strings.Contains(string(output), "already exists") {
+		return "", fmt.Errorf("create unit %s: %s", unit, strings.TrimSpace(string(output)))
+	}
+
+	updateArgs := []string{"unit", "update", unit, "-", "--space", space, "--change-desc", changeDesc}
+	done = traceCub(updateArgs)
+	update := exec.Command("cub", updateArgs...)
+	update.Stdin = strings.NewReader(manifest)
+	output, err = update.CombinedOutput()
+	done(err)
+	if err != nil {
+		return "", fmt.Errorf("update unit %s: %s", unit, strings.TrimSpace(string(output)))
+	}
+	return "Updated", nil
+}
@@ -0,0 +1,70 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestBuildInventoryManifest(t *testing.T) {
+	entries := []MapEntry{
+		{Namespace: "prod", Kind: "Deployment", Name: "web", Owner: "Flux", Status: "Ready"},
+		{Namespace: "prod", Kind: "Deployment", Name: "api", Owner: "Native", Status: "Ready"},
+		{Namespace: "prod", Kind: "Secret", Name: "db-password", Owner: "Native"},
+		{Namespace: "dev", Kind: "Service", Name: "web", Owner: "ArgoCD"},
+	}
+
+	manifest, err := buildInventoryManifest("prod-east", entries)
+	if err != nil {
+		t.Fatalf("buildInventoryManifest: %v", err)
+	}
+
+	var cm struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Data map[string]string `json:"data"`
+	}
+	if err := yaml.Unmarshal([]byte(manifest), &cm); err != nil {
+		t.Fatalf("manifest is not valid YAML: %v", err)
+	}
+	if cm.Kind != "ConfigMap" || cm.Metadata.Name != "cub-scout-inventory" {
+		t.Errorf("unexpected object %s/%s", cm.Kind, cm.Metadata.Name)
+	}
+	if cm.Metadata.Labels["cub-scout/cluster"] != "prod-east" {
+		t.Errorf("cluster label = %q", cm.Metadata.Labels["cub-scout/cluster"])
+	}
+
+	var summary InventorySummary
+	if err := json.Unmarshal([]byte(cm.Data["summary.json"]), &summary); err != nil {
+		t.Fatalf("summary.json: %v", err)
+	}
+	if summary.Total != 3 || summary.ByOwner["Native"] != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if strings.Contains(cm.Data["summary.json"], "generatedAt") {
+		t.Errorf("summary.json has a timestamp, so every export would be a new revision:\n%s", cm.Data["summary.json"])
+	}
+	again, _ := buildInventoryManifest("prod-east", entries)
+	if again != manifest {
+		t.Error("exporting the same entries again rendered a different manifest")
+	}
+
+	var items []InventoryItem
+	if err := json.Unmarshal([]byte(cm.Data["inventory.json"]), &items); err != nil {
+		t.Fatalf("inventory.json: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items (secrets excluded), got %d", len(items))
+	}
+	if items[0].Namespace != "dev" || items[1].Name != "api" || items[2].Name != "web" {
+		t.Errorf("items not sorted: %+v", items)
+	}
+}
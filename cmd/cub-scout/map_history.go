@@ -0,0 +1,208 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// sprawlShowHistory charts the recorded coverage samples (--history)
+	sprawlShowHistory bool
+	// sprawlHistoryDir overrides the history store directory (--history-dir)
+	sprawlHistoryDir string
+)
+
+func init() {
+	mapSprawlCmd.Flags().BoolVar(&sprawlShowHistory, "history", false, "Chart GitOps coverage and ConfigHub adoption recorded by earlier runs")
+	mapSprawlCmd.Flags().StringVar(&sprawlHistoryDir, "history-dir", "", "History store directory (default: $CUB_SCOUT_HISTORY or ~/.cub-scout/history)")
+}
+
+// sprawlHistoryLimit is the most samples kept per cluster, a year of daily
+// samples.
+const sprawlHistoryLimit = 366
+
+// sprawlChartWidth is the most points in a history sparkline; longer
+// histories are sampled evenly.
+const sprawlChartWidth = 48
+
+// SprawlSample is the workload ownership of a cluster at one map sprawl run.
+// Acknowledged orphans are left out, as they are from the coverage score.
+type SprawlSample struct {
+	At        time.Time `json:"at"`
+	Workloads int       `json:"workloads"`
+	Managed   int       `json:"managed"`
+	ConfigHub int       `json:"confighub"`
+}
+
+// Coverage is the GitOps-managed share of the workloads, in percent.
+func (s SprawlSample) Coverage() int {
+	if s.Workloads == 0 {
+		return 0
+	}
+	return s.Managed * 100 / s.Workloads
+}
+
+// Adoption is the ConfigHub-managed share of the workloads, in percent.
+func (s SprawlSample) Adoption() int {
+	if s.Workloads == 0 {
+		return 0
+	}
+	return s.ConfigHub * 100 / s.Workloads
+}
+
+// HistoryStore is a directory holding each cluster's map sprawl samples as
+// <cluster>-sprawl.json, oldest first.
+type HistoryStore struct {
+	Dir string
+}
+
+// openHistoryStore returns the history store at dir, falling back to
+// $CUB_SCOUT_HISTORY and then ~/.cub-scout/history.
+func openHistoryStore(dir string) HistoryStore {
+	if dir == "" {
+		dir = os.Getenv("CUB_SCOUT_HISTORY")
+	}
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".cub-scout", "history")
+	}
+	return HistoryStore{Dir: dir}
+}
+
+func (s HistoryStore) sprawlFile(cluster string) string {
+	if cluster == "" {
+		cluster = "default"
+	}
+	return filepath.Join(s.Dir, unsafeFileChars.ReplaceAllString(cluster, "_")+"-sprawl.json")
+}
+
+// LoadSprawl reads the cluster's samples, oldest first. A cluster without
+// history has none.
+func (s HistoryStore) LoadSprawl(cluster string) ([]SprawlSample, error) {
+	path := s.sprawlFile(cluster)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var samples []SprawlSample
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return samples, nil
+}
+
+// RecordSprawl adds sample to the cluster's history and returns the
+// updated history.
+func (s HistoryStore) RecordSprawl(cluster string, sample SprawlSample) ([]SprawlSample, error) {
+	samples, err := s.LoadSprawl(cluster)
+	if err != nil {
+		return nil, err
+	}
+	samples = appendSprawlSample(samples, sample)
+
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create history store: %w", err)
+	}
+	data, err := json.MarshalIndent(samples, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode history: %w", err)
+	}
+	path := s.sprawlFile(cluster)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return nil, fmt.Errorf("write history: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("write history: %w", err)
+	}
+	return samples, nil
+}
+
+// appendSprawlSample keeps one sample per day: a sample from the same UTC
+// day as the last one replaces it. Only the newest sprawlHistoryLimit
+// samples are kept.
+func appendSprawlSample(samples []SprawlSample, sample SprawlSample) []SprawlSample {
+	if n := len(samples); n > 0 && sameDay(samples[n-1].At, sample.At) {
+		samples[n-1] = sample
+	} else {
+		samples = append(samples, sample)
+	}
+	if len(samples) > sprawlHistoryLimit {
+		samples = samples[len(samples)-sprawlHistoryLimit:]
+	}
+	return samples
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.UTC().Format("2006-01-02") == b.UTC().Format("2006-01-02")
+}
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws percentages on a fixed 0-100 scale, so lines for
+// different measures compare directly.
+func sparkline(values []int) string {
+	var b strings.Builder
+	for _, v := range values {
+		if v < 0 {
+			v = 0
+		}
+		if v > 100 {
+			v = 100
+		}
+		b.WriteRune(sparkLevels[v*(len(sparkLevels)-1)/100])
+	}
+	return b.String()
+}
+
+// chartPoints returns at most width samples, evenly spaced and always
+// including the first and last.
+func chartPoints(samples []SprawlSample, width int) []SprawlSample {
+	if len(samples) <= width || width < 2 {
+		return samples
+	}
+	points := make([]SprawlSample, width)
+	for i := range points {
+		points[i] = samples[i*(len(samples)-1)/(width-1)]
+	}
+	return points
+}
+
+// writeSprawlHistory charts GitOps coverage and ConfigHub adoption over the
+// recorded samples, with the change from the first to the last.
+func writeSprawlHistory(w io.Writer, store HistoryStore, cluster string, samples []SprawlSample) {
+	fmt.Fprintln(w)
+	if len(samples) < 2 {
+		fmt.Fprintf(w, "HISTORY: %d sample(s) for %s; each map sprawl run records one a day\n", len(samples), cluster)
+		fmt.Fprintf(w, "  %s(%s)%s\n", colorDim, store.sprawlFile(cluster), colorReset)
+		return
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	fmt.Fprintf(w, "HISTORY (%s, %d samples, %s → %s):\n", cluster, len(samples),
+		first.At.Format("2006-01-02"), last.At.Format("2006-01-02"))
+
+	points := chartPoints(samples, sprawlChartWidth)
+	coverage := make([]int, len(points))
+	adoption := make([]int, len(points))
+	for i, p := range points {
+		coverage[i] = p.Coverage()
+		adoption[i] = p.Adoption()
+	}
+	fmt.Fprintf(w, "  GitOps coverage  %s  %3d%% → %3d%%  (%+d)\n",
+		sparkline(coverage), first.Coverage(), last.Coverage(), last.Coverage()-first.Coverage())
+	fmt.Fprintf(w, "  ConfigHub        %s  %3d%% → %3d%%  (%+d)\n",
+		sparkline(adoption), first.Adoption(), last.Adoption(), last.Adoption()-first.Adoption())
+	fmt.Fprintf(w, "  Workloads        %d → %d\n", first.Workloads, last.Workloads)
+}
@@ -0,0 +1,128 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func TestRecordSprawlHistory(t *testing.T) {
+	store := HistoryStore{Dir: t.TempDir()}
+	day := time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC)
+
+	if _, err := store.RecordSprawl("prod-east", SprawlSample{At: day, Workloads: 10, Managed: 5}); err != nil {
+		t.Fatal(err)
+	}
+	// A later run the same day replaces the morning's sample
+	if _, err := store.RecordSprawl("prod-east", SprawlSample{At: day.Add(8 * time.Hour), Workloads: 10, Managed: 6}); err != nil {
+		t.Fatal(err)
+	}
+	samples, err := store.RecordSprawl("prod-east", SprawlSample{At: day.AddDate(0, 0, 1), Workloads: 10, Managed: 7, ConfigHub: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || samples[0].Managed != 6 || samples[1].Managed != 7 {
+		t.Errorf("samples = %+v, want one per day", samples)
+	}
+
+	loaded, err := store.LoadSprawl("prod-east")
+	if err != nil || len(loaded) != 2 {
+		t.Errorf("LoadSprawl = %+v, %v", loaded, err)
+	}
+	if other, _ := store.LoadSprawl("prod-west"); len(other) != 0 {
+		t.Errorf("prod-west has %d samples, want none", len(other))
+	}
+}
+
+func TestMapSprawlRecordsOnlyListedCoverage(t *testing.T) {
+	store := HistoryStore{Dir: t.TempDir()}
+	day := time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC)
+	if _, err := store.RecordSprawl("prod-east", SprawlSample{At: day, Workloads: 10, Managed: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed list must not record a zero-workload sample
+	client := agenttest.FakeClient()
+	client.PrependReactor("list", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "", nil)
+	})
+	if err := mapSprawl(context.Background(), client, store, "prod-east"); err == nil {
+		t.Fatal("expected list error")
+	}
+	samples, err := store.LoadSprawl("prod-east")
+	if err != nil || len(samples) != 1 || samples[0].Workloads != 10 {
+		t.Errorf("after failed list: samples = %+v, %v", samples, err)
+	}
+
+	if err := mapSprawl(context.Background(), agenttest.FakeClient(agenttest.Deployment("prod", "api")), store, "prod-east"); err != nil {
+		t.Fatal(err)
+	}
+	samples, err = store.LoadSprawl("prod-east")
+	if err != nil || len(samples) != 2 || samples[1].Workloads != 1 {
+		t.Errorf("after successful list: samples = %+v, %v", samples, err)
+	}
+}
+
+func TestAppendSprawlSampleLimit(t *testing.T) {
+	var samples []SprawlSample
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < sprawlHistoryLimit+10; i++ {
+		samples = appendSprawlSample(samples, SprawlSample{At: day.AddDate(0, 0, i)})
+	}
+	if len(samples) != sprawlHistoryLimit || !samples[0].At.Equal(day.AddDate(0, 0, 10)) {
+		t.Errorf("kept %d samples from %s, want the newest %d", len(samples), samples[0].At, sprawlHistoryLimit)
+	}
+}
+
+func TestWriteSprawlHistory(t *testing.T) {
+	store := HistoryStore{Dir: t.TempDir()}
+	day := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	samples := []SprawlSample{
+		{At: day, Workloads: 20, Managed: 10},
+		{At: day.AddDate(0, 0, 7), Workloads: 20, Managed: 15, ConfigHub: 4},
+		{At: day.AddDate(0, 0, 14), Workloads: 22, Managed: 22, ConfigHub: 11},
+	}
+
+	var buf bytes.Buffer
+	writeSprawlHistory(&buf, store, "prod-east", samples)
+	out := buf.String()
+	for _, want := range []string{
+		"HISTORY (prod-east, 3 samples, 2026-09-01 → 2026-09-15):",
+		"GitOps coverage  ▄▆█   50% → 100%  (+50)",
+		"ConfigHub        ▁▂▄    0% →  50%  (+50)",
+		"Workloads        20 → 22",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	writeSprawlHistory(&buf, store, "prod-east", samples[:1])
+	if !strings.Contains(buf.String(), "1 sample(s) for prod-east") {
+		t.Errorf("single sample output:\n%s", buf.String())
+	}
+}
+
+func TestChartPoints(t *testing.T) {
+	samples := make([]SprawlSample, 100)
+	for i := range samples {
+		samples[i].Workloads = i
+	}
+	points := chartPoints(samples, 10)
+	if len(points) != 10 || points[0].Workloads != 0 || points[9].Workloads != 99 {
+		t.Errorf("points = %+v, want 10 spanning the first and last", points)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+	"github.com/confighub/cub-scout/pkg/query"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestScanMapList(t *testing.T) {
+	client := agenttest.FakeClient(agenttest.Cluster(60)...)
+	entries, byOwner, err := scanMapList(context.Background(), client, mapListScope{}, "test", &progressBar{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 60 {
+		t.Fatalf("scanned %d entries, want 60", len(entries))
+	}
+	for _, owner := range []string{"Flux", "ArgoCD", "Helm", "ConfigHub", "Terraform", "Native"} {
+		if byOwner[owner] != 10 {
+			t.Errorf("byOwner[%s] = %d, want 10 (%v)", owner, byOwner[owner], byOwner)
+		}
+	}
+
+	entries, _, _ = scanMapList(context.Background(), client, mapListScope{Namespace: "team-01"}, "test", &progressBar{})
+	for _, e := range entries {
+		if e.Namespace != "team-01" {
+			t.Errorf("namespace scan returned %s/%s", e.Namespace, e.Name)
+		}
+	}
+}
+
+func TestScanMapListSelectors(t *testing.T) {
+	ctx := context.Background()
+	client := agenttest.FakeClient(agenttest.Cluster(60)...)
+
+	// Labels are filtered by the API server, and -q still applies on top
+	entries, _, err := scanMapList(ctx, client, mapListScope{LabelSelector: "app.kubernetes.io/managed-by=Helm"}, "test", &progressBar{})
+	if err != nil || len(entries) != 10 {
+		t.Fatalf("managed-by=Helm matched %d entries (%v), want 10", len(entries), err)
+	}
+	q, _ := query.Parse("kind=ConfigMap AND namespace=team-02")
+	if n := countMatches(q, entries); n != 1 {
+		t.Errorf("-q on selected entries matched %d, want 1", n)
+	}
+
+	// Types without the field are skipped quietly; if none has it, say so
+	client.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.ListAction).GetListRestrictions().Fields.String() == "" {
+			return false, nil, nil
+		}
+		if action.GetResource().Resource == "configmaps" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewBadRequest(`field label not supported: status.phase`)
+	})
+	rec := &listRecorder{}
+	recording := newRecordingClient(client, rec)
+	entries, _, err = scanMapList(ctx, recording, mapListScope{FieldSelector: "status.phase=Running"}, "test", &progressBar{})
+	if err != nil || len(entries) != 20 {
+		t.Errorf("field selector kept %d entries (%v), want the 20 ConfigMaps", len(entries), err)
+	}
+	if failures := rec.Failures(); len(failures) != 0 {
+		t.Errorf("rejected field selectors should not be partial results: %+v", failures)
+	}
+
+	client.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewBadRequest(`field label not supported: status.phase`)
+	})
+	if _, _, err := scanMapList(ctx, recording, mapListScope{FieldSelector: "status.phase=Running"}, "test", &progressBar{}); err == nil || !strings.Contains(err.Error(), "not supported by any resource type") {
+		t.Errorf("expected an error when no type supports the field, got %v", err)
+	}
+	if err := (mapListScope{LabelSelector: "app in (a"}).validate(); err == nil {
+		t.Error("validate should reject a malformed label selector")
+	}
+}
+
+func countMatches(q *query.Query, entries []MapEntry) int {
+	n := 0
+	for _, e := range entries {
+		if q.Matches(e) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestStreamMapList(t *testing.T) {
+	objs := agenttest.Cluster(60)
+	objs = append(objs,
+		agenttest.Object("batch/v1", "CronJob", "team-00", "nightly", agenttest.ManagedByArgo("batch")),
+		agenttest.Object("batch/v1", "Job", "team-00", "nightly-1", agenttest.WithOwnerReference("batch/v1", "CronJob", "nightly")),
+	)
+	client := agenttest.FakeClient(objs...)
+	want, _, _ := scanMapList(context.Background(), client, mapListScope{}, "test", &progressBar{})
+
+	var got []MapEntry
+	err := streamMapList(context.Background(), client, mapListScope{}, "test", func(e MapEntry) error {
+		got = append(got, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("streamed %d entries, scanned %d", len(got), len(want))
+	}
+	byID := map[string]MapEntry{}
+	for _, e := range want {
+		byID[e.ID] = e
+	}
+	for _, e := range got {
+		if w, ok := byID[e.ID]; !ok || w.Owner != e.Owner || w.Status != e.Status {
+			t.Errorf("streamed %s owned by %s, scanned %+v", e.ID, e.Owner, w)
+		}
+		if e.Kind == "Job" && e.Owner != "ArgoCD" {
+			t.Errorf("Job should inherit its CronJob's owner, got %s", e.Owner)
+		}
+	}
+
+	stop := errors.New("closed pipe")
+	n := 0
+	err = streamMapList(context.Background(), client, mapListScope{}, "test", func(MapEntry) error {
+		n++
+		return stop
+	})
+	if !errors.Is(err, stop) || n != 1 {
+		t.Errorf("emit error should stop the stream after 1 entry, got %d (%v)", n, err)
+	}
+}
+
+func TestPrintMapListTable(t *testing.T) {
+	entries := []MapEntry{
+		{Namespace: "payments", Kind: "Deployment", Name: "api", Owner: "ConfigHub", OwnerDetails: map[string]string{"space": "prod", "unit": "api"}},
+		{Namespace: "payments", Kind: "Service", Name: "api", Owner: "Flux", OwnerDetails: map[string]string{"name": "apps"}},
+	}
+	var buf bytes.Buffer
+	printMapListTable(&buf, entries, false)
+	if out := buf.String(); !strings.HasPrefix(out, "NAMESPACE  KIND        NAME  OWNER\n") || strings.Contains(out, "OWNER_DETAIL") {
+		t.Errorf("table:\n%s", out)
+	}
+
+	buf.Reset()
+	printMapListTable(&buf, entries, true)
+	for _, want := range []string{"OWNER_DETAIL", "prod/api", "apps"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("verbose table should contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+// Benchmarks for map list over a synthetic 10k-object cluster. CI holds
+// them to test/bench-budget.txt.
+
+func BenchmarkMapListScan_10k(b *testing.B) {
+	client := agenttest.FakeClient(agenttest.Cluster(10000)...)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = scanMapList(ctx, client, mapListScope{}, "bench", &progressBar{})
+	}
+}
+
+func BenchmarkMapListQuery_10k(b *testing.B) {
+	entries, _, _ := scanMapList(context.Background(), agenttest.FakeClient(agenttest.Cluster(10000)...), mapListScope{}, "bench", &progressBar{})
+	q, err := query.Parse("kind=Deployment AND namespace~=team-[0-4].* AND owner!=Native")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range entries {
+			q.Matches(e)
+		}
+	}
+}
+
+func BenchmarkMapListFormat_10k(b *testing.B) {
+	entries, _, _ := scanMapList(context.Background(), agenttest.FakeClient(agenttest.Cluster(10000)...), mapListScope{}, "bench", &progressBar{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		printMapListTable(io.Discard, entries, true)
+	}
+}
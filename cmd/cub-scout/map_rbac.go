@@ -0,0 +1,453 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var mapRBACCmd = &cobra.Command{
+	Use:   "rbac",
+	Short: "Show RBAC bindings grouped by owner",
+	Long: `Show ClusterRoles, ClusterRoleBindings and RoleBindings grouped by the owner that deployed them.
+
+Highlights the deployer blast radius:
+- GitOps controllers (Flux, ArgoCD, ConfigHub workers) bound to cluster-admin
+- Orphaned bindings whose ServiceAccount subjects no longer exist
+- ClusterRoles granting every verb on every resource
+
+Examples:
+  cub-scout map rbac                 # All bindings by owner
+  cub-scout map rbac --namespace prod
+  cub-scout map rbac --json          # JSON output for security tooling`,
+	RunE: runMapRBAC,
+}
+
+// gitopsControllerAccounts are well-known ServiceAccounts used by GitOps controllers.
+// Keys are "namespace/name"; values are the controller's display owner.
+var gitopsControllerAccounts = map[string]string{
+	"flux-system/kustomize-controller":                                "Flux",
+	"flux-system/helm-controller":                                     "Flux",
+	"flux-system/source-controller":                                   "Flux",
+	"argocd/argocd-application-controller":                            "ArgoCD",
+	"argocd/argocd-server":                                            "ArgoCD",
+	"argocd/argocd-applicationset-controller":                         "ArgoCD",
+	"confighub/confighub-worker":                                      "ConfigHub",
+	"confighub-system/confighub-worker":                               "ConfigHub",
+	"crossplane-system/crossplane":                                    "Crossplane",
+	"flux-system/tf-controller":                                       "Terraform",
+	"flux-system/tofu-controller":                                     "Terraform",
+	"argo-cd/argocd-application-controller":                           "ArgoCD",
+	"openshift-gitops/openshift-gitops-argocd-application-controller": "ArgoCD",
+}
+
+// RBACBinding is a role binding annotated with ownership and risk findings.
+type RBACBinding struct {
+	Kind            string   `json:"kind"` // ClusterRoleBinding or RoleBinding
+	Name            string   `json:"name"`
+	Namespace       string   `json:"namespace,omitempty"`
+	Role            string   `json:"role"` // ClusterRole/admin, Role/reader
+	Owner           string   `json:"owner"`
+	Subjects        []string `json:"subjects"`
+	ClusterAdmin    bool     `json:"clusterAdmin,omitempty"`
+	Controllers     []string `json:"gitopsControllers,omitempty"` // Controller SAs granted this role
+	MissingAccounts []string `json:"missingServiceAccounts,omitempty"`
+}
+
+// Orphaned reports whether every ServiceAccount subject of the binding is gone.
+func (b RBACBinding) Orphaned() bool {
+	if len(b.MissingAccounts) == 0 {
+		return false
+	}
+	saCount := 0
+	for _, s := range b.Subjects {
+		if strings.HasPrefix(s, "ServiceAccount/") {
+			saCount++
+		}
+	}
+	return saCount > 0 && len(b.MissingAccounts) == saCount
+}
+
+// RBACRole is a ClusterRole annotated with its owner and the bindings that
+// grant it.
+type RBACRole struct {
+	Name     string `json:"name"`
+	Owner    string `json:"owner"`
+	Bindings int    `json:"bindings"`           // Bindings referencing this role
+	Wildcard bool   `json:"wildcard,omitempty"` // Grants every verb on every resource
+}
+
+// RBACReport is the JSON output of 'map rbac'.
+type RBACReport struct {
+	Bindings         []RBACBinding  `json:"bindings"`
+	ClusterRoles     []RBACRole     `json:"clusterRoles"`
+	ByOwner          map[string]int `json:"byOwner"`
+	RolesByOwner     map[string]int `json:"rolesByOwner"`
+	ControllerAdmins int            `json:"controllerAdmins"`
+	Orphaned         int            `json:"orphaned"`
+
+	// Warnings are lists that failed, e.g. forbidden RoleBindings, so the
+	// report is incomplete
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func init() {
+	mapCmd.AddCommand(mapRBACCmd)
+	mapRBACCmd.Flags().StringVar(&mapNamespace, "namespace", "", "Filter RoleBindings by namespace")
+	_ = mapRBACCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+}
+
+func runMapRBAC(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	inv, err := listRBAC(ctx, dynClient, mapNamespace)
+	if err != nil {
+		return err
+	}
+	report := buildRBACReport(inv.Bindings, inv.ClusterRoles, inv.Accounts)
+	report.Warnings = inv.Warnings
+
+	if mapJSON {
+		return writeJSON(os.Stdout, "RBACReport", report)
+	}
+
+	printRBACReport(report)
+	return nil
+}
+
+// rbacInventory is what 'map rbac' lists from the cluster.
+type rbacInventory struct {
+	Bindings     []unstructured.Unstructured
+	ClusterRoles []unstructured.Unstructured
+
+	// Accounts is the set of existing ServiceAccounts keyed by "namespace/name"
+	Accounts map[string]bool
+
+	Warnings []string
+}
+
+// listRBAC lists bindings, ClusterRoles and ServiceAccounts. Cluster-scoped
+// kinds are skipped when namespace is set. ClusterRoleBindings and
+// ServiceAccounts are required; a failed RoleBinding or ClusterRole list,
+// typically forbidden by RBAC itself, becomes a warning so the rest of the
+// report still renders.
+func listRBAC(ctx context.Context, client dynamic.Interface, namespace string) (rbacInventory, error) {
+	var inv rbacInventory
+	rbac := func(resource string) schema.GroupVersionResource {
+		return schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: resource}
+	}
+
+	if namespace == "" {
+		l, err := client.Resource(rbac("clusterrolebindings")).List(ctx, v1.ListOptions{})
+		if err != nil {
+			return inv, fmt.Errorf("list clusterrolebindings: %w", err)
+		}
+		inv.Bindings = append(inv.Bindings, l.Items...)
+
+		l, err = client.Resource(rbac("clusterroles")).List(ctx, v1.ListOptions{})
+		if err != nil {
+			inv.Warnings = append(inv.Warnings, fmt.Sprintf("list clusterroles: %v; ClusterRoles not shown", err))
+		} else {
+			inv.ClusterRoles = l.Items
+		}
+	}
+
+	l, err := client.Resource(rbac("rolebindings")).Namespace(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		inv.Warnings = append(inv.Warnings, fmt.Sprintf("list rolebindings: %v; RoleBindings not shown", err))
+	} else {
+		inv.Bindings = append(inv.Bindings, l.Items...)
+	}
+
+	// Collect existing ServiceAccounts so dangling subjects can be detected
+	saGVR := schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	saList, err := client.Resource(saGVR).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return inv, fmt.Errorf("list serviceaccounts: %w", err)
+	}
+	inv.Accounts = make(map[string]bool, len(saList.Items))
+	for _, sa := range saList.Items {
+		inv.Accounts[sa.GetNamespace()+"/"+sa.GetName()] = true
+	}
+	return inv, nil
+}
+
+// buildRBACReport classifies bindings and ClusterRoles by owner and flags
+// risky or orphaned ones. accounts is the set of existing ServiceAccounts
+// keyed by "namespace/name".
+func buildRBACReport(bindings, clusterRoles []unstructured.Unstructured, accounts map[string]bool) RBACReport {
+	report := RBACReport{ByOwner: map[string]int{}, RolesByOwner: map[string]int{}}
+
+	boundRoles := map[string]int{}
+	for i := range bindings {
+		b := analyzeBinding(&bindings[i], accounts)
+		report.ByOwner[b.Owner]++
+		if strings.HasPrefix(b.Role, "ClusterRole/") {
+			boundRoles[strings.TrimPrefix(b.Role, "ClusterRole/")]++
+		}
+		if b.ClusterAdmin && len(b.Controllers) > 0 {
+			report.ControllerAdmins++
+		}
+		if b.Orphaned() {
+			report.Orphaned++
+		}
+		report.Bindings = append(report.Bindings, b)
+	}
+
+	sort.Slice(report.Bindings, func(i, j int) bool {
+		a, b := report.Bindings[i], report.Bindings[j]
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	for i := range clusterRoles {
+		role := &clusterRoles[i]
+		r := RBACRole{
+			Name:     role.GetName(),
+			Owner:    displayOwner(agent.DetectOwnership(role).Type),
+			Bindings: boundRoles[role.GetName()],
+			Wildcard: grantsEverything(role),
+		}
+		report.RolesByOwner[r.Owner]++
+		report.ClusterRoles = append(report.ClusterRoles, r)
+	}
+	sort.Slice(report.ClusterRoles, func(i, j int) bool {
+		a, b := report.ClusterRoles[i], report.ClusterRoles[j]
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return a.Name < b.Name
+	})
+
+	return report
+}
+
+// grantsEverything reports whether a role has a rule allowing every verb on
+// every resource, the equivalent of cluster-admin.
+func grantsEverything(role *unstructured.Unstructured) bool {
+	rules, _, _ := unstructured.NestedSlice(role.Object, "rules")
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		has := func(field string) bool {
+			values, _, _ := unstructured.NestedStringSlice(rule, field)
+			for _, v := range values {
+				if v == "*" {
+					return true
+				}
+			}
+			return false
+		}
+		if has("verbs") && has("resources") && has("apiGroups") {
+			return true
+		}
+	}
+	return false
+}
+
+func analyzeBinding(obj *unstructured.Unstructured, accounts map[string]bool) RBACBinding {
+	ownership := agent.DetectOwnership(obj)
+	roleKind, _, _ := unstructured.NestedString(obj.Object, "roleRef", "kind")
+	roleName, _, _ := unstructured.NestedString(obj.Object, "roleRef", "name")
+
+	b := RBACBinding{
+		Kind:         obj.GetKind(),
+		Name:         obj.GetName(),
+		Namespace:    obj.GetNamespace(),
+		Role:         roleKind + "/" + roleName,
+		Owner:        displayOwner(ownership.Type),
+		ClusterAdmin: roleKind == "ClusterRole" && roleName == "cluster-admin",
+	}
+
+	subjects, _, _ := unstructured.NestedSlice(obj.Object, "subjects")
+	for _, s := range subjects {
+		subj, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := subj["kind"].(string)
+		name, _ := subj["name"].(string)
+		ns, _ := subj["namespace"].(string)
+
+		if kind != "ServiceAccount" {
+			b.Subjects = append(b.Subjects, kind+"/"+name)
+			continue
+		}
+		// RoleBinding subjects may omit namespace; it defaults to the binding's namespace
+		if ns == "" {
+			ns = obj.GetNamespace()
+		}
+		key := ns + "/" + name
+		b.Subjects = append(b.Subjects, "ServiceAccount/"+key)
+		if _, ok := gitopsControllerAccounts[key]; ok {
+			b.Controllers = append(b.Controllers, key)
+		}
+		if !accounts[key] {
+			b.MissingAccounts = append(b.MissingAccounts, key)
+		}
+	}
+
+	return b
+}
+
+func printRBACReport(report RBACReport) {
+	fmt.Println()
+	fmt.Println("RBAC OWNERSHIP MAP")
+	fmt.Println("════════════════════════════════════════════════════════════════════")
+	fmt.Println("Role bindings grouped by the owner that deployed them.")
+	fmt.Println()
+
+	for _, warning := range report.Warnings {
+		fmt.Printf("⚠ %s\n", warning)
+	}
+	if len(report.Warnings) > 0 {
+		fmt.Println()
+	}
+
+	if len(report.Bindings) == 0 && len(report.ClusterRoles) == 0 {
+		fmt.Println("No role bindings found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "OWNER\tKIND\tNAMESPACE\tNAME\tROLE\tFLAGS")
+	fmt.Fprintln(w, "─────\t────\t─────────\t────\t────\t─────")
+	for _, b := range report.Bindings {
+		ns := b.Namespace
+		if ns == "" {
+			ns = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", b.Owner, b.Kind, ns, b.Name, b.Role, strings.Join(bindingFlags(b), ", "))
+	}
+	w.Flush()
+
+	owners := make([]string, 0, len(report.ByOwner))
+	for owner := range report.ByOwner {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	parts := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		parts = append(parts, fmt.Sprintf("%s(%d)", owner, report.ByOwner[owner]))
+	}
+	fmt.Printf("\nTotal: %d bindings\n", len(report.Bindings))
+	fmt.Printf("By Owner: %s\n", strings.Join(parts, " "))
+
+	printRBACRoles(report)
+
+	if report.ControllerAdmins > 0 {
+		fmt.Printf("\n⚠ %d binding(s) grant cluster-admin to GitOps controllers\n", report.ControllerAdmins)
+		fmt.Println("  A compromised Git repo can change anything in the cluster.")
+	}
+	if report.Orphaned > 0 {
+		fmt.Printf("\n✗ %d orphaned binding(s) point at deleted ServiceAccounts\n", report.Orphaned)
+		fmt.Println("  Recreating an account with the same name silently regains these permissions.")
+	}
+
+	fmt.Println()
+	fmt.Println("NEXT STEPS:")
+	fmt.Println("→ Inspect a binding:   kubectl describe clusterrolebinding <name>")
+	fmt.Println("→ Trace its deployer:  cub-scout trace clusterrolebinding/<name>")
+}
+
+// bindingFlags returns the FLAGS column for a binding, naming every GitOps
+// controller granted cluster-admin.
+func bindingFlags(b RBACBinding) []string {
+	var flags []string
+	if b.ClusterAdmin && len(b.Controllers) > 0 {
+		controllers := make([]string, 0, len(b.Controllers))
+		for _, key := range b.Controllers {
+			controllers = append(controllers, gitopsControllerAccounts[key]+" "+key[strings.Index(key, "/")+1:])
+		}
+		flags = append(flags, "⚠ cluster-admin to "+strings.Join(controllers, ", "))
+	} else if b.ClusterAdmin {
+		flags = append(flags, "cluster-admin")
+	}
+	if b.Orphaned() {
+		flags = append(flags, "✗ orphaned")
+	} else if len(b.MissingAccounts) > 0 {
+		flags = append(flags, fmt.Sprintf("%d missing SA", len(b.MissingAccounts)))
+	}
+	return flags
+}
+
+// printRBACRoles prints the ClusterRoles by owner. Built-in system: roles
+// are counted but not listed unless they grant everything.
+func printRBACRoles(report RBACReport) {
+	if len(report.ClusterRoles) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println("CLUSTER ROLES")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "OWNER\tNAME\tBINDINGS\tFLAGS")
+	fmt.Fprintln(w, "─────\t────\t────────\t─────")
+	hidden, wildcards := 0, 0
+	for _, r := range report.ClusterRoles {
+		if r.Wildcard {
+			wildcards++
+		}
+		if strings.HasPrefix(r.Name, "system:") && !r.Wildcard {
+			hidden++
+			continue
+		}
+		var flags []string
+		if r.Wildcard {
+			flags = append(flags, "⚠ all verbs on all resources")
+		}
+		if r.Bindings == 0 {
+			flags = append(flags, "unbound")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", r.Owner, r.Name, r.Bindings, strings.Join(flags, ", "))
+	}
+	w.Flush()
+
+	owners := make([]string, 0, len(report.RolesByOwner))
+	for owner := range report.RolesByOwner {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	parts := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		parts = append(parts, fmt.Sprintf("%s(%d)", owner, report.RolesByOwner[owner]))
+	}
+	fmt.Printf("\nTotal: %d ClusterRoles", len(report.ClusterRoles))
+	if hidden > 0 {
+		fmt.Printf(" (%d built-in system: roles not listed)", hidden)
+	}
+	fmt.Printf("\nBy Owner: %s\n", strings.Join(parts, " "))
+	if wildcards > 0 {
+		fmt.Printf("\n⚠ %d ClusterRole(s) grant every verb on every resource\n", wildcards)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func newTestBinding(kind, namespace, name, roleKind, roleName string, labels map[string]string, subjects ...map[string]interface{}) unstructured.Unstructured {
+	subs := make([]interface{}, 0, len(subjects))
+	for _, s := range subjects {
+		subs = append(subs, s)
+	}
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       kind,
+		"roleRef": map[string]interface{}{
+			"apiGroup": "rbac.authorization.k8s.io",
+			"kind":     roleKind,
+			"name":     roleName,
+		},
+		"subjects": subs,
+	}}
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	u.SetLabels(labels)
+	return u
+}
+
+func saSubject(namespace, name string) map[string]interface{} {
+	s := map[string]interface{}{"kind": "ServiceAccount", "name": name}
+	if namespace != "" {
+		s["namespace"] = namespace
+	}
+	return s
+}
+
+func TestBuildRBACReport(t *testing.T) {
+	accounts := map[string]bool{
+		"flux-system/kustomize-controller": true,
+		"prod/app":                         true,
+	}
+
+	bindings := []unstructured.Unstructured{
+		newTestBinding("ClusterRoleBinding", "", "cluster-reconciler-flux-system", "ClusterRole", "cluster-admin",
+			map[string]string{"kustomize.toolkit.fluxcd.io/name": "flux-system"},
+			saSubject("flux-system", "kustomize-controller")),
+		newTestBinding("RoleBinding", "prod", "legacy-deployer", "ClusterRole", "edit", nil,
+			saSubject("", "jenkins")),
+		newTestBinding("RoleBinding", "prod", "app-reader", "Role", "reader",
+			map[string]string{"app.kubernetes.io/managed-by": "Helm"},
+			saSubject("", "app"),
+			map[string]interface{}{"kind": "Group", "name": "devs"}),
+	}
+
+	roles := []unstructured.Unstructured{
+		*agenttest.Object("rbac.authorization.k8s.io/v1", "ClusterRole", "", "cluster-admin"),
+		*agenttest.Object("rbac.authorization.k8s.io/v1", "ClusterRole", "", "edit"),
+		*agenttest.Object("rbac.authorization.k8s.io/v1", "ClusterRole", "", "flux-view",
+			agenttest.ManagedByFluxKustomization("flux-system", "flux-system")),
+	}
+	roles[0].Object["rules"] = []interface{}{map[string]interface{}{
+		"apiGroups": []interface{}{"*"}, "resources": []interface{}{"*"}, "verbs": []interface{}{"*"},
+	}}
+
+	report := buildRBACReport(bindings, roles, accounts)
+
+	if len(report.Bindings) != 3 {
+		t.Fatalf("expected 3 bindings, got %d", len(report.Bindings))
+	}
+	if report.ControllerAdmins != 1 {
+		t.Errorf("ControllerAdmins = %d, want 1", report.ControllerAdmins)
+	}
+	if report.Orphaned != 1 {
+		t.Errorf("Orphaned = %d, want 1", report.Orphaned)
+	}
+	if report.ByOwner["Flux"] != 1 || report.ByOwner["Helm"] != 1 || report.ByOwner["Native"] != 1 {
+		t.Errorf("unexpected ByOwner: %v", report.ByOwner)
+	}
+
+	// Sorted by owner: Flux, Helm, Native
+	wantOrder := []string{"cluster-reconciler-flux-system", "app-reader", "legacy-deployer"}
+	for i, name := range wantOrder {
+		if report.Bindings[i].Name != name {
+			t.Errorf("Bindings[%d] = %s, want %s", i, report.Bindings[i].Name, name)
+		}
+	}
+
+	legacy := report.Bindings[2]
+	if len(legacy.MissingAccounts) != 1 || legacy.MissingAccounts[0] != "prod/jenkins" {
+		t.Errorf("expected namespace-defaulted missing SA prod/jenkins, got %v", legacy.MissingAccounts)
+	}
+
+	reader := report.Bindings[1]
+	if reader.Orphaned() {
+		t.Error("binding with existing ServiceAccount should not be orphaned")
+	}
+
+	if len(report.ClusterRoles) != 3 || report.RolesByOwner["Flux"] != 1 || report.RolesByOwner["Native"] != 2 {
+		t.Fatalf("ClusterRoles = %+v, RolesByOwner = %v", report.ClusterRoles, report.RolesByOwner)
+	}
+	for _, r := range report.ClusterRoles {
+		switch r.Name {
+		case "cluster-admin":
+			if !r.Wildcard || r.Bindings != 1 {
+				t.Errorf("cluster-admin = %+v, want wildcard with 1 binding", r)
+			}
+		case "edit":
+			if r.Wildcard || r.Bindings != 1 {
+				t.Errorf("edit = %+v, want 1 binding", r)
+			}
+		case "flux-view":
+			if r.Bindings != 0 {
+				t.Errorf("flux-view = %+v, want unbound", r)
+			}
+		}
+	}
+}
+
+func TestListRBACWarnsOnForbiddenLists(t *testing.T) {
+	client := agenttest.FakeClient(
+		agenttest.Object("v1", "ServiceAccount", "prod", "app"),
+		agenttest.Object("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "", "admins"),
+	)
+	for _, resource := range []string{"rolebindings", "clusterroles"} {
+		client.PrependReactor("list", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+			gr := schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: action.GetResource().Resource}
+			return true, nil, apierrors.NewForbidden(gr, "", nil)
+		})
+	}
+
+	inv, err := listRBAC(context.Background(), client, "")
+	if err != nil {
+		t.Fatalf("listRBAC() error = %v", err)
+	}
+	if len(inv.Bindings) != 1 || !inv.Accounts["prod/app"] {
+		t.Errorf("inventory = %+v", inv)
+	}
+	if len(inv.Warnings) != 2 || !strings.Contains(inv.Warnings[0], "clusterroles") || !strings.Contains(inv.Warnings[1], "rolebindings") {
+		t.Errorf("warnings = %v, want forbidden clusterroles and rolebindings", inv.Warnings)
+	}
+
+	client.PrependReactor("list", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "serviceaccounts"}, "", nil)
+	})
+	if _, err := listRBAC(context.Background(), client, ""); err == nil {
+		t.Error("listRBAC() without ServiceAccounts should fail")
+	}
+}
+
+func TestBindingFlagsNamesEveryController(t *testing.T) {
+	report := buildRBACReport([]unstructured.Unstructured{
+		newTestBinding("ClusterRoleBinding", "", "gitops-admin", "ClusterRole", "cluster-admin", nil,
+			saSubject("flux-system", "kustomize-controller"),
+			saSubject("argocd", "argocd-application-controller")),
+	}, nil, map[string]bool{"flux-system/kustomize-controller": true, "argocd/argocd-application-controller": true})
+
+	flags := bindingFlags(report.Bindings[0])
+	if len(flags) != 1 || flags[0] != "⚠ cluster-admin to Flux kustomize-controller, ArgoCD argocd-application-controller" {
+		t.Errorf("flags = %q, want both controllers named", flags)
+	}
+}
+
+func TestRBACBindingOrphanedIgnoresNonServiceAccountSubjects(t *testing.T) {
+	b := RBACBinding{
+		Subjects:        []string{"ServiceAccount/prod/gone", "User/alice"},
+		MissingAccounts: []string{"prod/gone"},
+	}
+	if !b.Orphaned() {
+		t.Error("expected binding to be orphaned when all ServiceAccount subjects are missing")
+	}
+
+	b = RBACBinding{Subjects: []string{"Group/devs"}}
+	if b.Orphaned() {
+		t.Error("binding with only group subjects should not be orphaned")
+	}
+}
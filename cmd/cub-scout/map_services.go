@@ -0,0 +1,330 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var mapServicesExternalOnly bool
+
+var mapServicesCmd = &cobra.Command{
+	Use:     "services",
+	Aliases: []string{"exposure", "svc"},
+	Short:   "Show network exposure of services by owner",
+	Long: `Show Services, Ingresses and Gateway API routes correlated to the
+workloads they select and the deployers that own them.
+
+A service is considered externally exposed when it is:
+- type LoadBalancer or NodePort
+- a backend of an Ingress
+- a backend of an HTTPRoute/GRPCRoute attached to a Gateway
+
+Externally exposed services owned by Native resources (or fronting Native
+workloads) are flagged — a common audit question.
+
+Examples:
+  cub-scout map services
+  cub-scout map services --external
+  cub-scout map services --namespace prod --json`,
+	RunE: runMapServices,
+}
+
+// ServiceExposure describes one Service and how it is reachable.
+type ServiceExposure struct {
+	Namespace      string   `json:"namespace"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Owner          string   `json:"owner"`
+	ExposedVia     []string `json:"exposedVia,omitempty"` // LoadBalancer, NodePort, Ingress/x, HTTPRoute/y (Gateway/z)
+	Workloads      []string `json:"workloads,omitempty"`  // Kind/name of selected workloads
+	WorkloadOwners []string `json:"workloadOwners,omitempty"`
+	Unmanaged      bool     `json:"unmanagedExposure,omitempty"`
+}
+
+// External reports whether the service is reachable from outside the cluster.
+func (e ServiceExposure) External() bool {
+	return len(e.ExposedVia) > 0
+}
+
+func init() {
+	mapCmd.AddCommand(mapServicesCmd)
+	mapServicesCmd.Flags().StringVar(&mapNamespace, "namespace", "", "Filter by namespace")
+	mapServicesCmd.Flags().BoolVar(&mapServicesExternalOnly, "external", false, "Only show externally exposed services")
+	_ = mapServicesCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+}
+
+func runMapServices(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	list := func(gvr schema.GroupVersionResource) []unstructured.Unstructured {
+		l, err := dynClient.Resource(gvr).Namespace(mapNamespace).List(ctx, v1.ListOptions{})
+		if err != nil {
+			return nil // CRD not installed or no access
+		}
+		return l.Items
+	}
+
+	svcList, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "services"}).Namespace(mapNamespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list services: %w", err)
+	}
+	services := svcList.Items
+
+	var workloads []unstructured.Unstructured
+	for _, res := range []string{"deployments", "statefulsets", "daemonsets"} {
+		workloads = append(workloads, list(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: res})...)
+	}
+
+	var routes []unstructured.Unstructured
+	routes = append(routes, list(schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"})...)
+	routes = append(routes, list(schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"})...)
+	routes = append(routes, list(schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "grpcroutes"})...)
+
+	exposures := buildServiceExposures(services, workloads, routes)
+
+	if mapServicesExternalOnly {
+		filtered := exposures[:0]
+		for _, e := range exposures {
+			if e.External() {
+				filtered = append(filtered, e)
+			}
+		}
+		exposures = filtered
+	}
+
+	if mapJSON {
+		return writeJSON(os.Stdout, "ServiceExposures", exposures)
+	}
+
+	printServiceExposures(exposures)
+	return nil
+}
+
+// buildServiceExposures correlates services with the workloads they select and
+// the Ingress/Gateway routes that reference them.
+func buildServiceExposures(services, workloads, routes []unstructured.Unstructured) []ServiceExposure {
+	// Index route backends: "namespace/service" -> ["Ingress/web", ...]
+	backends := map[string][]string{}
+	for i := range routes {
+		route := &routes[i]
+		label := route.GetKind() + "/" + route.GetName()
+		if route.GetKind() != "Ingress" {
+			if gws := routeGateways(route); len(gws) > 0 {
+				label += " (" + strings.Join(gws, ",") + ")"
+			}
+		}
+		for _, key := range routeBackends(route) {
+			backends[key] = appendUnique(backends[key], label)
+		}
+	}
+
+	var result []ServiceExposure
+	for i := range services {
+		svc := &services[i]
+		if isSystemNamespace(svc.GetNamespace()) {
+			continue
+		}
+
+		svcType, _, _ := unstructured.NestedString(svc.Object, "spec", "type")
+		if svcType == "" {
+			svcType = "ClusterIP"
+		}
+
+		e := ServiceExposure{
+			Namespace: svc.GetNamespace(),
+			Name:      svc.GetName(),
+			Type:      svcType,
+			Owner:     displayOwner(agent.DetectOwnership(svc).Type),
+		}
+		if svcType == "LoadBalancer" || svcType == "NodePort" {
+			e.ExposedVia = append(e.ExposedVia, svcType)
+		}
+		e.ExposedVia = append(e.ExposedVia, backends[svc.GetNamespace()+"/"+svc.GetName()]...)
+
+		selector, _, _ := unstructured.NestedStringMap(svc.Object, "spec", "selector")
+		if len(selector) > 0 {
+			for j := range workloads {
+				wl := &workloads[j]
+				if wl.GetNamespace() != svc.GetNamespace() {
+					continue
+				}
+				podLabels, _, _ := unstructured.NestedStringMap(wl.Object, "spec", "template", "metadata", "labels")
+				if !matchesSelector(podLabels, selector) {
+					continue
+				}
+				e.Workloads = append(e.Workloads, wl.GetKind()+"/"+wl.GetName())
+				e.WorkloadOwners = appendUnique(e.WorkloadOwners, displayOwner(agent.DetectOwnership(wl).Type))
+			}
+		}
+
+		if e.External() {
+			e.Unmanaged = e.Owner == "Native"
+			for _, o := range e.WorkloadOwners {
+				if o == "Native" {
+					e.Unmanaged = true
+				}
+			}
+		}
+
+		sort.Strings(e.Workloads)
+		sort.Strings(e.WorkloadOwners)
+		result = append(result, e)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// routeBackends returns "namespace/service" keys referenced by an Ingress or
+// Gateway API route.
+func routeBackends(route *unstructured.Unstructured) []string {
+	ns := route.GetNamespace()
+	var keys []string
+
+	if route.GetKind() == "Ingress" {
+		if name, found, _ := unstructured.NestedString(route.Object, "spec", "defaultBackend", "service", "name"); found {
+			keys = append(keys, ns+"/"+name)
+		}
+		rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+		for _, r := range rules {
+			rule, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			paths, _, _ := unstructured.NestedSlice(rule, "http", "paths")
+			for _, p := range paths {
+				path, ok := p.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if name, found, _ := unstructured.NestedString(path, "backend", "service", "name"); found {
+					keys = appendUnique(keys, ns+"/"+name)
+				}
+			}
+		}
+		return keys
+	}
+
+	// Gateway API routes: spec.rules[].backendRefs[]
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		refs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+		for _, ref := range refs {
+			backend, ok := ref.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if kind, _ := backend["kind"].(string); kind != "" && kind != "Service" {
+				continue
+			}
+			name, _ := backend["name"].(string)
+			backendNS, _ := backend["namespace"].(string)
+			if backendNS == "" {
+				backendNS = ns
+			}
+			if name != "" {
+				keys = appendUnique(keys, backendNS+"/"+name)
+			}
+		}
+	}
+	return keys
+}
+
+// routeGateways returns "Gateway/name" for each parentRef of a Gateway API route.
+func routeGateways(route *unstructured.Unstructured) []string {
+	var gateways []string
+	parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	for _, p := range parents {
+		parent, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if kind, _ := parent["kind"].(string); kind != "" && kind != "Gateway" {
+			continue
+		}
+		if name, _ := parent["name"].(string); name != "" {
+			gateways = appendUnique(gateways, "Gateway/"+name)
+		}
+	}
+	return gateways
+}
+
+func printServiceExposures(exposures []ServiceExposure) {
+	fmt.Println()
+	fmt.Println("NETWORK EXPOSURE")
+	fmt.Println("════════════════════════════════════════════════════════════════════")
+	fmt.Println("Services, the workloads behind them, and how they are reachable.")
+	fmt.Println()
+
+	if len(exposures) == 0 {
+		fmt.Println("No services found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tSERVICE\tTYPE\tOWNER\tWORKLOADS\tEXPOSED VIA")
+	fmt.Fprintln(w, "─────────\t───────\t────\t─────\t─────────\t───────────")
+	var external, unmanaged int
+	for _, e := range exposures {
+		exposed := "-"
+		if e.External() {
+			external++
+			exposed = strings.Join(e.ExposedVia, ", ")
+		}
+		workloads := "-"
+		if len(e.Workloads) > 0 {
+			workloads = strings.Join(e.Workloads, ", ")
+			if len(e.WorkloadOwners) > 0 {
+				workloads += " [" + strings.Join(e.WorkloadOwners, ",") + "]"
+			}
+		}
+		prefix := ""
+		if e.Unmanaged {
+			unmanaged++
+			prefix = "⚠ "
+		}
+		fmt.Fprintf(w, "%s\t%s%s\t%s\t%s\t%s\t%s\n", e.Namespace, prefix, e.Name, e.Type, e.Owner, workloads, exposed)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d services, %d externally exposed\n", len(exposures), external)
+	if unmanaged > 0 {
+		fmt.Printf("⚠ %d externally exposed service(s) are Native or front Native workloads\n", unmanaged)
+		fmt.Println("  These are reachable from outside the cluster but not managed by GitOps.")
+		fmt.Println("  Run: cub-scout map orphans  # to see all unmanaged resources")
+	}
+}
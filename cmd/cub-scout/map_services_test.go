@@ -0,0 +1,143 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestService(namespace, name, svcType string, labels, selector map[string]string) unstructured.Unstructured {
+	spec := map[string]interface{}{}
+	if svcType != "" {
+		spec["type"] = svcType
+	}
+	if selector != nil {
+		sel := map[string]interface{}{}
+		for k, v := range selector {
+			sel[k] = v
+		}
+		spec["selector"] = sel
+	}
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"spec":       spec,
+	}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func newTestPodTemplateWorkload(namespace, name string, labels, podLabels map[string]string) unstructured.Unstructured {
+	pl := map[string]interface{}{}
+	for k, v := range podLabels {
+		pl[k] = v
+	}
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": pl},
+			},
+		},
+	}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
+
+func TestBuildServiceExposures(t *testing.T) {
+	fluxLabels := map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps"}
+
+	services := []unstructured.Unstructured{
+		newTestService("prod", "internal", "", fluxLabels, map[string]string{"app": "internal"}),
+		newTestService("prod", "lb", "LoadBalancer", nil, map[string]string{"app": "lb"}),
+		newTestService("prod", "web", "ClusterIP", fluxLabels, map[string]string{"app": "web"}),
+		newTestService("prod", "api", "ClusterIP", fluxLabels, map[string]string{"app": "api"}),
+		newTestService("kube-system", "kube-dns", "ClusterIP", nil, nil),
+	}
+	workloads := []unstructured.Unstructured{
+		newTestPodTemplateWorkload("prod", "internal", fluxLabels, map[string]string{"app": "internal"}),
+		newTestPodTemplateWorkload("prod", "lb", nil, map[string]string{"app": "lb"}),
+		newTestPodTemplateWorkload("prod", "web", nil, map[string]string{"app": "web"}),
+		newTestPodTemplateWorkload("prod", "api", fluxLabels, map[string]string{"app": "api"}),
+	}
+
+	ingress := unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "Ingress",
+		"metadata": map[string]interface{}{"name": "web", "namespace": "prod"},
+		"spec": map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{
+					"http": map[string]interface{}{
+						"paths": []interface{}{
+							map[string]interface{}{"backend": map[string]interface{}{"service": map[string]interface{}{"name": "web"}}},
+						},
+					},
+				},
+			},
+		},
+	}}
+	route := unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "HTTPRoute",
+		"metadata": map[string]interface{}{"name": "api", "namespace": "prod"},
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "public"}},
+			"rules": []interface{}{
+				map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": "api", "port": int64(80)}}},
+			},
+		},
+	}}
+
+	got := buildServiceExposures(services, workloads, []unstructured.Unstructured{ingress, route})
+	if len(got) != 4 {
+		t.Fatalf("expected 4 services (system namespace skipped), got %d", len(got))
+	}
+
+	byName := map[string]ServiceExposure{}
+	for _, e := range got {
+		byName[e.Name] = e
+	}
+
+	if e := byName["internal"]; e.External() || e.Unmanaged || e.Type != "ClusterIP" {
+		t.Errorf("internal: unexpected exposure %+v", e)
+	}
+	if e := byName["lb"]; !e.External() || !e.Unmanaged || e.Owner != "Native" {
+		t.Errorf("lb: expected unmanaged LoadBalancer, got %+v", e)
+	}
+	if e := byName["web"]; !reflect.DeepEqual(e.ExposedVia, []string{"Ingress/web"}) || !e.Unmanaged {
+		t.Errorf("web: expected Ingress exposure fronting Native workload, got %+v", e)
+	}
+	e := byName["api"]
+	if !reflect.DeepEqual(e.ExposedVia, []string{"HTTPRoute/api (Gateway/public)"}) {
+		t.Errorf("api: ExposedVia = %v", e.ExposedVia)
+	}
+	if e.Unmanaged || !reflect.DeepEqual(e.Workloads, []string{"Deployment/api"}) || !reflect.DeepEqual(e.WorkloadOwners, []string{"Flux"}) {
+		t.Errorf("api: unexpected exposure %+v", e)
+	}
+}
+
+func TestRouteBackendsCrossNamespace(t *testing.T) {
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "GRPCRoute",
+		"metadata": map[string]interface{}{"name": "grpc", "namespace": "edge"},
+		"spec": map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{"backendRefs": []interface{}{
+					map[string]interface{}{"name": "svc", "namespace": "backend"},
+					map[string]interface{}{"name": "bucket", "kind": "Backend"},
+				}},
+			},
+		},
+	}}
+	if got := routeBackends(route); !reflect.DeepEqual(got, []string{"backend/svc"}) {
+		t.Errorf("routeBackends = %v, want [backend/svc]", got)
+	}
+}
@@ -0,0 +1,384 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+// ConfigHub and cli-utils markers on applied resources.
+const (
+	unitSlugKey        = "confighub.com/UnitSlug"
+	spaceNameKey       = "confighub.com/SpaceName"
+	owningInventoryKey = "config.k8s.io/owning-inventory"
+	inventoryIDKey     = "cli-utils.sigs.k8s.io/inventory-id"
+)
+
+var (
+	staleNamespace string
+	staleCleanup   bool
+	staleYes       bool
+)
+
+var mapStaleCmd = &cobra.Command{
+	Use:     "stale",
+	Aliases: []string{"garbage"},
+	Short:   "List resources labeled for ConfigHub units or inventories that no longer exist",
+	Long: `Find resources that still carry ConfigHub ownership markers after their
+unit or inventory is gone: the unit was deleted, its space was deleted, or the
+resources were left behind by an earlier import.
+
+A resource is stale when:
+  - its confighub.com/UnitSlug label or annotation names a unit that no longer
+    exists (in its confighub.com/SpaceName space, or in any space when unset);
+    a space you cannot read is skipped, only one ConfigHub reports as not
+    found counts as deleted
+  - its config.k8s.io/owning-inventory annotation names an inventory that is
+    neither a ConfigHub unit nor a cli-utils inventory in the cluster
+
+Stale markers make map and trace report ConfigHub as the owner, and make the
+next apply of a new unit refuse to adopt the resource. With --cleanup the
+markers are removed; the resources themselves are left running. Map
+commands are read-only by default, so --cleanup needs --read-only=false.
+
+Examples:
+  cub-scout map stale                      # List stale resources
+  cub-scout map stale --namespace prod
+  cub-scout map stale --cleanup --read-only=false        # Strip stale markers (prompts)
+  cub-scout map stale --cleanup --read-only=false --yes`,
+	Args: cobra.NoArgs,
+	RunE: runMapStale,
+}
+
+func init() {
+	mapStaleCmd.Flags().StringVarP(&staleNamespace, "namespace", "n", "", "Only check this namespace")
+	_ = mapStaleCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	mapStaleCmd.Flags().BoolVar(&staleCleanup, "cleanup", false, "Remove stale ConfigHub labels and annotations from the listed resources")
+	mapStaleCmd.Flags().BoolVarP(&staleYes, "yes", "y", false, "Skip the --cleanup confirmation")
+
+	mapCmd.AddCommand(mapStaleCmd)
+}
+
+// StaleResource is a live resource whose ConfigHub unit or inventory is gone.
+type StaleResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Markers are the labels and annotations to remove, e.g. label:confighub.com/UnitSlug
+	Markers []string `json:"markers"`
+	Reasons []string `json:"reasons"`
+}
+
+// staleGVRs are the kinds ConfigHub units commonly apply.
+var staleGVRs = []schema.GroupVersionResource{
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Group: "apps", Version: "v1", Resource: "daemonsets"},
+	{Group: "batch", Version: "v1", Resource: "cronjobs"},
+	{Group: "", Version: "v1", Resource: "services"},
+	{Group: "", Version: "v1", Resource: "serviceaccounts"},
+	{Group: "", Version: "v1", Resource: "configmaps"},
+	{Group: "", Version: "v1", Resource: "secrets"},
+	{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+}
+
+// hubInventory is what exists in ConfigHub and the cluster to own resources.
+type hubInventory struct {
+	units       map[string]map[string]bool // space -> unit slug
+	unitIDs     map[string]bool
+	inventories map[string]bool // cli-utils inventory IDs in the cluster
+
+	// deletedSpaces are spaces missing from the space list that cub reports
+	// as not found. Other unlisted spaces, e.g. ones the user cannot read,
+	// are unknown and their resources are not reported stale.
+	deletedSpaces map[string]bool
+}
+
+func runMapStale(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if staleCleanup {
+		if err := checkWrite("remove stale markers (--cleanup)"); err != nil {
+			return err
+		}
+	}
+
+	hub, err := loadHubInventory()
+	if err != nil {
+		// Without the unit list every marked resource would look stale
+		return fmt.Errorf("load ConfigHub units: %w\n\n  Check that you're authenticated: cub auth login", err)
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	var objs []unstructured.Unstructured
+	for _, gvr := range staleGVRs {
+		l, err := dynClient.Resource(gvr).Namespace(staleNamespace).List(ctx, v1.ListOptions{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: list %s: %v; not checked\n", gvr.Resource, err)
+			continue
+		}
+		objs = append(objs, l.Items...)
+	}
+	// Inventories may live outside --namespace. Without them every
+	// inventory-marked resource would look stale.
+	cms, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).List(ctx, v1.ListOptions{LabelSelector: inventoryIDKey})
+	if err != nil {
+		return fmt.Errorf("list cli-utils inventories: %w", err)
+	}
+	for _, cm := range cms.Items {
+		hub.inventories[cm.GetLabels()[inventoryIDKey]] = true
+	}
+
+	if unreadable := hub.probeUnlistedSpaces(objs); len(unreadable) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: cannot read space(s) %s; their resources were not checked\n", strings.Join(unreadable, ", "))
+	}
+
+	stale := findStaleResources(objs, hub)
+	if mapJSON && !staleCleanup {
+		return writeJSON(os.Stdout, "StaleResources", stale)
+	}
+	printStaleResources(os.Stdout, stale)
+	if !staleCleanup || len(stale) == 0 {
+		return nil
+	}
+
+	if ok, err := confirmChanges(staleCleanupChanges(stale), staleYes); err != nil || !ok {
+		return err
+	}
+	failed := 0
+	for _, s := range stale {
+		gvr, err := agent.APIVersionKindToGVR(s.APIVersion, s.Kind)
+		if err == nil {
+			_, err = dynClient.Resource(gvr).Namespace(s.Namespace).Patch(ctx, s.Name, types.MergePatchType, staleCleanupPatch(s), v1.PatchOptions{})
+		}
+		if err != nil {
+			fmt.Printf("  %s✗%s %s/%s/%s: %v\n", colorRed, colorReset, s.Kind, s.Namespace, s.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  %s✓%s %s/%s/%s\n", colorGreen, colorReset, s.Kind, s.Namespace, s.Name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d resource(s) could not be cleaned up", failed, len(stale))
+	}
+	return nil
+}
+
+// loadHubInventory lists every unit in every space of the current org.
+func loadHubInventory() (hubInventory, error) {
+	hub := hubInventory{units: map[string]map[string]bool{}, unitIDs: map[string]bool{}, inventories: map[string]bool{}, deletedSpaces: map[string]bool{}}
+	spaces, err := listSpaceSlugs()
+	if err != nil {
+		return hub, err
+	}
+	for _, space := range spaces {
+		units, err := loadUnitsForSpace(space)
+		if err != nil {
+			return hub, fmt.Errorf("list units in space %s: %w", space, err)
+		}
+		hub.units[space] = map[string]bool{}
+		for _, u := range units {
+			hub.units[space][u.Unit.Slug] = true
+			if u.Unit.UnitID != "" {
+				hub.unitIDs[u.Unit.UnitID] = true
+			}
+		}
+	}
+	return hub, nil
+}
+
+// probeUnlistedSpaces looks up each space named by objs that the space list
+// did not return, recording the ones cub reports as not found in
+// deletedSpaces. It returns the spaces that could not be confirmed either
+// way, sorted.
+func (hub *hubInventory) probeUnlistedSpaces(objs []unstructured.Unstructured) []string {
+	probed := map[string]bool{}
+	var unreadable []string
+	for i := range objs {
+		space := agent.ConfigHubSpace(&objs[i])
+		if space == "" || probed[space] {
+			continue
+		}
+		if _, listed := hub.units[space]; listed {
+			continue
+		}
+		probed[space] = true
+		_, err := runCubCommand("space", "get", space, "--json", "--quiet")
+		switch {
+		case cubNotFound(err):
+			hub.deletedSpaces[space] = true
+		default:
+			// Found but not listed, or not readable: units unknown
+			unreadable = append(unreadable, space)
+		}
+	}
+	sort.Strings(unreadable)
+	return unreadable
+}
+
+// cubNotFound reports whether a failed cub command said the object does
+// not exist, as opposed to failing for access or connectivity.
+func cubNotFound(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	stderr := strings.ToLower(string(exitErr.Stderr))
+	return strings.Contains(stderr, "not found") || strings.Contains(stderr, "does not exist")
+}
+
+// findStaleResources returns the resources whose ConfigHub markers point at
+// units or inventories missing from hub, sorted by namespace, kind and name.
+func findStaleResources(objs []unstructured.Unstructured, hub hubInventory) []StaleResource {
+	stale := []StaleResource{}
+	for i := range objs {
+		obj := &objs[i]
+		labels, annotations := obj.GetLabels(), obj.GetAnnotations()
+		var markers, reasons []string
+
+		slug, space := labels[unitSlugKey], agent.ConfigHubSpace(obj)
+		if slug == "" {
+			slug = annotations[unitSlugKey]
+		}
+		if slug != "" {
+			if reason := missingUnitReason(hub, space, slug); reason != "" {
+				reasons = append(reasons, reason)
+				if _, ok := labels[unitSlugKey]; ok {
+					markers = append(markers, "label:"+unitSlugKey)
+				}
+				if _, ok := annotations[unitSlugKey]; ok {
+					markers = append(markers, "annotation:"+unitSlugKey)
+				}
+			}
+		}
+
+		if inv := annotations[owningInventoryKey]; inv != "" && !hub.unitIDs[inv] && !hub.inventories[inv] {
+			reasons = append(reasons, "inventory "+inv+" not found")
+			markers = append(markers, "annotation:"+owningInventoryKey)
+			if _, ok := labels[inventoryIDKey]; ok {
+				markers = append(markers, "label:"+inventoryIDKey)
+			}
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+		stale = append(stale, StaleResource{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+			Markers:    markers,
+			Reasons:    reasons,
+		})
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		a, b := stale[i], stale[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return stale
+}
+
+// missingUnitReason explains why unit slug in space no longer exists, or
+// returns "" when it does or cannot be told. An empty space matches the
+// slug in any space.
+func missingUnitReason(hub hubInventory, space, slug string) string {
+	if space != "" {
+		units, ok := hub.units[space]
+		switch {
+		case !ok && hub.deletedSpaces[space]:
+			return "space " + space + " no longer exists"
+		case !ok:
+			return "" // Not readable, see probeUnlistedSpaces
+		case !units[slug]:
+			return "unit " + space + "/" + slug + " no longer exists"
+		}
+		return ""
+	}
+	for _, units := range hub.units {
+		if units[slug] {
+			return ""
+		}
+	}
+	return "no unit " + slug + " in any space"
+}
+
+// staleCleanupPatch is a merge patch removing a stale resource's markers.
+func staleCleanupPatch(s StaleResource) []byte {
+	meta := map[string]map[string]interface{}{}
+	for _, m := range s.Markers {
+		where, key, _ := strings.Cut(m, ":")
+		field := where + "s" // labels, annotations
+		if meta[field] == nil {
+			meta[field] = map[string]interface{}{}
+		}
+		meta[field][key] = nil
+	}
+	patch, _ := json.Marshal(map[string]interface{}{"metadata": meta})
+	return patch
+}
+
+// staleCleanupChanges lists the markers --cleanup removes from each resource.
+func staleCleanupChanges(stale []StaleResource) []plannedChange {
+	changes := make([]plannedChange, 0, len(stale))
+	for _, s := range stale {
+		changes = append(changes, plannedChange{
+			Action:  "remove markers",
+			Target:  s.Kind + " " + s.Namespace + "/" + s.Name,
+			Detail:  strings.Join(s.Markers, ", "),
+			Cluster: true,
+		})
+	}
+	return changes
+}
+
+func printStaleResources(w io.Writer, stale []StaleResource) {
+	if len(stale) == 0 {
+		fmt.Fprintln(w, "No stale ConfigHub markers found.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tKIND\tNAME\tREASON")
+	for _, s := range stale {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", orDash(s.Namespace), s.Kind, s.Name, strings.Join(s.Reasons, "; "))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d resource(s) carry ConfigHub markers for units or inventories that no longer exist.\n", len(stale))
+	if !staleCleanup {
+		fmt.Fprintf(w, "%sRemove the markers (resources keep running): cub-scout map stale --cleanup --read-only=false%s\n", colorDim, colorReset)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func staleTestObject(kind, name string, labels, annotations map[string]string) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "apps/v1", "kind": kind}}
+	u.SetNamespace("prod")
+	u.SetName(name)
+	u.SetLabels(labels)
+	u.SetAnnotations(annotations)
+	return u
+}
+
+func TestFindStaleResources(t *testing.T) {
+	hub := hubInventory{
+		units:         map[string]map[string]bool{"prod": {"api": true}, "dev": {"web": true}},
+		unitIDs:       map[string]bool{"0b9e-unit": true},
+		inventories:   map[string]bool{"kpt-inv": true},
+		deletedSpaces: map[string]bool{"legacy": true},
+	}
+	objs := []unstructured.Unstructured{
+		staleTestObject("Deployment", "api", map[string]string{unitSlugKey: "api"}, map[string]string{spaceNameKey: "prod", owningInventoryKey: "0b9e-unit"}),
+		staleTestObject("Deployment", "web", map[string]string{unitSlugKey: "web"}, nil), // found in dev
+		staleTestObject("Deployment", "jobs", map[string]string{unitSlugKey: "jobs"}, map[string]string{spaceNameKey: "prod"}),
+		staleTestObject("Deployment", "old", nil, map[string]string{unitSlugKey: "old", spaceNameKey: "legacy"}),
+		staleTestObject("Deployment", "ghost", map[string]string{unitSlugKey: "ghost", inventoryIDKey: "gone"}, map[string]string{owningInventoryKey: "gone"}),
+		staleTestObject("Deployment", "kpt", nil, map[string]string{owningInventoryKey: "kpt-inv"}),
+		staleTestObject("Deployment", "plain", map[string]string{"app": "plain"}, nil),
+		// In a space the user cannot read
+		staleTestObject("Deployment", "private", map[string]string{unitSlugKey: "private"}, map[string]string{spaceNameKey: "secret-team"}),
+	}
+
+	stale := findStaleResources(objs, hub)
+	got := map[string]StaleResource{}
+	for _, s := range stale {
+		got[s.Name] = s
+	}
+	if len(stale) != 3 {
+		t.Fatalf("got %d stale resources, want 3: %+v", len(stale), stale)
+	}
+
+	tests := []struct {
+		name    string
+		reasons string
+		markers string
+	}{
+		{"jobs", "unit prod/jobs no longer exists", "label:" + unitSlugKey},
+		{"old", "space legacy no longer exists", "annotation:" + unitSlugKey},
+		{"ghost", "no unit ghost in any space; inventory gone not found", "label:" + unitSlugKey + " annotation:" + owningInventoryKey + " label:" + inventoryIDKey},
+	}
+	for _, tt := range tests {
+		s, ok := got[tt.name]
+		if !ok {
+			t.Errorf("%s not reported stale", tt.name)
+			continue
+		}
+		if r := strings.Join(s.Reasons, "; "); r != tt.reasons {
+			t.Errorf("%s reasons = %q, want %q", tt.name, r, tt.reasons)
+		}
+		if m := strings.Join(s.Markers, " "); m != tt.markers {
+			t.Errorf("%s markers = %q, want %q", tt.name, m, tt.markers)
+		}
+	}
+	if stale[0].Name != "ghost" || stale[2].Name != "old" {
+		t.Errorf("not sorted by name: %s, %s, %s", stale[0].Name, stale[1].Name, stale[2].Name)
+	}
+}
+
+func TestProbeUnlistedSpaces(t *testing.T) {
+	restore := cubExec
+	defer func() { cubExec = restore }()
+	cubCache.Purge()
+	cubExec = func(args ...string) ([]byte, error) {
+		switch args[2] {
+		case "legacy":
+			return nil, &exec.ExitError{Stderr: []byte("Error: space legacy not found")}
+		case "secret-team":
+			return nil, &exec.ExitError{Stderr: []byte("Error: permission denied")}
+		}
+		return []byte(`{}`), nil
+	}
+
+	hub := hubInventory{units: map[string]map[string]bool{"prod": {}}, deletedSpaces: map[string]bool{}}
+	objs := []unstructured.Unstructured{
+		staleTestObject("Deployment", "a", nil, map[string]string{spaceNameKey: "prod"}),
+		staleTestObject("Deployment", "b", nil, map[string]string{spaceNameKey: "legacy"}),
+		staleTestObject("Deployment", "c", map[string]string{spaceNameKey: "secret-team"}, nil),
+		staleTestObject("Deployment", "d", nil, map[string]string{spaceNameKey: "unlisted"}),
+	}
+	unreadable := hub.probeUnlistedSpaces(objs)
+	if strings.Join(unreadable, ",") != "secret-team,unlisted" {
+		t.Errorf("unreadable = %v", unreadable)
+	}
+	if len(hub.deletedSpaces) != 1 || !hub.deletedSpaces["legacy"] {
+		t.Errorf("deletedSpaces = %v, want only legacy", hub.deletedSpaces)
+	}
+}
+
+func TestStaleCleanupPatch(t *testing.T) {
+	patch := staleCleanupPatch(StaleResource{Markers: []string{"label:" + unitSlugKey, "annotation:" + owningInventoryKey}})
+	var got map[string]map[string]map[string]interface{}
+	if err := json.Unmarshal(patch, &got); err != nil {
+		t.Fatal(err)
+	}
+	meta := got["metadata"]
+	if v, ok := meta["labels"][unitSlugKey]; !ok || v != nil {
+		t.Errorf("label not removed: %s", patch)
+	}
+	if v, ok := meta["annotations"][owningInventoryKey]; !ok || v != nil {
+		t.Errorf("annotation not removed: %s", patch)
+	}
+}
+
+func TestPrintStaleResources(t *testing.T) {
+	var buf bytes.Buffer
+	printStaleResources(&buf, nil)
+	if !strings.Contains(buf.String(), "No stale ConfigHub markers") {
+		t.Errorf("empty output = %q", buf.String())
+	}
+
+	buf.Reset()
+	printStaleResources(&buf, []StaleResource{{Kind: "Deployment", Namespace: "prod", Name: "jobs", Reasons: []string{"unit prod/jobs no longer exists"}}})
+	for _, want := range []string{"unit prod/jobs no longer exists", "1 resource(s)", "map stale --cleanup"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
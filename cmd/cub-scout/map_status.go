@@ -0,0 +1,173 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+var mapStatusWide bool
+
+func init() {
+	mapStatusCmd.Flags().BoolVar(&mapStatusWide, "wide", false, "Break the summary down per deployer type, owner and namespace")
+}
+
+// StatusSummary is the cluster health summary behind map status. Its JSON
+// form is meant for CI smoke tests and MOTD banners, so slices are always
+// present and sorted.
+type StatusSummary struct {
+	Healthy   bool        `json:"healthy"`
+	Problems  int         `json:"problems"`
+	Deployers StatusCount `json:"deployers"`
+	Workloads StatusCount `json:"workloads"`
+	// ByDeployer counts deployers (Kustomizations, HelmReleases, Argo CD
+	// Applications) per kind
+	ByDeployer []DeployerStatus `json:"byDeployer"`
+	// ByOwner counts workloads per owner (Flux, ArgoCD, Helm, ..., Native)
+	ByOwner     []OwnerStatus     `json:"byOwner"`
+	ByNamespace []NamespaceStatus `json:"byNamespace"`
+}
+
+// StatusCount is how many of a set of resources are ready.
+type StatusCount struct {
+	Ready int `json:"ready"`
+	Total int `json:"total"`
+}
+
+// DeployerStatus is the readiness of one kind of deployer.
+type DeployerStatus struct {
+	Kind  string `json:"kind"`
+	Owner string `json:"owner"`
+	Ready int    `json:"ready"`
+	Total int    `json:"total"`
+}
+
+// OwnerStatus is the readiness of the workloads one owner manages.
+type OwnerStatus struct {
+	Owner string `json:"owner"`
+	Ready int    `json:"ready"`
+	Total int    `json:"total"`
+}
+
+// NamespaceStatus is the readiness of the deployers and workloads in one
+// namespace.
+type NamespaceStatus struct {
+	Namespace string      `json:"namespace"`
+	Problems  int         `json:"problems"`
+	Deployers StatusCount `json:"deployers"`
+	Workloads StatusCount `json:"workloads"`
+}
+
+// statusEntry is one deployer or workload counted by map status.
+type statusEntry struct {
+	Kind      string
+	Owner     string
+	Namespace string
+	Ready     bool
+}
+
+func (c *StatusCount) add(ready bool) {
+	c.Total++
+	if ready {
+		c.Ready++
+	}
+}
+
+// buildStatusSummary tallies deployers and workloads overall, per deployer
+// kind, per workload owner and per namespace.
+func buildStatusSummary(deployers, workloads []statusEntry) StatusSummary {
+	s := StatusSummary{ByDeployer: []DeployerStatus{}, ByOwner: []OwnerStatus{}, ByNamespace: []NamespaceStatus{}}
+	byKind := map[string]*DeployerStatus{}
+	byOwner := map[string]*OwnerStatus{}
+	byNamespace := map[string]*NamespaceStatus{}
+	namespace := func(ns string) *NamespaceStatus {
+		if byNamespace[ns] == nil {
+			byNamespace[ns] = &NamespaceStatus{Namespace: ns}
+		}
+		return byNamespace[ns]
+	}
+
+	for _, d := range deployers {
+		s.Deployers.add(d.Ready)
+		namespace(d.Namespace).Deployers.add(d.Ready)
+		if byKind[d.Kind] == nil {
+			byKind[d.Kind] = &DeployerStatus{Kind: d.Kind, Owner: d.Owner}
+		}
+		byKind[d.Kind].Total++
+		if d.Ready {
+			byKind[d.Kind].Ready++
+		}
+	}
+	for _, w := range workloads {
+		s.Workloads.add(w.Ready)
+		namespace(w.Namespace).Workloads.add(w.Ready)
+		if byOwner[w.Owner] == nil {
+			byOwner[w.Owner] = &OwnerStatus{Owner: w.Owner}
+		}
+		byOwner[w.Owner].Total++
+		if w.Ready {
+			byOwner[w.Owner].Ready++
+		}
+	}
+
+	for _, d := range byKind {
+		s.ByDeployer = append(s.ByDeployer, *d)
+	}
+	sort.Slice(s.ByDeployer, func(i, j int) bool { return s.ByDeployer[i].Kind < s.ByDeployer[j].Kind })
+	for _, o := range byOwner {
+		s.ByOwner = append(s.ByOwner, *o)
+	}
+	sort.Slice(s.ByOwner, func(i, j int) bool { return s.ByOwner[i].Owner < s.ByOwner[j].Owner })
+	for _, n := range byNamespace {
+		n.Problems = (n.Deployers.Total - n.Deployers.Ready) + (n.Workloads.Total - n.Workloads.Ready)
+		s.ByNamespace = append(s.ByNamespace, *n)
+	}
+	sort.Slice(s.ByNamespace, func(i, j int) bool { return s.ByNamespace[i].Namespace < s.ByNamespace[j].Namespace })
+
+	s.Problems = (s.Deployers.Total - s.Deployers.Ready) + (s.Workloads.Total - s.Workloads.Ready)
+	s.Healthy = s.Problems == 0
+	return s
+}
+
+// printStatusLine prints the one-line summary.
+func printStatusLine(w io.Writer, s StatusSummary) {
+	if s.Healthy {
+		fmt.Fprintf(w, "✓ healthy: %d/%d deployers, %d/%d workloads\n",
+			s.Deployers.Ready, s.Deployers.Total, s.Workloads.Ready, s.Workloads.Total)
+		return
+	}
+	fmt.Fprintf(w, "✗ %d problem(s): %d/%d deployers, %d/%d workloads\n",
+		s.Problems, s.Deployers.Ready, s.Deployers.Total, s.Workloads.Ready, s.Workloads.Total)
+}
+
+// printStatusWide prints the one-line summary followed by the per deployer,
+// per owner and per namespace breakdown.
+func printStatusWide(w io.Writer, s StatusSummary) {
+	printStatusLine(w, s)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(s.ByDeployer) > 0 {
+		fmt.Fprintln(tw, "\nDEPLOYER\tOWNER\tREADY")
+		for _, d := range s.ByDeployer {
+			fmt.Fprintf(tw, "%s\t%s\t%d/%d\n", d.Kind, d.Owner, d.Ready, d.Total)
+		}
+	}
+	if len(s.ByOwner) > 0 {
+		fmt.Fprintln(tw, "\nWORKLOAD OWNER\t\tREADY")
+		for _, o := range s.ByOwner {
+			fmt.Fprintf(tw, "%s\t\t%d/%d\n", o.Owner, o.Ready, o.Total)
+		}
+	}
+	if len(s.ByNamespace) > 0 {
+		fmt.Fprintln(tw, "\nNAMESPACE\tDEPLOYERS\tWORKLOADS\tPROBLEMS")
+		for _, n := range s.ByNamespace {
+			fmt.Fprintf(tw, "%s\t%d/%d\t%d/%d\t%d\n", n.Namespace,
+				n.Deployers.Ready, n.Deployers.Total, n.Workloads.Ready, n.Workloads.Total, n.Problems)
+		}
+	}
+	tw.Flush()
+}
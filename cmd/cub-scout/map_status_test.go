@@ -0,0 +1,66 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuildStatusSummary(t *testing.T) {
+	s := buildStatusSummary(
+		[]statusEntry{
+			{"Kustomization", "Flux", "flux-system", true},
+			{"Kustomization", "Flux", "flux-system", false},
+			{"Application", "ArgoCD", "argocd", true},
+		},
+		[]statusEntry{
+			{"Deployment", "Flux", "payments", true},
+			{"Deployment", "Flux", "payments", false},
+			{"Deployment", "Native", "default", true},
+		},
+	)
+
+	if s.Healthy || s.Problems != 2 || s.Deployers != (StatusCount{2, 3}) || s.Workloads != (StatusCount{2, 3}) {
+		t.Errorf("totals = %+v", s)
+	}
+	if len(s.ByDeployer) != 2 || s.ByDeployer[0] != (DeployerStatus{"Application", "ArgoCD", 1, 1}) || s.ByDeployer[1] != (DeployerStatus{"Kustomization", "Flux", 1, 2}) {
+		t.Errorf("byDeployer = %+v", s.ByDeployer)
+	}
+	if len(s.ByOwner) != 2 || s.ByOwner[0] != (OwnerStatus{"Flux", 1, 2}) || s.ByOwner[1] != (OwnerStatus{"Native", 1, 1}) {
+		t.Errorf("byOwner = %+v", s.ByOwner)
+	}
+	var names []string
+	for _, n := range s.ByNamespace {
+		names = append(names, n.Namespace)
+	}
+	if strings.Join(names, ",") != "argocd,default,flux-system,payments" {
+		t.Errorf("namespaces = %v", names)
+	}
+	if s.ByNamespace[3].Problems != 1 || s.ByNamespace[3].Workloads != (StatusCount{1, 2}) {
+		t.Errorf("payments = %+v", s.ByNamespace[3])
+	}
+
+	var buf bytes.Buffer
+	printStatusWide(&buf, s)
+	for _, want := range []string{"✗ 2 problem(s): 2/3 deployers, 2/3 workloads", "Kustomization  Flux", "1/2", "payments"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("wide output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestStatusSummaryEmptyJSON(t *testing.T) {
+	// Consumers index into the breakdowns, so they are [] rather than null
+	data, err := json.Marshal(buildStatusSummary(nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"healthy":true,"problems":0,"deployers":{"ready":0,"total":0},"workloads":{"ready":0,"total":0},"byDeployer":[],"byOwner":[],"byNamespace":[]}`
+	if string(data) != want {
+		t.Errorf("got %s", data)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	terraformStateFile string
+	terraformDir       string
+	terraformNamespace string
+)
+
+var mapTerraformCmd = &cobra.Command{
+	Use:     "terraform",
+	Aliases: []string{"tf"},
+	Short:   "Link Terraform-managed resources to their state addresses",
+	Long: `Read Terraform state and link each Kubernetes object it manages to its
+resource address and module, then report where state and cluster disagree:
+
+  - in state, missing in cluster: deleted out of band, or the state is for
+    another cluster
+  - in cluster, missing in state: the object looks Terraform-managed (a
+    Terraform field manager or app.terraform.io markers) but no state
+    resource claims it, e.g. after 'terraform state rm'
+
+Objects of the kubernetes provider are read: typed resources such as
+kubernetes_deployment_v1, and kubernetes_manifest. Resources that only
+patch objects owned by something else (kubernetes_labels,
+kubernetes_annotations, kubernetes_env) are not ownership and are skipped.
+
+State comes from a file (--state, "-" for stdin), or from the backend a
+Terraform working directory is configured for (--dir, which runs
+'terraform state pull' there and needs the terraform CLI and backend
+credentials).
+
+Examples:
+  cub-scout map terraform --state terraform.tfstate
+  cub-scout map terraform --dir infra/k8s
+  terraform state pull | cub-scout map terraform --state -
+  cub-scout map terraform --dir infra/k8s --namespace prod --json`,
+	Args: cobra.NoArgs,
+	RunE: runMapTerraform,
+}
+
+func init() {
+	mapTerraformCmd.Flags().StringVar(&terraformStateFile, "state", "", `Terraform state file ("-" for stdin)`)
+	mapTerraformCmd.Flags().StringVar(&terraformDir, "dir", "", "Terraform working directory to pull state from (runs 'terraform state pull')")
+	mapTerraformCmd.Flags().StringVarP(&terraformNamespace, "namespace", "n", "", "Only check this namespace")
+	_ = mapTerraformCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	mapTerraformCmd.MarkFlagsMutuallyExclusive("state", "dir")
+	mapTerraformCmd.MarkFlagsOneRequired("state", "dir")
+
+	mapCmd.AddCommand(mapTerraformCmd)
+}
+
+func runMapTerraform(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	data, err := readTerraformState(terraformStateFile, terraformDir)
+	if err != nil {
+		return err
+	}
+	state, err := agent.ParseTerraformState(data)
+	if err != nil {
+		return err
+	}
+	if terraformNamespace != "" {
+		state = filterTerraformState(state, terraformNamespace)
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	live, listed := listTerraformTargets(ctx, dynClient, state, terraformNamespace)
+	c := agent.CorrelateTerraformState(state, live, listed)
+	if mapJSON {
+		return writeJSON(os.Stdout, "TerraformCorrelation", c)
+	}
+	printTerraformCorrelation(os.Stdout, c)
+	return nil
+}
+
+// readTerraformState reads state from file ("-" for stdin), or pulls it
+// from dir's configured backend.
+func readTerraformState(file, dir string) ([]byte, error) {
+	switch {
+	case file == "-":
+		return io.ReadAll(os.Stdin)
+	case file != "":
+		return os.ReadFile(file)
+	}
+	var stderr bytes.Buffer
+	pull := exec.Command("terraform", "-chdir="+dir, "state", "pull")
+	pull.Stderr = &stderr
+	out, err := pull.Output()
+	if err != nil {
+		return nil, fmt.Errorf("terraform state pull in %s: %w: %s", dir, err, strings.TrimSpace(stderr.String()))
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, fmt.Errorf("terraform state pull in %s: no state (run terraform init, or nothing applied yet)", dir)
+	}
+	return out, nil
+}
+
+// filterTerraformState keeps the state resources in namespace;
+// cluster-scoped ones are dropped.
+func filterTerraformState(state []agent.TerraformResource, namespace string) []agent.TerraformResource {
+	var out []agent.TerraformResource
+	for _, r := range state {
+		if r.Namespace == namespace {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// listTerraformTargets lists every kind state may hold, returning the
+// objects and the kinds that could be listed.
+func listTerraformTargets(ctx context.Context, dynClient dynamic.Interface, state []agent.TerraformResource, namespace string) ([]unstructured.Unstructured, []string) {
+	var live []unstructured.Unstructured
+	var listed []string
+	for _, t := range agent.TerraformTargets(state) {
+		l, err := dynClient.Resource(t.GVR).Namespace(namespace).List(ctx, v1.ListOptions{})
+		if err != nil {
+			continue // Not installed, not permitted, or cluster-scoped with --namespace
+		}
+		for i := range l.Items {
+			l.Items[i].SetKind(t.Kind)
+		}
+		live = append(live, l.Items...)
+		listed = append(listed, t.Kind)
+	}
+	return live, listed
+}
+
+func printTerraformCorrelation(w io.Writer, c agent.TerraformCorrelation) {
+	fmt.Fprintln(w, "TERRAFORM STATE")
+	fmt.Fprintln(w)
+	if len(c.Linked) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ADDRESS\tKIND\tNAMESPACE\tNAME\tMARKERS")
+		for _, l := range c.Linked {
+			markers := "✓"
+			if !l.Detected {
+				markers = "none"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", l.Address, l.Kind, orDash(l.Namespace), l.Name, markers)
+		}
+		tw.Flush()
+	}
+
+	if len(c.MissingInCluster) > 0 {
+		fmt.Fprintf(w, "\n%sIN STATE, MISSING IN CLUSTER:%s\n", colorRed, colorReset)
+		for _, r := range c.MissingInCluster {
+			fmt.Fprintf(w, "  ✗ %-48s %s/%s/%s\n", r.Address, r.Kind, orDash(r.Namespace), r.Name)
+		}
+	}
+	if len(c.MissingInState) > 0 {
+		fmt.Fprintf(w, "\n%sIN CLUSTER, MISSING IN STATE:%s\n", colorYellow, colorReset)
+		for _, o := range c.MissingInState {
+			fmt.Fprintf(w, "  ⚠ %s/%s/%s\n", o.Kind, orDash(o.Namespace), o.Name)
+		}
+	}
+	if len(c.Unchecked) > 0 {
+		fmt.Fprintf(w, "\n%s%d state resource(s) of kinds that couldn't be listed were not checked:%s\n", colorDim, len(c.Unchecked), colorReset)
+		for _, r := range c.Unchecked {
+			fmt.Fprintf(w, "%s  %s (%s)%s\n", colorDim, r.Address, r.Kind, colorReset)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d linked, %d missing in cluster, %d missing in state\n",
+		len(c.Linked), len(c.MissingInCluster), len(c.MissingInState))
+	if len(c.MissingInCluster) > 0 {
+		fmt.Fprintf(w, "%s→ Recreate with 'terraform apply', or drop from state: terraform state rm <address>%s\n", colorDim, colorReset)
+	}
+	if len(c.MissingInState) > 0 {
+		fmt.Fprintf(w, "%s→ Adopt with 'terraform import <address> <id>', or find the state that applied them%s\n", colorDim, colorReset)
+	}
+}
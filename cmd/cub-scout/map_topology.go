@@ -0,0 +1,288 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	deployersGraph       bool
+	deployersGraphFormat string
+)
+
+// graphFormats are the --graph-format values of 'map deployers --graph'.
+var graphFormats = []string{"table", "dot"}
+
+func init() {
+	mapDeployersCmd.Flags().BoolVar(&deployersGraph, "graph", false, "Show which sources each deployer consumes, with shared and orphaned sources")
+	mapDeployersCmd.Flags().StringVar(&deployersGraphFormat, "graph-format", "table", "Graph output: "+strings.Join(graphFormats, ", "))
+	_ = mapDeployersCmd.RegisterFlagCompletionFunc("graph-format", cobra.FixedCompletions(graphFormats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// SourceNode is a source in the deployer topology: a Flux source object, or
+// a repository URL an Argo CD Application reads that no Flux source covers.
+type SourceNode struct {
+	ID        string `json:"id"`   // Kind/namespace/name, or Repository/<url>
+	Kind      string `json:"kind"` // GitRepository, OCIRepository, HelmRepository, Bucket, Repository
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	URL       string `json:"url,omitempty"`
+	Consumers int    `json:"consumers"`
+	// Shared sources feed more than one deployer
+	Shared bool `json:"shared,omitempty"`
+	// Orphaned sources exist but no deployer consumes them
+	Orphaned bool `json:"orphaned,omitempty"`
+	// Missing sources are referenced by a deployer but not found
+	Missing bool `json:"missing,omitempty"`
+}
+
+// TopologyEdge is a deployer consuming a source.
+type TopologyEdge struct {
+	Source   string `json:"source"`   // SourceNode ID
+	Deployer string `json:"deployer"` // Kind/namespace/name
+}
+
+// SourceTopology is the source-to-deployer graph of a cluster.
+type SourceTopology struct {
+	Sources []SourceNode   `json:"sources"`
+	Edges   []TopologyEdge `json:"edges"`
+}
+
+// topologySourceGVRs are the Flux source kinds in the topology. OCIRepository
+// falls back to v1beta2 for Flux releases before 2.6.
+var topologySourceGVRs = [][]schema.GroupVersionResource{
+	{{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories"}},
+	{
+		{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "ocirepositories"},
+		{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "ocirepositories"},
+	},
+	{{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "helmrepositories"}},
+	{{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "buckets"}},
+}
+
+var topologyDeployerGVRs = []schema.GroupVersionResource{
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"},
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"},
+	{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+}
+
+func runMapDeployersGraph(ctx context.Context, dynClient dynamic.Interface) error {
+	if !contains(graphFormats, deployersGraphFormat) {
+		return fmt.Errorf("unknown --graph-format %q (want %s)", deployersGraphFormat, strings.Join(graphFormats, ", "))
+	}
+
+	var sources, deployers []unstructured.Unstructured
+	for _, versions := range topologySourceGVRs {
+		for _, gvr := range versions {
+			if l, err := dynClient.Resource(gvr).List(ctx, v1.ListOptions{}); err == nil {
+				sources = append(sources, l.Items...)
+				break
+			}
+		}
+	}
+	for _, gvr := range topologyDeployerGVRs {
+		if l, err := dynClient.Resource(gvr).List(ctx, v1.ListOptions{}); err == nil {
+			deployers = append(deployers, l.Items...)
+		}
+	}
+
+	topo := buildSourceTopology(sources, deployers)
+	switch {
+	case mapJSON:
+		return writeJSON(os.Stdout, "SourceTopology", topo)
+	case deployersGraphFormat == "dot":
+		printTopologyDOT(os.Stdout, topo)
+	default:
+		printTopologyTable(os.Stdout, topo)
+	}
+	return nil
+}
+
+// buildSourceTopology links each deployer to the sources it reads. Flux
+// deployers reference sources by sourceRef; Argo CD Applications by repo URL,
+// which is matched to a Flux source with the same URL when there is one.
+func buildSourceTopology(sources, deployers []unstructured.Unstructured) SourceTopology {
+	nodes := map[string]*SourceNode{}
+	byURL := map[string]string{}
+	for i := range sources {
+		src := &sources[i]
+		url, _, _ := unstructured.NestedString(src.Object, "spec", "url")
+		node := &SourceNode{Kind: src.GetKind(), Namespace: src.GetNamespace(), Name: src.GetName(), URL: url}
+		node.ID = node.Kind + "/" + node.Namespace + "/" + node.Name
+		nodes[node.ID] = node
+		if url != "" {
+			if _, ok := byURL[normalizeRepoURL(url)]; !ok {
+				byURL[normalizeRepoURL(url)] = node.ID
+			}
+		}
+	}
+
+	topo := SourceTopology{Sources: []SourceNode{}, Edges: []TopologyEdge{}}
+	seen := map[TopologyEdge]bool{}
+	link := func(sourceID string, deployer *unstructured.Unstructured) {
+		edge := TopologyEdge{Source: sourceID, Deployer: deployer.GetKind() + "/" + deployer.GetNamespace() + "/" + deployer.GetName()}
+		if seen[edge] {
+			return
+		}
+		seen[edge] = true
+		nodes[sourceID].Consumers++
+		topo.Edges = append(topo.Edges, edge)
+	}
+
+	for i := range deployers {
+		d := &deployers[i]
+		if d.GetKind() == "Application" {
+			for _, url := range argoRepoURLs(d) {
+				id, ok := byURL[normalizeRepoURL(url)]
+				if !ok {
+					id = "Repository/" + url
+					if nodes[id] == nil {
+						nodes[id] = &SourceNode{ID: id, Kind: "Repository", Name: url, URL: url}
+					}
+				}
+				link(id, d)
+			}
+			continue
+		}
+
+		ref, err := agent.FluxSourceRef(d)
+		if err != nil {
+			continue
+		}
+		id := ref.Kind + "/" + ref.Namespace + "/" + ref.Name
+		if nodes[id] == nil {
+			nodes[id] = &SourceNode{ID: id, Kind: ref.Kind, Namespace: ref.Namespace, Name: ref.Name, Missing: true}
+		}
+		link(id, d)
+	}
+
+	for _, node := range nodes {
+		node.Shared = node.Consumers > 1
+		node.Orphaned = node.Consumers == 0
+		topo.Sources = append(topo.Sources, *node)
+	}
+	sort.Slice(topo.Sources, func(i, j int) bool { return topo.Sources[i].ID < topo.Sources[j].ID })
+	sort.Slice(topo.Edges, func(i, j int) bool {
+		if topo.Edges[i].Source != topo.Edges[j].Source {
+			return topo.Edges[i].Source < topo.Edges[j].Source
+		}
+		return topo.Edges[i].Deployer < topo.Edges[j].Deployer
+	})
+	return topo
+}
+
+// argoRepoURLs returns the repo URLs of an Application's source or sources.
+func argoRepoURLs(app *unstructured.Unstructured) []string {
+	var urls []string
+	if url, _, _ := unstructured.NestedString(app.Object, "spec", "source", "repoURL"); url != "" {
+		urls = append(urls, url)
+	}
+	sources, _, _ := unstructured.NestedSlice(app.Object, "spec", "sources")
+	for _, s := range sources {
+		if m, ok := s.(map[string]interface{}); ok {
+			if url, _ := m["repoURL"].(string); url != "" {
+				urls = append(urls, url)
+			}
+		}
+	}
+	return urls
+}
+
+// normalizeRepoURL makes equivalent repo URLs compare equal: scheme, a
+// trailing slash and a .git suffix are ignored.
+func normalizeRepoURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	}
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	return url
+}
+
+func printTopologyTable(w io.Writer, topo SourceTopology) {
+	if len(topo.Sources) == 0 {
+		fmt.Fprintln(w, "No GitOps sources or deployers found.")
+		return
+	}
+
+	nodes := map[string]SourceNode{}
+	for _, s := range topo.Sources {
+		nodes[s.ID] = s
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\t\tDEPLOYER\tNOTE")
+	for _, e := range topo.Edges {
+		note := ""
+		switch src := nodes[e.Source]; {
+		case src.Missing:
+			note = "source not found"
+		case src.Shared:
+			note = fmt.Sprintf("shared (%d)", src.Consumers)
+		}
+		fmt.Fprintf(tw, "%s\t→\t%s\t%s\n", e.Source, e.Deployer, note)
+	}
+	for _, s := range topo.Sources {
+		if s.Orphaned {
+			fmt.Fprintf(tw, "%s\t→\t-\torphaned\n", s.ID)
+		}
+	}
+	tw.Flush()
+
+	var shared, orphaned, missing int
+	for _, s := range topo.Sources {
+		switch {
+		case s.Missing:
+			missing++
+		case s.Orphaned:
+			orphaned++
+		case s.Shared:
+			shared++
+		}
+	}
+	fmt.Fprintf(w, "\n%d sources, %d edges: %d shared, %d orphaned, %d missing\n",
+		len(topo.Sources), len(topo.Edges), shared, orphaned, missing)
+	if orphaned > 0 {
+		fmt.Fprintln(w, "Orphaned sources are still fetched by the source controller; delete them if unused.")
+	}
+}
+
+// printTopologyDOT renders the topology for Graphviz: shared sources are
+// filled yellow, orphaned sources grey and dashed, missing sources red.
+func printTopologyDOT(w io.Writer, topo SourceTopology) {
+	fmt.Fprintln(w, "digraph sources {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box, fontname=Helvetica];")
+	for _, s := range topo.Sources {
+		attrs := []string{"shape=cylinder"}
+		switch {
+		case s.Missing:
+			attrs = append(attrs, "color=red", "style=dashed", `xlabel="missing"`)
+		case s.Orphaned:
+			attrs = append(attrs, "color=gray", "style=dashed", `xlabel="orphaned"`)
+		case s.Shared:
+			attrs = append(attrs, "style=filled", `fillcolor="#fff3b0"`)
+		}
+		fmt.Fprintf(w, "  %q [%s];\n", s.ID, strings.Join(attrs, ", "))
+	}
+	for _, e := range topo.Edges {
+		fmt.Fprintf(w, "  %q -> %q;\n", e.Source, e.Deployer)
+	}
+	fmt.Fprintln(w, "}")
+}
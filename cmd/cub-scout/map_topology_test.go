@@ -0,0 +1,125 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestTopologyObject(kind, namespace, name string, spec map[string]interface{}) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{"kind": kind, "spec": spec}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func testTopology() SourceTopology {
+	sources := []unstructured.Unstructured{
+		newTestTopologyObject("GitRepository", "flux-system", "platform", map[string]interface{}{"url": "https://github.com/acme/platform.git"}),
+		newTestTopologyObject("HelmRepository", "flux-system", "bitnami", map[string]interface{}{"url": "https://charts.bitnami.com/bitnami"}),
+		newTestTopologyObject("GitRepository", "flux-system", "old", map[string]interface{}{"url": "https://github.com/acme/old"}),
+	}
+	deployers := []unstructured.Unstructured{
+		newTestTopologyObject("Kustomization", "flux-system", "infra", map[string]interface{}{
+			"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "platform"},
+		}),
+		newTestTopologyObject("Kustomization", "apps", "web", map[string]interface{}{
+			"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "platform", "namespace": "flux-system"},
+		}),
+		newTestTopologyObject("HelmRelease", "apps", "redis", map[string]interface{}{
+			"chart": map[string]interface{}{"spec": map[string]interface{}{
+				"sourceRef": map[string]interface{}{"kind": "HelmRepository", "name": "bitnami", "namespace": "flux-system"},
+			}},
+		}),
+		newTestTopologyObject("HelmRelease", "apps", "podinfo", map[string]interface{}{
+			"chartRef": map[string]interface{}{"kind": "OCIRepository", "name": "podinfo"},
+		}),
+		// Same repo as the platform GitRepository, different URL spelling
+		newTestTopologyObject("Application", "argocd", "guestbook", map[string]interface{}{
+			"source": map[string]interface{}{"repoURL": "https://github.com/acme/platform"},
+		}),
+		newTestTopologyObject("Application", "argocd", "multi", map[string]interface{}{
+			"sources": []interface{}{
+				map[string]interface{}{"repoURL": "https://github.com/acme/values"},
+				map[string]interface{}{"repoURL": "https://github.com/acme/values"},
+			},
+		}),
+	}
+	return buildSourceTopology(sources, deployers)
+}
+
+func TestBuildSourceTopology(t *testing.T) {
+	topo := testTopology()
+
+	nodes := map[string]SourceNode{}
+	for _, s := range topo.Sources {
+		nodes[s.ID] = s
+	}
+	tests := []struct {
+		id                        string
+		consumers                 int
+		shared, orphaned, missing bool
+	}{
+		{"GitRepository/flux-system/platform", 3, true, false, false},
+		{"HelmRepository/flux-system/bitnami", 1, false, false, false},
+		{"GitRepository/flux-system/old", 0, false, true, false},
+		{"OCIRepository/apps/podinfo", 1, false, false, true},
+		{"Repository/https://github.com/acme/values", 1, false, false, false},
+	}
+	for _, tt := range tests {
+		n, ok := nodes[tt.id]
+		if !ok {
+			t.Errorf("missing node %s", tt.id)
+			continue
+		}
+		if n.Consumers != tt.consumers || n.Shared != tt.shared || n.Orphaned != tt.orphaned || n.Missing != tt.missing {
+			t.Errorf("%s = %+v", tt.id, n)
+		}
+	}
+	if len(topo.Sources) != len(tests) {
+		t.Errorf("got %d sources, want %d", len(topo.Sources), len(tests))
+	}
+	if len(topo.Edges) != 6 {
+		t.Errorf("got %d edges, want 6 (duplicate Argo sources collapse)", len(topo.Edges))
+	}
+}
+
+func TestPrintTopology(t *testing.T) {
+	topo := testTopology()
+
+	var table bytes.Buffer
+	printTopologyTable(&table, topo)
+	var rows []string
+	for _, line := range strings.Split(table.String(), "\n") {
+		rows = append(rows, strings.Join(strings.Fields(line), " "))
+	}
+	normalized := strings.Join(rows, "\n")
+	for _, want := range []string{
+		"GitRepository/flux-system/platform → Kustomization/apps/web shared (3)",
+		"OCIRepository/apps/podinfo → HelmRelease/apps/podinfo source not found",
+		"GitRepository/flux-system/old → - orphaned",
+		"5 sources, 6 edges: 1 shared, 1 orphaned, 1 missing",
+	} {
+		if !strings.Contains(normalized, want) {
+			t.Errorf("table missing %q:\n%s", want, table.String())
+		}
+	}
+
+	var dot bytes.Buffer
+	printTopologyDOT(&dot, topo)
+	for _, want := range []string{
+		"digraph sources {",
+		`"GitRepository/flux-system/platform" [shape=cylinder, style=filled, fillcolor="#fff3b0"];`,
+		`"GitRepository/flux-system/old" [shape=cylinder, color=gray, style=dashed, xlabel="orphaned"];`,
+		`"HelmRepository/flux-system/bitnami" -> "HelmRelease/apps/redis";`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("dot missing %q:\n%s", want, dot.String())
+		}
+	}
+}
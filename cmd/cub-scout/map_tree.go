@@ -0,0 +1,349 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var mapTreeCmd = &cobra.Command{
+	Use:   "tree <workload>",
+	Short: "Show a workload's live Deployment → ReplicaSet → Pod tree",
+	Long: `Show the live tree of one workload, whoever owns it: the Deployment, its
+ReplicaSets and their Pods, with each Pod's node, IP and restarts.
+StatefulSets and DaemonSets list their Pods directly.
+
+The workload is kind/name (deployment/api, sts/db, ds/agent) or a bare
+Deployment name. Without --namespace every namespace is searched. Old
+ReplicaSets scaled to zero are hidden unless --verbose.
+
+Examples:
+  cub-scout map tree api --namespace payments
+  cub-scout map tree statefulset/postgres
+  cub-scout map tree deploy/api --namespace payments --json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeMapTreeWorkload,
+	RunE:              runMapTree,
+}
+
+func init() {
+	mapCmd.AddCommand(mapTreeCmd)
+	mapTreeCmd.Flags().StringVar(&mapNamespace, "namespace", "", "Namespace of the workload")
+	_ = mapTreeCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+}
+
+// LiveTree is a workload's live state: its ReplicaSets and Pods.
+type LiveTree struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Owner     string `json:"owner"`
+	ManagedBy string `json:"managedBy,omitempty"`
+	Ready     string `json:"ready"`
+	// ReplicaSets of a Deployment, newest revision first
+	ReplicaSets []LiveReplicaSet `json:"replicaSets,omitempty"`
+	// Pods owned by the workload directly (StatefulSets, DaemonSets)
+	Pods []LivePod `json:"pods,omitempty"`
+}
+
+// LiveReplicaSet is one ReplicaSet of a Deployment.
+type LiveReplicaSet struct {
+	Name     string    `json:"name"`
+	Revision int       `json:"revision,omitempty"`
+	Current  bool      `json:"current"`
+	Ready    string    `json:"ready"`
+	Pods     []LivePod `json:"pods"`
+}
+
+// LivePod is one Pod in a live tree.
+type LivePod struct {
+	Name     string `json:"name"`
+	Phase    string `json:"phase"`
+	Node     string `json:"node,omitempty"`
+	IP       string `json:"ip,omitempty"`
+	Restarts int64  `json:"restarts"`
+}
+
+func runMapTree(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	kind, name := "Deployment", args[0]
+	if k, n, ok := strings.Cut(args[0], "/"); ok {
+		kind, name = normalizeKind(k), n
+	}
+	if kind != "Deployment" && kind != "StatefulSet" && kind != "DaemonSet" {
+		return fmt.Errorf("map tree shows Deployments, StatefulSets and DaemonSets, not %s", kind)
+	}
+	gvr := kindToGVR(kind)
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+	loadDelegations(ctx, dynClient)
+
+	workload, err := findWorkload(ctx, dynClient, gvr, kind, name)
+	if err != nil {
+		return err
+	}
+	ns := workload.GetNamespace()
+
+	var replicaSets, pods []unstructured.Unstructured
+	if kind == "Deployment" {
+		l, err := dynClient.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}).Namespace(ns).List(ctx, v1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("list replicasets: %w", err)
+		}
+		replicaSets = l.Items
+	}
+	podList, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace(ns).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list pods: %w", err)
+	}
+	pods = podList.Items
+
+	tree := buildLiveTree(workload, replicaSets, pods)
+	if mapJSON {
+		return writeJSON(os.Stdout, "LiveTree", tree)
+	}
+
+	fmt.Printf("%s%s/%s%s in %s [%s%s%s] %s\n", colorBold, tree.Kind, tree.Name, colorReset, tree.Namespace,
+		getOwnerColor(tree.Owner), tree.Owner, colorReset, tree.Ready)
+	if tree.ManagedBy != "" && tree.ManagedBy != "-" {
+		fmt.Printf("%sManaged by %s%s\n", colorDim, tree.ManagedBy, colorReset)
+	}
+	printLiveTree(os.Stdout, tree, "", mapVerbose)
+	return nil
+}
+
+// findWorkload gets the workload in --namespace, or searches every
+// namespace for a single match.
+func findWorkload(ctx context.Context, dynClient dynamic.Interface, gvr schema.GroupVersionResource, kind, name string) (*unstructured.Unstructured, error) {
+	if mapNamespace != "" {
+		obj, err := dynClient.Resource(gvr).Namespace(mapNamespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get %s %s/%s: %w", kind, mapNamespace, name, err)
+		}
+		return obj, nil
+	}
+
+	l, err := dynClient.Resource(gvr).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", gvr.Resource, err)
+	}
+	var matches []unstructured.Unstructured
+	for _, item := range l.Items {
+		if item.GetName() == name {
+			matches = append(matches, item)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%s %s not found in any namespace", kind, name)
+	case 1:
+		return &matches[0], nil
+	}
+	var nss []string
+	for _, m := range matches {
+		nss = append(nss, m.GetNamespace())
+	}
+	return nil, fmt.Errorf("%s %s exists in several namespaces (%s); pass --namespace", kind, name, strings.Join(nss, ", "))
+}
+
+// buildLiveTree builds a workload's tree from the ReplicaSets and Pods in
+// its namespace, matching ownerReferences by UID (or by name when UIDs are
+// missing).
+func buildLiveTree(workload *unstructured.Unstructured, replicaSets, pods []unstructured.Unstructured) LiveTree {
+	owner, managedBy := detectOwnership(workload)
+	tree := LiveTree{
+		Kind:      workload.GetKind(),
+		Name:      workload.GetName(),
+		Namespace: workload.GetNamespace(),
+		Owner:     owner,
+		ManagedBy: managedBy,
+		Ready:     workloadReadyString(workload),
+	}
+
+	podsOf := func(parent *unstructured.Unstructured) []LivePod {
+		out := []LivePod{}
+		for i := range pods {
+			if ownedBy(&pods[i], parent) {
+				out = append(out, livePod(&pods[i]))
+			}
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+		return out
+	}
+
+	if tree.Kind != "Deployment" {
+		tree.Pods = podsOf(workload)
+		return tree
+	}
+
+	current := workload.GetAnnotations()["deployment.kubernetes.io/revision"]
+	for i := range replicaSets {
+		rs := &replicaSets[i]
+		if !ownedBy(rs, workload) {
+			continue
+		}
+		revision := rs.GetAnnotations()["deployment.kubernetes.io/revision"]
+		n, _ := strconv.Atoi(revision)
+		desired, _, _ := unstructured.NestedInt64(rs.Object, "spec", "replicas")
+		ready, _, _ := unstructured.NestedInt64(rs.Object, "status", "readyReplicas")
+		tree.ReplicaSets = append(tree.ReplicaSets, LiveReplicaSet{
+			Name:     rs.GetName(),
+			Revision: n,
+			Current:  revision == current && (current != "" || desired > 0),
+			Ready:    fmt.Sprintf("%d/%d", ready, desired),
+			Pods:     podsOf(rs),
+		})
+	}
+	sort.Slice(tree.ReplicaSets, func(i, j int) bool {
+		a, b := tree.ReplicaSets[i], tree.ReplicaSets[j]
+		if a.Revision != b.Revision {
+			return a.Revision > b.Revision
+		}
+		return a.Name < b.Name
+	})
+	return tree
+}
+
+// ownedBy reports whether obj has an ownerReference to parent.
+func ownedBy(obj, parent *unstructured.Unstructured) bool {
+	if obj.GetNamespace() != parent.GetNamespace() {
+		return false
+	}
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID != "" && parent.GetUID() != "" {
+			if ref.UID == parent.GetUID() {
+				return true
+			}
+			continue
+		}
+		if ref.Kind == parent.GetKind() && ref.Name == parent.GetName() {
+			return true
+		}
+	}
+	return false
+}
+
+func livePod(pod *unstructured.Unstructured) LivePod {
+	p := LivePod{Name: pod.GetName(), Phase: "Unknown"}
+	if phase, ok, _ := unstructured.NestedString(pod.Object, "status", "phase"); ok {
+		p.Phase = phase
+	}
+	p.Node, _, _ = unstructured.NestedString(pod.Object, "spec", "nodeName")
+	p.IP, _, _ = unstructured.NestedString(pod.Object, "status", "podIP")
+	statuses, _, _ := unstructured.NestedSlice(pod.Object, "status", "containerStatuses")
+	for _, s := range statuses {
+		if cs, ok := s.(map[string]interface{}); ok {
+			n, _, _ := unstructured.NestedInt64(cs, "restartCount")
+			p.Restarts += n
+		}
+	}
+	return p
+}
+
+// workloadReadyString returns "ready/desired ready" for a workload.
+func workloadReadyString(obj *unstructured.Unstructured) string {
+	if obj.GetKind() == "DaemonSet" {
+		desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberReady")
+		return fmt.Sprintf("%d/%d ready", ready, desired)
+	}
+	desired, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		desired = 1
+	}
+	ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	return fmt.Sprintf("%d/%d ready", ready, desired)
+}
+
+// printLiveTree prints a tree's ReplicaSets and Pods below its workload
+// line, each line starting with prefix. Old ReplicaSets scaled to zero are
+// left out unless all is set.
+func printLiveTree(w io.Writer, tree LiveTree, prefix string, all bool) {
+	if tree.Kind != "Deployment" {
+		printLivePods(w, tree.Pods, prefix)
+		return
+	}
+
+	var shown []LiveReplicaSet
+	for _, rs := range tree.ReplicaSets {
+		if all || rs.Current || len(rs.Pods) > 0 {
+			shown = append(shown, rs)
+		}
+	}
+	for i, rs := range shown {
+		connector, childPrefix := "├─", prefix+"│  "
+		if i == len(shown)-1 {
+			connector, childPrefix = "└─", prefix+"   "
+		}
+		note := ""
+		if !rs.Current {
+			note = ", old"
+		}
+		fmt.Fprintf(w, "%s%s ReplicaSet/%s (%s ready%s)\n", prefix, connector, rs.Name, rs.Ready, note)
+		printLivePods(w, rs.Pods, childPrefix)
+	}
+}
+
+func printLivePods(w io.Writer, pods []LivePod, prefix string) {
+	for i, pod := range pods {
+		connector := "├─"
+		if i == len(pods)-1 {
+			connector = "└─"
+		}
+		icon := "✓"
+		if pod.Phase != "Running" && pod.Phase != "Succeeded" {
+			icon = "✗"
+		}
+		details := []string{pod.Phase}
+		if pod.Node != "" {
+			details = append(details, pod.Node)
+		}
+		if pod.IP != "" {
+			details = append(details, pod.IP)
+		}
+		switch pod.Restarts {
+		case 0:
+		case 1:
+			details = append(details, "1 restart")
+		default:
+			details = append(details, fmt.Sprintf("%d restarts", pod.Restarts))
+		}
+		fmt.Fprintf(w, "%s%s %s Pod/%s (%s)\n", prefix, connector, icon, pod.Name, strings.Join(details, ", "))
+	}
+}
+
+// completeMapTreeWorkload completes map tree's workload: a bare Deployment
+// name, or kind/name for any workload kind, in the current namespace.
+func completeMapTreeWorkload(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if kind, _, ok := strings.Cut(toComplete, "/"); ok {
+		var names []string
+		for _, name := range completeResourceNames(cmd, normalizeKind(kind)) {
+			names = append(names, kind+"/"+name)
+		}
+		return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+	return filterPrefix(completeResourceNames(cmd, "Deployment"), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
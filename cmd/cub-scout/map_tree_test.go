@@ -0,0 +1,94 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func testTreePod(ns, name, ownerKind, owner, phase, node, ip string, restarts int64) unstructured.Unstructured {
+	pod := agenttest.Object("v1", "Pod", ns, name, agenttest.WithOwnerReference("apps/v1", ownerKind, owner))
+	pod.Object["spec"] = map[string]interface{}{"nodeName": node}
+	pod.Object["status"] = map[string]interface{}{
+		"phase": phase,
+		"podIP": ip,
+		"containerStatuses": []interface{}{
+			map[string]interface{}{"name": "app", "restartCount": restarts},
+		},
+	}
+	return *pod
+}
+
+func TestBuildLiveTree(t *testing.T) {
+	revision := func(n string) agenttest.Option {
+		return agenttest.WithAnnotations(map[string]string{"deployment.kubernetes.io/revision": n})
+	}
+	dep := agenttest.Deployment("shop", "api", revision("2"))
+	current := agenttest.Object("apps/v1", "ReplicaSet", "shop", "api-7f9c", revision("2"),
+		agenttest.WithOwnerReference("apps/v1", "Deployment", "api"))
+	old := agenttest.Object("apps/v1", "ReplicaSet", "shop", "api-5d4f", revision("1"),
+		agenttest.WithOwnerReference("apps/v1", "Deployment", "api"))
+	other := agenttest.Object("apps/v1", "ReplicaSet", "shop", "web-1a2b",
+		agenttest.WithOwnerReference("apps/v1", "Deployment", "web"))
+	pods := []unstructured.Unstructured{
+		testTreePod("shop", "api-7f9c-b", "ReplicaSet", "api-7f9c", "Running", "node-2", "10.0.0.8", 3),
+		testTreePod("shop", "api-7f9c-a", "ReplicaSet", "api-7f9c", "Running", "node-1", "10.0.0.7", 0),
+		testTreePod("other", "api-7f9c-x", "ReplicaSet", "api-7f9c", "Running", "node-1", "10.0.1.1", 0),
+	}
+
+	tree := buildLiveTree(dep, []unstructured.Unstructured{*old, *other, *current}, pods)
+	if tree.Owner != "Native" || len(tree.ReplicaSets) != 2 {
+		t.Fatalf("owner %s, replicaSets %+v", tree.Owner, tree.ReplicaSets)
+	}
+	if rs := tree.ReplicaSets[0]; rs.Name != "api-7f9c" || !rs.Current || len(rs.Pods) != 2 || rs.Pods[0].Name != "api-7f9c-a" {
+		t.Errorf("current RS = %+v", rs)
+	}
+	if rs := tree.ReplicaSets[1]; rs.Current || len(rs.Pods) != 0 {
+		t.Errorf("old RS = %+v", rs)
+	}
+
+	var buf bytes.Buffer
+	printLiveTree(&buf, tree, "", false)
+	out := buf.String()
+	for _, want := range []string{
+		"└─ ReplicaSet/api-7f9c (",
+		"├─ ✓ Pod/api-7f9c-a (Running, node-1, 10.0.0.7)",
+		"└─ ✓ Pod/api-7f9c-b (Running, node-2, 10.0.0.8, 3 restarts)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "api-5d4f") {
+		t.Errorf("empty old ReplicaSet shown:\n%s", out)
+	}
+	buf.Reset()
+	printLiveTree(&buf, tree, "", true)
+	if !strings.Contains(buf.String(), "ReplicaSet/api-5d4f (0/0 ready, old)") {
+		t.Errorf("--verbose output:\n%s", buf.String())
+	}
+}
+
+func TestBuildLiveTreeStatefulSet(t *testing.T) {
+	sts := agenttest.Workload("StatefulSet", "db", "postgres",
+		agenttest.ManagedByConfigHub("prod", "postgres", 4))
+	pods := []unstructured.Unstructured{
+		testTreePod("db", "postgres-0", "StatefulSet", "postgres", "Pending", "", "", 0),
+	}
+	tree := buildLiveTree(sts, nil, pods)
+	if tree.Owner != "ConfigHub" || len(tree.Pods) != 1 || len(tree.ReplicaSets) != 0 {
+		t.Fatalf("tree = %+v", tree)
+	}
+	var buf bytes.Buffer
+	printLiveTree(&buf, tree, "  ", false)
+	if got, want := buf.String(), "  └─ ✗ Pod/postgres-0 (Pending)\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeSystemNamespaces disables namespace exclusion (--include-system)
+var includeSystemNamespaces bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&includeSystemNamespaces, "include-system", false, "Include system namespaces excluded by ~/.cub-scout/namespaces.yaml")
+}
+
+// defaultExcludedNamespaces are platform namespaces hidden from map, orphans,
+// suggest and import unless configured otherwise.
+var defaultExcludedNamespaces = []string{
+	"kube-system",
+	"kube-public",
+	"kube-node-lease",
+	"local-path-storage",
+	"flux-system",  // Flux controllers
+	"argocd",       // ArgoCD controllers
+	"cert-manager", // Cluster add-ons
+	"ingress-nginx",
+}
+
+// NamespaceConfig is the namespace exclusion file, e.g.:
+//
+//	exclude: [kube-*, flux-system, argocd, "*-system"]
+//	include: [payments-system]
+//
+// Entries are glob patterns. Include wins over exclude. Omitting exclude
+// keeps the defaults; "exclude: []" hides nothing.
+type NamespaceConfig struct {
+	Exclude []string `yaml:"exclude"`
+	Include []string `yaml:"include"`
+}
+
+// NamespaceConfigFile returns the namespace config path:
+// $CUB_SCOUT_NAMESPACES, then ~/.cub-scout/namespaces.yaml.
+func NamespaceConfigFile() string {
+	if file := os.Getenv("CUB_SCOUT_NAMESPACES"); file != "" {
+		return file
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cub-scout", "namespaces.yaml")
+}
+
+// loadNamespaceConfig reads file, falling back to the defaults when it does
+// not exist.
+func loadNamespaceConfig(file string) (NamespaceConfig, error) {
+	cfg := NamespaceConfig{}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NamespaceConfig{Exclude: defaultExcludedNamespaces}, nil
+		}
+		return NamespaceConfig{Exclude: defaultExcludedNamespaces}, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return NamespaceConfig{Exclude: defaultExcludedNamespaces}, fmt.Errorf("parse %s: %w", file, err)
+	}
+	if cfg.Exclude == nil {
+		cfg.Exclude = defaultExcludedNamespaces
+	}
+	for _, p := range append(append([]string{}, cfg.Exclude...), cfg.Include...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return NamespaceConfig{Exclude: defaultExcludedNamespaces}, fmt.Errorf("parse %s: bad pattern %q", file, p)
+		}
+	}
+	return cfg, nil
+}
+
+// Excluded reports whether ns matches an exclude pattern and no include pattern.
+func (c NamespaceConfig) Excluded(ns string) bool {
+	return !c.Included(ns) && matchNamespace(c.Exclude, ns)
+}
+
+// Included reports whether ns is explicitly included.
+func (c NamespaceConfig) Included(ns string) bool {
+	return matchNamespace(c.Include, ns)
+}
+
+func matchNamespace(patterns []string, ns string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, ns); ok {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	namespaceConfigOnce sync.Once
+	namespaceConfig     NamespaceConfig
+)
+
+// activeNamespaceConfig loads the namespace config once per process.
+func activeNamespaceConfig() NamespaceConfig {
+	namespaceConfigOnce.Do(func() {
+		var err error
+		namespaceConfig, err = loadNamespaceConfig(NamespaceConfigFile())
+		if err != nil {
+			logger.Warn("using default namespace exclusions", "err", err)
+		}
+	})
+	return namespaceConfig
+}
+
+// isSystemNamespace reports whether ns is excluded as platform noise.
+// Always false with --include-system.
+func isSystemNamespace(ns string) bool {
+	if includeSystemNamespaces {
+		return false
+	}
+	return activeNamespaceConfig().Excluded(ns)
+}
+
+// isIncludedNamespace reports whether ns is explicitly listed in include,
+// which overrides any built-in heuristics as well as exclude.
+func isIncludedNamespace(ns string) bool {
+	return includeSystemNamespaces || activeNamespaceConfig().Included(ns)
+}
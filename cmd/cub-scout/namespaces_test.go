@@ -0,0 +1,76 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadNamespaceConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := loadNamespaceConfig(filepath.Join(dir, "missing.yaml"))
+	if err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if !cfg.Excluded("kube-system") || !cfg.Excluded("flux-system") || cfg.Excluded("payments") {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+
+	file := filepath.Join(dir, "namespaces.yaml")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("exclude: [kube-*, \"*-system\"]\ninclude: [payments-system]\n")
+	cfg, err = loadNamespaceConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]bool{
+		"kube-system":     true,
+		"kube-node-lease": true,
+		"flux-system":     true,
+		"payments-system": false, // include wins
+		"argocd":          false, // not in custom exclude
+		"prod":            false,
+	}
+	for ns, want := range tests {
+		if got := cfg.Excluded(ns); got != want {
+			t.Errorf("Excluded(%q) = %v, want %v", ns, got, want)
+		}
+	}
+
+	write("include: [argocd]\n")
+	if cfg, _ = loadNamespaceConfig(file); cfg.Excluded("argocd") || !cfg.Excluded("kube-system") {
+		t.Errorf("include-only config should keep default excludes: %+v", cfg)
+	}
+
+	write("exclude: []\n")
+	if cfg, _ = loadNamespaceConfig(file); cfg.Excluded("kube-system") {
+		t.Error("empty exclude list should hide nothing")
+	}
+
+	write("exclude: [\"[\"]\n")
+	if _, err := loadNamespaceConfig(file); err == nil {
+		t.Error("expected error for bad pattern")
+	}
+}
+
+func TestIncludeSystemOverride(t *testing.T) {
+	defer func() { includeSystemNamespaces = false }()
+
+	if !isSystemNamespace("kube-system") || !isSystemNamespaceForSuggest("default") {
+		t.Fatal("expected system namespaces to be excluded by default")
+	}
+	includeSystemNamespaces = true
+	if isSystemNamespace("kube-system") || isSystemNamespaceForSuggest("kube-system") || isSystemNamespaceForSuggest("default") {
+		t.Error("--include-system should disable exclusions")
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// Paging flags (--page, --page-size)
+var (
+	mapPage     int
+	mapPageSize int
+)
+
+// addPageFlags registers --page and --page-size on a listing command.
+func addPageFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&mapPage, "page", 1, "Page number to show (1-based, with --page-size)")
+	cmd.Flags().IntVar(&mapPageSize, "page-size", 0, "Rows per page; 0 shows all rows")
+}
+
+// sortResources stably orders items by namespace, kind, then name, so output
+// does not depend on API iteration order.
+func sortResources[T any](items []T, key func(T) (namespace, kind, name string)) {
+	sort.SliceStable(items, func(i, j int) bool {
+		ni, ki, mi := key(items[i])
+		nj, kj, mj := key(items[j])
+		if ni != nj {
+			return ni < nj
+		}
+		if ki != kj {
+			return ki < kj
+		}
+		return mi < mj
+	})
+}
+
+// pageInfo describes the slice of rows returned by paginate.
+type pageInfo struct {
+	Page  int // 1-based page number
+	Pages int // total pages
+	Start int // 0-based index of the first row on the page
+	End   int // index after the last row on the page
+	Total int // rows across all pages
+	Size  int // rows per page; 0 when paging is off
+}
+
+// paginate returns the requested page of items. A size of 0 returns every
+// item. Pages past the end are empty so scripts can loop until no rows come
+// back.
+func paginate[T any](items []T, page, size int) ([]T, pageInfo, error) {
+	if page < 1 {
+		return nil, pageInfo{}, fmt.Errorf("invalid --page %d (must be 1 or more)", page)
+	}
+	if size < 0 {
+		return nil, pageInfo{}, fmt.Errorf("invalid --page-size %d (must be 0 or more)", size)
+	}
+	info := pageInfo{Page: page, Pages: 1, End: len(items), Total: len(items), Size: size}
+	if size == 0 {
+		if page > 1 {
+			info.Start = len(items)
+			return items[len(items):], info, nil
+		}
+		return items, info, nil
+	}
+	info.Pages = (len(items) + size - 1) / size
+	if info.Pages == 0 {
+		info.Pages = 1
+	}
+	info.Start = min((page-1)*size, len(items))
+	info.End = min(info.Start+size, len(items))
+	return items[info.Start:info.End], info, nil
+}
+
+// printPageFooter tells the reader which rows are shown and how to get the
+// next page. Prints nothing when paging is off.
+func printPageFooter(w io.Writer, p pageInfo) {
+	if p.Size == 0 {
+		return
+	}
+	if p.Start == p.End {
+		fmt.Fprintf(w, "%sPage %d/%d: no rows (%d total)%s\n", colorDim, p.Page, p.Pages, p.Total, colorReset)
+		return
+	}
+	fmt.Fprintf(w, "%sPage %d/%d: rows %d-%d of %d", colorDim, p.Page, p.Pages, p.Start+1, p.End, p.Total)
+	if p.Page < p.Pages {
+		fmt.Fprintf(w, "; next: --page %d", p.Page+1)
+	}
+	fmt.Fprintf(w, "%s\n", colorReset)
+}
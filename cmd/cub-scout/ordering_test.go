@@ -0,0 +1,77 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSortResources(t *testing.T) {
+	entries := []MapEntry{
+		{Namespace: "prod", Kind: "Service", Name: "api"},
+		{Namespace: "", Kind: "Namespace", Name: "prod"},
+		{Namespace: "prod", Kind: "Deployment", Name: "web"},
+		{Namespace: "dev", Kind: "Deployment", Name: "api"},
+		{Namespace: "prod", Kind: "Deployment", Name: "api"},
+	}
+	sortResources(entries, func(e MapEntry) (string, string, string) { return e.Namespace, e.Kind, e.Name })
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Namespace+"/"+e.Kind+"/"+e.Name)
+	}
+	want := "/Namespace/prod dev/Deployment/api prod/Deployment/api prod/Deployment/web prod/Service/api"
+	if strings.Join(got, " ") != want {
+		t.Errorf("order = %v, want %s", got, want)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		page, size int
+		want       []int
+		info       pageInfo
+	}{
+		{1, 0, []int{1, 2, 3, 4, 5}, pageInfo{Page: 1, Pages: 1, Start: 0, End: 5, Total: 5}},
+		{1, 2, []int{1, 2}, pageInfo{Page: 1, Pages: 3, Start: 0, End: 2, Total: 5, Size: 2}},
+		{3, 2, []int{5}, pageInfo{Page: 3, Pages: 3, Start: 4, End: 5, Total: 5, Size: 2}},
+		{4, 2, []int{}, pageInfo{Page: 4, Pages: 3, Start: 5, End: 5, Total: 5, Size: 2}},
+	}
+	for _, tt := range tests {
+		got, info, err := paginate(items, tt.page, tt.size)
+		if err != nil {
+			t.Fatalf("paginate(%d, %d): %v", tt.page, tt.size, err)
+		}
+		if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+			t.Errorf("paginate(%d, %d) = %v, want %v", tt.page, tt.size, got, tt.want)
+		}
+		if info != tt.info {
+			t.Errorf("paginate(%d, %d) info = %+v, want %+v", tt.page, tt.size, info, tt.info)
+		}
+	}
+
+	if _, _, err := paginate(items, 0, 2); err == nil {
+		t.Error("expected error for page 0")
+	}
+	if _, _, err := paginate(items, 1, -1); err == nil {
+		t.Error("expected error for negative page size")
+	}
+}
+
+func TestPrintPageFooter(t *testing.T) {
+	var buf bytes.Buffer
+	printPageFooter(&buf, pageInfo{Page: 1, Pages: 1, End: 5, Total: 5})
+	if buf.Len() != 0 {
+		t.Errorf("expected no footer without paging, got %q", buf.String())
+	}
+
+	printPageFooter(&buf, pageInfo{Page: 2, Pages: 3, Start: 50, End: 100, Total: 120, Size: 50})
+	if out := buf.String(); !strings.Contains(out, "rows 51-100 of 120") || !strings.Contains(out, "--page 3") {
+		t.Errorf("unexpected footer %q", out)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/confighub/cub-scout/pkg/orgpattern"
+	"github.com/spf13/cobra"
+)
+
+var (
+	orgPatternsSpaces []string
+	orgPatternsJSON   bool
+)
+
+var orgPatternsCmd = &cobra.Command{
+	Use:   "patterns",
+	Short: "Detect the ConfigHub org layout and recommend improvements",
+	Long: `Detect how the spaces in the current ConfigHub org are laid out: which
+spaces form the Hub (platform) and which are AppSpaces (teams), and which
+reference architecture the layout follows (KubeCon, TraderX, curious-cub,
+Banko, Arnie, environment-based).
+
+A space is Hub when it has a Role=hub label, when units in other spaces
+deploy through its targets, when it holds workers or targets and no units,
+or when its name says so (platform-*, *-base, *-infra, ...). Space names,
+Environment and region labels, and unit variant/region labels give the
+environments and regions.
+
+Recommendations point out what would make the layout easier to operate:
+missing Hub or base spaces, workers living in AppSpaces, unlabelled units,
+units without a target.
+
+This is the detection the hierarchy TUI shows in the Hub/AppSpace view
+(press B in map --hub). For GitOps repository layouts, see map patterns.
+
+Examples:
+  cub-scout patterns
+  cub-scout patterns --space platform-prod --space apptique-prod
+  cub-scout patterns --json`,
+	Args: cobra.NoArgs,
+	RunE: runOrgPatterns,
+}
+
+func init() {
+	orgPatternsCmd.Flags().StringSliceVar(&orgPatternsSpaces, "space", nil, "Spaces to include (default: all spaces)")
+	orgPatternsCmd.Flags().BoolVar(&orgPatternsJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(orgPatternsCmd)
+}
+
+// orgSpaceData is a space and what was loaded from it.
+type orgSpaceData struct {
+	Space   CubSpaceData
+	Units   []CubUnitData
+	Targets []CubTargetData
+	Workers []CubWorkerData
+}
+
+// toOrgPatternSpaces converts loaded spaces for pattern detection. A unit's
+// target space is resolved from the target's SpaceID when that space was
+// loaded, and is otherwise taken to be the unit's own space.
+func toOrgPatternSpaces(data []orgSpaceData) []orgpattern.Space {
+	slugByID := make(map[string]string, len(data))
+	for _, d := range data {
+		if d.Space.Space.SpaceID != "" {
+			slugByID[d.Space.Space.SpaceID] = d.Space.Space.Slug
+		}
+	}
+	spaces := make([]orgpattern.Space, 0, len(data))
+	for _, d := range data {
+		s := orgpattern.Space{Slug: d.Space.Space.Slug, Labels: d.Space.Space.Labels}
+		for _, u := range d.Units {
+			unit := orgpattern.Unit{Slug: u.Unit.Slug, Labels: u.Unit.Labels}
+			if u.Target.Slug != "" {
+				unit.TargetSpace = s.Slug
+				if slug, ok := slugByID[u.Target.SpaceID]; ok {
+					unit.TargetSpace = slug
+				}
+			}
+			s.Units = append(s.Units, unit)
+		}
+		for _, t := range d.Targets {
+			s.Targets = append(s.Targets, t.Target.Slug)
+		}
+		for _, w := range d.Workers {
+			s.Workers = append(s.Workers, w.BridgeWorker.Slug)
+		}
+		spaces = append(spaces, s)
+	}
+	return spaces
+}
+
+// loadOrgSpaces lists the org's spaces, keeps those in only (all when
+// empty), and loads their units, targets and workers through spaceLoadPool.
+func loadOrgSpaces(ctx context.Context, only []string) ([]orgSpaceData, error) {
+	out, err := runCubCommand("space", "list", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var all []CubSpaceData
+	if err := json.Unmarshal(out, &all); err != nil {
+		return nil, fmt.Errorf("parse space list: %w", err)
+	}
+	keep := make(map[string]bool, len(only))
+	for _, s := range only {
+		keep[s] = true
+	}
+	var data []orgSpaceData
+	for _, s := range all {
+		if len(keep) == 0 || keep[s.Space.Slug] {
+			data = append(data, orgSpaceData{Space: s})
+		}
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].Space.Space.Slug < data[j].Space.Space.Slug })
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i := range data {
+		d := &data[i]
+		slug := d.Space.Space.Slug
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			_ = spaceLoadPool.Do(ctx, func() {
+				units, err := loadUnitsForSpace(slug)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("list units in %s: %w", slug, err)
+					}
+					mu.Unlock()
+				}
+				d.Units = units
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = spaceLoadPool.Do(ctx, func() { d.Targets, _ = loadTargetsForSpace(slug) })
+		}()
+		go func() {
+			defer wg.Done()
+			_ = spaceLoadPool.Do(ctx, func() { d.Workers, _ = loadWorkersForSpace(slug) })
+		}()
+	}
+	wg.Wait()
+	return data, firstErr
+}
+
+func runOrgPatterns(cmd *cobra.Command, args []string) error {
+	if err := checkCubAuth(cmd.CommandPath()); err != nil {
+		return err
+	}
+	data, err := loadOrgSpaces(cmd.Context(), orgPatternsSpaces)
+	if err != nil {
+		return err
+	}
+	result := orgpattern.Detect(toOrgPatternSpaces(data))
+	if orgPatternsJSON {
+		return writeJSON(os.Stdout, "OrgPattern", result)
+	}
+	printOrgPattern(os.Stdout, result)
+	return nil
+}
+
+func printOrgPattern(w io.Writer, r orgpattern.Result) {
+	if r.Pattern == orgpattern.PatternNone {
+		fmt.Fprintln(w, "No spaces found.")
+		return
+	}
+	fmt.Fprintf(w, "%sPattern:%s %s\n", colorBold, colorReset, r.Pattern)
+	for _, t := range r.Traits {
+		fmt.Fprintf(w, "  • %s\n", t)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Hub (%d):       %s\n", len(r.Hub), orDash(strings.Join(r.Hub, ", ")))
+	fmt.Fprintf(w, "AppSpaces (%d): %s\n", len(r.AppSpaces), orDash(strings.Join(r.AppSpaces, ", ")))
+
+	sig := r.Signals
+	var envs, regions []string
+	for env := range sig.Envs {
+		envs = append(envs, env)
+	}
+	sort.Slice(envs, func(i, j int) bool { return envPriority(envs[i]) < envPriority(envs[j]) })
+	for region := range sig.Regions {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%sSignals%s\n", colorDim, colorReset)
+	fmt.Fprintf(w, "  environments:  %s\n", orDash(strings.Join(envs, ", ")))
+	fmt.Fprintf(w, "  regions:       %s\n", orDash(strings.Join(regions, ", ")))
+	fmt.Fprintf(w, "  hub:           %d worker(s), %d target(s)\n", sig.WorkersInHub, sig.TargetsInHub)
+	fmt.Fprintf(w, "  units:         %d in AppSpaces, %d labelled, %d via Hub targets\n", sig.Units, sig.LabelledUnits, sig.HubTargetUnits)
+
+	fmt.Fprintln(w)
+	if len(r.Recommendations) == 0 {
+		fmt.Fprintf(w, "%s✓%s No recommendations: the layout follows the Hub/AppSpace model.\n", colorGreen, colorReset)
+		return
+	}
+	fmt.Fprintf(w, "%sRecommendations%s\n", colorBold, colorReset)
+	for _, rec := range r.Recommendations {
+		fmt.Fprintf(w, "  %s→%s %s\n", colorYellow, colorReset, rec)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/orgpattern"
+)
+
+func TestToOrgPatternSpaces(t *testing.T) {
+	var hub, app orgSpaceData
+	hub.Space.Space.Slug, hub.Space.Space.SpaceID = "clusters", "space-1"
+	hub.Targets = make([]CubTargetData, 1)
+	hub.Targets[0].Target.Slug = "prod"
+	app.Space.Space.Slug, app.Space.Space.SpaceID = "shop-prod", "space-2"
+	app.Units = make([]CubUnitData, 3)
+	app.Units[0].Unit.Slug = "shop"
+	app.Units[0].Unit.Labels = map[string]string{"variant": "prod"}
+	app.Units[0].Target.Slug, app.Units[0].Target.SpaceID = "prod", "space-1"
+	app.Units[1].Unit.Slug = "local"
+	app.Units[1].Target.Slug, app.Units[1].Target.SpaceID = "local", "space-9"
+	app.Units[2].Unit.Slug = "draft"
+
+	spaces := toOrgPatternSpaces([]orgSpaceData{hub, app})
+	units := spaces[1].Units
+	if units[0].TargetSpace != "clusters" || units[0].Labels["variant"] != "prod" {
+		t.Errorf("unit on a Hub target = %+v, want target space clusters", units[0])
+	}
+	if units[1].TargetSpace != "shop-prod" {
+		t.Errorf("unit on an unknown space's target = %+v, want its own space", units[1])
+	}
+	if units[2].TargetSpace != "" {
+		t.Errorf("untargeted unit = %+v, want no target space", units[2])
+	}
+
+	r := orgpattern.Detect(spaces)
+	if len(r.Hub) != 1 || r.Hub[0] != "clusters" {
+		t.Errorf("Hub = %v, want [clusters]", r.Hub)
+	}
+}
+
+func TestPrintOrgPattern(t *testing.T) {
+	r := orgpattern.Detect([]orgpattern.Space{
+		{Slug: "platform-prod", Workers: []string{"w"}, Targets: []string{"prod"}},
+		{Slug: "shop-dev", Labels: map[string]string{"Environment": "dev"}, Units: []orgpattern.Unit{{Slug: "shop", Labels: map[string]string{"variant": "dev"}, TargetSpace: "platform-prod"}}},
+		{Slug: "shop-prod", Units: []orgpattern.Unit{{Slug: "shop", TargetSpace: "platform-prod"}}},
+	})
+	var buf bytes.Buffer
+	printOrgPattern(&buf, r)
+	out := buf.String()
+	for _, want := range []string{
+		"Pattern:" + colorReset + " KubeCon Demo",
+		"Hub (1):       platform-prod",
+		"AppSpaces (2): shop-dev, shop-prod",
+		"environments:  dev, prod",
+		"hub:           1 worker(s), 1 target(s)",
+		"2 in AppSpaces, 1 labelled, 2 via Hub targets",
+		"1 of 2 AppSpace unit(s) have no variant label",
+		"1 AppSpace(s) have no Environment label",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printOrgPattern(&buf, orgpattern.Detect(nil))
+	if buf.String() != "No spaces found.\n" {
+		t.Errorf("empty org output = %q", buf.String())
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+	"github.com/confighub/cub-scout/pkg/orgpattern"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// outputAPIVersion is the apiVersion of the versioned --json envelope.
+const outputAPIVersion = "cub-scout/v1"
+
+// outputVersion selects the --json format (--output-version). Empty keeps the
+// unversioned output that existing scripts parse.
+var outputVersion string
+
+// OutputEnvelope wraps --json output with --output-version v1. Fields may be
+// added to Data within v1; renames and removals need a new version.
+type OutputEnvelope struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Data       any    `json:"data"`
+}
+
+// outputKinds maps each envelope kind to the Go type of its data. It is the
+// source of the published JSON schemas.
+var outputKinds = map[string]any{
+	"MapList":              []MapEntry{},
+	"MapEntry":             MapEntry{},
+	"CrashList":            []CrashInfo{},
+	"CostReport":           CostReport{},
+	"RBACReport":           RBACReport{},
+	"ServiceExposures":     []ServiceExposure{},
+	"ConfigReferences":     []ConfigReference{},
+	"CRDInventory":         []CRDInfo{},
+	"StatusSummary":        StatusSummary{},
+	"LiveTree":             LiveTree{},
+	"UpgradeReport":        UpgradeReport{},
+	"DelegatedPipelines":   []DelegatedPipeline{},
+	"SourceTopology":       SourceTopology{},
+	"StaleResources":       []StaleResource{},
+	"TerraformCorrelation": agent.TerraformCorrelation{},
+	"FleetUnits":           []FleetUnit{},
+	"FleetInventory":       []FleetInventoryEntry{},
+	"Patterns":             PatternsResult{},
+	"ScanResult":           CombinedScanResult{},
+	"ScanDiff":             ScanDiff{},
+	"PathScanResult":       PathScanResult{},
+	"KustomizeScanResult":  KustomizeScanResult{},
+	"UnitSuggestions":      SuggestionJSON{},
+	"UnitDrifts":           []UnitDrift{},
+	"LocalDiffs":           []LocalDiff{},
+	"LagSLOResults":        []LagSLOResult{},
+	"ImportVerifications":  []ImportVerification{},
+	"PolicyCatalog":        []*agent.KyvernoPolicy{},
+	"TraceResult":          agent.TraceResult{},
+	"IncidentStream":       []TimelineEntry{},
+	"ReverseTraceResult":   agent.ReverseTraceResult{},
+	"OrgPattern":           orgpattern.Result{},
+	"UnitGraph":            UnitGraph{},
+	"UnitFanOut":           UnitFanOut{},
+	"Promotions":           []PromotionChain{},
+	"ChangeSets":           []ChangeSet{},
+	"ChangeSet":            ChangeSet{},
+	"Triggers":             []TriggerInfo{},
+}
+
+var schemaDir string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [kind]",
+	Short: "Print the JSON schema for versioned --json output",
+	Long: `Print the JSON schema for --json output with --output-version v1.
+
+Versioned output is wrapped in an envelope naming its schema:
+
+  {"apiVersion": "cub-scout/v1", "kind": "MapList", "data": [...]}
+
+Within v1, fields may be added but are never renamed or removed, so tools
+should ignore fields they do not know. Without --output-version, --json
+prints the unversioned data only.
+
+With no kind, lists the available kinds.
+
+Examples:
+  cub-scout map list --json --output-version v1
+  cub-scout schema
+  cub-scout schema MapList
+  cub-scout schema --dir docs/reference/schemas/v1`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runSchema,
+	ValidArgsFunction: completeOutputKinds,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputVersion, "output-version", "", "Wrap --json output in a versioned envelope (v1)")
+	rootCmd.AddCommand(schemaCmd)
+	schemaCmd.Flags().StringVar(&schemaDir, "dir", "", "Write every schema to <dir>/<kind>.json")
+}
+
+// writeJSON encodes v as indented JSON, wrapped in an envelope of the given
+// kind when --output-version is set.
+func writeJSON(w io.Writer, kind string, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	switch outputVersion {
+	case "":
+		return enc.Encode(v)
+	case "v1":
+		return enc.Encode(OutputEnvelope{APIVersion: outputAPIVersion, Kind: kind, Data: v})
+	}
+	return fmt.Errorf("unsupported --output-version %q (supported: v1)", outputVersion)
+}
+
+// writeNDJSON encodes v as one line of compact JSON, for output streamed
+// one entry at a time (--format ndjson). Each line is wrapped in an envelope
+// of the given kind when --output-version is set.
+func writeNDJSON(w io.Writer, kind string, v any) error {
+	enc := json.NewEncoder(w)
+	switch outputVersion {
+	case "":
+		return enc.Encode(v)
+	case "v1":
+		return enc.Encode(OutputEnvelope{APIVersion: outputAPIVersion, Kind: kind, Data: v})
+	}
+	return fmt.Errorf("unsupported --output-version %q (supported: v1)", outputVersion)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if schemaDir != "" {
+		if err := os.MkdirAll(schemaDir, 0o755); err != nil {
+			return err
+		}
+		for _, kind := range outputKindNames() {
+			data, err := outputSchemaJSON(kind)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(schemaDir, kind+".json"), data, 0o644); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Wrote %d schemas to %s\n", len(outputKinds), schemaDir)
+		return nil
+	}
+
+	if len(args) == 0 {
+		for _, kind := range outputKindNames() {
+			fmt.Println(kind)
+		}
+		return nil
+	}
+
+	data, err := outputSchemaJSON(args[0])
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func outputKindNames() []string {
+	names := make([]string, 0, len(outputKinds))
+	for kind := range outputKinds {
+		names = append(names, kind)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func completeOutputKinds(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return outputKindNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// outputSchemaJSON returns the JSON schema of the v1 envelope for kind.
+func outputSchemaJSON(kind string) ([]byte, error) {
+	v, ok := outputKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %q (valid: %s)", kind, strings.Join(outputKindNames(), ", "))
+	}
+	g := &schemaGenerator{defs: map[string]map[string]any{}, names: map[reflect.Type]string{}}
+	schema := map[string]any{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"$id":      "https://github.com/confighub/cub-scout/schemas/v1/" + kind + ".json",
+		"title":    kind,
+		"type":     "object",
+		"required": []string{"apiVersion", "kind", "data"},
+		"properties": map[string]any{
+			"apiVersion": map[string]any{"const": outputAPIVersion},
+			"kind":       map[string]any{"const": kind},
+			"data":       g.schemaFor(reflect.TypeOf(v)),
+		},
+	}
+	if len(g.defs) > 0 {
+		schema["$defs"] = g.defs
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaGenerator derives JSON schema from Go types using encoding/json
+// rules. Named structs become $defs so recursive types terminate.
+type schemaGenerator struct {
+	defs  map[string]map[string]any
+	names map[reflect.Type]string
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	metaTimeType      = reflect.TypeOf(metav1.Time{})
+)
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return map[string]any{"anyOf": []any{g.schemaFor(t.Elem()), map[string]any{"type": "null"}}}
+	}
+	if t == timeType || t == metaTimeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return map[string]any{} // custom encoding, e.g. unstructured objects
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": []string{"array", "null"}, "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": []string{"object", "null"}, "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name, ok := g.names[t]
+		if !ok {
+			name = g.defName(t)
+			g.names[t] = name
+			g.defs[name] = map[string]any{} // placeholder for recursion
+			g.defs[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	}
+	return map[string]any{} // interfaces and anything else: any JSON value
+}
+
+// defName returns a unique $defs key, qualifying with the package when two
+// packages use the same type name.
+func (g *schemaGenerator) defName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := g.defs[name]; taken {
+		name = filepath.Base(t.PkgPath()) + "." + name
+	}
+	return name
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	g.addFields(t, props, &required)
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+func (g *schemaGenerator) addFields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(ft, props, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = g.schemaFor(f.Type)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	defer func() { outputVersion = "" }()
+	entries := []MapEntry{{Namespace: "prod", Kind: "Deployment", Name: "api", Owner: "Flux"}}
+
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, "MapList", entries); err != nil {
+		t.Fatal(err)
+	}
+	var bare []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &bare); err != nil {
+		t.Fatalf("default output should stay a bare array: %v", err)
+	}
+
+	outputVersion = "v1"
+	buf.Reset()
+	if err := writeJSON(&buf, "MapList", entries); err != nil {
+		t.Fatal(err)
+	}
+	var env struct {
+		APIVersion string           `json:"apiVersion"`
+		Kind       string           `json:"kind"`
+		Data       []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.APIVersion != "cub-scout/v1" || env.Kind != "MapList" || len(env.Data) != 1 || env.Data[0]["name"] != "api" {
+		t.Errorf("unexpected envelope: %s", buf.String())
+	}
+
+	outputVersion = "v2"
+	if err := writeJSON(&buf, "MapList", entries); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	defer func() { outputVersion = "" }()
+
+	var buf bytes.Buffer
+	for _, name := range []string{"api", "worker"} {
+		if err := writeNDJSON(&buf, "MapEntry", MapEntry{Namespace: "prod", Kind: "Deployment", Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want one line per entry, got:\n%s", buf.String())
+	}
+	var e map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil || e["name"] != "worker" {
+		t.Errorf("line 2 = %s (%v)", lines[1], err)
+	}
+
+	outputVersion = "v1"
+	buf.Reset()
+	if err := writeNDJSON(&buf, "MapEntry", MapEntry{Name: "api"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), `{"apiVersion":"cub-scout/v1","kind":"MapEntry","data":{`) || strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("unexpected envelope line: %s", buf.String())
+	}
+}
+
+// TestOutputSchemasPublished fails when a --json type changes without
+// regenerating docs/reference/schemas/v1.
+func TestOutputSchemasPublished(t *testing.T) {
+	dir := filepath.Join("..", "..", "docs", "reference", "schemas", "v1")
+	for _, kind := range outputKindNames() {
+		want, err := outputSchemaJSON(kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, kind+".json"))
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("%s.json is out of date; run: go run ./cmd/cub-scout schema --dir docs/reference/schemas/v1", kind)
+		}
+	}
+}
+
+func TestOutputSchemaRequiredFields(t *testing.T) {
+	type node struct {
+		Name     string  `json:"name"`
+		Note     string  `json:"note,omitempty"`
+		Children []*node `json:"children"`
+	}
+	g := &schemaGenerator{defs: map[string]map[string]any{}, names: map[reflect.Type]string{}}
+	ref := g.schemaFor(reflect.TypeOf(node{}))
+	if ref["$ref"] != "#/$defs/node" {
+		t.Fatalf("expected $ref for named struct, got %v", ref)
+	}
+	def := g.defs["node"]
+	required, _ := def["required"].([]string)
+	if len(required) != 2 || required[0] != "children" || required[1] != "name" {
+		t.Errorf("required = %v, want [children name]", required)
+	}
+}
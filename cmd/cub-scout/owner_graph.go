@@ -0,0 +1,41 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+// ownerGraph indexes the objects a command listed, so objects created by
+// controllers (Pods, ReplicaSets, Jobs) inherit the GitOps owner at the top
+// of their ownerReferences. Commands set it via indexOwners before
+// classifying anything; nil means direct detection only.
+var ownerGraph *agent.OwnerIndex
+
+// listedObjects is one resource type's list result.
+type listedObjects struct {
+	gvr   schema.GroupVersionResource
+	items []unstructured.Unstructured
+}
+
+// indexOwners sets ownerGraph to every listed object.
+func indexOwners(lists []listedObjects) *agent.OwnerIndex {
+	idx := agent.NewOwnerIndex()
+	for i := range lists {
+		for j := range lists[i].items {
+			idx.Add(&lists[i].items[j])
+		}
+	}
+	ownerGraph = idx
+	return idx
+}
+
+// graphOwnership returns who manages resource, following ownerReferences
+// through ownerGraph when the resource itself carries no GitOps markers.
+func graphOwnership(resource *unstructured.Unstructured) agent.Ownership {
+	return ownerGraph.DetectOwnership(resource)
+}
@@ -5,7 +5,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -136,9 +135,7 @@ func runMapPatterns(cmd *cobra.Command, args []string) error {
 	result.Suggested = suggestOrganization(result)
 
 	if mapJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(result)
+		return writeJSON(os.Stdout, "Patterns", result)
 	}
 
 	printPatterns(result)
@@ -0,0 +1,196 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// listFailure is a List call that failed for a reason other than the
+// resource type not being installed, e.g. RBAC or a timeout.
+type listFailure struct {
+	Resource  string
+	Namespace string
+	Err       error
+}
+
+// Reason explains the failure and how to fix it.
+func (f listFailure) Reason() string {
+	switch {
+	case apierrors.IsForbidden(f.Err):
+		scope := "-A"
+		if f.Namespace != "" {
+			scope = "-n " + f.Namespace
+		}
+		return fmt.Sprintf("forbidden (check RBAC: kubectl auth can-i list %s %s)", f.Resource, scope)
+	case errors.Is(f.Err, context.DeadlineExceeded) || apierrors.IsTimeout(f.Err) || apierrors.IsServerTimeout(f.Err):
+		return "timed out (raise --timeout)"
+	case apierrors.IsUnauthorized(f.Err):
+		return "unauthorized (check your kubeconfig credentials)"
+	}
+	return f.Err.Error()
+}
+
+// isNotInstalled reports list errors that mean the CRD or API group is absent,
+// which is expected and not worth a warning.
+func isNotInstalled(err error) bool {
+	return apierrors.IsNotFound(err) || meta.IsNoMatchError(err)
+}
+
+// isUnsupportedFieldSelector reports a list rejected because its resource
+// type has no such field (e.g. status.phase on Deployments). With a field
+// selector that is expected for some types and not worth a warning.
+func isUnsupportedFieldSelector(err error, fieldSelector string) bool {
+	return fieldSelector != "" && apierrors.IsBadRequest(err)
+}
+
+// listRecorder collects list failures from a recordingClient.
+type listRecorder struct {
+	mu       sync.Mutex
+	seen     map[string]bool
+	failures []listFailure
+}
+
+func (r *listRecorder) record(gvr schema.GroupVersionResource, namespace string, err error) {
+	if err == nil || isNotInstalled(err) {
+		return
+	}
+	resource := gvr.Resource
+	if gvr.Group != "" {
+		resource += "." + gvr.Group
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := resource + "/" + namespace
+	if r.seen == nil {
+		r.seen = map[string]bool{}
+	}
+	if r.seen[key] {
+		return
+	}
+	r.seen[key] = true
+	r.failures = append(r.failures, listFailure{Resource: resource, Namespace: namespace, Err: err})
+}
+
+// Failures returns the recorded failures in the order they happened.
+func (r *listRecorder) Failures() []listFailure {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]listFailure(nil), r.failures...)
+}
+
+// recordingClient wraps a dynamic client and records failed List calls, so
+// commands that skip unlistable resource types can still report them.
+type recordingClient struct {
+	dynamic.Interface
+	rec *listRecorder
+}
+
+func newRecordingClient(client dynamic.Interface, rec *listRecorder) dynamic.Interface {
+	return &recordingClient{Interface: client, rec: rec}
+}
+
+func (c *recordingClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &recordingResource{NamespaceableResourceInterface: c.Interface.Resource(gvr), gvr: gvr, rec: c.rec}
+}
+
+type recordingResource struct {
+	dynamic.NamespaceableResourceInterface
+	gvr schema.GroupVersionResource
+	rec *listRecorder
+}
+
+func (r *recordingResource) Namespace(ns string) dynamic.ResourceInterface {
+	return &recordingNamespacedResource{ResourceInterface: r.NamespaceableResourceInterface.Namespace(ns), gvr: r.gvr, ns: ns, rec: r.rec}
+}
+
+func (r *recordingResource) List(ctx context.Context, opts v1.ListOptions) (*unstructured.UnstructuredList, error) {
+	l, err := r.NamespaceableResourceInterface.List(ctx, opts)
+	if !isUnsupportedFieldSelector(err, opts.FieldSelector) {
+		r.rec.record(r.gvr, "", err)
+	}
+	return l, err
+}
+
+type recordingNamespacedResource struct {
+	dynamic.ResourceInterface
+	gvr schema.GroupVersionResource
+	ns  string
+	rec *listRecorder
+}
+
+func (r *recordingNamespacedResource) List(ctx context.Context, opts v1.ListOptions) (*unstructured.UnstructuredList, error) {
+	l, err := r.ResourceInterface.List(ctx, opts)
+	if !isUnsupportedFieldSelector(err, opts.FieldSelector) {
+		r.rec.record(r.gvr, r.ns, err)
+	}
+	return l, err
+}
+
+// printPartialResults warns that output is incomplete because some resource
+// types could not be listed. Prints nothing when every list succeeded.
+func printPartialResults(w io.Writer, failures []listFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s⚠ Partial results: %d resource type(s) could not be listed%s\n", colorYellow, len(failures), colorReset)
+	for _, f := range failures {
+		where := ""
+		if f.Namespace != "" {
+			where = " in " + f.Namespace
+		}
+		fmt.Fprintf(w, "  %s%s: %s\n", f.Resource, where, f.Reason())
+	}
+}
+
+// progressBar draws a single-line progress bar on a terminal. It is a no-op
+// when the writer is not a terminal, so piped and JSON output stay clean.
+type progressBar struct {
+	w       io.Writer
+	total   int
+	done    int
+	enabled bool
+}
+
+func newProgressBar(total int) *progressBar {
+	return &progressBar{w: os.Stderr, total: total, enabled: stderrIsTerminal()}
+}
+
+// Step marks the start of the next unit of work, labelled e.g. "configmaps".
+func (p *progressBar) Step(label string) {
+	if !p.enabled || p.total == 0 {
+		return
+	}
+	p.done++
+	const width = 20
+	filled := p.done * width / p.total
+	fmt.Fprintf(p.w, "\r\033[K%s[%s%s]%s %d/%d %s", colorDim,
+		strings.Repeat("█", filled), strings.Repeat("░", width-filled), colorReset, p.done, p.total, label)
+}
+
+// Done clears the progress line.
+func (p *progressBar) Done() {
+	if p.enabled {
+		fmt.Fprint(p.w, "\r\033[K")
+	}
+}
+
+func stderrIsTerminal() bool {
+	fi, err := os.Stderr.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,91 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRecordingClientReportsPartialResults(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	apps := schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	kustomizations := schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+
+	fake := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		secrets: "SecretList", apps: "ApplicationList", deployments: "DeploymentList", kustomizations: "KustomizationList",
+	})
+	fake.PrependReactor("list", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", nil)
+	})
+	fake.PrependReactor("list", "kustomizations", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "kustomizations"}, "")
+	})
+	fake.PrependReactor("list", "applications", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, context.DeadlineExceeded
+	})
+
+	rec := &listRecorder{}
+	client := newRecordingClient(fake, rec)
+	ctx := context.Background()
+	_, _ = client.Resource(secrets).Namespace("prod").List(ctx, v1.ListOptions{})
+	_, _ = client.Resource(secrets).Namespace("prod").List(ctx, v1.ListOptions{}) // duplicate
+	_, _ = client.Resource(kustomizations).List(ctx, v1.ListOptions{})            // not installed
+	_, _ = client.Resource(apps).List(ctx, v1.ListOptions{})
+	if _, err := client.Resource(deployments).List(ctx, v1.ListOptions{}); err != nil {
+		t.Fatalf("deployments: %v", err)
+	}
+
+	failures := rec.Failures()
+	if len(failures) != 2 {
+		t.Fatalf("expected 2 failures, got %d: %+v", len(failures), failures)
+	}
+
+	var buf bytes.Buffer
+	printPartialResults(&buf, failures)
+	out := buf.String()
+	for _, want := range []string{
+		"Partial results: 2 resource type(s)",
+		"secrets in prod: forbidden (check RBAC: kubectl auth can-i list secrets -n prod)",
+		"applications.argoproj.io: timed out (raise --timeout)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printPartialResults(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without failures, got %q", buf.String())
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	var buf bytes.Buffer
+	p := &progressBar{w: &buf, total: 4, enabled: true}
+	p.Step("deployments")
+	p.Step("services")
+	if !strings.Contains(buf.String(), "2/4 services") || !strings.Contains(buf.String(), "██████████░░░░░░░░░░") {
+		t.Errorf("unexpected progress output %q", buf.String())
+	}
+
+	buf.Reset()
+	quiet := &progressBar{w: &buf, total: 4}
+	quiet.Step("deployments")
+	quiet.Done()
+	if buf.Len() != 0 {
+		t.Errorf("disabled progress bar wrote %q", buf.String())
+	}
+}
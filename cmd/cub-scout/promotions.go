@@ -0,0 +1,269 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	promotionsSpace  string
+	promotionsBehind bool
+	promotionsJSON   bool
+)
+
+var promotionsCmd = &cobra.Command{
+	Use:   "promotions [space/unit]",
+	Short: "Show which upstream revision each cloned unit is on, and how long it has lagged",
+	Long: `Show promotion lag along ConfigHub clone chains.
+
+A unit cloned from an upstream unit (base → dev → staging → prod) takes the
+upstream's changes when it is upgraded. For each clone this shows the
+upstream revision it was last upgraded to, the upstream's head, how many
+revisions it is behind, and how long: since the first upstream revision
+it has not taken was created.
+
+With no unit, shows every chain from a base unit. Given a unit, shows the
+chain it belongs to. --space keeps the chains that reach into that space,
+and --behind the chains with a clone that is behind.
+
+The hub TUI shows the same lag next to each unit whose upstream is loaded.
+
+Examples:
+  cub-scout promotions                           # Every clone chain
+  cub-scout promotions platform-base/nginx       # The chain nginx is in
+  cub-scout promotions --space prod --behind     # Chains where prod lags
+  cub-scout promotions --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPromotions,
+}
+
+func init() {
+	promotionsCmd.Flags().StringVar(&promotionsSpace, "space", "", "Space of the unit, or with no unit, the space the chains must reach")
+	_ = promotionsCmd.RegisterFlagCompletionFunc("space", completeSpaces)
+	promotionsCmd.Flags().BoolVar(&promotionsBehind, "behind", false, "Only show chains with a clone behind its upstream")
+	promotionsCmd.Flags().BoolVar(&promotionsJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(promotionsCmd)
+}
+
+// PromotionChain is a base unit and every unit cloned from it, directly or
+// through other clones.
+type PromotionChain struct {
+	Base         UnitRef         `json:"base"`
+	HeadRevision int             `json:"headRevision"`
+	Steps        []PromotionStep `json:"steps"`
+}
+
+// PromotionStep is one clone in a chain, and how far it trails its
+// upstream.
+type PromotionStep struct {
+	UnitRef
+	Upstream UnitRef `json:"upstream"`
+	// Depth is 1 for clones of the base, 2 for their clones, and so on
+	Depth int `json:"depth"`
+	// UpstreamRevision is the upstream revision the clone was last
+	// upgraded to, and UpstreamHead the upstream's head revision
+	UpstreamRevision int `json:"upstreamRevision"`
+	UpstreamHead     int `json:"upstreamHead"`
+	Behind           int `json:"behind"`
+	// Since is when the first upstream revision the clone has not taken
+	// was created
+	Since      *time.Time `json:"since,omitempty"`
+	LagSeconds int64      `json:"lagSeconds,omitempty"`
+}
+
+// Lagging reports whether any clone in the chain is behind its upstream.
+func (c PromotionChain) Lagging() bool {
+	for _, s := range c.Steps {
+		if s.Behind > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// buildPromotionChains lists the clone chain below each base. Revisions of
+// an upstream are fetched, once, only when a clone of it is behind; a unit
+// whose revisions can't be listed leaves its clones' lag undated.
+func buildPromotionChains(g *UnitGraph, bases []*UnitGraphNode, revisions func(space, unit string) ([]ConfigHubRevision, error), now time.Time) []PromotionChain {
+	cache := map[UnitRef][]ConfigHubRevision{}
+	var chains []PromotionChain
+	for _, base := range bases {
+		chain := PromotionChain{Base: base.UnitRef, HeadRevision: base.HeadRevision}
+		var walk func(up *UnitGraphNode, depth int, path map[UnitRef]bool)
+		walk = func(up *UnitGraphNode, depth int, path map[UnitRef]bool) {
+			for _, ref := range up.Downstream {
+				clone := g.Get(ref)
+				if clone == nil || path[ref] {
+					continue
+				}
+				step := PromotionStep{
+					UnitRef:          ref,
+					Upstream:         up.UnitRef,
+					Depth:            depth,
+					UpstreamRevision: clone.UpstreamRevision,
+					UpstreamHead:     up.HeadRevision,
+				}
+				if step.UpstreamRevision < step.UpstreamHead {
+					step.Behind = step.UpstreamHead - step.UpstreamRevision
+					revs, ok := cache[up.UnitRef]
+					if !ok {
+						revs, _ = revisions(up.Space, up.Unit)
+						cache[up.UnitRef] = revs
+					}
+					if since, ok := oldestRevisionAfter(revs, step.UpstreamRevision); ok {
+						step.Since = &since
+						step.LagSeconds = int64(now.Sub(since).Seconds())
+					}
+				}
+				chain.Steps = append(chain.Steps, step)
+				path[ref] = true
+				walk(clone, depth+1, path)
+				delete(path, ref)
+			}
+		}
+		walk(base, 1, map[UnitRef]bool{base.UnitRef: true})
+		chains = append(chains, chain)
+	}
+	return chains
+}
+
+// promotionBases returns the units at the top of a clone chain: those with
+// clones and no upstream of their own.
+func promotionBases(g *UnitGraph) []*UnitGraphNode {
+	var bases []*UnitGraphNode
+	for _, n := range g.Units {
+		if n.Upstream == nil && len(n.Downstream) > 0 {
+			bases = append(bases, n)
+		}
+	}
+	return bases
+}
+
+// promotionBaseOf returns the base of the chain n is in: the top of its
+// upstream chain, or n itself.
+func promotionBaseOf(g *UnitGraph, n *UnitGraphNode) *UnitGraphNode {
+	chain := g.UpstreamChain(n)
+	if len(chain) == 0 {
+		return n
+	}
+	if base := g.Get(chain[len(chain)-1]); base != nil {
+		return base
+	}
+	// The top of the chain was not loaded; start below it
+	if len(chain) > 1 {
+		return g.Get(chain[len(chain)-2])
+	}
+	return n
+}
+
+func runPromotions(cmd *cobra.Command, args []string) error {
+	if err := checkCubAuth(cmd.CommandPath()); err != nil {
+		return err
+	}
+	g, err := loadUnitGraph(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	bases := promotionBases(g)
+	if len(args) == 1 {
+		n, err := g.Find(args[0], promotionsSpace)
+		if err != nil {
+			return err
+		}
+		bases = []*UnitGraphNode{promotionBaseOf(g, n)}
+	}
+	chains := buildPromotionChains(g, bases, fetchConfigHubRevisions, time.Now())
+	chains = filterPromotionChains(chains, len(args) == 0, promotionsSpace, promotionsBehind)
+
+	if promotionsJSON {
+		if chains == nil {
+			chains = []PromotionChain{}
+		}
+		return writeJSON(os.Stdout, "Promotions", chains)
+	}
+	printPromotionChains(os.Stdout, chains)
+	return nil
+}
+
+// filterPromotionChains keeps the chains reaching space, when bySpace is
+// set, and with behind the chains that lag.
+func filterPromotionChains(chains []PromotionChain, bySpace bool, space string, behind bool) []PromotionChain {
+	var keep []PromotionChain
+	for _, c := range chains {
+		if bySpace && space != "" && !chainReaches(c, space) {
+			continue
+		}
+		if behind && !c.Lagging() {
+			continue
+		}
+		keep = append(keep, c)
+	}
+	return keep
+}
+
+func chainReaches(c PromotionChain, space string) bool {
+	if c.Base.Space == space {
+		return true
+	}
+	for _, s := range c.Steps {
+		if s.Space == space {
+			return true
+		}
+	}
+	return false
+}
+
+func printPromotionChains(w io.Writer, chains []PromotionChain) {
+	if len(chains) == 0 {
+		fmt.Fprintln(w, "No cloned units found.")
+		return
+	}
+	lagging := 0
+	for i, c := range chains {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s%s%s (rev %d)\n", colorBold, c.Base, colorReset, c.HeadRevision)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  SPACE\tUNIT\tUPSTREAM\tREVISION\tBEHIND\tLAG")
+		for _, s := range c.Steps {
+			behind, lag := "-", "-"
+			if s.Behind > 0 {
+				behind = fmt.Sprintf("%d", s.Behind)
+				lagging++
+			}
+			if s.Since != nil {
+				lag = formatElapsed(time.Duration(s.LagSeconds) * time.Second)
+			}
+			fmt.Fprintf(tw, "  %s%s\t%s\t%s\t%d/%d\t%s\t%s\n",
+				strings.Repeat("  ", s.Depth-1), s.Space, s.Unit, s.Upstream, s.UpstreamRevision, s.UpstreamHead, behind, lag)
+		}
+		tw.Flush()
+	}
+	if lagging == 0 {
+		fmt.Fprintf(w, "\n✓ Every clone is on its upstream's head revision\n")
+		return
+	}
+	fmt.Fprintf(w, "\n⚠ %d clone(s) behind their upstream\n", lagging)
+}
+
+// promotionLagInfo is the tree's note for a clone behind its upstream, or
+// "" when it is not, or its upstream is not loaded.
+func promotionLagInfo(g *UnitGraph, ref UnitRef) string {
+	n := g.Get(ref)
+	if n == nil || !g.behind(n) {
+		return ""
+	}
+	up := g.Get(*n.Upstream)
+	return fmt.Sprintf("↑%d behind %s", up.HeadRevision-n.UpstreamRevision, up.Space)
+}
@@ -0,0 +1,113 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPromotionGraph is base → dev → staging → prod, with staging two
+// revisions behind dev and prod one behind staging.
+func testPromotionGraph() *UnitGraph {
+	return buildUnitGraph(map[string][]CubUnitData{
+		"base":    {graphUnit("b", "api", 7, "", 0), graphUnit("x", "solo", 2, "", 0)},
+		"dev":     {graphUnit("d", "api", 4, "b", 7)},
+		"staging": {graphUnit("s", "api", 3, "d", 2)},
+		"prod":    {graphUnit("p", "api", 1, "s", 2)},
+	}, nil)
+}
+
+func TestBuildPromotionChains(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var fetched []string
+	revisions := func(space, unit string) ([]ConfigHubRevision, error) {
+		fetched = append(fetched, space+"/"+unit)
+		if space == "staging" {
+			return nil, errors.New("forbidden")
+		}
+		return []ConfigHubRevision{
+			{Num: 2, CreatedAt: now.Add(-72 * time.Hour)},
+			{Num: 3, CreatedAt: now.Add(-26 * time.Hour)},
+			{Num: 4, CreatedAt: now.Add(-2 * time.Hour)},
+		}, nil
+	}
+
+	g := testPromotionGraph()
+	chains := buildPromotionChains(g, promotionBases(g), revisions, now)
+	if len(chains) != 1 || chains[0].Base.String() != "base/api" || len(chains[0].Steps) != 3 {
+		t.Fatalf("chains = %+v", chains)
+	}
+	dev, staging, prod := chains[0].Steps[0], chains[0].Steps[1], chains[0].Steps[2]
+	if dev.Space != "dev" || dev.Behind != 0 || dev.Since != nil {
+		t.Errorf("dev = %+v, want on head", dev)
+	}
+	if staging.Depth != 2 || staging.Behind != 2 || staging.Upstream.Space != "dev" || staging.LagSeconds != 26*3600 {
+		t.Errorf("staging = %+v, want 2 behind dev since rev 3", staging)
+	}
+	if prod.Behind != 1 || prod.Since != nil {
+		t.Errorf("prod = %+v, want behind but undated when revisions fail", prod)
+	}
+	if strings.Join(fetched, " ") != "dev/api staging/api" {
+		t.Errorf("fetched revisions of %v, want only upstreams with a clone behind", fetched)
+	}
+
+	if got := filterPromotionChains(chains, true, "prod", true); len(got) != 1 {
+		t.Errorf("chain reaching prod and lagging was dropped")
+	}
+	if got := filterPromotionChains(chains, true, "qa", false); len(got) != 0 {
+		t.Errorf("chain not reaching qa was kept")
+	}
+	if base := promotionBaseOf(g, g.Get(UnitRef{"prod", "api"})); base.String() != "base/api" {
+		t.Errorf("base of prod/api = %s", base)
+	}
+}
+
+func TestPrintPromotionChains(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-26 * time.Hour)
+	chains := []PromotionChain{{
+		Base: UnitRef{"base", "api"}, HeadRevision: 7,
+		Steps: []PromotionStep{
+			{UnitRef: UnitRef{"dev", "api"}, Upstream: UnitRef{"base", "api"}, Depth: 1, UpstreamRevision: 7, UpstreamHead: 7},
+			{UnitRef: UnitRef{"staging", "api"}, Upstream: UnitRef{"dev", "api"}, Depth: 2, UpstreamRevision: 2, UpstreamHead: 4, Behind: 2, Since: &since, LagSeconds: 26 * 3600},
+		},
+	}}
+	var buf bytes.Buffer
+	printPromotionChains(&buf, chains)
+	out := buf.String()
+	for _, want := range []string{"base/api", "(rev 7)", "SPACE", "    staging", "2/4", "1d 2h", "1 clone(s) behind"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printPromotionChains(&buf, nil)
+	if !strings.Contains(buf.String(), "No cloned units") {
+		t.Errorf("empty output: %s", buf.String())
+	}
+}
+
+func TestPromotionLagInfo(t *testing.T) {
+	g := testPromotionGraph()
+	if got := promotionLagInfo(g, UnitRef{"staging", "api"}); got != "↑2 behind dev" {
+		t.Errorf("staging lag = %q", got)
+	}
+	if got := promotionLagInfo(g, UnitRef{"dev", "api"}); got != "" {
+		t.Errorf("dev is on head, got %q", got)
+	}
+
+	// Before the upstream's space loads, there is nothing to compare with
+	g = buildUnitGraph(map[string][]CubUnitData{"prod": {graphUnit("p", "api", 1, "s", 2)}}, nil)
+	if got := promotionLagInfo(g, UnitRef{"prod", "api"}); got != "" {
+		t.Errorf("unloaded upstream should give no lag, got %q", got)
+	}
+	if info := buildUnitInfo(CubUnitData{}, "↑1 behind staging"); !strings.Contains(info, "↑1 behind staging") {
+		t.Errorf("unit info = %q", info)
+	}
+}
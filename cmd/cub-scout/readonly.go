@@ -0,0 +1,101 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// readOnlyEnv forces read-only mode on or off. It is also set for child
+// processes (the TUI runs cub-scout scan and remedy) so they inherit the mode.
+const readOnlyEnv = "CUB_SCOUT_READ_ONLY"
+
+var (
+	readOnlyFlag bool
+
+	// readOnlyMode blocks every cluster write when set; resolved per command
+	// by applyReadOnly.
+	readOnlyMode bool
+)
+
+// readOnlyCommands are the top-level commands that are read-only unless
+// --read-only=false is passed.
+var readOnlyCommands = map[string]bool{"map": true, "scan": true, "trace": true}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&readOnlyFlag, "read-only", false, "Block every write to the cluster (default for map, scan and trace; --read-only=false allows writes)")
+}
+
+// applyReadOnly resolves read-only mode for cmd: --read-only when given,
+// then CUB_SCOUT_READ_ONLY, then the command's default.
+func applyReadOnly(cmd *cobra.Command) error {
+	switch f := cmd.Flag("read-only"); {
+	case f != nil && f.Changed:
+		readOnlyMode = readOnlyFlag
+	case os.Getenv(readOnlyEnv) != "":
+		v, err := strconv.ParseBool(os.Getenv(readOnlyEnv))
+		if err != nil {
+			return fmt.Errorf("invalid %s=%q: want true or false", readOnlyEnv, os.Getenv(readOnlyEnv))
+		}
+		readOnlyMode = v
+	default:
+		readOnlyMode = readOnlyCommands[topLevelCommand(cmd).Name()]
+	}
+	return os.Setenv(readOnlyEnv, strconv.FormatBool(readOnlyMode))
+}
+
+// topLevelCommand returns the direct child of the root that cmd runs under.
+func topLevelCommand(cmd *cobra.Command) *cobra.Command {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd
+}
+
+// checkWrite returns an error when read-only mode blocks action, e.g.
+// "label Deployment/api". Call it before writes that bypass the Kubernetes
+// client: kubectl, cub unit apply and remedy commands.
+func checkWrite(action string) error {
+	if !readOnlyMode {
+		return nil
+	}
+	return fmt.Errorf("read-only mode: refusing to %s (pass --read-only=false to allow cluster writes)", action)
+}
+
+// readOnlyRoundTripper rejects mutating Kubernetes API requests in
+// read-only mode. Access reviews and server-side dry runs change nothing
+// and are let through.
+type readOnlyRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (t *readOnlyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if readOnlyMode && !safeRequest(req) {
+		return nil, checkWrite(req.Method + " " + req.URL.Path)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// safeRequest reports whether req cannot change cluster state. Port-forwards
+// (deep-dive --open-ui) only open a connection to a pod.
+func safeRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	if req.Method == http.MethodPost {
+		for _, suffix := range []string{"/selfsubjectaccessreviews", "/selfsubjectrulesreviews", "/portforward"} {
+			if strings.HasSuffix(req.URL.Path, suffix) {
+				return true
+			}
+		}
+	}
+	return req.URL.Query().Get("dryRun") == "All"
+}
@@ -0,0 +1,130 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyReadOnly(t *testing.T) {
+	t.Cleanup(func() { readOnlyMode, readOnlyFlag = false, false })
+
+	// commands builds a fresh tree so parsed flags don't leak between cases
+	commands := func() (stale, importC *cobra.Command) {
+		root := &cobra.Command{Use: "cub-scout"}
+		root.PersistentFlags().BoolVar(&readOnlyFlag, "read-only", false, "")
+		mapC := &cobra.Command{Use: "map"}
+		stale = &cobra.Command{Use: "stale"}
+		importC = &cobra.Command{Use: "import"}
+		mapC.AddCommand(stale)
+		root.AddCommand(mapC, importC)
+		return stale, importC
+	}
+
+	tests := []struct {
+		name     string
+		isImport bool
+		args     []string
+		env      string
+		want     bool
+	}{
+		{"map subcommand defaults on", false, nil, "", true},
+		{"import defaults off", true, nil, "", false},
+		{"env turns import on", true, nil, "true", true},
+		{"flag overrides map default", false, []string{"--read-only=false"}, "", false},
+		{"flag overrides env", true, []string{"--read-only=false"}, "1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(readOnlyEnv, tt.env)
+			cmd, importC := commands()
+			if tt.isImport {
+				cmd = importC
+			}
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := applyReadOnly(cmd); err != nil {
+				t.Fatal(err)
+			}
+			if readOnlyMode != tt.want {
+				t.Errorf("readOnlyMode = %v, want %v", readOnlyMode, tt.want)
+			}
+		})
+	}
+
+	_, importC := commands()
+	t.Setenv(readOnlyEnv, "sometimes")
+	if err := applyReadOnly(importC); err == nil {
+		t.Errorf("invalid %s: want error", readOnlyEnv)
+	}
+}
+
+func TestReadOnlyRoundTripper(t *testing.T) {
+	t.Cleanup(func() { readOnlyMode = false })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	client := &http.Client{Transport: &readOnlyRoundTripper{next: http.DefaultTransport}}
+
+	do := func(method, path string) error {
+		req, _ := http.NewRequest(method, srv.URL+path, nil)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	readOnlyMode = true
+	for _, ok := range []struct{ method, path string }{
+		{http.MethodGet, "/apis/apps/v1/deployments"},
+		{http.MethodPost, "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews"},
+		{http.MethodPost, "/api/v1/namespaces/argocd/pods/argocd-server-0/portforward"},
+		{http.MethodPatch, "/apis/apps/v1/namespaces/prod/deployments/api?dryRun=All"},
+	} {
+		if err := do(ok.method, ok.path); err != nil {
+			t.Errorf("%s %s: %v, want allowed", ok.method, ok.path, err)
+		}
+	}
+	for _, blocked := range []string{http.MethodPatch, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		err := do(blocked, "/apis/apps/v1/namespaces/prod/deployments/api")
+		if err == nil || !strings.Contains(err.Error(), "read-only mode") {
+			t.Errorf("%s: err = %v, want read-only refusal", blocked, err)
+		}
+	}
+
+	readOnlyMode = false
+	if err := do(http.MethodPatch, "/apis/apps/v1/namespaces/prod/deployments/api"); err != nil {
+		t.Errorf("PATCH with read-only off: %v", err)
+	}
+	if err := checkWrite("label Deployment/api"); err != nil {
+		t.Errorf("checkWrite with read-only off: %v", err)
+	}
+}
+
+func TestDemoCleanupReadOnly(t *testing.T) {
+	readOnlyMode = true
+	t.Cleanup(func() { readOnlyMode = false })
+
+	// A refused delete is a failed cleanup, not a completed one
+	for name, cleanup := range map[string]func() error{
+		"quick":       cleanupDemoQuick,
+		"ccve":        cleanupDemoCCVE,
+		"query":       cleanupDemoQuery,
+		"break-glass": cleanupScenarioBreakGlass,
+	} {
+		if err := cleanup(); err == nil || !strings.Contains(err.Error(), "read-only mode") {
+			t.Errorf("%s: err = %v, want read-only refusal", name, err)
+		}
+	}
+	if err := kubectlDeleteNamespace("break-glass-demo"); err == nil || !strings.Contains(err.Error(), "delete namespace break-glass-demo") {
+		t.Errorf("namespace delete: err = %v, want read-only refusal", err)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/confighub/cub-scout/pkg/redact"
+)
+
+// RedactConfig is the redaction file, e.g.:
+//
+//	patterns:
+//	  - 'corp-[0-9a-f]{32}'      # masked wherever it appears
+//	  - 'license=(\S+)'          # only the capture group is masked
+//	disable: [jwt]               # built-in rules to turn off
+//
+// Secret data and the built-in token rules are always masked unless
+// disabled here.
+type RedactConfig struct {
+	Patterns []string `yaml:"patterns"`
+	Disable  []string `yaml:"disable"`
+}
+
+// RedactConfigFile returns the redaction config path: $CUB_SCOUT_REDACT,
+// then ~/.cub-scout/redact.yaml.
+func RedactConfigFile() string {
+	if file := os.Getenv("CUB_SCOUT_REDACT"); file != "" {
+		return file
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cub-scout", "redact.yaml")
+}
+
+// loadRedactor builds a Redactor from file, with only the built-in rules
+// when it does not exist.
+func loadRedactor(file string) (*redact.Redactor, error) {
+	var cfg RedactConfig
+	data, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+	}
+	r, err := redact.New(cfg.Patterns, cfg.Disable)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	return r, nil
+}
+
+var (
+	redactorOnce sync.Once
+	redactor     *redact.Redactor
+)
+
+// activeRedactor loads the redaction config once per process. A broken file
+// is reported and the built-in rules are used.
+func activeRedactor() *redact.Redactor {
+	redactorOnce.Do(func() {
+		var err error
+		redactor, err = loadRedactor(RedactConfigFile())
+		if err != nil {
+			logger.Warn("using built-in redaction rules only", "err", err)
+			redactor, _ = redact.New(nil, nil)
+		}
+	})
+	return redactor
+}
+
+// redactStdout sends everything printed to os.Stdout through the redactor
+// until the returned func is called, for commands like deep-dive that print
+// cluster data from many places.
+func redactStdout() (restore func()) {
+	r := activeRedactor()
+	orig := os.Stdout
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return func() {}
+	}
+	os.Stdout = pw
+	done := make(chan struct{})
+	go func() {
+		_ = r.Lines(orig, pr)
+		pr.Close()
+		close(done)
+	}()
+	return func() {
+		os.Stdout = orig
+		pw.Close()
+		<-done
+	}
+}
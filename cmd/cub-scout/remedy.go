@@ -78,6 +78,7 @@ func init() {
 
 	remedyCmd.Flags().BoolVar(&remedyDryRun, "dry-run", true, "Show what would be changed (default: true)")
 	remedyCmd.Flags().StringVarP(&remedyNamespace, "namespace", "n", "", "Namespace to operate in")
+	_ = remedyCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
 	remedyCmd.Flags().BoolVar(&remedyForce, "force", false, "Skip confirmation for high-risk actions")
 	remedyCmd.Flags().BoolVar(&remedyAll, "all", false, "Fix all auto-fixable issues")
 	remedyCmd.Flags().BoolVar(&remedyJSON, "json", false, "Output as JSON")
@@ -138,6 +139,12 @@ func runRemedy(cmd *cobra.Command, args []string) error {
 		return listAutoFixableCCVEs()
 	}
 
+	if !remedyDryRun {
+		if err := checkWrite("execute remedies"); err != nil {
+			return err
+		}
+	}
+
 	// Build registry
 	reg := remedy.DefaultRegistry()
 
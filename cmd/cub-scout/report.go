@@ -0,0 +1,333 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	reportUpgradeTarget    string
+	reportUpgradeNamespace string
+	reportUpgradeThreshold string
+	reportUpgradeJSON      bool
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Readiness reports that combine several scans",
+}
+
+var reportUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Go/no-go summary per namespace and owner before a cluster upgrade",
+	Long: `Check whether the cluster is ready to upgrade to --target, combining:
+
+  - deprecated APIs: resources applied with an API the target version
+    removes (see 'scan --deprecated-apis')
+  - PDB coverage: PodDisruptionBudgets that allow no disruptions block
+    node drains; multi-replica workloads without a PDB lose all replicas
+    when their nodes drain together
+  - deployer health: stuck HelmReleases, Kustomizations and Applications
+    can't ship the fixes
+
+Findings are grouped by namespace and owner. A group is no-go when it has a
+removed API, a PDB blocking drains, or a stuck deployer; workloads without a
+PDB are warnings. The command exits 1 when any group is no-go.
+
+Examples:
+  cub-scout report upgrade --target 1.31
+  cub-scout report upgrade -n payments
+  cub-scout report upgrade --target 1.31 --json`,
+	Args: cobra.NoArgs,
+	RunE: runReportUpgrade,
+}
+
+func init() {
+	reportUpgradeCmd.Flags().StringVar(&reportUpgradeTarget, "target", "", "Kubernetes version to upgrade to (default: one minor above the cluster)")
+	reportUpgradeCmd.Flags().StringVarP(&reportUpgradeNamespace, "namespace", "n", "", "Only report this namespace (default: all namespaces)")
+	_ = reportUpgradeCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	reportUpgradeCmd.Flags().StringVar(&reportUpgradeThreshold, "threshold", "5m", "How long a deployer must be failing to count as stuck")
+	reportUpgradeCmd.Flags().BoolVar(&reportUpgradeJSON, "json", false, "Output as JSON")
+	reportCmd.AddCommand(reportUpgradeCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+// UpgradeReport is the upgrade readiness of each namespace and owner.
+type UpgradeReport struct {
+	ServerVersion string `json:"serverVersion,omitempty"`
+	TargetVersion string `json:"targetVersion,omitempty"`
+	// Verdict is "go" or "no-go" for the cluster as a whole
+	Verdict string         `json:"verdict"`
+	Groups  []UpgradeGroup `json:"groups"`
+	Summary struct {
+		Groups   int `json:"groups"`
+		NoGo     int `json:"noGo"`
+		Blockers int `json:"blockers"`
+		Warnings int `json:"warnings"`
+	} `json:"summary"`
+}
+
+// UpgradeGroup is the readiness of one owner's resources in a namespace.
+type UpgradeGroup struct {
+	Namespace string        `json:"namespace"`
+	Owner     string        `json:"owner"`
+	Verdict   string        `json:"verdict"`
+	Blockers  []UpgradeItem `json:"blockers,omitempty"`
+	Warnings  []UpgradeItem `json:"warnings,omitempty"`
+}
+
+// UpgradeItem is one readiness finding.
+type UpgradeItem struct {
+	// Check is deprecated-api, pdb or deployer
+	Check    string `json:"check"`
+	Resource string `json:"resource"`
+	Message  string `json:"message"`
+}
+
+func runReportUpgrade(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	threshold, err := time.ParseDuration(reportUpgradeThreshold)
+	if err != nil {
+		return fmt.Errorf("invalid threshold duration %q: %w", reportUpgradeThreshold, err)
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	serverVersion := ""
+	if dc, err := discovery.NewDiscoveryClientForConfig(cfg); err == nil {
+		if v, err := dc.ServerVersion(); err == nil {
+			serverVersion = v.GitVersion
+		}
+	}
+	target, err := deprecatedAPITarget(serverVersion, reportUpgradeTarget)
+	if err != nil {
+		return err
+	}
+
+	scanner := agent.NewStateScannerWithClient(dynClient)
+	deprecated, err := scanner.ScanDeprecatedAPIs(ctx, serverVersion, target)
+	if err != nil {
+		return fmt.Errorf("deprecated API scan: %w", err)
+	}
+	var stuck *agent.StateScanResult
+	if reportUpgradeNamespace != "" {
+		stuck, err = scanner.ScanNamespaceWithThreshold(ctx, reportUpgradeNamespace, threshold)
+	} else {
+		stuck, err = scanner.ScanWithThreshold(ctx, threshold)
+	}
+	if err != nil {
+		return fmt.Errorf("deployer health scan: %w", err)
+	}
+
+	pdbs := listAll(ctx, dynClient, schema.GroupVersionResource{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"})
+	var workloads []unstructured.Unstructured
+	for _, resource := range []string{"deployments", "statefulsets"} {
+		workloads = append(workloads, listAll(ctx, dynClient, schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: resource})...)
+	}
+
+	report := buildUpgradeReport(serverVersion, target, deprecated.Findings, pdbs, workloads, stuck.Findings, reportUpgradeNamespace)
+
+	if reportUpgradeJSON {
+		if err := writeJSON(os.Stdout, "UpgradeReport", report); err != nil {
+			return err
+		}
+	} else {
+		printUpgradeReport(os.Stdout, report)
+	}
+	if report.Verdict == "no-go" {
+		return fmt.Errorf("upgrade to %s: no-go for %d of %d group(s)", displayTarget(target), report.Summary.NoGo, report.Summary.Groups)
+	}
+	return nil
+}
+
+// buildUpgradeReport groups deprecated API findings, PDB coverage and stuck
+// deployers by namespace and owner. A non-empty namespace limits the report
+// to it.
+func buildUpgradeReport(serverVersion, target string, deprecated []agent.DeprecatedAPIFinding, pdbs, workloads []unstructured.Unstructured, stuck []agent.StuckFinding, namespace string) *UpgradeReport {
+	report := &UpgradeReport{ServerVersion: serverVersion, TargetVersion: target, Verdict: "go", Groups: []UpgradeGroup{}}
+	groups := map[string]*UpgradeGroup{}
+	add := func(ns, owner string, blocker bool, item UpgradeItem) {
+		if namespace != "" && ns != namespace {
+			return
+		}
+		if ns == "" {
+			ns = "(cluster)"
+		}
+		key := ns + "\x00" + owner
+		g, ok := groups[key]
+		if !ok {
+			g = &UpgradeGroup{Namespace: ns, Owner: owner}
+			groups[key] = g
+		}
+		if blocker {
+			g.Blockers = append(g.Blockers, item)
+		} else {
+			g.Warnings = append(g.Warnings, item)
+		}
+	}
+
+	for _, f := range deprecated {
+		add(f.Namespace, displayOwner(f.Owner), true, UpgradeItem{
+			Check:    "deprecated-api",
+			Resource: f.Kind + "/" + f.Name,
+			Message:  f.Message,
+		})
+	}
+
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		allowed, _, _ := unstructured.NestedInt64(pdb.Object, "status", "disruptionsAllowed")
+		healthy, _, _ := unstructured.NestedInt64(pdb.Object, "status", "currentHealthy")
+		if allowed == 0 && healthy > 0 {
+			add(pdb.GetNamespace(), displayOwner(agent.DetectOwnership(pdb).Type), true, UpgradeItem{
+				Check:    "pdb",
+				Resource: "PodDisruptionBudget/" + pdb.GetName(),
+				Message:  "allows 0 disruptions; node drains during the upgrade will hang",
+			})
+		}
+	}
+	for i := range workloads {
+		w := &workloads[i]
+		replicas, found, _ := unstructured.NestedInt64(w.Object, "spec", "replicas")
+		if !found || replicas < 2 || isSystemNamespace(w.GetNamespace()) || pdbCovers(pdbs, w) {
+			continue
+		}
+		add(w.GetNamespace(), displayOwner(agent.DetectOwnership(w).Type), false, UpgradeItem{
+			Check:    "pdb",
+			Resource: w.GetKind() + "/" + w.GetName(),
+			Message:  fmt.Sprintf("%d replicas and no PodDisruptionBudget; a drain can evict all of them at once", replicas),
+		})
+	}
+
+	for _, f := range stuck {
+		owner := agent.OwnerFlux
+		if f.Kind == "Application" {
+			owner = agent.OwnerArgo
+		}
+		add(f.Namespace, displayOwner(owner), true, UpgradeItem{
+			Check:    "deployer",
+			Resource: f.Kind + "/" + f.Name,
+			Message:  "stuck: " + f.Message,
+		})
+	}
+
+	for _, g := range groups {
+		g.Verdict = "go"
+		if len(g.Blockers) > 0 {
+			g.Verdict = "no-go"
+			report.Verdict = "no-go"
+			report.Summary.NoGo++
+		}
+		report.Summary.Blockers += len(g.Blockers)
+		report.Summary.Warnings += len(g.Warnings)
+		report.Groups = append(report.Groups, *g)
+	}
+	report.Summary.Groups = len(report.Groups)
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if (a.Verdict == "no-go") != (b.Verdict == "no-go") {
+			return a.Verdict == "no-go"
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Owner < b.Owner
+	})
+	return report
+}
+
+// pdbCovers reports whether a PDB in the workload's namespace selects its
+// pod template.
+func pdbCovers(pdbs []unstructured.Unstructured, workload *unstructured.Unstructured) bool {
+	podLabels, _, _ := unstructured.NestedStringMap(workload.Object, "spec", "template", "metadata", "labels")
+	for i := range pdbs {
+		if pdbs[i].GetNamespace() != workload.GetNamespace() {
+			continue
+		}
+		raw, found, _ := unstructured.NestedMap(pdbs[i].Object, "spec", "selector")
+		if !found {
+			continue
+		}
+		var ls v1.LabelSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &ls); err != nil {
+			continue
+		}
+		selector, err := v1.LabelSelectorAsSelector(&ls)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(podLabels)) {
+			return true
+		}
+	}
+	return false
+}
+
+func displayTarget(target string) string {
+	if target == "" {
+		return "the next Kubernetes version"
+	}
+	return "Kubernetes " + target
+}
+
+func printUpgradeReport(w io.Writer, report *UpgradeReport) {
+	fmt.Fprintf(w, "%s%sUPGRADE READINESS: %s%s\n", colorBold, colorCyan, displayTarget(report.TargetVersion), colorReset)
+	if report.ServerVersion != "" {
+		fmt.Fprintf(w, "%sCluster is running %s%s\n", colorDim, report.ServerVersion, colorReset)
+	}
+	fmt.Fprintln(w)
+
+	if len(report.Groups) == 0 {
+		fmt.Fprintf(w, "%s%s✓ No deprecated APIs, PDB problems or stuck deployers found%s\n", colorBold, colorGreen, colorReset)
+		return
+	}
+
+	for _, g := range report.Groups {
+		verdict := colorGreen + "GO" + colorReset
+		if g.Verdict == "no-go" {
+			verdict = colorRed + "NO-GO" + colorReset
+		}
+		fmt.Fprintf(w, "%s%s / %s%s  %s\n", colorBold, g.Namespace, g.Owner, colorReset, verdict)
+		for _, item := range g.Blockers {
+			fmt.Fprintf(w, "  %s✗%s %-14s %s: %s\n", colorRed, colorReset, item.Check, item.Resource, item.Message)
+		}
+		for _, item := range g.Warnings {
+			fmt.Fprintf(w, "  %s⚠%s %-14s %s: %s\n", colorYellow, colorReset, item.Check, item.Resource, item.Message)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "════════════════════════════════════════════════════════════════════\n")
+	verdict := colorGreen + "GO" + colorReset
+	if report.Verdict == "no-go" {
+		verdict = colorRed + "NO-GO" + colorReset
+	}
+	fmt.Fprintf(w, "%s: %d of %d group(s) no-go, %d blocker(s), %d warning(s)\n",
+		verdict, report.Summary.NoGo, report.Summary.Groups, report.Summary.Blockers, report.Summary.Warnings)
+}
@@ -0,0 +1,89 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+func newTestPDB(namespace, name string, matchLabels map[string]interface{}, allowed, healthy int64) unstructured.Unstructured {
+	u := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "policy/v1",
+		"kind":       "PodDisruptionBudget",
+		"spec":       map[string]interface{}{"selector": map[string]interface{}{"matchLabels": matchLabels}},
+		"status":     map[string]interface{}{"disruptionsAllowed": allowed, "currentHealthy": healthy},
+	}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+// newTestPodWorkload is a Deployment whose pod template has podLabels.
+func newTestPodWorkload(namespace, name string, replicas int64, podLabels map[string]string) unstructured.Unstructured {
+	u := newTestWorkload("Deployment", namespace, name, replicas, nil)
+	_ = unstructured.SetNestedStringMap(u.Object, podLabels, "spec", "template", "metadata", "labels")
+	return *u
+}
+
+func TestBuildUpgradeReport(t *testing.T) {
+	deprecated := []agent.DeprecatedAPIFinding{
+		{Kind: "CronJob", Name: "report", Namespace: "jobs", Owner: agent.OwnerFlux, Message: "batch/v1beta1 CronJob is removed in Kubernetes 1.25"},
+	}
+	pdbs := []unstructured.Unstructured{
+		newTestPDB("web", "api", map[string]interface{}{"app": "api"}, 1, 3),
+		newTestPDB("db", "postgres", map[string]interface{}{"app": "postgres"}, 0, 1),
+	}
+	workloads := []unstructured.Unstructured{
+		newTestPodWorkload("web", "api", 3, map[string]string{"app": "api"}),
+		newTestPodWorkload("web", "worker", 2, map[string]string{"app": "worker"}),
+		newTestPodWorkload("web", "cron-ui", 1, map[string]string{"app": "cron-ui"}),
+	}
+	stuck := []agent.StuckFinding{{Kind: "Application", Name: "payments", Namespace: "argocd", Message: "sync failed"}}
+
+	report := buildUpgradeReport("v1.30.2", "1.31", deprecated, pdbs, workloads, stuck, "")
+	if report.Verdict != "no-go" || report.Summary.Groups != 4 || report.Summary.NoGo != 3 || report.Summary.Warnings != 1 {
+		t.Fatalf("report = %+v", report.Summary)
+	}
+
+	byNamespace := map[string]UpgradeGroup{}
+	for _, g := range report.Groups {
+		byNamespace[g.Namespace] = g
+	}
+	if g := byNamespace["web"]; g.Verdict != "go" || len(g.Warnings) != 1 || g.Warnings[0].Resource != "Deployment/worker" {
+		t.Errorf("web = %+v, want only the uncovered multi-replica worker", g)
+	}
+	if g := byNamespace["db"]; g.Verdict != "no-go" || g.Blockers[0].Check != "pdb" {
+		t.Errorf("db = %+v", g)
+	}
+	if g := byNamespace["argocd"]; g.Owner != "ArgoCD" || g.Blockers[0].Check != "deployer" {
+		t.Errorf("argocd = %+v", g)
+	}
+	if report.Groups[len(report.Groups)-1].Verdict != "go" {
+		t.Errorf("no-go groups should sort first: %+v", report.Groups)
+	}
+
+	scoped := buildUpgradeReport("v1.30.2", "1.31", deprecated, pdbs, workloads, stuck, "web")
+	if scoped.Verdict != "go" || scoped.Summary.Groups != 1 {
+		t.Errorf("namespace-scoped report = %+v", scoped.Summary)
+	}
+}
+
+func TestPrintUpgradeReport(t *testing.T) {
+	report := buildUpgradeReport("v1.30.2", "1.31", nil,
+		[]unstructured.Unstructured{newTestPDB("db", "postgres", map[string]interface{}{"app": "postgres"}, 0, 1)}, nil, nil, "")
+	var buf bytes.Buffer
+	printUpgradeReport(&buf, report)
+	out := buf.String()
+	for _, want := range []string{"UPGRADE READINESS: Kubernetes 1.31", "db / Native", "PodDisruptionBudget/postgres", "1 of 1 group(s) no-go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/internal/mapsvc"
+)
+
+var (
+	rolloutWait        time.Duration
+	rolloutUntilStable bool
+)
+
+// defaultRolloutWait bounds --wait given without a value, and --until-stable.
+const defaultRolloutWait = 5 * time.Minute
+
+// addRolloutWaitFlags adds --wait and --until-stable to a drift command.
+func addRolloutWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&rolloutWait, "wait", 0, "Wait up to this long for in-progress rollouts to settle before checking (--wait alone: 5m)")
+	cmd.Flags().Lookup("wait").NoOptDefVal = defaultRolloutWait.String()
+	cmd.Flags().BoolVar(&rolloutUntilStable, "until-stable", false, "Re-check after rollouts settle until two checks in a row agree")
+}
+
+var rolloutGVRs = []schema.GroupVersionResource{
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Group: "apps", Version: "v1", Resource: "daemonsets"},
+}
+
+// unsettledRollouts lists workloads still rolling out, as
+// "Kind/namespace/name (reason)". System namespaces are ignored.
+func unsettledRollouts(ctx context.Context, dynClient dynamic.Interface, namespace string) []string {
+	var pending []string
+	for _, gvr := range rolloutGVRs {
+		l, err := dynClient.Resource(gvr).Namespace(namespace).List(ctx, v1.ListOptions{})
+		if err != nil {
+			continue
+		}
+		for i := range l.Items {
+			obj := &l.Items[i]
+			if isSystemNamespace(obj.GetNamespace()) {
+				continue
+			}
+			if settled, reason := mapsvc.RolloutSettled(obj); !settled {
+				pending = append(pending, fmt.Sprintf("%s/%s/%s (%s)", obj.GetKind(), obj.GetNamespace(), obj.GetName(), reason))
+			}
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// rolloutGate delays a drift check until rollouts settle, so a check taken
+// mid-rollout doesn't report drift that is about to resolve itself.
+type rolloutGate struct {
+	timeout     time.Duration
+	untilStable bool
+	poll        time.Duration
+	unsettled   func() []string
+	now         func() time.Time
+	sleep       func(time.Duration)
+	log         io.Writer
+}
+
+// newRolloutGate gates on the cluster's workloads in namespace ("" for all)
+// using the --wait and --until-stable flags.
+func newRolloutGate(ctx context.Context, dynClient dynamic.Interface, namespace string) rolloutGate {
+	timeout := rolloutWait
+	if timeout == 0 && rolloutUntilStable {
+		timeout = defaultRolloutWait
+	}
+	return rolloutGate{
+		timeout:     timeout,
+		untilStable: rolloutUntilStable,
+		poll:        2 * time.Second,
+		unsettled:   func() []string { return unsettledRollouts(ctx, dynClient, namespace) },
+		now:         time.Now,
+		sleep:       time.Sleep,
+		log:         os.Stderr,
+	}
+}
+
+// run calls check once rollouts have settled, or once the timeout passes.
+// check returns a summary of what it found; with untilStable, check runs
+// again after every poll until two summaries in a row match. A gate with no
+// timeout calls check once, immediately.
+func (g rolloutGate) run(check func() (string, error)) error {
+	if g.timeout <= 0 {
+		_, err := check()
+		return err
+	}
+	deadline := g.now().Add(g.timeout)
+
+	settle := func() {
+		announced := false
+		for {
+			pending := g.unsettled()
+			if len(pending) == 0 {
+				return
+			}
+			if !g.now().Before(deadline) {
+				fmt.Fprintf(g.log, "Warning: %d rollout(s) still in progress after %s; results may include transient drift:\n", len(pending), g.timeout)
+				for i, p := range pending {
+					if i == 5 {
+						fmt.Fprintf(g.log, "  ... and %d more\n", len(pending)-i)
+						break
+					}
+					fmt.Fprintf(g.log, "  %s\n", p)
+				}
+				return
+			}
+			if !announced {
+				fmt.Fprintf(g.log, "Waiting for %d rollout(s) to settle: %s\n", len(pending), strings.Join(firstN(pending, 3), ", "))
+				announced = true
+			}
+			g.sleep(g.poll)
+		}
+	}
+
+	settle()
+	last, err := check()
+	if err != nil || !g.untilStable {
+		return err
+	}
+	for g.now().Before(deadline) {
+		g.sleep(g.poll)
+		settle()
+		summary, err := check()
+		if err != nil {
+			return err
+		}
+		if summary == last {
+			return nil
+		}
+		last = summary
+	}
+	fmt.Fprintf(g.log, "Warning: results still changing after %s; showing the latest check\n", g.timeout)
+	return nil
+}
+
+func firstN(items []string, n int) []string {
+	if len(items) > n {
+		return append(items[:n:n], fmt.Sprintf("+%d more", len(items)-n))
+	}
+	return items
+}
+
+// localRolloutGate gates on the current kubeconfig cluster. Without --wait
+// or --until-stable, or when the cluster is unreachable, it doesn't wait.
+func localRolloutGate(ctx context.Context) rolloutGate {
+	if rolloutWait == 0 && !rolloutUntilStable {
+		return rolloutGate{}
+	}
+	cfg, err := buildConfig()
+	if err == nil {
+		var dynClient dynamic.Interface
+		if dynClient, err = dynamic.NewForConfig(cfg); err == nil {
+			return newRolloutGate(ctx, dynClient, "")
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Warning: cannot watch rollouts without cluster access (%v); not waiting\n", err)
+	g := newRolloutGate(ctx, nil, "")
+	g.unsettled = func() []string { return nil }
+	return g
+}
@@ -0,0 +1,104 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRolloutGate returns a gate on a fake clock whose workloads settle after
+// pendingPolls polls.
+func fakeRolloutGate(timeout time.Duration, untilStable bool, pendingPolls int) (*rolloutGate, *bytes.Buffer) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	var log bytes.Buffer
+	polls := 0
+	g := &rolloutGate{
+		timeout:     timeout,
+		untilStable: untilStable,
+		poll:        2 * time.Second,
+		unsettled: func() []string {
+			if polls < pendingPolls {
+				return []string{"Deployment/prod/api (1/3 updated)"}
+			}
+			return nil
+		},
+		now:   func() time.Time { return now },
+		sleep: func(d time.Duration) { polls++; now = now.Add(d) },
+		log:   &log,
+	}
+	return g, &log
+}
+
+func TestRolloutGateWaitsForRollouts(t *testing.T) {
+	g, log := fakeRolloutGate(time.Minute, false, 3)
+	checks := 0
+	if err := g.run(func() (string, error) { checks++; return "", nil }); err != nil {
+		t.Fatal(err)
+	}
+	if checks != 1 {
+		t.Errorf("checked %d times, want 1", checks)
+	}
+	if !strings.Contains(log.String(), "Waiting for 1 rollout(s) to settle: Deployment/prod/api (1/3 updated)") {
+		t.Errorf("log = %q", log.String())
+	}
+	if strings.Contains(log.String(), "Warning") {
+		t.Errorf("settled in time but warned: %q", log.String())
+	}
+}
+
+func TestRolloutGateTimesOut(t *testing.T) {
+	g, log := fakeRolloutGate(10*time.Second, false, 100)
+	checks := 0
+	if err := g.run(func() (string, error) { checks++; return "", nil }); err != nil {
+		t.Fatal(err)
+	}
+	if checks != 1 {
+		t.Errorf("checked %d times, want 1 after timing out", checks)
+	}
+	if !strings.Contains(log.String(), "1 rollout(s) still in progress after 10s") {
+		t.Errorf("log = %q", log.String())
+	}
+}
+
+func TestRolloutGateUntilStable(t *testing.T) {
+	g, _ := fakeRolloutGate(time.Minute, true, 0)
+	results := []string{"a", "b", "b", "c"}
+	checks := 0
+	err := g.run(func() (string, error) {
+		r := results[checks]
+		checks++
+		return r, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checks != 3 {
+		t.Errorf("checked %d times, want 3 (stop once two in a row agree)", checks)
+	}
+
+	g, log := fakeRolloutGate(5*time.Second, true, 0)
+	checks = 0
+	_ = g.run(func() (string, error) { checks++; return fmt.Sprint(checks), nil })
+	if !strings.Contains(log.String(), "results still changing after 5s") {
+		t.Errorf("log = %q", log.String())
+	}
+
+	g, _ = fakeRolloutGate(time.Minute, true, 0)
+	boom := errors.New("boom")
+	if err := g.run(func() (string, error) { return "", boom }); !errors.Is(err, boom) {
+		t.Errorf("err = %v, want check error", err)
+	}
+}
+
+func TestRolloutGateNoWait(t *testing.T) {
+	checks := 0
+	if err := (rolloutGate{}).run(func() (string, error) { checks++; return "", nil }); err != nil || checks != 1 {
+		t.Errorf("checks = %d, err = %v", checks, err)
+	}
+}
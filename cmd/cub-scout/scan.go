@@ -5,7 +5,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,6 +13,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/discovery"
 
 	"github.com/confighub/cub-scout/pkg/agent"
 	"github.com/confighub/cub-scout/pkg/hub"
@@ -32,6 +32,8 @@ var (
 	scanThreshold         string
 	scanFile              string
 	scanExplain           bool
+	scanDeprecatedAPIs    bool
+	scanTargetVersion     string
 )
 
 var scanCmd = &cobra.Command{
@@ -68,12 +70,27 @@ Examples:
   # Scan for dangling/orphan resources (HPA, Service, Ingress, NetworkPolicy)
   cub-scout scan --dangling
 
+  # Find resources using APIs removed by the next Kubernetes release
+  cub-scout scan --deprecated-apis
+  cub-scout scan --deprecated-apis --target-version 1.32
+
   # Output as JSON
   cub-scout scan --json
 
+  # Compare with a saved scan: new, resolved and persisting items
+  cub-scout scan --json > baseline.json
+  cub-scout scan --diff baseline.json
+  cub-scout scan --diff baseline.json --against later.json
+
   # Scan a YAML file (static analysis, no cluster required)
   cub-scout scan --file manifest.yaml
 
+  # Scan a GitOps repo directory before deploying (kustomize-aware)
+  cub-scout scan path ./clusters/prod
+
+  # Check kustomize overlays for hygiene problems (no build, no cluster)
+  cub-scout scan kustomize ./apps
+
   # List all KPOL policies in database
   cub-scout scan --list
 
@@ -90,6 +107,7 @@ func init() {
 	rootCmd.AddCommand(scanCmd)
 
 	scanCmd.Flags().StringVarP(&scanNamespace, "namespace", "n", "", "Namespace to scan (default: all namespaces)")
+	_ = scanCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
 	scanCmd.Flags().BoolVar(&scanJSON, "json", false, "Output as JSON")
 	scanCmd.Flags().BoolVar(&scanList, "list", false, "List all KPOL policies in database")
 	scanCmd.Flags().BoolVar(&scanVerbose, "verbose", false, "Show detailed output")
@@ -98,6 +116,8 @@ func init() {
 	scanCmd.Flags().BoolVar(&scanTimingBombs, "timing-bombs", false, "Scan for timing bombs (expiring certs, quota limits)")
 	scanCmd.Flags().BoolVar(&scanIncludeUnresolved, "include-unresolved", false, "Include unresolved findings from Trivy/Kyverno")
 	scanCmd.Flags().BoolVar(&scanDangling, "dangling", false, "Scan for dangling/orphan resources (HPA, Service, Ingress, NetworkPolicy)")
+	scanCmd.Flags().BoolVar(&scanDeprecatedAPIs, "deprecated-apis", false, "Scan for resources using APIs removed in upcoming Kubernetes versions")
+	scanCmd.Flags().StringVar(&scanTargetVersion, "target-version", "", "Kubernetes version to check removals against (default: one minor above the cluster)")
 	scanCmd.Flags().StringVar(&scanThreshold, "threshold", "5m", "Duration threshold for stuck detection (e.g., 30s, 2m, 5m)")
 	scanCmd.Flags().StringVar(&scanFile, "file", "", "YAML file to scan (static analysis, no cluster required)")
 	scanCmd.Flags().BoolVar(&scanExplain, "explain", false, "Show explanatory content to help learn GitOps risk concepts")
@@ -111,6 +131,8 @@ type CombinedScanResult struct {
 	Unresolved  *agent.UnresolvedResult `json:"unresolved,omitempty"`
 	Dangling    *agent.DanglingResult   `json:"dangling,omitempty"`
 	Static      *agent.StaticScanResult `json:"static,omitempty"`
+	// DeprecatedAPIs lists resources using APIs removed by the target version
+	DeprecatedAPIs *agent.DeprecatedAPIResult `json:"deprecatedApis,omitempty"`
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
@@ -142,10 +164,36 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return runFileScan(ctx, scanFile, policyDBDir)
 	}
 
+	// Diff mode - compare a baseline with another snapshot or the live cluster
+	if scanDiff != "" {
+		return runScanDiff(ctx, policyDBDir, scanDiff, scanAgainst)
+	}
+
+	result, err := collectClusterScan(ctx, policyDBDir)
+	if err != nil {
+		return err
+	}
+
+	// Output results
+	if scanJSON {
+		return outputCombinedJSON(result)
+	}
+	if scanKyvernoOnly && result.Kyverno != nil && result.Kyverno.Error != "" {
+		// Only warn if Kyverno was explicitly requested
+		fmt.Printf("\n%s⚠ Kyverno not installed%s\n", colorYellow, colorReset)
+		fmt.Printf("  PolicyReport CRD not found in cluster.\n")
+		fmt.Printf("  Install Kyverno: https://kyverno.io/docs/installation/\n\n")
+		return nil
+	}
+	return outputCombinedHuman(result.Kyverno, result.State, result.TimingBombs, result.Unresolved, result.Dangling, result.DeprecatedAPIs)
+}
+
+// collectClusterScan runs the scanners the flags select against the cluster.
+func collectClusterScan(ctx context.Context, policyDBDir string) (*CombinedScanResult, error) {
 	// Build k8s config
 	cfg, err := buildConfig()
 	if err != nil {
-		return fmt.Errorf("failed to build kubernetes config: %w", err)
+		return nil, fmt.Errorf("failed to build kubernetes config: %w", err)
 	}
 
 	// Determine what to scan (default: both)
@@ -162,12 +210,13 @@ func runScan(cmd *cobra.Command, args []string) error {
 	var timingBombResult *agent.TimingBombResult
 	var unresolvedResult *agent.UnresolvedResult
 	var danglingResult *agent.DanglingResult
+	var deprecatedResult *agent.DeprecatedAPIResult
 
 	// Run Kyverno scan
 	if runKyverno {
 		scanner, err := agent.NewKyvernoScanner(cfg, policyDBDir)
 		if err != nil {
-			return fmt.Errorf("failed to create kyverno scanner: %w", err)
+			return nil, fmt.Errorf("failed to create kyverno scanner: %w", err)
 		}
 
 		if scanner.Available(ctx) {
@@ -177,19 +226,12 @@ func runScan(cmd *cobra.Command, args []string) error {
 				kyvernoResult, err = scanner.Scan(ctx)
 			}
 			if err != nil {
-				return fmt.Errorf("kyverno scan failed: %w", err)
+				return nil, fmt.Errorf("kyverno scan failed: %w", err)
 			}
 		} else if scanKyvernoOnly {
-			// Only warn if Kyverno was explicitly requested
-			if scanJSON {
-				return outputCombinedJSON(&CombinedScanResult{
-					Kyverno: &agent.ScanResult{Error: "Kyverno not installed or PolicyReport CRD not found"},
-				})
-			}
-			fmt.Printf("\n%s⚠ Kyverno not installed%s\n", colorYellow, colorReset)
-			fmt.Printf("  PolicyReport CRD not found in cluster.\n")
-			fmt.Printf("  Install Kyverno: https://kyverno.io/docs/installation/\n\n")
-			return nil
+			return &CombinedScanResult{
+				Kyverno: &agent.ScanResult{Error: "Kyverno not installed or PolicyReport CRD not found"},
+			}, nil
 		}
 	}
 
@@ -197,13 +239,13 @@ func runScan(cmd *cobra.Command, args []string) error {
 	if runState {
 		stateScanner, err := agent.NewStateScanner(cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create state scanner: %w", err)
+			return nil, fmt.Errorf("failed to create state scanner: %w", err)
 		}
 
 		// Parse threshold duration
 		threshold, err := time.ParseDuration(scanThreshold)
 		if err != nil {
-			return fmt.Errorf("invalid threshold duration %q: %w", scanThreshold, err)
+			return nil, fmt.Errorf("invalid threshold duration %q: %w", scanThreshold, err)
 		}
 
 		if scanNamespace != "" {
@@ -212,7 +254,7 @@ func runScan(cmd *cobra.Command, args []string) error {
 			stateResult, err = stateScanner.ScanWithThreshold(ctx, threshold)
 		}
 		if err != nil {
-			return fmt.Errorf("state scan failed: %w", err)
+			return nil, fmt.Errorf("state scan failed: %w", err)
 		}
 	}
 
@@ -220,12 +262,12 @@ func runScan(cmd *cobra.Command, args []string) error {
 	if scanTimingBombs {
 		stateScanner, err := agent.NewStateScanner(cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create state scanner for timing bombs: %w", err)
+			return nil, fmt.Errorf("failed to create state scanner for timing bombs: %w", err)
 		}
 
 		timingBombResult, err = stateScanner.ScanTimingBombs(ctx)
 		if err != nil {
-			return fmt.Errorf("timing bomb scan failed: %w", err)
+			return nil, fmt.Errorf("timing bomb scan failed: %w", err)
 		}
 	}
 
@@ -233,12 +275,12 @@ func runScan(cmd *cobra.Command, args []string) error {
 	if scanIncludeUnresolved {
 		stateScanner, err := agent.NewStateScanner(cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create state scanner for unresolved: %w", err)
+			return nil, fmt.Errorf("failed to create state scanner for unresolved: %w", err)
 		}
 
 		unresolvedResult, err = stateScanner.ScanUnresolvedFindings(ctx)
 		if err != nil {
-			return fmt.Errorf("unresolved findings scan failed: %w", err)
+			return nil, fmt.Errorf("unresolved findings scan failed: %w", err)
 		}
 	}
 
@@ -246,26 +288,47 @@ func runScan(cmd *cobra.Command, args []string) error {
 	if scanDangling {
 		stateScanner, err := agent.NewStateScanner(cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create state scanner for dangling: %w", err)
+			return nil, fmt.Errorf("failed to create state scanner for dangling: %w", err)
 		}
 
 		danglingResult, err = stateScanner.ScanDanglingResources(ctx)
 		if err != nil {
-			return fmt.Errorf("dangling resources scan failed: %w", err)
+			return nil, fmt.Errorf("dangling resources scan failed: %w", err)
 		}
 	}
 
-	// Output results
-	if scanJSON {
-		return outputCombinedJSON(&CombinedScanResult{
-			Kyverno:     kyvernoResult,
-			State:       stateResult,
-			TimingBombs: timingBombResult,
-			Unresolved:  unresolvedResult,
-			Dangling:    danglingResult,
-		})
-	}
-	return outputCombinedHuman(kyvernoResult, stateResult, timingBombResult, unresolvedResult, danglingResult)
+	// Run Deprecated APIs scan
+	if scanDeprecatedAPIs {
+		stateScanner, err := agent.NewStateScanner(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create state scanner for deprecated APIs: %w", err)
+		}
+
+		serverVersion := ""
+		if dc, err := discovery.NewDiscoveryClientForConfig(cfg); err == nil {
+			if v, err := dc.ServerVersion(); err == nil {
+				serverVersion = v.GitVersion
+			}
+		}
+		targetVersion, err := deprecatedAPITarget(serverVersion, scanTargetVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		deprecatedResult, err = stateScanner.ScanDeprecatedAPIs(ctx, serverVersion, targetVersion)
+		if err != nil {
+			return nil, fmt.Errorf("deprecated API scan failed: %w", err)
+		}
+	}
+
+	return &CombinedScanResult{
+		Kyverno:        kyvernoResult,
+		State:          stateResult,
+		TimingBombs:    timingBombResult,
+		Unresolved:     unresolvedResult,
+		Dangling:       danglingResult,
+		DeprecatedAPIs: deprecatedResult,
+	}, nil
 }
 
 // findPolicyDBDir locates the Kyverno policy database
@@ -320,9 +383,7 @@ func listKPOLPolicies(policyDBDir string) error {
 	})
 
 	if scanJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(policies)
+		return writeJSON(os.Stdout, "PolicyCatalog", policies)
 	}
 
 	// Human output
@@ -348,9 +409,7 @@ func listKPOLPolicies(policyDBDir string) error {
 
 // outputCombinedJSON outputs the combined scan result as JSON
 func outputCombinedJSON(result *CombinedScanResult) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(result)
+	return writeJSON(os.Stdout, "ScanResult", result)
 }
 
 // outputFinding outputs a single finding
@@ -400,8 +459,8 @@ func severityColor(severity string) string {
 	}
 }
 
-// outputCombinedHuman outputs Kyverno, state, timing bomb, unresolved, and dangling results in human-readable format
-func outputCombinedHuman(kyvernoResult *agent.ScanResult, stateResult *agent.StateScanResult, timingBombResult *agent.TimingBombResult, unresolvedResult *agent.UnresolvedResult, danglingResult *agent.DanglingResult) error {
+// outputCombinedHuman outputs Kyverno, state, timing bomb, unresolved, dangling, and deprecated API results in human-readable format
+func outputCombinedHuman(kyvernoResult *agent.ScanResult, stateResult *agent.StateScanResult, timingBombResult *agent.TimingBombResult, unresolvedResult *agent.UnresolvedResult, danglingResult *agent.DanglingResult, deprecatedResult *agent.DeprecatedAPIResult) error {
 	fmt.Printf("\n")
 
 	// Explanatory content when --explain is used
@@ -421,6 +480,9 @@ func outputCombinedHuman(kyvernoResult *agent.ScanResult, stateResult *agent.Sta
 		fmt.Printf("  %sDangling Resources%s — Resources pointing to nothing\n", colorPurple, colorReset)
 		fmt.Printf("       HPA, Service, Ingress targeting deleted workloads\n")
 		fmt.Printf("       Risk: Broken routing, wasted capacity\n\n")
+		fmt.Printf("  %sDeprecated APIs%s — Manifests using APIs a Kubernetes upgrade removes\n", colorYellow, colorReset)
+		fmt.Printf("       extensions/v1beta1, batch/v1beta1, autoscaling/v2beta2, ...\n")
+		fmt.Printf("       Risk: Applies fail after the upgrade; the owning team must migrate\n\n")
 		fmt.Printf("%sEach finding has a CCVE ID (e.g., CCVE-2025-0027) from our Risk Scorecard database.%s\n", colorDim, colorReset)
 		fmt.Printf("%sSee: https://github.com/confighubai/confighub-scan%s\n", colorDim, colorReset)
 		fmt.Printf("\n")
@@ -484,6 +546,9 @@ func outputCombinedHuman(kyvernoResult *agent.ScanResult, stateResult *agent.Sta
 			colorRed, stateResult.Summary.HelmReleaseStuck, colorReset,
 			colorYellow, stateResult.Summary.KustomizationStuck, colorReset,
 			colorCyan, stateResult.Summary.ApplicationStuck, colorReset)
+		if gaps := stateResult.Summary.AlertingGaps; gaps > 0 {
+			fmt.Printf("%s%d deployer(s) have no alerting; see cub-scout map deployers --verbose%s\n\n", colorDim, gaps, colorReset)
+		}
 	}
 
 	// Output Kyverno findings
@@ -745,6 +810,16 @@ func outputCombinedHuman(kyvernoResult *agent.ScanResult, stateResult *agent.Sta
 		fmt.Printf("%s%s✓ No dangling resources found%s\n\n", colorBold, colorGreen, colorReset)
 	}
 
+	// Output deprecated API findings
+	if deprecatedResult != nil && len(deprecatedResult.Findings) > 0 {
+		hasOutput = true
+		fmt.Printf("\n")
+		outputDeprecatedAPIs(deprecatedResult)
+	} else if scanDeprecatedAPIs {
+		fmt.Printf("\n%s%sDEPRECATED API SCAN%s\n", colorBold, colorCyan, colorReset)
+		fmt.Printf("%s%s✓ No resources use removed APIs%s\n\n", colorBold, colorGreen, colorReset)
+	}
+
 	if !hasOutput {
 		fmt.Printf("%s%s✓ No issues found%s\n\n", colorBold, colorGreen, colorReset)
 	}
@@ -765,6 +840,80 @@ func outputCombinedHuman(kyvernoResult *agent.ScanResult, stateResult *agent.Sta
 	return nil
 }
 
+// deprecatedAPITarget returns the Kubernetes version to check removals
+// against: the --target-version flag, else one minor above the server.
+func deprecatedAPITarget(serverVersion, flag string) (string, error) {
+	if flag != "" {
+		if _, ok := agent.ParseMinorVersion(flag); !ok {
+			return "", fmt.Errorf("invalid --target-version %q (expected e.g. 1.32)", flag)
+		}
+		return flag, nil
+	}
+	if minor, ok := agent.ParseMinorVersion(serverVersion); ok {
+		return fmt.Sprintf("1.%d", minor+1), nil
+	}
+	return "", nil // unknown: report every known removal
+}
+
+// outputDeprecatedAPIs prints deprecated API findings grouped by the owner
+// that has to migrate them.
+func outputDeprecatedAPIs(result *agent.DeprecatedAPIResult) {
+	fmt.Printf("%s%sDEPRECATED API SCAN%s\n", colorBold, colorCyan, colorReset)
+	switch {
+	case result.ServerVersion != "" && result.TargetVersion != "":
+		fmt.Printf("%sCluster %s, checking removals through Kubernetes %s%s\n\n", colorDim, result.ServerVersion, result.TargetVersion, colorReset)
+	case result.TargetVersion != "":
+		fmt.Printf("%sChecking removals through Kubernetes %s%s\n\n", colorDim, result.TargetVersion, colorReset)
+	default:
+		fmt.Printf("%sCluster version unknown, checking all known removals%s\n\n", colorDim, colorReset)
+	}
+
+	type group struct {
+		title    string
+		findings []agent.DeprecatedAPIFinding
+	}
+	var groups []*group
+	byOwner := map[string]*group{}
+	for _, f := range result.Findings {
+		title := displayOwner(f.Owner)
+		if f.Unit != "" {
+			title += " unit " + f.Unit
+		} else if f.OwnerName != "" {
+			title += " " + f.OwnerName
+		}
+		g, ok := byOwner[title]
+		if !ok {
+			g = &group{title: title}
+			byOwner[title] = g
+			groups = append(groups, g)
+		}
+		g.findings = append(g.findings, f)
+	}
+
+	for _, g := range groups {
+		fmt.Printf("%s%s (%d)%s\n", colorBold, g.title, len(g.findings), colorReset)
+		fmt.Printf("────────────────────────────────────────────────────────────────────\n")
+		for _, f := range g.findings {
+			resource := f.Kind + "/" + f.Name
+			if f.Namespace != "" {
+				resource = f.Namespace + "/" + resource
+			}
+			fmt.Printf("%s[%s]%s %s %s%s%s\n", severityColor(f.Severity), strings.ToUpper(f.Severity[:1]), colorReset, resource, colorDim, f.APIVersion, colorReset)
+			fmt.Printf("  %sRemoved in:%s %s", colorDim, colorReset, f.RemovedIn)
+			if f.Replacement != "" {
+				fmt.Printf("  %sMigrate to:%s %s", colorDim, colorReset, f.Replacement)
+			}
+			fmt.Printf("  %sFound in:%s %s\n", colorDim, colorReset, f.Source)
+		}
+		fmt.Printf("\n")
+	}
+
+	fmt.Printf("════════════════════════════════════════════════════════════════════\n")
+	fmt.Printf("Deprecated APIs: %s%d removed%s, %s%d upcoming%s\n\n",
+		colorRed, result.Summary.Removed, colorReset,
+		colorYellow, result.Summary.Upcoming, colorReset)
+}
+
 // outputStuckFinding outputs a single stuck finding with remediation
 func outputStuckFinding(f agent.StuckFinding) {
 	sevColor := severityColor(f.Severity)
@@ -1021,6 +1170,9 @@ func outputStaticFinding(f agent.StaticFinding) {
 		resource = fmt.Sprintf("%s/%s/%s", f.Namespace, f.Kind, f.ResourceName)
 	}
 	fmt.Printf("  %sResource:%s %s\n", colorDim, colorReset, resource)
+	if f.File != "" && scanFile == "" {
+		fmt.Printf("  %sFile:%s %s\n", colorDim, colorReset, f.File)
+	}
 
 	// Message
 	if f.Message != "" {
@@ -0,0 +1,362 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	// scanDiff is the baseline snapshot to compare against (--diff)
+	scanDiff string
+	// scanAgainst is the snapshot to compare the baseline with (--against);
+	// the live cluster when empty
+	scanAgainst string
+)
+
+func init() {
+	scanCmd.Flags().StringVar(&scanDiff, "diff", "", "Compare against a baseline snapshot (scan --json or snapshot output) and report new, resolved and persisting items")
+	scanCmd.Flags().StringVar(&scanAgainst, "against", "", "Snapshot to compare the --diff baseline with (default: scan the live cluster)")
+}
+
+// ScanDiffItem is one finding, orphan or drift item of a snapshot.
+type ScanDiffItem struct {
+	// Category is the scanner the item comes from: kyverno, stuck,
+	// timing-bomb, unresolved, dangling, deprecated-api, static, or orphan
+	// and drift for resource snapshots
+	Category string `json:"category"`
+	// ID is the CCVE, policy or rule, when the item has one
+	ID        string `json:"id,omitempty"`
+	Severity  string `json:"severity,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Message   string `json:"message,omitempty"`
+
+	// key identifies the item across snapshots; messages with durations
+	// and counts change between runs and are left out
+	key string
+}
+
+// ScanDiff is the result of scan --diff.
+type ScanDiff struct {
+	Baseline   string         `json:"baseline"`
+	Current    string         `json:"current"`
+	New        []ScanDiffItem `json:"new"`
+	Resolved   []ScanDiffItem `json:"resolved"`
+	Persisting []ScanDiffItem `json:"persisting"`
+
+	// Skipped lists the categories only one side scanned (e.g. the baseline
+	// ran --timing-bombs and the live scan didn't); they are not compared
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// scanSnapshot is a parsed snapshot file: a scan --json result or a
+// snapshot (GSF) dump.
+type scanSnapshot struct {
+	scan *CombinedScanResult
+	gsf  *GSFSnapshot
+}
+
+// loadScanSnapshot reads a snapshot file, accepting both the bare --json
+// output and the --output-version v1 envelope.
+func loadScanSnapshot(file string) (scanSnapshot, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return scanSnapshot{}, err
+	}
+	snap, err := parseScanSnapshot(data)
+	if err != nil {
+		return scanSnapshot{}, fmt.Errorf("parse %s: %w", file, err)
+	}
+	return snap, nil
+}
+
+func parseScanSnapshot(data []byte) (scanSnapshot, error) {
+	var probe struct {
+		APIVersion string          `json:"apiVersion"`
+		Kind       string          `json:"kind"`
+		Data       json.RawMessage `json:"data"`
+		Version    string          `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return scanSnapshot{}, err
+	}
+	if probe.APIVersion == outputAPIVersion {
+		if probe.Kind != "ScanResult" {
+			return scanSnapshot{}, fmt.Errorf("kind %s is not a scan result", probe.Kind)
+		}
+		data = probe.Data
+	}
+
+	if strings.HasPrefix(probe.Version, "gsf/") {
+		var gsf GSFSnapshot
+		if err := json.Unmarshal(data, &gsf); err != nil {
+			return scanSnapshot{}, err
+		}
+		return scanSnapshot{gsf: &gsf}, nil
+	}
+	var scan CombinedScanResult
+	if err := json.Unmarshal(data, &scan); err != nil {
+		return scanSnapshot{}, fmt.Errorf("not a scan --json or snapshot file: %w", err)
+	}
+	if scan == (CombinedScanResult{}) {
+		return scanSnapshot{}, fmt.Errorf("not a scan --json or snapshot file")
+	}
+	return scanSnapshot{scan: &scan}, nil
+}
+
+// items flattens the snapshot into comparable items.
+func (s scanSnapshot) items() []ScanDiffItem {
+	if s.gsf != nil {
+		return gsfDiffItems(s.gsf)
+	}
+	return scanDiffItems(s.scan)
+}
+
+// categories returns the categories the snapshot covers: every scanner that
+// ran, whether or not it found anything.
+func (s scanSnapshot) categories() map[string]bool {
+	if s.gsf != nil {
+		return map[string]bool{"orphan": true, "drift": true}
+	}
+	r := s.scan
+	return map[string]bool{
+		"kyverno":        r.Kyverno != nil && r.Kyverno.Error == "",
+		"stuck":          r.State != nil,
+		"timing-bomb":    r.TimingBombs != nil,
+		"unresolved":     r.Unresolved != nil,
+		"dangling":       r.Dangling != nil,
+		"deprecated-api": r.DeprecatedAPIs != nil,
+		"static":         r.Static != nil,
+	}
+}
+
+// compareScanSnapshots diffs the categories both snapshots cover.
+func compareScanSnapshots(baseline, current scanSnapshot) ScanDiff {
+	inBaseline, inCurrent := baseline.categories(), current.categories()
+	var d ScanDiff
+	for c := range inBaseline {
+		if inBaseline[c] != inCurrent[c] {
+			d.Skipped = append(d.Skipped, c)
+		}
+	}
+	sort.Strings(d.Skipped)
+
+	covered := func(items []ScanDiffItem) []ScanDiffItem {
+		var out []ScanDiffItem
+		for _, it := range items {
+			if inBaseline[it.Category] && inCurrent[it.Category] {
+				out = append(out, it)
+			}
+		}
+		return out
+	}
+	d.New, d.Resolved, d.Persisting = diffScanItems(covered(baseline.items()), covered(current.items()))
+	return d
+}
+
+func newDiffItem(category, id, severity, kind, namespace, name, message string, keyParts ...string) ScanDiffItem {
+	key := strings.Join(append([]string{category, id, kind, namespace, name}, keyParts...), "|")
+	return ScanDiffItem{
+		Category: category, ID: id, Severity: severity,
+		Kind: kind, Namespace: namespace, Name: name, Message: message,
+		key: key,
+	}
+}
+
+// scanDiffItems flattens every scanner's findings.
+func scanDiffItems(r *CombinedScanResult) []ScanDiffItem {
+	var items []ScanDiffItem
+	if r.Kyverno != nil {
+		for _, f := range r.Kyverno.Findings {
+			id := f.PolicyID
+			if id == "" {
+				id = f.PolicyName
+			}
+			kind, name, _ := strings.Cut(f.Resource, "/")
+			items = append(items, newDiffItem("kyverno", id, f.Severity, kind, f.Namespace, name, f.Message, f.Rule))
+		}
+	}
+	if r.State != nil {
+		for _, f := range r.State.Findings {
+			items = append(items, newDiffItem("stuck", f.CCVEID, f.Severity, f.Kind, f.Namespace, f.Name, f.Message, f.Condition))
+		}
+	}
+	if r.TimingBombs != nil {
+		for _, f := range r.TimingBombs.Findings {
+			items = append(items, newDiffItem("timing-bomb", f.CCVEID, f.Severity, f.Kind, f.Namespace, f.Name, f.Message, f.Reason))
+		}
+	}
+	if r.Unresolved != nil {
+		for _, f := range r.Unresolved.Findings {
+			items = append(items, newDiffItem("unresolved", f.CCVEID, f.Severity, f.Kind, f.Namespace, f.Name, f.Message, f.Source, f.FindingType))
+		}
+	}
+	if r.Dangling != nil {
+		for _, f := range r.Dangling.Findings {
+			items = append(items, newDiffItem("dangling", f.CCVEID, f.Severity, f.Kind, f.Namespace, f.Name, f.Message, f.TargetKind, f.TargetName))
+		}
+	}
+	if r.DeprecatedAPIs != nil {
+		for _, f := range r.DeprecatedAPIs.Findings {
+			items = append(items, newDiffItem("deprecated-api", f.APIVersion, f.Severity, f.Kind, f.Namespace, f.Name, f.Message, f.RemovedIn))
+		}
+	}
+	if r.Static != nil {
+		for _, f := range r.Static.Findings {
+			items = append(items, newDiffItem("static", f.CCVEID, f.Severity, f.Kind, f.Namespace, f.ResourceName, f.Message, f.File))
+		}
+	}
+	return items
+}
+
+// gsfDiffItems lists a resource snapshot's orphans (no detected owner,
+// outside excluded namespaces, as map orphans shows them) and drifted
+// resources.
+func gsfDiffItems(s *GSFSnapshot) []ScanDiffItem {
+	var items []ScanDiffItem
+	for _, e := range s.Entries {
+		if e.Drift != nil {
+			items = append(items, newDiffItem("drift", e.Drift.Type, "", e.Kind, e.Namespace, e.Name, e.Drift.Summary))
+		}
+		if (e.Owner == nil || e.Owner.Type == "unknown") && !isSystemNamespace(e.Namespace) {
+			items = append(items, newDiffItem("orphan", "", "", e.Kind, e.Namespace, e.Name, "no GitOps or ConfigHub owner"))
+		}
+	}
+	return items
+}
+
+// diffScanItems splits the items of two snapshots into new (only in
+// current), resolved (only in baseline) and persisting (in both, reported
+// as in current).
+func diffScanItems(baseline, current []ScanDiffItem) (added, resolved, persisting []ScanDiffItem) {
+	added, resolved, persisting = []ScanDiffItem{}, []ScanDiffItem{}, []ScanDiffItem{}
+	inBaseline := map[string]bool{}
+	for _, it := range baseline {
+		inBaseline[it.key] = true
+	}
+	inCurrent := map[string]bool{}
+	for _, it := range current {
+		if inCurrent[it.key] {
+			continue
+		}
+		inCurrent[it.key] = true
+		if inBaseline[it.key] {
+			persisting = append(persisting, it)
+		} else {
+			added = append(added, it)
+		}
+	}
+	seen := map[string]bool{}
+	for _, it := range baseline {
+		if !inCurrent[it.key] && !seen[it.key] {
+			seen[it.key] = true
+			resolved = append(resolved, it)
+		}
+	}
+	for _, list := range [][]ScanDiffItem{added, resolved, persisting} {
+		sortDiffItems(list)
+	}
+	return added, resolved, persisting
+}
+
+func sortDiffItems(items []ScanDiffItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Category != items[j].Category {
+			return items[i].Category < items[j].Category
+		}
+		return items[i].key < items[j].key
+	})
+}
+
+// runScanDiff compares the baseline snapshot with another snapshot file or,
+// without --against, the live cluster scanned the same way.
+func runScanDiff(ctx context.Context, policyDBDir, baselineFile, currentFile string) error {
+	baseline, err := loadScanSnapshot(baselineFile)
+	if err != nil {
+		return err
+	}
+
+	var current scanSnapshot
+	currentName := currentFile
+	switch {
+	case currentFile != "":
+		if current, err = loadScanSnapshot(currentFile); err != nil {
+			return err
+		}
+		if (baseline.gsf == nil) != (current.gsf == nil) {
+			return fmt.Errorf("%s and %s are different kinds of snapshot; compare scan --json with scan --json, or snapshot with snapshot", baselineFile, currentFile)
+		}
+	case baseline.gsf != nil:
+		currentName = "live cluster"
+		cfg, err := buildConfig()
+		if err != nil {
+			return fmt.Errorf("build kubernetes config: %w", err)
+		}
+		dynClient, err := dynamic.NewForConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("create dynamic client: %w", err)
+		}
+		gsf := collectSnapshot(ctx, dynClient, baseline.gsf.Cluster, scanNamespace, "", false)
+		// Redacted like the saved snapshot, so masked values don't show as changes
+		redactSnapshot(activeRedactor(), &gsf)
+		current = scanSnapshot{gsf: &gsf}
+	default:
+		currentName = "live cluster"
+		result, err := collectClusterScan(ctx, policyDBDir)
+		if err != nil {
+			return err
+		}
+		current = scanSnapshot{scan: result}
+	}
+
+	diff := compareScanSnapshots(baseline, current)
+	diff.Baseline, diff.Current = baselineFile, currentName
+	if scanJSON {
+		return writeJSON(os.Stdout, "ScanDiff", diff)
+	}
+	printScanDiff(os.Stdout, diff)
+	return nil
+}
+
+func printScanDiff(w io.Writer, d ScanDiff) {
+	fmt.Fprintf(w, "\n%sSCAN DIFF%s  %s → %s\n", colorBold, colorReset, d.Baseline, d.Current)
+	fmt.Fprintln(w, "════════════════════════════════════════════════════════════════════")
+
+	section := func(title, marker, color string, items []ScanDiffItem) {
+		fmt.Fprintf(w, "\n%s%s (%d)%s\n", color, title, len(items), colorReset)
+		if len(items) == 0 {
+			fmt.Fprintf(w, "  %snone%s\n", colorDim, colorReset)
+			return
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, it := range items {
+			resource := it.Kind + "/" + it.Name
+			if it.Namespace != "" {
+				resource = it.Namespace + "/" + resource
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", marker, it.Category, orDash(it.ID), resource, truncate(it.Message, 70))
+		}
+		tw.Flush()
+	}
+	section("NEW", "+", colorRed, d.New)
+	section("RESOLVED", "-", colorGreen, d.Resolved)
+	section("PERSISTING", "=", colorYellow, d.Persisting)
+
+	fmt.Fprintf(w, "\n%d new, %d resolved, %d persisting\n", len(d.New), len(d.Resolved), len(d.Persisting))
+	if len(d.Skipped) > 0 {
+		fmt.Fprintf(w, "%sNot compared (scanned on one side only): %s. Run both scans with the same flags.%s\n",
+			colorDim, strings.Join(d.Skipped, ", "), colorReset)
+	}
+}
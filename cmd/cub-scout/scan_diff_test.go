@@ -0,0 +1,130 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+func stuck(ns, name, duration string) agent.StuckFinding {
+	return agent.StuckFinding{
+		CCVEID: "CCVE-2025-0027", Severity: "critical", Kind: "HelmRelease",
+		Namespace: ns, Name: name, Condition: "Ready=False",
+		Message: "stuck for " + duration,
+	}
+}
+
+func TestCompareScanSnapshots(t *testing.T) {
+	baseline := scanSnapshot{scan: &CombinedScanResult{
+		State: &agent.StateScanResult{Findings: []agent.StuckFinding{
+			stuck("apps", "redis", "2h"),
+			stuck("apps", "api", "40m"),
+		}},
+		Dangling: &agent.DanglingResult{Findings: []agent.DanglingFinding{
+			{CCVEID: "CCVE-2025-0687", Kind: "HorizontalPodAutoscaler", Namespace: "apps", Name: "old", TargetKind: "Deployment", TargetName: "gone"},
+		}},
+	}}
+	current := scanSnapshot{scan: &CombinedScanResult{
+		State: &agent.StateScanResult{Findings: []agent.StuckFinding{
+			// Same finding, later: only the message changed
+			stuck("apps", "redis", "9d"),
+			stuck("web", "frontend", "10m"),
+		}},
+	}}
+
+	d := compareScanSnapshots(baseline, current)
+	names := func(items []ScanDiffItem) string {
+		var out []string
+		for _, it := range items {
+			out = append(out, it.Namespace+"/"+it.Name)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := names(d.New); got != "web/frontend" {
+		t.Errorf("New = %s, want web/frontend", got)
+	}
+	if got := names(d.Resolved); got != "apps/api" {
+		t.Errorf("Resolved = %s, want apps/api", got)
+	}
+	if got := names(d.Persisting); got != "apps/redis" {
+		t.Errorf("Persisting = %s, want apps/redis", got)
+	}
+	if d.Persisting[0].Message != "stuck for 9d" {
+		t.Errorf("persisting item should carry the current message, got %q", d.Persisting[0].Message)
+	}
+	// The current scan didn't run --dangling, so the HPA isn't "resolved"
+	if strings.Join(d.Skipped, ",") != "dangling" {
+		t.Errorf("Skipped = %v, want [dangling]", d.Skipped)
+	}
+}
+
+func TestCompareGSFSnapshots(t *testing.T) {
+	entry := func(kind, ns, name string, owner *GSFOwner, drift *GSFDrift) GSFEntry {
+		return GSFEntry{Kind: kind, Namespace: ns, Name: name, Owner: owner, Drift: drift}
+	}
+	flux := &GSFOwner{Type: agent.OwnerFlux, Name: "apps"}
+	baseline := scanSnapshot{gsf: &GSFSnapshot{Version: "gsf/v1", Entries: []GSFEntry{
+		entry("Deployment", "apps", "legacy", nil, nil),
+		entry("Deployment", "apps", "api", flux, &GSFDrift{Type: "spec", Summary: "replicas 3 → 5"}),
+		entry("Pod", "kube-system", "coredns", nil, nil),
+	}}}
+	current := scanSnapshot{gsf: &GSFSnapshot{Version: "gsf/v1", Entries: []GSFEntry{
+		entry("Deployment", "apps", "legacy", flux, nil),
+		entry("Deployment", "apps", "api", flux, &GSFDrift{Type: "spec", Summary: "replicas 3 → 6"}),
+		entry("ConfigMap", "apps", "scratch", nil, nil),
+	}}}
+
+	d := compareScanSnapshots(baseline, current)
+	describe := func(items []ScanDiffItem) string {
+		var out []string
+		for _, it := range items {
+			out = append(out, it.Category+":"+it.Name)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := describe(d.New); got != "orphan:scratch" {
+		t.Errorf("New = %s", got)
+	}
+	if got := describe(d.Resolved); got != "orphan:legacy" {
+		t.Errorf("Resolved = %s (system namespaces are not orphans)", got)
+	}
+	if got := describe(d.Persisting); got != "drift:api" {
+		t.Errorf("Persisting = %s", got)
+	}
+}
+
+func TestParseScanSnapshot(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantGSF bool
+		wantErr string
+	}{
+		{name: "scan json", data: `{"state": {"findings": []}}`},
+		{name: "versioned scan json", data: `{"apiVersion": "cub-scout/v1", "kind": "ScanResult", "data": {"dangling": {"findings": []}}}`},
+		{name: "gsf snapshot", data: `{"version": "gsf/v1", "entries": []}`, wantGSF: true},
+		{name: "other kind", data: `{"apiVersion": "cub-scout/v1", "kind": "MapList", "data": []}`, wantErr: "not a scan result"},
+		{name: "unrelated json", data: `{"items": []}`, wantErr: "not a scan --json or snapshot file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap, err := parseScanSnapshot([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (snap.gsf != nil) != tt.wantGSF {
+				t.Errorf("gsf = %v, want %v", snap.gsf != nil, tt.wantGSF)
+			}
+		})
+	}
+}
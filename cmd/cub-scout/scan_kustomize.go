@@ -0,0 +1,141 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+	"github.com/confighub/cub-scout/pkg/gitops"
+)
+
+var scanKustomizeFailOn string
+
+var scanKustomizeCmd = &cobra.Command{
+	Use:   "kustomize <dir>",
+	Short: "Check kustomize overlays for hygiene problems",
+	Long: `Check every kustomization under a directory for overlay hygiene, without
+building it or contacting a cluster. It complements the live-cluster checks
+of 'scan' and the rendered-manifest checks of 'scan path'.
+
+Checks:
+  - overlays rendering namespaced resources with no namespace (CCVE-2025-0699)
+  - resource IDs registered twice in one build, which fails kustomize build,
+    and overlays of one base rendering the same IDs (CCVE-2025-0700)
+  - patches that are empty or match no resource (CCVE-2025-0701)
+  - remote bases, files and Helm charts not pinned to a tag, commit or
+    version (CCVE-2025-0702)
+
+An overlay is a kustomization no other kustomization under the directory
+includes. Resources are resolved from the files: namespace, namePrefix and
+nameSuffix are applied, generators and other transformers are not.
+
+The command exits 1 when a finding at or above --fail-on severity is found
+(default: critical), or when a kustomization can't be read.
+
+Examples:
+  cub-scout scan kustomize ./apps
+  cub-scout scan kustomize . --fail-on warning
+  cub-scout scan kustomize ./clusters --json`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runScanKustomize,
+}
+
+func init() {
+	scanKustomizeCmd.Flags().StringVar(&scanKustomizeFailOn, "fail-on", "critical", "Exit 1 on findings at or above this severity: "+strings.Join(scanFailOnLevels, ", "))
+	_ = scanKustomizeCmd.RegisterFlagCompletionFunc("fail-on", cobra.FixedCompletions(scanFailOnLevels, cobra.ShellCompDirectiveNoFileComp))
+	scanKustomizeCmd.Flags().BoolVar(&scanJSON, "json", false, "Output as JSON")
+	scanKustomizeCmd.Flags().BoolVar(&scanVerbose, "verbose", false, "Show info findings")
+
+	scanCmd.AddCommand(scanKustomizeCmd)
+}
+
+// KustomizeScanResult is the result of 'scan kustomize'.
+type KustomizeScanResult struct {
+	Path           string                `json:"path"`
+	ScannedAt      time.Time             `json:"scannedAt"`
+	Kustomizations int                   `json:"kustomizations"`
+	Overlays       []string              `json:"overlays"`
+	ResourceCount  int                   `json:"resourceCount"`
+	Findings       []agent.StaticFinding `json:"findings"`
+	// Errors are kustomizations that could not be read
+	Errors []string `json:"errors,omitempty"`
+}
+
+// kustomizeScanResult converts lint findings into static findings, so they
+// print and gate like those of 'scan path'.
+func kustomizeScanResult(root string, lint *gitops.KustomizeLintResult) KustomizeScanResult {
+	result := KustomizeScanResult{
+		Path:           root,
+		ScannedAt:      time.Now(),
+		Kustomizations: lint.Kustomizations,
+		Overlays:       lint.Overlays,
+		ResourceCount:  lint.Resources,
+		Findings:       []agent.StaticFinding{},
+		Errors:         lint.Errors,
+	}
+	for _, f := range lint.Findings {
+		result.Findings = append(result.Findings, agent.StaticFinding{
+			CCVEID:       f.CCVEID,
+			Name:         f.Name,
+			Kind:         f.Kind,
+			ResourceName: f.ResourceName,
+			Namespace:    f.Namespace,
+			File:         filepath.Join(root, f.Dir),
+			Severity:     f.Severity,
+			Category:     f.Category,
+			Message:      f.Message,
+			Remediation:  f.Remediation,
+		})
+	}
+	return result
+}
+
+func runScanKustomize(cmd *cobra.Command, args []string) error {
+	if !contains(scanFailOnLevels, scanKustomizeFailOn) {
+		return fmt.Errorf("unknown --fail-on %q (want %s)", scanKustomizeFailOn, strings.Join(scanFailOnLevels, ", "))
+	}
+	root := args[0]
+	lint, err := gitops.LintKustomizations(root)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", root, err)
+	}
+	result := kustomizeScanResult(root, lint)
+
+	if scanJSON {
+		if err := writeJSON(os.Stdout, "KustomizeScanResult", result); err != nil {
+			return err
+		}
+	} else {
+		printKustomizeScan(result)
+	}
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d kustomization(s) could not be read", len(result.Errors))
+	}
+	if n := countFindingsAtOrAbove(result.Findings, scanKustomizeFailOn); n > 0 {
+		return fmt.Errorf("%d finding(s) at or above %s severity", n, scanKustomizeFailOn)
+	}
+	return nil
+}
+
+func printKustomizeScan(result KustomizeScanResult) {
+	static := &agent.StaticScanResult{
+		File:          fmt.Sprintf("%s (%d kustomization(s), %d overlay(s))", result.Path, result.Kustomizations, len(result.Overlays)),
+		ResourceCount: result.ResourceCount,
+		Findings:      result.Findings,
+	}
+	_ = outputStaticScanHuman(static)
+
+	for _, e := range result.Errors {
+		fmt.Printf("%s✗ %s%s\n", colorRed, e, colorReset)
+	}
+}
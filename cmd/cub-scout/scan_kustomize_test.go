@@ -0,0 +1,41 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/gitops"
+)
+
+func TestKustomizeScanResult(t *testing.T) {
+	root := writeTestTree(t, map[string]string{
+		"base/kustomization.yaml":         "resources: [deployment.yaml]\n",
+		"base/deployment.yaml":            "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n",
+		"overlays/dev/kustomization.yaml": "resources: [../../base, github.com/org/shared//monitoring]\n",
+	})
+	lint, err := gitops.LintKustomizations(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := kustomizeScanResult(root, lint)
+
+	if result.Kustomizations != 2 || result.ResourceCount != 1 || len(result.Overlays) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+	severities := map[string]string{}
+	for _, f := range result.Findings {
+		severities[f.CCVEID] = f.Severity
+		if f.File != filepath.Join(root, "overlays/dev") {
+			t.Errorf("finding file = %q, want the overlay directory", f.File)
+		}
+	}
+	if severities["CCVE-2025-0699"] != "warning" || severities["CCVE-2025-0702"] != "warning" || len(severities) != 2 {
+		t.Errorf("findings = %+v", result.Findings)
+	}
+	if n := countFindingsAtOrAbove(result.Findings, "critical"); n != 0 {
+		t.Errorf("%d critical findings, want the default --fail-on to pass", n)
+	}
+}
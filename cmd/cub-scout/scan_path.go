@@ -0,0 +1,274 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	scanPathNoKustomize bool
+	scanPathFailOn      string
+)
+
+// scanFailOnLevels are the --fail-on values, most to least severe.
+var scanFailOnLevels = []string{"critical", "warning", "info", "none"}
+
+var scanPathCmd = &cobra.Command{
+	Use:   "path <dir-or-file>",
+	Short: "Scan local GitOps manifests before they are deployed",
+	Long: `Scan manifests in a Git checkout with the same detectors 'scan --file' and
+'remedy' use, so a pull request can be gated before Flux or Argo CD applies it.
+
+Directories are walked recursively:
+  - a directory with a kustomization.yaml is rendered with 'kubectl kustomize'
+    and its output scanned; its subdirectories are not walked separately
+  - other *.yaml and *.yml files are scanned as they are
+  - Helm charts (directories with Chart.yaml) and hidden directories are skipped
+
+Files that are not valid YAML (e.g. templates) are reported and skipped.
+
+The command exits 1 when a finding at or above --fail-on severity is found
+(default: critical), or when a kustomization fails to build.
+
+Examples:
+  cub-scout scan path ./clusters/prod
+  cub-scout scan path ./apps/base/deployment.yaml
+  cub-scout scan path . --no-kustomize --fail-on warning
+  cub-scout scan path ./clusters/prod --json`,
+	Args: cobra.ExactArgs(1),
+	// Findings fail the command; that is a result, not a usage error
+	SilenceUsage: true,
+	RunE:         runScanPath,
+}
+
+func init() {
+	scanPathCmd.Flags().BoolVar(&scanPathNoKustomize, "no-kustomize", false, "Scan files as they are instead of rendering kustomizations")
+	scanPathCmd.Flags().StringVar(&scanPathFailOn, "fail-on", "critical", "Exit 1 on findings at or above this severity: "+strings.Join(scanFailOnLevels, ", "))
+	_ = scanPathCmd.RegisterFlagCompletionFunc("fail-on", cobra.FixedCompletions(scanFailOnLevels, cobra.ShellCompDirectiveNoFileComp))
+	scanPathCmd.Flags().BoolVar(&scanJSON, "json", false, "Output as JSON")
+	scanPathCmd.Flags().BoolVar(&scanVerbose, "verbose", false, "Show info findings")
+
+	scanCmd.AddCommand(scanPathCmd)
+}
+
+// manifestSource is a unit of scanning: one YAML file, one kustomization or
+// one Helm chart.
+type manifestSource struct {
+	Path       string
+	Kustomized bool
+	Helm       bool
+}
+
+// findManifestSources walks root for manifest files and kustomizations, and
+// with helm set, charts. Without helm, charts are skipped.
+func findManifestSources(root string, kustomize, helm bool) ([]manifestSource, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []manifestSource{{Path: root}}, nil
+	}
+
+	var sources []manifestSource
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if fileExists(filepath.Join(path, "Chart.yaml")) {
+				if helm {
+					sources = append(sources, manifestSource{Path: path, Helm: true})
+				}
+				return filepath.SkipDir
+			}
+			if kustomize && isKustomizeDir(path) {
+				sources = append(sources, manifestSource{Path: path, Kustomized: true})
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+			sources = append(sources, manifestSource{Path: path})
+		}
+		return nil
+	})
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Path < sources[j].Path })
+	return sources, err
+}
+
+func isKustomizeDir(dir string) bool {
+	for _, name := range []string{"kustomization.yaml", "kustomization.yml", "Kustomization"} {
+		if fileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// loadManifestSource returns the parsed resources of a source, rendering
+// kustomizations with kubectl.
+func loadManifestSource(src manifestSource) ([]map[string]interface{}, error) {
+	if !src.Kustomized {
+		f, err := os.Open(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return agent.ParseManifests(f)
+	}
+
+	cmd := exec.Command("kubectl", "kustomize", src.Path)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("kubectl kustomize: %s", msg)
+		}
+		return nil, fmt.Errorf("kubectl kustomize: %w", err)
+	}
+	return agent.ParseManifests(bytes.NewReader(out))
+}
+
+// PathScanResult is the result of 'scan path': static findings across every
+// manifest source under a path.
+type PathScanResult struct {
+	Path          string                `json:"path"`
+	ScannedAt     time.Time             `json:"scannedAt"`
+	Sources       int                   `json:"sources"`
+	ResourceCount int                   `json:"resourceCount"`
+	Findings      []agent.StaticFinding `json:"findings"`
+	// Skipped are sources that could not be parsed, with the reason
+	Skipped []string `json:"skipped,omitempty"`
+	// Errors are kustomizations that failed to build
+	Errors []string `json:"errors,omitempty"`
+}
+
+// scanManifestSources scans every source with the static scanner. Files that
+// don't parse are skipped; kustomizations that don't build are errors.
+func scanManifestSources(root string, sources []manifestSource, scanner *agent.StaticScanner, load func(manifestSource) ([]map[string]interface{}, error)) PathScanResult {
+	result := PathScanResult{Path: root, ScannedAt: time.Now(), Findings: []agent.StaticFinding{}}
+	for _, src := range sources {
+		resources, err := load(src)
+		if err != nil {
+			if src.Kustomized {
+				result.Errors = append(result.Errors, src.Path+": "+err.Error())
+			} else {
+				result.Skipped = append(result.Skipped, src.Path+": "+err.Error())
+			}
+			continue
+		}
+		var manifests []map[string]interface{}
+		for _, r := range resources {
+			if kind, _ := r["kind"].(string); kind != "" {
+				manifests = append(manifests, r)
+			}
+		}
+		if len(manifests) == 0 {
+			continue // values files and other non-manifest YAML
+		}
+		result.Sources++
+		res := scanner.ScanResources(src.Path, manifests)
+		result.ResourceCount += res.ResourceCount
+		result.Findings = append(result.Findings, res.Findings...)
+	}
+	return result
+}
+
+// countFindingsAtOrAbove counts findings at least as severe as level.
+func countFindingsAtOrAbove(findings []agent.StaticFinding, level string) int {
+	rank := func(sev string) int {
+		for i, l := range scanFailOnLevels {
+			if l == sev {
+				return i
+			}
+		}
+		return len(scanFailOnLevels) - 2 // unknown severities count as info
+	}
+	if level == "none" {
+		return 0
+	}
+	n := 0
+	for _, f := range findings {
+		if rank(f.Severity) <= rank(level) {
+			n++
+		}
+	}
+	return n
+}
+
+func runScanPath(cmd *cobra.Command, args []string) error {
+	if !contains(scanFailOnLevels, scanPathFailOn) {
+		return fmt.Errorf("unknown --fail-on %q (want %s)", scanPathFailOn, strings.Join(scanFailOnLevels, ", "))
+	}
+	root := args[0]
+	sources, err := findManifestSources(root, !scanPathNoKustomize, false)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", root, err)
+	}
+
+	ccveDir := findPolicyDBDir()
+	if strings.HasSuffix(ccveDir, "/kyverno") {
+		ccveDir = filepath.Dir(ccveDir)
+	}
+	scanner, err := agent.NewStaticScanner(ccveDir)
+	if err != nil {
+		return fmt.Errorf("failed to create static scanner: %w", err)
+	}
+
+	result := scanManifestSources(root, sources, scanner, loadManifestSource)
+	if scanJSON {
+		if err := writeJSON(os.Stdout, "PathScanResult", result); err != nil {
+			return err
+		}
+	} else {
+		printPathScan(result)
+	}
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d kustomization(s) failed to build", len(result.Errors))
+	}
+	if n := countFindingsAtOrAbove(result.Findings, scanPathFailOn); n > 0 {
+		return fmt.Errorf("%d finding(s) at or above %s severity", n, scanPathFailOn)
+	}
+	return nil
+}
+
+func printPathScan(result PathScanResult) {
+	static := &agent.StaticScanResult{
+		File:          fmt.Sprintf("%s (%d source(s))", result.Path, result.Sources),
+		ResourceCount: result.ResourceCount,
+		Findings:      result.Findings,
+	}
+	_ = outputStaticScanHuman(static)
+
+	for _, e := range result.Errors {
+		fmt.Printf("%s✗ %s%s\n", colorRed, e, colorReset)
+	}
+	for _, s := range result.Skipped {
+		fmt.Printf("%sSkipped %s%s\n", colorDim, s, colorReset)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+func writeTestTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestFindManifestSources(t *testing.T) {
+	root := writeTestTree(t, map[string]string{
+		"clusters/prod/kustomization.yaml": "resources: [../../apps/base]\n",
+		"clusters/prod/patch.yaml":         "kind: Deployment\n",
+		"apps/base/deployment.yaml":        "kind: Deployment\n",
+		"apps/base/service.yml":            "kind: Service\n",
+		"apps/base/README.md":              "docs\n",
+		"charts/api/Chart.yaml":            "name: api\n",
+		"charts/api/templates/deploy.yaml": "{{ .Values }}\n",
+		".github/workflows/ci.yaml":        "on: push\n",
+	})
+	rel := func(sources []manifestSource) []manifestSource {
+		for i := range sources {
+			sources[i].Path, _ = filepath.Rel(root, sources[i].Path)
+		}
+		return sources
+	}
+
+	got, err := findManifestSources(root, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []manifestSource{
+		{Path: "apps/base/deployment.yaml"},
+		{Path: "apps/base/service.yml"},
+		{Path: "clusters/prod", Kustomized: true},
+	}
+	if got = rel(got); !reflect.DeepEqual(got, want) {
+		t.Errorf("kustomize sources = %+v\nwant %+v", got, want)
+	}
+
+	got, err = findManifestSources(root, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []manifestSource{
+		{Path: "apps/base/deployment.yaml"},
+		{Path: "apps/base/service.yml"},
+		{Path: "clusters/prod/kustomization.yaml"},
+		{Path: "clusters/prod/patch.yaml"},
+	}
+	if got = rel(got); !reflect.DeepEqual(got, want) {
+		t.Errorf("raw sources = %+v\nwant %+v", got, want)
+	}
+
+	got, err = findManifestSources(root, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got = rel(got); len(got) != 4 || got[2] != (manifestSource{Path: "charts/api", Helm: true}) {
+		t.Errorf("helm sources = %+v", got)
+	}
+
+	file := filepath.Join(root, "apps/base/deployment.yaml")
+	if got, _ := findManifestSources(file, true, false); len(got) != 1 || got[0].Path != file {
+		t.Errorf("single file sources = %+v", got)
+	}
+	if _, err := findManifestSources(filepath.Join(root, "missing"), true, false); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestScanManifestSources(t *testing.T) {
+	scanner, err := agent.NewStaticScanner("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdb := map[string]interface{}{
+		"kind":     "PodDisruptionBudget",
+		"metadata": map[string]interface{}{"name": "api", "namespace": "prod"},
+		"spec":     map[string]interface{}{"minAvailable": "100%"},
+	}
+	hr := map[string]interface{}{
+		"kind":     "HelmRelease",
+		"metadata": map[string]interface{}{"name": "redis"},
+		"spec":     map[string]interface{}{},
+	}
+	load := func(src manifestSource) ([]map[string]interface{}, error) {
+		switch src.Path {
+		case "clusters/prod":
+			return []map[string]interface{}{pdb}, nil
+		case "apps/redis.yaml":
+			return []map[string]interface{}{hr}, nil
+		case "values.yaml":
+			return []map[string]interface{}{{"replicaCount": 3}}, nil
+		case "clusters/broken":
+			return nil, errors.New("kubectl kustomize: missing resource")
+		default:
+			return nil, errors.New("yaml: line 1: did not find expected key")
+		}
+	}
+
+	result := scanManifestSources(".", []manifestSource{
+		{Path: "clusters/prod", Kustomized: true},
+		{Path: "clusters/broken", Kustomized: true},
+		{Path: "apps/redis.yaml"},
+		{Path: "values.yaml"},
+		{Path: "templates/deploy.yaml"},
+	}, scanner, load)
+
+	if result.Sources != 2 || result.ResourceCount != 2 {
+		t.Errorf("sources = %d, resources = %d; want 2, 2", result.Sources, result.ResourceCount)
+	}
+	if len(result.Findings) != 2 {
+		t.Fatalf("findings = %+v", result.Findings)
+	}
+	if f := result.Findings[0]; f.File != "clusters/prod" || f.Severity != "critical" {
+		t.Errorf("first finding = %+v", f)
+	}
+	if len(result.Errors) != 1 || !strings.HasPrefix(result.Errors[0], "clusters/broken:") {
+		t.Errorf("errors = %v", result.Errors)
+	}
+	if len(result.Skipped) != 1 || !strings.HasPrefix(result.Skipped[0], "templates/deploy.yaml:") {
+		t.Errorf("skipped = %v", result.Skipped)
+	}
+}
+
+func TestCountFindingsAtOrAbove(t *testing.T) {
+	findings := []agent.StaticFinding{{Severity: "critical"}, {Severity: "warning"}, {Severity: "warning"}, {Severity: "info"}}
+	for level, want := range map[string]int{"critical": 1, "warning": 3, "info": 4, "none": 0} {
+		if got := countFindingsAtOrAbove(findings, level); got != want {
+			t.Errorf("countFindingsAtOrAbove(%s) = %d, want %d", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+// SinksConfig is the agent-mode sinks file, e.g.:
+//
+//	sinks:
+//	  - type: stdout
+//	  - type: webhook
+//	    url: https://hooks.example.com/cub-scout
+//	    headers:
+//	      Authorization: Bearer ${WEBHOOK_TOKEN}
+//	  - type: s3
+//	    bucket: cluster-snapshots
+//	    prefix: cub-scout
+//	  - type: confighub
+//	    space: platform-inventory
+//	    reports: [drift, scan]
+type SinksConfig struct {
+	Sinks []SinkConfig `json:"sinks"`
+}
+
+// SinkConfig configures one sink. Fields apply by type:
+// webhook uses url and headers, s3/gcs use bucket and prefix, confighub uses space.
+type SinkConfig struct {
+	Type    string            `json:"type"` // stdout, webhook, s3, gcs, confighub
+	Name    string            `json:"name,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Bucket  string            `json:"bucket,omitempty"`
+	Prefix  string            `json:"prefix,omitempty"`
+	Space   string            `json:"space,omitempty"`
+
+	// Reports limits the sink to these report kinds (default: all)
+	Reports []string `json:"reports,omitempty"`
+}
+
+// Report kinds produced in agent mode
+const (
+	reportInventory = "inventory"
+	reportDrift     = "drift"
+	reportScan      = "scan"
+	reportSLO       = "slo"
+)
+
+// AgentReport is one result pushed to sinks.
+type AgentReport struct {
+	Kind        string      `json:"kind"`
+	Cluster     string      `json:"cluster"`
+	GeneratedAt time.Time   `json:"generatedAt"`
+	Payload     interface{} `json:"payload"`
+}
+
+// reportSink delivers agent reports to a destination.
+type reportSink interface {
+	Name() string
+	Accepts(kind string) bool
+	Send(ctx context.Context, report AgentReport) error
+}
+
+// loadSinks reads and validates a sinks file. Header values may reference
+// environment variables ($VAR or ${VAR}) so secrets stay out of the file.
+func loadSinks(file string) ([]reportSink, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read sinks file: %w", err)
+	}
+	var cfg SinksConfig
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse sinks file %s: %w", file, err)
+	}
+	if len(cfg.Sinks) == 0 {
+		return nil, fmt.Errorf("sinks file %s defines no sinks", file)
+	}
+
+	sinks := make([]reportSink, 0, len(cfg.Sinks))
+	for i, sc := range cfg.Sinks {
+		sink, err := newSink(sc)
+		if err != nil {
+			return nil, fmt.Errorf("sink %d (%s): %w", i+1, sc.Type, err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+func newSink(sc SinkConfig) (reportSink, error) {
+	for _, kind := range sc.Reports {
+		if kind != reportInventory && kind != reportDrift && kind != reportScan && kind != reportSLO {
+			return nil, fmt.Errorf("unknown report kind %q (valid: inventory, drift, scan, slo)", kind)
+		}
+	}
+	base := sinkBase{name: sc.Name, reports: sc.Reports}
+	if base.name == "" {
+		base.name = sc.Type
+	}
+
+	switch sc.Type {
+	case "stdout":
+		return &stdoutSink{sinkBase: base, w: os.Stdout}, nil
+	case "webhook":
+		if sc.URL == "" {
+			return nil, fmt.Errorf("url is required")
+		}
+		headers := map[string]string{}
+		for k, v := range sc.Headers {
+			headers[k] = os.ExpandEnv(v)
+		}
+		return &webhookSink{sinkBase: base, url: sc.URL, headers: headers, client: &http.Client{Timeout: 30 * time.Second}}, nil
+	case "s3", "gcs":
+		if sc.Bucket == "" {
+			return nil, fmt.Errorf("bucket is required")
+		}
+		return &objectStoreSink{sinkBase: base, scheme: sc.Type, bucket: sc.Bucket, prefix: sc.Prefix, upload: uploadWithCLI}, nil
+	case "confighub":
+		if sc.Space == "" {
+			return nil, fmt.Errorf("space is required")
+		}
+		return &confighubSink{sinkBase: base, space: sc.Space}, nil
+	case "":
+		return nil, fmt.Errorf("type is required")
+	}
+	return nil, fmt.Errorf("unknown sink type %q (valid: stdout, webhook, s3, gcs, confighub)", sc.Type)
+}
+
+// publishReports sends every report to every sink that accepts it. A failing
+// sink does not stop the others; all errors are returned together.
+func publishReports(ctx context.Context, sinks []reportSink, reports []AgentReport) error {
+	var errs []error
+	for _, sink := range sinks {
+		for _, report := range reports {
+			if !sink.Accepts(report.Kind) {
+				continue
+			}
+			if err := sink.Send(ctx, report); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", sink.Name(), report.Kind, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type sinkBase struct {
+	name    string
+	reports []string
+}
+
+func (b sinkBase) Name() string { return b.name }
+
+func (b sinkBase) Accepts(kind string) bool {
+	if len(b.reports) == 0 {
+		return true
+	}
+	for _, r := range b.reports {
+		if r == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// stdoutSink writes one JSON object per line.
+type stdoutSink struct {
+	sinkBase
+	w io.Writer
+}
+
+func (s *stdoutSink) Send(_ context.Context, report AgentReport) error {
+	return json.NewEncoder(s.w).Encode(report)
+}
+
+// webhookSink POSTs each report as JSON.
+type webhookSink struct {
+	sinkBase
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (s *webhookSink) Send(ctx context.Context, report AgentReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("encode report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// objectStoreSink archives each report as a timestamped object:
+// <prefix>/<cluster>/<kind>/<timestamp>.json
+type objectStoreSink struct {
+	sinkBase
+	scheme string // s3 or gcs
+	bucket string
+	prefix string
+	upload func(ctx context.Context, scheme, uri string, data []byte) error
+}
+
+func (s *objectStoreSink) Send(ctx context.Context, report AgentReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode report: %w", err)
+	}
+	return s.upload(ctx, s.scheme, s.objectURI(report), data)
+}
+
+func (s *objectStoreSink) objectURI(report AgentReport) string {
+	key := path.Join(s.prefix, sanitizeSlug(report.Cluster), report.Kind,
+		report.GeneratedAt.UTC().Format("20060102T150405Z")+".json")
+	scheme := "s3"
+	if s.scheme == "gcs" {
+		scheme = "gs"
+	}
+	return scheme + "://" + s.bucket + "/" + key
+}
+
+// uploadWithCLI streams data to object storage with the provider CLI, which
+// picks up credentials the usual way (IRSA, workload identity, env vars).
+func uploadWithCLI(ctx context.Context, scheme, uri string, data []byte) error {
+	var c *exec.Cmd
+	if scheme == "gcs" {
+		c = exec.CommandContext(ctx, "gcloud", "storage", "cp", "-", uri)
+	} else {
+		c = exec.CommandContext(ctx, "aws", "s3", "cp", "-", uri)
+	}
+	c.Stdin = bytes.NewReader(data)
+	if output, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("upload %s: %s", uri, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// confighubSink stores the latest report of each kind as a unit
+// (<kind>-<cluster>) so ConfigHub revision history keeps older results.
+type confighubSink struct {
+	sinkBase
+	space string
+}
+
+func (s *confighubSink) Send(_ context.Context, report AgentReport) error {
+	manifest, err := buildReportManifest(report)
+	if err != nil {
+		return err
+	}
+	unit := sanitizeSlug(report.Kind + "-" + report.Cluster)
+	labels := []string{"cub-scout-report=" + report.Kind, "cluster=" + sanitizeSlug(report.Cluster)}
+	_, err = upsertReportUnit(s.space, unit, labels, manifest, "cub-scout "+report.Kind+" report")
+	return err
+}
+
+// buildReportManifest renders a report as a ConfigMap, like the inventory
+// unit. Like the inventory it carries no timestamp, so a report that has not
+// changed since the last tick renders the same unit and adds no revision.
+func buildReportManifest(report AgentReport) (string, error) {
+	payload, err := json.MarshalIndent(unitPayload(report.Payload), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode report: %w", err)
+	}
+	cm := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "cub-scout-" + report.Kind,
+			"namespace": "cub-scout",
+			"labels": map[string]string{
+				"app.kubernetes.io/managed-by": "cub-scout",
+				"cub-scout/cluster":            report.Cluster,
+			},
+		},
+		"data": map[string]string{
+			report.Kind + ".json": string(payload),
+		},
+	}
+	out, err := yaml.Marshal(cm)
+	if err != nil {
+		return "", fmt.Errorf("encode report manifest: %w", err)
+	}
+	return string(out), nil
+}
+
+// unitPayload returns a copy of a report payload without the time it was
+// generated, scanned or evaluated at.
+func unitPayload(payload interface{}) interface{} {
+	switch p := payload.(type) {
+	case map[string]interface{}:
+		summary, ok := p["summary"].(InventorySummary)
+		if !ok {
+			return p
+		}
+		summary.GeneratedAt = time.Time{}
+		out := make(map[string]interface{}, len(p))
+		for k, v := range p {
+			out[k] = v
+		}
+		out["summary"] = summary
+		return out
+	case *agent.StateScanResult:
+		scan := *p
+		scan.ScannedAt = time.Time{}
+		return &scan
+	case []LagSLOResult:
+		results := make([]LagSLOResult, len(p))
+		for i, result := range p {
+			result.EvaluatedAt = time.Time{}
+			results[i] = result
+		}
+		return results
+	}
+	return payload
+}
@@ -0,0 +1,191 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+func writeSinksFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "sinks.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadSinks(t *testing.T) {
+	t.Setenv("WEBHOOK_TOKEN", "s3cret")
+	file := writeSinksFile(t, `
+sinks:
+  - type: stdout
+  - type: webhook
+    url: https://hooks.example.com/scout
+    headers:
+      Authorization: Bearer ${WEBHOOK_TOKEN}
+  - type: gcs
+    bucket: snapshots
+    prefix: scout
+  - type: confighub
+    space: inventory
+    reports: [drift]
+`)
+	sinks, err := loadSinks(file)
+	if err != nil {
+		t.Fatalf("loadSinks: %v", err)
+	}
+	if len(sinks) != 4 {
+		t.Fatalf("expected 4 sinks, got %d", len(sinks))
+	}
+	if got := sinks[1].(*webhookSink).headers["Authorization"]; got != "Bearer s3cret" {
+		t.Errorf("header not expanded: %q", got)
+	}
+	if sinks[3].Accepts(reportScan) || !sinks[3].Accepts(reportDrift) {
+		t.Error("confighub sink should only accept drift reports")
+	}
+
+	for name, content := range map[string]string{
+		"unknown type":   "sinks:\n  - type: kafka\n",
+		"missing url":    "sinks:\n  - type: webhook\n",
+		"missing bucket": "sinks:\n  - type: s3\n",
+		"unknown report": "sinks:\n  - type: stdout\n    reports: [logs]\n",
+		"unknown field":  "sinks:\n  - type: stdout\n    topic: x\n",
+		"empty":          "sinks: []\n",
+	} {
+		if _, err := loadSinks(writeSinksFile(t, content)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func testReport(kind string) AgentReport {
+	return AgentReport{
+		Kind:        kind,
+		Cluster:     "prod-east",
+		GeneratedAt: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+		Payload:     []DriftItem{{Kind: "Kustomization", Name: "apps", Namespace: "flux-system", Reason: "BuildFailed"}},
+	}
+}
+
+func TestStdoutSinkWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &stdoutSink{sinkBase: sinkBase{name: "stdout"}, w: &buf}
+	if err := publishReports(context.Background(), []reportSink{sink}, []AgentReport{testReport(reportDrift), testReport(reportScan)}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %d", len(lines))
+	}
+	var got AgentReport
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil || got.Kind != reportDrift || got.Cluster != "prod-east" {
+		t.Errorf("unexpected line %q (%v)", lines[0], err)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var auth string
+	var received AgentReport
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		if received.Kind == reportScan {
+			http.Error(w, "scan not accepted", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	sink := &webhookSink{sinkBase: sinkBase{name: "hook"}, url: srv.URL, headers: map[string]string{"Authorization": "Bearer t"}, client: srv.Client()}
+	if err := sink.Send(context.Background(), testReport(reportDrift)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if auth != "Bearer t" || received.Kind != reportDrift {
+		t.Errorf("unexpected request: auth=%q kind=%q", auth, received.Kind)
+	}
+	err := sink.Send(context.Background(), testReport(reportScan))
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected 400 error, got %v", err)
+	}
+}
+
+func TestObjectStoreSinkAndPublishErrors(t *testing.T) {
+	var uris []string
+	s3 := &objectStoreSink{sinkBase: sinkBase{name: "s3"}, scheme: "s3", bucket: "snaps", prefix: "scout",
+		upload: func(_ context.Context, _, uri string, _ []byte) error {
+			uris = append(uris, uri)
+			return nil
+		}}
+	failing := &objectStoreSink{sinkBase: sinkBase{name: "gcs"}, scheme: "gcs", bucket: "b",
+		upload: func(context.Context, string, string, []byte) error { return errors.New("denied") }}
+
+	err := publishReports(context.Background(), []reportSink{failing, s3}, []AgentReport{testReport(reportInventory)})
+	if err == nil || !strings.Contains(err.Error(), "gcs: inventory: denied") {
+		t.Errorf("expected gcs error, got %v", err)
+	}
+	if len(uris) != 1 || uris[0] != "s3://snaps/scout/prod-east/inventory/20260301T093000Z.json" {
+		t.Errorf("unexpected uploads: %v", uris)
+	}
+	if got := failing.objectURI(testReport(reportScan)); got != "gs://b/prod-east/scan/20260301T093000Z.json" {
+		t.Errorf("gcs URI = %q", got)
+	}
+}
+
+func TestBuildReportManifest(t *testing.T) {
+	manifest, err := buildReportManifest(testReport(reportDrift))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"name: cub-scout-drift", "drift.json:", "BuildFailed", "cub-scout/cluster: prod-east"} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("manifest missing %q:\n%s", want, manifest)
+		}
+	}
+}
+
+func TestReportManifestStableAcrossTicks(t *testing.T) {
+	entries := []MapEntry{{Namespace: "prod", Kind: "Deployment", Name: "api", Owner: "Flux", Status: "Ready"}}
+	tick := func(at time.Time) []AgentReport {
+		return []AgentReport{
+			inventoryReport("prod-east", entries, at),
+			{Kind: reportScan, Cluster: "prod-east", GeneratedAt: at, Payload: &agent.StateScanResult{ScannedAt: at}},
+			{Kind: reportSLO, Cluster: "prod-east", GeneratedAt: at, Payload: []LagSLOResult{{SLO: "prod", MaxLag: "1h", EvaluatedAt: at, Units: 3}}},
+		}
+	}
+
+	first := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	before, after := tick(first), tick(first.Add(5*time.Minute))
+	for i := range before {
+		a, err := buildReportManifest(before[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := buildReportManifest(after[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a != b {
+			t.Errorf("%s unit changed between ticks:\n%s\n---\n%s", before[i].Kind, a, b)
+		}
+		if strings.Contains(a, "2026") {
+			t.Errorf("%s unit carries a timestamp:\n%s", before[i].Kind, a)
+		}
+	}
+	// The reports themselves keep their timestamps for the other sinks
+	if scan := after[1].Payload.(*agent.StateScanResult); !scan.ScannedAt.Equal(first.Add(5 * time.Minute)) {
+		t.Errorf("scan payload ScannedAt = %v, want the tick time", scan.ScannedAt)
+	}
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -16,6 +17,7 @@ import (
 	"k8s.io/client-go/dynamic"
 
 	"github.com/confighub/cub-scout/pkg/agent"
+	"github.com/confighub/cub-scout/pkg/redact"
 )
 
 var (
@@ -27,12 +29,16 @@ var (
 
 // GSFSnapshot represents the GitOps State Format output
 type GSFSnapshot struct {
-	Version     string        `json:"version"`
-	GeneratedAt time.Time     `json:"generatedAt"`
-	Cluster     string        `json:"cluster"`
-	Entries     []GSFEntry    `json:"entries"`
-	Relations   []GSFRelation `json:"relations,omitempty"`
-	Summary     GSFSummary    `json:"summary"`
+	Version     string    `json:"version"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Cluster     string    `json:"cluster"`
+	// Environment and ClusterLabels come from the cluster registry
+	// (~/.cub-scout/clusters.yaml)
+	Environment   string            `json:"environment,omitempty"`
+	ClusterLabels map[string]string `json:"clusterLabels,omitempty"`
+	Entries       []GSFEntry        `json:"entries"`
+	Relations     []GSFRelation     `json:"relations,omitempty"`
+	Summary       GSFSummary        `json:"summary"`
 }
 
 // GSFEntry represents a resource entry in GSF
@@ -102,6 +108,9 @@ Examples:
 
   # Filter by kind
   cub-scout snapshot --kind Deployment
+
+  # Add to the fleet store for "map fleet --from-store"
+  CLUSTER_NAME=prod-east cub-scout snapshot --push
 `,
 	RunE: runSnapshot,
 }
@@ -111,7 +120,9 @@ func init() {
 
 	snapshotCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "", "Output file (default: stdout, use '-' for explicit stdout)")
 	snapshotCmd.Flags().StringVarP(&snapshotNamespace, "namespace", "n", "", "Filter by namespace")
+	_ = snapshotCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
 	snapshotCmd.Flags().StringVarP(&snapshotKind, "kind", "k", "", "Filter by kind")
+	_ = snapshotCmd.RegisterFlagCompletionFunc("kind", completeKinds)
 	snapshotCmd.Flags().BoolVar(&snapshotRelations, "relations", false, "Include resource relations (owns, selects, mounts, references)")
 }
 
@@ -131,11 +142,60 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get cluster name
-	clusterName := os.Getenv("CLUSTER_NAME")
-	if clusterName == "" {
-		clusterName = "default"
+	cluster := currentCluster()
+	clusterName := cluster.Name
+
+	snapshot := collectSnapshot(ctx, dynClient, clusterName, snapshotNamespace, snapshotKind, snapshotRelations)
+	snapshot.Environment, snapshot.ClusterLabels = cluster.Environment, cluster.Labels
+	redactSnapshot(activeRedactor(), &snapshot)
+
+	// Encode output
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if snapshotOutput != "" && snapshotOutput != "-" {
+		f, err := os.Create(snapshotOutput)
+		if err != nil {
+			return fmt.Errorf("create output file: %w", err)
+		}
+		defer f.Close()
+		enc = json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+	}
+
+	if err := enc.Encode(snapshot); err != nil {
+		return fmt.Errorf("encode output: %w", err)
+	}
+
+	if snapshotPush {
+		path, err := openFleetStore(fleetStorePath).Save(&snapshot)
+		if err != nil {
+			return fmt.Errorf("push to fleet store: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "✓ Pushed %s snapshot to %s\n", clusterName, path)
+	}
+
+	return nil
+}
+
+// redactSnapshot masks secrets in the free-form parts of a snapshot (labels,
+// owner details and drift summaries) so it can be shared or pushed.
+func redactSnapshot(r *redact.Redactor, snapshot *GSFSnapshot) {
+	for i := range snapshot.Entries {
+		e := &snapshot.Entries[i]
+		e.Labels = r.Strings(e.Labels)
+		if e.Owner != nil {
+			e.Owner.Details = r.Strings(e.Owner.Details)
+		}
+		if e.Drift != nil {
+			e.Drift.Summary = r.String(e.Drift.Summary)
+		}
 	}
+}
 
+// collectSnapshot lists the snapshot's resource types in namespace ("" for
+// all), optionally of one kind, and classifies their ownership.
+func collectSnapshot(ctx context.Context, dynClient dynamic.Interface, clusterName, namespace, kind string, relations bool) GSFSnapshot {
 	// Collect resources
 	entries := []GSFEntry{}
 	byKind := map[string]int{}
@@ -158,34 +218,37 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 		{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"},
 		// Argo resources
 		{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+		// Scheduled work; Jobs and their Pods inherit their CronJob's owner
+		{Group: "batch", Version: "v1", Resource: "cronjobs"},
+		{Group: "batch", Version: "v1", Resource: "jobs"},
 	}
 
 	// Store raw items for relation building
 	var allItems []unstructured.Unstructured
 
+	var lists []listedObjects
 	for _, gvr := range resources {
-		var list *unstructured.UnstructuredList
-		var err error
-
-		if snapshotNamespace != "" {
-			list, err = dynClient.Resource(gvr).Namespace(snapshotNamespace).List(ctx, v1.ListOptions{})
-		} else {
-			list, err = dynClient.Resource(gvr).List(ctx, v1.ListOptions{})
-		}
-
+		list, err := dynClient.Resource(gvr).Namespace(namespace).List(ctx, v1.ListOptions{})
 		if err != nil {
 			// Skip resources that don't exist (CRDs not installed)
 			continue
 		}
+		lists = append(lists, listedObjects{gvr, list.Items})
+	}
 
-		for _, item := range list.Items {
+	// Index everything before classifying, so Pods resolve through their
+	// ReplicaSet or Job to the GitOps owner at the top
+	indexOwners(lists)
+	for _, l := range lists {
+		gvr := l.gvr
+		for _, item := range l.items {
 			// Filter by kind if specified
-			if snapshotKind != "" && item.GetKind() != snapshotKind {
+			if kind != "" && item.GetKind() != kind {
 				continue
 			}
 
 			// Detect ownership
-			ownership := agent.DetectOwnership(&item)
+			ownership := graphOwnership(&item)
 
 			entry := GSFEntry{
 				ID:         fmt.Sprintf("%s/%s/%s/%s/%s", clusterName, item.GetNamespace(), gvr.Group, item.GetKind(), item.GetName()),
@@ -215,54 +278,33 @@ func runSnapshot(cmd *cobra.Command, args []string) error {
 			}
 
 			// Store for relation building
-			if snapshotRelations {
+			if relations {
 				allItems = append(allItems, item)
 			}
 		}
 	}
 
 	// Build relations if requested
-	var relations []GSFRelation
-	if snapshotRelations {
-		relations = append(relations, buildOwnsRelations(allItems, clusterName)...)
-		relations = append(relations, buildSelectsRelations(allItems, clusterName)...)
-		relations = append(relations, buildMountsRelations(allItems, clusterName)...)
-		relations = append(relations, buildReferencesRelations(allItems, clusterName)...)
+	var rels []GSFRelation
+	if relations {
+		rels = append(rels, buildOwnsRelations(allItems, clusterName)...)
+		rels = append(rels, buildSelectsRelations(allItems, clusterName)...)
+		rels = append(rels, buildMountsRelations(allItems, clusterName)...)
+		rels = append(rels, buildReferencesRelations(allItems, clusterName)...)
 	}
 
-	// Build snapshot
-	snapshot := GSFSnapshot{
+	return GSFSnapshot{
 		Version:     "gsf/v1",
 		GeneratedAt: time.Now().UTC(),
 		Cluster:     clusterName,
 		Entries:     entries,
-		Relations:   relations,
+		Relations:   rels,
 		Summary: GSFSummary{
 			Total:   len(entries),
 			ByKind:  byKind,
 			ByOwner: byOwner,
 		},
 	}
-
-	// Encode output
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-
-	if snapshotOutput != "" && snapshotOutput != "-" {
-		f, err := os.Create(snapshotOutput)
-		if err != nil {
-			return fmt.Errorf("create output file: %w", err)
-		}
-		defer f.Close()
-		enc = json.NewEncoder(f)
-		enc.SetIndent("", "  ")
-	}
-
-	if err := enc.Encode(snapshot); err != nil {
-		return fmt.Errorf("encode output: %w", err)
-	}
-
-	return nil
 }
 
 // buildOwnsRelations extracts ownership relations from OwnerReferences
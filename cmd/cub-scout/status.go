@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -24,7 +23,7 @@ var statusCmd = &cobra.Command{
 
 Displays:
   - ConfigHub connection status (Offline/Online/Connected)
-  - Current cluster name (from CLUSTER_NAME env or default)
+  - Current cluster name (CLUSTER_NAME, ~/.cub-scout/clusters.yaml, or the context)
   - Current kubectl context
   - Worker status (if connected to ConfigHub)
 
@@ -75,7 +74,7 @@ func runStatus(cmd *cobra.Command) error {
 
 	status := StatusInfo{
 		Mode:        "offline",
-		ClusterName: getClusterName(),
+		ClusterName: currentClusterName(),
 		Context:     getCurrentContext(),
 	}
 
@@ -120,6 +119,9 @@ func printStatus(s StatusInfo) {
 		fmt.Println("            Run: cub auth login")
 	case "offline":
 		fmt.Println("ConfigHub:  \033[31m○\033[0m Offline")
+		if !cubInstalled() {
+			fmt.Println("            cub CLI not found; install from " + cubInstallURL)
+		}
 	}
 
 	// Cluster info
@@ -145,7 +147,7 @@ func printStatus(s StatusInfo) {
 // getStatusCubContext gets the current cub context and email
 // Returns context, email, and error
 func getStatusCubContext() (*statusCubContext, string, error) {
-	out, err := exec.Command("cub", "context", "get", "--json").Output()
+	out, err := runCubCommand("context", "get", "--json")
 	if err != nil {
 		return nil, "", err
 	}
@@ -170,18 +172,10 @@ func getStatusCubContext() (*statusCubContext, string, error) {
 	return nil, "", fmt.Errorf("no context found")
 }
 
-func getClusterName() string {
-	name := os.Getenv("CLUSTER_NAME")
-	if name == "" {
-		return "default"
-	}
-	return name
-}
-
 // isOnline checks basic internet connectivity
 func isOnline() bool {
 	// Simple check - if we can run cub without errors, we're probably online
-	_, err := exec.Command("cub", "--version").Output()
+	_, err := runCubCommand("--version")
 	return err == nil
 }
 
@@ -197,7 +191,7 @@ func getWorkerForCluster(space, clusterName string) *WorkerInfo {
 		return nil
 	}
 
-	out, err := exec.Command("cub", "worker", "list", "--space", space, "--json").Output()
+	out, err := runCubCommand("worker", "list", "--space", space, "--json")
 	if err != nil {
 		return nil
 	}
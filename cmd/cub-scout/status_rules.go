@@ -0,0 +1,42 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/confighub/cub-scout/internal/mapsvc"
+)
+
+// StatusRulesFile returns the status rules path: $CUB_SCOUT_STATUS_RULES,
+// then ~/.cub-scout/status-rules.yaml.
+func StatusRulesFile() string {
+	if file := os.Getenv("CUB_SCOUT_STATUS_RULES"); file != "" {
+		return file
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cub-scout", "status-rules.yaml")
+}
+
+var (
+	statusRulesOnce sync.Once
+	statusRules     mapsvc.StatusRules
+)
+
+// activeStatusRules loads the status rules once per process and hands them
+// to mapsvc, so every map view reports custom resources the same way. A
+// broken file is reported and ignored.
+func activeStatusRules() mapsvc.StatusRules {
+	statusRulesOnce.Do(func() {
+		var err error
+		statusRules, err = mapsvc.LoadStatusRules(StatusRulesFile())
+		if err != nil {
+			logger.Warn("ignoring status rules", "err", err)
+		}
+		mapsvc.SetStatusRules(statusRules)
+	})
+	return statusRules
+}
@@ -23,6 +23,7 @@ type ImportSuggestion struct {
 type HubAppSpaceSuggestion struct {
 	AppSpace string            // The team's App Space (one per team, not per env)
 	Units    []HubAppSpaceUnit // Units with app/variant labels
+	Bases    []HubAppSpaceUnit // Base units that multi-variant apps clone from
 }
 
 // HubAppSpaceUnit represents a unit in the Hub/App Space model
@@ -30,13 +31,21 @@ type HubAppSpaceUnit struct {
 	Slug      string         // e.g., "payment-api-prod"
 	App       string         // app label value
 	Variant   string         // variant label value
-	Workloads []WorkloadInfo // workloads that map to this unit
+	Workloads []WorkloadInfo // workloads that map to this unit (for a base: its seed)
+
+	Upstream    string // base unit this variant clones from, if any
+	SeedVariant string // for a base: the variant whose workloads seed it
+
+	Confidence float64  // 0-1: how sure the grouping is (see unitRationale)
+	Rationale  []string // signals that drove the grouping
 }
 
 // AppSuggestion represents a suggested app grouping
 type AppSuggestion struct {
 	Name     string
 	Variants []VariantSuggestion
+	Base     string // base unit slug when the app has several variants
+	BaseSeed string // variant whose workloads seed the base unit
 }
 
 // VariantSuggestion represents a suggested variant within an app
@@ -44,6 +53,10 @@ type VariantSuggestion struct {
 	Name      string
 	Workloads []WorkloadInfo
 	UnitSlug  string // Generated unit slug: app-variant or just app if default
+	Upstream  string // base unit this variant clones from, if any
+
+	Confidence float64  // 0-1: how sure the grouping is (see unitRationale)
+	Rationale  []string // signals that drove the grouping
 }
 
 // Common environment/variant suffixes and prefixes
@@ -60,21 +73,18 @@ var variantPatterns = []string{
 // SuggestStructure analyzes workloads and suggests an import structure
 func SuggestStructure(workloads []WorkloadInfo, defaultSpace string) ImportSuggestion {
 	// Group workloads by inferred app name
-	appGroups := make(map[string]map[string][]WorkloadInfo) // app -> variant -> workloads
+	appGroups := make(map[string]map[string][]WorkloadInfo)      // app -> variant -> workloads
+	appSignals := make(map[string]map[string][]appVariantSignal) // app -> variant -> signals
 
-	for _, w := range workloads {
-		app, variant := inferAppAndVariant(w)
-		if app == "" {
-			app = w.Name // fallback to workload name
-		}
-		if variant == "" {
-			variant = "default"
-		}
+	for i, sig := range inferAppVariants(workloads) {
+		app, variant := sig.App, sig.Variant
 
 		if appGroups[app] == nil {
 			appGroups[app] = make(map[string][]WorkloadInfo)
+			appSignals[app] = make(map[string][]appVariantSignal)
 		}
-		appGroups[app][variant] = append(appGroups[app][variant], w)
+		appGroups[app][variant] = append(appGroups[app][variant], workloads[i])
+		appSignals[app][variant] = append(appSignals[app][variant], sig)
 	}
 
 	// Build suggestion structure
@@ -110,13 +120,17 @@ func SuggestStructure(workloads []WorkloadInfo, defaultSpace string) ImportSugge
 				unitSlug = fmt.Sprintf("%s-%s", appName, variantName)
 			}
 
+			confidence, rationale := unitRationale(appSignals[appName][variantName])
 			app.Variants = append(app.Variants, VariantSuggestion{
-				Name:      variantName,
-				Workloads: wls,
-				UnitSlug:  sanitizeSlug(unitSlug),
+				Name:       variantName,
+				Workloads:  wls,
+				UnitSlug:   sanitizeSlug(unitSlug),
+				Confidence: confidence,
+				Rationale:  rationale,
 			})
 		}
 
+		proposeBase(&app)
 		suggestion.Apps = append(suggestion.Apps, app)
 	}
 
@@ -125,57 +139,65 @@ func SuggestStructure(workloads []WorkloadInfo, defaultSpace string) ImportSugge
 
 // inferAppAndVariant extracts app name and variant from workload metadata
 func inferAppAndVariant(w WorkloadInfo) (app, variant string) {
+	s := inferSignals(w)
+	return s.App, s.Variant
+}
+
+// inferSignals infers a workload's app and variant and records which signal
+// decided each.
+func inferSignals(w WorkloadInfo) appVariantSignal {
+	var s appVariantSignal
+	setApp := func(v string, src suggestSignal) {
+		if s.App == "" && v != "" {
+			s.App, s.AppSource = v, src
+		}
+	}
+	setVariant := func(v string, src suggestSignal) {
+		if s.Variant == "" && v != "" {
+			s.Variant, s.VariantSource = v, src
+		}
+	}
+
 	// Priority 0: GitOps deployer path (most reliable signal)
 	// Flux Kustomization: spec.path: "./staging" -> variant=staging
 	// Argo Application: spec.source.path: "apps/prod" -> variant=prod
 	if w.KustomizationPath != "" {
-		variant = extractVariantFromPath(w.KustomizationPath)
+		setVariant(extractVariantFromPath(w.KustomizationPath), suggestSignal{signalFluxPath, "Flux Kustomization path " + w.KustomizationPath})
 	} else if w.ApplicationPath != "" {
-		variant = extractVariantFromPath(w.ApplicationPath)
+		setVariant(extractVariantFromPath(w.ApplicationPath), suggestSignal{signalArgoPath, "Argo CD source path " + w.ApplicationPath})
 	}
 
 	// Priority 1: Kubernetes recommended labels
 	if name, ok := w.Labels["app.kubernetes.io/name"]; ok {
-		app = name
+		setApp(name, suggestSignal{signalNameLabel, "label app.kubernetes.io/name=" + name})
 	}
-	if variant == "" {
-		if instance, ok := w.Labels["app.kubernetes.io/instance"]; ok && instance != app {
-			// instance is often variant (e.g., "myapp-prod")
-			variant = extractVariantFromInstance(instance, app)
-		}
+	if instance, ok := w.Labels["app.kubernetes.io/instance"]; ok && instance != s.App {
+		// instance is often variant (e.g., "myapp-prod")
+		setVariant(extractVariantFromInstance(instance, s.App), suggestSignal{signalInstanceLabel, "label app.kubernetes.io/instance=" + instance})
 	}
 
 	// Priority 2: Common labels
-	if app == "" {
-		if name, ok := w.Labels["app"]; ok {
-			app = name
-		}
+	if name, ok := w.Labels["app"]; ok {
+		setApp(name, suggestSignal{signalAppLabel, "label app=" + name})
 	}
-	if variant == "" {
-		if env, ok := w.Labels["environment"]; ok {
-			variant = env
-		} else if env, ok := w.Labels["env"]; ok {
-			variant = env
-		}
+	if env, ok := w.Labels["environment"]; ok {
+		setVariant(env, suggestSignal{signalEnvLabel, "label environment=" + env})
+	} else if env, ok := w.Labels["env"]; ok {
+		setVariant(env, suggestSignal{signalEnvLabel, "label env=" + env})
 	}
 
 	// Priority 3: Namespace pattern detection
-	if app == "" || variant == "" {
+	if s.App == "" || s.Variant == "" {
 		nsApp, nsVariant := parseNamespacePattern(w.Namespace)
-		if app == "" {
-			app = nsApp
-		}
-		if variant == "" {
-			variant = nsVariant
-		}
+		src := suggestSignal{signalNamespacePattern, "namespace " + w.Namespace}
+		setApp(nsApp, src)
+		setVariant(nsVariant, src)
 	}
 
 	// Priority 4: Workload name parsing
-	if app == "" {
-		app = w.Name
-	}
+	setApp(w.Name, suggestSignal{signalWorkloadName, "workload name " + w.Name})
 
-	return app, variant
+	return s
 }
 
 // parseNamespacePattern extracts app and variant from namespace naming conventions
@@ -300,17 +322,18 @@ func inferSpace(workloads []WorkloadInfo, defaultSpace string) string {
 	return "imported"
 }
 
-// isSystemNamespaceForSuggest returns true for Kubernetes system namespaces (used in suggestions)
-// This is more conservative than the import version, including gitops namespaces
+// isSystemNamespaceForSuggest returns true for namespaces that never hold an app.
+// This is more conservative than isSystemNamespace: "default" is skipped too,
+// unless the namespace config includes it explicitly.
 func isSystemNamespaceForSuggest(ns string) bool {
-	systemNS := []string{
-		"default", "kube-system", "kube-public", "kube-node-lease",
-		"flux-system", "argocd", "cert-manager", "ingress-nginx",
+	if isSystemNamespace(ns) {
+		return true
 	}
-	for _, s := range systemNS {
-		if ns == s {
-			return true
-		}
+	if isIncludedNamespace(ns) {
+		return false
+	}
+	if ns == "default" {
+		return true
 	}
 	// Prefix matches for dynamic system namespaces (e.g., local-path-storage)
 	systemPrefixes := []string{"local-path-"}
@@ -386,13 +409,20 @@ func (s *ImportSuggestion) Print() {
 			fmt.Printf("    └── Unit: %s (%d workload(s))\n", v.UnitSlug, len(v.Workloads))
 		} else {
 			fmt.Printf("    ├── App: %s\n", app.Name)
+			if app.Base != "" {
+				fmt.Printf("    │   ├── Base: %s (upstream, seeded from %s)\n", app.Base, app.BaseSeed)
+			}
 			for i, v := range app.Variants {
 				prefix := "│   ├──"
 				if i == len(app.Variants)-1 {
 					prefix = "│   └──"
 				}
-				fmt.Printf("    %s Unit: %s (variant=%s, %d workload(s))\n",
-					prefix, v.UnitSlug, v.Name, len(v.Workloads))
+				clones := ""
+				if v.Upstream != "" {
+					clones = ", clones " + v.Upstream
+				}
+				fmt.Printf("    %s Unit: %s (variant=%s, %d workload(s)%s)\n",
+					prefix, v.UnitSlug, v.Name, len(v.Workloads), clones)
 			}
 		}
 	}
@@ -426,18 +456,12 @@ func SuggestHubAppSpaceStructure(workloads []WorkloadInfo, defaultSpace string)
 		app, variant string
 	}
 	unitGroups := make(map[unitKey][]WorkloadInfo)
+	unitSignals := make(map[unitKey][]appVariantSignal)
 
-	for _, w := range workloads {
-		app, variant := inferAppAndVariant(w)
-		if app == "" {
-			app = w.Name // fallback to workload name
-		}
-		if variant == "" {
-			variant = "default"
-		}
-
-		key := unitKey{app: app, variant: variant}
-		unitGroups[key] = append(unitGroups[key], w)
+	for i, sig := range inferAppVariants(workloads) {
+		key := unitKey{app: sig.App, variant: sig.Variant}
+		unitGroups[key] = append(unitGroups[key], workloads[i])
+		unitSignals[key] = append(unitSignals[key], sig)
 	}
 
 	// Build suggestion
@@ -466,13 +490,17 @@ func SuggestHubAppSpaceStructure(workloads []WorkloadInfo, defaultSpace string)
 			slug = fmt.Sprintf("%s-%s", key.app, key.variant)
 		}
 
+		confidence, rationale := unitRationale(unitSignals[key])
 		suggestion.Units = append(suggestion.Units, HubAppSpaceUnit{
-			Slug:      sanitizeSlug(slug),
-			App:       key.app,
-			Variant:   key.variant,
-			Workloads: wls,
+			Slug:       sanitizeSlug(slug),
+			App:        key.app,
+			Variant:    key.variant,
+			Workloads:  wls,
+			Confidence: confidence,
+			Rationale:  rationale,
 		})
 	}
+	proposeHubBases(&suggestion)
 
 	return suggestion
 }
@@ -546,13 +574,22 @@ func (s *HubAppSpaceSuggestion) Print() {
 		} else {
 			// Multiple variants
 			fmt.Printf("    ├── app=%s\n", app)
+			for _, b := range s.Bases {
+				if b.App == app {
+					fmt.Printf("    │   ├── Base: %s (upstream, seeded from variant=%s)\n", b.Slug, b.SeedVariant)
+				}
+			}
 			for i, u := range units {
 				prefix := "│   ├──"
 				if i == len(units)-1 {
 					prefix = "│   └──"
 				}
-				fmt.Printf("    %s Unit: %s (variant=%s, %d workload(s))\n",
-					prefix, u.Slug, u.Variant, len(u.Workloads))
+				clones := ""
+				if u.Upstream != "" {
+					clones = ", clones " + u.Upstream
+				}
+				fmt.Printf("    %s Unit: %s (variant=%s, %d workload(s)%s)\n",
+					prefix, u.Slug, u.Variant, len(u.Workloads), clones)
 			}
 		}
 	}
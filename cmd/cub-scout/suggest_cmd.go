@@ -0,0 +1,204 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	suggestNamespace     string
+	suggestAll           bool
+	suggestContexts      []string
+	suggestSpace         string
+	suggestJSON          bool
+	suggestFormat        string
+	suggestRationale     bool
+	suggestMinConfidence float64
+)
+
+// suggestFormats are the --format values of 'suggest'.
+var suggestFormats = []string{"table", "json", "yaml"}
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Suggest ConfigHub units with confidence and rationale",
+	Long: `Suggest how cluster workloads group into ConfigHub units, with a
+confidence score and the signals behind each unit.
+
+Prints the proposed App Space and, per unit, its app, variant, base
+(upstream) unit and workloads, so teams can review the plan in a PR before
+importing. --format yaml or json writes the plan as a file to commit.
+
+With --rationale, each unit also lists the labels, GitOps paths and
+namespace patterns that decided its app and variant, and how many of its
+workloads each one covered. Confidence runs from 0 to 1: GitOps paths and
+recommended labels score high, namespace and workload-name guesses score
+low. Use --min-confidence to fail when any unit needs a human look.
+
+Examples:
+  cub-scout suggest                                  # Current cluster, as a table
+  cub-scout suggest -n payments --rationale          # One namespace, with reasons
+  cub-scout suggest --contexts dev,prod              # Same app across clusters
+  cub-scout suggest --format yaml > suggest.yaml     # Plan to review in a PR
+  cub-scout suggest --json --min-confidence 0.7      # Fail on weak groupings`,
+	Args: cobra.NoArgs,
+	RunE: runSuggest,
+}
+
+func init() {
+	rootCmd.AddCommand(suggestCmd)
+
+	suggestCmd.Flags().StringVarP(&suggestNamespace, "namespace", "n", "", "Filter by namespace")
+	_ = suggestCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	suggestCmd.Flags().BoolVarP(&suggestAll, "all", "A", false, "Include system namespaces")
+	suggestCmd.Flags().StringSliceVar(&suggestContexts, "contexts", nil, "Kube contexts to scan (same app across clusters becomes variants)")
+	_ = suggestCmd.RegisterFlagCompletionFunc("contexts", completeKubeContexts)
+	suggestCmd.Flags().StringVar(&suggestSpace, "space", "", "App Space to suggest units for")
+	_ = suggestCmd.RegisterFlagCompletionFunc("space", completeSpaces)
+	suggestCmd.Flags().StringVarP(&suggestFormat, "format", "o", "table", "Output format: "+strings.Join(suggestFormats, ", "))
+	_ = suggestCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(suggestFormats, cobra.ShellCompDirectiveNoFileComp))
+	suggestCmd.Flags().BoolVar(&suggestJSON, "json", false, "Output as JSON (same as --format json)")
+	suggestCmd.Flags().BoolVar(&suggestRationale, "rationale", false, "For table output: list the signals behind each unit")
+	suggestCmd.Flags().Float64Var(&suggestMinConfidence, "min-confidence", 0, "Exit with an error if any unit scores below this (0-1)")
+}
+
+func runSuggest(cmd *cobra.Command, args []string) error {
+	format := suggestFormat
+	if suggestJSON {
+		format = "json"
+	}
+	if !contains(suggestFormats, format) {
+		return fmt.Errorf("unknown --format %q (want %s)", format, strings.Join(suggestFormats, ", "))
+	}
+	if suggestMinConfidence < 0 || suggestMinConfidence > 1 {
+		return fmt.Errorf("--min-confidence must be between 0 and 1, got %g", suggestMinConfidence)
+	}
+
+	workloads, err := scanSuggestWorkloads(cmd.Context(), suggestContexts, suggestNamespace, suggestAll)
+	if err != nil {
+		return err
+	}
+	suggestion := SuggestHubAppSpaceStructure(workloads, suggestSpace)
+
+	switch format {
+	case "json":
+		if err := writeJSON(os.Stdout, "UnitSuggestions", convertToSuggestionJSON(&suggestion)); err != nil {
+			return err
+		}
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(convertToSuggestionJSON(&suggestion)); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	default:
+		if suggestRationale {
+			printUnitRationale(os.Stdout, &suggestion)
+		} else {
+			printSuggestionTable(os.Stdout, &suggestion)
+		}
+	}
+
+	if low := lowConfidenceUnits(&suggestion, suggestMinConfidence); len(low) > 0 {
+		return fmt.Errorf("%d unit(s) below confidence %g: %s", len(low), suggestMinConfidence, strings.Join(low, ", "))
+	}
+	return nil
+}
+
+// printSuggestionTable prints one row per suggested unit, base units before
+// the variants cloning them.
+func printSuggestionTable(w io.Writer, s *HubAppSpaceSuggestion) {
+	if len(s.Units) == 0 {
+		fmt.Fprintln(w, "No workloads found.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SPACE\tUNIT\tAPP\tVARIANT\tUPSTREAM\tCONFIDENCE\tWORKLOADS")
+	for _, u := range suggestedUnitsInOrder(s) {
+		upstream := u.Upstream
+		if upstream == "" {
+			upstream = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%.0f%%\t%s\n",
+			s.AppSpace, u.Slug, u.App, u.Variant, upstream, u.Confidence*100,
+			strings.Join(workloadRefs(u.Workloads), ","))
+	}
+	tw.Flush()
+}
+
+// suggestedUnitsInOrder returns the suggested units with each app's base unit
+// placed before its first variant.
+func suggestedUnitsInOrder(s *HubAppSpaceSuggestion) []HubAppSpaceUnit {
+	var units []HubAppSpaceUnit
+	placed := make(map[string]bool)
+	for _, u := range s.Units {
+		for _, b := range s.Bases {
+			if b.App == u.App && !placed[b.Slug] {
+				placed[b.Slug] = true
+				units = append(units, b)
+			}
+		}
+		units = append(units, u)
+	}
+	return units
+}
+
+// printUnitRationale prints each suggested unit with its confidence and the
+// signals that drove it. Base units come before the variants cloning them.
+func printUnitRationale(w io.Writer, s *HubAppSpaceSuggestion) {
+	fmt.Fprintf(w, "%sUnit Suggestions%s (App Space: %s)\n", colorBold, colorReset, s.AppSpace)
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	if len(s.Units) == 0 {
+		fmt.Fprintln(w, "No workloads found.")
+		return
+	}
+
+	for _, u := range suggestedUnitsInOrder(s) {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%s%s%s  %s%3.0f%%%s  app=%s variant=%s, %d workload(s)\n",
+			colorBold, u.Slug, colorReset, confidenceColor(u.Confidence), u.Confidence*100, colorReset,
+			u.App, u.Variant, len(u.Workloads))
+		if u.Upstream != "" {
+			fmt.Fprintf(w, "  clones %s\n", u.Upstream)
+		}
+		for _, r := range u.Rationale {
+			fmt.Fprintf(w, "  %s• %s%s\n", colorDim, r, colorReset)
+		}
+	}
+}
+
+// confidenceColor colors a confidence score: green when the grouping came
+// from deliberate signals, yellow for mixed, red for guesses.
+func confidenceColor(c float64) string {
+	switch {
+	case c >= 0.8:
+		return colorGreen
+	case c >= 0.6:
+		return colorYellow
+	default:
+		return colorRed
+	}
+}
+
+// lowConfidenceUnits returns the slugs of units scoring below threshold.
+func lowConfidenceUnits(s *HubAppSpaceSuggestion, threshold float64) []string {
+	var low []string
+	for _, u := range append(append([]HubAppSpaceUnit{}, s.Bases...), s.Units...) {
+		if u.Confidence < threshold {
+			low = append(low, u.Slug)
+		}
+	}
+	return low
+}
@@ -0,0 +1,110 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Signal kinds the suggestion engine groups workloads by.
+const (
+	signalFluxPath         = "flux-path"
+	signalArgoPath         = "argo-path"
+	signalNameLabel        = "name-label"
+	signalInstanceLabel    = "instance-label"
+	signalAppLabel         = "app-label"
+	signalEnvLabel         = "env-label"
+	signalNamespacePattern = "namespace-pattern"
+	signalCluster          = "cluster"
+	signalNamespace        = "namespace"
+	signalWorkloadName     = "workload-name"
+	signalSingleLocation   = "single-location"
+)
+
+// signalConfidence is how much each kind of signal is trusted, from 0 to 1.
+// GitOps paths and recommended labels are set on purpose; names and
+// locations are guesses.
+var signalConfidence = map[string]float64{
+	signalFluxPath:         0.95,
+	signalArgoPath:         0.95,
+	signalNameLabel:        0.9,
+	signalAppLabel:         0.8,
+	signalEnvLabel:         0.8,
+	signalInstanceLabel:    0.75,
+	signalCluster:          0.7,
+	signalSingleLocation:   0.7,
+	signalNamespacePattern: 0.6,
+	signalNamespace:        0.5,
+	signalWorkloadName:     0.4,
+}
+
+// suggestSignal is one piece of evidence for an app or variant.
+type suggestSignal struct {
+	Kind string // a key of signalConfidence
+	Text string // human-readable evidence, e.g. "label app=api"
+}
+
+// appVariantSignal is the inferred app and variant of one workload and the
+// signals that decided them.
+type appVariantSignal struct {
+	App, Variant             string
+	AppSource, VariantSource suggestSignal
+}
+
+// unitRationale scores a proposed unit from the signals of its workloads.
+// Each workload scores the weaker of its app and variant signals; the unit
+// scores their mean. The rationale lists each signal with how many workloads
+// it decided, most common first.
+func unitRationale(signals []appVariantSignal) (confidence float64, rationale []string) {
+	if len(signals) == 0 {
+		return 0, nil
+	}
+	type reason struct {
+		text  string
+		count int
+	}
+	var reasons []*reason
+	byText := make(map[string]*reason)
+	add := func(text string) {
+		if r, ok := byText[text]; ok {
+			r.count++
+			return
+		}
+		r := &reason{text: text, count: 1}
+		byText[text] = r
+		reasons = append(reasons, r)
+	}
+
+	total := 0.0
+	for _, s := range signals {
+		total += math.Min(signalConfidence[s.AppSource.Kind], signalConfidence[s.VariantSource.Kind])
+		add(fmt.Sprintf("app=%s from %s", s.App, s.AppSource.Text))
+		add(fmt.Sprintf("variant=%s from %s", s.Variant, s.VariantSource.Text))
+	}
+
+	sort.SliceStable(reasons, func(i, j int) bool { return reasons[i].count > reasons[j].count })
+	for _, r := range reasons {
+		rationale = append(rationale, fmt.Sprintf("%s (%d/%d workloads)", r.text, r.count, len(signals)))
+	}
+	return roundConfidence(total / float64(len(signals))), rationale
+}
+
+// baseRationale scores a base unit: it is only as sure as its least
+// certain variant.
+func baseRationale(seed string, variants []string, confidences []float64) (float64, []string) {
+	confidence := 1.0
+	for _, c := range confidences {
+		confidence = math.Min(confidence, c)
+	}
+	return confidence, []string{
+		fmt.Sprintf("upstream for %d variants: %v", len(variants), variants),
+		fmt.Sprintf("seeded from variant=%s (most workloads)", seed),
+	}
+}
+
+func roundConfidence(c float64) float64 {
+	return math.Round(c*100) / 100
+}
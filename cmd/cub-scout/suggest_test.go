@@ -3,7 +3,13 @@
 
 package main
 
-import "testing"
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
 
 func TestExtractVariantFromPath(t *testing.T) {
 	tests := []struct {
@@ -188,3 +194,197 @@ func TestInferAppAndVariant(t *testing.T) {
 		})
 	}
 }
+
+func TestInferAppVariantsAcrossLocations(t *testing.T) {
+	tests := []struct {
+		name      string
+		workloads []WorkloadInfo
+		want      []string
+	}{
+		{
+			name: "same app in environment clusters",
+			workloads: []WorkloadInfo{
+				{Name: "api", Namespace: "shop", Cluster: "eks-prod-1", Labels: map[string]string{"app": "api"}},
+				{Name: "api", Namespace: "shop", Cluster: "eks-dev-1", Labels: map[string]string{"app": "api"}},
+			},
+			want: []string{"prod", "dev"},
+		},
+		{
+			name: "clusters without distinct environment words use the cluster name",
+			workloads: []WorkloadInfo{
+				{Name: "api", Namespace: "shop", Cluster: "prod-eu", Labels: map[string]string{"app": "api"}},
+				{Name: "api", Namespace: "shop", Cluster: "prod-us", Labels: map[string]string{"app": "api"}},
+			},
+			want: []string{"prod-eu", "prod-us"},
+		},
+		{
+			name: "same app in several namespaces of one cluster",
+			workloads: []WorkloadInfo{
+				{Name: "api", Namespace: "team-a", Labels: map[string]string{"app": "api"}},
+				{Name: "api", Namespace: "team-b", Labels: map[string]string{"app": "api"}},
+			},
+			want: []string{"team-a", "team-b"},
+		},
+		{
+			name: "explicit variants are kept",
+			workloads: []WorkloadInfo{
+				{Name: "api", Namespace: "shop", Cluster: "c1", Labels: map[string]string{"app": "api", "env": "qa"}},
+				{Name: "api", Namespace: "shop", Cluster: "c2", Labels: map[string]string{"app": "api"}},
+			},
+			want: []string{"qa", "default"},
+		},
+		{
+			name: "single location stays default",
+			workloads: []WorkloadInfo{
+				{Name: "api", Namespace: "shop", Labels: map[string]string{"app": "api"}},
+				{Name: "worker", Namespace: "shop", Labels: map[string]string{"app": "api"}},
+			},
+			want: []string{"default", "default"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var variants []string
+			for _, s := range inferAppVariants(tt.workloads) {
+				variants = append(variants, s.Variant)
+			}
+			if strings.Join(variants, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("variants = %v, want %v", variants, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestStructureBaseLayout(t *testing.T) {
+	workloads := []WorkloadInfo{
+		{Name: "api", Kind: "Deployment", Namespace: "shop", Cluster: "dev", Labels: map[string]string{"app": "api"}},
+		{Name: "api", Kind: "Deployment", Namespace: "shop", Cluster: "prod", Labels: map[string]string{"app": "api"}},
+		{Name: "api-worker", Kind: "Deployment", Namespace: "shop", Cluster: "prod", Labels: map[string]string{"app": "api"}},
+		{Name: "cron", Kind: "Deployment", Namespace: "ops", Cluster: "prod", Labels: map[string]string{"app": "cron"}},
+	}
+
+	s := SuggestStructure(workloads, "shop")
+	if len(s.Apps) != 2 {
+		t.Fatalf("apps = %+v", s.Apps)
+	}
+	api := s.Apps[0]
+	if api.Base != "api-base" || api.BaseSeed != "prod" {
+		t.Errorf("api base = %q seeded from %q, want api-base from prod", api.Base, api.BaseSeed)
+	}
+	for _, v := range api.Variants {
+		if v.Upstream != "api-base" || v.UnitSlug != "api-"+v.Name {
+			t.Errorf("variant %+v should clone api-base", v)
+		}
+	}
+	if cron := s.Apps[1]; cron.Base != "" || cron.Variants[0].Upstream != "" {
+		t.Errorf("single-variant app should not get a base: %+v", cron)
+	}
+
+	hub := SuggestHubAppSpaceStructure(workloads, "shop")
+	if len(hub.Bases) != 1 || hub.Bases[0].Slug != "api-base" || len(hub.Bases[0].Workloads) != 2 {
+		t.Fatalf("hub bases = %+v", hub.Bases)
+	}
+
+	var buf bytes.Buffer
+	hub.GenerateUnits(&buf)
+	script := buf.String()
+	for _, want := range []string{
+		"cub space create shop",
+		"{ kubectl get deployment api -n shop -o yaml --context prod; echo ---; kubectl get deployment api-worker -n shop -o yaml --context prod; } | cub unit create api-base - --space shop --labels app=api,variant=base",
+		"cub unit create api-dev --space shop --upstream-unit api-base --labels app=api,variant=dev",
+		"cub unit create api-prod --space shop --upstream-unit api-base --labels app=api,variant=prod",
+		"kubectl get deployment cron -n ops -o yaml --context prod | cub unit create cron - --space shop --labels app=cron,variant=default",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Index(script, "api-base -") > strings.Index(script, "--upstream-unit api-base") {
+		t.Errorf("base must be created before its clones:\n%s", script)
+	}
+}
+
+func TestSuggestConfidenceAndRationale(t *testing.T) {
+	nameLabel := map[string]string{"app.kubernetes.io/name": "payment-api"}
+	workloads := []WorkloadInfo{
+		{Name: "payment-api", Kind: "Deployment", Namespace: "payments", Labels: nameLabel, KustomizationPath: "apps/payment-api/prod"},
+		{Name: "payment-api-worker", Kind: "Deployment", Namespace: "payments", Labels: nameLabel, KustomizationPath: "apps/payment-api/prod"},
+		{Name: "worker", Kind: "Deployment", Namespace: "payments", Cluster: "prod"},
+	}
+
+	hub := SuggestHubAppSpaceStructure(workloads, "payments")
+	if len(hub.Units) != 2 {
+		t.Fatalf("units = %+v", hub.Units)
+	}
+	api, worker := hub.Units[0], hub.Units[1]
+	if api.Slug != "payment-api-prod" || api.Confidence != 0.9 {
+		t.Errorf("payment-api-prod confidence = %v, want 0.9 (%+v)", api.Confidence, api)
+	}
+	wantRationale := []string{
+		"app=payment-api from label app.kubernetes.io/name=payment-api (2/2 workloads)",
+		"variant=prod from Flux Kustomization path apps/payment-api/prod (2/2 workloads)",
+	}
+	if strings.Join(api.Rationale, "\n") != strings.Join(wantRationale, "\n") {
+		t.Errorf("rationale = %q, want %q", api.Rationale, wantRationale)
+	}
+	// No labels: the app comes from the namespace, a weaker signal
+	if worker.Slug != "payments" || worker.Confidence != 0.6 {
+		t.Errorf("payments confidence = %v, want 0.6 (%+v)", worker.Confidence, worker)
+	}
+
+	if low := lowConfidenceUnits(&hub, 0.7); len(low) != 1 || low[0] != "payments" {
+		t.Errorf("lowConfidenceUnits = %v, want [payments]", low)
+	}
+
+	out := convertToSuggestionJSON(&hub)
+	if got := out.Units[1].Workloads; len(got) != 1 || got[0] != "prod:payments/worker" {
+		t.Errorf("worker workloads = %v", got)
+	}
+	if out.Units[0].Confidence != 0.9 || len(out.Units[0].Rationale) != 2 || len(out.Bases) != 0 {
+		t.Errorf("JSON unit = %+v, bases = %+v", out.Units[0], out.Bases)
+	}
+}
+
+func TestBaseRationaleTakesWeakestVariant(t *testing.T) {
+	confidence, rationale := baseRationale("prod", []string{"dev", "prod"}, []float64{0.8, 0.5})
+	if confidence != 0.5 {
+		t.Errorf("confidence = %v, want 0.5", confidence)
+	}
+	if len(rationale) != 2 || !strings.Contains(rationale[1], "variant=prod") {
+		t.Errorf("rationale = %q", rationale)
+	}
+}
+
+func TestSuggestTableAndYAML(t *testing.T) {
+	workloads := []WorkloadInfo{
+		{Name: "api", Kind: "Deployment", Namespace: "shop", Cluster: "dev", Labels: map[string]string{"app": "api"}},
+		{Name: "api", Kind: "Deployment", Namespace: "shop", Cluster: "prod", Labels: map[string]string{"app": "api"}},
+	}
+	hub := SuggestHubAppSpaceStructure(workloads, "shop")
+
+	var buf bytes.Buffer
+	printSuggestionTable(&buf, &hub)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "SPACE") {
+		t.Fatalf("table:\n%s", buf.String())
+	}
+	for i, want := range []string{"api-base", "api-dev", "api-prod"} {
+		if fields := strings.Fields(lines[i+1]); fields[0] != "shop" || fields[1] != want {
+			t.Errorf("row %d = %q, want unit %s", i+1, lines[i+1], want)
+		}
+	}
+	if !strings.Contains(lines[2], "api-base") || !strings.Contains(lines[2], "dev:shop/api") {
+		t.Errorf("variant row should show its upstream and workload: %q", lines[2])
+	}
+
+	out, err := yaml.Marshal(convertToSuggestionJSON(&hub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"appSpace: shop", "upstream: api-base", "- prod:shop/api"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("yaml missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// baseVariant is the variant name of the proposed base unit an app's variants clone from.
+const baseVariant = "base"
+
+// inferAppVariants returns the app and variant of each workload. On top of
+// inferSignals, an app found in several clusters or namespaces with no
+// other variant signal gets one variant per location, so the same app in
+// dev/staging/prod clusters is not merged into a single unit.
+func inferAppVariants(workloads []WorkloadInfo) []appVariantSignal {
+	signals := make([]appVariantSignal, len(workloads))
+
+	type locations struct{ clusters, namespaces map[string]bool }
+	unplaced := make(map[string]*locations) // app -> locations of variant-less workloads
+	for i, w := range workloads {
+		signals[i] = inferSignals(w)
+		if signals[i].Variant != "" {
+			continue
+		}
+		l := unplaced[signals[i].App]
+		if l == nil {
+			l = &locations{clusters: map[string]bool{}, namespaces: map[string]bool{}}
+			unplaced[signals[i].App] = l
+		}
+		l.clusters[w.Cluster] = true
+		l.namespaces[w.Namespace] = true
+	}
+
+	for i, w := range workloads {
+		s := &signals[i]
+		if s.Variant != "" {
+			continue
+		}
+		l := unplaced[s.App]
+		switch {
+		case len(l.clusters) > 1:
+			s.Variant = locationVariant(w.Cluster, l.clusters)
+			s.VariantSource = suggestSignal{signalCluster, fmt.Sprintf("cluster %s (app runs in %d clusters)", w.Cluster, len(l.clusters))}
+		case len(l.namespaces) > 1:
+			s.Variant = locationVariant(w.Namespace, l.namespaces)
+			s.VariantSource = suggestSignal{signalNamespace, fmt.Sprintf("namespace %s (app runs in %d namespaces)", w.Namespace, len(l.namespaces))}
+		default:
+			s.Variant = "default"
+			s.VariantSource = suggestSignal{signalSingleLocation, "no variant signal and a single location"}
+		}
+	}
+	return signals
+}
+
+// locationVariant names the variant for a cluster or namespace. It uses the
+// environment word in the name (e.g. "prod" in "prod-eu") when every sibling
+// location has a distinct one, and the location name itself otherwise.
+func locationVariant(location string, siblings map[string]bool) string {
+	seen := make(map[string]bool)
+	for loc := range siblings {
+		v := environmentWord(loc)
+		if v == "" || seen[v] {
+			return locationSlug(location)
+		}
+		seen[v] = true
+	}
+	return environmentWord(location)
+}
+
+// environmentWord returns the normalized environment word in a name
+// ("staging" for "eu-stage-1"), or "".
+func environmentWord(name string) string {
+	for _, part := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	}) {
+		for _, v := range variantPatterns {
+			if part == v {
+				return normalizeVariant(v)
+			}
+		}
+	}
+	return ""
+}
+
+func locationSlug(location string) string {
+	if location == "" {
+		return "default"
+	}
+	return sanitizeSlug(location)
+}
+
+// baseSeed picks the variant whose workloads seed an app's base unit: the
+// one with the most workloads, first by name on ties.
+func baseSeed(variants []VariantSuggestion) string {
+	seed, most := "", -1
+	for _, v := range variants {
+		if len(v.Workloads) > most {
+			seed, most = v.Name, len(v.Workloads)
+		}
+	}
+	return seed
+}
+
+// proposeBase turns an app with two or more variants into a base+variant
+// layout: a <app>-base upstream unit that each variant clones from.
+func proposeBase(app *AppSuggestion) {
+	if len(app.Variants) < 2 {
+		return
+	}
+	app.Base = sanitizeSlug(app.Name + "-" + baseVariant)
+	app.BaseSeed = baseSeed(app.Variants)
+	for i := range app.Variants {
+		app.Variants[i].Upstream = app.Base
+	}
+}
+
+// proposeHubBases adds a base unit per app with two or more variants and
+// points each variant unit at it.
+func proposeHubBases(s *HubAppSpaceSuggestion) {
+	byApp := make(map[string][]int)
+	var apps []string
+	for i, u := range s.Units {
+		if byApp[u.App] == nil {
+			apps = append(apps, u.App)
+		}
+		byApp[u.App] = append(byApp[u.App], i)
+	}
+	sort.Strings(apps)
+
+	for _, app := range apps {
+		idx := byApp[app]
+		if len(idx) < 2 {
+			continue
+		}
+		variants := make([]VariantSuggestion, len(idx))
+		for j, i := range idx {
+			variants[j] = VariantSuggestion{Name: s.Units[i].Variant, Workloads: s.Units[i].Workloads}
+		}
+		seed := baseSeed(variants)
+		base := HubAppSpaceUnit{Slug: sanitizeSlug(app + "-" + baseVariant), App: app, Variant: baseVariant, SeedVariant: seed}
+		var names []string
+		var confidences []float64
+		for _, i := range idx {
+			s.Units[i].Upstream = base.Slug
+			if s.Units[i].Variant == seed {
+				base.Workloads = s.Units[i].Workloads
+			}
+			names = append(names, s.Units[i].Variant)
+			confidences = append(confidences, s.Units[i].Confidence)
+		}
+		base.Confidence, base.Rationale = baseRationale(seed, names, confidences)
+		s.Bases = append(s.Bases, base)
+	}
+}
+
+// GenerateUnits writes a shell script that creates the suggested units:
+// base units seeded from live workloads, then variant units as downstream
+// clones of their base. Apps with one variant get a plain unit.
+func (s *HubAppSpaceSuggestion) GenerateUnits(w io.Writer) {
+	fmt.Fprintln(w, "#!/bin/sh")
+	fmt.Fprintln(w, "# Generated by: cub-scout tree suggest --generate-units")
+	fmt.Fprintln(w, "# Review before running. Base units are seeded from live workloads;")
+	fmt.Fprintln(w, "# variant units clone their base (cub unit tree --edge clone).")
+	fmt.Fprintln(w, "set -e")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "cub space create %s || true\n", s.AppSpace)
+
+	bases := make(map[string]HubAppSpaceUnit)
+	for _, b := range s.Bases {
+		bases[b.App] = b
+	}
+	done := make(map[string]bool)
+	for _, u := range s.Units {
+		if b, ok := bases[u.App]; ok && !done[b.Slug] {
+			done[b.Slug] = true
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "# app=%s: base seeded from variant %s\n", b.App, b.SeedVariant)
+			fmt.Fprintf(w, "%s | cub unit create %s - --space %s --labels app=%s,variant=%s\n",
+				seedCommand(b.Workloads), b.Slug, s.AppSpace, b.App, baseVariant)
+		}
+		if u.Upstream != "" {
+			fmt.Fprintf(w, "cub unit create %s --space %s --upstream-unit %s --labels app=%s,variant=%s\n",
+				u.Slug, s.AppSpace, u.Upstream, u.App, u.Variant)
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "# app=%s\n", u.App)
+		fmt.Fprintf(w, "%s | cub unit create %s - --space %s --labels app=%s,variant=%s\n",
+			seedCommand(u.Workloads), u.Slug, s.AppSpace, u.App, u.Variant)
+	}
+}
+
+// seedCommand returns a shell command printing the workloads as one
+// multi-document YAML stream.
+func seedCommand(workloads []WorkloadInfo) string {
+	if len(workloads) == 0 {
+		return "echo '# no workloads'"
+	}
+	var parts []string
+	for _, wl := range workloads {
+		get := fmt.Sprintf("kubectl get %s %s -n %s -o yaml", strings.ToLower(wl.Kind), wl.Name, wl.Namespace)
+		if wl.Cluster != "" {
+			get += " --context " + wl.Cluster
+		}
+		parts = append(parts, get)
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "{ " + strings.Join(parts, "; echo ---; ") + "; }"
+}
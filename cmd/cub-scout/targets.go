@@ -0,0 +1,238 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confighub/cub-scout/internal/hierarchysvc"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	targetsSpace string
+	targetsJSON  bool
+)
+
+var targetsCmd = &cobra.Command{
+	Use:   "targets",
+	Short: "Inspect ConfigHub targets",
+	Long:  `Inspect ConfigHub targets and the workers and clusters behind them.`,
+}
+
+var targetsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Probe target health: worker liveness, last apply and cluster reachability",
+	Long: `Probe every target in a space and report a real status for each:
+
+  error  no worker assigned, worker not Ready, or KubeContext unreachable
+  warn   worker heartbeat older than 5m, or KubeContext not in the local
+         kubeconfig (the worker may run elsewhere; reachability unchecked)
+  ok     everything checked answered
+
+The KubeContext is read from the target parameters and contacted with a
+short timeout using your local kubeconfig. Last apply is the newest
+completed apply of any unit on the target.
+
+Exit status is non-zero when any target is in error.
+
+Examples:
+  cub-scout targets status
+  cub-scout targets status --space prod
+  cub-scout targets status --json`,
+	RunE: runTargetsStatus,
+}
+
+func init() {
+	targetsStatusCmd.Flags().StringVar(&targetsSpace, "space", "", "ConfigHub space (default: current cub context space)")
+	targetsStatusCmd.Flags().BoolVar(&targetsJSON, "json", false, "Output as JSON")
+	targetsCmd.AddCommand(targetsStatusCmd)
+	rootCmd.AddCommand(targetsCmd)
+}
+
+// kubeContextProbeTimeout bounds each KubeContext reachability check.
+const kubeContextProbeTimeout = 5 * time.Second
+
+// TargetStatus is one row of `targets status`.
+type TargetStatus struct {
+	Target      string    `json:"target"`
+	Provider    string    `json:"provider,omitempty"`
+	Worker      string    `json:"worker,omitempty"`
+	KubeContext string    `json:"kubeContext,omitempty"`
+	LastApply   time.Time `json:"lastApply"`
+	hierarchysvc.TargetHealth
+}
+
+// contextProber reports whether a kubeconfig context exists locally and,
+// if it does, the error from contacting its API server.
+type contextProber func(name string) (found bool, err error)
+
+// probeKubeContext checks a context from the local kubeconfig by asking its
+// API server for its version.
+func probeKubeContext(name string) (bool, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	raw, err := rules.Load()
+	if err != nil {
+		return false, nil
+	}
+	if _, ok := raw.Contexts[name]; !ok {
+		return false, nil
+	}
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules,
+		&clientcmd.ConfigOverrides{CurrentContext: name}).ClientConfig()
+	if err != nil {
+		return true, err
+	}
+	cfg.Timeout = kubeContextProbeTimeout
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return true, err
+	}
+	_, err = client.Discovery().ServerVersion()
+	return true, err
+}
+
+// probeTargets probes every target against the space's units and workers.
+// Each distinct KubeContext is contacted once.
+func probeTargets(units []CubUnitData, targets []CubTargetData, workers []CubWorkerData, prober contextProber, now time.Time) []TargetStatus {
+	workersByID := make(map[string]CubWorkerData, len(workers))
+	for _, w := range workers {
+		workersByID[w.BridgeWorker.BridgeWorkerID] = w
+	}
+	lastApply := make(map[string]time.Time)
+	for _, u := range units {
+		if u.UnitStatus.Action != "Apply" || u.Target.Slug == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, u.UnitStatus.ActionTerminatedAt); err == nil && t.After(lastApply[u.Target.Slug]) {
+			lastApply[u.Target.Slug] = t
+		}
+	}
+	type contextResult struct {
+		found bool
+		err   error
+	}
+	contexts := make(map[string]contextResult)
+
+	statuses := make([]TargetStatus, 0, len(targets))
+	for _, t := range targets {
+		p := hierarchysvc.TargetProbe{
+			WorkerSlug:  t.Target.BridgeWorkerID,
+			LastApply:   lastApply[t.Target.Slug],
+			KubeContext: t.KubeContext(),
+		}
+		if w, ok := workersByID[t.Target.BridgeWorkerID]; ok {
+			p.WorkerSlug = w.BridgeWorker.Slug
+			p.WorkerFound = true
+			p.WorkerCondition = w.BridgeWorker.Condition
+			if seen, err := time.Parse(time.RFC3339, w.BridgeWorker.LastSeenAt); err == nil {
+				p.WorkerLastSeen = seen
+			}
+		}
+		if p.KubeContext != "" && prober != nil {
+			res, ok := contexts[p.KubeContext]
+			if !ok {
+				res.found, res.err = prober(p.KubeContext)
+				contexts[p.KubeContext] = res
+			}
+			p.ContextChecked, p.ContextErr = res.found, res.err
+		}
+		statuses = append(statuses, TargetStatus{
+			Target:       t.Target.Slug,
+			Provider:     t.Target.ProviderType,
+			Worker:       p.WorkerSlug,
+			KubeContext:  p.KubeContext,
+			LastApply:    p.LastApply,
+			TargetHealth: hierarchysvc.ProbeTarget(p, now),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Target < statuses[j].Target })
+	return statuses
+}
+
+// targetHealthBySlug indexes probe results for the hierarchy tree.
+func targetHealthBySlug(statuses []TargetStatus) map[string]hierarchysvc.TargetHealth {
+	health := make(map[string]hierarchysvc.TargetHealth, len(statuses))
+	for _, s := range statuses {
+		health[s.Target] = s.TargetHealth
+	}
+	return health
+}
+
+func runTargetsStatus(cmd *cobra.Command, args []string) error {
+	space := targetsSpace
+	if space == "" {
+		var err error
+		if space, err = getCurrentSpace(); err != nil {
+			return fmt.Errorf("no --space given and %w", err)
+		}
+	}
+	units, err := loadUnitsForSpace(space)
+	if err != nil {
+		return fmt.Errorf("list units in %s: %w", space, err)
+	}
+	targets, err := loadTargetsForSpace(space)
+	if err != nil {
+		return fmt.Errorf("list targets in %s: %w", space, err)
+	}
+	workers, err := loadWorkersForSpace(space)
+	if err != nil {
+		return fmt.Errorf("list workers in %s: %w", space, err)
+	}
+
+	statuses := probeTargets(units, targets, workers, probeKubeContext, time.Now())
+	if targetsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(statuses); err != nil {
+			return err
+		}
+	} else {
+		printTargetStatuses(os.Stdout, space, statuses, time.Now())
+	}
+
+	failed := 0
+	for _, s := range statuses {
+		if s.Status == hierarchysvc.TargetError {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d target(s) in error", failed)
+	}
+	return nil
+}
+
+func printTargetStatuses(w io.Writer, space string, statuses []TargetStatus, now time.Time) {
+	if len(statuses) == 0 {
+		fmt.Fprintf(w, "No targets in space %s.\n", space)
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tWORKER\tCONTEXT\tLAST APPLY\tSTATUS\tDETAIL")
+	for _, s := range statuses {
+		applied := "never"
+		if !s.LastApply.IsZero() {
+			applied = formatDuration(now.Sub(s.LastApply)) + " ago"
+		}
+		color := colorGreen
+		switch s.Status {
+		case hierarchysvc.TargetWarn:
+			color = colorYellow
+		case hierarchysvc.TargetError:
+			color = colorRed
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s%s%s\t%s\n", s.Target, orDash(s.Worker), orDash(s.KubeContext),
+			applied, color, s.Status, colorReset, s.Summary())
+	}
+	tw.Flush()
+}
@@ -0,0 +1,81 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/confighub/cub-scout/internal/hierarchysvc"
+)
+
+func TestProbeTargets(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var prod, dev, orphan CubTargetData
+	prod.Target.Slug, prod.Target.BridgeWorkerID, prod.Target.Parameters = "prod", "w-1", `{"KubeContext":"prod-ctx"}`
+	dev.Target.Slug, dev.Target.BridgeWorkerID, dev.Target.Parameters = "dev", "w-2", `{"KubeContext":"prod-ctx"}`
+	orphan.Target.Slug = "orphan"
+
+	var ready, down CubWorkerData
+	ready.BridgeWorker.BridgeWorkerID, ready.BridgeWorker.Slug, ready.BridgeWorker.Condition = "w-1", "prod-worker", "Ready"
+	ready.BridgeWorker.LastSeenAt = now.Add(-30 * time.Second).Format(time.RFC3339)
+	down.BridgeWorker.BridgeWorkerID, down.BridgeWorker.Slug, down.BridgeWorker.Condition = "w-2", "dev-worker", "Disconnected"
+
+	var older, newer, synced CubUnitData
+	older.Target.Slug, older.UnitStatus.Action, older.UnitStatus.ActionTerminatedAt = "prod", "Apply", "2026-03-01T10:00:00Z"
+	newer.Target.Slug, newer.UnitStatus.Action, newer.UnitStatus.ActionTerminatedAt = "prod", "Apply", "2026-03-01T11:00:00Z"
+	synced.Target.Slug, synced.UnitStatus.Action, synced.UnitStatus.ActionTerminatedAt = "prod", "Refresh", "2026-03-01T11:30:00Z"
+
+	probes := 0
+	prober := func(name string) (bool, error) {
+		probes++
+		return true, nil
+	}
+
+	statuses := probeTargets([]CubUnitData{older, newer, synced}, []CubTargetData{prod, orphan, dev},
+		[]CubWorkerData{ready, down}, prober, now)
+
+	if probes != 1 {
+		t.Errorf("shared KubeContext probed %d times, want 1", probes)
+	}
+	if len(statuses) != 3 || statuses[0].Target != "dev" || statuses[1].Target != "orphan" || statuses[2].Target != "prod" {
+		t.Fatalf("statuses not sorted by target: %+v", statuses)
+	}
+	if s := statuses[0]; s.Status != hierarchysvc.TargetError || s.Summary() != "worker dev-worker is Disconnected" {
+		t.Errorf("dev = %+v, want error for disconnected worker", s)
+	}
+	if s := statuses[1]; s.Status != hierarchysvc.TargetError || s.Summary() != "no worker assigned" {
+		t.Errorf("orphan = %+v, want error for missing worker", s)
+	}
+	prodStatus := statuses[2]
+	if prodStatus.Status != hierarchysvc.TargetOK || prodStatus.Worker != "prod-worker" || prodStatus.KubeContext != "prod-ctx" {
+		t.Errorf("prod = %+v, want ok on prod-worker/prod-ctx", prodStatus)
+	}
+	if want := time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC); !prodStatus.LastApply.Equal(want) {
+		t.Errorf("prod last apply = %v, want %v (newest Apply, ignoring other actions)", prodStatus.LastApply, want)
+	}
+
+	var buf bytes.Buffer
+	printTargetStatuses(&buf, "space", statuses, now)
+	if !strings.Contains(buf.String(), "1h0m ago") || !strings.Contains(buf.String(), "never") {
+		t.Errorf("table missing last apply columns:\n%s", buf.String())
+	}
+}
+
+func TestProbeTargetsUnreachableContext(t *testing.T) {
+	var target CubTargetData
+	target.Target.Slug, target.Target.BridgeWorkerID, target.Target.Parameters = "prod", "w-1", `{"KubeContext":"gone"}`
+	var worker CubWorkerData
+	worker.BridgeWorker.BridgeWorkerID, worker.BridgeWorker.Slug, worker.BridgeWorker.Condition = "w-1", "w", "Ready"
+
+	statuses := probeTargets(nil, []CubTargetData{target}, []CubWorkerData{worker},
+		func(string) (bool, error) { return true, errors.New("connection refused") }, time.Now())
+	if statuses[0].Status != hierarchysvc.TargetError {
+		t.Errorf("unreachable context status = %q, want error", statuses[0].Status)
+	}
+}
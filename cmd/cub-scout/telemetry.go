@@ -0,0 +1,200 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	logLevel  string
+	logFormat string
+
+	// logger writes structured diagnostics to stderr. Command output stays on
+	// stdout; the logger is for timings, failures and agent-mode events.
+	logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	// commandCtx carries the span of the running command. Kubernetes and cub
+	// calls made with context.Background() are parented to it.
+	commandCtx  = context.Background()
+	commandSpan trace.Span
+
+	shutdownTracing = func(context.Context) error { return nil }
+)
+
+const tracerName = "github.com/confighub/cub-scout"
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "Log level: debug, info, warn, error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Log format: text or json")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := startTelemetry(cmd, args); err != nil {
+			return err
+		}
+		return applyReadOnly(cmd)
+	}
+}
+
+// newLogger builds the stderr logger for --log-level and --log-format.
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid --log-level %q (valid: debug, info, warn, error)", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("invalid --log-format %q (valid: text, json)", format)
+}
+
+// startTelemetry configures logging and, when an OTLP endpoint is set in the
+// standard OTEL_EXPORTER_OTLP_* variables, exports a trace per command.
+func startTelemetry(cmd *cobra.Command, args []string) error {
+	l, err := newLogger(os.Stderr, logLevel, logFormat)
+	if err != nil {
+		return err
+	}
+	logger = l
+	slog.SetDefault(logger)
+
+	ctx := cmd.Context()
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return fmt.Errorf("create OTLP trace exporter: %w", err)
+		}
+		res, err := resource.New(ctx,
+			resource.WithAttributes(
+				attribute.String("service.name", "cub-scout"),
+				attribute.String("service.version", BuildTag),
+			),
+			resource.WithFromEnv(),
+		)
+		if err != nil {
+			return fmt.Errorf("build trace resource: %w", err)
+		}
+		tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+		otel.SetTracerProvider(tp)
+		shutdownTracing = tp.Shutdown
+		logger.Debug("tracing enabled", "exporter", "otlp/http")
+	}
+
+	commandCtx, commandSpan = otel.Tracer(tracerName).Start(ctx, cmd.CommandPath(),
+		trace.WithAttributes(attribute.StringSlice("cub-scout.args", args)))
+	cmd.SetContext(commandCtx)
+	return nil
+}
+
+// finishTelemetry ends the command span and flushes pending spans.
+func finishTelemetry(err error) {
+	if commandSpan == nil {
+		return
+	}
+	if err != nil {
+		commandSpan.RecordError(err)
+		commandSpan.SetStatus(codes.Error, err.Error())
+	}
+	commandSpan.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Warn("flush traces failed", "err", err)
+	}
+}
+
+// spanContext returns ctx if it already carries a span, otherwise the
+// command context, so calls made with context.Background() join the trace.
+func spanContext(ctx context.Context) context.Context {
+	if trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, trace.SpanFromContext(commandCtx))
+}
+
+// tracingRoundTripper records a span and a debug log line per Kubernetes
+// API request.
+type tracingRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (t *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(tracerName).Start(spanContext(req.Context()), "k8s "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+			attribute.String("server.address", req.URL.Host),
+		))
+	defer span.End()
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	elapsed := time.Since(start)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Debug("k8s request", "method", req.Method, "path", req.URL.Path, "duration", elapsed, "err", err)
+		return resp, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 500 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	logger.Debug("k8s request", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "duration", elapsed)
+	return resp, nil
+}
+
+// traceCub starts a span for a cub CLI call. Call the returned function with
+// the call's error when it finishes.
+func traceCub(args []string) func(error) {
+	_, span := otel.Tracer(tracerName).Start(commandCtx, "cub "+cubSubcommand(args),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.StringSlice("cub.args", args)))
+	start := time.Now()
+	return func(err error) {
+		elapsed := time.Since(start)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			logger.Debug("cub command", "args", strings.Join(args, " "), "duration", elapsed, "err", err)
+		} else {
+			logger.Debug("cub command", "args", strings.Join(args, " "), "duration", elapsed)
+		}
+		span.End()
+	}
+}
+
+// cubSubcommand returns the leading non-flag words, e.g. "unit list".
+func cubSubcommand(args []string) string {
+	var words []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") || len(words) == 2 {
+			break
+		}
+		words = append(words, a)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,91 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := newLogger(&buf, "info", "json")
+	if err != nil {
+		t.Fatalf("newLogger: %v", err)
+	}
+	l.Debug("hidden")
+	l.Info("export done", "resources", 3)
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("expected one JSON line, got %q: %v", buf.String(), err)
+	}
+	if line["msg"] != "export done" || line["level"] != "INFO" || line["resources"] != float64(3) {
+		t.Errorf("unexpected log line: %v", line)
+	}
+
+	if _, err := newLogger(&buf, "verbose", "text"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+	if _, err := newLogger(&buf, "info", "yaml"); err == nil {
+		t.Error("expected error for invalid format")
+	}
+}
+
+func TestTracingRoundTripper(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prev)
+
+	var logs bytes.Buffer
+	prevLogger := logger
+	logger, _ = newLogger(&logs, "debug", "text")
+	defer func() { logger = prevLogger }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &tracingRoundTripper{next: http.DefaultTransport}}
+	resp, err := client.Get(srv.URL + "/apis/kustomize.toolkit.fluxcd.io/v1/kustomizations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	done := traceCub([]string{"unit", "list", "--space", "prod"})
+	done(nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name() != "k8s GET" {
+		t.Errorf("span name = %q", spans[0].Name())
+	}
+	attrs := attribute.NewSet(spans[0].Attributes()...)
+	if v, _ := attrs.Value("http.response.status_code"); v.AsInt64() != 404 {
+		t.Errorf("status attribute = %v", v)
+	}
+	if v, _ := attrs.Value("url.path"); v.AsString() != "/apis/kustomize.toolkit.fluxcd.io/v1/kustomizations" {
+		t.Errorf("path attribute = %v", v)
+	}
+	if spans[1].Name() != "cub unit list" {
+		t.Errorf("cub span name = %q", spans[1].Name())
+	}
+	if !strings.Contains(logs.String(), "k8s request") || !strings.Contains(logs.String(), "status=404") {
+		t.Errorf("expected debug log for request, got %q", logs.String())
+	}
+}
@@ -0,0 +1,360 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	timelineNamespace string
+	timelineSince     string
+	timelineJSON      bool
+)
+
+var timelineCmd = &cobra.Command{
+	Use:   "timeline <kind/name>",
+	Short: "Show a chronological incident timeline for a resource",
+	Long: `Interleave everything that happened to a resource into one chronological list:
+
+  - Deployer syncs (Flux/Argo CD/Helm history)
+  - ConfigHub unit revisions
+  - Field manager writes (managedFields), including manual kubectl changes
+  - Kubernetes Events for the resource, its ReplicaSets and Pods
+  - Container restarts (with termination reason)
+
+The result is a ready-made incident timeline.
+
+Examples:
+  cub-scout timeline deploy/api -n prod
+  cub-scout timeline deploy/api -n prod --since 6h
+  cub-scout timeline deploy/api -n prod --json`,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE:              runTimeline,
+	ValidArgsFunction: completeResourceArg,
+}
+
+func init() {
+	rootCmd.AddCommand(timelineCmd)
+	timelineCmd.Flags().StringVarP(&timelineNamespace, "namespace", "n", "default", "Namespace of the resource")
+	timelineCmd.Flags().StringVar(&timelineSince, "since", "24h", "How far back to look (e.g., 1h, 24h, 7d)")
+	timelineCmd.Flags().BoolVar(&timelineJSON, "json", false, "Output as JSON")
+	_ = timelineCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	_ = timelineCmd.RegisterFlagCompletionFunc("since", completeSince)
+}
+
+// TimelineEntry is one event in a resource timeline.
+type TimelineEntry struct {
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"`              // Flux, ArgoCD, Helm, ConfigHub, Kubernetes, Restart, or a field manager tool
+	Namespace string    `json:"namespace,omitempty"` // set in the cluster-wide incident stream
+	Object    string    `json:"object,omitempty"`
+	Message   string    `json:"message"`
+	Warning   bool      `json:"warning,omitempty"`
+}
+
+func runTimeline(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	kind, name, err := parseResourceArgs(args)
+	if err != nil {
+		return err
+	}
+	window, err := parseDuration(timelineSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	since := time.Now().Add(-window)
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("create dynamic client: %w", err)
+	}
+
+	gvr := kindToGVR(kind)
+	if gvr.Resource == "" {
+		return fmt.Errorf("unknown resource kind: %s", kind)
+	}
+	obj, err := dynClient.Resource(gvr).Namespace(timelineNamespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get %s/%s: %w", kind, name, err)
+	}
+
+	var entries []TimelineEntry
+
+	ownership := agent.DetectOwnership(obj)
+	if d := fetchDeployerRevision(ctx, dynClient, ownership, timelineNamespace, 0); d != nil {
+		entries = append(entries, timelineFromDeployer(d, displayOwner(ownership.Type))...)
+	}
+	if ch := configHubBlame(obj, 0); ch != nil {
+		entries = append(entries, timelineFromRevisions(ch)...)
+	}
+	entries = append(entries, timelineFromManagers(agent.ParseManagedFields(obj))...)
+
+	// Related objects: the resource itself plus ReplicaSets and Pods it selects
+	related := map[string]bool{kind + "/" + name: true}
+	pods := relatedPods(ctx, dynClient, obj, related)
+	entries = append(entries, timelineFromPods(pods)...)
+
+	eventList, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "events"}).Namespace(timelineNamespace).List(ctx, v1.ListOptions{})
+	if err == nil {
+		entries = append(entries, timelineFromEvents(eventList.Items, related)...)
+	}
+
+	entries = sortTimeline(entries, since)
+
+	if timelineJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	printTimeline(agent.ResourceRef{Kind: kind, Name: name, Namespace: timelineNamespace}, timelineSince, entries)
+	return nil
+}
+
+// relatedPods lists the ReplicaSets and Pods selected by a workload, adding
+// "Kind/name" keys for each to related. Non-workloads return nil.
+func relatedPods(ctx context.Context, dynClient dynamic.Interface, obj *unstructured.Unstructured, related map[string]bool) []unstructured.Unstructured {
+	matchLabels, found, _ := unstructured.NestedStringMap(obj.Object, "spec", "selector", "matchLabels")
+	if !found || len(matchLabels) == 0 {
+		return nil
+	}
+	var parts []string
+	for k, v := range matchLabels {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	opts := v1.ListOptions{LabelSelector: strings.Join(parts, ",")}
+
+	if obj.GetKind() == "Deployment" {
+		rsList, err := dynClient.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}).Namespace(obj.GetNamespace()).List(ctx, opts)
+		if err == nil {
+			for _, rs := range rsList.Items {
+				related["ReplicaSet/"+rs.GetName()] = true
+			}
+		}
+	}
+
+	podList, err := dynClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace(obj.GetNamespace()).List(ctx, opts)
+	if err != nil {
+		return nil
+	}
+	for _, pod := range podList.Items {
+		related["Pod/"+pod.GetName()] = true
+	}
+	return podList.Items
+}
+
+// timelineFromDeployer converts deployer history into timeline entries.
+func timelineFromDeployer(d *DeployerRevision, owner string) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, h := range d.History {
+		if h.Timestamp.IsZero() {
+			continue
+		}
+		msg := "applied " + h.Revision
+		if h.Status != "" {
+			msg += " (" + h.Status + ")"
+		}
+		if h.Source != "" {
+			msg += " — " + h.Source
+		}
+		entries = append(entries, TimelineEntry{
+			Time:    h.Timestamp,
+			Source:  owner,
+			Object:  d.Kind + "/" + d.Name,
+			Message: msg,
+			Warning: strings.Contains(strings.ToLower(h.Status), "fail"),
+		})
+	}
+	return entries
+}
+
+// timelineFromRevisions converts ConfigHub unit revisions into timeline entries.
+func timelineFromRevisions(ch *BlameConfigHub) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, rev := range ch.Revisions {
+		if rev.CreatedAt.IsZero() {
+			continue
+		}
+		msg := fmt.Sprintf("rev %d", rev.Num)
+		if rev.Description != "" {
+			msg += fmt.Sprintf(" %q", rev.Description)
+		}
+		if rev.Author != "" {
+			msg += " by " + rev.Author
+		}
+		entries = append(entries, TimelineEntry{
+			Time:    rev.CreatedAt,
+			Source:  "ConfigHub",
+			Object:  "unit/" + ch.Unit,
+			Message: msg,
+		})
+	}
+	return entries
+}
+
+// timelineFromManagers records each field manager's last write. Manual
+// kubectl writes are flagged as warnings.
+func timelineFromManagers(managers []agent.FieldManager) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, m := range managers {
+		if m.Time == nil || m.Subresource != "" {
+			continue
+		}
+		msg := strings.ToLower(m.Operation) + " by " + m.Manager
+		if len(m.Fields) > 0 {
+			msg += ": " + strings.Join(m.Fields, ", ")
+		}
+		entries = append(entries, TimelineEntry{
+			Time:    *m.Time,
+			Source:  m.Tool,
+			Message: msg,
+			Warning: m.ManualChange(),
+		})
+	}
+	return entries
+}
+
+// timelineFromPods reports the last termination of each restarted container.
+func timelineFromPods(pods []unstructured.Unstructured) []TimelineEntry {
+	var entries []TimelineEntry
+	for _, pod := range pods {
+		statuses, _, _ := unstructured.NestedSlice(pod.Object, "status", "containerStatuses")
+		for _, s := range statuses {
+			cs, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			restarts, _, _ := unstructured.NestedInt64(cs, "restartCount")
+			if restarts == 0 {
+				continue
+			}
+			finished, _, _ := unstructured.NestedString(cs, "lastState", "terminated", "finishedAt")
+			ts, err := time.Parse(time.RFC3339, finished)
+			if err != nil {
+				continue
+			}
+			container, _, _ := unstructured.NestedString(cs, "name")
+			reason, _, _ := unstructured.NestedString(cs, "lastState", "terminated", "reason")
+			exitCode, _, _ := unstructured.NestedInt64(cs, "lastState", "terminated", "exitCode")
+			entries = append(entries, TimelineEntry{
+				Time:    ts,
+				Source:  "Restart",
+				Object:  "Pod/" + pod.GetName(),
+				Message: fmt.Sprintf("container %s terminated: %s (exit %d), %d restarts total", container, reason, exitCode, restarts),
+				Warning: true,
+			})
+		}
+	}
+	return entries
+}
+
+// timelineFromEvents converts Events whose involved object is in related,
+// or every Event when related is nil.
+func timelineFromEvents(events []unstructured.Unstructured, related map[string]bool) []TimelineEntry {
+	var entries []TimelineEntry
+	for i := range events {
+		ev := &events[i]
+		kind, _, _ := unstructured.NestedString(ev.Object, "involvedObject", "kind")
+		name, _, _ := unstructured.NestedString(ev.Object, "involvedObject", "name")
+		if related != nil && !related[kind+"/"+name] {
+			continue
+		}
+		ts := eventTimestamp(ev)
+		if ts.IsZero() {
+			continue
+		}
+		reason, _, _ := unstructured.NestedString(ev.Object, "reason")
+		message, _, _ := unstructured.NestedString(ev.Object, "message")
+		eventType, _, _ := unstructured.NestedString(ev.Object, "type")
+		count, _, _ := unstructured.NestedInt64(ev.Object, "count")
+
+		msg := reason + ": " + message
+		if count > 1 {
+			msg += fmt.Sprintf(" (x%d)", count)
+		}
+		entries = append(entries, TimelineEntry{
+			Time:      ts,
+			Source:    "Kubernetes",
+			Namespace: ev.GetNamespace(),
+			Object:    kind + "/" + name,
+			Message:   msg,
+			Warning:   eventType == "Warning",
+		})
+	}
+	return entries
+}
+
+// sortTimeline drops entries before since and orders the rest oldest first.
+func sortTimeline(entries []TimelineEntry, since time.Time) []TimelineEntry {
+	filtered := entries[:0]
+	for _, e := range entries {
+		if !e.Time.Before(since) {
+			filtered = append(filtered, e)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].Time.Before(filtered[j].Time) })
+	return filtered
+}
+
+func printTimeline(ref agent.ResourceRef, window string, entries []TimelineEntry) {
+	fmt.Printf("\n%s%sTIMELINE:%s %s%s%s %s(last %s)%s\n\n", colorBold, colorCyan, colorReset, colorBold, ref.String(), colorReset, colorDim, window, colorReset)
+
+	if len(entries) == 0 {
+		fmt.Println("  No events in this window. Try a longer --since.")
+		fmt.Println()
+		return
+	}
+
+	for _, e := range entries {
+		sourceColor := colorCyan
+		switch e.Source {
+		case "ConfigHub":
+			sourceColor = colorBlue
+		case "Kubernetes":
+			sourceColor = colorDim
+		case "Restart", "kubectl":
+			sourceColor = colorYellow
+		}
+		msgColor := ""
+		if e.Warning {
+			msgColor = colorYellow
+		}
+		object := ""
+		if e.Object != "" {
+			object = e.Object + " "
+		}
+		fmt.Printf("  %s  %s%-11s%s %s%s%s%s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05"),
+			sourceColor, e.Source, colorReset,
+			object, msgColor, e.Message, colorReset)
+	}
+
+	var warnings int
+	for _, e := range entries {
+		if e.Warning {
+			warnings++
+		}
+	}
+	fmt.Printf("\n%d events, %d warnings\n\n", len(entries), warnings)
+}
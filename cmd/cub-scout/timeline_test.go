@@ -0,0 +1,110 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+func TestTimelineInterleavesSources(t *testing.T) {
+	base := time.Date(2026, 1, 30, 9, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+
+	deployer := &DeployerRevision{Kind: "Kustomization", Name: "apps", History: []agent.HistoryEntry{
+		{Timestamp: at(5), Revision: "main@sha1:abc1234", Status: "ReconciliationSucceeded"},
+	}}
+	revisions := &BlameConfigHub{Unit: "api", Revisions: []ConfigHubRevision{
+		{Num: 7, Description: "bump image", Author: "bob", CreatedAt: at(1)},
+	}}
+	kubectlTime := at(20)
+	managers := []agent.FieldManager{
+		{Manager: "kubectl-edit", Tool: "kubectl", Operation: "Update", Time: &kubectlTime, Fields: []string{"spec.replicas"}},
+	}
+	pod := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "api-abc"},
+		"status": map[string]interface{}{
+			"containerStatuses": []interface{}{
+				map[string]interface{}{
+					"name":         "api",
+					"restartCount": int64(3),
+					"lastState": map[string]interface{}{
+						"terminated": map[string]interface{}{"reason": "OOMKilled", "exitCode": int64(137), "finishedAt": at(10).Format(time.RFC3339)},
+					},
+				},
+			},
+		},
+	}}
+	event := func(kind, name, reason string, min int) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"reason":         reason,
+			"type":           "Warning",
+			"message":        "msg",
+			"lastTimestamp":  at(min).Format(time.RFC3339),
+			"involvedObject": map[string]interface{}{"kind": kind, "name": name},
+		}}
+	}
+	events := []unstructured.Unstructured{
+		event("Pod", "api-abc", "BackOff", 11),
+		event("Pod", "other-xyz", "BackOff", 12), // unrelated pod
+	}
+	related := map[string]bool{"Deployment/api": true, "Pod/api-abc": true}
+
+	var entries []TimelineEntry
+	entries = append(entries, timelineFromDeployer(deployer, "Flux")...)
+	entries = append(entries, timelineFromRevisions(revisions)...)
+	entries = append(entries, timelineFromManagers(managers)...)
+	entries = append(entries, timelineFromPods([]unstructured.Unstructured{pod})...)
+	entries = append(entries, timelineFromEvents(events, related)...)
+	entries = sortTimeline(entries, base)
+
+	wantSources := []string{"ConfigHub", "Flux", "Restart", "Kubernetes", "kubectl"}
+	if len(entries) != len(wantSources) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(wantSources), len(entries), entries)
+	}
+	for i, want := range wantSources {
+		if entries[i].Source != want {
+			t.Errorf("entries[%d].Source = %s, want %s", i, entries[i].Source, want)
+		}
+	}
+	if !entries[2].Warning || !entries[4].Warning || entries[1].Warning {
+		t.Errorf("unexpected warning flags: %+v", entries)
+	}
+}
+
+func TestSortTimelineWindow(t *testing.T) {
+	now := time.Now()
+	entries := []TimelineEntry{
+		{Time: now.Add(-48 * time.Hour), Message: "old"},
+		{Time: now.Add(-time.Hour), Message: "recent"},
+	}
+	got := sortTimeline(entries, now.Add(-24*time.Hour))
+	if len(got) != 1 || got[0].Message != "recent" {
+		t.Errorf("unexpected entries: %+v", got)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30m": 30 * time.Minute,
+		"24h": 24 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+	}
+	for in, want := range tests {
+		got, err := parseDuration(in)
+		if err != nil || got != want {
+			t.Errorf("parseDuration(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"xd", "7dx", "0d", "-1h", ""} {
+		if _, err := parseDuration(bad); err == nil {
+			t.Errorf("parseDuration(%q) = nil error, want error", bad)
+		}
+	}
+}
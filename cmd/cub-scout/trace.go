@@ -5,7 +5,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -14,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -30,6 +30,8 @@ var (
 	traceExplain   bool   // Show explanatory content for learning
 	traceHistory   bool   // Show deployment history
 	traceLimit     int    // Limit number of history entries
+	traceCommits   bool   // Resolve Git revisions to commit links
+	traceGitDir    string // Local clone used for commit lookup
 )
 
 // ANSI color codes for colorful output
@@ -55,9 +57,14 @@ You don't need to know which tool manages a resource. Just run trace and
 cub-scout auto-detects the owner and shows the full delivery chain.
 
 Under the hood:
-  - Flux resources: uses 'flux trace'
+  - Flux resources: uses 'flux trace', or follows sourceRef and dependsOn
+    (across namespaces) in the cluster when the flux CLI isn't installed
   - ArgoCD resources: uses 'argocd app get'
-  - Helm resources: reads release metadata
+  - Helm resources: reads the release Secret (chart, version, revision)
+    and, when a Flux HelmRelease installs the release, continues to the
+    HelmRelease, its chart source and any Argo CD Application syncing it
+  - Crossplane resources: walks managed resource → composite → claim and
+    shows the Composition revision the composite was rendered with
 
 The value: In mixed environments with multiple GitOps tools, one command
 traces any resource without switching between flux/argocd/helm CLIs.
@@ -84,9 +91,16 @@ Examples:
   # Show deployment history (who deployed what, when)
   cub-scout trace deployment/nginx -n demo --history
 
+  # Resolve revisions to commits through the GitHub/GitLab API
+  cub-scout trace deployment/nginx -n demo --commits
+
+  # Read commit author/subject from a local clone instead of the provider API
+  cub-scout trace deployment/nginx -n demo --commits --git-dir ~/src/platform-config
+
 The output shows:
   - The full chain from GitRepository → Kustomization/HelmRelease → Resource
   - Status and revision at each level
+  - The Git commit behind each revision (URL, author, subject) with --commits
   - Where in the chain something is broken (if applicable)
 
 Reverse trace (--reverse) walks ownerReferences to find:
@@ -98,14 +112,16 @@ Diff mode (--diff) shows what would change if GitOps reconciled:
   - For ArgoCD: runs 'argocd app diff'
   - Useful for debugging "why isn't my change applying?" and upgrade tracing
 `,
-	Args: cobra.RangeArgs(0, 2),
-	RunE: runTrace,
+	Args:              cobra.RangeArgs(0, 2),
+	RunE:              runTrace,
+	ValidArgsFunction: completeResourceArg,
 }
 
 func init() {
 	rootCmd.AddCommand(traceCmd)
 
 	traceCmd.Flags().StringVarP(&traceNamespace, "namespace", "n", "", "Namespace of the resource (default: flux-system)")
+	_ = traceCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
 	traceCmd.Flags().BoolVar(&traceJSON, "json", false, "Output as JSON")
 	traceCmd.Flags().StringVar(&traceApp, "app", "", "Trace Argo CD application by name")
 	traceCmd.Flags().BoolVarP(&traceReverse, "reverse", "r", false, "Reverse trace - walk ownerReferences up to find GitOps source")
@@ -113,6 +129,8 @@ func init() {
 	traceCmd.Flags().BoolVar(&traceExplain, "explain", false, "Show explanatory content to help learn GitOps concepts")
 	traceCmd.Flags().BoolVar(&traceHistory, "history", false, "Show deployment history (who deployed what, when)")
 	traceCmd.Flags().IntVar(&traceLimit, "limit", 10, "Limit number of history entries (default: 10)")
+	traceCmd.Flags().BoolVar(&traceCommits, "commits", false, "Resolve Git revisions to commit URL, author and subject; calls the GitHub/GitLab API (uses GITHUB_TOKEN/GITLAB_TOKEN)")
+	traceCmd.Flags().StringVar(&traceGitDir, "git-dir", "", "Local clone of the source repo to read commit details from")
 }
 
 func runTrace(cmd *cobra.Command, args []string) error {
@@ -174,6 +192,10 @@ func runTrace(cmd *cobra.Command, args []string) error {
 		if appErr != nil {
 			return fmt.Errorf("trace failed: %w", appErr)
 		}
+		if traceCommits {
+			appResult.Chain = agent.NewCommitEnricher(traceGitDir).EnrichChainWithCommits(ctx, appResult.Chain)
+		}
+		appResult.Delegation = agent.DelegationFromChain(appResult.Chain)
 		if traceJSON {
 			return outputTraceJSON(appResult)
 		}
@@ -181,19 +203,35 @@ func runTrace(cmd *cobra.Command, args []string) error {
 	}
 
 	// Detect ownership to choose the right tracer
-	ownership, err := detectResourceOwnership(ctx, kind, name, traceNamespace)
+	ownership, chain, err := detectResourceOwnership(ctx, kind, name, traceNamespace)
 	if err != nil {
 		// Ownership detection failed, try Flux tracer as default
 		ownership = &agent.Ownership{Type: agent.OwnerFlux}
 	}
 
+	// A Pod or Job owned through its controllers is traced from the
+	// controller carrying the GitOps labels
+	tracedKind, tracedName := kind, name
+	if chain != nil && chain.Managed != nil {
+		tracedKind, tracedName = chain.Managed.Kind, chain.Managed.Name
+	}
+
 	switch ownership.Type {
 	case agent.OwnerFlux:
 		tracer := agent.NewFluxTracer()
-		if !tracer.Available() {
+		flux, deployer := fluxTraceDeployer(ctx, ownership, tracedKind, tracedName, traceNamespace)
+		switch {
+		case tracer.Available():
+			result, err = tracer.Trace(ctx, tracedKind, tracedName, traceNamespace)
+			if err == nil && deployer != nil {
+				result.DependsOn, _ = flux.Dependencies(ctx, deployer)
+			}
+		case deployer != nil:
+			// No flux CLI: follow sourceRef and dependsOn in the cluster
+			result, err = flux.Trace(ctx, deployer, agent.ResourceRef{Kind: kind, Name: name, Namespace: traceNamespace})
+		default:
 			return fmt.Errorf("flux CLI not found - install from https://fluxcd.io/docs/installation/")
 		}
-		result, err = tracer.Trace(ctx, kind, name, traceNamespace)
 
 	case agent.OwnerArgo:
 		tracer := agent.NewArgoTracer()
@@ -224,11 +262,21 @@ func runTrace(cmd *cobra.Command, args []string) error {
 		}
 		tracer := agent.NewHelmTracer(clientset)
 		if ownership.Name != "" {
-			result, err = tracer.TraceRelease(ctx, ownership.Name, traceNamespace)
+			releaseNamespace := ownership.Namespace
+			if releaseNamespace == "" {
+				releaseNamespace = traceNamespace
+			}
+			result, err = tracer.TraceResource(ctx, ownership.Name, releaseNamespace, agent.ResourceRef{Kind: tracedKind, Name: tracedName, Namespace: traceNamespace})
 		} else {
-			result, err = tracer.Trace(ctx, kind, name, traceNamespace)
+			result, err = tracer.Trace(ctx, tracedKind, tracedName, traceNamespace)
+		}
+		if err == nil && result.Helm != nil {
+			traceHelmGitOps(ctx, result)
 		}
 
+	case agent.OwnerCrossplane:
+		result, err = traceCrossplane(ctx, kind, name, traceNamespace)
+
 	default:
 		// Try Flux first, then Argo, then report not managed
 		fluxTracer := agent.NewFluxTracer()
@@ -256,12 +304,25 @@ func runTrace(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("trace failed: %w", err)
 	}
+	if chain != nil && len(chain.Links) > 1 {
+		result.OwnerChain = chain
+	}
 
 	// Enrich chain links with timing information
 	if len(result.Chain) > 0 {
 		enrichTraceWithTiming(ctx, result)
 	}
 
+	// Link revisions to commits (closes the loop from pod to PR)
+	if traceCommits && len(result.Chain) > 0 {
+		result.Chain = agent.NewCommitEnricher(traceGitDir).EnrichChainWithCommits(ctx, result.Chain)
+	}
+
+	// ConfigHub OCI artifact applied by Flux/Argo: link back to the unit
+	if result.Delegation = agent.DelegationFromChain(result.Chain); result.Delegation != nil {
+		enrichTraceWithUnit(ctx, result, kind, name, traceNamespace)
+	}
+
 	// Detect cross-owner references if we have a workload
 	if kind == "Deployment" || kind == "StatefulSet" || kind == "DaemonSet" || kind == "Pod" {
 		crossRefs, crossErr := detectCrossOwnerReferences(ctx, kind, name, traceNamespace, ownership)
@@ -277,6 +338,84 @@ func runTrace(cmd *cobra.Command, args []string) error {
 	return outputTraceHuman(result)
 }
 
+// fluxTraceDeployer fetches the Kustomization or HelmRelease a resource is
+// applied by, or the resource itself when it is one, with the resolver
+// that fetched it. The deployer is nil when it can't be read.
+func fluxTraceDeployer(ctx context.Context, ownership *agent.Ownership, kind, name, namespace string) (*agent.FluxResolver, *unstructured.Unstructured) {
+	cfg, err := buildConfig()
+	if err != nil {
+		return nil, nil
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, nil
+	}
+	flux := agent.NewFluxResolver(agent.ClientFluxLookup(dynClient))
+
+	ref := agent.FluxObjectRef{Kind: kind, Name: name, Namespace: namespace}
+	if kind != "Kustomization" && kind != "HelmRelease" {
+		if ownership == nil || ownership.Name == "" {
+			return flux, nil
+		}
+		ref = agent.FluxObjectRef{Kind: "Kustomization", Name: ownership.Name, Namespace: ownership.Namespace}
+		if ownership.SubType == "helmrelease" {
+			ref.Kind = "HelmRelease"
+		}
+		if ref.Namespace == "" {
+			ref.Namespace = "flux-system"
+		}
+	}
+	deployer, _ := flux.Get(ctx, ref)
+	return flux, deployer
+}
+
+// traceHelmGitOps continues a Helm trace past the release when GitOps
+// installs it: a Flux HelmRelease and its chart source go ahead of the
+// release, and when an Argo CD Application syncs that HelmRelease, the
+// Application and its source go ahead of those. Lookup failures leave the
+// Helm trace as it is.
+func traceHelmGitOps(ctx context.Context, result *agent.TraceResult) {
+	cfg, err := buildConfig()
+	if err != nil {
+		return
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return
+	}
+	storageNamespace, _, _ := strings.Cut(result.Helm.Secret, "/")
+	hr, err := agent.FindFluxHelmRelease(ctx, dynClient, result.Helm.Release, storageNamespace)
+	if err != nil || hr == nil {
+		return
+	}
+	flux := agent.NewFluxResolver(agent.ClientFluxLookup(dynClient))
+	if err := flux.TraceHelmRelease(ctx, hr, result); err != nil {
+		return
+	}
+
+	owner := agent.DetectOwnership(hr)
+	if owner.Type != agent.OwnerArgo || owner.Name == "" {
+		return
+	}
+	argo := agent.NewArgoTracer()
+	if !argo.Available() {
+		return
+	}
+	app, err := argo.TraceApplication(ctx, owner.Name)
+	if err != nil || app.Error != "" {
+		return
+	}
+	// The Application's source and the Application; its managed resources
+	// follow in the Argo chain and are not part of this one
+	for i, l := range app.Chain {
+		if l.Kind == "Application" {
+			result.Chain = append(app.Chain[:i+1:i+1], result.Chain...)
+			result.FullyManaged = result.FullyManaged && l.Ready
+			break
+		}
+	}
+}
+
 // normalizeKind normalizes resource kind names
 func normalizeKind(kind string) string {
 	kind = strings.ToLower(kind)
@@ -293,6 +432,14 @@ func normalizeKind(kind string) string {
 		return "StatefulSet"
 	case "ds", "daemonset", "daemonsets":
 		return "DaemonSet"
+	case "po", "pod", "pods":
+		return "Pod"
+	case "rs", "replicaset", "replicasets":
+		return "ReplicaSet"
+	case "job", "jobs":
+		return "Job"
+	case "cj", "cronjob", "cronjobs":
+		return "CronJob"
 	case "ing", "ingress", "ingresses":
 		return "Ingress"
 	case "ks", "kustomization", "kustomizations":
@@ -356,38 +503,70 @@ func detectCrossOwnerReferences(ctx context.Context, kind, name, namespace strin
 	return detector.DetectCrossReferences(ctx, resource, resourceOwner)
 }
 
-// detectResourceOwnership fetches the resource and detects its owner
-func detectResourceOwnership(ctx context.Context, kind, name, namespace string) (*agent.Ownership, error) {
+// enrichTraceWithUnit reads ConfigHub unit metadata carried through the OCI
+// artifact onto the traced resource (delegated apply).
+func enrichTraceWithUnit(ctx context.Context, result *agent.TraceResult, kind, name, namespace string) {
+	gvr := kindToGVR(kind)
+	if gvr.Resource == "" {
+		return
+	}
+	cfg, err := buildConfig()
+	if err != nil {
+		return
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return
+	}
+	resource, err := dynClient.Resource(gvr).Namespace(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return
+	}
+	result.EnrichWithConfigHub(resource.GetLabels(), resource.GetAnnotations())
+}
+
+// detectResourceOwnership fetches the resource and detects its owner,
+// walking ownerReferences to the top-level controller
+func detectResourceOwnership(ctx context.Context, kind, name, namespace string) (*agent.Ownership, *agent.OwnerChain, error) {
 	cfg, err := buildConfig()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	dynClient, err := dynamic.NewForConfig(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Map kind to GVR
 	gvr := kindToGVR(kind)
 	if gvr.Resource == "" {
-		return nil, fmt.Errorf("unknown resource kind: %s", kind)
+		return nil, nil, fmt.Errorf("unknown resource kind: %s", kind)
 	}
 
 	// Fetch the resource
 	resource, err := dynClient.Resource(gvr).Namespace(namespace).Get(ctx, name, v1.GetOptions{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Detect ownership
-	ownership := agent.DetectOwnership(resource)
-	return &ownership, nil
+	resolver := agent.NewOwnerResolver(agent.ClientOwnerLookup(dynClient))
+	ownership, chain := resolver.DetectOwnership(ctx, resource)
+	return &ownership, &chain, nil
 }
 
 // kindToGVR maps a kind to its GroupVersionResource
 func kindToGVR(kind string) schema.GroupVersionResource {
 	switch kind {
+	case "Pod":
+		return schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+	case "ReplicaSet":
+		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+	case "Job":
+		return schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	case "CronJob":
+		return schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}
 	case "Deployment":
 		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
 	case "StatefulSet":
@@ -423,9 +602,7 @@ func kindToGVR(kind string) schema.GroupVersionResource {
 
 // outputTraceJSON outputs the trace result as JSON
 func outputTraceJSON(result *agent.TraceResult) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(result)
+	return writeJSON(os.Stdout, "TraceResult", result)
 }
 
 // outputTraceHuman outputs the trace result in human-readable format with colors
@@ -527,6 +704,21 @@ func outputTraceHuman(result *agent.TraceResult) error {
 		if link.Revision != "" {
 			fmt.Printf("%s%sRevision:%s %s%s%s\n", detailPrefix, colorDim, colorReset, colorPurple, link.Revision, colorReset)
 		}
+		if c := link.Commit; c != nil {
+			if c.Subject != "" {
+				commitLine := c.Subject
+				if c.Author != "" {
+					commitLine += " — " + c.Author
+				}
+				if c.Date != nil {
+					commitLine += ", " + formatElapsed(time.Since(*c.Date)) + " ago"
+				}
+				fmt.Printf("%s%sCommit:%s %s\n", detailPrefix, colorDim, colorReset, commitLine)
+			}
+			if c.URL != "" {
+				fmt.Printf("%s%sCommit URL:%s %s%s%s\n", detailPrefix, colorDim, colorReset, colorBlue, c.URL, colorReset)
+			}
+		}
 		if link.Status != "" {
 			statusColor := colorGreen
 			if !link.Ready {
@@ -555,6 +747,71 @@ func outputTraceHuman(result *agent.TraceResult) error {
 		}
 	}
 
+	// Controller chain the owner was resolved through
+	if c := result.OwnerChain; c != nil {
+		fmt.Printf("\n")
+		fmt.Printf("%sOwner chain:%s %s\n", colorDim, colorReset, c.String())
+	}
+
+	// Flux dependsOn chain, which must be ready before the deployer applies
+	if len(result.DependsOn) > 0 {
+		fmt.Printf("\n")
+		fmt.Printf("%sDepends on:%s\n", colorDim, colorReset)
+		for _, d := range result.DependsOn {
+			icon, note := colorGreen+"✓", ""
+			switch {
+			case d.Cycle:
+				icon, note = colorRed+"✗", " (cycle)"
+			case d.Missing:
+				icon, note = colorRed+"✗", " (not found)"
+			case d.Suspended:
+				icon, note = colorYellow+"⏸", " (suspended)"
+			case !d.Ready:
+				icon, note = colorRed+"✗", ": "+d.Message
+			}
+			if d.CrossNamespace {
+				note = " [cross-namespace]" + note
+			}
+			fmt.Printf("  %s%s%s %s/%s%s\n", strings.Repeat("  ", d.Depth-1), icon, colorReset, d.Namespace, d.Name, note)
+		}
+	}
+
+	// Helm: the release revision and where Helm stores it
+	if h := result.Helm; h != nil {
+		fmt.Printf("\n")
+		fmt.Printf("%sHelm release:%s %s/%s revision %d (%s), chart %s-%s", colorDim, colorReset, h.Namespace, h.Release, h.Revision, h.Status, h.Chart, h.ChartVersion)
+		if h.AppVersion != "" {
+			fmt.Printf(" (app %s)", h.AppVersion)
+		}
+		fmt.Printf("\n")
+		fmt.Printf("  %sStored in Secret %s%s\n", colorDim, h.Secret, colorReset)
+		if h.ManagedBy != nil {
+			fmt.Printf("  %sInstalled by %s/%s in %s; upgrade it through the HelmRelease, not helm upgrade.%s\n", colorDim, h.ManagedBy.Kind, h.ManagedBy.Name, h.ManagedBy.Namespace, colorReset)
+		}
+	}
+
+	// Crossplane: the Composition revision the XR was rendered with
+	if result.Crossplane != nil {
+		fmt.Print(renderCrossplaneComposition(result.Crossplane.Composition))
+	}
+
+	// Delegated apply: ConfigHub owns, Flux/Argo applies
+	if d := result.Delegation; d != nil {
+		unit := "(unit not recorded on resource)"
+		if result.ConfigHub != nil && result.ConfigHub.UnitSlug != "" {
+			unit = result.ConfigHub.UnitSlug
+		}
+		fmt.Printf("\n")
+		fmt.Printf("%s%s%s%s\n", colorBold, colorBlue, d.OwnerLabel(), colorReset)
+		fmt.Printf("  %sUnit%s %s%s%s → %sOCI%s %s → %s%s/%s%s → %s\n",
+			colorDim, colorReset, colorCyan, unit, colorReset,
+			colorDim, colorReset, d.URL,
+			colorCyan, d.DeployerKind, d.DeployerName, colorReset,
+			result.Object.String())
+		fmt.Printf("  %sEdit in ConfigHub (space %s, target %s); direct cluster edits are reverted on the next apply.%s\n",
+			colorDim, d.Space, d.Target, colorReset)
+	}
+
 	// Show cross-owner references if detected
 	if len(result.CrossReferences) > 0 {
 		fmt.Printf("\n")
@@ -695,9 +952,7 @@ func runReverseTrace(ctx context.Context, kind, name, namespace string) error {
 
 // outputReverseTraceJSON outputs the reverse trace result as JSON
 func outputReverseTraceJSON(result *agent.ReverseTraceResult) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(result)
+	return writeJSON(os.Stdout, "ReverseTraceResult", result)
 }
 
 // outputReverseTraceHuman outputs the reverse trace result in human-readable format
@@ -766,10 +1021,22 @@ func outputReverseTraceHuman(result *agent.ReverseTraceResult) error {
 		fmt.Printf("\n")
 	}
 
+	// Note where the walk stopped short of a top-level controller
+	if c := result.OwnerChain; c != nil {
+		if c.Unresolved != nil {
+			fmt.Printf("  %s⚠ owner %s/%s could not be fetched; the chain stops here%s\n", colorYellow, c.Unresolved.Kind, c.Unresolved.Name, colorReset)
+		}
+		if c.Cycle {
+			fmt.Printf("  %s⚠ ownerReferences form a cycle; the chain stops before repeating%s\n", colorYellow, colorReset)
+		}
+	}
+
 	// If this looks Crossplane-managed, show XR-first lineage (Managed → XR → optional Claim).
 	// This does not alter ownership detection; it only surfaces what the resolver can infer
 	// from already-fetched objects.
-	if len(result.Objects) > 0 {
+	if result.Crossplane != nil {
+		fmt.Print(renderCrossplaneLineageHuman(result.Crossplane))
+	} else if len(result.Objects) > 0 {
 		if lineage, ok := agent.ResolveCrossplaneLineage(result.Objects[0], result.Objects); ok {
 			fmt.Print(renderCrossplaneLineageHuman(lineage))
 		}
@@ -789,6 +1056,8 @@ func outputReverseTraceHuman(result *agent.ReverseTraceResult) error {
 		ownerColor = colorYellow
 	case "confighub":
 		ownerColor = colorBlue
+	case "crossplane":
+		ownerColor = colorBlue
 	case "native":
 		ownerColor = colorRed
 	}
@@ -909,7 +1178,7 @@ func runTraceDiff(ctx context.Context, kind, name, namespace string) error {
 	}
 
 	// For other resources, detect ownership to choose the right diff tool
-	ownership, err := detectResourceOwnership(ctx, kind, name, namespace)
+	ownership, _, err := detectResourceOwnership(ctx, kind, name, namespace)
 	if err != nil {
 		// Try to infer from kind
 		ownership = &agent.Ownership{Type: agent.OwnerUnknown}
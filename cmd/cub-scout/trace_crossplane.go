@@ -4,14 +4,59 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
 	"github.com/confighub/cub-scout/pkg/agent"
 )
 
+// traceCrossplane traces a Crossplane-composed resource from its claim,
+// through the composite and managed resource, down to the resource.
+func traceCrossplane(ctx context.Context, kind, name, namespace string) (*agent.TraceResult, error) {
+	cfg, err := buildConfig()
+	if err != nil {
+		return nil, fmt.Errorf("build kubernetes config: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create dynamic client: %w", err)
+	}
+	obj, err := dynClient.Resource(kindToGVR(kind)).Namespace(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return agent.NewCrossplaneTracer(dynClient).Trace(ctx, obj)
+}
+
+// renderCrossplaneComposition renders the Composition an XR uses, warning
+// when a Manual update policy keeps it behind the latest revision.
+func renderCrossplaneComposition(c *agent.CrossplaneComposition) string {
+	if c == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n%s%sComposition:%s %s", colorBold, colorWhite, colorReset, c.String()))
+	if c.UpdatePolicy != "" {
+		b.WriteString(fmt.Sprintf(" %s(update policy %s)%s", colorDim, c.UpdatePolicy, colorReset))
+	}
+	b.WriteString("\n")
+	if c.Behind() {
+		b.WriteString(fmt.Sprintf("  %s⚠ revision %d is available; this XR is still rendered from revision %d%s\n",
+			colorYellow, c.LatestRevision, c.RevisionNumber, colorReset))
+		if c.UpdatePolicy == "Manual" {
+			b.WriteString(fmt.Sprintf("  %s  Move it with spec.compositionRevisionRef, or set compositionUpdatePolicy: Automatic%s\n", colorDim, colorReset))
+		}
+	}
+	return b.String()
+}
+
 // renderCrossplaneLineageHuman renders a compact XR-first Crossplane lineage section.
-// It is intended for the reverse trace UX where we already have a small local object set.
+// It is intended for the reverse trace UX, from the lineage resolved in the
+// cluster or, failing that, from the objects the reverse trace fetched.
 func renderCrossplaneLineageHuman(lineage *agent.CrossplaneLineage) string {
 	if lineage == nil {
 		return ""
@@ -33,6 +78,15 @@ func renderCrossplaneLineageHuman(lineage *agent.CrossplaneLineage) string {
 		b.WriteString(fmt.Sprintf("  %sxr:%s       %s\n", colorDim, colorReset, label))
 	}
 
+	// Parent composites, when XRs are nested
+	for _, p := range lineage.Parents {
+		label := p.Ref.String()
+		if !p.Present {
+			label += fmt.Sprintf(" %s(partial lineage)%s", colorDim, colorReset)
+		}
+		b.WriteString(fmt.Sprintf("  %sparent xr:%s %s\n", colorDim, colorReset, label))
+	}
+
 	// Optional claim (enrichment)
 	if lineage.Claim != nil && lineage.Claim.Ref.Name != "" {
 		label := lineage.Claim.Ref.String()
@@ -42,6 +96,14 @@ func renderCrossplaneLineageHuman(lineage *agent.CrossplaneLineage) string {
 		b.WriteString(fmt.Sprintf("  %sclaim:%s    %s\n", colorDim, colorReset, label))
 	}
 
+	if c := lineage.Composition; c != nil {
+		label := c.String()
+		if c.Behind() {
+			label += fmt.Sprintf(" %s(revision %d available, update policy %s)%s", colorYellow, c.LatestRevision, c.UpdatePolicy, colorReset)
+		}
+		b.WriteString(fmt.Sprintf("  %scomposition:%s %s\n", colorDim, colorReset, label))
+	}
+
 	if len(lineage.Evidence) > 0 {
 		b.WriteString(fmt.Sprintf("  %sevidence:%s %s\n", colorDim, colorReset, strings.Join(lineage.Evidence, ", ")))
 	}
@@ -172,3 +172,25 @@ func TestRenderCrossplaneLineageHuman(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderCrossplaneComposition(t *testing.T) {
+	if got := renderCrossplaneComposition(nil); got != "" {
+		t.Errorf("nil composition = %q, want empty", got)
+	}
+
+	current := renderCrossplaneComposition(&agent.CrossplaneComposition{
+		Name: "xpostgres", Revision: "xpostgres-c0ffe", RevisionNumber: 3, LatestRevision: 3, UpdatePolicy: "Automatic",
+	})
+	if !strings.Contains(current, "xpostgres rev 3 (xpostgres-c0ffe)") || strings.Contains(current, "⚠") {
+		t.Errorf("current revision:\n%s", current)
+	}
+
+	behind := renderCrossplaneComposition(&agent.CrossplaneComposition{
+		Name: "xpostgres", Revision: "xpostgres-8f3c2", RevisionNumber: 2, LatestRevision: 3, UpdatePolicy: "Manual",
+	})
+	for _, want := range []string{"revision 3 is available", "still rendered from revision 2", "compositionUpdatePolicy: Automatic"} {
+		if !strings.Contains(behind, want) {
+			t.Errorf("behind: missing %q in:\n%s", want, behind)
+		}
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"sort"
 	"strings"
 
@@ -16,6 +15,8 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 var (
@@ -24,6 +25,9 @@ var (
 	treeAll       bool
 	treeSpace     string // For ConfigHub tree
 	treeEdge      string // For ConfigHub tree (clone/link)
+
+	treeContexts      []string // For 'suggest': kube contexts to scan
+	treeGenerateUnits bool     // For 'suggest': print a cub script creating the units
 )
 
 var treeCmd = &cobra.Command{
@@ -65,6 +69,10 @@ Examples:
   cub-scout tree config --space my-space
   cub-scout tree config --space "*" --edge link
 
+  # Suggest units, detecting the same app across clusters as variants,
+  # and print a script that creates base + variant (clone) units
+  cub-scout tree suggest --contexts dev,staging,prod --generate-units
+
 The 'tree' command complements 'cub unit tree' in the ConfigHub CLI:
   - cub-scout tree: What's deployed in THIS cluster
   - cub unit tree:  How Units relate ACROSS your fleet
@@ -79,9 +87,14 @@ func init() {
 
 	treeCmd.Flags().BoolVar(&treeJSON, "json", false, "Output as JSON")
 	treeCmd.Flags().StringVarP(&treeNamespace, "namespace", "n", "", "Filter by namespace")
+	_ = treeCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
 	treeCmd.Flags().BoolVarP(&treeAll, "all", "A", false, "Show all resources including system namespaces")
 	treeCmd.Flags().StringVar(&treeSpace, "space", "", "ConfigHub space for 'config' view (use '*' for all spaces)")
+	_ = treeCmd.RegisterFlagCompletionFunc("space", completeSpaces)
 	treeCmd.Flags().StringVar(&treeEdge, "edge", "clone", "Edge type for 'config' view: clone (inheritance) or link (dependencies)")
+	treeCmd.Flags().StringSliceVar(&treeContexts, "contexts", nil, "Kube contexts to scan for 'suggest' (same app across clusters becomes variants)")
+	_ = treeCmd.RegisterFlagCompletionFunc("contexts", completeKubeContexts)
+	treeCmd.Flags().BoolVar(&treeGenerateUnits, "generate-units", false, "For 'suggest': print a cub script that creates base and variant units")
 }
 
 func runTree(cmd *cobra.Command, args []string) error {
@@ -147,6 +160,7 @@ func runTreeRuntime(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create dynamic client: %w", err)
 	}
+	loadDelegations(ctx, dynClient)
 
 	// Get Deployments
 	deployGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
@@ -332,6 +346,7 @@ func runTreeOwnership(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create dynamic client: %w", err)
 	}
+	loadDelegations(ctx, dynClient)
 
 	// Get Deployments
 	deployGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
@@ -566,9 +581,8 @@ func runTreePatterns() error {
 
 func runTreeConfig() error {
 	// Check if cub CLI is available
-	_, err := exec.LookPath("cub")
-	if err != nil {
-		return fmt.Errorf("'cub' CLI not found. Install with: brew install confighub/tap/cub")
+	if !cubInstalled() {
+		return cubMissingError("tree config")
 	}
 
 	// Build command args
@@ -597,11 +611,12 @@ func runTreeConfig() error {
 	fmt.Println()
 
 	// Execute cub unit tree
-	cmd := exec.Command("cub", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	return cmd.Run()
+	out, err := runCubCommand(args...)
+	if err != nil {
+		return fmt.Errorf("cub unit tree: %s", cubStderr(err))
+	}
+	_, err = os.Stdout.Write(out)
+	return err
 }
 
 func getOwnerColor(owner string) string {
@@ -635,29 +650,97 @@ func getStatusIcon(status string) string {
 }
 
 func runTreeSuggest(ctx context.Context) error {
-
-	cfg, err := buildConfig()
+	workloads, err := scanSuggestWorkloads(ctx, treeContexts, treeNamespace, treeAll)
 	if err != nil {
-		return fmt.Errorf("failed to build config: %w", err)
+		return err
+	}
+
+	if treeGenerateUnits {
+		suggestion := SuggestHubAppSpaceStructure(workloads, treeSpace)
+		suggestion.GenerateUnits(os.Stdout)
+		return nil
+	}
+
+	if treeJSON {
+		suggestion := SuggestHubAppSpaceStructure(workloads, treeSpace)
+		return json.NewEncoder(os.Stdout).Encode(suggestion)
+	}
+
+	// Print suggestion
+	fmt.Printf("%sHub/AppSpace Suggestion%s\n", colorBold, colorReset)
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Println()
+	fmt.Println("Based on cluster workloads, here's a suggested ConfigHub structure:")
+	fmt.Println()
+
+	suggestion := SuggestHubAppSpaceStructure(workloads, treeSpace)
+	suggestion.Print()
+
+	fmt.Println()
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Printf("\n%sNext steps:%s\n", colorBold, colorReset)
+	fmt.Println("  1. Review the suggested structure above")
+	fmt.Println("  2. Import workloads: cub-scout import -n <namespace>")
+	fmt.Println("     or generate base + variant units: cub-scout tree suggest --generate-units > units.sh")
+	fmt.Println("  3. View in ConfigHub: cub unit tree --space <space>")
+	fmt.Println()
+	fmt.Println("For fleet-wide queries after import:")
+	fmt.Println("  cub unit list --space \"*\"                    # All units")
+	fmt.Println("  cub unit tree --space \"*\" --edge clone       # Inheritance")
+	fmt.Println("  cub unit tree --space \"*\" --edge link        # Dependencies")
+
+	return nil
+}
+
+// scanSuggestWorkloads lists the workloads to suggest units for, from each
+// kube context in contexts, or from the current cluster when contexts is empty.
+func scanSuggestWorkloads(ctx context.Context, contexts []string, namespace string, all bool) ([]WorkloadInfo, error) {
+	if len(contexts) == 0 {
+		cfg, err := buildConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build config: %w", err)
+		}
+		return listSuggestWorkloads(ctx, cfg, "", namespace, all)
 	}
 
+	var workloads []WorkloadInfo
+	for _, kubeContext := range contexts {
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+		cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("context %s: failed to build config: %w", kubeContext, err)
+		}
+		found, err := listSuggestWorkloads(ctx, cfg, kubeContext, namespace, all)
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", kubeContext, err)
+		}
+		workloads = append(workloads, found...)
+	}
+	return workloads, nil
+}
+
+// listSuggestWorkloads lists the Deployments 'suggest' groups into units.
+// cluster is recorded on each workload when scanning several contexts.
+func listSuggestWorkloads(ctx context.Context, cfg *rest.Config, cluster, namespace string, all bool) ([]WorkloadInfo, error) {
 	dynClient, err := dynamic.NewForConfig(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %w", err)
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
+	loadDelegations(ctx, dynClient)
 
 	// Get Deployments
 	deployGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
-	deploys, err := dynClient.Resource(deployGVR).Namespace(treeNamespace).List(ctx, v1.ListOptions{})
+	deploys, err := dynClient.Resource(deployGVR).Namespace(namespace).List(ctx, v1.ListOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to list deployments: %w", err)
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
 	}
 
 	// Build workload info list
 	var workloads []WorkloadInfo
 	for _, deploy := range deploys.Items {
 		ns := deploy.GetNamespace()
-		if !treeAll && isSystemNamespace(ns) {
+		if !all && isSystemNamespace(ns) {
 			continue
 		}
 
@@ -681,39 +764,12 @@ func runTreeSuggest(ctx context.Context) error {
 			Owner:             owner,
 			Labels:            labels,
 			Annotations:       annotations,
+			Cluster:           cluster,
 			KustomizationPath: kustomizationPath,
 			ApplicationPath:   applicationPath,
 		})
 	}
-
-	if treeJSON {
-		suggestion := SuggestHubAppSpaceStructure(workloads, treeSpace)
-		return json.NewEncoder(os.Stdout).Encode(suggestion)
-	}
-
-	// Print suggestion
-	fmt.Printf("%sHub/AppSpace Suggestion%s\n", colorBold, colorReset)
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Println()
-	fmt.Println("Based on cluster workloads, here's a suggested ConfigHub structure:")
-	fmt.Println()
-
-	suggestion := SuggestHubAppSpaceStructure(workloads, treeSpace)
-	suggestion.Print()
-
-	fmt.Println()
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Printf("\n%sNext steps:%s\n", colorBold, colorReset)
-	fmt.Println("  1. Review the suggested structure above")
-	fmt.Println("  2. Import workloads: cub-scout import -n <namespace>")
-	fmt.Println("  3. View in ConfigHub: cub unit tree --space <space>")
-	fmt.Println()
-	fmt.Println("For fleet-wide queries after import:")
-	fmt.Println("  cub unit list --space \"*\"                    # All units")
-	fmt.Println("  cub unit tree --space \"*\" --edge clone       # Inheritance")
-	fmt.Println("  cub unit tree --space \"*\" --edge link        # Dependencies")
-
-	return nil
+	return workloads, nil
 }
 
 // isSystemNamespace is defined in import.go
@@ -0,0 +1,162 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/golden"
+)
+
+// Golden-file tests for the hierarchy TUI. Each renders a view at a fixed
+// size from the fake cub runner's data and compares it with
+// testdata/<TestName>.golden. Update the files after an intended change:
+//
+//	go test ./cmd/cub-scout -run Golden -update
+
+// fakeCub answers cub commands with canned output, keyed by the arguments
+// joined with spaces. Commands it does not know fail as cub would.
+type fakeCub map[string]string
+
+// install makes runCubCommand use f until the test ends.
+func (f fakeCub) install(t *testing.T) {
+	t.Helper()
+	// Reads cached from another test's fake would hide this one's
+	cubCache.Purge()
+	prev := cubExec
+	cubExec = func(args ...string) ([]byte, error) {
+		if out, ok := f[strings.Join(args, " ")]; ok {
+			return []byte(out), nil
+		}
+		return nil, fmt.Errorf("fake cub: unexpected command: cub %s", strings.Join(args, " "))
+	}
+	t.Cleanup(func() {
+		cubExec = prev
+		cubCache.Purge()
+	})
+}
+
+// goldenCub is a small org: a platform space holding the worker and
+// target, and an app space whose units deploy through it.
+var goldenCub = fakeCub{
+	"context get --json": `{"name":"default","coordinate":{"organizationID":"org_acme"},"settings":{"defaultSpace":"payments-prod"}}`,
+	"organization list --json": `[
+		{"OrganizationID":"org-1","ExternalID":"org_acme","DisplayName":"Acme","Slug":"acme"},
+		{"OrganizationID":"org-2","ExternalID":"org_other","DisplayName":"Other","Slug":"other"}]`,
+	"space list --json": `[
+		{"Space":{"Slug":"payments-prod","SpaceID":"s-2","OrganizationID":"org-1"},"TotalUnitCount":2,"TotalBridgeWorkerCount":0},
+		{"Space":{"Slug":"platform-prod","SpaceID":"s-1","OrganizationID":"org-1"},"TotalUnitCount":0,"TotalBridgeWorkerCount":1,"TargetCountByToolchainType":{"Kubernetes/YAML":1}}]`,
+	"unit list --space payments-prod --json": `[
+		{"Unit":{"Slug":"payments-api","HeadRevisionNum":4,"LiveRevisionNum":4},"Target":{"Slug":"prod-cluster","SpaceID":"s-1"},"UnitStatus":{"Status":"Ready","SyncStatus":"Synced","Drift":"NotDrifted"}},
+		{"Unit":{"Slug":"payments-worker","HeadRevisionNum":3,"LiveRevisionNum":2},"Target":{"Slug":"prod-cluster","SpaceID":"s-1"},"UnitStatus":{"Status":"Progressing","SyncStatus":"OutOfSync"}}]`,
+	"target list --space payments-prod --json": `[]`,
+	"worker list --space payments-prod --json": `[]`,
+	"unit list --space platform-prod --json":   `[]`,
+	"target list --space platform-prod --json": `[{"Target":{"Slug":"prod-cluster","ProviderType":"Kubernetes","BridgeWorkerID":"w-1"}}]`,
+	"worker list --space platform-prod --json": `[{"BridgeWorker":{"BridgeWorkerID":"w-1","Slug":"prod-worker","Condition":"Ready"}}]`,
+}
+
+// goldenModel loads goldenCub into a hierarchy model the way the TUI does
+// on startup: the org, then each space's units, targets and workers.
+func goldenModel(t *testing.T) Model {
+	t.Helper()
+	goldenCub.install(t)
+
+	m := Model{
+		keymap:         defaultKeyMap(),
+		loading:        true,
+		detailsPane:    viewport.New(40, 20),
+		currentCluster: "prod-cluster",
+	}
+	m = applyMsg(m, tea.WindowSizeMsg{Width: 100, Height: 30})
+	msg := loadDataCmd()
+	if err, ok := msg.(errMsg); ok {
+		t.Fatalf("load: %v", err.err)
+	}
+	m = applyMsg(m, msg)
+	for _, space := range []string{"payments-prod", "platform-prod"} {
+		m = applyMsg(m, loadSpaceDataCmd(space)())
+	}
+	return m
+}
+
+// applyMsg applies msg, unwrapping the *Model overlay handlers return.
+func applyMsg(m Model, msg tea.Msg) Model {
+	next, _ := m.Update(msg)
+	if p, ok := next.(*Model); ok {
+		return *p
+	}
+	return next.(Model)
+}
+
+func TestHierarchyTreeGolden(t *testing.T) {
+	m := goldenModel(t)
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+func TestHierarchyHubAppSpaceGolden(t *testing.T) {
+	m := goldenModel(t)
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+func TestImportWizardSourceGolden(t *testing.T) {
+	m := goldenModel(t)
+	m.importMode = true
+	m.importStep = importStepSource
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+func TestImportWizardNamespaceGolden(t *testing.T) {
+	m := goldenModel(t)
+	m.importMode = true
+	m.importStep = importStepNamespace
+	m.importNamespaces = []namespaceInfo{
+		{Name: "payments", Deployments: 2, FluxCount: 2},
+		{Name: "checkout", Deployments: 1, StatefulSet: 1, HelmCount: 1, NativeCount: 1},
+		{Name: "batch", Deployments: 1, ConfigHubCount: 1},
+	}
+	m.importCursor = 1
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+func TestPanelViewGolden(t *testing.T) {
+	m := goldenModel(t)
+	m.panelMode = true
+	m = applyMsg(m, panelDataLoadedMsg{
+		workloads: []MapEntry{
+			{Kind: "Deployment", Name: "payments-api", Namespace: "payments", Status: "Ready", OwnerDetails: map[string]string{"revision": "4"}},
+			{Kind: "Deployment", Name: "payments-worker", Namespace: "payments", Status: "NotReady", OwnerDetails: map[string]string{"revision": "2"}},
+			{Kind: "Deployment", Name: "legacy-cron", Namespace: "payments", Status: "Ready"},
+		},
+		correlation: map[string][]MapEntry{
+			"payments-api":    {{Kind: "Deployment", Name: "payments-api", Status: "Ready", OwnerDetails: map[string]string{"revision": "4"}}},
+			"payments-worker": {{Kind: "Deployment", Name: "payments-worker", Status: "NotReady", OwnerDetails: map[string]string{"revision": "2"}}},
+		},
+		orphans: []MapEntry{{Kind: "Deployment", Name: "legacy-cron", Namespace: "payments"}},
+	})
+	golden.RequireEqual(t, []byte(m.View()))
+}
+
+func TestSuggestViewGolden(t *testing.T) {
+	m := goldenModel(t)
+	m.suggestMode = true
+	m = applyMsg(m, suggestDataLoadedMsg{proposal: &HubAppSpaceSuggestion{
+		AppSpace: "payments",
+		Units: []HubAppSpaceUnit{
+			{Slug: "payments-api-prod", App: "payments-api", Variant: "prod", Workloads: []WorkloadInfo{
+				{Kind: "Deployment", Name: "payments-api", Namespace: "payments-prod", Owner: "Flux"},
+			}},
+			{Slug: "payments-worker-prod", App: "payments-worker", Variant: "prod", Workloads: []WorkloadInfo{
+				{Kind: "Deployment", Name: "payments-worker", Namespace: "payments-prod", Owner: "Helm"},
+				{Kind: "CronJob", Name: "payments-reconcile", Namespace: "payments-prod", Owner: "Native"},
+			}},
+		},
+	}})
+	golden.RequireEqual(t, []byte(m.View()))
+}
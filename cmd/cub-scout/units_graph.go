@@ -0,0 +1,505 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	unitsGraphSpace string
+	unitsGraphJSON  bool
+)
+
+var unitsCmd = &cobra.Command{
+	Use:   "units",
+	Short: "Inspect ConfigHub units",
+	Long:  `Inspect ConfigHub units and the relationships between them.`,
+}
+
+var unitsGraphCmd = &cobra.Command{
+	Use:   "graph [space/unit]",
+	Short: "Show unit clone and link relationships, and what a change fans out to",
+	Long: `Show how ConfigHub units depend on each other:
+
+  clone  the unit was cloned from an upstream unit; upgrading it pulls in
+         the upstream's changes
+  link   the unit links to another unit whose values it needs, so a change
+         there changes what it is rendered with
+
+Given a unit, shows its upstream chain, the units it links to, and every
+unit a change to it fans out to: its clones and the units linking to it,
+then theirs. Clones still on an older upstream revision are marked
+"behind".
+
+With no unit, shows each base unit (one with clones or dependents that has
+no upstream or links of its own) and its fan-out. --space keeps the trees
+that reach into that space.
+
+Every space is loaded, since clones and links cross spaces.
+
+Examples:
+  cub-scout units graph                          # Every base unit
+  cub-scout units graph platform-base/nginx      # What a change to nginx reaches
+  cub-scout units graph nginx --space platform-base
+  cub-scout units graph --space shop-prod        # Trees reaching shop-prod
+  cub-scout units graph --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUnitsGraph,
+}
+
+func init() {
+	unitsGraphCmd.Flags().StringVar(&unitsGraphSpace, "space", "", "Space of the unit, or with no unit, the space the trees must reach")
+	_ = unitsGraphCmd.RegisterFlagCompletionFunc("space", completeSpaces)
+	unitsGraphCmd.Flags().BoolVar(&unitsGraphJSON, "json", false, "Output as JSON")
+	unitsCmd.AddCommand(unitsGraphCmd)
+	rootCmd.AddCommand(unitsCmd)
+}
+
+// UnitRef names a unit.
+type UnitRef struct {
+	Space string `json:"space"`
+	Unit  string `json:"unit"`
+}
+
+func (r UnitRef) String() string {
+	if r.Space == "" {
+		return r.Unit
+	}
+	return r.Space + "/" + r.Unit
+}
+
+// UnitLink is a ConfigHub link: From needs values To provides.
+type UnitLink struct {
+	Slug string  `json:"slug,omitempty"`
+	From UnitRef `json:"from"`
+	To   UnitRef `json:"to"`
+}
+
+// UnitGraphNode is a unit and its clone and link relationships.
+type UnitGraphNode struct {
+	UnitRef
+	HeadRevision int `json:"headRevision"`
+
+	// Upstream is the unit this one was cloned from, and UpstreamRevision
+	// the upstream revision it was last upgraded to
+	Upstream         *UnitRef `json:"upstream,omitempty"`
+	UpstreamRevision int      `json:"upstreamRevision,omitempty"`
+
+	// Downstream are the units cloned from this one
+	Downstream []UnitRef `json:"downstream,omitempty"`
+
+	DependsOn  []UnitLink `json:"dependsOn,omitempty"`
+	DependedBy []UnitLink `json:"dependedBy,omitempty"`
+}
+
+// UnitGraph is the clone and link graph of the units in an org.
+type UnitGraph struct {
+	// Units lists the units with at least one relationship
+	Units []*UnitGraphNode `json:"units"`
+
+	byRef map[UnitRef]*UnitGraphNode
+}
+
+// UnitImpact is a unit a change reaches.
+type UnitImpact struct {
+	UnitRef
+	// Via is "clone" or "link"
+	Via string `json:"via"`
+	// From is the unit the change reaches it through
+	From  UnitRef `json:"from"`
+	Depth int     `json:"depth"`
+	// Behind is set on a clone upgraded to an older revision of From than
+	// its head
+	Behind bool `json:"behind,omitempty"`
+}
+
+// UnitFanOut is `units graph` for one unit.
+type UnitFanOut struct {
+	Unit UnitGraphNode `json:"unit"`
+	// UpstreamChain is the clone chain above the unit, nearest first
+	UpstreamChain []UnitRef    `json:"upstreamChain,omitempty"`
+	FanOut        []UnitImpact `json:"fanOut"`
+}
+
+// buildUnitGraph joins units, keyed by space slug, with the links between
+// them. Upstreams and links are resolved by unit ID; one pointing at a
+// unit that was not loaded names it by ID or slug alone.
+func buildUnitGraph(units map[string][]CubUnitData, links []CubLinkData) *UnitGraph {
+	g := &UnitGraph{byRef: map[UnitRef]*UnitGraphNode{}}
+	byID := map[string]*UnitGraphNode{}
+	var all []*UnitGraphNode
+	for space, list := range units {
+		for _, u := range list {
+			n := &UnitGraphNode{
+				UnitRef:          UnitRef{Space: space, Unit: u.Unit.Slug},
+				HeadRevision:     u.Unit.HeadRevisionNum,
+				UpstreamRevision: u.Unit.UpstreamRevisionNum,
+			}
+			g.byRef[n.UnitRef] = n
+			if u.Unit.UnitID != "" {
+				byID[u.Unit.UnitID] = n
+			}
+			all = append(all, n)
+		}
+	}
+	upstreamOf := map[*UnitGraphNode]string{}
+	for space, list := range units {
+		for _, u := range list {
+			if u.Unit.UpstreamUnitID != "" {
+				upstreamOf[g.byRef[UnitRef{Space: space, Unit: u.Unit.Slug}]] = u.Unit.UpstreamUnitID
+			}
+		}
+	}
+	for n, id := range upstreamOf {
+		ref := UnitRef{Unit: id}
+		if up, ok := byID[id]; ok {
+			ref = up.UnitRef
+			up.Downstream = append(up.Downstream, n.UnitRef)
+		}
+		n.Upstream = &ref
+	}
+
+	seen := map[string]bool{}
+	for _, l := range links {
+		if l.Link.LinkID != "" {
+			if seen[l.Link.LinkID] {
+				continue // listed from both spaces
+			}
+			seen[l.Link.LinkID] = true
+		}
+		from, to := byID[l.Link.FromUnitID], byID[l.Link.ToUnitID]
+		link := UnitLink{Slug: l.Link.Slug, From: UnitRef{Unit: l.FromUnit.Slug}, To: UnitRef{Unit: l.ToUnit.Slug}}
+		if from != nil {
+			link.From = from.UnitRef
+		}
+		if to != nil {
+			link.To = to.UnitRef
+		}
+		if from != nil {
+			from.DependsOn = append(from.DependsOn, link)
+		}
+		if to != nil {
+			to.DependedBy = append(to.DependedBy, link)
+		}
+	}
+
+	for _, n := range all {
+		if n.Upstream == nil && len(n.Downstream) == 0 && len(n.DependsOn) == 0 && len(n.DependedBy) == 0 {
+			continue
+		}
+		sort.Slice(n.Downstream, func(i, j int) bool { return n.Downstream[i].String() < n.Downstream[j].String() })
+		sort.Slice(n.DependsOn, func(i, j int) bool { return n.DependsOn[i].To.String() < n.DependsOn[j].To.String() })
+		sort.Slice(n.DependedBy, func(i, j int) bool { return n.DependedBy[i].From.String() < n.DependedBy[j].From.String() })
+		g.Units = append(g.Units, n)
+	}
+	sort.Slice(g.Units, func(i, j int) bool { return g.Units[i].String() < g.Units[j].String() })
+	return g
+}
+
+// Get returns the unit ref names, or nil when it was not loaded.
+func (g *UnitGraph) Get(ref UnitRef) *UnitGraphNode {
+	return g.byRef[ref]
+}
+
+// Find resolves a unit given as "space/unit", or as a slug alone when only
+// one space has a unit of that name.
+func (g *UnitGraph) Find(name, space string) (*UnitGraphNode, error) {
+	if s, u, ok := strings.Cut(name, "/"); ok {
+		space, name = s, u
+	}
+	if space != "" {
+		n, ok := g.byRef[UnitRef{Space: space, Unit: name}]
+		if !ok {
+			return nil, fmt.Errorf("unit %s/%s not found", space, name)
+		}
+		return n, nil
+	}
+	var found []*UnitGraphNode
+	for ref, n := range g.byRef {
+		if ref.Unit == name {
+			found = append(found, n)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("unit %s not found", name)
+	case 1:
+		return found[0], nil
+	}
+	var spaces []string
+	for _, n := range found {
+		spaces = append(spaces, n.Space)
+	}
+	sort.Strings(spaces)
+	return nil, fmt.Errorf("unit %s is in several spaces (%s); name one with --space or space/unit", name, strings.Join(spaces, ", "))
+}
+
+// UpstreamChain returns the units above n in its clone chain, nearest
+// first.
+func (g *UnitGraph) UpstreamChain(n *UnitGraphNode) []UnitRef {
+	var chain []UnitRef
+	seen := map[UnitRef]bool{n.UnitRef: true}
+	for n.Upstream != nil && !seen[*n.Upstream] {
+		chain = append(chain, *n.Upstream)
+		seen[*n.Upstream] = true
+		next := g.byRef[*n.Upstream]
+		if next == nil {
+			break
+		}
+		n = next
+	}
+	return chain
+}
+
+// behind reports whether clone was upgraded to an older revision of its
+// upstream than the upstream's head.
+func (g *UnitGraph) behind(clone *UnitGraphNode) bool {
+	if clone == nil || clone.Upstream == nil {
+		return false
+	}
+	up := g.byRef[*clone.Upstream]
+	return up != nil && clone.UpstreamRevision < up.HeadRevision
+}
+
+// FanOut lists every unit a change to ref reaches, breadth-first: its
+// clones and the units linking to it, then theirs. Each unit is listed
+// once, at the shallowest depth it is reached.
+func (g *UnitGraph) FanOut(ref UnitRef) []UnitImpact {
+	var out []UnitImpact
+	seen := map[UnitRef]bool{ref: true}
+	queue := []UnitRef{ref}
+	for depth := 1; len(queue) > 0; depth++ {
+		var next []UnitRef
+		for _, r := range queue {
+			for _, impact := range g.children(r) {
+				if seen[impact.UnitRef] {
+					continue
+				}
+				seen[impact.UnitRef] = true
+				impact.Depth = depth
+				out = append(out, impact)
+				next = append(next, impact.UnitRef)
+			}
+		}
+		queue = next
+	}
+	return out
+}
+
+// children are the units a change to ref reaches directly.
+func (g *UnitGraph) children(ref UnitRef) []UnitImpact {
+	n := g.byRef[ref]
+	if n == nil {
+		return nil
+	}
+	var out []UnitImpact
+	for _, d := range n.Downstream {
+		out = append(out, UnitImpact{UnitRef: d, Via: "clone", From: ref, Behind: g.behind(g.byRef[d])})
+	}
+	for _, l := range n.DependedBy {
+		out = append(out, UnitImpact{UnitRef: l.From, Via: "link", From: ref})
+	}
+	return out
+}
+
+// Roots returns the base units: those with clones or dependents and no
+// upstream or links of their own.
+func (g *UnitGraph) Roots() []*UnitGraphNode {
+	var roots []*UnitGraphNode
+	for _, n := range g.Units {
+		if n.Upstream == nil && len(n.DependsOn) == 0 && (len(n.Downstream) > 0 || len(n.DependedBy) > 0) {
+			roots = append(roots, n)
+		}
+	}
+	return roots
+}
+
+// loadUnitGraph loads the units and links of every space through
+// spaceLoadPool.
+func loadUnitGraph(ctx context.Context) (*UnitGraph, error) {
+	spaces, err := listSpaceSlugs()
+	if err != nil {
+		return nil, fmt.Errorf("list spaces: %w", err)
+	}
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		units    = make(map[string][]CubUnitData, len(spaces))
+		links    []CubLinkData
+	)
+	for _, space := range spaces {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = spaceLoadPool.Do(ctx, func() {
+				list, err := loadUnitsForSpace(space)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("list units in %s: %w", space, err)
+				}
+				units[space] = list
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = spaceLoadPool.Do(ctx, func() {
+				// A space whose links can't be listed still shows clones
+				list, _ := loadLinksForSpace(space)
+				mu.Lock()
+				defer mu.Unlock()
+				links = append(links, list...)
+			})
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return buildUnitGraph(units, links), nil
+}
+
+func loadLinksForSpace(spaceSlug string) ([]CubLinkData, error) {
+	linksJSON, err := runCubCommand("link", "list", "--space", spaceSlug, "--json")
+	if err != nil {
+		return nil, err
+	}
+	var links []CubLinkData
+	if err := json.Unmarshal(linksJSON, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func runUnitsGraph(cmd *cobra.Command, args []string) error {
+	if err := checkCubAuth(cmd.CommandPath()); err != nil {
+		return err
+	}
+	g, err := loadUnitGraph(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		roots := g.Roots()
+		if unitsGraphSpace != "" {
+			roots = rootsReaching(g, roots, unitsGraphSpace)
+		}
+		if unitsGraphJSON {
+			return writeJSON(os.Stdout, "UnitGraph", g)
+		}
+		printUnitForest(os.Stdout, g, roots)
+		return nil
+	}
+
+	n, err := g.Find(args[0], unitsGraphSpace)
+	if err != nil {
+		return err
+	}
+	fan := UnitFanOut{Unit: *n, UpstreamChain: g.UpstreamChain(n), FanOut: g.FanOut(n.UnitRef)}
+	if unitsGraphJSON {
+		return writeJSON(os.Stdout, "UnitFanOut", fan)
+	}
+	printUnitFanOut(os.Stdout, g, fan)
+	return nil
+}
+
+// rootsReaching keeps the roots whose fan-out includes a unit in space.
+func rootsReaching(g *UnitGraph, roots []*UnitGraphNode, space string) []*UnitGraphNode {
+	var keep []*UnitGraphNode
+	for _, r := range roots {
+		reaches := r.Space == space
+		for _, i := range g.FanOut(r.UnitRef) {
+			reaches = reaches || i.Space == space
+		}
+		if reaches {
+			keep = append(keep, r)
+		}
+	}
+	return keep
+}
+
+func printUnitForest(w io.Writer, g *UnitGraph, roots []*UnitGraphNode) {
+	if len(roots) == 0 {
+		fmt.Fprintln(w, "No unit clones or links found.")
+		return
+	}
+	for i, r := range roots {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s%s%s %s(%d unit(s) downstream)%s\n", colorBold, r, colorReset, colorDim, len(g.FanOut(r.UnitRef)), colorReset)
+		printUnitTree(w, g, r.UnitRef, "", map[UnitRef]bool{r.UnitRef: true})
+	}
+}
+
+func printUnitFanOut(w io.Writer, g *UnitGraph, fan UnitFanOut) {
+	n := fan.Unit
+	fmt.Fprintf(w, "%sUnit:%s %s (rev %d)\n", colorBold, colorReset, n.UnitRef, n.HeadRevision)
+	if len(fan.UpstreamChain) > 0 {
+		var chain []string
+		for _, r := range fan.UpstreamChain {
+			chain = append(chain, r.String())
+		}
+		line := strings.Join(chain, " ← ")
+		if up := g.Get(fan.UpstreamChain[0]); up != nil {
+			line += fmt.Sprintf(" (at upstream rev %d of %d)", n.UpstreamRevision, up.HeadRevision)
+		}
+		fmt.Fprintf(w, "  Cloned from:  %s\n", line)
+	}
+	for _, l := range n.DependsOn {
+		fmt.Fprintf(w, "  Links to:     %s%s\n", l.To, linkSlug(l))
+	}
+	fmt.Fprintln(w)
+	if len(fan.FanOut) == 0 {
+		fmt.Fprintln(w, "A change to this unit reaches no other unit.")
+		return
+	}
+	fmt.Fprintf(w, "A change fans out to %d unit(s):\n", len(fan.FanOut))
+	fmt.Fprintf(w, "%s\n", n.UnitRef)
+	printUnitTree(w, g, n.UnitRef, "", map[UnitRef]bool{n.UnitRef: true})
+}
+
+// printUnitTree prints the units a change to ref reaches as a tree. A unit
+// already printed on the path is shown once more, marked as a cycle.
+func printUnitTree(w io.Writer, g *UnitGraph, ref UnitRef, indent string, path map[UnitRef]bool) {
+	children := g.children(ref)
+	for i, c := range children {
+		branch, next := "├─ ", "│  "
+		if i == len(children)-1 {
+			branch, next = "└─ ", "   "
+		}
+		note := ""
+		if c.Behind {
+			n := g.Get(c.UnitRef)
+			note = fmt.Sprintf(" %s(behind: upstream rev %d of %d)%s", colorYellow, n.UpstreamRevision, g.Get(ref).HeadRevision, colorReset)
+		}
+		if path[c.UnitRef] {
+			fmt.Fprintf(w, "%s%s%-5s %s %s(cycle)%s\n", indent, branch, c.Via, c.UnitRef, colorRed, colorReset)
+			continue
+		}
+		fmt.Fprintf(w, "%s%s%s%-5s%s %s%s\n", indent, branch, colorDim, c.Via, colorReset, c.UnitRef, note)
+		path[c.UnitRef] = true
+		printUnitTree(w, g, c.UnitRef, indent+next, path)
+		delete(path, c.UnitRef)
+	}
+}
+
+func linkSlug(l UnitLink) string {
+	if l.Slug == "" {
+		return ""
+	}
+	return " (" + l.Slug + ")"
+}
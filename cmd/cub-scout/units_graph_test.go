@@ -0,0 +1,132 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func graphUnit(id, slug string, head int, upstream string, upstreamRev int) CubUnitData {
+	var u CubUnitData
+	u.Unit.UnitID, u.Unit.Slug, u.Unit.HeadRevisionNum = id, slug, head
+	u.Unit.UpstreamUnitID, u.Unit.UpstreamRevisionNum = upstream, upstreamRev
+	return u
+}
+
+func graphLink(id, slug, from, to string) CubLinkData {
+	var l CubLinkData
+	l.Link.LinkID, l.Link.Slug, l.Link.FromUnitID, l.Link.ToUnitID = id, slug, from, to
+	return l
+}
+
+// testUnitGraph is a base nginx unit cloned into dev and prod, prod still
+// on an older revision, with a frontend in dev linking to the dev clone.
+func testUnitGraph() *UnitGraph {
+	return buildUnitGraph(map[string][]CubUnitData{
+		"platform":  {graphUnit("u1", "nginx", 5, "", 0), graphUnit("u9", "lonely", 1, "", 0)},
+		"shop-dev":  {graphUnit("u2", "nginx", 2, "u1", 5), graphUnit("u4", "frontend", 1, "", 0)},
+		"shop-prod": {graphUnit("u3", "nginx", 1, "u1", 3)},
+	}, []CubLinkData{
+		graphLink("l1", "ingress-class", "u4", "u2"),
+		graphLink("l1", "ingress-class", "u4", "u2"), // listed twice
+		graphLink("l2", "", "u4", "u-gone"),
+	})
+}
+
+func TestBuildUnitGraph(t *testing.T) {
+	g := testUnitGraph()
+	if len(g.Units) != 4 {
+		t.Errorf("graph has %d units, want 4 (lonely has no relationships)", len(g.Units))
+	}
+	base := g.Get(UnitRef{"platform", "nginx"})
+	if len(base.Downstream) != 2 || base.Downstream[0].String() != "shop-dev/nginx" {
+		t.Errorf("base downstream = %v", base.Downstream)
+	}
+	dev := g.Get(UnitRef{"shop-dev", "nginx"})
+	if dev.Upstream == nil || *dev.Upstream != (UnitRef{"platform", "nginx"}) || len(dev.DependedBy) != 1 {
+		t.Errorf("dev clone = %+v", dev)
+	}
+	frontend := g.Get(UnitRef{"shop-dev", "frontend"})
+	if len(frontend.DependsOn) != 2 || frontend.DependsOn[1].To.Space != "shop-dev" {
+		t.Errorf("frontend links = %+v, want the duplicate dropped and the unknown unit kept", frontend.DependsOn)
+	}
+
+	roots := g.Roots()
+	if len(roots) != 1 || roots[0].String() != "platform/nginx" {
+		t.Errorf("roots = %v", roots)
+	}
+	if len(rootsReaching(g, roots, "shop-prod")) != 1 || len(rootsReaching(g, roots, "other")) != 0 {
+		t.Error("rootsReaching should keep trees that reach the space")
+	}
+}
+
+func TestUnitGraphFanOut(t *testing.T) {
+	g := testUnitGraph()
+	fan := g.FanOut(UnitRef{"platform", "nginx"})
+	var got []string
+	for _, i := range fan {
+		s := i.Via + ":" + i.String()
+		if i.Behind {
+			s += "(behind)"
+		}
+		got = append(got, s)
+	}
+	want := "clone:shop-dev/nginx clone:shop-prod/nginx(behind) link:shop-dev/frontend"
+	if strings.Join(got, " ") != want {
+		t.Errorf("fan-out = %v, want %s", got, want)
+	}
+	if fan[2].Depth != 2 || fan[2].From.String() != "shop-dev/nginx" {
+		t.Errorf("frontend impact = %+v, want depth 2 via shop-dev/nginx", fan[2])
+	}
+	if chain := g.UpstreamChain(g.Get(UnitRef{"shop-prod", "nginx"})); len(chain) != 1 || chain[0].String() != "platform/nginx" {
+		t.Errorf("upstream chain = %v", chain)
+	}
+
+	if _, err := g.Find("nginx", ""); err == nil || !strings.Contains(err.Error(), "several spaces") {
+		t.Errorf("ambiguous unit should be an error, got %v", err)
+	}
+	if n, err := g.Find("shop-prod/nginx", ""); err != nil || n.Space != "shop-prod" {
+		t.Errorf("Find(shop-prod/nginx) = %v, %v", n, err)
+	}
+	if n, err := g.Find("frontend", ""); err != nil || n.Space != "shop-dev" {
+		t.Errorf("Find(frontend) = %v, %v", n, err)
+	}
+}
+
+func TestPrintUnitFanOut(t *testing.T) {
+	g := testUnitGraph()
+	n := g.Get(UnitRef{"shop-prod", "nginx"})
+	var buf bytes.Buffer
+	printUnitFanOut(&buf, g, UnitFanOut{Unit: *n, UpstreamChain: g.UpstreamChain(n)})
+	if out := buf.String(); !strings.Contains(out, "Cloned from:  platform/nginx (at upstream rev 3 of 5)") || !strings.Contains(out, "reaches no other unit") {
+		t.Errorf("output:\n%s", out)
+	}
+
+	buf.Reset()
+	printUnitForest(&buf, g, g.Roots())
+	out := buf.String()
+	for _, want := range []string{"(3 unit(s) downstream)", "shop-dev/nginx", "└─ ", "shop-dev/frontend", "behind: upstream rev 3 of 5"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("forest should contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatUnitRelations(t *testing.T) {
+	g := testUnitGraph()
+	out := formatUnitRelations(g, UnitRef{"shop-dev", "nginx"})
+	for _, want := range []string{"DEPENDENCIES", "Upstream:    platform/nginx (rev 5 of 5)", "Linked by:   shop-dev/frontend (ingress-class)", "a change reaches 1 unit(s)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("relations should contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "behind") {
+		t.Errorf("an up-to-date clone is not behind:\n%s", out)
+	}
+	if formatUnitRelations(g, UnitRef{"platform", "lonely"}) != "" {
+		t.Error("a unit without relationships should have no section")
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+var (
+	verifyUnits     []string
+	verifyArgoNS    string
+	verifyImportOut bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify that an import or migration completed",
+}
+
+var verifyImportCmd = &cobra.Command{
+	Use:   "import <space>",
+	Short: "Check that imported units took over their live resources",
+	Long: `Check that each unit imported into a space has taken over its live resources.
+
+Every unit gets a pass/fail checklist:
+  applied    the unit has a live revision
+  live data  the worker reports live data for the unit
+  labels     every resource in the unit carries confighub.com/UnitSlug=<unit>
+  handoff    the Flux Kustomization/HelmRelease or Argo CD Application that
+             used to manage the resources is deleted or suspended (Flux
+             spec.suspend, Argo CD auto-sync off)
+
+The command exits 1 when any unit fails a check.
+
+Examples:
+  cub-scout verify import payments
+  cub-scout verify import payments --unit api --unit worker
+  cub-scout verify import payments --json`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSpaces,
+	RunE:              runVerifyImport,
+}
+
+func init() {
+	verifyImportCmd.Flags().StringSliceVar(&verifyUnits, "unit", nil, "Units to check (default: every unit in the space)")
+	verifyImportCmd.Flags().StringVar(&verifyArgoNS, "argocd-namespace", "argocd", "Namespace where ArgoCD is installed")
+	verifyImportCmd.Flags().BoolVar(&verifyImportOut, "json", false, "Output as JSON")
+
+	verifyCmd.AddCommand(verifyImportCmd)
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// Import verification checks, in checklist order.
+const (
+	checkApplied  = "applied"
+	checkLiveData = "live data"
+	checkLabels   = "labels"
+	checkHandoff  = "handoff"
+)
+
+// ImportCheck is one item of a unit's post-import checklist.
+type ImportCheck struct {
+	Name   string `json:"name"`
+	Pass   bool   `json:"pass"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ImportVerification is the checklist of one imported unit.
+type ImportVerification struct {
+	Space  string        `json:"space"`
+	Unit   string        `json:"unit"`
+	Pass   bool          `json:"pass"`
+	Checks []ImportCheck `json:"checks"`
+}
+
+// verifySource fetches what import verification needs from ConfigHub and
+// the cluster. object and deployer return nil, nil when nothing is found.
+type verifySource struct {
+	desired  func(space, unit string) ([]byte, error)
+	liveData func(space, unit string) ([]byte, error)
+	object   func(apiVersion, kind, namespace, name string) (*unstructured.Unstructured, error)
+	deployer func(owner agent.Ownership) (*unstructured.Unstructured, error)
+}
+
+func runVerifyImport(cmd *cobra.Command, args []string) error {
+	if !cubInstalled() {
+		return cubMissingError(cmd.CommandPath())
+	}
+	space := args[0]
+	units, err := loadUnitsForSpace(space)
+	if err != nil {
+		return fmt.Errorf("list units in space %s: %w\n\n  Check that you're authenticated: cub auth login", space, err)
+	}
+	if len(verifyUnits) > 0 {
+		var selected []CubUnitData
+		for _, u := range units {
+			if contains(verifyUnits, u.Unit.Slug) {
+				selected = append(selected, u)
+			}
+		}
+		units = selected
+	}
+	if len(units) == 0 {
+		return fmt.Errorf("no units to verify in space %s", space)
+	}
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return fmt.Errorf("failed to build kubeconfig: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	src := clusterVerifySource(context.Background(), dynClient, verifyArgoNS)
+
+	results := make([]ImportVerification, 0, len(units))
+	for _, u := range units {
+		results = append(results, verifyImportedUnit(space, u, src))
+	}
+
+	if verifyImportOut {
+		if err := writeJSON(os.Stdout, "ImportVerifications", results); err != nil {
+			return err
+		}
+	} else {
+		printImportVerifications(os.Stdout, results)
+	}
+	if failed := countFailedVerifications(results); failed > 0 {
+		return fmt.Errorf("%d of %d unit(s) failed import verification", failed, len(results))
+	}
+	return nil
+}
+
+func clusterVerifySource(ctx context.Context, dynClient dynamic.Interface, argoNamespace string) verifySource {
+	get := func(apiVersion, kind, namespace, name string) (*unstructured.Unstructured, error) {
+		gvr, err := agent.APIVersionKindToGVR(apiVersion, kind)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := dynClient.Resource(gvr).Namespace(namespace).Get(ctx, name, v1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return obj, err
+	}
+	return verifySource{
+		desired: func(space, unit string) ([]byte, error) {
+			return runCubCommand("unit", "get", unit, "--space", space, "--data-only")
+		},
+		liveData: func(space, unit string) ([]byte, error) {
+			return runCubCommand("unit", "livedata", unit, "--space", space)
+		},
+		object: get,
+		deployer: func(owner agent.Ownership) (*unstructured.Unstructured, error) {
+			switch owner.SubType {
+			case "kustomization":
+				return get("kustomize.toolkit.fluxcd.io/v1", "Kustomization", owner.Namespace, owner.Name)
+			case "helmrelease":
+				return get("helm.toolkit.fluxcd.io/v2", "HelmRelease", owner.Namespace, owner.Name)
+			case "application":
+				return get("argoproj.io/v1alpha1", "Application", argoNamespace, owner.Name)
+			}
+			return nil, nil
+		},
+	}
+}
+
+// verifyImportedUnit runs the post-import checklist for one unit. Checks that
+// need the unit's resources are skipped as failed when its data can't be read.
+func verifyImportedUnit(space string, u CubUnitData, src verifySource) ImportVerification {
+	v := ImportVerification{Space: space, Unit: u.Unit.Slug}
+	add := func(name string, pass bool, format string, a ...any) {
+		v.Checks = append(v.Checks, ImportCheck{Name: name, Pass: pass, Detail: fmt.Sprintf(format, a...)})
+	}
+
+	switch live, head := u.Unit.LiveRevisionNum, u.Unit.HeadRevisionNum; {
+	case live == 0:
+		add(checkApplied, false, "never applied (head revision %d); run: cub unit apply %s --space %s", head, u.Unit.Slug, space)
+	case live < head:
+		add(checkApplied, true, "live revision %d, behind head %d", live, head)
+	default:
+		add(checkApplied, true, "live revision %d", live)
+	}
+
+	if data, err := src.liveData(space, u.Unit.Slug); err != nil {
+		add(checkLiveData, false, "no live data: %v", err)
+	} else if docs, err := decodeYAMLDocs(data); err != nil || len(docs) == 0 {
+		add(checkLiveData, false, "worker has not reported live data; check the target and worker")
+	} else {
+		add(checkLiveData, true, "%d resource(s) reported", len(docs))
+	}
+
+	data, err := src.desired(space, u.Unit.Slug)
+	var docs []map[string]any
+	if err == nil {
+		docs, err = decodeYAMLDocs(data)
+	}
+	if err != nil {
+		add(checkLabels, false, "could not read unit data: %v", err)
+		add(checkHandoff, false, "could not read unit data")
+		return v
+	}
+
+	var unlabeled, missing, stale []string
+	owners := map[agent.Ownership]bool{}
+	for _, doc := range docs {
+		obj := unstructured.Unstructured{Object: doc}
+		key := resourceKey(doc)
+		live, err := src.object(obj.GetAPIVersion(), obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		if err != nil || live == nil {
+			missing = append(missing, key)
+			continue
+		}
+		slug := live.GetLabels()["confighub.com/UnitSlug"]
+		if slug == "" {
+			slug = live.GetAnnotations()["confighub.com/UnitSlug"]
+		}
+		if slug != u.Unit.Slug {
+			unlabeled = append(unlabeled, key)
+		}
+		for _, o := range gitopsClaims(live) {
+			owners[agent.Ownership{Type: o.Type, SubType: o.SubType, Name: o.Name, Namespace: o.Namespace}] = true
+		}
+	}
+
+	switch {
+	case len(missing)+len(unlabeled) == 0:
+		add(checkLabels, true, "%d/%d resource(s) labeled confighub.com/UnitSlug=%s", len(docs), len(docs), u.Unit.Slug)
+	case len(unlabeled) == 0:
+		add(checkLabels, false, "not found in cluster: %s", strings.Join(missing, ", "))
+	default:
+		detail := "missing confighub.com/UnitSlug=" + u.Unit.Slug + ": " + strings.Join(unlabeled, ", ")
+		if len(missing) > 0 {
+			detail += "; not found in cluster: " + strings.Join(missing, ", ")
+		}
+		add(checkLabels, false, "%s", detail)
+	}
+
+	var released []string
+	for o := range owners {
+		name := deployerName(o)
+		d, err := src.deployer(o)
+		switch {
+		case err != nil:
+			stale = append(stale, name+" (unreadable: "+err.Error()+")")
+		case d == nil:
+			released = append(released, name+" deleted")
+		case deployerSuspended(d):
+			released = append(released, name+" suspended")
+		default:
+			stale = append(stale, name+" still reconciling")
+		}
+	}
+	sort.Strings(released)
+	sort.Strings(stale)
+	switch {
+	case len(stale) > 0:
+		add(checkHandoff, false, "%s", strings.Join(stale, "; "))
+	case len(released) > 0:
+		add(checkHandoff, true, "%s", strings.Join(released, "; "))
+	default:
+		add(checkHandoff, true, "no Flux or Argo CD ownership on live resources")
+	}
+
+	v.Pass = true
+	for _, c := range v.Checks {
+		v.Pass = v.Pass && c.Pass
+	}
+	return v
+}
+
+// gitopsClaims returns the Flux and Argo CD managers still marked on a
+// resource. Contested detection sees every claim; otherwise a single owner.
+func gitopsClaims(obj *unstructured.Unstructured) []agent.Ownership {
+	claims := agent.DetectContestedOwnership(obj)
+	if claims == nil {
+		claims = []agent.Ownership{agent.DetectOwnership(obj)}
+	}
+	var out []agent.Ownership
+	for _, c := range claims {
+		if c.Type == agent.OwnerFlux || c.Type == agent.OwnerArgo {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// deployerSuspended reports whether a deployer no longer applies changes: a
+// suspended Flux object, or an Argo CD Application without automated sync.
+func deployerSuspended(d *unstructured.Unstructured) bool {
+	if d.GetKind() == "Application" {
+		_, automated, _ := unstructured.NestedFieldNoCopy(d.Object, "spec", "syncPolicy", "automated")
+		return !automated
+	}
+	suspended, _, _ := unstructured.NestedBool(d.Object, "spec", "suspend")
+	return suspended
+}
+
+func deployerName(o agent.Ownership) string {
+	switch o.SubType {
+	case "kustomization":
+		return "Flux Kustomization " + o.Namespace + "/" + o.Name
+	case "helmrelease":
+		return "Flux HelmRelease " + o.Namespace + "/" + o.Name
+	}
+	return "Argo CD Application " + o.Name
+}
+
+func countFailedVerifications(results []ImportVerification) int {
+	n := 0
+	for _, r := range results {
+		if !r.Pass {
+			n++
+		}
+	}
+	return n
+}
+
+func printImportVerifications(w io.Writer, results []ImportVerification) {
+	for _, r := range results {
+		icon := colorGreen + "✓" + colorReset
+		if !r.Pass {
+			icon = colorRed + "✗" + colorReset
+		}
+		fmt.Fprintf(w, "%s %s%s/%s%s\n", icon, colorBold, r.Space, r.Unit, colorReset)
+		for _, c := range r.Checks {
+			mark := colorGreen + "✓" + colorReset
+			if !c.Pass {
+				mark = colorRed + "✗" + colorReset
+			}
+			fmt.Fprintf(w, "    %s %-10s %s%s%s\n", mark, c.Name, colorDim, c.Detail, colorReset)
+		}
+		if !r.Pass {
+			fmt.Fprintf(w, "    %s→ %s%s\n", colorDim, hubFocusCommand(r.Space, "unit", r.Unit), colorReset)
+		}
+	}
+	failed := countFailedVerifications(results)
+	fmt.Fprintf(w, "\n%d/%d unit(s) passed", len(results)-failed, len(results))
+	if failed > 0 {
+		fmt.Fprintf(w, ", %d failed", failed)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,145 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+)
+
+const verifyTestData = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: prod
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: prod
+`
+
+func verifyTestSource(live map[string]*unstructured.Unstructured, deployers map[string]*unstructured.Unstructured) verifySource {
+	return verifySource{
+		desired:  func(space, unit string) ([]byte, error) { return []byte(verifyTestData), nil },
+		liveData: func(space, unit string) ([]byte, error) { return []byte(verifyTestData), nil },
+		object: func(apiVersion, kind, namespace, name string) (*unstructured.Unstructured, error) {
+			return live[kind+"/"+namespace+"/"+name], nil
+		},
+		deployer: func(o agent.Ownership) (*unstructured.Unstructured, error) {
+			return deployers[o.SubType+"/"+o.Name], nil
+		},
+	}
+}
+
+func verifyTestObject(kind string, labels, annotations map[string]string, spec map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"kind": kind, "spec": spec}}
+	u.SetLabels(labels)
+	u.SetAnnotations(annotations)
+	return u
+}
+
+func checkByName(v ImportVerification, name string) ImportCheck {
+	for _, c := range v.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	return ImportCheck{Name: name, Detail: "missing"}
+}
+
+func TestVerifyImportedUnit(t *testing.T) {
+	unit := driftTestUnit("api", 2, 2, "")
+	imported := map[string]string{"confighub.com/UnitSlug": "api"}
+	fluxLabels := map[string]string{
+		"confighub.com/UnitSlug":                "api",
+		"kustomize.toolkit.fluxcd.io/name":      "apps",
+		"kustomize.toolkit.fluxcd.io/namespace": "flux-system",
+	}
+
+	t.Run("clean import", func(t *testing.T) {
+		v := verifyImportedUnit("prod", unit, verifyTestSource(map[string]*unstructured.Unstructured{
+			"Deployment/prod/api": verifyTestObject("Deployment", imported, nil, nil),
+			"Service/prod/api":    verifyTestObject("Service", imported, nil, nil),
+		}, nil))
+		if !v.Pass || len(v.Checks) != 4 {
+			t.Fatalf("verification = %+v", v)
+		}
+	})
+
+	t.Run("flux still reconciling", func(t *testing.T) {
+		src := verifyTestSource(map[string]*unstructured.Unstructured{
+			"Deployment/prod/api": verifyTestObject("Deployment", fluxLabels, nil, nil),
+			"Service/prod/api":    verifyTestObject("Service", fluxLabels, nil, nil),
+		}, map[string]*unstructured.Unstructured{
+			"kustomization/apps": verifyTestObject("Kustomization", nil, nil, map[string]interface{}{"suspend": false}),
+		})
+		v := verifyImportedUnit("prod", unit, src)
+		if v.Pass {
+			t.Fatal("want failure while the Kustomization reconciles")
+		}
+		if c := checkByName(v, checkHandoff); c.Pass || c.Detail != "Flux Kustomization flux-system/apps still reconciling" {
+			t.Errorf("handoff = %+v", c)
+		}
+
+		src.deployer = func(agent.Ownership) (*unstructured.Unstructured, error) {
+			return verifyTestObject("Kustomization", nil, nil, map[string]interface{}{"suspend": true}), nil
+		}
+		if c := checkByName(verifyImportedUnit("prod", unit, src), checkHandoff); !c.Pass || !strings.HasSuffix(c.Detail, "suspended") {
+			t.Errorf("suspended handoff = %+v", c)
+		}
+	})
+
+	t.Run("argo auto-sync and missing label", func(t *testing.T) {
+		argo := map[string]string{"argocd.argoproj.io/tracking-id": "api:apps/Deployment:prod/api"}
+		src := verifyTestSource(map[string]*unstructured.Unstructured{
+			"Deployment/prod/api": verifyTestObject("Deployment", nil, argo, nil),
+		}, map[string]*unstructured.Unstructured{
+			"application/api": verifyTestObject("Application", nil, nil, map[string]interface{}{
+				"syncPolicy": map[string]interface{}{"automated": map[string]interface{}{"prune": true}},
+			}),
+		})
+		v := verifyImportedUnit("prod", unit, src)
+		labels := checkByName(v, checkLabels)
+		if labels.Pass || !strings.Contains(labels.Detail, "Deployment/prod/api") || !strings.Contains(labels.Detail, "not found in cluster: Service/prod/api") {
+			t.Errorf("labels = %+v", labels)
+		}
+		if c := checkByName(v, checkHandoff); c.Pass || c.Detail != "Argo CD Application api still reconciling" {
+			t.Errorf("handoff = %+v", c)
+		}
+	})
+
+	t.Run("not applied, no data", func(t *testing.T) {
+		src := verifyTestSource(nil, nil)
+		src.liveData = func(space, unit string) ([]byte, error) { return nil, nil }
+		src.desired = func(space, unit string) ([]byte, error) { return nil, errors.New("unit not found") }
+		v := verifyImportedUnit("prod", driftTestUnit("api", 1, 0, ""), src)
+		for _, name := range []string{checkApplied, checkLiveData, checkLabels, checkHandoff} {
+			if c := checkByName(v, name); c.Pass {
+				t.Errorf("%s passed: %+v", name, c)
+			}
+		}
+	})
+}
+
+func TestPrintImportVerifications(t *testing.T) {
+	var buf bytes.Buffer
+	printImportVerifications(&buf, []ImportVerification{
+		{Space: "prod", Unit: "api", Pass: true, Checks: []ImportCheck{{Name: checkApplied, Pass: true, Detail: "live revision 2"}}},
+		{Space: "prod", Unit: "web", Checks: []ImportCheck{{Name: checkHandoff, Detail: "Argo CD Application web still reconciling"}}},
+	})
+	out := buf.String()
+	for _, want := range []string{"prod/api", "live revision 2", "Argo CD Application web still reconciling", "1/2 unit(s) passed, 1 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package hierarchysvc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Cache keeps command output for a time-to-live, keyed by the command
+// line. The hierarchy TUI, its panels and the CLI commands read ConfigHub
+// through one cache, so moving around the tree does not refetch the same
+// space and unit lists every few seconds. Concurrent gets of a key that is
+// not cached share one fetch, and fetches are rate limited. Errors are not
+// cached.
+type Cache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	limiter *rate.Limiter
+
+	mu       sync.Mutex
+	entries  map[string]cacheEntry
+	inflight map[string]*cacheCall
+}
+
+type cacheEntry struct {
+	out     []byte
+	expires time.Time
+}
+
+type cacheCall struct {
+	done chan struct{}
+	out  []byte
+	err  error
+}
+
+// NewCache returns a cache keeping output for ttl and starting at most
+// perSecond fetches per second, with bursts of up to burst. ttl <= 0
+// disables caching: every get fetches. perSecond <= 0 disables the rate
+// limit.
+func NewCache(ttl time.Duration, perSecond float64, burst int) *Cache {
+	limit := rate.Inf
+	if perSecond > 0 {
+		limit = rate.Limit(perSecond)
+	}
+	return &Cache{
+		ttl:      ttl,
+		now:      time.Now,
+		limiter:  rate.NewLimiter(limit, max(burst, 1)),
+		entries:  make(map[string]cacheEntry),
+		inflight: make(map[string]*cacheCall),
+	}
+}
+
+// Get returns the output cached for key, or fetches and caches it.
+func (c *Cache) Get(key string, fetch func() ([]byte, error)) ([]byte, error) {
+	if c.ttl <= 0 {
+		return c.fetch(fetch)
+	}
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && c.now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.out, nil
+	}
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.out, call.err
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	call.out, call.err = c.fetch(fetch)
+
+	c.mu.Lock()
+	// A purge during the fetch drops the call, and its output may predate
+	// the change that caused the purge
+	if c.inflight[key] == call {
+		delete(c.inflight, key)
+		if call.err == nil {
+			c.entries[key] = cacheEntry{out: call.out, expires: c.now().Add(c.ttl)}
+		}
+	}
+	c.mu.Unlock()
+	close(call.done)
+	return call.out, call.err
+}
+
+func (c *Cache) fetch(fetch func() ([]byte, error)) ([]byte, error) {
+	if err := c.limiter.Wait(context.Background()); err != nil {
+		return nil, err
+	}
+	return fetch()
+}
+
+// Forget drops the output cached for key, so the next get fetches it.
+func (c *Cache) Forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	delete(c.inflight, key)
+}
+
+// Purge drops everything cached, e.g. after a write or on refresh.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cacheEntry)
+	c.inflight = make(map[string]*cacheCall)
+}
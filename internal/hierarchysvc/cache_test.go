@@ -0,0 +1,103 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package hierarchysvc
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheTTL(t *testing.T) {
+	c := NewCache(10*time.Second, 0, 0)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	var fetches int
+	fetch := func() ([]byte, error) {
+		fetches++
+		return []byte("spaces"), nil
+	}
+	for i := 0; i < 3; i++ {
+		if out, err := c.Get("space list", fetch); err != nil || string(out) != "spaces" {
+			t.Fatalf("Get = %q, %v", out, err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("fetches = %d, want 1 within the TTL", fetches)
+	}
+
+	now = now.Add(11 * time.Second)
+	_, _ = c.Get("space list", fetch)
+	if fetches != 2 {
+		t.Errorf("fetches = %d, want a refetch once expired", fetches)
+	}
+
+	c.Purge()
+	_, _ = c.Get("space list", fetch)
+	if fetches != 3 {
+		t.Errorf("fetches = %d, want a refetch after a purge", fetches)
+	}
+
+	c.Forget("space list")
+	_, _ = c.Get("space list", fetch)
+	if fetches != 4 {
+		t.Errorf("fetches = %d, want a refetch after Forget", fetches)
+	}
+}
+
+func TestCacheSkipsErrors(t *testing.T) {
+	c := NewCache(time.Minute, 0, 0)
+	var fetches int
+	fail := func() ([]byte, error) {
+		fetches++
+		return nil, errors.New("cub: not logged in")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get("unit list", fail); err == nil {
+			t.Fatal("want the fetch error")
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("fetches = %d, want errors not cached", fetches)
+	}
+
+	off := NewCache(0, 0, 0)
+	_, _ = off.Get("unit list", fail)
+	_, _ = off.Get("unit list", fail)
+	if fetches != 4 {
+		t.Errorf("fetches = %d, want every get to fetch with the cache off", fetches)
+	}
+}
+
+func TestCacheSharesConcurrentFetches(t *testing.T) {
+	c := NewCache(time.Minute, 0, 0)
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func() ([]byte, error) {
+		fetches.Add(1)
+		<-release
+		return []byte("units"), nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if out, _ := c.Get("unit list", fetch); string(out) != "units" {
+				t.Errorf("Get = %q", out)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("fetches = %d, want concurrent gets to share one", got)
+	}
+}
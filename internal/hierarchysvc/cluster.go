@@ -6,24 +6,41 @@
 package hierarchysvc
 
 import (
+	"regexp"
 	"strings"
 )
 
 // ExtractClusterName extracts a canonical cluster name from various context formats.
-// Handles AWS EKS ARNs, GKE context formats, kind clusters, and returns the input as-is for unknown formats.
+// Handles AWS EKS ARNs and eksctl contexts, AKS resource IDs, GKE, OpenShift
+// (oc login), Rancher project contexts, kind and k3d, and returns the input
+// as-is for unknown formats.
 func ExtractClusterName(contextName string) string {
 	// Handle empty/unknown
 	if contextName == "" || contextName == "unknown" {
 		return contextName
 	}
 
-	// AWS EKS: arn:aws:eks:region:account:cluster/name
-	if strings.HasPrefix(contextName, "arn:aws:eks:") {
-		if idx := strings.LastIndex(contextName, "/"); idx != -1 {
-			return contextName[idx+1:]
+	// AWS EKS: arn:aws:eks:region:account:cluster/name (also the aws-cn and
+	// aws-us-gov partitions)
+	if m := eksARN.FindStringSubmatch(contextName); m != nil {
+		return m[1]
+	}
+
+	// eksctl: user@name.region.eksctl.io
+	if strings.HasSuffix(contextName, ".eksctl.io") {
+		if at := strings.LastIndex(contextName, "@"); at != -1 {
+			name, _, _ := strings.Cut(contextName[at+1:], ".")
+			return name
 		}
 	}
 
+	// AKS: /subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/name
+	// (az aks get-credentials names the context after the cluster, so only
+	// resource IDs, as written by Azure Arc and some CI tooling, need parsing)
+	if m := aksResourceID.FindStringSubmatch(contextName); m != nil {
+		return m[1]
+	}
+
 	// GKE: gke_project_zone_cluster
 	if strings.HasPrefix(contextName, "gke_") {
 		parts := strings.Split(contextName, "_")
@@ -32,15 +49,38 @@ func ExtractClusterName(contextName string) string {
 		}
 	}
 
+	// OpenShift: namespace/api-cluster-example-com:6443/user, as written by
+	// oc login for the server https://api.cluster.example.com:6443
+	if m := openShiftContext.FindStringSubmatch(contextName); m != nil {
+		return strings.TrimPrefix(m[1], "api-")
+	}
+
+	// Rancher: c-xxxxx:p-xxxxx (cluster ID:project ID)
+	if m := rancherContext.FindStringSubmatch(contextName); m != nil {
+		return m[1]
+	}
+
 	// kind: kind-name
 	if strings.HasPrefix(contextName, "kind-") {
 		return strings.TrimPrefix(contextName, "kind-")
 	}
 
+	// k3d: k3d-name
+	if strings.HasPrefix(contextName, "k3d-") {
+		return strings.TrimPrefix(contextName, "k3d-")
+	}
+
 	// Default: use the context name as-is
 	return contextName
 }
 
+var (
+	eksARN           = regexp.MustCompile(`^arn:aws(?:-[a-z-]+)?:eks:[^:]*:[^:]*:cluster/([^/]+)$`)
+	aksResourceID    = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourcegroups/[^/]+/providers/microsoft\.(?:containerservice/managedclusters|kubernetes/connectedclusters)/([^/]+)$`)
+	openShiftContext = regexp.MustCompile(`^[^/]+/([^/:]+):\d+/[^/]+$`)
+	rancherContext   = regexp.MustCompile(`^(c-(?:m-)?[a-z0-9]+):p-[a-z0-9]+$`)
+)
+
 // MatchesCluster checks if a target cluster matches the current cluster.
 // Supports exact match and partial/case-insensitive matching for different naming conventions.
 func MatchesCluster(targetCluster, currentCluster string) bool {
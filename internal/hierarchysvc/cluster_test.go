@@ -36,6 +36,81 @@ func TestExtractClusterName(t *testing.T) {
 			input:    "kind-my-cluster",
 			expected: "my-cluster",
 		},
+		{
+			name:     "AWS EKS ARN in GovCloud",
+			input:    "arn:aws-us-gov:eks:us-gov-west-1:123456789012:cluster/gov-prod",
+			expected: "gov-prod",
+		},
+		{
+			name:     "AKS context from az aks get-credentials",
+			input:    "prod-aks",
+			expected: "prod-aks",
+		},
+		{
+			name:     "AKS managed cluster resource ID",
+			input:    "/subscriptions/0b1f6471-1bf0-4dda-aec3-cb9272f09590/resourceGroups/rg-prod/providers/Microsoft.ContainerService/managedClusters/prod-aks",
+			expected: "prod-aks",
+		},
+		{
+			name:     "Azure Arc connected cluster resource ID",
+			input:    "/subscriptions/0b1f6471-1bf0-4dda-aec3-cb9272f09590/resourcegroups/rg-edge/providers/microsoft.kubernetes/connectedclusters/store-042",
+			expected: "store-042",
+		},
+		{
+			name:     "GKE regional context",
+			input:    "gke_acme-platform_europe-west1_staging-eu",
+			expected: "staging-eu",
+		},
+		{
+			name:     "OpenShift oc login",
+			input:    "default/api-prod-east-example-com:6443/kube:admin",
+			expected: "prod-east-example-com",
+		},
+		{
+			name:     "OpenShift project and user",
+			input:    "payments/api-ocp4-corp-acme-io:6443/jane.doe@acme.io",
+			expected: "ocp4-corp-acme-io",
+		},
+		{
+			name:     "OpenShift system:admin",
+			input:    "openshift-config/api-crc-testing:6443/system:admin",
+			expected: "crc-testing",
+		},
+		{
+			name:     "Rancher project context",
+			input:    "c-7x2kq:p-9wzlm",
+			expected: "c-7x2kq",
+		},
+		{
+			name:     "Rancher v2.6 provisioned cluster project context",
+			input:    "c-m-4pbv8cfn:p-xk2tr",
+			expected: "c-m-4pbv8cfn",
+		},
+		{
+			name:     "Rancher cluster context",
+			input:    "downstream-prod",
+			expected: "downstream-prod",
+		},
+		{
+			name:     "k3d cluster",
+			input:    "k3d-dev",
+			expected: "dev",
+		},
+		{
+			name:     "k3d cluster with dashes",
+			input:    "k3d-cub-scout-e2e",
+			expected: "cub-scout-e2e",
+		},
+		{
+			name:     "eksctl context",
+			input:    "jane@prod-east.us-east-1.eksctl.io",
+			expected: "prod-east",
+		},
+		{
+			name:     "docker-desktop",
+			input:    "docker-desktop",
+			expected: "docker-desktop",
+		},
 		{
 			name:     "plain cluster name",
 			input:    "production",
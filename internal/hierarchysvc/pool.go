@@ -0,0 +1,54 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package hierarchysvc
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// LoadPool bounds how many loads run at once and how fast new ones start.
+// The hierarchy TUI runs its per-space loads (units, targets, workers,
+// triggers and guest org spaces) through one pool, so opening a large org
+// does not fork hundreds of `cub` processes at once. One-off commands and
+// supervised workers run outside it.
+type LoadPool struct {
+	slots   chan struct{}
+	limiter *rate.Limiter
+}
+
+// NewLoadPool returns a pool running at most workers loads at a time and
+// starting at most perSecond loads per second, with bursts of up to workers.
+// perSecond <= 0 disables the rate limit.
+func NewLoadPool(workers int, perSecond float64) *LoadPool {
+	if workers < 1 {
+		workers = 1
+	}
+	limit := rate.Inf
+	if perSecond > 0 {
+		limit = rate.Limit(perSecond)
+	}
+	return &LoadPool{
+		slots:   make(chan struct{}, workers),
+		limiter: rate.NewLimiter(limit, workers),
+	}
+}
+
+// Do waits for a free slot and the rate limit, then runs fn. It returns
+// ctx's error without running fn if ctx is done first.
+func (p *LoadPool) Do(ctx context.Context, fn func()) error {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.slots }()
+
+	if err := p.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	fn()
+	return nil
+}
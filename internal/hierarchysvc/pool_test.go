@@ -0,0 +1,70 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package hierarchysvc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadPoolBoundsConcurrency(t *testing.T) {
+	pool := NewLoadPool(3, 0)
+
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = pool.Do(context.Background(), func() {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				running.Add(-1)
+			})
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", got)
+	}
+}
+
+func TestLoadPoolRateLimit(t *testing.T) {
+	pool := NewLoadPool(1, 50) // burst 1, then one every 20ms
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := pool.Do(context.Background(), func() {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("4 loads at 50/s took %v, want >= 60ms minus slack", elapsed)
+	}
+}
+
+func TestLoadPoolContextCanceled(t *testing.T) {
+	pool := NewLoadPool(1, 0)
+	release := make(chan struct{})
+	go func() { _ = pool.Do(context.Background(), func() { <-release }) }()
+	time.Sleep(10 * time.Millisecond) // let the first load take the only slot
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ran := false
+	if err := pool.Do(ctx, func() { ran = true }); err == nil || ran {
+		t.Errorf("Do = %v, ran = %v; want context error without running", err, ran)
+	}
+	close(release)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package hierarchysvc
+
+import (
+	"fmt"
+	"time"
+)
+
+// Target health statuses, matching the TreeNode status strings.
+const (
+	TargetOK    = "ok"
+	TargetWarn  = "warn"
+	TargetError = "error"
+)
+
+// WorkerStaleAfter is how long a Ready worker may go without a heartbeat
+// before its targets are reported as warn.
+const WorkerStaleAfter = 5 * time.Minute
+
+// TargetProbe holds everything known about one target when it is probed.
+// Zero values mean "unknown" and never raise the status on their own, so a
+// probe with partial data degrades to fewer checks rather than false errors.
+type TargetProbe struct {
+	WorkerSlug      string    // empty when the target has no worker
+	WorkerFound     bool      // the worker exists in the space's worker list
+	WorkerCondition string    // e.g. Ready, Disconnected
+	WorkerLastSeen  time.Time // last heartbeat, zero if not reported
+	LastApply       time.Time // newest apply of any unit on the target
+	KubeContext     string    // from the target parameters, empty if unset
+	ContextChecked  bool      // KubeContext exists in the local kubeconfig and was contacted
+	ContextErr      error     // nil when the API server answered
+}
+
+// TargetHealth is the result of probing a target.
+type TargetHealth struct {
+	Status  string   `json:"status"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// ProbeTarget derives a target's health from its worker liveness and the
+// reachability of its KubeContext. An unassigned or disconnected worker, or
+// an unreachable cluster, is an error; a stale heartbeat or a KubeContext
+// that cannot be checked from this machine is a warning.
+func ProbeTarget(p TargetProbe, now time.Time) TargetHealth {
+	h := TargetHealth{Status: TargetOK}
+	raise := func(status, reason string) {
+		if status == TargetError || h.Status == TargetOK {
+			h.Status = status
+		}
+		h.Reasons = append(h.Reasons, reason)
+	}
+
+	switch {
+	case p.WorkerSlug == "":
+		raise(TargetError, "no worker assigned")
+	case !p.WorkerFound:
+		raise(TargetError, fmt.Sprintf("worker %s not found", p.WorkerSlug))
+	case p.WorkerCondition != "Ready":
+		condition := p.WorkerCondition
+		if condition == "" {
+			condition = "unknown"
+		}
+		raise(TargetError, fmt.Sprintf("worker %s is %s", p.WorkerSlug, condition))
+	case !p.WorkerLastSeen.IsZero() && now.Sub(p.WorkerLastSeen) > WorkerStaleAfter:
+		raise(TargetWarn, fmt.Sprintf("worker %s last seen %s ago", p.WorkerSlug, now.Sub(p.WorkerLastSeen).Round(time.Second)))
+	}
+
+	if p.KubeContext != "" {
+		switch {
+		case !p.ContextChecked:
+			raise(TargetWarn, fmt.Sprintf("context %s not in local kubeconfig", p.KubeContext))
+		case p.ContextErr != nil:
+			raise(TargetError, fmt.Sprintf("context %s unreachable: %v", p.KubeContext, p.ContextErr))
+		}
+	}
+	return h
+}
+
+// Summary returns a one-line description for tree and table output.
+func (h TargetHealth) Summary() string {
+	if len(h.Reasons) == 0 {
+		return "healthy"
+	}
+	return h.Reasons[0]
+}
@@ -0,0 +1,56 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package hierarchysvc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProbeTarget(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ready := TargetProbe{WorkerSlug: "w1", WorkerFound: true, WorkerCondition: "Ready", WorkerLastSeen: now.Add(-time.Minute)}
+
+	tests := []struct {
+		name   string
+		probe  func(p TargetProbe) TargetProbe
+		status string
+		reason string
+	}{
+		{"healthy", func(p TargetProbe) TargetProbe { return p }, TargetOK, ""},
+		{"no worker", func(p TargetProbe) TargetProbe { return TargetProbe{} }, TargetError, "no worker assigned"},
+		{"worker missing", func(p TargetProbe) TargetProbe { p.WorkerFound = false; return p }, TargetError, "worker w1 not found"},
+		{"worker disconnected", func(p TargetProbe) TargetProbe { p.WorkerCondition = "Disconnected"; return p }, TargetError, "worker w1 is Disconnected"},
+		{"stale heartbeat", func(p TargetProbe) TargetProbe { p.WorkerLastSeen = now.Add(-10 * time.Minute); return p }, TargetWarn, "worker w1 last seen 10m0s ago"},
+		{"context not local", func(p TargetProbe) TargetProbe { p.KubeContext = "prod"; return p }, TargetWarn, "context prod not in local kubeconfig"},
+		{"context reachable", func(p TargetProbe) TargetProbe { p.KubeContext = "prod"; p.ContextChecked = true; return p }, TargetOK, ""},
+		{"context unreachable", func(p TargetProbe) TargetProbe {
+			p.KubeContext, p.ContextChecked, p.ContextErr = "prod", true, errors.New("timeout")
+			return p
+		}, TargetError, "context prod unreachable: timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := ProbeTarget(tt.probe(ready), now)
+			if h.Status != tt.status {
+				t.Errorf("status = %q, want %q (reasons %v)", h.Status, tt.status, h.Reasons)
+			}
+			if tt.reason != "" && h.Summary() != tt.reason {
+				t.Errorf("summary = %q, want %q", h.Summary(), tt.reason)
+			}
+		})
+	}
+}
+
+func TestProbeTargetErrorOutranksWarn(t *testing.T) {
+	now := time.Now()
+	h := ProbeTarget(TargetProbe{
+		WorkerSlug: "w1", WorkerFound: true, WorkerCondition: "Ready", WorkerLastSeen: now.Add(-time.Hour),
+		KubeContext: "prod", ContextChecked: true, ContextErr: errors.New("refused"),
+	}, now)
+	if h.Status != TargetError || len(h.Reasons) != 2 {
+		t.Errorf("got %+v, want error with both reasons", h)
+	}
+}
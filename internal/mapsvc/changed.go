@@ -0,0 +1,47 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package mapsvc
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// LastChanged returns the latest time the object is known to have changed:
+// the newest of its creationTimestamp, every managedFields write (spec
+// edits that bump metadata.generation, label changes, status updates) and
+// every status condition transition. Objects listed without managedFields
+// fall back to conditions and creation.
+func LastChanged(obj *unstructured.Unstructured) time.Time {
+	latest := obj.GetCreationTimestamp().Time
+	later := func(t time.Time) {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+
+	for _, mf := range obj.GetManagedFields() {
+		if mf.Time != nil {
+			later(mf.Time.Time)
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Deployments also stamp lastUpdateTime on every progress update
+		for _, field := range []string{"lastTransitionTime", "lastUpdateTime"} {
+			if v, ok := cond[field].(string); ok {
+				if t, err := time.Parse(time.RFC3339, v); err == nil {
+					later(t)
+				}
+			}
+		}
+	}
+	return latest
+}
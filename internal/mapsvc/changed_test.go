@@ -0,0 +1,47 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package mapsvc
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestLastChanged(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	edited := created.Add(48 * time.Hour)
+	transitioned := created.Add(72 * time.Hour)
+
+	newObj := func() *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "Deployment"}}
+		u.SetCreationTimestamp(metav1.NewTime(created))
+		return u
+	}
+
+	if got := LastChanged(newObj()); !got.Equal(created) {
+		t.Errorf("untouched: got %v, want creation %v", got, created)
+	}
+
+	withFields := newObj()
+	withFields.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{Manager: "kubectl-client-side-apply", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: created}},
+		{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: edited}},
+		{Manager: "no-time", Operation: metav1.ManagedFieldsOperationApply},
+	})
+	if got := LastChanged(withFields); !got.Equal(edited) {
+		t.Errorf("managedFields: got %v, want %v", got, edited)
+	}
+
+	withConditions := newObj()
+	_ = unstructured.SetNestedSlice(withConditions.Object, []interface{}{
+		map[string]interface{}{"type": "Available", "lastTransitionTime": transitioned.Format(time.RFC3339)},
+		map[string]interface{}{"type": "Progressing", "lastUpdateTime": "not-a-time"},
+	}, "status", "conditions")
+	if got := LastChanged(withConditions); !got.Equal(transitioned) {
+		t.Errorf("conditions: got %v, want %v", got, transitioned)
+	}
+}
@@ -4,6 +4,8 @@
 package mapsvc
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -18,8 +20,12 @@ const (
 
 // DetectStatus determines the status of a Kubernetes resource.
 // It examines conditions, phase, and other status fields to determine
-// whether a resource is ready, pending, failed, or unknown.
+// whether a resource is ready, pending, failed, or unknown. User status
+// rules (SetStatusRules) are tried first.
 func DetectStatus(obj *unstructured.Unstructured) string {
+	if status, ok := MatchStatusRule(obj); ok {
+		return status
+	}
 	kind := obj.GetKind()
 	status, _, _ := unstructured.NestedMap(obj.Object, "status")
 	if status == nil {
@@ -204,3 +210,58 @@ func GetConditionMessage(obj *unstructured.Unstructured) string {
 	}
 	return ""
 }
+
+// RolloutSettled reports whether a Deployment, StatefulSet or DaemonSet has
+// finished rolling out: the controller has observed the latest spec and every
+// replica is updated and ready. When it has not, reason says what is pending,
+// e.g. "2/3 updated". Other kinds, and paused Deployments, are settled.
+func RolloutSettled(obj *unstructured.Unstructured) (settled bool, reason string) {
+	observed, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if found && observed < obj.GetGeneration() {
+		return false, "new spec not yet observed"
+	}
+
+	desired, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		desired = 1
+	}
+	replicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "replicas")
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+
+	switch obj.GetKind() {
+	case "Deployment":
+		if paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused"); paused {
+			return true, ""
+		}
+		switch {
+		case updated < desired:
+			return false, fmt.Sprintf("%d/%d updated", updated, desired)
+		case replicas > desired:
+			return false, fmt.Sprintf("%d old replica(s) terminating", replicas-desired)
+		case ready < desired:
+			return false, fmt.Sprintf("%d/%d ready", ready, desired)
+		}
+	case "StatefulSet":
+		current, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+		update, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+		strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+		switch {
+		case strategy != "OnDelete" && update != "" && current != update:
+			return false, fmt.Sprintf("%d/%d updated", updated, desired)
+		case ready < desired:
+			return false, fmt.Sprintf("%d/%d ready", ready, desired)
+		}
+	case "DaemonSet":
+		scheduled, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		updatedNodes, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedNumberScheduled")
+		readyNodes, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberReady")
+		switch {
+		case updatedNodes < scheduled:
+			return false, fmt.Sprintf("%d/%d nodes updated", updatedNodes, scheduled)
+		case readyNodes < scheduled:
+			return false, fmt.Sprintf("%d/%d nodes ready", readyNodes, scheduled)
+		}
+	}
+	return true, ""
+}
@@ -0,0 +1,178 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package mapsvc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// StatusRules are user-supplied status checks for kinds the built-in
+// heuristics don't know, typically CRDs, e.g.:
+//
+//	rules:
+//	  - kind: PostgresCluster
+//	    group: postgres-operator.crunchydata.com
+//	    checks:
+//	      - status: Failed
+//	        path: '{.status.conditions[?(@.type=="Degraded")].status}'
+//	        equals: "True"
+//	      - status: Ready
+//	        path: '{.status.phase}'
+//	        in: [Running, Healthy]
+//	      - status: Pending
+//	        path: '{.status.phase}'
+//
+// Checks are tried in order and the first match sets the status. A check
+// without equals or in matches when the path yields any non-empty value.
+type StatusRules struct {
+	Rules []StatusRule `yaml:"rules"`
+}
+
+// StatusRule holds the checks for one kind. Group, when set, must match the
+// object's API group.
+type StatusRule struct {
+	Kind   string        `yaml:"kind"`
+	Group  string        `yaml:"group,omitempty"`
+	Checks []StatusCheck `yaml:"checks"`
+}
+
+// StatusCheck sets Status when the JSONPath expression Path matches.
+type StatusCheck struct {
+	Status string   `yaml:"status"`
+	Path   string   `yaml:"path"`
+	Equals string   `yaml:"equals,omitempty"`
+	In     []string `yaml:"in,omitempty"`
+
+	parsed *jsonpath.JSONPath
+}
+
+// LoadStatusRules reads and validates a rules file. A missing file yields
+// no rules.
+func LoadStatusRules(file string) (StatusRules, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return StatusRules{}, nil
+		}
+		return StatusRules{}, err
+	}
+	rules, err := ParseStatusRules(data)
+	if err != nil {
+		return StatusRules{}, fmt.Errorf("parse %s: %w", file, err)
+	}
+	return rules, nil
+}
+
+// ParseStatusRules parses and validates rules from YAML, compiling each
+// check's JSONPath.
+func ParseStatusRules(data []byte) (StatusRules, error) {
+	var rules StatusRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return StatusRules{}, err
+	}
+	for i := range rules.Rules {
+		r := &rules.Rules[i]
+		if r.Kind == "" {
+			return StatusRules{}, fmt.Errorf("rule %d: kind is required", i+1)
+		}
+		for j := range r.Checks {
+			c := &r.Checks[j]
+			switch c.Status {
+			case StatusReady, StatusNotReady, StatusFailed, StatusPending, StatusUnknown:
+			default:
+				return StatusRules{}, fmt.Errorf("%s check %d: status %q is not one of Ready, NotReady, Failed, Pending, Unknown", r.Kind, j+1, c.Status)
+			}
+			if c.Equals != "" && len(c.In) > 0 {
+				return StatusRules{}, fmt.Errorf("%s check %d: set equals or in, not both", r.Kind, j+1)
+			}
+			expr := strings.TrimSpace(c.Path)
+			if !strings.HasPrefix(expr, "{") {
+				expr = "{" + expr + "}"
+			}
+			jp := jsonpath.New(r.Kind).AllowMissingKeys(true)
+			if err := jp.Parse(expr); err != nil {
+				return StatusRules{}, fmt.Errorf("%s check %d: path %q: %w", r.Kind, j+1, c.Path, err)
+			}
+			c.parsed = jp
+		}
+	}
+	return rules, nil
+}
+
+// Match returns the status the first matching check of obj's rule gives,
+// and false when no rule applies or no check matches.
+func (s StatusRules) Match(obj *unstructured.Unstructured) (string, bool) {
+	gvk := obj.GroupVersionKind()
+	for _, r := range s.Rules {
+		if r.Kind != gvk.Kind || (r.Group != "" && r.Group != gvk.Group) {
+			continue
+		}
+		for _, c := range r.Checks {
+			if c.matches(obj) {
+				return c.Status, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (c StatusCheck) matches(obj *unstructured.Unstructured) bool {
+	if c.parsed == nil {
+		return false
+	}
+	results, err := c.parsed.FindResults(obj.Object)
+	if err != nil {
+		return false
+	}
+	// A path may yield several values (a filter, [*]); any one may match
+	var values []string
+	for _, set := range results {
+		for _, v := range set {
+			if s := fmt.Sprint(v.Interface()); s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	switch {
+	case c.Equals != "":
+		return contains(values, c.Equals)
+	case len(c.In) > 0:
+		for _, want := range c.In {
+			if contains(values, want) {
+				return true
+			}
+		}
+		return false
+	}
+	return len(values) > 0
+}
+
+func contains(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
+
+// statusRules are the rules DetectStatus applies before its built-in
+// checks, set once per process with SetStatusRules.
+var statusRules StatusRules
+
+// SetStatusRules sets the user rules DetectStatus tries first.
+func SetStatusRules(rules StatusRules) {
+	statusRules = rules
+}
+
+// MatchStatusRule applies the rules set with SetStatusRules to obj.
+func MatchStatusRule(obj *unstructured.Unstructured) (string, bool) {
+	return statusRules.Match(obj)
+}
@@ -0,0 +1,124 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package mapsvc
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testStatusRules = `
+rules:
+  - kind: PostgresCluster
+    group: postgres-operator.crunchydata.com
+    checks:
+      - status: Failed
+        path: '{.status.conditions[?(@.type=="Degraded")].status}'
+        equals: "True"
+      - status: Ready
+        path: .status.phase
+        in: [Running, Healthy]
+      - status: Pending
+        path: .status.phase
+`
+
+func postgresCluster(group string, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": group + "/v1beta1",
+		"kind":       "PostgresCluster",
+		"metadata":   map[string]interface{}{"name": "db", "namespace": "data"},
+		"status":     status,
+	}}
+}
+
+func TestStatusRulesMatch(t *testing.T) {
+	rules, err := ParseStatusRules([]byte(testStatusRules))
+	if err != nil {
+		t.Fatalf("ParseStatusRules: %v", err)
+	}
+	const group = "postgres-operator.crunchydata.com"
+
+	tests := []struct {
+		name   string
+		obj    *unstructured.Unstructured
+		want   string
+		wantOK bool
+	}{
+		{
+			name: "degraded condition wins over phase",
+			obj: postgresCluster(group, map[string]interface{}{
+				"phase": "Running",
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Progressing", "status": "True"},
+					map[string]interface{}{"type": "Degraded", "status": "True"},
+				},
+			}),
+			want: StatusFailed, wantOK: true,
+		},
+		{
+			name: "phase in list",
+			obj:  postgresCluster(group, map[string]interface{}{"phase": "Healthy"}),
+			want: StatusReady, wantOK: true,
+		},
+		{
+			name: "any phase is pending",
+			obj:  postgresCluster(group, map[string]interface{}{"phase": "Bootstrapping"}),
+			want: StatusPending, wantOK: true,
+		},
+		{
+			name:   "no check matches",
+			obj:    postgresCluster(group, map[string]interface{}{}),
+			wantOK: false,
+		},
+		{
+			name:   "other group",
+			obj:    postgresCluster("example.com", map[string]interface{}{"phase": "Running"}),
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rules.Match(tt.obj)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Match() = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseStatusRulesErrors(t *testing.T) {
+	tests := []struct {
+		name, yaml, wantErr string
+	}{
+		{"missing kind", "rules: [{checks: [{status: Ready, path: .status.ok}]}]", "kind is required"},
+		{"bad status", "rules: [{kind: Foo, checks: [{status: Healthy, path: .status.ok}]}]", `status "Healthy"`},
+		{"equals and in", "rules: [{kind: Foo, checks: [{status: Ready, path: .status.ok, equals: x, in: [y]}]}]", "not both"},
+		{"bad path", "rules: [{kind: Foo, checks: [{status: Ready, path: '{.status[}'}]}]", "path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseStatusRules([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseStatusRules() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDetectStatusUsesRules(t *testing.T) {
+	rules, err := ParseStatusRules([]byte(testStatusRules))
+	if err != nil {
+		t.Fatalf("ParseStatusRules: %v", err)
+	}
+	SetStatusRules(rules)
+	defer SetStatusRules(StatusRules{})
+
+	obj := postgresCluster("postgres-operator.crunchydata.com", map[string]interface{}{"phase": "Running"})
+	if got := DetectStatus(obj); got != StatusReady {
+		t.Errorf("DetectStatus() = %q, want %q", got, StatusReady)
+	}
+}
@@ -193,6 +193,54 @@ func TestDetectStatus(t *testing.T) {
 	}
 }
 
+func TestRolloutSettled(t *testing.T) {
+	workload := func(kind string, generation int64, spec, status map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{"kind": kind, "spec": spec, "status": status}}
+		u.SetGeneration(generation)
+		return u
+	}
+
+	tests := []struct {
+		name    string
+		obj     *unstructured.Unstructured
+		settled bool
+		reason  string
+	}{
+		{"deployment rolled out", workload("Deployment", 2,
+			map[string]interface{}{"replicas": int64(3)},
+			map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3), "readyReplicas": int64(3)}), true, ""},
+		{"spec not observed", workload("Deployment", 3,
+			map[string]interface{}{"replicas": int64(3)},
+			map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3), "readyReplicas": int64(3)}), false, "new spec not yet observed"},
+		{"deployment updating", workload("Deployment", 2,
+			map[string]interface{}{"replicas": int64(3)},
+			map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(4), "updatedReplicas": int64(2), "readyReplicas": int64(3)}), false, "2/3 updated"},
+		{"old replicas terminating", workload("Deployment", 2,
+			map[string]interface{}{"replicas": int64(3)},
+			map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(4), "updatedReplicas": int64(3), "readyReplicas": int64(3)}), false, "1 old replica(s) terminating"},
+		{"paused deployment", workload("Deployment", 2,
+			map[string]interface{}{"replicas": int64(3), "paused": true},
+			map[string]interface{}{"observedGeneration": int64(2), "updatedReplicas": int64(1)}), true, ""},
+		{"statefulset revision pending", workload("StatefulSet", 1,
+			map[string]interface{}{"replicas": int64(2)},
+			map[string]interface{}{"readyReplicas": int64(2), "updatedReplicas": int64(1), "currentRevision": "db-1", "updateRevision": "db-2"}), false, "1/2 updated"},
+		{"statefulset OnDelete", workload("StatefulSet", 1,
+			map[string]interface{}{"replicas": int64(2), "updateStrategy": map[string]interface{}{"type": "OnDelete"}},
+			map[string]interface{}{"readyReplicas": int64(2), "currentRevision": "db-1", "updateRevision": "db-2"}), true, ""},
+		{"daemonset not ready", workload("DaemonSet", 1, nil,
+			map[string]interface{}{"desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(3), "numberReady": int64(2)}), false, "2/3 nodes ready"},
+		{"other kinds", workload("ConfigMap", 0, nil, nil), true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settled, reason := RolloutSettled(tt.obj)
+			if settled != tt.settled || reason != tt.reason {
+				t.Errorf("RolloutSettled = (%v, %q), want (%v, %q)", settled, reason, tt.settled, tt.reason)
+			}
+		})
+	}
+}
+
 func TestDisplayOwner(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -240,6 +288,7 @@ func TestEntryGetField(t *testing.T) {
 			"app": "nginx",
 			"env": "prod",
 		},
+		Contested: []string{"Flux", "ArgoCD"},
 	}
 
 	tests := []struct {
@@ -254,6 +303,7 @@ func TestEntryGetField(t *testing.T) {
 		{"status", "Ready", true},
 		{"cluster", "prod", true},
 		{"clusterName", "prod", true},
+		{"contested", "true", true},
 		{"labels[app]", "nginx", true},
 		{"labels[env]", "prod", true},
 		{"labels[missing]", "", false},
@@ -6,6 +6,7 @@
 package mapsvc
 
 import (
+	"strconv"
 	"strings"
 	"time"
 )
@@ -23,8 +24,11 @@ type Entry struct {
 	OwnerDetails map[string]string `json:"ownerDetails,omitempty"`
 	Labels       map[string]string `json:"labels,omitempty"`
 	Status       string            `json:"status"` // Ready, NotReady, Failed, Pending, Unknown
-	CreatedAt    time.Time         `json:"createdAt"`
-	UpdatedAt    time.Time         `json:"updatedAt"`
+	// Contested lists every manager claiming the resource when more than
+	// one does (e.g. ArgoCD and ConfigHub after a migration)
+	Contested []string  `json:"contested,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 // GetField implements query.Matchable for Entry.
@@ -54,6 +58,8 @@ func (e Entry) GetField(field string) (string, bool) {
 		return e.ClusterName, true
 	case "apiVersion":
 		return e.APIVersion, true
+	case "contested":
+		return strconv.FormatBool(len(e.Contested) > 0), true
 	default:
 		return "", false
 	}
@@ -0,0 +1,587 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package agenttest provides canonical fixtures for testing code built on
+// pkg/agent: workloads carrying the labels and annotations Flux, Argo CD,
+// Helm, Terraform and ConfigHub leave on what they deploy, the deployer
+// objects themselves, and a fake dynamic client that can list them.
+//
+//	dep := agenttest.Deployment("payments", "api", agenttest.ManagedByFluxKustomization("apps", "flux-system"))
+//	ks := agenttest.FluxKustomization("flux-system", "apps")
+//	scanner := agent.NewStateScannerWithClient(agenttest.FakeClient(dep, ks))
+//
+// The markers match what pkg/agent detects, so rule authors and detector
+// plugins can test against the same fixtures as cub-scout itself.
+package agenttest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// Option customizes a fixture.
+type Option func(*unstructured.Unstructured)
+
+// Object returns an object of the given apiVersion and kind. A namespace of
+// "" makes it cluster-scoped.
+func Object(apiVersion, kind, namespace, name string, opts ...Option) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+	}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetUID(types.UID(namespace + "/" + kind + "/" + name))
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// Workload returns a ready apps/v1 workload (Deployment, StatefulSet,
+// DaemonSet or ReplicaSet) with one replica, selecting app=<name>.
+func Workload(kind, namespace, name string, opts ...Option) *unstructured.Unstructured {
+	labels := map[string]interface{}{"app": name}
+	u := Object("apps/v1", kind, namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": labels},
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": labels},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": name, "image": name + ":latest"},
+				},
+			},
+		},
+	}
+	if kind != "DaemonSet" {
+		_ = unstructured.SetNestedField(u.Object, int64(1), "spec", "replicas")
+	}
+	setWorkloadReady(u, true)
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// Deployment returns a ready Deployment; see Workload.
+func Deployment(namespace, name string, opts ...Option) *unstructured.Unstructured {
+	return Workload("Deployment", namespace, name, opts...)
+}
+
+// FluxKustomization returns a ready Flux Kustomization applying ./<name>
+// from the flux-system GitRepository.
+func FluxKustomization(namespace, name string, opts ...Option) *unstructured.Unstructured {
+	u := Object("kustomize.toolkit.fluxcd.io/v1", "Kustomization", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"interval":  "10m",
+		"path":      "./" + name,
+		"prune":     true,
+		"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "flux-system"},
+	}
+	setCondition(u, "Ready", "True", "ReconciliationSucceeded", "Applied revision: main@sha1:0123456789abcdef")
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// FluxHelmRelease returns a ready Flux HelmRelease installing chart <name>
+// from a HelmRepository of the same name.
+func FluxHelmRelease(namespace, name string, opts ...Option) *unstructured.Unstructured {
+	u := Object("helm.toolkit.fluxcd.io/v2", "HelmRelease", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"interval": "10m",
+		"chart": map[string]interface{}{"spec": map[string]interface{}{
+			"chart":     name,
+			"sourceRef": map[string]interface{}{"kind": "HelmRepository", "name": name},
+		}},
+	}
+	setCondition(u, "Ready", "True", "InstallSucceeded", "Helm install succeeded")
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// FluxGitRepository returns a ready Flux GitRepository tracking main.
+func FluxGitRepository(namespace, name, url string, opts ...Option) *unstructured.Unstructured {
+	u := Object("source.toolkit.fluxcd.io/v1", "GitRepository", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"interval": "1m",
+		"url":      url,
+		"ref":      map[string]interface{}{"branch": "main"},
+	}
+	setCondition(u, "Ready", "True", "Succeeded", "stored artifact for revision 'main@sha1:0123456789abcdef'")
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// FluxHelmRepository returns a ready Flux HelmRepository serving an index
+// from url.
+func FluxHelmRepository(namespace, name, url string, opts ...Option) *unstructured.Unstructured {
+	u := Object("source.toolkit.fluxcd.io/v1", "HelmRepository", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"interval": "10m",
+		"url":      url,
+	}
+	setCondition(u, "Ready", "True", "Succeeded", "stored artifact: revision 'sha256:0123456789abcdef'")
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// FluxBucket returns a ready Flux Bucket reading bucketName from an S3
+// endpoint.
+func FluxBucket(namespace, name, bucketName string, opts ...Option) *unstructured.Unstructured {
+	u := Object("source.toolkit.fluxcd.io/v1", "Bucket", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"interval":   "5m",
+		"provider":   "aws",
+		"endpoint":   "s3.amazonaws.com",
+		"bucketName": bucketName,
+	}
+	setCondition(u, "Ready", "True", "Succeeded", "stored artifact: revision 'sha256:0123456789abcdef'")
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// FluxProvider returns a Flux notification Provider of type typ (slack,
+// msteams, github, ...).
+func FluxProvider(namespace, name, typ string, opts ...Option) *unstructured.Unstructured {
+	u := Object("notification.toolkit.fluxcd.io/v1beta3", "Provider", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"type":    typ,
+		"channel": "alerts",
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// FluxAlert returns a Flux Alert forwarding error events from sources to
+// provider. Each source is "Kind/name" or "Kind/namespace/name"; a name of
+// "*" selects every object of the kind.
+func FluxAlert(namespace, name, provider string, sources []string, opts ...Option) *unstructured.Unstructured {
+	u := Object("notification.toolkit.fluxcd.io/v1beta3", "Alert", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"providerRef":   map[string]interface{}{"name": provider},
+		"eventSeverity": "error",
+		"eventSources":  fluxObjectRefs(sources),
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// FluxReceiver returns a ready Flux Receiver of type typ (github, gitlab,
+// generic, ...) triggering resources, given as for FluxAlert.
+func FluxReceiver(namespace, name, typ string, resources []string, opts ...Option) *unstructured.Unstructured {
+	u := Object("notification.toolkit.fluxcd.io/v1", "Receiver", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"type":      typ,
+		"resources": fluxObjectRefs(resources),
+		"secretRef": map[string]interface{}{"name": name + "-token"},
+	}
+	setCondition(u, "Ready", "True", "Succeeded", "Receiver initialized for path: /hook/0123456789abcdef")
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// fluxObjectRefs turns "Kind/name" and "Kind/namespace/name" into Flux
+// cross-namespace object references.
+func fluxObjectRefs(refs []string) []interface{} {
+	out := make([]interface{}, 0, len(refs))
+	for _, ref := range refs {
+		parts := strings.SplitN(ref, "/", 3)
+		m := map[string]interface{}{"kind": parts[0], "name": parts[len(parts)-1]}
+		if len(parts) == 3 {
+			m["namespace"] = parts[1]
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// ArgoApplication returns a synced, healthy Argo CD Application deploying
+// path <name> to destNamespace.
+func ArgoApplication(namespace, name, destNamespace string, opts ...Option) *unstructured.Unstructured {
+	u := Object("argoproj.io/v1alpha1", "Application", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"project": "default",
+		"source": map[string]interface{}{
+			"repoURL":        "https://github.com/example/apps.git",
+			"path":           name,
+			"targetRevision": "HEAD",
+		},
+		"destination": map[string]interface{}{
+			"server":    "https://kubernetes.default.svc",
+			"namespace": destNamespace,
+		},
+	}
+	u.Object["status"] = map[string]interface{}{
+		"sync":   map[string]interface{}{"status": "Synced", "revision": "0123456789abcdef"},
+		"health": map[string]interface{}{"status": "Healthy"},
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// ArgoAppProject returns an Argo CD AppProject allowing every source and
+// destination.
+func ArgoAppProject(namespace, name string, opts ...Option) *unstructured.Unstructured {
+	u := Object("argoproj.io/v1alpha1", "AppProject", namespace, name)
+	u.Object["spec"] = map[string]interface{}{
+		"sourceRepos":  []interface{}{"*"},
+		"destinations": []interface{}{map[string]interface{}{"server": "*", "namespace": "*"}},
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+// WithLabels adds labels.
+func WithLabels(labels map[string]string) Option {
+	return func(u *unstructured.Unstructured) {
+		merged := u.GetLabels()
+		if merged == nil {
+			merged = map[string]string{}
+		}
+		for k, v := range labels {
+			merged[k] = v
+		}
+		u.SetLabels(merged)
+	}
+}
+
+// WithAnnotations adds annotations.
+func WithAnnotations(annotations map[string]string) Option {
+	return func(u *unstructured.Unstructured) {
+		merged := u.GetAnnotations()
+		if merged == nil {
+			merged = map[string]string{}
+		}
+		for k, v := range annotations {
+			merged[k] = v
+		}
+		u.SetAnnotations(merged)
+	}
+}
+
+// WithReplicas sets a workload's replicas, keeping it ready or not ready.
+func WithReplicas(n int64) Option {
+	return func(u *unstructured.Unstructured) {
+		ready := workloadReady(u)
+		_ = unstructured.SetNestedField(u.Object, n, "spec", "replicas")
+		setWorkloadReady(u, ready)
+	}
+}
+
+// WithOwnerReference adds a controller owner reference.
+func WithOwnerReference(apiVersion, kind, name string) Option {
+	return func(u *unstructured.Unstructured) {
+		controller := true
+		u.SetOwnerReferences(append(u.GetOwnerReferences(), metav1.OwnerReference{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Name:       name,
+			UID:        types.UID(u.GetNamespace() + "/" + kind + "/" + name),
+			Controller: &controller,
+		}))
+	}
+}
+
+// NotReady marks a fixture as failing: a workload has no ready replicas, a
+// Flux object has Ready=False, an Argo CD Application is OutOfSync and
+// Degraded.
+func NotReady(reason, message string) Option {
+	return func(u *unstructured.Unstructured) {
+		switch {
+		case u.GetAPIVersion() == "apps/v1":
+			setWorkloadReady(u, false)
+		case u.GetKind() == "Application" && strings.HasPrefix(u.GetAPIVersion(), "argoproj.io/"):
+			_ = unstructured.SetNestedField(u.Object, "OutOfSync", "status", "sync", "status")
+			_ = unstructured.SetNestedField(u.Object, "Degraded", "status", "health", "status")
+			_ = unstructured.SetNestedField(u.Object, message, "status", "health", "message")
+		default:
+			setCondition(u, "Ready", "False", reason, message)
+		}
+	}
+}
+
+// Suspended suspends a Flux object.
+func Suspended() Option {
+	return func(u *unstructured.Unstructured) {
+		_ = unstructured.SetNestedField(u.Object, true, "spec", "suspend")
+	}
+}
+
+// ArgoAutomatedSync sets an Argo CD Application's automated sync policy.
+func ArgoAutomatedSync(prune, selfHeal bool) Option {
+	return func(u *unstructured.Unstructured) {
+		_ = unstructured.SetNestedMap(u.Object, map[string]interface{}{"prune": prune, "selfHeal": selfHeal}, "spec", "syncPolicy", "automated")
+	}
+}
+
+// ManagedByFluxKustomization marks an object as applied by a Flux
+// Kustomization.
+func ManagedByFluxKustomization(name, namespace string) Option {
+	return WithLabels(map[string]string{
+		"kustomize.toolkit.fluxcd.io/name":      name,
+		"kustomize.toolkit.fluxcd.io/namespace": namespace,
+	})
+}
+
+// ManagedByFluxHelmRelease marks an object as installed by a Flux
+// HelmRelease, which also leaves Helm's own markers.
+func ManagedByFluxHelmRelease(name, namespace string) Option {
+	return func(u *unstructured.Unstructured) {
+		ManagedByHelm(name, namespace, name+"-1.0.0")(u)
+		WithLabels(map[string]string{
+			"helm.toolkit.fluxcd.io/name":      name,
+			"helm.toolkit.fluxcd.io/namespace": namespace,
+		})(u)
+	}
+}
+
+// ManagedByArgo marks an object as synced by an Argo CD Application using
+// label tracking.
+func ManagedByArgo(app string) Option {
+	return WithLabels(map[string]string{"argocd.argoproj.io/instance": app})
+}
+
+// ManagedByArgoTrackingID marks an object as synced by an Argo CD
+// Application using annotation tracking.
+func ManagedByArgoTrackingID(app string) Option {
+	return func(u *unstructured.Unstructured) {
+		gvk := u.GroupVersionKind()
+		id := fmt.Sprintf("%s:%s/%s:%s/%s", app, gvk.Group, gvk.Kind, u.GetNamespace(), u.GetName())
+		WithAnnotations(map[string]string{"argocd.argoproj.io/tracking-id": id})(u)
+	}
+}
+
+// ManagedByHelm marks an object as installed by a Helm release.
+func ManagedByHelm(release, namespace, chart string) Option {
+	return func(u *unstructured.Unstructured) {
+		WithLabels(map[string]string{
+			"app.kubernetes.io/managed-by": "Helm",
+			"app.kubernetes.io/instance":   release,
+			"helm.sh/chart":                chart,
+		})(u)
+		WithAnnotations(map[string]string{
+			"meta.helm.sh/release-name":      release,
+			"meta.helm.sh/release-namespace": namespace,
+		})(u)
+	}
+}
+
+// ManagedByTerraform marks an object as applied by a Terraform workspace.
+func ManagedByTerraform(workspace string) Option {
+	return WithAnnotations(map[string]string{
+		"app.terraform.io/run-id":         "run-0123456789",
+		"app.terraform.io/workspace-name": workspace,
+	})
+}
+
+// ManagedByConfigHub marks an object as applied from a ConfigHub unit at a
+// revision.
+func ManagedByConfigHub(space, unit string, revision int) Option {
+	return func(u *unstructured.Unstructured) {
+		WithLabels(map[string]string{"confighub.com/UnitSlug": unit})(u)
+		WithAnnotations(map[string]string{
+			"confighub.com/SpaceName":   space,
+			"confighub.com/RevisionNum": strconv.Itoa(revision),
+		})(u)
+	}
+}
+
+// ListKinds maps every resource pkg/agent lists to its list kind, as a fake
+// dynamic client needs.
+var ListKinds = map[schema.GroupVersionResource]string{
+	// Core resources
+	{Group: "", Version: "v1", Resource: "services"}:               "ServiceList",
+	{Group: "", Version: "v1", Resource: "pods"}:                   "PodList",
+	{Group: "", Version: "v1", Resource: "secrets"}:                "SecretList",
+	{Group: "", Version: "v1", Resource: "configmaps"}:             "ConfigMapList",
+	{Group: "", Version: "v1", Resource: "namespaces"}:             "NamespaceList",
+	{Group: "", Version: "v1", Resource: "persistentvolumeclaims"}: "PersistentVolumeClaimList",
+	{Group: "", Version: "v1", Resource: "replicationcontrollers"}: "ReplicationControllerList",
+	{Group: "", Version: "v1", Resource: "serviceaccounts"}:        "ServiceAccountList",
+
+	// Apps resources
+	{Group: "apps", Version: "v1", Resource: "deployments"}:  "DeploymentList",
+	{Group: "apps", Version: "v1", Resource: "replicasets"}:  "ReplicaSetList",
+	{Group: "apps", Version: "v1", Resource: "statefulsets"}: "StatefulSetList",
+	{Group: "apps", Version: "v1", Resource: "daemonsets"}:   "DaemonSetList",
+
+	// Autoscaling
+	{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}:      "HorizontalPodAutoscalerList",
+	{Group: "autoscaling", Version: "v1", Resource: "horizontalpodautoscalers"}:      "HorizontalPodAutoscalerList",
+	{Group: "autoscaling.k8s.io", Version: "v1", Resource: "verticalpodautoscalers"}: "VerticalPodAutoscalerList",
+
+	// Networking
+	{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}:       "IngressList",
+	{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}: "NetworkPolicyList",
+
+	// Policy
+	{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}: "PodDisruptionBudgetList",
+
+	// Batch
+	{Group: "batch", Version: "v1", Resource: "jobs"}:     "JobList",
+	{Group: "batch", Version: "v1", Resource: "cronjobs"}: "CronJobList",
+
+	// RBAC
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}:               "RoleList",
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}:        "RoleBindingList",
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}:        "ClusterRoleList",
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}: "ClusterRoleBindingList",
+
+	// Flux resources
+	{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}:            "HelmReleaseList",
+	{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}:     "KustomizationList",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories"}:       "GitRepositoryList",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "gitrepositories"}:  "GitRepositoryList",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "helmrepositories"}:      "HelmRepositoryList",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "helmrepositories"}: "HelmRepositoryList",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "helmcharts"}:            "HelmChartList",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "helmcharts"}:       "HelmChartList",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "buckets"}:               "BucketList",
+	{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "buckets"}:          "BucketList",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta3", Resource: "alerts"}:     "AlertList",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1beta3", Resource: "providers"}:  "ProviderList",
+	{Group: "notification.toolkit.fluxcd.io", Version: "v1", Resource: "receivers"}:       "ReceiverList",
+
+	// Argo CD resources
+	{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}:    "ApplicationList",
+	{Group: "argoproj.io", Version: "v1alpha1", Resource: "applicationsets"}: "ApplicationSetList",
+	{Group: "argoproj.io", Version: "v1alpha1", Resource: "appprojects"}:     "AppProjectList",
+
+	// Crossplane
+	{Group: "apiextensions.crossplane.io", Version: "v1", Resource: "compositeresourcedefinitions"}: "CompositeResourceDefinitionList",
+	{Group: "apiextensions.crossplane.io", Version: "v1", Resource: "compositionrevisions"}:         "CompositionRevisionList",
+
+	// Certificates
+	{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}: "CertificateList",
+	{Group: "cert-manager.io", Version: "v1", Resource: "issuers"}:      "IssuerList",
+}
+
+// Cluster returns n objects spread over 50 namespaces, the shape of a busy
+// shared cluster for benchmarks: Deployments, Services and ConfigMaps in
+// turn, managed by Flux, Argo CD, Helm, ConfigHub, Terraform or nothing,
+// with every seventh workload not ready. The same n always gives the same
+// objects.
+func Cluster(n int) []*unstructured.Unstructured {
+	owners := []func(i int) Option{
+		func(i int) Option { return ManagedByFluxKustomization(fmt.Sprintf("apps-%d", i%20), "flux-system") },
+		func(i int) Option { return ManagedByArgo(fmt.Sprintf("app-%d", i%20)) },
+		func(i int) Option { return ManagedByHelm(fmt.Sprintf("release-%d", i%20), "default", "chart-1.0.0") },
+		func(i int) Option { return ManagedByConfigHub("prod", fmt.Sprintf("unit-%d", i%20), i%9+1) },
+		func(i int) Option { return ManagedByTerraform(fmt.Sprintf("ws-%d", i%5)) },
+		func(i int) Option { return WithLabels(map[string]string{"app": fmt.Sprintf("app-%d", i%20)}) },
+	}
+	objs := make([]*unstructured.Unstructured, 0, n)
+	for i := 0; i < n; i++ {
+		namespace := fmt.Sprintf("team-%02d", i%50)
+		name := fmt.Sprintf("obj-%05d", i)
+		owner := owners[i%len(owners)](i)
+		switch i % 3 {
+		case 0:
+			opts := []Option{owner}
+			if i%7 == 0 {
+				opts = append(opts, NotReady("ProgressDeadlineExceeded", "deployment exceeded its progress deadline"))
+			}
+			objs = append(objs, Deployment(namespace, name, opts...))
+		case 1:
+			objs = append(objs, Object("v1", "Service", namespace, name, owner))
+		default:
+			objs = append(objs, Object("v1", "ConfigMap", namespace, name, owner))
+		}
+	}
+	return objs
+}
+
+// FakeClient returns a fake dynamic client serving objs, able to list every
+// resource in ListKinds.
+func FakeClient(objs ...*unstructured.Unstructured) *dynamicfake.FakeDynamicClient {
+	runtimeObjs := make([]runtime.Object, len(objs))
+	for i, obj := range objs {
+		runtimeObjs[i] = obj
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), ListKinds, runtimeObjs...)
+}
+
+func workloadReady(u *unstructured.Unstructured) bool {
+	replicas, _, _ := unstructured.NestedInt64(u.Object, "status", "replicas")
+	ready, _, _ := unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+	if u.GetKind() == "DaemonSet" {
+		replicas, _, _ = unstructured.NestedInt64(u.Object, "status", "desiredNumberScheduled")
+		ready, _, _ = unstructured.NestedInt64(u.Object, "status", "numberReady")
+	}
+	return ready == replicas
+}
+
+func setWorkloadReady(u *unstructured.Unstructured, ready bool) {
+	replicas, found, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	readyReplicas := replicas
+	if !ready {
+		readyReplicas = 0
+	}
+	status := map[string]interface{}{
+		"replicas":          replicas,
+		"readyReplicas":     readyReplicas,
+		"availableReplicas": readyReplicas,
+	}
+	if u.GetKind() == "DaemonSet" {
+		status = map[string]interface{}{
+			"desiredNumberScheduled": replicas,
+			"numberReady":            readyReplicas,
+			"numberAvailable":        readyReplicas,
+		}
+	}
+	u.Object["status"] = status
+}
+
+func setCondition(u *unstructured.Unstructured, condType, status, reason, message string) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	cond := map[string]interface{}{
+		"type":               condType,
+		"status":             status,
+		"reason":             reason,
+		"message":            message,
+		"lastTransitionTime": "2025-01-01T00:00:00Z",
+	}
+	replaced := false
+	for i, c := range conditions {
+		if m, ok := c.(map[string]interface{}); ok && m["type"] == condType {
+			conditions[i] = cond
+			replaced = true
+		}
+	}
+	if !replaced {
+		conditions = append(conditions, cond)
+	}
+	_ = unstructured.SetNestedSlice(u.Object, conditions, "status", "conditions")
+}
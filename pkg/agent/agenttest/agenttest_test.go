@@ -0,0 +1,95 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agenttest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/confighub/cub-scout/pkg/agent"
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TestMarkersMatchDetection pins each marker option to the owner pkg/agent
+// detects, so the fixtures stay canonical as detection evolves.
+func TestMarkersMatchDetection(t *testing.T) {
+	tests := []struct {
+		opt      agenttest.Option
+		wantType string
+		wantName string
+	}{
+		{agenttest.ManagedByFluxKustomization("apps", "flux-system"), agent.OwnerFlux, "apps"},
+		{agenttest.ManagedByFluxHelmRelease("podinfo", "flux-system"), agent.OwnerFlux, "podinfo"},
+		{agenttest.ManagedByArgo("guestbook"), agent.OwnerArgo, "guestbook"},
+		{agenttest.ManagedByArgoTrackingID("guestbook"), agent.OwnerArgo, "guestbook"},
+		{agenttest.ManagedByHelm("redis", "cache", "redis-18.0.0"), agent.OwnerHelm, "redis"},
+		{agenttest.ManagedByTerraform("platform"), agent.OwnerTerraform, "platform"},
+		{agenttest.ManagedByConfigHub("prod", "checkout", 7), agent.OwnerConfigHub, "checkout"},
+		{agenttest.WithLabels(nil), agent.OwnerUnknown, ""},
+	}
+	for _, tt := range tests {
+		dep := agenttest.Deployment("payments", "api", tt.opt)
+		got := agent.DetectOwnership(dep)
+		if got.Type != tt.wantType || got.Name != tt.wantName {
+			t.Errorf("%v: detected %s/%s, want %s/%s", dep.GetLabels(), got.Type, got.Name, tt.wantType, tt.wantName)
+		}
+	}
+}
+
+func TestWorkloadReadiness(t *testing.T) {
+	dep := agenttest.Deployment("payments", "api", agenttest.WithReplicas(3))
+	if ready := dep.Object["status"].(map[string]interface{})["readyReplicas"]; ready != int64(3) {
+		t.Errorf("readyReplicas = %v, want 3", ready)
+	}
+	dep = agenttest.Deployment("payments", "api", agenttest.NotReady("", ""), agenttest.WithReplicas(3))
+	if ready := dep.Object["status"].(map[string]interface{})["readyReplicas"]; ready != int64(0) {
+		t.Errorf("readyReplicas = %v, want 0 after NotReady", ready)
+	}
+}
+
+func TestFakeClientServesFixtures(t *testing.T) {
+	client := agenttest.FakeClient(
+		agenttest.FluxKustomization("flux-system", "apps"),
+		agenttest.FluxKustomization("flux-system", "infra", agenttest.NotReady("BuildFailed", "kustomize build failed")),
+		agenttest.FluxHelmRelease("flux-system", "podinfo"),
+		agenttest.ArgoApplication("argocd", "guestbook", "guestbook"),
+		agenttest.Deployment("payments", "api"),
+	)
+
+	deps, err := client.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).
+		Namespace("payments").List(context.Background(), v1.ListOptions{})
+	if err != nil || len(deps.Items) != 1 {
+		t.Fatalf("list deployments: %v, %v", deps, err)
+	}
+
+	result, err := agent.NewStateScannerWithClient(client).ScanWithThreshold(context.Background(), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Summary.KustomizationStuck != 1 || result.Summary.HelmReleaseStuck != 0 || result.Summary.ApplicationStuck != 0 {
+		t.Errorf("summary = %+v, want only the failing Kustomization stuck", result.Summary)
+	}
+}
+
+func TestCluster(t *testing.T) {
+	objs := agenttest.Cluster(600)
+	if len(objs) != 600 {
+		t.Fatalf("Cluster(600) returned %d objects", len(objs))
+	}
+	owners := map[string]int{}
+	for _, obj := range objs {
+		owners[agent.DetectOwnership(obj).Type]++
+	}
+	for _, owner := range []string{agent.OwnerFlux, agent.OwnerArgo, agent.OwnerHelm, agent.OwnerConfigHub, agent.OwnerTerraform, agent.OwnerUnknown} {
+		if owners[owner] != 100 {
+			t.Errorf("%s owns %d objects, want 100 (%v)", owner, owners[owner], owners)
+		}
+	}
+	if again := agenttest.Cluster(600); again[599].GetName() != objs[599].GetName() || again[599].GetNamespace() != objs[599].GetNamespace() {
+		t.Error("Cluster should be deterministic")
+	}
+}
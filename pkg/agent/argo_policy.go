@@ -0,0 +1,328 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// Argo CD notifications markers
+const (
+	// ArgoSubscribeAnnotationPrefix starts the annotations subscribing an
+	// Application or AppProject: subscribe.<trigger>.<service> or
+	// subscribe.<service> for the default triggers
+	ArgoSubscribeAnnotationPrefix = "notifications.argoproj.io/subscribe."
+
+	// ArgoNotificationsConfigMap holds the notification services, triggers
+	// and default subscriptions, next to the Applications
+	ArgoNotificationsConfigMap = "argocd-notifications-cm"
+)
+
+var (
+	argoApplicationsGVR = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+	argoAppProjectsGVR  = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "appprojects"}
+	configMapsGVR       = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+// ArgoSyncPolicy is an Application's spec.syncPolicy.automated.
+type ArgoSyncPolicy struct {
+	Automated  bool `json:"automated"`
+	Prune      bool `json:"prune,omitempty"`
+	SelfHeal   bool `json:"selfHeal,omitempty"`
+	AllowEmpty bool `json:"allowEmpty,omitempty"`
+}
+
+// ArgoSyncPolicyOf reads an Application's sync policy. An automated block
+// with enabled: false (Argo CD 3.1) is manual sync.
+func ArgoSyncPolicyOf(app *unstructured.Unstructured) ArgoSyncPolicy {
+	automated, found, _ := unstructured.NestedMap(app.Object, "spec", "syncPolicy", "automated")
+	if !found {
+		return ArgoSyncPolicy{}
+	}
+	if enabled, ok := automated["enabled"].(bool); ok && !enabled {
+		return ArgoSyncPolicy{}
+	}
+	p := ArgoSyncPolicy{Automated: true}
+	p.Prune, _ = automated["prune"].(bool)
+	p.SelfHeal, _ = automated["selfHeal"].(bool)
+	p.AllowEmpty, _ = automated["allowEmpty"].(bool)
+	return p
+}
+
+// String renders the policy as "manual", "auto" or e.g.
+// "auto+prune+selfHeal".
+func (p ArgoSyncPolicy) String() string {
+	if !p.Automated {
+		return "manual"
+	}
+	s := "auto"
+	if p.Prune {
+		s += "+prune"
+	}
+	if p.SelfHeal {
+		s += "+selfHeal"
+	}
+	if p.AllowEmpty {
+		s += "+allowEmpty"
+	}
+	return s
+}
+
+// ArgoPolicyRisk is a risky sync policy on an Application.
+type ArgoPolicyRisk struct {
+	CCVEID      string `json:"ccveId"`
+	Reason      string `json:"reason"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation"`
+}
+
+// ArgoAppPolicy is the sync policy and notification subscriptions of an
+// Application.
+type ArgoAppPolicy struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Project   string `json:"project"`
+
+	// Production is set when the Application's environment label, its
+	// destination or its source path names production
+	Production bool `json:"production,omitempty"`
+
+	// Healthy is set when the Application is Synced and Healthy
+	Healthy bool `json:"healthy"`
+
+	Sync ArgoSyncPolicy `json:"syncPolicy"`
+
+	// Subscriptions are <trigger>.<service> or <service>, prefixed with
+	// "project/" or "default/" when inherited from the AppProject or the
+	// default subscriptions of argocd-notifications-cm
+	Subscriptions []string `json:"subscriptions,omitempty"`
+
+	// Notified is set when notifications are configured and at least one
+	// subscription covers the Application
+	Notified bool `json:"notified"`
+
+	Risks []ArgoPolicyRisk `json:"risks,omitempty"`
+}
+
+// ArgoPolicyAudit is the sync policy and notification coverage of the
+// Argo CD Applications of a cluster.
+type ArgoPolicyAudit struct {
+	// NotificationsInstalled is set when argocd-notifications-cm exists
+	// next to the Applications
+	NotificationsInstalled bool `json:"notificationsInstalled"`
+
+	Apps []ArgoAppPolicy `json:"apps,omitempty"`
+}
+
+// Unnotified returns the Applications no notification subscription covers.
+func (a ArgoPolicyAudit) Unnotified() []ArgoAppPolicy {
+	var out []ArgoAppPolicy
+	for _, app := range a.Apps {
+		if !app.Notified {
+			out = append(out, app)
+		}
+	}
+	return out
+}
+
+// Risky returns the Applications with a risky sync policy.
+func (a ArgoPolicyAudit) Risky() []ArgoAppPolicy {
+	var out []ArgoAppPolicy
+	for _, app := range a.Apps {
+		if len(app.Risks) > 0 {
+			out = append(out, app)
+		}
+	}
+	return out
+}
+
+// AuditArgoPolicies reads the Applications in namespace ("" for all), the
+// AppProjects, and the argocd-notifications-cm ConfigMap of each namespace
+// holding Applications. Without the Application API the audit is empty; an
+// error is returned only when the Applications could not be read.
+func AuditArgoPolicies(ctx context.Context, client dynamic.Interface, namespace string) (ArgoPolicyAudit, error) {
+	list, err := client.Resource(argoApplicationsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ArgoPolicyAudit{}, nil
+		}
+		return ArgoPolicyAudit{}, fmt.Errorf("list Argo CD Applications: %w", err)
+	}
+	if len(list.Items) == 0 {
+		return ArgoPolicyAudit{}, nil
+	}
+
+	var projects []unstructured.Unstructured
+	if projList, err := client.Resource(argoAppProjectsGVR).List(ctx, metav1.ListOptions{}); err == nil {
+		projects = projList.Items
+	}
+
+	var configMaps []unstructured.Unstructured
+	seen := map[string]bool{}
+	for _, app := range list.Items {
+		ns := app.GetNamespace()
+		if seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		if cm, err := client.Resource(configMapsGVR).Namespace(ns).Get(ctx, ArgoNotificationsConfigMap, metav1.GetOptions{}); err == nil {
+			configMaps = append(configMaps, *cm)
+		}
+	}
+	return BuildArgoPolicyAudit(list.Items, projects, configMaps), nil
+}
+
+// argoDefaultSubscription is an entry of the subscriptions key of
+// argocd-notifications-cm.
+type argoDefaultSubscription struct {
+	Recipients []string `json:"recipients"`
+	Triggers   []string `json:"triggers"`
+	Selector   string   `json:"selector"`
+}
+
+// BuildArgoPolicyAudit audits each Application's sync policy and resolves
+// its subscriptions from its own annotations, its AppProject's, and the
+// default subscriptions of the argocd-notifications-cm in its namespace.
+func BuildArgoPolicyAudit(apps, projects, notificationConfigMaps []unstructured.Unstructured) ArgoPolicyAudit {
+	audit := ArgoPolicyAudit{NotificationsInstalled: len(notificationConfigMaps) > 0}
+
+	projectSubs := map[string][]string{}
+	for i := range projects {
+		p := &projects[i]
+		projectSubs[p.GetNamespace()+"/"+p.GetName()] = argoSubscriptions(p.GetAnnotations())
+	}
+	configured := map[string]bool{}
+	defaults := map[string][]argoDefaultSubscription{}
+	for i := range notificationConfigMaps {
+		cm := &notificationConfigMaps[i]
+		configured[cm.GetNamespace()] = true
+		raw, _, _ := unstructured.NestedString(cm.Object, "data", "subscriptions")
+		var subs []argoDefaultSubscription
+		if err := yaml.Unmarshal([]byte(raw), &subs); err == nil {
+			defaults[cm.GetNamespace()] = subs
+		}
+	}
+
+	for i := range apps {
+		obj := &apps[i]
+		app := ArgoAppPolicy{Name: obj.GetName(), Namespace: obj.GetNamespace(), Sync: ArgoSyncPolicyOf(obj)}
+		app.Project, _, _ = unstructured.NestedString(obj.Object, "spec", "project")
+		if app.Project == "" {
+			app.Project = "default"
+		}
+		syncStatus, _, _ := unstructured.NestedString(obj.Object, "status", "sync", "status")
+		healthStatus, _, _ := unstructured.NestedString(obj.Object, "status", "health", "status")
+		app.Healthy = syncStatus == "Synced" && healthStatus == "Healthy"
+		app.Production = argoProduction(obj)
+
+		app.Subscriptions = argoSubscriptions(obj.GetAnnotations())
+		for _, s := range projectSubs[app.Namespace+"/"+app.Project] {
+			app.Subscriptions = append(app.Subscriptions, "project/"+s)
+		}
+		for _, d := range defaults[app.Namespace] {
+			if !d.selects(obj.GetLabels()) {
+				continue
+			}
+			for _, r := range d.Recipients {
+				service, _, _ := strings.Cut(r, ":")
+				app.Subscriptions = append(app.Subscriptions, "default/"+service)
+			}
+		}
+		app.Notified = configured[app.Namespace] && len(app.Subscriptions) > 0
+		app.Risks = argoPolicyRisks(app)
+		audit.Apps = append(audit.Apps, app)
+	}
+	sort.Slice(audit.Apps, func(i, j int) bool {
+		if audit.Apps[i].Namespace != audit.Apps[j].Namespace {
+			return audit.Apps[i].Namespace < audit.Apps[j].Namespace
+		}
+		return audit.Apps[i].Name < audit.Apps[j].Name
+	})
+	return audit
+}
+
+// selects reports whether a default subscription applies to an Application
+// with the given labels. An invalid selector selects nothing.
+func (d argoDefaultSubscription) selects(appLabels map[string]string) bool {
+	if d.Selector == "" {
+		return true
+	}
+	sel, err := labels.Parse(d.Selector)
+	return err == nil && sel.Matches(labels.Set(appLabels))
+}
+
+// argoSubscriptions returns the sorted <trigger>.<service> (or <service>)
+// of the subscribe annotations with recipients.
+func argoSubscriptions(annotations map[string]string) []string {
+	var out []string
+	for k, v := range annotations {
+		if sub, ok := strings.CutPrefix(k, ArgoSubscribeAnnotationPrefix); ok && sub != "" && strings.TrimSpace(v) != "" {
+			out = append(out, sub)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// argoProduction reports whether an Application deploys to production: its
+// environment label, else its destination namespace or cluster name or its
+// source path mentioning prod.
+func argoProduction(app *unstructured.Unstructured) bool {
+	for _, key := range []string{"environment", "env"} {
+		if env := app.GetLabels()[key]; env != "" {
+			return strings.HasPrefix(strings.ToLower(env), "prod")
+		}
+	}
+	destNS, _, _ := unstructured.NestedString(app.Object, "spec", "destination", "namespace")
+	destName, _, _ := unstructured.NestedString(app.Object, "spec", "destination", "name")
+	path, _, _ := unstructured.NestedString(app.Object, "spec", "source", "path")
+	return strings.Contains(strings.ToLower(destNS+" "+destName+" "+path), "prod")
+}
+
+// argoPolicyRisks returns the risky combinations in an Application's sync
+// policy.
+func argoPolicyRisks(app ArgoAppPolicy) []ArgoPolicyRisk {
+	var risks []ArgoPolicyRisk
+	p := app.Sync
+	if p.Prune && !p.SelfHeal {
+		risks = append(risks, ArgoPolicyRisk{
+			CCVEID:      "CCVE-2025-0695",
+			Reason:      "AutoPruneWithoutSelfHeal",
+			Severity:    "warning",
+			Message:     "Automated sync prunes resources removed from Git but never reverts manual changes; drift persists until the next commit",
+			Remediation: "Set spec.syncPolicy.automated.selfHeal: true, or drop prune and prune on manual syncs",
+		})
+	}
+	if p.Prune && p.AllowEmpty {
+		risks = append(risks, ArgoPolicyRisk{
+			CCVEID:      "CCVE-2025-0696",
+			Reason:      "AutoPruneAllowEmpty",
+			Severity:    "warning",
+			Message:     "Automated sync with prune and allowEmpty deletes every resource when the source renders nothing (wrong path or revision)",
+			Remediation: "Remove spec.syncPolicy.automated.allowEmpty so an empty render fails the sync instead",
+		})
+	}
+	if app.Production && !p.Automated {
+		risks = append(risks, ArgoPolicyRisk{
+			CCVEID:      "CCVE-2025-0697",
+			Reason:      "ManualSyncProduction",
+			Severity:    "warning",
+			Message:     "Production Application syncs manually; merged changes wait for someone to sync and drift is never reverted",
+			Remediation: "Enable spec.syncPolicy.automated with selfHeal: true, using sync windows to control when production changes",
+		})
+	}
+	return risks
+}
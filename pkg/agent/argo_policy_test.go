@@ -0,0 +1,161 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func notificationsConfigMap(namespace, subscriptions string) *unstructured.Unstructured {
+	cm := agenttest.Object("v1", "ConfigMap", namespace, ArgoNotificationsConfigMap)
+	cm.Object["data"] = map[string]interface{}{
+		"service.slack": "token: $slack-token",
+		"subscriptions": subscriptions,
+	}
+	return cm
+}
+
+func TestArgoSyncPolicyOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		automated map[string]interface{}
+		want      string
+	}{
+		{"manual", nil, "manual"},
+		{"empty automated", map[string]interface{}{}, "auto"},
+		{"prune and self-heal", map[string]interface{}{"prune": true, "selfHeal": true}, "auto+prune+selfHeal"},
+		{"allow empty", map[string]interface{}{"prune": true, "allowEmpty": true}, "auto+prune+allowEmpty"},
+		{"disabled", map[string]interface{}{"enabled": false, "prune": true}, "manual"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := agenttest.ArgoApplication("argocd", "web", "web")
+			if tt.automated != nil {
+				_ = unstructured.SetNestedMap(app.Object, tt.automated, "spec", "syncPolicy", "automated")
+			}
+			if got := ArgoSyncPolicyOf(app).String(); got != tt.want {
+				t.Errorf("ArgoSyncPolicyOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildArgoPolicyAudit(t *testing.T) {
+	apps := []unstructured.Unstructured{
+		// Subscribed itself, safe policy
+		*agenttest.ArgoApplication("argocd", "api", "api-prod", agenttest.ArgoAutomatedSync(true, true),
+			agenttest.WithAnnotations(map[string]string{ArgoSubscribeAnnotationPrefix + "on-sync-failed.slack": "deploys"})),
+		// Inherits the team project's subscription; prunes without self-heal
+		*agenttest.ArgoApplication("argocd", "worker", "worker", agenttest.ArgoAutomatedSync(true, false),
+			func(u *unstructured.Unstructured) {
+				_ = unstructured.SetNestedField(u.Object, "team", "spec", "project")
+			}),
+		// Manual sync in production, covered by the default subscription
+		*agenttest.ArgoApplication("argocd", "billing", "billing", agenttest.WithLabels(map[string]string{"env": "production", "tier": "critical"})),
+		// Covered by nothing
+		*agenttest.ArgoApplication("argocd", "docs", "docs-staging",
+			agenttest.NotReady("Degraded", "Deployment docs has 0 ready replicas"),
+			agenttest.WithAnnotations(map[string]string{ArgoSubscribeAnnotationPrefix + "on-deployed.slack": ""})),
+	}
+	projects := []unstructured.Unstructured{
+		*agenttest.ArgoAppProject("argocd", "team", agenttest.WithAnnotations(map[string]string{
+			ArgoSubscribeAnnotationPrefix + "on-health-degraded.teams": "oncall",
+		})),
+	}
+	cms := []unstructured.Unstructured{*notificationsConfigMap("argocd", `
+- recipients: [slack:critical, "pagerduty:payments"]
+  triggers: [on-sync-failed]
+  selector: tier=critical
+`)}
+
+	audit := BuildArgoPolicyAudit(apps, projects, cms)
+	if !audit.NotificationsInstalled || len(audit.Apps) != 4 {
+		t.Fatalf("audit = %+v", audit)
+	}
+	byName := map[string]ArgoAppPolicy{}
+	for _, app := range audit.Apps {
+		byName[app.Name] = app
+	}
+
+	if api := byName["api"]; !api.Notified || len(api.Risks) != 0 || !api.Production {
+		t.Errorf("api = %+v, want a notified production app without risks", api)
+	}
+	worker := byName["worker"]
+	if len(worker.Subscriptions) != 1 || worker.Subscriptions[0] != "project/on-health-degraded.teams" {
+		t.Errorf("worker subscriptions = %v", worker.Subscriptions)
+	}
+	if len(worker.Risks) != 1 || worker.Risks[0].Reason != "AutoPruneWithoutSelfHeal" {
+		t.Errorf("worker risks = %+v", worker.Risks)
+	}
+	billing := byName["billing"]
+	if !billing.Notified || len(billing.Subscriptions) != 2 || billing.Subscriptions[1] != "default/pagerduty" {
+		t.Errorf("billing subscriptions = %v", billing.Subscriptions)
+	}
+	if len(billing.Risks) != 1 || billing.Risks[0].CCVEID != "CCVE-2025-0697" {
+		t.Errorf("billing risks = %+v", billing.Risks)
+	}
+
+	unnotified := audit.Unnotified()
+	if len(unnotified) != 1 || unnotified[0].Name != "docs" || unnotified[0].Healthy {
+		t.Errorf("unnotified = %+v, want only the degraded docs app", unnotified)
+	}
+	if risky := audit.Risky(); len(risky) != 2 {
+		t.Errorf("risky = %+v, want worker and billing", risky)
+	}
+}
+
+func TestBuildArgoPolicyAuditWithoutNotifications(t *testing.T) {
+	apps := []unstructured.Unstructured{
+		*agenttest.ArgoApplication("argocd", "api", "api", agenttest.ArgoAutomatedSync(true, true),
+			agenttest.WithAnnotations(map[string]string{ArgoSubscribeAnnotationPrefix + "on-sync-failed.slack": "deploys"})),
+	}
+	audit := BuildArgoPolicyAudit(apps, nil, nil)
+	if audit.NotificationsInstalled || len(audit.Unnotified()) != 1 {
+		t.Errorf("audit = %+v, want subscriptions ignored without argocd-notifications-cm", audit)
+	}
+}
+
+func TestScanArgoPolicies(t *testing.T) {
+	client := newFakeDynamicClientForScan(
+		notificationsConfigMap("argocd", ""),
+		agenttest.ArgoApplication("argocd", "api", "api", agenttest.ArgoAutomatedSync(true, false),
+			agenttest.WithAnnotations(map[string]string{ArgoSubscribeAnnotationPrefix + "on-sync-failed.slack": "deploys"})),
+		agenttest.ArgoApplication("argocd", "web", "web-prod"),
+		agenttest.ArgoApplication("argocd", "docs", "docs-staging", agenttest.NotReady("Degraded", "Deployment docs has 0 ready replicas")),
+	)
+	scanner := NewStateScannerWithClient(client)
+
+	result, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, f := range result.Findings {
+		if f.Kind == "Application" {
+			got[f.Name+" "+f.CCVEID] = f.Category
+		}
+	}
+	want := map[string]string{
+		"api CCVE-2025-0695": "CONFIG",
+		"web CCVE-2025-0697": "CONFIG",
+		// Unnotified and degraded; web is unnotified but healthy, a gap
+		"docs CCVE-2025-0698": "SILENT",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("findings = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s category = %q, want %q", k, got[k], v)
+		}
+	}
+	if result.Summary.PolicyRisks != 2 || result.Summary.SilentFailures != 1 || result.Summary.AlertingGaps != 1 {
+		t.Errorf("summary = %+v", result.Summary)
+	}
+}
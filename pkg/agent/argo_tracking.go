@@ -0,0 +1,134 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"strings"
+)
+
+// Argo CD resource tracking methods, the application.resourceTrackingMethod
+// setting of the argocd-cm ConfigMap
+const (
+	ArgoTrackingLabel           = "label"
+	ArgoTrackingAnnotation      = "annotation"
+	ArgoTrackingAnnotationLabel = "annotation+label"
+)
+
+// Argo CD tracking markers
+const (
+	ArgoInstanceLabel        = "argocd.argoproj.io/instance"
+	ArgoTrackingIDAnnotation = "argocd.argoproj.io/tracking-id"
+)
+
+// ArgoTracking is how an Argo CD installation marks the resources it
+// manages. The zero value, used when argocd-cm can't be read, accepts both
+// the tracking-id annotation and the instance label.
+type ArgoTracking struct {
+	// Method is the resource tracking method; "" when unknown
+	Method string `json:"method,omitempty"`
+
+	// InstanceLabelKey is the label that label tracking writes the Application
+	// name to (application.instanceLabelKey), when set
+	InstanceLabelKey string `json:"instanceLabelKey,omitempty"`
+}
+
+// argoTracking is the tracking of the cluster being scanned, set once per
+// command with SetArgoTracking.
+var argoTracking ArgoTracking
+
+// SetArgoTracking sets the tracking method ownership detection uses for
+// Argo CD.
+func SetArgoTracking(t ArgoTracking) {
+	argoTracking = t
+}
+
+// CurrentArgoTracking returns the tracking set with SetArgoTracking.
+func CurrentArgoTracking() ArgoTracking {
+	return argoTracking
+}
+
+// ArgoTrackingFromConfigMap reads the tracking settings from the data of the
+// argocd-cm ConfigMap. Argo CD 3 tracks with the annotation by default and
+// earlier versions with the label, so a missing method stays unknown.
+func ArgoTrackingFromConfigMap(data map[string]string) ArgoTracking {
+	t := ArgoTracking{InstanceLabelKey: strings.TrimSpace(data["application.instanceLabelKey"])}
+	switch method := strings.TrimSpace(data["application.resourceTrackingMethod"]); method {
+	case ArgoTrackingLabel, ArgoTrackingAnnotation, ArgoTrackingAnnotationLabel:
+		t.Method = method
+	}
+	return t
+}
+
+// labelKeys returns the instance labels to check, configured key first.
+func (t ArgoTracking) labelKeys() []string {
+	if t.InstanceLabelKey == "" || t.InstanceLabelKey == ArgoInstanceLabel {
+		return []string{ArgoInstanceLabel}
+	}
+	return []string{t.InstanceLabelKey, ArgoInstanceLabel}
+}
+
+// ArgoApplication returns the Application tracking a resource and the
+// marker it came from ("annotation:argocd.argoproj.io/tracking-id" or
+// "label:<key>"), or "" when Argo CD doesn't track it. namespace is set for
+// Applications outside Argo CD's own namespace.
+//
+// The tracking-id annotation wins over labels unless the cluster tracks
+// with labels: annotation+label truncates the label to 63 characters, and
+// under annotation tracking a Helm chart's app.kubernetes.io/instance label
+// says nothing about Argo CD. The label is still read as a fallback, since
+// resources synced before a switch to annotation tracking keep it until
+// their next sync.
+func ArgoApplication(labels, annotations map[string]string) (namespace, app, source string) {
+	fromAnnotation := func() (string, string, string) {
+		if ns, name, ok := ParseArgoTrackingID(annotations[ArgoTrackingIDAnnotation]); ok {
+			return ns, name, "annotation:" + ArgoTrackingIDAnnotation
+		}
+		return "", "", ""
+	}
+	fromLabels := func() (string, string, string) {
+		for _, key := range argoTracking.labelKeys() {
+			if v := labels[key]; v != "" {
+				ns, name := splitArgoInstance(v)
+				return ns, name, "label:" + key
+			}
+		}
+		return "", "", ""
+	}
+
+	if argoTracking.Method == ArgoTrackingLabel {
+		if namespace, app, source = fromLabels(); app != "" {
+			return namespace, app, source
+		}
+		return fromAnnotation()
+	}
+	if namespace, app, source = fromAnnotation(); app != "" {
+		return namespace, app, source
+	}
+	return fromLabels()
+}
+
+// ParseArgoTrackingID parses a tracking-id annotation,
+// "<app>:<group>/<kind>:<namespace>/<name>". The "<app-namespace>:<app>:..."
+// form some older installations wrote is accepted too.
+func ParseArgoTrackingID(id string) (appNamespace, app string, ok bool) {
+	instance, rest, _ := strings.Cut(id, ":")
+	if instance == "" {
+		return "", "", false
+	}
+	if name, _, found := strings.Cut(rest, ":"); found && name != "" && !strings.Contains(name, "/") {
+		return instance, name, true
+	}
+	appNamespace, app = splitArgoInstance(instance)
+	return appNamespace, app, true
+}
+
+// splitArgoInstance splits the "<app-namespace>_<app>" form Argo CD writes
+// for Applications outside its own namespace. Kubernetes names can't
+// contain "_", so a plain name is returned whole.
+func splitArgoInstance(v string) (namespace, app string) {
+	if ns, name, found := strings.Cut(v, "_"); found && ns != "" && name != "" {
+		return ns, name
+	}
+	return "", v
+}
@@ -0,0 +1,98 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import "testing"
+
+func TestArgoTrackingFromConfigMap(t *testing.T) {
+	got := ArgoTrackingFromConfigMap(map[string]string{
+		"application.resourceTrackingMethod": " annotation+label\n",
+		"application.instanceLabelKey":       "argocd.example.com/app",
+	})
+	want := ArgoTracking{Method: ArgoTrackingAnnotationLabel, InstanceLabelKey: "argocd.example.com/app"}
+	if got != want {
+		t.Errorf("ArgoTrackingFromConfigMap = %+v, want %+v", got, want)
+	}
+	if got := ArgoTrackingFromConfigMap(map[string]string{"application.resourceTrackingMethod": "bogus"}); got.Method != "" {
+		t.Errorf("unknown method = %q, want empty", got.Method)
+	}
+}
+
+func TestArgoApplication(t *testing.T) {
+	t.Cleanup(func() { SetArgoTracking(ArgoTracking{}) })
+
+	both := struct{ labels, annotations map[string]string }{
+		labels:      map[string]string{ArgoInstanceLabel: "truncated-name"},
+		annotations: map[string]string{ArgoTrackingIDAnnotation: "full-name:apps/Deployment:shop/cart"},
+	}
+	helmInstance := map[string]string{"app.kubernetes.io/instance": "cart"}
+
+	tests := []struct {
+		name        string
+		tracking    ArgoTracking
+		labels      map[string]string
+		annotations map[string]string
+		wantNS      string
+		wantApp     string
+		wantSource  string
+	}{
+		{"unknown method prefers the annotation", ArgoTracking{}, both.labels, both.annotations,
+			"", "full-name", "annotation:" + ArgoTrackingIDAnnotation},
+		{"label method prefers the label", ArgoTracking{Method: ArgoTrackingLabel}, both.labels, both.annotations,
+			"", "truncated-name", "label:" + ArgoInstanceLabel},
+		{"annotation method falls back to a stale label", ArgoTracking{Method: ArgoTrackingAnnotation}, both.labels, nil,
+			"", "truncated-name", "label:" + ArgoInstanceLabel},
+		{"custom instance label", ArgoTracking{Method: ArgoTrackingLabel, InstanceLabelKey: "app.kubernetes.io/instance"}, helmInstance, nil,
+			"", "cart", "label:app.kubernetes.io/instance"},
+		{"custom key not configured", ArgoTracking{Method: ArgoTrackingLabel}, helmInstance, nil,
+			"", "", ""},
+		{"app in any namespace", ArgoTracking{}, nil, map[string]string{ArgoTrackingIDAnnotation: "team-a_cart:apps/Deployment:shop/cart"},
+			"team-a", "cart", "annotation:" + ArgoTrackingIDAnnotation},
+		{"app in any namespace label", ArgoTracking{}, map[string]string{ArgoInstanceLabel: "team-a_cart"}, nil,
+			"team-a", "cart", "label:" + ArgoInstanceLabel},
+		{"malformed annotation", ArgoTracking{}, nil, map[string]string{ArgoTrackingIDAnnotation: ":apps/Deployment:shop/cart"},
+			"", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetArgoTracking(tt.tracking)
+			ns, app, source := ArgoApplication(tt.labels, tt.annotations)
+			if ns != tt.wantNS || app != tt.wantApp || source != tt.wantSource {
+				t.Errorf("ArgoApplication = (%q, %q, %q), want (%q, %q, %q)", ns, app, source, tt.wantNS, tt.wantApp, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestArgoTrackingOwnership(t *testing.T) {
+	t.Cleanup(func() { SetArgoTracking(ArgoTracking{}) })
+	res := newTestResource("shop", "cart", map[string]string{"app.kubernetes.io/instance": "cart"}, nil)
+
+	if got := DetectOwnership(res); got.Type == OwnerArgo {
+		t.Fatalf("without configured label tracking, ownership = %+v", got)
+	}
+	SetArgoTracking(ArgoTracking{Method: ArgoTrackingLabel, InstanceLabelKey: "app.kubernetes.io/instance"})
+	if got := DetectOwnership(res); got.Type != OwnerArgo || got.Name != "cart" {
+		t.Errorf("with label tracking on app.kubernetes.io/instance, ownership = %+v, want Argo cart", got)
+	}
+}
+
+func TestParseArgoTrackingID(t *testing.T) {
+	tests := []struct {
+		id, wantNS, wantApp string
+		wantOK              bool
+	}{
+		{"guestbook:apps/Deployment:default/guestbook", "", "guestbook", true},
+		{"core:/ConfigMap:default/settings", "", "core", true},
+		{"team-a_cart:apps/Deployment:shop/cart", "team-a", "cart", true},
+		{"argocd:my-app:apps/Deployment:default/nginx", "argocd", "my-app", true},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		ns, app, ok := ParseArgoTrackingID(tt.id)
+		if ns != tt.wantNS || app != tt.wantApp || ok != tt.wantOK {
+			t.Errorf("ParseArgoTrackingID(%q) = (%q, %q, %v), want (%q, %q, %v)", tt.id, ns, app, ok, tt.wantNS, tt.wantApp, tt.wantOK)
+		}
+	}
+}
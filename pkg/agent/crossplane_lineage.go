@@ -27,6 +27,14 @@ type CrossplaneLineage struct {
 	Composite CrossplaneLineageNode  `json:"composite"`
 	Claim     *CrossplaneLineageNode `json:"claim,omitempty"`
 
+	// Parents are the composites above Composite when XRs are nested,
+	// nearest first
+	Parents []CrossplaneLineageNode `json:"parents,omitempty"`
+
+	// Composition is the Composition revision Composite was rendered with,
+	// when resolved from the cluster
+	Composition *CrossplaneComposition `json:"composition,omitempty"`
+
 	// Evidence describes which signals were used to build the lineage.
 	Evidence []string `json:"evidence,omitempty"`
 }
@@ -0,0 +1,489 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	xrdGVR                 = schema.GroupVersionResource{Group: "apiextensions.crossplane.io", Version: "v1", Resource: "compositeresourcedefinitions"}
+	compositionRevisionGVR = schema.GroupVersionResource{Group: "apiextensions.crossplane.io", Version: "v1", Resource: "compositionrevisions"}
+)
+
+// maxCrossplaneDepth bounds the walk through nested composites.
+const maxCrossplaneDepth = 8
+
+// CrossplaneComposition is the Composition an XR was rendered with.
+type CrossplaneComposition struct {
+	// Name is the Composition (spec.compositionRef)
+	Name string `json:"name"`
+
+	// Revision is the CompositionRevision in use (spec.compositionRevisionRef)
+	Revision string `json:"revision,omitempty"`
+
+	// RevisionNumber is that revision's spec.revision
+	RevisionNumber int64 `json:"revisionNumber,omitempty"`
+
+	// LatestRevision is the highest revision of the Composition
+	LatestRevision int64 `json:"latestRevision,omitempty"`
+
+	// UpdatePolicy is Automatic or Manual; Manual XRs stay on their
+	// revision when the Composition changes
+	UpdatePolicy string `json:"updatePolicy,omitempty"`
+}
+
+// Behind reports whether a newer revision of the Composition exists than
+// the one the XR uses.
+func (c CrossplaneComposition) Behind() bool {
+	return c.RevisionNumber > 0 && c.LatestRevision > c.RevisionNumber
+}
+
+// String renders the composition as "xpostgres rev 3 (xpostgres-8f3c2a1)".
+func (c CrossplaneComposition) String() string {
+	s := c.Name
+	if c.RevisionNumber > 0 {
+		s += fmt.Sprintf(" rev %d", c.RevisionNumber)
+	}
+	if c.Revision != "" {
+		s += " (" + c.Revision + ")"
+	}
+	return s
+}
+
+// xrdNames are the kinds an XRD defines.
+type xrdNames struct {
+	group                   string
+	kind, plural            string
+	claimKind, claimsPlural string
+}
+
+// CrossplaneTracer walks a Crossplane-composed resource up through its
+// managed resource and composites to the claim, reading the XRDs to map
+// composite and claim kinds to resources.
+type CrossplaneTracer struct {
+	client dynamic.Interface
+	xrds   []xrdNames
+	loaded bool
+}
+
+// NewCrossplaneTracer creates a tracer reading from client.
+func NewCrossplaneTracer(client dynamic.Interface) *CrossplaneTracer {
+	return &CrossplaneTracer{client: client}
+}
+
+// Lineage resolves obj's Crossplane lineage from the cluster: the managed
+// resource, the composite (XR) and any parent composites, the claim, and
+// the Composition revision the XR was rendered with. Objects that can't be
+// fetched are kept with Present=false and the walk stops there. It returns
+// false when obj isn't Crossplane-composed.
+func (t *CrossplaneTracer) Lineage(ctx context.Context, obj *unstructured.Unstructured) (*CrossplaneLineage, bool) {
+	if obj == nil || DetectOwnership(obj).Type != OwnerCrossplane {
+		return nil, false
+	}
+	lineage := &CrossplaneLineage{Managed: CrossplaneLineageNode{Ref: resourceRefFromUnstructured(obj), Present: true}}
+
+	// Walk up to the first composite: a connection secret is owned by its
+	// managed resource, which is owned by the XR
+	cur := obj
+	var xr *unstructured.Unstructured
+	for depth := 0; depth < maxCrossplaneDepth && xr == nil; depth++ {
+		parent, ref, evidence := t.parent(ctx, cur)
+		if evidence == "" {
+			break
+		}
+		lineage.Evidence = append(lineage.Evidence, evidence)
+		if parent == nil {
+			lineage.Composite = CrossplaneLineageNode{Ref: ref}
+			return lineage, true
+		}
+		if t.isComposite(parent) {
+			xr = parent
+			break
+		}
+		if t.isClaim(parent) {
+			lineage.Claim = &CrossplaneLineageNode{Ref: resourceRefFromUnstructured(parent), Present: true}
+			break
+		}
+		// An intermediate object such as the managed resource behind a
+		// connection secret: it's the managed resource
+		lineage.Managed = CrossplaneLineageNode{Ref: resourceRefFromUnstructured(parent), Present: true}
+		cur = parent
+	}
+	if xr == nil && lineage.Claim != nil {
+		// Owned by the claim, e.g. its connection secret: the claim's
+		// resourceRef names the XR
+		xr = t.claimComposite(ctx, lineage.Claim.Ref)
+	}
+	if xr == nil {
+		lineage.Evidence = append(lineage.Evidence, "xr:unresolved")
+		lineage.Composite = CrossplaneLineageNode{Ref: ResourceRef{Kind: "CompositeResource"}}
+		return lineage, true
+	}
+	lineage.Composite = CrossplaneLineageNode{Ref: resourceRefFromUnstructured(xr), Present: true}
+	lineage.Composition = t.composition(ctx, xr)
+
+	// Nested composites: an XR composed by another XR carries the parent's
+	// composite label; the top-level XR's label names itself
+	top := xr
+	for depth := 0; depth < maxCrossplaneDepth; depth++ {
+		name := top.GetLabels()["crossplane.io/composite"]
+		if name == "" || name == top.GetName() {
+			break
+		}
+		parent, ref, evidence := t.parent(ctx, top)
+		if evidence == "" {
+			break
+		}
+		node := CrossplaneLineageNode{Ref: ref, Present: parent != nil}
+		lineage.Parents = append(lineage.Parents, node)
+		if parent == nil {
+			return lineage, true
+		}
+		top = parent
+	}
+
+	if lineage.Claim != nil {
+		return lineage, true
+	}
+	if claim, ref, ok := t.claim(ctx, top); ok {
+		lineage.Evidence = append(lineage.Evidence, "claimRef")
+		lineage.Claim = &CrossplaneLineageNode{Ref: ref, Present: claim != nil}
+	}
+	return lineage, true
+}
+
+// Trace builds a trace chain for a Crossplane-composed resource, from the
+// claim down to obj, with the Composition revision on the XR.
+func (t *CrossplaneTracer) Trace(ctx context.Context, obj *unstructured.Unstructured) (*TraceResult, error) {
+	result := &TraceResult{
+		Object:   resourceRefFromUnstructured(obj),
+		Tool:     "crossplane",
+		TracedAt: time.Now(),
+	}
+	lineage, ok := t.Lineage(ctx, obj)
+	if !ok {
+		result.Error = "not managed by Crossplane"
+		return result, nil
+	}
+	result.Crossplane = lineage
+
+	fetch := func(n CrossplaneLineageNode) ChainLink {
+		link := ChainLink{Kind: n.Ref.Kind, Name: n.Ref.Name, Namespace: n.Ref.Namespace}
+		if !n.Present {
+			link.Status = "not found"
+			return link
+		}
+		if o := t.get(ctx, n.Ref); o != nil {
+			return crossplaneChainLink(o)
+		}
+		link.Status = "not found"
+		return link
+	}
+
+	if lineage.Claim != nil {
+		result.Chain = append(result.Chain, fetch(*lineage.Claim))
+	}
+	for i := len(lineage.Parents) - 1; i >= 0; i-- {
+		result.Chain = append(result.Chain, fetch(lineage.Parents[i]))
+	}
+	if lineage.Composite.Ref.Name != "" {
+		link := fetch(lineage.Composite)
+		if c := lineage.Composition; c != nil {
+			link.Revision = c.String()
+		}
+		result.Chain = append(result.Chain, link)
+	}
+	if lineage.Managed.Ref != result.Object {
+		result.Chain = append(result.Chain, fetch(lineage.Managed))
+	}
+	result.Chain = append(result.Chain, crossplaneChainLink(obj))
+
+	result.FullyManaged = lineage.Composite.Present
+	return result, nil
+}
+
+// parent returns the Crossplane object cur was composed by or belongs to,
+// with the evidence used. A parent that can't be fetched is returned as a
+// ref only; evidence is empty when cur has no Crossplane parent.
+func (t *CrossplaneTracer) parent(ctx context.Context, cur *unstructured.Unstructured) (*unstructured.Unstructured, ResourceRef, string) {
+	owners := cur.GetOwnerReferences()
+
+	// Composed resources carry the XR's name; the ownerRef of that name
+	// gives its kind
+	if name := cur.GetLabels()["crossplane.io/composite"]; name != "" && name != cur.GetName() {
+		for _, or := range owners {
+			if or.Name == name {
+				return t.fetchOwner(ctx, cur, or, "label:crossplane.io/composite")
+			}
+		}
+		return nil, ResourceRef{Kind: "CompositeResource", Name: name}, "label:crossplane.io/composite"
+	}
+
+	// Otherwise a controller owner in a Crossplane provider group, or of a
+	// kind an XRD defines
+	for _, or := range owners {
+		if or.Controller == nil || !*or.Controller {
+			continue
+		}
+		group := strings.SplitN(or.APIVersion, "/", 2)[0]
+		if strings.Contains(group, "crossplane.io") || strings.Contains(group, "upbound.io") || t.xrdFor(group, or.Kind) != nil {
+			return t.fetchOwner(ctx, cur, or, "ownerRef:"+or.APIVersion+"/"+or.Kind)
+		}
+	}
+	return nil, ResourceRef{}, ""
+}
+
+func (t *CrossplaneTracer) fetchOwner(ctx context.Context, cur *unstructured.Unstructured, or metav1.OwnerReference, evidence string) (*unstructured.Unstructured, ResourceRef, string) {
+	ref := ResourceRef{Kind: or.Kind, Name: or.Name}
+	if gv, err := schema.ParseGroupVersion(or.APIVersion); err == nil {
+		ref.Group, ref.Version = gv.Group, gv.Version
+	}
+	// Composites are cluster-scoped in Crossplane v1 but namespaced in v2:
+	// try the child's namespace first
+	if cur.GetNamespace() != "" {
+		ref.Namespace = cur.GetNamespace()
+		if o := t.get(ctx, ref); o != nil {
+			return o, resourceRefFromUnstructured(o), evidence
+		}
+		ref.Namespace = ""
+	}
+	if o := t.get(ctx, ref); o != nil {
+		return o, resourceRefFromUnstructured(o), evidence
+	}
+	return nil, ref, evidence
+}
+
+// claim returns the claim bound to a top-level XR, from spec.claimRef or
+// the claim labels.
+func (t *CrossplaneTracer) claim(ctx context.Context, xr *unstructured.Unstructured) (*unstructured.Unstructured, ResourceRef, bool) {
+	var ref ResourceRef
+	if claimRef, ok, _ := unstructured.NestedStringMap(xr.Object, "spec", "claimRef"); ok && claimRef["name"] != "" {
+		ref = ResourceRef{Kind: claimRef["kind"], Name: claimRef["name"], Namespace: claimRef["namespace"]}
+		if gv, err := schema.ParseGroupVersion(claimRef["apiVersion"]); err == nil {
+			ref.Group, ref.Version = gv.Group, gv.Version
+		}
+	} else if name := xr.GetLabels()["crossplane.io/claim-name"]; name != "" {
+		ref = ResourceRef{Kind: "Claim", Name: name, Namespace: xr.GetLabels()["crossplane.io/claim-namespace"]}
+		gvk := xr.GroupVersionKind()
+		if x := t.xrdFor(gvk.Group, gvk.Kind); x != nil && x.claimKind != "" {
+			ref.Kind, ref.Group, ref.Version = x.claimKind, gvk.Group, gvk.Version
+		}
+	} else {
+		return nil, ResourceRef{}, false
+	}
+	if o := t.get(ctx, ref); o != nil {
+		return o, resourceRefFromUnstructured(o), true
+	}
+	return nil, ref, true
+}
+
+// claimComposite fetches the XR a claim is bound to (spec.resourceRef).
+func (t *CrossplaneTracer) claimComposite(ctx context.Context, claim ResourceRef) *unstructured.Unstructured {
+	obj := t.get(ctx, claim)
+	if obj == nil {
+		return nil
+	}
+	ref, ok, _ := unstructured.NestedStringMap(obj.Object, "spec", "resourceRef")
+	if !ok || ref["name"] == "" {
+		return nil
+	}
+	xrRef := ResourceRef{Kind: ref["kind"], Name: ref["name"]}
+	if gv, err := schema.ParseGroupVersion(ref["apiVersion"]); err == nil {
+		xrRef.Group, xrRef.Version = gv.Group, gv.Version
+	}
+	return t.get(ctx, xrRef)
+}
+
+// composition reads the Composition and revision an XR uses, from spec
+// (Crossplane v1) or spec.crossplane (v2).
+func (t *CrossplaneTracer) composition(ctx context.Context, xr *unstructured.Unstructured) *CrossplaneComposition {
+	field := func(path ...string) string {
+		if v, ok, _ := unstructured.NestedString(xr.Object, append([]string{"spec", "crossplane"}, path...)...); ok {
+			return v
+		}
+		v, _, _ := unstructured.NestedString(xr.Object, append([]string{"spec"}, path...)...)
+		return v
+	}
+	c := &CrossplaneComposition{
+		Name:         field("compositionRef", "name"),
+		Revision:     field("compositionRevisionRef", "name"),
+		UpdatePolicy: field("compositionUpdatePolicy"),
+	}
+	if c.Name == "" {
+		return nil
+	}
+	if c.UpdatePolicy == "" {
+		c.UpdatePolicy = "Automatic"
+	}
+
+	revs, err := t.client.Resource(compositionRevisionGVR).List(ctx, metav1.ListOptions{LabelSelector: "crossplane.io/composition-name=" + c.Name})
+	if err != nil {
+		return c
+	}
+	for _, rev := range revs.Items {
+		n, _, _ := unstructured.NestedInt64(rev.Object, "spec", "revision")
+		if rev.GetName() == c.Revision {
+			c.RevisionNumber = n
+		}
+		if n > c.LatestRevision {
+			c.LatestRevision = n
+		}
+	}
+	return c
+}
+
+// get fetches ref, mapping its kind to a resource through the XRDs, the
+// built-in kinds, or the lowercase plural Crossplane providers use.
+func (t *CrossplaneTracer) get(ctx context.Context, ref ResourceRef) *unstructured.Unstructured {
+	if ref.Name == "" || ref.Version == "" {
+		return nil
+	}
+	gvr := schema.GroupVersionResource{Group: ref.Group, Version: ref.Version}
+	if x := t.xrdFor(ref.Group, ref.Kind); x != nil {
+		gvr.Resource = x.plural
+		if ref.Kind == x.claimKind {
+			gvr.Resource = x.claimsPlural
+		}
+	} else if resource := KindToResource(ref.Kind); resource != "" {
+		gvr.Resource = resource
+	} else {
+		gvr.Resource = crossplanePlural(ref.Kind)
+	}
+	obj, err := t.client.Resource(gvr).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return obj
+}
+
+// crossplanePlural pluralizes a kind the way generated provider CRDs do.
+func crossplanePlural(kind string) string {
+	k := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(k, "s"), strings.HasSuffix(k, "x"):
+		return k + "es"
+	case strings.HasSuffix(k, "y") && !strings.HasSuffix(k, "ay") && !strings.HasSuffix(k, "ey"):
+		return strings.TrimSuffix(k, "y") + "ies"
+	}
+	return k + "s"
+}
+
+// xrdFor returns the XRD defining kind (as composite or claim) in group.
+func (t *CrossplaneTracer) xrdFor(group, kind string) *xrdNames {
+	t.loadXRDs()
+	for i := range t.xrds {
+		x := &t.xrds[i]
+		if x.group == group && (x.kind == kind || (x.claimKind != "" && x.claimKind == kind)) {
+			return x
+		}
+	}
+	return nil
+}
+
+// loadXRDs lists the XRDs once; without them only built-in kinds and
+// provider plurals can be fetched.
+func (t *CrossplaneTracer) loadXRDs() {
+	if t.loaded {
+		return
+	}
+	t.loaded = true
+	list, err := t.client.Resource(xrdGVR).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return
+	}
+	for _, x := range list.Items {
+		n := xrdNames{}
+		n.group, _, _ = unstructured.NestedString(x.Object, "spec", "group")
+		n.kind, _, _ = unstructured.NestedString(x.Object, "spec", "names", "kind")
+		n.plural, _, _ = unstructured.NestedString(x.Object, "spec", "names", "plural")
+		n.claimKind, _, _ = unstructured.NestedString(x.Object, "spec", "claimNames", "kind")
+		n.claimsPlural, _, _ = unstructured.NestedString(x.Object, "spec", "claimNames", "plural")
+		t.xrds = append(t.xrds, n)
+	}
+}
+
+// isComposite reports whether obj is an XR: a kind an XRD defines, or an
+// object with a composition reference.
+func (t *CrossplaneTracer) isComposite(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	if x := t.xrdFor(gvk.Group, gvk.Kind); x != nil {
+		return x.kind == gvk.Kind
+	}
+	for _, path := range [][]string{{"spec", "compositionRef"}, {"spec", "crossplane", "compositionRef"}} {
+		if _, ok, _ := unstructured.NestedMap(obj.Object, path...); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isClaim reports whether obj is a claim: a claim kind an XRD defines, or
+// an object bound to a composite through spec.resourceRef.
+func (t *CrossplaneTracer) isClaim(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	if x := t.xrdFor(gvk.Group, gvk.Kind); x != nil {
+		return x.claimKind == gvk.Kind
+	}
+	_, ok, _ := unstructured.NestedMap(obj.Object, "spec", "resourceRef")
+	return ok
+}
+
+// crossplaneChainLink reads a chain link's status. Crossplane objects
+// report Ready and Synced conditions; Synced=False means the last
+// reconcile failed even while the resource is still up.
+func crossplaneChainLink(obj *unstructured.Unstructured) ChainLink {
+	link := ChainLink{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace(), Ready: true}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var synced, ready string
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		status, _ := cond["status"].(string)
+		reason, _ := cond["reason"].(string)
+		msg, _ := cond["message"].(string)
+		switch cond["type"] {
+		case "Ready", "Available":
+			ready = status
+			if status != "True" {
+				link.Ready = false
+				link.StatusReason = reason
+				link.Message = msg
+			}
+		case "Synced":
+			synced = status
+			if status != "True" {
+				link.Ready = false
+				if link.Message == "" {
+					link.StatusReason, link.Message = reason, msg
+				}
+			}
+		}
+	}
+	switch {
+	case ready == "" && synced == "":
+		if replicas, ok, _ := unstructured.NestedInt64(obj.Object, "status", "replicas"); ok {
+			readyReplicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+			link.Status = fmt.Sprintf("%d/%d ready", readyReplicas, replicas)
+			link.Ready = readyReplicas == replicas && replicas > 0
+		}
+	case synced == "False":
+		link.Status = "Synced=False"
+	case ready == "True":
+		link.Status = "Ready"
+	default:
+		link.Status = "Not ready"
+	}
+	return link
+}
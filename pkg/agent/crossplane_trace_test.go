@@ -0,0 +1,125 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+// crossplaneFixtures is a claim in prod whose XR composes an RDS instance,
+// which writes a connection secret; the XR is pinned to revision 2 of 3.
+func crossplaneFixtures() []*unstructured.Unstructured {
+	set := func(value interface{}, fields ...string) agenttest.Option {
+		return func(u *unstructured.Unstructured) { _ = unstructured.SetNestedField(u.Object, value, fields...) }
+	}
+	claimLabels := agenttest.WithLabels(map[string]string{
+		"crossplane.io/composite":       "orders-db-x7k2p",
+		"crossplane.io/claim-name":      "orders-db",
+		"crossplane.io/claim-namespace": "prod",
+	})
+	revision := func(name string, n int64) *unstructured.Unstructured {
+		return agenttest.Object("apiextensions.crossplane.io/v1", "CompositionRevision", "", name,
+			agenttest.WithLabels(map[string]string{"crossplane.io/composition-name": "xpostgres"}),
+			set(n, "spec", "revision"))
+	}
+
+	return []*unstructured.Unstructured{
+		agenttest.Object("apiextensions.crossplane.io/v1", "CompositeResourceDefinition", "", "xpostgresinstances.db.example.org",
+			set("db.example.org", "spec", "group"),
+			set(map[string]interface{}{"kind": "XPostgresInstance", "plural": "xpostgresinstances"}, "spec", "names"),
+			set(map[string]interface{}{"kind": "PostgresInstance", "plural": "postgresinstances"}, "spec", "claimNames")),
+		revision("xpostgres-4b1d9", 1),
+		revision("xpostgres-8f3c2", 2),
+		revision("xpostgres-c0ffe", 3),
+		agenttest.Object("db.example.org/v1alpha1", "PostgresInstance", "prod", "orders-db",
+			set(map[string]interface{}{"apiVersion": "db.example.org/v1alpha1", "kind": "XPostgresInstance", "name": "orders-db-x7k2p"}, "spec", "resourceRef")),
+		agenttest.Object("db.example.org/v1alpha1", "XPostgresInstance", "", "orders-db-x7k2p", claimLabels,
+			set(map[string]interface{}{"apiVersion": "db.example.org/v1alpha1", "kind": "PostgresInstance", "name": "orders-db", "namespace": "prod"}, "spec", "claimRef"),
+			set(map[string]interface{}{"name": "xpostgres"}, "spec", "compositionRef"),
+			set(map[string]interface{}{"name": "xpostgres-8f3c2"}, "spec", "compositionRevisionRef"),
+			set("Manual", "spec", "compositionUpdatePolicy")),
+		agenttest.Object("rds.aws.upbound.io/v1beta1", "Instance", "", "orders-db-x7k2p-9qz4m", claimLabels,
+			agenttest.WithOwnerReference("db.example.org/v1alpha1", "XPostgresInstance", "orders-db-x7k2p")),
+		agenttest.Object("v1", "Secret", "prod", "orders-db-conn",
+			agenttest.WithOwnerReference("rds.aws.upbound.io/v1beta1", "Instance", "orders-db-x7k2p-9qz4m")),
+	}
+}
+
+func TestCrossplaneTracerLineage(t *testing.T) {
+	objs := crossplaneFixtures()
+	tracer := NewCrossplaneTracer(agenttest.FakeClient(objs...))
+	secret := objs[len(objs)-1]
+
+	lineage, ok := tracer.Lineage(context.Background(), secret)
+	if !ok {
+		t.Fatal("connection secret: want Crossplane lineage")
+	}
+	if got := lineage.Managed.Ref.String(); got != "Instance/orders-db-x7k2p-9qz4m" {
+		t.Errorf("Managed = %s, want the RDS instance", got)
+	}
+	if !lineage.Composite.Present || lineage.Composite.Ref.Kind != "XPostgresInstance" {
+		t.Errorf("Composite = %+v, want the XPostgresInstance", lineage.Composite)
+	}
+	if lineage.Claim == nil || !lineage.Claim.Present || lineage.Claim.Ref.String() != "PostgresInstance/orders-db in prod" {
+		t.Errorf("Claim = %+v, want PostgresInstance/orders-db in prod", lineage.Claim)
+	}
+
+	c := lineage.Composition
+	if c == nil {
+		t.Fatal("Composition = nil")
+	}
+	if c.Name != "xpostgres" || c.Revision != "xpostgres-8f3c2" || c.RevisionNumber != 2 || c.LatestRevision != 3 || c.UpdatePolicy != "Manual" {
+		t.Errorf("Composition = %+v", c)
+	}
+	if !c.Behind() {
+		t.Error("Behind() = false, want true (rev 2 of 3)")
+	}
+
+	if _, ok := tracer.Lineage(context.Background(), agenttest.Deployment("prod", "web")); ok {
+		t.Error("plain Deployment: want no Crossplane lineage")
+	}
+}
+
+func TestCrossplaneTracerTrace(t *testing.T) {
+	objs := crossplaneFixtures()
+	result, err := NewCrossplaneTracer(agenttest.FakeClient(objs...)).Trace(context.Background(), objs[len(objs)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"PostgresInstance/orders-db", "XPostgresInstance/orders-db-x7k2p", "Instance/orders-db-x7k2p-9qz4m", "Secret/orders-db-conn"}
+	if len(result.Chain) != len(want) {
+		t.Fatalf("Chain has %d links, want %d: %+v", len(result.Chain), len(want), result.Chain)
+	}
+	for i, link := range result.Chain {
+		if got := link.Kind + "/" + link.Name; got != want[i] {
+			t.Errorf("Chain[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+	if got := result.Chain[1].Revision; got != "xpostgres rev 2 (xpostgres-8f3c2)" {
+		t.Errorf("XR Revision = %q", got)
+	}
+	if result.Tool != "crossplane" || !result.FullyManaged {
+		t.Errorf("Tool = %q, FullyManaged = %v", result.Tool, result.FullyManaged)
+	}
+}
+
+func TestCrossplanePlural(t *testing.T) {
+	for kind, want := range map[string]string{
+		"Instance": "instances",
+		"Bucket":   "buckets",
+		"Policy":   "policies",
+		"Gateway":  "gateways",
+		"Address":  "addresses",
+	} {
+		if got := crossplanePlural(kind); got != want {
+			t.Errorf("crossplanePlural(%s) = %s, want %s", kind, got, want)
+		}
+	}
+}
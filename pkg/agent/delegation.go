@@ -0,0 +1,275 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Delegation describes a GitOps deployer that applies a ConfigHub OCI artifact.
+// ConfigHub is the source of truth; the deployer only performs the apply
+// (e.g., a Flux Kustomization fed by a FluxOCIWriter target).
+type Delegation struct {
+	// Via is the tool performing the apply ("Flux" or "ArgoCD")
+	Via string `json:"via"`
+
+	// DeployerKind is the kind of the applying object (e.g., "Kustomization")
+	DeployerKind string `json:"deployerKind"`
+
+	// DeployerName is the name of the applying object
+	DeployerName string `json:"deployerName"`
+
+	// DeployerNamespace is the namespace of the applying object
+	DeployerNamespace string `json:"deployerNamespace,omitempty"`
+
+	// SourceKind, SourceName and SourceNamespace identify the intermediate
+	// source (e.g., OCIRepository)
+	SourceKind      string `json:"sourceKind,omitempty"`
+	SourceName      string `json:"sourceName,omitempty"`
+	SourceNamespace string `json:"sourceNamespace,omitempty"`
+
+	// URL is the ConfigHub OCI URL being applied
+	URL string `json:"url"`
+
+	// Instance, Space and Target are parsed from the OCI URL
+	Instance string `json:"instance,omitempty"`
+	Space    string `json:"space,omitempty"`
+	Target   string `json:"target,omitempty"`
+}
+
+// OwnerLabel returns the display label for delegated resources,
+// e.g. "ConfigHub (delegated via Flux)".
+func (d *Delegation) OwnerLabel() string {
+	return "ConfigHub (delegated via " + d.Via + ")"
+}
+
+// UnitFor returns the ConfigHub unit that produced resource, from the
+// confighub.com/UnitSlug label carried through the artifact. Returns "" when
+// the unit cannot be determined; the target still identifies the artifact.
+func (d *Delegation) UnitFor(resource *unstructured.Unstructured) string {
+	if resource == nil {
+		return ""
+	}
+	if unit := resource.GetLabels()["confighub.com/UnitSlug"]; unit != "" {
+		return unit
+	}
+	return resource.GetAnnotations()["confighub.com/UnitSlug"]
+}
+
+// DelegationIndex maps GitOps deployers to the ConfigHub artifacts they apply
+type DelegationIndex struct {
+	byDeployer map[string]*Delegation
+}
+
+// NewDelegationIndex creates an empty index
+func NewDelegationIndex() *DelegationIndex {
+	return &DelegationIndex{byDeployer: map[string]*Delegation{}}
+}
+
+// Len returns the number of delegating deployers
+func (x *DelegationIndex) Len() int {
+	if x == nil {
+		return 0
+	}
+	return len(x.byDeployer)
+}
+
+// Delegations returns all indexed delegations, sorted by deployer
+func (x *DelegationIndex) Delegations() []*Delegation {
+	if x == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(x.byDeployer))
+	for key := range x.byDeployer {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	out := make([]*Delegation, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, x.byDeployer[key])
+	}
+	return out
+}
+
+func delegationKey(ownerType, namespace, name string) string {
+	return ownerType + "/" + namespace + "/" + name
+}
+
+// AddFluxKustomizations indexes Kustomizations whose sourceRef is an
+// OCIRepository pointing at a ConfigHub OCI registry.
+func (x *DelegationIndex) AddFluxKustomizations(kustomizations, ociRepositories []unstructured.Unstructured) {
+	urls := map[string]string{}
+	for _, repo := range ociRepositories {
+		url, _, _ := unstructured.NestedString(repo.Object, "spec", "url")
+		urls[repo.GetNamespace()+"/"+repo.GetName()] = url
+	}
+
+	for _, ks := range kustomizations {
+		kind, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "kind")
+		if kind != "OCIRepository" {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "name")
+		ns, _, _ := unstructured.NestedString(ks.Object, "spec", "sourceRef", "namespace")
+		if ns == "" {
+			ns = ks.GetNamespace()
+		}
+
+		info := ParseOCISource(urls[ns+"/"+name])
+		if !info.IsConfigHub {
+			continue
+		}
+		x.byDeployer[delegationKey(OwnerFlux, ks.GetNamespace(), ks.GetName())] = &Delegation{
+			Via:               "Flux",
+			DeployerKind:      "Kustomization",
+			DeployerName:      ks.GetName(),
+			DeployerNamespace: ks.GetNamespace(),
+			SourceKind:        "OCIRepository",
+			SourceName:        name,
+			SourceNamespace:   ns,
+			URL:               info.Raw,
+			Instance:          info.Instance,
+			Space:             info.Space,
+			Target:            info.Target,
+		}
+	}
+}
+
+// AddArgoApplications indexes Applications whose source (or any of their
+// sources) is a ConfigHub OCI registry. Applications are keyed by name, which
+// is all the argocd.argoproj.io/instance label records.
+func (x *DelegationIndex) AddArgoApplications(apps []unstructured.Unstructured) {
+	for _, app := range apps {
+		repoURLs := []string{}
+		if url, _, _ := unstructured.NestedString(app.Object, "spec", "source", "repoURL"); url != "" {
+			repoURLs = append(repoURLs, url)
+		}
+		sources, _, _ := unstructured.NestedSlice(app.Object, "spec", "sources")
+		for _, src := range sources {
+			if m, ok := src.(map[string]interface{}); ok {
+				if url, ok := m["repoURL"].(string); ok && url != "" {
+					repoURLs = append(repoURLs, url)
+				}
+			}
+		}
+
+		for _, url := range repoURLs {
+			info := ParseOCISource(argoOCIURL(url))
+			if !info.IsConfigHub {
+				continue
+			}
+			x.byDeployer[delegationKey(OwnerArgo, "", app.GetName())] = &Delegation{
+				Via:               "ArgoCD",
+				DeployerKind:      "Application",
+				DeployerName:      app.GetName(),
+				DeployerNamespace: app.GetNamespace(),
+				URL:               info.Raw,
+				Instance:          info.Instance,
+				Space:             info.Space,
+				Target:            info.Target,
+			}
+			break
+		}
+	}
+}
+
+// argoOCIURL normalizes Argo repoURLs: Helm OCI sources omit the oci:// scheme
+// (e.g., "oci.api.confighub.com/target/prod/us-west").
+func argoOCIURL(repoURL string) string {
+	if strings.Contains(repoURL, "://") {
+		return repoURL
+	}
+	return "oci://" + repoURL
+}
+
+// Deployer returns the delegation for a deployer object by kind, or nil.
+func (x *DelegationIndex) Deployer(kind, namespace, name string) *Delegation {
+	switch kind {
+	case "Kustomization":
+		return x.Lookup(Ownership{Type: OwnerFlux, SubType: "kustomization", Name: name, Namespace: namespace})
+	case "Application":
+		return x.Lookup(Ownership{Type: OwnerArgo, SubType: "application", Name: name})
+	}
+	return nil
+}
+
+// Lookup returns the delegation behind a detected ownership, or nil if the
+// owning deployer does not apply a ConfigHub artifact.
+func (x *DelegationIndex) Lookup(ownership Ownership) *Delegation {
+	if x.Len() == 0 {
+		return nil
+	}
+	switch {
+	case ownership.Type == OwnerFlux && ownership.SubType == "kustomization":
+		if d, ok := x.byDeployer[delegationKey(OwnerFlux, ownership.Namespace, ownership.Name)]; ok {
+			return d
+		}
+		// Older Flux versions omit the namespace label; match by name when unambiguous
+		if ownership.Namespace == "" {
+			return x.lookupByName(OwnerFlux, ownership.Name)
+		}
+	case ownership.Type == OwnerArgo:
+		return x.byDeployer[delegationKey(OwnerArgo, "", ownership.Name)]
+	}
+	return nil
+}
+
+func (x *DelegationIndex) lookupByName(ownerType, name string) *Delegation {
+	var found *Delegation
+	for key, d := range x.byDeployer {
+		if d.DeployerName != name || key != delegationKey(ownerType, d.DeployerNamespace, name) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = d
+	}
+	return found
+}
+
+// DetectDelegation returns the delegation for resource, or nil if it is not
+// applied from a ConfigHub artifact.
+func (x *DelegationIndex) DetectDelegation(resource *unstructured.Unstructured) *Delegation {
+	if x.Len() == 0 || resource == nil {
+		return nil
+	}
+	return x.Lookup(DetectOwnership(resource))
+}
+
+// DelegationFromChain returns the delegation in a traced chain: a ConfigHub
+// OCI source link followed by the Kustomization or Application applying it.
+// Returns nil for chains that do not start from ConfigHub.
+func DelegationFromChain(chain []ChainLink) *Delegation {
+	for i, link := range chain {
+		if link.OCISource == nil || !link.OCISource.IsConfigHub {
+			continue
+		}
+		for _, next := range chain[i+1:] {
+			d := &Delegation{
+				DeployerKind:      next.Kind,
+				DeployerName:      next.Name,
+				DeployerNamespace: next.Namespace,
+				URL:               link.OCISource.Raw,
+				Instance:          link.OCISource.Instance,
+				Space:             link.OCISource.Space,
+				Target:            link.OCISource.Target,
+			}
+			switch next.Kind {
+			case "Kustomization":
+				d.Via = "Flux"
+				d.SourceKind = "OCIRepository"
+			case "Application":
+				d.Via = "ArgoCD"
+			default:
+				continue
+			}
+			return d
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func crdObject(kind, namespace, name string, spec map[string]interface{}) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     kind,
+		"metadata": map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":     spec,
+	}}
+}
+
+func TestDelegationIndexFluxKustomizations(t *testing.T) {
+	kustomizations := []unstructured.Unstructured{
+		crdObject("Kustomization", "flux-system", "prod-apps", map[string]interface{}{
+			"sourceRef": map[string]interface{}{"kind": "OCIRepository", "name": "confighub-prod"},
+		}),
+		crdObject("Kustomization", "flux-system", "ghcr-apps", map[string]interface{}{
+			"sourceRef": map[string]interface{}{"kind": "OCIRepository", "name": "ghcr"},
+		}),
+		crdObject("Kustomization", "flux-system", "git-apps", map[string]interface{}{
+			"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "flux-system"},
+		}),
+	}
+	repos := []unstructured.Unstructured{
+		crdObject("OCIRepository", "flux-system", "confighub-prod", map[string]interface{}{
+			"url": "oci://oci.api.confighub.com/target/prod/us-west",
+		}),
+		crdObject("OCIRepository", "flux-system", "ghcr", map[string]interface{}{
+			"url": "oci://ghcr.io/my-org/my-repo",
+		}),
+	}
+
+	idx := NewDelegationIndex()
+	idx.AddFluxKustomizations(kustomizations, repos)
+	if idx.Len() != 1 {
+		t.Fatalf("expected 1 delegation, got %d", idx.Len())
+	}
+
+	d := idx.Lookup(Ownership{Type: OwnerFlux, SubType: "kustomization", Name: "prod-apps", Namespace: "flux-system"})
+	if d == nil {
+		t.Fatal("expected delegation for prod-apps")
+	}
+	if d.Via != "Flux" || d.Space != "prod" || d.Target != "us-west" || d.SourceName != "confighub-prod" {
+		t.Errorf("unexpected delegation: %+v", d)
+	}
+	if got := d.OwnerLabel(); got != "ConfigHub (delegated via Flux)" {
+		t.Errorf("OwnerLabel() = %q", got)
+	}
+
+	// Missing namespace label falls back to an unambiguous name match
+	if idx.Lookup(Ownership{Type: OwnerFlux, SubType: "kustomization", Name: "prod-apps"}) == nil {
+		t.Error("expected name-only lookup to match")
+	}
+	for _, name := range []string{"ghcr-apps", "git-apps"} {
+		if idx.Lookup(Ownership{Type: OwnerFlux, SubType: "kustomization", Name: name, Namespace: "flux-system"}) != nil {
+			t.Errorf("%s should not be delegated", name)
+		}
+	}
+}
+
+func TestDelegationDetectAndUnit(t *testing.T) {
+	idx := NewDelegationIndex()
+	idx.AddFluxKustomizations(
+		[]unstructured.Unstructured{crdObject("Kustomization", "flux-system", "apps", map[string]interface{}{
+			"sourceRef": map[string]interface{}{"kind": "OCIRepository", "name": "hub", "namespace": "sources"},
+		})},
+		[]unstructured.Unstructured{crdObject("OCIRepository", "sources", "hub", map[string]interface{}{
+			"url": "oci://oci.localhost:8080/target/qa/qa-cluster",
+		})},
+	)
+
+	resource := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Deployment",
+		"metadata": map[string]interface{}{
+			"name": "api",
+			"labels": map[string]interface{}{
+				"kustomize.toolkit.fluxcd.io/name":      "apps",
+				"kustomize.toolkit.fluxcd.io/namespace": "flux-system",
+				"confighub.com/UnitSlug":                "api",
+			},
+		},
+	}}
+	d := idx.DetectDelegation(resource)
+	if d == nil {
+		t.Fatal("expected delegation")
+	}
+	if unit := d.UnitFor(resource); unit != "api" {
+		t.Errorf("UnitFor() = %q, want api", unit)
+	}
+
+	var empty *DelegationIndex
+	if empty.DetectDelegation(resource) != nil {
+		t.Error("nil index should not detect delegation")
+	}
+}
+
+func TestDelegationIndexArgoApplications(t *testing.T) {
+	apps := []unstructured.Unstructured{
+		crdObject("Application", "argocd", "prod", map[string]interface{}{
+			"source": map[string]interface{}{"repoURL": "oci://oci.api.confighub.com/target/prod/us-west"},
+		}),
+		crdObject("Application", "argocd", "multi", map[string]interface{}{
+			"sources": []interface{}{
+				map[string]interface{}{"repoURL": "https://github.com/org/values"},
+				map[string]interface{}{"repoURL": "oci.api.confighub.com/target/staging/eu", "chart": "app"},
+			},
+		}),
+		crdObject("Application", "argocd", "git", map[string]interface{}{
+			"source": map[string]interface{}{"repoURL": "https://github.com/org/repo"},
+		}),
+	}
+
+	idx := NewDelegationIndex()
+	idx.AddArgoApplications(apps)
+	if idx.Len() != 2 {
+		t.Fatalf("expected 2 delegations, got %d", idx.Len())
+	}
+
+	d := idx.Lookup(Ownership{Type: OwnerArgo, SubType: "application", Name: "prod"})
+	if d == nil || d.Via != "ArgoCD" || d.Space != "prod" || d.Target != "us-west" || d.DeployerNamespace != "argocd" {
+		t.Errorf("unexpected delegation: %+v", d)
+	}
+	if d := idx.Deployer("Application", "argocd", "multi"); d == nil || d.Space != "staging" {
+		t.Errorf("expected multi-source delegation, got %+v", d)
+	}
+	if idx.Deployer("Application", "argocd", "git") != nil {
+		t.Error("git Application should not be delegated")
+	}
+}
+
+func TestDelegationFromChain(t *testing.T) {
+	oci := ParseOCISource("oci://oci.api.confighub.com/target/prod/us-west")
+	chain := []ChainLink{
+		{Kind: "ConfigHub OCI", Name: "prod/us-west", OCISource: &oci},
+		{Kind: "Application", Name: "prod", Namespace: "argocd"},
+		{Kind: "Deployment", Name: "api"},
+	}
+	d := DelegationFromChain(chain)
+	if d == nil || d.Via != "ArgoCD" || d.DeployerName != "prod" || d.Target != "us-west" {
+		t.Errorf("unexpected delegation: %+v", d)
+	}
+
+	git := []ChainLink{{Kind: "GitRepository", Name: "flux-system"}, {Kind: "Kustomization", Name: "apps"}}
+	if DelegationFromChain(git) != nil {
+		t.Error("git chain should not be delegated")
+	}
+}
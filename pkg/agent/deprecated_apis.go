@@ -0,0 +1,460 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RemovedAPI is a Kubernetes API version that is removed in a release.
+type RemovedAPI struct {
+	APIVersion string
+	Kind       string
+	// RemovedIn is the Kubernetes minor release that stops serving it ("1.25")
+	RemovedIn string
+	// Replacement is the apiVersion to migrate to, empty if the kind is gone
+	Replacement string
+	// Resource is the plural resource name, used to list live objects
+	Resource string
+}
+
+// RemovedAPIs lists the API versions removed from Kubernetes, after the
+// Kubernetes deprecation guide (kubernetes.io/docs/reference/using-api/deprecation-guide).
+var RemovedAPIs = []RemovedAPI{
+	// v1.16
+	{"extensions/v1beta1", "Deployment", "1.16", "apps/v1", "deployments"},
+	{"extensions/v1beta1", "DaemonSet", "1.16", "apps/v1", "daemonsets"},
+	{"extensions/v1beta1", "ReplicaSet", "1.16", "apps/v1", "replicasets"},
+	{"extensions/v1beta1", "NetworkPolicy", "1.16", "networking.k8s.io/v1", "networkpolicies"},
+	{"apps/v1beta1", "Deployment", "1.16", "apps/v1", "deployments"},
+	{"apps/v1beta1", "StatefulSet", "1.16", "apps/v1", "statefulsets"},
+	{"apps/v1beta2", "Deployment", "1.16", "apps/v1", "deployments"},
+	{"apps/v1beta2", "StatefulSet", "1.16", "apps/v1", "statefulsets"},
+	{"apps/v1beta2", "DaemonSet", "1.16", "apps/v1", "daemonsets"},
+	{"apps/v1beta2", "ReplicaSet", "1.16", "apps/v1", "replicasets"},
+
+	// v1.22
+	{"extensions/v1beta1", "Ingress", "1.22", "networking.k8s.io/v1", "ingresses"},
+	{"networking.k8s.io/v1beta1", "Ingress", "1.22", "networking.k8s.io/v1", "ingresses"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "1.22", "networking.k8s.io/v1", "ingressclasses"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "1.22", "apiextensions.k8s.io/v1", "customresourcedefinitions"},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "1.22", "admissionregistration.k8s.io/v1", "mutatingwebhookconfigurations"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "1.22", "admissionregistration.k8s.io/v1", "validatingwebhookconfigurations"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "1.22", "apiregistration.k8s.io/v1", "apiservices"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "1.22", "rbac.authorization.k8s.io/v1", "clusterroles"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "1.22", "rbac.authorization.k8s.io/v1", "clusterrolebindings"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "1.22", "rbac.authorization.k8s.io/v1", "roles"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "1.22", "rbac.authorization.k8s.io/v1", "rolebindings"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "1.22", "scheduling.k8s.io/v1", "priorityclasses"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", "1.22", "storage.k8s.io/v1", "csidrivers"},
+	{"storage.k8s.io/v1beta1", "CSINode", "1.22", "storage.k8s.io/v1", "csinodes"},
+	{"storage.k8s.io/v1beta1", "StorageClass", "1.22", "storage.k8s.io/v1", "storageclasses"},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", "1.22", "storage.k8s.io/v1", "volumeattachments"},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", "1.22", "certificates.k8s.io/v1", "certificatesigningrequests"},
+	{"coordination.k8s.io/v1beta1", "Lease", "1.22", "coordination.k8s.io/v1", "leases"},
+
+	// v1.25
+	{"batch/v1beta1", "CronJob", "1.25", "batch/v1", "cronjobs"},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", "1.25", "discovery.k8s.io/v1", "endpointslices"},
+	{"events.k8s.io/v1beta1", "Event", "1.25", "events.k8s.io/v1", "events"},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "1.25", "autoscaling/v2", "horizontalpodautoscalers"},
+	{"policy/v1beta1", "PodDisruptionBudget", "1.25", "policy/v1", "poddisruptionbudgets"},
+	{"policy/v1beta1", "PodSecurityPolicy", "1.25", "", "podsecuritypolicies"},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "1.25", "node.k8s.io/v1", "runtimeclasses"},
+
+	// v1.26
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "1.26", "flowcontrol.apiserver.k8s.io/v1", "flowschemas"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "1.26", "flowcontrol.apiserver.k8s.io/v1", "prioritylevelconfigurations"},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "1.26", "autoscaling/v2", "horizontalpodautoscalers"},
+
+	// v1.27
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "1.27", "storage.k8s.io/v1", "csistoragecapacities"},
+
+	// v1.29
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "1.29", "flowcontrol.apiserver.k8s.io/v1", "flowschemas"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "1.29", "flowcontrol.apiserver.k8s.io/v1", "prioritylevelconfigurations"},
+
+	// v1.32
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "1.32", "flowcontrol.apiserver.k8s.io/v1", "flowschemas"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "1.32", "flowcontrol.apiserver.k8s.io/v1", "prioritylevelconfigurations"},
+}
+
+// LookupRemovedAPI returns the removal entry for apiVersion and kind, or nil.
+func LookupRemovedAPI(apiVersion, kind string) *RemovedAPI {
+	for i := range RemovedAPIs {
+		if RemovedAPIs[i].APIVersion == apiVersion && RemovedAPIs[i].Kind == kind {
+			return &RemovedAPIs[i]
+		}
+	}
+	return nil
+}
+
+// DeprecatedAPIFinding is a resource declared or applied with an API that is
+// removed by the target Kubernetes version.
+type DeprecatedAPIFinding struct {
+	// Severity is critical when the cluster no longer serves the API (the
+	// next apply from the source fails), warning when an upgrade removes it
+	Severity    string `json:"severity"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace,omitempty"`
+	APIVersion  string `json:"apiVersion"`
+	RemovedIn   string `json:"removedIn"`
+	Replacement string `json:"replacement,omitempty"`
+	// Source is where the API version was found: last-applied, Flux
+	// inventory, Argo CD, Helm release
+	Source string `json:"source"`
+	// Owner, OwnerName and Unit say who should make the fix
+	Owner     string `json:"owner"`
+	OwnerName string `json:"ownerName,omitempty"`
+	Unit      string `json:"unit,omitempty"`
+	Message   string `json:"message"`
+}
+
+// DeprecatedAPIResult contains removed-API findings up to TargetVersion.
+type DeprecatedAPIResult struct {
+	ServerVersion string                 `json:"serverVersion,omitempty"`
+	TargetVersion string                 `json:"targetVersion,omitempty"`
+	Findings      []DeprecatedAPIFinding `json:"findings"`
+	Summary       struct {
+		Total    int `json:"total"`
+		Removed  int `json:"removed"`
+		Upcoming int `json:"upcoming"`
+	} `json:"summary"`
+}
+
+const (
+	apiSourceLastApplied = "last-applied"
+	apiSourceFlux        = "Flux inventory"
+	apiSourceArgo        = "Argo CD"
+	apiSourceHelm        = "Helm release"
+)
+
+// minorVersion parses the minor release from "1.29", "v1.29.3" or "1.29+".
+func ParseMinorVersion(v string) (int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 || parts[0] != "1" {
+		return 0, false
+	}
+	minor, err := strconv.Atoi(strings.TrimRight(parts[1], "+"))
+	return minor, err == nil
+}
+
+// apiUsage is one place a resource's apiVersion was recorded.
+type apiUsage struct {
+	apiVersion, kind, namespace, name string
+	source, owner, ownerName, unit    string
+}
+
+// deprecatedAPICollector turns usages into findings for APIs removed by the
+// target version, one finding per resource and API version.
+type deprecatedAPICollector struct {
+	server, target int // minor versions; 0 when unknown
+	byKey          map[string]*DeprecatedAPIFinding
+	order          []string
+}
+
+func newDeprecatedAPICollector(serverVersion, targetVersion string) *deprecatedAPICollector {
+	c := &deprecatedAPICollector{byKey: map[string]*DeprecatedAPIFinding{}}
+	c.server, _ = ParseMinorVersion(serverVersion)
+	c.target, _ = ParseMinorVersion(targetVersion)
+	return c
+}
+
+func (c *deprecatedAPICollector) add(u apiUsage) {
+	api := LookupRemovedAPI(u.apiVersion, u.kind)
+	if api == nil {
+		return
+	}
+	removed, _ := ParseMinorVersion(api.RemovedIn)
+	if c.target != 0 && removed > c.target {
+		return
+	}
+
+	key := u.apiVersion + "/" + u.kind + "/" + u.namespace + "/" + u.name
+	if existing, ok := c.byKey[key]; ok {
+		// A GitOps source is the root cause; keep it over the live annotation
+		if existing.Source == apiSourceLastApplied && u.source != apiSourceLastApplied {
+			if u.unit == "" {
+				u.unit = existing.Unit
+			}
+			*existing = c.finding(u, api, removed)
+		}
+		return
+	}
+	f := c.finding(u, api, removed)
+	c.byKey[key] = &f
+	c.order = append(c.order, key)
+}
+
+func (c *deprecatedAPICollector) finding(u apiUsage, api *RemovedAPI, removed int) DeprecatedAPIFinding {
+	f := DeprecatedAPIFinding{
+		Severity:    "warning",
+		Kind:        u.kind,
+		Name:        u.name,
+		Namespace:   u.namespace,
+		APIVersion:  u.apiVersion,
+		RemovedIn:   api.RemovedIn,
+		Replacement: api.Replacement,
+		Source:      u.source,
+		Owner:       u.owner,
+		OwnerName:   u.ownerName,
+		Unit:        u.unit,
+	}
+	if c.server != 0 && removed <= c.server {
+		f.Severity = "critical"
+		f.Message = fmt.Sprintf("%s %s was removed in Kubernetes %s and is not served by this cluster", u.apiVersion, u.kind, api.RemovedIn)
+	} else {
+		f.Message = fmt.Sprintf("%s %s is removed in Kubernetes %s", u.apiVersion, u.kind, api.RemovedIn)
+	}
+	if api.Replacement != "" {
+		f.Message += "; migrate to " + api.Replacement
+	} else {
+		f.Message += "; it has no replacement"
+	}
+	return f
+}
+
+func (c *deprecatedAPICollector) result(serverVersion, targetVersion string) *DeprecatedAPIResult {
+	result := &DeprecatedAPIResult{ServerVersion: serverVersion, TargetVersion: targetVersion, Findings: []DeprecatedAPIFinding{}}
+	for _, key := range c.order {
+		f := *c.byKey[key]
+		result.Findings = append(result.Findings, f)
+		if f.Severity == "critical" {
+			result.Summary.Removed++
+		} else {
+			result.Summary.Upcoming++
+		}
+	}
+	result.Summary.Total = len(result.Findings)
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		a, b := result.Findings[i], result.Findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity == "critical"
+		}
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return a.OwnerName < b.OwnerName
+	})
+	return result
+}
+
+// ScanDeprecatedAPIs finds resources using APIs removed by targetVersion
+// (all known removals when empty), in live objects' last-applied
+// configuration, Flux Kustomization inventories, Argo CD Application
+// resource lists and deployed Helm release manifests. serverVersion is the
+// cluster's version; APIs it no longer serves are critical.
+func (s *StateScanner) ScanDeprecatedAPIs(ctx context.Context, serverVersion, targetVersion string) (*DeprecatedAPIResult, error) {
+	c := newDeprecatedAPICollector(serverVersion, targetVersion)
+	list := func(gvr schema.GroupVersionResource, opts v1.ListOptions) []unstructured.Unstructured {
+		l, err := s.client.Resource(gvr).List(ctx, opts)
+		if err != nil {
+			return nil // API not served or no access
+		}
+		return l.Items
+	}
+
+	// Live objects, through the API that replaced the removed one
+	listed := map[schema.GroupVersionResource]bool{}
+	for _, api := range RemovedAPIs {
+		if api.Replacement == "" {
+			continue
+		}
+		gv, _ := schema.ParseGroupVersion(api.Replacement)
+		gvr := gv.WithResource(api.Resource)
+		if listed[gvr] {
+			continue
+		}
+		listed[gvr] = true
+		items := list(gvr, v1.ListOptions{})
+		for i := range items {
+			if u, ok := lastAppliedUsage(&items[i]); ok {
+				c.add(u)
+			}
+		}
+	}
+
+	for _, ks := range list(schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}, v1.ListOptions{}) {
+		for _, u := range fluxInventoryUsages(&ks) {
+			c.add(u)
+		}
+	}
+	for _, app := range list(schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}, v1.ListOptions{}) {
+		for _, u := range argoResourceUsages(&app) {
+			c.add(u)
+		}
+	}
+	for _, secret := range list(schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, v1.ListOptions{LabelSelector: "owner=helm,status=deployed"}) {
+		usages, err := helmReleaseUsages(&secret)
+		if err != nil {
+			continue // not a readable Helm release
+		}
+		for _, u := range usages {
+			c.add(u)
+		}
+	}
+
+	return c.result(serverVersion, targetVersion), nil
+}
+
+// lastAppliedUsage reads the apiVersion from an object's
+// kubectl.kubernetes.io/last-applied-configuration annotation.
+func lastAppliedUsage(obj *unstructured.Unstructured) (apiUsage, bool) {
+	raw := obj.GetAnnotations()["kubectl.kubernetes.io/last-applied-configuration"]
+	if raw == "" {
+		return apiUsage{}, false
+	}
+	var applied struct {
+		APIVersion string `json:"apiVersion"`
+	}
+	if err := json.Unmarshal([]byte(raw), &applied); err != nil || applied.APIVersion == "" {
+		return apiUsage{}, false
+	}
+	ownership := DetectOwnership(obj)
+	u := apiUsage{
+		apiVersion: applied.APIVersion,
+		kind:       obj.GetKind(),
+		namespace:  obj.GetNamespace(),
+		name:       obj.GetName(),
+		source:     apiSourceLastApplied,
+		owner:      ownership.Type,
+		ownerName:  ownership.Name,
+	}
+	if ownership.Type == OwnerConfigHub {
+		u.unit = ownership.Name
+	}
+	return u, true
+}
+
+// fluxInventoryUsages reads status.inventory of a Flux Kustomization, whose
+// entries record the API version applied from Git:
+// {"id": "<namespace>_<name>_<group>_<kind>", "v": "<version>"}.
+func fluxInventoryUsages(ks *unstructured.Unstructured) []apiUsage {
+	entries, _, _ := unstructured.NestedSlice(ks.Object, "status", "inventory", "entries")
+	var usages []apiUsage
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := entry["id"].(string)
+		version, _ := entry["v"].(string)
+		parts := strings.Split(id, "_")
+		if len(parts) != 4 || version == "" {
+			continue
+		}
+		apiVersion := version
+		if parts[2] != "" {
+			apiVersion = parts[2] + "/" + version
+		}
+		usages = append(usages, apiUsage{
+			apiVersion: apiVersion,
+			kind:       parts[3],
+			namespace:  parts[0],
+			name:       parts[1],
+			source:     apiSourceFlux,
+			owner:      OwnerFlux,
+			ownerName:  "Kustomization/" + ks.GetNamespace() + "/" + ks.GetName(),
+		})
+	}
+	return usages
+}
+
+// argoResourceUsages reads status.resources of an Argo CD Application.
+func argoResourceUsages(app *unstructured.Unstructured) []apiUsage {
+	resources, _, _ := unstructured.NestedSlice(app.Object, "status", "resources")
+	var usages []apiUsage
+	for _, r := range resources {
+		res, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _ := res["group"].(string)
+		version, _ := res["version"].(string)
+		kind, _ := res["kind"].(string)
+		namespace, _ := res["namespace"].(string)
+		name, _ := res["name"].(string)
+		if version == "" {
+			continue
+		}
+		apiVersion := version
+		if group != "" {
+			apiVersion = group + "/" + version
+		}
+		usages = append(usages, apiUsage{
+			apiVersion: apiVersion,
+			kind:       kind,
+			namespace:  namespace,
+			name:       name,
+			source:     apiSourceArgo,
+			owner:      OwnerArgo,
+			ownerName:  "Application/" + app.GetNamespace() + "/" + app.GetName(),
+		})
+	}
+	return usages
+}
+
+// helmReleaseUsages decodes a Helm release secret and reads the apiVersion
+// of each rendered manifest.
+func helmReleaseUsages(secret *unstructured.Unstructured) ([]apiUsage, error) {
+	encoded, _, _ := unstructured.NestedString(secret.Object, "data", "release")
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	release, err := (&HelmTracer{}).decodeRelease(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []apiUsage
+	dec := yaml.NewDecoder(strings.NewReader(release.Manifest))
+	for {
+		var doc struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Metadata   struct {
+				Name      string `yaml:"name"`
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+		}
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return usages, nil // keep what parsed
+		}
+		if doc.Kind == "" {
+			continue
+		}
+		namespace := doc.Metadata.Namespace
+		if namespace == "" && LookupRemovedAPI(doc.APIVersion, doc.Kind) != nil {
+			namespace = release.Namespace
+		}
+		usages = append(usages, apiUsage{
+			apiVersion: doc.APIVersion,
+			kind:       doc.Kind,
+			namespace:  namespace,
+			name:       doc.Metadata.Name,
+			source:     apiSourceHelm,
+			owner:      OwnerHelm,
+			ownerName:  release.Namespace + "/" + release.Name,
+		})
+	}
+	return usages, nil
+}
@@ -0,0 +1,172 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseMinorVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"1.29", 29, true},
+		{"v1.30.2", 30, true},
+		{"1.28+", 28, true},
+		{"", 0, false},
+		{"2.0", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseMinorVersion(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseMinorVersion(%q) = %d, %v; want %d, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDeprecatedAPICollector(t *testing.T) {
+	c := newDeprecatedAPICollector("v1.25.4", "1.26")
+
+	// Removed before the server version: critical
+	c.add(apiUsage{apiVersion: "batch/v1beta1", kind: "CronJob", namespace: "jobs", name: "report", source: apiSourceLastApplied, owner: OwnerConfigHub, ownerName: "report", unit: "report"})
+	// Same object found in the Flux inventory: replaces the live finding, keeps the unit
+	c.add(apiUsage{apiVersion: "batch/v1beta1", kind: "CronJob", namespace: "jobs", name: "report", source: apiSourceFlux, owner: OwnerFlux, ownerName: "Kustomization/flux-system/jobs"})
+	// Removed by the target version: warning
+	c.add(apiUsage{apiVersion: "autoscaling/v2beta2", kind: "HorizontalPodAutoscaler", namespace: "web", name: "api", source: apiSourceHelm, owner: OwnerHelm, ownerName: "web/api"})
+	// Removed after the target version: ignored
+	c.add(apiUsage{apiVersion: "flowcontrol.apiserver.k8s.io/v1beta2", kind: "FlowSchema", name: "custom", source: apiSourceArgo, owner: OwnerArgo})
+	// Current API: ignored
+	c.add(apiUsage{apiVersion: "apps/v1", kind: "Deployment", namespace: "web", name: "api", source: apiSourceLastApplied})
+
+	result := c.result("v1.25.4", "1.26")
+	if result.Summary.Total != 2 || result.Summary.Removed != 1 || result.Summary.Upcoming != 1 {
+		t.Fatalf("summary = %+v, findings = %+v", result.Summary, result.Findings)
+	}
+
+	cron := result.Findings[0]
+	if cron.Severity != "critical" || cron.Source != apiSourceFlux || cron.Unit != "report" || cron.Replacement != "batch/v1" {
+		t.Errorf("cronjob finding = %+v", cron)
+	}
+	hpa := result.Findings[1]
+	if hpa.Severity != "warning" || hpa.RemovedIn != "1.26" || hpa.OwnerName != "web/api" {
+		t.Errorf("hpa finding = %+v", hpa)
+	}
+}
+
+func TestDeprecatedAPICollectorUnknownVersion(t *testing.T) {
+	c := newDeprecatedAPICollector("", "")
+	c.add(apiUsage{apiVersion: "flowcontrol.apiserver.k8s.io/v1beta3", kind: "FlowSchema", name: "custom"})
+	c.add(apiUsage{apiVersion: "policy/v1beta1", kind: "PodSecurityPolicy", name: "restricted"})
+
+	result := c.result("", "")
+	if result.Summary.Total != 2 || result.Summary.Removed != 0 {
+		t.Fatalf("summary = %+v", result.Summary)
+	}
+	for _, f := range result.Findings {
+		if f.Kind == "PodSecurityPolicy" && f.Message != "policy/v1beta1 PodSecurityPolicy is removed in Kubernetes 1.25; it has no replacement" {
+			t.Errorf("psp message = %q", f.Message)
+		}
+	}
+}
+
+func TestLastAppliedUsage(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "networking.k8s.io/v1", "kind": "Ingress"}}
+	obj.SetNamespace("web")
+	obj.SetName("api")
+	obj.SetLabels(map[string]string{"app.kubernetes.io/managed-by": "Helm"})
+	obj.SetAnnotations(map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"extensions/v1beta1","kind":"Ingress"}`,
+		"meta.helm.sh/release-name":                        "api",
+	})
+
+	u, ok := lastAppliedUsage(obj)
+	if !ok {
+		t.Fatal("expected usage from last-applied-configuration")
+	}
+	if u.apiVersion != "extensions/v1beta1" || u.kind != "Ingress" || u.owner != OwnerHelm {
+		t.Errorf("usage = %+v", u)
+	}
+
+	obj.SetAnnotations(nil)
+	if _, ok := lastAppliedUsage(obj); ok {
+		t.Error("expected no usage without last-applied-configuration")
+	}
+}
+
+func TestFluxInventoryUsages(t *testing.T) {
+	ks := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"inventory": map[string]interface{}{"entries": []interface{}{
+			map[string]interface{}{"id": "jobs_report_batch_CronJob", "v": "v1beta1"},
+			map[string]interface{}{"id": "jobs_report-config__ConfigMap", "v": "v1"},
+			map[string]interface{}{"id": "malformed", "v": "v1"},
+		}}},
+	}}
+	ks.SetNamespace("flux-system")
+	ks.SetName("jobs")
+
+	usages := fluxInventoryUsages(ks)
+	if len(usages) != 2 {
+		t.Fatalf("got %d usages: %+v", len(usages), usages)
+	}
+	if u := usages[0]; u.apiVersion != "batch/v1beta1" || u.kind != "CronJob" || u.namespace != "jobs" || u.ownerName != "Kustomization/flux-system/jobs" {
+		t.Errorf("cronjob usage = %+v", u)
+	}
+	if u := usages[1]; u.apiVersion != "v1" || u.name != "report-config" {
+		t.Errorf("configmap usage = %+v", u)
+	}
+}
+
+func TestArgoResourceUsages(t *testing.T) {
+	app := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"resources": []interface{}{
+			map[string]interface{}{"group": "policy", "version": "v1beta1", "kind": "PodDisruptionBudget", "namespace": "web", "name": "api"},
+		}},
+	}}
+	app.SetNamespace("argocd")
+	app.SetName("web")
+
+	usages := argoResourceUsages(app)
+	if len(usages) != 1 || usages[0].apiVersion != "policy/v1beta1" || usages[0].owner != OwnerArgo || usages[0].ownerName != "Application/argocd/web" {
+		t.Errorf("usages = %+v", usages)
+	}
+}
+
+func TestHelmReleaseUsages(t *testing.T) {
+	release := &helmRelease{
+		Name:      "api",
+		Namespace: "web",
+		Manifest: `---
+# Source: api/templates/hpa.yaml
+apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: api
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: web
+`,
+	}
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"data": map[string]interface{}{"release": base64.StdEncoding.EncodeToString(encodeRelease(t, release))},
+	}}
+
+	usages, err := helmReleaseUsages(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usages) != 2 {
+		t.Fatalf("got %d usages: %+v", len(usages), usages)
+	}
+	if u := usages[0]; u.apiVersion != "autoscaling/v2beta2" || u.namespace != "web" || u.ownerName != "web/api" {
+		t.Errorf("hpa usage = %+v", u)
+	}
+}
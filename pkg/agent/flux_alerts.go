@@ -0,0 +1,295 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// fluxAlertedKinds are the deployers whose reconciliation failures alert
+// coverage is reported for.
+var fluxAlertedKinds = []string{"Kustomization", "HelmRelease"}
+
+// FluxEventSource is an Alert's eventSources entry or a Receiver's
+// resources entry. Name "*" selects every object of the kind in the
+// namespace, narrowed by MatchLabels.
+type FluxEventSource struct {
+	Kind        string            `json:"kind"`
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// String renders the source as "Kind/namespace/name".
+func (e FluxEventSource) String() string {
+	return e.Kind + "/" + e.Namespace + "/" + e.Name
+}
+
+// Matches reports whether the source selects the object.
+func (e FluxEventSource) Matches(kind, namespace, name string, labels map[string]string) bool {
+	if e.Kind != kind || e.Namespace != namespace {
+		return false
+	}
+	if e.Name != "*" {
+		return e.Name == name
+	}
+	for k, v := range e.MatchLabels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// FluxAlert is a notification-controller Alert: the events it forwards and
+// the Provider it sends them to.
+type FluxAlert struct {
+	FluxObjectRef
+
+	// Provider is spec.providerRef.name, in the Alert's namespace
+	Provider string `json:"provider"`
+
+	// ProviderType is the Provider's spec.type (slack, msteams, github, ...)
+	ProviderType string `json:"providerType,omitempty"`
+
+	// Severity is spec.eventSeverity: info forwards every event, error only
+	// failures. Either forwards reconciliation failures.
+	Severity string `json:"eventSeverity"`
+
+	Sources   []FluxEventSource `json:"eventSources"`
+	Suspended bool              `json:"suspended,omitempty"`
+
+	// Problem is why the Alert delivers nothing, e.g. "suspended" or
+	// "provider slack not found"; "" when it delivers
+	Problem string `json:"problem,omitempty"`
+}
+
+// Delivers reports whether the Alert forwards events to a Provider.
+func (a FluxAlert) Delivers() bool {
+	return a.Problem == ""
+}
+
+// FluxReceiver is a notification-controller Receiver: a webhook that
+// triggers reconciliation of its resources.
+type FluxReceiver struct {
+	FluxObjectRef
+	Type      string            `json:"type"`
+	Resources []FluxEventSource `json:"resources"`
+	Ready     bool              `json:"ready"`
+	Message   string            `json:"message,omitempty"`
+}
+
+// FluxAlertCoverage is which delivering Alerts forward a deployer's
+// failures. A deployer no Alert covers fails silently.
+type FluxAlertCoverage struct {
+	FluxObjectRef
+	Ready  bool     `json:"ready"`
+	Alerts []string `json:"alerts,omitempty"`
+}
+
+// Silent reports whether no Alert forwards the deployer's failures.
+func (c FluxAlertCoverage) Silent() bool {
+	return len(c.Alerts) == 0
+}
+
+// FluxAlerting is the notification-controller configuration of a cluster
+// and the alert coverage of its Kustomizations and HelmReleases.
+type FluxAlerting struct {
+	// Installed is false when the cluster doesn't serve the Alert API
+	Installed bool `json:"installed"`
+
+	Alerts    []FluxAlert         `json:"alerts,omitempty"`
+	Providers int                 `json:"providers"`
+	Receivers []FluxReceiver      `json:"receivers,omitempty"`
+	Coverage  []FluxAlertCoverage `json:"coverage,omitempty"`
+}
+
+// Silent returns the deployers no Alert covers.
+func (a FluxAlerting) Silent() []FluxAlertCoverage {
+	var out []FluxAlertCoverage
+	for _, c := range a.Coverage {
+		if c.Silent() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+var fluxNotificationKinds = map[string]fluxKind{
+	"Alert":    {"notification.toolkit.fluxcd.io", "alerts", []string{"v1beta3", "v1beta2"}},
+	"Provider": {"notification.toolkit.fluxcd.io", "providers", []string{"v1beta3", "v1beta2"}},
+	"Receiver": {"notification.toolkit.fluxcd.io", "receivers", []string{"v1", "v1beta2"}},
+}
+
+// listFluxKind lists a kind in namespace ("" for all), trying the next API
+// version while one isn't served. When none is, the error is a NotFound.
+func listFluxKind(ctx context.Context, client dynamic.Interface, k fluxKind, namespace string) ([]unstructured.Unstructured, error) {
+	var err error
+	for _, version := range k.versions {
+		gvr := schema.GroupVersionResource{Group: k.group, Version: version, Resource: k.resource}
+		var list *unstructured.UnstructuredList
+		list, err = client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err == nil {
+			return list.Items, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			break
+		}
+	}
+	return nil, err
+}
+
+// ListFluxAlerting reads Alerts, Providers and Receivers cluster-wide, as
+// an Alert may watch other namespaces, and the alert coverage of the
+// Kustomizations and HelmReleases (at their current API version) in
+// namespace ("" for all). Without the Alert API every deployer is silent;
+// an error is returned only when the Alerts could not be read.
+func ListFluxAlerting(ctx context.Context, client dynamic.Interface, namespace string) (FluxAlerting, error) {
+	alerts, err := listFluxKind(ctx, client, fluxNotificationKinds["Alert"], "")
+	installed := err == nil
+	if err != nil && !apierrors.IsNotFound(err) {
+		return FluxAlerting{}, fmt.Errorf("list Flux Alerts: %w", err)
+	}
+	var providers, receivers []unstructured.Unstructured
+	if installed {
+		providers, _ = listFluxKind(ctx, client, fluxNotificationKinds["Provider"], "")
+		receivers, _ = listFluxKind(ctx, client, fluxNotificationKinds["Receiver"], "")
+	}
+
+	var deployers []unstructured.Unstructured
+	for _, kind := range fluxAlertedKinds {
+		k := fluxKinds[kind]
+		gvr := schema.GroupVersionResource{Group: k.group, Version: k.versions[0], Resource: k.resource}
+		list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			continue
+		}
+		for i := range list.Items {
+			list.Items[i].SetKind(kind)
+		}
+		deployers = append(deployers, list.Items...)
+	}
+	result := BuildFluxAlerting(alerts, providers, receivers, deployers)
+	result.Installed = installed
+	return result, nil
+}
+
+// BuildFluxAlerting resolves each Alert's Provider and the Alerts covering
+// each deployer.
+func BuildFluxAlerting(alerts, providers, receivers, deployers []unstructured.Unstructured) FluxAlerting {
+	result := FluxAlerting{Installed: true, Providers: len(providers)}
+
+	type provider struct {
+		typ       string
+		suspended bool
+	}
+	byName := map[string]provider{}
+	for i := range providers {
+		p := &providers[i]
+		typ, _, _ := unstructured.NestedString(p.Object, "spec", "type")
+		suspended, _, _ := unstructured.NestedBool(p.Object, "spec", "suspend")
+		byName[p.GetNamespace()+"/"+p.GetName()] = provider{typ, suspended}
+	}
+
+	for i := range alerts {
+		obj := &alerts[i]
+		a := FluxAlert{FluxObjectRef: FluxObjectRef{Kind: "Alert", Name: obj.GetName(), Namespace: obj.GetNamespace()}}
+		a.Provider, _, _ = unstructured.NestedString(obj.Object, "spec", "providerRef", "name")
+		a.Severity, _, _ = unstructured.NestedString(obj.Object, "spec", "eventSeverity")
+		if a.Severity == "" {
+			a.Severity = "info"
+		}
+		a.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+		a.Sources = fluxEventSources(obj, "eventSources")
+
+		p, found := byName[a.Namespace+"/"+a.Provider]
+		a.ProviderType = p.typ
+		switch {
+		case a.Suspended:
+			a.Problem = "suspended"
+		case !found:
+			a.Problem = "provider " + a.Provider + " not found"
+		case p.suspended:
+			a.Problem = "provider " + a.Provider + " suspended"
+		}
+		result.Alerts = append(result.Alerts, a)
+	}
+
+	for i := range receivers {
+		obj := &receivers[i]
+		r := FluxReceiver{FluxObjectRef: FluxObjectRef{Kind: "Receiver", Name: obj.GetName(), Namespace: obj.GetNamespace()}}
+		r.Type, _, _ = unstructured.NestedString(obj.Object, "spec", "type")
+		r.Resources = fluxEventSources(obj, "resources")
+		r.Ready, r.Message = FluxReady(obj)
+		result.Receivers = append(result.Receivers, r)
+	}
+
+	for i := range deployers {
+		obj := &deployers[i]
+		c := FluxAlertCoverage{FluxObjectRef: FluxObjectRef{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace()}}
+		c.Ready, _ = FluxReady(obj)
+		for _, a := range result.Alerts {
+			if !a.Delivers() {
+				continue
+			}
+			for _, src := range a.Sources {
+				if src.Matches(c.Kind, c.Namespace, c.Name, obj.GetLabels()) {
+					c.Alerts = append(c.Alerts, a.Namespace+"/"+a.Name)
+					break
+				}
+			}
+		}
+		result.Coverage = append(result.Coverage, c)
+	}
+	sort.Slice(result.Coverage, func(i, j int) bool {
+		return result.Coverage[i].String() < result.Coverage[j].String()
+	})
+	return result
+}
+
+// fluxEventSources reads spec.<field>, defaulting each namespace to obj's.
+func fluxEventSources(obj *unstructured.Unstructured, field string) []FluxEventSource {
+	items, _, _ := unstructured.NestedSlice(obj.Object, "spec", field)
+	var out []FluxEventSource
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		src := FluxEventSource{Namespace: obj.GetNamespace()}
+		src.Kind, _, _ = unstructured.NestedString(m, "kind")
+		src.Name, _, _ = unstructured.NestedString(m, "name")
+		if ns, _, _ := unstructured.NestedString(m, "namespace"); ns != "" {
+			src.Namespace = ns
+		}
+		src.MatchLabels, _, _ = unstructured.NestedStringMap(m, "matchLabels")
+		out = append(out, src)
+	}
+	return out
+}
+
+// SourcesSummary renders an Alert's event sources, e.g.
+// "Kustomization/*, HelmRelease/apps/podinfo", leaving out the Alert's own
+// namespace.
+func (a FluxAlert) SourcesSummary() string {
+	parts := make([]string, 0, len(a.Sources))
+	for _, s := range a.Sources {
+		p := s.Kind + "/"
+		if s.Namespace != a.Namespace {
+			p += s.Namespace + "/"
+		}
+		p += s.Name
+		parts = append(parts, p)
+	}
+	return strings.Join(parts, ", ")
+}
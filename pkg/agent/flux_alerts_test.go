@@ -0,0 +1,170 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func TestFluxEventSourceMatches(t *testing.T) {
+	tier := FluxEventSource{Kind: "Kustomization", Name: "*", Namespace: "apps", MatchLabels: map[string]string{"tier": "web"}}
+	tests := []struct {
+		name      string
+		src       FluxEventSource
+		kind, ns  string
+		objName   string
+		labels    map[string]string
+		wantMatch bool
+	}{
+		{"by name", FluxEventSource{Kind: "HelmRelease", Name: "podinfo", Namespace: "apps"}, "HelmRelease", "apps", "podinfo", nil, true},
+		{"other name", FluxEventSource{Kind: "HelmRelease", Name: "podinfo", Namespace: "apps"}, "HelmRelease", "apps", "redis", nil, false},
+		{"other kind", FluxEventSource{Kind: "HelmRelease", Name: "*", Namespace: "apps"}, "Kustomization", "apps", "podinfo", nil, false},
+		{"other namespace", FluxEventSource{Kind: "HelmRelease", Name: "*", Namespace: "apps"}, "HelmRelease", "infra", "podinfo", nil, false},
+		{"wildcard with labels", tier, "Kustomization", "apps", "frontend", map[string]string{"tier": "web", "team": "a"}, true},
+		{"wildcard missing label", tier, "Kustomization", "apps", "backend", map[string]string{"tier": "api"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.src.Matches(tt.kind, tt.ns, tt.objName, tt.labels); got != tt.wantMatch {
+				t.Errorf("Matches() = %v, want %v", got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestBuildFluxAlerting(t *testing.T) {
+	objs := func(us ...*unstructured.Unstructured) []unstructured.Unstructured {
+		out := make([]unstructured.Unstructured, 0, len(us))
+		for _, u := range us {
+			out = append(out, *u)
+		}
+		return out
+	}
+	providers := objs(
+		agenttest.FluxProvider("flux-system", "slack", "slack"),
+		agenttest.FluxProvider("flux-system", "teams", "msteams", agenttest.Suspended()),
+	)
+	alerts := objs(
+		agenttest.FluxAlert("flux-system", "infra", "slack", []string{"Kustomization/*"}),
+		agenttest.FluxAlert("flux-system", "apps", "slack", []string{"HelmRelease/apps/podinfo"}),
+		agenttest.FluxAlert("flux-system", "paused", "slack", []string{"HelmRelease/apps/*"}, agenttest.Suspended()),
+		agenttest.FluxAlert("flux-system", "muted", "teams", []string{"HelmRelease/apps/*"}),
+		agenttest.FluxAlert("flux-system", "typo", "slak", []string{"HelmRelease/apps/*"}),
+	)
+	receivers := objs(
+		agenttest.FluxReceiver("flux-system", "github", "github", []string{"GitRepository/flux-system"}),
+	)
+	deployers := objs(
+		agenttest.FluxKustomization("flux-system", "infra"),
+		agenttest.FluxHelmRelease("apps", "podinfo"),
+		agenttest.FluxHelmRelease("apps", "redis", agenttest.NotReady("InstallFailed", "timed out")),
+	)
+
+	got := BuildFluxAlerting(alerts, providers, receivers, deployers)
+
+	if got.Providers != 2 || len(got.Alerts) != 5 || len(got.Receivers) != 1 {
+		t.Fatalf("got %d providers, %d alerts, %d receivers", got.Providers, len(got.Alerts), len(got.Receivers))
+	}
+	problems := map[string]string{}
+	for _, a := range got.Alerts {
+		problems[a.Name] = a.Problem
+	}
+	for name, want := range map[string]string{
+		"infra":  "",
+		"apps":   "",
+		"paused": "suspended",
+		"muted":  "provider teams suspended",
+		"typo":   "provider slak not found",
+	} {
+		if problems[name] != want {
+			t.Errorf("alert %s problem = %q, want %q", name, problems[name], want)
+		}
+	}
+	if s := got.Alerts[1].SourcesSummary(); s != "HelmRelease/apps/podinfo" {
+		t.Errorf("SourcesSummary() = %q", s)
+	}
+	if s := got.Alerts[0].SourcesSummary(); s != "Kustomization/*" {
+		t.Errorf("SourcesSummary() = %q, want the Alert's own namespace left out", s)
+	}
+	if r := got.Receivers[0]; !r.Ready || r.Type != "github" || r.Resources[0].Namespace != "flux-system" {
+		t.Errorf("receiver = %+v", r)
+	}
+
+	silent := got.Silent()
+	if len(silent) != 1 || silent[0].Name != "redis" || silent[0].Ready {
+		t.Fatalf("silent = %+v, want only the failing redis HelmRelease", silent)
+	}
+	for _, c := range got.Coverage {
+		if c.Name == "podinfo" && (len(c.Alerts) != 1 || c.Alerts[0] != "flux-system/apps") {
+			t.Errorf("podinfo covered by %v, want flux-system/apps", c.Alerts)
+		}
+	}
+}
+
+func TestListFluxAlertingNotInstalled(t *testing.T) {
+	alerts := func(version string) schema.GroupVersionResource {
+		return schema.GroupVersionResource{Group: "notification.toolkit.fluxcd.io", Version: version, Resource: "alerts"}
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}:        "HelmReleaseList",
+		{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}: "KustomizationList",
+		alerts("v1beta3"): "AlertList",
+		alerts("v1beta2"): "AlertList",
+	}, agenttest.FluxKustomization("flux-system", "apps"))
+	// Neither Alert version is served
+	client.Fake.PrependReactor("list", "alerts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "notification.toolkit.fluxcd.io", Resource: "alerts"}, "")
+	})
+
+	got, err := ListFluxAlerting(context.Background(), client, "")
+	if err != nil {
+		t.Fatalf("ListFluxAlerting() error = %v", err)
+	}
+	if got.Installed {
+		t.Error("Installed = true without the Alert API")
+	}
+	if len(got.Silent()) != 1 {
+		t.Errorf("silent = %+v, want the Kustomization", got.Silent())
+	}
+}
+
+func TestScanUnalertedFailures(t *testing.T) {
+	client := newFakeDynamicClientForScan(
+		agenttest.FluxProvider("flux-system", "slack", "slack"),
+		agenttest.FluxAlert("flux-system", "infra", "slack", []string{"Kustomization/*"}),
+		agenttest.FluxKustomization("flux-system", "infra"),
+		agenttest.FluxHelmRelease("apps", "podinfo"),
+		agenttest.FluxHelmRelease("apps", "redis", agenttest.NotReady("UpgradeFailed", "timed out")),
+		agenttest.FluxHelmRelease("apps", "kafka", agenttest.NotReady("InstallFailed", "timed out")),
+	)
+	scanner := NewStateScannerWithClient(client)
+
+	// kafka was already reported stuck
+	stuck := map[string]bool{resourceKey("HelmRelease", "apps", "kafka"): true}
+	var warnings []string
+	findings, gaps := scanner.scanUnalertedFailures(context.Background(), "", stuck, &warnings)
+	if len(warnings) != 0 {
+		t.Fatalf("warnings = %v", warnings)
+	}
+	// Only redis is failing unnoticed; healthy podinfo and stuck kafka are gaps
+	if len(findings) != 1 || findings[0].Name != "redis" {
+		t.Fatalf("findings = %+v, want only redis", findings)
+	}
+	if f := findings[0]; f.CCVEID != "CCVE-2025-0694" || f.Reason != "FailuresNotAlerted" || f.Category != "SILENT" || f.Severity != "warning" {
+		t.Errorf("finding = %+v", f)
+	}
+	if gaps != 2 {
+		t.Errorf("gaps = %d, want podinfo and kafka", gaps)
+	}
+}
@@ -0,0 +1,462 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// ErrNoFluxSource is returned by FluxSourceRef for objects without a
+// sourceRef or chartRef.
+var ErrNoFluxSource = errors.New("no sourceRef")
+
+// fluxKind is a Flux kind the resolver can fetch. Versions are tried in
+// order, so clusters still serving a beta API resolve too.
+type fluxKind struct {
+	group    string
+	resource string
+	versions []string
+}
+
+var fluxKinds = map[string]fluxKind{
+	"GitRepository":  {"source.toolkit.fluxcd.io", "gitrepositories", []string{"v1", "v1beta2"}},
+	"OCIRepository":  {"source.toolkit.fluxcd.io", "ocirepositories", []string{"v1", "v1beta2"}},
+	"HelmRepository": {"source.toolkit.fluxcd.io", "helmrepositories", []string{"v1", "v1beta2"}},
+	"Bucket":         {"source.toolkit.fluxcd.io", "buckets", []string{"v1", "v1beta2"}},
+	"HelmChart":      {"source.toolkit.fluxcd.io", "helmcharts", []string{"v1", "v1beta2"}},
+	"Kustomization":  {"kustomize.toolkit.fluxcd.io", "kustomizations", []string{"v1", "v1beta2"}},
+	"HelmRelease":    {"helm.toolkit.fluxcd.io", "helmreleases", []string{"v2", "v2beta2", "v2beta1"}},
+}
+
+// fluxSourceKinds are the kinds a sourceRef or chartRef may point at.
+var fluxSourceKinds = []string{"GitRepository", "OCIRepository", "HelmRepository", "Bucket", "HelmChart"}
+
+// FluxObjectRef is a reference between Flux objects: a sourceRef, chartRef
+// or dependsOn entry, with the namespace defaulted to the referrer's.
+type FluxObjectRef struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// CrossNamespace is set when the reference leaves the referrer's
+	// namespace, which Flux rejects under --no-cross-namespace-refs
+	CrossNamespace bool `json:"crossNamespace,omitempty"`
+}
+
+// String renders the reference as "Kind/namespace/name".
+func (r FluxObjectRef) String() string {
+	return r.Kind + "/" + r.Namespace + "/" + r.Name
+}
+
+// FluxSourceRef returns the source a Flux object points at:
+// spec.sourceRef (Kustomization, HelmChart), spec.chart.spec.sourceRef or
+// spec.chartRef (HelmRelease). The kind is matched case-insensitively and
+// may carry an apiVersion ("source.toolkit.fluxcd.io/v1/GitRepository").
+// It returns ErrNoFluxSource when the object has no reference, and an error
+// for an unknown kind or a reference without a name.
+func FluxSourceRef(obj *unstructured.Unstructured) (FluxObjectRef, error) {
+	var ref map[string]interface{}
+	for _, path := range [][]string{
+		{"spec", "sourceRef"},
+		{"spec", "chart", "spec", "sourceRef"},
+		{"spec", "chartRef"},
+	} {
+		if m, ok, _ := unstructured.NestedMap(obj.Object, path...); ok && len(m) > 0 {
+			ref = m
+			break
+		}
+	}
+	if ref == nil {
+		return FluxObjectRef{}, ErrNoFluxSource
+	}
+
+	rawKind, _ := ref["kind"].(string)
+	name, _ := ref["name"].(string)
+	namespace, _ := ref["namespace"].(string)
+	kind, ok := normalizeFluxKind(rawKind, fluxSourceKinds)
+	if !ok {
+		return FluxObjectRef{}, fmt.Errorf("unsupported source kind %q", rawKind)
+	}
+	if strings.TrimSpace(name) == "" {
+		return FluxObjectRef{}, fmt.Errorf("%s sourceRef has no name", kind)
+	}
+	return newFluxObjectRef(obj, kind, strings.TrimSpace(name), strings.TrimSpace(namespace)), nil
+}
+
+// FluxDependsOn returns the dependsOn entries of a Kustomization or
+// HelmRelease, which refer to objects of the same kind.
+func FluxDependsOn(obj *unstructured.Unstructured) []FluxObjectRef {
+	entries, _, _ := unstructured.NestedSlice(obj.Object, "spec", "dependsOn")
+	var refs []FluxObjectRef
+	for _, e := range entries {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		namespace, _ := m["namespace"].(string)
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		refs = append(refs, newFluxObjectRef(obj, obj.GetKind(), strings.TrimSpace(name), strings.TrimSpace(namespace)))
+	}
+	return refs
+}
+
+func newFluxObjectRef(referrer *unstructured.Unstructured, kind, name, namespace string) FluxObjectRef {
+	ref := FluxObjectRef{Kind: kind, Name: name, Namespace: namespace}
+	if ref.Namespace == "" {
+		ref.Namespace = referrer.GetNamespace()
+	}
+	ref.CrossNamespace = ref.Namespace != referrer.GetNamespace()
+	return ref
+}
+
+// normalizeFluxKind matches kind against allowed, ignoring case and any
+// "group/version/" prefix.
+func normalizeFluxKind(kind string, allowed []string) (string, bool) {
+	kind = strings.TrimSpace(kind)
+	if i := strings.LastIndex(kind, "/"); i >= 0 {
+		kind = kind[i+1:]
+	}
+	for _, k := range allowed {
+		if strings.EqualFold(kind, k) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// FluxLookup fetches the Flux object a reference points at. It returns nil
+// and no error when the object does not exist.
+type FluxLookup func(ctx context.Context, ref FluxObjectRef) (*unstructured.Unstructured, error)
+
+// ClientFluxLookup looks Flux objects up in the cluster, trying each served
+// API version of the kind.
+func ClientFluxLookup(client dynamic.Interface) FluxLookup {
+	return func(ctx context.Context, ref FluxObjectRef) (*unstructured.Unstructured, error) {
+		k, ok := fluxKinds[ref.Kind]
+		if !ok {
+			return nil, fmt.Errorf("unsupported Flux kind %q", ref.Kind)
+		}
+		var firstErr error
+		for _, version := range k.versions {
+			gvr := schema.GroupVersionResource{Group: k.group, Version: version, Resource: k.resource}
+			obj, err := client.Resource(gvr).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err == nil {
+				return obj, nil
+			}
+			if firstErr == nil && !apierrors.IsNotFound(err) {
+				firstErr = err
+			}
+		}
+		// Not found in any version (or the version isn't served)
+		return nil, firstErr
+	}
+}
+
+// FluxSource is a resolved source.
+type FluxSource struct {
+	FluxObjectRef
+
+	// URL is spec.url, or endpoint/bucketName for a Bucket
+	URL      string `json:"url,omitempty"`
+	Revision string `json:"revision,omitempty"`
+
+	Ready     bool   `json:"ready"`
+	Suspended bool   `json:"suspended,omitempty"`
+	Message   string `json:"message,omitempty"`
+
+	// Missing is set when the referenced source does not exist
+	Missing bool `json:"missing,omitempty"`
+
+	// Upstream is the source a HelmChart is built from, when the
+	// reference is a HelmRelease chartRef to a HelmChart
+	Upstream *FluxSource `json:"upstream,omitempty"`
+}
+
+// FluxDependency is one Kustomization or HelmRelease in a dependsOn chain.
+type FluxDependency struct {
+	FluxObjectRef
+
+	// Depth is 1 for a direct dependency, 2 for its dependencies, ...
+	Depth int `json:"depth"`
+
+	// Via is the dependent that pulled this one in
+	Via FluxObjectRef `json:"via"`
+
+	Ready     bool   `json:"ready"`
+	Suspended bool   `json:"suspended,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Missing   bool   `json:"missing,omitempty"`
+
+	// Cycle is set when the dependency leads back into the chain; Flux
+	// never reconciles such objects
+	Cycle bool `json:"cycle,omitempty"`
+}
+
+// FluxResolver resolves Flux sourceRefs and dependsOn chains across
+// namespaces. Fetched objects are cached; a resolver is meant for one
+// command.
+type FluxResolver struct {
+	lookup FluxLookup
+	cache  map[string]*unstructured.Unstructured // nil records a missing object
+}
+
+// NewFluxResolver returns a resolver fetching objects with lookup.
+func NewFluxResolver(lookup FluxLookup) *FluxResolver {
+	return &FluxResolver{lookup: lookup, cache: map[string]*unstructured.Unstructured{}}
+}
+
+// Get fetches the object ref points at through the cache, returning nil
+// when it doesn't exist.
+func (r *FluxResolver) Get(ctx context.Context, ref FluxObjectRef) (*unstructured.Unstructured, error) {
+	key := ref.Kind + "/" + ref.Namespace + "/" + ref.Name
+	if obj, ok := r.cache[key]; ok {
+		return obj, nil
+	}
+	obj, err := r.lookup(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[key] = obj
+	return obj, nil
+}
+
+// Source resolves the source of a Kustomization, HelmRelease or HelmChart.
+// A source that doesn't exist is returned with Missing set.
+func (r *FluxResolver) Source(ctx context.Context, obj *unstructured.Unstructured) (*FluxSource, error) {
+	ref, err := FluxSourceRef(obj)
+	if err != nil {
+		return nil, err
+	}
+	src, err := r.source(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Kind == "HelmChart" && !src.Missing {
+		chart, _ := r.Get(ctx, ref)
+		if upstream, err := r.Source(ctx, chart); err == nil {
+			src.Upstream = upstream
+		}
+	}
+	return src, nil
+}
+
+func (r *FluxResolver) source(ctx context.Context, ref FluxObjectRef) (*FluxSource, error) {
+	src := &FluxSource{FluxObjectRef: ref}
+	obj, err := r.Get(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", ref, err)
+	}
+	if obj == nil {
+		src.Missing = true
+		src.Message = "not found"
+		return src, nil
+	}
+
+	src.URL, _, _ = unstructured.NestedString(obj.Object, "spec", "url")
+	if ref.Kind == "Bucket" {
+		endpoint, _, _ := unstructured.NestedString(obj.Object, "spec", "endpoint")
+		bucket, _, _ := unstructured.NestedString(obj.Object, "spec", "bucketName")
+		src.URL = strings.TrimSuffix(endpoint, "/") + "/" + bucket
+	}
+	src.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "artifact", "revision")
+	src.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+	src.Ready, src.Message = FluxReady(obj)
+	return src, nil
+}
+
+// Dependencies walks the dependsOn chain of a Kustomization or HelmRelease
+// depth-first, each object listed once. Missing dependencies and cycles are
+// reported on the entry rather than as errors; an error means an object
+// could not be fetched.
+func (r *FluxResolver) Dependencies(ctx context.Context, obj *unstructured.Unstructured) ([]FluxDependency, error) {
+	self := FluxObjectRef{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace()}
+	var deps []FluxDependency
+	seen := map[FluxObjectRef]bool{}
+
+	var walk func(parent *unstructured.Unstructured, via FluxObjectRef, path map[FluxObjectRef]bool, depth int) error
+	walk = func(parent *unstructured.Unstructured, via FluxObjectRef, path map[FluxObjectRef]bool, depth int) error {
+		for _, ref := range FluxDependsOn(parent) {
+			key := ref
+			key.CrossNamespace = false
+			dep := FluxDependency{FluxObjectRef: ref, Depth: depth, Via: via}
+			if path[key] {
+				dep.Cycle = true
+				deps = append(deps, dep)
+				continue
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			child, err := r.Get(ctx, ref)
+			if err != nil {
+				return fmt.Errorf("get %s: %w", ref, err)
+			}
+			if child == nil {
+				dep.Missing = true
+				dep.Message = "not found"
+				deps = append(deps, dep)
+				continue
+			}
+			dep.Suspended, _, _ = unstructured.NestedBool(child.Object, "spec", "suspend")
+			dep.Ready, dep.Message = FluxReady(child)
+			deps = append(deps, dep)
+
+			path[key] = true
+			err = walk(child, ref, path, depth+1)
+			delete(path, key)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(obj, self, map[FluxObjectRef]bool{self: true}, 1); err != nil {
+		return deps, err
+	}
+	return deps, nil
+}
+
+// Trace builds the chain from a deployer's source to object from the
+// cluster alone, for when the flux CLI isn't installed: the HelmChart's
+// upstream (if any), the source, the deployer and the object itself, with
+// the deployer's dependsOn chain.
+func (r *FluxResolver) Trace(ctx context.Context, deployer *unstructured.Unstructured, object ResourceRef) (*TraceResult, error) {
+	result := &TraceResult{Object: object, Tool: "flux", TracedAt: time.Now()}
+
+	src, err := r.Source(ctx, deployer)
+	if err != nil && !errors.Is(err, ErrNoFluxSource) {
+		return nil, err
+	}
+	var sources []*FluxSource
+	for s := src; s != nil; s = s.Upstream {
+		sources = append([]*FluxSource{s}, sources...)
+	}
+	for _, s := range sources {
+		link := ChainLink{
+			Kind: s.Kind, Name: s.Name, Namespace: s.Namespace,
+			Ready: s.Ready && !s.Suspended, Status: fluxStatus(s.Ready, s.Suspended, s.Missing),
+			Revision: s.Revision, URL: s.URL, Message: s.Message,
+		}
+		if !link.Ready {
+			link.StatusReason = s.Message
+		}
+		if s.Kind == "OCIRepository" && strings.HasPrefix(s.URL, "oci://") {
+			info := ParseOCISource(s.URL)
+			link.OCISource = &info
+		}
+		result.Chain = append(result.Chain, link)
+	}
+
+	ready, msg := FluxReady(deployer)
+	suspended, _, _ := unstructured.NestedBool(deployer.Object, "spec", "suspend")
+	link := ChainLink{
+		Kind: deployer.GetKind(), Name: deployer.GetName(), Namespace: deployer.GetNamespace(),
+		Ready: ready && !suspended, Status: fluxStatus(ready, suspended, false), Message: msg,
+	}
+	link.Revision, _, _ = unstructured.NestedString(deployer.Object, "status", "lastAppliedRevision")
+	link.Path, _, _ = unstructured.NestedString(deployer.Object, "spec", "path")
+	if !link.Ready {
+		link.StatusReason = msg
+	}
+	result.Chain = append(result.Chain, link)
+
+	if object.Kind != deployer.GetKind() || object.Name != deployer.GetName() {
+		result.Chain = append(result.Chain, ChainLink{Kind: object.Kind, Name: object.Name, Namespace: object.Namespace, Ready: true, Status: "Managed"})
+	}
+
+	if result.DependsOn, err = r.Dependencies(ctx, deployer); err != nil {
+		return nil, err
+	}
+	result.FullyManaged = true
+	for _, l := range result.Chain {
+		if !l.Ready {
+			result.FullyManaged = false
+		}
+	}
+	return result, nil
+}
+
+func fluxStatus(ready, suspended, missing bool) string {
+	switch {
+	case missing:
+		return "Not found"
+	case suspended:
+		return "Suspended"
+	case ready:
+		return "Ready"
+	}
+	return "Not ready"
+}
+
+// FluxBlocker returns why a Flux object can't make progress because of
+// what it depends on: its source not ready (or missing), or the first
+// dependency that isn't ready. It returns "" when nothing upstream blocks.
+func FluxBlocker(src *FluxSource, deps []FluxDependency) string {
+	if src != nil {
+		// A HelmChart fails when what it's built from does; report the root
+		if src.Upstream != nil {
+			if b := FluxBlocker(src.Upstream, nil); b != "" {
+				return b
+			}
+		}
+		switch {
+		case src.Missing:
+			return fmt.Sprintf("source %s not found", src.FluxObjectRef)
+		case src.Suspended:
+			return fmt.Sprintf("source %s suspended", src.FluxObjectRef)
+		case !src.Ready:
+			return fmt.Sprintf("source %s not ready: %s", src.FluxObjectRef, src.Message)
+		}
+	}
+	for _, d := range deps {
+		switch {
+		case d.Cycle:
+			return fmt.Sprintf("dependency cycle through %s", d.FluxObjectRef)
+		case d.Missing:
+			return fmt.Sprintf("dependency %s not found", d.FluxObjectRef)
+		case d.Suspended:
+			return fmt.Sprintf("dependency %s suspended", d.FluxObjectRef)
+		case !d.Ready:
+			return fmt.Sprintf("waiting on dependency %s", d.FluxObjectRef)
+		}
+	}
+	return ""
+}
+
+// FluxReady reads a Flux object's Ready condition, returning its message
+// when it isn't True.
+func FluxReady(obj *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		if cond["status"] == "True" {
+			return true, ""
+		}
+		msg, _ := cond["message"].(string)
+		if msg == "" {
+			msg, _ = cond["reason"].(string)
+		}
+		return false, msg
+	}
+	return false, "no Ready condition"
+}
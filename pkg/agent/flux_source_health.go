@@ -0,0 +1,168 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// fluxHealthSourceKinds are the sources whose fetch status is reported on
+// their own: object storage buckets and Helm repository indexes, which
+// fail for credentials and endpoints rather than for what's in Git.
+var fluxHealthSourceKinds = []string{"Bucket", "HelmRepository"}
+
+// FluxSourceHealth is the fetch status of a Bucket or HelmRepository.
+type FluxSourceHealth struct {
+	FluxObjectRef
+
+	// URL is spec.url, or endpoint/bucketName for a Bucket
+	URL string `json:"url,omitempty"`
+
+	// Provider is a Bucket's spec.provider (generic, aws, gcp, azure) or a
+	// HelmRepository's spec.type (default, oci)
+	Provider string `json:"provider,omitempty"`
+
+	Revision  string `json:"revision,omitempty"`
+	Ready     bool   `json:"ready"`
+	Suspended bool   `json:"suspended,omitempty"`
+	Message   string `json:"message,omitempty"`
+
+	// LastFetched is when the artifact was last updated; zero when the
+	// source never produced one
+	LastFetched time.Time `json:"lastFetched,omitempty"`
+
+	// Static is set for OCI HelmRepositories, which Flux doesn't fetch:
+	// charts are pulled by the HelmChart and there is no index
+	Static bool `json:"static,omitempty"`
+}
+
+// NewFluxSourceHealth reads the fetch status of a Bucket or HelmRepository.
+func NewFluxSourceHealth(obj *unstructured.Unstructured) FluxSourceHealth {
+	h := FluxSourceHealth{FluxObjectRef: FluxObjectRef{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace()}}
+	h.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+	h.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "artifact", "revision")
+	if s, _, _ := unstructured.NestedString(obj.Object, "status", "artifact", "lastUpdateTime"); s != "" {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			h.LastFetched = t
+		}
+	}
+
+	switch h.Kind {
+	case "Bucket":
+		endpoint, _, _ := unstructured.NestedString(obj.Object, "spec", "endpoint")
+		bucket, _, _ := unstructured.NestedString(obj.Object, "spec", "bucketName")
+		h.URL = strings.TrimSuffix(endpoint, "/") + "/" + bucket
+		h.Provider, _, _ = unstructured.NestedString(obj.Object, "spec", "provider")
+		if h.Provider == "" {
+			h.Provider = "generic"
+		}
+	case "HelmRepository":
+		h.URL, _, _ = unstructured.NestedString(obj.Object, "spec", "url")
+		h.Provider, _, _ = unstructured.NestedString(obj.Object, "spec", "type")
+		if h.Provider == "" {
+			h.Provider = "default"
+		}
+		if h.Provider == "oci" {
+			// Flux 2.1+ leaves OCI HelmRepositories without conditions
+			h.Static = true
+			h.Ready = true
+			return h
+		}
+	}
+	h.Ready, h.Message = FluxReady(obj)
+	return h
+}
+
+// Healthy reports whether the source fetched successfully and isn't
+// suspended.
+func (h FluxSourceHealth) Healthy() bool {
+	return h.Ready && !h.Suspended
+}
+
+// Age returns how long ago the artifact was last updated, or 0 when it never
+// was.
+func (h FluxSourceHealth) Age(now time.Time) time.Duration {
+	if h.LastFetched.IsZero() {
+		return 0
+	}
+	return now.Sub(h.LastFetched)
+}
+
+// Problem describes why an unhealthy source isn't serving, e.g.
+// "suspended" or "fetch failed: ... (last fetched 3h ago)". It returns ""
+// for a healthy source.
+func (h FluxSourceHealth) Problem(now time.Time) string {
+	if h.Healthy() {
+		return ""
+	}
+	var msg string
+	switch {
+	case h.Suspended:
+		msg = "suspended"
+	case h.Kind == "HelmRepository":
+		msg = "index fetch failed: " + h.Message
+	default:
+		msg = "fetch failed: " + h.Message
+	}
+	if h.LastFetched.IsZero() {
+		return msg + " (never fetched)"
+	}
+	return fmt.Sprintf("%s (last fetched %s ago)", msg, shortAge(h.Age(now)))
+}
+
+// ListFluxSourceHealth lists the Buckets and HelmRepositories in namespace
+// ("" for all), trying each served API version of the kind. A kind the
+// cluster doesn't serve is skipped.
+func ListFluxSourceHealth(ctx context.Context, client dynamic.Interface, namespace string) []FluxSourceHealth {
+	var out []FluxSourceHealth
+	for _, kind := range fluxHealthSourceKinds {
+		k := fluxKinds[kind]
+		for _, version := range k.versions {
+			gvr := schema.GroupVersionResource{Group: k.group, Version: version, Resource: k.resource}
+			list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				continue
+			}
+			for i := range list.Items {
+				list.Items[i].SetKind(kind)
+				out = append(out, NewFluxSourceHealth(&list.Items[i]))
+			}
+			break
+		}
+	}
+	return out
+}
+
+// shortAge renders a duration as "45s", "12m", "3h" or "2d".
+func shortAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// ShortAge renders how long ago the source was fetched for tables: "3h",
+// "never" or "-" for a static source.
+func (h FluxSourceHealth) ShortAge(now time.Time) string {
+	switch {
+	case h.Static:
+		return "-"
+	case h.LastFetched.IsZero():
+		return "never"
+	}
+	return shortAge(h.Age(now))
+}
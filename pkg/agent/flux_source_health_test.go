@@ -0,0 +1,105 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func fetchedAt(t time.Time) agenttest.Option {
+	return func(u *unstructured.Unstructured) {
+		_ = unstructured.SetNestedField(u.Object, t.Format(time.RFC3339), "status", "artifact", "lastUpdateTime")
+		_ = unstructured.SetNestedField(u.Object, "sha256:abc", "status", "artifact", "revision")
+	}
+}
+
+const bitnami = "https://charts.bitnami.com/bitnami"
+
+func TestNewFluxSourceHealth(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		obj         *unstructured.Unstructured
+		wantHealthy bool
+		wantAge     string
+		wantProblem string
+	}{
+		{
+			name:        "bucket fetched",
+			obj:         agenttest.FluxBucket("flux-system", "manifests", "manifests", fetchedAt(now.Add(-3*time.Minute))),
+			wantHealthy: true,
+			wantAge:     "3m",
+		},
+		{
+			name: "bucket access denied",
+			obj: agenttest.FluxBucket("flux-system", "manifests", "manifests", fetchedAt(now.Add(-5*time.Hour)),
+				agenttest.NotReady("BucketOperationFailed", "AccessDenied: access denied")),
+			wantAge:     "5h",
+			wantProblem: "fetch failed: AccessDenied: access denied (last fetched 5h ago)",
+		},
+		{
+			name:        "helm index never fetched",
+			obj:         agenttest.FluxHelmRepository("flux-system", "bitnami", bitnami, agenttest.NotReady("IndexationFailed", "failed to fetch index: 404")),
+			wantAge:     "never",
+			wantProblem: "index fetch failed: failed to fetch index: 404 (never fetched)",
+		},
+		{
+			name:        "suspended",
+			obj:         agenttest.FluxHelmRepository("flux-system", "bitnami", bitnami, fetchedAt(now.Add(-48*time.Hour)), agenttest.Suspended()),
+			wantAge:     "2d",
+			wantProblem: "suspended (last fetched 2d ago)",
+		},
+		{
+			name: "oci helm repository has no index",
+			obj: agenttest.FluxHelmRepository("flux-system", "ghcr", "oci://ghcr.io/stefanprodan/charts", withSpec(map[string]interface{}{"type": "oci"}),
+				agenttest.NotReady("", "")),
+			wantHealthy: true,
+			wantAge:     "-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewFluxSourceHealth(tt.obj)
+			if h.Healthy() != tt.wantHealthy {
+				t.Errorf("Healthy() = %v, want %v", h.Healthy(), tt.wantHealthy)
+			}
+			if got := h.ShortAge(now); got != tt.wantAge {
+				t.Errorf("ShortAge() = %q, want %q", got, tt.wantAge)
+			}
+			if got := h.Problem(now); got != tt.wantProblem {
+				t.Errorf("Problem() = %q, want %q", got, tt.wantProblem)
+			}
+		})
+	}
+}
+
+func TestListFluxSourceHealth(t *testing.T) {
+	client := agenttest.FakeClient(
+		agenttest.FluxBucket("flux-system", "manifests", "manifests"),
+		agenttest.FluxHelmRepository("apps", "bitnami", bitnami),
+		agenttest.FluxGitRepository("flux-system", "flux-system", "https://github.com/example/fleet"),
+	)
+
+	sources := ListFluxSourceHealth(context.Background(), client, "")
+	var got []string
+	for _, s := range sources {
+		got = append(got, s.FluxObjectRef.String()+" "+s.Provider+" "+s.URL)
+	}
+	want := []string{
+		"Bucket/flux-system/manifests aws s3.amazonaws.com/manifests",
+		"HelmRepository/apps/bitnami default https://charts.bitnami.com/bitnami",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ListFluxSourceHealth() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func withSpec(fields map[string]interface{}) agenttest.Option {
+	return func(u *unstructured.Unstructured) {
+		spec, _, _ := unstructured.NestedMap(u.Object, "spec")
+		if spec == nil {
+			spec = map[string]interface{}{}
+		}
+		for k, v := range fields {
+			spec[k] = v
+		}
+		u.Object["spec"] = spec
+	}
+}
+
+func dependsOn(refs ...string) agenttest.Option {
+	var entries []interface{}
+	for _, r := range refs {
+		entry := map[string]interface{}{"name": r}
+		if ns, name, ok := strings.Cut(r, "/"); ok {
+			entry = map[string]interface{}{"namespace": ns, "name": name}
+		}
+		entries = append(entries, entry)
+	}
+	return withSpec(map[string]interface{}{"dependsOn": entries})
+}
+
+func TestFluxSourceRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     *unstructured.Unstructured
+		want    FluxObjectRef
+		wantErr string
+	}{
+		{
+			name: "kustomization defaults to its namespace",
+			obj:  agenttest.FluxKustomization("apps", "web"),
+			want: FluxObjectRef{Kind: "GitRepository", Name: "flux-system", Namespace: "apps"},
+		},
+		{
+			name: "cross-namespace, kind in any case with apiVersion",
+			obj: agenttest.FluxKustomization("apps", "web", withSpec(map[string]interface{}{
+				"sourceRef": map[string]interface{}{"kind": "source.toolkit.fluxcd.io/v1/ocirepository", "name": "hub", "namespace": "flux-system"},
+			})),
+			want: FluxObjectRef{Kind: "OCIRepository", Name: "hub", Namespace: "flux-system", CrossNamespace: true},
+		},
+		{
+			name: "helmrelease chart sourceRef",
+			obj:  agenttest.FluxHelmRelease("apps", "redis"),
+			want: FluxObjectRef{Kind: "HelmRepository", Name: "redis", Namespace: "apps"},
+		},
+		{
+			name: "helmrelease chartRef",
+			obj: agenttest.Object("helm.toolkit.fluxcd.io/v2", "HelmRelease", "apps", "podinfo", withSpec(map[string]interface{}{
+				"chartRef": map[string]interface{}{"kind": "HelmChart", "name": "podinfo", "namespace": "charts"},
+			})),
+			want: FluxObjectRef{Kind: "HelmChart", Name: "podinfo", Namespace: "charts", CrossNamespace: true},
+		},
+		{
+			name: "bucket",
+			obj: agenttest.FluxKustomization("apps", "web", withSpec(map[string]interface{}{
+				"sourceRef": map[string]interface{}{"kind": "Bucket", "name": "manifests"},
+			})),
+			want: FluxObjectRef{Kind: "Bucket", Name: "manifests", Namespace: "apps"},
+		},
+		{
+			name: "unknown kind",
+			obj: agenttest.FluxKustomization("apps", "web", withSpec(map[string]interface{}{
+				"sourceRef": map[string]interface{}{"kind": "SVNRepository", "name": "old"},
+			})),
+			wantErr: `unsupported source kind "SVNRepository"`,
+		},
+		{
+			name: "no name",
+			obj: agenttest.FluxKustomization("apps", "web", withSpec(map[string]interface{}{
+				"sourceRef": map[string]interface{}{"kind": "GitRepository"},
+			})),
+			wantErr: "has no name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FluxSourceRef(tt.obj)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FluxSourceRef: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FluxSourceRef = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := FluxSourceRef(agenttest.Deployment("apps", "web")); !errors.Is(err, ErrNoFluxSource) {
+		t.Errorf("Deployment error = %v, want ErrNoFluxSource", err)
+	}
+}
+
+func TestFluxResolverSource(t *testing.T) {
+	ctx := context.Background()
+	// The OCIRepository is only served as v1beta2
+	oci := agenttest.Object("source.toolkit.fluxcd.io/v1beta2", "OCIRepository", "flux-system", "hub", withSpec(map[string]interface{}{
+		"url": "oci://registry.example.com/apps",
+	}))
+	git := agenttest.FluxGitRepository("platform", "charts", "https://github.com/acme/charts",
+		agenttest.NotReady("GitOperationFailed", "auth failed"))
+	chart := agenttest.Object("source.toolkit.fluxcd.io/v1", "HelmChart", "platform", "podinfo", withSpec(map[string]interface{}{
+		"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "charts"},
+	}))
+	r := NewFluxResolver(ClientFluxLookup(agenttest.FakeClient(oci, git, chart)))
+
+	ks := agenttest.FluxKustomization("apps", "web", withSpec(map[string]interface{}{
+		"sourceRef": map[string]interface{}{"kind": "OCIRepository", "name": "hub", "namespace": "flux-system"},
+	}))
+	src, err := r.Source(ctx, ks)
+	if err != nil {
+		t.Fatalf("Source: %v", err)
+	}
+	if src.Missing || src.URL != "oci://registry.example.com/apps" || !src.CrossNamespace {
+		t.Errorf("source = %+v, want the v1beta2 OCIRepository across namespaces", src)
+	}
+
+	hr := agenttest.Object("helm.toolkit.fluxcd.io/v2", "HelmRelease", "apps", "podinfo", withSpec(map[string]interface{}{
+		"chartRef": map[string]interface{}{"kind": "HelmChart", "name": "podinfo", "namespace": "platform"},
+	}))
+	src, err = r.Source(ctx, hr)
+	if err != nil {
+		t.Fatalf("Source: %v", err)
+	}
+	if src.Upstream == nil || src.Upstream.Kind != "GitRepository" || src.Upstream.Ready {
+		t.Fatalf("upstream = %+v, want the not-ready GitRepository", src.Upstream)
+	}
+	if got := FluxBlocker(src, nil); !strings.Contains(got, "GitRepository/platform/charts not ready: auth failed") {
+		t.Errorf("FluxBlocker = %q", got)
+	}
+
+	missing := agenttest.FluxKustomization("apps", "gone")
+	src, err = r.Source(ctx, missing)
+	if err != nil || !src.Missing {
+		t.Errorf("missing source = %+v, %v", src, err)
+	}
+}
+
+func TestFluxResolverDependencies(t *testing.T) {
+	ctx := context.Background()
+	infra := agenttest.FluxKustomization("flux-system", "infra", dependsOn("crds"))
+	crds := agenttest.FluxKustomization("flux-system", "crds")
+	db := agenttest.FluxKustomization("data", "db", dependsOn("flux-system/infra"),
+		agenttest.NotReady("HealthCheckFailed", "db not healthy"))
+	apps := agenttest.FluxKustomization("apps", "web", dependsOn("flux-system/infra", "data/db", "missing"))
+	r := NewFluxResolver(ClientFluxLookup(agenttest.FakeClient(infra, crds, db, apps)))
+
+	deps, err := r.Dependencies(ctx, apps)
+	if err != nil {
+		t.Fatalf("Dependencies: %v", err)
+	}
+	var got []string
+	for _, d := range deps {
+		s := d.Namespace + "/" + d.Name
+		if d.Depth > 1 {
+			s = strings.Repeat(">", d.Depth-1) + s
+		}
+		if d.Missing {
+			s += " missing"
+		}
+		if !d.Ready && !d.Missing {
+			s += " not-ready"
+		}
+		got = append(got, s)
+	}
+	want := []string{"flux-system/infra", ">flux-system/crds", "data/db not-ready", "apps/missing missing"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("dependencies = %v, want %v", got, want)
+	}
+	if !deps[0].CrossNamespace || deps[1].CrossNamespace {
+		t.Errorf("crossNamespace = %v/%v, want true/false", deps[0].CrossNamespace, deps[1].CrossNamespace)
+	}
+	if got := FluxBlocker(nil, deps); got != "waiting on dependency Kustomization/data/db" {
+		t.Errorf("FluxBlocker = %q", got)
+	}
+}
+
+func TestFluxResolverDependencyCycle(t *testing.T) {
+	a := agenttest.FluxKustomization("flux-system", "a", dependsOn("b"))
+	b := agenttest.FluxKustomization("flux-system", "b", dependsOn("a"))
+	r := NewFluxResolver(ClientFluxLookup(agenttest.FakeClient(a, b)))
+
+	deps, err := r.Dependencies(context.Background(), a)
+	if err != nil {
+		t.Fatalf("Dependencies: %v", err)
+	}
+	if len(deps) != 2 || deps[0].Name != "b" || !deps[1].Cycle || deps[1].Name != "a" {
+		t.Fatalf("deps = %+v, want b then a (cycle)", deps)
+	}
+	if got := FluxBlocker(nil, deps); got != "dependency cycle through Kustomization/flux-system/a" {
+		t.Errorf("FluxBlocker = %q", got)
+	}
+}
+
+func TestFluxResolverTrace(t *testing.T) {
+	git := agenttest.FluxGitRepository("flux-system", "platform", "https://github.com/acme/platform")
+	ks := agenttest.FluxKustomization("apps", "web", withSpec(map[string]interface{}{
+		"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "platform", "namespace": "flux-system"},
+	}))
+	r := NewFluxResolver(ClientFluxLookup(agenttest.FakeClient(git, ks)))
+
+	result, err := r.Trace(context.Background(), ks, ResourceRef{Kind: "Deployment", Name: "web", Namespace: "apps"})
+	if err != nil {
+		t.Fatalf("Trace: %v", err)
+	}
+	var kinds []string
+	for _, l := range result.Chain {
+		kinds = append(kinds, l.Kind)
+	}
+	if strings.Join(kinds, " → ") != "GitRepository → Kustomization → Deployment" {
+		t.Errorf("chain = %v", kinds)
+	}
+	if !result.FullyManaged || result.Chain[0].URL != "https://github.com/acme/platform" || result.Chain[1].Path != "./web" {
+		t.Errorf("result = %+v", result)
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// GitCommit describes the Git commit behind a chain link's revision
+type GitCommit struct {
+	// SHA is the full or abbreviated commit hash
+	SHA string `json:"sha"`
+
+	// URL is a browsable link to the commit on the Git provider
+	URL string `json:"url,omitempty"`
+
+	// Author is the commit author name
+	Author string `json:"author,omitempty"`
+
+	// Subject is the first line of the commit message
+	Subject string `json:"subject,omitempty"`
+
+	// Date is when the commit was authored
+	Date *time.Time `json:"date,omitempty"`
+}
+
+// CommitEnricher resolves chain link revisions to Git commits
+type CommitEnricher struct {
+	httpClient *http.Client
+
+	// LocalDir is a local clone checked before calling provider APIs (optional)
+	LocalDir string
+
+	// apiBase overrides provider API endpoints (for tests)
+	apiBase string
+}
+
+// NewCommitEnricher creates a new commit enricher. Provider APIs are called
+// with GITHUB_TOKEN / GITLAB_TOKEN when set, and only for the hosts
+// gitProvider recognizes.
+func NewCommitEnricher(localDir string) *CommitEnricher {
+	return &CommitEnricher{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		LocalDir:   localDir,
+	}
+}
+
+// EnrichChainWithCommits sets Commit on every link whose revision contains a
+// commit SHA. The repository URL comes from the nearest source link above it.
+// Lookup failures leave only the SHA and URL set.
+func (e *CommitEnricher) EnrichChainWithCommits(ctx context.Context, chain []ChainLink) []ChainLink {
+	repoURL := ""
+	cache := map[string]*GitCommit{}
+	for i := range chain {
+		link := &chain[i]
+		if link.URL != "" && !strings.HasPrefix(link.URL, "oci://") {
+			repoURL = link.URL
+		}
+		sha := ParseRevisionSHA(link.Revision)
+		if sha == "" || repoURL == "" {
+			continue
+		}
+
+		if c, ok := cache[sha]; ok {
+			link.Commit = c
+			continue
+		}
+
+		commit := e.Resolve(ctx, repoURL, link.Revision)
+		cache[sha] = commit
+		link.Commit = commit
+	}
+	return chain
+}
+
+// Resolve returns the commit behind a revision in repoURL, or nil if the
+// revision does not contain a commit SHA. Lookup failures leave only the
+// SHA and URL set.
+func (e *CommitEnricher) Resolve(ctx context.Context, repoURL, revision string) *GitCommit {
+	sha := ParseRevisionSHA(revision)
+	if sha == "" || repoURL == "" {
+		return nil
+	}
+
+	commit := &GitCommit{SHA: sha, URL: CommitURL(repoURL, sha)}
+	if details, err := e.lookup(ctx, repoURL, sha); err == nil {
+		commit.Author = details.Author
+		commit.Subject = details.Subject
+		commit.Date = details.Date
+	}
+	return commit
+}
+
+var (
+	sha1RevisionPattern = regexp.MustCompile(`sha1:([a-f0-9]{7,40})`)
+	bareSHAPattern      = regexp.MustCompile(`^[a-f0-9]{7,40}$`)
+)
+
+// ParseRevisionSHA extracts a commit SHA from Flux and Argo revision strings.
+// e.g., "main@sha1:abc123def456" -> "abc123def456", "main/abc123def" -> "abc123def".
+// Returns "" for tags, semver versions and OCI digests.
+func ParseRevisionSHA(rev string) string {
+	if rev == "" {
+		return ""
+	}
+	if m := sha1RevisionPattern.FindStringSubmatch(rev); len(m) > 1 {
+		return m[1]
+	}
+	// Flux v1beta2 format "branch/sha"
+	if idx := strings.LastIndex(rev, "/"); idx != -1 && bareSHAPattern.MatchString(rev[idx+1:]) {
+		return rev[idx+1:]
+	}
+	// Argo sync revision is a bare 40-char SHA
+	if bareSHAPattern.MatchString(rev) && len(rev) == 40 {
+		return rev
+	}
+	return ""
+}
+
+// gitRepo is a Git remote split into host and owner/repo path
+type gitRepo struct {
+	Host string
+	Path string
+}
+
+// parseGitRepoURL normalizes https, ssh:// and scp-style Git remotes.
+func parseGitRepoURL(repoURL string) (gitRepo, bool) {
+	u := strings.TrimSpace(repoURL)
+	if u == "" {
+		return gitRepo{}, false
+	}
+
+	// scp-style: git@github.com:org/repo.git
+	if !strings.Contains(u, "://") {
+		at := strings.Index(u, "@")
+		colon := strings.Index(u, ":")
+		if colon == -1 || colon < at {
+			return gitRepo{}, false
+		}
+		return gitRepo{
+			Host: u[at+1 : colon],
+			Path: strings.Trim(strings.TrimSuffix(u[colon+1:], ".git"), "/"),
+		}, true
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" {
+		return gitRepo{}, false
+	}
+	path := strings.Trim(strings.TrimSuffix(parsed.Path, ".git"), "/")
+	if path == "" {
+		return gitRepo{}, false
+	}
+	return gitRepo{Host: parsed.Hostname(), Path: path}, true
+}
+
+// Environment variables listing GitHub Enterprise and self-managed GitLab
+// hosts, comma-separated. Other hosts are never sent a token.
+const (
+	GitHubHostsEnv = "CUB_SCOUT_GITHUB_HOSTS"
+	GitLabHostsEnv = "CUB_SCOUT_GITLAB_HOSTS"
+)
+
+// gitProvider returns "github", "gitlab" or "bitbucket" for host, or "".
+// Hosts are matched exactly: repository URLs come from objects in the
+// cluster, and a look-alike host such as github.example.io must not
+// receive GITHUB_TOKEN.
+func gitProvider(host string) string {
+	host = strings.ToLower(host)
+	switch {
+	case host == "github.com" || hostListed(os.Getenv(GitHubHostsEnv), host):
+		return "github"
+	case host == "gitlab.com" || hostListed(os.Getenv(GitLabHostsEnv), host):
+		return "gitlab"
+	case host == "bitbucket.org":
+		return "bitbucket"
+	}
+	return ""
+}
+
+func hostListed(list, host string) bool {
+	for _, h := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(h), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// CommitURL returns a browsable commit URL for GitHub, GitLab and Bitbucket
+// remotes, or "" if the provider is not recognized.
+func CommitURL(repoURL, sha string) string {
+	repo, ok := parseGitRepoURL(repoURL)
+	if !ok || sha == "" {
+		return ""
+	}
+	switch gitProvider(repo.Host) {
+	case "github":
+		return fmt.Sprintf("https://%s/%s/commit/%s", repo.Host, repo.Path, sha)
+	case "gitlab":
+		return fmt.Sprintf("https://%s/%s/-/commit/%s", repo.Host, repo.Path, sha)
+	case "bitbucket":
+		return fmt.Sprintf("https://%s/%s/commits/%s", repo.Host, repo.Path, sha)
+	}
+	return ""
+}
+
+// lookup fetches author and subject from a local clone or the provider API.
+func (e *CommitEnricher) lookup(ctx context.Context, repoURL, sha string) (*GitCommit, error) {
+	if e.LocalDir != "" {
+		if c, err := lookupLocalCommit(ctx, e.LocalDir, sha); err == nil {
+			return c, nil
+		}
+	}
+
+	repo, ok := parseGitRepoURL(repoURL)
+	if !ok {
+		return nil, fmt.Errorf("unrecognized repository URL: %s", repoURL)
+	}
+	switch gitProvider(repo.Host) {
+	case "github":
+		return e.lookupGitHub(ctx, repo, sha)
+	case "gitlab":
+		return e.lookupGitLab(ctx, repo, sha)
+	}
+	return nil, fmt.Errorf("no commit API for %s", repo.Host)
+}
+
+// lookupLocalCommit reads commit metadata from a local clone with git show.
+func lookupLocalCommit(ctx context.Context, dir, sha string) (*GitCommit, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "show", "-s", "--format=%an%x00%s%x00%aI", sha).Output()
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.SplitN(strings.TrimSpace(string(out)), "\x00", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("unexpected git show output")
+	}
+	c := &GitCommit{SHA: sha, Author: parts[0], Subject: parts[1]}
+	if t, err := time.Parse(time.RFC3339, parts[2]); err == nil {
+		c.Date = &t
+	}
+	return c, nil
+}
+
+func (e *CommitEnricher) lookupGitHub(ctx context.Context, repo gitRepo, sha string) (*GitCommit, error) {
+	base := e.apiBase
+	if base == "" {
+		base = "https://api.github.com"
+		if repo.Host != "github.com" {
+			base = "https://" + repo.Host + "/api/v3" // GitHub Enterprise
+		}
+	}
+
+	var resp struct {
+		Commit struct {
+			Message string `json:"message"`
+			Author  struct {
+				Name string    `json:"name"`
+				Date time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+	}
+	if err := e.getJSON(ctx, fmt.Sprintf("%s/repos/%s/commits/%s", base, repo.Path, sha), "Bearer", os.Getenv("GITHUB_TOKEN"), &resp); err != nil {
+		return nil, err
+	}
+	date := resp.Commit.Author.Date
+	return &GitCommit{
+		SHA:     sha,
+		Author:  resp.Commit.Author.Name,
+		Subject: firstLine(resp.Commit.Message),
+		Date:    &date,
+	}, nil
+}
+
+func (e *CommitEnricher) lookupGitLab(ctx context.Context, repo gitRepo, sha string) (*GitCommit, error) {
+	base := e.apiBase
+	if base == "" {
+		base = "https://" + repo.Host + "/api/v4"
+	}
+
+	var resp struct {
+		Title      string    `json:"title"`
+		AuthorName string    `json:"author_name"`
+		AuthoredAt time.Time `json:"authored_date"`
+	}
+	endpoint := fmt.Sprintf("%s/projects/%s/repository/commits/%s", base, url.PathEscape(repo.Path), sha)
+	if err := e.getJSON(ctx, endpoint, "PRIVATE-TOKEN", os.Getenv("GITLAB_TOKEN"), &resp); err != nil {
+		return nil, err
+	}
+	date := resp.AuthoredAt
+	return &GitCommit{
+		SHA:     sha,
+		Author:  resp.AuthorName,
+		Subject: resp.Title,
+		Date:    &date,
+	}, nil
+}
+
+// getJSON performs an authenticated GET and decodes the JSON response.
+// authScheme "PRIVATE-TOKEN" is sent as a header; anything else as Authorization.
+func (e *CommitEnricher) getJSON(ctx context.Context, endpoint, authScheme, token string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		if authScheme == "PRIVATE-TOKEN" {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		} else {
+			req.Header.Set("Authorization", authScheme+" "+token)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx != -1 {
+		return strings.TrimSpace(s[:idx])
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,144 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRevisionSHA(t *testing.T) {
+	tests := []struct {
+		rev  string
+		want string
+	}{
+		{"main@sha1:abc123def4567890", "abc123def4567890"},
+		{"v1.2.3@sha1:0123456789abcdef0123456789abcdef01234567", "0123456789abcdef0123456789abcdef01234567"},
+		{"main/abc123def", "abc123def"},
+		{"0123456789abcdef0123456789abcdef01234567", "0123456789abcdef0123456789abcdef01234567"},
+		{"HEAD", ""},
+		{"v1.2.3", ""},
+		{"deadbeef", ""}, // too ambiguous without a branch prefix
+		{"latest@sha256:abcdef0123456789", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ParseRevisionSHA(tt.rev); got != tt.want {
+			t.Errorf("ParseRevisionSHA(%q) = %q, want %q", tt.rev, got, tt.want)
+		}
+	}
+}
+
+func TestCommitURL(t *testing.T) {
+	tests := []struct {
+		repo string
+		want string
+	}{
+		{"https://github.com/org/repo.git", "https://github.com/org/repo/commit/abc1234"},
+		{"ssh://git@github.com/org/repo", "https://github.com/org/repo/commit/abc1234"},
+		{"git@github.com:org/repo.git", "https://github.com/org/repo/commit/abc1234"},
+		{"https://gitlab.com/group/sub/repo", "https://gitlab.com/group/sub/repo/-/commit/abc1234"},
+		{"https://bitbucket.org/team/repo.git", "https://bitbucket.org/team/repo/commits/abc1234"},
+		{"https://git.internal.example/repo.git", ""},
+		{"https://github.attacker.io/org/repo.git", ""},
+		{"https://gitlab.evil.example/org/repo.git", ""},
+		{"https://github.corp.example/org/repo.git", "https://github.corp.example/org/repo/commit/abc1234"},
+		{"https://git.corp.example/group/repo.git", "https://git.corp.example/group/repo/-/commit/abc1234"},
+		{"oci://ghcr.io/org/manifests", ""},
+	}
+	t.Setenv(GitHubHostsEnv, "github.corp.example")
+	t.Setenv(GitLabHostsEnv, "gitlab.corp.example, git.corp.example")
+	for _, tt := range tests {
+		if got := CommitURL(tt.repo, "abc1234"); got != tt.want {
+			t.Errorf("CommitURL(%q) = %q, want %q", tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestEnrichChainWithCommits(t *testing.T) {
+	sha := "0123456789abcdef0123456789abcdef01234567"
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/repos/org/repo/commits/"+sha {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"commit":{"message":"Bump api to v2\n\nDetails","author":{"name":"Alice","date":"2026-01-30T10:00:00Z"}}}`))
+	}))
+	defer server.Close()
+
+	e := NewCommitEnricher("")
+	e.apiBase = server.URL
+
+	chain := []ChainLink{
+		{Kind: "GitRepository", Name: "repo", URL: "https://github.com/org/repo.git", Revision: "main@sha1:" + sha},
+		{Kind: "Kustomization", Name: "apps", Revision: "main@sha1:" + sha},
+		{Kind: "Deployment", Name: "api"},
+	}
+	chain = e.EnrichChainWithCommits(context.Background(), chain)
+
+	for _, i := range []int{0, 1} {
+		c := chain[i].Commit
+		if c == nil {
+			t.Fatalf("chain[%d]: expected commit", i)
+		}
+		if c.Subject != "Bump api to v2" || c.Author != "Alice" {
+			t.Errorf("chain[%d]: unexpected commit %+v", i, c)
+		}
+		if c.URL != "https://github.com/org/repo/commit/"+sha {
+			t.Errorf("chain[%d]: URL = %s", i, c.URL)
+		}
+	}
+	if chain[2].Commit != nil {
+		t.Error("expected no commit for link without revision")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 API call (cached), got %d", calls)
+	}
+}
+
+func TestEnrichChainWithCommitsSendsTokenOnlyToKnownHosts(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	t.Setenv("GITHUB_TOKEN", "secret")
+	t.Setenv("GITLAB_TOKEN", "secret")
+
+	e := NewCommitEnricher("")
+	e.apiBase = server.URL
+	chain := e.EnrichChainWithCommits(context.Background(), []ChainLink{
+		{Kind: "GitRepository", URL: "https://github.attacker.io/org/repo.git", Revision: "main@sha1:abc1234"},
+		{Kind: "GitRepository", URL: "https://mygitlab.example/org/repo.git", Revision: "main@sha1:def5678"},
+	})
+	if len(auth) != 0 {
+		t.Errorf("look-alike hosts were called with %v", auth)
+	}
+	if chain[0].Commit == nil || chain[0].Commit.URL != "" {
+		t.Errorf("commit = %+v, want SHA only", chain[0].Commit)
+	}
+}
+
+func TestEnrichChainWithCommits_LookupFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	e := NewCommitEnricher("")
+	e.apiBase = server.URL
+
+	chain := e.EnrichChainWithCommits(context.Background(), []ChainLink{
+		{Kind: "Application", URL: "git@github.com:org/repo.git", Revision: "main@sha1:abc1234"},
+	})
+	c := chain[0].Commit
+	if c == nil || c.URL != "https://github.com/org/repo/commit/abc1234" || c.Subject != "" {
+		t.Errorf("expected URL-only commit on lookup failure, got %+v", c)
+	}
+}
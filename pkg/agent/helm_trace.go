@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -15,7 +16,11 @@ import (
 	"strings"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -100,6 +105,48 @@ func (h *HelmTracer) TraceRelease(ctx context.Context, releaseName, namespace st
 	return h.buildTraceResult(release, "Release", releaseName, namespace)
 }
 
+// TraceResource traces a resource installed by a known Helm release, as
+// named by its meta.helm.sh annotations: the chart, the release and the
+// resource. releaseNamespace is where the release's storage Secrets live.
+func (h *HelmTracer) TraceResource(ctx context.Context, releaseName, releaseNamespace string, object ResourceRef) (*TraceResult, error) {
+	release, err := h.getRelease(ctx, releaseName, releaseNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if release == nil {
+		return &TraceResult{
+			Object:       object,
+			FullyManaged: false,
+			Tool:         "helm",
+			TracedAt:     time.Now(),
+			Error:        fmt.Sprintf("Helm release '%s' not found in namespace '%s'", releaseName, releaseNamespace),
+		}, nil
+	}
+
+	return h.buildTraceResult(release, object.Kind, object.Name, object.Namespace)
+}
+
+// HelmReleaseInfo describes the Helm release revision a resource was
+// installed by, and where Helm stores it.
+type HelmReleaseInfo struct {
+	Release      string `json:"release"`
+	Namespace    string `json:"namespace"`
+	Revision     int    `json:"revision"`
+	Status       string `json:"status"`
+	Chart        string `json:"chart"`
+	ChartVersion string `json:"chartVersion"`
+	AppVersion   string `json:"appVersion,omitempty"`
+
+	// Secret is the storage Secret holding the revision, as
+	// namespace/sh.helm.release.v1.<release>.v<revision>
+	Secret string `json:"secret"`
+
+	// ManagedBy is the Flux HelmRelease that installs the release, when
+	// it isn't a plain helm install
+	ManagedBy *ResourceRef `json:"managedBy,omitempty"`
+}
+
 // helmRelease represents a Helm release stored in a Kubernetes secret
 type helmRelease struct {
 	Name      string            `json:"name"`
@@ -110,6 +157,9 @@ type helmRelease struct {
 	Config    map[string]any    `json:"config"`
 	Manifest  string            `json:"manifest"`
 	Labels    map[string]string `json:"labels"`
+
+	// secret is the storage Secret the release was read from
+	secret ResourceRef
 }
 
 type helmReleaseInfo struct {
@@ -156,6 +206,7 @@ func (h *HelmTracer) listReleases(ctx context.Context, namespace string) ([]*hel
 		if err != nil {
 			continue // Skip undecodable releases
 		}
+		release.secret = ResourceRef{Kind: "Secret", Name: secret.Name, Namespace: secret.Namespace}
 
 		// Keep only the latest version of each release
 		existing, ok := releaseMap[release.Name]
@@ -195,6 +246,7 @@ func (h *HelmTracer) getRelease(ctx context.Context, name, namespace string) (*h
 		if err != nil {
 			continue
 		}
+		release.secret = ResourceRef{Kind: "Secret", Name: secret.Name, Namespace: secret.Namespace}
 
 		if latestRelease == nil || release.Version > latestRelease.Version {
 			latestRelease = release
@@ -274,6 +326,16 @@ func (h *HelmTracer) buildTraceResult(release *helmRelease, kind, name, namespac
 		FullyManaged: true,
 		Tool:         "helm",
 		TracedAt:     time.Now(),
+		Helm: &HelmReleaseInfo{
+			Release:      release.Name,
+			Namespace:    release.Namespace,
+			Revision:     release.Version,
+			Status:       release.Info.Status,
+			Chart:        release.Chart.Metadata.Name,
+			ChartVersion: release.Chart.Metadata.Version,
+			AppVersion:   release.Chart.Metadata.AppVersion,
+			Secret:       release.secret.Namespace + "/" + release.secret.Name,
+		},
 	}
 
 	// Determine chart source URL if available
@@ -393,3 +455,102 @@ func (h *HelmTracer) GetReleaseHistory(ctx context.Context, releaseName, namespa
 
 	return history, nil
 }
+
+// FluxHelmReleaseName returns the name and storage namespace of the Helm
+// release a Flux HelmRelease installs: the latest status.history entry once
+// installed, otherwise spec.releaseName or [targetNamespace-]name, shortened
+// as helm-controller does past Helm's 53 character limit.
+func FluxHelmReleaseName(hr *unstructured.Unstructured) (name, storageNamespace string) {
+	storageNamespace, _, _ = unstructured.NestedString(hr.Object, "spec", "storageNamespace")
+	if storageNamespace == "" {
+		storageNamespace = hr.GetNamespace()
+	}
+	if history, _, _ := unstructured.NestedSlice(hr.Object, "status", "history"); len(history) > 0 {
+		if latest, ok := history[0].(map[string]interface{}); ok {
+			if n, _ := latest["name"].(string); n != "" {
+				return n, storageNamespace
+			}
+		}
+	}
+	if name, _, _ = unstructured.NestedString(hr.Object, "spec", "releaseName"); name != "" {
+		return name, storageNamespace
+	}
+	name = hr.GetName()
+	if target, _, _ := unstructured.NestedString(hr.Object, "spec", "targetNamespace"); target != "" {
+		name = target + "-" + name
+	}
+	if len(name) > 53 {
+		sum := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+		name = name[:40] + "-" + sum[:12]
+	}
+	return name, storageNamespace
+}
+
+// FindFluxHelmRelease returns the Flux HelmRelease that installs the Helm
+// release stored in storageNamespace, or nil for a plain helm install or a
+// cluster without Flux.
+func FindFluxHelmRelease(ctx context.Context, client dynamic.Interface, release, storageNamespace string) (*unstructured.Unstructured, error) {
+	k := fluxKinds["HelmRelease"]
+	for _, version := range k.versions {
+		gvr := schema.GroupVersionResource{Group: k.group, Version: version, Resource: k.resource}
+		list, err := client.Resource(gvr).Namespace("").List(ctx, v1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			continue // Version not served
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			hr := &list.Items[i]
+			if name, ns := FluxHelmReleaseName(hr); name == release && ns == storageNamespace {
+				return hr, nil
+			}
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// TraceHelmRelease continues a Helm trace to the Flux HelmRelease that
+// installs the release and its chart source, giving e.g.
+// HelmRepository → HelmChart → HelmRelease → Release → Deployment. The
+// Helm chart link is dropped when the HelmRelease's chartRef already names
+// a Flux HelmChart.
+func (r *FluxResolver) TraceHelmRelease(ctx context.Context, hr *unstructured.Unstructured, helm *TraceResult) error {
+	ref := ResourceRef{Kind: hr.GetKind(), Name: hr.GetName(), Namespace: hr.GetNamespace()}
+	flux, err := r.Trace(ctx, hr, ref)
+	if err != nil {
+		return err
+	}
+
+	// Flux links are the sources then the HelmRelease; the Helm links are
+	// the chart, the release and the resource
+	sources, hrLink := flux.Chain[:len(flux.Chain)-1], flux.Chain[len(flux.Chain)-1]
+	rest := helm.Chain
+	chain := append([]ChainLink{}, sources...)
+	if len(rest) > 0 && rest[0].Kind == "HelmChart" {
+		fluxChart := false
+		for _, l := range sources {
+			fluxChart = fluxChart || l.Kind == "HelmChart"
+		}
+		if !fluxChart {
+			chain = append(chain, rest[0])
+		}
+		rest = rest[1:]
+	}
+	chain = append(chain, hrLink)
+	helm.Chain = append(chain, rest...)
+
+	helm.Tool = "flux"
+	helm.DependsOn = flux.DependsOn
+	if helm.Helm != nil {
+		helm.Helm.ManagedBy = &ref
+	}
+	helm.FullyManaged = true
+	for _, l := range helm.Chain {
+		if !l.Ready {
+			helm.FullyManaged = false
+		}
+	}
+	return nil
+}
@@ -9,12 +9,16 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
 )
 
 // encodeRelease encodes a helmRelease to the format stored in k8s secrets
@@ -505,3 +509,120 @@ func TestHelmHistoryNoRelease(t *testing.T) {
 		t.Errorf("Expected empty history, got %d entries", len(history))
 	}
 }
+
+func TestHelmTracerTraceResource(t *testing.T) {
+	release := &helmRelease{
+		Name:      "podinfo",
+		Namespace: "apps",
+		Version:   4,
+		Info:      helmReleaseInfo{Status: "deployed"},
+		Chart:     helmChart{Metadata: helmChartMetadata{Name: "podinfo", Version: "6.5.4", AppVersion: "6.5.4"}},
+	}
+	fakeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1.podinfo.v4",
+			Namespace: "flux-system",
+			Labels:    map[string]string{"owner": "helm", "name": "podinfo"},
+		},
+		Data: map[string][]byte{"release": encodeRelease(t, release)},
+	})
+
+	// The storage namespace comes from meta.helm.sh/release-namespace, and
+	// the resource need not appear in the stored manifest
+	result, err := NewHelmTracer(fakeClient).TraceResource(context.Background(), "podinfo", "flux-system",
+		ResourceRef{Kind: "Deployment", Name: "podinfo", Namespace: "apps"})
+	if err != nil {
+		t.Fatalf("TraceResource error: %v", err)
+	}
+	if len(result.Chain) != 3 || result.Chain[2].Kind != "Deployment" || result.Object.Kind != "Deployment" {
+		t.Fatalf("chain = %+v, want HelmChart → Release → Deployment", result.Chain)
+	}
+	want := HelmReleaseInfo{
+		Release: "podinfo", Namespace: "apps", Revision: 4, Status: "deployed",
+		Chart: "podinfo", ChartVersion: "6.5.4", AppVersion: "6.5.4",
+		Secret: "flux-system/sh.helm.release.v1.podinfo.v4",
+	}
+	if result.Helm == nil || *result.Helm != want {
+		t.Errorf("Helm = %+v, want %+v", result.Helm, want)
+	}
+
+	result, _ = NewHelmTracer(fakeClient).TraceResource(context.Background(), "podinfo", "apps", ResourceRef{Kind: "Deployment", Name: "podinfo", Namespace: "apps"})
+	if result.Error == "" || result.Helm != nil {
+		t.Errorf("release in the wrong namespace should not be found: %+v", result)
+	}
+}
+
+func TestFluxHelmReleaseName(t *testing.T) {
+	tests := []struct {
+		name          string
+		hr            *unstructured.Unstructured
+		wantName      string
+		wantNamespace string
+	}{
+		{"default", agenttest.FluxHelmRelease("flux-system", "podinfo"), "podinfo", "flux-system"},
+		{"target namespace", agenttest.FluxHelmRelease("flux-system", "podinfo", withSpec(map[string]interface{}{"targetNamespace": "apps"})), "apps-podinfo", "flux-system"},
+		{"release name and storage namespace", agenttest.FluxHelmRelease("flux-system", "podinfo", withSpec(map[string]interface{}{
+			"releaseName": "web", "targetNamespace": "apps", "storageNamespace": "apps",
+		})), "web", "apps"},
+		{"long name", agenttest.FluxHelmRelease("flux-system", "a-very-long-helm-release-name-for-the-payments-service", withSpec(map[string]interface{}{"targetNamespace": "payments-production"})),
+			"payments-production-a-very-long-helm-rel-", "flux-system"},
+		{"status history", agenttest.FluxHelmRelease("flux-system", "podinfo", func(u *unstructured.Unstructured) {
+			_ = unstructured.SetNestedSlice(u.Object, []interface{}{map[string]interface{}{"name": "podinfo-v1", "version": int64(3)}}, "status", "history")
+		}), "podinfo-v1", "flux-system"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, ns := FluxHelmReleaseName(tt.hr)
+			if tt.name == "long name" {
+				if len(name) != 53 || !strings.HasPrefix(name, tt.wantName) {
+					t.Errorf("name = %q (%d chars), want %q plus a 12 character hash", name, len(name), tt.wantName)
+				}
+			} else if name != tt.wantName {
+				t.Errorf("name = %q, want %q", name, tt.wantName)
+			}
+			if ns != tt.wantNamespace {
+				t.Errorf("storage namespace = %q, want %q", ns, tt.wantNamespace)
+			}
+		})
+	}
+}
+
+func TestFluxTraceHelmRelease(t *testing.T) {
+	ctx := context.Background()
+	repo := agenttest.FluxHelmRepository("flux-system", "podinfo", "https://stefanprodan.github.io/podinfo")
+	hr := agenttest.FluxHelmRelease("flux-system", "podinfo", withSpec(map[string]interface{}{"targetNamespace": "apps"}))
+	other := agenttest.FluxHelmRelease("flux-system", "redis")
+	client := agenttest.FakeClient(repo, hr, other)
+
+	found, err := FindFluxHelmRelease(ctx, client, "apps-podinfo", "flux-system")
+	if err != nil || found == nil || found.GetName() != "podinfo" {
+		t.Fatalf("FindFluxHelmRelease = %v, %v; want HelmRelease podinfo", found, err)
+	}
+	if found, _ := FindFluxHelmRelease(ctx, client, "apps-podinfo", "apps"); found != nil {
+		t.Errorf("a release stored elsewhere should not match, got %s", found.GetName())
+	}
+
+	result := &TraceResult{
+		Tool: "helm",
+		Chain: []ChainLink{
+			{Kind: "HelmChart", Name: "podinfo", Ready: true, Revision: "6.5.4"},
+			{Kind: "Release", Name: "apps-podinfo", Ready: true, Revision: "v4"},
+			{Kind: "Deployment", Name: "podinfo", Namespace: "apps", Ready: true},
+		},
+		FullyManaged: true,
+		Helm:         &HelmReleaseInfo{Release: "apps-podinfo", Secret: "flux-system/sh.helm.release.v1.apps-podinfo.v4"},
+	}
+	if err := NewFluxResolver(ClientFluxLookup(client)).TraceHelmRelease(ctx, found, result); err != nil {
+		t.Fatalf("TraceHelmRelease: %v", err)
+	}
+	var kinds []string
+	for _, l := range result.Chain {
+		kinds = append(kinds, l.Kind)
+	}
+	if strings.Join(kinds, " → ") != "HelmRepository → HelmChart → HelmRelease → Release → Deployment" {
+		t.Errorf("chain = %v", kinds)
+	}
+	if result.Tool != "flux" || !result.FullyManaged || result.Helm.ManagedBy == nil || result.Helm.ManagedBy.Name != "podinfo" {
+		t.Errorf("result = %+v, helm = %+v", result, result.Helm)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// FieldManager summarizes one managedFields entry on a resource
+type FieldManager struct {
+	// Manager is the field manager name (e.g., "kustomize-controller", "kubectl-edit")
+	Manager string `json:"manager"`
+
+	// Tool is the recognized tool behind the manager (e.g., "Flux", "kubectl")
+	Tool string `json:"tool"`
+
+	// Operation is "Apply" (server-side apply) or "Update"
+	Operation string `json:"operation"`
+
+	// Subresource is set when the fields were written via a subresource (e.g., "status")
+	Subresource string `json:"subresource,omitempty"`
+
+	// Time is when the manager last changed its fields
+	Time *time.Time `json:"time,omitempty"`
+
+	// Fields lists the owned field paths, two levels deep (e.g., "spec.replicas")
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ManualChange reports whether the manager represents a human editing the
+// resource directly rather than a controller or GitOps tool.
+func (m FieldManager) ManualChange() bool {
+	return m.Tool == "kubectl"
+}
+
+// OwnsSpec reports whether the manager owns any spec field.
+func (m FieldManager) OwnsSpec() bool {
+	if m.Subresource != "" {
+		return false
+	}
+	for _, f := range m.Fields {
+		if f == "spec" || strings.HasPrefix(f, "spec.") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseManagedFields returns the field managers of a resource, newest first.
+func ParseManagedFields(obj *unstructured.Unstructured) []FieldManager {
+	var managers []FieldManager
+	for _, mf := range obj.GetManagedFields() {
+		m := FieldManager{
+			Manager:     mf.Manager,
+			Tool:        ManagerTool(mf.Manager),
+			Operation:   string(mf.Operation),
+			Subresource: mf.Subresource,
+		}
+		if mf.Time != nil {
+			t := mf.Time.Time
+			m.Time = &t
+		}
+		if mf.FieldsV1 != nil {
+			m.Fields = fieldPaths(mf.FieldsV1.Raw, 2)
+		}
+		managers = append(managers, m)
+	}
+
+	sort.SliceStable(managers, func(i, j int) bool {
+		ti, tj := managers[i].Time, managers[j].Time
+		if ti == nil || tj == nil {
+			return tj == nil && ti != nil
+		}
+		return ti.After(*tj)
+	})
+	return managers
+}
+
+// LastSpecWriter returns the most recent manager that owns spec fields, or nil.
+func LastSpecWriter(managers []FieldManager) *FieldManager {
+	for i := range managers {
+		if managers[i].OwnsSpec() {
+			return &managers[i]
+		}
+	}
+	return nil
+}
+
+// ManagerTool maps a field manager name to the tool behind it.
+func ManagerTool(manager string) string {
+	switch {
+	case strings.Contains(manager, "kustomize-controller"):
+		return "Flux"
+	case strings.Contains(manager, "helm-controller"):
+		return "Flux (Helm)"
+	case strings.Contains(manager, "argocd"):
+		return "ArgoCD"
+	case manager == "helm":
+		return "Helm"
+	case strings.HasPrefix(manager, "kubectl"):
+		return "kubectl"
+	case strings.Contains(manager, "confighub") || strings.Contains(manager, "cub-worker"):
+		return "ConfigHub"
+	case strings.Contains(manager, "crossplane"):
+		return "Crossplane"
+	case strings.Contains(manager, "terraform"):
+		return "Terraform"
+	case manager == "kube-controller-manager" || strings.HasPrefix(manager, "kube-"):
+		return "Kubernetes"
+	default:
+		return manager
+	}
+}
+
+// fieldPaths flattens a FieldsV1 document into dotted paths up to depth.
+// Keyed list entries (k:, v:, i:) are collapsed into their parent path.
+func fieldPaths(raw []byte, depth int) []string {
+	var tree map[string]interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var walk func(node map[string]interface{}, prefix string, level int)
+	walk = func(node map[string]interface{}, prefix string, level int) {
+		for key, child := range node {
+			if !strings.HasPrefix(key, "f:") {
+				continue
+			}
+			path := strings.TrimPrefix(key, "f:")
+			if prefix != "" {
+				path = prefix + "." + path
+			}
+			childMap, _ := child.(map[string]interface{})
+			if level >= depth || !hasFieldKeys(childMap) {
+				seen[path] = true
+				continue
+			}
+			walk(childMap, path, level+1)
+		}
+	}
+	walk(tree, "", 1)
+
+	paths := make([]string, 0, len(seen))
+	for p := range seen {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func hasFieldKeys(node map[string]interface{}) bool {
+	for key := range node {
+		if strings.HasPrefix(key, "f:") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseManagedFields(t *testing.T) {
+	older := metav1.NewTime(time.Date(2026, 1, 30, 9, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(time.Date(2026, 1, 30, 10, 0, 0, 0, time.UTC))
+	newest := metav1.NewTime(time.Date(2026, 1, 30, 11, 0, 0, 0, time.UTC))
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:   "kustomize-controller",
+			Operation: metav1.ManagedFieldsOperationApply,
+			Time:      &older,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:app":{}}},` +
+				`"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"web\"}":{".":{},"f:image":{}}}}}}}`)},
+		},
+		{
+			Manager:   "kubectl-edit",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			Time:      &newer,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+		},
+		{
+			Manager:     "kube-controller-manager",
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: "status",
+			Time:        &newest,
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:replicas":{}}}`)},
+		},
+	})
+
+	managers := ParseManagedFields(obj)
+	if len(managers) != 3 {
+		t.Fatalf("expected 3 managers, got %d", len(managers))
+	}
+	if managers[0].Manager != "kube-controller-manager" || managers[2].Manager != "kustomize-controller" {
+		t.Errorf("managers not sorted newest first: %v, %v, %v", managers[0].Manager, managers[1].Manager, managers[2].Manager)
+	}
+
+	want := []string{"metadata.labels", "spec.replicas", "spec.template"}
+	if !reflect.DeepEqual(managers[2].Fields, want) {
+		t.Errorf("Fields = %v, want %v", managers[2].Fields, want)
+	}
+	if managers[2].Tool != "Flux" {
+		t.Errorf("Tool = %s, want Flux", managers[2].Tool)
+	}
+
+	last := LastSpecWriter(managers)
+	if last == nil || last.Manager != "kubectl-edit" || !last.ManualChange() {
+		t.Errorf("LastSpecWriter = %+v, want manual kubectl-edit", last)
+	}
+}
+
+func TestManagerTool(t *testing.T) {
+	tests := map[string]string{
+		"argocd-controller":         "ArgoCD",
+		"helm-controller":           "Flux (Helm)",
+		"helm":                      "Helm",
+		"kubectl-client-side-apply": "kubectl",
+		"kube-controller-manager":   "Kubernetes",
+		"my-operator":               "my-operator",
+	}
+	for manager, want := range tests {
+		if got := ManagerTool(manager); got != want {
+			t.Errorf("ManagerTool(%q) = %q, want %q", manager, got, want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// OwnerLookup fetches the object an ownerReference of an object in
+// namespace points at. It returns nil and no error when the owner does not
+// exist.
+type OwnerLookup func(ctx context.Context, namespace string, ref metav1.OwnerReference) (*unstructured.Unstructured, error)
+
+// ClientOwnerLookup looks owners up in the cluster.
+func ClientOwnerLookup(client dynamic.Interface) OwnerLookup {
+	return func(ctx context.Context, namespace string, ref metav1.OwnerReference) (*unstructured.Unstructured, error) {
+		gvr, err := APIVersionKindToGVR(ref.APIVersion, ref.Kind)
+		if err != nil {
+			return nil, nil // Not a kind we can fetch; the walk stops here
+		}
+		owner, err := client.Resource(gvr).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return owner, err
+	}
+}
+
+// OwnerChain is an object's controller ownerReferences chain, from the
+// object itself up to its top-level controller.
+type OwnerChain struct {
+	Links []ResourceRef `json:"links"`
+
+	// Managed is the nearest link carrying a GitOps owner's markers, when
+	// ownership was attributed through the chain
+	Managed *ResourceRef `json:"managed,omitempty"`
+
+	// Cycle is set when the ownerReferences loop; the chain stops before
+	// the first repeated object
+	Cycle bool `json:"cycle,omitempty"`
+
+	// Unresolved is the owner the walk could not fetch (deleted, not
+	// listed, or an unknown kind)
+	Unresolved *ResourceRef `json:"unresolved,omitempty"`
+}
+
+// Top returns the top-level controller, the last link.
+func (c OwnerChain) Top() ResourceRef {
+	if len(c.Links) == 0 {
+		return ResourceRef{}
+	}
+	return c.Links[len(c.Links)-1]
+}
+
+// String renders the chain as "Pod/x → Job/y → CronJob/z".
+func (c OwnerChain) String() string {
+	parts := make([]string, 0, len(c.Links)+1)
+	for _, l := range c.Links {
+		parts = append(parts, l.Kind+"/"+l.Name)
+	}
+	if c.Unresolved != nil {
+		parts = append(parts, c.Unresolved.Kind+"/"+c.Unresolved.Name+" (unresolved)")
+	}
+	s := strings.Join(parts, " → ")
+	if c.Cycle {
+		s += " (cycle)"
+	}
+	return s
+}
+
+// OwnerResolver walks controller ownerReferences to the top-level
+// controller. Owners and resolved chains are cached, so the Pods of one
+// ReplicaSet cost a single walk; a resolver is meant for one scan.
+type OwnerResolver struct {
+	lookup OwnerLookup
+	owners map[string]*unstructured.Unstructured // nil records a missing owner
+	chains map[string]resolvedChain
+}
+
+type resolvedChain struct {
+	objs  []*unstructured.Unstructured
+	chain OwnerChain
+}
+
+// NewOwnerResolver returns a resolver fetching owners with lookup.
+func NewOwnerResolver(lookup OwnerLookup) *OwnerResolver {
+	return &OwnerResolver{
+		lookup: lookup,
+		owners: map[string]*unstructured.Unstructured{},
+		chains: map[string]resolvedChain{},
+	}
+}
+
+// Resolve returns obj and its controllers up to the top-level one, with
+// the chain describing them.
+func (r *OwnerResolver) Resolve(ctx context.Context, obj *unstructured.Unstructured) ([]*unstructured.Unstructured, OwnerChain) {
+	key := objectKey(obj)
+	if c, ok := r.chains[key]; ok {
+		return c.objs, c.chain
+	}
+
+	objs := []*unstructured.Unstructured{obj}
+	chain := OwnerChain{Links: []ResourceRef{objectRef(obj)}}
+	seen := map[string]bool{key: true}
+	add := func(o *unstructured.Unstructured) bool {
+		if seen[objectKey(o)] {
+			chain.Cycle = true
+			return false
+		}
+		seen[objectKey(o)] = true
+		objs = append(objs, o)
+		chain.Links = append(chain.Links, objectRef(o))
+		return true
+	}
+
+	current := obj
+walk:
+	for len(objs) <= maxOwnerDepth {
+		ref, ok := controllerRef(current)
+		if !ok {
+			break
+		}
+		parent := r.owner(ctx, current.GetNamespace(), ref)
+		if parent == nil {
+			chain.Unresolved = &ResourceRef{Kind: ref.Kind, Name: ref.Name, Namespace: current.GetNamespace()}
+			break
+		}
+
+		// Reuse the parent's resolved chain when another object got there first
+		if c, ok := r.chains[objectKey(parent)]; ok {
+			for _, o := range c.objs {
+				if !add(o) {
+					break walk
+				}
+			}
+			chain.Cycle, chain.Unresolved = c.chain.Cycle, c.chain.Unresolved
+			break
+		}
+		if !add(parent) {
+			break
+		}
+		current = parent
+	}
+
+	r.chains[key] = resolvedChain{objs, chain}
+	if !chain.Cycle {
+		// Each controller's chain is the rest of this one
+		for i := 1; i < len(objs); i++ {
+			k := objectKey(objs[i])
+			if _, ok := r.chains[k]; !ok {
+				r.chains[k] = resolvedChain{objs[i:], OwnerChain{Links: chain.Links[i:], Unresolved: chain.Unresolved}}
+			}
+		}
+	}
+	return objs, chain
+}
+
+// DetectOwnership is DetectOwnership, except that an object owned only
+// through ownerReferences inherits the GitOps owner of its nearest
+// controller that has one. Source then records that controller, e.g.
+// "ownerRef:CronJob/backup → label:kustomize.toolkit.fluxcd.io/name", and
+// the chain's Managed points at it. Without such a controller the direct
+// result stands.
+func (r *OwnerResolver) DetectOwnership(ctx context.Context, obj *unstructured.Unstructured) (Ownership, OwnerChain) {
+	objs, chain := r.Resolve(ctx, obj)
+	direct := DetectOwnership(obj)
+	if direct.Type != OwnerKubernetes {
+		return direct, chain
+	}
+
+	for _, o := range objs[1:] {
+		ownership := DetectOwnership(o)
+		switch ownership.Type {
+		case OwnerKubernetes:
+			continue
+		case OwnerUnknown:
+			return direct, chain
+		}
+		ref := objectRef(o)
+		chain.Managed = &ref
+		ownership.Source = "ownerRef:" + o.GetKind() + "/" + o.GetName() + " → " + ownership.Source
+		return ownership, chain
+	}
+	return direct, chain
+}
+
+// owner fetches an owner through the cache.
+func (r *OwnerResolver) owner(ctx context.Context, namespace string, ref metav1.OwnerReference) *unstructured.Unstructured {
+	key := ownerKey(ref.Kind, namespace, ref.Name)
+	if o, ok := r.owners[key]; ok {
+		return o
+	}
+	o, err := r.lookup(ctx, namespace, ref)
+	if err != nil {
+		o = nil
+	}
+	r.owners[key] = o
+	return o
+}
+
+// controllerRef returns obj's controller ownerReference, or its first one.
+func controllerRef(obj *unstructured.Unstructured) (metav1.OwnerReference, bool) {
+	refs := obj.GetOwnerReferences()
+	if len(refs) == 0 {
+		return metav1.OwnerReference{}, false
+	}
+	for _, r := range refs {
+		if r.Controller != nil && *r.Controller {
+			return r, true
+		}
+	}
+	return refs[0], true
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	return ownerKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+func objectRef(obj *unstructured.Unstructured) ResourceRef {
+	return ResourceRef{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace()}
+}
@@ -0,0 +1,88 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+// countingLookup serves owners from objs and counts fetches.
+func countingLookup(fetches *int, objs ...*unstructured.Unstructured) OwnerLookup {
+	return func(_ context.Context, namespace string, ref metav1.OwnerReference) (*unstructured.Unstructured, error) {
+		*fetches++
+		for _, o := range objs {
+			if o.GetKind() == ref.Kind && o.GetName() == ref.Name && o.GetNamespace() == namespace {
+				return o, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+func TestOwnerResolverChain(t *testing.T) {
+	cronJob := agenttest.Object("batch/v1", "CronJob", "ops", "backup",
+		agenttest.ManagedByFluxKustomization("ops", "flux-system"))
+	job := agenttest.Object("batch/v1", "Job", "ops", "backup-1",
+		agenttest.WithOwnerReference("batch/v1", "CronJob", "backup"))
+	pod1 := agenttest.Object("v1", "Pod", "ops", "backup-1-a",
+		agenttest.WithOwnerReference("batch/v1", "Job", "backup-1"))
+	pod2 := agenttest.Object("v1", "Pod", "ops", "backup-1-b",
+		agenttest.WithOwnerReference("batch/v1", "Job", "backup-1"))
+
+	var fetches int
+	r := NewOwnerResolver(countingLookup(&fetches, cronJob, job))
+
+	ownership, chain := r.DetectOwnership(context.Background(), pod1)
+	if ownership.Type != OwnerFlux || ownership.Name != "ops" {
+		t.Errorf("ownership = %s/%s, want flux/ops", ownership.Type, ownership.Name)
+	}
+	if got, want := chain.String(), "Pod/backup-1-a → Job/backup-1 → CronJob/backup"; got != want {
+		t.Errorf("chain = %q, want %q", got, want)
+	}
+	if chain.Managed == nil || chain.Managed.Kind != "CronJob" || chain.Top().Name != "backup" {
+		t.Errorf("managed = %+v, top = %+v", chain.Managed, chain.Top())
+	}
+
+	// The second Pod reuses the Job's resolved chain
+	before := fetches
+	if _, chain := r.Resolve(context.Background(), pod2); chain.String() != "Pod/backup-1-b → Job/backup-1 → CronJob/backup" {
+		t.Errorf("pod2 chain = %s", chain)
+	}
+	if fetches != before {
+		t.Errorf("pod2 cost %d fetches, want none", fetches-before)
+	}
+}
+
+func TestOwnerResolverStops(t *testing.T) {
+	// a → b → a
+	a := agenttest.Object("v1", "ConfigMap", "ns", "a", agenttest.WithOwnerReference("v1", "ConfigMap", "b"))
+	b := agenttest.Object("v1", "ConfigMap", "ns", "b", agenttest.WithOwnerReference("v1", "ConfigMap", "a"))
+	var fetches int
+	r := NewOwnerResolver(countingLookup(&fetches, a, b))
+
+	_, chain := r.Resolve(context.Background(), a)
+	if !chain.Cycle || len(chain.Links) != 2 {
+		t.Errorf("a: chain = %s, cycle = %v", chain, chain.Cycle)
+	}
+	// b splices a's cached chain and still stops at the repeat
+	_, chain = r.Resolve(context.Background(), b)
+	if !chain.Cycle || len(chain.Links) != 2 {
+		t.Errorf("b: chain = %s, cycle = %v", chain, chain.Cycle)
+	}
+
+	orphan := agenttest.Object("v1", "Pod", "ns", "p", agenttest.WithOwnerReference("apps/v1", "ReplicaSet", "gone"))
+	ownership, chain := r.DetectOwnership(context.Background(), orphan)
+	if chain.Unresolved == nil || chain.Unresolved.Name != "gone" || ownership.Type != OwnerKubernetes {
+		t.Errorf("unresolved = %+v, ownership = %s", chain.Unresolved, ownership.Type)
+	}
+	if got, want := chain.String(), "Pod/p → ReplicaSet/gone (unresolved)"; got != want {
+		t.Errorf("chain = %q, want %q", got, want)
+	}
+}
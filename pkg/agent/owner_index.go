@@ -0,0 +1,97 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxOwnerDepth bounds the ownerReferences walk (Pod → Job → CronJob is 2).
+const maxOwnerDepth = 8
+
+// OwnerIndex resolves ownership through ownerReferences among listed
+// objects. GitOps labels do not propagate to what controllers create, so a
+// Pod of a Job of a CronJob Flux applies carries no Flux label and
+// DetectOwnership calls it k8s-owned. The index walks the controller
+// ownerReferences with an OwnerResolver up to an object with a GitOps owner
+// and attributes the descendant to it.
+type OwnerIndex struct {
+	byUID    map[types.UID]*unstructured.Unstructured
+	byKey    map[string]*unstructured.Unstructured
+	resolver *OwnerResolver
+}
+
+// NewOwnerIndex returns an index of the given objects, the candidate owners.
+func NewOwnerIndex(objs ...[]unstructured.Unstructured) *OwnerIndex {
+	x := &OwnerIndex{byUID: map[types.UID]*unstructured.Unstructured{}, byKey: map[string]*unstructured.Unstructured{}}
+	for _, list := range objs {
+		for i := range list {
+			x.Add(&list[i])
+		}
+	}
+	return x
+}
+
+// Add indexes obj as a candidate owner.
+func (x *OwnerIndex) Add(obj *unstructured.Unstructured) {
+	if uid := obj.GetUID(); uid != "" {
+		x.byUID[uid] = obj
+	}
+	x.byKey[objectKey(obj)] = obj
+	x.resolver = nil // Cached chains may now resolve further
+}
+
+// Len returns the number of indexed objects.
+func (x *OwnerIndex) Len() int {
+	if x == nil {
+		return 0
+	}
+	return len(x.byKey)
+}
+
+// DetectOwnership is OwnerResolver.DetectOwnership over the indexed
+// objects. With a nil or empty index the direct result stands.
+func (x *OwnerIndex) DetectOwnership(resource *unstructured.Unstructured) Ownership {
+	if x.Len() == 0 {
+		return DetectOwnership(resource)
+	}
+	ownership, _ := x.resolve().DetectOwnership(context.Background(), resource)
+	return ownership
+}
+
+// Chain returns resource's ownerReferences chain among the indexed objects.
+func (x *OwnerIndex) Chain(resource *unstructured.Unstructured) OwnerChain {
+	if x.Len() == 0 {
+		return OwnerChain{Links: []ResourceRef{objectRef(resource)}}
+	}
+	_, chain := x.resolve().DetectOwnership(context.Background(), resource)
+	return chain
+}
+
+func (x *OwnerIndex) resolve() *OwnerResolver {
+	if x.resolver == nil {
+		x.resolver = NewOwnerResolver(x.lookup)
+	}
+	return x.resolver
+}
+
+// lookup finds an owner by UID, then by name in the namespace, then as a
+// cluster-scoped owner of a namespaced object.
+func (x *OwnerIndex) lookup(_ context.Context, namespace string, ref metav1.OwnerReference) (*unstructured.Unstructured, error) {
+	if owner := x.byUID[ref.UID]; ref.UID != "" && owner != nil {
+		return owner, nil
+	}
+	if owner := x.byKey[ownerKey(ref.Kind, namespace, ref.Name)]; owner != nil {
+		return owner, nil
+	}
+	return x.byKey[ownerKey(ref.Kind, "", ref.Name)], nil
+}
+
+func ownerKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
@@ -0,0 +1,64 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+func TestOwnerIndexWalksOwnerReferences(t *testing.T) {
+	cronJob := agenttest.Object("batch/v1", "CronJob", "ops", "backup",
+		agenttest.ManagedByFluxKustomization("ops", "flux-system"))
+	job := agenttest.Object("batch/v1", "Job", "ops", "backup-28000000",
+		agenttest.WithOwnerReference("batch/v1", "CronJob", "backup"))
+	pod := agenttest.Object("v1", "Pod", "ops", "backup-28000000-abcde",
+		agenttest.WithOwnerReference("batch/v1", "Job", "backup-28000000"))
+	nativeRS := agenttest.Object("apps/v1", "ReplicaSet", "ops", "adhoc-5d4f",
+		agenttest.WithOwnerReference("apps/v1", "Deployment", "adhoc"))
+	nativeDep := agenttest.Deployment("ops", "adhoc")
+	nativePod := agenttest.Object("v1", "Pod", "ops", "adhoc-5d4f-xyz",
+		agenttest.WithOwnerReference("apps/v1", "ReplicaSet", "adhoc-5d4f"))
+
+	x := NewOwnerIndex([]unstructured.Unstructured{*cronJob, *job, *nativeRS, *nativeDep})
+
+	for _, obj := range []*unstructured.Unstructured{job, pod} {
+		got := x.DetectOwnership(obj)
+		if got.Type != OwnerFlux || got.Name != "ops" {
+			t.Errorf("%s: got %s/%s, want flux/ops", obj.GetKind(), got.Type, got.Name)
+		}
+		if want := "ownerRef:CronJob/backup → label:kustomize.toolkit.fluxcd.io/name"; got.Source != want {
+			t.Errorf("%s: source = %q, want %q", obj.GetKind(), got.Source, want)
+		}
+	}
+
+	// A chain ending at an unmanaged Deployment keeps the direct owner
+	if got := x.DetectOwnership(nativePod); got.Type != OwnerKubernetes || got.Name != "adhoc-5d4f" {
+		t.Errorf("native pod: got %+v", got)
+	}
+
+	// Owners missing from the index, and a nil index, change nothing
+	orphan := agenttest.Object("v1", "Pod", "ops", "x", agenttest.WithOwnerReference("batch/v1", "Job", "gone"))
+	if got := x.DetectOwnership(orphan); got.Type != OwnerKubernetes {
+		t.Errorf("missing owner: got %+v", got)
+	}
+	var empty *OwnerIndex
+	if got := empty.DetectOwnership(pod); got.Type != OwnerKubernetes {
+		t.Errorf("nil index: got %+v", got)
+	}
+}
+
+func TestOwnerIndexStopsOnCycles(t *testing.T) {
+	a := agenttest.Object("v1", "ConfigMap", "ns", "a", agenttest.WithOwnerReference("v1", "ConfigMap", "b"))
+	b := agenttest.Object("v1", "ConfigMap", "ns", "b", agenttest.WithOwnerReference("v1", "ConfigMap", "a"))
+	x := NewOwnerIndex()
+	x.Add(a)
+	x.Add(b)
+	if got := x.DetectOwnership(a); got.Type != OwnerKubernetes || got.Name != "b" {
+		t.Errorf("cycle: got %+v", got)
+	}
+}
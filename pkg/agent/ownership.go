@@ -69,6 +69,37 @@ func DetectOwnership(resource *unstructured.Unstructured) Ownership {
 	return Ownership{Type: OwnerUnknown}
 }
 
+// DetectContestedOwnership returns every GitOps manager that claims the
+// resource when more than one does, e.g. an Argo CD instance label next to
+// Flux kustomize labels, or a ConfigHub UnitSlug on a resource Argo CD still
+// syncs. Two managers applying the same object fight over it, a common
+// leftover of migrating between tools. It returns nil when at most one
+// manager claims the resource.
+//
+// Helm, Crossplane and owner references are not counted: Flux and Argo CD
+// render Helm charts, and controllers label what they create, so those marks
+// appear alongside a GitOps manager by design.
+func DetectContestedOwnership(resource *unstructured.Unstructured) []Ownership {
+	labels := resource.GetLabels()
+	annotations := resource.GetAnnotations()
+
+	var claims []Ownership
+	for _, detect := range []func(labels, annotations map[string]string) Ownership{
+		detectFluxOwnership,
+		detectArgoOwnership,
+		detectTerraformOwnership,
+		detectConfigHubOwnership,
+	} {
+		if ownership := detect(labels, annotations); ownership.Type != "" {
+			claims = append(claims, ownership)
+		}
+	}
+	if len(claims) < 2 {
+		return nil
+	}
+	return claims
+}
+
 func detectFluxOwnership(labels, annotations map[string]string) Ownership {
 	// Flux Kustomization
 	if name, ok := labels["kustomize.toolkit.fluxcd.io/name"]; ok {
@@ -100,54 +131,43 @@ func detectFluxOwnership(labels, annotations map[string]string) Ownership {
 }
 
 func detectArgoOwnership(labels, annotations map[string]string) Ownership {
-	// Argo CD Application - check for argocd.argoproj.io/instance label
-	if argoInstance, isArgo := labels["argocd.argoproj.io/instance"]; isArgo {
-		// Prefer the Argo-specific label value
-		name := argoInstance
-		source := "label:argocd.argoproj.io/instance"
-		if name == "" {
+	// Tracking-id annotation or instance label, in the order the cluster's
+	// tracking method prefers
+	_, name, source := ArgoApplication(labels, annotations)
+	if name == "" {
+		if v, isArgo := labels[ArgoInstanceLabel]; isArgo && v == "" {
 			// Fall back to app.kubernetes.io/instance if Argo label is empty
 			name = labels["app.kubernetes.io/instance"]
 			source = "label:app.kubernetes.io/instance"
 		}
-		if name != "" {
-			return Ownership{
-				Type:       OwnerArgo,
-				SubType:    "application",
-				Name:       name,
-				Source:     source,
-				Confidence: "medium",
-			}
-		}
 	}
-
-	// Alternative: check tracking-id annotation
-	if tracking, ok := annotations["argocd.argoproj.io/tracking-id"]; ok && tracking != "" {
-		// Format: <app-name>:<group>/<kind>:<namespace>/<name>
-		// Handle malformed tracking IDs gracefully
-		parts := strings.SplitN(tracking, ":", 2)
-		if len(parts) > 0 && parts[0] != "" {
-			return Ownership{
-				Type:       OwnerArgo,
-				SubType:    "application",
-				Name:       parts[0],
-				Source:     "annotation:argocd.argoproj.io/tracking-id",
-				Confidence: "medium",
-			}
-		}
+	if name == "" {
+		return Ownership{}
+	}
+	return Ownership{
+		Type:       OwnerArgo,
+		SubType:    "application",
+		Name:       name,
+		Source:     source,
+		Confidence: "medium",
 	}
-
-	return Ownership{}
 }
 
 func detectHelmOwnership(labels, annotations map[string]string) Ownership {
 	// Helm release
+	// Helm 3 annotates what it installs with the exact release name and the
+	// namespace its storage Secrets live in; the instance label is a chart
+	// convention that usually, but not always, matches the release.
 	if release, ok := labels["app.kubernetes.io/managed-by"]; ok && release == "Helm" {
-		name := labels["app.kubernetes.io/instance"]
+		name := annotations["meta.helm.sh/release-name"]
+		if name == "" {
+			name = labels["app.kubernetes.io/instance"]
+		}
 		return Ownership{
 			Type:       OwnerHelm,
 			SubType:    "release",
 			Name:       name,
+			Namespace:  annotations["meta.helm.sh/release-namespace"],
 			Source:     "label:app.kubernetes.io/managed-by=Helm",
 			Confidence: "high",
 		}
@@ -197,20 +217,31 @@ func detectTerraformOwnership(labels, annotations map[string]string) Ownership {
 	return Ownership{}
 }
 
+// ConfigHubSpace returns the ConfigHub space a resource was applied from, or
+// "". It is set even when another tool, e.g. Flux in delegated apply, owns
+// the resource.
+func ConfigHubSpace(resource *unstructured.Unstructured) string {
+	return configHubSpace(resource.GetLabels(), resource.GetAnnotations())
+}
+
+// configHubSpace reads confighub.com/SpaceName, preferring the annotation.
+func configHubSpace(labels, annotations map[string]string) string {
+	if space := annotations["confighub.com/SpaceName"]; space != "" {
+		return space
+	}
+	return labels["confighub.com/SpaceName"]
+}
+
 func detectConfigHubOwnership(labels, annotations map[string]string) Ownership {
 	// ConfigHub Unit - check both label and annotation
 	// Label: confighub.com/UnitSlug
 	// Annotations: confighub.com/SpaceName, confighub.com/SpaceID, confighub.com/RevisionNum
 	if unit, ok := labels["confighub.com/UnitSlug"]; ok {
-		space := annotations["confighub.com/SpaceName"]
-		if space == "" {
-			space = labels["confighub.com/SpaceName"]
-		}
 		return Ownership{
 			Type:       OwnerConfigHub,
 			SubType:    "unit",
 			Name:       unit,
-			Namespace:  space,
+			Namespace:  configHubSpace(labels, annotations),
 			Source:     "label:confighub.com/UnitSlug",
 			Confidence: "high",
 		}
@@ -218,12 +249,11 @@ func detectConfigHubOwnership(labels, annotations map[string]string) Ownership {
 
 	// Also check annotation (some resources may only have annotation)
 	if unit, ok := annotations["confighub.com/UnitSlug"]; ok {
-		space := annotations["confighub.com/SpaceName"]
 		return Ownership{
 			Type:       OwnerConfigHub,
 			SubType:    "unit",
 			Name:       unit,
-			Namespace:  space,
+			Namespace:  configHubSpace(labels, annotations),
 			Source:     "annotation:confighub.com/UnitSlug",
 			Confidence: "high",
 		}
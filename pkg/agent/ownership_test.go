@@ -6,6 +6,7 @@ package agent
 import (
 	"testing"
 
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -226,6 +227,22 @@ func TestDetectOwnership_Helm(t *testing.T) {
 	}
 }
 
+func TestDetectOwnership_HelmReleaseAnnotations(t *testing.T) {
+	// The release name and storage namespace come from Helm's annotations
+	// when the chart's instance label says something else
+	resource := newTestResource("apps", "podinfo", map[string]string{
+		"app.kubernetes.io/managed-by": "Helm",
+		"app.kubernetes.io/instance":   "podinfo",
+	}, map[string]string{
+		"meta.helm.sh/release-name":      "apps-podinfo",
+		"meta.helm.sh/release-namespace": "flux-system",
+	})
+	ownership := DetectOwnership(resource)
+	if ownership.Type != OwnerHelm || ownership.Name != "apps-podinfo" || ownership.Namespace != "flux-system" {
+		t.Errorf("ownership = %+v, want Helm release flux-system/apps-podinfo", ownership)
+	}
+}
+
 func TestDetectOwnership_Terraform(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -342,6 +359,24 @@ func TestDetectOwnership_ConfigHub(t *testing.T) {
 	}
 }
 
+func TestConfigHubSpace(t *testing.T) {
+	// Delegated apply: Flux owns the resource, ConfigHub still names the space
+	flux := newTestResource("apps", "web",
+		map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps", "confighub.com/SpaceName": "web-prod"}, nil)
+	if got := ConfigHubSpace(flux); got != "web-prod" {
+		t.Errorf("ConfigHubSpace() = %q, want the label space", got)
+	}
+	both := newTestResource("apps", "web",
+		map[string]string{"confighub.com/SpaceName": "label-space"},
+		map[string]string{"confighub.com/SpaceName": "annotation-space"})
+	if got := ConfigHubSpace(both); got != "annotation-space" {
+		t.Errorf("ConfigHubSpace() = %q, want the annotation to win", got)
+	}
+	if got := ConfigHubSpace(newTestResource("apps", "web", nil, nil)); got != "" {
+		t.Errorf("ConfigHubSpace() = %q, want empty", got)
+	}
+}
+
 func TestDetectOwnership_Crossplane(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -756,6 +791,63 @@ func TestDetectOwnership_Priority(t *testing.T) {
 	})
 }
 
+func TestDetectContestedOwnership(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		wantTypes   []string
+	}{
+		{
+			name: "Argo and Flux labels",
+			labels: map[string]string{
+				"argocd.argoproj.io/instance":      "my-app",
+				"kustomize.toolkit.fluxcd.io/name": "apps",
+			},
+			wantTypes: []string{OwnerFlux, OwnerArgo},
+		},
+		{
+			name:        "ConfigHub unit still synced by Argo",
+			labels:      map[string]string{"confighub.com/UnitSlug": "my-app"},
+			annotations: map[string]string{"argocd.argoproj.io/tracking-id": "my-app:apps/Deployment:prod/my-app"},
+			wantTypes:   []string{OwnerArgo, OwnerConfigHub},
+		},
+		{
+			name:   "Flux HelmRelease renders Helm labels",
+			labels: map[string]string{"helm.toolkit.fluxcd.io/name": "redis", "app.kubernetes.io/managed-by": "Helm"},
+		},
+		{
+			name:   "Argo Helm chart",
+			labels: map[string]string{"argocd.argoproj.io/instance": "redis", "helm.sh/chart": "redis-17.0.0"},
+		},
+		{
+			name:   "Flux applies a Crossplane claim",
+			labels: map[string]string{"kustomize.toolkit.fluxcd.io/name": "infra", "crossplane.io/claim-name": "db"},
+		},
+		{
+			name:   "single manager",
+			labels: map[string]string{"confighub.com/UnitSlug": "my-app"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := DetectContestedOwnership(newTestResource("prod", "my-app", tt.labels, tt.annotations))
+			var got []string
+			for _, c := range claims {
+				got = append(got, c.Type)
+			}
+			if len(got) != len(tt.wantTypes) {
+				t.Fatalf("claims = %v, want %v", got, tt.wantTypes)
+			}
+			for i := range got {
+				if got[i] != tt.wantTypes[i] {
+					t.Errorf("claims = %v, want %v", got, tt.wantTypes)
+				}
+			}
+		})
+	}
+}
+
 // Benchmark tests for ownership detection
 func BenchmarkDetectOwnership_Flux(b *testing.B) {
 	resource := newTestResource("test-ns", "test", map[string]string{
@@ -780,3 +872,16 @@ func BenchmarkDetectOwnership_Unknown(b *testing.B) {
 		DetectOwnership(resource)
 	}
 }
+
+// BenchmarkDetectOwnership_Cluster10k detects the owner of every object in
+// a synthetic 10k-object cluster; see test/bench-budget.txt.
+func BenchmarkDetectOwnership_Cluster10k(b *testing.B) {
+	objs := agenttest.Cluster(10000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, obj := range objs {
+			DetectOwnership(obj)
+		}
+	}
+}
@@ -16,12 +16,13 @@ import (
 
 // ReverseTracer walks ownerReferences to find the GitOps source
 type ReverseTracer struct {
-	client dynamic.Interface
+	client   dynamic.Interface
+	resolver *OwnerResolver
 }
 
 // NewReverseTracer creates a new reverse tracer
 func NewReverseTracer(client dynamic.Interface) *ReverseTracer {
-	return &ReverseTracer{client: client}
+	return &ReverseTracer{client: client, resolver: NewOwnerResolver(ClientOwnerLookup(client))}
 }
 
 // ReverseTraceResult contains the full chain from resource to Git source
@@ -36,16 +37,24 @@ type ReverseTraceResult struct {
 	// K8sChain is the Kubernetes ownership chain (Pod → ReplicaSet → Deployment)
 	K8sChain []ChainLink `json:"k8sChain"`
 
+	// OwnerChain is the resolved ownerReferences chain behind K8sChain,
+	// noting cycles and owners that could not be fetched
+	OwnerChain *OwnerChain `json:"ownerChain,omitempty"`
+
 	// GitOpsChain is the GitOps chain (Deployment → Kustomization → GitRepository)
 	// This is populated by calling the appropriate tool tracer
 	GitOpsChain []ChainLink `json:"gitOpsChain,omitempty"`
 
 	// Owner indicates the detected owner type
-	Owner string `json:"owner"` // "flux", "argo", "helm", "confighub", "native"
+	Owner string `json:"owner"` // "flux", "argo", "helm", "confighub", "terraform", "crossplane", "native"
 
 	// OwnerDetails contains additional ownership info
 	OwnerDetails *Ownership `json:"ownerDetails,omitempty"`
 
+	// Crossplane is the managed resource → composite → claim lineage, for
+	// Crossplane-composed resources
+	Crossplane *CrossplaneLineage `json:"crossplane,omitempty"`
+
 	// TopResource is the top of the K8s ownership chain
 	TopResource *ResourceRef `json:"topResource,omitempty"`
 
@@ -100,65 +109,21 @@ func (r *ReverseTracer) Trace(ctx context.Context, kind, name, namespace string)
 		return result, nil
 	}
 
-	// Keep the starting object for local analysis
-	result.Objects = append(result.Objects, resource)
-
-	// Add starting resource to chain
-	result.K8sChain = append(result.K8sChain, r.resourceToChainLink(resource))
-
-	// Walk ownerReferences
-	current := resource
-	for {
-		owners := current.GetOwnerReferences()
-		if len(owners) == 0 {
-			break
-		}
-
-		// Get the controller owner (or first owner)
-		var owner metav1.OwnerReference
-		for _, o := range owners {
-			if o.Controller != nil && *o.Controller {
-				owner = o
-				break
-			}
-		}
-		if owner.Name == "" && len(owners) > 0 {
-			owner = owners[0]
-		}
-
-		// Fetch the owner resource
-		ownerGVR, err := APIVersionKindToGVR(owner.APIVersion, owner.Kind)
-		if err != nil {
-			// Can't resolve owner, stop here
-			break
-		}
-
-		ownerResource, err := r.client.Resource(ownerGVR).Namespace(namespace).Get(ctx, owner.Name, metav1.GetOptions{})
-		if err != nil {
-			// Can't fetch owner, stop here
-			break
-		}
-
-		// Keep the fetched owner object for local analysis
-		result.Objects = append(result.Objects, ownerResource)
-
-		result.K8sChain = append(result.K8sChain, r.resourceToChainLink(ownerResource))
-		current = ownerResource
+	// Walk ownerReferences to the top-level controller, keeping the
+	// fetched objects for local analysis
+	objs, _ := r.resolver.Resolve(ctx, resource)
+	ownership, chain := r.resolver.DetectOwnership(ctx, resource)
+	result.Objects = append(result.Objects, objs...)
+	for _, o := range objs {
+		result.K8sChain = append(result.K8sChain, r.resourceToChainLink(o))
 	}
+	result.OwnerChain = &chain
 
 	// The top of the chain is the last item
-	if len(result.K8sChain) > 0 {
-		top := result.K8sChain[len(result.K8sChain)-1]
-		result.TopResource = &ResourceRef{
-			Kind:      top.Kind,
-			Name:      top.Name,
-			Namespace: top.Namespace,
-		}
-	}
+	top := chain.Top()
+	result.TopResource = &top
 
-	// Detect ownership of the top resource
-	topResource := current
-	ownership := DetectOwnership(topResource)
+	topResource := objs[len(objs)-1]
 	result.OwnerDetails = &ownership
 
 	switch ownership.Type {
@@ -172,6 +137,9 @@ func (r *ReverseTracer) Trace(ctx context.Context, kind, name, namespace string)
 		result.Owner = "confighub"
 	case OwnerTerraform:
 		result.Owner = "terraform"
+	case OwnerCrossplane:
+		result.Owner = "crossplane"
+		result.Crossplane, _ = NewCrossplaneTracer(r.client).Lineage(ctx, resource)
 	default:
 		result.Owner = "native"
 		// Populate orphan metadata for native resources
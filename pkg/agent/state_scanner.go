@@ -47,7 +47,7 @@ type StuckFinding struct {
 
 // StateScanResult contains findings from state scanning
 type StateScanResult struct {
-	ScannedAt time.Time        `json:"scannedAt"`
+	ScannedAt time.Time        `json:"scannedAt,omitzero"`
 	Findings  []StuckFinding   `json:"findings"`
 	Summary   StateScanSummary `json:"summary"`
 	Warnings  []string         `json:"warnings,omitempty"`
@@ -59,7 +59,13 @@ type StateScanSummary struct {
 	KustomizationStuck int `json:"kustomizationStuck"`
 	ApplicationStuck   int `json:"applicationStuck"`
 	SilentFailures     int `json:"silentFailures"`
-	Total              int `json:"total"`
+	PolicyRisks        int `json:"policyRisks"`
+	// AlertingGaps counts Flux deployers and Argo CD Applications that no
+	// Alert or notification subscription covers but that are not reported
+	// as silent failures: healthy now, or already reported stuck. They are
+	// coverage gaps, not findings.
+	AlertingGaps int `json:"alertingGaps"`
+	Total        int `json:"total"`
 }
 
 // formatScanWarning creates a descriptive warning for scan errors
@@ -120,11 +126,27 @@ func (s *StateScanner) ScanWithThreshold(ctx context.Context, threshold time.Dur
 	result.Findings = append(result.Findings, argoFindings...)
 	result.Summary.ApplicationStuck = len(argoFindings)
 
+	// Deployers failing unnoticed are reported once: stuck ones above
+	stuck := findingResources(result.Findings)
+
 	// Scan for silent failures (Ready=True but misconfigured)
 	silentFindings := s.scanSilentFailures(ctx, &warnings)
 	result.Findings = append(result.Findings, silentFindings...)
 	result.Summary.SilentFailures = len(silentFindings)
 
+	// Scan for deployers failing with no Alert to forward it
+	unalerted, fluxGaps := s.scanUnalertedFailures(ctx, "", stuck, &warnings)
+	result.Findings = append(result.Findings, unalerted...)
+	result.Summary.SilentFailures += len(unalerted)
+
+	// Audit Argo CD sync policies and notification subscriptions
+	policyFindings, unnotified, argoGaps := s.scanArgoPolicies(ctx, "", stuck, &warnings)
+	result.Findings = append(result.Findings, policyFindings...)
+	result.Summary.PolicyRisks = len(policyFindings)
+	result.Findings = append(result.Findings, unnotified...)
+	result.Summary.SilentFailures += len(unnotified)
+	result.Summary.AlertingGaps = fluxGaps + argoGaps
+
 	result.Summary.Total = len(result.Findings)
 	result.Warnings = warnings
 	return result, nil
@@ -158,11 +180,27 @@ func (s *StateScanner) ScanNamespaceWithThreshold(ctx context.Context, namespace
 	result.Findings = append(result.Findings, argoFindings...)
 	result.Summary.ApplicationStuck = len(argoFindings)
 
+	// Deployers failing unnoticed are reported once: stuck ones above
+	stuck := findingResources(result.Findings)
+
 	// Scan for silent failures in namespace
 	silentFindings := s.scanSilentFailuresNamespace(ctx, namespace, &warnings)
 	result.Findings = append(result.Findings, silentFindings...)
 	result.Summary.SilentFailures = len(silentFindings)
 
+	// Scan for deployers failing with no Alert to forward it
+	unalerted, fluxGaps := s.scanUnalertedFailures(ctx, namespace, stuck, &warnings)
+	result.Findings = append(result.Findings, unalerted...)
+	result.Summary.SilentFailures += len(unalerted)
+
+	// Audit Argo CD sync policies and notification subscriptions
+	policyFindings, unnotified, argoGaps := s.scanArgoPolicies(ctx, namespace, stuck, &warnings)
+	result.Findings = append(result.Findings, policyFindings...)
+	result.Summary.PolicyRisks = len(policyFindings)
+	result.Findings = append(result.Findings, unnotified...)
+	result.Summary.SilentFailures += len(unnotified)
+	result.Summary.AlertingGaps = fluxGaps + argoGaps
+
 	result.Summary.Total = len(result.Findings)
 	result.Warnings = warnings
 	return result, nil
@@ -791,6 +829,115 @@ func (s *StateScanner) scanSilentFailures(ctx context.Context, warnings *[]strin
 	return findings
 }
 
+// findingResources returns the resources findings are about, keyed by
+// resourceKey.
+func findingResources(findings []StuckFinding) map[string]bool {
+	keys := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		keys[resourceKey(f.Kind, f.Namespace, f.Name)] = true
+	}
+	return keys
+}
+
+func resourceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// scanUnalertedFailures finds Kustomizations and HelmReleases failing now
+// whose reconciliation failures no delivering Flux Alert forwards
+// (CCVE-2025-0694). Deployers in stuck were reported already. The other
+// unalerted ones, healthy or stuck, are only counted, as gaps.
+func (s *StateScanner) scanUnalertedFailures(ctx context.Context, namespace string, stuck map[string]bool, warnings *[]string) (findings []StuckFinding, gaps int) {
+	alerting, err := ListFluxAlerting(ctx, s.client, namespace)
+	if err != nil {
+		*warnings = append(*warnings, err.Error())
+		return nil, 0
+	}
+
+	for _, c := range alerting.Silent() {
+		if c.Ready || stuck[resourceKey(c.Kind, c.Namespace, c.Name)] {
+			gaps++
+			continue
+		}
+		message := "No Flux Alert forwards this deployer's events; it is failing and nobody was notified"
+		remediation := fmt.Sprintf("Add an Alert in %s with eventSources {kind: %s, name: '*'} and eventSeverity: error", c.Namespace, c.Kind)
+		if !alerting.Installed {
+			message = "notification-controller is not installed; reconciliation failures are silent"
+			remediation = "Install the Flux notification-controller and add an Alert for this namespace"
+		}
+		findings = append(findings, StuckFinding{
+			CCVEID:      "CCVE-2025-0694",
+			Category:    "SILENT",
+			Severity:    "warning",
+			Kind:        c.Kind,
+			Name:        c.Name,
+			Namespace:   c.Namespace,
+			Condition:   "no Alert",
+			Reason:      "FailuresNotAlerted",
+			Message:     message,
+			Remediation: remediation,
+			Command:     "kubectl get alerts.notification.toolkit.fluxcd.io -A",
+		})
+	}
+	return findings, gaps
+}
+
+// scanArgoPolicies audits Argo CD Applications: risky sync policies
+// (CCVE-2025-0695 to 0697) and Applications out of sync or unhealthy now
+// that no notification subscription covers (CCVE-2025-0698), which fail
+// silently. Applications in stuck were reported already; they and healthy
+// unnotified Applications are only counted, as gaps.
+func (s *StateScanner) scanArgoPolicies(ctx context.Context, namespace string, stuck map[string]bool, warnings *[]string) (policy, unnotified []StuckFinding, gaps int) {
+	audit, err := AuditArgoPolicies(ctx, s.client, namespace)
+	if err != nil {
+		*warnings = append(*warnings, err.Error())
+		return nil, nil, 0
+	}
+
+	for _, app := range audit.Apps {
+		for _, r := range app.Risks {
+			policy = append(policy, StuckFinding{
+				CCVEID:      r.CCVEID,
+				Category:    "CONFIG",
+				Severity:    r.Severity,
+				Kind:        "Application",
+				Name:        app.Name,
+				Namespace:   app.Namespace,
+				Condition:   "syncPolicy=" + app.Sync.String(),
+				Reason:      r.Reason,
+				Message:     r.Message,
+				Remediation: r.Remediation,
+				Command:     fmt.Sprintf("kubectl get application %s -n %s -o jsonpath='{.spec.syncPolicy}'", app.Name, app.Namespace),
+			})
+		}
+	}
+
+	for _, app := range audit.Unnotified() {
+		if app.Healthy || stuck[resourceKey("Application", app.Namespace, app.Name)] {
+			gaps++
+			continue
+		}
+		message := "No notification subscription covers this Application; sync failures and degraded health are silent"
+		if !audit.NotificationsInstalled {
+			message = "Argo CD notifications are not configured (no " + ArgoNotificationsConfigMap + "); sync failures and degraded health are silent"
+		}
+		unnotified = append(unnotified, StuckFinding{
+			CCVEID:      "CCVE-2025-0698",
+			Category:    "SILENT",
+			Severity:    "warning",
+			Kind:        "Application",
+			Name:        app.Name,
+			Namespace:   app.Namespace,
+			Condition:   "no subscription",
+			Reason:      "NotificationsNotSubscribed",
+			Message:     message,
+			Remediation: "Annotate the Application or its AppProject with " + ArgoSubscribeAnnotationPrefix + "on-sync-failed.<service>, or add a default subscription to " + ArgoNotificationsConfigMap,
+			Command:     fmt.Sprintf("kubectl get configmap %s -n %s -o yaml", ArgoNotificationsConfigMap, app.Namespace),
+		})
+	}
+	return policy, unnotified, gaps
+}
+
 // scanHelmReleaseSilentFailures checks HelmReleases for silent misconfigurations
 func (s *StateScanner) scanHelmReleaseSilentFailures(ctx context.Context, warnings *[]string) []StuckFinding {
 	var findings []StuckFinding
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -815,6 +816,11 @@ func newFakeDynamicClientForScan(objects ...runtime.Object) *dynamicfake.FakeDyn
 		{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}:        "HelmReleaseList",
 		{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}: "KustomizationList",
 		{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}:             "ApplicationList",
+		{Group: "argoproj.io", Version: "v1alpha1", Resource: "appprojects"}:              "AppProjectList",
+		// Flux notification CRDs, for alert coverage
+		{Group: "notification.toolkit.fluxcd.io", Version: "v1beta3", Resource: "alerts"}:    "AlertList",
+		{Group: "notification.toolkit.fluxcd.io", Version: "v1beta3", Resource: "providers"}: "ProviderList",
+		{Group: "notification.toolkit.fluxcd.io", Version: "v1", Resource: "receivers"}:      "ReceiverList",
 		// Core resources
 		{Group: "", Version: "v1", Resource: "services"}:   "ServiceList",
 		{Group: "", Version: "v1", Resource: "pods"}:       "PodList",
@@ -993,6 +999,7 @@ func TestScanContractSummaryConsistency(t *testing.T) {
 	kustomizeCount := 0
 	argoCount := 0
 	silentCount := 0
+	policyCount := 0
 
 	for _, f := range result.Findings {
 		switch f.Kind {
@@ -1009,7 +1016,14 @@ func TestScanContractSummaryConsistency(t *testing.T) {
 				silentCount++
 			}
 		case "Application":
-			argoCount++
+			switch f.Category {
+			case "STATE":
+				argoCount++
+			case "SILENT":
+				silentCount++
+			case "CONFIG":
+				policyCount++
+			}
 		}
 	}
 
@@ -1021,12 +1035,16 @@ func TestScanContractSummaryConsistency(t *testing.T) {
 		"ApplicationStuck count must match actual stuck Application findings")
 	assert.Equal(t, silentCount, result.Summary.SilentFailures,
 		"SilentFailures count must match actual silent failure findings")
+	assert.Equal(t, policyCount, result.Summary.PolicyRisks,
+		"PolicyRisks count must match actual sync policy findings")
 
 	// Verify we got the expected number of findings
 	// 2 stuck HelmReleases + 1 stuck Kustomization = 3 findings
 	assert.Equal(t, 3, result.Summary.Total, "Expected 3 stuck resources")
 	assert.Equal(t, 2, result.Summary.HelmReleaseStuck, "Expected 2 stuck HelmReleases")
 	assert.Equal(t, 1, result.Summary.KustomizationStuck, "Expected 1 stuck Kustomization")
+	// No Alert covers them, but being stuck they are reported once, as stuck
+	assert.Equal(t, 3, result.Summary.AlertingGaps, "Expected the stuck deployers counted as alerting gaps")
 }
 
 // TestScanDanglingResourcesEmpty tests scanning with no resources
@@ -1235,76 +1253,10 @@ func loadFixture(t *testing.T, filename string) *unstructured.Unstructured {
 	return obj
 }
 
-// createFakeClient creates a fake dynamic client with the given objects.
-// The client is configured with custom list kinds for all resource types
-// that the scanner may list during scanning.
+// createFakeClient creates a fake dynamic client with the given objects,
+// able to list every resource the scanner may list.
 func createFakeClient(objs ...*unstructured.Unstructured) *dynamicfake.FakeDynamicClient {
-	scheme := runtime.NewScheme()
-	runtimeObjs := make([]runtime.Object, len(objs))
-	for i, obj := range objs {
-		runtimeObjs[i] = obj
-	}
-
-	// Register list kinds for all resources the scanner may list
-	gvrToListKind := map[schema.GroupVersionResource]string{
-		// Core resources
-		{Group: "", Version: "v1", Resource: "services"}:               "ServiceList",
-		{Group: "", Version: "v1", Resource: "pods"}:                   "PodList",
-		{Group: "", Version: "v1", Resource: "secrets"}:                "SecretList",
-		{Group: "", Version: "v1", Resource: "configmaps"}:             "ConfigMapList",
-		{Group: "", Version: "v1", Resource: "namespaces"}:             "NamespaceList",
-		{Group: "", Version: "v1", Resource: "persistentvolumeclaims"}: "PersistentVolumeClaimList",
-		{Group: "", Version: "v1", Resource: "replicationcontrollers"}: "ReplicationControllerList",
-		{Group: "", Version: "v1", Resource: "serviceaccounts"}:        "ServiceAccountList",
-
-		// Apps resources
-		{Group: "apps", Version: "v1", Resource: "deployments"}:  "DeploymentList",
-		{Group: "apps", Version: "v1", Resource: "replicasets"}:  "ReplicaSetList",
-		{Group: "apps", Version: "v1", Resource: "statefulsets"}: "StatefulSetList",
-		{Group: "apps", Version: "v1", Resource: "daemonsets"}:   "DaemonSetList",
-
-		// Autoscaling
-		{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}:      "HorizontalPodAutoscalerList",
-		{Group: "autoscaling", Version: "v1", Resource: "horizontalpodautoscalers"}:      "HorizontalPodAutoscalerList",
-		{Group: "autoscaling.k8s.io", Version: "v1", Resource: "verticalpodautoscalers"}: "VerticalPodAutoscalerList",
-
-		// Networking
-		{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}:       "IngressList",
-		{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}: "NetworkPolicyList",
-
-		// Policy
-		{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets"}: "PodDisruptionBudgetList",
-
-		// Batch
-		{Group: "batch", Version: "v1", Resource: "jobs"}:     "JobList",
-		{Group: "batch", Version: "v1", Resource: "cronjobs"}: "CronJobList",
-
-		// RBAC
-		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}:               "RoleList",
-		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}:        "RoleBindingList",
-		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}:        "ClusterRoleList",
-		{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}: "ClusterRoleBindingList",
-
-		// Flux resources
-		{Group: "helm.toolkit.fluxcd.io", Version: "v2", Resource: "helmreleases"}:            "HelmReleaseList",
-		{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}:     "KustomizationList",
-		{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "gitrepositories"}:       "GitRepositoryList",
-		{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "gitrepositories"}:  "GitRepositoryList",
-		{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "helmrepositories"}:      "HelmRepositoryList",
-		{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "helmrepositories"}: "HelmRepositoryList",
-		{Group: "source.toolkit.fluxcd.io", Version: "v1", Resource: "helmcharts"}:            "HelmChartList",
-		{Group: "source.toolkit.fluxcd.io", Version: "v1beta2", Resource: "helmcharts"}:       "HelmChartList",
-
-		// Argo CD resources
-		{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}:    "ApplicationList",
-		{Group: "argoproj.io", Version: "v1alpha1", Resource: "applicationsets"}: "ApplicationSetList",
-
-		// Certificates
-		{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}: "CertificateList",
-		{Group: "cert-manager.io", Version: "v1", Resource: "issuers"}:      "IssuerList",
-	}
-
-	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, gvrToListKind, runtimeObjs...)
+	return agenttest.FakeClient(objs...)
 }
 
 // TestDanglingHPA tests detection of HPAs targeting non-existent deployments
@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -32,6 +33,7 @@ type StaticFinding struct {
 	Kind         string `json:"kind"`
 	ResourceName string `json:"resource_name"`
 	Namespace    string `json:"namespace,omitempty"`
+	File         string `json:"file,omitempty"`
 	Severity     string `json:"severity"`
 	Category     string `json:"category"`
 	Message      string `json:"message"`
@@ -66,20 +68,29 @@ func NewStaticScanner(ccveDBDir string) (*StaticScanner, error) {
 
 // ScanFile scans a YAML file for misconfigurations
 func (s *StaticScanner) ScanFile(ctx context.Context, filename string) (*StaticScanResult, error) {
-	result := &StaticScanResult{
-		File:      filename,
-		ScannedAt: time.Now(),
-		Findings:  []StaticFinding{},
-	}
-
 	// Read and parse YAML file
 	resources, err := s.parseYAMLFile(filename)
 	if err != nil {
-		result.Error = fmt.Sprintf("failed to parse YAML: %v", err)
-		return result, nil
+		return &StaticScanResult{
+			File:      filename,
+			ScannedAt: time.Now(),
+			Findings:  []StaticFinding{},
+			Error:     fmt.Sprintf("failed to parse YAML: %v", err),
+		}, nil
 	}
+	return s.ScanResources(filename, resources), nil
+}
 
-	result.ResourceCount = len(resources)
+// ScanResources runs the detection patterns against parsed manifests, e.g.
+// the output of a kustomize build. source names where they came from and is
+// recorded on each finding.
+func (s *StaticScanner) ScanResources(source string, resources []map[string]interface{}) *StaticScanResult {
+	result := &StaticScanResult{
+		File:          source,
+		ScannedAt:     time.Now(),
+		ResourceCount: len(resources),
+		Findings:      []StaticFinding{},
+	}
 
 	// Check each resource against patterns
 	for _, resource := range resources {
@@ -102,6 +113,7 @@ func (s *StaticScanner) ScanFile(ctx context.Context, filename string) (*StaticS
 					Kind:         kind,
 					ResourceName: name,
 					Namespace:    namespace,
+					File:         source,
 					Severity:     pattern.Severity,
 					Category:     pattern.Category,
 					Message:      message,
@@ -112,7 +124,7 @@ func (s *StaticScanner) ScanFile(ctx context.Context, filename string) (*StaticS
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 // parseYAMLFile parses a YAML file with multiple documents
@@ -122,9 +134,14 @@ func (s *StaticScanner) parseYAMLFile(filename string) ([]map[string]interface{}
 		return nil, err
 	}
 	defer file.Close()
+	return ParseManifests(file)
+}
 
+// ParseManifests decodes a multi-document YAML stream, skipping empty
+// documents.
+func ParseManifests(r io.Reader) ([]map[string]interface{}, error) {
 	var resources []map[string]interface{}
-	decoder := yaml.NewDecoder(bufio.NewReader(file))
+	decoder := yaml.NewDecoder(bufio.NewReader(r))
 
 	for {
 		var doc map[string]interface{}
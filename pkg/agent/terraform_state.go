@@ -0,0 +1,308 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// terraformKind is the Kubernetes kind a typed kubernetes provider resource
+// manages.
+type terraformKind struct {
+	kind          string
+	gvr           schema.GroupVersionResource
+	clusterScoped bool
+}
+
+// terraformKinds maps typed kubernetes provider resources, without the
+// "kubernetes_" prefix and version suffix, to the kind they create.
+// Resources that patch objects owned by something else (kubernetes_labels,
+// kubernetes_annotations, kubernetes_env, kubernetes_config_map_v1_data)
+// are left out: Terraform doesn't own those objects.
+var terraformKinds = map[string]terraformKind{
+	"deployment":                {"Deployment", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, false},
+	"stateful_set":              {"StatefulSet", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, false},
+	"daemonset":                 {"DaemonSet", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, false},
+	"daemon_set":                {"DaemonSet", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, false},
+	"service":                   {"Service", schema.GroupVersionResource{Version: "v1", Resource: "services"}, false},
+	"config_map":                {"ConfigMap", schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, false},
+	"secret":                    {"Secret", schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, false},
+	"service_account":           {"ServiceAccount", schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}, false},
+	"persistent_volume_claim":   {"PersistentVolumeClaim", schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}, false},
+	"namespace":                 {"Namespace", schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, true},
+	"ingress":                   {"Ingress", schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}, false},
+	"network_policy":            {"NetworkPolicy", schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}, false},
+	"job":                       {"Job", schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, false},
+	"cron_job":                  {"CronJob", schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, false},
+	"horizontal_pod_autoscaler": {"HorizontalPodAutoscaler", schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}, false},
+	"role":                      {"Role", schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}, false},
+	"role_binding":              {"RoleBinding", schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}, false},
+	"cluster_role":              {"ClusterRole", schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, true},
+	"cluster_role_binding":      {"ClusterRoleBinding", schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}, true},
+}
+
+// TerraformResource is a Kubernetes object recorded in Terraform state.
+type TerraformResource struct {
+	// Address is the resource's state address, e.g.
+	// module.app.kubernetes_deployment_v1.web or kubernetes_manifest.crd["a"]
+	Address string `json:"address"`
+
+	// Module is the module path, empty for the root module
+	Module string `json:"module,omitempty"`
+
+	// Type is the Terraform resource type, e.g. kubernetes_deployment_v1
+	Type string `json:"type"`
+
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// tfState is the part of a version 4 state file read here.
+type tfState struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   interface{}            `json:"index_key"`
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// ParseTerraformState reads the Kubernetes objects managed by the kubernetes
+// provider from a state file (terraform.tfstate, or `terraform state pull`
+// output): typed resources such as kubernetes_deployment_v1, and
+// kubernetes_manifest. Other providers' resources are ignored.
+func ParseTerraformState(data []byte) ([]TerraformResource, error) {
+	var state tfState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parse terraform state: %w", err)
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported terraform state version %d (want 4, Terraform 0.12+)", state.Version)
+	}
+
+	var out []TerraformResource
+	for _, r := range state.Resources {
+		if r.Mode != "managed" || !strings.HasPrefix(r.Type, "kubernetes_") {
+			continue
+		}
+		for _, inst := range r.Instances {
+			res := TerraformResource{Address: terraformAddress(r.Module, r.Type, r.Name, inst.IndexKey), Module: r.Module, Type: r.Type}
+			if r.Type == "kubernetes_manifest" {
+				// "object" is the live object after apply; "manifest" what was planned
+				obj, _ := inst.Attributes["object"].(map[string]interface{})
+				if obj == nil {
+					obj, _ = inst.Attributes["manifest"].(map[string]interface{})
+				}
+				if obj == nil {
+					continue
+				}
+				u := unstructured.Unstructured{Object: obj}
+				res.APIVersion, res.Kind, res.Namespace, res.Name = u.GetAPIVersion(), u.GetKind(), u.GetNamespace(), u.GetName()
+			} else {
+				k, ok := terraformKinds[terraformBaseType(r.Type)]
+				if !ok {
+					continue
+				}
+				res.APIVersion, res.Kind = k.gvr.GroupVersion().String(), k.kind
+				res.Namespace, res.Name = terraformMetadata(inst.Attributes)
+				if k.clusterScoped {
+					res.Namespace = ""
+				} else if res.Namespace == "" {
+					res.Namespace = "default"
+				}
+			}
+			if res.Kind == "" || res.Name == "" {
+				continue
+			}
+			out = append(out, res)
+		}
+	}
+	return out, nil
+}
+
+// terraformBaseType strips "kubernetes_" and a version suffix ("_v1",
+// "_v2beta2") from a resource type.
+func terraformBaseType(t string) string {
+	base := strings.TrimPrefix(t, "kubernetes_")
+	i := strings.LastIndex(base, "_v")
+	if i > 0 && len(base) > i+2 && base[i+2] >= '0' && base[i+2] <= '9' && !strings.Contains(base[i+1:], "_") {
+		base = base[:i]
+	}
+	return base
+}
+
+// terraformMetadata reads name and namespace from a typed resource's
+// metadata block, which state stores as a one-element list.
+func terraformMetadata(attrs map[string]interface{}) (namespace, name string) {
+	blocks, _ := attrs["metadata"].([]interface{})
+	if len(blocks) == 0 {
+		return "", ""
+	}
+	meta, _ := blocks[0].(map[string]interface{})
+	namespace, _ = meta["namespace"].(string)
+	name, _ = meta["name"].(string)
+	return namespace, name
+}
+
+// terraformAddress renders a resource instance address as Terraform does.
+func terraformAddress(module, typ, name string, key interface{}) string {
+	addr := typ + "." + name
+	if module != "" {
+		addr = module + "." + addr
+	}
+	switch k := key.(type) {
+	case string:
+		addr += fmt.Sprintf("[%q]", k)
+	case float64:
+		addr += fmt.Sprintf("[%d]", int(k))
+	}
+	return addr
+}
+
+// TerraformTarget is a kind to list from the cluster to check state
+// against.
+type TerraformTarget struct {
+	Kind string
+	GVR  schema.GroupVersionResource
+}
+
+// TerraformTargets returns the kinds to list: every kind the typed
+// kubernetes provider resources create, plus the kinds of the state's
+// kubernetes_manifest objects that map to a known resource.
+func TerraformTargets(state []TerraformResource) []TerraformTarget {
+	seen := map[string]bool{}
+	var targets []TerraformTarget
+	add := func(kind string, gvr schema.GroupVersionResource) {
+		if !seen[kind] {
+			seen[kind] = true
+			targets = append(targets, TerraformTarget{Kind: kind, GVR: gvr})
+		}
+	}
+	for _, k := range terraformKinds {
+		add(k.kind, k.gvr)
+	}
+	for _, r := range state {
+		if seen[r.Kind] {
+			continue
+		}
+		if gvr, err := APIVersionKindToGVR(r.APIVersion, r.Kind); err == nil {
+			add(r.Kind, gvr)
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Kind < targets[j].Kind })
+	return targets
+}
+
+// IsTerraformManaged reports whether a live object looks applied by
+// Terraform: Terraform Cloud annotations or labels, or a Terraform field
+// manager (the kubernetes provider applies as "Terraform").
+func IsTerraformManaged(obj *unstructured.Unstructured) bool {
+	if DetectOwnership(obj).Type == OwnerTerraform {
+		return true
+	}
+	for _, mf := range obj.GetManagedFields() {
+		if strings.Contains(strings.ToLower(mf.Manager), "terraform") {
+			return true
+		}
+	}
+	return false
+}
+
+// TerraformLink is a state resource found in the cluster.
+type TerraformLink struct {
+	TerraformResource
+
+	// Detected is false when the live object carries no Terraform markers,
+	// e.g. it was imported into state or its field manager was overwritten
+	Detected bool `json:"detected"`
+}
+
+// TerraformOrphan is a live object that looks Terraform-managed but isn't
+// in the state: applied from another state, or removed from state with
+// `terraform state rm` and left running.
+type TerraformOrphan struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// TerraformCorrelation links Terraform state to the cluster.
+type TerraformCorrelation struct {
+	// Linked are the state resources present in the cluster
+	Linked []TerraformLink `json:"linked"`
+
+	// MissingInCluster are in the state but not in the cluster: deleted out
+	// of band, or state for another cluster
+	MissingInCluster []TerraformResource `json:"missingInCluster"`
+
+	// MissingInState look Terraform-managed in the cluster but aren't in
+	// the state
+	MissingInState []TerraformOrphan `json:"missingInState"`
+
+	// Unchecked are state resources of kinds that couldn't be listed
+	Unchecked []TerraformResource `json:"unchecked,omitempty"`
+}
+
+// CorrelateTerraformState matches state resources to live objects by kind,
+// namespace and name. live must hold every object of the listed kinds;
+// state resources of other kinds are unchecked rather than missing.
+func CorrelateTerraformState(state []TerraformResource, live []unstructured.Unstructured, listed []string) TerraformCorrelation {
+	key := func(kind, ns, name string) string { return kind + "/" + ns + "/" + name }
+	listedKinds := map[string]bool{}
+	for _, kind := range listed {
+		listedKinds[kind] = true
+	}
+	liveByKey := map[string]*unstructured.Unstructured{}
+	for i := range live {
+		obj := &live[i]
+		liveByKey[key(obj.GetKind(), obj.GetNamespace(), obj.GetName())] = obj
+	}
+
+	c := TerraformCorrelation{Linked: []TerraformLink{}, MissingInCluster: []TerraformResource{}, MissingInState: []TerraformOrphan{}}
+	inState := map[string]bool{}
+	for _, r := range state {
+		k := key(r.Kind, r.Namespace, r.Name)
+		inState[k] = true
+		if obj, ok := liveByKey[k]; ok {
+			c.Linked = append(c.Linked, TerraformLink{TerraformResource: r, Detected: IsTerraformManaged(obj)})
+			continue
+		}
+		if !listedKinds[r.Kind] {
+			c.Unchecked = append(c.Unchecked, r)
+			continue
+		}
+		c.MissingInCluster = append(c.MissingInCluster, r)
+	}
+	for i := range live {
+		obj := &live[i]
+		if inState[key(obj.GetKind(), obj.GetNamespace(), obj.GetName())] || !IsTerraformManaged(obj) {
+			continue
+		}
+		c.MissingInState = append(c.MissingInState, TerraformOrphan{
+			APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(),
+		})
+	}
+
+	sort.Slice(c.Linked, func(i, j int) bool { return c.Linked[i].Address < c.Linked[j].Address })
+	sort.Slice(c.MissingInCluster, func(i, j int) bool { return c.MissingInCluster[i].Address < c.MissingInCluster[j].Address })
+	sort.Slice(c.MissingInState, func(i, j int) bool {
+		a, b := c.MissingInState[i], c.MissingInState[j]
+		return key(a.Namespace, a.Kind, a.Name) < key(b.Namespace, b.Kind, b.Name)
+	})
+	return c
+}
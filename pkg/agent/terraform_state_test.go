@@ -0,0 +1,146 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/confighub/cub-scout/pkg/agent/agenttest"
+)
+
+const terraformStateJSON = `{
+  "version": 4,
+  "terraform_version": "1.9.5",
+  "resources": [
+    {
+      "module": "module.app",
+      "mode": "managed",
+      "type": "kubernetes_deployment_v1",
+      "name": "web",
+      "instances": [{"attributes": {"metadata": [{"name": "web", "namespace": "prod"}]}}]
+    },
+    {
+      "mode": "managed",
+      "type": "kubernetes_config_map",
+      "name": "settings",
+      "instances": [
+        {"index_key": "a", "attributes": {"metadata": [{"name": "settings-a", "namespace": ""}]}},
+        {"index_key": "b", "attributes": {"metadata": [{"name": "settings-b", "namespace": ""}]}}
+      ]
+    },
+    {
+      "mode": "managed",
+      "type": "kubernetes_namespace_v1",
+      "name": "prod",
+      "instances": [{"attributes": {"metadata": [{"name": "prod"}]}}]
+    },
+    {
+      "mode": "managed",
+      "type": "kubernetes_manifest",
+      "name": "cert",
+      "instances": [{"index_key": 0, "attributes": {"object": {"apiVersion": "cert-manager.io/v1", "kind": "Certificate", "metadata": {"name": "web-tls", "namespace": "prod"}}}}]
+    },
+    {
+      "mode": "managed",
+      "type": "kubernetes_config_map_v1_data",
+      "name": "patch",
+      "instances": [{"attributes": {"metadata": [{"name": "aws-auth", "namespace": "kube-system"}]}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "instances": [{"attributes": {"bucket": "logs"}}]
+    },
+    {
+      "mode": "data",
+      "type": "kubernetes_service_v1",
+      "name": "lb",
+      "instances": [{"attributes": {"metadata": [{"name": "lb", "namespace": "prod"}]}}]
+    }
+  ]
+}`
+
+func TestParseTerraformState(t *testing.T) {
+	got, err := ParseTerraformState([]byte(terraformStateJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TerraformResource{
+		{Address: "module.app.kubernetes_deployment_v1.web", Module: "module.app", Type: "kubernetes_deployment_v1", APIVersion: "apps/v1", Kind: "Deployment", Namespace: "prod", Name: "web"},
+		{Address: `kubernetes_config_map.settings["a"]`, Type: "kubernetes_config_map", APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "settings-a"},
+		{Address: `kubernetes_config_map.settings["b"]`, Type: "kubernetes_config_map", APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "settings-b"},
+		{Address: "kubernetes_namespace_v1.prod", Type: "kubernetes_namespace_v1", APIVersion: "v1", Kind: "Namespace", Name: "prod"},
+		{Address: "kubernetes_manifest.cert[0]", Type: "kubernetes_manifest", APIVersion: "cert-manager.io/v1", Kind: "Certificate", Namespace: "prod", Name: "web-tls"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d resources, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("resource %d:\n got %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := ParseTerraformState([]byte(`{"version": 3, "modules": []}`)); err == nil {
+		t.Error("version 3 state: want error")
+	}
+}
+
+func TestCorrelateTerraformState(t *testing.T) {
+	state, err := ParseTerraformState([]byte(terraformStateJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byTerraform := func(u *unstructured.Unstructured) {
+		u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "Terraform", Operation: metav1.ManagedFieldsOperationApply}})
+	}
+	live := []unstructured.Unstructured{
+		*agenttest.Deployment("prod", "web", byTerraform),
+		*agenttest.Object("v1", "ConfigMap", "default", "settings-a"), // imported: no markers
+		*agenttest.Object("v1", "Namespace", "", "prod", byTerraform),
+		*agenttest.Deployment("prod", "worker", byTerraform),                           // state rm'd
+		*agenttest.Deployment("prod", "api", agenttest.ManagedByTerraform("platform")), // another workspace
+		*agenttest.Deployment("prod", "kubectl-made"),
+	}
+	listed := []string{"Deployment", "ConfigMap", "Namespace"} // Certificate CRD not installed
+
+	c := CorrelateTerraformState(state, live, listed)
+
+	if len(c.Linked) != 3 {
+		t.Fatalf("Linked = %+v, want 3", c.Linked)
+	}
+	for _, l := range c.Linked {
+		if wantDetected := l.Kind != "ConfigMap"; l.Detected != wantDetected {
+			t.Errorf("%s Detected = %v, want %v", l.Address, l.Detected, wantDetected)
+		}
+	}
+	if len(c.MissingInCluster) != 1 || c.MissingInCluster[0].Name != "settings-b" {
+		t.Errorf("MissingInCluster = %+v, want settings-b", c.MissingInCluster)
+	}
+	if len(c.MissingInState) != 2 || c.MissingInState[0].Name != "api" || c.MissingInState[1].Name != "worker" {
+		t.Errorf("MissingInState = %+v, want api, worker", c.MissingInState)
+	}
+	if len(c.Unchecked) != 1 || c.Unchecked[0].Kind != "Certificate" {
+		t.Errorf("Unchecked = %+v, want the Certificate", c.Unchecked)
+	}
+}
+
+func TestTerraformBaseType(t *testing.T) {
+	tests := map[string]string{
+		"kubernetes_deployment_v1":                     "deployment",
+		"kubernetes_horizontal_pod_autoscaler_v2":      "horizontal_pod_autoscaler",
+		"kubernetes_horizontal_pod_autoscaler_v2beta2": "horizontal_pod_autoscaler",
+		"kubernetes_config_map":                        "config_map",
+		"kubernetes_config_map_v1_data":                "config_map_v1_data",
+	}
+	for in, want := range tests {
+		if got := terraformBaseType(in); got != want {
+			t.Errorf("terraformBaseType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
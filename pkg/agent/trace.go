@@ -22,7 +22,7 @@ type TraceResult struct {
 	FullyManaged bool `json:"fullyManaged"`
 
 	// Tool indicates which GitOps tool manages this resource
-	Tool string `json:"tool"` // "flux", "argocd", or ""
+	Tool string `json:"tool"` // "flux", "argocd", "crossplane", or ""
 
 	// Error contains any error encountered during tracing
 	Error string `json:"error,omitempty"`
@@ -40,6 +40,25 @@ type TraceResult struct {
 	// CrossReferences contains resources referenced by this resource that have different owners
 	// For example, a Flux-managed Deployment referencing a Crossplane-created Secret
 	CrossReferences []CrossReference `json:"crossReferences,omitempty"`
+
+	// OwnerChain is the ownerReferences chain the owner was resolved
+	// through, when the resource is created by a controller (Pod → Job →
+	// CronJob)
+	OwnerChain *OwnerChain `json:"ownerChain,omitempty"`
+
+	// DependsOn is the Flux deployer's dependsOn chain, across namespaces
+	DependsOn []FluxDependency `json:"dependsOn,omitempty"`
+
+	// Delegation is set when the chain applies a ConfigHub OCI artifact:
+	// ConfigHub owns the configuration and Flux/Argo only performs the apply
+	Delegation *Delegation `json:"delegation,omitempty"`
+
+	// Crossplane is the managed resource → composite → claim lineage of a
+	// Crossplane-composed resource
+	Crossplane *CrossplaneLineage `json:"crossplane,omitempty"`
+
+	// Helm is the Helm release a Helm-installed resource belongs to
+	Helm *HelmReleaseInfo `json:"helm,omitempty"`
 }
 
 // CrossReference represents a reference to a resource with a different owner
@@ -166,6 +185,9 @@ type ChainLink struct {
 
 	// OCISource contains parsed OCI source information (for OCI-based sources)
 	OCISource *OCISourceInfo `json:"ociSource,omitempty"`
+
+	// Commit is the Git commit behind Revision, when it can be resolved
+	Commit *GitCommit `json:"commit,omitempty"`
 }
 
 // IsHealthy returns true if this chain link is in a healthy state
@@ -0,0 +1,145 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package diff renders the difference between two versions of a Kubernetes
+// object as a unified diff, side-by-side columns, or an RFC 6902 JSON patch.
+// Every diff-producing command renders through it, so --diff-format behaves
+// the same everywhere.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/confighub/cub-scout/pkg/redact"
+)
+
+// Format selects how a diff is rendered.
+type Format string
+
+const (
+	Unified    Format = "unified"
+	SideBySide Format = "side-by-side"
+	JSONPatch  Format = "jsonpatch"
+)
+
+// Formats lists the accepted --diff-format values.
+var Formats = []string{string(Unified), string(SideBySide), string(JSONPatch)}
+
+// ParseFormat parses a --diff-format value.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case Unified, "":
+		return Unified, nil
+	case SideBySide, "sidebyside":
+		return SideBySide, nil
+	case JSONPatch, "json-patch":
+		return JSONPatch, nil
+	}
+	return "", fmt.Errorf("unknown diff format %q (use %s)", s, strings.Join(Formats, ", "))
+}
+
+// DefaultWidth is the side-by-side width when the terminal's is unknown.
+const DefaultWidth = 120
+
+// Options control rendering.
+type Options struct {
+	Format Format
+	// Color adds ANSI colors: removed lines red, added lines green
+	Color bool
+	// Width is the terminal width side-by-side columns are fitted to
+	// (default DefaultWidth)
+	Width int
+	// Context is the number of unchanged lines around each unified hunk
+	// (default 3)
+	Context int
+	// Redactor masks secrets in both versions before they are diffed
+	Redactor *redact.Redactor
+}
+
+// Document is two versions of one object. A nil From or To means the object
+// is absent on that side.
+type Document struct {
+	// Name heads the diff, e.g. Deployment/prod/api
+	Name string
+	// FromLabel and ToLabel name the two sides, e.g. "local" and "live"
+	FromLabel string
+	ToLabel   string
+	From      interface{}
+	To        interface{}
+}
+
+const (
+	ansiReset = "\033[0m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiCyan  = "\033[36m"
+	ansiBold  = "\033[1m"
+)
+
+// Render writes the difference between doc.From and doc.To in opts.Format.
+// Identical versions render nothing.
+func Render(w io.Writer, doc Document, opts Options) error {
+	if opts.Width <= 0 {
+		opts.Width = DefaultWidth
+	}
+	if opts.Context <= 0 {
+		opts.Context = 3
+	}
+	doc.From, doc.To = opts.Redactor.Value(doc.From), opts.Redactor.Value(doc.To)
+	if opts.Format == JSONPatch {
+		return renderPatch(w, doc, opts)
+	}
+
+	from, err := toLines(doc.From)
+	if err != nil {
+		return fmt.Errorf("%s: %w", doc.Name, err)
+	}
+	to, err := toLines(doc.To)
+	if err != nil {
+		return fmt.Errorf("%s: %w", doc.Name, err)
+	}
+	edits := diffLines(from, to)
+	if !hasChanges(edits) {
+		return nil
+	}
+
+	switch opts.Format {
+	case SideBySide:
+		renderSideBySide(w, doc, edits, opts)
+	default:
+		renderUnified(w, doc, edits, opts)
+	}
+	return nil
+}
+
+// toLines renders v as YAML lines, keys sorted, so both sides line up.
+func toLines(v interface{}) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// paint wraps s in color when colors are on.
+func paint(s, color string, opts Options) string {
+	if !opts.Color || s == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// label returns the side's label, or def when it has none.
+func label(l, def string) string {
+	if l == "" {
+		return def
+	}
+	return l
+}
@@ -0,0 +1,149 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func deployment(replicas int, image string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "api", "namespace": "prod"},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "api", "image": image},
+					},
+				},
+			},
+		},
+	}
+}
+
+func render(t *testing.T, doc Document, opts Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Render(&buf, doc, opts); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{
+		"":             Unified,
+		"unified":      Unified,
+		"side-by-side": SideBySide,
+		"SideBySide":   SideBySide,
+		"jsonpatch":    JSONPatch,
+		"json-patch":   JSONPatch,
+	} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("context"); err == nil {
+		t.Error("ParseFormat should reject unknown formats")
+	}
+}
+
+func TestRenderUnified(t *testing.T) {
+	doc := Document{Name: "Deployment/prod/api", FromLabel: "live", ToLabel: "local",
+		From: deployment(2, "api:1.1"), To: deployment(3, "api:1.1")}
+	out := render(t, doc, Options{Format: Unified, Context: 1})
+
+	if !strings.HasPrefix(out, "--- live/Deployment/prod/api\n+++ local/Deployment/prod/api\n@@ ") {
+		t.Fatalf("missing headers:\n%s", out)
+	}
+	for _, line := range []string{"-  replicas: 2", "+  replicas: 3"} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("output missing %q:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "image: api:1.1") {
+		t.Errorf("lines outside the context should be left out:\n%s", out)
+	}
+	if strings.Contains(out, "\033[") {
+		t.Errorf("colors should be off by default:\n%s", out)
+	}
+
+	if colored := render(t, doc, Options{Format: Unified, Color: true}); !strings.Contains(colored, ansiRed+"-  replicas: 2"+ansiReset) {
+		t.Errorf("removed lines should be red:\n%q", colored)
+	}
+	if same := render(t, Document{From: deployment(2, "a"), To: deployment(2, "a")}, Options{}); same != "" {
+		t.Errorf("identical documents should render nothing, got:\n%s", same)
+	}
+}
+
+func TestRenderUnifiedAbsentSide(t *testing.T) {
+	out := render(t, Document{Name: "ConfigMap/prod/new", To: map[string]interface{}{"kind": "ConfigMap"}}, Options{})
+	if !strings.Contains(out, "@@ -0,0 +1,1 @@\n+kind: ConfigMap\n") {
+		t.Errorf("a missing object should diff against nothing:\n%s", out)
+	}
+}
+
+func TestHunksMergeOverlappingContext(t *testing.T) {
+	from := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	to := []string{"a", "B", "c", "D", "e", "f", "g", "h", "i", "J"}
+	hs := hunks(diffLines(from, to), 1)
+	if len(hs) != 2 {
+		t.Fatalf("got %d hunks, want 2 (b and d merged, j separate)", len(hs))
+	}
+	if got := hunkRange(hs[0], func(e edit) int { return e.fromLine }); got != "1,5" {
+		t.Errorf("first hunk range = %s, want 1,5", got)
+	}
+}
+
+func TestRenderSideBySide(t *testing.T) {
+	doc := Document{Name: "Deployment/prod/api", FromLabel: "live", ToLabel: "local",
+		From: deployment(2, "api:1.1"), To: deployment(2, "registry.example.com/team/api:1.2")}
+	out := render(t, doc, Options{Format: SideBySide, Width: 60})
+
+	var changed bool
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if n := utf8.RuneCountInString(line); n > 60 {
+			t.Errorf("line is %d runes, wider than 60: %q", n, line)
+		}
+		if strings.Contains(line, " | ") && strings.Contains(line, "image: api:1.1") {
+			changed = true
+		}
+	}
+	if !changed {
+		t.Errorf("the changed image should sit next to its replacement:\n%s", out)
+	}
+	if !strings.Contains(out, "…") {
+		t.Errorf("the long image should be truncated to the column:\n%s", out)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	from := deployment(2, "api:1.1")
+	to := deployment(3, "api:1.2")
+	to["metadata"].(map[string]interface{})["annotations"] = map[string]interface{}{"example.com/owner": "team"}
+	delete(to["metadata"].(map[string]interface{}), "namespace")
+
+	got, _ := json.Marshal(Patch(from, to))
+	want := `[{"op":"add","path":"/metadata/annotations","value":{"example.com/owner":"team"}},` +
+		`{"op":"remove","path":"/metadata/namespace"},` +
+		`{"op":"replace","path":"/spec/replicas","value":3},` +
+		`{"op":"replace","path":"/spec/template/spec/containers/0/image","value":"api:1.2"}]`
+	if string(got) != want {
+		t.Errorf("Patch =\n%s\nwant\n%s", got, want)
+	}
+
+	if ops := Patch(map[string]interface{}{"a/b~c": 1}, map[string]interface{}{"a/b~c": 2}); ops[0].Path != "/a~1b~0c" {
+		t.Errorf("path = %q, want the key escaped", ops[0].Path)
+	}
+	if ops := Patch(deployment(2, "a"), deployment(2, "a")); len(ops) != 0 {
+		t.Errorf("identical documents should have no ops, got %v", ops)
+	}
+}
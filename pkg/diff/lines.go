@@ -0,0 +1,255 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// editKind is what happened to one line.
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+// edit is one line of a line diff. fromLine and toLine are 1-based line
+// numbers on each side, 0 when the line isn't on that side.
+type edit struct {
+	kind     editKind
+	text     string
+	fromLine int
+	toLine   int
+}
+
+// diffLines returns the edits turning from into to, from a longest common
+// subsequence of lines. Deletions come before insertions at each change.
+func diffLines(from, to []string) []edit {
+	n, m := len(from), len(to)
+	// lcs[i][j] is the LCS length of from[i:] and to[j:]
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	edits := make([]edit, 0, n+m)
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && from[i] == to[j]:
+			edits = append(edits, edit{kind: editEqual, text: from[i], fromLine: i + 1, toLine: j + 1})
+			i++
+			j++
+		case j == m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{kind: editDelete, text: from[i], fromLine: i + 1})
+			i++
+		default:
+			edits = append(edits, edit{kind: editInsert, text: to[j], toLine: j + 1})
+			j++
+		}
+	}
+	return edits
+}
+
+func hasChanges(edits []edit) bool {
+	for _, e := range edits {
+		if e.kind != editEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// hunks groups edits into runs of changes with up to context equal lines
+// around them, merging runs whose context overlaps.
+func hunks(edits []edit, context int) [][]edit {
+	var out [][]edit
+	start, end := -1, -1
+	for i, e := range edits {
+		if e.kind == editEqual {
+			continue
+		}
+		lo, hi := max(i-context, 0), min(i+context+1, len(edits))
+		if start >= 0 && lo <= end {
+			end = hi
+			continue
+		}
+		if start >= 0 {
+			out = append(out, edits[start:end])
+		}
+		start, end = lo, hi
+	}
+	if start >= 0 {
+		out = append(out, edits[start:end])
+	}
+	return out
+}
+
+// hunkRange returns the "start,count" of a hunk on one side, as diff -u
+// writes it. Hunks carry context, so a side has no lines only when the
+// object is absent there, which diff -u writes as 0,0.
+func hunkRange(h []edit, side func(edit) int) string {
+	first, count := 0, 0
+	for _, e := range h {
+		if line := side(e); line > 0 {
+			if first == 0 {
+				first = line
+			}
+			count++
+		}
+	}
+	return fmt.Sprintf("%d,%d", first, count)
+}
+
+func renderUnified(w io.Writer, doc Document, edits []edit, opts Options) {
+	fmt.Fprintln(w, paint("--- "+label(doc.FromLabel, "a")+"/"+doc.Name, ansiBold, opts))
+	fmt.Fprintln(w, paint("+++ "+label(doc.ToLabel, "b")+"/"+doc.Name, ansiBold, opts))
+	for _, h := range hunks(edits, opts.Context) {
+		header := fmt.Sprintf("@@ -%s +%s @@",
+			hunkRange(h, func(e edit) int { return e.fromLine }),
+			hunkRange(h, func(e edit) int { return e.toLine }))
+		fmt.Fprintln(w, paint(header, ansiCyan, opts))
+		for _, e := range h {
+			switch e.kind {
+			case editDelete:
+				fmt.Fprintln(w, paint("-"+e.text, ansiRed, opts))
+			case editInsert:
+				fmt.Fprintln(w, paint("+"+e.text, ansiGreen, opts))
+			default:
+				fmt.Fprintln(w, " "+e.text)
+			}
+		}
+	}
+}
+
+// sideBySideRow is one row of side-by-side output; an empty side is blank.
+type sideBySideRow struct {
+	left, right       string
+	leftKind          editKind
+	rightKind         editKind
+	hasLeft, hasRight bool
+}
+
+// sideBySideRows pairs each run of deletions with the insertions that
+// follow it, so a changed line sits next to its replacement.
+func sideBySideRows(edits []edit) []sideBySideRow {
+	var rows []sideBySideRow
+	for i := 0; i < len(edits); {
+		if edits[i].kind == editEqual {
+			rows = append(rows, sideBySideRow{left: edits[i].text, right: edits[i].text, hasLeft: true, hasRight: true})
+			i++
+			continue
+		}
+		var dels, ins []string
+		for i < len(edits) && edits[i].kind == editDelete {
+			dels = append(dels, edits[i].text)
+			i++
+		}
+		for i < len(edits) && edits[i].kind == editInsert {
+			ins = append(ins, edits[i].text)
+			i++
+		}
+		for k := 0; k < max(len(dels), len(ins)); k++ {
+			var r sideBySideRow
+			if k < len(dels) {
+				r.left, r.leftKind, r.hasLeft = dels[k], editDelete, true
+			}
+			if k < len(ins) {
+				r.right, r.rightKind, r.hasRight = ins[k], editInsert, true
+			}
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// renderSideBySide prints the two versions in columns fitted to opts.Width,
+// with a gutter marking changed (|), removed (<) and added (>) lines.
+// Unchanged stretches are trimmed to the context lines around changes.
+func renderSideBySide(w io.Writer, doc Document, edits []edit, opts Options) {
+	col := (opts.Width - 3) / 2
+	if col < 10 {
+		col = 10
+	}
+	fmt.Fprintln(w, paint(doc.Name, ansiBold, opts))
+	fmt.Fprintf(w, "%s %s %s\n",
+		paint(fit(label(doc.FromLabel, "from"), col), ansiBold, opts), " ",
+		paint(label(doc.ToLabel, "to"), ansiBold, opts))
+
+	rows := sideBySideRows(edits)
+	keep := make([]bool, len(rows))
+	for i, r := range rows {
+		if r.leftKind != editEqual || r.rightKind != editEqual || !r.hasLeft || !r.hasRight {
+			for k := max(i-opts.Context, 0); k < min(i+opts.Context+1, len(rows)); k++ {
+				keep[k] = true
+			}
+		}
+	}
+
+	skipped := false
+	for i, r := range rows {
+		if !keep[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			fmt.Fprintln(w, paint(fit("⋯", col)+"   ⋯", ansiCyan, opts))
+			skipped = false
+		}
+		gutter := " "
+		switch {
+		case r.hasLeft && r.hasRight && r.leftKind == editEqual:
+		case r.hasLeft && r.hasRight:
+			gutter = "|"
+		case r.hasLeft:
+			gutter = "<"
+		default:
+			gutter = ">"
+		}
+		left, right := fit(r.left, col), fitRight(r.right, col)
+		if r.leftKind == editDelete {
+			left = paint(left, ansiRed, opts)
+		}
+		if r.rightKind == editInsert {
+			right = paint(right, ansiGreen, opts)
+		}
+		fmt.Fprintf(w, "%s %s %s\n", left, gutter, right)
+	}
+	if skipped {
+		fmt.Fprintln(w, paint(fit("⋯", col)+"   ⋯", ansiCyan, opts))
+	}
+}
+
+// fit pads or truncates s to exactly width runes.
+func fit(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		return string([]rune(s)[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// fitRight truncates s to width runes without padding, for the last column.
+func fitRight(s string, width int) string {
+	if utf8.RuneCountInString(s) > width {
+		return string([]rune(s)[:width-1]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,110 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOp is one RFC 6902 JSON patch operation.
+type PatchOp struct {
+	Op    string      `json:"op"` // add, remove, replace
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Patch returns the JSON patch turning from into to. Maps are patched key
+// by key; lists of the same length element by element, other lists are
+// replaced whole.
+func Patch(from, to interface{}) []PatchOp {
+	return patch("", normalize(from), normalize(to))
+}
+
+func patch(path string, from, to interface{}) []PatchOp {
+	switch {
+	case from == nil && to == nil:
+		return nil
+	case from == nil:
+		return []PatchOp{{Op: "add", Path: path, Value: to}}
+	case to == nil:
+		return []PatchOp{{Op: "remove", Path: path}}
+	}
+
+	fm, fok := from.(map[string]interface{})
+	tm, tok := to.(map[string]interface{})
+	if fok && tok {
+		keys := make([]string, 0, len(fm)+len(tm))
+		for k := range fm {
+			keys = append(keys, k)
+		}
+		for k := range tm {
+			if _, ok := fm[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var ops []PatchOp
+		for _, k := range keys {
+			ops = append(ops, patch(path+"/"+escapePointer(k), fm[k], tm[k])...)
+		}
+		return ops
+	}
+
+	fl, fok := from.([]interface{})
+	tl, tok := to.([]interface{})
+	if fok && tok && len(fl) == len(tl) {
+		var ops []PatchOp
+		for i := range fl {
+			ops = append(ops, patch(path+"/"+strconv.Itoa(i), fl[i], tl[i])...)
+		}
+		return ops
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+	return []PatchOp{{Op: "replace", Path: path, Value: to}}
+}
+
+// normalize round-trips v through JSON so numbers and nested maps compare
+// the same whichever decoder produced them.
+func normalize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// escapePointer escapes a key for a JSON pointer (RFC 6901).
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func renderPatch(w io.Writer, doc Document, opts Options) error {
+	ops := Patch(doc.From, doc.To)
+	if len(ops) == 0 {
+		return nil
+	}
+	data, err := json.MarshalIndent(ops, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%s: %w", doc.Name, err)
+	}
+	fmt.Fprintln(w, paint("# "+doc.Name+": "+label(doc.FromLabel, "from")+" → "+label(doc.ToLabel, "to"), ansiBold, opts))
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,196 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package explain provides the educational content behind --explain.
+//
+// Content lives in embedded YAML files under content/:
+// - owners.yaml: one topic per owner (Flux, ArgoCD, Helm, ..., Native)
+// - findings.yaml: one topic per finding (not-ready, orphan, ...)
+// - concepts.yaml: section intros (ownership)
+//
+// Owner topics also map ownership sources (e.g. "label:helm.sh/chart") to
+// the reason that source implies the owner, so output can say why a
+// specific resource has the owner it has.
+package explain
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed content/*.yaml
+var contentFS embed.FS
+
+// Level controls how much explanatory content is shown.
+type Level int
+
+const (
+	Off     Level = iota // no explanations
+	Brief                // one-line summaries and an example per owner
+	Verbose              // adds detail paragraphs, evidence and next steps
+)
+
+// ParseLevel parses an --explain value. A bare --explain means brief.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "", "off", "false":
+		return Off, nil
+	case "brief", "on", "true":
+		return Brief, nil
+	case "verbose":
+		return Verbose, nil
+	}
+	return Off, fmt.Errorf("invalid --explain %q (valid: brief, verbose)", s)
+}
+
+// Topic is one unit of explanatory content.
+type Topic struct {
+	ID       string            `yaml:"id"`
+	Summary  string            `yaml:"summary"`
+	Detail   string            `yaml:"detail"`
+	Evidence map[string]string `yaml:"evidence,omitempty"`
+	Next     []string          `yaml:"next,omitempty"`
+	Diagram  string            `yaml:"diagram,omitempty"`
+}
+
+// Library holds all loaded topics by section.
+type Library struct {
+	Owners   map[string]Topic
+	Findings map[string]Topic
+	Concepts map[string]Topic
+}
+
+// Load parses the embedded content files.
+func Load() (*Library, error) {
+	lib := &Library{}
+	for _, f := range []struct {
+		file string
+		dst  *map[string]Topic
+	}{
+		{"content/owners.yaml", &lib.Owners},
+		{"content/findings.yaml", &lib.Findings},
+		{"content/concepts.yaml", &lib.Concepts},
+	} {
+		topics, err := loadTopics(f.file)
+		if err != nil {
+			return nil, err
+		}
+		*f.dst = topics
+	}
+	return lib, nil
+}
+
+func loadTopics(file string) (map[string]Topic, error) {
+	data, err := contentFS.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var list []Topic
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	topics := make(map[string]Topic, len(list))
+	for _, t := range list {
+		if t.ID == "" || t.Summary == "" {
+			return nil, fmt.Errorf("parse %s: every topic needs an id and a summary", file)
+		}
+		if _, dup := topics[t.ID]; dup {
+			return nil, fmt.Errorf("parse %s: duplicate topic %q", file, t.ID)
+		}
+		topics[t.ID] = t
+	}
+	return topics, nil
+}
+
+// Why returns the reason an ownership source implies this topic's owner,
+// using the longest matching evidence prefix. Empty when nothing matches.
+func (t Topic) Why(source string) string {
+	best, reason := -1, ""
+	for prefix, r := range t.Evidence {
+		if strings.HasPrefix(source, prefix) && len(prefix) > best {
+			best, reason = len(prefix), r
+		}
+	}
+	return reason
+}
+
+// Because describes why a resource has its owner, e.g.
+// "Flux-managed because of label kustomize.toolkit.fluxcd.io/name=apps".
+// labels supplies values for label sources.
+func Because(owner, source string, labels map[string]string) string {
+	if owner == "Native" {
+		if strings.HasPrefix(source, "ownerRef") {
+			return "Native: owned by a parent resource via ownerReferences, with no GitOps labels"
+		}
+		return "Native: no ownership labels or annotations found"
+	}
+	if source == "" {
+		return owner + "-managed"
+	}
+	kind, key, _ := strings.Cut(source, ":")
+	switch kind {
+	case "label":
+		if k, v, hasValue := strings.Cut(key, "="); hasValue {
+			return fmt.Sprintf("%s-managed because of label %s=%s", owner, k, v)
+		}
+		if v, ok := labels[key]; ok && v != "" {
+			return fmt.Sprintf("%s-managed because of label %s=%s", owner, key, v)
+		}
+		return fmt.Sprintf("%s-managed because of label %s", owner, key)
+	case "annotation":
+		return fmt.Sprintf("%s-managed because of annotation %s", owner, key)
+	case "ownerRef":
+		return fmt.Sprintf("%s-managed because its owner reference is a %s resource", owner, key)
+	case "apiGroup":
+		return fmt.Sprintf("%s-managed because its API group is %s", owner, key)
+	}
+	return fmt.Sprintf("%s-managed (%s)", owner, source)
+}
+
+// Placeholders are substituted into Next steps.
+type Placeholders struct {
+	Kind, Name, Namespace, OwnerName string
+}
+
+// NextSteps returns the topic's next steps with placeholders filled in.
+// Unknown values keep their <placeholder>.
+func (t Topic) NextSteps(p Placeholders) []string {
+	r := strings.NewReplacer(
+		"<kind>", orPlaceholder(strings.ToLower(p.Kind), "<kind>"),
+		"<name>", orPlaceholder(p.Name, "<name>"),
+		"<namespace>", orPlaceholder(p.Namespace, "<namespace>"),
+		"<owner-name>", orPlaceholder(p.OwnerName, "<owner-name>"),
+	)
+	steps := make([]string, len(t.Next))
+	for i, s := range t.Next {
+		steps[i] = r.Replace(s)
+	}
+	return steps
+}
+
+func orPlaceholder(v, placeholder string) string {
+	if v == "" {
+		return placeholder
+	}
+	return v
+}
+
+// OwnerIDs returns the owner topic IDs in display order: alphabetical, with
+// Native last.
+func (l *Library) OwnerIDs() []string {
+	ids := make([]string, 0, len(l.Owners))
+	for id := range l.Owners {
+		if id != "Native" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if _, ok := l.Owners["Native"]; ok {
+		ids = append(ids, "Native")
+	}
+	return ids
+}
@@ -0,0 +1,86 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package explain
+
+import (
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	lib, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, owner := range []string{"Flux", "ArgoCD", "Helm", "Terraform", "Crossplane", "ConfigHub", "Native"} {
+		if _, ok := lib.Owners[owner]; !ok {
+			t.Errorf("missing owner topic %q", owner)
+		}
+	}
+	for _, finding := range []string{"not-ready", "failed", "pending", "orphan", "delegated"} {
+		if _, ok := lib.Findings[finding]; !ok {
+			t.Errorf("missing finding topic %q", finding)
+		}
+	}
+	if _, ok := lib.Concepts["ownership"]; !ok {
+		t.Error("missing ownership concept")
+	}
+	if ids := lib.OwnerIDs(); ids[len(ids)-1] != "Native" {
+		t.Errorf("Native should be listed last, got %v", ids)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{"": Off, "brief": Brief, "true": Brief, "verbose": Verbose, "VERBOSE": Verbose}
+	for in, want := range tests {
+		got, err := ParseLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestWhy(t *testing.T) {
+	lib, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	native := lib.Owners["Native"]
+	if why := native.Why("ownerRef:controller"); why != native.Evidence["ownerRef:controller"] {
+		t.Errorf("expected longest prefix match, got %q", why)
+	}
+	if why := native.Why(""); why != native.Evidence[""] {
+		t.Errorf("expected fallback evidence for no source, got %q", why)
+	}
+	if why := lib.Owners["Crossplane"].Why("ownerRef:example.crossplane.io/v1"); why == "" {
+		t.Error("expected prefix match for dynamic ownerRef source")
+	}
+}
+
+func TestBecause(t *testing.T) {
+	labels := map[string]string{"kustomize.toolkit.fluxcd.io/name": "apps"}
+	tests := []struct {
+		owner, source, want string
+	}{
+		{"Flux", "label:kustomize.toolkit.fluxcd.io/name", "Flux-managed because of label kustomize.toolkit.fluxcd.io/name=apps"},
+		{"Helm", "label:app.kubernetes.io/managed-by=Helm", "Helm-managed because of label app.kubernetes.io/managed-by=Helm"},
+		{"ArgoCD", "annotation:argocd.argoproj.io/tracking-id", "ArgoCD-managed because of annotation argocd.argoproj.io/tracking-id"},
+		{"Native", "", "Native: no ownership labels or annotations found"},
+	}
+	for _, tt := range tests {
+		if got := Because(tt.owner, tt.source, labels); got != tt.want {
+			t.Errorf("Because(%q, %q) = %q, want %q", tt.owner, tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestNextSteps(t *testing.T) {
+	topic := Topic{Next: []string{"cub-scout trace <kind>/<name> -n <namespace>", "argocd app get <owner-name>"}}
+	got := topic.NextSteps(Placeholders{Kind: "Deployment", Name: "api", Namespace: "prod"})
+	if got[0] != "cub-scout trace deployment/api -n prod" || got[1] != "argocd app get <owner-name>" {
+		t.Errorf("NextSteps = %v", got)
+	}
+}
@@ -0,0 +1,693 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package gitops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// KustomizeFinding is an overlay hygiene problem found without building
+// the kustomization.
+type KustomizeFinding struct {
+	CCVEID   string `json:"ccve_id"`
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Category string `json:"category"`
+
+	// Dir is the kustomization directory, relative to the linted root
+	Dir string `json:"dir"`
+
+	// Kind, ResourceName and Namespace name the resource concerned; Kind is
+	// Kustomization when the finding is about the kustomization itself
+	Kind         string `json:"kind"`
+	ResourceName string `json:"resource_name"`
+	Namespace    string `json:"namespace,omitempty"`
+
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// KustomizeLintResult is the result of LintKustomizations.
+type KustomizeLintResult struct {
+	// Kustomizations counts the kustomizations under the root
+	Kustomizations int `json:"kustomizations"`
+
+	// Overlays are the kustomizations no other kustomization includes: what
+	// Flux or Argo CD points at
+	Overlays []string `json:"overlays"`
+
+	// Resources counts the resources the overlays render
+	Resources int `json:"resources"`
+
+	Findings []KustomizeFinding `json:"findings"`
+
+	// Errors are kustomizations that could not be read, e.g. a missing
+	// resource
+	Errors []string `json:"errors,omitempty"`
+}
+
+// kustomizeFiles are the file names kustomize reads, in its order.
+var kustomizeFiles = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// clusterScopedKinds are the common kinds the namespace transformer leaves
+// alone.
+var clusterScopedKinds = map[string]bool{
+	"Namespace": true, "CustomResourceDefinition": true, "ClusterRole": true, "ClusterRoleBinding": true,
+	"StorageClass": true, "PriorityClass": true, "PersistentVolume": true, "IngressClass": true,
+	"ValidatingWebhookConfiguration": true, "MutatingWebhookConfiguration": true, "APIService": true,
+	"ClusterIssuer": true, "ClusterPolicy": true, "RuntimeClass": true, "CSIDriver": true,
+}
+
+// floatingRefs are refs that move: building twice can give different
+// resources.
+var floatingRefs = map[string]bool{"": true, "main": true, "master": true, "HEAD": true, "develop": true, "trunk": true, "latest": true}
+
+// pinnedSegment matches a commit SHA or a version in a remote file URL.
+var pinnedSegment = regexp.MustCompile(`^([0-9a-f]{7,40}|v?\d+(\.\d+)+([-+].*)?)$`)
+
+// kustomizeResource is a resource a kustomization renders, as far as it can
+// be known without running kustomize.
+type kustomizeResource struct {
+	Kind, Name, Namespace string
+
+	// OrigName is the name before any namePrefix or nameSuffix; patches
+	// may target either
+	OrigName string
+
+	Labels, Annotations map[string]string
+}
+
+func (r kustomizeResource) id() string {
+	if r.Namespace == "" {
+		return r.Kind + "/" + r.Name
+	}
+	return r.Kind + "/" + r.Namespace + "/" + r.Name
+}
+
+// kustomizeNode is a kustomization read from disk with the resources it
+// renders.
+type kustomizeNode struct {
+	dir       string
+	k         *Kustomization
+	resources []kustomizeResource
+
+	// bases are the local kustomizations it includes, transitively
+	bases map[string]bool
+
+	// partial is set when a resource or base could not be read, so the
+	// resources are incomplete
+	partial  bool
+	err      error
+	visiting bool
+}
+
+type kustomizeLinter struct {
+	root     string
+	nodes    map[string]*kustomizeNode
+	result   KustomizeLintResult
+	reported map[string]bool
+}
+
+// LintKustomizations checks every kustomization under root for overlay
+// hygiene: overlays rendering namespaced resources without a namespace
+// (CCVE-2025-0699), resource IDs registered twice in one build or rendered
+// by several overlays of the same base (CCVE-2025-0700), patches that match
+// no resource or are empty (CCVE-2025-0701), and remote bases and Helm
+// charts not pinned to a version (CCVE-2025-0702). Resources are resolved
+// statically: namespace, namePrefix and nameSuffix are applied, other
+// transformers and generators are not.
+func LintKustomizations(root string) (*KustomizeLintResult, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if kustomizationFile(path) != "" {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l := &kustomizeLinter{root: root, nodes: map[string]*kustomizeNode{}, reported: map[string]bool{}}
+	l.result.Findings = []KustomizeFinding{}
+	for _, dir := range dirs {
+		_, _ = l.build(dir)
+	}
+
+	included := map[string]bool{}
+	for _, n := range l.nodes {
+		for b := range n.bases {
+			included[b] = true
+		}
+	}
+	var overlays []*kustomizeNode
+	for _, dir := range dirs {
+		n := l.nodes[dir]
+		if n.err != nil {
+			continue
+		}
+		l.result.Kustomizations++
+		if !included[dir] && n.k.Kind != "Component" {
+			overlays = append(overlays, n)
+			l.result.Overlays = append(l.result.Overlays, l.rel(dir))
+		}
+	}
+	for _, n := range overlays {
+		l.result.Resources += len(n.resources)
+		l.checkNamespaces(n)
+	}
+	l.checkSharedIDs(overlays)
+
+	sort.SliceStable(l.result.Findings, func(i, j int) bool {
+		return l.result.Findings[i].Dir < l.result.Findings[j].Dir
+	})
+	sort.Strings(l.result.Errors)
+	return &l.result, nil
+}
+
+// kustomizationFile returns the kustomization file in dir, or "".
+func kustomizationFile(dir string) string {
+	for _, name := range kustomizeFiles {
+		if path := filepath.Join(dir, name); fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+func (l *kustomizeLinter) rel(path string) string {
+	if rel, err := filepath.Rel(l.root, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func (l *kustomizeLinter) add(f KustomizeFinding) {
+	key := f.CCVEID + " " + f.Dir + " " + f.Kind + " " + f.Namespace + " " + f.ResourceName
+	if l.reported[key] {
+		return
+	}
+	l.reported[key] = true
+	l.result.Findings = append(l.result.Findings, f)
+}
+
+func (l *kustomizeLinter) fail(n *kustomizeNode, err error) {
+	key := n.dir + ": " + err.Error()
+	if !l.reported[key] {
+		l.reported[key] = true
+		l.result.Errors = append(l.result.Errors, l.rel(n.dir)+": "+err.Error())
+	}
+}
+
+// build reads the kustomization in dir and resolves its resources, once.
+func (l *kustomizeLinter) build(dir string) (*kustomizeNode, error) {
+	if n, ok := l.nodes[dir]; ok {
+		if n.visiting {
+			return nil, fmt.Errorf("kustomization %s includes itself", l.rel(dir))
+		}
+		return n, n.err
+	}
+	n := &kustomizeNode{dir: dir, bases: map[string]bool{}, visiting: true}
+	l.nodes[dir] = n
+	defer func() { n.visiting = false }()
+
+	k, err := parseKustomization(kustomizationFile(dir))
+	if err != nil {
+		n.err = fmt.Errorf("read kustomization: %w", err)
+		l.fail(n, n.err)
+		return n, n.err
+	}
+	n.k = k
+
+	origin := map[string]string{}
+	add := func(r kustomizeResource, from string) {
+		if prev, dup := origin[r.id()]; dup {
+			l.add(KustomizeFinding{
+				CCVEID: "CCVE-2025-0700", Name: "Duplicate resource ID", Severity: "critical", Category: "RENDER",
+				Dir: l.rel(dir), Kind: r.Kind, ResourceName: r.Name, Namespace: r.Namespace,
+				Message:     fmt.Sprintf("%s is rendered by both %s and %s; kustomize build fails", r.id(), prev, from),
+				Remediation: "Include the resource from one place only, or give one copy a different name or namespace",
+			})
+			return
+		}
+		origin[r.id()] = from
+		n.resources = append(n.resources, r)
+	}
+
+	entries := append(append(append([]string{}, k.Resources...), k.Bases...), k.Components...)
+	for _, entry := range entries {
+		if isRemoteResource(entry) {
+			l.checkRemote(n, entry)
+			continue
+		}
+		path := filepath.Join(dir, entry)
+		info, err := os.Stat(path)
+		if err != nil {
+			n.partial = true
+			l.fail(n, fmt.Errorf("resource %s not found", entry))
+			continue
+		}
+		if !info.IsDir() {
+			rs, err := readKustomizeResources(path)
+			if err != nil {
+				n.partial = true
+				l.fail(n, fmt.Errorf("resource %s: %w", entry, err))
+				continue
+			}
+			for _, r := range rs {
+				add(r, entry)
+			}
+			continue
+		}
+		if kustomizationFile(path) == "" {
+			n.partial = true
+			l.fail(n, fmt.Errorf("resource %s is a directory without a kustomization", entry))
+			continue
+		}
+		base, err := l.build(path)
+		if err != nil {
+			n.partial = true
+			if base == nil {
+				l.fail(n, err)
+			}
+			continue
+		}
+		n.bases[path] = true
+		for b := range base.bases {
+			n.bases[b] = true
+		}
+		n.partial = n.partial || base.partial
+		for _, r := range base.resources {
+			add(r, entry)
+		}
+	}
+
+	for _, c := range k.HelmCharts {
+		if c.Version == "" {
+			l.add(KustomizeFinding{
+				CCVEID: "CCVE-2025-0702", Name: "Unpinned remote base", Severity: "warning", Category: "SOURCE",
+				Dir: l.rel(dir), Kind: "HelmChart", ResourceName: c.Name,
+				Message:     fmt.Sprintf("Helm chart %s has no version; every build pulls the latest chart from %s", c.Name, c.Repo),
+				Remediation: "Set version on the helmCharts entry",
+			})
+		}
+	}
+
+	// Components patch the resources of the kustomization including them,
+	// and incomplete resources would make every patch look unused
+	if k.Kind != "Component" && !n.partial {
+		l.checkPatches(n)
+	}
+
+	for i := range n.resources {
+		r := &n.resources[i]
+		if k.Namespace != "" && !clusterScopedKinds[r.Kind] {
+			r.Namespace = k.Namespace
+		}
+		if r.Kind != "Namespace" && r.Kind != "CustomResourceDefinition" {
+			r.Name = k.NamePrefix + r.Name + k.NameSuffix
+		}
+	}
+	return n, nil
+}
+
+// readKustomizeResources reads the resources of a manifest file.
+func readKustomizeResources(path string) ([]kustomizeResource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := yamlDocs(data)
+	if err != nil {
+		return nil, err
+	}
+	var out []kustomizeResource
+	for _, doc := range docs {
+		m, ok := doc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if r, ok := kustomizeResourceOf(m); ok {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// kustomizeResourceOf reads the kind, name, namespace, labels and
+// annotations of a manifest or strategic merge patch.
+func kustomizeResourceOf(m map[string]interface{}) (kustomizeResource, bool) {
+	kind, _ := m["kind"].(string)
+	meta, _ := m["metadata"].(map[string]interface{})
+	name, _ := meta["name"].(string)
+	if kind == "" || name == "" {
+		return kustomizeResource{}, false
+	}
+	ns, _ := meta["namespace"].(string)
+	return kustomizeResource{
+		Kind: kind, Name: name, OrigName: name, Namespace: ns,
+		Labels: stringMap(meta["labels"]), Annotations: stringMap(meta["annotations"]),
+	}, true
+}
+
+func stringMap(v interface{}) map[string]string {
+	m, _ := v.(map[string]interface{})
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
+
+// yamlDocs decodes every document of a YAML stream, skipping empty ones.
+func yamlDocs(data []byte) ([]interface{}, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []interface{}
+	for {
+		var doc interface{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+}
+
+// checkPatches reports the patches of n that are empty or match none of
+// the resources it includes.
+func (l *kustomizeLinter) checkPatches(n *kustomizeNode) {
+	noop := func(label, why string) {
+		l.add(KustomizeFinding{
+			CCVEID: "CCVE-2025-0701", Name: "Patch changes nothing", Severity: "warning", Category: "CONFIG",
+			Dir: l.rel(n.dir), Kind: "Patch", ResourceName: label,
+			Message:     fmt.Sprintf("Patch %s %s", label, why),
+			Remediation: "Fix the patch target (kind, name or namespace as named in the base) or remove the patch",
+		})
+	}
+
+	type patch struct {
+		Patch
+		label string
+	}
+	var patches []patch
+	for _, p := range n.k.PatchesStrategicMerge {
+		if strings.Contains(p, "\n") {
+			patches = append(patches, patch{Patch{Patch: p}, "(inline)"})
+		} else {
+			patches = append(patches, patch{Patch{Path: p}, p})
+		}
+	}
+	for _, p := range append(append([]Patch{}, n.k.Patches...), n.k.PatchesJSON6902...) {
+		label := p.Path
+		if label == "" {
+			label = "(inline)"
+			if p.Target.Kind != "" || p.Target.Name != "" {
+				label = "(inline) for " + strings.Trim(p.Target.Kind+"/"+p.Target.Name, "/")
+			}
+		}
+		patches = append(patches, patch{p, label})
+	}
+
+	for _, p := range patches {
+		content := []byte(p.Patch.Patch)
+		if p.Path != "" {
+			data, err := os.ReadFile(filepath.Join(n.dir, p.Path))
+			if err != nil {
+				l.fail(n, fmt.Errorf("patch %s not found", p.Path))
+				continue
+			}
+			content = data
+		}
+		docs, err := yamlDocs(content)
+		if err != nil {
+			l.fail(n, fmt.Errorf("patch %s: %w", p.label, err))
+			continue
+		}
+		if len(docs) == 0 || isEmptyPatch(docs) {
+			noop(p.label, "is empty")
+			continue
+		}
+
+		if p.Target != (Target{}) {
+			if !matchesAny(n.resources, p.Target.matches) {
+				noop(p.label, "targets "+p.Target.String()+", which no resource matches")
+			}
+			continue
+		}
+		for _, doc := range docs {
+			m, _ := doc.(map[string]interface{})
+			target, ok := kustomizeResourceOf(m)
+			if !ok {
+				continue
+			}
+			if !matchesAny(n.resources, func(r kustomizeResource) bool {
+				return r.Kind == target.Kind && (r.Name == target.Name || r.OrigName == target.Name) &&
+					(target.Namespace == "" || r.Namespace == target.Namespace)
+			}) {
+				noop(p.label, "patches "+target.id()+", which no resource matches")
+			}
+		}
+	}
+}
+
+// isEmptyPatch reports whether a patch holds no operations: an empty JSON
+// patch list, or a strategic merge patch with nothing besides its ID.
+func isEmptyPatch(docs []interface{}) bool {
+	for _, doc := range docs {
+		switch d := doc.(type) {
+		case []interface{}:
+			if len(d) > 0 {
+				return false
+			}
+		case map[string]interface{}:
+			for k := range d {
+				if k != "apiVersion" && k != "kind" && k != "metadata" {
+					return false
+				}
+			}
+			if meta, _ := d["metadata"].(map[string]interface{}); len(meta) > 2 || (len(meta) == 2 && meta["namespace"] == nil) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(resources []kustomizeResource, match func(kustomizeResource) bool) bool {
+	for _, r := range resources {
+		if match(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether a patch target selects r. Name is a regular
+// expression matched against the current or the original name.
+func (t Target) matches(r kustomizeResource) bool {
+	if t.Kind != "" && t.Kind != r.Kind {
+		return false
+	}
+	if t.Namespace != "" && t.Namespace != r.Namespace {
+		return false
+	}
+	if t.Name != "" {
+		re, err := regexp.Compile("^(?:" + t.Name + ")$")
+		if err != nil {
+			if t.Name != r.Name && t.Name != r.OrigName {
+				return false
+			}
+		} else if !re.MatchString(r.Name) && !re.MatchString(r.OrigName) {
+			return false
+		}
+	}
+	for sel, set := range map[string]map[string]string{t.LabelSelector: r.Labels, t.AnnotationSelector: r.Annotations} {
+		if sel == "" {
+			continue
+		}
+		s, err := labels.Parse(sel)
+		if err != nil || !s.Matches(labels.Set(set)) {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the target as e.g. "Deployment/api in payments".
+func (t Target) String() string {
+	s := t.Kind
+	if s == "" {
+		s = "any kind"
+	}
+	if t.Name != "" {
+		s += "/" + t.Name
+	}
+	if t.Namespace != "" {
+		s += " in " + t.Namespace
+	}
+	if t.LabelSelector != "" {
+		s += " with labels " + t.LabelSelector
+	}
+	return s
+}
+
+// isRemoteResource reports whether a resources entry is a remote base or
+// file rather than a local path.
+func isRemoteResource(entry string) bool {
+	if strings.Contains(entry, "://") || strings.HasPrefix(entry, "git@") || strings.HasPrefix(entry, "git::") {
+		return true
+	}
+	for _, host := range []string{"github.com/", "gitlab.com/", "bitbucket.org/"} {
+		if strings.HasPrefix(entry, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteRef returns the ref a remote base or file is pinned to: its ref or
+// version query parameter, or for a file URL the path segment naming a
+// commit, version or branch.
+func remoteRef(entry string) string {
+	if _, query, ok := strings.Cut(entry, "?"); ok {
+		if q, err := url.ParseQuery(query); err == nil {
+			for _, key := range []string{"ref", "version"} {
+				if ref := q.Get(key); ref != "" {
+					return ref
+				}
+			}
+		}
+		return ""
+	}
+	ext := filepath.Ext(entry)
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return ""
+	}
+	u, err := url.Parse(entry)
+	if err != nil {
+		return ""
+	}
+	for _, seg := range strings.Split(u.Path, "/") {
+		if pinnedSegment.MatchString(seg) || (seg != "" && floatingRefs[seg]) {
+			return seg
+		}
+	}
+	return ""
+}
+
+// checkRemote reports a remote resource not pinned to a tag or commit.
+func (l *kustomizeLinter) checkRemote(n *kustomizeNode, entry string) {
+	ref := remoteRef(entry)
+	if !floatingRefs[ref] {
+		return
+	}
+	msg := fmt.Sprintf("Remote resource %s is not pinned; kustomize fetches the default branch on every build", entry)
+	if ref != "" {
+		msg = fmt.Sprintf("Remote resource %s follows %s; every build can render different resources", entry, ref)
+	}
+	l.add(KustomizeFinding{
+		CCVEID: "CCVE-2025-0702", Name: "Unpinned remote base", Severity: "warning", Category: "SOURCE",
+		Dir: l.rel(n.dir), Kind: "Remote", ResourceName: entry,
+		Message:     msg,
+		Remediation: "Pin the resource to a tag or commit, e.g. ?ref=v1.2.3",
+	})
+}
+
+// checkNamespaces reports an overlay rendering namespaced resources with no
+// namespace: they land in whatever namespace the applier defaults to.
+func (l *kustomizeLinter) checkNamespaces(n *kustomizeNode) {
+	var missing []string
+	for _, r := range n.resources {
+		if r.Namespace == "" && !clusterScopedKinds[r.Kind] {
+			missing = append(missing, r.Kind+"/"+r.Name)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	example := strings.Join(missing[:min(len(missing), 3)], ", ")
+	if len(missing) > 3 {
+		example += ", ..."
+	}
+	l.add(KustomizeFinding{
+		CCVEID: "CCVE-2025-0699", Name: "Overlay resources have no namespace", Severity: "warning", Category: "CONFIG",
+		Dir: l.rel(n.dir), Kind: "Kustomization", ResourceName: l.rel(n.dir),
+		Message:     fmt.Sprintf("%d namespaced resource(s) have no namespace (%s); they land in the applier's default namespace", len(missing), example),
+		Remediation: "Set namespace: in the overlay's kustomization.yaml, or targetNamespace on the Flux Kustomization",
+	})
+}
+
+// checkSharedIDs reports overlays of a common base that render resources
+// with the same ID: applied to one cluster, they overwrite each other.
+func (l *kustomizeLinter) checkSharedIDs(overlays []*kustomizeNode) {
+	for i, a := range overlays {
+		for _, b := range overlays[i+1:] {
+			if !sharesBase(a, b) {
+				continue
+			}
+			ids := map[string]bool{}
+			for _, r := range a.resources {
+				ids[r.id()] = true
+			}
+			var shared []string
+			for _, r := range b.resources {
+				if ids[r.id()] {
+					shared = append(shared, r.id())
+				}
+			}
+			if len(shared) == 0 {
+				continue
+			}
+			l.add(KustomizeFinding{
+				CCVEID: "CCVE-2025-0700", Name: "Duplicate resource ID", Severity: "info", Category: "CONFIG",
+				Dir: l.rel(a.dir), Kind: "Kustomization", ResourceName: l.rel(b.dir),
+				Message: fmt.Sprintf("Overlays %s and %s both render %d resource(s) with the same ID (e.g. %s); applied to one cluster they overwrite each other",
+					l.rel(a.dir), l.rel(b.dir), len(shared), shared[0]),
+				Remediation: "Give each overlay its own namespace or namePrefix, unless they target different clusters",
+			})
+		}
+	}
+}
+
+func sharesBase(a, b *kustomizeNode) bool {
+	for base := range a.bases {
+		if b.bases[base] {
+			return true
+		}
+	}
+	return false
+}
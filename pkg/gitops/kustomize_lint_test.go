@@ -0,0 +1,178 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func writeKustomizeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+const deploymentAPI = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  labels:
+    app: api
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+`
+
+// findingsByCheck returns "CCVE dir resource" for every finding, sorted.
+func findingsByCheck(result *KustomizeLintResult) []string {
+	var out []string
+	for _, f := range result.Findings {
+		out = append(out, f.CCVEID+" "+f.Dir+" "+f.ResourceName)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestLintKustomizationsClean(t *testing.T) {
+	root := writeKustomizeTree(t, map[string]string{
+		"base/kustomization.yaml":          "resources: [deployment.yaml]\n",
+		"base/deployment.yaml":             deploymentAPI,
+		"overlays/prod/kustomization.yaml": "namespace: api-prod\nresources: [../../base]\npatches:\n- path: replicas.yaml\n- target: {kind: Deployment, name: ap.*}\n  patch: |\n    - op: replace\n      path: /spec/replicas\n      value: 3\n",
+		"overlays/prod/replicas.yaml":      "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\nspec:\n  replicas: 3\n",
+		"overlays/dev/kustomization.yaml":  "namespace: api-dev\nresources:\n- ../../base\n- https://github.com/fluxcd/flux2/releases/download/v2.3.0/install.yaml\n- github.com/org/shared//monitoring?ref=v1.4.0\n",
+	})
+
+	result, err := LintKustomizations(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Findings) != 0 || len(result.Errors) != 0 {
+		t.Errorf("findings = %v, errors = %v", findingsByCheck(result), result.Errors)
+	}
+	if result.Kustomizations != 3 || !reflect.DeepEqual(result.Overlays, []string{"overlays/dev", "overlays/prod"}) {
+		t.Errorf("kustomizations = %d, overlays = %v", result.Kustomizations, result.Overlays)
+	}
+}
+
+func TestLintKustomizationsFindings(t *testing.T) {
+	root := writeKustomizeTree(t, map[string]string{
+		"base/kustomization.yaml": "resources: [deployment.yaml]\n",
+		"base/deployment.yaml":    deploymentAPI,
+		// No namespace, a patch for a renamed Deployment, an empty patch
+		"overlays/staging/kustomization.yaml": `resources: [../../base]
+patchesStrategicMerge: [worker.yaml]
+patches:
+- path: empty.yaml
+- target: {kind: Deployment, labelSelector: app=web}
+  patch: '[{"op": "replace", "path": "/spec/replicas", "value": 2}]'
+`,
+		"overlays/staging/worker.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: worker\nspec:\n  replicas: 2\n",
+		"overlays/staging/empty.yaml":  "[]\n",
+		// Same IDs as staging, unpinned remote bases and chart
+		"overlays/canary/kustomization.yaml": `resources:
+- ../../base
+- github.com/org/shared//monitoring
+- https://raw.githubusercontent.com/org/repo/main/crds.yaml
+helmCharts:
+- name: redis
+  repo: https://charts.bitnami.com/bitnami
+`,
+		// Includes the base twice
+		"clusters/dup/kustomization.yaml": "namespace: dup\nresources: [../../base, ../../overlays/canary]\n",
+	})
+
+	result, err := LintKustomizations(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"CCVE-2025-0699 overlays/staging overlays/staging",
+		"CCVE-2025-0700 clusters/dup api",
+		"CCVE-2025-0700 clusters/dup api",
+		"CCVE-2025-0701 overlays/staging (inline) for Deployment",
+		"CCVE-2025-0701 overlays/staging empty.yaml",
+		"CCVE-2025-0701 overlays/staging worker.yaml",
+		"CCVE-2025-0702 overlays/canary github.com/org/shared//monitoring",
+		"CCVE-2025-0702 overlays/canary https://raw.githubusercontent.com/org/repo/main/crds.yaml",
+		"CCVE-2025-0702 overlays/canary redis",
+	}
+	if got := findingsByCheck(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, f := range result.Findings {
+		if f.CCVEID == "CCVE-2025-0700" && f.Severity != "critical" {
+			t.Errorf("duplicate in one build should be critical: %+v", f)
+		}
+	}
+}
+
+func TestLintKustomizationsSharedIDs(t *testing.T) {
+	root := writeKustomizeTree(t, map[string]string{
+		"base/kustomization.yaml":         "namespace: api\nresources: [deployment.yaml]\n",
+		"base/deployment.yaml":            deploymentAPI,
+		"overlays/a/kustomization.yaml":   "resources: [../../base]\n",
+		"overlays/b/kustomization.yaml":   "resources: [../../base]\n",
+		"overlays/c/kustomization.yaml":   "resources: [../../base]\nnamePrefix: c-\n",
+		"standalone/kustomization.yaml":   "resources: [deployment.yaml]\nnamespace: api\n",
+		"standalone/deployment.yaml":      deploymentAPI,
+		"overlays/bad/kustomization.yaml": "resources: [../../missing]\n",
+	})
+
+	result, err := LintKustomizations(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// standalone renders the same IDs but shares no base with the overlays
+	want := []string{"CCVE-2025-0700 overlays/a overlays/b"}
+	if got := findingsByCheck(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+	if len(result.Findings) == 1 && result.Findings[0].Severity != "info" {
+		t.Errorf("shared IDs across overlays should be info: %+v", result.Findings[0])
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "overlays/bad: resource ../../missing not found") {
+		t.Errorf("errors = %v", result.Errors)
+	}
+}
+
+func TestRemoteRef(t *testing.T) {
+	tests := map[string]string{
+		"github.com/org/repo//deploy?ref=v1.2.0":                          "v1.2.0",
+		"https://github.com/org/repo//deploy?ref=main&timeout=90s":        "main",
+		"git@github.com:org/repo.git//deploy?version=0123abc":             "0123abc",
+		"github.com/org/repo//deploy":                                     "",
+		"https://github.com/fluxcd/flux2/releases/download/v2.3.0/x.yaml": "v2.3.0",
+		"https://raw.githubusercontent.com/org/repo/master/crds.yaml":     "master",
+		"https://example.com/manifests/crds.yaml":                         "",
+	}
+	for entry, want := range tests {
+		if !isRemoteResource(entry) {
+			t.Errorf("%s not detected as remote", entry)
+		}
+		if got := remoteRef(entry); got != want {
+			t.Errorf("remoteRef(%s) = %q, want %q", entry, got, want)
+		}
+	}
+	if isRemoteResource("../../base") {
+		t.Error("local path detected as remote")
+	}
+}
@@ -128,22 +128,45 @@ type ClusterDefinition struct {
 
 // Kustomization represents a kustomization.yaml file
 type Kustomization struct {
-	APIVersion string   `yaml:"apiVersion"`
-	Kind       string   `yaml:"kind"`
-	Resources  []string `yaml:"resources"`
-	Patches    []Patch  `yaml:"patches,omitempty"`
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Namespace  string      `yaml:"namespace,omitempty"`
+	NamePrefix string      `yaml:"namePrefix,omitempty"`
+	NameSuffix string      `yaml:"nameSuffix,omitempty"`
+	Resources  []string    `yaml:"resources"`
+	Bases      []string    `yaml:"bases,omitempty"`
+	Components []string    `yaml:"components,omitempty"`
+	Patches    []Patch     `yaml:"patches,omitempty"`
+	HelmCharts []HelmChart `yaml:"helmCharts,omitempty"`
+
+	// Deprecated patch fields, still accepted by kustomize
+	PatchesStrategicMerge []string `yaml:"patchesStrategicMerge,omitempty"`
+	PatchesJSON6902       []Patch  `yaml:"patchesJson6902,omitempty"`
 }
 
-// Patch represents a kustomize patch
+// Patch represents a kustomize patch, read from Path or given inline
 type Patch struct {
 	Path   string `yaml:"path"`
+	Patch  string `yaml:"patch,omitempty"`
 	Target Target `yaml:"target,omitempty"`
 }
 
-// Target represents a patch target
+// Target represents a patch target. Name is a regular expression.
 type Target struct {
-	Kind string `yaml:"kind"`
-	Name string `yaml:"name"`
+	Group              string `yaml:"group,omitempty"`
+	Version            string `yaml:"version,omitempty"`
+	Kind               string `yaml:"kind"`
+	Name               string `yaml:"name"`
+	Namespace          string `yaml:"namespace,omitempty"`
+	LabelSelector      string `yaml:"labelSelector,omitempty"`
+	AnnotationSelector string `yaml:"annotationSelector,omitempty"`
+}
+
+// HelmChart represents a kustomize helmCharts entry
+type HelmChart struct {
+	Name    string `yaml:"name"`
+	Repo    string `yaml:"repo,omitempty"`
+	Version string `yaml:"version,omitempty"`
 }
 
 // ParseRepo parses a GitOps repository and returns its structure
@@ -0,0 +1,335 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package orgpattern recognises how a ConfigHub organization is laid out:
+// which spaces form the Hub (platform) and which are AppSpaces (teams), and
+// which reference architecture the layout follows. It reads space names,
+// space and unit labels, and which spaces own the workers and targets that
+// units deploy through. The patterns are documented in
+// docs/reference/hub-appspace-examples.md.
+package orgpattern
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Space is what detection needs to know about one ConfigHub space. Units,
+// Targets and Workers may be empty when the space has not been loaded;
+// detection then falls back to the name and labels.
+type Space struct {
+	Slug    string            `json:"slug"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Units   []Unit            `json:"units,omitempty"`
+	Targets []string          `json:"targets,omitempty"` // target slugs
+	Workers []string          `json:"workers,omitempty"` // worker slugs
+}
+
+// Unit is a unit and the target it deploys through.
+type Unit struct {
+	Slug   string            `json:"slug"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// TargetSpace is the space that owns the unit's target, or "" when
+	// the unit has no target.
+	TargetSpace string `json:"targetSpace,omitempty"`
+}
+
+// Role is a space's place in the Hub/AppSpace model.
+type Role string
+
+const (
+	RoleHub      Role = "hub"
+	RoleAppSpace Role = "appspace"
+)
+
+// Pattern names.
+const (
+	PatternNone        = "None"
+	PatternBanko       = "Banko (Flux)"
+	PatternArnie       = "Arnie (ArgoCD)"
+	PatternTraderX     = "TraderX (Multi-region)"
+	PatternKubeCon     = "KubeCon Demo"
+	PatternCuriousCub  = "curious-cub (Standard)"
+	PatternEnvironment = "Environment-based"
+	PatternCustom      = "Custom"
+)
+
+// hubNames, hubPrefixes and hubSuffixes mark platform spaces by name.
+var (
+	hubNames    = map[string]bool{"base": true, "infra": true, "platform": true, "hub": true, "shared": true}
+	hubPrefixes = []string{"platform-", "infra-", "hub-", "shared-"}
+	hubSuffixes = []string{"-platform", "-base", "-infra", "-variants", "-shared"}
+)
+
+// envTokens map name tokens and label values to environments.
+var envTokens = map[string]string{
+	"dev": "dev", "development": "dev", "qa": "dev", "test": "dev", "integration": "dev", "load": "dev",
+	"staging": "staging", "stage": "staging", "stg": "staging",
+	"prod": "prod", "production": "prod", "prd": "prod",
+}
+
+// regionTokens are name tokens and label values read as regions.
+var regionTokens = map[string]bool{
+	"asia": true, "eu": true, "us": true, "emea": true, "apac": true, "amer": true,
+	"useast": true, "uswest": true, "euwest": true,
+}
+
+// ClassifyName places a space by its name alone, as the hierarchy TUI's
+// Hub/AppSpace view does before spaces are loaded.
+func ClassifyName(slug string) Role {
+	lower := strings.ToLower(slug)
+	if hubNames[lower] {
+		return RoleHub
+	}
+	for _, p := range hubPrefixes {
+		if strings.HasPrefix(lower, p) {
+			return RoleHub
+		}
+	}
+	for _, s := range hubSuffixes {
+		if strings.HasSuffix(lower, s) {
+			return RoleHub
+		}
+	}
+	return RoleAppSpace
+}
+
+// Classify places a space using its Role label when set, then the workers
+// and targets it owns, then its name. A space that owns targets other
+// spaces' units deploy through, or holds workers and no units, is a Hub.
+func Classify(s Space, servedBy map[string]bool) Role {
+	switch Role(strings.ToLower(s.Labels["Role"])) {
+	case RoleHub:
+		return RoleHub
+	case RoleAppSpace:
+		return RoleAppSpace
+	}
+	if servedBy[s.Slug] || (len(s.Units) == 0 && (len(s.Workers) > 0 || len(s.Targets) > 0)) {
+		return RoleHub
+	}
+	return ClassifyName(s.Slug)
+}
+
+// Signals are the facts a pattern is recognised from.
+type Signals struct {
+	Platform bool `json:"platform"` // a platform-* style space
+	Base     bool `json:"base"`     // a base/template space
+	Infra    bool `json:"infra"`    // an infra space
+	Clusters bool `json:"clusters"` // spaces named after clusters
+
+	Envs    map[string]int `json:"envs,omitempty"`    // environment -> spaces
+	Regions map[string]int `json:"regions,omitempty"` // region -> spaces
+
+	Units            int `json:"units"`
+	LabelledUnits    int `json:"labelledUnits"`    // units with a variant or Environment label
+	HubTargetUnits   int `json:"hubTargetUnits"`   // units deploying through a Hub-owned target
+	AppSpaceWorkers  int `json:"appSpaceWorkers"`  // workers living in AppSpaces
+	RegionlessUnits  int `json:"regionlessUnits"`  // units in regional spaces without a region label
+	UntargetedUnits  int `json:"untargetedUnits"`  // units with no target
+	WorkersInHub     int `json:"workersInHub"`     // workers living in Hub spaces
+	TargetsInHub     int `json:"targetsInHub"`     // targets living in Hub spaces
+	AppSpacesLabeled int `json:"appSpacesLabeled"` // AppSpaces with an Environment label
+}
+
+// Result is a detected layout.
+type Result struct {
+	Pattern         string   `json:"pattern"`
+	Traits          []string `json:"traits"`
+	Hub             []string `json:"hub"`
+	AppSpaces       []string `json:"appSpaces"`
+	Signals         Signals  `json:"signals"`
+	Recommendations []string `json:"recommendations,omitempty"`
+}
+
+// Detect recognises the layout of spaces.
+func Detect(spaces []Space) Result {
+	r := Result{Hub: []string{}, AppSpaces: []string{}}
+	if len(spaces) == 0 {
+		r.Pattern = PatternNone
+		r.Traits = []string{"No spaces"}
+		return r
+	}
+
+	// Spaces whose targets other spaces' units deploy through
+	servedBy := make(map[string]bool)
+	for _, s := range spaces {
+		for _, u := range s.Units {
+			if u.TargetSpace != "" && u.TargetSpace != s.Slug {
+				servedBy[u.TargetSpace] = true
+			}
+		}
+	}
+	roles := make(map[string]Role, len(spaces))
+	for _, s := range spaces {
+		roles[s.Slug] = Classify(s, servedBy)
+		if roles[s.Slug] == RoleHub {
+			r.Hub = append(r.Hub, s.Slug)
+		} else {
+			r.AppSpaces = append(r.AppSpaces, s.Slug)
+		}
+	}
+	sort.Strings(r.Hub)
+	sort.Strings(r.AppSpaces)
+
+	r.Signals = collectSignals(spaces, roles)
+	r.Pattern, r.Traits = matchPattern(r.Signals)
+	r.Recommendations = recommend(r, spaces, roles)
+	return r
+}
+
+func collectSignals(spaces []Space, roles map[string]Role) Signals {
+	sig := Signals{Envs: map[string]int{}, Regions: map[string]int{}}
+	for _, s := range spaces {
+		lower := strings.ToLower(s.Slug)
+		tokens := strings.FieldsFunc(lower, func(r rune) bool { return r == '-' || r == '.' || r == '_' })
+		if strings.HasPrefix(lower, "platform") || strings.HasSuffix(lower, "-platform") {
+			sig.Platform = true
+		}
+		if containsToken(tokens, "base") || containsToken(tokens, "variants") {
+			sig.Base = true
+		}
+		if containsToken(tokens, "infra") {
+			sig.Infra = true
+		}
+		if strings.HasPrefix(lower, "cluster-") || strings.Contains(lower, ".example.") || strings.Count(lower, ".") >= 2 {
+			sig.Clusters = true
+		}
+
+		env := envTokens[strings.ToLower(s.Labels["Environment"])]
+		region := strings.ToLower(s.Labels["region"])
+		for _, t := range tokens {
+			if e, ok := envTokens[t]; ok && env == "" {
+				env = e
+			}
+			if regionTokens[t] && region == "" {
+				region = t
+			}
+		}
+		for _, u := range s.Units {
+			if env == "" {
+				env = envTokens[strings.ToLower(u.Labels["variant"])]
+			}
+			if region == "" && regionTokens[strings.ToLower(u.Labels["region"])] {
+				region = strings.ToLower(u.Labels["region"])
+			}
+		}
+		if env != "" && roles[s.Slug] == RoleAppSpace {
+			sig.Envs[env]++
+		}
+		if region != "" {
+			sig.Regions[region]++
+		}
+
+		if roles[s.Slug] == RoleHub {
+			sig.WorkersInHub += len(s.Workers)
+			sig.TargetsInHub += len(s.Targets)
+			continue
+		}
+
+		sig.AppSpaceWorkers += len(s.Workers)
+		if s.Labels["Environment"] != "" {
+			sig.AppSpacesLabeled++
+		}
+		regional := region != "" && s.Labels["region"] == ""
+		for _, u := range s.Units {
+			sig.Units++
+			if u.Labels["variant"] != "" || u.Labels["Environment"] != "" {
+				sig.LabelledUnits++
+			}
+			switch {
+			case u.TargetSpace == "":
+				sig.UntargetedUnits++
+			case roles[u.TargetSpace] == RoleHub:
+				sig.HubTargetUnits++
+			}
+			if regional && u.Labels["region"] == "" {
+				sig.RegionlessUnits++
+			}
+		}
+	}
+	return sig
+}
+
+func containsToken(tokens []string, want string) bool {
+	for _, t := range tokens {
+		if t == want {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern names the layout. The more specific layouts are tried
+// first; regional AppSpaces only make a TraderX layout when there is no
+// dev-to-prod chain, since Arnie and curious-cub layouts use regions too.
+func matchPattern(sig Signals) (string, []string) {
+	envs := sig.Envs["dev"] > 0 && sig.Envs["prod"] > 0
+	regions := len(sig.Regions) >= 2
+	switch {
+	case sig.Clusters && (sig.Base || sig.Infra || sig.Platform):
+		return PatternBanko, []string{"Cluster-per-directory structure", "Versioned platform components", "platform/ → Hub, clusters/* → AppSpaces"}
+	case sig.Base && sig.Infra && envs:
+		return PatternCuriousCub, []string{"Base/Infra Hub", "dev/staging/prod AppSpaces"}
+	case sig.Base && envs:
+		return PatternArnie, []string{"Folders-per-environment", "Promotion = file copy", "base/ → Hub, envs/* → AppSpaces"}
+	case regions && (sig.Base || sig.Infra):
+		return PatternTraderX, []string{"Base/Infra Hub + regional AppSpaces", "Labels: variant, region"}
+	case sig.Platform && envs:
+		return PatternKubeCon, []string{"Platform team + App teams", "platform-* → Hub, app*-dev/prod → AppSpaces"}
+	case envs:
+		return PatternEnvironment, []string{"Spaces per environment (dev/staging/prod)", "Consider adding base/infra Hub spaces"}
+	}
+	return PatternCustom, []string{"No standard pattern detected", "See docs for reference architectures"}
+}
+
+func recommend(r Result, spaces []Space, roles map[string]Role) []string {
+	sig := r.Signals
+	var recs []string
+	if len(r.Hub) == 0 {
+		recs = append(recs, "No Hub spaces: add a platform space for workers, targets and shared config (cub-scout init space)")
+	}
+	if len(sig.Envs) > 0 && !sig.Base && r.Pattern != PatternKubeCon {
+		recs = append(recs, "No base space: keep upstream units in a *-base space and clone them into each environment")
+	}
+	if sig.AppSpaceWorkers > 0 {
+		recs = append(recs, fmt.Sprintf("%d worker(s) live in AppSpaces: move workers and targets to a Hub space so teams share them", sig.AppSpaceWorkers))
+	}
+	if n := sig.Units - sig.LabelledUnits; sig.Units > 0 && n > 0 {
+		recs = append(recs, fmt.Sprintf("%d of %d AppSpace unit(s) have no variant label: label them variant=<env> so promotion and queries can group them", n, sig.Units))
+	}
+	if sig.RegionlessUnits > 0 {
+		recs = append(recs, fmt.Sprintf("%d unit(s) in regional spaces have no region label: label them region=<region>", sig.RegionlessUnits))
+	}
+	if sig.UntargetedUnits > 0 {
+		recs = append(recs, fmt.Sprintf("%d unit(s) have no target: set one with cub unit set-target", sig.UntargetedUnits))
+	}
+	if unlabelled := len(r.AppSpaces) - sig.AppSpacesLabeled; len(sig.Envs) > 0 && unlabelled > 0 {
+		recs = append(recs, fmt.Sprintf("%d AppSpace(s) have no Environment label: label spaces so environment grouping does not rely on names", unlabelled))
+	}
+	var nameOnly []string
+	for _, s := range spaces {
+		if roles[s.Slug] == RoleHub && ClassifyName(s.Slug) == RoleAppSpace && s.Labels["Role"] == "" {
+			nameOnly = append(nameOnly, s.Slug)
+		}
+	}
+	if len(nameOnly) > 0 {
+		sort.Strings(nameOnly)
+		recs = append(recs, fmt.Sprintf("%s act(s) as Hub but the name does not say so: label Role=hub or rename to platform-* or *-infra", strings.Join(nameOnly, ", ")))
+	}
+	return recs
+}
+
+// Summary renders the pattern and its traits as the hierarchy TUI shows
+// them: a "Pattern:" line followed by bullets.
+func (r Result) Summary() string {
+	if r.Pattern == PatternNone {
+		return "No pattern detected (no spaces)\n"
+	}
+	var b strings.Builder
+	b.WriteString("Pattern: " + r.Pattern + "\n")
+	for _, t := range r.Traits {
+		b.WriteString("• " + t + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+package orgpattern
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func loadFixture(t *testing.T, name string) []Space {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spaces []Space
+	if err := json.Unmarshal(data, &spaces); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return spaces
+}
+
+func TestDetectFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		pattern string
+		hub     []string
+		recs    []string // substrings that must appear in some recommendation
+	}{
+		{
+			fixture: "kubecon",
+			pattern: PatternKubeCon,
+			hub:     []string{"platform-dev", "platform-prod"},
+			recs:    []string{"6 AppSpace(s) have no Environment label"},
+		},
+		{
+			fixture: "traderx",
+			pattern: PatternTraderX,
+			hub:     []string{"fluffy-cub-traderx-base", "fluffy-cub-traderx-infra"},
+			recs:    []string{"1 unit(s) in regional spaces have no region label"},
+		},
+		{
+			fixture: "curious-cub",
+			pattern: PatternCuriousCub,
+			hub:     []string{"curious-cub-base", "curious-cub-infra"},
+			recs:    []string{"1 AppSpace(s) have no Environment label"},
+		},
+		{
+			fixture: "banko",
+			pattern: PatternBanko,
+			hub:     []string{"banko-platform"},
+			recs:    []string{"2 worker(s) live in AppSpaces"},
+		},
+		{
+			fixture: "arnie",
+			pattern: PatternArnie,
+			hub:     []string{"arnie-base", "arnie-platform", "arnie-variants"},
+		},
+		{
+			fixture: "acorn-bear",
+			pattern: PatternTraderX,
+			hub:     []string{"acorn-bear-infra"},
+			recs:    []string{"4 unit(s) have no target"},
+		},
+		{
+			fixture: "jesper",
+			pattern: PatternCustom,
+			hub:     []string{},
+			recs:    []string{"No Hub spaces"},
+		},
+		{
+			fixture: "env-only",
+			pattern: PatternEnvironment,
+			hub:     []string{},
+			recs:    []string{"No Hub spaces", "No base space", "3 of 3 AppSpace unit(s) have no variant label", "3 unit(s) have no target"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			r := Detect(loadFixture(t, tt.fixture))
+			if r.Pattern != tt.pattern {
+				t.Errorf("pattern = %q, want %q (signals %+v)", r.Pattern, tt.pattern, r.Signals)
+			}
+			if !reflect.DeepEqual(r.Hub, tt.hub) {
+				t.Errorf("hub = %v, want %v", r.Hub, tt.hub)
+			}
+			all := strings.Join(r.Recommendations, "\n")
+			for _, want := range tt.recs {
+				if !strings.Contains(all, want) {
+					t.Errorf("recommendations should mention %q, got:\n%s", want, all)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectEmpty(t *testing.T) {
+	r := Detect(nil)
+	if r.Pattern != PatternNone || r.Summary() != "No pattern detected (no spaces)\n" {
+		t.Errorf("Detect(nil) = %+v", r)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		space    Space
+		servedBy map[string]bool
+		want     Role
+	}{
+		{"hub prefix", Space{Slug: "platform-prod"}, nil, RoleHub},
+		{"hub suffix", Space{Slug: "payments-infra"}, nil, RoleHub},
+		{"bare name", Space{Slug: "base"}, nil, RoleHub},
+		{"team space", Space{Slug: "payments-prod"}, nil, RoleAppSpace},
+		{"label wins over name", Space{Slug: "platform-sandbox", Labels: map[string]string{"Role": "appspace"}}, nil, RoleAppSpace},
+		{"label case-insensitive", Space{Slug: "payments", Labels: map[string]string{"Role": "Hub"}}, nil, RoleHub},
+		{"serves other spaces", Space{Slug: "clusters", Units: []Unit{{Slug: "cni"}}}, map[string]bool{"clusters": true}, RoleHub},
+		{"workers and no units", Space{Slug: "ops", Workers: []string{"w"}}, nil, RoleHub},
+		{"workers and units", Space{Slug: "ops", Workers: []string{"w"}, Units: []Unit{{Slug: "app"}}}, nil, RoleAppSpace},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.space, tt.servedBy); got != tt.want {
+				t.Errorf("Classify(%s) = %s, want %s", tt.space.Slug, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectHubByTargets(t *testing.T) {
+	spaces := []Space{
+		{Slug: "clusters", Targets: []string{"prod"}, Units: []Unit{{Slug: "cni", TargetSpace: "clusters"}}},
+		{Slug: "shop-dev", Units: []Unit{{Slug: "shop", Labels: map[string]string{"variant": "dev"}, TargetSpace: "clusters"}}},
+		{Slug: "shop-prod", Units: []Unit{{Slug: "shop", Labels: map[string]string{"variant": "prod"}, TargetSpace: "clusters"}}},
+	}
+	r := Detect(spaces)
+	if !reflect.DeepEqual(r.Hub, []string{"clusters"}) {
+		t.Fatalf("hub = %v, want [clusters]: units in other spaces deploy through its target", r.Hub)
+	}
+	if r.Signals.HubTargetUnits != 2 {
+		t.Errorf("HubTargetUnits = %d, want 2", r.Signals.HubTargetUnits)
+	}
+	if !strings.Contains(strings.Join(r.Recommendations, "\n"), "clusters act(s) as Hub but the name does not say so") {
+		t.Errorf("expected a Role=hub recommendation, got %v", r.Recommendations)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	got := Detect(loadFixture(t, "kubecon")).Summary()
+	want := "Pattern: KubeCon Demo\n• Platform team + App teams\n• platform-* → Hub, app*-dev/prod → AppSpaces\n"
+	if got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -128,6 +129,14 @@ func (dd *DriftDetector) DetectDrift(resource *unstructured.Unstructured) (*Drif
 	}, nil
 }
 
+// Compare returns the differences between a declared object and its live
+// state, sorted by path. Paths in FieldsToIgnore are skipped.
+func Compare(declared, live interface{}) []DriftChange {
+	changes := (&DriftDetector{}).compare("", declared, live)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
 // compare recursively compares two maps and returns differences
 func (dd *DriftDetector) compare(path string, declared, live interface{}) []DriftChange {
 	var changes []DriftChange
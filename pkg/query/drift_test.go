@@ -164,6 +164,29 @@ func TestFormatDrift(t *testing.T) {
 	}
 }
 
+func TestCompare(t *testing.T) {
+	declared := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "nginx", "labels": map[string]interface{}{"app": "nginx"}},
+		"spec":     map[string]interface{}{"replicas": 3, "paused": false},
+	}
+	live := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "nginx", "labels": map[string]interface{}{"app": "web"}, "uid": "abc"},
+		"spec":     map[string]interface{}{"replicas": int64(5), "paused": false},
+		"status":   map[string]interface{}{"readyReplicas": int64(5)},
+	}
+
+	changes := Compare(declared, live)
+	if len(changes) != 2 {
+		t.Fatalf("Compare() = %+v, want 2 changes", changes)
+	}
+	if changes[0].Path != "metadata.labels.app" || changes[1].Path != "spec.replicas" {
+		t.Errorf("changes not sorted by path: %+v", changes)
+	}
+	if got := Compare(declared, declared); len(got) != 0 {
+		t.Errorf("Compare(x, x) = %+v", got)
+	}
+}
+
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || findSubstring(s, substr))
 }
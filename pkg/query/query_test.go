@@ -4,6 +4,7 @@
 package query
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -329,3 +330,33 @@ func TestQueryString(t *testing.T) {
 		})
 	}
 }
+
+// BenchmarkMatches_10k evaluates a typical map list query, with a regex and
+// a label condition, against 10k entries; see test/bench-budget.txt.
+func BenchmarkMatches_10k(b *testing.B) {
+	owners := []string{"Flux", "ArgoCD", "Helm", "ConfigHub", "Terraform", "Native"}
+	kinds := []string{"Deployment", "Service", "ConfigMap"}
+	entries := make([]mockEntry, 10000)
+	for i := range entries {
+		entries[i] = mockEntry{
+			data: map[string]string{
+				"kind":      kinds[i%len(kinds)],
+				"namespace": fmt.Sprintf("team-%02d", i%50),
+				"name":      fmt.Sprintf("obj-%05d", i),
+				"owner":     owners[i%len(owners)],
+			},
+			labels: map[string]string{"app": fmt.Sprintf("app-%d", i%20)},
+		}
+	}
+	q, err := Parse("kind=Deployment,StatefulSet AND namespace~=team-[0-4].* AND owner!=Native OR labels[app]=app-7")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range entries {
+			q.Matches(e)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright (C) ConfigHub, Inc.
+// SPDX-License-Identifier: MIT
+
+// Package redact masks secrets in cub-scout output: Secret data, well-known
+// token formats, and patterns users configure. Diffs, snapshots and deep-dive
+// output all go through it, so a snapshot can be shared without leaking
+// credentials the cluster happens to hold in plain sight.
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces every redacted value.
+const Mask = "(redacted)"
+
+// Rule is a named pattern whose matches are masked. When the pattern has a
+// capture group only the first group is masked, so "password=(\S+)" keeps
+// "password=" readable.
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// Builtins are the rules every Redactor starts with.
+var Builtins = []Rule{
+	{"private-key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----(?s:.*?)(?:-----END [A-Z ]*PRIVATE KEY-----|$)`)},
+	{"github-token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"gitlab-token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{"aws-access-key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"slack-token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+	{"bearer", regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/-]{16,}=*)`)},
+	{"url-password", regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`)},
+	// password: x, DB_PASSWORD=x, client_secret: x, token: x; not keys that
+	// merely end in a keyword, like existingSecret or automountServiceAccountToken
+	{"secret-assignment", regexp.MustCompile(`(?i)\b(?:[\w.-]*(?:password|passwd|secret[_-]?key|client[_-]?secret|access[_-]?key|api[_-]?key|auth[_-]?token|access[_-]?token|private[_-]?key|[_.-]token|[_.-]secret)|token|secret)["']?\s*[:=]\s*["']?([^\s"',}(]+)`)},
+}
+
+// Redactor masks secrets in strings, decoded objects and streams. A nil
+// Redactor masks nothing.
+type Redactor struct {
+	rules []Rule
+}
+
+// New returns a Redactor with the built-in rules except those named in
+// disable, plus patterns, which are regular expressions.
+func New(patterns, disable []string) (*Redactor, error) {
+	r := &Redactor{}
+	known := map[string]bool{}
+	for _, rule := range Builtins {
+		known[rule.Name] = true
+	}
+	for _, name := range disable {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown built-in rule %q", name)
+		}
+	}
+	for _, rule := range Builtins {
+		if !contains(disable, rule.Name) {
+			r.rules = append(r.rules, rule)
+		}
+	}
+	for i, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", p, err)
+		}
+		r.rules = append(r.rules, Rule{Name: fmt.Sprintf("pattern-%d", i+1), Pattern: re})
+	}
+	return r, nil
+}
+
+// String masks every rule match in s.
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, rule := range r.rules {
+		s = maskMatches(rule.Pattern, s)
+	}
+	return s
+}
+
+func maskMatches(re *regexp.Regexp, s string) string {
+	if re.NumSubexp() == 0 {
+		return re.ReplaceAllLiteralString(s, Mask)
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range re.FindAllStringSubmatchIndex(s, -1) {
+		start, end := m[0], m[1]
+		if m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(Mask)
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// Value returns a copy of a decoded JSON or YAML value with every string
+// masked. Maps and lists are copied; other values are returned as they are.
+func (r *Redactor) Value(v interface{}) interface{} {
+	if r == nil {
+		return v
+	}
+	switch v := v.(type) {
+	case string:
+		return r.String(v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			if s, ok := e.(string); ok {
+				// Mask with the key in view, so rules like password: x apply
+				// to fields as well as to text
+				if line := r.String(k + ": " + s); strings.HasPrefix(line, k+": ") {
+					out[k] = strings.TrimPrefix(line, k+": ")
+					continue
+				}
+			}
+			out[k] = r.Value(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = r.Value(e)
+		}
+		return out
+	case map[string]string:
+		return r.Strings(v)
+	}
+	return v
+}
+
+// Strings returns a copy of m with every value masked.
+func (r *Redactor) Strings(m map[string]string) map[string]string {
+	if r == nil || m == nil {
+		return m
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = r.String(v)
+	}
+	return out
+}
+
+// Differs marks a masked value that differs from the other side of a diff.
+const Differs = "(redacted, differs)"
+
+// SecretData returns copies of two versions of a Secret with their data
+// values replaced by Mask, or by Differs where the second version's value
+// is not the first's. Keys only in to are masked too. Either side may be
+// nil.
+func SecretData(from, to map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	fromData, _ := from["data"].(map[string]interface{})
+	toData, _ := to["data"].(map[string]interface{})
+	maskedFrom := make(map[string]interface{}, len(fromData))
+	maskedTo := make(map[string]interface{}, len(toData))
+	for k := range fromData {
+		maskedFrom[k] = Mask
+	}
+	for k, v := range toData {
+		maskedTo[k] = Mask
+		if fv, ok := fromData[k]; ok && fmt.Sprint(fv) != fmt.Sprint(v) {
+			maskedTo[k] = Differs
+		}
+	}
+	if fromData != nil {
+		from = withField(from, "data", maskedFrom)
+	}
+	if toData != nil {
+		to = withField(to, "data", maskedTo)
+	}
+	return from, to
+}
+
+// withField returns a copy of doc with key set to v.
+func withField(doc map[string]interface{}, key string, v interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(doc)+1)
+	for k, dv := range doc {
+		out[k] = dv
+	}
+	out[key] = v
+	return out
+}
+
+// Writer returns a writer that masks each line written to it before passing
+// it on to w. A private key spans lines, so from its BEGIN line up to its END
+// line the whole block is written as one Mask. Close flushes a final
+// unterminated line.
+func (r *Redactor) Writer(w io.Writer) io.WriteCloser {
+	return &lineWriter{r: r, w: w}
+}
+
+// PEM armor of a private key, which the private-key rule masks when the
+// whole block is in one string.
+var (
+	pemKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	pemKeyEnd   = regexp.MustCompile(`-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+type lineWriter struct {
+	r     *Redactor
+	w     io.Writer
+	buf   bytes.Buffer
+	inKey bool // between the BEGIN and END lines of a private key
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.buf.Write(p)
+	for {
+		i := bytes.IndexByte(lw.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := lw.buf.Next(i + 1)
+		if _, err := io.WriteString(lw.w, lw.mask(string(line))); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// mask masks one line. Lines inside a private key are dropped, as the key's
+// BEGIN line already wrote the Mask for the block.
+func (lw *lineWriter) mask(line string) string {
+	if lw.inKey {
+		end := pemKeyEnd.FindStringIndex(line)
+		if end == nil {
+			return ""
+		}
+		lw.inKey = false
+		return lw.r.String(line[end[1]:])
+	}
+	if !lw.r.enabled("private-key") {
+		return lw.r.String(line)
+	}
+	begin := pemKeyBegin.FindStringIndex(line)
+	if begin == nil || pemKeyEnd.MatchString(line[begin[1]:]) {
+		return lw.r.String(line)
+	}
+	lw.inKey = true
+	masked := lw.r.String(line[:begin[0]]) + Mask
+	if strings.HasSuffix(line, "\n") {
+		masked += "\n"
+	}
+	return masked
+}
+
+func (lw *lineWriter) Close() error {
+	if lw.buf.Len() == 0 {
+		return nil
+	}
+	_, err := io.WriteString(lw.w, lw.mask(lw.buf.String()))
+	lw.buf.Reset()
+	return err
+}
+
+// enabled reports whether the built-in rule name is in use.
+func (r *Redactor) enabled(name string) bool {
+	if r == nil {
+		return false
+	}
+	for _, rule := range r.rules {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Lines masks each line of text read from in and writes it to out.
+func (r *Redactor) Lines(out io.Writer, in io.Reader) error {
+	w := r.Writer(out)
+	if _, err := io.Copy(w, in); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+#!/bin/bash
+# check-bench-budget.sh - Run the map scan benchmarks and enforce the budget
+#
+# Reads test/bench-budget.txt, runs each benchmark three times and fails when
+# the best ns/op or the allocs/op exceeds its limit. Taking the best of three
+# keeps one noisy run on a shared CI runner from failing the build.
+#
+# Usage: ./scripts/check-bench-budget.sh [budget-file]
+
+set -euo pipefail
+
+BUDGET="${1:-test/bench-budget.txt}"
+COUNT=3
+
+failed=0
+while read -r pkg bench max_ns max_allocs; do
+    case "$pkg" in ''|'#'*) continue ;; esac
+
+    out=$(go test "$pkg" -run '^$' -bench "^${bench}\$" -benchmem -count "$COUNT" </dev/null)
+    # Benchmark lines: name-N  iters  X ns/op  Y B/op  Z allocs/op
+    read -r ns allocs < <(echo "$out" | awk -v b="$bench" '
+        $1 ~ "^"b"(-[0-9]+)?$" {
+            for (i = 2; i < NF; i++) {
+                if ($(i+1) == "ns/op" && (best == "" || $i + 0 < best)) best = $i + 0
+                if ($(i+1) == "allocs/op") allocs = $i + 0
+            }
+        }
+        END { if (best != "") printf "%d %d\n", best, allocs }') || true
+
+    if [ -z "${ns:-}" ]; then
+        echo "✗ $bench: benchmark did not run"
+        echo "$out"
+        failed=1
+        continue
+    fi
+    if [ "$ns" -gt "$max_ns" ] || [ "$allocs" -gt "$max_allocs" ]; then
+        echo "✗ $bench: $ns ns/op, $allocs allocs/op (budget $max_ns ns/op, $max_allocs allocs/op)"
+        failed=1
+    else
+        echo "✓ $bench: $ns ns/op, $allocs allocs/op (budget $max_ns ns/op, $max_allocs allocs/op)"
+    fi
+    unset ns allocs
+done < "$BUDGET"
+
+if [ "$failed" -ne 0 ]; then
+    echo ""
+    echo "Performance budget exceeded. See test/bench-budget.txt."
+    exit 1
+fi
@@ -2,7 +2,8 @@
 # check-readonly.sh - Verify read-only policy is enforced
 #
 # This script checks that Kubernetes client write operations are only used
-# in allowed files (remedy.go, import commands, test files).
+# in allowed files (remedy.go, import commands, cleanup commands, test files).
+# Every allowed file is documented under "Write Paths" in SECURITY.md.
 #
 # We look for patterns like:
 #   - client.Create(ctx, ...)
@@ -21,6 +22,8 @@ ALLOWED_PATTERNS=(
     "import.go"           # Import command can write (ConfigHub sync)
     "import_wizard.go"    # Import wizard can write
     "import_argocd.go"    # ArgoCD import can write
+    "map_stale.go"        # map stale --cleanup removes stale markers
+    "map_delegated_annotate.go" # map delegated --annotate writes link annotations
     "_test.go"            # Tests can use any operations
     "mock"                # Mock implementations
     "fake"                # Fake implementations